@@ -286,6 +286,8 @@ func (msdc *MainDailySplitController) createDailySplitFlow(kt *kit.Kit, summary
 				msdc.ext,
 			),
 		},
+		Priority:    enumor.FlowPriorityLow,
+		FairnessKey: taskserver.AccountFairnessKey(msdc.RootAccountID),
 	})
 	if err != nil {
 		return "", fmt.Errorf("create daily split task flow failed for %s/%s/%s/%d/%d/%d/%d, err %s",
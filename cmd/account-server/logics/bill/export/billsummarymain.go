@@ -48,6 +48,12 @@ type BillSummaryMainTable struct {
 	CurrentMonthCostSynced    string `header:"已确认账单美金（美元）"`
 	CurrentMonthRMBCost       string `header:"当前账单人民币（元）"`
 	CurrentMonthCost          string `header:"当前账单美金（美元）"`
+	ForecastMonthRMBCost      string `header:"预测月末账单人民币（元）"`
+	ForecastMonthRMBLower     string `header:"预测月末账单下限人民币（元）"`
+	ForecastMonthRMBUpper     string `header:"预测月末账单上限人民币（元）"`
+	ForecastQuarterRMBCost    string `header:"预测季末账单人民币（元）"`
+	ForecastQuarterRMBLower   string `header:"预测季末账单下限人民币（元）"`
+	ForecastQuarterRMBUpper   string `header:"预测季末账单上限人民币（元）"`
 }
 
 // GetHeaderValues ...
@@ -258,6 +258,8 @@ func (mac *MainAccountController) createMainSummaryFlow(
 			mainsummary.BuildMainSummaryTask(
 				mac.RootAccountID, mac.MainAccountID, mac.Vendor, billYear, billMonth),
 		},
+		Priority:    enumor.FlowPriorityLow,
+		FairnessKey: taskserver.AccountFairnessKey(mac.RootAccountID),
 	})
 }
 
@@ -370,6 +370,8 @@ func (r *DefaultMonthTaskRunner) createMonthTaskFlow(kt *kit.Kit, task *billcore
 		Tasks: []taskserver.CustomFlowTask{
 			monthtask.BuildMonthTask(task.Type, step, r.rootAccountID, r.vendor, task.BillYear, task.BillMonth, r.ext),
 		},
+		Priority:    enumor.FlowPriorityLow,
+		FairnessKey: taskserver.AccountFairnessKey(r.rootAccountID),
 	}
 	result, err := r.client.TaskServer().CreateCustomFlow(kt, flowReq)
 	if err != nil {
@@ -210,10 +210,12 @@ func (dp *DailyPuller) createNewPullTask(kt *kit.Kit, billTask *bill.BillDailyPu
 		dp.Vendor, dp.MainAccountID, dp.MainAccountCloudID, dp.Version, dp.BillMonth, billTask.BillDay)
 
 	flowInfo := &taskserver.AddCustomFlowReq{
-		Name:      enumor.FlowPullRawBill,
-		Memo:      memo,
-		ShareData: tableasync.NewShareData(infoMap),
-		Tasks:     []taskserver.CustomFlowTask{task},
+		Name:        enumor.FlowPullRawBill,
+		Memo:        memo,
+		ShareData:   tableasync.NewShareData(infoMap),
+		Tasks:       []taskserver.CustomFlowTask{task},
+		Priority:    enumor.FlowPriorityLow,
+		FairnessKey: taskserver.AccountFairnessKey(dp.RootAccountID),
 	}
 	flowResult, err := dp.Client.TaskServer().CreateCustomFlow(kt, flowInfo)
 	if err != nil {
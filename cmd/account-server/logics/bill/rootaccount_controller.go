@@ -224,6 +224,8 @@ func (rac *RootAccountController) createRootSummaryTask(
 			rootsummary.BuildRootSummaryTask(
 				rac.RootAccountID, rac.Vendor, billYear, billMonth),
 		},
+		Priority:    enumor.FlowPriorityLow,
+		FairnessKey: taskserver.AccountFairnessKey(rac.RootAccountID),
 	})
 }
 
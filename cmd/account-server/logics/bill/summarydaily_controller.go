@@ -249,9 +249,11 @@ func (msdc *MainSummaryDailyController) createDailySummaryTask(
 		VersionID:          summary.CurrentVersion,
 	}
 	taskReq := &taskserver.AddCustomFlowReq{
-		Name:  enumor.FlowBillDailySummary,
-		Memo:  memo,
-		Tasks: []taskserver.CustomFlowTask{dailysummary.BuildDailySummaryTask(opt)},
+		Name:        enumor.FlowBillDailySummary,
+		Memo:        memo,
+		Tasks:       []taskserver.CustomFlowTask{dailysummary.BuildDailySummaryTask(opt)},
+		Priority:    enumor.FlowPriorityLow,
+		FairnessKey: taskserver.AccountFairnessKey(msdc.RootAccountID),
 	}
 	result, err := msdc.Client.TaskServer().CreateCustomFlow(kt, taskReq)
 	if err != nil {
@@ -0,0 +1,166 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package billalert 账单预算及费用异常告警，在每次日账单汇总完成后评估预算使用情况和费用异常，并通过CMSI邮件通知
+package billalert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+)
+
+// Alerter 账单告警器，仅在主节点运行
+type Alerter struct {
+	Sd      serviced.ServiceDiscover
+	Client  *client.ClientSet
+	CmsiCli cmsi.Client
+
+	// watermark 上次评估时日账单汇总的最新更新时间，汇总数据无变化时不重复评估
+	watermark string
+}
+
+// Run 启动账单告警器
+func (a *Alerter) Run(ctx context.Context) {
+	opt := cc.AccountServer().BillAlert
+	if !opt.Enable {
+		logs.Infof("bill alerter is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(*opt.SyncDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.loopOnce()
+		case <-ctx.Done():
+			logs.Infof("bill alerter context done")
+			return
+		}
+	}
+}
+
+func (a *Alerter) loopOnce() {
+	if !a.Sd.IsMaster() {
+		return
+	}
+
+	kt := getInternalKit()
+	now := time.Now()
+	billYear, billMonth := now.Year(), int(now.Month())
+
+	watermark, err := a.getSummaryWatermark(kt, billYear, billMonth)
+	if err != nil {
+		logs.Errorf("get bill summary watermark failed, err: %v, rid: %s", err, kt.Rid)
+		return
+	}
+	if len(watermark) == 0 || watermark == a.watermark {
+		return
+	}
+
+	logs.Infof("[billalert] bill summary of %d-%02d updated(%s), start evaluate, rid: %s",
+		billYear, billMonth, watermark, kt.Rid)
+	summaryList, err := a.listSummaryMain(kt, billYear, billMonth)
+	if err != nil {
+		logs.Errorf("list bill summary main failed, err: %v, rid: %s", err, kt.Rid)
+		return
+	}
+
+	budgetErr := a.evaluateBudgets(kt, billYear, billMonth, summaryList)
+	if budgetErr != nil {
+		logs.Errorf("evaluate bill budgets failed, err: %v, rid: %s", budgetErr, kt.Rid)
+	}
+	anomalyErr := a.evaluateAnomaly(kt, billYear, billMonth, summaryList)
+	if anomalyErr != nil {
+		logs.Errorf("evaluate bill cost anomaly failed, err: %v, rid: %s", anomalyErr, kt.Rid)
+	}
+	// 评估失败时保留旧的水位，下一轮重新评估，已发送的告警通过去重标识避免重复发送
+	if budgetErr == nil && anomalyErr == nil {
+		a.watermark = watermark
+	}
+}
+
+// getSummaryWatermark 日账单汇总完成后会依次更新日汇总和二级账号汇总，两者最新的更新时间作为水位
+func (a *Alerter) getSummaryWatermark(kt *kit.Kit, billYear, billMonth int) (string, error) {
+	expr := tools.ExpressionAnd(
+		tools.RuleEqual("bill_year", billYear),
+		tools.RuleEqual("bill_month", billMonth),
+	)
+	page := &core.BasePage{Start: 0, Limit: 1, Sort: "updated_at", Order: core.Descending}
+
+	dailyResult, err := a.Client.DataService().Global.Bill.ListBillSummaryDaily(kt,
+		&dsbill.BillSummaryDailyListReq{Filter: expr, Page: page, Fields: []string{"id", "updated_at"}})
+	if err != nil {
+		return "", fmt.Errorf("list latest bill summary daily failed, err: %v", err)
+	}
+	if len(dailyResult.Details) == 0 {
+		return "", nil
+	}
+
+	mainResult, err := a.Client.DataService().Global.Bill.ListBillSummaryMain(kt,
+		&dsbill.BillSummaryMainListReq{Filter: expr, Page: page, Fields: []string{"id", "updated_at"}})
+	if err != nil {
+		return "", fmt.Errorf("list latest bill summary main failed, err: %v", err)
+	}
+	if len(mainResult.Details) == 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf("%s/%s", dailyResult.Details[0].UpdatedAt, mainResult.Details[0].UpdatedAt), nil
+}
+
+func (a *Alerter) listSummaryMain(kt *kit.Kit, billYear, billMonth int) ([]*dsbill.BillSummaryMain, error) {
+	expr := tools.ExpressionAnd(
+		tools.RuleEqual("bill_year", billYear),
+		tools.RuleEqual("bill_month", billMonth),
+	)
+	result := make([]*dsbill.BillSummaryMain, 0)
+	page := &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit}
+	for {
+		resp, err := a.Client.DataService().Global.Bill.ListBillSummaryMain(kt,
+			&dsbill.BillSummaryMainListReq{Filter: expr, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, resp.Details...)
+		if uint(len(resp.Details)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+	return result, nil
+}
+
+func getInternalKit() *kit.Kit {
+	newKit := kit.New()
+	newKit.User = string(cc.AccountServerName)
+	newKit.AppCode = string(cc.AccountServerName)
+	return newKit
+}
@@ -0,0 +1,232 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billalert
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"hcm/pkg/api/core"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// anomalyMinSamples 费用异常检测至少需要的历史天数，历史数据不足时不检测
+	anomalyMinSamples = 7
+	// anomalyStdFloorRatio 标准差下限占均值的比例，避免费用非常平稳时微小波动被判定为异常
+	anomalyStdFloorRatio = 0.05
+)
+
+// dailyCost 二级账号某天的费用
+type dailyCost struct {
+	Year  int
+	Month int
+	Day   int
+	Cost  decimal.Decimal
+}
+
+// evaluateAnomaly 以二级账号为单位，对比最新一天的日账单费用与之前若干天费用的均值和标准差，偏离过大时告警
+func (a *Alerter) evaluateAnomaly(kt *kit.Kit, billYear, billMonth int,
+	summaryList []*dsbill.BillSummaryMain) error {
+
+	if len(summaryList) == 0 {
+		return nil
+	}
+	opt := cc.AccountServer().BillAlert
+
+	lastMonthTime := time.Date(billYear, time.Month(billMonth), 1, 0, 0, 0, 0, time.Local).AddDate(0, -1, 0)
+	lastYear, lastMonth := lastMonthTime.Year(), int(lastMonthTime.Month())
+	lastSummaryList, err := a.listSummaryMain(kt, lastYear, lastMonth)
+	if err != nil {
+		return err
+	}
+	lastVersionMap := make(map[string]int, len(lastSummaryList))
+	for _, one := range lastSummaryList {
+		lastVersionMap[one.MainAccountID] = one.CurrentVersion
+	}
+
+	alerts, err := a.listAlerts(kt, enumor.BillAlertAnomaly, billYear, billMonth)
+	if err != nil {
+		return err
+	}
+	alertedKeys := make(map[string]struct{}, len(alerts))
+	for _, one := range alerts {
+		alertedKeys[one.DedupKey] = struct{}{}
+	}
+
+	for _, summary := range summaryList {
+		costs, err := a.listDailyCost(kt, summary.MainAccountID, billYear, billMonth, summary.CurrentVersion)
+		if err != nil {
+			return err
+		}
+		if len(costs) == 0 {
+			continue
+		}
+		if lastVersion, ok := lastVersionMap[summary.MainAccountID]; ok {
+			lastCosts, err := a.listDailyCost(kt, summary.MainAccountID, lastYear, lastMonth, lastVersion)
+			if err != nil {
+				return err
+			}
+			costs = append(lastCosts, costs...)
+		}
+
+		latest := costs[len(costs)-1]
+		history := costs[:len(costs)-1]
+		if len(history) > int(opt.AnomalyWindowDays) {
+			history = history[len(history)-int(opt.AnomalyWindowDays):]
+		}
+		mean, anomaly := detectAnomaly(history, latest.Cost, opt.AnomalyZScore,
+			decimal.NewFromFloat(opt.AnomalyMinCost))
+		if !anomaly {
+			continue
+		}
+
+		dedupKey := fmt.Sprintf("anomaly/%s/%d-%02d-%02d", summary.MainAccountID, latest.Year, latest.Month, latest.Day)
+		if _, ok := alertedKeys[dedupKey]; ok {
+			continue
+		}
+		receivers, err := a.getMainAccountManagers(kt, summary.MainAccountID)
+		if err != nil {
+			return err
+		}
+		alert := &dsbill.BillAlertCreateReq{
+			AlertType:    enumor.BillAlertAnomaly,
+			ScopeType:    enumor.BillBudgetScopeMainAccount,
+			ScopeID:      summary.MainAccountID,
+			Vendor:       summary.Vendor,
+			BillYear:     billYear,
+			BillMonth:    billMonth,
+			BillDay:      latest.Day,
+			Cost:         latest.Cost,
+			ExpectedCost: mean,
+			Currency:     summary.Currency,
+			Message: fmt.Sprintf("二级账号[%s]%d-%02d-%02d费用%s %s，明显高于近%d天的日均费用%s %s",
+				summary.MainAccountCloudID, latest.Year, latest.Month, latest.Day, latest.Cost.StringFixed(2),
+				summary.Currency, len(history), mean.StringFixed(2), summary.Currency),
+			Receivers: receivers,
+			DedupKey:  dedupKey,
+		}
+		title := fmt.Sprintf("HCM账单费用异常告警: 二级账号 %s %d-%02d-%02d", summary.MainAccountCloudID,
+			latest.Year, latest.Month, latest.Day)
+		if err := a.notifyAndRecord(kt, title, alert); err != nil {
+			logs.Errorf("notify bill anomaly alert failed, err: %v, main account: %s, rid: %s", err,
+				summary.MainAccountID, kt.Rid)
+			return err
+		}
+		alertedKeys[dedupKey] = struct{}{}
+	}
+
+	return nil
+}
+
+// listDailyCost 获取二级账号指定月份、指定版本的每日费用，按日期升序排列
+func (a *Alerter) listDailyCost(kt *kit.Kit, mainAccountID string, billYear, billMonth, versionID int) (
+	[]dailyCost, error) {
+
+	listReq := &dsbill.BillSummaryDailyListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("main_account_id", mainAccountID),
+			tools.RuleEqual("bill_year", billYear),
+			tools.RuleEqual("bill_month", billMonth),
+			tools.RuleEqual("version_id", versionID),
+		),
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit},
+		Fields: []string{"bill_day", "cost"},
+	}
+	dayCostMap := make(map[int]decimal.Decimal)
+	for {
+		resp, err := a.Client.DataService().Global.Bill.ListBillSummaryDaily(kt, listReq)
+		if err != nil {
+			return nil, fmt.Errorf("list bill summary daily of %s %d-%02d failed, err: %v", mainAccountID,
+				billYear, billMonth, err)
+		}
+		for _, one := range resp.Details {
+			dayCostMap[one.BillDay] = dayCostMap[one.BillDay].Add(one.Cost)
+		}
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	result := make([]dailyCost, 0, len(dayCostMap))
+	for day, cost := range dayCostMap {
+		result = append(result, dailyCost{Year: billYear, Month: billMonth, Day: day, Cost: cost})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Day < result[j].Day })
+	return result, nil
+}
+
+func (a *Alerter) getMainAccountManagers(kt *kit.Kit, mainAccountID string) ([]string, error) {
+	resp, err := a.Client.DataService().Global.MainAccount.List(kt, &core.ListReq{
+		Filter: tools.EqualExpression("id", mainAccountID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list main account %s failed, err: %v", mainAccountID, err)
+	}
+	if len(resp.Details) == 0 {
+		return nil, nil
+	}
+	account := resp.Details[0]
+	return slice.Unique(append(append([]string{}, account.Managers...), account.BakManagers...)), nil
+}
+
+// detectAnomaly 计算历史费用的均值和标准差，最新费用的z-score不低于zScore且高出均值不少于minCost时视为异常
+func detectAnomaly(history []dailyCost, latest decimal.Decimal, zScore float64, minCost decimal.Decimal) (
+	decimal.Decimal, bool) {
+
+	if len(history) < anomalyMinSamples {
+		return decimal.Zero, false
+	}
+
+	sum := decimal.Zero
+	for _, one := range history {
+		sum = sum.Add(one.Cost)
+	}
+	mean := sum.Div(decimal.NewFromInt(int64(len(history))))
+
+	meanF := mean.InexactFloat64()
+	variance := 0.0
+	for _, one := range history {
+		diff := one.Cost.InexactFloat64() - meanF
+		variance += diff * diff
+	}
+	std := math.Max(math.Sqrt(variance/float64(len(history))), math.Abs(meanF)*anomalyStdFloorRatio)
+	if std == 0 {
+		return mean, false
+	}
+
+	diff := latest.Sub(mean)
+	if diff.LessThan(minCost) {
+		return mean, false
+	}
+	return mean, diff.InexactFloat64()/std >= zScore
+}
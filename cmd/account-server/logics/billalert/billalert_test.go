@@ -0,0 +1,146 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billalert
+
+import (
+	"strings"
+	"testing"
+
+	billcore "hcm/pkg/api/core/bill"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDetectAnomaly(t *testing.T) {
+	steady := make([]dailyCost, 0)
+	for day := 1; day <= 10; day++ {
+		steady = append(steady, dailyCost{Day: day, Cost: decimal.NewFromInt(100 + int64(day%3))})
+	}
+
+	tests := []struct {
+		name    string
+		history []dailyCost
+		latest  int64
+		minCost int64
+		want    bool
+	}{
+		{name: "not enough samples", history: steady[:anomalyMinSamples-1], latest: 1000, want: false},
+		{name: "normal fluctuation", history: steady, latest: 103, want: false},
+		{name: "cost spike", history: steady, latest: 300, want: true},
+		{name: "spike below min cost", history: steady, latest: 300, minCost: 500, want: false},
+		{name: "cost drop", history: steady, latest: 0, want: false},
+		{name: "all zero history", history: make([]dailyCost, anomalyMinSamples), latest: 10, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := detectAnomaly(tt.history, decimal.NewFromInt(tt.latest), 3, decimal.NewFromInt(tt.minCost))
+			if got != tt.want {
+				t.Errorf("detectAnomaly() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighestCrossedThreshold(t *testing.T) {
+	tests := []struct {
+		name    string
+		percent int64
+		alerted int64
+		want    int64
+		crossed bool
+	}{
+		{name: "below all thresholds", percent: 50, want: 0, crossed: false},
+		{name: "cross first threshold", percent: 85, want: 80, crossed: true},
+		{name: "cross multiple thresholds", percent: 120, want: 100, crossed: true},
+		{name: "already alerted", percent: 90, alerted: 80, want: 0, crossed: false},
+		{name: "cross higher after alerted", percent: 100, alerted: 80, want: 100, crossed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, crossed := highestCrossedThreshold([]int64{100, 80}, decimal.NewFromInt(tt.percent), tt.alerted)
+			if got != tt.want || crossed != tt.crossed {
+				t.Errorf("highestCrossedThreshold() = %v, %v, want %v, %v", got, crossed, tt.want, tt.crossed)
+			}
+		})
+	}
+}
+
+func TestSumBudgetCost(t *testing.T) {
+	summaryList := []*dsbill.BillSummaryMain{
+		{MainAccountID: "main-1", RootAccountID: "root", Vendor: enumor.Aws, BkBizID: 10, ProductID: 1,
+			CurrentMonthRMBCost: decimal.NewFromInt(100), AdjustmentRMBCost: decimal.NewFromInt(-10)},
+		{MainAccountID: "main-2", RootAccountID: "root", Vendor: enumor.Gcp, BkBizID: 20, ProductID: 1,
+			CurrentMonthRMBCost: decimal.NewFromInt(50)},
+	}
+
+	tests := []struct {
+		name   string
+		budget *billcore.Budget
+		want   int64
+	}{
+		{
+			// 业务范围按二级账号所属业务统计
+			name:   "main account biz",
+			budget: &billcore.Budget{ScopeType: enumor.BillBudgetScopeMainAccountBiz, ScopeID: "10"},
+			want:   90,
+		},
+		{
+			name:   "root account",
+			budget: &billcore.Budget{ScopeType: enumor.BillBudgetScopeRootAccount, ScopeID: "root"},
+			want:   140,
+		},
+		{
+			name: "product of vendor",
+			budget: &billcore.Budget{ScopeType: enumor.BillBudgetScopeProduct, ScopeID: "1",
+				Vendor: enumor.Gcp},
+			want: 50,
+		},
+		{
+			name:   "unknown main account",
+			budget: &billcore.Budget{ScopeType: enumor.BillBudgetScopeMainAccount, ScopeID: "main-3"},
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		if got := sumBudgetCost(tt.budget, summaryList); !got.Equal(decimal.NewFromInt(tt.want)) {
+			t.Errorf("case %s: expect cost %d, but got: %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestAlertMailContent(t *testing.T) {
+	alert := &dsbill.BillAlertCreateReq{
+		BillYear:  2024,
+		BillMonth: 3,
+		Message:   `预算[<script>alert("x")</script>&team]当月费用100.00元`,
+	}
+	content := alertMailContent(alert)
+	if strings.Contains(content, "<script>") {
+		t.Errorf("mail content should escape alert message, but got: %s", content)
+	}
+	if !strings.Contains(content, "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;&amp;team") {
+		t.Errorf("mail content should contain escaped alert message, but got: %s", content)
+	}
+	if !strings.Contains(content, "2024-03") {
+		t.Errorf("mail content should contain bill month, but got: %s", content)
+	}
+}
@@ -0,0 +1,197 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billalert
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"hcm/pkg/api/core"
+	billcore "hcm/pkg/api/core/bill"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+
+	"github.com/shopspring/decimal"
+)
+
+// evaluateBudgets 按预算范围汇总当月人民币费用，每个预算每月每个阈值只告警一次，同时跨过多个阈值时只告警最高的阈值
+func (a *Alerter) evaluateBudgets(kt *kit.Kit, billYear, billMonth int,
+	summaryList []*dsbill.BillSummaryMain) error {
+
+	budgets, err := a.listEnabledBudgets(kt)
+	if err != nil {
+		return err
+	}
+	if len(budgets) == 0 {
+		return nil
+	}
+
+	alertedMap, err := a.getAlertedThresholds(kt, billYear, billMonth)
+	if err != nil {
+		return err
+	}
+
+	for _, budget := range budgets {
+		if !budget.Amount.IsPositive() {
+			continue
+		}
+		cost := sumBudgetCost(budget, summaryList)
+		percent := cost.Div(budget.Amount).Mul(decimal.NewFromInt(100))
+		threshold, crossed := highestCrossedThreshold(budget.Thresholds, percent, alertedMap[budget.ID])
+		if !crossed {
+			continue
+		}
+
+		alert := &dsbill.BillAlertCreateReq{
+			AlertType: enumor.BillAlertBudget,
+			BudgetID:  budget.ID,
+			ScopeType: budget.ScopeType,
+			ScopeID:   budget.ScopeID,
+			Vendor:    budget.Vendor,
+			BillYear:  billYear,
+			BillMonth: billMonth,
+			Threshold: threshold,
+			Amount:    budget.Amount,
+			Cost:      cost,
+			Currency:  enumor.CurrencyRMB,
+			Message: fmt.Sprintf("预算[%s]当月费用%s元，已达到预算金额%s元的%s%%，超过告警阈值%d%%",
+				budget.Name, cost.StringFixed(2), budget.Amount.StringFixed(2), percent.StringFixed(2), threshold),
+			Receivers: slice.Unique(append(append([]string{}, budget.Receivers...), budget.Creator)),
+			DedupKey:  fmt.Sprintf("budget/%s/%d-%02d/%d", budget.ID, billYear, billMonth, threshold),
+		}
+		title := fmt.Sprintf("HCM账单预算告警: %s 当月费用已达预算的%d%%", budget.Name, threshold)
+		if err := a.notifyAndRecord(kt, title, alert); err != nil {
+			logs.Errorf("notify bill budget alert failed, err: %v, budget: %s, rid: %s", err, budget.ID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *Alerter) listEnabledBudgets(kt *kit.Kit) ([]*billcore.Budget, error) {
+	result := make([]*billcore.Budget, 0)
+	listReq := &core.ListReq{
+		Filter: tools.EqualExpression("enabled", true),
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit},
+	}
+	for {
+		resp, err := a.Client.DataService().Global.Bill.ListBillBudget(kt, listReq)
+		if err != nil {
+			return nil, fmt.Errorf("list enabled bill budget failed, err: %v", err)
+		}
+		result = append(result, resp.Details...)
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+	return result, nil
+}
+
+// getAlertedThresholds 获取当月各预算已经告警过的最高阈值
+func (a *Alerter) getAlertedThresholds(kt *kit.Kit, billYear, billMonth int) (map[string]int64, error) {
+	alerts, err := a.listAlerts(kt, enumor.BillAlertBudget, billYear, billMonth)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]int64, len(alerts))
+	for _, one := range alerts {
+		if one.Threshold > result[one.BudgetID] {
+			result[one.BudgetID] = one.Threshold
+		}
+	}
+	return result, nil
+}
+
+func (a *Alerter) listAlerts(kt *kit.Kit, alertType enumor.BillAlertType, billYear, billMonth int) (
+	[]*billcore.Alert, error) {
+
+	result := make([]*billcore.Alert, 0)
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("alert_type", alertType),
+			tools.RuleEqual("bill_year", billYear),
+			tools.RuleEqual("bill_month", billMonth),
+		),
+		Page:   &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit},
+		Fields: []string{"id", "budget_id", "threshold", "dedup_key"},
+	}
+	for {
+		resp, err := a.Client.DataService().Global.Bill.ListBillAlert(kt, listReq)
+		if err != nil {
+			return nil, fmt.Errorf("list %s bill alert failed, err: %v", alertType, err)
+		}
+		result = append(result, resp.Details...)
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+	return result, nil
+}
+
+// sumBudgetCost 汇总预算范围内的当月人民币费用，包含调账费用
+func sumBudgetCost(budget *billcore.Budget, summaryList []*dsbill.BillSummaryMain) decimal.Decimal {
+	cost := decimal.Zero
+	for _, summary := range summaryList {
+		if len(budget.Vendor) != 0 && budget.Vendor != summary.Vendor {
+			continue
+		}
+		var scopeID string
+		switch budget.ScopeType {
+		case enumor.BillBudgetScopeMainAccountBiz:
+			scopeID = strconv.FormatInt(summary.BkBizID, 10)
+		case enumor.BillBudgetScopeMainAccount:
+			scopeID = summary.MainAccountID
+		case enumor.BillBudgetScopeRootAccount:
+			scopeID = summary.RootAccountID
+		case enumor.BillBudgetScopeProduct:
+			scopeID = strconv.FormatInt(summary.ProductID, 10)
+		default:
+			continue
+		}
+		if scopeID != budget.ScopeID {
+			continue
+		}
+		cost = cost.Add(summary.CurrentMonthRMBCost).Add(summary.AdjustmentRMBCost)
+	}
+	return cost
+}
+
+// highestCrossedThreshold 返回已达到且高于已告警阈值的最高阈值
+func highestCrossedThreshold(thresholds []int64, percent decimal.Decimal, alerted int64) (int64, bool) {
+	sorted := append([]int64{}, thresholds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+	for _, threshold := range sorted {
+		if threshold <= alerted {
+			return 0, false
+		}
+		if percent.GreaterThanOrEqual(decimal.NewFromInt(threshold)) {
+			return threshold, true
+		}
+	}
+	return 0, false
+}
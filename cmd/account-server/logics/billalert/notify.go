@@ -0,0 +1,77 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billalert
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+)
+
+// notifyAndRecord 发送告警邮件并记录告警历史，邮件发送失败时仍记录告警，避免每轮评估重复发送
+func (a *Alerter) notifyAndRecord(kt *kit.Kit, title string, alert *dsbill.BillAlertCreateReq) error {
+	receivers := make([]string, 0, len(alert.Receivers))
+	for _, one := range alert.Receivers {
+		if len(one) != 0 {
+			receivers = append(receivers, one)
+		}
+	}
+	alert.Receivers = receivers
+
+	alert.NotifyState = enumor.BillAlertNotifySkipped
+	if len(receivers) != 0 {
+		mail := &cmsi.CmsiMail{
+			ReceiverUserName: strings.Join(receivers, ","),
+			Title:            title,
+			Content:          alertMailContent(alert),
+			BodyFormat:       "Html",
+		}
+		alert.NotifyState = enumor.BillAlertNotifySuccess
+		if err := a.CmsiCli.SendMail(kt, mail); err != nil {
+			logs.Errorf("send bill alert mail failed, err: %v, dedup key: %s, rid: %s", err, alert.DedupKey, kt.Rid)
+			alert.NotifyState = enumor.BillAlertNotifyFailed
+		}
+	}
+
+	_, err := a.Client.DataService().Global.Bill.BatchCreateBillAlert(kt,
+		&dsbill.BatchBillAlertCreateReq{Items: []dsbill.BillAlertCreateReq{*alert}})
+	if err != nil {
+		return fmt.Errorf("create bill alert failed, err: %v", err)
+	}
+	logs.Infof("[billalert] bill alert triggered, dedup key: %s, notify state: %s, rid: %s", alert.DedupKey,
+		alert.NotifyState, kt.Rid)
+	return nil
+}
+
+// alertMailContent 生成告警邮件内容，告警信息中包含用户填写的预算名称，需要转义后再写入html邮件
+func alertMailContent(alert *dsbill.BillAlertCreateReq) string {
+	return fmt.Sprintf(alertMailTemplate, html.EscapeString(alert.Message), alert.BillYear, alert.BillMonth)
+}
+
+// alertMailTemplate 账单告警邮件模版
+const alertMailTemplate = `<p>%s</p>
+<p>账单月份: %d-%02d</p>
+<p>详情请登录HCM查看账单汇总及告警记录。</p>`
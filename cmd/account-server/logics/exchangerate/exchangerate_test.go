@@ -0,0 +1,175 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package exchangerate
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestHttpProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("year") != "2024" || r.URL.Query().Get("month") != "3" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/csv" {
+			fmt.Fprint(w, "from,to,rate\nUSD,CNY,7.1\neur,cny,7.8\nJPY,USD,0.0067\n")
+			return
+		}
+		fmt.Fprint(w, `{"rates":[{"from":"USD","to":"CNY","rate":"7.1"},{"from":"EUR","to":"CNY","rate":7.8},`+
+			`{"from":"GBP","to":"CNY","rate":"9.0"}]}`)
+	}))
+	defer server.Close()
+
+	timeout := 5 * time.Second
+	tests := []struct {
+		name     string
+		provider cc.ExchangeRateProviderType
+		path     string
+	}{
+		{name: "json", provider: cc.ExchangeRateProviderHttpJson, path: "/json"},
+		{name: "csv", provider: cc.ExchangeRateProviderHttpCsv, path: "/csv"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewProvider(cc.ExchangeRateProvider{
+				Type:    tt.provider,
+				URL:     server.URL + tt.path + "?year={year}&month={month}&to={to}",
+				Timeout: &timeout,
+			})
+			if err != nil {
+				t.Fatalf("create provider failed, err: %v", err)
+			}
+			rates, err := provider.FetchMonthlyRates(kit.New(), 2024, 3,
+				[]enumor.CurrencyCode{enumor.CurrencyUSD, enumor.CurrencyEUR}, enumor.CurrencyCNY)
+			if err != nil {
+				t.Fatalf("fetch monthly rates failed, err: %v", err)
+			}
+			if len(rates) != 2 {
+				t.Fatalf("fetch monthly rates got %d rates, want 2", len(rates))
+			}
+			if rates[0].From != enumor.CurrencyUSD || !rates[0].Rate.Equal(decimal.RequireFromString("7.1")) {
+				t.Errorf("first rate got %+v, want USD 7.1", rates[0])
+			}
+			if rates[1].From != enumor.CurrencyEUR || !rates[1].Rate.Equal(decimal.RequireFromString("7.8")) {
+				t.Errorf("second rate got %+v, want EUR 7.8", rates[1])
+			}
+		})
+	}
+}
+
+func TestValidateRate(t *testing.T) {
+	reference := decimal.RequireFromString("7.0")
+	tests := []struct {
+		name      string
+		rate      string
+		reference *billcore.ExchangeRate
+		wantErr   bool
+	}{
+		{name: "no reference", rate: "7.5", wantErr: false},
+		{name: "small change", rate: "7.3", reference: &billcore.ExchangeRate{ExchangeRate: &reference}},
+		{name: "sudden jump", rate: "8.0", reference: &billcore.ExchangeRate{ExchangeRate: &reference}, wantErr: true},
+		{name: "sudden drop", rate: "0.7", reference: &billcore.ExchangeRate{ExchangeRate: &reference}, wantErr: true},
+		{name: "not positive", rate: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := Rate{From: enumor.CurrencyUSD, To: enumor.CurrencyCNY, Rate: decimal.RequireFromString(tt.rate)}
+			err := validateRate(rate, tt.reference, 0.1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRate() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanAutoRecalculate(t *testing.T) {
+	tests := []struct {
+		state enumor.RootBillSummaryState
+		want  bool
+	}{
+		{state: enumor.RootAccountBillSummaryStateAccounting, want: true},
+		{state: enumor.RootAccountBillSummaryStateAccounted, want: true},
+		{state: enumor.RootAccountBillSummaryStateConfirmed, want: false},
+		{state: enumor.RootAccountBillSummaryStateSyncing, want: false},
+		{state: enumor.RootAccountBillSummaryStateSynced, want: false},
+		{state: "", want: true},
+	}
+	for _, tt := range tests {
+		if got := canAutoRecalculate(tt.state); got != tt.want {
+			t.Errorf("canAutoRecalculate(%q) = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestRecalcDebouncer(t *testing.T) {
+	now := time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)
+	usd := []enumor.CurrencyCode{enumor.CurrencyUSD}
+	d := newRecalcDebouncer(24 * time.Hour)
+
+	if got := d.take(2024, 3, now, false); len(got) != 0 {
+		t.Errorf("take without pending currency got %v, want empty", got)
+	}
+
+	d.add(2024, 3, usd)
+	if got := d.take(2024, 3, now, false); len(got) != 1 || got[0] != enumor.CurrencyUSD {
+		t.Errorf("first take got %v, want [USD]", got)
+	}
+
+	// 重算间隔内的汇率变化合并到下次重算
+	d.add(2024, 3, usd)
+	d.add(2024, 3, []enumor.CurrencyCode{enumor.CurrencyEUR})
+	if got := d.take(2024, 3, now.Add(time.Hour), false); len(got) != 0 {
+		t.Errorf("take within interval got %v, want empty", got)
+	}
+	got := d.take(2024, 3, now.Add(24*time.Hour), false)
+	if len(got) != 2 || got[0] != enumor.CurrencyEUR || got[1] != enumor.CurrencyUSD {
+		t.Errorf("take after interval got %v, want [EUR USD]", got)
+	}
+
+	// 月份结束后汇率已确定，强制重算不受重算间隔限制
+	d.add(2024, 3, usd)
+	if got := d.take(2024, 3, now.Add(25*time.Hour), true); len(got) != 1 {
+		t.Errorf("force take got %v, want [USD]", got)
+	}
+
+	// 重算失败时放回的币种在下次同步时立即重试
+	d.retry(2024, 3, usd)
+	if got := d.take(2024, 3, now.Add(26*time.Hour), false); len(got) != 1 {
+		t.Errorf("take after retry got %v, want [USD]", got)
+	}
+
+	// 不同月份分别计算重算间隔
+	d.add(2024, 4, usd)
+	if got := d.take(2024, 4, now.Add(26*time.Hour), false); len(got) != 1 {
+		t.Errorf("take of another month got %v, want [USD]", got)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package exchangerate
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxResponseSize 汇率数据源响应的最大长度
+const maxResponseSize = 4 << 20
+
+// httpProvider 通过http接口获取汇率，支持json和csv两种格式:
+// json: {"rates": [{"from": "USD", "to": "CNY", "rate": "7.1"}]}
+// csv: 首行为表头，需包含from、to、rate三列
+type httpProvider struct {
+	format  cc.ExchangeRateProviderType
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newHttpProvider(opt cc.ExchangeRateProvider) *httpProvider {
+	return &httpProvider{
+		format:  opt.Type,
+		url:     opt.URL,
+		headers: opt.Headers,
+		client:  &http.Client{Timeout: *opt.Timeout},
+	}
+}
+
+// FetchMonthlyRates 请求http数据源获取指定月份的汇率
+func (p *httpProvider) FetchMonthlyRates(kt *kit.Kit, year, month int, from []enumor.CurrencyCode,
+	to enumor.CurrencyCode) ([]Rate, error) {
+
+	fromList := make([]string, 0, len(from))
+	for _, currency := range from {
+		fromList = append(fromList, string(currency))
+	}
+	replacer := strings.NewReplacer(
+		"{year}", strconv.Itoa(year),
+		"{month}", strconv.Itoa(month),
+		"{from}", url.QueryEscape(strings.Join(fromList, ",")),
+		"{to}", url.QueryEscape(string(to)),
+	)
+
+	req, err := http.NewRequestWithContext(kt.Ctx, http.MethodGet, replacer.Replace(p.url), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create exchange rate request failed, err: %v", err)
+	}
+	for key, value := range p.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request exchange rate provider failed, err: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rate provider returns unexpected status code: %d", resp.StatusCode)
+	}
+
+	body := io.LimitReader(resp.Body, maxResponseSize)
+	var rates []Rate
+	if p.format == cc.ExchangeRateProviderHttpCsv {
+		rates, err = parseCsvRates(body)
+	} else {
+		rates, err = parseJsonRates(body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return filterRates(rates, from, to), nil
+}
+
+func parseJsonRates(reader io.Reader) ([]Rate, error) {
+	result := new(struct {
+		Rates []Rate `json:"rates"`
+	})
+	if err := json.NewDecoder(reader).Decode(result); err != nil {
+		return nil, fmt.Errorf("decode json exchange rates failed, err: %v", err)
+	}
+	return result.Rates, nil
+}
+
+func parseCsvRates(reader io.Reader) ([]Rate, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv exchange rates failed, err: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("csv exchange rates is empty")
+	}
+
+	columns := make(map[string]int)
+	for idx, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = idx
+	}
+	for _, name := range []string{"from", "to", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv exchange rates missing column: %s", name)
+		}
+	}
+
+	rates := make([]Rate, 0, len(records)-1)
+	for line, record := range records[1:] {
+		rate, err := decimal.NewFromString(strings.TrimSpace(record[columns["rate"]]))
+		if err != nil {
+			return nil, fmt.Errorf("parse csv exchange rate of line %d failed, err: %v", line+2, err)
+		}
+		rates = append(rates, Rate{
+			From: enumor.CurrencyCode(strings.ToUpper(strings.TrimSpace(record[columns["from"]]))),
+			To:   enumor.CurrencyCode(strings.ToUpper(strings.TrimSpace(record[columns["to"]]))),
+			Rate: rate,
+		})
+	}
+	return rates, nil
+}
@@ -0,0 +1,92 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package exchangerate 汇率自动同步，定时从汇率数据源拉取月度汇率，校验后写入汇率表，并重新计算受影响账单汇总的人民币费用
+package exchangerate
+
+import (
+	"fmt"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rate 月度汇率
+type Rate struct {
+	From enumor.CurrencyCode `json:"from"`
+	To   enumor.CurrencyCode `json:"to"`
+	Rate decimal.Decimal     `json:"rate"`
+}
+
+// Provider 汇率数据源
+type Provider interface {
+	// FetchMonthlyRates 获取指定月份各原币种到目标币种的汇率，数据源中不存在的币种不返回
+	FetchMonthlyRates(kt *kit.Kit, year, month int, from []enumor.CurrencyCode, to enumor.CurrencyCode) ([]Rate, error)
+}
+
+// NewProvider 根据配置创建汇率数据源
+func NewProvider(opt cc.ExchangeRateProvider) (Provider, error) {
+	switch opt.Type {
+	case cc.ExchangeRateProviderHttpJson, cc.ExchangeRateProviderHttpCsv:
+		return newHttpProvider(opt), nil
+	case cc.ExchangeRateProviderStatic:
+		return &staticProvider{rates: opt.Rates}, nil
+	default:
+		return nil, fmt.Errorf("unsupported exchange rate provider type: %s", opt.Type)
+	}
+}
+
+// filterRates 仅保留需要同步的原币种到目标币种的汇率
+func filterRates(rates []Rate, from []enumor.CurrencyCode, to enumor.CurrencyCode) []Rate {
+	wanted := make(map[enumor.CurrencyCode]struct{}, len(from))
+	for _, currency := range from {
+		wanted[currency] = struct{}{}
+	}
+	result := make([]Rate, 0, len(rates))
+	for _, rate := range rates {
+		if rate.To != to {
+			continue
+		}
+		if _, ok := wanted[rate.From]; !ok {
+			continue
+		}
+		result = append(result, rate)
+		delete(wanted, rate.From)
+	}
+	return result
+}
+
+// staticProvider 使用配置文件中的固定汇率，用于本地调试
+type staticProvider struct {
+	rates map[enumor.CurrencyCode]float64
+}
+
+// FetchMonthlyRates 所有月份返回相同的汇率
+func (p *staticProvider) FetchMonthlyRates(_ *kit.Kit, _, _ int, from []enumor.CurrencyCode,
+	to enumor.CurrencyCode) ([]Rate, error) {
+
+	rates := make([]Rate, 0, len(p.rates))
+	for currency, rate := range p.rates {
+		rates = append(rates, Rate{From: currency, To: to, Rate: decimal.NewFromFloat(rate)})
+	}
+	return filterRates(rates, from, to), nil
+}
@@ -0,0 +1,427 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package exchangerate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"hcm/cmd/task-server/logics/action/bill/mainsummary"
+	"hcm/cmd/task-server/logics/action/bill/rootsummary"
+	"hcm/pkg/api/core"
+	billcore "hcm/pkg/api/core/bill"
+	dsbill "hcm/pkg/api/data-service/bill"
+	taskserver "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/cc"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+
+	"github.com/shopspring/decimal"
+)
+
+// Syncer 汇率同步器，仅在主节点运行
+type Syncer struct {
+	Sd       serviced.ServiceDiscover
+	Client   *client.ClientSet
+	Provider Provider
+
+	debouncer *recalcDebouncer
+}
+
+// Run 启动汇率同步器
+func (s *Syncer) Run(ctx context.Context) {
+	opt := cc.AccountServer().ExchangeRate
+	if !opt.Enable {
+		logs.Infof("exchange rate syncer is disabled")
+		return
+	}
+
+	s.debouncer = newRecalcDebouncer(*opt.RecalculateInterval)
+	ticker := time.NewTicker(*opt.SyncDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.loopOnce(opt)
+		case <-ctx.Done():
+			logs.Infof("exchange rate syncer context done")
+			return
+		}
+	}
+}
+
+func (s *Syncer) loopOnce(opt cc.ExchangeRateOption) {
+	if !s.Sd.IsMaster() {
+		return
+	}
+
+	kt := getInternalKit()
+	now := time.Now()
+
+	// 上月汇率已确定，汇率变化时立即重算，同时带上上月作为当月时被延后重算的币种
+	lastBillYear, lastBillMonth := times.GetLastMonthUTC()
+	changed, err := s.syncMonth(kt, opt, lastBillYear, lastBillMonth)
+	if err != nil {
+		logs.Errorf("sync exchange rate of %d-%02d failed, err: %v, rid: %s",
+			lastBillYear, lastBillMonth, err, kt.Rid)
+	}
+	s.debouncer.add(lastBillYear, lastBillMonth, changed)
+	s.recalculateDue(kt, lastBillYear, lastBillMonth, now, true)
+
+	// 当月汇率在月内可能随每次同步小幅变化，按重算间隔合并重算，避免频繁重算当月账单汇总
+	curBillYear, curBillMonth := times.GetCurrentMonthUTC()
+	changed, err = s.syncMonth(kt, opt, curBillYear, curBillMonth)
+	if err != nil {
+		logs.Errorf("sync exchange rate of %d-%02d failed, err: %v, rid: %s",
+			curBillYear, curBillMonth, err, kt.Rid)
+	}
+	s.debouncer.add(curBillYear, curBillMonth, changed)
+	s.recalculateDue(kt, curBillYear, curBillMonth, now, false)
+}
+
+// recalculateDue 重算到期的汇率变化币种对应的账单汇总，重算失败时保留这些币种，在下次同步时重试
+func (s *Syncer) recalculateDue(kt *kit.Kit, billYear, billMonth int, now time.Time, force bool) {
+	currencies := s.debouncer.take(billYear, billMonth, now, force)
+	if len(currencies) == 0 {
+		return
+	}
+	if err := s.recalculateSummary(kt, billYear, billMonth, currencies); err != nil {
+		logs.Errorf("recalculate bill summary of %d-%02d failed, err: %v, currencies: %v, rid: %s",
+			billYear, billMonth, err, currencies, kt.Rid)
+		s.debouncer.retry(billYear, billMonth, currencies)
+	}
+}
+
+// syncMonth 拉取指定月份的汇率并写入汇率表，返回汇率有变化的原币种
+func (s *Syncer) syncMonth(kt *kit.Kit, opt cc.ExchangeRateOption, billYear, billMonth int) (
+	[]enumor.CurrencyCode, error) {
+
+	rates, err := s.Provider.FetchMonthlyRates(kt, billYear, billMonth, opt.FromCurrencies, opt.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		logs.Warnf("exchange rate provider returns no rate of %d-%02d, rid: %s", billYear, billMonth, kt.Rid)
+		return nil, nil
+	}
+
+	existRates, err := s.listMonthRates(kt, billYear, billMonth, opt.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+	prevYear, prevMonth, err := times.GetLastMonth(billYear, billMonth)
+	if err != nil {
+		return nil, err
+	}
+	prevRates, err := s.listMonthRates(kt, prevYear, prevMonth, opt.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &dsbill.BatchCreateBillExchangeRateReq{}
+	// 已写入的汇率才算作变化，写入失败时也返回已写入的币种，保证这些币种对应的账单汇总会被重算
+	changed := make([]enumor.CurrencyCode, 0)
+	for _, rate := range rates {
+		exist, isExist := existRates[rate.From]
+		// 已有当月汇率时与当月汇率比较，否则与上月汇率比较
+		reference := prevRates[rate.From]
+		if isExist {
+			reference = exist
+		}
+		if err := validateRate(rate, reference, opt.MaxJumpRatio); err != nil {
+			logs.Errorf("reject exchange rate %s->%s of %d-%02d, err: %v, rid: %s",
+				rate.From, rate.To, billYear, billMonth, err, kt.Rid)
+			continue
+		}
+
+		if !isExist {
+			createReq.ExchangeRates = append(createReq.ExchangeRates, dsbill.ExchangeRateCreate{
+				Year:         billYear,
+				Month:        billMonth,
+				FromCurrency: rate.From,
+				ToCurrency:   rate.To,
+				ExchangeRate: cvt.ValToPtr(rate.Rate),
+			})
+			continue
+		}
+		if exist.ExchangeRate != nil && exist.ExchangeRate.Equal(rate.Rate) {
+			continue
+		}
+		updateReq := &dsbill.ExchangeRateUpdateReq{ID: exist.ID, ExchangeRate: cvt.ValToPtr(rate.Rate)}
+		if err := s.Client.DataService().Global.Bill.UpdateExchangeRate(kt, updateReq); err != nil {
+			return changed, fmt.Errorf("update exchange rate %s failed, err: %v", exist.ID, err)
+		}
+		logs.Infof("update exchange rate %s->%s of %d-%02d from %s to %s, rid: %s", rate.From, rate.To,
+			billYear, billMonth, exist.ExchangeRate, rate.Rate, kt.Rid)
+		changed = append(changed, rate.From)
+	}
+
+	if len(createReq.ExchangeRates) > 0 {
+		if _, err := s.Client.DataService().Global.Bill.BatchCreateExchangeRate(kt, createReq); err != nil {
+			return changed, fmt.Errorf("create exchange rate failed, err: %v", err)
+		}
+		for _, rate := range createReq.ExchangeRates {
+			changed = append(changed, rate.FromCurrency)
+		}
+		logs.Infof("create %d exchange rates of %d-%02d, rid: %s", len(createReq.ExchangeRates), billYear,
+			billMonth, kt.Rid)
+	}
+	return changed, nil
+}
+
+// validateRate 校验汇率为正数，且相对参考汇率的变化比例不超过maxJumpRatio
+func validateRate(rate Rate, reference *billcore.ExchangeRate, maxJumpRatio float64) error {
+	if !rate.Rate.IsPositive() {
+		return fmt.Errorf("exchange rate should be positive, got %s", rate.Rate)
+	}
+	if reference == nil || reference.ExchangeRate == nil || !reference.ExchangeRate.IsPositive() {
+		return nil
+	}
+	jump := rate.Rate.Sub(*reference.ExchangeRate).Abs().Div(*reference.ExchangeRate)
+	if jump.GreaterThan(decimal.NewFromFloat(maxJumpRatio)) {
+		return fmt.Errorf("exchange rate jumps from %s to %s, exceeds max jump ratio %v",
+			reference.ExchangeRate, rate.Rate, maxJumpRatio)
+	}
+	return nil
+}
+
+// listMonthRates 获取指定月份到目标币种的汇率，key为原币种
+func (s *Syncer) listMonthRates(kt *kit.Kit, billYear, billMonth int, to enumor.CurrencyCode) (
+	map[enumor.CurrencyCode]*billcore.ExchangeRate, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("year", billYear),
+			tools.RuleEqual("month", billMonth),
+			tools.RuleEqual("to_currency", to),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	result, err := s.Client.DataService().Global.Bill.ListExchangeRate(kt, listReq)
+	if err != nil {
+		return nil, fmt.Errorf("list exchange rate of %d-%02d failed, err: %v", billYear, billMonth, err)
+	}
+	rates := make(map[enumor.CurrencyCode]*billcore.ExchangeRate, len(result.Details))
+	for idx := range result.Details {
+		rate := result.Details[idx]
+		if _, ok := rates[rate.FromCurrency]; !ok {
+			rates[rate.FromCurrency] = &rate
+		}
+	}
+	return rates, nil
+}
+
+// recalculateSummary 为使用变化币种的一级账号创建重算任务，先重算各二级账号汇总，再重算一级账号汇总。
+// 已确认或已同步的一级账号账单已对外生效，不自动重算，需要运维人员重新核算后才会使用新汇率
+func (s *Syncer) recalculateSummary(kt *kit.Kit, billYear, billMonth int, currencies []enumor.CurrencyCode) error {
+	summaryList, err := s.listSummaryMain(kt, billYear, billMonth, currencies)
+	if err != nil {
+		return err
+	}
+	rootStates, err := s.listRootSummaryState(kt, billYear, billMonth)
+	if err != nil {
+		return err
+	}
+
+	rootSummaryMap := make(map[string][]*dsbill.BillSummaryMain)
+	for _, summary := range summaryList {
+		rootSummaryMap[summary.RootAccountID] = append(rootSummaryMap[summary.RootAccountID], summary)
+	}
+	for rootAccountID, mainSummaryList := range rootSummaryMap {
+		if state := rootStates[rootAccountID]; !canAutoRecalculate(state) {
+			logs.Warnf("bill of root account %s in %d-%02d is in state %s, skip recalculating it with new "+
+				"exchange rate of %v, reaccount it manually if needed, rid: %s", rootAccountID, billYear, billMonth,
+				state, currencies, kt.Rid)
+			continue
+		}
+
+		tasks := make([]taskserver.CustomFlowTask, 0, len(mainSummaryList)+1)
+		mainActionIDs := make([]action.ActIDType, 0, len(mainSummaryList))
+		for _, summary := range mainSummaryList {
+			task := mainsummary.BuildMainSummaryTask(rootAccountID, summary.MainAccountID, summary.Vendor,
+				billYear, billMonth)
+			tasks = append(tasks, task)
+			mainActionIDs = append(mainActionIDs, task.ActionID)
+		}
+		rootTask := rootsummary.BuildRootSummaryTask(rootAccountID, mainSummaryList[0].Vendor, billYear, billMonth)
+		rootTask.DependOn = mainActionIDs
+		tasks = append(tasks, rootTask)
+
+		result, err := s.Client.TaskServer().CreateCustomFlow(kt, &taskserver.AddCustomFlowReq{
+			Name:  enumor.FlowBillExchangeRateRecalculate,
+			Memo:  fmt.Sprintf("recalculate root %s, %4d-%02d", rootAccountID, billYear, billMonth),
+			Tasks: tasks,
+		})
+		if err != nil {
+			return fmt.Errorf("create exchange rate recalculate flow for root account %s failed, err: %v",
+				rootAccountID, err)
+		}
+		logs.Infof("create exchange rate recalculate flow %s for root account %s, %d-%02d, rid: %s",
+			result.ID, rootAccountID, billYear, billMonth, kt.Rid)
+	}
+	return nil
+}
+
+// canAutoRecalculate 已确认、同步中、已同步的一级账号账单已对外生效，汇率变化时不自动重算
+func canAutoRecalculate(state enumor.RootBillSummaryState) bool {
+	switch state {
+	case enumor.RootAccountBillSummaryStateConfirmed, enumor.RootAccountBillSummaryStateSyncing,
+		enumor.RootAccountBillSummaryStateSynced:
+		return false
+	default:
+		return true
+	}
+}
+
+// listRootSummaryState 获取指定月份各一级账号的账单汇总状态，key为一级账号ID
+func (s *Syncer) listRootSummaryState(kt *kit.Kit, billYear, billMonth int) (
+	map[string]enumor.RootBillSummaryState, error) {
+
+	expr := tools.ExpressionAnd(
+		tools.RuleEqual("bill_year", billYear),
+		tools.RuleEqual("bill_month", billMonth),
+	)
+	states := make(map[string]enumor.RootBillSummaryState)
+	page := &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit}
+	for {
+		resp, err := s.Client.DataService().Global.Bill.ListBillSummaryRoot(kt, &dsbill.BillSummaryRootListReq{
+			Filter: expr,
+			Page:   page,
+			Fields: []string{"id", "root_account_id", "state"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list bill summary root of %d-%02d failed, err: %v", billYear, billMonth, err)
+		}
+		for _, summary := range resp.Details {
+			states[summary.RootAccountID] = summary.State
+		}
+		if uint(len(resp.Details)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+	return states, nil
+}
+
+func (s *Syncer) listSummaryMain(kt *kit.Kit, billYear, billMonth int, currencies []enumor.CurrencyCode) (
+	[]*dsbill.BillSummaryMain, error) {
+
+	expr := tools.ExpressionAnd(
+		tools.RuleEqual("bill_year", billYear),
+		tools.RuleEqual("bill_month", billMonth),
+		tools.RuleIn("currency", currencies),
+	)
+	result := make([]*dsbill.BillSummaryMain, 0)
+	page := &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit}
+	for {
+		resp, err := s.Client.DataService().Global.Bill.ListBillSummaryMain(kt, &dsbill.BillSummaryMainListReq{
+			Filter: expr,
+			Page:   page,
+			Fields: []string{"id", "root_account_id", "main_account_id", "vendor"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list bill summary main of %d-%02d failed, err: %v", billYear, billMonth, err)
+		}
+		result = append(result, resp.Details...)
+		if uint(len(resp.Details)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+	return result, nil
+}
+
+// recalcDebouncer 记录各月份汇率变化后待重算的币种，限制同一月份账单汇总的重算频率，仅在主节点的同步协程中使用
+type recalcDebouncer struct {
+	interval time.Duration
+	// lastAt 各月份上次重算的时间，key为账单月份
+	lastAt map[string]time.Time
+	// pending 各月份待重算的币种，key为账单月份
+	pending map[string]map[enumor.CurrencyCode]struct{}
+}
+
+func newRecalcDebouncer(interval time.Duration) *recalcDebouncer {
+	return &recalcDebouncer{
+		interval: interval,
+		lastAt:   make(map[string]time.Time),
+		pending:  make(map[string]map[enumor.CurrencyCode]struct{}),
+	}
+}
+
+func monthKey(billYear, billMonth int) string {
+	return fmt.Sprintf("%d-%02d", billYear, billMonth)
+}
+
+// add 记录汇率变化的币种，等待下次到期时重算
+func (d *recalcDebouncer) add(billYear, billMonth int, currencies []enumor.CurrencyCode) {
+	if len(currencies) == 0 {
+		return
+	}
+	key := monthKey(billYear, billMonth)
+	if _, ok := d.pending[key]; !ok {
+		d.pending[key] = make(map[enumor.CurrencyCode]struct{})
+	}
+	for _, currency := range currencies {
+		d.pending[key][currency] = struct{}{}
+	}
+}
+
+// take 距上次重算超过重算间隔或force为true时，取出该月份全部待重算的币种，否则返回空
+func (d *recalcDebouncer) take(billYear, billMonth int, now time.Time, force bool) []enumor.CurrencyCode {
+	key := monthKey(billYear, billMonth)
+	pending := d.pending[key]
+	if len(pending) == 0 {
+		return nil
+	}
+	if lastAt, ok := d.lastAt[key]; !force && ok && now.Sub(lastAt) < d.interval {
+		return nil
+	}
+
+	currencies := make([]enumor.CurrencyCode, 0, len(pending))
+	for currency := range pending {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i] < currencies[j] })
+	delete(d.pending, key)
+	d.lastAt[key] = now
+	return currencies
+}
+
+// retry 重算失败时放回待重算的币种，并允许下次同步时立即重试
+func (d *recalcDebouncer) retry(billYear, billMonth int, currencies []enumor.CurrencyCode) {
+	d.add(billYear, billMonth, currencies)
+	delete(d.lastAt, monthKey(billYear, billMonth))
+}
+
+func getInternalKit() *kit.Kit {
+	newKit := kit.New()
+	newKit.User = string(cc.AccountServerName)
+	newKit.AppCode = string(cc.AccountServerName)
+	return newKit
+}
@@ -0,0 +1,262 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billallocation
+
+import (
+	rawjson "encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"hcm/cmd/task-server/logics/action/bill/dailysplit"
+	asbill "hcm/pkg/api/account-server/bill"
+	"hcm/pkg/api/core"
+	protocore "hcm/pkg/api/core/account-set"
+	billcore "hcm/pkg/api/core/bill"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/times"
+
+	"github.com/shopspring/decimal"
+)
+
+// PreviewBillAllocationRule 使用指定规则对历史月份的账单明细重新分摊，返回分摊前后各业务的费用，不修改账单数据。
+// 账单明细按天分摊，与日账单分账时的处理方式一致，按用量占比分摊的共享费用只按当天的用量占比分摊
+func (s *service) PreviewBillAllocationRule(cts *rest.Contexts) (any, error) {
+	req := new(asbill.PreviewAllocationRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Find}})
+	if err != nil {
+		return nil, err
+	}
+
+	mainAccount, err := s.getMainAccount(cts.Kit, req.MainAccountID)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := s.getPreviewRule(cts.Kit, req)
+	if err != nil {
+		return nil, err
+	}
+	versionID, err := s.getCurrentVersion(cts.Kit, mainAccount, req.BillYear, req.BillMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	allocator := dailysplit.NewBillAllocator(rule, mainAccount.Vendor, mainAccount.BkBizID,
+		dailysplit.NewResOwnerLookup(s.client.DataService()))
+	preview := newAllocationPreview()
+	days := times.DaysInMonth(req.BillYear, time.Month(req.BillMonth))
+	for day := 1; day <= days; day++ {
+		if err := s.previewDay(cts.Kit, mainAccount, req, versionID, day, allocator, preview); err != nil {
+			logs.Errorf("fail to preview allocation of %s day %d, err: %v, rid: %s", mainAccount.ID, day, err,
+				cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return &asbill.AllocationPreviewResult{
+		MainAccountID: mainAccount.ID,
+		BillYear:      req.BillYear,
+		BillMonth:     req.BillMonth,
+		Currency:      preview.currency,
+		Details:       preview.details(),
+	}, nil
+}
+
+// getPreviewRule 获取已保存的规则版本，或使用请求中未保存的规则
+func (s *service) getPreviewRule(kt *kit.Kit, req *asbill.PreviewAllocationRuleReq) (*billcore.AllocationRule,
+	error) {
+
+	if len(req.RuleID) == 0 {
+		return &billcore.AllocationRule{
+			MainAccountID:  req.MainAccountID,
+			RuleType:       req.RuleType,
+			Config:         req.Config,
+			DefaultBkBizID: req.DefaultBkBizID,
+			Enabled:        true,
+		}, nil
+	}
+
+	result, err := s.client.DataService().Global.Bill.ListBillAllocationRule(kt, &dsbill.BillAllocationRuleListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("id", req.RuleID),
+			tools.RuleEqual("main_account_id", req.MainAccountID),
+		),
+		Page: core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("fail to get bill allocation rule %s, err: %v, rid: %s", req.RuleID, err, kt.Rid)
+		return nil, err
+	}
+	if len(result.Details) != 1 {
+		return nil, errf.Newf(errf.RecordNotFound, "allocation rule %s of main account %s not found", req.RuleID,
+			req.MainAccountID)
+	}
+	return result.Details[0], nil
+}
+
+// getCurrentVersion 获取二级账号账单月份当前生效的账单版本
+func (s *service) getCurrentVersion(kt *kit.Kit, mainAccount *protocore.BaseMainAccount, billYear,
+	billMonth int) (int, error) {
+
+	result, err := s.client.DataService().Global.Bill.ListBillSummaryMain(kt, &dsbill.BillSummaryMainListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("main_account_id", mainAccount.ID),
+			tools.RuleEqual("vendor", mainAccount.Vendor),
+			tools.RuleEqual("bill_year", billYear),
+			tools.RuleEqual("bill_month", billMonth),
+		),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"current_version"},
+	})
+	if err != nil {
+		logs.Errorf("fail to get main account bill summary of %s %d-%02d, err: %v, rid: %s", mainAccount.ID,
+			billYear, billMonth, err, kt.Rid)
+		return 0, err
+	}
+	if len(result.Details) != 1 {
+		return 0, errf.Newf(errf.RecordNotFound, "bill summary of main account %s %d-%02d not found",
+			mainAccount.ID, billYear, billMonth)
+	}
+	return result.Details[0].CurrentVersion, nil
+}
+
+// previewDay 分页读取一天的账单明细并分摊
+func (s *service) previewDay(kt *kit.Kit, mainAccount *protocore.BaseMainAccount,
+	req *asbill.PreviewAllocationRuleReq, versionID, billDay int, allocator *dailysplit.BillAllocator,
+	preview *allocationPreview) error {
+
+	listReq := &dsbill.BillItemListReq{
+		ItemCommonOpt: &dsbill.ItemCommonOpt{
+			Vendor: mainAccount.Vendor,
+			Year:   req.BillYear,
+			Month:  req.BillMonth,
+		},
+		ListReq: &core.ListReq{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("root_account_id", mainAccount.ParentAccountID),
+				tools.RuleEqual("main_account_id", mainAccount.ID),
+				tools.RuleEqual("version_id", versionID),
+				tools.RuleEqual("bill_day", billDay),
+			),
+			Page: &core.BasePage{Start: 0, Limit: core.DefaultMaxPageLimit, Sort: "id", Order: core.Ascending},
+		},
+	}
+	for {
+		result, err := s.client.DataService().Global.Bill.ListBillItemRaw(kt, listReq)
+		if err != nil {
+			return fmt.Errorf("list bill item failed, err: %v", err)
+		}
+
+		items := make([]dsbill.BillItemCreateReq[rawjson.RawMessage], 0, len(result.Details))
+		for _, one := range result.Details {
+			preview.addCurrent(one.BkBizID, one.Currency, one.Cost)
+			ext := one.Extension
+			items = append(items, dsbill.BillItemCreateReq[rawjson.RawMessage]{
+				RootAccountID: one.RootAccountID,
+				MainAccountID: one.MainAccountID,
+				Vendor:        one.Vendor,
+				ProductID:     one.ProductID,
+				BkBizID:       one.BkBizID,
+				BillYear:      one.BillYear,
+				BillMonth:     one.BillMonth,
+				BillDay:       one.BillDay,
+				VersionID:     one.VersionID,
+				Currency:      one.Currency,
+				Cost:          one.Cost,
+				ResAmount:     one.ResAmount,
+				Extension:     &ext,
+			})
+		}
+		allocated, err := allocator.Allocate(kt, items)
+		if err != nil {
+			return err
+		}
+		for _, one := range allocated {
+			preview.addAllocated(one.BkBizID, one.Cost)
+		}
+
+		if len(result.Details) < int(core.DefaultMaxPageLimit) {
+			break
+		}
+		listReq.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+
+	for _, one := range allocator.Flush() {
+		preview.addAllocated(one.BkBizID, one.Cost)
+	}
+	return nil
+}
+
+// allocationPreview 汇总分摊前后各业务的费用
+type allocationPreview struct {
+	currency enumor.CurrencyCode
+	costs    map[int64]*asbill.AllocationPreviewBizCost
+}
+
+func newAllocationPreview() *allocationPreview {
+	return &allocationPreview{costs: make(map[int64]*asbill.AllocationPreviewBizCost)}
+}
+
+func (p *allocationPreview) get(bizID int64) *asbill.AllocationPreviewBizCost {
+	cost, ok := p.costs[bizID]
+	if !ok {
+		cost = &asbill.AllocationPreviewBizCost{BkBizID: bizID}
+		p.costs[bizID] = cost
+	}
+	return cost
+}
+
+func (p *allocationPreview) addCurrent(bizID int64, currency enumor.CurrencyCode, cost decimal.Decimal) {
+	if len(p.currency) == 0 {
+		p.currency = currency
+	}
+	one := p.get(bizID)
+	one.CurrentCost = one.CurrentCost.Add(cost)
+}
+
+func (p *allocationPreview) addAllocated(bizID int64, cost decimal.Decimal) {
+	one := p.get(bizID)
+	one.AllocatedCost = one.AllocatedCost.Add(cost)
+}
+
+// details 按业务ID升序返回各业务费用
+func (p *allocationPreview) details() []*asbill.AllocationPreviewBizCost {
+	result := make([]*asbill.AllocationPreviewBizCost, 0, len(p.costs))
+	for _, cost := range p.costs {
+		result = append(result, cost)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].BkBizID < result[j].BkBizID })
+	return result
+}
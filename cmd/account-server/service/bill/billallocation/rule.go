@@ -0,0 +1,107 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billallocation
+
+import (
+	asbill "hcm/pkg/api/account-server/bill"
+	"hcm/pkg/api/core"
+	protocore "hcm/pkg/api/core/account-set"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// CreateBillAllocationRule 创建二级账号分账规则新版本，已有版本不可修改
+func (s *service) CreateBillAllocationRule(cts *rest.Contexts) (any, error) {
+	req := new(asbill.CreateAllocationRuleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Create}})
+	if err != nil {
+		return nil, err
+	}
+
+	mainAccount, err := s.getMainAccount(cts.Kit, req.MainAccountID)
+	if err != nil {
+		return nil, err
+	}
+	dsReq := &dsbill.BillAllocationRuleCreateReq{
+		RootAccountID:  mainAccount.ParentAccountID,
+		MainAccountID:  mainAccount.ID,
+		Vendor:         mainAccount.Vendor,
+		EffectiveYear:  req.EffectiveYear,
+		EffectiveMonth: req.EffectiveMonth,
+		RuleType:       req.RuleType,
+		Config:         req.Config,
+		DefaultBkBizID: req.DefaultBkBizID,
+		Enabled:        req.Enabled,
+		Memo:           req.Memo,
+	}
+	result, err := s.client.DataService().Global.Bill.CreateBillAllocationRule(cts.Kit, dsReq)
+	if err != nil {
+		logs.Errorf("fail to create bill allocation rule, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListBillAllocationRule 查询分账规则
+func (s *service) ListBillAllocationRule(cts *rest.Contexts) (any, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Find}})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.DataService().Global.Bill.ListBillAllocationRule(cts.Kit, req)
+}
+
+func (s *service) getMainAccount(kt *kit.Kit, mainAccountID string) (*protocore.BaseMainAccount, error) {
+	result, err := s.client.DataService().Global.MainAccount.List(kt, &core.ListReq{
+		Filter: tools.EqualExpression("id", mainAccountID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("fail to get main account %s, err: %v, rid: %s", mainAccountID, err, kt.Rid)
+		return nil, err
+	}
+	if len(result.Details) != 1 {
+		return nil, errf.Newf(errf.RecordNotFound, "main account %s not found", mainAccountID)
+	}
+	return result.Details[0], nil
+}
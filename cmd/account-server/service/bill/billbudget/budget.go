@@ -0,0 +1,166 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billbudget
+
+import (
+	asbill "hcm/pkg/api/account-server/bill"
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// CreateBillBudget 创建账单预算
+func (s *service) CreateBillBudget(cts *rest.Contexts) (any, error) {
+	req := new(asbill.CreateBudgetReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Create}})
+	if err != nil {
+		return nil, err
+	}
+
+	dsReq := &dsbill.BillBudgetCreateReq{
+		Name:       req.Name,
+		ScopeType:  req.ScopeType,
+		ScopeID:    req.ScopeID,
+		Vendor:     req.Vendor,
+		Amount:     req.Amount,
+		Thresholds: req.Thresholds,
+		Receivers:  req.Receivers,
+		Enabled:    req.Enabled,
+		Memo:       req.Memo,
+	}
+	result, err := s.client.DataService().Global.Bill.CreateBillBudget(cts.Kit, dsReq)
+	if err != nil {
+		logs.Errorf("fail to create bill budget, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateBillBudget 更新账单预算，预算范围不允许修改
+func (s *service) UpdateBillBudget(cts *rest.Contexts) (any, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+	req := new(asbill.UpdateBudgetReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Update}})
+	if err != nil {
+		return nil, err
+	}
+
+	dsReq := &dsbill.BillBudgetUpdateReq{
+		ID:         id,
+		Name:       req.Name,
+		Vendor:     req.Vendor,
+		Amount:     req.Amount,
+		Thresholds: req.Thresholds,
+		Receivers:  req.Receivers,
+		Enabled:    req.Enabled,
+		Memo:       req.Memo,
+	}
+	if err = s.client.DataService().Global.Bill.UpdateBillBudget(cts.Kit, dsReq); err != nil {
+		logs.Errorf("fail to update bill budget, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+	return nil, nil
+}
+
+// ListBillBudget 查询账单预算
+func (s *service) ListBillBudget(cts *rest.Contexts) (any, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Find}})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.DataService().Global.Bill.ListBillBudget(cts.Kit, req)
+}
+
+// BatchDeleteBillBudget 批量删除账单预算，已触发的告警记录保留
+func (s *service) BatchDeleteBillBudget(cts *rest.Contexts) (any, error) {
+	req := new(core.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Delete}})
+	if err != nil {
+		return nil, err
+	}
+
+	delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", req.IDs)}
+	if err = s.client.DataService().Global.Bill.BatchDeleteBillBudget(cts.Kit, delReq); err != nil {
+		logs.Errorf("fail to batch delete bill budget, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+	return nil, nil
+}
+
+// ListBillAlert 查询已触发的账单告警记录
+func (s *service) ListBillAlert(cts *rest.Contexts) (any, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Find}})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.DataService().Global.Bill.ListBillAlert(cts.Kit, req)
+}
@@ -0,0 +1,169 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billforecast
+
+import (
+	"strconv"
+
+	asbill "hcm/pkg/api/account-server/bill"
+	"hcm/pkg/api/core"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	cvt "hcm/pkg/tools/converter"
+)
+
+// ListCostForecast list month-end and quarter-end cost forecast of root account, main account or biz
+func (s *service) ListCostForecast(cts *rest.Contexts) (interface{}, error) {
+	req := new(asbill.ListCostForecastReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Find}})
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.ScopeType {
+	case enumor.BillBudgetScopeRootAccount:
+		return s.listRootForecast(cts.Kit, req)
+	case enumor.BillBudgetScopeMainAccount:
+		return s.listMainForecast(cts.Kit, req)
+	default:
+		return s.listBizForecast(cts.Kit, req)
+	}
+}
+
+func monthExpression(req *asbill.ListCostForecastReq, idField string, ids []any) *filter.Expression {
+	rules := []*filter.AtomRule{
+		tools.RuleEqual("bill_year", req.BillYear),
+		tools.RuleEqual("bill_month", req.BillMonth),
+	}
+	if len(ids) > 0 {
+		rules = append(rules, tools.RuleIn(idField, ids))
+	}
+	return tools.ExpressionAnd(rules...)
+}
+
+func (s *service) listRootForecast(kt *kit.Kit, req *asbill.ListCostForecastReq) (
+	*asbill.ListCostForecastResult, error) {
+
+	ids := make([]any, 0, len(req.ScopeIDs))
+	for _, id := range req.ScopeIDs {
+		ids = append(ids, id)
+	}
+	listReq := &dsbill.BillSummaryRootListReq{
+		Filter: monthExpression(req, "root_account_id", ids),
+		Page:   req.Page,
+	}
+	summary, err := s.client.DataService().Global.Bill.ListBillSummaryRoot(kt, listReq)
+	if err != nil {
+		logs.Errorf("list bill summary root for cost forecast failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	result := &asbill.ListCostForecastResult{Count: cvt.PtrToVal(summary.Count)}
+	for _, one := range summary.Details {
+		result.Details = append(result.Details, &asbill.CostForecastResult{
+			ScopeType:            req.ScopeType,
+			ScopeID:              one.RootAccountID,
+			Vendor:               one.Vendor,
+			CurrentMonthRMBCost:  one.CurrentMonthRMBCost,
+			CostForecast:         one.CostForecast,
+			Currency:             one.Currency,
+			CurrencyCostForecast: cvt.ValToPtr(one.CurrencyCostForecast),
+		})
+	}
+	return result, nil
+}
+
+func (s *service) listMainForecast(kt *kit.Kit, req *asbill.ListCostForecastReq) (
+	*asbill.ListCostForecastResult, error) {
+
+	ids := make([]any, 0, len(req.ScopeIDs))
+	for _, id := range req.ScopeIDs {
+		ids = append(ids, id)
+	}
+	listReq := &dsbill.BillSummaryMainListReq{
+		Filter: monthExpression(req, "main_account_id", ids),
+		Page:   req.Page,
+	}
+	summary, err := s.client.DataService().Global.Bill.ListBillSummaryMain(kt, listReq)
+	if err != nil {
+		logs.Errorf("list bill summary main for cost forecast failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	result := &asbill.ListCostForecastResult{Count: summary.Count}
+	for _, one := range summary.Details {
+		result.Details = append(result.Details, &asbill.CostForecastResult{
+			ScopeType:            req.ScopeType,
+			ScopeID:              one.MainAccountID,
+			Vendor:               one.Vendor,
+			CurrentMonthRMBCost:  one.CurrentMonthRMBCost,
+			CostForecast:         one.CostForecast,
+			Currency:             one.Currency,
+			CurrencyCostForecast: cvt.ValToPtr(one.CurrencyCostForecast),
+		})
+	}
+	return result, nil
+}
+
+func (s *service) listBizForecast(kt *kit.Kit, req *asbill.ListCostForecastReq) (
+	*asbill.ListCostForecastResult, error) {
+
+	ids := make([]any, 0, len(req.ScopeIDs))
+	for _, id := range req.ScopeIDs {
+		// 已在请求校验中确认为整数
+		bizID, _ := strconv.ParseInt(id, 10, 64)
+		ids = append(ids, bizID)
+	}
+	listReq := &core.ListReq{
+		Filter: monthExpression(req, "bk_biz_id", ids),
+		Page:   req.Page,
+	}
+	summary, err := s.client.DataService().Global.Bill.ListBillSummaryBiz(kt, listReq)
+	if err != nil {
+		logs.Errorf("list bill summary biz for cost forecast failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	result := &asbill.ListCostForecastResult{Count: summary.Count}
+	for _, one := range summary.Details {
+		result.Details = append(result.Details, &asbill.CostForecastResult{
+			ScopeType:           req.ScopeType,
+			ScopeID:             strconv.FormatInt(one.BkBizID, 10),
+			CurrentMonthRMBCost: one.CurrentMonthRMBCost,
+			CostForecast:        one.CostForecast,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,138 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billresourcecost
+
+import (
+	asbill "hcm/pkg/api/account-server/bill"
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+)
+
+// ListResourceCost 查询资源维度日账单
+func (s *service) ListResourceCost(cts *rest.Contexts) (any, error) {
+	req := new(asbill.ListResourceCostReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Find}})
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []filter.RuleFactory{
+		tools.RuleEqual("bill_year", req.BillYear),
+		tools.RuleEqual("bill_month", req.BillMonth),
+	}
+	if req.Filter != nil {
+		rules = append(rules, req.Filter)
+	}
+	expr, err := tools.And(rules...)
+	if err != nil {
+		logs.Errorf("build resource cost filter failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	listReq := &core.ListReq{Filter: expr, Page: req.Page}
+	return s.client.DataService().Global.Bill.ListBillResourceCost(cts.Kit, listReq)
+}
+
+// SumResourceCost 查询单个资源的月度费用，按币种分别汇总
+func (s *service) SumResourceCost(cts *rest.Contexts) (any, error) {
+	req := new(asbill.SumResourceCostReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Find}})
+	if err != nil {
+		return nil, err
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("bill_year", req.BillYear),
+			tools.RuleEqual("bill_month", req.BillMonth),
+			tools.RuleEqual("res_type", req.ResType),
+			tools.RuleEqual("res_id", req.ResID),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	return s.client.DataService().Global.Bill.ListBillResourceCostGroupByRes(cts.Kit, listReq)
+}
+
+// ListTopResourceCost 查询业务下当月指定币种费用最高的前N个资源
+func (s *service) ListTopResourceCost(cts *rest.Contexts) (any, error) {
+	req := new(asbill.TopResourceCostReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	err := s.authorizer.AuthorizeWithPerm(cts.Kit,
+		meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AccountBill, Action: meta.Find}})
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []*filter.AtomRule{
+		tools.RuleEqual("bill_year", req.BillYear),
+		tools.RuleEqual("bill_month", req.BillMonth),
+		tools.RuleEqual("bk_biz_id", req.BkBizID),
+		// 不同币种的费用不能直接比较，只在同一币种内排序
+		tools.RuleEqual("currency", req.Currency),
+	}
+	if len(req.Vendor) > 0 {
+		rules = append(rules, tools.RuleEqual("vendor", req.Vendor))
+	}
+	if len(req.ResTypes) > 0 {
+		rules = append(rules, tools.RuleIn("res_type", req.ResTypes))
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = asbill.DefaultTopResourceCostLimit
+	}
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(rules...),
+		Page: &core.BasePage{
+			Start: 0,
+			Limit: limit,
+			Sort:  "cost",
+			Order: core.Descending,
+		},
+	}
+	return s.client.DataService().Global.Bill.ListBillResourceCostGroupByRes(cts.Kit, listReq)
+}
@@ -122,6 +122,12 @@ func toRawData(kt *kit.Kit, details []*billproto.BillSummaryBizResult, bizMap ma
 			CurrentMonthCostSynced:    detail.CurrentMonthCostSynced.String(),
 			CurrentMonthRMBCost:       detail.CurrentMonthRMBCost.String(),
 			CurrentMonthCost:          detail.CurrentMonthCost.String(),
+			ForecastMonthRMBCost:      detail.ForecastMonthRMBCost.String(),
+			ForecastMonthRMBLower:     detail.ForecastMonthRMBLower.String(),
+			ForecastMonthRMBUpper:     detail.ForecastMonthRMBUpper.String(),
+			ForecastQuarterRMBCost:    detail.ForecastQuarterRMBCost.String(),
+			ForecastQuarterRMBLower:   detail.ForecastQuarterRMBLower.String(),
+			ForecastQuarterRMBUpper:   detail.ForecastQuarterRMBUpper.String(),
 		}
 		fields, err := table.GetHeaderValues()
 		if err != nil {
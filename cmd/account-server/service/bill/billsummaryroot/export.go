@@ -180,6 +180,12 @@ func toRawData(kt *kit.Kit, details []*billcore.SummaryRoot,
 			CurrentMonthCost:          detail.CurrentMonthCost.String(),
 			AdjustRMBCost:             detail.AdjustmentRMBCost.String(),
 			AdjustCost:                detail.AdjustmentCost.String(),
+			ForecastMonthRMBCost:      detail.ForecastMonthRMBCost.String(),
+			ForecastMonthRMBLower:     detail.ForecastMonthRMBLower.String(),
+			ForecastMonthRMBUpper:     detail.ForecastMonthRMBUpper.String(),
+			ForecastQuarterRMBCost:    detail.ForecastQuarterRMBCost.String(),
+			ForecastQuarterRMBLower:   detail.ForecastQuarterRMBLower.String(),
+			ForecastQuarterRMBUpper:   detail.ForecastQuarterRMBUpper.String(),
 		}
 		fields, err := table.GetHeaderValues()
 		if err != nil {
@@ -30,10 +30,16 @@ import (
 
 	logicaudit "hcm/cmd/account-server/logics/audit"
 	"hcm/cmd/account-server/logics/bill"
+	"hcm/cmd/account-server/logics/billalert"
+	exchangeratelogic "hcm/cmd/account-server/logics/exchangerate"
 	mainaccount "hcm/cmd/account-server/service/account-set/main-account"
 	rootaccount "hcm/cmd/account-server/service/account-set/root-account"
 	"hcm/cmd/account-server/service/bill/billadjustment"
+	"hcm/cmd/account-server/service/bill/billallocation"
+	"hcm/cmd/account-server/service/bill/billbudget"
+	"hcm/cmd/account-server/service/bill/billforecast"
 	"hcm/cmd/account-server/service/bill/billitem"
+	"hcm/cmd/account-server/service/bill/billresourcecost"
 	"hcm/cmd/account-server/service/bill/billsummarybiz"
 	"hcm/cmd/account-server/service/bill/billsummarymain"
 	"hcm/cmd/account-server/service/bill/billsummaryroot"
@@ -52,6 +58,7 @@ import (
 	restcli "hcm/pkg/rest/client"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
 	"hcm/pkg/thirdparty/esb"
 	"hcm/pkg/tools/ssl"
 
@@ -65,6 +72,8 @@ type Service struct {
 	authorizer  auth.Authorizer
 	audit       logicaudit.Interface
 	billManager *bill.BillManager
+	billAlerter *billalert.Alerter
+	rateSyncer  *exchangeratelogic.Syncer
 	esbClient   esb.Client
 }
 
@@ -114,17 +123,71 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 		CurrentRootControllers: make(map[string]*bill.RootAccountController),
 	}
 
+	billAlerter, err := newBillAlerter(sd, apiClientSet)
+	if err != nil {
+		return nil, err
+	}
+
+	rateSyncer, err := newExchangeRateSyncer(sd, apiClientSet)
+	if err != nil {
+		return nil, err
+	}
+
 	svr := &Service{
 		clientSet:   apiClientSet,
 		authorizer:  authorizer,
 		audit:       logicaudit.NewAudit(apiClientSet.DataService()),
 		billManager: newBillManager,
+		billAlerter: billAlerter,
+		rateSyncer:  rateSyncer,
 		esbClient:   esbClient,
 	}
 
 	return svr, nil
 }
 
+// newBillAlerter 根据配置创建账单预算及费用异常告警器，未开启时不创建CMSI客户端
+func newBillAlerter(sd serviced.ServiceDiscover, apiClientSet *client.ClientSet) (*billalert.Alerter, error) {
+	alerter := &billalert.Alerter{
+		Sd:     sd,
+		Client: apiClientSet,
+	}
+	cfg := cc.AccountServer().BillAlert
+	if !cfg.Enable {
+		return alerter, nil
+	}
+
+	cmsiCli, err := cmsi.NewClient(&cfg.Cmsi, metrics.Register())
+	if err != nil {
+		logs.Errorf("failed to create cmsi client for bill alert, err: %v", err)
+		return nil, err
+	}
+	alerter.CmsiCli = cmsiCli
+	return alerter, nil
+}
+
+// newExchangeRateSyncer 根据配置创建汇率同步器，未开启时不创建汇率数据源
+func newExchangeRateSyncer(sd serviced.ServiceDiscover, apiClientSet *client.ClientSet) (
+	*exchangeratelogic.Syncer, error) {
+
+	syncer := &exchangeratelogic.Syncer{
+		Sd:     sd,
+		Client: apiClientSet,
+	}
+	cfg := cc.AccountServer().ExchangeRate
+	if !cfg.Enable {
+		return syncer, nil
+	}
+
+	provider, err := exchangeratelogic.NewProvider(cfg.Provider)
+	if err != nil {
+		logs.Errorf("failed to create exchange rate provider, err: %v", err)
+		return nil, err
+	}
+	syncer.Provider = provider
+	return syncer, nil
+}
+
 // newCipherFromConfig 根据配置文件里的加密配置，选择配置的算法并生成对应的加解密器
 func newCipherFromConfig(cryptoConfig cc.Crypto) (cryptography.Crypto, error) {
 	// TODO: 目前只支持国际加密，还未支持中国国家商业加密，待后续支持再调整
@@ -158,6 +221,8 @@ func (s *Service) ListenAndServeRest() error {
 
 	logs.Infof("start bill manager")
 	go s.billManager.Run(context.Background())
+	go s.billAlerter.Run(context.Background())
+	go s.rateSyncer.Run(context.Background())
 
 	logs.Infof("listen restful server on %s with secure(%v) now.", server.Addr, network.TLS.Enable())
 
@@ -211,7 +276,11 @@ func (s *Service) apiSet() *restful.Container {
 	billitem.InitBillItemService(c)
 	billsummarybiz.InitService(c)
 	billadjustment.InitBillAdjustmentService(c)
+	billbudget.InitService(c)
+	billforecast.InitService(c)
+	billallocation.InitService(c)
 	billsyncrecord.InitService(c)
+	billresourcecost.InitService(c)
 	exchangerate.InitService(c)
 
 	return restful.NewContainer().Add(c.WebService)
@@ -50,6 +50,8 @@ func AdaptAuthOptions(a *meta.ResourceAttribute) (client.ActionID, []client.Reso
 		return genSubnetResource(a)
 	case meta.Disk:
 		return genDiskResource(a)
+	case meta.DiskSnapshot:
+		return genDiskSnapshotResource(a)
 	case meta.SecurityGroup:
 		return genSecurityGroupResource(a)
 	case meta.SecurityGroupRule:
@@ -110,6 +112,16 @@ func AdaptAuthOptions(a *meta.ResourceAttribute) (client.ActionID, []client.Reso
 		return genApplicationResources(a)
 	case meta.AccountBillThirdParty:
 		return genAccountBillThirdPartyResource(a)
+	case meta.DistributedLock:
+		return genDistributedLockResource(a)
+	case meta.AdmissionPolicy:
+		return genAdmissionPolicyResource(a)
+	case meta.IPAM:
+		return genIPAMResource(a)
+	case meta.RecyclePolicy:
+		return genRecyclePolicyResource(a)
+	case meta.DiskBackupPolicy:
+		return genDiskBackupPolicyResource(a)
 	default:
 		return "", nil, errf.Newf(errf.InvalidParameter, "unsupported hcm auth type: %s", a.Basic.Type)
 	}
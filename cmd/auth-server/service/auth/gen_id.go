@@ -219,6 +219,11 @@ func genDiskResource(a *meta.ResourceAttribute) (client.ActionID, []client.Resou
 	}
 }
 
+// genDiskSnapshotResource generate disk snapshot related iam resource.
+func genDiskSnapshotResource(a *meta.ResourceAttribute) (client.ActionID, []client.Resource, error) {
+	return genIaaSResourceResource(a)
+}
+
 // genSecurityGroupResource generate security group related iam resource.
 func genSecurityGroupResource(a *meta.ResourceAttribute) (client.ActionID, []client.Resource, error) {
 	res := client.Resource{
@@ -606,9 +611,11 @@ func genLoadBalancerResource(a *meta.ResourceAttribute) (client.ActionID, []clie
 	case meta.Update:
 		// update resource is related to hcm account resource
 		return sys.CLBResOperate, []client.Resource{res}, nil
-	case meta.Delete:
+	case meta.Delete, meta.Recycle:
 		// delete resource is related to hcm account resource
 		return sys.CLBResDelete, []client.Resource{res}, nil
+	case meta.Recover:
+		return sys.RecycleBinOperate, []client.Resource{res}, nil
 	default:
 		return "", nil, errf.Newf(errf.InvalidParameter, "unsupported hcm action: %s", a.Basic.Action)
 	}
@@ -632,8 +639,10 @@ func genBizLoadBalancerResource(a *meta.ResourceAttribute) (client.ActionID, []c
 		return sys.BizCLBResCreate, []client.Resource{res}, nil
 	case meta.Update:
 		return sys.BizCLBResOperate, []client.Resource{res}, nil
-	case meta.Delete:
+	case meta.Delete, meta.Recycle:
 		return sys.BizCLBResDelete, []client.Resource{res}, nil
+	case meta.Recover:
+		return sys.BizRecycleBinOperate, []client.Resource{res}, nil
 	default:
 		return "", nil, errf.Newf(errf.InvalidParameter, "unsupported hcm action: %s", a.Basic.Action)
 	}
@@ -736,3 +745,69 @@ func genAccountBillThirdPartyResource(a *meta.ResourceAttribute) (client.ActionI
 		return "", nil, errf.Newf(errf.InvalidParameter, "unsupported hcm action: %s", a.Basic.Action)
 	}
 }
+
+// genDistributedLockResource generate distributed lock related iam resource, lock management is treated as
+// global configuration of platform.
+func genDistributedLockResource(a *meta.ResourceAttribute) (client.ActionID, []client.Resource, error) {
+	switch a.Basic.Action {
+	case meta.Find, meta.Delete:
+		return sys.GlobalConfiguration, make([]client.Resource, 0), nil
+	default:
+		return "", nil, errf.Newf(errf.InvalidParameter, "unsupported hcm action: %s", a.Basic.Action)
+	}
+}
+
+// genAdmissionPolicyResource generate admission policy related iam resource, admission policy management is treated
+// as global configuration of platform.
+func genAdmissionPolicyResource(a *meta.ResourceAttribute) (client.ActionID, []client.Resource, error) {
+	switch a.Basic.Action {
+	case meta.Find, meta.Create, meta.Update, meta.Delete:
+		return sys.GlobalConfiguration, make([]client.Resource, 0), nil
+	default:
+		return "", nil, errf.Newf(errf.InvalidParameter, "unsupported hcm action: %s", a.Basic.Action)
+	}
+}
+
+// genIPAMResource generate ipam related iam resource, cidr check, suggestion and block query only need login,
+// block reservation and sync are treated as global configuration.
+func genIPAMResource(a *meta.ResourceAttribute) (client.ActionID, []client.Resource, error) {
+	switch a.Basic.Action {
+	case meta.Find:
+		return sys.Skip, make([]client.Resource, 0), nil
+	case meta.Create, meta.Delete, meta.Update:
+		return sys.GlobalConfiguration, make([]client.Resource, 0), nil
+	default:
+		return "", nil, errf.Newf(errf.InvalidParameter, "unsupported hcm action: %s", a.Basic.Action)
+	}
+}
+
+// genRecyclePolicyResource generate recycle policy related iam resource, biz users can view the recycle policies
+// which apply to their biz, managing recycle policies is treated as global configuration.
+func genRecyclePolicyResource(a *meta.ResourceAttribute) (client.ActionID, []client.Resource, error) {
+	switch a.Basic.Action {
+	case meta.Find:
+		if a.BizID > 0 {
+			bizRes := client.Resource{
+				System: sys.SystemIDCMDB,
+				Type:   sys.Biz,
+				ID:     strconv.FormatInt(a.BizID, 10),
+			}
+			return sys.BizAccess, []client.Resource{bizRes}, nil
+		}
+		return sys.GlobalConfiguration, make([]client.Resource, 0), nil
+	case meta.Create, meta.Update, meta.Delete:
+		return sys.GlobalConfiguration, make([]client.Resource, 0), nil
+	default:
+		return "", nil, errf.Newf(errf.InvalidParameter, "unsupported hcm action: %s", a.Basic.Action)
+	}
+}
+
+// genDiskBackupPolicyResource generate disk backup policy related iam resource, disk backup policy belongs to biz,
+// and is managed with biz iaas resource permissions.
+func genDiskBackupPolicyResource(a *meta.ResourceAttribute) (client.ActionID, []client.Resource, error) {
+	if a.BizID <= 0 {
+		return "", nil, errf.New(errf.InvalidParameter, "disk backup policy only supports biz operation")
+	}
+
+	return genBizIaaSResResource(a)
+}
@@ -27,7 +27,6 @@ import (
 
 	"hcm/cmd/cloud-server/options"
 	"hcm/cmd/cloud-server/service"
-	"hcm/cmd/cloud-server/service/sync/lock"
 	"hcm/pkg/cc"
 	"hcm/pkg/logs"
 	"hcm/pkg/metrics"
@@ -114,7 +113,7 @@ func (ds *cloudServer) register() error {
 }
 
 func (ds *cloudServer) finalizer() {
-	lock.Manager.Close()
+	ds.svc.Close()
 
 	if err := ds.sd.Deregister(); err != nil {
 		logs.Errorf("process service shutdown, but deregister failed, err: %v", err)
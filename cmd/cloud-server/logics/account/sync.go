@@ -22,29 +22,29 @@ package account
 import (
 	"errors"
 	"fmt"
-	"strings"
+	"time"
 
 	"hcm/cmd/cloud-server/service/sync/aws"
 	"hcm/cmd/cloud-server/service/sync/azure"
 	"hcm/cmd/cloud-server/service/sync/gcp"
 	"hcm/cmd/cloud-server/service/sync/huawei"
-	"hcm/cmd/cloud-server/service/sync/lock"
 	"hcm/cmd/cloud-server/service/sync/tcloud"
 	"hcm/pkg/api/core"
 	protocloud "hcm/pkg/api/data-service/cloud/zone"
+	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	dataservice "hcm/pkg/client/data-service"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/kit"
+	"hcm/pkg/lock"
 	"hcm/pkg/logs"
-
-	etcd3 "go.etcd.io/etcd/client/v3"
 )
 
 // Sync 账号同步。该操作同一账号不可并行执行，且是异步同步。
-func Sync(kt *kit.Kit, cli *client.ClientSet, vendor enumor.Vendor, accountID string) error {
+func Sync(kt *kit.Kit, cli *client.ClientSet, locker lock.Locker, vendor enumor.Vendor, accountID string) error {
 
 	syncer, ok := vendorSyncerMap[vendor]
 	if !ok {
@@ -58,25 +58,25 @@ func Sync(kt *kit.Kit, cli *client.ClientSet, vendor enumor.Vendor, accountID st
 		return err
 	}
 
-	leaseID, err := lock.Manager.TryLock(lock.Key(accountID))
+	// 锁不自动续期，同步时间超过限频时间后锁自动过期，与同步限频保持一致
+	opt := &lock.Option{
+		TTL:   time.Duration(cc.CloudServer().CloudResource.Sync.SyncFrequencyLimitingTimeMin) * time.Minute,
+		Owner: kt.Rid,
+	}
+	lease, err := locker.TryLock(kt, SyncLockKey(accountID), opt)
 	if err != nil {
-		if err == lock.ErrLockFailed {
+		if errf.IsLockHeld(err) {
 			return errors.New("synchronization is in progress")
 		}
 
 		return err
 	}
 
-	go func(leaseID etcd3.LeaseID) {
+	go func(lease *lock.Lease) {
 		defer func() {
-			if err := lock.Manager.UnLock(leaseID); err != nil {
-				// 锁已经超时释放了
-				if strings.Contains(err.Error(), "requested lease not found") {
-					return
-				}
-
-				logs.Errorf("%s: unlock account sync lock failed, err: %v, accountID: %s, leaseID: %d, rid: %s",
-					constant.AccountSyncFailed, err, accountID, leaseID, kt.Rid)
+			if err := locker.Unlock(kt, lease); err != nil {
+				logs.Errorf("%s: unlock account sync lock failed, err: %v, accountID: %s, token: %d, rid: %s",
+					constant.AccountSyncFailed, err, accountID, lease.Token, kt.Rid)
 			}
 		}()
 
@@ -85,11 +85,16 @@ func Sync(kt *kit.Kit, cli *client.ClientSet, vendor enumor.Vendor, accountID st
 			logs.Errorf("[%s] sync account %s failed on %s, err: %v, rid: %s", vendor, accountID, resType, err, kt.Rid)
 		}
 
-	}(leaseID)
+	}(lease)
 
 	return nil
 }
 
+// SyncLockKey 返回账号同步锁的key
+func SyncLockKey(accountID string) string {
+	return lock.Key(string(cc.CloudServerName), "sync", accountID)
+}
+
 // check is there any tree types of public resources, if one of that type does not exist, we sync all public resources
 func isNeedSyncPublicResource(kt *kit.Kit, dataCli *dataservice.Client, syncer VendorSyncer) (
 	bool, error) {
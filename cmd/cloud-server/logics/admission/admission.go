@@ -0,0 +1,220 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package admission 准入策略，在资源创建、修改请求下发到 hc-service 前按管理员定义的策略规则进行校验
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"hcm/pkg/api/core"
+	coreadmission "hcm/pkg/api/core/admission"
+	dsadmission "hcm/pkg/api/data-service/admission"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// Interface define admission interface.
+type Interface interface {
+	// Admit 使用启用的准入策略校验资源请求并记录每条作用于该请求的策略的校验结果，
+	// 命中拒绝模式的策略时返回 errf.AdmissionDenied 错误，命中告警模式的策略时仅记录结果。
+	Admit(kt *kit.Kit, req *Request) error
+}
+
+// Request define admission request.
+type Request struct {
+	ResType   enumor.CloudResourceType
+	Action    enumor.AdmissionAction
+	Vendor    enumor.Vendor
+	BkBizID   int64
+	AccountID string
+	// Objects 待校验的资源请求对象，一次请求包含多个资源时(如批量创建安全组规则)每个资源对应一个对象
+	Objects []Object
+}
+
+// Object 资源请求对象，key 为资源请求的 json 字段名。
+type Object map[string]interface{}
+
+// NewObject convert resource request to object by its json fields.
+func NewObject(data interface{}) (Object, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := make(Object)
+	if err = json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// NewObjects convert resource requests to objects, and set extra fields to every object.
+func NewObjects[T any](items []T, extra map[string]interface{}) ([]Object, error) {
+	objects := make([]Object, 0, len(items))
+	for _, item := range items {
+		obj, err := NewObject(item)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range extra {
+			obj[k] = v
+		}
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// NewAdmission new admission.
+func NewAdmission(client *client.ClientSet) Interface {
+	return &admission{
+		client: client,
+	}
+}
+
+type admission struct {
+	client *client.ClientSet
+}
+
+// Admit resource request by admission policies.
+func (a *admission) Admit(kt *kit.Kit, req *Request) error {
+	if req == nil || len(req.Objects) == 0 {
+		return nil
+	}
+
+	policies, err := a.listEnabledPolicies(kt, req.ResType, req.Action)
+	if err != nil {
+		return err
+	}
+
+	decisions := make([]coreadmission.Decision, 0)
+	denied := make([]string, 0)
+	for i := range policies {
+		policy := &policies[i]
+		if !policy.AppliesToBiz(req.BkBizID) {
+			continue
+		}
+
+		decision := coreadmission.Decision{
+			PolicyID:   policy.ID,
+			PolicyName: policy.Name,
+			Mode:       policy.Mode,
+			Result:     enumor.AdmissionPass,
+			Message:    policy.Message,
+			ResType:    req.ResType,
+			Action:     req.Action,
+			Vendor:     req.Vendor,
+			BkBizID:    req.BkBizID,
+			AccountID:  req.AccountID,
+		}
+		for _, obj := range req.Objects {
+			if MatchRule(policy.Rule, req.withBuiltinFields(obj)) {
+				decision.Objects = append(decision.Objects, obj)
+			}
+		}
+
+		if len(decision.Objects) != 0 {
+			switch policy.Mode {
+			case enumor.AdmissionDenyMode:
+				decision.Result = enumor.AdmissionDeny
+				denied = append(denied, fmt.Sprintf("%s(%s)", policy.Name, policy.Message))
+			default:
+				decision.Result = enumor.AdmissionWarn
+				logs.Warnf("%s %s request hits admission policy %s(%s) in warn mode, user: %s, rid: %s",
+					req.Action, req.ResType, policy.Name, policy.ID, kt.User, kt.Rid)
+			}
+		}
+		decisions = append(decisions, decision)
+	}
+
+	if len(decisions) == 0 {
+		return nil
+	}
+
+	for _, batch := range slice.Split(decisions, constant.BatchOperationMaxLimit) {
+		if err = a.client.DataService().Global.AdmissionPolicy.RecordDecision(kt,
+			&dsadmission.RecordDecisionReq{Decisions: batch}); err != nil {
+			logs.Errorf("record admission decisions failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	if len(denied) != 0 {
+		return errf.Newf(errf.AdmissionDenied, "request is denied by admission policy: %s",
+			strings.Join(denied, ", "))
+	}
+
+	return nil
+}
+
+// withBuiltinFields set vendor, bk_biz_id and account_id of the request to object if the fields are not set,
+// so that policy rules can use them as conditions.
+func (req *Request) withBuiltinFields(obj Object) Object {
+	result := make(Object, len(obj)+3)
+	result["vendor"] = string(req.Vendor)
+	result["bk_biz_id"] = float64(req.BkBizID)
+	result["account_id"] = req.AccountID
+	for k, v := range obj {
+		result[k] = v
+	}
+
+	return result
+}
+
+func (a *admission) listEnabledPolicies(kt *kit.Kit, resType enumor.CloudResourceType,
+	action enumor.AdmissionAction) ([]coreadmission.Policy, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("res_type", resType),
+			tools.RuleEqual("action", action),
+			tools.RuleEqual("enabled", true),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+
+	policies := make([]coreadmission.Policy, 0)
+	for {
+		result, err := a.client.DataService().Global.AdmissionPolicy.List(kt, listReq)
+		if err != nil {
+			logs.Errorf("list admission policy failed, err: %v, res_type: %s, action: %s, rid: %s", err, resType,
+				action, kt.Rid)
+			return nil, err
+		}
+
+		policies = append(policies, result.Details...)
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,63 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package admission
+
+import (
+	"strings"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+// NewCreateCvmRequest build create cvm admission request, besides request fields, the object provides
+// instance_family field parsed from instance_type.
+func NewCreateCvmRequest(vendor enumor.Vendor, bizID int64, accountID string, data interface{}) (*Request, error) {
+	obj, err := NewObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	instType, _ := obj["instance_type"].(string)
+	obj["instance_family"] = instanceFamily(vendor, instType)
+
+	return &Request{
+		ResType:   enumor.CvmCloudResType,
+		Action:    enumor.AdmissionCreate,
+		Vendor:    vendor,
+		BkBizID:   bizID,
+		AccountID: accountID,
+		Objects:   []Object{obj},
+	}, nil
+}
+
+// instanceFamily parse instance family from instance type, e.g. S5.MEDIUM4 => S5, n2-standard-4 => n2,
+// Standard_D2s_v3 => Standard_D2s.
+func instanceFamily(vendor enumor.Vendor, instType string) string {
+	switch vendor {
+	case enumor.Gcp:
+		return strings.Split(instType, "-")[0]
+	case enumor.Azure:
+		if idx := strings.LastIndex(instType, "_v"); idx > 0 {
+			return instType[:idx]
+		}
+		return instType
+	default:
+		return strings.Split(instType, ".")[0]
+	}
+}
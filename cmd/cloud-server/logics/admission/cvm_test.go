@@ -0,0 +1,53 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package admission
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestNewCreateCvmRequest(t *testing.T) {
+	body := map[string]interface{}{"bk_biz_id": 100, "instance_type": "S5.MEDIUM4"}
+	req, err := NewCreateCvmRequest(enumor.TCloud, 100, "account", body)
+	if err != nil {
+		t.Fatalf("new create cvm request failed, err: %v", err)
+	}
+
+	if req.BkBizID != 100 || req.ResType != enumor.CvmCloudResType || req.Action != enumor.AdmissionCreate {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	if len(req.Objects) != 1 || req.Objects[0]["instance_family"] != "S5" {
+		t.Errorf("unexpected objects: %+v", req.Objects)
+	}
+
+	families := map[enumor.Vendor][2]string{
+		enumor.Gcp:   {"n2-standard-4", "n2"},
+		enumor.Azure: {"Standard_D2s_v3", "Standard_D2s"},
+		enumor.Aws:   {"t3.micro", "t3"},
+	}
+	for vendor, c := range families {
+		if got := instanceFamily(vendor, c[0]); got != c[1] {
+			t.Errorf("%s instance family of %s expect %s, got %s", vendor, c[0], c[1], got)
+		}
+	}
+}
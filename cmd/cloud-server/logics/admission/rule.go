@@ -0,0 +1,174 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package admission
+
+import (
+	"strconv"
+	"strings"
+
+	coreadmission "hcm/pkg/api/core/admission"
+	"hcm/pkg/criteria/enumor"
+)
+
+// MatchRule return if the object matches all conditions of the rule.
+func MatchRule(rule *coreadmission.Rule, obj Object) bool {
+	if rule == nil || len(rule.Conditions) == 0 {
+		return false
+	}
+
+	for i := range rule.Conditions {
+		if !matchCondition(&rule.Conditions[i], obj[rule.Conditions[i].Field]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchCondition 判断字段值是否满足条件，字段值为数组时，正向操作符任一元素满足即满足，
+// 反向操作符(neq、nin、not_prefix)需所有元素均满足。
+func matchCondition(cond *coreadmission.Condition, fieldValue interface{}) bool {
+	values := flatten(fieldValue)
+
+	switch cond.Op {
+	case enumor.AdmissionNotEqual:
+		return !anyMatch(values, func(v string) bool { return v == toString(cond.Value) })
+
+	case enumor.AdmissionNotIn:
+		return !anyMatch(values, func(v string) bool { return inValues(v, cond.Value) })
+
+	case enumor.AdmissionNotPrefix:
+		return !anyMatch(values, func(v string) bool { return strings.HasPrefix(v, toString(cond.Value)) })
+
+	case enumor.AdmissionEqual:
+		return anyMatch(values, func(v string) bool { return v == toString(cond.Value) })
+
+	case enumor.AdmissionIn:
+		return anyMatch(values, func(v string) bool { return inValues(v, cond.Value) })
+
+	case enumor.AdmissionPrefix:
+		return anyMatch(values, func(v string) bool { return strings.HasPrefix(v, toString(cond.Value)) })
+
+	case enumor.AdmissionPortCovers:
+		port, ok := cond.Value.(float64)
+		if !ok {
+			return false
+		}
+		return anyMatch(values, func(v string) bool { return portCovers(v, int64(port)) })
+
+	default:
+		return false
+	}
+}
+
+func anyMatch(values []string, match func(v string) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func inValues(v string, condValue interface{}) bool {
+	list, ok := condValue.([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, one := range list {
+		if v == toString(one) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flatten convert field value to string values, nil value is converted to empty values.
+func flatten(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return []string{}
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, one := range v {
+			if one == nil {
+				continue
+			}
+			result = append(result, toString(one))
+		}
+		return result
+	default:
+		return []string{toString(v)}
+	}
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
+
+// portCovers 判断端口表达式是否覆盖指定端口，支持 22、20-30、22,80、ALL、-1 等格式。
+func portCovers(expr string, port int64) bool {
+	expr = strings.TrimSpace(expr)
+	if strings.EqualFold(expr, "ALL") || expr == "-1" || expr == "*" {
+		return true
+	}
+
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+
+		bounds := strings.SplitN(part, "-", 2)
+		from, err := strconv.ParseInt(strings.TrimSpace(bounds[0]), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		to := from
+		if len(bounds) == 2 {
+			if to, err = strconv.ParseInt(strings.TrimSpace(bounds[1]), 10, 64); err != nil {
+				continue
+			}
+		}
+
+		if from <= port && port <= to {
+			return true
+		}
+	}
+
+	return false
+}
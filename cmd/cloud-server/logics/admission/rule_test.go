@@ -0,0 +1,74 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package admission
+
+import (
+	"testing"
+
+	coreadmission "hcm/pkg/api/core/admission"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestMatchRule(t *testing.T) {
+	// no 0.0.0.0/0 on port 22 in ingress security group rules.
+	sshRule := &coreadmission.Rule{Conditions: []coreadmission.Condition{
+		{Field: "type", Op: enumor.AdmissionEqual, Value: "ingress"},
+		{Field: "ipv4_cidr", Op: enumor.AdmissionIn, Value: []interface{}{"0.0.0.0/0"}},
+		{Field: "port", Op: enumor.AdmissionPortCovers, Value: float64(22)},
+	}}
+
+	// cvm must use instance family S5 or SA2.
+	familyRule := &coreadmission.Rule{Conditions: []coreadmission.Condition{
+		{Field: "instance_family", Op: enumor.AdmissionNotIn, Value: []interface{}{"S5", "SA2"}},
+	}}
+
+	cases := []struct {
+		name   string
+		rule   *coreadmission.Rule
+		obj    Object
+		expect bool
+	}{
+		{"ssh open to world", sshRule,
+			Object{"type": "ingress", "ipv4_cidr": "0.0.0.0/0", "port": "22"}, true},
+		{"all ports open to world", sshRule,
+			Object{"type": "ingress", "ipv4_cidr": "0.0.0.0/0", "port": "ALL"}, true},
+		{"port range covers ssh", sshRule,
+			Object{"type": "ingress", "ipv4_cidr": "0.0.0.0/0", "port": "80,20-30"}, true},
+		{"port not covered", sshRule,
+			Object{"type": "ingress", "ipv4_cidr": "0.0.0.0/0", "port": "80,443"}, false},
+		{"limited cidr", sshRule,
+			Object{"type": "ingress", "ipv4_cidr": "10.0.0.0/8", "port": "22"}, false},
+		{"egress rule", sshRule,
+			Object{"type": "egress", "ipv4_cidr": "0.0.0.0/0", "port": "22"}, false},
+		{"allowed family", familyRule, Object{"instance_family": "S5"}, false},
+		{"disallowed family", familyRule, Object{"instance_family": "M5"}, true},
+		{"missing family", familyRule, Object{}, true},
+		{"array field value", &coreadmission.Rule{Conditions: []coreadmission.Condition{
+			{Field: "source_address_prefixes", Op: enumor.AdmissionEqual, Value: "*"},
+		}}, Object{"source_address_prefixes": []interface{}{"10.0.0.1", "*"}}, true},
+		{"empty rule", &coreadmission.Rule{}, Object{"port": "22"}, false},
+	}
+
+	for _, c := range cases {
+		if got := MatchRule(c.rule, c.obj); got != c.expect {
+			t.Errorf("case %s: expect match %v, but got %v", c.name, c.expect, got)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package dependency 资源删除前依赖检查，通过本地关联关系(及可选的云上实时状态)检查仍依赖待删除资源的资源
+package dependency
+
+import (
+	"fmt"
+	"strings"
+
+	csdependency "hcm/pkg/api/cloud-server/dependency"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// Interface define dependency interface.
+type Interface interface {
+	// Check 按传入的资源ID顺序返回各资源的依赖资源，live 为true时同时检查云上实时状态
+	Check(kt *kit.Kit, resType enumor.CloudResourceType, ids []string, live bool) (
+		[]csdependency.ResDependency, error)
+	// EnsureDeletable 通过本地关联关系检查资源是否可以删除，存在依赖资源时返回 errf.ResourceHasDependents 错误
+	EnsureDeletable(kt *kit.Kit, resType enumor.CloudResourceType, ids []string) error
+}
+
+// NewDependency new dependency.
+func NewDependency(client *client.ClientSet) Interface {
+	return &dependency{
+		client: client,
+		rules:  newRules(client),
+	}
+}
+
+type dependency struct {
+	client *client.ClientSet
+	rules  map[enumor.CloudResourceType][]rule
+}
+
+// Check 查询资源的依赖资源
+func (d *dependency) Check(kt *kit.Kit, resType enumor.CloudResourceType, ids []string, live bool) (
+	[]csdependency.ResDependency, error) {
+
+	if _, exist := csdependency.CheckResTypes[resType]; !exist {
+		return nil, errf.Newf(errf.InvalidParameter, "res_type %s does not support dependency check", resType)
+	}
+
+	// 目前仅子网支持云上实时检查，其他资源类型直接拒绝，避免调用方误以为已检查云上状态
+	if _, exist := csdependency.LiveCheckResTypes[resType]; live && !exist {
+		return nil, errf.Newf(errf.InvalidParameter, "res_type %s does not support live dependency check", resType)
+	}
+
+	ids = slice.Unique(ids)
+	depMap, err := d.listLocalDependents(kt, resType, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if live {
+		cloudDeps, err := d.listSubnetCloudDependents(kt, ids)
+		if err != nil {
+			return nil, err
+		}
+		// 云上依赖放在最前面，避免依赖资源过多时被截断
+		for id, dep := range cloudDeps {
+			depMap[id] = append([]csdependency.Dependent{dep}, depMap[id]...)
+		}
+	}
+
+	results := make([]csdependency.ResDependency, 0, len(ids))
+	for _, id := range ids {
+		deps := depMap[id]
+		result := csdependency.ResDependency{
+			ID:             id,
+			Deletable:      len(deps) == 0,
+			DependentCount: len(deps),
+			Dependents:     deps,
+		}
+		if len(deps) > csdependency.MaxDependents {
+			result.Dependents = deps[:csdependency.MaxDependents]
+		}
+		results = append(results, result)
+	}
+
+	if err = d.fillDependentInfo(kt, results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// EnsureDeletable 资源存在依赖资源时返回错误，错误信息中列出每个资源的第一个依赖资源
+func (d *dependency) EnsureDeletable(kt *kit.Kit, resType enumor.CloudResourceType, ids []string) error {
+	results, err := d.Check(kt, resType, ids, false)
+	if err != nil {
+		return err
+	}
+
+	blocked := make([]string, 0)
+	for _, result := range results {
+		if result.Deletable {
+			continue
+		}
+
+		dep := result.Dependents[0]
+		name := dep.ID
+		if len(dep.CloudID) != 0 {
+			name = dep.CloudID
+		}
+		blocked = append(blocked, fmt.Sprintf("%s(%s) has %d dependents, such as %s(%s): %s", resType, result.ID,
+			result.DependentCount, dep.ResType, name, dep.Reason))
+	}
+
+	if len(blocked) != 0 {
+		return errf.New(errf.ResourceHasDependents, strings.Join(blocked, "; "))
+	}
+
+	return nil
+}
+
+// listLocalDependents 按资源类型的删除依赖规则查询本地关联关系中的依赖资源
+func (d *dependency) listLocalDependents(kt *kit.Kit, resType enumor.CloudResourceType, ids []string) (
+	map[string][]csdependency.Dependent, error) {
+
+	depMap := make(map[string][]csdependency.Dependent, len(ids))
+	// 同一依赖资源可能由多个关联关系表记录，如安全组与主机的关联关系，需要去重
+	seen := make(map[dependent]struct{})
+	for _, r := range d.rules[resType] {
+		deps, err := r.listDependents(kt, ids)
+		if err != nil {
+			logs.Errorf("list %s dependents by %s failed, err: %v, ids: %v, rid: %s", resType, r.field, err, ids,
+				kt.Rid)
+			return nil, err
+		}
+
+		for _, dep := range deps {
+			if len(dep.id) == 0 {
+				continue
+			}
+			if _, exist := seen[dep]; exist {
+				continue
+			}
+			seen[dep] = struct{}{}
+
+			depMap[dep.resID] = append(depMap[dep.resID], csdependency.Dependent{
+				Source:  csdependency.LocalDependentSource,
+				ResType: dep.resType,
+				ID:      dep.id,
+				Reason:  r.reason,
+			})
+		}
+	}
+
+	return depMap, nil
+}
+
+// fillDependentInfo 补充本地依赖资源的云资源ID及名称
+func (d *dependency) fillDependentInfo(kt *kit.Kit, results []csdependency.ResDependency) error {
+	typeIDs := make(map[enumor.CloudResourceType][]string)
+	for _, result := range results {
+		for _, dep := range result.Dependents {
+			if dep.Source == csdependency.LocalDependentSource {
+				typeIDs[dep.ResType] = append(typeIDs[dep.ResType], dep.ID)
+			}
+		}
+	}
+
+	for resType, ids := range typeIDs {
+		fields := []string{"id", "cloud_id", "name"}
+		if resType == enumor.EipCloudResType {
+			// eip 名称可能为空值
+			fields = []string{"id", "cloud_id"}
+		}
+
+		for _, part := range slice.Split(slice.Unique(ids), int(filter.DefaultMaxInLimit)) {
+			req := protocloud.ListResourceBasicInfoReq{ResourceType: resType, IDs: part, Fields: fields}
+			infoMap, err := d.client.DataService().Global.Cloud.ListResBasicInfo(kt, req)
+			if err != nil {
+				if errf.IsRecordNotFound(err) {
+					continue
+				}
+				logs.Errorf("list %s basic info failed, err: %v, ids: %v, rid: %s", resType, err, part, kt.Rid)
+				return err
+			}
+
+			for i := range results {
+				for j := range results[i].Dependents {
+					dep := &results[i].Dependents[j]
+					info, exist := infoMap[dep.ID]
+					if !exist || dep.ResType != resType || dep.Source != csdependency.LocalDependentSource {
+						continue
+					}
+					dep.CloudID = info.CloudID
+					dep.Name = info.Name
+				}
+			}
+		}
+	}
+
+	return nil
+}
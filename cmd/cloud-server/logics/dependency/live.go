@@ -0,0 +1,163 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dependency
+
+import (
+	"fmt"
+
+	csdependency "hcm/pkg/api/cloud-server/dependency"
+	"hcm/pkg/api/core"
+	hcsubnet "hcm/pkg/api/hc-service/subnet"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// listSubnetCloudDependents 通过hc-service查询云上子网已使用的ip数量，云上仍有已使用ip的子网无法删除，
+// 用于发现本地尚未同步的依赖资源
+func (d *dependency) listSubnetCloudDependents(kt *kit.Kit, ids []string) (map[string]csdependency.Dependent,
+	error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "vendor", "account_id", "region"},
+	}
+	subnets, err := d.client.DataService().Global.Subnet.List(kt.Ctx, kt.Header(), listReq)
+	if err != nil {
+		logs.Errorf("list subnet failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return nil, err
+	}
+
+	// 按账号、地域对子网分组，azure 需按资源组及vpc分组，因此单独处理
+	groups := make(map[enumor.Vendor]map[[2]string][]string)
+	azureIDs := make([]string, 0)
+	for _, one := range subnets.Details {
+		if one.Vendor == enumor.Azure {
+			azureIDs = append(azureIDs, one.ID)
+			continue
+		}
+		if _, exist := groups[one.Vendor]; !exist {
+			groups[one.Vendor] = make(map[[2]string][]string)
+		}
+		key := [2]string{one.AccountID, one.Region}
+		groups[one.Vendor][key] = append(groups[one.Vendor][key], one.ID)
+	}
+
+	ipResults := make(map[string]hcsubnet.AvailIPResult)
+	for vendor, group := range groups {
+		for key, subnetIDs := range group {
+			if err = d.countSubnetIP(kt, vendor, key[0], key[1], subnetIDs, ipResults); err != nil {
+				logs.Errorf("count %s subnet ip failed, err: %v, ids: %v, rid: %s", vendor, err, subnetIDs, kt.Rid)
+				return nil, err
+			}
+		}
+	}
+
+	if len(azureIDs) > 0 {
+		if err = d.countAzureSubnetIP(kt, azureIDs, ipResults); err != nil {
+			logs.Errorf("count azure subnet ip failed, err: %v, ids: %v, rid: %s", err, azureIDs, kt.Rid)
+			return nil, err
+		}
+	}
+
+	result := make(map[string]csdependency.Dependent)
+	for id, ipResult := range ipResults {
+		if ipResult.UsedIPCount == 0 {
+			continue
+		}
+		result[id] = csdependency.Dependent{
+			Source: csdependency.CloudDependentSource,
+			Reason: fmt.Sprintf("%d ip addresses are in use on cloud", ipResult.UsedIPCount),
+		}
+	}
+
+	return result, nil
+}
+
+func (d *dependency) countSubnetIP(kt *kit.Kit, vendor enumor.Vendor, accountID, region string, ids []string,
+	result map[string]hcsubnet.AvailIPResult) error {
+
+	req := &hcsubnet.ListCountIPReq{Region: region, AccountID: accountID, IDs: ids}
+
+	var respData map[string]hcsubnet.AvailIPResult
+	var err error
+	switch vendor {
+	case enumor.TCloud:
+		respData, err = d.client.HCService().TCloud.Subnet.ListCountIP(kt.Ctx, kt.Header(), req)
+	case enumor.Aws:
+		respData, err = d.client.HCService().Aws.Subnet.ListCountIP(kt.Ctx, kt.Header(), req)
+	case enumor.Gcp:
+		respData, err = d.client.HCService().Gcp.Subnet.ListCountIP(kt.Ctx, kt.Header(), req)
+	case enumor.HuaWei:
+		respData = make(map[string]hcsubnet.AvailIPResult, len(ids))
+		for _, id := range ids {
+			ipResult, err := d.client.HCService().HuaWei.Subnet.CountIP(kt.Ctx, kt.Header(), id)
+			if err != nil {
+				return err
+			}
+			respData[id] = *ipResult
+		}
+	default:
+		// 不支持查询云上ip使用情况的云厂商不做实时检查
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for id, ipResult := range respData {
+		result[id] = ipResult
+	}
+	return nil
+}
+
+func (d *dependency) countAzureSubnetIP(kt *kit.Kit, ids []string, result map[string]hcsubnet.AvailIPResult) error {
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+	}
+	subnets, err := d.client.DataService().Azure.Subnet.ListSubnetExt(kt.Ctx, kt.Header(), listReq)
+	if err != nil {
+		return err
+	}
+
+	// 按账号、资源组、vpc分组
+	groups := make(map[[3]string][]string)
+	for _, one := range subnets.Details {
+		key := [3]string{one.AccountID, one.Extension.ResourceGroupName, one.VpcID}
+		groups[key] = append(groups[key], one.ID)
+	}
+
+	for key, subnetIDs := range groups {
+		req := &hcsubnet.ListAzureCountIPReq{AccountID: key[0], ResourceGroupName: key[1], VpcID: key[2],
+			IDs: subnetIDs}
+		respData, err := d.client.HCService().Azure.Subnet.ListCountIP(kt.Ctx, kt.Header(), req)
+		if err != nil {
+			return err
+		}
+		for id, ipResult := range respData {
+			result[id] = ipResult
+		}
+	}
+
+	return nil
+}
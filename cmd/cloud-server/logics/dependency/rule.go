@@ -0,0 +1,314 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dependency
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// dependent 依赖待删除资源的资源
+type dependent struct {
+	// resID 被依赖的待删除资源ID
+	resID   string
+	resType enumor.CloudResourceType
+	id      string
+}
+
+// rule 定义一类资源的删除依赖，通过本地关联关系查询依赖待删除资源的资源
+type rule struct {
+	// field 依赖资源数据中指向待删除资源ID的字段
+	field  string
+	reason string
+	list   func(kt *kit.Kit, req *core.ListReq) ([]dependent, error)
+}
+
+// newRules 各资源类型的删除依赖规则
+func newRules(cli *client.ClientSet) map[enumor.CloudResourceType][]rule {
+	global := cli.DataService().Global
+
+	return map[enumor.CloudResourceType][]rule{
+		enumor.VpcCloudResType: {
+			{
+				field:  "vpc_id",
+				reason: "subnet in vpc",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.Subnet.List(kt.Ctx, kt.Header(), req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.VpcID, resType: enumor.SubnetCloudResType, id: one.ID})
+					}
+					return deps, nil
+				},
+			},
+			{
+				field:  "vpc_id",
+				reason: "cvm in vpc",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.VpcCvmRel.List(kt.Ctx, kt.Header(), req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.VpcID, resType: enumor.CvmCloudResType, id: one.CvmID})
+					}
+					return deps, nil
+				},
+			},
+			{
+				field:  "vpc_id",
+				reason: "load balancer in vpc",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.LoadBalancer.ListLoadBalancer(kt, req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.VpcID, resType: enumor.LoadBalancerCloudResType,
+							id: one.ID})
+					}
+					return deps, nil
+				},
+			},
+		},
+		enumor.SubnetCloudResType: {
+			{
+				field:  "subnet_id",
+				reason: "cvm in subnet",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.SubnetCvmRel.List(kt.Ctx, kt.Header(), req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.SubnetID, resType: enumor.CvmCloudResType,
+							id: one.CvmID})
+					}
+					return deps, nil
+				},
+			},
+			{
+				field:  "subnet_id",
+				reason: "network interface in subnet",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.NetworkInterface.List(kt, req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.SubnetID,
+							resType: enumor.NetworkInterfaceCloudResType, id: one.ID})
+					}
+					return deps, nil
+				},
+			},
+			{
+				field:  "subnet_id",
+				reason: "load balancer in subnet",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.LoadBalancer.ListLoadBalancer(kt, req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.SubnetID, resType: enumor.LoadBalancerCloudResType,
+							id: one.ID})
+					}
+					return deps, nil
+				},
+			},
+		},
+		enumor.RouteTableCloudResType: {
+			{
+				field:  "route_table_id",
+				reason: "subnet associated with route table",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.Subnet.List(kt.Ctx, kt.Header(), req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.RouteTableID, resType: enumor.SubnetCloudResType,
+							id: one.ID})
+					}
+					return deps, nil
+				},
+			},
+		},
+		enumor.SecurityGroupCloudResType: {
+			{
+				field:  "security_group_id",
+				reason: "cvm bound to security group",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.SGCvmRel.ListSgCvmRels(kt.Ctx, kt.Header(), req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.SecurityGroupID, resType: enumor.CvmCloudResType,
+							id: one.CvmID})
+					}
+					return deps, nil
+				},
+			},
+			{
+				field:  "security_group_id",
+				reason: "resource bound to security group",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.SGCommonRel.ListSgCommonRels(kt, req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.SecurityGroupID, resType: one.ResType, id: one.ResID})
+					}
+					return deps, nil
+				},
+			},
+		},
+		enumor.DiskCloudResType: {
+			{
+				field:  "disk_id",
+				reason: "disk attached to cvm",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.ListDiskCvmRel(kt, req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.DiskID, resType: enumor.CvmCloudResType,
+							id: one.CvmID})
+					}
+					return deps, nil
+				},
+			},
+		},
+		enumor.EipCloudResType: {
+			{
+				field:  "eip_id",
+				reason: "eip associated with cvm",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.ListEipCvmRel(kt, req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.EipID, resType: enumor.CvmCloudResType, id: one.CvmID})
+					}
+					return deps, nil
+				},
+			},
+		},
+		enumor.NetworkInterfaceCloudResType: {
+			{
+				field:  "network_interface_id",
+				reason: "network interface attached to cvm",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.NetworkInterfaceCvmRel.ListNetworkCvmRels(kt, req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.NetworkInterfaceID, resType: enumor.CvmCloudResType,
+							id: one.CvmID})
+					}
+					return deps, nil
+				},
+			},
+		},
+		enumor.LoadBalancerCloudResType: {
+			{
+				field:  "lb_id",
+				reason: "listener of load balancer",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.LoadBalancer.ListListener(kt, req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.LbID, resType: enumor.ListenerCloudResType,
+							id: one.ID})
+					}
+					return deps, nil
+				},
+			},
+		},
+		enumor.TargetGroupCloudResType: {
+			{
+				field:  "target_group_id",
+				reason: "target group bound to listener",
+				list: func(kt *kit.Kit, req *core.ListReq) ([]dependent, error) {
+					result, err := global.LoadBalancer.ListTargetGroupListenerRel(kt, req)
+					if err != nil {
+						return nil, err
+					}
+					deps := make([]dependent, 0, len(result.Details))
+					for _, one := range result.Details {
+						deps = append(deps, dependent{resID: one.TargetGroupID, resType: enumor.ListenerCloudResType,
+							id: one.LblID})
+					}
+					return deps, nil
+				},
+			},
+		},
+	}
+}
+
+// listDependents 查询依赖指定资源的全部资源
+func (r rule) listDependents(kt *kit.Kit, ids []string) ([]dependent, error) {
+	deps := make([]dependent, 0)
+	for _, part := range slice.Split(ids, int(filter.DefaultMaxInLimit)) {
+		req := &core.ListReq{Filter: tools.ContainersExpression(r.field, part), Page: core.NewDefaultBasePage()}
+		for {
+			details, err := r.list(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			deps = append(deps, details...)
+
+			if uint(len(details)) < req.Page.Limit {
+				break
+			}
+			req.Page.Start += uint32(req.Page.Limit)
+		}
+	}
+
+	return deps, nil
+}
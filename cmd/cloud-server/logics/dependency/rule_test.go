@@ -0,0 +1,187 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dependency
+
+import (
+	"fmt"
+	"testing"
+
+	csdependency "hcm/pkg/api/cloud-server/dependency"
+	"hcm/pkg/api/core"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+)
+
+func TestRuleMatrix(t *testing.T) {
+	type ruleCase struct {
+		field  string
+		reason string
+	}
+
+	cases := map[enumor.CloudResourceType][]ruleCase{
+		enumor.VpcCloudResType: {
+			{field: "vpc_id", reason: "subnet in vpc"},
+			{field: "vpc_id", reason: "cvm in vpc"},
+			{field: "vpc_id", reason: "load balancer in vpc"},
+		},
+		enumor.SubnetCloudResType: {
+			{field: "subnet_id", reason: "cvm in subnet"},
+			{field: "subnet_id", reason: "network interface in subnet"},
+			{field: "subnet_id", reason: "load balancer in subnet"},
+		},
+		enumor.RouteTableCloudResType: {
+			{field: "route_table_id", reason: "subnet associated with route table"},
+		},
+		enumor.SecurityGroupCloudResType: {
+			{field: "security_group_id", reason: "cvm bound to security group"},
+			{field: "security_group_id", reason: "resource bound to security group"},
+		},
+		enumor.DiskCloudResType: {
+			{field: "disk_id", reason: "disk attached to cvm"},
+		},
+		enumor.EipCloudResType: {
+			{field: "eip_id", reason: "eip associated with cvm"},
+		},
+		enumor.NetworkInterfaceCloudResType: {
+			{field: "network_interface_id", reason: "network interface attached to cvm"},
+		},
+		enumor.LoadBalancerCloudResType: {
+			{field: "lb_id", reason: "listener of load balancer"},
+		},
+		enumor.TargetGroupCloudResType: {
+			{field: "target_group_id", reason: "target group bound to listener"},
+		},
+	}
+
+	rules := newRules(new(client.ClientSet))
+	if len(rules) != len(csdependency.CheckResTypes) {
+		t.Errorf("rule types %d mismatch check res types %d", len(rules), len(csdependency.CheckResTypes))
+	}
+
+	for resType := range csdependency.CheckResTypes {
+		expects, exist := cases[resType]
+		if !exist {
+			t.Errorf("%s has no rule case", resType)
+			continue
+		}
+
+		got := rules[resType]
+		if len(got) != len(expects) {
+			t.Errorf("%s rules length expect %d, got %d", resType, len(expects), len(got))
+			continue
+		}
+		for i, expect := range expects {
+			if got[i].field != expect.field || got[i].reason != expect.reason || got[i].list == nil {
+				t.Errorf("%s rule %d expect %+v, got field: %s, reason: %s", resType, i, expect, got[i].field,
+					got[i].reason)
+			}
+		}
+	}
+}
+
+// newFakeRule 按查询条件与分页返回依赖资源的规则，calls 记录查询次数
+func newFakeRule(field string, deps []dependent, calls *int) rule {
+	return rule{
+		field:  field,
+		reason: "fake",
+		list: func(_ *kit.Kit, req *core.ListReq) ([]dependent, error) {
+			*calls++
+			ids := make(map[string]struct{})
+			for _, id := range req.Filter.Rules[0].(filter.AtomRule).Value.([]string) {
+				ids[id] = struct{}{}
+			}
+
+			matched := make([]dependent, 0)
+			for _, dep := range deps {
+				if _, exist := ids[dep.resID]; exist {
+					matched = append(matched, dep)
+				}
+			}
+
+			start := int(req.Page.Start)
+			if start >= len(matched) {
+				return []dependent{}, nil
+			}
+			end := start + int(req.Page.Limit)
+			if end > len(matched) {
+				end = len(matched)
+			}
+			return matched[start:end], nil
+		},
+	}
+}
+
+func TestListLocalDependents(t *testing.T) {
+	cvmDeps := make([]dependent, 0)
+	for i := 0; i < int(core.DefaultMaxPageLimit)+1; i++ {
+		cvmDeps = append(cvmDeps, dependent{resID: "sg1", resType: enumor.CvmCloudResType, id: fmt.Sprintf("cvm%d", i)})
+	}
+	// 通用关联关系中与主机关联关系重复的依赖资源只统计一次，空ID的依赖资源被忽略
+	commonDeps := []dependent{
+		{resID: "sg1", resType: enumor.CvmCloudResType, id: "cvm0"},
+		{resID: "sg2", resType: enumor.LoadBalancerCloudResType, id: "lb1"},
+		{resID: "sg2", resType: enumor.LoadBalancerCloudResType, id: ""},
+	}
+
+	cvmCalls, commonCalls := 0, 0
+	d := &dependency{rules: map[enumor.CloudResourceType][]rule{
+		enumor.SecurityGroupCloudResType: {
+			newFakeRule("security_group_id", cvmDeps, &cvmCalls),
+			newFakeRule("security_group_id", commonDeps, &commonCalls),
+		},
+	}}
+
+	depMap, err := d.listLocalDependents(kit.New(), enumor.SecurityGroupCloudResType, []string{"sg1", "sg2", "sg3"})
+	if err != nil {
+		t.Fatalf("list local dependents failed, err: %v", err)
+	}
+
+	if len(depMap["sg1"]) != len(cvmDeps) || len(depMap["sg2"]) != 1 || len(depMap["sg3"]) != 0 {
+		t.Errorf("unexpected dependents count, sg1: %d, sg2: %d, sg3: %d", len(depMap["sg1"]), len(depMap["sg2"]),
+			len(depMap["sg3"]))
+	}
+	if cvmCalls != 2 || commonCalls != 1 {
+		t.Errorf("unexpected list calls, cvm: %d, common: %d", cvmCalls, commonCalls)
+	}
+	if dep := depMap["sg2"][0]; dep.ResType != enumor.LoadBalancerCloudResType || dep.ID != "lb1" ||
+		dep.Source != csdependency.LocalDependentSource {
+		t.Errorf("unexpected sg2 dependent: %+v", dep)
+	}
+}
+
+func TestLiveCheckUnsupportedResType(t *testing.T) {
+	d := &dependency{rules: make(map[enumor.CloudResourceType][]rule)}
+	for resType := range csdependency.CheckResTypes {
+		if _, exist := csdependency.LiveCheckResTypes[resType]; exist {
+			continue
+		}
+
+		if _, err := d.Check(kit.New(), resType, []string{"id"}, true); err == nil {
+			t.Errorf("live dependency check of %s should be rejected", resType)
+		}
+
+		req := &csdependency.CheckReq{ResType: resType, IDs: []string{"id"}, Live: true}
+		if err := req.Validate(); err == nil {
+			t.Errorf("live dependency check request of %s should be invalid", resType)
+		}
+	}
+}
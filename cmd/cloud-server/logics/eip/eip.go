@@ -53,6 +53,7 @@ type Interface interface {
 	BatchGetEipInfo(kt *kit.Kit, cvmStatus map[string]*recycle.CvmDetail) error
 	BatchUnbind(kt *kit.Kit, cvmStatus map[string]*recycle.CvmDetail) (failed []string, err error)
 	BatchRebind(kt *kit.Kit, cvmRecycleMap map[string]*recycle.CvmDetail) error
+	UnbindEip(kt *kit.Kit, vendor enumor.Vendor, accountID, eipID string) (cvmID string, err error)
 }
 
 type eip struct {
@@ -416,3 +417,43 @@ func (e *eip) DeleteEip(kt *kit.Kit, vendor enumor.Vendor, eipId string) (err er
 	}
 	return err
 }
+
+// UnbindEip 解绑eip与主机的绑定关系，返回解绑的主机id，eip未绑定主机时返回空
+func (e *eip) UnbindEip(kt *kit.Kit, vendor enumor.Vendor, accountID, eipID string) (string, error) {
+	relReq := &core.ListReq{
+		Filter: tools.EqualExpression("eip_id", eipID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	relRes, err := e.client.DataService().Global.ListEipCvmRel(kt, relReq)
+	if err != nil {
+		logs.Errorf("fail to list eip cvm rel, err: %v, eipID: %s, rid: %s", err, eipID, kt.Rid)
+		return "", err
+	}
+
+	if len(relRes.Details) == 0 {
+		return "", nil
+	}
+
+	cvmID := relRes.Details[0].CvmID
+	cvmDetail := map[string]*recycle.CvmDetail{
+		cvmID: {Vendor: vendor, AccountID: accountID, CvmID: cvmID},
+	}
+	if err = e.BatchGetEipInfo(kt, cvmDetail); err != nil {
+		logs.Errorf("fail to get eip info of cvm, err: %v, cvmID: %s, rid: %s", err, cvmID, kt.Rid)
+		return "", err
+	}
+
+	for _, bind := range cvmDetail[cvmID].EipList {
+		if bind.EipID != eipID {
+			continue
+		}
+
+		if err = e.DisassociateEip(kt, vendor, eipID, cvmID, bind.NicID, accountID); err != nil {
+			logs.Errorf("fail to unbind eip, err: %v, eipID: %s, cvmID: %s, rid: %s", err, eipID, cvmID, kt.Rid)
+			return "", err
+		}
+		return cvmID, nil
+	}
+
+	return "", nil
+}
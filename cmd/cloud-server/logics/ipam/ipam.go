@@ -0,0 +1,269 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package ipam 地址管理，索引所有已同步的vpc、子网网段及手动预留的地址块，提供跨厂商、跨账号的网段冲突检测及空闲网段推荐
+package ipam
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	csipam "hcm/pkg/api/cloud-server/ipam"
+	"hcm/pkg/api/core"
+	coreipam "hcm/pkg/api/core/ipam"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/cidr"
+)
+
+// Interface define ipam interface.
+type Interface interface {
+	// CheckCidr 返回范围内与网段重叠的地址块，无冲突时返回空
+	CheckCidr(kt *kit.Kit, req *csipam.CheckCidrReq) ([]coreipam.Block, error)
+	// EnsureVpcCidrAvailable 校验新建vpc的网段与所属云区域内已有的地址块不重叠，冲突时返回 errf.CidrConflict 错误
+	EnsureVpcCidrAvailable(kt *kit.Kit, bkCloudID int64, cidrs []string) error
+	// EnsureSubnetCidrAvailable 校验新建子网的网段与所属vpc云区域内已有的地址块不重叠，所属vpc自身的网段除外，
+	// vpc未绑定云区域时不做校验
+	EnsureSubnetCidrAvailable(kt *kit.Kit, accountID, cloudVpcID string, cidrs []string) error
+	// SuggestCidr 在父网段中按地址顺序查找范围内未被占用的指定掩码长度的地址块
+	SuggestCidr(kt *kit.Kit, req *csipam.SuggestCidrReq) ([]string, error)
+	// SyncAccountBlocks 根据账号下已同步的vpc、子网网段刷新地址块索引
+	SyncAccountBlocks(kt *kit.Kit, accountID string) error
+	// SyncVpcBlocks 根据指定vpc及其子网的网段刷新地址块索引，用于vpc、子网创建及vpc绑定云区域后及时更新索引
+	SyncVpcBlocks(kt *kit.Kit, vpcIDs []string) error
+}
+
+// NewIPAM new ipam.
+func NewIPAM(client *client.ClientSet) Interface {
+	return &ipam{
+		client: client,
+	}
+}
+
+type ipam struct {
+	client *client.ClientSet
+}
+
+// CheckCidr 返回范围内与网段重叠的地址块
+func (i *ipam) CheckCidr(kt *kit.Kit, req *csipam.CheckCidrReq) ([]coreipam.Block, error) {
+	ipVersion, err := cidr.CidrIPAddressType(req.Cidr)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules := append(scopeRules(req.Scope), tools.RuleEqual("ip_version", ipVersion))
+	blocks, err := i.listBlocks(kt, rules...)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[string]struct{}, len(req.ExcludeResIDs))
+	for _, id := range req.ExcludeResIDs {
+		excluded[id] = struct{}{}
+	}
+
+	conflicts := make([]coreipam.Block, 0)
+	for _, block := range blocks {
+		if _, exist := excluded[block.ResID]; exist && len(block.ResID) != 0 {
+			continue
+		}
+
+		overlapped, err := cidr.IsOverlapped(req.Cidr, block.Cidr)
+		if err != nil {
+			logs.Errorf("check cidr overlapped failed, err: %v, cidr: %s, block: %s, rid: %s", err, req.Cidr,
+				block.Cidr, kt.Rid)
+			continue
+		}
+
+		if overlapped {
+			conflicts = append(conflicts, block)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// EnsureVpcCidrAvailable 校验新建vpc的网段与所属云区域内已有的地址块不重叠
+func (i *ipam) EnsureVpcCidrAvailable(kt *kit.Kit, bkCloudID int64, cidrs []string) error {
+	if bkCloudID == constant.UnbindBkCloudID {
+		return nil
+	}
+
+	scope := &csipam.Scope{BkCloudIDs: []int64{bkCloudID}}
+	return i.ensureCidrAvailable(kt, scope, cidrs, nil)
+}
+
+// EnsureSubnetCidrAvailable 校验新建子网的网段与所属vpc云区域内已有的地址块不重叠
+func (i *ipam) EnsureSubnetCidrAvailable(kt *kit.Kit, accountID, cloudVpcID string, cidrs []string) error {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("account_id", accountID), tools.RuleEqual("cloud_id", cloudVpcID)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := i.client.DataService().Global.Vpc.List(kt.Ctx, kt.Header(), listReq)
+	if err != nil {
+		logs.Errorf("list vpc failed, err: %v, account: %s, cloud id: %s, rid: %s", err, accountID, cloudVpcID,
+			kt.Rid)
+		return err
+	}
+
+	if len(result.Details) == 0 {
+		// vpc尚未同步到本地时交由云上校验
+		return nil
+	}
+
+	vpc := result.Details[0]
+	if vpc.BkCloudID == constant.UnbindBkCloudID {
+		return nil
+	}
+
+	scope := &csipam.Scope{BkCloudIDs: []int64{vpc.BkCloudID}}
+	return i.ensureCidrAvailable(kt, scope, cidrs, []string{vpc.ID})
+}
+
+func (i *ipam) ensureCidrAvailable(kt *kit.Kit, scope *csipam.Scope, cidrs []string, excludeResIDs []string) error {
+	for _, one := range cidrs {
+		if len(one) == 0 {
+			continue
+		}
+
+		req := &csipam.CheckCidrReq{Cidr: one, Scope: scope, ExcludeResIDs: excludeResIDs}
+		conflicts, err := i.CheckCidr(kt, req)
+		if err != nil {
+			return err
+		}
+
+		if len(conflicts) == 0 {
+			continue
+		}
+
+		descs := make([]string, 0, len(conflicts))
+		for _, block := range conflicts {
+			descs = append(descs, fmt.Sprintf("%s(%s %s)", block.Cidr, block.Type, block.ResID))
+		}
+		return errf.Newf(errf.CidrConflict, "cidr %s conflicts with %s in cloud area %v", one,
+			strings.Join(descs, ", "), scope.BkCloudIDs)
+	}
+
+	return nil
+}
+
+// SuggestCidr 在父网段中按地址顺序查找范围内未被占用的指定掩码长度的地址块
+func (i *ipam) SuggestCidr(kt *kit.Kit, req *csipam.SuggestCidrReq) ([]string, error) {
+	_, parent, err := net.ParseCIDR(req.ParentCidr)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	ipVersion, err := cidr.CidrIPAddressType(req.ParentCidr)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules := append(scopeRules(req.Scope), tools.RuleEqual("ip_version", ipVersion))
+	blocks, err := i.listBlocks(kt, rules...)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make([]net.IPNet, 0, len(blocks))
+	for _, block := range blocks {
+		_, ipNet, err := net.ParseCIDR(block.Cidr)
+		if err != nil {
+			logs.Errorf("parse ipam block cidr failed, err: %v, block: %s, rid: %s", err, block.ID, kt.Rid)
+			continue
+		}
+		used = append(used, *ipNet)
+	}
+
+	count := req.Count
+	if count == 0 {
+		count = 1
+	}
+
+	cidrs := make([]string, 0, count)
+	for len(cidrs) < count {
+		ipNet, err := cidr.FirstAvailableNet(*parent, used, req.MaskLen)
+		if err != nil {
+			if len(cidrs) == 0 {
+				return nil, errf.Newf(errf.CidrConflict, "no free /%d block in %s", req.MaskLen, req.ParentCidr)
+			}
+			break
+		}
+
+		cidrs = append(cidrs, ipNet.String())
+		used = append(used, ipNet)
+	}
+
+	return cidrs, nil
+}
+
+func (i *ipam) listBlocks(kt *kit.Kit, rules ...*filter.AtomRule) ([]coreipam.Block, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(rules...),
+		Page:   core.NewDefaultBasePage(),
+	}
+
+	blocks := make([]coreipam.Block, 0)
+	for {
+		result, err := i.client.DataService().Global.IPAMBlock.List(kt, listReq)
+		if err != nil {
+			logs.Errorf("list ipam block failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		blocks = append(blocks, result.Details...)
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return blocks, nil
+}
+
+func scopeRules(scope *csipam.Scope) []*filter.AtomRule {
+	rules := make([]*filter.AtomRule, 0)
+	if scope == nil {
+		return rules
+	}
+
+	if len(scope.BkCloudIDs) != 0 {
+		rules = append(rules, tools.RuleIn("bk_cloud_id", scope.BkCloudIDs))
+	}
+	if len(scope.Vendors) != 0 {
+		rules = append(rules, tools.RuleIn("vendor", scope.Vendors))
+	}
+	if len(scope.AccountIDs) != 0 {
+		rules = append(rules, tools.RuleIn("account_id", scope.AccountIDs))
+	}
+	if len(scope.Regions) != 0 {
+		rules = append(rules, tools.RuleIn("region", scope.Regions))
+	}
+	if len(scope.BkBizIDs) != 0 {
+		rules = append(rules, tools.RuleIn("bk_biz_id", scope.BkBizIDs))
+	}
+
+	return rules
+}
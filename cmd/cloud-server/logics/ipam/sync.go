@@ -0,0 +1,325 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package ipam
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	coreipam "hcm/pkg/api/core/ipam"
+	dataservice "hcm/pkg/api/data-service"
+	dsipam "hcm/pkg/api/data-service/ipam"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// SyncAccountBlocks 根据账号下已同步的vpc、子网网段刷新地址块索引，已删除的vpc、子网对应的地址块一并删除
+func (i *ipam) SyncAccountBlocks(kt *kit.Kit, accountID string) error {
+	vpcs, err := i.listVpcs(kt, tools.RuleEqual("account_id", accountID))
+	if err != nil {
+		return err
+	}
+
+	expects, err := i.buildBlocks(kt, vpcs)
+	if err != nil {
+		return err
+	}
+
+	exists, err := i.listBlocks(kt, tools.RuleEqual("account_id", accountID),
+		tools.RuleNotEqual("type", enumor.IPAMReservedBlock))
+	if err != nil {
+		return err
+	}
+
+	return i.applyBlocks(kt, expects, exists)
+}
+
+// SyncVpcBlocks 根据指定vpc及其子网的网段刷新地址块索引
+func (i *ipam) SyncVpcBlocks(kt *kit.Kit, vpcIDs []string) error {
+	for _, ids := range slice.Split(slice.Unique(vpcIDs), int(filter.DefaultMaxInLimit)) {
+		vpcs, err := i.listVpcs(kt, tools.RuleIn("id", ids))
+		if err != nil {
+			return err
+		}
+
+		expects, err := i.buildBlocks(kt, vpcs)
+		if err != nil {
+			return err
+		}
+
+		exists, err := i.listBlocks(kt, tools.RuleIn("vpc_id", ids),
+			tools.RuleNotEqual("type", enumor.IPAMReservedBlock))
+		if err != nil {
+			return err
+		}
+
+		if err = i.applyBlocks(kt, expects, exists); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyBlocks 删除索引中已不存在的地址块，再创建新增的地址块，云区域、业务变更的地址块通过删除后重建更新
+func (i *ipam) applyBlocks(kt *kit.Kit, expects []dsipam.BlockCreate, exists []coreipam.Block) error {
+	existMap := make(map[string]struct{}, len(exists))
+	for _, one := range exists {
+		existMap[blockKey(one.Type, one.ResID, one.Cidr, one.BkCloudID, one.BkBizID)] = struct{}{}
+	}
+
+	expectMap := make(map[string]struct{}, len(expects))
+	toCreate := make([]dsipam.BlockCreate, 0)
+	for _, one := range expects {
+		key := blockKey(one.Type, one.ResID, one.Cidr, one.BkCloudID, one.BkBizID)
+		if _, exist := expectMap[key]; exist {
+			continue
+		}
+		expectMap[key] = struct{}{}
+
+		if _, exist := existMap[key]; !exist {
+			toCreate = append(toCreate, one)
+		}
+	}
+
+	toDelete := make([]string, 0)
+	for _, one := range exists {
+		if _, exist := expectMap[blockKey(one.Type, one.ResID, one.Cidr, one.BkCloudID, one.BkBizID)]; !exist {
+			toDelete = append(toDelete, one.ID)
+		}
+	}
+
+	for _, ids := range slice.Split(toDelete, int(core.DefaultMaxPageLimit)) {
+		req := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", ids)}
+		if err := i.client.DataService().Global.IPAMBlock.BatchDelete(kt, req); err != nil {
+			logs.Errorf("delete ipam block failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+			return err
+		}
+	}
+
+	for _, blocks := range slice.Split(toCreate, int(core.DefaultMaxPageLimit)) {
+		req := &dsipam.BatchCreateReq{Blocks: blocks}
+		if _, err := i.client.DataService().Global.IPAMBlock.BatchCreate(kt, req); err != nil {
+			logs.Errorf("create ipam block failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func blockKey(typ enumor.IPAMBlockType, resID, cidr string, bkCloudID, bkBizID int64) string {
+	return fmt.Sprintf("%s/%s/%s/%d/%d", typ, resID, cidr, bkCloudID, bkBizID)
+}
+
+// buildBlocks 根据vpc及其子网的网段生成地址块，子网地址块使用所属vpc的云区域
+func (i *ipam) buildBlocks(kt *kit.Kit, vpcs []corecloud.BaseVpc) ([]dsipam.BlockCreate, error) {
+	blocks := make([]dsipam.BlockCreate, 0)
+	for _, batch := range slice.Split(vpcs, int(filter.DefaultMaxInLimit)) {
+		vpcMap := make(map[string]corecloud.BaseVpc, len(batch))
+		vendorIDs := make(map[enumor.Vendor][]string)
+		for _, vpc := range batch {
+			vpcMap[vpc.ID] = vpc
+			vendorIDs[vpc.Vendor] = append(vendorIDs[vpc.Vendor], vpc.ID)
+		}
+
+		for vendor, ids := range vendorIDs {
+			cidrMap, err := i.listVpcCidrs(kt, vendor, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, id := range ids {
+				vpc := vpcMap[id]
+				for _, one := range cidrMap[id] {
+					blocks = append(blocks, dsipam.BlockCreate{
+						Type:      enumor.IPAMVpcBlock,
+						ResID:     vpc.ID,
+						Vendor:    vpc.Vendor,
+						AccountID: vpc.AccountID,
+						Region:    vpc.Region,
+						VpcID:     vpc.ID,
+						BkCloudID: vpc.BkCloudID,
+						BkBizID:   vpc.BkBizID,
+						Cidr:      one,
+					})
+				}
+			}
+		}
+
+		subnets, err := i.listSubnets(kt, converter.MapKeyToStringSlice(vpcMap))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, subnet := range subnets {
+			vpc, exist := vpcMap[subnet.VpcID]
+			if !exist {
+				continue
+			}
+
+			for _, one := range append(subnet.Ipv4Cidr, subnet.Ipv6Cidr...) {
+				blocks = append(blocks, dsipam.BlockCreate{
+					Type:      enumor.IPAMSubnetBlock,
+					ResID:     subnet.ID,
+					Vendor:    subnet.Vendor,
+					AccountID: subnet.AccountID,
+					Region:    subnet.Region,
+					VpcID:     subnet.VpcID,
+					BkCloudID: vpc.BkCloudID,
+					BkBizID:   subnet.BkBizID,
+					Cidr:      one,
+				})
+			}
+		}
+	}
+
+	return blocks, nil
+}
+
+func (i *ipam) listVpcs(kt *kit.Kit, rule *filter.AtomRule) ([]corecloud.BaseVpc, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(rule),
+		Page:   core.NewDefaultBasePage(),
+	}
+
+	vpcs := make([]corecloud.BaseVpc, 0)
+	for {
+		result, err := i.client.DataService().Global.Vpc.List(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list vpc failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		vpcs = append(vpcs, result.Details...)
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return vpcs, nil
+}
+
+func (i *ipam) listSubnets(kt *kit.Kit, vpcIDs []string) ([]corecloud.BaseSubnet, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("vpc_id", vpcIDs),
+		Page:   core.NewDefaultBasePage(),
+	}
+
+	subnets := make([]corecloud.BaseSubnet, 0)
+	for {
+		result, err := i.client.DataService().Global.Subnet.List(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list subnet failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		subnets = append(subnets, result.Details...)
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return subnets, nil
+}
+
+// listVpcCidrs 从vpc的扩展信息中获取网段，gcp vpc 不包含网段，仅由其子网网段构成地址块
+func (i *ipam) listVpcCidrs(kt *kit.Kit, vendor enumor.Vendor, ids []string) (map[string][]string, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   core.NewDefaultBasePage(),
+	}
+
+	cidrMap := make(map[string][]string, len(ids))
+	switch vendor {
+	case enumor.TCloud:
+		result, err := i.client.DataService().TCloud.Vpc.ListVpcExt(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list tcloud vpc failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		for _, vpc := range result.Details {
+			if vpc.Extension == nil {
+				continue
+			}
+			for _, one := range vpc.Extension.Cidr {
+				cidrMap[vpc.ID] = append(cidrMap[vpc.ID], one.Cidr)
+			}
+		}
+
+	case enumor.Aws:
+		result, err := i.client.DataService().Aws.Vpc.ListVpcExt(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list aws vpc failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		for _, vpc := range result.Details {
+			if vpc.Extension == nil {
+				continue
+			}
+			for _, one := range vpc.Extension.Cidr {
+				cidrMap[vpc.ID] = append(cidrMap[vpc.ID], one.Cidr)
+			}
+		}
+
+	case enumor.Azure:
+		result, err := i.client.DataService().Azure.Vpc.ListVpcExt(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list azure vpc failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		for _, vpc := range result.Details {
+			if vpc.Extension == nil {
+				continue
+			}
+			for _, one := range vpc.Extension.Cidr {
+				cidrMap[vpc.ID] = append(cidrMap[vpc.ID], one.Cidr)
+			}
+		}
+
+	case enumor.HuaWei:
+		result, err := i.client.DataService().HuaWei.Vpc.ListVpcExt(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list huawei vpc failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+		for _, vpc := range result.Details {
+			if vpc.Extension == nil {
+				continue
+			}
+			for _, one := range vpc.Extension.Cidr {
+				cidrMap[vpc.ID] = append(cidrMap[vpc.ID], one.Cidr)
+			}
+		}
+
+	default:
+		// gcp 等厂商的vpc不包含网段
+	}
+
+	return cidrMap, nil
+}
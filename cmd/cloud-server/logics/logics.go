@@ -21,10 +21,15 @@
 package logics
 
 import (
+	"hcm/cmd/cloud-server/logics/admission"
 	"hcm/cmd/cloud-server/logics/audit"
 	"hcm/cmd/cloud-server/logics/cvm"
+	"hcm/cmd/cloud-server/logics/dependency"
 	"hcm/cmd/cloud-server/logics/disk"
 	"hcm/cmd/cloud-server/logics/eip"
+	"hcm/cmd/cloud-server/logics/ipam"
+	securitygroup "hcm/cmd/cloud-server/logics/security-group"
+	"hcm/cmd/cloud-server/logics/topology"
 	"hcm/pkg/client"
 	"hcm/pkg/thirdparty/esb"
 )
@@ -35,6 +40,16 @@ type Logics struct {
 	Disk  disk.Interface
 	Cvm   cvm.Interface
 	Eip   eip.Interface
+	// Admission 准入策略校验
+	Admission admission.Interface
+	// SecurityGroup 安全组规则风险分析及暴露面计算
+	SecurityGroup securitygroup.Interface
+	// IPAM 地址块索引、网段冲突检测及空闲网段推荐
+	IPAM ipam.Interface
+	// Topology 资源拓扑图
+	Topology topology.Interface
+	// Dependency 资源删除前依赖检查
+	Dependency dependency.Interface
 }
 
 // NewLogics create a new cloud server logics.
@@ -47,5 +62,11 @@ func NewLogics(c *client.ClientSet, esbClient esb.Client) *Logics {
 		Disk:  disk.NewDisk(c, auditLogics),
 		Cvm:   cvm.NewCvm(c, auditLogics, eipLogics, diskLogics, esbClient),
 		Eip:   eip.NewEip(c, auditLogics),
+
+		Admission:     admission.NewAdmission(c),
+		SecurityGroup: securitygroup.NewSecurityGroup(c),
+		IPAM:          ipam.NewIPAM(c),
+		Topology:      topology.NewTopology(c),
+		Dependency:    dependency.NewDependency(c),
 	}
 }
@@ -0,0 +1,82 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package logicsrecycle
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	corerp "hcm/pkg/api/core/recycle-policy"
+	dsrr "hcm/pkg/api/data-service/recycle-record"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+)
+
+// ListPolicy 获取资源类型下所有启用的回收策略
+func ListPolicy(kt *kit.Kit, ds *dataservice.Client, resType enumor.CloudResourceType) (
+	[]corerp.RecyclePolicy, error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{"res_type": resType, "enabled": true}),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := ds.Global.RecyclePolicy.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list %s recycle policy failed, err: %v, rid: %s", resType, err, kt.Rid)
+		return nil, err
+	}
+
+	return result.Details, nil
+}
+
+// GetPolicy 获取作用于业务下资源的回收策略，未配置回收策略时返回nil
+func GetPolicy(kt *kit.Kit, ds *dataservice.Client, resType enumor.CloudResourceType, bizID int64) (
+	*corerp.RecyclePolicy, error) {
+
+	policies, err := ListPolicy(kt, ds, resType)
+	if err != nil {
+		return nil, err
+	}
+
+	return corerp.PickPolicy(policies, bizID), nil
+}
+
+// ValidateRecoverRecords 只能恢复指定资源类型、处于等待回收状态的非关联回收记录
+func ValidateRecoverRecords(records *dsrr.ListResult, resType enumor.CloudResourceType) error {
+	for _, one := range records.Details {
+		if one.ResType != resType {
+			return fmt.Errorf("record: %s not is %s recycle record", one.ID, resType)
+		}
+
+		if one.Status != enumor.WaitingRecycleRecordStatus {
+			return fmt.Errorf("record: %s not is wait_recycle status", one.ID)
+		}
+
+		if one.RecycleType == enumor.RecycleTypeRelated {
+			return fmt.Errorf("related recycled %s(%s) can not be operated", resType, one.ResID)
+		}
+	}
+
+	return nil
+}
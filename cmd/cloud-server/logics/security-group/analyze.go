@@ -0,0 +1,397 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/criteria/enumor"
+)
+
+// adminPorts 对公网开放存在风险的管理类端口
+var adminPorts = []struct {
+	port int
+	name string
+}{
+	{port: 22, name: "ssh"},
+	{port: 23, name: "telnet"},
+	{port: 3389, name: "rdp"},
+	{port: 1433, name: "sqlserver"},
+	{port: 1521, name: "oracle"},
+	{port: 2375, name: "docker"},
+	{port: 3306, name: "mysql"},
+	{port: 5432, name: "postgresql"},
+	{port: 6379, name: "redis"},
+	{port: 9200, name: "elasticsearch"},
+	{port: 11211, name: "memcached"},
+	{port: 27017, name: "mongodb"},
+}
+
+// worldSources 表示任意地址的对端
+var worldSources = map[string]struct{}{
+	"0.0.0.0/0": {},
+	"::/0":      {},
+	"*":         {},
+	"any":       {},
+	"internet":  {},
+}
+
+// exposureProtocols 暴露面计算时区分的协议，all 协议的规则作用于所有协议
+var exposureProtocols = []string{"tcp", "udp", "icmp", "icmpv6"}
+
+// parsedRule 解析过端口的归一化规则
+type parsedRule struct {
+	proto.NormalizedSGRule
+	ports portSet
+}
+
+// exposure 协议 -> 对任意地址放通的端口集合
+type exposure map[string]portSet
+
+// parseRules 解析规则端口，并按匹配顺序对规则排序。协议端口无法解析的规则(如引用了协议端口模版)不参与分析。
+// 优先级相同时拒绝规则先于放通规则匹配，其余保持原有顺序。
+func parseRules(rules []proto.NormalizedSGRule, ruleType enumor.SecurityGroupRuleType) []parsedRule {
+	parsed := make([]parsedRule, 0, len(rules))
+	for _, one := range rules {
+		if one.Type != ruleType || len(one.Protocol) == 0 {
+			continue
+		}
+
+		ports, err := parsePorts(one.Ports)
+		if err != nil {
+			continue
+		}
+		parsed = append(parsed, parsedRule{NormalizedSGRule: one, ports: ports})
+	}
+
+	sort.SliceStable(parsed, func(i, j int) bool {
+		if parsed[i].Priority != parsed[j].Priority {
+			return parsed[i].Priority < parsed[j].Priority
+		}
+		return parsed[i].Action == enumor.SGRuleDrop && parsed[j].Action != enumor.SGRuleDrop
+	})
+
+	return parsed
+}
+
+func isWorldSource(source string) bool {
+	_, exist := worldSources[strings.ToLower(strings.TrimSpace(source))]
+	return exist
+}
+
+func hasWorldSource(rule parsedRule) bool {
+	for _, source := range rule.Sources {
+		if isWorldSource(source) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sourceContains 判断对端地址 a 是否包含对端地址 b，非CIDR格式的地址(如azure的服务标记)只与自身相同时包含
+func sourceContains(a, b string) bool {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+	if a == b || a == "*" || a == "any" {
+		return true
+	}
+
+	netA, ok := parseCIDR(a)
+	if !ok {
+		return false
+	}
+	netB, ok := parseCIDR(b)
+	if !ok {
+		return false
+	}
+
+	onesA, bitsA := netA.Mask.Size()
+	onesB, bitsB := netB.Mask.Size()
+	return bitsA == bitsB && onesA <= onesB && netA.Contains(netB.IP)
+}
+
+func parseCIDR(value string) (*net.IPNet, bool) {
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, false
+		}
+		if ip.To4() != nil {
+			value += "/32"
+		} else {
+			value += "/128"
+		}
+	}
+
+	_, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, false
+	}
+
+	return ipNet, true
+}
+
+// ruleCovers 判断规则 a 匹配的流量是否完全包含规则 b 匹配的流量
+func ruleCovers(a, b parsedRule) bool {
+	if a.Protocol != allProtocol && a.Protocol != b.Protocol {
+		return false
+	}
+
+	if !a.ports.contains(b.ports) {
+		return false
+	}
+
+	if len(b.Sources) == 0 && len(b.RemoteRefs) == 0 {
+		return false
+	}
+
+	for _, source := range b.Sources {
+		covered := false
+		for _, candidate := range a.Sources {
+			if sourceContains(candidate, source) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+
+	for _, ref := range b.RemoteRefs {
+		covered := false
+		for _, candidate := range a.RemoteRefs {
+			if candidate == ref {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AnalyzeRules 分析同一个安全组下的规则风险，包括对公网开放管理类端口、被完全覆盖的规则
+func AnalyzeRules(rules []proto.NormalizedSGRule) []proto.SGRisk {
+	risks := make([]proto.SGRisk, 0)
+	for _, ruleType := range []enumor.SecurityGroupRuleType{enumor.Ingress, enumor.Egress} {
+		parsed := parseRules(rules, ruleType)
+		risks = append(risks, analyzeCoveredRules(parsed)...)
+		if ruleType == enumor.Ingress {
+			risks = append(risks, analyzeWorldOpenAdminPorts(parsed)...)
+		}
+	}
+
+	return risks
+}
+
+// analyzeCoveredRules 规则被先匹配的规则完全覆盖时，动作相同则为冗余规则，动作相反则规则永远不会生效
+func analyzeCoveredRules(rules []parsedRule) []proto.SGRisk {
+	risks := make([]proto.SGRisk, 0)
+	for j := range rules {
+		for i := 0; i < j; i++ {
+			if rules[i].ID == rules[j].ID || !ruleCovers(rules[i], rules[j]) {
+				continue
+			}
+
+			risk := proto.SGRisk{
+				Type:    enumor.RedundantSGRule,
+				Level:   enumor.SGRiskLow,
+				RuleIDs: []string{rules[j].ID, rules[i].ID},
+				Message: fmt.Sprintf("%s rule %s is fully covered by rule %s with the same action", rules[j].Type,
+					rules[j].ID, rules[i].ID),
+			}
+			if rules[i].Action != rules[j].Action {
+				risk.Type = enumor.ShadowedSGRule
+				risk.Level = enumor.SGRiskMedium
+				risk.Message = fmt.Sprintf("%s rule %s is shadowed by rule %s with opposite action and never takes "+
+					"effect", rules[j].Type, rules[j].ID, rules[i].ID)
+			}
+			risks = append(risks, risk)
+			break
+		}
+	}
+
+	return risks
+}
+
+// analyzeWorldOpenAdminPorts 只有对任意地址实际生效的放通端口才会被判定为风险，已被先匹配的拒绝规则拦截的端口不计入
+func analyzeWorldOpenAdminPorts(rules []parsedRule) []proto.SGRisk {
+	contributions := worldContributions(rules)
+	indexes := make([]int, 0, len(contributions))
+	for idx := range contributions {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	risks := make([]proto.SGRisk, 0)
+	for _, idx := range indexes {
+		rule, opened := rules[idx], contributions[idx]
+		if rule.Protocol != "tcp" && rule.Protocol != allProtocol {
+			continue
+		}
+
+		ports := make([]string, 0)
+		for _, admin := range adminPorts {
+			if opened["tcp"].containsPort(admin.port) {
+				ports = append(ports, fmt.Sprintf("%d(%s)", admin.port, admin.name))
+			}
+		}
+		if len(ports) == 0 {
+			continue
+		}
+
+		risks = append(risks, proto.SGRisk{
+			Type:    enumor.WorldOpenAdminPort,
+			Level:   enumor.SGRiskHigh,
+			RuleIDs: []string{rule.ID},
+			Message: fmt.Sprintf("rule %s opens admin ports %s to the internet", rule.ID, strings.Join(ports, ", ")),
+		})
+	}
+
+	return risks
+}
+
+// worldContributions 按匹配顺序计算每条放通规则对任意地址实际放通的协议端口，返回规则下标 -> 实际放通的端口
+func worldContributions(rules []parsedRule) map[int]exposure {
+	decided := make(exposure)
+	contributions := make(map[int]exposure)
+	for idx, rule := range rules {
+		if !hasWorldSource(rule) {
+			continue
+		}
+
+		opened := make(exposure)
+		for _, protocol := range ruleProtocols(rule.Protocol) {
+			remains := rule.ports.subtract(decided[protocol])
+			decided[protocol] = decided[protocol].union(rule.ports)
+			if rule.Action == enumor.SGRuleAccept && !remains.isEmpty() {
+				opened[protocol] = remains
+			}
+		}
+
+		if len(opened) != 0 {
+			contributions[idx] = opened
+		}
+	}
+
+	return contributions
+}
+
+func ruleProtocols(protocol string) []string {
+	if protocol == allProtocol {
+		return exposureProtocols
+	}
+
+	return []string{protocol}
+}
+
+// groupExposure 计算单个安全组入站方向对任意地址放通的协议端口
+func groupExposure(rules []proto.NormalizedSGRule) exposure {
+	result := make(exposure)
+	for _, opened := range worldContributions(parseRules(rules, enumor.Ingress)) {
+		result = result.union(opened)
+	}
+
+	return result
+}
+
+func (e exposure) union(other exposure) exposure {
+	result := make(exposure)
+	for protocol, ports := range e {
+		result[protocol] = ports
+	}
+	for protocol, ports := range other {
+		result[protocol] = result[protocol].union(ports)
+	}
+
+	return result
+}
+
+func (e exposure) intersect(other exposure) exposure {
+	result := make(exposure)
+	for protocol, ports := range e {
+		if both := ports.intersect(other[protocol]); !both.isEmpty() {
+			result[protocol] = both
+		}
+	}
+
+	return result
+}
+
+// exposedPorts 转换为接口返回格式，所有协议的所有端口均放通时合并为一条 all 协议的记录
+func (e exposure) exposedPorts() []proto.ExposedPort {
+	allOpened := true
+	for _, protocol := range exposureProtocols {
+		if !e[protocol].contains(fullPortSet()) {
+			allOpened = false
+			break
+		}
+	}
+	if allOpened {
+		return []proto.ExposedPort{{Protocol: allProtocol, Ports: allPorts}}
+	}
+
+	protocols := make([]string, 0, len(e))
+	for protocol, ports := range e {
+		if !ports.isEmpty() {
+			protocols = append(protocols, protocol)
+		}
+	}
+	sort.Strings(protocols)
+
+	result := make([]proto.ExposedPort, 0, len(protocols))
+	for _, protocol := range protocols {
+		result = append(result, proto.ExposedPort{Protocol: protocol, Ports: e[protocol].String()})
+	}
+
+	return result
+}
+
+// effectiveInboundRules 返回生效的入站放通规则，被先匹配的拒绝规则完全覆盖的放通规则不会生效
+func effectiveInboundRules(rules []proto.NormalizedSGRule) []proto.NormalizedSGRule {
+	parsed := parseRules(rules, enumor.Ingress)
+	result := make([]proto.NormalizedSGRule, 0)
+	for j, rule := range parsed {
+		if rule.Action != enumor.SGRuleAccept {
+			continue
+		}
+
+		shadowed := false
+		for i := 0; i < j; i++ {
+			if parsed[i].Action == enumor.SGRuleDrop && ruleCovers(parsed[i], rule) {
+				shadowed = true
+				break
+			}
+		}
+		if !shadowed {
+			result = append(result, rule.NormalizedSGRule)
+		}
+	}
+
+	return result
+}
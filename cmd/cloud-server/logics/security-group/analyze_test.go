@@ -0,0 +1,99 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"testing"
+
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/criteria/enumor"
+)
+
+func rule(id string, priority int64, protocol, ports string, action enumor.SGRuleAction,
+	sources ...string) proto.NormalizedSGRule {
+
+	return proto.NormalizedSGRule{ID: id, SecurityGroupID: "sg", Type: enumor.Ingress, Priority: priority,
+		Protocol: protocol, Ports: ports, Sources: sources, Action: action}
+}
+
+func riskTypes(risks []proto.SGRisk) map[enumor.SGRiskType][]string {
+	result := make(map[enumor.SGRiskType][]string)
+	for _, one := range risks {
+		result[one.Type] = append(result[one.Type], one.RuleIDs[0])
+	}
+	return result
+}
+
+func TestAnalyzeRules(t *testing.T) {
+	rules := []proto.NormalizedSGRule{
+		// 22 is denied from the internet before the accept rule, so only 3389 is really opened.
+		rule("deny-ssh", 1, "tcp", "22", enumor.SGRuleDrop, "0.0.0.0/0"),
+		rule("open-admin", 2, "tcp", "22,3389", enumor.SGRuleAccept, "0.0.0.0/0"),
+		rule("office-ssh", 3, "tcp", "22", enumor.SGRuleAccept, "10.0.0.0/8"),
+		rule("office-ssh-host", 4, "tcp", "22", enumor.SGRuleAccept, "10.0.1.1"),
+		rule("web", 5, "tcp", "80-443", enumor.SGRuleAccept, "0.0.0.0/0"),
+	}
+
+	risks := riskTypes(AnalyzeRules(rules))
+	if ids := risks[enumor.WorldOpenAdminPort]; len(ids) != 1 || ids[0] != "open-admin" {
+		t.Errorf("unexpected world open admin port risks: %v", ids)
+	}
+	// office-ssh and office-ssh-host are covered by deny-ssh since 0.0.0.0/0 contains all ipv4 addresses.
+	if ids := risks[enumor.ShadowedSGRule]; len(ids) != 2 || ids[0] != "office-ssh" || ids[1] != "office-ssh-host" {
+		t.Errorf("unexpected shadowed rule risks: %v", ids)
+	}
+	if ids := risks[enumor.RedundantSGRule]; len(ids) != 0 {
+		t.Errorf("unexpected redundant rule risks: %v", ids)
+	}
+
+	redundant := []proto.NormalizedSGRule{
+		rule("wide", 1, "all", "ALL", enumor.SGRuleAccept, "10.0.0.0/8"),
+		rule("narrow", 2, "udp", "53", enumor.SGRuleAccept, "10.1.0.0/16"),
+		rule("other", 3, "udp", "53", enumor.SGRuleAccept, "192.168.0.0/16"),
+	}
+	risks = riskTypes(AnalyzeRules(redundant))
+	if ids := risks[enumor.RedundantSGRule]; len(ids) != 1 || ids[0] != "narrow" {
+		t.Errorf("unexpected redundant rule risks: %v", ids)
+	}
+}
+
+func TestGroupExposure(t *testing.T) {
+	rules := []proto.NormalizedSGRule{
+		rule("deny-ssh", 1, "tcp", "22", enumor.SGRuleDrop, "0.0.0.0/0"),
+		rule("open-tcp", 2, "tcp", "1-1024", enumor.SGRuleAccept, "0.0.0.0/0"),
+		rule("office", 3, "all", "ALL", enumor.SGRuleAccept, "10.0.0.0/8"),
+	}
+
+	ports := groupExposure(rules).exposedPorts()
+	if len(ports) != 1 || ports[0].Protocol != "tcp" || ports[0].Ports != "1-21,23-1024" {
+		t.Errorf("unexpected exposed ports: %+v", ports)
+	}
+
+	all := groupExposure([]proto.NormalizedSGRule{rule("any", 1, "all", "ALL", enumor.SGRuleAccept, "*")})
+	if ports = all.exposedPorts(); len(ports) != 1 || ports[0].Protocol != allProtocol {
+		t.Errorf("unexpected exposed ports: %+v", ports)
+	}
+
+	// azure subnet and network interface security groups must both accept the traffic.
+	nic := groupExposure([]proto.NormalizedSGRule{rule("web", 1, "tcp", "80,443", enumor.SGRuleAccept, "Internet")})
+	if ports = all.intersect(nic).exposedPorts(); len(ports) != 1 || ports[0].Ports != "80,443" {
+		t.Errorf("unexpected exposed ports: %+v", ports)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"strings"
+
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+)
+
+// cvmSGLevels 主机关联的安全组层级，同一层级内的安全组放通取并集，不同层级之间取交集。
+// azure子网和网络接口上的安全组为两个层级，流量需要同时被两个层级放通，其他云只有主机一个层级。
+type cvmSGLevels [][]string
+
+func (levels cvmSGLevels) sgIDs() []string {
+	ids := make([]string, 0)
+	for _, level := range levels {
+		ids = append(ids, level...)
+	}
+
+	return slice.Unique(ids)
+}
+
+// CvmExposure 计算主机关联的所有安全组叠加后的入站暴露面
+func (sg *securityGroup) CvmExposure(kt *kit.Kit, cvms []corecvm.BaseCvm) ([]proto.CvmSGExposure, error) {
+	cvmLevels := make(map[string]cvmSGLevels)
+	relCvmIDs := make([]string, 0)
+	gcpCvms := make([]corecvm.BaseCvm, 0)
+	for _, cvm := range cvms {
+		switch cvm.Vendor {
+		case enumor.Azure:
+			levels, err := sg.listAzureCvmSGLevels(kt, cvm.ID)
+			if err != nil {
+				return nil, err
+			}
+			cvmLevels[cvm.ID] = levels
+		case enumor.Gcp:
+			gcpCvms = append(gcpCvms, cvm)
+		default:
+			relCvmIDs = append(relCvmIDs, cvm.ID)
+		}
+	}
+
+	if len(relCvmIDs) != 0 {
+		rels, err := sg.client.DataService().Global.SGCvmRel.ListWithSecurityGroup(kt.Ctx, kt.Header(),
+			&dataproto.SGCvmRelWithSecurityGroupListReq{CvmIDs: relCvmIDs})
+		if err != nil {
+			logs.Errorf("list security group by cvm ids failed, err: %v, cvm ids: %v, rid: %s", err, relCvmIDs,
+				kt.Rid)
+			return nil, err
+		}
+
+		relSGIDs := make(map[string][]string)
+		for _, rel := range rels {
+			relSGIDs[rel.CvmID] = append(relSGIDs[rel.CvmID], rel.ID)
+		}
+		for _, cvmID := range relCvmIDs {
+			cvmLevels[cvmID] = cvmSGLevels{relSGIDs[cvmID]}
+		}
+	}
+
+	gcpExposures, err := sg.gcpCvmExposure(kt, gcpCvms)
+	if err != nil {
+		return nil, err
+	}
+
+	sgRules := make(map[string][]proto.NormalizedSGRule)
+	result := make([]proto.CvmSGExposure, 0, len(cvms))
+	for _, cvm := range cvms {
+		if cvm.Vendor == enumor.Gcp {
+			result = append(result, gcpExposures[cvm.ID])
+			continue
+		}
+
+		levels := cvmLevels[cvm.ID]
+		for _, sgID := range levels.sgIDs() {
+			if _, exist := sgRules[sgID]; exist {
+				continue
+			}
+
+			rules, err := sg.listNormalizedRules(kt, cvm.Vendor, sgID)
+			if err != nil {
+				return nil, err
+			}
+			sgRules[sgID] = rules
+		}
+
+		result = append(result, buildCvmExposure(cvm, levels, sgRules))
+	}
+
+	return result, nil
+}
+
+func buildCvmExposure(cvm corecvm.BaseCvm, levels cvmSGLevels,
+	sgRules map[string][]proto.NormalizedSGRule) proto.CvmSGExposure {
+
+	var opened exposure
+	inbound := make([]proto.NormalizedSGRule, 0)
+	for _, level := range levels {
+		if len(level) == 0 {
+			continue
+		}
+
+		levelOpened := make(exposure)
+		for _, sgID := range level {
+			levelOpened = levelOpened.union(groupExposure(sgRules[sgID]))
+			inbound = append(inbound, effectiveInboundRules(sgRules[sgID])...)
+		}
+
+		if opened == nil {
+			opened = levelOpened
+			continue
+		}
+		opened = opened.intersect(levelOpened)
+	}
+
+	return proto.CvmSGExposure{
+		CvmID:            cvm.ID,
+		Vendor:           cvm.Vendor,
+		SecurityGroupIDs: levels.sgIDs(),
+		PublicPorts:      opened.exposedPorts(),
+		InboundRules:     inbound,
+	}
+}
+
+// listAzureCvmSGLevels azure安全组关联在主机所在的子网和主机的网络接口上
+func (sg *securityGroup) listAzureCvmSGLevels(kt *kit.Kit, cvmID string) (cvmSGLevels, error) {
+	cvm, err := sg.client.DataService().Azure.Cvm.GetCvm(kt.Ctx, kt.Header(), cvmID)
+	if err != nil {
+		logs.Errorf("get azure cvm failed, err: %v, cvm id: %s, rid: %s", err, cvmID, kt.Rid)
+		return nil, err
+	}
+
+	levels := make(cvmSGLevels, 2)
+	if len(cvm.SubnetIDs) != 0 {
+		listReq := &core.ListReq{
+			Filter: tools.ContainersExpression("id", cvm.SubnetIDs),
+			Page:   core.NewDefaultBasePage(),
+		}
+		subnets, err := sg.client.DataService().Azure.Subnet.ListSubnetExt(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list azure subnet failed, err: %v, ids: %v, rid: %s", err, cvm.SubnetIDs, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range subnets.Details {
+			if one.Extension != nil && len(one.Extension.SecurityGroupID) != 0 {
+				levels[0] = append(levels[0], one.Extension.SecurityGroupID)
+			}
+		}
+	}
+
+	if cvm.Extension != nil && len(cvm.Extension.CloudNetworkInterfaceIDs) != 0 {
+		listReq := &core.ListReq{
+			Filter: tools.ContainersExpression("cloud_id", cvm.Extension.CloudNetworkInterfaceIDs),
+			Page:   core.NewDefaultBasePage(),
+		}
+		nis, err := sg.client.DataService().Azure.NetworkInterface.ListNetworkInterfaceExt(kt.Ctx, kt.Header(),
+			listReq)
+		if err != nil {
+			logs.Errorf("list azure network interface failed, err: %v, cloud ids: %v, rid: %s", err,
+				cvm.Extension.CloudNetworkInterfaceIDs, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range nis.Details {
+			if one.Extension != nil && len(converter.PtrToVal(one.Extension.SecurityGroupID)) != 0 {
+				levels[1] = append(levels[1], *one.Extension.SecurityGroupID)
+			}
+		}
+	}
+
+	return levels, nil
+}
+
+// gcpCvmExposure gcp防火墙规则作用于vpc，未指定目标网络标签和服务账号的规则作用于vpc下所有主机。
+// 由于主机的网络标签和服务账号未同步，指定了目标的规则无法确定是否作用于主机，仅返回规则ID。
+func (sg *securityGroup) gcpCvmExposure(kt *kit.Kit, cvms []corecvm.BaseCvm) (map[string]proto.CvmSGExposure,
+	error) {
+
+	result := make(map[string]proto.CvmSGExposure, len(cvms))
+	if len(cvms) == 0 {
+		return result, nil
+	}
+
+	vpcIDs := make([]string, 0)
+	for _, cvm := range cvms {
+		vpcIDs = append(vpcIDs, cvm.VpcIDs...)
+	}
+
+	rules, err := sg.listGcpFirewallRules(kt, slice.Unique(vpcIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cvm := range cvms {
+		applied := make([]proto.NormalizedSGRule, 0)
+		unresolved := make([]string, 0)
+		for _, rule := range rules {
+			if !slice.IsItemInSlice(cvm.VpcIDs, rule.VpcId) || rule.Disabled ||
+				!strings.EqualFold(rule.Type, string(enumor.Ingress)) {
+				continue
+			}
+
+			if len(rule.TargetTags) != 0 || len(rule.TargetServiceAccounts) != 0 {
+				unresolved = append(unresolved, rule.ID)
+				continue
+			}
+			applied = append(applied, NormalizeGcpFirewallRules([]corecloud.GcpFirewallRule{rule})...)
+		}
+
+		result[cvm.ID] = proto.CvmSGExposure{
+			CvmID:             cvm.ID,
+			Vendor:            cvm.Vendor,
+			SecurityGroupIDs:  cvm.VpcIDs,
+			PublicPorts:       groupExposure(applied).exposedPorts(),
+			InboundRules:      effectiveInboundRules(applied),
+			UnresolvedRuleIDs: unresolved,
+		}
+	}
+
+	return result, nil
+}
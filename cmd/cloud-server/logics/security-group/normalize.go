@@ -0,0 +1,280 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"fmt"
+	"strings"
+
+	proto "hcm/pkg/api/cloud-server"
+	corecloud "hcm/pkg/api/core/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+)
+
+const (
+	// allProtocol 归一化后表示所有协议的取值
+	allProtocol = "all"
+)
+
+// normalizeProtocol 将各云厂商的协议统一为小写协议名，所有协议统一为 all
+func normalizeProtocol(protocol string) string {
+	switch strings.ToLower(strings.TrimSpace(protocol)) {
+	case "", "-1", "*", "any", "all":
+		return allProtocol
+	case "6":
+		return "tcp"
+	case "17":
+		return "udp"
+	case "1":
+		return "icmp"
+	case "58":
+		return "icmpv6"
+	default:
+		return strings.ToLower(strings.TrimSpace(protocol))
+	}
+}
+
+// normalizeRulePorts 只有tcp、udp协议区分端口，其他协议的端口统一为 ALL
+func normalizeRulePorts(protocol, ports string) string {
+	if protocol != "tcp" && protocol != "udp" && protocol != allProtocol {
+		return allPorts
+	}
+
+	ports = strings.TrimSpace(ports)
+	switch strings.ToLower(ports) {
+	case "", "all", "*", "-1", "any":
+		return allPorts
+	}
+
+	return ports
+}
+
+func appendNotEmpty(list []string, values ...*string) []string {
+	for _, value := range values {
+		if len(converter.PtrToVal(value)) != 0 {
+			list = append(list, *value)
+		}
+	}
+
+	return list
+}
+
+func appendRefs(refs []string, kind string, values ...string) []string {
+	for _, value := range values {
+		if len(value) != 0 {
+			refs = append(refs, kind+":"+value)
+		}
+	}
+
+	return refs
+}
+
+// NormalizeTCloudRules normalize tcloud security group rules.
+func NormalizeTCloudRules(rules []corecloud.TCloudSecurityGroupRule) []proto.NormalizedSGRule {
+	result := make([]proto.NormalizedSGRule, 0, len(rules))
+	for _, one := range rules {
+		rule := proto.NormalizedSGRule{
+			ID:              one.ID,
+			SecurityGroupID: one.SecurityGroupID,
+			Vendor:          enumor.TCloud,
+			Type:            one.Type,
+			Priority:        one.CloudPolicyIndex,
+			Sources:         appendNotEmpty(make([]string, 0), one.IPv4Cidr, one.IPv6Cidr),
+			RemoteRefs:      appendRefs(nil, "security_group", converter.PtrToVal(one.CloudTargetSecurityGroupID)),
+			Action:          enumor.SGRuleDrop,
+		}
+		rule.RemoteRefs = appendRefs(rule.RemoteRefs, "address", converter.PtrToVal(one.CloudAddressID))
+		rule.RemoteRefs = appendRefs(rule.RemoteRefs, "address_group", converter.PtrToVal(one.CloudAddressGroupID))
+
+		// 使用了协议端口模版的规则，协议端口无法展开，不参与端口相关的分析
+		if len(converter.PtrToVal(one.CloudServiceID)) == 0 && len(converter.PtrToVal(one.CloudServiceGroupID)) == 0 {
+			rule.Protocol = normalizeProtocol(converter.PtrToVal(one.Protocol))
+			rule.Ports = normalizeRulePorts(rule.Protocol, converter.PtrToVal(one.Port))
+		}
+
+		if strings.EqualFold(one.Action, "ACCEPT") {
+			rule.Action = enumor.SGRuleAccept
+		}
+		result = append(result, rule)
+	}
+
+	return result
+}
+
+// NormalizeAwsRules normalize aws security group rules, aws security group rules only support accept.
+func NormalizeAwsRules(rules []corecloud.AwsSecurityGroupRule) []proto.NormalizedSGRule {
+	result := make([]proto.NormalizedSGRule, 0, len(rules))
+	for _, one := range rules {
+		rule := proto.NormalizedSGRule{
+			ID:              one.ID,
+			SecurityGroupID: one.SecurityGroupID,
+			Vendor:          enumor.Aws,
+			Type:            one.Type,
+			Protocol:        normalizeProtocol(converter.PtrToVal(one.Protocol)),
+			Sources:         appendNotEmpty(make([]string, 0), one.IPv4Cidr, one.IPv6Cidr),
+			RemoteRefs:      appendRefs(nil, "security_group", converter.PtrToVal(one.CloudTargetSecurityGroupID)),
+			Action:          enumor.SGRuleAccept,
+		}
+		rule.RemoteRefs = appendRefs(rule.RemoteRefs, "prefix_list", converter.PtrToVal(one.CloudPrefixListID))
+
+		from, to := converter.PtrToVal(one.FromPort), converter.PtrToVal(one.ToPort)
+		switch {
+		case one.FromPort == nil || one.ToPort == nil || from == -1 || to == -1:
+			rule.Ports = allPorts
+		case from == to:
+			rule.Ports = fmt.Sprintf("%d", from)
+		default:
+			rule.Ports = fmt.Sprintf("%d-%d", from, to)
+		}
+		rule.Ports = normalizeRulePorts(rule.Protocol, rule.Ports)
+
+		result = append(result, rule)
+	}
+
+	return result
+}
+
+// NormalizeHuaWeiRules normalize huawei security group rules.
+func NormalizeHuaWeiRules(rules []corecloud.HuaWeiSecurityGroupRule) []proto.NormalizedSGRule {
+	result := make([]proto.NormalizedSGRule, 0, len(rules))
+	for _, one := range rules {
+		rule := proto.NormalizedSGRule{
+			ID:              one.ID,
+			SecurityGroupID: one.SecurityGroupID,
+			Vendor:          enumor.HuaWei,
+			Type:            one.Type,
+			Priority:        one.Priority,
+			Protocol:        normalizeProtocol(one.Protocol),
+			Sources:         make([]string, 0),
+			RemoteRefs:      appendRefs(nil, "security_group", one.CloudRemoteGroupID),
+			Action:          enumor.SGRuleDrop,
+		}
+		rule.Ports = normalizeRulePorts(rule.Protocol, one.Port)
+		rule.RemoteRefs = appendRefs(rule.RemoteRefs, "address_group", one.CloudRemoteAddressGroupID)
+
+		switch {
+		case len(one.RemoteIPPrefix) != 0:
+			rule.Sources = append(rule.Sources, one.RemoteIPPrefix)
+		case len(rule.RemoteRefs) == 0:
+			// 华为云未指定对端时表示对应IP版本的所有地址
+			if strings.EqualFold(one.Ethertype, "IPv6") {
+				rule.Sources = append(rule.Sources, "::/0")
+			} else {
+				rule.Sources = append(rule.Sources, "0.0.0.0/0")
+			}
+		}
+
+		if strings.EqualFold(one.Action, "allow") {
+			rule.Action = enumor.SGRuleAccept
+		}
+		result = append(result, rule)
+	}
+
+	return result
+}
+
+// NormalizeAzureRules normalize azure security group rules.
+func NormalizeAzureRules(rules []corecloud.AzureSecurityGroupRule) []proto.NormalizedSGRule {
+	result := make([]proto.NormalizedSGRule, 0, len(rules))
+	for _, one := range rules {
+		rule := proto.NormalizedSGRule{
+			ID:              one.ID,
+			SecurityGroupID: one.SecurityGroupID,
+			Vendor:          enumor.Azure,
+			Type:            one.Type,
+			Priority:        int64(one.Priority),
+			Protocol:        normalizeProtocol(one.Protocol),
+			Action:          enumor.SGRuleDrop,
+		}
+
+		ports := appendNotEmpty(make([]string, 0), one.DestinationPortRange)
+		ports = appendNotEmpty(ports, one.DestinationPortRanges...)
+		rule.Ports = normalizeRulePorts(rule.Protocol, strings.Join(ports, ","))
+		if converter.PtrToVal(one.DestinationPortRange) == "*" {
+			rule.Ports = allPorts
+		}
+
+		// 入站规则的对端为源地址，出站规则的对端为目的地址
+		if one.Type == enumor.Egress {
+			rule.Sources = appendNotEmpty(make([]string, 0), one.DestinationAddressPrefix)
+			rule.Sources = appendNotEmpty(rule.Sources, one.DestinationAddressPrefixes...)
+			rule.RemoteRefs = appendRefs(nil, "application_security_group",
+				converter.PtrToSlice(one.CloudDestinationAppSecurityGroupIDs)...)
+		} else {
+			rule.Sources = appendNotEmpty(make([]string, 0), one.SourceAddressPrefix)
+			rule.Sources = appendNotEmpty(rule.Sources, one.SourceAddressPrefixes...)
+			rule.RemoteRefs = appendRefs(nil, "application_security_group",
+				converter.PtrToSlice(one.CloudSourceAppSecurityGroupIDs)...)
+		}
+
+		if strings.EqualFold(one.Access, "Allow") {
+			rule.Action = enumor.SGRuleAccept
+		}
+		result = append(result, rule)
+	}
+
+	return result
+}
+
+// NormalizeGcpFirewallRules normalize gcp firewall rules, gcp firewall rule belongs to vpc, so security group id
+// of normalized rule is vpc id. one firewall rule with multiple protocols is split into multiple normalized rules
+// with the same id, and disabled firewall rules are ignored.
+func NormalizeGcpFirewallRules(rules []corecloud.GcpFirewallRule) []proto.NormalizedSGRule {
+	result := make([]proto.NormalizedSGRule, 0, len(rules))
+	for _, one := range rules {
+		if one.Disabled {
+			continue
+		}
+
+		ruleType := enumor.Ingress
+		sources := one.SourceRanges
+		refs := appendRefs(nil, "tag", one.SourceTags...)
+		refs = appendRefs(refs, "service_account", one.SourceServiceAccounts...)
+		if strings.EqualFold(one.Type, string(enumor.Egress)) {
+			ruleType = enumor.Egress
+			sources = one.DestinationRanges
+			refs = nil
+		}
+
+		action, protocolSets := enumor.SGRuleAccept, one.Allowed
+		if len(one.Denied) != 0 {
+			action, protocolSets = enumor.SGRuleDrop, one.Denied
+		}
+
+		for _, set := range protocolSets {
+			rule := proto.NormalizedSGRule{
+				ID:              one.ID,
+				SecurityGroupID: one.VpcId,
+				Vendor:          enumor.Gcp,
+				Type:            ruleType,
+				Priority:        one.Priority,
+				Protocol:        normalizeProtocol(set.Protocol),
+				Sources:         append(make([]string, 0, len(sources)), sources...),
+				RemoteRefs:      refs,
+				Action:          action,
+			}
+			rule.Ports = normalizeRulePorts(rule.Protocol, strings.Join(set.Port, ","))
+			result = append(result, rule)
+		}
+	}
+
+	return result
+}
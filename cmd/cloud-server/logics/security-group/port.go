@@ -0,0 +1,180 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	minPort = 0
+	maxPort = 65535
+
+	// allPorts 归一化后表示所有端口的取值
+	allPorts = "ALL"
+)
+
+type portRange struct {
+	from int
+	to   int
+}
+
+// portSet 端口集合，由有序且互不重叠的端口区间组成
+type portSet []portRange
+
+func fullPortSet() portSet {
+	return portSet{{from: minPort, to: maxPort}}
+}
+
+// parsePorts 解析归一化后的端口，支持 ALL、22、80-443、80,443,1000-2000 格式
+func parsePorts(ports string) (portSet, error) {
+	ports = strings.TrimSpace(ports)
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("ports is empty")
+	}
+
+	if strings.EqualFold(ports, allPorts) {
+		return fullPortSet(), nil
+	}
+
+	set := make(portSet, 0)
+	for _, part := range strings.Split(ports, ",") {
+		part = strings.TrimSpace(part)
+		fromStr, toStr, isRange := strings.Cut(part, "-")
+		if !isRange {
+			toStr = fromStr
+		}
+
+		from, err := strconv.Atoi(strings.TrimSpace(fromStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid port: %s", part)
+		}
+		to, err := strconv.Atoi(strings.TrimSpace(toStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid port: %s", part)
+		}
+
+		if from < minPort || to > maxPort || from > to {
+			return nil, fmt.Errorf("invalid port range: %s", part)
+		}
+		set = append(set, portRange{from: from, to: to})
+	}
+
+	return set.normalize(), nil
+}
+
+// normalize 排序并合并相邻或重叠的区间
+func (s portSet) normalize() portSet {
+	if len(s) == 0 {
+		return portSet{}
+	}
+
+	sorted := make(portSet, len(s))
+	copy(sorted, s)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].from < sorted[j].from })
+
+	result := portSet{sorted[0]}
+	for _, one := range sorted[1:] {
+		last := &result[len(result)-1]
+		if one.from <= last.to+1 {
+			if one.to > last.to {
+				last.to = one.to
+			}
+			continue
+		}
+		result = append(result, one)
+	}
+
+	return result
+}
+
+func (s portSet) isEmpty() bool {
+	return len(s) == 0
+}
+
+func (s portSet) union(other portSet) portSet {
+	return append(append(portSet{}, s...), other...).normalize()
+}
+
+func (s portSet) subtract(other portSet) portSet {
+	result := make(portSet, 0)
+	for _, one := range s {
+		remains := portSet{one}
+		for _, sub := range other {
+			next := make(portSet, 0)
+			for _, r := range remains {
+				if sub.to < r.from || sub.from > r.to {
+					next = append(next, r)
+					continue
+				}
+				if sub.from > r.from {
+					next = append(next, portRange{from: r.from, to: sub.from - 1})
+				}
+				if sub.to < r.to {
+					next = append(next, portRange{from: sub.to + 1, to: r.to})
+				}
+			}
+			remains = next
+		}
+		result = append(result, remains...)
+	}
+
+	return result.normalize()
+}
+
+func (s portSet) intersect(other portSet) portSet {
+	return s.subtract(s.subtract(other))
+}
+
+// contains 判断 s 是否完全包含 other
+func (s portSet) contains(other portSet) bool {
+	return other.subtract(s).isEmpty()
+}
+
+func (s portSet) containsPort(port int) bool {
+	for _, one := range s {
+		if port >= one.from && port <= one.to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String 输出为归一化的端口格式
+func (s portSet) String() string {
+	if len(s) == 1 && s[0].from == minPort && s[0].to == maxPort {
+		return allPorts
+	}
+
+	parts := make([]string, 0, len(s))
+	for _, one := range s {
+		if one.from == one.to {
+			parts = append(parts, strconv.Itoa(one.from))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%d-%d", one.from, one.to))
+	}
+
+	return strings.Join(parts, ",")
+}
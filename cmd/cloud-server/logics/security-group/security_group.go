@@ -0,0 +1,296 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package securitygroup 安全组规则归一化、风险分析及主机入站暴露面计算
+package securitygroup
+
+import (
+	"fmt"
+
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// Interface define security group analysis interface.
+type Interface interface {
+	// AnalyzeRisk 分析安全组的规则风险及是否未被使用
+	AnalyzeRisk(kt *kit.Kit, sgs []corecloud.BaseSecurityGroup) ([]proto.SGRiskReport, error)
+	// AnalyzeGcpFirewallRisk 以vpc为维度分析gcp防火墙规则的风险
+	AnalyzeGcpFirewallRisk(kt *kit.Kit, rules []corecloud.GcpFirewallRule) []proto.SGRiskReport
+	// CvmExposure 计算主机关联的所有安全组叠加后的入站暴露面
+	CvmExposure(kt *kit.Kit, cvms []corecvm.BaseCvm) ([]proto.CvmSGExposure, error)
+}
+
+type securityGroup struct {
+	client *client.ClientSet
+}
+
+// NewSecurityGroup new security group analysis.
+func NewSecurityGroup(client *client.ClientSet) Interface {
+	return &securityGroup{
+		client: client,
+	}
+}
+
+// AnalyzeRisk 分析安全组的规则风险及是否未被使用
+func (sg *securityGroup) AnalyzeRisk(kt *kit.Kit, sgs []corecloud.BaseSecurityGroup) ([]proto.SGRiskReport,
+	error) {
+
+	usedIDs, err := sg.listUsedSGIDs(kt, sgs)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]proto.SGRiskReport, 0, len(sgs))
+	for _, one := range sgs {
+		rules, err := sg.listNormalizedRules(kt, one.Vendor, one.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		report := proto.SGRiskReport{
+			SecurityGroupID: one.ID,
+			Name:            one.Name,
+			Vendor:          one.Vendor,
+			AccountID:       one.AccountID,
+			BkBizID:         one.BkBizID,
+			RuleCount:       len(rules),
+			Rules:           rules,
+			Risks:           AnalyzeRules(rules),
+		}
+
+		if _, exist := usedIDs[one.ID]; !exist {
+			report.Risks = append(report.Risks, proto.SGRisk{
+				Type:    enumor.UnusedSecurityGroup,
+				Level:   enumor.SGRiskLow,
+				Message: fmt.Sprintf("security group %s is not associated with any cvm", one.ID),
+			})
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// AnalyzeGcpFirewallRisk 以vpc为维度分析gcp防火墙规则的风险
+func (sg *securityGroup) AnalyzeGcpFirewallRisk(kt *kit.Kit, rules []corecloud.GcpFirewallRule) []proto.SGRiskReport {
+	vpcRules := make(map[string][]corecloud.GcpFirewallRule)
+	vpcIDs := make([]string, 0)
+	for _, one := range rules {
+		if _, exist := vpcRules[one.VpcId]; !exist {
+			vpcIDs = append(vpcIDs, one.VpcId)
+		}
+		vpcRules[one.VpcId] = append(vpcRules[one.VpcId], one)
+	}
+
+	reports := make([]proto.SGRiskReport, 0, len(vpcIDs))
+	for _, vpcID := range vpcIDs {
+		normalized := NormalizeGcpFirewallRules(vpcRules[vpcID])
+		reports = append(reports, proto.SGRiskReport{
+			SecurityGroupID: vpcID,
+			Name:            vpcRules[vpcID][0].CloudVpcID,
+			Vendor:          enumor.Gcp,
+			AccountID:       vpcRules[vpcID][0].AccountID,
+			BkBizID:         vpcRules[vpcID][0].BkBizID,
+			RuleCount:       len(vpcRules[vpcID]),
+			Rules:           normalized,
+			Risks:           AnalyzeRules(normalized),
+		})
+	}
+
+	return reports
+}
+
+// listUsedSGIDs 查询已被使用的安全组，azure安全组关联在子网和网络接口上，其他云安全组关联在主机上
+func (sg *securityGroup) listUsedSGIDs(kt *kit.Kit, sgs []corecloud.BaseSecurityGroup) (map[string]struct{},
+	error) {
+
+	usedIDs := make(map[string]struct{})
+	relSGIDs := make([]string, 0)
+	for _, one := range sgs {
+		if one.Vendor != enumor.Azure {
+			relSGIDs = append(relSGIDs, one.ID)
+			continue
+		}
+
+		used, err := sg.isAzureSGUsed(kt, one.ID)
+		if err != nil {
+			return nil, err
+		}
+		if used {
+			usedIDs[one.ID] = struct{}{}
+		}
+	}
+
+	for _, ids := range slice.Split(relSGIDs, constant.BatchOperationMaxLimit) {
+		listReq := &core.ListReq{
+			Filter: tools.ContainersExpression("security_group_id", ids),
+			Page:   core.NewDefaultBasePage(),
+			Fields: []string{"security_group_id"},
+		}
+		for {
+			result, err := sg.client.DataService().Global.SGCvmRel.ListSgCvmRels(kt.Ctx, kt.Header(), listReq)
+			if err != nil {
+				logs.Errorf("list security group cvm rel failed, err: %v, sg ids: %v, rid: %s", err, ids, kt.Rid)
+				return nil, err
+			}
+
+			for _, rel := range result.Details {
+				usedIDs[rel.SecurityGroupID] = struct{}{}
+			}
+
+			if uint(len(result.Details)) < listReq.Page.Limit {
+				break
+			}
+			listReq.Page.Start += uint32(listReq.Page.Limit)
+		}
+	}
+
+	return usedIDs, nil
+}
+
+func (sg *securityGroup) isAzureSGUsed(kt *kit.Kit, sgID string) (bool, error) {
+	listReq := &core.ListReq{
+		Filter: &filter.Expression{
+			Op: filter.And,
+			Rules: []filter.RuleFactory{
+				&filter.AtomRule{Field: "extension.security_group_id", Op: filter.JSONEqual.Factory(), Value: sgID},
+			},
+		},
+		Page: core.NewCountPage(),
+	}
+	subnetResult, err := sg.client.DataService().Global.Subnet.List(kt.Ctx, kt.Header(), listReq)
+	if err != nil {
+		logs.Errorf("count subnet by security group failed, err: %v, sg id: %s, rid: %s", err, sgID, kt.Rid)
+		return false, err
+	}
+
+	if subnetResult.Count != 0 {
+		return true, nil
+	}
+
+	niResult, err := sg.client.DataService().Global.NetworkInterface.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("count network interface by security group failed, err: %v, sg id: %s, rid: %s", err, sgID,
+			kt.Rid)
+		return false, err
+	}
+
+	return niResult.Count != 0, nil
+}
+
+// listNormalizedRules 查询安全组下的所有规则，并转换为归一化规则
+func (sg *securityGroup) listNormalizedRules(kt *kit.Kit, vendor enumor.Vendor, sgID string) (
+	[]proto.NormalizedSGRule, error) {
+
+	result := make([]proto.NormalizedSGRule, 0)
+	ruleFilter := tools.EqualExpression("security_group_id", sgID)
+	page := core.NewDefaultBasePage()
+	for {
+		var rules []proto.NormalizedSGRule
+		switch vendor {
+		case enumor.TCloud:
+			listReq := &dataproto.TCloudSGRuleListReq{Filter: ruleFilter, Page: page}
+			resp, err := sg.client.DataService().TCloud.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+				listReq, sgID)
+			if err != nil {
+				logs.Errorf("list tcloud security group rule failed, err: %v, sg id: %s, rid: %s", err, sgID, kt.Rid)
+				return nil, err
+			}
+			rules = NormalizeTCloudRules(resp.Details)
+
+		case enumor.Aws:
+			listReq := &dataproto.AwsSGRuleListReq{Filter: ruleFilter, Page: page}
+			resp, err := sg.client.DataService().Aws.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+				listReq, sgID)
+			if err != nil {
+				logs.Errorf("list aws security group rule failed, err: %v, sg id: %s, rid: %s", err, sgID, kt.Rid)
+				return nil, err
+			}
+			rules = NormalizeAwsRules(resp.Details)
+
+		case enumor.HuaWei:
+			listReq := &dataproto.HuaWeiSGRuleListReq{Filter: ruleFilter, Page: page}
+			resp, err := sg.client.DataService().HuaWei.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+				listReq, sgID)
+			if err != nil {
+				logs.Errorf("list huawei security group rule failed, err: %v, sg id: %s, rid: %s", err, sgID, kt.Rid)
+				return nil, err
+			}
+			rules = NormalizeHuaWeiRules(resp.Details)
+
+		case enumor.Azure:
+			listReq := &dataproto.AzureSGRuleListReq{Filter: ruleFilter, Page: page}
+			resp, err := sg.client.DataService().Azure.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+				listReq, sgID)
+			if err != nil {
+				logs.Errorf("list azure security group rule failed, err: %v, sg id: %s, rid: %s", err, sgID, kt.Rid)
+				return nil, err
+			}
+			rules = NormalizeAzureRules(resp.Details)
+
+		default:
+			return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support", vendor)
+		}
+
+		result = append(result, rules...)
+		if uint(len(rules)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+
+	return result, nil
+}
+
+// listGcpFirewallRules 查询vpc下所有的gcp防火墙规则
+func (sg *securityGroup) listGcpFirewallRules(kt *kit.Kit, vpcIDs []string) ([]corecloud.GcpFirewallRule, error) {
+	result := make([]corecloud.GcpFirewallRule, 0)
+	listReq := &dataproto.GcpFirewallRuleListReq{
+		Filter: tools.ContainersExpression("vpc_id", vpcIDs),
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		resp, err := sg.client.DataService().Gcp.Firewall.ListFirewallRule(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list gcp firewall rule failed, err: %v, vpc ids: %v, rid: %s", err, vpcIDs, kt.Rid)
+			return nil, err
+		}
+
+		result = append(result, resp.Details...)
+		if uint(len(resp.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,254 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package topology
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// pair 一条关联关系两端的资源ID，source 为上游资源
+type pair struct {
+	source string
+	target string
+}
+
+// relation 定义两类资源之间的关联关系及其查询方式
+type relation struct {
+	source enumor.CloudResourceType
+	target enumor.CloudResourceType
+	// sourceField、targetField 为关联关系数据中上下游资源ID对应的字段
+	sourceField string
+	targetField string
+	// rules 查询关联关系时附加的过滤条件
+	rules []*filter.AtomRule
+	list  func(kt *kit.Kit, req *core.ListReq) ([]pair, error)
+}
+
+// newRelations 拓扑图的资源关联关系: vpc -> 子网 -> 主机 -> 硬盘/eip/网络接口/安全组 -> 负载均衡 -> 监听器 -> 目标组
+func newRelations(cli *client.ClientSet) []relation {
+	global := cli.DataService().Global
+
+	return []relation{
+		{
+			source: enumor.VpcCloudResType, target: enumor.SubnetCloudResType,
+			sourceField: "vpc_id", targetField: "id",
+			list: func(kt *kit.Kit, req *core.ListReq) ([]pair, error) {
+				result, err := global.Subnet.List(kt.Ctx, kt.Header(), req)
+				if err != nil {
+					return nil, err
+				}
+				pairs := make([]pair, 0, len(result.Details))
+				for _, one := range result.Details {
+					pairs = append(pairs, pair{source: one.VpcID, target: one.ID})
+				}
+				return pairs, nil
+			},
+		},
+		{
+			source: enumor.VpcCloudResType, target: enumor.LoadBalancerCloudResType,
+			sourceField: "vpc_id", targetField: "id",
+			list: func(kt *kit.Kit, req *core.ListReq) ([]pair, error) {
+				result, err := global.LoadBalancer.ListLoadBalancer(kt, req)
+				if err != nil {
+					return nil, err
+				}
+				pairs := make([]pair, 0, len(result.Details))
+				for _, one := range result.Details {
+					pairs = append(pairs, pair{source: one.VpcID, target: one.ID})
+				}
+				return pairs, nil
+			},
+		},
+		{
+			source: enumor.SubnetCloudResType, target: enumor.CvmCloudResType,
+			sourceField: "subnet_id", targetField: "cvm_id",
+			list: func(kt *kit.Kit, req *core.ListReq) ([]pair, error) {
+				result, err := global.SubnetCvmRel.List(kt.Ctx, kt.Header(), req)
+				if err != nil {
+					return nil, err
+				}
+				pairs := make([]pair, 0, len(result.Details))
+				for _, one := range result.Details {
+					pairs = append(pairs, pair{source: one.SubnetID, target: one.CvmID})
+				}
+				return pairs, nil
+			},
+		},
+		{
+			source: enumor.CvmCloudResType, target: enumor.DiskCloudResType,
+			sourceField: "cvm_id", targetField: "disk_id",
+			list: func(kt *kit.Kit, req *core.ListReq) ([]pair, error) {
+				result, err := global.ListDiskCvmRel(kt, req)
+				if err != nil {
+					return nil, err
+				}
+				pairs := make([]pair, 0, len(result.Details))
+				for _, one := range result.Details {
+					pairs = append(pairs, pair{source: one.CvmID, target: one.DiskID})
+				}
+				return pairs, nil
+			},
+		},
+		{
+			source: enumor.CvmCloudResType, target: enumor.EipCloudResType,
+			sourceField: "cvm_id", targetField: "eip_id",
+			list: func(kt *kit.Kit, req *core.ListReq) ([]pair, error) {
+				result, err := global.ListEipCvmRel(kt, req)
+				if err != nil {
+					return nil, err
+				}
+				pairs := make([]pair, 0, len(result.Details))
+				for _, one := range result.Details {
+					pairs = append(pairs, pair{source: one.CvmID, target: one.EipID})
+				}
+				return pairs, nil
+			},
+		},
+		{
+			source: enumor.CvmCloudResType, target: enumor.NetworkInterfaceCloudResType,
+			sourceField: "cvm_id", targetField: "network_interface_id",
+			list: func(kt *kit.Kit, req *core.ListReq) ([]pair, error) {
+				result, err := global.NetworkInterfaceCvmRel.ListNetworkCvmRels(kt, req)
+				if err != nil {
+					return nil, err
+				}
+				pairs := make([]pair, 0, len(result.Details))
+				for _, one := range result.Details {
+					pairs = append(pairs, pair{source: one.CvmID, target: one.NetworkInterfaceID})
+				}
+				return pairs, nil
+			},
+		},
+		{
+			source: enumor.CvmCloudResType, target: enumor.SecurityGroupCloudResType,
+			sourceField: "cvm_id", targetField: "security_group_id",
+			list: func(kt *kit.Kit, req *core.ListReq) ([]pair, error) {
+				result, err := global.SGCvmRel.ListSgCvmRels(kt.Ctx, kt.Header(), req)
+				if err != nil {
+					return nil, err
+				}
+				pairs := make([]pair, 0, len(result.Details))
+				for _, one := range result.Details {
+					pairs = append(pairs, pair{source: one.CvmID, target: one.SecurityGroupID})
+				}
+				return pairs, nil
+			},
+		},
+		newSGCommonRelation(cli, enumor.CvmCloudResType),
+		newSGCommonRelation(cli, enumor.LoadBalancerCloudResType),
+		{
+			source: enumor.LoadBalancerCloudResType, target: enumor.ListenerCloudResType,
+			sourceField: "lb_id", targetField: "id",
+			list: func(kt *kit.Kit, req *core.ListReq) ([]pair, error) {
+				result, err := global.LoadBalancer.ListListener(kt, req)
+				if err != nil {
+					return nil, err
+				}
+				pairs := make([]pair, 0, len(result.Details))
+				for _, one := range result.Details {
+					pairs = append(pairs, pair{source: one.LbID, target: one.ID})
+				}
+				return pairs, nil
+			},
+		},
+		{
+			source: enumor.ListenerCloudResType, target: enumor.TargetGroupCloudResType,
+			sourceField: "lbl_id", targetField: "target_group_id",
+			list: func(kt *kit.Kit, req *core.ListReq) ([]pair, error) {
+				result, err := global.LoadBalancer.ListTargetGroupListenerRel(kt, req)
+				if err != nil {
+					return nil, err
+				}
+				pairs := make([]pair, 0, len(result.Details))
+				for _, one := range result.Details {
+					pairs = append(pairs, pair{source: one.LblID, target: one.TargetGroupID})
+				}
+				return pairs, nil
+			},
+		},
+		{
+			source: enumor.TargetGroupCloudResType, target: enumor.CvmCloudResType,
+			sourceField: "target_group_id", targetField: "inst_id",
+			list: func(kt *kit.Kit, req *core.ListReq) ([]pair, error) {
+				result, err := global.LoadBalancer.ListTarget(kt, req)
+				if err != nil {
+					return nil, err
+				}
+				pairs := make([]pair, 0, len(result.Details))
+				for _, one := range result.Details {
+					// 非主机类型的后端(如ip类型)没有对应的主机ID，展开时会被忽略
+					pairs = append(pairs, pair{source: one.TargetGroupID, target: one.InstID})
+				}
+				return pairs, nil
+			},
+		},
+	}
+}
+
+// newSGCommonRelation 安全组与主机、负载均衡等资源的通用关联关系，安全组视为其绑定资源的下游资源
+func newSGCommonRelation(cli *client.ClientSet, resType enumor.CloudResourceType) relation {
+	return relation{
+		source: resType, target: enumor.SecurityGroupCloudResType,
+		sourceField: "res_id", targetField: "security_group_id",
+		rules: []*filter.AtomRule{tools.RuleEqual("res_type", resType)},
+		list: func(kt *kit.Kit, req *core.ListReq) ([]pair, error) {
+			result, err := cli.DataService().Global.SGCommonRel.ListSgCommonRels(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			pairs := make([]pair, 0, len(result.Details))
+			for _, one := range result.Details {
+				pairs = append(pairs, pair{source: one.ResID, target: one.SecurityGroupID})
+			}
+			return pairs, nil
+		},
+	}
+}
+
+// listPairs 按关联关系一端的资源ID分页查询关联关系，每页结果交给 visit 处理，visit 返回 false 时停止查询
+func (r relation) listPairs(kt *kit.Kit, field string, ids []string, visit func(pairs []pair) bool) error {
+	for _, part := range slice.Split(ids, int(filter.DefaultMaxInLimit)) {
+		rules := append([]*filter.AtomRule{tools.RuleIn(field, part)}, r.rules...)
+		req := &core.ListReq{Filter: tools.ExpressionAnd(rules...), Page: core.NewDefaultBasePage()}
+		for {
+			details, err := r.list(kt, req)
+			if err != nil {
+				return err
+			}
+
+			if !visit(details) {
+				return nil
+			}
+
+			if uint(len(details)) < req.Page.Limit {
+				break
+			}
+			req.Page.Start += uint32(req.Page.Limit)
+		}
+	}
+
+	return nil
+}
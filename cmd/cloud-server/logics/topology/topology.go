@@ -0,0 +1,316 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package topology 资源拓扑，从任意资源出发按关联关系逐层展开，生成资源之间的拓扑图
+package topology
+
+import (
+	"sort"
+
+	cstopology "hcm/pkg/api/cloud-server/topology"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// MaxGraphNodes 拓扑图中资源数量上限，超过上限后停止展开
+const MaxGraphNodes = 500
+
+// Interface define topology interface.
+type Interface interface {
+	// Graph 从根资源出发按关联关系逐层展开，返回指定深度内的资源及其关联关系
+	Graph(kt *kit.Kit, opt *GraphOption) (*cstopology.Graph, error)
+}
+
+// GraphOption define get topology graph option.
+type GraphOption struct {
+	ResType  enumor.CloudResourceType
+	ID       string
+	Depth    int
+	ResTypes []enumor.CloudResourceType
+	// BkBizID 业务下查询时仅展开属于该业务的资源，为0时不限制业务
+	BkBizID int64
+}
+
+// NewTopology new topology.
+func NewTopology(client *client.ClientSet) Interface {
+	return &topology{
+		relations:    newRelations(client),
+		listResBasic: client.DataService().Global.Cloud.ListResBasicInfo,
+	}
+}
+
+type topology struct {
+	relations    []relation
+	listResBasic func(kt *kit.Kit, req protocloud.ListResourceBasicInfoReq) (
+		map[string]types.CloudResourceBasicInfo, error)
+}
+
+// graphBuilder 记录展开过程中已加入拓扑图的资源及关联关系
+type graphBuilder struct {
+	opt     *GraphOption
+	allowed map[enumor.CloudResourceType]struct{}
+	nodes   map[nodeKey]struct{}
+	edges   map[cstopology.Edge]struct{}
+	graph   *cstopology.Graph
+}
+
+type nodeKey struct {
+	resType enumor.CloudResourceType
+	id      string
+}
+
+// Graph 从根资源出发按层展开，每层按资源类型批量查询关联关系及资源基本信息
+func (t *topology) Graph(kt *kit.Kit, opt *GraphOption) (*cstopology.Graph, error) {
+	b := newGraphBuilder(opt)
+
+	roots, err := t.listNodes(kt, opt.ResType, []string{opt.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) == 0 || !b.inBiz(roots[0]) {
+		return nil, errf.Newf(errf.RecordNotFound, "%s(%s) not found", opt.ResType, opt.ID)
+	}
+	b.addNode(roots[0], 0)
+
+	frontier := map[enumor.CloudResourceType][]string{opt.ResType: {opt.ID}}
+	for depth := 1; depth <= opt.Depth && len(frontier) > 0 && !b.graph.Truncated; depth++ {
+		candidates, edges, truncated, err := t.expand(kt, b, frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		next := make(map[enumor.CloudResourceType][]string)
+		for _, resType := range sortedTypes(candidates) {
+			infos, err := t.listNodes(kt, resType, candidates[resType])
+			if err != nil {
+				return nil, err
+			}
+
+			for _, info := range infos {
+				if !b.inBiz(info) {
+					continue
+				}
+				if len(b.nodes) >= MaxGraphNodes {
+					truncated = true
+					break
+				}
+				b.addNode(info, depth)
+				next[resType] = append(next[resType], info.ID)
+			}
+		}
+
+		for _, edge := range edges {
+			b.addEdge(edge)
+		}
+		b.graph.Truncated = truncated
+		frontier = next
+	}
+
+	return b.graph, nil
+}
+
+// expand 分页查询上一层资源的关联关系，返回尚未加入拓扑图的关联资源ID及候选的关联关系。
+// 候选资源数量达到拓扑图剩余的资源数量上限后停止查询，并返回拓扑图已被截断。
+func (t *topology) expand(kt *kit.Kit, b *graphBuilder, frontier map[enumor.CloudResourceType][]string) (
+	map[enumor.CloudResourceType][]string, []cstopology.Edge, bool, error) {
+
+	budget := MaxGraphNodes - len(b.nodes)
+	candidates := make(map[enumor.CloudResourceType][]string)
+	seen := make(map[nodeKey]struct{})
+	edges := make([]cstopology.Edge, 0)
+	truncated := false
+
+	// addPairs 记录一页关联关系，next 为关联关系中待展开一端的资源类型及ID，预算用完时返回 false 停止查询
+	addPairs := func(rel relation, resType enumor.CloudResourceType, pairs []pair, next func(p pair) string) bool {
+		for _, p := range pairs {
+			if len(p.source) == 0 || len(p.target) == 0 {
+				continue
+			}
+
+			key := nodeKey{resType: resType, id: next(p)}
+			_, inGraph := b.nodes[key]
+			_, inSeen := seen[key]
+			if !inGraph && !inSeen {
+				if len(seen) >= budget {
+					truncated = true
+					return false
+				}
+				seen[key] = struct{}{}
+				candidates[resType] = append(candidates[resType], key.id)
+			}
+			edges = append(edges, newEdge(rel, p))
+		}
+		return true
+	}
+
+	for _, rel := range t.relations {
+		if truncated {
+			break
+		}
+		if !b.isAllowed(rel.source) || !b.isAllowed(rel.target) {
+			continue
+		}
+
+		// 上一层资源作为上游资源时展开其下游资源，作为下游资源时展开其上游资源
+		if ids := frontier[rel.source]; len(ids) > 0 {
+			err := rel.listPairs(kt, rel.sourceField, ids, func(pairs []pair) bool {
+				return addPairs(rel, rel.target, pairs, func(p pair) string { return p.target })
+			})
+			if err != nil {
+				logs.Errorf("list %s of %s failed, err: %v, rid: %s", rel.target, rel.source, err, kt.Rid)
+				return nil, nil, false, err
+			}
+		}
+
+		if ids := frontier[rel.target]; len(ids) > 0 && !truncated {
+			err := rel.listPairs(kt, rel.targetField, ids, func(pairs []pair) bool {
+				return addPairs(rel, rel.source, pairs, func(p pair) string { return p.source })
+			})
+			if err != nil {
+				logs.Errorf("list %s of %s failed, err: %v, rid: %s", rel.source, rel.target, err, kt.Rid)
+				return nil, nil, false, err
+			}
+		}
+	}
+
+	return candidates, edges, truncated, nil
+}
+
+// listNodes 查询资源基本信息，关联关系中已不存在的资源会被忽略
+func (t *topology) listNodes(kt *kit.Kit, resType enumor.CloudResourceType, ids []string) (
+	[]types.CloudResourceBasicInfo, error) {
+
+	infos := make([]types.CloudResourceBasicInfo, 0, len(ids))
+	for _, part := range slice.Split(ids, int(filter.DefaultMaxInLimit)) {
+		req := protocloud.ListResourceBasicInfoReq{ResourceType: resType, IDs: part, Fields: basicInfoFields(resType)}
+		infoMap, err := t.listResBasic(kt, req)
+		if err != nil {
+			if errf.IsRecordNotFound(err) {
+				continue
+			}
+			logs.Errorf("list %s basic info failed, err: %v, ids: %v, rid: %s", resType, err, part, kt.Rid)
+			return nil, err
+		}
+
+		// 按传入的ID顺序返回，保证拓扑图结果稳定
+		for _, id := range part {
+			if info, exist := infoMap[id]; exist {
+				infos = append(infos, info)
+			}
+		}
+	}
+
+	return infos, nil
+}
+
+// basicInfoFields 拓扑图节点需要的资源基本信息字段，监听器没有地域字段，eip名称可能为空值因此不查询
+func basicInfoFields(resType enumor.CloudResourceType) []string {
+	fields := []string{"id", "vendor", "account_id", "bk_biz_id", "cloud_id"}
+	if resType != enumor.EipCloudResType {
+		fields = append(fields, "name")
+	}
+	if resType != enumor.ListenerCloudResType {
+		fields = append(fields, "region")
+	}
+	return fields
+}
+
+func newGraphBuilder(opt *GraphOption) *graphBuilder {
+	b := &graphBuilder{
+		opt:   opt,
+		nodes: make(map[nodeKey]struct{}),
+		edges: make(map[cstopology.Edge]struct{}),
+		graph: &cstopology.Graph{Nodes: make([]cstopology.Node, 0), Edges: make([]cstopology.Edge, 0)},
+	}
+
+	if len(opt.ResTypes) > 0 {
+		b.allowed = map[enumor.CloudResourceType]struct{}{opt.ResType: {}}
+		for _, resType := range opt.ResTypes {
+			b.allowed[resType] = struct{}{}
+		}
+	}
+
+	return b
+}
+
+// isAllowed 未指定展开的资源类型时展开所有类型，根资源类型总是允许的
+func (b *graphBuilder) isAllowed(resType enumor.CloudResourceType) bool {
+	if b.allowed == nil {
+		return true
+	}
+	_, exist := b.allowed[resType]
+	return exist
+}
+
+// inBiz 业务下查询时仅保留属于该业务的资源
+func (b *graphBuilder) inBiz(info types.CloudResourceBasicInfo) bool {
+	return b.opt.BkBizID == 0 || info.BkBizID == b.opt.BkBizID
+}
+
+func (b *graphBuilder) addNode(info types.CloudResourceBasicInfo, depth int) {
+	node := cstopology.Node{
+		ResType:   info.ResType,
+		ID:        info.ID,
+		CloudID:   info.CloudID,
+		Name:      info.Name,
+		Vendor:    info.Vendor,
+		AccountID: info.AccountID,
+		BkBizID:   info.BkBizID,
+		Region:    info.Region,
+		Depth:     depth,
+	}
+	b.graph.Nodes = append(b.graph.Nodes, node)
+	b.nodes[nodeKey{resType: node.ResType, id: node.ID}] = struct{}{}
+}
+
+// addEdge 仅保留两端资源都已加入拓扑图的关联关系
+func (b *graphBuilder) addEdge(edge cstopology.Edge) {
+	if _, exist := b.nodes[nodeKey{resType: edge.SourceType, id: edge.SourceID}]; !exist {
+		return
+	}
+	if _, exist := b.nodes[nodeKey{resType: edge.TargetType, id: edge.TargetID}]; !exist {
+		return
+	}
+	if _, exist := b.edges[edge]; exist {
+		return
+	}
+
+	b.edges[edge] = struct{}{}
+	b.graph.Edges = append(b.graph.Edges, edge)
+}
+
+func newEdge(rel relation, p pair) cstopology.Edge {
+	return cstopology.Edge{SourceType: rel.source, SourceID: p.source, TargetType: rel.target, TargetID: p.target}
+}
+
+func sortedTypes(m map[enumor.CloudResourceType][]string) []enumor.CloudResourceType {
+	resTypes := make([]enumor.CloudResourceType, 0, len(m))
+	for resType := range m {
+		resTypes = append(resTypes, resType)
+	}
+	sort.Slice(resTypes, func(i, j int) bool { return resTypes[i] < resTypes[j] })
+	return resTypes
+}
@@ -0,0 +1,200 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package topology
+
+import (
+	"fmt"
+	"testing"
+
+	"hcm/pkg/api/core"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+)
+
+// fakeStore 内存中的资源及关联关系，按照关联关系数据的查询条件与分页返回结果
+type fakeStore struct {
+	infos map[nodeKey]types.CloudResourceBasicInfo
+	calls int
+}
+
+func (s *fakeStore) addNode(resType enumor.CloudResourceType, id string, bizID int64) {
+	s.infos[nodeKey{resType: resType, id: id}] = types.CloudResourceBasicInfo{ID: id, ResType: resType,
+		BkBizID: bizID}
+}
+
+func (s *fakeStore) relation(source, target enumor.CloudResourceType, pairs []pair) relation {
+	return relation{
+		source: source, target: target, sourceField: "source", targetField: "target",
+		list: func(_ *kit.Kit, req *core.ListReq) ([]pair, error) {
+			s.calls++
+			rule := req.Filter.Rules[0].(*filter.AtomRule)
+			ids := make(map[string]struct{})
+			for _, id := range rule.Value.([]string) {
+				ids[id] = struct{}{}
+			}
+
+			matched := make([]pair, 0)
+			for _, p := range pairs {
+				value := p.source
+				if rule.Field == "target" {
+					value = p.target
+				}
+				if _, exist := ids[value]; exist {
+					matched = append(matched, p)
+				}
+			}
+
+			start := int(req.Page.Start)
+			if start >= len(matched) {
+				return []pair{}, nil
+			}
+			end := start + int(req.Page.Limit)
+			if end > len(matched) {
+				end = len(matched)
+			}
+			return matched[start:end], nil
+		},
+	}
+}
+
+func (s *fakeStore) listResBasic(_ *kit.Kit, req protocloud.ListResourceBasicInfoReq) (
+	map[string]types.CloudResourceBasicInfo, error) {
+
+	result := make(map[string]types.CloudResourceBasicInfo)
+	for _, id := range req.IDs {
+		if info, exist := s.infos[nodeKey{resType: req.ResourceType, id: id}]; exist {
+			result[id] = info
+		}
+	}
+	return result, nil
+}
+
+// newFakeTopology vpc1 下有子网 subnet1(业务1)、subnet2(业务2)，主机 cvm1 在 subnet1 中，cvm2 在 subnet2 中
+func newFakeTopology() (*topology, *fakeStore) {
+	store := &fakeStore{infos: make(map[nodeKey]types.CloudResourceBasicInfo)}
+	store.addNode(enumor.VpcCloudResType, "vpc1", 1)
+	store.addNode(enumor.SubnetCloudResType, "subnet1", 1)
+	store.addNode(enumor.SubnetCloudResType, "subnet2", 2)
+	store.addNode(enumor.CvmCloudResType, "cvm1", 1)
+	store.addNode(enumor.CvmCloudResType, "cvm2", 2)
+
+	t := &topology{
+		relations: []relation{
+			store.relation(enumor.VpcCloudResType, enumor.SubnetCloudResType,
+				[]pair{{source: "vpc1", target: "subnet1"}, {source: "vpc1", target: "subnet2"}}),
+			store.relation(enumor.SubnetCloudResType, enumor.CvmCloudResType,
+				[]pair{{source: "subnet1", target: "cvm1"}, {source: "subnet2", target: "cvm2"}}),
+		},
+		listResBasic: store.listResBasic,
+	}
+	return t, store
+}
+
+func TestGraphTraversal(t *testing.T) {
+	topo, _ := newFakeTopology()
+
+	graph, err := topo.Graph(kit.New(), &GraphOption{ResType: enumor.CvmCloudResType, ID: "cvm1", Depth: 2})
+	if err != nil {
+		t.Fatalf("get graph failed, err: %v", err)
+	}
+
+	depths := make(map[string]int)
+	for _, node := range graph.Nodes {
+		depths[node.ID] = node.Depth
+	}
+	// 从主机出发，向上展开子网及vpc，再向下展开vpc的其他子网需要第3层
+	if len(graph.Nodes) != 3 || graph.Truncated {
+		t.Fatalf("unexpected graph nodes: %+v, truncated: %v", graph.Nodes, graph.Truncated)
+	}
+	for id, depth := range map[string]int{"cvm1": 0, "subnet1": 1, "vpc1": 2} {
+		if got, exist := depths[id]; !exist || got != depth {
+			t.Errorf("node %s depth expect %d, got %d(exist: %v)", id, depth, got, exist)
+		}
+	}
+	if len(graph.Edges) != 2 {
+		t.Errorf("unexpected graph edges: %+v", graph.Edges)
+	}
+
+	graph, err = topo.Graph(kit.New(), &GraphOption{ResType: enumor.VpcCloudResType, ID: "vpc1", Depth: 1})
+	if err != nil {
+		t.Fatalf("get graph failed, err: %v", err)
+	}
+	if len(graph.Nodes) != 3 || len(graph.Edges) != 2 {
+		t.Errorf("vpc graph with depth 1 should have 2 subnets, got nodes: %+v", graph.Nodes)
+	}
+}
+
+func TestGraphBizFilter(t *testing.T) {
+	topo, _ := newFakeTopology()
+
+	graph, err := topo.Graph(kit.New(), &GraphOption{ResType: enumor.VpcCloudResType, ID: "vpc1", Depth: 3,
+		BkBizID: 1})
+	if err != nil {
+		t.Fatalf("get graph failed, err: %v", err)
+	}
+
+	for _, node := range graph.Nodes {
+		if node.BkBizID != 1 {
+			t.Errorf("node %s(%s) not belongs to biz 1", node.ResType, node.ID)
+		}
+	}
+	if len(graph.Nodes) != 3 || len(graph.Edges) != 2 {
+		t.Errorf("unexpected biz graph, nodes: %+v, edges: %+v", graph.Nodes, graph.Edges)
+	}
+
+	if _, err = topo.Graph(kit.New(), &GraphOption{ResType: enumor.SubnetCloudResType, ID: "subnet2", Depth: 1,
+		BkBizID: 1}); err == nil {
+		t.Errorf("root resource not in biz should not be found")
+	}
+}
+
+func TestGraphTruncated(t *testing.T) {
+	topo, store := newFakeTopology()
+
+	// vpc2 下的子网数量远超拓扑图资源上限
+	store.addNode(enumor.VpcCloudResType, "vpc2", 1)
+	pairs := make([]pair, 0)
+	for i := 0; i < 4*MaxGraphNodes; i++ {
+		id := fmt.Sprintf("subnet-%d", i)
+		store.addNode(enumor.SubnetCloudResType, id, 1)
+		pairs = append(pairs, pair{source: "vpc2", target: id})
+	}
+	topo.relations = []relation{store.relation(enumor.VpcCloudResType, enumor.SubnetCloudResType, pairs)}
+
+	graph, err := topo.Graph(kit.New(), &GraphOption{ResType: enumor.VpcCloudResType, ID: "vpc2", Depth: 2})
+	if err != nil {
+		t.Fatalf("get graph failed, err: %v", err)
+	}
+
+	if !graph.Truncated || len(graph.Nodes) != MaxGraphNodes {
+		t.Errorf("graph should be truncated at %d nodes, got: %d, truncated: %v", MaxGraphNodes,
+			len(graph.Nodes), graph.Truncated)
+	}
+	if len(graph.Edges) != MaxGraphNodes-1 {
+		t.Errorf("graph edges expect %d, got: %d", MaxGraphNodes-1, len(graph.Edges))
+	}
+	// 达到资源上限后不再继续分页查询关联关系
+	if store.calls != 1 {
+		t.Errorf("relation should be listed only once after node budget reached, got: %d", store.calls)
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/iam/auth"
 	"hcm/pkg/iam/meta"
+	"hcm/pkg/lock"
 	"hcm/pkg/rest"
 )
 
@@ -38,6 +39,7 @@ func InitAccountService(c *capability.Capability) {
 		client:     c.ApiClient,
 		authorizer: c.Authorizer,
 		audit:      c.Audit,
+		locker:     c.Locker,
 	}
 
 	h := rest.NewHandler()
@@ -52,8 +54,11 @@ func InitAccountService(c *capability.Capability) {
 	h.Add("ResourceList", http.MethodPost, "/accounts/resources/accounts/list", svc.ResourceList)
 	h.Add("GetAccount", http.MethodGet, "/accounts/{account_id}", svc.GetAccount)
 	h.Add("GetSyncDetail", http.MethodGet, "/accounts/sync_details/{account_id}", svc.GetSyncDetail)
+	h.Add("ListSyncRun", http.MethodPost, "/accounts/sync_details/{account_id}/runs/list", svc.ListSyncRun)
 	h.Add("UpdateAccount", http.MethodPatch, "/accounts/{account_id}", svc.UpdateAccount)
 	h.Add("SyncCloudResource", http.MethodPost, "/accounts/{account_id}/sync", svc.SyncCloudResource)
+	h.Add("PlanSyncCloudResource", http.MethodPost, "/accounts/{account_id}/sync/plans/{res_type}",
+		svc.PlanSyncCloudResource)
 	h.Add("DeleteAccount", http.MethodDelete, "/accounts/{account_id}", svc.DeleteAccount)
 	h.Add("DeleteValidate", http.MethodPost, "/accounts/{account_id}/delete/validate", svc.DeleteValidate)
 
@@ -93,6 +98,7 @@ type accountSvc struct {
 	client     *client.ClientSet
 	authorizer auth.Authorizer
 	audit      audit.Interface
+	locker     lock.Locker
 }
 
 func (a *accountSvc) checkPermission(cts *rest.Contexts, action meta.Action, accountID string) error {
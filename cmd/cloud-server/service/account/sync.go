@@ -20,10 +20,14 @@
 package account
 
 import (
+	"fmt"
+
 	"hcm/cmd/cloud-server/logics/account"
+	"hcm/pkg/api/hc-service/sync"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 )
 
@@ -43,9 +47,57 @@ func (a *accountSvc) SyncCloudResource(cts *rest.Contexts) (interface{}, error)
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	if err = account.Sync(cts.Kit, a.client, baseInfo.Vendor, accountID); err != nil {
+	if err = account.Sync(cts.Kit, a.client, a.locker, baseInfo.Vendor, accountID); err != nil {
 		return nil, err
 	}
 
 	return nil, nil
 }
+
+// PlanSyncCloudResource 以计划模式同步账号下指定类型的资源，只返回同步将产生的变更，不修改本地数据。
+// 请求体与 hc-service 对应资源同步接口一致，账号ID以路径参数为准。
+func (a *accountSvc) PlanSyncCloudResource(cts *rest.Contexts) (interface{}, error) {
+	accountID := cts.PathParameter("account_id").String()
+	resType := enumor.CloudResourceType(cts.PathParameter("res_type").String())
+
+	req := make(map[string]interface{})
+	if err := cts.DecodeInto(&req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	// 校验用户有该账号的更新权限
+	if err := a.checkPermission(cts, meta.Update, accountID); err != nil {
+		return nil, err
+	}
+
+	// 查询该账号对应的Vendor
+	baseInfo, err := a.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
+		enumor.AccountCloudResType, accountID)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	req["account_id"] = accountID
+
+	var result *sync.PlanResult
+	switch baseInfo.Vendor {
+	case enumor.TCloud:
+		result, err = a.client.HCService().TCloud.Sync.PlanSync(cts.Kit, resType, &req)
+	case enumor.Aws:
+		result, err = a.client.HCService().Aws.Sync.PlanSync(cts.Kit, resType, &req)
+	case enumor.HuaWei:
+		result, err = a.client.HCService().HuaWei.Sync.PlanSync(cts.Kit, resType, &req)
+	case enumor.Gcp:
+		result, err = a.client.HCService().Gcp.Sync.PlanSync(cts.Kit, resType, &req)
+	case enumor.Azure:
+		result, err = a.client.HCService().Azure.Sync.PlanSync(cts.Kit, resType, &req)
+	default:
+		return nil, errf.NewFromErr(errf.InvalidParameter, fmt.Errorf("vendor: %s not support", baseInfo.Vendor))
+	}
+	if err != nil {
+		logs.Errorf("plan sync %s resource %s failed, err: %v, account: %s, rid: %s", baseInfo.Vendor, resType,
+			err, accountID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -24,6 +24,7 @@ import (
 	"hcm/pkg/api/core"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/rest"
 	"hcm/pkg/runtime/filter"
@@ -86,3 +87,30 @@ func (a *accountSvc) GetSyncDetail(cts *rest.Contexts) (interface{}, error) {
 		IassRes: iassRes,
 	}, nil
 }
+
+// ListSyncRun list account sync run history.
+func (a *accountSvc) ListSyncRun(cts *rest.Contexts) (interface{}, error) {
+	accountID := cts.PathParameter("account_id").String()
+
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// 校验用户有该账号的查看权限
+	if err := a.checkPermission(cts, meta.Find, accountID); err != nil {
+		return nil, err
+	}
+
+	accountFilter, err := tools.And(tools.RuleEqual("account_id", accountID), req.Filter)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	req.Filter = accountFilter
+
+	return a.client.DataService().Global.SyncRun.List(cts.Kit, req)
+}
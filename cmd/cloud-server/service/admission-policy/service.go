@@ -0,0 +1,159 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package admissionpolicy 准入策略管理服务
+package admissionpolicy
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/service/capability"
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dsadmission "hcm/pkg/api/data-service/admission"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitService initialize the admission policy service.
+func InitService(c *capability.Capability) {
+	svc := &policySvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateAdmissionPolicy", http.MethodPost, "/admission_policies/create", svc.CreatePolicy)
+	h.Add("UpdateAdmissionPolicy", http.MethodPatch, "/admission_policies/{id}", svc.UpdatePolicy)
+	h.Add("ListAdmissionPolicy", http.MethodPost, "/admission_policies/list", svc.ListPolicy)
+	h.Add("BatchDeleteAdmissionPolicy", http.MethodDelete, "/admission_policies/batch", svc.BatchDeletePolicy)
+
+	h.Load(c.WebService)
+}
+
+type policySvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+}
+
+// CreatePolicy create admission policy.
+func (svc *policySvc) CreatePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsadmission.CreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AdmissionPolicy, Action: meta.Create}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	result, err := svc.client.DataService().Global.AdmissionPolicy.Create(cts.Kit, req)
+	if err != nil {
+		logs.Errorf("create admission policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdatePolicy update admission policy.
+func (svc *policySvc) UpdatePolicy(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(dsadmission.UpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AdmissionPolicy, Action: meta.Update,
+		ResourceID: id}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	if err := svc.client.DataService().Global.AdmissionPolicy.Update(cts.Kit, id, req); err != nil {
+		logs.Errorf("update admission policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListPolicy list admission policy.
+func (svc *policySvc) ListPolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AdmissionPolicy, Action: meta.Find}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	return svc.client.DataService().Global.AdmissionPolicy.List(cts.Kit, req)
+}
+
+// BatchDeletePolicy batch delete admission policy.
+func (svc *policySvc) BatchDeletePolicy(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.AdmissionPolicy, Action: meta.Delete}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", req.IDs)}
+	if err := svc.client.DataService().Global.AdmissionPolicy.BatchDelete(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete admission policy failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -68,7 +68,7 @@ func (a *ApplicationOfAddAccount) Deliver() (enumor.ApplicationStatus, map[strin
 	// 不同步登记账号
 	if a.req.Type != enumor.RegistrationAccount {
 		go func() {
-			err = account.Sync(a.Cts.Kit, a.Client, a.req.Vendor, accountID)
+			err = account.Sync(a.Cts.Kit, a.Client, a.Locker, a.req.Vendor, accountID)
 			if err != nil {
 				logs.Errorf("sync account: %s failed, err: %v, rid: %s", accountID, err, a.Cts.Kit.Rid)
 			}
@@ -22,11 +22,13 @@ package handlers
 import (
 	"fmt"
 
+	"hcm/cmd/cloud-server/logics/admission"
 	"hcm/cmd/cloud-server/logics/audit"
 	"hcm/pkg/api/core"
 	"hcm/pkg/client"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/cryptography"
+	"hcm/pkg/lock"
 	"hcm/pkg/rest"
 	"hcm/pkg/thirdparty/api-gateway/cmsi"
 	itsm2 "hcm/pkg/thirdparty/api-gateway/itsm"
@@ -42,6 +44,8 @@ type HandlerOption struct {
 	Audit     audit.Interface
 	ItsmCli   itsm2.Client
 	CmsiCli   cmsi.Client
+	Locker    lock.Locker
+	Admission admission.Interface
 }
 
 // BaseApplicationHandler 基础的Handler 一些公共函数和属性处理，可以给到其他具体Handler组合
@@ -55,6 +59,8 @@ type BaseApplicationHandler struct {
 	Cipher     cryptography.Crypto
 	Audit      audit.Interface
 	CmsiClient cmsi.Client
+	Locker     lock.Locker
+	Admission  admission.Interface
 }
 
 // NewBaseApplicationHandler ...
@@ -70,6 +76,8 @@ func NewBaseApplicationHandler(
 		Cipher:          opt.Cipher,
 		Audit:           opt.Audit,
 		CmsiClient:      opt.CmsiCli,
+		Locker:          opt.Locker,
+		Admission:       opt.Admission,
 	}
 }
 
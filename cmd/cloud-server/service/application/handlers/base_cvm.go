@@ -20,9 +20,11 @@
 package handlers
 
 import (
+	"hcm/cmd/cloud-server/logics/admission"
 	"hcm/pkg/api/core"
 	corecvm "hcm/pkg/api/core/cloud/cvm"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
 	"hcm/pkg/runtime/filter"
 )
 
@@ -52,3 +54,17 @@ func (a *BaseApplicationHandler) ListCvm(
 
 	return resp.Details, nil
 }
+
+// AdmitCreateCvm 使用准入策略校验申请单的创建主机请求，按申请单所属业务匹配策略
+func (a *BaseApplicationHandler) AdmitCreateCvm(bizID int64, accountID string, req interface{}) error {
+	if a.Admission == nil {
+		return nil
+	}
+
+	admitReq, err := admission.NewCreateCvmRequest(a.vendor, bizID, accountID, req)
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return a.Admission.Admit(a.Cts.Kit, admitReq)
+}
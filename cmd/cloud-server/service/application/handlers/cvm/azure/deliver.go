@@ -49,8 +49,9 @@ func (a *ApplicationOfCreateAzureCvm) Deliver() (enumor.ApplicationStatus, map[s
 		})
 
 	addReq := &ts.AddCustomFlowReq{
-		Name:  enumor.FlowCreateCvm,
-		Tasks: tasks,
+		Name:        enumor.FlowCreateCvm,
+		Tasks:       tasks,
+		FairnessKey: ts.BizFairnessKey(a.req.BkBizID),
 	}
 	result, err := a.Client.TaskServer().CreateCustomFlow(a.Cts.Kit, addReq)
 	if err != nil {
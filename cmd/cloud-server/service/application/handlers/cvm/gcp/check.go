@@ -31,5 +31,9 @@ func (a *ApplicationOfCreateGcpCvm) CheckReq() error {
 		return err
 	}
 
+	if err := a.AdmitCreateCvm(a.req.BkBizID, a.req.AccountID, a.req); err != nil {
+		return err
+	}
+
 	return nil
 }
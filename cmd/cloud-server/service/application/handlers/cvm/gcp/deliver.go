@@ -50,8 +50,9 @@ func (a *ApplicationOfCreateGcpCvm) Deliver() (enumor.ApplicationStatus, map[str
 			}
 		})
 	addReq := &ts.AddCustomFlowReq{
-		Name:  enumor.FlowCreateCvm,
-		Tasks: tasks,
+		Name:        enumor.FlowCreateCvm,
+		Tasks:       tasks,
+		FairnessKey: ts.BizFairnessKey(a.req.BkBizID),
 	}
 	result, err := a.Client.TaskServer().CreateCustomFlow(a.Cts.Kit, addReq)
 	if err != nil {
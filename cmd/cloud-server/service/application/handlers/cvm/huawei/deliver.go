@@ -51,8 +51,9 @@ func (a *ApplicationOfCreateHuaWeiCvm) Deliver() (enumor.ApplicationStatus, map[
 			}
 		})
 	addReq := &ts.AddCustomFlowReq{
-		Name:  enumor.FlowCreateCvm,
-		Tasks: tasks,
+		Name:        enumor.FlowCreateCvm,
+		Tasks:       tasks,
+		FairnessKey: ts.BizFairnessKey(a.req.BkBizID),
 	}
 	result, err := a.Client.TaskServer().CreateCustomFlow(a.Cts.Kit, addReq)
 	if err != nil {
@@ -35,6 +35,10 @@ func (a *ApplicationOfCreateTCloudCvm) CheckReq() error {
 		return err
 	}
 
+	if err := a.AdmitCreateCvm(a.req.BkBizID, a.req.AccountID, a.req); err != nil {
+		return err
+	}
+
 	// TCloud 支持 DryRun，可预校验
 	result, err := a.Client.HCService().TCloud.Cvm.BatchCreateCvm(a.Cts.Kit, a.toHcProtoTCloudBatchCreateReq(true))
 	if err != nil {
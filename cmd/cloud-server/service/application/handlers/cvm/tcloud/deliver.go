@@ -50,8 +50,9 @@ func (a *ApplicationOfCreateTCloudCvm) Deliver() (enumor.ApplicationStatus, map[
 		})
 
 	addReq := &ts.AddCustomFlowReq{
-		Name:  enumor.FlowCreateCvm,
-		Tasks: tasks,
+		Name:        enumor.FlowCreateCvm,
+		Tasks:       tasks,
+		FairnessKey: ts.BizFairnessKey(a.req.BkBizID),
 	}
 	result, err := a.Client.TaskServer().CreateCustomFlow(a.Cts.Kit, addReq)
 	if err != nil {
@@ -26,6 +26,7 @@ import (
 
 	"github.com/tidwall/gjson"
 
+	"hcm/cmd/cloud-server/logics/admission"
 	"hcm/cmd/cloud-server/logics/audit"
 	"hcm/cmd/cloud-server/service/application/handlers"
 	"hcm/cmd/cloud-server/service/capability"
@@ -37,6 +38,7 @@ import (
 	"hcm/pkg/cryptography"
 	"hcm/pkg/iam/auth"
 	"hcm/pkg/iam/meta"
+	"hcm/pkg/lock"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/runtime/filter"
@@ -56,6 +58,8 @@ func InitApplicationService(c *capability.Capability, bkHcmUrl string) {
 		esbCli:     c.EsbClient,
 		bkHcmUrl:   bkHcmUrl,
 		cmsiCli:    c.CmsiCli,
+		locker:     c.Locker,
+		admission:  c.Logics.Admission,
 	}
 	h := rest.NewHandler()
 	h.Add("ListApplications", "POST", "/applications/list", svc.ListApplications)
@@ -99,6 +103,8 @@ type applicationSvc struct {
 	esbCli     esb.Client
 	bkHcmUrl   string
 	cmsiCli    cmsi.Client
+	locker     lock.Locker
+	admission  admission.Interface
 }
 
 func (a *applicationSvc) getCallbackUrl() string {
@@ -114,6 +120,8 @@ func (a *applicationSvc) getHandlerOption(cts *rest.Contexts) *handlers.HandlerO
 		Cipher:    a.cipher,
 		Audit:     a.audit,
 		CmsiCli:   a.cmsiCli,
+		Locker:    a.locker,
+		Admission: a.admission,
 	}
 }
 
@@ -23,6 +23,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"hcm/cmd/cloud-server/logics/admission"
 	"hcm/cmd/cloud-server/logics/async"
 	"hcm/cmd/cloud-server/service/common"
 	actioncvm "hcm/cmd/task-server/logics/action/cvm"
@@ -34,6 +35,7 @@ import (
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 )
@@ -81,9 +83,16 @@ func (svc *cvmSvc) CreateCvm(cts *rest.Contexts) (interface{}, error) {
 		return nil, err
 	}
 
+	if err = svc.admitCreateCvm(cts.Kit, info.Vendor, req.AccountID, req.Data); err != nil {
+		return nil, err
+	}
+
+	// 同步等待执行结果的任务流优先派发，并以账号作为公平键，避免单个账号的大量任务流影响其他账号
 	addReq := &ts.AddCustomFlowReq{
-		Name:  enumor.FlowCreateCvm,
-		Tasks: tasks,
+		Name:        enumor.FlowCreateCvm,
+		Tasks:       tasks,
+		Priority:    enumor.FlowPriorityHigh,
+		FairnessKey: ts.AccountFairnessKey(req.AccountID),
 	}
 	result, err := svc.client.TaskServer().CreateCustomFlow(cts.Kit, addReq)
 	if err != nil {
@@ -94,6 +103,16 @@ func (svc *cvmSvc) CreateCvm(cts *rest.Contexts) (interface{}, error) {
 	return result, async.WaitTaskToEnd(cts.Kit, svc.client.TaskServer(), result.ID)
 }
 
+// admitCreateCvm 使用准入策略校验资源下创建主机请求，资源下创建的主机属于未分配业务。
+func (svc *cvmSvc) admitCreateCvm(kt *kit.Kit, vendor enumor.Vendor, accountID string, body json.RawMessage) error {
+	req, err := admission.NewCreateCvmRequest(vendor, constant.UnassignedBiz, accountID, body)
+	if err != nil {
+		return errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	return svc.admission.Admit(kt, req)
+}
+
 func (svc *cvmSvc) buildCreateAzureCvmTasks(body json.RawMessage) ([]ts.CustomFlowTask, error) {
 
 	req := new(cscvm.AzureCvmCreateReq)
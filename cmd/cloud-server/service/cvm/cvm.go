@@ -23,6 +23,7 @@ package cvm
 import (
 	"net/http"
 
+	"hcm/cmd/cloud-server/logics/admission"
 	"hcm/cmd/cloud-server/logics/audit"
 	"hcm/cmd/cloud-server/logics/cvm"
 	"hcm/cmd/cloud-server/logics/disk"
@@ -42,6 +43,7 @@ func InitCvmService(c *capability.Capability) {
 		diskLgc:    c.Logics.Disk,
 		cvmLgc:     c.Logics.Cvm,
 		eipLgc:     c.Logics.Eip,
+		admission:  c.Logics.Admission,
 	}
 
 	h := rest.NewHandler()
@@ -91,4 +93,5 @@ type cvmSvc struct {
 	diskLgc    disk.Interface
 	cvmLgc     cvm.Interface
 	eipLgc     eip.Interface
+	admission  admission.Interface
 }
@@ -82,8 +82,10 @@ func (svc *cvmSvc) batchDeleteCvmSvc(cts *rest.Contexts, validHandler handler.Va
 		return nil, err
 	}
 	addReq := &ts.AddCustomFlowReq{
-		Name:  enumor.FlowDeleteCvm,
-		Tasks: tasks,
+		Name:        enumor.FlowDeleteCvm,
+		Tasks:       tasks,
+		Priority:    enumor.FlowPriorityHigh,
+		FairnessKey: operationFairnessKey(basicInfoMap),
 	}
 	result, err := svc.client.TaskServer().CreateCustomFlow(cts.Kit, addReq)
 	if err != nil {
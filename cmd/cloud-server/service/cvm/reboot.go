@@ -83,8 +83,10 @@ func (svc *cvmSvc) batchRebootCvmSvc(cts *rest.Contexts, validHandler handler.Va
 		return nil, err
 	}
 	addReq := &ts.AddCustomFlowReq{
-		Name:  enumor.FlowRebootCvm,
-		Tasks: tasks,
+		Name:        enumor.FlowRebootCvm,
+		Tasks:       tasks,
+		Priority:    enumor.FlowPriorityHigh,
+		FairnessKey: operationFairnessKey(basicInfoMap),
 	}
 	result, err := svc.client.TaskServer().CreateCustomFlow(cts.Kit, addReq)
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	proto "hcm/pkg/api/cloud-server/cvm"
 	"hcm/pkg/api/cloud-server/recycle"
 	"hcm/pkg/api/core"
+	corerp "hcm/pkg/api/core/recycle-policy"
 	corerecord "hcm/pkg/api/core/recycle-record"
 	protoaudit "hcm/pkg/api/data-service/audit"
 	"hcm/pkg/api/data-service/cloud"
@@ -87,8 +88,21 @@ func (svc *cvmSvc) recycleCvmSvc(cts *rest.Contexts, validHandler handler.ValidW
 		return nil, err
 	}
 
+	policies, err := logicsrecycle.ListPolicy(cts.Kit, svc.client.DataService(), enumor.CvmCloudResType)
+	if err != nil {
+		return nil, err
+	}
+
 	cvmStatus := make(map[string]*recycle.CvmDetail, len(req.Infos))
-	for _, cvmRecycleReq := range req.Infos {
+	for i, cvmRecycleReq := range req.Infos {
+		// 回收策略要求先解绑时，磁盘和eip不随主机一起回收
+		policy := corerp.PickPolicy(policies, basicInfoMap[cvmRecycleReq.ID].BkBizID)
+		if policy != nil && policy.DetachBeforeRecycle {
+			req.Infos[i].WithDisk = false
+			req.Infos[i].WithEip = false
+			cvmRecycleReq = req.Infos[i]
+		}
+
 		cvmStatus[cvmRecycleReq.ID] = &recycle.CvmDetail{
 			Vendor:           basicInfoMap[cvmRecycleReq.ID].Vendor,
 			AccountID:        basicInfoMap[cvmRecycleReq.ID].AccountID,
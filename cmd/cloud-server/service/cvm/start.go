@@ -88,8 +88,10 @@ func (svc *cvmSvc) batchStartCvmSvc(cts *rest.Contexts, validHandler handler.Val
 		return nil, err
 	}
 	addReq := &ts.AddCustomFlowReq{
-		Name:  enumor.FlowStartCvm,
-		Tasks: tasks,
+		Name:        enumor.FlowStartCvm,
+		Tasks:       tasks,
+		Priority:    enumor.FlowPriorityHigh,
+		FairnessKey: operationFairnessKey(basicInfoMap),
 	}
 	result, err := svc.client.TaskServer().CreateCustomFlow(cts.Kit, addReq)
 	if err != nil {
@@ -100,6 +102,23 @@ func (svc *cvmSvc) batchStartCvmSvc(cts *rest.Contexts, validHandler handler.Val
 	return result, async.WaitTaskToEnd(cts.Kit, svc.client.TaskServer(), result.ID)
 }
 
+// operationFairnessKey 批量操作的主机属于同一个账号时以账号作为任务流公平键，否则不设置公平键
+func operationFairnessKey(basicInfoMap map[string]types.CloudResourceBasicInfo) string {
+	accountID := ""
+	for _, info := range basicInfoMap {
+		if len(accountID) != 0 && accountID != info.AccountID {
+			return ""
+		}
+		accountID = info.AccountID
+	}
+
+	if len(accountID) == 0 {
+		return ""
+	}
+
+	return ts.AccountFairnessKey(accountID)
+}
+
 func buildOperationTasks(actionName enumor.ActionName, basicInfoMap map[string]types.CloudResourceBasicInfo) (
 	[]ts.CustomFlowTask, error) {
 
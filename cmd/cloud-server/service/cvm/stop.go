@@ -83,8 +83,10 @@ func (svc *cvmSvc) batchStopCvmSvc(cts *rest.Contexts, validHandler handler.Vali
 		return nil, err
 	}
 	addReq := &ts.AddCustomFlowReq{
-		Name:  enumor.FlowStopCvm,
-		Tasks: tasks,
+		Name:        enumor.FlowStopCvm,
+		Tasks:       tasks,
+		Priority:    enumor.FlowPriorityHigh,
+		FairnessKey: operationFairnessKey(basicInfoMap),
 	}
 	result, err := svc.client.TaskServer().CreateCustomFlow(cts.Kit, addReq)
 	if err != nil {
@@ -0,0 +1,108 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package dependency 资源删除前依赖检查服务
+package dependency
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/logics/dependency"
+	"hcm/cmd/cloud-server/service/capability"
+	csdependency "hcm/pkg/api/cloud-server/dependency"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// InitService initialize the dependency service.
+func InitService(c *capability.Capability) {
+	svc := &dependencySvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+		dependency: c.Logics.Dependency,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CheckDependency", http.MethodPost, "/dependencies/check", svc.CheckDependency)
+	h.Add("CheckBizDependency", http.MethodPost, "/bizs/{bk_biz_id}/dependencies/check", svc.CheckBizDependency)
+
+	h.Load(c.WebService)
+}
+
+type dependencySvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+	dependency dependency.Interface
+}
+
+// CheckDependency check dependents of resources before deletion.
+func (svc *dependencySvc) CheckDependency(cts *rest.Contexts) (interface{}, error) {
+	return svc.checkDependency(cts, handler.ResOperateAuth)
+}
+
+// CheckBizDependency check dependents of biz resources before deletion.
+func (svc *dependencySvc) CheckBizDependency(cts *rest.Contexts) (interface{}, error) {
+	return svc.checkDependency(cts, handler.BizOperateAuth)
+}
+
+func (svc *dependencySvc) checkDependency(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(csdependency.CheckReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: req.ResType,
+		IDs:          req.IDs,
+		Fields:       types.CommonBasicInfoFields,
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+		ResType: meta.ResourceType(req.ResType), Action: meta.Find, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := svc.dependency.Check(cts.Kit, req.ResType, req.IDs, req.Live)
+	if err != nil {
+		logs.Errorf("check %s dependency failed, err: %v, ids: %v, rid: %s", req.ResType, err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &csdependency.CheckResult{Details: details}, nil
+}
@@ -0,0 +1,206 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package diskbackup
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	corebackup "hcm/pkg/api/core/disk-backup"
+	dsbackup "hcm/pkg/api/data-service/disk-backup"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+const (
+	alertMailTitleTemplate   = "【HCM】硬盘备份失败：%s(%s)"
+	alertMailContentTemplate = "备份策略：%s(%s)<br>业务ID：%d<br>执行结果：%s<br>目标硬盘数：%d<br>成功数：%d<br>" +
+		"失败数：%d<br>开始时间：%s<br>失败原因：%s"
+)
+
+// staleRunTimeout 执行中的备份超过该时间仍未结束时，认为备份任务已中断（如任务流执行失败、节点重启），按备份失败告警
+const staleRunTimeout = 6 * time.Hour
+
+type alerter struct {
+	client  *client.ClientSet
+	state   serviced.State
+	cmsiCli cmsi.Client
+}
+
+// AlertTiming 定时检查执行失败或者长时间未结束的硬盘备份，邮件通知备份策略的告警接收人和创建人，每次执行只告警一次
+func AlertTiming(c *client.ClientSet, state serviced.State, cmsiCli cmsi.Client) {
+	a := &alerter{
+		client:  c,
+		state:   state,
+		cmsiCli: cmsiCli,
+	}
+
+	go a.alertTiming()
+}
+
+func (a *alerter) alertTiming() {
+	for {
+		time.Sleep(time.Minute * 5)
+
+		if !a.state.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		if err := a.alertFailedRuns(kt); err != nil {
+			logs.Errorf("alert failed disk backup run failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
+
+func (a *alerter) alertFailedRuns(kt *kit.Kit) error {
+	listReq := &core.ListReq{
+		Filter: alertRunFilter(time.Now()),
+		Page:   core.NewDefaultBasePage(),
+	}
+	// 告警后的执行记录不再满足查询条件，因此每次都从第一页开始查询
+	runs, err := a.client.DataService().Global.DiskBackupPolicy.ListRun(kt, listReq)
+	if err != nil {
+		logs.Errorf("list failed disk backup run failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	if len(runs.Details) == 0 {
+		return nil
+	}
+
+	policyIDs := slice.Unique(slice.Map(runs.Details, func(r corebackup.DiskBackupRun) string { return r.PolicyID }))
+	policyReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", policyIDs),
+		Page:   core.NewDefaultBasePage(),
+	}
+	policies, err := a.client.DataService().Global.DiskBackupPolicy.List(kt, policyReq)
+	if err != nil {
+		logs.Errorf("list disk backup policy failed, err: %v, ids: %v, rid: %s", err, policyIDs, kt.Rid)
+		return err
+	}
+
+	policyMap := make(map[string]corebackup.DiskBackupPolicy, len(policies.Details))
+	for _, policy := range policies.Details {
+		policyMap[policy.ID] = policy
+	}
+
+	now := time.Now()
+	for _, run := range runs.Details {
+		updateReq := alertRunUpdate(&run, now)
+		if updateReq == nil {
+			continue
+		}
+
+		policy, exist := policyMap[run.PolicyID]
+		if exist {
+			if err = a.sendAlert(kt, &policy, &run); err != nil {
+				logs.Errorf("send disk backup run(%s) alert mail failed, err: %v, rid: %s", run.ID, err, kt.Rid)
+				continue
+			}
+		}
+
+		if err = a.client.DataService().Global.DiskBackupPolicy.UpdateRun(kt, run.ID, updateReq); err != nil {
+			logs.Errorf("mark disk backup run(%s) alerted failed, err: %v, rid: %s", run.ID, err, kt.Rid)
+		}
+	}
+
+	return nil
+}
+
+// alertRunFilter 需要告警的执行记录：执行失败、部分失败，或者执行中但是开始时间早于 staleRunTimeout 的未告警记录
+func alertRunFilter(now time.Time) *filter.Expression {
+	return &filter.Expression{
+		Op: filter.And,
+		Rules: []filter.RuleFactory{
+			tools.RuleEqual("alerted", false),
+			&filter.Expression{
+				Op: filter.Or,
+				Rules: []filter.RuleFactory{
+					tools.RuleIn("state", []enumor.DiskBackupRunState{enumor.DiskBackupFailed,
+						enumor.DiskBackupPartialFailed}),
+					tools.ExpressionAnd(
+						tools.RuleEqual("state", enumor.DiskBackupRunning),
+						tools.RuleLessThanEqual("start_at", times.ConvStdTimeFormat(now.Add(-staleRunTimeout))),
+					),
+				},
+			},
+		},
+	}
+}
+
+// alertRunUpdate 生成告警后更新执行记录的请求，长时间未结束的执行记录同时置为失败，run 会被更新为告警的内容。
+// 执行记录不需要告警时返回nil
+func alertRunUpdate(run *corebackup.DiskBackupRun, now time.Time) *dsbackup.RunUpdateReq {
+	updateReq := &dsbackup.RunUpdateReq{Alerted: converter.ValToPtr(true)}
+	if run.IsFailed() {
+		return updateReq
+	}
+
+	if run.State != enumor.DiskBackupRunning {
+		return nil
+	}
+
+	startAt, err := time.Parse(time.RFC3339, run.StartAt)
+	if err != nil || now.Sub(startAt) < staleRunTimeout {
+		return nil
+	}
+
+	run.State = enumor.DiskBackupFailed
+	run.Reason = fmt.Sprintf("disk backup run is not finished in %s, it may be interrupted", staleRunTimeout)
+	updateReq.State = run.State
+	updateReq.Reason = converter.ValToPtr(run.Reason)
+	updateReq.Finished = true
+	return updateReq
+}
+
+func (a *alerter) sendAlert(kt *kit.Kit, policy *corebackup.DiskBackupPolicy, run *corebackup.DiskBackupRun) error {
+	receivers := slice.Unique(append([]string{policy.Creator}, policy.AlertReceivers...))
+
+	reason := run.Reason
+	if len(reason) == 0 {
+		reasons := make([]string, 0, len(run.FailedDetails))
+		for _, one := range run.FailedDetails {
+			reasons = append(reasons, fmt.Sprintf("%s(%s): %s", one.DiskID, one.Stage, one.Reason))
+		}
+		reason = strings.Join(reasons, "<br>")
+	}
+
+	mail := &cmsi.CmsiMail{
+		ReceiverUserName: strings.Join(receivers, ","),
+		Title:            fmt.Sprintf(alertMailTitleTemplate, policy.Name, policy.ID),
+		Content: fmt.Sprintf(alertMailContentTemplate, policy.Name, policy.ID, policy.BkBizID, run.State,
+			run.TargetCount, run.SuccessCount, run.FailedCount, run.StartAt, reason),
+		BodyFormat: "Html",
+	}
+
+	return a.cmsiCli.SendMail(kt, mail)
+}
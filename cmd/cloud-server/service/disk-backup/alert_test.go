@@ -0,0 +1,104 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package diskbackup
+
+import (
+	"testing"
+	"time"
+
+	corebackup "hcm/pkg/api/core/disk-backup"
+	"hcm/pkg/criteria/enumor"
+	tablebackup "hcm/pkg/dal/table/disk-backup"
+	"hcm/pkg/runtime/filter"
+)
+
+func TestAlertRunUpdate(t *testing.T) {
+	now := time.Now()
+	startAt := func(d time.Duration) string {
+		return now.Add(-d).Format(time.RFC3339)
+	}
+
+	cases := []struct {
+		name      string
+		run       corebackup.DiskBackupRun
+		wantAlert bool
+		wantState enumor.DiskBackupRunState
+	}{
+		{
+			name:      "failed run",
+			run:       corebackup.DiskBackupRun{State: enumor.DiskBackupFailed, StartAt: startAt(time.Minute)},
+			wantAlert: true,
+			wantState: enumor.DiskBackupFailed,
+		},
+		{
+			name:      "partial failed run",
+			run:       corebackup.DiskBackupRun{State: enumor.DiskBackupPartialFailed, StartAt: startAt(time.Minute)},
+			wantAlert: true,
+			wantState: enumor.DiskBackupPartialFailed,
+		},
+		{
+			name:      "stale running run",
+			run:       corebackup.DiskBackupRun{State: enumor.DiskBackupRunning, StartAt: startAt(2 * staleRunTimeout)},
+			wantAlert: true,
+			wantState: enumor.DiskBackupFailed,
+		},
+		{
+			name: "running run",
+			run:  corebackup.DiskBackupRun{State: enumor.DiskBackupRunning, StartAt: startAt(time.Minute)},
+		},
+		{
+			name: "success run",
+			run:  corebackup.DiskBackupRun{State: enumor.DiskBackupSuccess, StartAt: startAt(2 * staleRunTimeout)},
+		},
+	}
+
+	for _, c := range cases {
+		run := c.run
+		req := alertRunUpdate(&run, now)
+		if (req != nil) != c.wantAlert {
+			t.Errorf("case %s: expect alert %v, but got: %v", c.name, c.wantAlert, req != nil)
+			continue
+		}
+
+		if req == nil {
+			continue
+		}
+
+		if err := req.Validate(); err != nil {
+			t.Errorf("case %s: update request should be valid, but got: %v", c.name, err)
+		}
+		if req.Alerted == nil || !*req.Alerted {
+			t.Errorf("case %s: run should be marked as alerted", c.name)
+		}
+		if run.State != c.wantState {
+			t.Errorf("case %s: expect alert state %s, but got: %s", c.name, c.wantState, run.State)
+		}
+		if c.run.State == enumor.DiskBackupRunning && (req.State != enumor.DiskBackupFailed || !req.Finished) {
+			t.Errorf("case %s: stale running run should be finished as failed", c.name)
+		}
+	}
+}
+
+func TestAlertRunFilter(t *testing.T) {
+	opt := filter.NewExprOption(filter.RuleFields(tablebackup.DiskBackupRunColumns.ColumnTypes()))
+	if err := alertRunFilter(time.Now()).Validate(opt); err != nil {
+		t.Errorf("alert run filter should be valid, but got: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package diskbackup ...
+package diskbackup
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/service/capability"
+	"hcm/pkg/client"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/rest"
+)
+
+// InitService initialize the disk backup policy service.
+func InitService(c *capability.Capability) {
+	svc := &diskBackupSvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateBizDiskBackupPolicy", http.MethodPost, "/bizs/{bk_biz_id}/disk_backup_policies/create",
+		svc.CreateBizDiskBackupPolicy)
+	h.Add("UpdateBizDiskBackupPolicy", http.MethodPatch, "/bizs/{bk_biz_id}/disk_backup_policies/{id}",
+		svc.UpdateBizDiskBackupPolicy)
+	h.Add("ListBizDiskBackupPolicy", http.MethodPost, "/bizs/{bk_biz_id}/disk_backup_policies/list",
+		svc.ListBizDiskBackupPolicy)
+	h.Add("BatchDeleteBizDiskBackupPolicy", http.MethodDelete, "/bizs/{bk_biz_id}/disk_backup_policies/batch",
+		svc.BatchDeleteBizDiskBackupPolicy)
+	h.Add("RunBizDiskBackupPolicy", http.MethodPost, "/bizs/{bk_biz_id}/disk_backup_policies/{id}/run",
+		svc.RunBizDiskBackupPolicy)
+	h.Add("ListBizDiskBackupRun", http.MethodPost, "/bizs/{bk_biz_id}/disk_backup_runs/list",
+		svc.ListBizDiskBackupRun)
+
+	h.Load(c.WebService)
+}
+
+type diskBackupSvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+}
@@ -0,0 +1,375 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package diskbackup
+
+import (
+	"fmt"
+
+	actionsnapshot "hcm/cmd/task-server/logics/action/disk-snapshot"
+	proto "hcm/pkg/api/cloud-server"
+	cloudproto "hcm/pkg/api/cloud-server/disk-backup"
+	"hcm/pkg/api/core"
+	corebackup "hcm/pkg/api/core/disk-backup"
+	dataservice "hcm/pkg/api/data-service"
+	dsbackup "hcm/pkg/api/data-service/disk-backup"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// CreateBizDiskBackupPolicy create biz disk backup policy, and register scheduled flow to run it by spec.
+func (svc *diskBackupSvc) CreateBizDiskBackupPolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(cloudproto.CreateReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.DiskBackupPolicy, Action: meta.Create},
+		BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	createReq := &dsbackup.CreateReq{
+		Name:           req.Name,
+		BkBizID:        bizID,
+		Spec:           req.Spec,
+		RetentionCount: req.RetentionCount,
+		Target:         req.Target,
+		AlertReceivers: req.AlertReceivers,
+		Enabled:        req.Enabled,
+		Memo:           req.Memo,
+	}
+	result, err := svc.client.DataService().Global.DiskBackupPolicy.Create(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("create disk backup policy failed, err: %v, biz: %d, rid: %s", err, bizID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if err = svc.syncScheduledFlow(cts.Kit, result.ID, req.Spec, *req.Enabled); err != nil {
+		// 定时任务流注册失败时删除策略，避免存在不会被执行的策略
+		delReq := &dataservice.BatchDeleteReq{Filter: tools.EqualExpression("id", result.ID)}
+		if delErr := svc.client.DataService().Global.DiskBackupPolicy.BatchDelete(cts.Kit, delReq); delErr != nil {
+			logs.Errorf("delete disk backup policy failed, err: %v, id: %s, rid: %s", delErr, result.ID,
+				cts.Kit.Rid)
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateBizDiskBackupPolicy update biz disk backup policy, the scheduled flow is updated with spec and enabled.
+func (svc *diskBackupSvc) UpdateBizDiskBackupPolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(cloudproto.UpdateReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.DiskBackupPolicy, Action: meta.Update},
+		BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	policy, err := svc.getBizPolicy(cts.Kit, bizID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updateReq := &dsbackup.UpdateReq{
+		Name:           req.Name,
+		Spec:           req.Spec,
+		RetentionCount: req.RetentionCount,
+		Target:         req.Target,
+		AlertReceivers: req.AlertReceivers,
+		Enabled:        req.Enabled,
+		Memo:           req.Memo,
+	}
+	if err = svc.client.DataService().Global.DiskBackupPolicy.Update(cts.Kit, id, updateReq); err != nil {
+		logs.Errorf("update disk backup policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(req.Spec) == 0 && req.Enabled == nil {
+		return nil, nil
+	}
+
+	spec, enabled := policy.Spec, policy.Enabled
+	if len(req.Spec) != 0 {
+		spec = req.Spec
+	}
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	return nil, svc.syncScheduledFlow(cts.Kit, id, spec, enabled)
+}
+
+// syncScheduledFlow 注册备份策略的定时任务流，并根据策略是否启用暂停或恢复定时任务流
+func (svc *diskBackupSvc) syncScheduledFlow(kt *kit.Kit, policyID, spec string, enabled bool) error {
+	registerReq := &ts.RegisterScheduledFlowReq{
+		Name:     fmt.Sprintf("%s_%s", enumor.FlowDiskBackupPolicy, policyID),
+		FlowName: enumor.FlowDiskBackupPolicy,
+		Spec:     spec,
+		Memo:     fmt.Sprintf("disk backup policy %s", policyID),
+		Tasks: []ts.TemplateFlowTask{{
+			ActionID: "1",
+			Params: &actionsnapshot.RunDiskBackupPolicyOption{
+				PolicyID: policyID,
+				Trigger:  enumor.TimingDiskBackupTrigger,
+			},
+		}},
+	}
+	result, err := svc.client.TaskServer().RegisterScheduledFlow(kt, registerReq)
+	if err != nil {
+		logs.Errorf("register disk backup policy scheduled flow failed, err: %v, policy: %s, rid: %s", err,
+			policyID, kt.Rid)
+		return err
+	}
+
+	if enabled {
+		err = svc.client.TaskServer().ResumeScheduledFlow(kt, result.ID)
+	} else {
+		err = svc.client.TaskServer().PauseScheduledFlow(kt, result.ID)
+	}
+	if err != nil {
+		logs.Errorf("set disk backup policy scheduled flow enabled: %v failed, err: %v, flow: %s, rid: %s",
+			enabled, err, result.ID, kt.Rid)
+		return err
+	}
+
+	updateReq := &dsbackup.UpdateReq{ScheduledFlowID: result.ID}
+	if err = svc.client.DataService().Global.DiskBackupPolicy.Update(kt, policyID, updateReq); err != nil {
+		logs.Errorf("update disk backup policy scheduled flow id failed, err: %v, policy: %s, rid: %s", err,
+			policyID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (svc *diskBackupSvc) getBizPolicy(kt *kit.Kit, bizID int64, id string) (*corebackup.DiskBackupPolicy,
+	error) {
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("id", id), tools.RuleEqual("bk_biz_id", bizID)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.client.DataService().Global.DiskBackupPolicy.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk backup policy failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk backup policy %s not found in biz %d", id, bizID)
+	}
+
+	return &result.Details[0], nil
+}
+
+// ListBizDiskBackupPolicy list biz disk backup policy.
+func (svc *diskBackupSvc) ListBizDiskBackupPolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(core.ListReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.DiskBackupPolicy, Action: meta.Find},
+		BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	req.Filter, err = tools.And(tools.RuleEqual("bk_biz_id", bizID), req.Filter)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return svc.client.DataService().Global.DiskBackupPolicy.List(cts.Kit, req)
+}
+
+// BatchDeleteBizDiskBackupPolicy batch delete biz disk backup policy and its scheduled flow,
+// the snapshots created by policy are reserved.
+func (svc *diskBackupSvc) BatchDeleteBizDiskBackupPolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(proto.BatchDeleteReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.DiskBackupPolicy, Action: meta.Delete},
+		BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", req.IDs), tools.RuleEqual("bk_biz_id", bizID)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	policies, err := svc.client.DataService().Global.DiskBackupPolicy.List(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list disk backup policy failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(policies.Details) != len(req.IDs) {
+		return nil, errf.Newf(errf.InvalidParameter, "some disk backup policies are not found in biz %d", bizID)
+	}
+
+	for _, policy := range policies.Details {
+		if len(policy.ScheduledFlowID) == 0 {
+			continue
+		}
+
+		err = svc.client.TaskServer().DeleteScheduledFlow(cts.Kit, policy.ScheduledFlowID)
+		if err != nil && !errf.IsRecordNotFound(err) {
+			logs.Errorf("delete disk backup policy scheduled flow failed, err: %v, flow: %s, rid: %s", err,
+				policy.ScheduledFlowID, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	delReq := &dataservice.BatchDeleteReq{Filter: tools.ContainersExpression("id", req.IDs)}
+	if err = svc.client.DataService().Global.DiskBackupPolicy.BatchDelete(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete disk backup policy failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// RunBizDiskBackupPolicy run biz disk backup policy immediately, return the async flow id.
+func (svc *diskBackupSvc) RunBizDiskBackupPolicy(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.DiskBackupPolicy, Action: meta.Update},
+		BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	if _, err = svc.getBizPolicy(cts.Kit, bizID, id); err != nil {
+		return nil, err
+	}
+
+	flowReq := &ts.AddTemplateFlowReq{
+		Name: enumor.FlowDiskBackupPolicy,
+		Tasks: []ts.TemplateFlowTask{{
+			ActionID: "1",
+			Params: &actionsnapshot.RunDiskBackupPolicyOption{
+				PolicyID: id,
+				Trigger:  enumor.ManualDiskBackupTrigger,
+			},
+		}},
+	}
+	result, err := svc.client.TaskServer().CreateTemplateFlow(cts.Kit, flowReq)
+	if err != nil {
+		logs.Errorf("create disk backup policy flow failed, err: %v, policy: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ListBizDiskBackupRun list biz disk backup run.
+func (svc *diskBackupSvc) ListBizDiskBackupRun(cts *rest.Contexts) (interface{}, error) {
+	bizID, err := cts.PathParameter("bk_biz_id").Int64()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(core.ListReq)
+	if err = cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err = req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.DiskBackupPolicy, Action: meta.Find},
+		BizID: bizID}
+	if err = svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	req.Filter, err = tools.And(tools.RuleEqual("bk_biz_id", bizID), req.Filter)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return svc.client.DataService().Global.DiskBackupPolicy.ListRun(cts.Kit, req)
+}
@@ -0,0 +1,64 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot ...
+package disksnapshot
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/service/capability"
+	"hcm/pkg/client"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/rest"
+)
+
+// InitService initialize the disk snapshot service.
+func InitService(c *capability.Capability) {
+	svc := &diskSnapshotSvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+		audit:      c.Audit,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("ListDiskSnapshot", http.MethodPost, "/disk_snapshots/list", svc.ListDiskSnapshot)
+	h.Add("CreateDiskSnapshot", http.MethodPost, "/disk_snapshots/create", svc.CreateDiskSnapshot)
+	h.Add("BatchDeleteDiskSnapshot", http.MethodDelete, "/disk_snapshots/batch", svc.BatchDeleteDiskSnapshot)
+	h.Add("RollbackDiskSnapshot", http.MethodPost, "/disk_snapshots/{id}/rollback", svc.RollbackDiskSnapshot)
+
+	// disk snapshot apis in biz
+	h.Add("ListBizDiskSnapshot", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshots/list", svc.ListBizDiskSnapshot)
+	h.Add("CreateBizDiskSnapshot", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshots/create",
+		svc.CreateBizDiskSnapshot)
+	h.Add("BatchDeleteBizDiskSnapshot", http.MethodDelete, "/bizs/{bk_biz_id}/disk_snapshots/batch",
+		svc.BatchDeleteBizDiskSnapshot)
+	h.Add("RollbackBizDiskSnapshot", http.MethodPost, "/bizs/{bk_biz_id}/disk_snapshots/{id}/rollback",
+		svc.RollbackBizDiskSnapshot)
+
+	h.Load(c.WebService)
+}
+
+type diskSnapshotSvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+	audit      audit.Interface
+}
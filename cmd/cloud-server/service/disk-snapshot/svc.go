@@ -0,0 +1,276 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"fmt"
+	"time"
+
+	actionsnapshot "hcm/cmd/task-server/logics/action/disk-snapshot"
+	cloudproto "hcm/pkg/api/cloud-server/disk-snapshot"
+	"hcm/pkg/api/core"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	"hcm/pkg/api/data-service/cloud"
+	dssnapshot "hcm/pkg/api/data-service/cloud/disk-snapshot"
+	hcproto "hcm/pkg/api/hc-service/disk-snapshot"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// ListDiskSnapshot list disk snapshot.
+func (svc *diskSnapshotSvc) ListDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.listDiskSnapshot(cts, handler.ListResourceAuthRes)
+}
+
+// ListBizDiskSnapshot list biz disk snapshot.
+func (svc *diskSnapshotSvc) ListBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.listDiskSnapshot(cts, handler.ListBizAuthRes)
+}
+
+func (svc *diskSnapshotSvc) listDiskSnapshot(cts *rest.Contexts, authHandler handler.ListAuthResHandler) (
+	interface{}, error) {
+
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// list authorized instances
+	expr, noPermFlag, err := authHandler(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.DiskSnapshot, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &dssnapshot.ListResult{Count: 0, Details: make([]coresnapshot.DiskSnapshot, 0)}, nil
+	}
+	req.Filter = expr
+
+	return svc.client.DataService().Global.DiskSnapshot.List(cts.Kit, req)
+}
+
+// CreateDiskSnapshot create disk snapshot.
+func (svc *diskSnapshotSvc) CreateDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.createDiskSnapshot(cts, handler.ResOperateAuth)
+}
+
+// CreateBizDiskSnapshot create biz disk snapshot.
+func (svc *diskSnapshotSvc) CreateBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.createDiskSnapshot(cts, handler.BizOperateAuth)
+}
+
+// createDiskSnapshot 快照创建为长耗时操作，提交异步任务后立即返回任务ID，任务等待快照创建完成，创建进度通过快照状态查询
+func (svc *diskSnapshotSvc) createDiskSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(cloudproto.CreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, enumor.DiskCloudResType,
+		req.DiskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.DiskSnapshot,
+		Action: meta.Create, BasicInfo: basicInfo})
+	if err != nil {
+		return nil, err
+	}
+
+	// 快照按名称幂等创建，未指定名称时在提交任务前生成，保证任务重试时不会重复创建快照
+	name := req.Name
+	if len(name) == 0 {
+		name = fmt.Sprintf("snapshot-%s-%s", req.DiskID, time.Now().Format("20060102150405"))
+	}
+
+	targets := []actionsnapshot.SnapshotTarget{{DiskID: req.DiskID, Name: name}}
+	flowReq := &ts.AddCustomFlowReq{
+		Name:  enumor.FlowCreateDiskSnapshot,
+		Tasks: actionsnapshot.BuildCreateDiskSnapshotTasks(basicInfo.Vendor, targets, req.Memo),
+	}
+	result, err := svc.client.TaskServer().CreateCustomFlow(cts.Kit, flowReq)
+	if err != nil {
+		logs.Errorf("call taskserver to create disk snapshot flow failed, err: %v, disk: %s, rid: %s", err,
+			req.DiskID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BatchDeleteDiskSnapshot batch delete disk snapshot.
+func (svc *diskSnapshotSvc) BatchDeleteDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteDiskSnapshot(cts, handler.ResOperateAuth)
+}
+
+// BatchDeleteBizDiskSnapshot batch delete biz disk snapshot.
+func (svc *diskSnapshotSvc) BatchDeleteBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchDeleteDiskSnapshot(cts, handler.BizOperateAuth)
+}
+
+func (svc *diskSnapshotSvc) batchDeleteDiskSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(core.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfoReq := cloud.ListResourceBasicInfoReq{
+		ResourceType: enumor.DiskSnapshotCloudResType,
+		IDs:          req.IDs,
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.DiskSnapshot,
+		Action: meta.Delete, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	// create delete audit.
+	if err = svc.audit.ResDeleteAudit(cts.Kit, enumor.DiskSnapshotAuditResType, req.IDs); err != nil {
+		logs.Errorf("create delete audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	succeeded := make([]string, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		info, exists := basicInfoMap[id]
+		if !exists {
+			return nil, errf.Newf(errf.InvalidParameter, "id %s has no corresponding vendor", id)
+		}
+
+		delReq := &hcproto.DeleteReq{ID: id}
+		switch info.Vendor {
+		case enumor.TCloud:
+			err = svc.client.HCService().TCloud.DiskSnapshot.DeleteDiskSnapshot(cts.Kit, delReq)
+		case enumor.Aws:
+			err = svc.client.HCService().Aws.DiskSnapshot.DeleteDiskSnapshot(cts.Kit, delReq)
+		case enumor.HuaWei:
+			err = svc.client.HCService().HuaWei.DiskSnapshot.DeleteDiskSnapshot(cts.Kit, delReq)
+		case enumor.Azure:
+			err = svc.client.HCService().Azure.DiskSnapshot.DeleteDiskSnapshot(cts.Kit, delReq)
+		case enumor.Gcp:
+			err = svc.client.HCService().Gcp.DiskSnapshot.DeleteDiskSnapshot(cts.Kit, delReq)
+		default:
+			err = errf.Newf(errf.InvalidParameter, "vendor: %s not support", info.Vendor)
+		}
+		if err != nil {
+			logs.Errorf("delete disk snapshot failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+			return &core.BatchOperateResult{
+				Succeeded: succeeded,
+				Failed:    &core.FailedInfo{ID: id, Error: err},
+			}, errf.NewFromErr(errf.PartialFailed, err)
+		}
+		succeeded = append(succeeded, id)
+	}
+
+	return nil, nil
+}
+
+// RollbackDiskSnapshot rollback disk by snapshot.
+func (svc *diskSnapshotSvc) RollbackDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.rollbackDiskSnapshot(cts, handler.ResOperateAuth)
+}
+
+// RollbackBizDiskSnapshot rollback biz disk by snapshot.
+func (svc *diskSnapshotSvc) RollbackBizDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	return svc.rollbackDiskSnapshot(cts, handler.BizOperateAuth)
+}
+
+// rollbackDiskSnapshot 使用快照数据覆盖源硬盘，仅腾讯云、华为云支持
+func (svc *diskSnapshotSvc) rollbackDiskSnapshot(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit,
+		enumor.DiskSnapshotCloudResType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.DiskSnapshot,
+		Action: meta.Update, BasicInfo: basicInfo})
+	if err != nil {
+		return nil, err
+	}
+
+	if basicInfo.Vendor != enumor.TCloud && basicInfo.Vendor != enumor.HuaWei {
+		return nil, errf.NewFromErr(errf.InvalidParameter,
+			fmt.Errorf("vendor: %s not support rollback disk snapshot", basicInfo.Vendor))
+	}
+
+	auditInfo := protoaudit.CloudResourceOperationInfo{
+		ResType: enumor.DiskSnapshotAuditResType,
+		ResID:   id,
+		Action:  protoaudit.Rollback,
+	}
+	if err = svc.audit.ResOperationAudit(cts.Kit, auditInfo); err != nil {
+		logs.Errorf("create rollback audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	rollbackReq := &hcproto.RollbackReq{ID: id}
+	switch basicInfo.Vendor {
+	case enumor.TCloud:
+		err = svc.client.HCService().TCloud.DiskSnapshot.RollbackDiskSnapshot(cts.Kit, rollbackReq)
+	case enumor.HuaWei:
+		err = svc.client.HCService().HuaWei.DiskSnapshot.RollbackDiskSnapshot(cts.Kit, rollbackReq)
+	}
+	if err != nil {
+		logs.Errorf("rollback disk snapshot failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
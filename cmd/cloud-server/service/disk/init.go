@@ -31,6 +31,7 @@ func InitDiskService(c *capability.Capability) {
 		authorizer: c.Authorizer,
 		audit:      c.Audit,
 		diskLgc:    c.Logics.Disk,
+		dependency: c.Logics.Dependency,
 	}
 
 	h := rest.NewHandler()
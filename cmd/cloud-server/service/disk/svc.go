@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/logics/dependency"
 	disklgc "hcm/cmd/cloud-server/logics/disk"
 	cloudproto "hcm/pkg/api/cloud-server/disk"
 	"hcm/pkg/api/core"
@@ -50,6 +51,7 @@ type diskSvc struct {
 	authorizer auth.Authorizer
 	audit      audit.Interface
 	diskLgc    disklgc.Interface
+	dependency dependency.Interface
 }
 
 // ListDisk list disk.
@@ -154,6 +156,11 @@ func (svc *diskSvc) deleteDisk(cts *rest.Contexts, validHandler handler.ValidWit
 		return nil, err
 	}
 
+	// check if the disk is still attached to cvm
+	if err = svc.dependency.EnsureDeletable(cts.Kit, enumor.DiskCloudResType, []string{diskID}); err != nil {
+		return nil, err
+	}
+
 	err = svc.diskLgc.DeleteDisk(cts.Kit, basicInfo.Vendor, basicInfo.ID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,116 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package distributedlock 分布式锁管理服务
+package distributedlock
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/service/capability"
+	cslock "hcm/pkg/api/cloud-server/lock"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/lock"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitService initialize the distributed lock service.
+func InitService(c *capability.Capability) {
+	svc := &lockSvc{
+		authorizer: c.Authorizer,
+		locker:     c.Locker,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("ListDistributedLock", http.MethodPost, "/locks/list", svc.ListLock)
+	h.Add("ForceReleaseDistributedLock", http.MethodPost, "/locks/force_release", svc.ForceReleaseLock)
+
+	h.Load(c.WebService)
+}
+
+type lockSvc struct {
+	authorizer auth.Authorizer
+	locker     lock.Locker
+}
+
+// ListLock list distributed locks which are held now.
+func (svc *lockSvc) ListLock(cts *rest.Contexts) (interface{}, error) {
+	req := new(cslock.ListLockReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.DistributedLock, Action: meta.Find}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	details, err := svc.locker.List(cts.Kit, req.Prefix)
+	if err != nil {
+		logs.Errorf("list distributed lock failed, err: %v, prefix: %s, rid: %s", err, req.Prefix, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &cslock.ListLockResult{Details: details}, nil
+}
+
+// ForceReleaseLock force release distributed lock, the holder will find the lock lost when renewing it.
+func (svc *lockSvc) ForceReleaseLock(cts *rest.Contexts) (interface{}, error) {
+	req := new(cslock.ForceReleaseLockReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.DistributedLock, Action: meta.Delete}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	info, err := svc.locker.Get(cts.Kit, req.Key)
+	if err != nil {
+		logs.Errorf("get distributed lock failed, err: %v, key: %s, rid: %s", err, req.Key, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if info == nil {
+		return nil, errf.Newf(errf.RecordNotFound, "lock %s is not held", req.Key)
+	}
+
+	if err = svc.locker.ForceRelease(cts.Kit, req.Key); err != nil {
+		logs.Errorf("force release distributed lock failed, err: %v, key: %s, rid: %s", err, req.Key, cts.Kit.Rid)
+		return nil, err
+	}
+
+	logs.Infof("distributed lock %s is force released by %s, holder: %s, token: %d, rid: %s", req.Key,
+		cts.Kit.User, info.Owner, info.Token, cts.Kit.Rid)
+
+	return nil, nil
+}
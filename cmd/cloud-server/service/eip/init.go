@@ -43,6 +43,8 @@ func InitEipService(c *capability.Capability) {
 		gcp:        gcp.NewGcp(c.ApiClient, c.Authorizer, c.Audit),
 		huawei:     huawei.NewHuaWei(c.ApiClient, c.Authorizer, c.Audit),
 		eip:        c.Logics.Eip,
+		admission:  c.Logics.Admission,
+		dependency: c.Logics.Dependency,
 	}
 
 	h := rest.NewHandler()
@@ -57,6 +59,8 @@ func InitEipService(c *capability.Capability) {
 	h.Add("AssociateEip", http.MethodPost, "/eips/associate", svc.AssociateEip)
 	h.Add("DisassociateEip", http.MethodPost, "/eips/disassociate", svc.DisassociateEip)
 	h.Add("CreateEip", http.MethodPost, "/eips/create", svc.CreateEip)
+	h.Add("RecycleEip", http.MethodPost, "/eips/recycle", svc.RecycleEip)
+	h.Add("RecoverEip", http.MethodPost, "/eips/recover", svc.RecoverEip)
 
 	// eip apis in biz
 	h.Add("ListBizEip", http.MethodPost, "/bizs/{bk_biz_id}/eips/list", svc.ListBizEip)
@@ -69,6 +73,8 @@ func InitEipService(c *capability.Capability) {
 	h.Add("AssociateBizEip", http.MethodPost, "/bizs/{bk_biz_id}/eips/associate", svc.AssociateBizEip)
 	h.Add("DisassociateBizEip", http.MethodPost, "/bizs/{bk_biz_id}/eips/disassociate", svc.DisassociateBizEip)
 	h.Add("CreateBizEip", http.MethodPost, "/bizs/{bk_biz_id}/eips/create", svc.CreateBizEip)
+	h.Add("RecycleBizEip", http.MethodPost, "/bizs/{bk_biz_id}/eips/recycle", svc.RecycleBizEip)
+	h.Add("RecoverBizEip", http.MethodPost, "/bizs/{bk_biz_id}/eips/recover", svc.RecoverBizEip)
 
 	h.Load(c.WebService)
 }
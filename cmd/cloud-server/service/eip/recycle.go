@@ -0,0 +1,245 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eip
+
+import (
+	logicsrecycle "hcm/cmd/cloud-server/logics/recycle"
+	csrecycle "hcm/pkg/api/cloud-server/recycle"
+	"hcm/pkg/api/core"
+	corerp "hcm/pkg/api/core/recycle-policy"
+	corerr "hcm/pkg/api/core/recycle-record"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	"hcm/pkg/api/data-service/cloud"
+	dsrr "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// RecycleEip recycle eip.
+func (svc *eipSvc) RecycleEip(cts *rest.Contexts) (interface{}, error) {
+	return svc.recycleEipSvc(cts, handler.ResOperateAuth)
+}
+
+// RecycleBizEip recycle biz eip.
+func (svc *eipSvc) RecycleBizEip(cts *rest.Contexts) (interface{}, error) {
+	return svc.recycleEipSvc(cts, handler.BizOperateAuth)
+}
+
+func (svc *eipSvc) recycleEipSvc(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (interface{}, error) {
+	req := new(csrecycle.ResRecycleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfoReq := cloud.ListResourceBasicInfoReq{
+		ResourceType: enumor.EipCloudResType,
+		IDs:          req.IDs,
+		Fields:       append(types.CommonBasicInfoFields, "recycle_status"),
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Eip,
+		Action: meta.Recycle, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := logicsrecycle.ListPolicy(cts.Kit, svc.client.DataService(), enumor.EipCloudResType)
+	if err != nil {
+		return nil, err
+	}
+
+	// 未配置回收前解绑的eip，要求不能绑定在主机上
+	detachIDs, checkIDs := make([]string, 0), make([]string, 0)
+	for _, id := range req.IDs {
+		policy := corerp.PickPolicy(policies, basicInfoMap[id].BkBizID)
+		if policy != nil && policy.DetachBeforeRecycle {
+			detachIDs = append(detachIDs, id)
+			continue
+		}
+		checkIDs = append(checkIDs, id)
+	}
+	if len(checkIDs) > 0 {
+		if err = svc.dependency.EnsureDeletable(cts.Kit, enumor.EipCloudResType, checkIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	auditInfos := make([]protoaudit.CloudResRecycleAuditInfo, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		auditInfos = append(auditInfos, protoaudit.CloudResRecycleAuditInfo{ResID: id})
+	}
+	auditReq := &protoaudit.CloudResourceRecycleAuditReq{
+		ResType: enumor.EipAuditResType,
+		Action:  protoaudit.Recycle,
+		Infos:   auditInfos,
+	}
+	if err = svc.audit.ResRecycleAudit(cts.Kit, auditReq); err != nil {
+		logs.Errorf("create recycle audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return svc.recycleEip(cts.Kit, req.IDs, detachIDs, basicInfoMap)
+}
+
+func (svc *eipSvc) recycleEip(kt *kit.Kit, ids, detachIDs []string,
+	infoMap map[string]types.CloudResourceBasicInfo) (interface{}, error) {
+
+	res := new(core.BatchOperateAllResult)
+
+	// 按回收策略解绑eip，解绑失败的eip不进入回收站
+	details := make(map[string]corerr.EipRecycleDetail, len(ids))
+	failedIDMap := make(map[string]struct{})
+	for _, id := range detachIDs {
+		info := infoMap[id]
+		cvmID, err := svc.eip.UnbindEip(kt, info.Vendor, info.AccountID, id)
+		if err != nil {
+			res.Failed = append(res.Failed, core.FailedInfo{ID: id, Error: err})
+			failedIDMap[id] = struct{}{}
+			continue
+		}
+		details[id] = corerr.EipRecycleDetail{DetachedCvmID: cvmID}
+	}
+
+	if len(failedIDMap) == len(ids) {
+		return res, res.Failed[0].Error
+	}
+
+	opt := &dsrr.BatchRecycleReq{
+		ResType:            enumor.EipCloudResType,
+		DefaultRecycleTime: cc.CloudServer().Recycle.AutoDeleteTime,
+		Infos:              make([]dsrr.RecycleReq, 0, len(ids)),
+	}
+	for _, id := range ids {
+		if _, exists := failedIDMap[id]; exists {
+			continue
+		}
+		opt.Infos = append(opt.Infos, dsrr.RecycleReq{ID: id, Detail: details[id]})
+	}
+
+	taskID, err := svc.client.DataService().Global.RecycleRecord.BatchRecycleCloudRes(kt, opt)
+	if err != nil {
+		logs.Errorf("fail to recycle eip, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return nil, err
+	}
+
+	if len(res.Failed) > 0 {
+		return res, res.Failed[0].Error
+	}
+	return &csrecycle.RecycleResult{TaskID: taskID}, nil
+}
+
+// RecoverEip recover eip.
+func (svc *eipSvc) RecoverEip(cts *rest.Contexts) (interface{}, error) {
+	return svc.recoverEip(cts, handler.ResOperateAuth)
+}
+
+// RecoverBizEip recover biz eip.
+func (svc *eipSvc) RecoverBizEip(cts *rest.Contexts) (interface{}, error) {
+	return svc.recoverEip(cts, handler.BizOperateAuth)
+}
+
+func (svc *eipSvc) recoverEip(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (interface{}, error) {
+	req := new(csrecycle.ResRecoverReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", req.RecordIDs),
+		Page:   &core.BasePage{Limit: constant.BatchOperationMaxLimit},
+	}
+	records, err := svc.client.DataService().Global.RecycleRecord.ListRecycleRecord(cts.Kit, listReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records.Details) != len(req.RecordIDs) {
+		return nil, errf.New(errf.InvalidParameter, "some record_ids are not in recycle bin")
+	}
+	if err = logicsrecycle.ValidateRecoverRecords(records, enumor.EipCloudResType); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	eipIDs := make([]string, 0, len(records.Details))
+	auditInfos := make([]protoaudit.CloudResRecycleAuditInfo, 0, len(records.Details))
+	for _, record := range records.Details {
+		eipIDs = append(eipIDs, record.ResID)
+		auditInfos = append(auditInfos, protoaudit.CloudResRecycleAuditInfo{ResID: record.ResID, Data: record.Detail})
+	}
+
+	basicInfoReq := cloud.ListResourceBasicInfoReq{
+		ResourceType: enumor.EipCloudResType,
+		IDs:          eipIDs,
+		Fields:       append(types.CommonBasicInfoFields, "recycle_status"),
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.Eip,
+		Action: meta.Recover, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	auditReq := &protoaudit.CloudResourceRecycleAuditReq{
+		ResType: enumor.EipAuditResType,
+		Action:  protoaudit.Recover,
+		Infos:   auditInfos,
+	}
+	if err = svc.audit.ResRecycleAudit(cts.Kit, auditReq); err != nil {
+		logs.Errorf("create recover audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	opt := &dsrr.BatchRecoverReq{
+		ResType:   enumor.EipCloudResType,
+		RecordIDs: req.RecordIDs,
+	}
+	if err = svc.client.DataService().Global.RecycleRecord.BatchRecoverCloudResource(cts.Kit, opt); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
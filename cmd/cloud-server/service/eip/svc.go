@@ -20,10 +20,15 @@
 package eip
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 
+	"hcm/cmd/cloud-server/logics/admission"
 	"hcm/cmd/cloud-server/logics/async"
 	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/logics/dependency"
 	"hcm/cmd/cloud-server/logics/eip"
 	"hcm/cmd/cloud-server/service/common"
 	"hcm/cmd/cloud-server/service/eip/aws"
@@ -63,6 +68,8 @@ type eipSvc struct {
 	gcp        *gcp.Gcp
 	huawei     *huawei.HuaWei
 	eip        eip.Interface
+	admission  admission.Interface
+	dependency dependency.Interface
 }
 
 // ListEip list eip.
@@ -268,6 +275,11 @@ func (svc *eipSvc) batchDeleteEip(cts *rest.Contexts, validHandler handler.Valid
 		return nil, err
 	}
 
+	// check if the eips are still associated with cvms
+	if err = svc.dependency.EnsureDeletable(cts.Kit, enumor.EipCloudResType, req.IDs); err != nil {
+		return nil, err
+	}
+
 	// create delete audit.
 	err = svc.audit.ResDeleteAudit(cts.Kit, enumor.EipAuditResType, req.IDs)
 	if err != nil {
@@ -275,8 +287,6 @@ func (svc *eipSvc) batchDeleteEip(cts *rest.Contexts, validHandler handler.Valid
 		return nil, err
 	}
 
-	// TODO 判断 Eip 是否可删除
-
 	tasks := make([]ts.CustomFlowTask, 0, len(req.IDs))
 	var nextID = counter.NewNumStringCounter(1, 10)
 	for _, eipID := range req.IDs {
@@ -349,6 +359,10 @@ func (svc *eipSvc) createEip(cts *rest.Contexts, bizID int64,
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err = svc.admitCreateEip(cts, baseInfo.Vendor, accountID, bizID); err != nil {
+		return nil, err
+	}
+
 	switch baseInfo.Vendor {
 	case enumor.TCloud:
 		return svc.tcloud.CreateEip(cts, bizID)
@@ -365,6 +379,30 @@ func (svc *eipSvc) createEip(cts *rest.Contexts, bizID int64,
 	}
 }
 
+// admitCreateEip 使用准入策略校验创建弹性 IP 请求，请求体读取后会被重置，以便后续按云厂商解析。
+func (svc *eipSvc) admitCreateEip(cts *rest.Contexts, vendor enumor.Vendor, accountID string, bizID int64) error {
+	body, err := io.ReadAll(cts.Request.Request.Body)
+	if err != nil {
+		logs.Errorf("read request body failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return err
+	}
+	cts.Request.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	obj := make(admission.Object)
+	if err = json.Unmarshal(body, &obj); err != nil {
+		return errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	return svc.admission.Admit(cts.Kit, &admission.Request{
+		ResType:   enumor.EipCloudResType,
+		Action:    enumor.AdmissionCreate,
+		Vendor:    vendor,
+		BkBizID:   bizID,
+		AccountID: accountID,
+		Objects:   []admission.Object{obj},
+	})
+}
+
 func (svc *eipSvc) authorizeEipAssignOp(kt *kit.Kit, ids []string) error {
 	basicInfoReq := cloud.ListResourceBasicInfoReq{
 		ResourceType: enumor.EipCloudResType,
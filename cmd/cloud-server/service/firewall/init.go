@@ -23,6 +23,7 @@ import (
 	"net/http"
 
 	"hcm/cmd/cloud-server/logics/audit"
+	sglogics "hcm/cmd/cloud-server/logics/security-group"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/pkg/client"
 	"hcm/pkg/iam/auth"
@@ -35,6 +36,7 @@ func InitFirewallService(cap *capability.Capability) {
 		client:     cap.ApiClient,
 		authorizer: cap.Authorizer,
 		audit:      cap.Audit,
+		sgLogic:    cap.Logics.SecurityGroup,
 	}
 
 	h := rest.NewHandler()
@@ -45,6 +47,8 @@ func InitFirewallService(cap *capability.Capability) {
 		svc.BatchDeleteGcpFirewallRule)
 	h.Add("UpdateGcpFirewallRule", http.MethodPut, "/vendors/gcp/firewalls/rules/{id}", svc.UpdateGcpFirewallRule)
 	h.Add("ListGcpFirewallRule", http.MethodPost, "/vendors/gcp/firewalls/rules/list", svc.ListGcpFirewallRule)
+	h.Add("AnalyzeGcpFirewallRuleRisk", http.MethodPost, "/vendors/gcp/firewalls/rules/risks/analyze",
+		svc.AnalyzeGcpFirewallRuleRisk)
 	h.Add("GetGcpFirewallRule", http.MethodGet, "/vendors/gcp/firewalls/rules/{id}", svc.GetGcpFirewallRule)
 	h.Add("AssignGcpFirewallRuleToBiz", http.MethodPost, "/vendors/gcp/firewalls/rules/assign/bizs",
 		svc.AssignGcpFirewallRuleToBiz)
@@ -60,6 +64,8 @@ func InitFirewallService(cap *capability.Capability) {
 		svc.ListBizGcpFirewallRule)
 	h.Add("GetBizGcpFirewallRule", http.MethodGet, "/bizs/{bk_biz_id}/vendors/gcp/firewalls/rules/{id}",
 		svc.GetBizGcpFirewallRule)
+	h.Add("AnalyzeBizGcpFirewallRuleRisk", http.MethodPost,
+		"/bizs/{bk_biz_id}/vendors/gcp/firewalls/rules/risks/analyze", svc.AnalyzeBizGcpFirewallRuleRisk)
 
 	h.Load(cap.WebService)
 }
@@ -68,4 +74,5 @@ type firewallSvc struct {
 	client     *client.ClientSet
 	authorizer auth.Authorizer
 	audit      audit.Interface
+	sgLogic    sglogics.Interface
 }
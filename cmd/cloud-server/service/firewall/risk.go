@@ -0,0 +1,87 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package firewall
+
+import (
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// AnalyzeGcpFirewallRuleRisk analyze gcp firewall rule risks grouped by vpc.
+func (svc *firewallSvc) AnalyzeGcpFirewallRuleRisk(cts *rest.Contexts) (interface{}, error) {
+	return svc.analyzeGcpFirewallRuleRisk(cts, handler.ListResourceAuthRes)
+}
+
+// AnalyzeBizGcpFirewallRuleRisk analyze biz gcp firewall rule risks grouped by vpc.
+func (svc *firewallSvc) AnalyzeBizGcpFirewallRuleRisk(cts *rest.Contexts) (interface{}, error) {
+	return svc.analyzeGcpFirewallRuleRisk(cts, handler.ListBizAuthRes)
+}
+
+func (svc *firewallSvc) analyzeGcpFirewallRuleRisk(cts *rest.Contexts, authHandler handler.ListAuthResHandler) (
+	interface{}, error) {
+
+	req := new(proto.GcpFirewallRiskAnalyzeReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// list authorized instances
+	expr, noPermFlag, err := authHandler(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.GcpFirewallRule, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return &proto.SGRiskAnalyzeResult{Details: make([]proto.SGRiskReport, 0)}, nil
+	}
+
+	rules := make([]corecloud.GcpFirewallRule, 0)
+	listReq := &dataproto.GcpFirewallRuleListReq{
+		Filter: expr,
+		Page:   core.NewDefaultBasePage(),
+	}
+	for {
+		result, err := svc.client.DataService().Gcp.Firewall.ListFirewallRule(cts.Kit.Ctx, cts.Kit.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list firewall rule failed, err: %v, req: %v, rid: %s", err, req, cts.Kit.Rid)
+			return nil, err
+		}
+
+		rules = append(rules, result.Details...)
+		if uint(len(result.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return &proto.SGRiskAnalyzeResult{Details: svc.sgLogic.AnalyzeGcpFirewallRisk(cts.Kit, rules)}, nil
+}
@@ -0,0 +1,231 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package ipam 地址管理服务
+package ipam
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/logics/ipam"
+	"hcm/cmd/cloud-server/service/capability"
+	proto "hcm/pkg/api/cloud-server"
+	csipam "hcm/pkg/api/cloud-server/ipam"
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dsipam "hcm/pkg/api/data-service/ipam"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitService initialize the ipam service.
+func InitService(c *capability.Capability) {
+	svc := &ipamSvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+		ipam:       c.Logics.IPAM,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CheckIPAMCidr", http.MethodPost, "/ipam/cidrs/check", svc.CheckCidr)
+	h.Add("SuggestIPAMCidr", http.MethodPost, "/ipam/cidrs/suggest", svc.SuggestCidr)
+	h.Add("ListIPAMBlock", http.MethodPost, "/ipam/blocks/list", svc.ListBlock)
+	h.Add("ReserveIPAMBlock", http.MethodPost, "/ipam/blocks/reserve", svc.ReserveBlock)
+	h.Add("BatchDeleteReservedIPAMBlock", http.MethodDelete, "/ipam/blocks/reserved/batch",
+		svc.BatchDeleteReservedBlock)
+	h.Add("SyncIPAMBlock", http.MethodPost, "/ipam/blocks/sync", svc.SyncBlock)
+
+	h.Load(c.WebService)
+}
+
+type ipamSvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+	ipam       ipam.Interface
+}
+
+// CheckCidr check if the cidr conflicts with the ipam blocks in scope.
+func (svc *ipamSvc) CheckCidr(cts *rest.Contexts) (interface{}, error) {
+	req := new(csipam.CheckCidrReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.IPAM, Action: meta.Find}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	conflicts, err := svc.ipam.CheckCidr(cts.Kit, req)
+	if err != nil {
+		logs.Errorf("check ipam cidr failed, err: %v, cidr: %s, rid: %s", err, req.Cidr, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &csipam.CheckCidrResult{Available: len(conflicts) == 0, Conflicts: conflicts}, nil
+}
+
+// SuggestCidr suggest free cidr blocks in parent cidr.
+func (svc *ipamSvc) SuggestCidr(cts *rest.Contexts) (interface{}, error) {
+	req := new(csipam.SuggestCidrReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.IPAM, Action: meta.Find}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	cidrs, err := svc.ipam.SuggestCidr(cts.Kit, req)
+	if err != nil {
+		logs.Errorf("suggest ipam cidr failed, err: %v, parent: %s, rid: %s", err, req.ParentCidr, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &csipam.SuggestCidrResult{Cidrs: cidrs}, nil
+}
+
+// ListBlock list ipam block.
+func (svc *ipamSvc) ListBlock(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.IPAM, Action: meta.Find}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	return svc.client.DataService().Global.IPAMBlock.List(cts.Kit, req)
+}
+
+// ReserveBlock reserve ipam block for vpc to be created, the block can not conflict with blocks in same cloud area.
+func (svc *ipamSvc) ReserveBlock(cts *rest.Contexts) (interface{}, error) {
+	req := new(csipam.ReserveBlockReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.IPAM, Action: meta.Create}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	if err := svc.ipam.EnsureVpcCidrAvailable(cts.Kit, req.BkCloudID, []string{req.Cidr}); err != nil {
+		return nil, err
+	}
+
+	createReq := &dsipam.BatchCreateReq{
+		Blocks: []dsipam.BlockCreate{{
+			Type:      enumor.IPAMReservedBlock,
+			Vendor:    req.Vendor,
+			AccountID: req.AccountID,
+			Region:    req.Region,
+			BkCloudID: req.BkCloudID,
+			BkBizID:   req.BkBizID,
+			Cidr:      req.Cidr,
+			Memo:      req.Memo,
+		}},
+	}
+	result, err := svc.client.DataService().Global.IPAMBlock.BatchCreate(cts.Kit, createReq)
+	if err != nil {
+		logs.Errorf("reserve ipam block failed, err: %v, cidr: %s, rid: %s", err, req.Cidr, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: result.IDs[0]}, nil
+}
+
+// BatchDeleteReservedBlock batch delete reserved ipam block, blocks of vpc and subnet are maintained by sync.
+func (svc *ipamSvc) BatchDeleteReservedBlock(cts *rest.Contexts) (interface{}, error) {
+	req := new(proto.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.IPAM, Action: meta.Delete}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	delReq := &dataservice.BatchDeleteReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("id", req.IDs), tools.RuleEqual("type", enumor.IPAMReservedBlock)),
+	}
+	if err := svc.client.DataService().Global.IPAMBlock.BatchDelete(cts.Kit, delReq); err != nil {
+		logs.Errorf("delete reserved ipam block failed, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// SyncBlock refresh ipam blocks of vpc and subnet of accounts.
+func (svc *ipamSvc) SyncBlock(cts *rest.Contexts) (interface{}, error) {
+	req := new(csipam.SyncBlockReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	authRes := meta.ResourceAttribute{Basic: &meta.Basic{Type: meta.IPAM, Action: meta.Update}}
+	if err := svc.authorizer.AuthorizeWithPerm(cts.Kit, authRes); err != nil {
+		return nil, err
+	}
+
+	for _, accountID := range req.AccountIDs {
+		if err := svc.ipam.SyncAccountBlocks(cts.Kit, accountID); err != nil {
+			logs.Errorf("sync ipam block failed, err: %v, account: %s, rid: %s", err, accountID, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
@@ -216,31 +216,49 @@ func (svc *lbSvc) buildDeleteRuleTasks(kt *kit.Kit, lbRuleMap map[string]cslb.Tc
 	flowStateResults := make([]*core.FlowStateResult, 0)
 
 	for lbID, ruleIDs := range lbRuleMap {
-		// 预检测
-		_, err := svc.checkResFlowRel(kt, lbID, enumor.LoadBalancerCloudResType)
+		result, err := svc.buildDeleteRuleTask(kt, lbID, ruleIDs, vendor)
 		if err != nil {
-			logs.Errorf("check resource flow relation failed, err: %v, rid: %s", err, kt.Rid)
 			return nil, err
 		}
 
-		// 创建Flow跟Task的初始化数据
-		flowID, err := svc.initFlowDeleteRule(kt, lbID, ruleIDs, vendor)
-		if err != nil {
-			logs.Errorf("init flow batch delete rule failed, err: %v, rid: %s", err, kt.Rid)
-			return nil, err
-		}
+		flowStateResults = append(flowStateResults, result)
+	}
 
-		// 锁定资源跟Flow的状态
-		err = svc.lockResFlowStatus(kt, lbID, enumor.LoadBalancerCloudResType, flowID, enumor.DeleteRuleTaskType)
-		if err != nil {
-			logs.Errorf("lock resource flow status failed, err: %v, rid: %s", err, kt.Rid)
-			return nil, err
-		}
+	return flowStateResults, nil
+}
+
+func (svc *lbSvc) buildDeleteRuleTask(kt *kit.Kit, lbID string, ruleIDs cslb.TcloudBatchDeleteRuleIDs,
+	vendor enumor.Vendor) (*core.FlowStateResult, error) {
 
-		flowStateResults = append(flowStateResults, &core.FlowStateResult{FlowID: flowID})
+	// 加锁，避免并发请求同时通过预检测
+	unlock, err := svc.lockResForFlow(kt, lbID, enumor.LoadBalancerCloudResType)
+	if err != nil {
+		return nil, err
 	}
+	defer unlock()
 
-	return flowStateResults, nil
+	// 预检测
+	_, err = svc.checkResFlowRel(kt, lbID, enumor.LoadBalancerCloudResType)
+	if err != nil {
+		logs.Errorf("check resource flow relation failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	// 创建Flow跟Task的初始化数据
+	flowID, err := svc.initFlowDeleteRule(kt, lbID, ruleIDs, vendor)
+	if err != nil {
+		logs.Errorf("init flow batch delete rule failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	// 锁定资源跟Flow的状态
+	err = svc.lockResFlowStatus(kt, lbID, enumor.LoadBalancerCloudResType, flowID, enumor.DeleteRuleTaskType)
+	if err != nil {
+		logs.Errorf("lock resource flow status failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	return &core.FlowStateResult{FlowID: flowID}, nil
 }
 
 func (svc *lbSvc) initFlowDeleteRule(kt *kit.Kit, lbID string, ruleIDs cslb.TcloudBatchDeleteRuleIDs, vendor enumor.Vendor) (string, error) {
@@ -23,6 +23,7 @@ package loadbalancer
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
 	actionflow "hcm/cmd/task-server/logics/flow"
@@ -37,6 +38,7 @@ import (
 	"hcm/pkg/async/action"
 	"hcm/pkg/async/backend"
 	"hcm/pkg/async/producer"
+	"hcm/pkg/cc"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
@@ -45,6 +47,7 @@ import (
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/kit"
+	"hcm/pkg/lock"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
 	"hcm/pkg/tools/classifier"
@@ -216,8 +219,15 @@ func (svc *lbSvc) batchCreateTargetDb(kt *kit.Kit, targets []*dataproto.TargetBa
 func (svc *lbSvc) buildAddTCloudTargetTasks(kt *kit.Kit, accountID, lbID string,
 	tgMap map[string][]*dataproto.TargetBaseReq) (*core.FlowStateResult, error) {
 
+	// 加锁，避免并发请求同时通过预检测
+	unlock, err := svc.lockResForFlow(kt, lbID, enumor.LoadBalancerCloudResType)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	// 预检测
-	_, err := svc.checkResFlowRel(kt, lbID, enumor.LoadBalancerCloudResType)
+	_, err = svc.checkResFlowRel(kt, lbID, enumor.LoadBalancerCloudResType)
 	if err != nil {
 		logs.Errorf("check resource flow relation failed, err: %v, rid: %s", err, kt.Rid)
 		return nil, err
@@ -281,6 +291,7 @@ func (svc *lbSvc) initFlowAddTargetByLbID(kt *kit.Kit, accountID, lbID string,
 		}),
 		Tasks:       tasks,
 		IsInitState: true,
+		FairnessKey: ts.AccountFairnessKey(accountID),
 	}
 	result, err := svc.client.TaskServer().CreateCustomFlow(kt, addReq)
 	if err != nil {
@@ -402,6 +413,29 @@ func (svc *lbSvc) lockResFlowStatus(kt *kit.Kit, resID string, resType enumor.Cl
 	return nil
 }
 
+// lockResForFlow 对资源加分布式锁，保证同一资源的预检测、创建Flow、锁定资源跟Flow的状态不会被并发执行，
+// 返回的函数用于释放锁
+func (svc *lbSvc) lockResForFlow(kt *kit.Kit, resID string, resType enumor.CloudResourceType) (func(), error) {
+	key := lock.Key(string(cc.CloudServerName), "res_flow", string(resType), resID)
+	lease, err := svc.locker.TryLock(kt, key, &lock.Option{TTL: time.Minute, Owner: kt.Rid, AutoRenew: true})
+	if err != nil {
+		if errf.IsLockHeld(err) {
+			return nil, errf.Newf(errf.LoadBalancerTaskExecuting, "resID: %s is processing", resID)
+		}
+
+		logs.Errorf("lock resource for flow failed, err: %v, resID: %s, resType: %s, rid: %s", err, resID, resType,
+			kt.Rid)
+		return nil, err
+	}
+
+	return func() {
+		if err := svc.locker.Unlock(kt, lease); err != nil {
+			logs.Errorf("unlock resource for flow failed, err: %v, resID: %s, resType: %s, rid: %s", err, resID,
+				resType, kt.Rid)
+		}
+	}, nil
+}
+
 func (svc *lbSvc) checkResFlowRel(kt *kit.Kit, resID string, resType enumor.CloudResourceType) (
 	*corelb.BaseResFlowLock, error) {
 
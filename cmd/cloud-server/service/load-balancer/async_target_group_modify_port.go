@@ -157,8 +157,15 @@ func (svc *lbSvc) batchUpdateTargetPortDb(kt *kit.Kit, req *cslb.TCloudBatchModi
 func (svc *lbSvc) buildModifyTCloudTargetTasksPort(kt *kit.Kit, req *cslb.TCloudBatchModifyTargetPortReq, lbID, tgID,
 	accountID string) (interface{}, error) {
 
+	// 加锁，避免并发请求同时通过预检测
+	unlock, err := svc.lockResForFlow(kt, lbID, enumor.LoadBalancerCloudResType)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	// 预检测
-	_, err := svc.checkResFlowRel(kt, lbID, enumor.LoadBalancerCloudResType)
+	_, err = svc.checkResFlowRel(kt, lbID, enumor.LoadBalancerCloudResType)
 	if err != nil {
 		return nil, err
 	}
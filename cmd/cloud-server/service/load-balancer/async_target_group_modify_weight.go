@@ -157,8 +157,15 @@ func (svc *lbSvc) batchUpdateTargetWeightDb(kt *kit.Kit, req *cslb.TCloudBatchMo
 func (svc *lbSvc) buildModifyTCloudTargetTasksWeight(kt *kit.Kit, req *cslb.TCloudBatchModifyTargetWeightReq,
 	lbID, tgID, accountID string) (*core.FlowStateResult, error) {
 
+	// 加锁，避免并发请求同时通过预检测
+	unlock, err := svc.lockResForFlow(kt, lbID, enumor.LoadBalancerCloudResType)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	// 预检测
-	_, err := svc.checkResFlowRel(kt, lbID, enumor.LoadBalancerCloudResType)
+	_, err = svc.checkResFlowRel(kt, lbID, enumor.LoadBalancerCloudResType)
 	if err != nil {
 		logs.Errorf("check resource flow relation failed, err: %v, rid: %s", err, kt.Rid)
 		return nil, err
@@ -25,6 +25,7 @@ import (
 	"errors"
 	"fmt"
 
+	"hcm/cmd/cloud-server/logics/admission"
 	cloudserver "hcm/pkg/api/cloud-server"
 	cslb "hcm/pkg/api/cloud-server/load-balancer"
 	"hcm/pkg/api/core"
@@ -86,6 +87,23 @@ func (svc *lbSvc) batchCreateTCloudLB(kt *kit.Kit, rawReq json.RawMessage) (any,
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 	req.BkBizID = constant.UnassignedBiz
+
+	obj, err := admission.NewObject(req)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	admitReq := &admission.Request{
+		ResType:   enumor.LoadBalancerCloudResType,
+		Action:    enumor.AdmissionCreate,
+		Vendor:    enumor.TCloud,
+		BkBizID:   req.BkBizID,
+		AccountID: req.AccountID,
+		Objects:   []admission.Object{obj},
+	}
+	if err = svc.admission.Admit(kt, admitReq); err != nil {
+		return nil, err
+	}
+
 	return svc.client.HCService().TCloud.Clb.BatchCreate(kt, req)
 }
 
@@ -80,6 +80,11 @@ func (svc *lbSvc) deleteTargetGroup(cts *rest.Contexts, validHandler handler.Val
 		return nil, err
 	}
 
+	// 检查目标组是否仍绑定监听器
+	if err = svc.dependency.EnsureDeletable(cts.Kit, enumor.TargetGroupCloudResType, req.IDs); err != nil {
+		return nil, err
+	}
+
 	if err = svc.audit.ResDeleteAudit(cts.Kit, enumor.TargetGroupAuditResType, basicInfoReq.IDs); err != nil {
 		logs.Errorf("create operation audit target group failed, ids: %v, err: %v, rid: %s",
 			basicInfoReq.IDs, err, cts.Kit.Rid)
@@ -248,23 +253,8 @@ func (svc *lbSvc) loadBalancerDeleteCheck(kt *kit.Kit, lbIDs []string) error {
 		}
 	}
 
-	// 检查是否存在监听器
-	lblListReq := &core.ListReq{
-		Filter: tools.ContainersExpression("lb_id", lbIDs),
-		Page:   &core.BasePage{Count: false, Start: 0, Limit: 1},
-	}
-	listenerResp, err := svc.client.DataService().Global.LoadBalancer.ListListener(kt, lblListReq)
-	if err != nil {
-		logs.Errorf("fail to query listener for delete load balancers, err: %v, lb ids: %v, rid: %s",
-			err, lbIDs, kt.Rid)
-		return nil
-	}
-	if len(listenerResp.Details) != 0 {
-		lbl := listenerResp.Details[0]
-		return fmt.Errorf("load balancer(%s) with listener(%s:%s) can not be deleted",
-			lbl.CloudLbID, lbl.CloudID, lbl.Name)
-	}
-	return nil
+	// 检查是否存在监听器等依赖负载均衡的资源
+	return svc.dependency.EnsureDeletable(kt, enumor.LoadBalancerCloudResType, lbIDs)
 }
 
 func buildTCloudLBDeletionTasks(infoMap map[string]types.CloudResourceBasicInfo) (tasks []ts.CustomFlowTask) {
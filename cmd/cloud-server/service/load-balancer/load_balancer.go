@@ -23,13 +23,16 @@ package loadbalancer
 import (
 	"net/http"
 
+	"hcm/cmd/cloud-server/logics/admission"
 	"hcm/cmd/cloud-server/logics/audit"
 	"hcm/cmd/cloud-server/logics/cvm"
+	"hcm/cmd/cloud-server/logics/dependency"
 	"hcm/cmd/cloud-server/logics/disk"
 	"hcm/cmd/cloud-server/logics/eip"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/pkg/client"
 	"hcm/pkg/iam/auth"
+	"hcm/pkg/lock"
 	"hcm/pkg/rest"
 )
 
@@ -39,6 +42,9 @@ func InitService(c *capability.Capability) {
 		client:     c.ApiClient,
 		authorizer: c.Authorizer,
 		audit:      c.Audit,
+		locker:     c.Locker,
+		admission:  c.Logics.Admission,
+		dependency: c.Logics.Dependency,
 	}
 
 	h := rest.NewHandler()
@@ -54,6 +60,8 @@ func InitService(c *capability.Capability) {
 	h.Add("TCloudDescribeResources", http.MethodPost,
 		"/vendors/tcloud/load_balancers/resources/describe", svc.TCloudDescribeResources)
 	h.Add("BatchDeleteLoadBalancer", http.MethodDelete, "/load_balancers/batch", svc.BatchDeleteLoadBalancer)
+	h.Add("RecycleLoadBalancer", http.MethodPost, "/load_balancers/recycle", svc.RecycleLoadBalancer)
+	h.Add("RecoverLoadBalancer", http.MethodPost, "/load_balancers/recover", svc.RecoverLoadBalancer)
 	h.Add("ListListenerCountByLbIDs", http.MethodPost, "/load_balancers/listeners/count", svc.ListListenerCountByLbIDs)
 	h.Add("GetLoadBalancerLockStatus", http.MethodGet,
 		"/load_balancers/{id}/lock/status", svc.GetLoadBalancerLockStatus)
@@ -78,6 +86,8 @@ func bizService(h *rest.Handler, svc *lbSvc) {
 		"/load_balancers/with/delete_protection/list", svc.ListBizLoadBalancerWithDeleteProtect)
 	h.Add("GetBizLoadBalancer", http.MethodGet, "/load_balancers/{id}", svc.GetBizLoadBalancer)
 	h.Add("BatchDeleteBizLoadBalancer", http.MethodDelete, "/load_balancers/batch", svc.BatchDeleteBizLoadBalancer)
+	h.Add("RecycleBizLoadBalancer", http.MethodPost, "/load_balancers/recycle", svc.RecycleBizLoadBalancer)
+	h.Add("RecoverBizLoadBalancer", http.MethodPost, "/load_balancers/recover", svc.RecoverBizLoadBalancer)
 
 	h.Add("ListBizListener", http.MethodPost, "/load_balancers/{lb_id}/listeners/list", svc.ListBizListener)
 	h.Add("GetBizListener", http.MethodGet, "/listeners/{id}", svc.GetBizListener)
@@ -175,4 +185,7 @@ type lbSvc struct {
 	diskLgc    disk.Interface
 	cvmLgc     cvm.Interface
 	eipLgc     eip.Interface
+	locker     lock.Locker
+	admission  admission.Interface
+	dependency dependency.Interface
 }
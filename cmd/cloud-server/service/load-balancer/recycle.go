@@ -0,0 +1,213 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package loadbalancer
+
+import (
+	logicsrecycle "hcm/cmd/cloud-server/logics/recycle"
+	csrecycle "hcm/pkg/api/cloud-server/recycle"
+	"hcm/pkg/api/core"
+	corerr "hcm/pkg/api/core/recycle-record"
+	protoaudit "hcm/pkg/api/data-service/audit"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	dsrr "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// RecycleLoadBalancer 回收负载均衡
+func (svc *lbSvc) RecycleLoadBalancer(cts *rest.Contexts) (any, error) {
+	return svc.recycleLoadBalancer(cts, handler.ResOperateAuth)
+}
+
+// RecycleBizLoadBalancer 业务下回收负载均衡
+func (svc *lbSvc) RecycleBizLoadBalancer(cts *rest.Contexts) (any, error) {
+	return svc.recycleLoadBalancer(cts, handler.BizOperateAuth)
+}
+
+func (svc *lbSvc) recycleLoadBalancer(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (any, error) {
+	req := new(csrecycle.ResRecycleReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	infoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: enumor.LoadBalancerCloudResType,
+		IDs:          req.IDs,
+		Fields:       append(types.CommonBasicInfoFields, "region", "recycle_status"),
+	}
+	lbInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, infoReq)
+	if err != nil {
+		return nil, err
+	}
+	for _, lbID := range req.IDs {
+		info, exist := lbInfoMap[lbID]
+		if !exist {
+			return nil, errf.Newf(errf.InvalidParameter, "load balancer(%s) not found", lbID)
+		}
+		// 回收站到期销毁负载均衡目前仅支持腾讯云，其他云厂商的负载均衡进入回收站后将无法被销毁
+		if info.Vendor != enumor.TCloud {
+			return nil, errf.Newf(errf.InvalidParameter, "recycle %s load balancer(%s) is not supported, "+
+				"only supports tcloud", info.Vendor, lbID)
+		}
+	}
+
+	// 业务校验、鉴权
+	err = validHandler(cts, &handler.ValidWithAuthOption{
+		Authorizer: svc.authorizer,
+		ResType:    meta.LoadBalancer,
+		Action:     meta.Recycle,
+		BasicInfos: lbInfoMap,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 进入回收站的负载均衡到期后会被直接销毁，因此回收前需要满足删除条件
+	if err = svc.loadBalancerDeleteCheck(cts.Kit, req.IDs); err != nil {
+		return nil, err
+	}
+
+	auditInfos := make([]protoaudit.CloudResRecycleAuditInfo, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		auditInfos = append(auditInfos, protoaudit.CloudResRecycleAuditInfo{ResID: id})
+	}
+	auditReq := &protoaudit.CloudResourceRecycleAuditReq{
+		ResType: enumor.LoadBalancerAuditResType,
+		Action:  protoaudit.Recycle,
+		Infos:   auditInfos,
+	}
+	if err = svc.audit.ResRecycleAudit(cts.Kit, auditReq); err != nil {
+		logs.Errorf("create load balancer recycle audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	opt := &dsrr.BatchRecycleReq{
+		ResType:            enumor.LoadBalancerCloudResType,
+		DefaultRecycleTime: cc.CloudServer().Recycle.AutoDeleteTime,
+		Infos:              make([]dsrr.RecycleReq, 0, len(req.IDs)),
+	}
+	for _, id := range req.IDs {
+		opt.Infos = append(opt.Infos, dsrr.RecycleReq{ID: id, Detail: corerr.BaseRecycleDetail{}})
+	}
+	taskID, err := svc.client.DataService().Global.RecycleRecord.BatchRecycleCloudRes(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("fail to recycle load balancer, err: %v, ids: %v, rid: %s", err, req.IDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &csrecycle.RecycleResult{TaskID: taskID}, nil
+}
+
+// RecoverLoadBalancer 从回收站恢复负载均衡
+func (svc *lbSvc) RecoverLoadBalancer(cts *rest.Contexts) (any, error) {
+	return svc.recoverLoadBalancer(cts, handler.ResOperateAuth)
+}
+
+// RecoverBizLoadBalancer 业务下从回收站恢复负载均衡
+func (svc *lbSvc) RecoverBizLoadBalancer(cts *rest.Contexts) (any, error) {
+	return svc.recoverLoadBalancer(cts, handler.BizOperateAuth)
+}
+
+func (svc *lbSvc) recoverLoadBalancer(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (any, error) {
+	req := new(csrecycle.ResRecoverReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", req.RecordIDs),
+		Page:   &core.BasePage{Limit: constant.BatchOperationMaxLimit},
+	}
+	records, err := svc.client.DataService().Global.RecycleRecord.ListRecycleRecord(cts.Kit, listReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records.Details) != len(req.RecordIDs) {
+		return nil, errf.New(errf.InvalidParameter, "some record_ids are not in recycle bin")
+	}
+	if err = logicsrecycle.ValidateRecoverRecords(records, enumor.LoadBalancerCloudResType); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	lbIDs := make([]string, 0, len(records.Details))
+	auditInfos := make([]protoaudit.CloudResRecycleAuditInfo, 0, len(records.Details))
+	for _, record := range records.Details {
+		lbIDs = append(lbIDs, record.ResID)
+		auditInfos = append(auditInfos, protoaudit.CloudResRecycleAuditInfo{ResID: record.ResID, Data: record.Detail})
+	}
+
+	infoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: enumor.LoadBalancerCloudResType,
+		IDs:          lbIDs,
+		Fields:       append(types.CommonBasicInfoFields, "recycle_status"),
+	}
+	lbInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, infoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// 业务校验、鉴权
+	err = validHandler(cts, &handler.ValidWithAuthOption{
+		Authorizer: svc.authorizer,
+		ResType:    meta.LoadBalancer,
+		Action:     meta.Recover,
+		BasicInfos: lbInfoMap,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	auditReq := &protoaudit.CloudResourceRecycleAuditReq{
+		ResType: enumor.LoadBalancerAuditResType,
+		Action:  protoaudit.Recover,
+		Infos:   auditInfos,
+	}
+	if err = svc.audit.ResRecycleAudit(cts.Kit, auditReq); err != nil {
+		logs.Errorf("create load balancer recover audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	opt := &dsrr.BatchRecoverReq{
+		ResType:   enumor.LoadBalancerCloudResType,
+		RecordIDs: req.RecordIDs,
+	}
+	if err = svc.client.DataService().Global.RecycleRecord.BatchRecoverCloudResource(cts.Kit, opt); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
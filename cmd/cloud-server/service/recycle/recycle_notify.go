@@ -0,0 +1,222 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recycle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	corerp "hcm/pkg/api/core/recycle-policy"
+	recyclerecord "hcm/pkg/api/core/recycle-record"
+	protocloud "hcm/pkg/api/data-service/cloud"
+	dsrr "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/maps"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+)
+
+const (
+	notifyMailTitleTemplate   = "【HCM】回收站资源即将销毁：%s(%s)"
+	notifyMailContentTemplate = "资源类型：%s<br>资源ID：%s<br>云资源ID：%s<br>业务ID：%d<br>预计销毁时间：%s<br>" +
+		"如需保留该资源，请在销毁前从回收站中恢复。"
+)
+
+// notifyTiming 按回收策略在资源销毁前通知回收人和账号负责人，每条回收记录只通知一次
+func (r *recycle) notifyTiming() {
+	for {
+		time.Sleep(time.Minute * 10)
+
+		if !r.state.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		if err := r.notifyUpcomingDestruction(kt); err != nil {
+			logs.Errorf("notify upcoming destruction failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
+
+func (r *recycle) notifyUpcomingDestruction(kt *kit.Kit) error {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("enabled", true), tools.RuleGreaterThan("notify_before_hour", 0)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	policyRes, err := r.client.DataService().Global.RecyclePolicy.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list notify recycle policy failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	if len(policyRes.Details) == 0 {
+		return nil
+	}
+
+	policyMap := make(map[enumor.CloudResourceType][]corerp.RecyclePolicy)
+	maxNotifyHour := uint(0)
+	for _, one := range policyRes.Details {
+		policyMap[one.ResType] = append(policyMap[one.ResType], one)
+		if one.NotifyBeforeHour > maxNotifyHour {
+			maxNotifyHour = one.NotifyBeforeHour
+		}
+	}
+
+	now := times.ConvStdTimeNow()
+	expr, err := tools.And(
+		tools.RuleIn("res_type", maps.Keys(policyMap)),
+		tools.RuleEqual("status", enumor.WaitingRecycleRecordStatus),
+		tools.RuleNotEqual("recycle_type", enumor.RecycleTypeRelated),
+		tools.RuleLessThanEqual("recycled_at",
+			times.ConvStdTimeFormat(now.Add(time.Duration(maxNotifyHour)*time.Hour))),
+	)
+	if err != nil {
+		return err
+	}
+
+	page := core.NewDefaultBasePage()
+	for {
+		recordRes, err := r.client.DataService().Global.RecycleRecord.ListRecycleRecord(kt,
+			&core.ListReq{Filter: expr, Page: page})
+		if err != nil {
+			logs.Errorf("list upcoming destruction recycle record failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+
+		toNotify := make([]recyclerecord.RecycleRecord, 0)
+		for _, record := range recordRes.Details {
+			policy := corerp.PickPolicy(policyMap[record.ResType], record.BkBizID)
+			if policy == nil || policy.NotifyBeforeHour == 0 {
+				continue
+			}
+
+			if !needNotify(record, now, policy.NotifyBeforeHour) {
+				continue
+			}
+			toNotify = append(toNotify, record)
+		}
+
+		if len(toNotify) > 0 {
+			r.notifyRecords(kt, toNotify)
+		}
+
+		if uint(len(recordRes.Details)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+
+	return nil
+}
+
+// needNotify 判断回收记录是否已进入通知时间窗口且未通知过
+func needNotify(record recyclerecord.RecycleRecord, now time.Time, notifyBeforeHour uint) bool {
+	recycledAt, err := time.Parse(constant.TimeStdFormat, record.RecycledAt)
+	if err != nil {
+		logs.Errorf("parse recycle record(%s) recycled_at %s failed, err: %v", record.ID, record.RecycledAt, err)
+		return false
+	}
+
+	if recycledAt.Sub(now) > time.Duration(notifyBeforeHour)*time.Hour {
+		return false
+	}
+
+	notify := new(recyclerecord.RecycleNotifyDetail)
+	if record.Detail != nil {
+		detail, err := json.Marshal(record.Detail)
+		if err != nil {
+			return false
+		}
+		if err = json.Unmarshal(detail, notify); err != nil {
+			return false
+		}
+	}
+
+	return len(notify.NotifiedAt) == 0
+}
+
+func (r *recycle) notifyRecords(kt *kit.Kit, records []recyclerecord.RecycleRecord) {
+	accountIDs := slice.Unique(slice.Map(records,
+		func(r recyclerecord.RecycleRecord) string { return r.AccountID }))
+	managerMap, err := r.listAccountManagers(kt, accountIDs)
+	if err != nil {
+		return
+	}
+
+	notifiedAt := times.ConvStdTimeFormat(times.ConvStdTimeNow())
+	notified := make([]dsrr.UpdateReq, 0, len(records))
+	for _, record := range records {
+		receivers := slice.Unique(append([]string{record.Creator}, managerMap[record.AccountID]...))
+		mail := &cmsi.CmsiMail{
+			ReceiverUserName: strings.Join(receivers, ","),
+			Title:            fmt.Sprintf(notifyMailTitleTemplate, record.ResName, record.CloudResID),
+			Content: fmt.Sprintf(notifyMailContentTemplate, record.ResType, record.ResID, record.CloudResID,
+				record.BkBizID, record.RecycledAt),
+			BodyFormat: "Html",
+		}
+		if err = r.cmsiCli.SendMail(kt, mail); err != nil {
+			logs.Errorf("send recycle record(%s) destruction notify mail failed, err: %v, rid: %s", record.ID,
+				err, kt.Rid)
+			continue
+		}
+
+		notified = append(notified, dsrr.UpdateReq{
+			ID:     record.ID,
+			Detail: recyclerecord.RecycleNotifyDetail{NotifiedAt: notifiedAt},
+		})
+	}
+
+	for _, batch := range slice.Split(notified, constant.BatchOperationMaxLimit) {
+		err = r.client.DataService().Global.RecycleRecord.BatchUpdateRecycleRecord(kt, &dsrr.BatchUpdateReq{Data: batch})
+		if err != nil {
+			logs.Errorf("mark recycle record notified failed, err: %v, rid: %s", err, kt.Rid)
+		}
+	}
+}
+
+func (r *recycle) listAccountManagers(kt *kit.Kit, accountIDs []string) (map[string][]string, error) {
+	managerMap := make(map[string][]string, len(accountIDs))
+	for _, ids := range slice.Split(accountIDs, int(core.DefaultMaxPageLimit)) {
+		listReq := &protocloud.AccountListReq{
+			Filter: &filter.Expression{Op: filter.And, Rules: []filter.RuleFactory{tools.RuleIn("id", ids)}},
+			Page:   core.NewDefaultBasePage(),
+		}
+		accounts, err := r.client.DataService().Global.Account.List(kt.Ctx, kt.Header(), listReq)
+		if err != nil {
+			logs.Errorf("list account failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range accounts.Details {
+			managerMap[one.ID] = one.Managers
+		}
+	}
+
+	return managerMap, nil
+}
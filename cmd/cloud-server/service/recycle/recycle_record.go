@@ -20,12 +20,18 @@
 package recycle
 
 import (
+	"time"
+
 	proto "hcm/pkg/api/cloud-server/recycle"
 	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/times"
 )
 
 // ListRecycleRecord list recycle record.
@@ -67,3 +73,63 @@ func (svc *svc) listRecycleRecord(cts *rest.Contexts, authHandler handler.ListAu
 	}
 	return svc.client.DataService().Global.RecycleRecord.ListRecycleRecord(cts.Kit, listReq)
 }
+
+// ListUpcomingDestruction list recycle records which will be destroyed within hours.
+func (svc *svc) ListUpcomingDestruction(cts *rest.Contexts) (interface{}, error) {
+	return svc.listUpcomingDestruction(cts, handler.ListResourceRecycleAuthRes)
+}
+
+// ListBizUpcomingDestruction list biz recycle records which will be destroyed within hours.
+func (svc *svc) ListBizUpcomingDestruction(cts *rest.Contexts) (interface{}, error) {
+	return svc.listUpcomingDestruction(cts, handler.ListBizRecycleAuthRes)
+}
+
+func (svc *svc) listUpcomingDestruction(cts *rest.Contexts, authHandler handler.ListAuthResHandler) (interface{},
+	error) {
+
+	req := new(proto.ListUpcomingDestructionReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	// list authorized instances
+	expr, noPermFlag, err := authHandler(cts, &handler.ListAuthResOption{Authorizer: svc.authorizer,
+		ResType: meta.RecycleBin, Action: meta.Find, Filter: req.Filter})
+	if err != nil {
+		return nil, err
+	}
+
+	if noPermFlag {
+		return new(proto.RecycleRecordListResult), nil
+	}
+
+	deadline := times.ConvStdTimeNow().Add(time.Duration(req.WithinHour) * time.Hour)
+	rules := []filter.RuleFactory{
+		tools.RuleEqual("status", enumor.WaitingRecycleRecordStatus),
+		tools.RuleLessThanEqual("recycled_at", times.ConvStdTimeFormat(deadline)),
+		// 关联资源随主资源一起销毁，不单独展示
+		tools.RuleNotEqual("recycle_type", enumor.RecycleTypeRelated),
+	}
+	if expr != nil {
+		rules = append(rules, expr)
+	}
+	listFilter, err := tools.And(rules...)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if !req.Page.Count && len(req.Page.Sort) == 0 {
+		req.Page.Sort = "recycled_at"
+		req.Page.Order = core.Ascending
+	}
+
+	listReq := &core.ListReq{
+		Filter: listFilter,
+		Page:   req.Page,
+	}
+	return svc.client.DataService().Global.RecycleRecord.ListRecycleRecord(cts.Kit, listReq)
+}
@@ -0,0 +1,261 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recycle
+
+import (
+	"errors"
+	"fmt"
+
+	logicsrecycle "hcm/cmd/cloud-server/logics/recycle"
+	actionsnapshot "hcm/cmd/task-server/logics/action/disk-snapshot"
+	"hcm/pkg/api/core"
+	coreasync "hcm/pkg/api/core/async"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+)
+
+// errSnapshotNotReady 销毁前快照尚未创建完成，资源暂不销毁，下一轮回收时再检查
+var errSnapshotNotReady = errors.New("snapshot before destroy is not ready")
+
+// snapshotGateDecision 销毁前快照的检查结果
+type snapshotGateDecision int
+
+const (
+	// snapshotGateDestroy 快照均已创建完成，可以销毁资源
+	snapshotGateDestroy snapshotGateDecision = iota
+	// snapshotGateWait 快照任务流执行中，等待下一轮回收时再检查
+	snapshotGateWait
+	// snapshotGateSubmit 没有执行中的快照任务流，提交任务流创建快照
+	snapshotGateSubmit
+)
+
+// snapshotBeforeDestroy 回收策略开启销毁前快照时，为待销毁的硬盘（或主机挂载的硬盘）创建快照，快照创建完成前不销毁资源，
+// 快照创建失败则不销毁资源。快照创建任务流会等待快照创建完成，每轮回收只检查一次快照状态和任务流状态，快照未创建完成时
+// 返回errSnapshotNotReady。快照名称由回收记录和硬盘决定，按名称幂等创建，同一回收记录多次提交任务流也不会重复创建快照。
+func (r *recycle) snapshotBeforeDestroy(kt *kit.Kit, resType enumor.CloudResourceType, recordID string,
+	info *types.CloudResourceBasicInfo) error {
+
+	policy, err := logicsrecycle.GetPolicy(kt, r.client.DataService(), resType, info.BkBizID)
+	if err != nil {
+		logs.Errorf("get recycle policy failed, err: %v, res type: %s, biz: %d, rid: %s", err, resType,
+			info.BkBizID, kt.Rid)
+		return err
+	}
+
+	if policy == nil || !policy.SnapshotBeforeDestroy {
+		return nil
+	}
+
+	diskIDs := []string{info.ID}
+	if resType == enumor.CvmCloudResType {
+		diskIDs, err = r.listCvmDiskIDs(kt, info.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(diskIDs) == 0 {
+		return nil
+	}
+
+	names := make(map[string]string, len(diskIDs))
+	for _, diskID := range diskIDs {
+		names[diskID] = recycleSnapshotName(recordID, diskID)
+	}
+
+	snapshots, err := r.listRecycleSnapshots(kt, names)
+	if err != nil {
+		return err
+	}
+
+	memo := recycleSnapshotFlowMemo(recordID)
+	flow, err := r.getLatestSnapshotFlow(kt, memo)
+	if err != nil {
+		return err
+	}
+
+	decision, err := decideSnapshotGate(names, snapshots, flow)
+	if err != nil {
+		logs.Errorf("snapshot before recycle failed, err: %v, res: %s, record: %s, rid: %s", err, info.ID,
+			recordID, kt.Rid)
+		return err
+	}
+
+	switch decision {
+	case snapshotGateDestroy:
+		return nil
+	case snapshotGateWait:
+		return errSnapshotNotReady
+	}
+
+	targets := make([]actionsnapshot.SnapshotTarget, 0, len(diskIDs))
+	for _, diskID := range diskIDs {
+		targets = append(targets, actionsnapshot.SnapshotTarget{DiskID: diskID, Name: names[diskID]})
+	}
+	snapshotMemo := converter.ValToPtr(fmt.Sprintf("snapshot before recycle %s(%s)", resType, info.ID))
+
+	result, err := r.client.TaskServer().CreateCustomFlow(kt, &ts.AddCustomFlowReq{
+		Name:  enumor.FlowCreateDiskSnapshot,
+		Memo:  memo,
+		Tasks: actionsnapshot.BuildCreateDiskSnapshotTasks(info.Vendor, targets, snapshotMemo),
+	})
+	if err != nil {
+		logs.Errorf("create disk snapshot flow before recycle failed, err: %v, res: %s, rid: %s", err, info.ID,
+			kt.Rid)
+		return err
+	}
+
+	logs.Infof("submit disk snapshot flow before recycle, flow: %s, res: %s, record: %s, rid: %s", result.ID,
+		info.ID, recordID, kt.Rid)
+	return errSnapshotNotReady
+}
+
+// decideSnapshotGate 根据待销毁硬盘的快照状态以及回收记录最近一次的快照任务流，决定资源是否可以销毁，快照创建失败时返回错误
+func decideSnapshotGate(names map[string]string, snapshots []coresnapshot.DiskSnapshot,
+	flow *coreasync.AsyncFlow) (snapshotGateDecision, error) {
+
+	snapshotMap := make(map[string]coresnapshot.DiskSnapshot, len(snapshots))
+	for _, one := range snapshots {
+		if name, exist := names[one.DiskID]; exist && name == one.Name {
+			snapshotMap[one.DiskID] = one
+		}
+	}
+
+	allReady := true
+	for diskID, name := range names {
+		one, exist := snapshotMap[diskID]
+		if !exist {
+			allReady = false
+			continue
+		}
+
+		if one.IsFailed() {
+			return 0, fmt.Errorf("snapshot %s(%s) of disk %s is %s", name, one.ID, diskID, one.Status)
+		}
+
+		if !one.IsReady() {
+			allReady = false
+		}
+	}
+
+	if allReady {
+		return snapshotGateDestroy, nil
+	}
+
+	if flow == nil {
+		return snapshotGateSubmit, nil
+	}
+
+	switch flow.State {
+	case enumor.FlowSuccess:
+		// 任务流执行成功时快照均已创建完成，快照仍不可用说明快照在任务流结束后被删除，重新提交任务流创建快照
+		return snapshotGateSubmit, nil
+
+	case enumor.FlowFailed, enumor.FlowCancel:
+		reason := ""
+		if flow.Reason != nil {
+			reason = flow.Reason.Message
+		}
+		return 0, fmt.Errorf("snapshot flow: %s before destroy is %s, reason: %s", flow.ID, flow.State, reason)
+
+	default:
+		return snapshotGateWait, nil
+	}
+}
+
+// recycleSnapshotName 回收记录销毁前快照的名称，符合各云厂商快照名称规范
+func recycleSnapshotName(recordID, diskID string) string {
+	return fmt.Sprintf("recycle-%s-%s", recordID, diskID)
+}
+
+// recycleSnapshotFlowMemo 回收记录销毁前快照任务流的备注，用于查找回收记录已提交的任务流
+func recycleSnapshotFlowMemo(recordID string) string {
+	return fmt.Sprintf("snapshot before recycle, record: %s", recordID)
+}
+
+// listRecycleSnapshots 查询待销毁硬盘已创建的销毁前快照
+func (r *recycle) listRecycleSnapshots(kt *kit.Kit, names map[string]string) ([]coresnapshot.DiskSnapshot,
+	error) {
+
+	diskIDs := make([]string, 0, len(names))
+	snapshotNames := make([]string, 0, len(names))
+	for diskID, name := range names {
+		diskIDs = append(diskIDs, diskID)
+		snapshotNames = append(snapshotNames, name)
+	}
+
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleIn("disk_id", diskIDs), tools.RuleIn("name", snapshotNames)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := r.client.DataService().Global.DiskSnapshot.List(kt, req)
+	if err != nil {
+		logs.Errorf("list recycle disk snapshot failed, err: %v, names: %v, rid: %s", err, snapshotNames, kt.Rid)
+		return nil, err
+	}
+
+	return result.Details, nil
+}
+
+// getLatestSnapshotFlow 查询回收记录最近一次提交的快照任务流，不存在时返回nil
+func (r *recycle) getLatestSnapshotFlow(kt *kit.Kit, memo string) (*coreasync.AsyncFlow, error) {
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("name", enumor.FlowCreateDiskSnapshot),
+			tools.RuleEqual("memo", memo)),
+		Page: &core.BasePage{Start: 0, Limit: 1, Sort: "created_at", Order: core.Descending},
+	}
+	result, err := r.client.TaskServer().ListFlow(kt, req)
+	if err != nil {
+		logs.Errorf("list recycle disk snapshot flow failed, err: %v, memo: %s, rid: %s", err, memo, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, nil
+	}
+
+	return &result.Details[0], nil
+}
+
+// listCvmDiskIDs 查询主机挂载的硬盘ID
+func (r *recycle) listCvmDiskIDs(kt *kit.Kit, cvmID string) ([]string, error) {
+	req := &core.ListReq{
+		Filter: tools.EqualExpression("cvm_id", cvmID),
+		Page:   core.NewDefaultBasePage(),
+	}
+	rels, err := r.client.DataService().Global.ListDiskCvmRel(kt, req)
+	if err != nil {
+		logs.Errorf("list disk cvm rel failed, err: %v, cvm: %s, rid: %s", err, cvmID, kt.Rid)
+		return nil, err
+	}
+
+	diskIDs := make([]string, 0, len(rels.Details))
+	for _, rel := range rels.Details {
+		diskIDs = append(diskIDs, rel.DiskID)
+	}
+
+	return diskIDs, nil
+}
@@ -0,0 +1,108 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recycle
+
+import (
+	"testing"
+
+	coreasync "hcm/pkg/api/core/async"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestDecideSnapshotGate(t *testing.T) {
+	names := map[string]string{
+		"disk-1": recycleSnapshotName("record-1", "disk-1"),
+		"disk-2": recycleSnapshotName("record-1", "disk-2"),
+	}
+	snapshot := func(diskID, status string) coresnapshot.DiskSnapshot {
+		return coresnapshot.DiskSnapshot{Vendor: enumor.TCloud, DiskID: diskID, Name: names[diskID], Status: status}
+	}
+	flow := func(state enumor.FlowState) *coreasync.AsyncFlow {
+		return &coreasync.AsyncFlow{ID: "flow-1", State: state}
+	}
+
+	cases := []struct {
+		name      string
+		snapshots []coresnapshot.DiskSnapshot
+		flow      *coreasync.AsyncFlow
+		want      snapshotGateDecision
+		wantErr   bool
+	}{
+		{
+			name:      "all snapshots ready",
+			snapshots: []coresnapshot.DiskSnapshot{snapshot("disk-1", "NORMAL"), snapshot("disk-2", "NORMAL")},
+			want:      snapshotGateDestroy,
+		},
+		{
+			name: "no snapshot and no flow",
+			want: snapshotGateSubmit,
+		},
+		{
+			name: "snapshot of other record is ignored",
+			snapshots: []coresnapshot.DiskSnapshot{snapshot("disk-1", "NORMAL"),
+				{Vendor: enumor.TCloud, DiskID: "disk-2", Name: "other", Status: "NORMAL"}},
+			want: snapshotGateSubmit,
+		},
+		{
+			name:      "flow running",
+			snapshots: []coresnapshot.DiskSnapshot{snapshot("disk-1", "CREATING")},
+			flow:      flow(enumor.FlowRunning),
+			want:      snapshotGateWait,
+		},
+		{
+			name:      "flow succeeded but snapshot deleted",
+			snapshots: []coresnapshot.DiskSnapshot{snapshot("disk-2", "NORMAL")},
+			flow:      flow(enumor.FlowSuccess),
+			want:      snapshotGateSubmit,
+		},
+		{
+			name:    "flow failed",
+			flow:    flow(enumor.FlowFailed),
+			wantErr: true,
+		},
+		{
+			name:    "flow canceled",
+			flow:    flow(enumor.FlowCancel),
+			wantErr: true,
+		},
+		{
+			name: "snapshot failed",
+			snapshots: []coresnapshot.DiskSnapshot{
+				{Vendor: enumor.Aws, DiskID: "disk-1", Name: names["disk-1"], Status: "error"},
+				{Vendor: enumor.Aws, DiskID: "disk-2", Name: names["disk-2"], Status: "completed"},
+			},
+			flow:    flow(enumor.FlowSuccess),
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		got, err := decideSnapshotGate(names, c.snapshots, c.flow)
+		if (err != nil) != c.wantErr {
+			t.Errorf("case %s: expect error %v, but got: %v", c.name, c.wantErr, err)
+			continue
+		}
+
+		if !c.wantErr && got != c.want {
+			t.Errorf("case %s: expect decision %d, but got: %d", c.name, c.want, got)
+		}
+	}
+}
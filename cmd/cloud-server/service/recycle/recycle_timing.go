@@ -20,6 +20,7 @@
 package recycle
 
 import (
+	"errors"
 	"time"
 
 	"hcm/cmd/cloud-server/logics"
@@ -27,6 +28,7 @@ import (
 	"hcm/pkg/api/core"
 	recyclerecord "hcm/pkg/api/core/recycle-record"
 	dataproto "hcm/pkg/api/data-service/cloud"
+	hcproto "hcm/pkg/api/hc-service/load-balancer"
 	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	"hcm/pkg/criteria/enumor"
@@ -34,9 +36,11 @@ import (
 	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/kit"
+	"hcm/pkg/lock"
 	"hcm/pkg/logs"
 	"hcm/pkg/runtime/filter"
 	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
 	"hcm/pkg/thirdparty/esb"
 	"hcm/pkg/tools/retry"
 	"hcm/pkg/tools/slice"
@@ -44,24 +48,33 @@ import (
 )
 
 type recycle struct {
-	client *client.ClientSet
-	logics *logics.Logics
-	state  serviced.State
+	client  *client.ClientSet
+	logics  *logics.Logics
+	state   serviced.State
+	locker  lock.Locker
+	cmsiCli cmsi.Client
 }
 
 // RecycleTiming timing recycle all resource.
-func RecycleTiming(c *client.ClientSet, state serviced.State, conf cc.Recycle, esbClient esb.Client) {
+func RecycleTiming(c *client.ClientSet, state serviced.State, conf cc.Recycle, esbClient esb.Client,
+	locker lock.Locker, cmsiCli cmsi.Client) {
+
 	r := &recycle{
-		client: c,
-		state:  state,
-		logics: logics.NewLogics(c, esbClient),
+		client:  c,
+		state:   state,
+		logics:  logics.NewLogics(c, esbClient),
+		locker:  locker,
+		cmsiCli: cmsiCli,
 	}
 
 	go r.recycleTiming(enumor.DiskCloudResType, r.recycleDiskWorker, conf)
 	go r.recycleTiming(enumor.CvmCloudResType, r.recycleCvmWorker, conf)
+	go r.recycleTiming(enumor.EipCloudResType, r.recycleEipWorker, conf)
+	go r.recycleTiming(enumor.LoadBalancerCloudResType, r.recycleLoadBalancerWorker, conf)
+	go r.notifyTiming()
 }
 
-type recycleWorker func(kt *kit.Kit, info *types.CloudResourceBasicInfo) error
+type recycleWorker func(kt *kit.Kit, recordID string, info *types.CloudResourceBasicInfo) error
 
 func (r *recycle) recycleTiming(resType enumor.CloudResourceType, worker recycleWorker, conf cc.Recycle) {
 	for {
@@ -130,23 +143,33 @@ func (r *recycle) recycleTiming(resType enumor.CloudResourceType, worker recycle
 		}
 
 		// recycle resources one by one
+		deferred := 0
 		for _, record := range recordRes.Details {
 			if !r.state.IsMaster() {
 				logs.Infof("recycle %s res(id: %s), but is not master, skip, rid: %s", resType, record.ResID, kt.Rid)
 				time.Sleep(time.Minute)
 				break
 			}
-			r.execWorker(kt, worker, record, basicInfoMap)
+			if r.execWorker(kt, worker, record, basicInfoMap) {
+				deferred++
+			}
 		}
 
-		logs.Infof("finished recycle %s, count: %d, rid: %s", resType, len(recordRes.Details), kt.Rid)
+		logs.Infof("finished recycle %s, count: %d, deferred: %d, rid: %s", resType, len(recordRes.Details),
+			deferred, kt.Rid)
+
+		// 有资源等待销毁前快照创建完成时，这些资源会在下一轮被再次查询到，等待一段时间后再检查，避免频繁查询
+		if deferred > 0 {
+			time.Sleep(time.Minute)
+		}
 	}
 }
 
 const maxRetryCount = 3
 
+// execWorker 回收单个资源，返回资源是否因销毁前快照未创建完成而推迟到下一轮回收
 func (r *recycle) execWorker(kt *kit.Kit, worker recycleWorker, record recyclerecord.RecycleRecord,
-	basicInfoMap map[string]types.CloudResourceBasicInfo) {
+	basicInfoMap map[string]types.CloudResourceBasicInfo) bool {
 
 	basicInfo, exists := basicInfoMap[record.ResID]
 	if !exists {
@@ -154,30 +177,62 @@ func (r *recycle) execWorker(kt *kit.Kit, worker recycleWorker, record recyclere
 			kt.Rid)
 		logicsrecycle.MarkRecordFailed(kt, r.client.DataService(),
 			errf.New(errf.RecordNotFound, "Recourse Not Found"), []string{record.ID})
-		return
+		return false
 	}
 
+	// 主节点切换期间新旧主节点可能同时回收同一个资源，加锁保证同一资源同时只有一个回收任务
+	lease, err := r.locker.TryLock(kt, lock.Key(string(cc.CloudServerName), "recycle", string(record.ResType),
+		record.ResID), &lock.Option{TTL: time.Minute, Owner: kt.Rid, AutoRenew: true})
+	if err != nil {
+		logs.Errorf("lock recycle %s res(id: %s) failed, skip, err: %v, rid: %s", record.ResType, record.ResID,
+			err, kt.Rid)
+		return false
+	}
+	defer func() {
+		if err := r.locker.Unlock(kt, lease); err != nil {
+			logs.Errorf("unlock recycle %s res(id: %s) failed, err: %v, rid: %s", record.ResType, record.ResID,
+				err, kt.Rid)
+		}
+	}()
+
 	rty := retry.NewRetryPolicy(maxRetryCount, [2]uint{500, 15000})
-	var err error
 
 	// 类型为cvm且在业务下回收的，需要检查是否在cmdb 待回收模块中
 	// 因为cvm记录中的BkBizID已经在加入业务的时候被清掉了，所以要以recycle_record中的为准
 	basicInfo.BkBizID = record.BkBizID
+	deferred := false
 	err = rty.BaseExec(kt, func() error {
-		return worker(kt, &basicInfo)
+		err := worker(kt, record.ID, &basicInfo)
+		if errors.Is(err, errSnapshotNotReady) {
+			deferred = true
+			return nil
+		}
+		return err
 	})
 	if err != nil {
 		// Failed after retry
 		logicsrecycle.MarkRecordFailed(kt, r.client.DataService(), err, []string{record.ID})
-		return
+		return false
+	}
+
+	if deferred {
+		logs.Infof("[%s]recycle res(id: %s) is deferred until snapshot before destroy is ready, rid: %s",
+			record.ResType, record.ResID, kt.Rid)
+		return true
 	}
+
 	// Success
 	logs.V(3).Infof("[%s]recycle res(id: %s) success,  rid: %s", record.ResType, record.ID, kt.Rid)
 
 	logicsrecycle.MarkRecordSuccess(kt, r.client.DataService(), []string{record.ID})
+	return false
 }
 
-func (r *recycle) recycleDiskWorker(kt *kit.Kit, info *types.CloudResourceBasicInfo) error {
+func (r *recycle) recycleDiskWorker(kt *kit.Kit, recordID string, info *types.CloudResourceBasicInfo) error {
+	if err := r.snapshotBeforeDestroy(kt, enumor.DiskCloudResType, recordID, info); err != nil {
+		return err
+	}
+
 	res, err := r.logics.Disk.DeleteRecycledDisk(kt, map[string]types.CloudResourceBasicInfo{info.ID: *info})
 	if err != nil {
 		logs.Errorf("delete disk failed, err: %v, res: %+v, disk: %s, rid: %s", err, res, info.ID, kt.Rid)
@@ -186,7 +241,11 @@ func (r *recycle) recycleDiskWorker(kt *kit.Kit, info *types.CloudResourceBasicI
 	return nil
 }
 
-func (r *recycle) recycleCvmWorker(kt *kit.Kit, info *types.CloudResourceBasicInfo) error {
+func (r *recycle) recycleCvmWorker(kt *kit.Kit, recordID string, info *types.CloudResourceBasicInfo) error {
+	if err := r.snapshotBeforeDestroy(kt, enumor.CvmCloudResType, recordID, info); err != nil {
+		return err
+	}
+
 	// 实际销毁CVM
 	res, err := r.logics.Cvm.DestroyRecycledCvm(kt, map[string]types.CloudResourceBasicInfo{info.ID: *info}, nil)
 	if err != nil {
@@ -195,3 +254,28 @@ func (r *recycle) recycleCvmWorker(kt *kit.Kit, info *types.CloudResourceBasicIn
 	}
 	return nil
 }
+
+func (r *recycle) recycleEipWorker(kt *kit.Kit, _ string, info *types.CloudResourceBasicInfo) error {
+	if err := r.logics.Eip.DeleteEip(kt, info.Vendor, info.ID); err != nil {
+		logs.Errorf("delete eip failed, err: %v, eip: %s, rid: %s", err, info.ID, kt.Rid)
+		return err
+	}
+	return nil
+}
+
+func (r *recycle) recycleLoadBalancerWorker(kt *kit.Kit, _ string, info *types.CloudResourceBasicInfo) error {
+	if info.Vendor != enumor.TCloud {
+		return errf.Newf(errf.InvalidParameter, "recycle %s load balancer is not supported", info.Vendor)
+	}
+
+	req := &hcproto.TCloudBatchDeleteLoadbalancerReq{
+		AccountID: info.AccountID,
+		Region:    info.Region,
+		IDs:       []string{info.ID},
+	}
+	if err := r.client.HCService().TCloud.Clb.BatchDeleteLoadBalancer(kt, req); err != nil {
+		logs.Errorf("delete load balancer failed, err: %v, lb: %s, rid: %s", err, info.ID, kt.Rid)
+		return err
+	}
+	return nil
+}
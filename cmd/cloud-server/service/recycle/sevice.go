@@ -39,6 +39,17 @@ func InitService(c *capability.Capability) {
 
 	h.Add("ListRecycleRecord", http.MethodPost, "/recycle_records/list", svc.ListRecycleRecord)
 	h.Add("ListBizRecycleRecord", http.MethodPost, "/bizs/{bk_biz_id}/recycle_records/list", svc.ListBizRecycleRecord)
+	h.Add("ListUpcomingDestruction", http.MethodPost, "/recycle_records/upcoming/list",
+		svc.ListUpcomingDestruction)
+	h.Add("ListBizUpcomingDestruction", http.MethodPost, "/bizs/{bk_biz_id}/recycle_records/upcoming/list",
+		svc.ListBizUpcomingDestruction)
+
+	h.Add("CreateRecyclePolicy", http.MethodPost, "/recycle_policies/create", svc.CreateRecyclePolicy)
+	h.Add("UpdateRecyclePolicy", http.MethodPatch, "/recycle_policies/{id}", svc.UpdateRecyclePolicy)
+	h.Add("ListRecyclePolicy", http.MethodPost, "/recycle_policies/list", svc.ListRecyclePolicy)
+	h.Add("ListBizRecyclePolicy", http.MethodPost, "/bizs/{bk_biz_id}/recycle_policies/list",
+		svc.ListBizRecyclePolicy)
+	h.Add("BatchDeleteRecyclePolicy", http.MethodDelete, "/recycle_policies/batch", svc.BatchDeleteRecyclePolicy)
 
 	h.Load(c.WebService)
 }
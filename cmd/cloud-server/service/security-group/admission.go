@@ -0,0 +1,214 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	"fmt"
+	"strings"
+
+	"hcm/cmd/cloud-server/logics/admission"
+	"hcm/pkg/api/core"
+	corecloud "hcm/pkg/api/core/cloud"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// admitCreateSGRules 使用准入策略校验安全组规则的创建请求。除各云厂商规则的请求字段外，
+// 还为每条规则提供统一的 type(ingress/egress)、port、cidr 字段，便于编写跨云厂商的策略。
+func admitCreateSGRules[T any](kt *kit.Kit, adm admission.Interface, sgBaseInfo *types.CloudResourceBasicInfo,
+	egress, ingress []T) error {
+
+	objects, err := admission.NewObjects(egress, map[string]interface{}{"type": string(enumor.Egress)})
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	ingressObjects, err := admission.NewObjects(ingress, map[string]interface{}{"type": string(enumor.Ingress)})
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	objects = append(objects, ingressObjects...)
+
+	return admitSGRuleObjects(kt, adm, sgBaseInfo, enumor.AdmissionCreate, objects)
+}
+
+// admitUpdateSGRule 使用准入策略校验安全组规则的修改请求。修改请求只包含部分字段，
+// 因此以db中已有的规则为基础合并请求中的非空字段后再校验，保证策略看到的是修改后完整的规则。
+func (svc *securityGroupSvc) admitUpdateSGRule(kt *kit.Kit, sgBaseInfo *types.CloudResourceBasicInfo, id string,
+	update interface{}) error {
+
+	stored, err := svc.getSGRule(kt, sgBaseInfo, id)
+	if err != nil {
+		return err
+	}
+
+	obj, err := mergeSGRuleUpdate(stored, update)
+	if err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return admitSGRuleObjects(kt, svc.admission, sgBaseInfo, enumor.AdmissionUpdate, []admission.Object{obj})
+}
+
+// mergeSGRuleUpdate 将修改请求中的非空字段覆盖到已有规则上
+func mergeSGRuleUpdate(stored, update interface{}) (admission.Object, error) {
+	obj, err := admission.NewObject(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	updateObj, err := admission.NewObject(update)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range updateObj {
+		if v != nil {
+			obj[k] = v
+		}
+	}
+
+	return obj, nil
+}
+
+// getSGRule 查询安全组下指定的规则
+func (svc *securityGroupSvc) getSGRule(kt *kit.Kit, sgBaseInfo *types.CloudResourceBasicInfo, id string) (
+	interface{}, error) {
+
+	ruleFilter := tools.ExpressionAnd(tools.RuleEqual("id", id), tools.RuleEqual("security_group_id", sgBaseInfo.ID))
+	page := &core.BasePage{Limit: 1}
+
+	var rules []interface{}
+	switch sgBaseInfo.Vendor {
+	case enumor.TCloud:
+		resp, err := svc.client.DataService().TCloud.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+			&dataproto.TCloudSGRuleListReq{Filter: ruleFilter, Page: page}, sgBaseInfo.ID)
+		if err != nil {
+			logs.Errorf("list tcloud security group rule failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return nil, err
+		}
+		rules = slice.Map(resp.Details, func(one corecloud.TCloudSecurityGroupRule) interface{} { return one })
+
+	case enumor.Aws:
+		resp, err := svc.client.DataService().Aws.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+			&dataproto.AwsSGRuleListReq{Filter: ruleFilter, Page: page}, sgBaseInfo.ID)
+		if err != nil {
+			logs.Errorf("list aws security group rule failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return nil, err
+		}
+		rules = slice.Map(resp.Details, func(one corecloud.AwsSecurityGroupRule) interface{} { return one })
+
+	case enumor.Azure:
+		resp, err := svc.client.DataService().Azure.SecurityGroup.ListSecurityGroupRule(kt.Ctx, kt.Header(),
+			&dataproto.AzureSGRuleListReq{Filter: ruleFilter, Page: page}, sgBaseInfo.ID)
+		if err != nil {
+			logs.Errorf("list azure security group rule failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return nil, err
+		}
+		rules = slice.Map(resp.Details, func(one corecloud.AzureSecurityGroupRule) interface{} { return one })
+
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor: %s not support", sgBaseInfo.Vendor)
+	}
+
+	if len(rules) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "security group rule: %s not found", id)
+	}
+
+	return rules[0], nil
+}
+
+func admitSGRuleObjects(kt *kit.Kit, adm admission.Interface, sgBaseInfo *types.CloudResourceBasicInfo,
+	action enumor.AdmissionAction, objects []admission.Object) error {
+
+	for _, obj := range objects {
+		fillSGRuleAdmissionFields(obj)
+	}
+
+	return adm.Admit(kt, &admission.Request{
+		ResType:   enumor.SecurityGroupRuleCloudResType,
+		Action:    action,
+		Vendor:    sgBaseInfo.Vendor,
+		BkBizID:   sgBaseInfo.BkBizID,
+		AccountID: sgBaseInfo.AccountID,
+		Objects:   objects,
+	})
+}
+
+// fillSGRuleAdmissionFields fill port and cidr fields of security group rule by vendor specific fields.
+func fillSGRuleAdmissionFields(obj admission.Object) {
+	if obj["port"] == nil {
+		switch {
+		// aws 使用 from_port、to_port 表示端口范围，-1 表示所有端口
+		case obj["from_port"] != nil:
+			from, _ := obj["from_port"].(float64)
+			to, _ := obj["to_port"].(float64)
+			if from == -1 {
+				obj["port"] = "ALL"
+			} else {
+				obj["port"] = fmt.Sprintf("%d-%d", int64(from), int64(to))
+			}
+
+		// azure 使用 destination_port_range(s) 表示端口
+		case obj["destination_port_range"] != nil:
+			obj["port"] = obj["destination_port_range"]
+		case obj["destination_port_ranges"] != nil:
+			ports := make([]string, 0)
+			for _, one := range toList(obj["destination_port_ranges"]) {
+				if port, ok := one.(string); ok {
+					ports = append(ports, port)
+				}
+			}
+			obj["port"] = strings.Join(ports, ",")
+		}
+	}
+
+	cidrs := make([]interface{}, 0)
+	for _, field := range []string{"ipv4_cidr", "ipv6_cidr", "remote_ip_prefix"} {
+		if obj[field] != nil {
+			cidrs = append(cidrs, obj[field])
+		}
+	}
+
+	// azure 入站规则的对端为源地址，出站规则的对端为目的地址
+	prefix := "source"
+	if obj["type"] == string(enumor.Egress) {
+		prefix = "destination"
+	}
+	if obj[prefix+"_address_prefix"] != nil {
+		cidrs = append(cidrs, obj[prefix+"_address_prefix"])
+	}
+	cidrs = append(cidrs, toList(obj[prefix+"_address_prefixes"])...)
+
+	if len(cidrs) != 0 {
+		obj["cidr"] = cidrs
+	}
+}
+
+func toList(value interface{}) []interface{} {
+	list, _ := value.([]interface{})
+	return list
+}
@@ -104,6 +104,11 @@ func (svc *securityGroupSvc) createTCloudSGRule(cts *rest.Contexts, sgBaseInfo *
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := admitCreateSGRules(cts.Kit, svc.admission, sgBaseInfo, req.EgressRuleSet,
+		req.IngressRuleSet); err != nil {
+		return nil, err
+	}
+
 	createReq := &hcproto.TCloudSGRuleCreateReq{
 		AccountID: sgBaseInfo.AccountID,
 	}
@@ -166,6 +171,11 @@ func (svc *securityGroupSvc) createAwsSGRule(cts *rest.Contexts, sgBaseInfo *typ
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := admitCreateSGRules(cts.Kit, svc.admission, sgBaseInfo, req.EgressRuleSet,
+		req.IngressRuleSet); err != nil {
+		return nil, err
+	}
+
 	createReq := &hcproto.AwsSGRuleCreateReq{
 		AccountID: sgBaseInfo.AccountID,
 	}
@@ -220,6 +230,11 @@ func (svc *securityGroupSvc) createHuaWeiSGRule(cts *rest.Contexts, sgBaseInfo *
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := admitCreateSGRules(cts.Kit, svc.admission, sgBaseInfo, req.EgressRuleSet,
+		req.IngressRuleSet); err != nil {
+		return nil, err
+	}
+
 	getTaskID := counter.NewNumStringCounter(1, 10)
 	tasks := slice.Map(req.EgressRuleSet, func(r proto.HuaWeiSecurityGroupRule) ts.CustomFlowTask {
 		return ts.CustomFlowTask{
@@ -302,6 +317,11 @@ func (svc *securityGroupSvc) createAzureSGRule(cts *rest.Contexts, sgBaseInfo *t
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := admitCreateSGRules(cts.Kit, svc.admission, sgBaseInfo, req.EgressRuleSet,
+		req.IngressRuleSet); err != nil {
+		return nil, err
+	}
+
 	createReq := &hcproto.AzureSGRuleCreateReq{
 		AccountID: sgBaseInfo.AccountID,
 	}
@@ -73,6 +73,11 @@ func (svc *securityGroupSvc) batchDeleteSecurityGroup(cts *rest.Contexts, validH
 		return nil, err
 	}
 
+	// check if there are resources still bound to the security groups
+	if err = svc.dependency.EnsureDeletable(cts.Kit, enumor.SecurityGroupCloudResType, req.IDs); err != nil {
+		return nil, err
+	}
+
 	// create delete audit.
 	if err := svc.audit.ResDeleteAudit(cts.Kit, enumor.SecurityGroupAuditResType, req.IDs); err != nil {
 		logs.Errorf("create delete audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
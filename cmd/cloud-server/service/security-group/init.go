@@ -22,7 +22,10 @@ package securitygroup
 import (
 	"net/http"
 
+	"hcm/cmd/cloud-server/logics/admission"
 	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/logics/dependency"
+	sglogics "hcm/cmd/cloud-server/logics/security-group"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/pkg/client"
 	"hcm/pkg/iam/auth"
@@ -35,6 +38,9 @@ func InitSecurityGroupService(c *capability.Capability) {
 		client:     c.ApiClient,
 		authorizer: c.Authorizer,
 		audit:      c.Audit,
+		admission:  c.Logics.Admission,
+		sgLogic:    c.Logics.SecurityGroup,
+		dependency: c.Logics.Dependency,
 	}
 
 	h := rest.NewHandler()
@@ -70,6 +76,8 @@ func InitSecurityGroupService(c *capability.Capability) {
 		"/security_group/{id}/common/list", svc.ListResourceIdBySecurityGroup)
 	h.Add("ListCvmIdBySecurityGroup", http.MethodPost,
 		"/security_group/{id}/cvm/list", svc.ListCvmIdBySecurityGroup)
+	h.Add("AnalyzeSGRisk", http.MethodPost, "/security_groups/risks/analyze", svc.AnalyzeSGRisk)
+	h.Add("ListCvmSGExposure", http.MethodPost, "/security_groups/cvms/exposure/list", svc.ListCvmSGExposure)
 
 	bizService(h, svc)
 	initSecurityGroupServiceHooks(svc, h)
@@ -120,10 +128,17 @@ func bizService(h *rest.Handler, svc *securityGroupSvc) {
 		"/bizs/{bk_biz_id}/security_group/{id}/common/list", svc.ListBizResourceIDBySecurityGroup)
 	h.Add("ListBizCvmIdBySecurityGroup", http.MethodPost,
 		"/bizs/{bk_biz_id}/security_group/{id}/cvm/list", svc.ListBizCvmIdBySecurityGroup)
+	h.Add("AnalyzeBizSGRisk", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/risks/analyze",
+		svc.AnalyzeBizSGRisk)
+	h.Add("ListBizCvmSGExposure", http.MethodPost, "/bizs/{bk_biz_id}/security_groups/cvms/exposure/list",
+		svc.ListBizCvmSGExposure)
 }
 
 type securityGroupSvc struct {
 	client     *client.ClientSet
 	authorizer auth.Authorizer
 	audit      audit.Interface
+	admission  admission.Interface
+	sgLogic    sglogics.Interface
+	dependency dependency.Interface
 }
@@ -0,0 +1,169 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package securitygroup
+
+import (
+	proto "hcm/pkg/api/cloud-server"
+	"hcm/pkg/api/core"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// AnalyzeSGRisk analyze security group rule risks.
+func (svc *securityGroupSvc) AnalyzeSGRisk(cts *rest.Contexts) (interface{}, error) {
+	return svc.analyzeSGRisk(cts, handler.ResOperateAuth)
+}
+
+// AnalyzeBizSGRisk analyze biz security group rule risks.
+func (svc *securityGroupSvc) AnalyzeBizSGRisk(cts *rest.Contexts) (interface{}, error) {
+	return svc.analyzeSGRisk(cts, handler.BizOperateAuth)
+}
+
+func (svc *securityGroupSvc) analyzeSGRisk(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(proto.SGRiskAnalyzeReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	req.SecurityGroupIDs = slice.Unique(req.SecurityGroupIDs)
+
+	basicInfos, err := svc.listBasicInfo(cts.Kit, enumor.SecurityGroupCloudResType, req.SecurityGroupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.SecurityGroup,
+		Action: meta.Find, BasicInfos: basicInfos})
+	if err != nil {
+		return nil, err
+	}
+
+	listReq := &dataproto.SecurityGroupListReq{
+		Filter: tools.ContainersExpression("id", req.SecurityGroupIDs),
+		Page:   core.NewDefaultBasePage(),
+	}
+	sgResult, err := svc.client.DataService().Global.SecurityGroup.ListSecurityGroup(cts.Kit.Ctx, cts.Kit.Header(),
+		listReq)
+	if err != nil {
+		logs.Errorf("list security group failed, err: %v, ids: %v, rid: %s", err, req.SecurityGroupIDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	reports, err := svc.sgLogic.AnalyzeRisk(cts.Kit, sgResult.Details)
+	if err != nil {
+		logs.Errorf("analyze security group risk failed, err: %v, ids: %v, rid: %s", err, req.SecurityGroupIDs,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &proto.SGRiskAnalyzeResult{Details: reports}, nil
+}
+
+// ListCvmSGExposure list cvm effective inbound exposure of all associated security groups.
+func (svc *securityGroupSvc) ListCvmSGExposure(cts *rest.Contexts) (interface{}, error) {
+	return svc.listCvmSGExposure(cts, handler.ResOperateAuth)
+}
+
+// ListBizCvmSGExposure list biz cvm effective inbound exposure of all associated security groups.
+func (svc *securityGroupSvc) ListBizCvmSGExposure(cts *rest.Contexts) (interface{}, error) {
+	return svc.listCvmSGExposure(cts, handler.BizOperateAuth)
+}
+
+func (svc *securityGroupSvc) listCvmSGExposure(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (
+	interface{}, error) {
+
+	req := new(proto.CvmSGExposureReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	req.CvmIDs = slice.Unique(req.CvmIDs)
+
+	basicInfos, err := svc.listBasicInfo(cts.Kit, enumor.CvmCloudResType, req.CvmIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: meta.SecurityGroup,
+		Action: meta.Find, BasicInfos: basicInfos})
+	if err != nil {
+		return nil, err
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ContainersExpression("id", req.CvmIDs),
+		Page:   core.NewDefaultBasePage(),
+	}
+	cvmResult, err := svc.client.DataService().Global.Cvm.ListCvm(cts.Kit, listReq)
+	if err != nil {
+		logs.Errorf("list cvm failed, err: %v, ids: %v, rid: %s", err, req.CvmIDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	exposures, err := svc.sgLogic.CvmExposure(cts.Kit, cvmResult.Details)
+	if err != nil {
+		logs.Errorf("calculate cvm security group exposure failed, err: %v, ids: %v, rid: %s", err, req.CvmIDs,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &proto.CvmSGExposureResult{Details: exposures}, nil
+}
+
+func (svc *securityGroupSvc) listBasicInfo(kt *kit.Kit, resType enumor.CloudResourceType, ids []string) (
+	map[string]types.CloudResourceBasicInfo, error) {
+
+	basicInfoReq := dataproto.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+	}
+	basicInfos, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(kt, basicInfoReq)
+	if err != nil {
+		logs.Errorf("list %s basic info failed, err: %v, ids: %v, rid: %s", resType, err, ids, kt.Rid)
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if _, exist := basicInfos[id]; !exist {
+			return nil, errf.Newf(errf.RecordNotFound, "%s: %s not found", resType, id)
+		}
+	}
+
+	return basicInfos, nil
+}
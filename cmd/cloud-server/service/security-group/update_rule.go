@@ -101,6 +101,10 @@ func (svc *securityGroupSvc) updateTCloudSGRule(cts *rest.Contexts, sgBaseInfo *
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.admitUpdateSGRule(cts.Kit, sgBaseInfo, id, req); err != nil {
+		return nil, err
+	}
+
 	// create update audit.
 	updateFields, err := converter.StructToMap(req)
 	if err != nil {
@@ -146,6 +150,10 @@ func (svc *securityGroupSvc) updateAwsSGRule(cts *rest.Contexts, sgBaseInfo *typ
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.admitUpdateSGRule(cts.Kit, sgBaseInfo, id, req); err != nil {
+		return nil, err
+	}
+
 	// create update audit.
 	updateFields, err := converter.StructToMap(req)
 	if err != nil {
@@ -187,6 +195,10 @@ func (svc *securityGroupSvc) updateAzureSGRule(cts *rest.Contexts, sgBaseInfo *t
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.admitUpdateSGRule(cts.Kit, sgBaseInfo, id, req); err != nil {
+		return nil, err
+	}
+
 	// create update audit.
 	updateFields, err := converter.StructToMap(req)
 	if err != nil {
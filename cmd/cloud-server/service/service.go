@@ -31,6 +31,7 @@ import (
 	"hcm/cmd/cloud-server/logics"
 	logicaudit "hcm/cmd/cloud-server/logics/audit"
 	"hcm/cmd/cloud-server/service/account"
+	admissionpolicy "hcm/cmd/cloud-server/service/admission-policy"
 	"hcm/cmd/cloud-server/service/application"
 	appcvm "hcm/cmd/cloud-server/service/application/handlers/cvm"
 	approvalprocess "hcm/cmd/cloud-server/service/approval_process"
@@ -44,11 +45,16 @@ import (
 	"hcm/cmd/cloud-server/service/cert"
 	cloudselection "hcm/cmd/cloud-server/service/cloud-selection"
 	"hcm/cmd/cloud-server/service/cvm"
+	"hcm/cmd/cloud-server/service/dependency"
 	"hcm/cmd/cloud-server/service/disk"
+	diskbackup "hcm/cmd/cloud-server/service/disk-backup"
+	disksnapshot "hcm/cmd/cloud-server/service/disk-snapshot"
+	distributedlock "hcm/cmd/cloud-server/service/distributed-lock"
 	"hcm/cmd/cloud-server/service/eip"
 	"hcm/cmd/cloud-server/service/firewall"
 	"hcm/cmd/cloud-server/service/image"
 	instancetype "hcm/cmd/cloud-server/service/instance-type"
+	"hcm/cmd/cloud-server/service/ipam"
 	loadbalancer "hcm/cmd/cloud-server/service/load-balancer"
 	networkinterface "hcm/cmd/cloud-server/service/network-interface"
 	"hcm/cmd/cloud-server/service/recycle"
@@ -59,16 +65,19 @@ import (
 	subaccount "hcm/cmd/cloud-server/service/sub-account"
 	"hcm/cmd/cloud-server/service/subnet"
 	"hcm/cmd/cloud-server/service/sync"
-	"hcm/cmd/cloud-server/service/sync/lock"
+	"hcm/cmd/cloud-server/service/tag"
+	"hcm/cmd/cloud-server/service/topology"
 	"hcm/cmd/cloud-server/service/user"
 	"hcm/cmd/cloud-server/service/vpc"
 	"hcm/cmd/cloud-server/service/zone"
 	"hcm/pkg/cc"
 	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/cryptography"
 	"hcm/pkg/handler"
 	"hcm/pkg/iam/auth"
+	"hcm/pkg/lock"
 	"hcm/pkg/logs"
 	"hcm/pkg/metrics"
 	"hcm/pkg/rest"
@@ -82,6 +91,7 @@ import (
 	"hcm/pkg/tools/ssl"
 
 	"github.com/emicklei/go-restful/v3"
+	etcd3 "go.etcd.io/etcd/client/v3"
 )
 
 // Service do all the cloud server's work
@@ -97,6 +107,7 @@ type Service struct {
 	itsmCli   itsm.Client
 	bkBaseCli bkbase.Client
 	cmsiCli   cmsi.Client
+	locker    lock.Locker
 }
 
 // NewService create a service instance.
@@ -106,11 +117,7 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 		return nil, err
 	}
 
-	etcdCfg, err := cc.CloudServer().Service.Etcd.ToConfig()
-	if err != nil {
-		return nil, err
-	}
-	err = lock.InitManger(etcdCfg, int64(cc.CloudServer().CloudResource.Sync.SyncFrequencyLimitingTimeMin)*60)
+	svr.locker, err = newLocker(cc.CloudServer().Lock, apiClientSet)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +132,8 @@ func NewService(sd serviced.ServiceDiscover) (*Service, error) {
 		go bill.CloudBillConfigCreate(interval, sd, apiClientSet)
 	}
 
-	recycle.RecycleTiming(apiClientSet, sd, cc.CloudServer().Recycle, esbClient)
+	recycle.RecycleTiming(apiClientSet, sd, cc.CloudServer().Recycle, esbClient, svr.locker, svr.cmsiCli)
+	diskbackup.AlertTiming(apiClientSet, sd, svr.cmsiCli)
 
 	go appcvm.TimingHandleDeliverApplication(svr.client, 2*time.Second)
 
@@ -204,6 +212,42 @@ func getCloudClientSvr(sd serviced.ServiceDiscover) (*client.ClientSet, esb.Clie
 	return apiClientSet, esbClient, svr, nil
 }
 
+// newLocker 根据配置创建分布式锁
+func newLocker(cfg cc.Lock, cliSet *client.ClientSet) (lock.Locker, error) {
+	typ := cfg.GetBackend()
+	switch typ {
+	case enumor.BackendMysql:
+		return lock.New(typ, cliSet.DataService())
+
+	case enumor.BackendEtcd:
+		etcdCfg := cfg.Etcd
+		// 未单独配置etcd时，复用服务发现的etcd
+		if len(etcdCfg.Endpoints) == 0 {
+			etcdCfg = cc.CloudServer().Service.Etcd
+		}
+
+		etcdOpt, err := etcdCfg.ToConfig()
+		if err != nil {
+			return nil, fmt.Errorf("get lock etcd config failed, err: %v", err)
+		}
+
+		cli, err := etcd3.New(etcdOpt)
+		if err != nil {
+			return nil, fmt.Errorf("new lock etcd client failed, err: %v", err)
+		}
+
+		return lock.New(typ, cli)
+
+	default:
+		return nil, fmt.Errorf("unsupported lock backend type: %s", typ)
+	}
+}
+
+// Close release the resources held by service.
+func (s *Service) Close() {
+	s.locker.Close()
+}
+
 // newCipherFromConfig 根据配置文件里的加密配置，选择配置的算法并生成对应的加解密器
 func newCipherFromConfig(cryptoConfig cc.Crypto) (cryptography.Crypto, error) {
 	// TODO: 目前只支持国际加密，还未支持中国国家商业加密，待后续支持再调整
@@ -284,6 +328,7 @@ func (s *Service) apiSet(bkHcmUrl string) *restful.Container {
 		ItsmCli:    s.itsmCli,
 		BKBaseCli:  s.bkBaseCli,
 		CmsiCli:    s.cmsiCli,
+		Locker:     s.locker,
 	}
 
 	account.InitAccountService(c)
@@ -291,6 +336,8 @@ func (s *Service) apiSet(bkHcmUrl string) *restful.Container {
 	firewall.InitFirewallService(c)
 	vpc.InitVpcService(c)
 	disk.InitDiskService(c)
+	disksnapshot.InitService(c)
+	diskbackup.InitService(c)
 	subnet.InitSubnetService(c)
 	image.InitImageService(c)
 	routetable.InitRouteTableService(c)
@@ -299,6 +346,7 @@ func (s *Service) apiSet(bkHcmUrl string) *restful.Container {
 	zone.InitZoneService(c)
 	region.InitRegionService(c)
 	eip.InitEipService(c)
+	tag.InitService(c)
 	instancetype.InitInstanceTypeService(c)
 	networkinterface.InitNetworkInterfaceService(c)
 	subaccount.InitService(c)
@@ -319,6 +367,11 @@ func (s *Service) apiSet(bkHcmUrl string) *restful.Container {
 	asynctask.InitService(c)
 
 	bandwidthpackage.InitService(c)
+	distributedlock.InitService(c)
+	admissionpolicy.InitService(c)
+	ipam.InitService(c)
+	topology.InitService(c)
+	dependency.InitService(c)
 
 	return restful.NewContainer().Add(c.WebService)
 }
@@ -25,6 +25,8 @@ import (
 
 	"hcm/cmd/cloud-server/logics/async"
 	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/logics/dependency"
+	"hcm/cmd/cloud-server/logics/ipam"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/cmd/cloud-server/service/common"
 	actionsubnet "hcm/cmd/task-server/logics/action/subnet"
@@ -39,6 +41,7 @@ import (
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
 	"hcm/pkg/iam/auth"
 	"hcm/pkg/iam/meta"
 	"hcm/pkg/kit"
@@ -57,6 +60,8 @@ func InitSubnetService(c *capability.Capability) {
 		client:     c.ApiClient,
 		authorizer: c.Authorizer,
 		audit:      c.Audit,
+		ipam:       c.Logics.IPAM,
+		dependency: c.Logics.Dependency,
 	}
 
 	h := rest.NewHandler()
@@ -88,6 +93,8 @@ type subnetSvc struct {
 	client     *client.ClientSet
 	authorizer auth.Authorizer
 	audit      audit.Interface
+	ipam       ipam.Interface
+	dependency dependency.Interface
 }
 
 // CreateSubnet create subnet.
@@ -152,6 +159,11 @@ func (svc *subnetSvc) createTCloudSubnet(kt *kit.Kit, bizID int64, data json.Raw
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	cidrs := []string{req.IPv4Cidr}
+	if err := svc.ipam.EnsureSubnetCidrAvailable(kt, req.AccountID, req.CloudVpcID, cidrs); err != nil {
+		return nil, err
+	}
+
 	opt := &hcservice.TCloudSubnetBatchCreateReq{
 		BkBizID:    bizID,
 		AccountID:  req.AccountID,
@@ -170,6 +182,7 @@ func (svc *subnetSvc) createTCloudSubnet(kt *kit.Kit, bizID int64, data json.Raw
 	if err != nil {
 		return nil, err
 	}
+	svc.syncIPAMBlocks(kt, req.AccountID, req.CloudVpcID)
 
 	return core.CreateResult{ID: createRes.IDs[0]}, nil
 }
@@ -186,6 +199,11 @@ func (svc *subnetSvc) createAwsSubnet(kt *kit.Kit, bizID int64, data json.RawMes
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	cidrs := []string{converter.PtrToVal(req.IPv4Cidr), converter.PtrToVal(req.IPv6Cidr)}
+	if err := svc.ipam.EnsureSubnetCidrAvailable(kt, req.AccountID, req.CloudVpcID, cidrs); err != nil {
+		return nil, err
+	}
+
 	opt := &hcservice.SubnetCreateReq[hcservice.AwsSubnetCreateExt]{
 		BaseSubnetCreateReq: convertBaseSubnetCreateReq(bizID, req.BaseSubnetCreateReq),
 		Extension: &hcservice.AwsSubnetCreateExt{
@@ -199,6 +217,7 @@ func (svc *subnetSvc) createAwsSubnet(kt *kit.Kit, bizID int64, data json.RawMes
 	if err != nil {
 		return nil, err
 	}
+	svc.syncIPAMBlocks(kt, req.AccountID, req.CloudVpcID)
 
 	return createRes, nil
 }
@@ -215,6 +234,11 @@ func (svc *subnetSvc) createGcpSubnet(kt *kit.Kit, bizID int64, data json.RawMes
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	cidrs := []string{req.IPv4Cidr}
+	if err := svc.ipam.EnsureSubnetCidrAvailable(kt, req.AccountID, req.CloudVpcID, cidrs); err != nil {
+		return nil, err
+	}
+
 	opt := &hcservice.SubnetCreateReq[hcservice.GcpSubnetCreateExt]{
 		BaseSubnetCreateReq: convertBaseSubnetCreateReq(bizID, req.BaseSubnetCreateReq),
 		Extension: &hcservice.GcpSubnetCreateExt{
@@ -228,6 +252,7 @@ func (svc *subnetSvc) createGcpSubnet(kt *kit.Kit, bizID int64, data json.RawMes
 	if err != nil {
 		return nil, err
 	}
+	svc.syncIPAMBlocks(kt, req.AccountID, req.CloudVpcID)
 
 	return createRes, nil
 }
@@ -244,6 +269,11 @@ func (svc *subnetSvc) createAzureSubnet(kt *kit.Kit, bizID int64, data json.RawM
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	cidrs := append(append([]string{}, req.IPv4Cidr...), req.IPv6Cidr...)
+	if err := svc.ipam.EnsureSubnetCidrAvailable(kt, req.AccountID, req.CloudVpcID, cidrs); err != nil {
+		return nil, err
+	}
+
 	// check azure subnet params
 	if err := svc.checkAzureSubnetParams(req); err != nil {
 		return nil, err
@@ -264,6 +294,7 @@ func (svc *subnetSvc) createAzureSubnet(kt *kit.Kit, bizID int64, data json.RawM
 	if err != nil {
 		return nil, err
 	}
+	svc.syncIPAMBlocks(kt, req.AccountID, req.CloudVpcID)
 
 	return createRes, nil
 }
@@ -280,6 +311,11 @@ func (svc *subnetSvc) createHuaWeiSubnet(kt *kit.Kit, bizID int64, data json.Raw
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	cidrs := []string{req.IPv4Cidr}
+	if err := svc.ipam.EnsureSubnetCidrAvailable(kt, req.AccountID, req.CloudVpcID, cidrs); err != nil {
+		return nil, err
+	}
+
 	opt := &hcservice.SubnetCreateReq[hcservice.HuaWeiSubnetCreateExt]{
 		BaseSubnetCreateReq: convertBaseSubnetCreateReq(bizID, req.BaseSubnetCreateReq),
 		Extension: &hcservice.HuaWeiSubnetCreateExt{
@@ -294,10 +330,33 @@ func (svc *subnetSvc) createHuaWeiSubnet(kt *kit.Kit, bizID int64, data json.Raw
 	if err != nil {
 		return nil, err
 	}
+	svc.syncIPAMBlocks(kt, req.AccountID, req.CloudVpcID)
 
 	return createRes, nil
 }
 
+// syncIPAMBlocks 刷新子网所属vpc的地址块索引，失败时仅记录日志，由定时同步兜底
+func (svc *subnetSvc) syncIPAMBlocks(kt *kit.Kit, accountID, cloudVpcID string) {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("account_id", accountID), tools.RuleEqual("cloud_id", cloudVpcID)),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	result, err := svc.client.DataService().Global.Vpc.List(kt.Ctx, kt.Header(), listReq)
+	if err != nil {
+		logs.Errorf("list subnet vpc failed, err: %v, cloud vpc id: %s, rid: %s", err, cloudVpcID, kt.Rid)
+		return
+	}
+
+	vpcIDs := make([]string, 0, len(result.Details))
+	for _, vpc := range result.Details {
+		vpcIDs = append(vpcIDs, vpc.ID)
+	}
+	if err = svc.ipam.SyncVpcBlocks(kt, vpcIDs); err != nil {
+		logs.Errorf("sync vpc ipam blocks failed, err: %v, vpc ids: %v, rid: %s", err, vpcIDs, kt.Rid)
+	}
+}
+
 func convertBaseSubnetCreateReq(bizID int64, req *cloudserver.BaseSubnetCreateReq) *hcservice.BaseSubnetCreateReq {
 	return &hcservice.BaseSubnetCreateReq{
 		AccountID:  req.AccountID,
@@ -520,6 +579,11 @@ func (svc *subnetSvc) batchDeleteSubnet(cts *rest.Contexts, validHandler handler
 		return nil, err
 	}
 
+	// check if there are resources still depending on the subnets
+	if err = svc.dependency.EnsureDeletable(cts.Kit, enumor.SubnetCloudResType, req.IDs); err != nil {
+		return nil, err
+	}
+
 	// create delete audit.
 	if err := svc.audit.ResDeleteAudit(cts.Kit, enumor.SubnetAuditResType, req.IDs); err != nil {
 		logs.Errorf("create delete audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
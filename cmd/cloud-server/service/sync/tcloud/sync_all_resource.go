@@ -92,6 +92,7 @@ func SyncAllResource(kt *kit.Kit, cliSet *client.ClientSet,
 
 	syncFuncMap := map[enumor.CloudResourceType]ResSyncFunc{
 		enumor.DiskCloudResType:          SyncDisk,
+		enumor.DiskSnapshotCloudResType:  SyncDiskSnapshot,
 		enumor.VpcCloudResType:           SyncVpc,
 		enumor.SubnetCloudResType:        SyncSubnet,
 		enumor.EipCloudResType:           SyncEip,
@@ -116,6 +117,7 @@ func SyncAllResource(kt *kit.Kit, cliSet *client.ClientSet,
 func getSyncOrder() []enumor.CloudResourceType {
 	return []enumor.CloudResourceType{
 		enumor.DiskCloudResType,
+		enumor.DiskSnapshotCloudResType,
 		enumor.VpcCloudResType,
 		enumor.SubnetCloudResType,
 		enumor.EipCloudResType,
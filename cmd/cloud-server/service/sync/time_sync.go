@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"hcm/cmd/cloud-server/logics/account"
+	"hcm/cmd/cloud-server/logics/ipam"
 	"hcm/cmd/cloud-server/service/sync/detail"
 	"hcm/pkg/api/core"
 	corecloud "hcm/pkg/api/core/cloud"
@@ -88,6 +89,7 @@ func allAccountSync(kt *kit.Kit, cliSet *client.ClientSet, syncer account.Vendor
 	}
 	start := uint32(0)
 	syncPublicResource := true
+	ipamLogic := ipam.NewIPAM(cliSet)
 	for {
 		listReq.Page.Start = start
 		accounts, err := listAccountWithRetry(kt, cliSet.DataService(), listReq)
@@ -120,6 +122,12 @@ func allAccountSync(kt *kit.Kit, cliSet *client.ClientSet, syncer account.Vendor
 
 			// 公共资源仅需要同步一次即可
 			syncPublicResource = false
+
+			// 资源同步完成后刷新账号下vpc、子网的地址块索引
+			if err = ipamLogic.SyncAccountBlocks(kt, acc.ID); err != nil {
+				logs.Errorf("sync %s ipam blocks failed, err: %v, accountID: %s, rid: %s", syncer.Vendor(), err,
+					acc.ID, kt.Rid)
+			}
 		}
 		if len(accounts) < int(core.DefaultMaxPageLimit) {
 			break
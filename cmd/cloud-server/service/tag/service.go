@@ -0,0 +1,270 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tag 资源标签批量修改服务
+package tag
+
+import (
+	"fmt"
+	"net/http"
+
+	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/service/capability"
+	cstag "hcm/pkg/api/cloud-server/tag"
+	"hcm/pkg/api/data-service/cloud"
+	hctag "hcm/pkg/api/hc-service/tag"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+	"hcm/pkg/tools/slice"
+)
+
+// InitService initialize the tag service.
+func InitService(c *capability.Capability) {
+	svc := &tagSvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+		audit:      c.Audit,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("BatchAddTag", http.MethodPost, "/tags/batch/add", svc.BatchAddTag)
+	h.Add("BatchRemoveTag", http.MethodPost, "/tags/batch/remove", svc.BatchRemoveTag)
+
+	// tag apis in biz
+	h.Add("BatchAddBizTag", http.MethodPost, "/bizs/{bk_biz_id}/tags/batch/add", svc.BatchAddBizTag)
+	h.Add("BatchRemoveBizTag", http.MethodPost, "/bizs/{bk_biz_id}/tags/batch/remove", svc.BatchRemoveBizTag)
+
+	h.Load(c.WebService)
+}
+
+type tagSvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+	audit      audit.Interface
+}
+
+// tagResMeta 支持标签的资源类型对应的鉴权资源类型与审计资源类型
+var tagResMeta = map[enumor.CloudResourceType]struct {
+	authType  meta.ResourceType
+	auditType enumor.AuditResourceType
+}{
+	enumor.CvmCloudResType:    {authType: meta.Cvm, auditType: enumor.CvmAuditResType},
+	enumor.DiskCloudResType:   {authType: meta.Disk, auditType: enumor.DiskAuditResType},
+	enumor.EipCloudResType:    {authType: meta.Eip, auditType: enumor.EipAuditResType},
+	enumor.VpcCloudResType:    {authType: meta.Vpc, auditType: enumor.VpcCloudAuditResType},
+	enumor.SubnetCloudResType: {authType: meta.Subnet, auditType: enumor.SubnetAuditResType},
+}
+
+// BatchAddTag batch add resource tags.
+func (svc *tagSvc) BatchAddTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchAddTag(cts, handler.ResOperateAuth)
+}
+
+// BatchAddBizTag batch add biz resource tags.
+func (svc *tagSvc) BatchAddBizTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchAddTag(cts, handler.BizOperateAuth)
+}
+
+func (svc *tagSvc) batchAddTag(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (interface{}, error) {
+	req := new(cstag.BatchAddTagReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	groups, err := svc.validAndGroup(cts, validHandler, req.ResType, req.IDs,
+		map[string]interface{}{"add_tags": req.Tags})
+	if err != nil {
+		return nil, err
+	}
+
+	for key, ids := range groups {
+		hcReq := &hctag.BatchAddTagReq{AccountID: key.accountID, ResType: req.ResType, ResIDs: ids, Tags: req.Tags}
+
+		switch key.vendor {
+		case enumor.TCloud:
+			err = svc.client.HCService().TCloud.Tag.BatchAddTag(cts.Kit, hcReq)
+		case enumor.Aws:
+			err = svc.client.HCService().Aws.Tag.BatchAddTag(cts.Kit, hcReq)
+		case enumor.Azure:
+			err = svc.client.HCService().Azure.Tag.BatchAddTag(cts.Kit, hcReq)
+		case enumor.Gcp:
+			err = svc.client.HCService().Gcp.Tag.BatchAddTag(cts.Kit, hcReq)
+		case enumor.HuaWei:
+			err = svc.client.HCService().HuaWei.Tag.BatchAddTag(cts.Kit, hcReq)
+		default:
+			return nil, errf.Newf(errf.Unknown, "vendor: %s not support", key.vendor)
+		}
+
+		if err != nil {
+			logs.Errorf("batch add %s tag failed, err: %v, req: %+v, rid: %s", key.vendor, err, hcReq, cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// BatchRemoveTag batch remove resource tags.
+func (svc *tagSvc) BatchRemoveTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchRemoveTag(cts, handler.ResOperateAuth)
+}
+
+// BatchRemoveBizTag batch remove biz resource tags.
+func (svc *tagSvc) BatchRemoveBizTag(cts *rest.Contexts) (interface{}, error) {
+	return svc.batchRemoveTag(cts, handler.BizOperateAuth)
+}
+
+func (svc *tagSvc) batchRemoveTag(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (interface{},
+	error) {
+
+	req := new(cstag.BatchRemoveTagReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	groups, err := svc.validAndGroup(cts, validHandler, req.ResType, req.IDs,
+		map[string]interface{}{"remove_tag_keys": req.Keys})
+	if err != nil {
+		return nil, err
+	}
+
+	for key, ids := range groups {
+		hcReq := &hctag.BatchRemoveTagReq{AccountID: key.accountID, ResType: req.ResType, ResIDs: ids, Keys: req.Keys}
+
+		switch key.vendor {
+		case enumor.TCloud:
+			err = svc.client.HCService().TCloud.Tag.BatchRemoveTag(cts.Kit, hcReq)
+		case enumor.Aws:
+			err = svc.client.HCService().Aws.Tag.BatchRemoveTag(cts.Kit, hcReq)
+		case enumor.Azure:
+			err = svc.client.HCService().Azure.Tag.BatchRemoveTag(cts.Kit, hcReq)
+		case enumor.Gcp:
+			err = svc.client.HCService().Gcp.Tag.BatchRemoveTag(cts.Kit, hcReq)
+		case enumor.HuaWei:
+			err = svc.client.HCService().HuaWei.Tag.BatchRemoveTag(cts.Kit, hcReq)
+		default:
+			return nil, errf.Newf(errf.Unknown, "vendor: %s not support", key.vendor)
+		}
+
+		if err != nil {
+			logs.Errorf("batch remove %s tag failed, err: %v, req: %+v, rid: %s", key.vendor, err, hcReq,
+				cts.Kit.Rid)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+type accountKey struct {
+	vendor    enumor.Vendor
+	accountID string
+}
+
+// validAndGroup 鉴权并记录审计，返回按云厂商与账号分组的资源ID
+func (svc *tagSvc) validAndGroup(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler,
+	resType enumor.CloudResourceType, ids []string, updateFields map[string]interface{}) (
+	map[accountKey][]string, error) {
+
+	resMeta, exists := tagResMeta[resType]
+	if !exists {
+		return nil, errf.Newf(errf.InvalidParameter, "resource type %s not support tag", resType)
+	}
+
+	ids = slice.Unique(ids)
+	// 主机与硬盘支持回收，需要查询回收状态，回收站中的资源不允许修改标签
+	fields := append([]string{}, types.CommonBasicInfoFields...)
+	if resType == enumor.CvmCloudResType || resType == enumor.DiskCloudResType {
+		fields = append(fields, "recycle_status")
+	}
+
+	basicInfoReq := cloud.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          ids,
+		Fields:       fields,
+	}
+	basicInfoMap, err := svc.client.DataService().Global.Cloud.ListResBasicInfo(cts.Kit, basicInfoReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate biz and authorize
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer, ResType: resMeta.authType,
+		Action: meta.Update, BasicInfos: basicInfoMap})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[accountKey][]string)
+	recycledIDs := make([]string, 0)
+	for _, id := range ids {
+		info, exists := basicInfoMap[id]
+		if !exists {
+			return nil, errf.New(errf.InvalidParameter, fmt.Sprintf("id %s has no corresponding vendor", id))
+		}
+
+		if info.RecycleStatus == enumor.RecycleStatus {
+			recycledIDs = append(recycledIDs, id)
+			continue
+		}
+
+		key := accountKey{vendor: info.Vendor, accountID: info.AccountID}
+		groups[key] = append(groups[key], id)
+	}
+
+	if len(recycledIDs) > 0 {
+		return nil, errf.Newf(errf.InvalidParameter, "resources(ids: %v) are in recycle bin", recycledIDs)
+	}
+
+	if err = svc.updateAudit(cts.Kit, resMeta.auditType, ids, updateFields); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (svc *tagSvc) updateAudit(kt *kit.Kit, auditType enumor.AuditResourceType, ids []string,
+	updateFields map[string]interface{}) error {
+
+	for _, id := range ids {
+		if err := svc.audit.ResUpdateAudit(kt, auditType, id, updateFields); err != nil {
+			logs.Errorf("create update audit failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package topology 资源拓扑服务
+package topology
+
+import (
+	"net/http"
+
+	"hcm/cmd/cloud-server/logics/topology"
+	"hcm/cmd/cloud-server/service/capability"
+	cstopology "hcm/pkg/api/cloud-server/topology"
+	"hcm/pkg/client"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/iam/auth"
+	"hcm/pkg/iam/meta"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/hooks/handler"
+)
+
+// InitService initialize the topology service.
+func InitService(c *capability.Capability) {
+	svc := &topologySvc{
+		client:     c.ApiClient,
+		authorizer: c.Authorizer,
+		topology:   c.Logics.Topology,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("GetTopologyGraph", http.MethodPost, "/topology/graph", svc.GetGraph)
+	h.Add("GetBizTopologyGraph", http.MethodPost, "/bizs/{bk_biz_id}/topology/graph", svc.GetBizGraph)
+
+	h.Load(c.WebService)
+}
+
+type topologySvc struct {
+	client     *client.ClientSet
+	authorizer auth.Authorizer
+	topology   topology.Interface
+}
+
+// GetGraph get topology graph of resource.
+func (svc *topologySvc) GetGraph(cts *rest.Contexts) (interface{}, error) {
+	return svc.getGraph(cts, handler.ResOperateAuth)
+}
+
+// GetBizGraph get topology graph of biz resource, only resources in biz are expanded.
+func (svc *topologySvc) GetBizGraph(cts *rest.Contexts) (interface{}, error) {
+	return svc.getGraph(cts, handler.BizOperateAuth)
+}
+
+func (svc *topologySvc) getGraph(cts *rest.Contexts, validHandler handler.ValidWithAuthHandler) (interface{}, error) {
+	req := new(cstopology.GraphReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	basicInfo, err := svc.client.DataService().Global.Cloud.GetResBasicInfo(cts.Kit, req.ResType, req.ID,
+		types.CommonBasicInfoFields...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 校验根资源的查看权限
+	err = validHandler(cts, &handler.ValidWithAuthOption{Authorizer: svc.authorizer,
+		ResType: meta.ResourceType(req.ResType), Action: meta.Find, BasicInfo: basicInfo})
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &topology.GraphOption{
+		ResType:  req.ResType,
+		ID:       req.ID,
+		Depth:    req.Depth,
+		ResTypes: req.ResTypes,
+	}
+	if opt.Depth == 0 {
+		opt.Depth = cstopology.DefaultGraphDepth
+	}
+
+	if len(cts.PathParameter("bk_biz_id").String()) != 0 {
+		if opt.BkBizID, err = cts.PathParameter("bk_biz_id").Int64(); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+	}
+
+	graph, err := svc.topology.Graph(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("get topology graph failed, err: %v, res_type: %s, id: %s, rid: %s", err, req.ResType, req.ID,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	return graph, nil
+}
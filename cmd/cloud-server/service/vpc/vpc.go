@@ -25,6 +25,8 @@ import (
 	"fmt"
 
 	"hcm/cmd/cloud-server/logics/audit"
+	"hcm/cmd/cloud-server/logics/dependency"
+	"hcm/cmd/cloud-server/logics/ipam"
 	"hcm/cmd/cloud-server/service/capability"
 	"hcm/cmd/cloud-server/service/common"
 	cloudserver "hcm/pkg/api/cloud-server"
@@ -53,6 +55,8 @@ func InitVpcService(c *capability.Capability) {
 		client:     c.ApiClient,
 		authorizer: c.Authorizer,
 		audit:      c.Audit,
+		ipam:       c.Logics.IPAM,
+		dependency: c.Logics.Dependency,
 	}
 
 	h := rest.NewHandler()
@@ -80,6 +84,8 @@ type vpcSvc struct {
 	client     *client.ClientSet
 	authorizer auth.Authorizer
 	audit      audit.Interface
+	ipam       ipam.Interface
+	dependency dependency.Interface
 }
 
 // CreateVpc create vpc.
@@ -134,12 +140,18 @@ func (svc *vpcSvc) createTCloudVpc(kt *kit.Kit, body json.RawMessage) (interface
 	if err := req.Validate(false); err != nil {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
+
+	if err := svc.ipam.EnsureVpcCidrAvailable(kt, req.BkCloudID, []string{req.IPv4Cidr}); err != nil {
+		return nil, err
+	}
+
 	// 转换参数并调用HCService进行创建流程
 	result, err := svc.client.HCService().TCloud.Vpc.Create(kt.Ctx, kt.Header(), common.ConvTCloudVpcCreateReq(req))
 	if err != nil {
 		logs.Errorf("batch create tcloud vpc failed, err: %v, result: %v, rid: %s", err, result, kt.Rid)
 		return result, err
 	}
+	svc.syncIPAMBlocks(kt, []string{result.ID})
 
 	return result, nil
 }
@@ -155,11 +167,16 @@ func (svc *vpcSvc) createAzureVpc(kt *kit.Kit, body json.RawMessage) (interface{
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.ipam.EnsureVpcCidrAvailable(kt, req.BkCloudID, []string{req.IPv4Cidr}); err != nil {
+		return nil, err
+	}
+
 	result, err := svc.client.HCService().Azure.Vpc.Create(kt.Ctx, kt.Header(), common.ConvAzureVpcCreateReq(req))
 	if err != nil {
 		logs.Errorf("batch create azure vpc failed, err: %v, result: %v, rid: %s", err, result, kt.Rid)
 		return result, err
 	}
+	svc.syncIPAMBlocks(kt, []string{result.ID})
 
 	return result, nil
 }
@@ -175,12 +192,17 @@ func (svc *vpcSvc) createHuaWeiVpc(kt *kit.Kit, body json.RawMessage) (interface
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.ipam.EnsureVpcCidrAvailable(kt, req.BkCloudID, []string{req.IPv4Cidr}); err != nil {
+		return nil, err
+	}
+
 	result, err := svc.client.HCService().HuaWei.Vpc.Create(kt.Ctx, kt.Header(),
 		common.ConvHuaWeiVpcCreateReq(req))
 	if err != nil {
 		logs.Errorf("batch create huawei vpc failed, err: %v, result: %v, rid: %s", err, result, kt.Rid)
 		return result, err
 	}
+	svc.syncIPAMBlocks(kt, []string{result.ID})
 
 	return result, nil
 }
@@ -196,11 +218,16 @@ func (svc *vpcSvc) createGcpVpc(kt *kit.Kit, body json.RawMessage) (interface{},
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.ipam.EnsureVpcCidrAvailable(kt, req.BkCloudID, []string{req.Subnet.IPv4Cidr}); err != nil {
+		return nil, err
+	}
+
 	result, err := svc.client.HCService().Gcp.Vpc.Create(kt.Ctx, kt.Header(), common.ConvGcpVpcCreateReq(req))
 	if err != nil {
 		logs.Errorf("batch create gcp vpc failed, err: %v, result: %v, rid: %s", err, result, kt.Rid)
 		return result, err
 	}
+	svc.syncIPAMBlocks(kt, []string{result.ID})
 
 	return result, nil
 }
@@ -216,15 +243,27 @@ func (svc *vpcSvc) createAwsVpc(kt *kit.Kit, body json.RawMessage) (interface{},
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
+	if err := svc.ipam.EnsureVpcCidrAvailable(kt, req.BkCloudID, []string{req.IPv4Cidr}); err != nil {
+		return nil, err
+	}
+
 	result, err := svc.client.HCService().Aws.Vpc.Create(kt.Ctx, kt.Header(), common.ConvAwsVpcCreateReq(req))
 	if err != nil {
 		logs.Errorf("batch create aws vpc failed, err: %v, result: %v, rid: %s", err, result, kt.Rid)
 		return result, err
 	}
+	svc.syncIPAMBlocks(kt, []string{result.ID})
 
 	return result, nil
 }
 
+// syncIPAMBlocks 刷新新建或绑定云区域的vpc的地址块索引，失败时仅记录日志，由定时同步兜底
+func (svc *vpcSvc) syncIPAMBlocks(kt *kit.Kit, vpcIDs []string) {
+	if err := svc.ipam.SyncVpcBlocks(kt, vpcIDs); err != nil {
+		logs.Errorf("sync vpc ipam blocks failed, err: %v, vpc ids: %v, rid: %s", err, vpcIDs, kt.Rid)
+	}
+}
+
 // UpdateVpc update vpc.
 func (svc *vpcSvc) UpdateVpc(cts *rest.Contexts) (interface{}, error) {
 	return svc.updateVpc(cts, handler.ResOperateAuth)
@@ -480,6 +519,11 @@ func (svc *vpcSvc) deleteVpc(cts *rest.Contexts, validHandler handler.ValidWithA
 		return nil, err
 	}
 
+	// check if there are resources still depending on the vpc
+	if err = svc.dependency.EnsureDeletable(cts.Kit, enumor.VpcCloudResType, []string{id}); err != nil {
+		return nil, err
+	}
+
 	// create delete audit.
 	if err := svc.audit.ResDeleteAudit(cts.Kit, enumor.VpcCloudAuditResType, []string{id}); err != nil {
 		logs.Errorf("create delete audit failed, err: %v, rid: %s", err, cts.Kit.Rid)
@@ -569,6 +613,7 @@ func (svc *vpcSvc) AssignVpcToBiz(cts *rest.Contexts) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	svc.syncIPAMBlocks(cts.Kit, req.VpcIDs)
 
 	return nil, nil
 }
@@ -649,6 +694,7 @@ func (svc *vpcSvc) BindVpcWithCloudArea(cts *rest.Contexts) (interface{}, error)
 	if err != nil {
 		return nil, err
 	}
+	svc.syncIPAMBlocks(cts.Kit, ids)
 
 	return nil, nil
 }
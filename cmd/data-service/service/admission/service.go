@@ -0,0 +1,361 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package admission ...
+package admission
+
+import (
+	"fmt"
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	coreadmission "hcm/pkg/api/core/admission"
+	dataservice "hcm/pkg/api/data-service"
+	dsadmission "hcm/pkg/api/data-service/admission"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableadmission "hcm/pkg/dal/table/admission"
+	tableaudit "hcm/pkg/dal/table/audit"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitService initial the admission policy service
+func InitService(cap *capability.Capability) {
+	svc := &service{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateAdmissionPolicy", http.MethodPost, "/admission_policies/create", svc.Create)
+	h.Add("UpdateAdmissionPolicy", http.MethodPatch, "/admission_policies/{id}", svc.Update)
+	h.Add("ListAdmissionPolicy", http.MethodPost, "/admission_policies/list", svc.List)
+	h.Add("BatchDeleteAdmissionPolicy", http.MethodDelete, "/admission_policies/batch", svc.BatchDelete)
+	h.Add("RecordAdmissionDecision", http.MethodPost, "/admission_policies/decisions/record",
+		svc.RecordDecision)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	dao dao.Set
+}
+
+// Create admission policy.
+func (svc *service) Create(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsadmission.CreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rule, err := tabletype.NewJsonField(req.Rule)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tableadmission.AdmissionPolicyTable{
+		Name:     req.Name,
+		ResType:  req.ResType,
+		Action:   req.Action,
+		Mode:     req.Mode,
+		BkBizIDs: req.BkBizIDs,
+		Rule:     rule,
+		Message:  req.Message,
+		Enabled:  req.Enabled,
+		Memo:     req.Memo,
+		Creator:  cts.Kit.User,
+		Reviser:  cts.Kit.User,
+	}
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.AdmissionPolicy().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create admission policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: id.(string)}, nil
+}
+
+// Update admission policy.
+func (svc *service) Update(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(dsadmission.UpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	policy, err := svc.getPolicy(cts.Kit, id)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &tableadmission.AdmissionPolicyTable{
+		Name:     req.Name,
+		Mode:     req.Mode,
+		BkBizIDs: req.BkBizIDs,
+		Enabled:  req.Enabled,
+		Memo:     req.Memo,
+		Reviser:  cts.Kit.User,
+	}
+	if req.Message != nil {
+		model.Message = *req.Message
+	} else {
+		model.Message = policy.Message
+	}
+	if req.Memo == nil {
+		model.Memo = policy.Memo
+	}
+	if req.Rule != nil {
+		if model.Rule, err = tabletype.NewJsonField(req.Rule); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.AdmissionPolicy().UpdateByIDWithTx(cts.Kit, txn, id, model); err != nil {
+			return nil, err
+		}
+
+		auditInfo := &tableaudit.AuditTable{
+			ResID:    policy.ID,
+			ResName:  policy.Name,
+			ResType:  enumor.AdmissionPolicyAuditResType,
+			Action:   enumor.Update,
+			Operator: cts.Kit.User,
+			Source:   cts.Kit.GetRequestSource(),
+			Rid:      cts.Kit.Rid,
+			AppCode:  cts.Kit.AppCode,
+			Detail:   &tableaudit.BasicDetail{Data: policy, Changed: req},
+		}
+		return nil, svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, []*tableaudit.AuditTable{auditInfo})
+	})
+	if err != nil {
+		logs.Errorf("update admission policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *service) getPolicy(kt *kit.Kit, id string) (*tableadmission.AdmissionPolicyTable, error) {
+	opt := &types.ListOption{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.AdmissionPolicy().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list admission policy failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "admission policy %s not found", id)
+	}
+
+	return &result.Details[0], nil
+}
+
+// List admission policy.
+func (svc *service) List(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.AdmissionPolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list admission policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	if req.Page.Count {
+		return &dsadmission.ListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]coreadmission.Policy, 0, len(daoResp.Details))
+	for _, one := range daoResp.Details {
+		policy, err := convTableToPolicy(&one)
+		if err != nil {
+			logs.Errorf("convert admission policy failed, err: %v, id: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, *policy)
+	}
+
+	return &dsadmission.ListResult{Details: details}, nil
+}
+
+func convTableToPolicy(one *tableadmission.AdmissionPolicyTable) (*coreadmission.Policy, error) {
+	policy := &coreadmission.Policy{
+		ID:       one.ID,
+		Name:     one.Name,
+		ResType:  one.ResType,
+		Action:   one.Action,
+		Mode:     one.Mode,
+		BkBizIDs: one.BkBizIDs,
+		Message:  one.Message,
+		Memo:     one.Memo,
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+	if one.Enabled != nil {
+		policy.Enabled = *one.Enabled
+	}
+
+	if len(one.Rule) != 0 {
+		policy.Rule = new(coreadmission.Rule)
+		if err := json.UnmarshalFromString(string(one.Rule), policy.Rule); err != nil {
+			return nil, fmt.Errorf("unmarshal rule failed, err: %v", err)
+		}
+	}
+
+	return policy, nil
+}
+
+// BatchDelete admission policy.
+func (svc *service) BatchDelete(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+	}
+	listResp, err := svc.dao.AdmissionPolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list admission policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(listResp.Details))
+	audits := make([]*tableaudit.AuditTable, 0, len(listResp.Details))
+	for _, one := range listResp.Details {
+		ids = append(ids, one.ID)
+		audits = append(audits, &tableaudit.AuditTable{
+			ResID:    one.ID,
+			ResName:  one.Name,
+			ResType:  enumor.AdmissionPolicyAuditResType,
+			Action:   enumor.Delete,
+			Operator: cts.Kit.User,
+			Source:   cts.Kit.GetRequestSource(),
+			Rid:      cts.Kit.Rid,
+			AppCode:  cts.Kit.AppCode,
+			Detail:   &tableaudit.BasicDetail{Data: one},
+		})
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		delFilter := tools.ContainersExpression("id", ids)
+		if err := svc.dao.AdmissionPolicy().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, audits)
+	})
+	if err != nil {
+		logs.Errorf("delete admission policy failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// RecordDecision record admission decisions as audits of admission policy.
+func (svc *service) RecordDecision(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsadmission.RecordDecisionReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	audits := make([]*tableaudit.AuditTable, 0, len(req.Decisions))
+	for i := range req.Decisions {
+		one := req.Decisions[i]
+		audits = append(audits, &tableaudit.AuditTable{
+			ResID:     one.PolicyID,
+			ResName:   one.PolicyName,
+			ResType:   enumor.AdmissionPolicyAuditResType,
+			Action:    enumor.Admission,
+			BkBizID:   one.BkBizID,
+			Vendor:    one.Vendor,
+			AccountID: one.AccountID,
+			Operator:  cts.Kit.User,
+			Source:    cts.Kit.GetRequestSource(),
+			Rid:       cts.Kit.Rid,
+			AppCode:   cts.Kit.AppCode,
+			Detail:    &tableaudit.BasicDetail{Data: one},
+		})
+	}
+
+	if err := svc.dao.Audit().BatchCreate(cts.Kit, audits); err != nil {
+		logs.Errorf("record admission decision audits failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
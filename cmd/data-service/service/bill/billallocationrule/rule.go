@@ -0,0 +1,221 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billallocationrule
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	billcore "hcm/pkg/api/core/bill"
+	dataservice "hcm/pkg/api/data-service"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	daotypes "hcm/pkg/dal/dao/types"
+	tablebill "hcm/pkg/dal/table/bill"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateBillAllocationRule create a new version of account bill allocation rule,
+// version is increased by one from the latest version of the main account
+func (svc *service) CreateBillAllocationRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsbill.BillAllocationRuleCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	config, err := json.MarshalToString(req.Config)
+	if err != nil {
+		return nil, fmt.Errorf("marshal allocation rule config failed, err: %v", err)
+	}
+	latestVersion, err := svc.getLatestVersion(cts, req.MainAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	idList, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		rule := &tablebill.AccountBillAllocationRule{
+			RootAccountID:  req.RootAccountID,
+			MainAccountID:  req.MainAccountID,
+			Vendor:         req.Vendor,
+			Version:        latestVersion + 1,
+			EffectiveYear:  req.EffectiveYear,
+			EffectiveMonth: req.EffectiveMonth,
+			RuleType:       req.RuleType,
+			Config:         types.JsonField(config),
+			DefaultBkBizID: req.DefaultBkBizID,
+			Enabled:        req.Enabled,
+			Memo:           req.Memo,
+			Creator:        cts.Kit.User,
+			Reviser:        cts.Kit.User,
+		}
+		ids, err := svc.dao.AccountBillAllocationRule().CreateWithTx(cts.Kit, txn,
+			[]*tablebill.AccountBillAllocationRule{rule})
+		if err != nil {
+			return nil, fmt.Errorf("create account bill allocation rule failed, err: %v", err)
+		}
+		return ids, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	retList, ok := idList.([]string)
+	if !ok || len(retList) != 1 {
+		return nil, fmt.Errorf("create account bill allocation rule but return ids invalid, ids: %v", idList)
+	}
+
+	return &core.CreateResult{ID: retList[0]}, nil
+}
+
+// getLatestVersion 获取二级账号最新的分账规则版本，没有规则时返回0。
+// 并发创建时由 main_account_id + version 唯一索引保证版本不重复
+func (svc *service) getLatestVersion(cts *rest.Contexts, mainAccountID string) (int, error) {
+	opt := &daotypes.ListOption{
+		Filter: tools.EqualExpression("main_account_id", mainAccountID),
+		Page:   &core.BasePage{Start: 0, Limit: 1, Sort: "version", Order: core.Descending},
+		Fields: []string{"version"},
+	}
+	result, err := svc.dao.AccountBillAllocationRule().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list latest allocation rule of main account %s failed, err: %v, rid: %s",
+			mainAccountID, err, cts.Kit.Rid)
+		return 0, err
+	}
+	if len(result.Details) == 0 {
+		return 0, nil
+	}
+	return result.Details[0].Version, nil
+}
+
+// ListBillAllocationRule list account bill allocation rule with options
+func (svc *service) ListBillAllocationRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsbill.BillAllocationRuleListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	data, err := svc.dao.AccountBillAllocationRule().List(cts.Kit, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]*billcore.AllocationRule, len(data.Details))
+	for idx := range data.Details {
+		rule, err := convAllocationRule(&data.Details[idx])
+		if err != nil {
+			logs.Errorf("convert allocation rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+		details[idx] = rule
+	}
+
+	return &dsbill.BillAllocationRuleListResult{Details: details, Count: data.Count}, nil
+}
+
+// DeleteBillAllocationRule delete account bill allocation rule with filter
+func (svc *service) DeleteBillAllocationRule(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	listResp, err := svc.dao.AccountBillAllocationRule().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("delete list account bill allocation rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("delete list account bill allocation rule failed, err: %v", err)
+	}
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+	delIDs := make([]string, len(listResp.Details))
+	for index, one := range listResp.Details {
+		delIDs[index] = one.ID
+	}
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		delFilter := tools.ContainersExpression("id", delIDs)
+		if err = svc.dao.AccountBillAllocationRule().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("delete account bill allocation rule failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func convAllocationRule(m *tablebill.AccountBillAllocationRule) (*billcore.AllocationRule, error) {
+	result := &billcore.AllocationRule{
+		ID:             m.ID,
+		RootAccountID:  m.RootAccountID,
+		MainAccountID:  m.MainAccountID,
+		Vendor:         m.Vendor,
+		Version:        m.Version,
+		EffectiveYear:  m.EffectiveYear,
+		EffectiveMonth: m.EffectiveMonth,
+		RuleType:       m.RuleType,
+		DefaultBkBizID: m.DefaultBkBizID,
+		Revision: core.Revision{
+			Creator:   m.Creator,
+			Reviser:   m.Reviser,
+			CreatedAt: m.CreatedAt.String(),
+			UpdatedAt: m.UpdatedAt.String(),
+		},
+	}
+	if len(m.Config) != 0 {
+		if err := json.UnmarshalFromString(string(m.Config), &result.Config); err != nil {
+			return nil, fmt.Errorf("unmarshal config of allocation rule %s failed, err: %v", m.ID, err)
+		}
+	}
+	if m.Enabled != nil {
+		result.Enabled = *m.Enabled
+	}
+	if m.Memo != nil {
+		result.Memo = *m.Memo
+	}
+	return result, nil
+}
@@ -0,0 +1,155 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billbudget
+
+import (
+	"fmt"
+	"reflect"
+
+	"hcm/pkg/api/core"
+	billcore "hcm/pkg/api/core/bill"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	daotypes "hcm/pkg/dal/dao/types"
+	tablebill "hcm/pkg/dal/table/bill"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/rest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BatchCreateBillAlert batch create account bill alert
+func (svc *service) BatchCreateBillAlert(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsbill.BatchBillAlertCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	idList, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		alertList := make([]*tablebill.AccountBillAlert, 0, len(req.Items))
+		for _, item := range req.Items {
+			alertList = append(alertList, &tablebill.AccountBillAlert{
+				AlertType:    item.AlertType,
+				BudgetID:     item.BudgetID,
+				ScopeType:    item.ScopeType,
+				ScopeID:      item.ScopeID,
+				Vendor:       item.Vendor,
+				BillYear:     item.BillYear,
+				BillMonth:    item.BillMonth,
+				BillDay:      item.BillDay,
+				Threshold:    item.Threshold,
+				Amount:       &types.Decimal{Decimal: item.Amount},
+				Cost:         &types.Decimal{Decimal: item.Cost},
+				ExpectedCost: &types.Decimal{Decimal: item.ExpectedCost},
+				Currency:     item.Currency,
+				Message:      item.Message,
+				Receivers:    item.Receivers,
+				NotifyState:  item.NotifyState,
+				DedupKey:     item.DedupKey,
+				Creator:      cts.Kit.User,
+				Reviser:      cts.Kit.User,
+			})
+		}
+
+		ids, err := svc.dao.AccountBillAlert().CreateWithTx(cts.Kit, txn, alertList)
+		if err != nil {
+			return nil, fmt.Errorf("create account bill alert failed, err: %v", err)
+		}
+		return ids, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	retList, ok := idList.([]string)
+	if !ok {
+		return nil, fmt.Errorf("create account bill alert but return ids type not []string, ids type: %v",
+			reflect.TypeOf(idList).String())
+	}
+
+	return &core.BatchCreateResult{IDs: retList}, nil
+}
+
+// ListBillAlert list account bill alert with options
+func (svc *service) ListBillAlert(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsbill.BillAlertListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	data, err := svc.dao.AccountBillAlert().List(cts.Kit, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]*billcore.Alert, len(data.Details))
+	for idx := range data.Details {
+		details[idx] = convAlert(&data.Details[idx])
+	}
+
+	return &dsbill.BillAlertListResult{Details: details, Count: data.Count}, nil
+}
+
+func convAlert(m *tablebill.AccountBillAlert) *billcore.Alert {
+	result := &billcore.Alert{
+		ID:          m.ID,
+		AlertType:   m.AlertType,
+		BudgetID:    m.BudgetID,
+		ScopeType:   m.ScopeType,
+		ScopeID:     m.ScopeID,
+		Vendor:      m.Vendor,
+		BillYear:    m.BillYear,
+		BillMonth:   m.BillMonth,
+		BillDay:     m.BillDay,
+		Threshold:   m.Threshold,
+		Currency:    m.Currency,
+		Message:     m.Message,
+		Receivers:   m.Receivers,
+		NotifyState: m.NotifyState,
+		DedupKey:    m.DedupKey,
+		Revision: core.Revision{
+			Creator:   m.Creator,
+			Reviser:   m.Reviser,
+			CreatedAt: m.CreatedAt.String(),
+			UpdatedAt: m.UpdatedAt.String(),
+		},
+	}
+	if m.Amount != nil {
+		result.Amount = m.Amount.Decimal
+	}
+	if m.Cost != nil {
+		result.Cost = m.Cost.Decimal
+	}
+	if m.ExpectedCost != nil {
+		result.ExpectedCost = m.ExpectedCost.Decimal
+	}
+	return result
+}
@@ -0,0 +1,213 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billbudget
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	billcore "hcm/pkg/api/core/bill"
+	dataservice "hcm/pkg/api/data-service"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	daotypes "hcm/pkg/dal/dao/types"
+	tablebill "hcm/pkg/dal/table/bill"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateBillBudget create account bill budget
+func (svc *service) CreateBillBudget(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsbill.BillBudgetCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	idList, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		budget := &tablebill.AccountBillBudget{
+			Name:       req.Name,
+			ScopeType:  req.ScopeType,
+			ScopeID:    req.ScopeID,
+			Vendor:     req.Vendor,
+			Amount:     &types.Decimal{Decimal: req.Amount},
+			Thresholds: req.Thresholds,
+			Receivers:  req.Receivers,
+			Enabled:    req.Enabled,
+			Memo:       req.Memo,
+			Creator:    cts.Kit.User,
+			Reviser:    cts.Kit.User,
+		}
+		ids, err := svc.dao.AccountBillBudget().CreateWithTx(cts.Kit, txn, []*tablebill.AccountBillBudget{budget})
+		if err != nil {
+			return nil, fmt.Errorf("create account bill budget failed, err: %v", err)
+		}
+		return ids, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	retList, ok := idList.([]string)
+	if !ok || len(retList) != 1 {
+		return nil, fmt.Errorf("create account bill budget but return ids invalid, ids: %v", idList)
+	}
+
+	return &core.CreateResult{ID: retList[0]}, nil
+}
+
+// UpdateBillBudget update account bill budget
+func (svc *service) UpdateBillBudget(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsbill.BillBudgetUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	budget := &tablebill.AccountBillBudget{
+		Name:       req.Name,
+		Vendor:     req.Vendor,
+		Thresholds: req.Thresholds,
+		Receivers:  req.Receivers,
+		Enabled:    req.Enabled,
+		Memo:       req.Memo,
+		Reviser:    cts.Kit.User,
+	}
+	if req.Amount != nil {
+		budget.Amount = &types.Decimal{Decimal: *req.Amount}
+	}
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.AccountBillBudget().UpdateByIDWithTx(cts.Kit, txn, req.ID, budget); err != nil {
+			return nil, fmt.Errorf("update account bill budget failed, err: %v", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListBillBudget list account bill budget with options
+func (svc *service) ListBillBudget(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsbill.BillBudgetListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	data, err := svc.dao.AccountBillBudget().List(cts.Kit, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]*billcore.Budget, len(data.Details))
+	for idx := range data.Details {
+		details[idx] = convBudget(&data.Details[idx])
+	}
+
+	return &dsbill.BillBudgetListResult{Details: details, Count: data.Count}, nil
+}
+
+// DeleteBillBudget delete account bill budget with filter
+func (svc *service) DeleteBillBudget(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &daotypes.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id"},
+	}
+	listResp, err := svc.dao.AccountBillBudget().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("delete list account bill budget failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("delete list account bill budget failed, err: %v", err)
+	}
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+	delIDs := make([]string, len(listResp.Details))
+	for index, one := range listResp.Details {
+		delIDs[index] = one.ID
+	}
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		delFilter := tools.ContainersExpression("id", delIDs)
+		if err = svc.dao.AccountBillBudget().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("delete account bill budget failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func convBudget(m *tablebill.AccountBillBudget) *billcore.Budget {
+	result := &billcore.Budget{
+		ID:         m.ID,
+		Name:       m.Name,
+		ScopeType:  m.ScopeType,
+		ScopeID:    m.ScopeID,
+		Vendor:     m.Vendor,
+		Thresholds: m.Thresholds,
+		Receivers:  m.Receivers,
+		Revision: core.Revision{
+			Creator:   m.Creator,
+			Reviser:   m.Reviser,
+			CreatedAt: m.CreatedAt.String(),
+			UpdatedAt: m.UpdatedAt.String(),
+		},
+	}
+	if m.Amount != nil {
+		result.Amount = m.Amount.Decimal
+	}
+	if m.Enabled != nil {
+		result.Enabled = *m.Enabled
+	}
+	if m.Memo != nil {
+		result.Memo = *m.Memo
+	}
+	return result
+}
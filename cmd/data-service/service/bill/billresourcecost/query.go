@@ -0,0 +1,126 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package billresourcecost
+
+import (
+	"hcm/pkg/api/core"
+	billcore "hcm/pkg/api/core/bill"
+	dsbill "hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	tablebill "hcm/pkg/dal/table/bill"
+	"hcm/pkg/rest"
+)
+
+// ListBillResourceCost list account bill resource cost with options
+func (svc *service) ListBillResourceCost(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsbill.BillResourceCostListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	data, err := svc.dao.AccountBillResourceCost().List(cts.Kit, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]*billcore.ResourceCost, len(data.Details))
+	for idx := range data.Details {
+		details[idx] = convResourceCost(&data.Details[idx])
+	}
+
+	return &dsbill.BillResourceCostListResult{Details: details, Count: data.Count}, nil
+}
+
+// ListBillResourceCostGroupByRes list account bill resource cost summed by resource, sorted by cost in descending
+// order by default
+func (svc *service) ListBillResourceCostGroupByRes(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsbill.BillResourceCostListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	data, err := svc.dao.AccountBillResourceCost().ListGroupByResource(cts.Kit, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]*billcore.ResourceCostSum, len(data.Details))
+	for idx, one := range data.Details {
+		details[idx] = &billcore.ResourceCostSum{
+			Vendor:     one.Vendor,
+			ResCloudID: one.ResCloudID,
+			ResType:    one.ResType,
+			ResID:      one.ResID,
+			Currency:   one.Currency,
+			Count:      one.Count,
+		}
+		if one.Cost != nil {
+			details[idx].Cost = one.Cost.Decimal
+		}
+	}
+
+	return &dsbill.BillResourceCostSumListResult{Details: details, Count: data.Count}, nil
+}
+
+func convResourceCost(m *tablebill.AccountBillResourceCost) *billcore.ResourceCost {
+	result := &billcore.ResourceCost{
+		ID:            m.ID,
+		RootAccountID: m.RootAccountID,
+		MainAccountID: m.MainAccountID,
+		Vendor:        m.Vendor,
+		ProductID:     m.ProductID,
+		BkBizID:       m.BkBizID,
+		BillYear:      m.BillYear,
+		BillMonth:     m.BillMonth,
+		BillDay:       m.BillDay,
+		VersionID:     m.VersionID,
+		ResCloudID:    m.ResCloudID,
+		ResType:       m.ResType,
+		ResID:         m.ResID,
+		Currency:      m.Currency,
+		Count:         m.Count,
+		Revision: core.Revision{
+			Creator:   m.Creator,
+			Reviser:   m.Reviser,
+			CreatedAt: m.CreatedAt.String(),
+			UpdatedAt: m.UpdatedAt.String(),
+		},
+	}
+	if m.Cost != nil {
+		result.Cost = m.Cost.Decimal
+	}
+	return result
+}
@@ -21,12 +21,14 @@ package billsummarymain
 
 import (
 	"hcm/pkg/api/core"
+	billcore "hcm/pkg/api/core/bill"
 	dataproto "hcm/pkg/api/data-service/bill"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/types"
 	tablebill "hcm/pkg/dal/table/bill"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
 )
 
 // ListBillSummaryMain list account bill summary main with options
@@ -87,6 +89,15 @@ func toProtoPullerResult(m *tablebill.AccountBillSummaryMain) *dataproto.BillSum
 		State:                     m.State,
 		CreatedAt:                 m.CreatedAt,
 		UpdatedAt:                 m.UpdatedAt,
+		CostForecast:              toCostForecast(m),
+		CurrencyCostForecast: billcore.CurrencyCostForecast{
+			ForecastMonthCost:    cvt.PtrToVal(m.ForecastMonthCost).Decimal,
+			ForecastMonthLower:   cvt.PtrToVal(m.ForecastMonthLower).Decimal,
+			ForecastMonthUpper:   cvt.PtrToVal(m.ForecastMonthUpper).Decimal,
+			ForecastQuarterCost:  cvt.PtrToVal(m.ForecastQuarterCost).Decimal,
+			ForecastQuarterLower: cvt.PtrToVal(m.ForecastQuarterLower).Decimal,
+			ForecastQuarterUpper: cvt.PtrToVal(m.ForecastQuarterUpper).Decimal,
+		},
 	}
 }
 
@@ -131,5 +142,17 @@ func toBizResult(m *tablebill.AccountBillSummaryMain) *dataproto.BillSummaryBizR
 		CurrentMonthRMBCost:       m.CurrentMonthRMBCost.Decimal,
 		AdjustmentCost:            m.AdjustmentCost.Decimal,
 		AdjustmentRMBCost:         m.AdjustmentRMBCost.Decimal,
+		CostForecast:              toCostForecast(m),
+	}
+}
+
+func toCostForecast(m *tablebill.AccountBillSummaryMain) billcore.CostForecast {
+	return billcore.CostForecast{
+		ForecastMonthRMBCost:    cvt.PtrToVal(m.ForecastMonthRMBCost).Decimal,
+		ForecastMonthRMBLower:   cvt.PtrToVal(m.ForecastMonthRMBLower).Decimal,
+		ForecastMonthRMBUpper:   cvt.PtrToVal(m.ForecastMonthRMBUpper).Decimal,
+		ForecastQuarterRMBCost:  cvt.PtrToVal(m.ForecastQuarterRMBCost).Decimal,
+		ForecastQuarterRMBLower: cvt.PtrToVal(m.ForecastQuarterRMBLower).Decimal,
+		ForecastQuarterRMBUpper: cvt.PtrToVal(m.ForecastQuarterRMBUpper).Decimal,
 	}
 }
@@ -81,6 +81,22 @@ func (svc *service) UpdateBillSummaryMain(cts *rest.Contexts) (interface{}, erro
 	if req.AdjustmentRMBCost != nil {
 		billSummaryMain.AdjustmentRMBCost = &types.Decimal{Decimal: *req.AdjustmentRMBCost}
 	}
+	if req.CostForecast != nil {
+		billSummaryMain.ForecastMonthRMBCost = &types.Decimal{Decimal: req.ForecastMonthRMBCost}
+		billSummaryMain.ForecastMonthRMBLower = &types.Decimal{Decimal: req.ForecastMonthRMBLower}
+		billSummaryMain.ForecastMonthRMBUpper = &types.Decimal{Decimal: req.ForecastMonthRMBUpper}
+		billSummaryMain.ForecastQuarterRMBCost = &types.Decimal{Decimal: req.ForecastQuarterRMBCost}
+		billSummaryMain.ForecastQuarterRMBLower = &types.Decimal{Decimal: req.ForecastQuarterRMBLower}
+		billSummaryMain.ForecastQuarterRMBUpper = &types.Decimal{Decimal: req.ForecastQuarterRMBUpper}
+	}
+	if req.CurrencyCostForecast != nil {
+		billSummaryMain.ForecastMonthCost = &types.Decimal{Decimal: req.ForecastMonthCost}
+		billSummaryMain.ForecastMonthLower = &types.Decimal{Decimal: req.ForecastMonthLower}
+		billSummaryMain.ForecastMonthUpper = &types.Decimal{Decimal: req.ForecastMonthUpper}
+		billSummaryMain.ForecastQuarterCost = &types.Decimal{Decimal: req.ForecastQuarterCost}
+		billSummaryMain.ForecastQuarterLower = &types.Decimal{Decimal: req.ForecastQuarterLower}
+		billSummaryMain.ForecastQuarterUpper = &types.Decimal{Decimal: req.ForecastQuarterUpper}
+	}
 	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		if err := svc.dao.AccountBillSummaryMain().UpdateByIDWithTx(
 			cts.Kit, txn, billSummaryMain.ID, billSummaryMain); err != nil {
@@ -26,6 +26,7 @@ import (
 	"hcm/pkg/dal/dao/types"
 	tablebill "hcm/pkg/dal/table/bill"
 	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
 )
 
 // ListBillSummaryRoot list bill summary daily with options
@@ -82,5 +83,21 @@ func toProtoPullerResult(m *tablebill.AccountBillSummaryRoot) *bill.SummaryRoot
 		ProductNum:                m.ProductNum,
 		CreatedAt:                 m.CreatedAt,
 		UpdatedAt:                 m.UpdatedAt,
+		CostForecast: bill.CostForecast{
+			ForecastMonthRMBCost:    cvt.PtrToVal(m.ForecastMonthRMBCost).Decimal,
+			ForecastMonthRMBLower:   cvt.PtrToVal(m.ForecastMonthRMBLower).Decimal,
+			ForecastMonthRMBUpper:   cvt.PtrToVal(m.ForecastMonthRMBUpper).Decimal,
+			ForecastQuarterRMBCost:  cvt.PtrToVal(m.ForecastQuarterRMBCost).Decimal,
+			ForecastQuarterRMBLower: cvt.PtrToVal(m.ForecastQuarterRMBLower).Decimal,
+			ForecastQuarterRMBUpper: cvt.PtrToVal(m.ForecastQuarterRMBUpper).Decimal,
+		},
+		CurrencyCostForecast: bill.CurrencyCostForecast{
+			ForecastMonthCost:    cvt.PtrToVal(m.ForecastMonthCost).Decimal,
+			ForecastMonthLower:   cvt.PtrToVal(m.ForecastMonthLower).Decimal,
+			ForecastMonthUpper:   cvt.PtrToVal(m.ForecastMonthUpper).Decimal,
+			ForecastQuarterCost:  cvt.PtrToVal(m.ForecastQuarterCost).Decimal,
+			ForecastQuarterLower: cvt.PtrToVal(m.ForecastQuarterLower).Decimal,
+			ForecastQuarterUpper: cvt.PtrToVal(m.ForecastQuarterUpper).Decimal,
+		},
 	}
 }
@@ -85,6 +85,22 @@ func (svc *service) UpdateBillSummaryRoot(cts *rest.Contexts) (interface{}, erro
 	if req.AdjustmentRMBCost != nil {
 		billSummaryRoot.AdjustmentRMBCost = &types.Decimal{Decimal: *req.AdjustmentRMBCost}
 	}
+	if req.CostForecast != nil {
+		billSummaryRoot.ForecastMonthRMBCost = &types.Decimal{Decimal: req.ForecastMonthRMBCost}
+		billSummaryRoot.ForecastMonthRMBLower = &types.Decimal{Decimal: req.ForecastMonthRMBLower}
+		billSummaryRoot.ForecastMonthRMBUpper = &types.Decimal{Decimal: req.ForecastMonthRMBUpper}
+		billSummaryRoot.ForecastQuarterRMBCost = &types.Decimal{Decimal: req.ForecastQuarterRMBCost}
+		billSummaryRoot.ForecastQuarterRMBLower = &types.Decimal{Decimal: req.ForecastQuarterRMBLower}
+		billSummaryRoot.ForecastQuarterRMBUpper = &types.Decimal{Decimal: req.ForecastQuarterRMBUpper}
+	}
+	if req.CurrencyCostForecast != nil {
+		billSummaryRoot.ForecastMonthCost = &types.Decimal{Decimal: req.ForecastMonthCost}
+		billSummaryRoot.ForecastMonthLower = &types.Decimal{Decimal: req.ForecastMonthLower}
+		billSummaryRoot.ForecastMonthUpper = &types.Decimal{Decimal: req.ForecastMonthUpper}
+		billSummaryRoot.ForecastQuarterCost = &types.Decimal{Decimal: req.ForecastQuarterCost}
+		billSummaryRoot.ForecastQuarterLower = &types.Decimal{Decimal: req.ForecastQuarterLower}
+		billSummaryRoot.ForecastQuarterUpper = &types.Decimal{Decimal: req.ForecastQuarterUpper}
+	}
 	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		if err := svc.dao.AccountBillSummaryRoot().UpdateByIDWithTx(
 			cts.Kit, txn, billSummaryRoot.ID, billSummaryRoot); err != nil {
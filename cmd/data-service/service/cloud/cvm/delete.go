@@ -24,6 +24,7 @@ import (
 
 	"hcm/pkg/api/core"
 	protocloud "hcm/pkg/api/data-service/cloud"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/orm"
 	"hcm/pkg/dal/dao/tools"
@@ -86,6 +87,10 @@ func (svc *cvmSvc) BatchDeleteCvm(cts *rest.Contexts) (interface{}, error) {
 			return nil, err
 		}
 
+		if err := svc.dao.ResTag().DeleteByResIDsWithTx(cts.Kit, txn, enumor.CvmCloudResType, delIDs); err != nil {
+			return nil, err
+		}
+
 		// delete cmdb cloud hosts
 		if err = deleteCmdbHosts(svc, cts.Kit, listResp.Details); err != nil {
 			logs.Errorf("delete cmdb hosts failed, err: %v, rid: %s", err, cts.Kit.Rid)
@@ -0,0 +1,308 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot ...
+package disksnapshot
+
+import (
+	"fmt"
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dataservice "hcm/pkg/api/data-service"
+	dssnapshot "hcm/pkg/api/data-service/cloud/disk-snapshot"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableaudit "hcm/pkg/dal/table/audit"
+	tablesnapshot "hcm/pkg/dal/table/cloud/disk-snapshot"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitService initial the disk snapshot service
+func InitService(cap *capability.Capability) {
+	svc := &service{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("BatchCreateDiskSnapshot", http.MethodPost, "/disk_snapshots/batch/create", svc.BatchCreate)
+	h.Add("BatchUpdateDiskSnapshot", http.MethodPatch, "/disk_snapshots/batch/update", svc.BatchUpdate)
+	h.Add("ListDiskSnapshot", http.MethodPost, "/disk_snapshots/list", svc.List)
+	h.Add("BatchDeleteDiskSnapshot", http.MethodDelete, "/disk_snapshots/batch", svc.BatchDelete)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	dao dao.Set
+}
+
+// BatchCreate disk snapshot.
+func (svc *service) BatchCreate(cts *rest.Contexts) (interface{}, error) {
+	req := new(dssnapshot.BatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]*tablesnapshot.DiskSnapshotTable, 0, len(req.Snapshots))
+	for _, one := range req.Snapshots {
+		extension, err := marshalExtension(one.Extension)
+		if err != nil {
+			return nil, err
+		}
+
+		models = append(models, &tablesnapshot.DiskSnapshotTable{
+			Vendor:           one.Vendor,
+			AccountID:        one.AccountID,
+			CloudID:          one.CloudID,
+			Name:             one.Name,
+			Region:           one.Region,
+			Zone:             one.Zone,
+			DiskID:           one.DiskID,
+			CloudDiskID:      one.CloudDiskID,
+			DiskSize:         one.DiskSize,
+			Status:           one.Status,
+			BkBizID:          one.BkBizID,
+			CloudCreatedTime: one.CloudCreatedTime,
+			Extension:        extension,
+			Memo:             one.Memo,
+			Creator:          cts.Kit.User,
+			Reviser:          cts.Kit.User,
+		})
+	}
+
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.DiskSnapshot().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.BatchCreateResult{IDs: ids.([]string)}, nil
+}
+
+// BatchUpdate disk snapshot.
+func (svc *service) BatchUpdate(cts *rest.Contexts) (interface{}, error) {
+	req := new(dssnapshot.BatchUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		for _, one := range req.Snapshots {
+			extension, err := marshalExtension(one.Extension)
+			if err != nil {
+				return nil, err
+			}
+
+			model := &tablesnapshot.DiskSnapshotTable{
+				Name:           one.Name,
+				DiskID:         one.DiskID,
+				DiskSize:       one.DiskSize,
+				Status:         one.Status,
+				BkBizID:        one.BkBizID,
+				BackupPolicyID: one.BackupPolicyID,
+				Extension:      extension,
+				Memo:           one.Memo,
+				Reviser:        cts.Kit.User,
+			}
+			if err = svc.dao.DiskSnapshot().UpdateByIDWithTx(cts.Kit, txn, one.ID, model); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	})
+	if err != nil {
+		logs.Errorf("batch update disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func marshalExtension(extension *coresnapshot.Extension) (tabletype.JsonField, error) {
+	if extension == nil {
+		return "", nil
+	}
+
+	data, err := json.MarshalToString(extension)
+	if err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return tabletype.JsonField(data), nil
+}
+
+// List disk snapshot.
+func (svc *service) List(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.DiskSnapshot().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	if req.Page.Count {
+		return &dssnapshot.ListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]coresnapshot.DiskSnapshot, 0, len(daoResp.Details))
+	for i := range daoResp.Details {
+		one, err := convTableToSnapshot(&daoResp.Details[i])
+		if err != nil {
+			logs.Errorf("convert disk snapshot failed, err: %v, id: %s, rid: %s", err, daoResp.Details[i].ID,
+				cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, *one)
+	}
+
+	return &dssnapshot.ListResult{Details: details}, nil
+}
+
+func convTableToSnapshot(one *tablesnapshot.DiskSnapshotTable) (*coresnapshot.DiskSnapshot, error) {
+	var extension *coresnapshot.Extension
+	if len(one.Extension) != 0 {
+		extension = new(coresnapshot.Extension)
+		if err := json.UnmarshalFromString(string(one.Extension), extension); err != nil {
+			return nil, fmt.Errorf("unmarshal disk snapshot extension failed, err: %v", err)
+		}
+	}
+
+	return &coresnapshot.DiskSnapshot{
+		ID:               one.ID,
+		Vendor:           one.Vendor,
+		AccountID:        one.AccountID,
+		CloudID:          one.CloudID,
+		Name:             one.Name,
+		Region:           one.Region,
+		Zone:             one.Zone,
+		DiskID:           one.DiskID,
+		CloudDiskID:      one.CloudDiskID,
+		DiskSize:         one.DiskSize,
+		Status:           one.Status,
+		BkBizID:          one.BkBizID,
+		BackupPolicyID:   one.BackupPolicyID,
+		CloudCreatedTime: one.CloudCreatedTime,
+		Extension:        extension,
+		Memo:             one.Memo,
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}, nil
+}
+
+// BatchDelete disk snapshot.
+func (svc *service) BatchDelete(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+	}
+	listResp, err := svc.dao.DiskSnapshot().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(listResp.Details))
+	audits := make([]*tableaudit.AuditTable, 0, len(listResp.Details))
+	for _, one := range listResp.Details {
+		ids = append(ids, one.ID)
+		audits = append(audits, &tableaudit.AuditTable{
+			ResID:      one.ID,
+			CloudResID: one.CloudID,
+			ResName:    one.Name,
+			ResType:    enumor.DiskSnapshotAuditResType,
+			Action:     enumor.Delete,
+			BkBizID:    one.BkBizID,
+			Vendor:     one.Vendor,
+			AccountID:  one.AccountID,
+			Operator:   cts.Kit.User,
+			Source:     cts.Kit.GetRequestSource(),
+			Rid:        cts.Kit.Rid,
+			AppCode:    cts.Kit.AppCode,
+			Detail:     &tableaudit.BasicDetail{Data: one},
+		})
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		delFilter := tools.ContainersExpression("id", ids)
+		if err := svc.dao.DiskSnapshot().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, audits)
+	})
+	if err != nil {
+		logs.Errorf("delete disk snapshot failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -21,6 +21,7 @@ package eip
 
 import (
 	dataproto "hcm/pkg/api/data-service/cloud/eip"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/orm"
 	"hcm/pkg/rest"
@@ -39,8 +40,17 @@ func (svc *eipSvc) BatchDeleteEip(cts *rest.Contexts) (interface{}, error) {
 		return nil, errf.NewFromErr(errf.InvalidParameter, err)
 	}
 
-	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
-		return nil, svc.dao.Eip().DeleteWithTx(cts.Kit, txn, req.Filter)
+	delIDs, err := svc.dao.Cloud().ListResourceIDs(cts.Kit, enumor.EipCloudResType, req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.Eip().DeleteWithTx(cts.Kit, txn, req.Filter); err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.ResTag().DeleteByResIDsWithTx(cts.Kit, txn, enumor.EipCloudResType, delIDs)
 	})
 	if err != nil {
 		return nil, err
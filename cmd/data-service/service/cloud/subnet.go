@@ -485,6 +485,11 @@ func (svc *subnetSvc) BatchDeleteSubnet(cts *rest.Contexts) (interface{}, error)
 			return nil, err
 		}
 
+		err := svc.dao.ResTag().DeleteByResIDsWithTx(cts.Kit, txn, enumor.SubnetCloudResType, delSubnetIDs)
+		if err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
@@ -40,6 +40,10 @@ func InitService(cap *capability.Capability) {
 	h.Add("BatchCreateAccountSD", http.MethodPost, "/account_sync_details/batch/create", svc.BatchCreateAccountSD)
 	h.Add("BatchUpdateAccountSD", http.MethodPatch, "/account_sync_details/batch/update", svc.BatchUpdateAccountSD)
 
+	h.Add("BatchCreateSyncRun", http.MethodPost, "/sync_runs/batch/create", svc.BatchCreateSyncRun)
+	h.Add("ListSyncRun", http.MethodPost, "/sync_runs/list", svc.ListSyncRun)
+	h.Add("DeleteExpiredSyncRun", http.MethodDelete, "/sync_runs/expired", svc.DeleteExpiredSyncRun)
+
 	h.Load(cap.WebService)
 }
 
@@ -0,0 +1,126 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coresync "hcm/pkg/api/core/cloud/sync"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	tablesync "hcm/pkg/dal/table/cloud/sync"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// BatchCreateSyncRun create sync run.
+func (svc *service) BatchCreateSyncRun(cts *rest.Contexts) (interface{}, error) {
+	req := new(dssync.SyncRunBatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]tablesync.SyncRunTable, 0, len(req.Items))
+	for _, item := range req.Items {
+		models = append(models, tablesync.SyncRunTable{
+			Vendor:      item.Vendor,
+			AccountID:   item.AccountID,
+			Region:      item.Region,
+			ResType:     item.ResType,
+			TriggerType: item.TriggerType,
+			Status:      item.Status,
+			CreateCount: item.CreateCount,
+			UpdateCount: item.UpdateCount,
+			DeleteCount: item.DeleteCount,
+			DurationMs:  item.DurationMs,
+			Reason:      item.Reason,
+			StartAt:     item.StartAt,
+			EndAt:       item.EndAt,
+			Creator:     cts.Kit.User,
+		})
+	}
+
+	ids, err := svc.dao.SyncRun().BatchCreate(cts.Kit, models)
+	if err != nil {
+		logs.Errorf("batch create sync run failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.BatchCreateResult{IDs: ids}, nil
+}
+
+// ListSyncRun list sync run.
+func (svc *service) ListSyncRun(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.SyncRun().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list sync run failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list sync run failed, err: %v", err)
+	}
+	if req.Page.Count {
+		return &dssync.SyncRunListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]coresync.SyncRun, 0, len(daoResp.Details))
+	for _, one := range daoResp.Details {
+		details = append(details, coresync.SyncRun(one))
+	}
+
+	return &dssync.SyncRunListResult{Details: details}, nil
+}
+
+// DeleteExpiredSyncRun delete sync run created before the given time.
+func (svc *service) DeleteExpiredSyncRun(cts *rest.Contexts) (interface{}, error) {
+	req := new(dssync.SyncRunDeleteExpiredReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	deleted, err := svc.dao.SyncRun().DeleteBefore(cts.Kit, req.Before, req.Limit)
+	if err != nil {
+		logs.Errorf("delete expired sync run failed, err: %v, before: %s, rid: %s", err, req.Before, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &dssync.SyncRunDeleteExpiredResult{DeletedCount: deleted}, nil
+}
@@ -0,0 +1,262 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tag
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
+	dstag "hcm/pkg/api/data-service/cloud/tag"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tabletag "hcm/pkg/dal/table/cloud/tag"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SyncResTag replace the tags of resources with the tags synced from cloud.
+func (svc *service) SyncResTag(cts *rest.Contexts) (interface{}, error) {
+	req := new(dstag.ResTagSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	cloudIDs := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		cloudIDs = append(cloudIDs, item.CloudID)
+	}
+
+	infos, err := svc.dao.Cloud().ListResourceBasicInfoByCloudIDs(cts.Kit, req.ResType, req.AccountID, cloudIDs)
+	if err != nil {
+		logs.Errorf("list %s by cloud ids failed, err: %v, rid: %s", req.ResType, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	resIDMap := make(map[string]string, len(infos))
+	for _, info := range infos {
+		resIDMap[info.CloudID] = info.ID
+	}
+
+	resIDs := make([]string, 0, len(infos))
+	models := make([]tabletag.ResTagTable, 0)
+	for _, item := range req.Items {
+		resID, exist := resIDMap[item.CloudID]
+		if !exist {
+			// 资源可能在同步标签前已被删除，忽略即可
+			logs.V(3).Infof("%s %s not found when sync tag, skip it, rid: %s", req.ResType, item.CloudID,
+				cts.Kit.Rid)
+			continue
+		}
+
+		resIDs = append(resIDs, resID)
+		for _, key := range item.Tags.Keys() {
+			models = append(models, tabletag.ResTagTable{
+				Vendor:     req.Vendor,
+				AccountID:  req.AccountID,
+				ResType:    req.ResType,
+				ResID:      resID,
+				ResCloudID: item.CloudID,
+				TagKey:     key,
+				TagValue:   item.Tags[key],
+				Creator:    cts.Kit.User,
+				Reviser:    cts.Kit.User,
+			})
+		}
+	}
+
+	if len(resIDs) == 0 {
+		return nil, nil
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		delFilter := tools.ExpressionAnd(
+			tools.RuleEqual("res_type", req.ResType),
+			tools.RuleIn("res_id", resIDs),
+		)
+		if err := svc.dao.ResTag().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			return nil, err
+		}
+
+		if len(models) == 0 {
+			return nil, nil
+		}
+
+		return svc.dao.ResTag().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("sync %s tag failed, err: %v, rid: %s", req.ResType, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchUpsertResTag add tags to resources, the value of the existing tag key will be overwritten.
+func (svc *service) BatchUpsertResTag(cts *rest.Contexts) (interface{}, error) {
+	req := new(dstag.ResTagBatchUpsertReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	infos, err := svc.dao.Cloud().ListResourceBasicInfo(cts.Kit, req.ResType, req.ResIDs, "id", "vendor",
+		"account_id", "cloud_id")
+	if err != nil {
+		logs.Errorf("list %s basic info failed, err: %v, ids: %v, rid: %s", req.ResType, err, req.ResIDs,
+			cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(infos) != len(req.ResIDs) {
+		return nil, errf.Newf(errf.RecordNotFound, "some %s of %v are not found", req.ResType, req.ResIDs)
+	}
+
+	keys := req.Tags.Keys()
+	models := make([]tabletag.ResTagTable, 0, len(infos)*len(keys))
+	for _, info := range infos {
+		for _, key := range keys {
+			models = append(models, tabletag.ResTagTable{
+				Vendor:     info.Vendor,
+				AccountID:  info.AccountID,
+				ResType:    req.ResType,
+				ResID:      info.ID,
+				ResCloudID: info.CloudID,
+				TagKey:     key,
+				TagValue:   req.Tags[key],
+				Creator:    cts.Kit.User,
+				Reviser:    cts.Kit.User,
+			})
+		}
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		delFilter := tools.ExpressionAnd(
+			tools.RuleEqual("res_type", req.ResType),
+			tools.RuleIn("res_id", req.ResIDs),
+			tools.RuleIn("tag_key", keys),
+		)
+		if err := svc.dao.ResTag().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			return nil, err
+		}
+
+		return svc.dao.ResTag().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("upsert %s tag failed, err: %v, rid: %s", req.ResType, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchDeleteResTag delete tags of resources.
+func (svc *service) BatchDeleteResTag(cts *rest.Contexts) (interface{}, error) {
+	req := new(dstag.ResTagBatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules := []*filter.AtomRule{
+		tools.RuleEqual("res_type", req.ResType),
+		tools.RuleIn("res_id", req.ResIDs),
+	}
+	if len(req.Keys) != 0 {
+		rules = append(rules, tools.RuleIn("tag_key", req.Keys))
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.ResTag().DeleteWithTx(cts.Kit, txn, tools.ExpressionAnd(rules...))
+	})
+	if err != nil {
+		logs.Errorf("delete %s tag failed, err: %v, ids: %v, rid: %s", req.ResType, err, req.ResIDs, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ListResTag list resource tag.
+func (svc *service) ListResTag(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.ResTag().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list resource tag failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, fmt.Errorf("list resource tag failed, err: %v", err)
+	}
+	if req.Page.Count {
+		return &dstag.ResTagListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]coretag.ResTag, 0, len(daoResp.Details))
+	for _, one := range daoResp.Details {
+		details = append(details, convResTag(one))
+	}
+
+	return &dstag.ResTagListResult{Details: details}, nil
+}
+
+func convResTag(one tabletag.ResTagTable) coretag.ResTag {
+	return coretag.ResTag{
+		ID:         one.ID,
+		Vendor:     one.Vendor,
+		AccountID:  one.AccountID,
+		ResType:    one.ResType,
+		ResID:      one.ResID,
+		ResCloudID: one.ResCloudID,
+		TagKey:     one.TagKey,
+		TagValue:   one.TagValue,
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+}
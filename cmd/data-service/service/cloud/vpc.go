@@ -43,6 +43,7 @@ import (
 	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/converter"
 	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -480,6 +481,11 @@ func (svc *vpcSvc) BatchDeleteVpc(cts *rest.Contexts) (interface{}, error) {
 		delVpcIDs[index] = one.ID
 	}
 
+	delSubnetIDs, err := svc.listVpcSubnetIDs(cts.Kit, delVpcIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		delVpcFilter := tools.ContainersExpression("id", delVpcIDs)
 		if err := svc.dao.Vpc().BatchDeleteWithTx(cts.Kit, txn, delVpcFilter); err != nil {
@@ -491,6 +497,15 @@ func (svc *vpcSvc) BatchDeleteVpc(cts *rest.Contexts) (interface{}, error) {
 			return nil, err
 		}
 
+		if err := svc.dao.ResTag().DeleteByResIDsWithTx(cts.Kit, txn, enumor.VpcCloudResType, delVpcIDs); err != nil {
+			return nil, err
+		}
+
+		err := svc.dao.ResTag().DeleteByResIDsWithTx(cts.Kit, txn, enumor.SubnetCloudResType, delSubnetIDs)
+		if err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	})
 	if err != nil {
@@ -501,6 +516,36 @@ func (svc *vpcSvc) BatchDeleteVpc(cts *rest.Contexts) (interface{}, error) {
 	return nil, nil
 }
 
+// listVpcSubnetIDs list ids of subnets which belong to the vpcs.
+func (svc *vpcSvc) listVpcSubnetIDs(kt *kit.Kit, vpcIDs []string) ([]string, error) {
+	ids := make([]string, 0)
+	for _, partIDs := range slice.Split(vpcIDs, int(filter.DefaultMaxInLimit)) {
+		opt := &types.ListOption{
+			Fields: []string{"id"},
+			Filter: tools.ContainersExpression("vpc_id", partIDs),
+			Page:   core.NewDefaultBasePage(),
+		}
+		for {
+			result, err := svc.dao.Subnet().List(kt, opt)
+			if err != nil {
+				logs.Errorf("list subnet by vpc ids failed, err: %v, vpc ids: %v, rid: %s", err, partIDs, kt.Rid)
+				return nil, err
+			}
+
+			for _, one := range result.Details {
+				ids = append(ids, one.ID)
+			}
+
+			if uint(len(result.Details)) < opt.Page.Limit {
+				break
+			}
+			opt.Page.Start += uint32(opt.Page.Limit)
+		}
+	}
+
+	return ids, nil
+}
+
 // ListVpcExt ...
 func (svc *vpcSvc) ListVpcExt(cts *rest.Contexts) (interface{}, error) {
 	vendor := enumor.Vendor(cts.Request.PathParameter("vendor"))
@@ -0,0 +1,210 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package diskbackup
+
+import (
+	"time"
+
+	"hcm/pkg/api/core"
+	corebackup "hcm/pkg/api/core/disk-backup"
+	dsbackup "hcm/pkg/api/data-service/disk-backup"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tablebackup "hcm/pkg/dal/table/disk-backup"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+)
+
+// CreateRun create a running disk backup run which starts now.
+func (svc *service) CreateRun(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsbackup.RunCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tablebackup.DiskBackupRunTable{
+		PolicyID:    req.PolicyID,
+		BkBizID:     req.BkBizID,
+		TriggerType: req.TriggerType,
+		State:       enumor.DiskBackupRunning,
+		Alerted:     cvt.ValToPtr(false),
+		Rid:         cts.Kit.Rid,
+		StartAt:     time.Now(),
+		Creator:     cts.Kit.User,
+		Reviser:     cts.Kit.User,
+	}
+	id, err := svc.dao.DiskBackupRun().Create(cts.Kit, model)
+	if err != nil {
+		logs.Errorf("create disk backup run failed, err: %v, policy: %s, rid: %s", err, req.PolicyID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: id}, nil
+}
+
+// UpdateRun update disk backup run.
+func (svc *service) UpdateRun(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(dsbackup.RunUpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tablebackup.DiskBackupRunTable{
+		State:        req.State,
+		TargetCount:  req.TargetCount,
+		SuccessCount: req.SuccessCount,
+		FailedCount:  req.FailedCount,
+		PrunedCount:  req.PrunedCount,
+		Reason:       req.Reason,
+		Alerted:      req.Alerted,
+		Reviser:      cts.Kit.User,
+	}
+
+	if req.FailedDetails != nil {
+		details, err := tabletype.NewJsonField(req.FailedDetails)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+		model.FailedDetails = details
+	}
+
+	if req.Finished {
+		run, err := svc.getRun(cts.Kit, id)
+		if err != nil {
+			return nil, err
+		}
+		model.DurationMs = uint64(time.Since(run.StartAt).Milliseconds())
+	}
+
+	if err := svc.dao.DiskBackupRun().UpdateByID(cts.Kit, id, model); err != nil {
+		logs.Errorf("update disk backup run failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *service) getRun(kt *kit.Kit, id string) (*tablebackup.DiskBackupRunTable, error) {
+	opt := &types.ListOption{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.DiskBackupRun().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list disk backup run failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk backup run %s not found", id)
+	}
+
+	return &result.Details[0], nil
+}
+
+// ListRun list disk backup run.
+func (svc *service) ListRun(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.DiskBackupRun().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk backup run failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	if req.Page.Count {
+		return &dsbackup.RunListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]corebackup.DiskBackupRun, 0, len(daoResp.Details))
+	for i := range daoResp.Details {
+		run, err := convTableToRun(&daoResp.Details[i])
+		if err != nil {
+			logs.Errorf("convert disk backup run failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, *run)
+	}
+
+	return &dsbackup.RunListResult{Details: details}, nil
+}
+
+func convTableToRun(one *tablebackup.DiskBackupRunTable) (*corebackup.DiskBackupRun, error) {
+	run := &corebackup.DiskBackupRun{
+		ID:           one.ID,
+		PolicyID:     one.PolicyID,
+		BkBizID:      one.BkBizID,
+		TriggerType:  one.TriggerType,
+		State:        one.State,
+		TargetCount:  cvt.PtrToVal(one.TargetCount),
+		SuccessCount: cvt.PtrToVal(one.SuccessCount),
+		FailedCount:  cvt.PtrToVal(one.FailedCount),
+		PrunedCount:  cvt.PtrToVal(one.PrunedCount),
+		Reason:       cvt.PtrToVal(one.Reason),
+		Alerted:      cvt.PtrToVal(one.Alerted),
+		Rid:          one.Rid,
+		StartAt:      one.StartAt.Format(time.RFC3339),
+		DurationMs:   one.DurationMs,
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+
+	if len(one.FailedDetails) != 0 {
+		if err := json.UnmarshalFromString(string(one.FailedDetails), &run.FailedDetails); err != nil {
+			return nil, err
+		}
+	}
+
+	return run, nil
+}
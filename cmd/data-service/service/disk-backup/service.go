@@ -0,0 +1,322 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package diskbackup ...
+package diskbackup
+
+import (
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	corebackup "hcm/pkg/api/core/disk-backup"
+	dataservice "hcm/pkg/api/data-service"
+	dsbackup "hcm/pkg/api/data-service/disk-backup"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableaudit "hcm/pkg/dal/table/audit"
+	tablebackup "hcm/pkg/dal/table/disk-backup"
+	tabletype "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitService initial the disk backup policy service
+func InitService(cap *capability.Capability) {
+	svc := &service{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateDiskBackupPolicy", http.MethodPost, "/disk_backup_policies/create", svc.Create)
+	h.Add("UpdateDiskBackupPolicy", http.MethodPatch, "/disk_backup_policies/{id}", svc.Update)
+	h.Add("ListDiskBackupPolicy", http.MethodPost, "/disk_backup_policies/list", svc.List)
+	h.Add("BatchDeleteDiskBackupPolicy", http.MethodDelete, "/disk_backup_policies/batch", svc.BatchDelete)
+
+	h.Add("CreateDiskBackupRun", http.MethodPost, "/disk_backup_runs/create", svc.CreateRun)
+	h.Add("UpdateDiskBackupRun", http.MethodPatch, "/disk_backup_runs/{id}", svc.UpdateRun)
+	h.Add("ListDiskBackupRun", http.MethodPost, "/disk_backup_runs/list", svc.ListRun)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	dao dao.Set
+}
+
+// Create disk backup policy.
+func (svc *service) Create(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsbackup.CreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	target, err := tabletype.NewJsonField(req.Target)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tablebackup.DiskBackupPolicyTable{
+		Name:           req.Name,
+		BkBizID:        req.BkBizID,
+		Spec:           req.Spec,
+		RetentionCount: req.RetentionCount,
+		Target:         target,
+		AlertReceivers: req.AlertReceivers,
+		Enabled:        req.Enabled,
+		Memo:           req.Memo,
+		Creator:        cts.Kit.User,
+		Reviser:        cts.Kit.User,
+	}
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.DiskBackupPolicy().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create disk backup policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: id.(string)}, nil
+}
+
+// Update disk backup policy.
+func (svc *service) Update(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(dsbackup.UpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	policy, err := svc.getPolicy(cts.Kit, id)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &tablebackup.DiskBackupPolicyTable{
+		Name:            req.Name,
+		Spec:            req.Spec,
+		RetentionCount:  cvt.PtrToVal(req.RetentionCount),
+		AlertReceivers:  req.AlertReceivers,
+		Enabled:         req.Enabled,
+		ScheduledFlowID: req.ScheduledFlowID,
+		Memo:            req.Memo,
+		Reviser:         cts.Kit.User,
+	}
+	if req.Target != nil {
+		if model.Target, err = tabletype.NewJsonField(req.Target); err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.DiskBackupPolicy().UpdateByIDWithTx(cts.Kit, txn, id, model); err != nil {
+			return nil, err
+		}
+
+		auditInfo := &tableaudit.AuditTable{
+			ResID:    policy.ID,
+			ResName:  policy.Name,
+			ResType:  enumor.DiskBackupPolicyAuditResType,
+			BkBizID:  policy.BkBizID,
+			Action:   enumor.Update,
+			Operator: cts.Kit.User,
+			Source:   cts.Kit.GetRequestSource(),
+			Rid:      cts.Kit.Rid,
+			AppCode:  cts.Kit.AppCode,
+			Detail:   &tableaudit.BasicDetail{Data: policy, Changed: req},
+		}
+		return nil, svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, []*tableaudit.AuditTable{auditInfo})
+	})
+	if err != nil {
+		logs.Errorf("update disk backup policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *service) getPolicy(kt *kit.Kit, id string) (*tablebackup.DiskBackupPolicyTable, error) {
+	opt := &types.ListOption{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.DiskBackupPolicy().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list disk backup policy failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk backup policy %s not found", id)
+	}
+
+	return &result.Details[0], nil
+}
+
+// List disk backup policy.
+func (svc *service) List(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.DiskBackupPolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk backup policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	if req.Page.Count {
+		return &dsbackup.ListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]corebackup.DiskBackupPolicy, 0, len(daoResp.Details))
+	for i := range daoResp.Details {
+		policy, err := convTableToPolicy(&daoResp.Details[i])
+		if err != nil {
+			logs.Errorf("convert disk backup policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+			return nil, err
+		}
+		details = append(details, *policy)
+	}
+
+	return &dsbackup.ListResult{Details: details}, nil
+}
+
+func convTableToPolicy(one *tablebackup.DiskBackupPolicyTable) (*corebackup.DiskBackupPolicy, error) {
+	policy := &corebackup.DiskBackupPolicy{
+		ID:              one.ID,
+		Name:            one.Name,
+		BkBizID:         one.BkBizID,
+		Spec:            one.Spec,
+		RetentionCount:  one.RetentionCount,
+		AlertReceivers:  one.AlertReceivers,
+		Enabled:         cvt.PtrToVal(one.Enabled),
+		ScheduledFlowID: one.ScheduledFlowID,
+		Memo:            one.Memo,
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+
+	if len(one.Target) != 0 {
+		if err := json.UnmarshalFromString(string(one.Target), &policy.Target); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
+
+// BatchDelete disk backup policy, the runs of policy are deleted together.
+func (svc *service) BatchDelete(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+	}
+	listResp, err := svc.dao.DiskBackupPolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list disk backup policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(listResp.Details))
+	audits := make([]*tableaudit.AuditTable, 0, len(listResp.Details))
+	for _, one := range listResp.Details {
+		ids = append(ids, one.ID)
+		audits = append(audits, &tableaudit.AuditTable{
+			ResID:    one.ID,
+			ResName:  one.Name,
+			ResType:  enumor.DiskBackupPolicyAuditResType,
+			BkBizID:  one.BkBizID,
+			Action:   enumor.Delete,
+			Operator: cts.Kit.User,
+			Source:   cts.Kit.GetRequestSource(),
+			Rid:      cts.Kit.Rid,
+			AppCode:  cts.Kit.AppCode,
+			Detail:   &tableaudit.BasicDetail{Data: one},
+		})
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.DiskBackupRun().DeleteWithTx(cts.Kit, txn,
+			tools.ContainersExpression("policy_id", ids)); err != nil {
+			return nil, err
+		}
+
+		if err := svc.dao.DiskBackupPolicy().DeleteWithTx(cts.Kit, txn,
+			tools.ContainersExpression("id", ids)); err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, audits)
+	})
+	if err != nil {
+		logs.Errorf("delete disk backup policy failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,180 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package ipam ...
+package ipam
+
+import (
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	coreipam "hcm/pkg/api/core/ipam"
+	dataservice "hcm/pkg/api/data-service"
+	dsipam "hcm/pkg/api/data-service/ipam"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/types"
+	tableipam "hcm/pkg/dal/table/ipam"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/cidr"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitService initial the ipam block service
+func InitService(cap *capability.Capability) {
+	svc := &service{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("BatchCreateIPAMBlock", http.MethodPost, "/ipam_blocks/batch/create", svc.BatchCreate)
+	h.Add("ListIPAMBlock", http.MethodPost, "/ipam_blocks/list", svc.List)
+	h.Add("BatchDeleteIPAMBlock", http.MethodDelete, "/ipam_blocks/batch", svc.BatchDelete)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	dao dao.Set
+}
+
+// BatchCreate ipam block.
+func (svc *service) BatchCreate(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsipam.BatchCreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	models := make([]tableipam.IPAMBlockTable, 0, len(req.Blocks))
+	for _, one := range req.Blocks {
+		ipVersion, err := cidr.CidrIPAddressType(one.Cidr)
+		if err != nil {
+			return nil, errf.NewFromErr(errf.InvalidParameter, err)
+		}
+
+		models = append(models, tableipam.IPAMBlockTable{
+			Type:      one.Type,
+			ResID:     one.ResID,
+			Vendor:    one.Vendor,
+			AccountID: one.AccountID,
+			Region:    one.Region,
+			VpcID:     one.VpcID,
+			BkCloudID: one.BkCloudID,
+			BkBizID:   one.BkBizID,
+			Cidr:      one.Cidr,
+			IPVersion: ipVersion,
+			Memo:      one.Memo,
+			Creator:   cts.Kit.User,
+			Reviser:   cts.Kit.User,
+		})
+	}
+
+	ids, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.IPAMBlock().BatchCreateWithTx(cts.Kit, txn, models)
+	})
+	if err != nil {
+		logs.Errorf("batch create ipam block failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.BatchCreateResult{IDs: ids.([]string)}, nil
+}
+
+// List ipam block.
+func (svc *service) List(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.IPAMBlock().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list ipam block failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	if req.Page.Count {
+		return &dsipam.ListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]coreipam.Block, 0, len(daoResp.Details))
+	for _, one := range daoResp.Details {
+		details = append(details, coreipam.Block{
+			ID:        one.ID,
+			Type:      one.Type,
+			ResID:     one.ResID,
+			Vendor:    one.Vendor,
+			AccountID: one.AccountID,
+			Region:    one.Region,
+			VpcID:     one.VpcID,
+			BkCloudID: one.BkCloudID,
+			BkBizID:   one.BkBizID,
+			Cidr:      one.Cidr,
+			IPVersion: one.IPVersion,
+			Memo:      one.Memo,
+			Revision: core.Revision{
+				Creator:   one.Creator,
+				Reviser:   one.Reviser,
+				CreatedAt: one.CreatedAt.String(),
+				UpdatedAt: one.UpdatedAt.String(),
+			},
+		})
+	}
+
+	return &dsipam.ListResult{Details: details}, nil
+}
+
+// BatchDelete ipam block.
+func (svc *service) BatchDelete(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	_, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, svc.dao.IPAMBlock().DeleteWithTx(cts.Kit, txn, req.Filter)
+	})
+	if err != nil {
+		logs.Errorf("delete ipam block failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,162 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package lock ...
+package lock
+
+import (
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	corelock "hcm/pkg/api/core/lock"
+	dslock "hcm/pkg/api/data-service/lock"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the distributed lock service
+func InitService(cap *capability.Capability) {
+	svc := &service{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("AcquireDistributedLock", http.MethodPost, "/distributed_locks/acquire", svc.Acquire)
+	h.Add("RenewDistributedLock", http.MethodPost, "/distributed_locks/renew", svc.Renew)
+	h.Add("ReleaseDistributedLock", http.MethodPost, "/distributed_locks/release", svc.Release)
+	h.Add("ForceReleaseDistributedLock", http.MethodPost, "/distributed_locks/force_release", svc.ForceRelease)
+	h.Add("ListDistributedLock", http.MethodPost, "/distributed_locks/list", svc.List)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	dao dao.Set
+}
+
+// Acquire distributed lock.
+func (svc *service) Acquire(cts *rest.Contexts) (interface{}, error) {
+	req := new(dslock.AcquireReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model, err := svc.dao.DistributedLock().Acquire(cts.Kit, req.Key, req.Owner, req.TTLSec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corelock.LockInfo{
+		Key:        model.LockKey,
+		Owner:      model.Owner,
+		Token:      model.Token,
+		AcquiredAt: model.AcquiredAt,
+		ExpireAt:   model.ExpireAt,
+	}, nil
+}
+
+// Renew distributed lock.
+func (svc *service) Renew(cts *rest.Contexts) (interface{}, error) {
+	req := new(dslock.RenewReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, svc.dao.DistributedLock().Renew(cts.Kit, req.Key, req.Token, req.TTLSec)
+}
+
+// Release distributed lock.
+func (svc *service) Release(cts *rest.Contexts) (interface{}, error) {
+	req := new(dslock.ReleaseReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, svc.dao.DistributedLock().Release(cts.Kit, req.Key, req.Token)
+}
+
+// ForceRelease distributed lock.
+func (svc *service) ForceRelease(cts *rest.Contexts) (interface{}, error) {
+	req := new(dslock.ForceReleaseReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, svc.dao.DistributedLock().ForceRelease(cts.Kit, req.Key)
+}
+
+// List distributed lock.
+func (svc *service) List(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.DistributedLock().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list distributed lock failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	if req.Page.Count {
+		return &dslock.ListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]corelock.LockInfo, 0, len(daoResp.Details))
+	for _, one := range daoResp.Details {
+		details = append(details, corelock.LockInfo{
+			Key:        one.LockKey,
+			Owner:      one.Owner,
+			Token:      one.Token,
+			AcquiredAt: one.AcquiredAt,
+			ExpireAt:   one.ExpireAt,
+		})
+	}
+
+	return &dslock.ListResult{Details: details}, nil
+}
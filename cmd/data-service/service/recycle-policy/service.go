@@ -0,0 +1,294 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package recyclepolicy ...
+package recyclepolicy
+
+import (
+	"net/http"
+
+	"hcm/cmd/data-service/service/capability"
+	"hcm/pkg/api/core"
+	corerp "hcm/pkg/api/core/recycle-policy"
+	dataservice "hcm/pkg/api/data-service"
+	dsrp "hcm/pkg/api/data-service/recycle-policy"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableaudit "hcm/pkg/dal/table/audit"
+	tablerp "hcm/pkg/dal/table/recycle-policy"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	cvt "hcm/pkg/tools/converter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InitService initial the recycle policy service
+func InitService(cap *capability.Capability) {
+	svc := &service{
+		dao: cap.Dao,
+	}
+
+	h := rest.NewHandler()
+
+	h.Add("CreateRecyclePolicy", http.MethodPost, "/recycle_policies/create", svc.Create)
+	h.Add("UpdateRecyclePolicy", http.MethodPatch, "/recycle_policies/{id}", svc.Update)
+	h.Add("ListRecyclePolicy", http.MethodPost, "/recycle_policies/list", svc.List)
+	h.Add("BatchDeleteRecyclePolicy", http.MethodDelete, "/recycle_policies/batch", svc.BatchDelete)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	dao dao.Set
+}
+
+// Create recycle policy.
+func (svc *service) Create(cts *rest.Contexts) (interface{}, error) {
+	req := new(dsrp.CreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tablerp.RecyclePolicyTable{
+		BkBizID:               req.BkBizID,
+		ResType:               req.ResType,
+		RetentionHour:         cvt.ValToPtr(req.RetentionHour),
+		DetachBeforeRecycle:   cvt.ValToPtr(req.DetachBeforeRecycle),
+		SnapshotBeforeDestroy: cvt.ValToPtr(req.SnapshotBeforeDestroy),
+		NotifyBeforeHour:      cvt.ValToPtr(req.NotifyBeforeHour),
+		Enabled:               req.Enabled,
+		Memo:                  req.Memo,
+		Creator:               cts.Kit.User,
+		Reviser:               cts.Kit.User,
+	}
+	id, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return svc.dao.RecyclePolicy().CreateWithTx(cts.Kit, txn, model)
+	})
+	if err != nil {
+		logs.Errorf("create recycle policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: id.(string)}, nil
+}
+
+// Update recycle policy.
+func (svc *service) Update(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	req := new(dsrp.UpdateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	policy, err := svc.getPolicy(cts.Kit, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = req.ValidateWith(convTableToPolicy(policy)); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	model := &tablerp.RecyclePolicyTable{
+		RetentionHour:         req.RetentionHour,
+		DetachBeforeRecycle:   req.DetachBeforeRecycle,
+		SnapshotBeforeDestroy: req.SnapshotBeforeDestroy,
+		NotifyBeforeHour:      req.NotifyBeforeHour,
+		Enabled:               req.Enabled,
+		Memo:                  req.Memo,
+		Reviser:               cts.Kit.User,
+	}
+	if req.Memo == nil {
+		model.Memo = policy.Memo
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := svc.dao.RecyclePolicy().UpdateByIDWithTx(cts.Kit, txn, id, model); err != nil {
+			return nil, err
+		}
+
+		auditInfo := &tableaudit.AuditTable{
+			ResID:    policy.ID,
+			ResName:  string(policy.ResType),
+			ResType:  enumor.RecyclePolicyAuditResType,
+			BkBizID:  policy.BkBizID,
+			Action:   enumor.Update,
+			Operator: cts.Kit.User,
+			Source:   cts.Kit.GetRequestSource(),
+			Rid:      cts.Kit.Rid,
+			AppCode:  cts.Kit.AppCode,
+			Detail:   &tableaudit.BasicDetail{Data: policy, Changed: req},
+		}
+		return nil, svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, []*tableaudit.AuditTable{auditInfo})
+	})
+	if err != nil {
+		logs.Errorf("update recycle policy failed, err: %v, id: %s, rid: %s", err, id, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (svc *service) getPolicy(kt *kit.Kit, id string) (*tablerp.RecyclePolicyTable, error) {
+	opt := &types.ListOption{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.RecyclePolicy().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "recycle policy %s not found", id)
+	}
+
+	return &result.Details[0], nil
+}
+
+// List recycle policy.
+func (svc *service) List(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   req.Page,
+		Fields: req.Fields,
+	}
+	daoResp, err := svc.dao.RecyclePolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+	if req.Page.Count {
+		return &dsrp.ListResult{Count: daoResp.Count}, nil
+	}
+
+	details := make([]corerp.RecyclePolicy, 0, len(daoResp.Details))
+	for i := range daoResp.Details {
+		details = append(details, *convTableToPolicy(&daoResp.Details[i]))
+	}
+
+	return &dsrp.ListResult{Details: details}, nil
+}
+
+func convTableToPolicy(one *tablerp.RecyclePolicyTable) *corerp.RecyclePolicy {
+	return &corerp.RecyclePolicy{
+		ID:                    one.ID,
+		BkBizID:               one.BkBizID,
+		ResType:               one.ResType,
+		RetentionHour:         cvt.PtrToVal(one.RetentionHour),
+		DetachBeforeRecycle:   cvt.PtrToVal(one.DetachBeforeRecycle),
+		SnapshotBeforeDestroy: cvt.PtrToVal(one.SnapshotBeforeDestroy),
+		NotifyBeforeHour:      cvt.PtrToVal(one.NotifyBeforeHour),
+		Enabled:               cvt.PtrToVal(one.Enabled),
+		Memo:                  one.Memo,
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt.String(),
+			UpdatedAt: one.UpdatedAt.String(),
+		},
+	}
+}
+
+// BatchDelete recycle policy.
+func (svc *service) BatchDelete(cts *rest.Contexts) (interface{}, error) {
+	req := new(dataservice.BatchDeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	opt := &types.ListOption{
+		Filter: req.Filter,
+		Page:   core.NewDefaultBasePage(),
+	}
+	listResp, err := svc.dao.RecyclePolicy().List(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("list recycle policy failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if len(listResp.Details) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(listResp.Details))
+	audits := make([]*tableaudit.AuditTable, 0, len(listResp.Details))
+	for _, one := range listResp.Details {
+		ids = append(ids, one.ID)
+		audits = append(audits, &tableaudit.AuditTable{
+			ResID:    one.ID,
+			ResName:  string(one.ResType),
+			ResType:  enumor.RecyclePolicyAuditResType,
+			BkBizID:  one.BkBizID,
+			Action:   enumor.Delete,
+			Operator: cts.Kit.User,
+			Source:   cts.Kit.GetRequestSource(),
+			Rid:      cts.Kit.Rid,
+			AppCode:  cts.Kit.AppCode,
+			Detail:   &tableaudit.BasicDetail{Data: one},
+		})
+	}
+
+	_, err = svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		delFilter := tools.ContainersExpression("id", ids)
+		if err := svc.dao.RecyclePolicy().DeleteWithTx(cts.Kit, txn, delFilter); err != nil {
+			return nil, err
+		}
+
+		return nil, svc.dao.Audit().BatchCreateWithTx(cts.Kit, txn, audits)
+	})
+	if err != nil {
+		logs.Errorf("delete recycle policy failed, err: %v, ids: %v, rid: %s", err, ids, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
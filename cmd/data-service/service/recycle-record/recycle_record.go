@@ -25,8 +25,10 @@ import (
 
 	"hcm/cmd/data-service/service/capability"
 	"hcm/pkg/api/core"
+	corerp "hcm/pkg/api/core/recycle-policy"
 	protocore "hcm/pkg/api/core/recycle-record"
 	protodata "hcm/pkg/api/data-service/recycle-record"
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao"
@@ -39,6 +41,8 @@ import (
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	cvt "hcm/pkg/tools/converter"
 	"hcm/pkg/tools/json"
 	"hcm/pkg/tools/times"
 
@@ -91,6 +95,11 @@ func (svc *recycleRecordSvc) BatchRecycleCloudResource(cts *rest.Contexts) (inte
 		return nil, errf.Newf(errf.InvalidParameter, "recycle resource count is invalid")
 	}
 
+	policies, err := svc.listRecyclePolicy(cts.Kit, req.ResType)
+	if err != nil {
+		return nil, err
+	}
+
 	taskID, err := svc.dao.Txn().AutoTxn(cts.Kit, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
 		recycleRecords := make([]prototable.RecycleRecordTable, 0, len(resourceInfo))
 		for idx, info := range resourceInfo {
@@ -104,11 +113,8 @@ func (svc *recycleRecordSvc) BatchRecycleCloudResource(cts *rest.Contexts) (inte
 				return nil, errf.NewFromErr(errf.InvalidParameter, err)
 			}
 
-			recycleReserveTime := req.DefaultRecycleTime
-			// TODO: 将默认时间修改放到cloud-server中去做
-			if accountInfo.Details[0].RecycleReserveTime > -1 {
-				recycleReserveTime = uint(accountInfo.Details[0].RecycleReserveTime)
-			}
+			recycleReserveTime := getRecycleReserveTime(req.DefaultRecycleTime, policies, info.BkBizID,
+				accountInfo.Details[0].RecycleReserveTime)
 			recycleRecords = append(recycleRecords, prototable.RecycleRecordTable{
 				RecycleType: req.RecycleType,
 				Vendor:      info.Vendor,
@@ -145,6 +151,53 @@ func (svc *recycleRecordSvc) BatchRecycleCloudResource(cts *rest.Contexts) (inte
 	return taskID, nil
 }
 
+func (svc *recycleRecordSvc) listRecyclePolicy(kt *kit.Kit, resType enumor.CloudResourceType) (
+	[]corerp.RecyclePolicy, error) {
+
+	opt := &types.ListOption{
+		Filter: tools.EqualWithOpExpression(filter.And, map[string]interface{}{"res_type": resType, "enabled": true}),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.dao.RecyclePolicy().List(kt, opt)
+	if err != nil {
+		logs.Errorf("list %s recycle policy failed, err: %v, rid: %s", resType, err, kt.Rid)
+		return nil, err
+	}
+
+	policies := make([]corerp.RecyclePolicy, 0, len(result.Details))
+	for _, one := range result.Details {
+		policies = append(policies, corerp.RecyclePolicy{
+			ID:            one.ID,
+			BkBizID:       one.BkBizID,
+			ResType:       one.ResType,
+			RetentionHour: cvt.PtrToVal(one.RetentionHour),
+			Enabled:       cvt.PtrToVal(one.Enabled),
+		})
+	}
+
+	return policies, nil
+}
+
+// getRecycleReserveTime 获取资源在回收站中的保留时长，优先级：业务回收策略 > 账号回收站配置 > 所有业务的默认回收策略 > 全局配置
+func getRecycleReserveTime(defaultTime uint, policies []corerp.RecyclePolicy, bizID int64,
+	accountReserveTime int) uint {
+
+	policy := corerp.PickPolicy(policies, bizID)
+	if policy != nil && policy.BkBizID != constant.AttachedAllBiz {
+		return policy.RetentionHour
+	}
+
+	if accountReserveTime > -1 {
+		return uint(accountReserveTime)
+	}
+
+	if policy != nil {
+		return policy.RetentionHour
+	}
+
+	return defaultTime
+}
+
 func (svc *recycleRecordSvc) checkAndGetAccount(kt *kit.Kit, info protodao.RecycleResourceInfo) (
 	*types.ListAccountDetails, error) {
 
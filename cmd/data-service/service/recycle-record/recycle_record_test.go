@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recyclerecord
+
+import (
+	"testing"
+
+	corerp "hcm/pkg/api/core/recycle-policy"
+	"hcm/pkg/criteria/constant"
+)
+
+func TestGetRecycleReserveTime(t *testing.T) {
+	policies := []corerp.RecyclePolicy{
+		{BkBizID: constant.AttachedAllBiz, RetentionHour: 72, Enabled: true},
+		{BkBizID: 100, RetentionHour: 24, Enabled: true},
+		{BkBizID: 200, RetentionHour: 12, Enabled: false},
+	}
+
+	cases := []struct {
+		name           string
+		policies       []corerp.RecyclePolicy
+		bizID          int64
+		accountReserve int
+		expect         uint
+	}{
+		{"biz policy prior to account", policies, 100, 48, 24},
+		{"account prior to default policy", policies, 300, 48, 48},
+		{"default policy without account config", policies, 300, -1, 72},
+		{"disabled biz policy ignored", policies, 200, -1, 72},
+		{"unassigned biz uses default policy", policies, constant.UnassignedBiz, -1, 72},
+		{"no policy", nil, 100, -1, 120},
+	}
+
+	for _, c := range cases {
+		got := getRecycleReserveTime(120, c.policies, c.bizID, c.accountReserve)
+		if got != c.expect {
+			t.Errorf("%s: expect %d, but got %d", c.name, c.expect, got)
+		}
+	}
+}
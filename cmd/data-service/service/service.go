@@ -30,14 +30,18 @@ import (
 
 	mainaccount "hcm/cmd/data-service/service/account-set/main-account"
 	rootaccount "hcm/cmd/data-service/service/account-set/root-account"
+	"hcm/cmd/data-service/service/admission"
 	"hcm/cmd/data-service/service/application"
 	"hcm/cmd/data-service/service/audit"
 	"hcm/cmd/data-service/service/auth"
 	"hcm/cmd/data-service/service/bill/billadjustmentitem"
+	"hcm/cmd/data-service/service/bill/billallocationrule"
+	"hcm/cmd/data-service/service/bill/billbudget"
 	"hcm/cmd/data-service/service/bill/billdailytask"
 	"hcm/cmd/data-service/service/bill/billexchangerate"
 	"hcm/cmd/data-service/service/bill/billitem"
 	"hcm/cmd/data-service/service/bill/billmonthtask"
+	"hcm/cmd/data-service/service/bill/billresourcecost"
 	"hcm/cmd/data-service/service/bill/billsummarydaily"
 	"hcm/cmd/data-service/service/bill/billsummarymain"
 	"hcm/cmd/data-service/service/bill/billsummaryroot"
@@ -56,6 +60,7 @@ import (
 	"hcm/cmd/data-service/service/cloud/cvm"
 	"hcm/cmd/data-service/service/cloud/disk"
 	diskcvmrel "hcm/cmd/data-service/service/cloud/disk-cvm-rel"
+	disksnapshot "hcm/cmd/data-service/service/cloud/disk-snapshot"
 	"hcm/cmd/data-service/service/cloud/eip"
 	eipcvmrel "hcm/cmd/data-service/service/cloud/eip-cvm-rel"
 	"hcm/cmd/data-service/service/cloud/image"
@@ -70,8 +75,13 @@ import (
 	sgcvmrel "hcm/cmd/data-service/service/cloud/security-group-cvm-rel"
 	subaccount "hcm/cmd/data-service/service/cloud/sub-account"
 	sync "hcm/cmd/data-service/service/cloud/sync"
+	"hcm/cmd/data-service/service/cloud/tag"
 	"hcm/cmd/data-service/service/cloud/zone"
 	"hcm/cmd/data-service/service/cos"
+	diskbackup "hcm/cmd/data-service/service/disk-backup"
+	"hcm/cmd/data-service/service/ipam"
+	"hcm/cmd/data-service/service/lock"
+	recyclepolicy "hcm/cmd/data-service/service/recycle-policy"
 	recyclerecord "hcm/cmd/data-service/service/recycle-record"
 	"hcm/cmd/data-service/service/user"
 	"hcm/pkg/cc"
@@ -238,12 +248,19 @@ func (s *Service) apiSet() *restful.Container {
 	networkinterface.InitNetInterfaceService(capability)
 	networkcvmrel.InitService(capability)
 	recyclerecord.InitRecycleRecordService(capability)
+	recyclepolicy.InitService(capability)
 	bill.InitBillConfigService(capability)
 	subaccount.InitService(capability)
 	sync.InitService(capability)
+	tag.InitService(capability)
+	lock.InitService(capability)
+	admission.InitService(capability)
+	ipam.InitService(capability)
 	user.InitService(capability)
 	cloudselection.InitService(capability)
 	argstpl.InitService(capability)
+	disksnapshot.InitService(capability)
+	diskbackup.InitService(capability)
 	cert.InitService(capability)
 	loadbalancer.InitService(capability)
 	sgcomrel.InitService(capability)
@@ -269,6 +286,9 @@ func (s *Service) apiSet() *restful.Container {
 
 	billexchangerate.InitService(capability)
 	billsyncrecord.InitService(capability)
+	billresourcecost.InitService(capability)
+	billbudget.InitService(capability)
+	billallocationrule.InitService(capability)
 
 	return restful.NewContainer().Add(capability.WebService)
 }
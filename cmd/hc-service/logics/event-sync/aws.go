@@ -0,0 +1,155 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"encoding/json"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+const (
+	awsInstanceSetPath = "requestParameters.instancesSet.items[].instanceId"
+	awsVpcIDPath       = "requestParameters.vpcId"
+	awsSubnetIDPath    = "requestParameters.subnetId"
+	awsGroupIDPath     = "requestParameters.groupId"
+	awsVolumeIDPath    = "requestParameters.volumeId"
+	awsAllocationPath  = "requestParameters.allocationId"
+	awsRouteTablePath  = "requestParameters.routeTableId"
+)
+
+// awsEventRules CloudTrail 事件名称与资源类型的对应关系。
+var awsEventRules = map[string]eventRule{
+	"RunInstances":            {enumor.CvmCloudResType, []string{"responseElements.instancesSet.items[].instanceId"}},
+	"TerminateInstances":      {enumor.CvmCloudResType, []string{awsInstanceSetPath}},
+	"StartInstances":          {enumor.CvmCloudResType, []string{awsInstanceSetPath}},
+	"StopInstances":           {enumor.CvmCloudResType, []string{awsInstanceSetPath}},
+	"RebootInstances":         {enumor.CvmCloudResType, []string{awsInstanceSetPath}},
+	"ModifyInstanceAttribute": {enumor.CvmCloudResType, []string{"requestParameters.instanceId"}},
+
+	"CreateVpc":          {enumor.VpcCloudResType, []string{"responseElements.vpc.vpcId"}},
+	"DeleteVpc":          {enumor.VpcCloudResType, []string{awsVpcIDPath}},
+	"ModifyVpcAttribute": {enumor.VpcCloudResType, []string{awsVpcIDPath}},
+
+	"CreateSubnet":          {enumor.SubnetCloudResType, []string{"responseElements.subnet.subnetId"}},
+	"DeleteSubnet":          {enumor.SubnetCloudResType, []string{awsSubnetIDPath}},
+	"ModifySubnetAttribute": {enumor.SubnetCloudResType, []string{awsSubnetIDPath}},
+
+	"CreateSecurityGroup":           {enumor.SecurityGroupCloudResType, []string{"responseElements.groupId"}},
+	"DeleteSecurityGroup":           {enumor.SecurityGroupCloudResType, []string{awsGroupIDPath}},
+	"AuthorizeSecurityGroupIngress": {enumor.SecurityGroupCloudResType, []string{awsGroupIDPath}},
+	"AuthorizeSecurityGroupEgress":  {enumor.SecurityGroupCloudResType, []string{awsGroupIDPath}},
+	"RevokeSecurityGroupIngress":    {enumor.SecurityGroupCloudResType, []string{awsGroupIDPath}},
+	"RevokeSecurityGroupEgress":     {enumor.SecurityGroupCloudResType, []string{awsGroupIDPath}},
+
+	"CreateVolume": {enumor.DiskCloudResType, []string{"responseElements.volumeId"}},
+	"DeleteVolume": {enumor.DiskCloudResType, []string{awsVolumeIDPath}},
+	"AttachVolume": {enumor.DiskCloudResType, []string{awsVolumeIDPath}},
+	"DetachVolume": {enumor.DiskCloudResType, []string{awsVolumeIDPath}},
+	"ModifyVolume": {enumor.DiskCloudResType, []string{awsVolumeIDPath}},
+
+	"AllocateAddress":     {enumor.EipCloudResType, []string{"responseElements.allocationId"}},
+	"ReleaseAddress":      {enumor.EipCloudResType, []string{awsAllocationPath}},
+	"AssociateAddress":    {enumor.EipCloudResType, []string{awsAllocationPath}},
+	"DisassociateAddress": {enumor.EipCloudResType, []string{awsAllocationPath}},
+
+	"CreateRouteTable":    {enumor.RouteTableCloudResType, []string{"responseElements.routeTable.routeTableId"}},
+	"DeleteRouteTable":    {enumor.RouteTableCloudResType, []string{awsRouteTablePath}},
+	"CreateRoute":         {enumor.RouteTableCloudResType, []string{awsRouteTablePath}},
+	"DeleteRoute":         {enumor.RouteTableCloudResType, []string{awsRouteTablePath}},
+	"ReplaceRoute":        {enumor.RouteTableCloudResType, []string{awsRouteTablePath}},
+	"AssociateRouteTable": {enumor.RouteTableCloudResType, []string{awsRouteTablePath}},
+}
+
+// awsTrailRecord CloudTrail 事件记录中解析所需的字段。
+type awsTrailRecord struct {
+	EventID   string `json:"eventID"`
+	EventName string `json:"eventName"`
+	AwsRegion string `json:"awsRegion"`
+	ErrorCode string `json:"errorCode"`
+	ReadOnly  bool   `json:"readOnly"`
+}
+
+// awsTrailLog CloudTrail 投递到存储桶的日志文件格式
+type awsTrailLog struct {
+	Records []json.RawMessage `json:"Records"`
+}
+
+// AwsParser AWS CloudTrail 事件解析器，支持单条事件和 {"Records": [...]} 格式的日志文件。
+type AwsParser struct{}
+
+var _ Parser = new(AwsParser)
+
+// Vendor ...
+func (p *AwsParser) Vendor() enumor.Vendor {
+	return enumor.Aws
+}
+
+// Parse ...
+func (p *AwsParser) Parse(data []byte) ([]Event, error) {
+	trailLog := new(awsTrailLog)
+	if err := json.Unmarshal(data, trailLog); err != nil {
+		return nil, err
+	}
+
+	if len(trailLog.Records) == 0 {
+		return p.parseOne(data)
+	}
+
+	events := make([]Event, 0, len(trailLog.Records))
+	for _, one := range trailLog.Records {
+		parsed, err := p.parseOne(one)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, parsed...)
+	}
+	return events, nil
+}
+
+func (p *AwsParser) parseOne(data []byte) ([]Event, error) {
+	record := new(awsTrailRecord)
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, err
+	}
+
+	// 只读操作和调用失败的操作不会变更资源
+	if record.ReadOnly || len(record.ErrorCode) != 0 {
+		return nil, nil
+	}
+
+	rule, exists := awsEventRules[record.EventName]
+	if !exists {
+		return nil, nil
+	}
+
+	detail := make(map[string]interface{})
+	if err := json.Unmarshal(data, &detail); err != nil {
+		return nil, err
+	}
+
+	cloudIDs := extractByPaths(detail, rule.Paths)
+	if len(cloudIDs) == 0 {
+		return nil, nil
+	}
+
+	return []Event{{ID: record.EventID, Name: record.EventName, Region: record.AwsRegion, ResType: rule.ResType,
+		CloudIDs: cloudIDs}}, nil
+}
@@ -0,0 +1,230 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"fmt"
+	"sort"
+	gosync "sync"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/serviced"
+	"hcm/pkg/tools/slice"
+)
+
+// Ingestor 事件接入器，从事件源拉取或接收推送的原始事件，解析后按账号、地域、资源类型合并为同步批次，
+// 在合并窗口结束后仅针对涉及的云ID触发同步。
+type Ingestor struct {
+	sources []Source
+	parsers map[enumor.Vendor]Parser
+	syncer  Syncer
+	state   serviced.State
+
+	pollInterval time.Duration
+	debounce     time.Duration
+	concurrency  int
+	maxRetry     int
+
+	lock    gosync.Mutex
+	pending map[BatchKey]*batch
+}
+
+// batch 待同步批次
+type batch struct {
+	cloudIDs map[string]struct{}
+	// readyAt 批次可以同步的时间，即首个事件到达时间加上合并窗口
+	readyAt time.Time
+	retry   int
+}
+
+// NewIngestor new ingestor.
+func NewIngestor(conf cc.EventSync, sources []Source, syncer Syncer, state serviced.State) *Ingestor {
+	return &Ingestor{
+		sources:      sources,
+		parsers:      NewParsers(),
+		syncer:       syncer,
+		state:        state,
+		pollInterval: time.Duration(conf.PollIntervalSec) * time.Second,
+		debounce:     time.Duration(conf.DebounceSec) * time.Second,
+		concurrency:  int(conf.Concurrency),
+		maxRetry:     int(conf.MaxRetry),
+		pending:      make(map[BatchKey]*batch),
+	}
+}
+
+// Run 定时拉取事件源并同步已到期的批次。事件源只由主节点拉取，推送到各节点的事件由接收节点同步。
+func (i *Ingestor) Run() {
+	logs.Infof("event sync ingestor start, sources: %d, poll interval: %v, debounce: %v", len(i.sources),
+		i.pollInterval, i.debounce)
+
+	for {
+		time.Sleep(i.pollInterval)
+
+		kt := core.NewBackendKit()
+		if i.state.IsMaster() {
+			i.Poll(kt)
+		}
+
+		i.Flush(kt, false)
+	}
+}
+
+// Poll 拉取所有事件源的新事件，单个事件源失败不影响其他事件源。
+func (i *Ingestor) Poll(kt *kit.Kit) {
+	for _, source := range i.sources {
+		records, err := source.Poll(kt)
+		if err != nil {
+			logs.Errorf("poll event source %s failed, err: %v, rid: %s", source.Name(), err, kt.Rid)
+		}
+
+		for _, record := range records {
+			if _, err = i.Ingest(record); err != nil {
+				logs.Errorf("ingest event from source %s failed, err: %v, record: %s, rid: %s", source.Name(), err,
+					record.Data, kt.Rid)
+			}
+		}
+	}
+}
+
+// Ingest 解析原始事件记录并加入待同步批次，返回解析出的资源变更事件数。任意一条记录解析失败时，
+// 本次传入的记录都不会加入待同步批次。
+func (i *Ingestor) Ingest(records ...Record) (int, error) {
+	batches := make(map[BatchKey][]string)
+	count := 0
+	for _, record := range records {
+		parser, exists := i.parsers[record.Vendor]
+		if !exists {
+			return 0, fmt.Errorf("vendor %s event is not supported", record.Vendor)
+		}
+
+		events, err := parser.Parse(record.Data)
+		if err != nil {
+			return 0, fmt.Errorf("parse %s event failed, err: %v", record.Vendor, err)
+		}
+
+		for _, event := range events {
+			key := BatchKey{Vendor: record.Vendor, AccountID: record.AccountID, Region: event.Region,
+				ResType: event.ResType}
+			batches[key] = append(batches[key], event.CloudIDs...)
+		}
+		count += len(events)
+	}
+
+	for key, cloudIDs := range batches {
+		i.add(key, cloudIDs, 0)
+	}
+
+	return count, nil
+}
+
+func (i *Ingestor) add(key BatchKey, cloudIDs []string, retry int) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	one, exists := i.pending[key]
+	if !exists {
+		one = &batch{cloudIDs: make(map[string]struct{}), readyAt: time.Now().Add(i.debounce)}
+		i.pending[key] = one
+	}
+
+	if retry > one.retry {
+		one.retry = retry
+	}
+
+	for _, cloudID := range cloudIDs {
+		one.cloudIDs[cloudID] = struct{}{}
+	}
+}
+
+// takeReady 取出已到期的批次，force 为 true 时取出全部批次。
+func (i *Ingestor) takeReady(force bool) map[BatchKey]*batch {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	now := time.Now()
+	ready := make(map[BatchKey]*batch)
+	for key, one := range i.pending {
+		if force || !now.Before(one.readyAt) {
+			ready[key] = one
+			delete(i.pending, key)
+		}
+	}
+
+	return ready
+}
+
+// Flush 同步已到期的批次，force 为 true 时忽略合并窗口同步全部批次。同步失败的资源在下一个合并窗口后重试，
+// 超过最大重试次数后放弃，等待定时全量同步修正。
+func (i *Ingestor) Flush(kt *kit.Kit, force bool) {
+	ready := i.takeReady(force)
+	if len(ready) == 0 {
+		return
+	}
+
+	limiter := make(chan struct{}, i.concurrency)
+	wg := gosync.WaitGroup{}
+	for key, one := range ready {
+		cloudIDs := make([]string, 0, len(one.cloudIDs))
+		for cloudID := range one.cloudIDs {
+			cloudIDs = append(cloudIDs, cloudID)
+		}
+		sort.Strings(cloudIDs)
+
+		for _, part := range slice.Split(cloudIDs, constant.CloudResourceSyncMaxLimit) {
+			limiter <- struct{}{}
+			wg.Add(1)
+
+			go func(key BatchKey, cloudIDs []string, retry int) {
+				defer func() {
+					<-limiter
+					wg.Done()
+				}()
+
+				i.sync(kt.NewSubKit(), key, cloudIDs, retry)
+			}(key, part, one.retry)
+		}
+	}
+
+	wg.Wait()
+}
+
+func (i *Ingestor) sync(kt *kit.Kit, key BatchKey, cloudIDs []string, retry int) {
+	err := i.syncer.Sync(kt, key, cloudIDs)
+	if err == nil {
+		logs.Infof("event sync %s success, cloud ids: %v, rid: %s", key, cloudIDs, kt.Rid)
+		return
+	}
+
+	if retry >= i.maxRetry {
+		logs.Errorf("event sync %s failed and exceeds max retry %d, err: %v, cloud ids: %v, rid: %s", key,
+			i.maxRetry, err, cloudIDs, kt.Rid)
+		return
+	}
+
+	logs.Errorf("event sync %s failed, will retry later, err: %v, retry: %d, cloud ids: %v, rid: %s", key, err,
+		retry, cloudIDs, kt.Rid)
+	i.add(key, cloudIDs, retry+1)
+}
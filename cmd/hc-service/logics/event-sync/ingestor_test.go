@@ -0,0 +1,181 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	gosync "sync"
+	"testing"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+const (
+	awsRunInstances = `{"eventID":"e1","eventName":"RunInstances","awsRegion":"us-east-1","readOnly":false,` +
+		`"responseElements":{"instancesSet":{"items":[{"instanceId":"i-1"},{"instanceId":"i-2"}]}}}`
+	awsTerminate = `{"eventID":"e2","eventName":"TerminateInstances","awsRegion":"us-east-1",` +
+		`"requestParameters":{"instancesSet":{"items":[{"instanceId":"i-2"},{"instanceId":"i-3"}]}}}`
+	awsDescribe = `{"eventID":"e3","eventName":"DescribeInstances","awsRegion":"us-east-1","readOnly":true}`
+	awsFailed   = `{"eventID":"e4","eventName":"DeleteVpc","awsRegion":"us-east-1","errorCode":"DependencyViolation",` +
+		`"requestParameters":{"vpcId":"vpc-1"}}`
+	tcloudDeleteVpc = `{"EventId":"t1","EventName":"DeleteVpc","EventRegion":"ap-guangzhou","ErrorCode":0,` +
+		`"Resources":{"ResourceType":"vpc","ResourceName":"vpc-a, vpc-b"}}`
+)
+
+func TestAwsParser(t *testing.T) {
+	parser := new(AwsParser)
+
+	events, err := parser.Parse([]byte(`{"Records":[` + awsRunInstances + `,` + awsDescribe + `,` + awsFailed + `]}`))
+	if err != nil {
+		t.Fatalf("parse aws trail log failed, err: %v", err)
+	}
+
+	want := []Event{{ID: "e1", Name: "RunInstances", Region: "us-east-1", ResType: enumor.CvmCloudResType,
+		CloudIDs: []string{"i-1", "i-2"}}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("aws events mismatch, want: %+v, got: %+v", want, events)
+	}
+}
+
+func TestTCloudParser(t *testing.T) {
+	events, err := new(TCloudParser).Parse([]byte(tcloudDeleteVpc))
+	if err != nil {
+		t.Fatalf("parse tcloud audit event failed, err: %v", err)
+	}
+
+	want := []Event{{ID: "t1", Name: "DeleteVpc", Region: "ap-guangzhou", ResType: enumor.VpcCloudResType,
+		CloudIDs: []string{"vpc-a", "vpc-b"}}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("tcloud events mismatch, want: %+v, got: %+v", want, events)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	source := NewFileSource("test", path, enumor.Aws, "00000001")
+
+	records, err := source.Poll(kit.New())
+	if err != nil || len(records) != 0 {
+		t.Fatalf("poll not exist file should return empty, records: %d, err: %v", len(records), err)
+	}
+
+	// 未写完的行不会被读取
+	writeFile(t, path, awsRunInstances+"\n"+awsTerminate, os.O_CREATE|os.O_WRONLY)
+	records, err = source.Poll(kit.New())
+	if err != nil || len(records) != 1 {
+		t.Fatalf("first poll should return 1 record, records: %d, err: %v", len(records), err)
+	}
+
+	writeFile(t, path, "\n", os.O_APPEND|os.O_WRONLY)
+	records, err = source.Poll(kit.New())
+	if err != nil || len(records) != 1 || string(records[0].Data) != awsTerminate {
+		t.Fatalf("second poll should return the completed record, records: %+v, err: %v", records, err)
+	}
+
+	// 文件截断后从头读取
+	writeFile(t, path, awsDescribe+"\n", os.O_TRUNC|os.O_WRONLY)
+	records, err = source.Poll(kit.New())
+	if err != nil || len(records) != 1 || string(records[0].Data) != awsDescribe {
+		t.Fatalf("poll after truncate should read from start, records: %+v, err: %v", records, err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string, flag int) {
+	file, err := os.OpenFile(path, flag, 0644)
+	if err != nil {
+		t.Fatalf("open file failed, err: %v", err)
+	}
+	defer file.Close()
+
+	if _, err = file.WriteString(content); err != nil {
+		t.Fatalf("write file failed, err: %v", err)
+	}
+}
+
+type fakeSyncer struct {
+	lock  gosync.Mutex
+	fail  bool
+	calls map[BatchKey][][]string
+}
+
+func (s *fakeSyncer) Sync(_ *kit.Kit, key BatchKey, cloudIDs []string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.calls[key] = append(s.calls[key], cloudIDs)
+	if s.fail {
+		return errors.New("sync failed")
+	}
+	return nil
+}
+
+func TestIngestor(t *testing.T) {
+	syncer := &fakeSyncer{calls: make(map[BatchKey][][]string)}
+	conf := cc.EventSync{PollIntervalSec: 1, DebounceSec: 60, Concurrency: 2, MaxRetry: 1}
+	ingestor := NewIngestor(conf, nil, syncer, nil)
+
+	count, err := ingestor.Ingest(
+		Record{Vendor: enumor.Aws, AccountID: "00000001", Data: []byte(awsRunInstances)},
+		Record{Vendor: enumor.Aws, AccountID: "00000001", Data: []byte(awsTerminate)},
+		Record{Vendor: enumor.Aws, AccountID: "00000001", Data: []byte(awsDescribe)},
+	)
+	if err != nil || count != 2 {
+		t.Fatalf("ingest should parse 2 events, count: %d, err: %v", count, err)
+	}
+
+	if _, err = ingestor.Ingest(Record{Vendor: enumor.Gcp, Data: []byte(`{}`)}); err == nil {
+		t.Errorf("ingest unsupported vendor event should fail")
+	}
+
+	// 合并窗口未结束，不会触发同步
+	ingestor.Flush(kit.New(), false)
+	if len(syncer.calls) != 0 {
+		t.Fatalf("flush before debounce should not sync, calls: %v", syncer.calls)
+	}
+
+	ingestor.Flush(kit.New(), true)
+	key := BatchKey{Vendor: enumor.Aws, AccountID: "00000001", Region: "us-east-1", ResType: enumor.CvmCloudResType}
+	want := map[BatchKey][][]string{key: {{"i-1", "i-2", "i-3"}}}
+	if !reflect.DeepEqual(syncer.calls, want) {
+		t.Fatalf("sync calls mismatch, want: %v, got: %v", want, syncer.calls)
+	}
+
+	// 同步失败后重新加入待同步批次，超过最大重试次数后放弃
+	syncer.fail = true
+	if _, err = ingestor.Ingest(Record{Vendor: enumor.Aws, AccountID: "00000001",
+		Data: []byte(awsRunInstances)}); err != nil {
+		t.Fatalf("ingest failed, err: %v", err)
+	}
+
+	for retry := 0; retry <= int(conf.MaxRetry)+1; retry++ {
+		ingestor.Flush(kit.New(), true)
+	}
+	if len(syncer.calls[key]) != 1+int(conf.MaxRetry)+1 {
+		t.Errorf("sync retry times mismatch, calls: %v", syncer.calls[key])
+	}
+	if len(ingestor.pending) != 0 {
+		t.Errorf("pending batches should be dropped after max retry, pending: %d", len(ingestor.pending))
+	}
+}
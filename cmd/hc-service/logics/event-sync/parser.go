@@ -0,0 +1,101 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"strings"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+// NewParsers 返回支持的云厂商事件解析器。
+func NewParsers() map[enumor.Vendor]Parser {
+	parsers := []Parser{new(TCloudParser), new(AwsParser)}
+
+	result := make(map[enumor.Vendor]Parser, len(parsers))
+	for _, parser := range parsers {
+		result[parser.Vendor()] = parser
+	}
+	return result
+}
+
+// eventRule 事件名称与资源类型的对应关系，Paths 为从事件中提取资源云ID的路径，
+// 以 . 分隔，字段名以 [] 结尾表示该字段为数组，对数组中每个元素继续按剩余路径提取。
+type eventRule struct {
+	ResType enumor.CloudResourceType
+	Paths   []string
+}
+
+// extractByPaths 按路径从事件中提取去重后的字符串值。
+func extractByPaths(data map[string]interface{}, paths []string) []string {
+	values := make([]string, 0)
+	exists := make(map[string]struct{})
+	for _, path := range paths {
+		for _, value := range extract(data, strings.Split(path, ".")) {
+			if _, ok := exists[value]; ok || len(value) == 0 {
+				continue
+			}
+			exists[value] = struct{}{}
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func extract(data interface{}, fields []string) []string {
+	if len(fields) == 0 {
+		switch val := data.(type) {
+		case string:
+			return []string{val}
+		case []interface{}:
+			values := make([]string, 0, len(val))
+			for _, one := range val {
+				if str, ok := one.(string); ok {
+					values = append(values, str)
+				}
+			}
+			return values
+		default:
+			return nil
+		}
+	}
+
+	obj, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	field := fields[0]
+	if !strings.HasSuffix(field, "[]") {
+		return extract(obj[field], fields[1:])
+	}
+
+	items, ok := obj[strings.TrimSuffix(field, "[]")].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	values := make([]string, 0)
+	for _, item := range items {
+		values = append(values, extract(item, fields[1:])...)
+	}
+	return values
+}
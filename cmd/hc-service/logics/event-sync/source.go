@@ -0,0 +1,127 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+// NewSources 根据配置创建事件源。
+func NewSources(configs []cc.EventSource) ([]Source, error) {
+	sources := make([]Source, 0, len(configs))
+	for _, one := range configs {
+		switch one.Type {
+		case cc.FileEventSource:
+			sources = append(sources, NewFileSource(one.Name, one.Path, one.Vendor, one.AccountID))
+		default:
+			return nil, fmt.Errorf("event source type %s is not supported", one.Type)
+		}
+	}
+
+	return sources, nil
+}
+
+// FileSource 本地文件事件源，文件每行为一条云厂商原始事件记录。每次拉取从上次读取的位置开始，
+// 只读取新追加的完整行，文件被截断（如日志轮转）后从头开始读取。
+type FileSource struct {
+	name      string
+	path      string
+	vendor    enumor.Vendor
+	accountID string
+	offset    int64
+}
+
+var _ Source = new(FileSource)
+
+// NewFileSource new file source.
+func NewFileSource(name, path string, vendor enumor.Vendor, accountID string) *FileSource {
+	return &FileSource{
+		name:      name,
+		path:      path,
+		vendor:    vendor,
+		accountID: accountID,
+	}
+}
+
+// Name ...
+func (s *FileSource) Name() string {
+	return s.name
+}
+
+// Poll ...
+func (s *FileSource) Poll(_ *kit.Kit) ([]Record, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() < s.offset {
+		s.offset = 0
+	}
+
+	if info.Size() == s.offset {
+		return nil, nil
+	}
+
+	if _, err = file.Seek(s.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0)
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// 未以换行结尾的行可能还在写入，留到下次拉取
+			if err == io.EOF {
+				break
+			}
+			return records, err
+		}
+
+		s.offset += int64(len(line))
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		records = append(records, Record{Vendor: s.vendor, AccountID: s.accountID, Data: line})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,126 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"fmt"
+
+	ressync "hcm/cmd/hc-service/logics/res-sync"
+	"hcm/cmd/hc-service/logics/res-sync/aws"
+	"hcm/cmd/hc-service/logics/res-sync/tcloud"
+	synclogic "hcm/cmd/hc-service/logics/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// NewResSyncer 基于资源同步客户端的同步器，按云ID调用对应资源的批量同步，每次同步记录为事件触发的执行记录。
+func NewResSyncer(cli ressync.Interface, recorder *synclogic.RunRecorder) Syncer {
+	return &resSyncer{cli: cli, recorder: recorder}
+}
+
+type resSyncer struct {
+	cli      ressync.Interface
+	recorder *synclogic.RunRecorder
+}
+
+// Sync ...
+func (s *resSyncer) Sync(kt *kit.Kit, key BatchKey, cloudIDs []string) (err error) {
+	kt = kt.NewSubKit()
+	run := synclogic.StartRun(kt, key.Vendor, key.AccountID, key.Region, key.ResType, enumor.EventSyncTrigger)
+	defer func() {
+		s.recorder.Finish(kt, run, err)
+	}()
+
+	switch key.Vendor {
+	case enumor.TCloud:
+		err = s.tcloud(kt, key, cloudIDs)
+	case enumor.Aws:
+		err = s.aws(kt, key, cloudIDs)
+	default:
+		return fmt.Errorf("vendor %s event sync is not supported", key.Vendor)
+	}
+	if err != nil {
+		logs.Errorf("event sync %s failed, err: %v, cloud ids: %v, rid: %s", key, err, cloudIDs, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (s *resSyncer) tcloud(kt *kit.Kit, key BatchKey, cloudIDs []string) error {
+	syncCli, err := s.cli.TCloud(kt, key.AccountID)
+	if err != nil {
+		return err
+	}
+
+	params := &tcloud.SyncBaseParams{AccountID: key.AccountID, Region: key.Region, CloudIDs: cloudIDs}
+	switch key.ResType {
+	case enumor.CvmCloudResType:
+		_, err = syncCli.CvmWithRelRes(kt, params, new(tcloud.SyncCvmWithRelResOption))
+	case enumor.VpcCloudResType:
+		_, err = syncCli.Vpc(kt, params, new(tcloud.SyncVpcOption))
+	case enumor.SubnetCloudResType:
+		_, err = syncCli.Subnet(kt, params, new(tcloud.SyncSubnetOption))
+	case enumor.SecurityGroupCloudResType:
+		_, err = syncCli.SecurityGroup(kt, params, new(tcloud.SyncSGOption))
+	case enumor.DiskCloudResType:
+		_, err = syncCli.Disk(kt, params, new(tcloud.SyncDiskOption))
+	case enumor.EipCloudResType:
+		_, err = syncCli.Eip(kt, params, new(tcloud.SyncEipOption))
+	case enumor.RouteTableCloudResType:
+		_, err = syncCli.RouteTable(kt, params, new(tcloud.SyncRouteTableOption))
+	case enumor.LoadBalancerCloudResType:
+		_, err = syncCli.LoadBalancerWithListener(kt, params, new(tcloud.SyncLBOption))
+	default:
+		return fmt.Errorf("tcloud %s event sync is not supported", key.ResType)
+	}
+
+	return err
+}
+
+func (s *resSyncer) aws(kt *kit.Kit, key BatchKey, cloudIDs []string) error {
+	syncCli, err := s.cli.Aws(kt, key.AccountID)
+	if err != nil {
+		return err
+	}
+
+	params := &aws.SyncBaseParams{AccountID: key.AccountID, Region: key.Region, CloudIDs: cloudIDs}
+	switch key.ResType {
+	case enumor.CvmCloudResType:
+		_, err = syncCli.CvmWithRelRes(kt, params, new(aws.SyncCvmWithRelResOption))
+	case enumor.VpcCloudResType:
+		_, err = syncCli.Vpc(kt, params, new(aws.SyncVpcOption))
+	case enumor.SubnetCloudResType:
+		_, err = syncCli.Subnet(kt, params, new(aws.SyncSubnetOption))
+	case enumor.SecurityGroupCloudResType:
+		_, err = syncCli.SecurityGroup(kt, params, new(aws.SyncSGOption))
+	case enumor.DiskCloudResType:
+		_, err = syncCli.Disk(kt, params, new(aws.SyncDiskOption))
+	case enumor.EipCloudResType:
+		_, err = syncCli.Eip(kt, params, new(aws.SyncEipOption))
+	case enumor.RouteTableCloudResType:
+		_, err = syncCli.RouteTable(kt, params, new(aws.SyncRouteTableOption))
+	default:
+		return fmt.Errorf("aws %s event sync is not supported", key.ResType)
+	}
+
+	return err
+}
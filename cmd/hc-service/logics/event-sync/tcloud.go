@@ -0,0 +1,183 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package eventsync
+
+import (
+	"encoding/json"
+	"strings"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+// tcloudEventRules 腾讯云操作审计事件名称与资源类型的对应关系，资源云ID取自事件的 Resources.ResourceName。
+var tcloudEventRules = map[string]enumor.CloudResourceType{
+	"RunInstances":             enumor.CvmCloudResType,
+	"TerminateInstances":       enumor.CvmCloudResType,
+	"StartInstances":           enumor.CvmCloudResType,
+	"StopInstances":            enumor.CvmCloudResType,
+	"RebootInstances":          enumor.CvmCloudResType,
+	"ResetInstance":            enumor.CvmCloudResType,
+	"ResetInstancesType":       enumor.CvmCloudResType,
+	"ModifyInstancesAttribute": enumor.CvmCloudResType,
+
+	"CreateVpc":          enumor.VpcCloudResType,
+	"DeleteVpc":          enumor.VpcCloudResType,
+	"ModifyVpcAttribute": enumor.VpcCloudResType,
+
+	"CreateSubnet":          enumor.SubnetCloudResType,
+	"CreateSubnets":         enumor.SubnetCloudResType,
+	"DeleteSubnet":          enumor.SubnetCloudResType,
+	"ModifySubnetAttribute": enumor.SubnetCloudResType,
+
+	"CreateSecurityGroup":             enumor.SecurityGroupCloudResType,
+	"DeleteSecurityGroup":             enumor.SecurityGroupCloudResType,
+	"ModifySecurityGroupAttribute":    enumor.SecurityGroupCloudResType,
+	"CreateSecurityGroupPolicies":     enumor.SecurityGroupCloudResType,
+	"DeleteSecurityGroupPolicies":     enumor.SecurityGroupCloudResType,
+	"ModifySecurityGroupPolicies":     enumor.SecurityGroupCloudResType,
+	"ReplaceSecurityGroupPolicy":      enumor.SecurityGroupCloudResType,
+	"ReplaceSecurityGroupPolicies":    enumor.SecurityGroupCloudResType,
+	"AssociateSecurityGroups":         enumor.SecurityGroupCloudResType,
+	"DisassociateSecurityGroups":      enumor.SecurityGroupCloudResType,
+	"ModifyInstancesSecurityGroups":   enumor.SecurityGroupCloudResType,
+	"ModifySecurityGroupDescription":  enumor.SecurityGroupCloudResType,
+	"ModifySecurityGroupPolicySeqNos": enumor.SecurityGroupCloudResType,
+
+	"CreateDisks":          enumor.DiskCloudResType,
+	"TerminateDisks":       enumor.DiskCloudResType,
+	"AttachDisks":          enumor.DiskCloudResType,
+	"DetachDisks":          enumor.DiskCloudResType,
+	"ResizeDisk":           enumor.DiskCloudResType,
+	"ModifyDiskAttributes": enumor.DiskCloudResType,
+
+	"AllocateAddresses":      enumor.EipCloudResType,
+	"ReleaseAddresses":       enumor.EipCloudResType,
+	"AssociateAddress":       enumor.EipCloudResType,
+	"DisassociateAddress":    enumor.EipCloudResType,
+	"ModifyAddressAttribute": enumor.EipCloudResType,
+
+	"CreateRouteTable":             enumor.RouteTableCloudResType,
+	"DeleteRouteTable":             enumor.RouteTableCloudResType,
+	"ModifyRouteTableAttribute":    enumor.RouteTableCloudResType,
+	"CreateRoutes":                 enumor.RouteTableCloudResType,
+	"DeleteRoutes":                 enumor.RouteTableCloudResType,
+	"ReplaceRoutes":                enumor.RouteTableCloudResType,
+	"ReplaceRouteTableAssociation": enumor.RouteTableCloudResType,
+
+	"CreateLoadBalancer":           enumor.LoadBalancerCloudResType,
+	"DeleteLoadBalancer":           enumor.LoadBalancerCloudResType,
+	"ModifyLoadBalancerAttributes": enumor.LoadBalancerCloudResType,
+	"CreateListener":               enumor.LoadBalancerCloudResType,
+	"DeleteListener":               enumor.LoadBalancerCloudResType,
+	"ModifyListener":               enumor.LoadBalancerCloudResType,
+	"CreateRule":                   enumor.LoadBalancerCloudResType,
+	"DeleteRule":                   enumor.LoadBalancerCloudResType,
+	"ModifyRule":                   enumor.LoadBalancerCloudResType,
+	"RegisterTargets":              enumor.LoadBalancerCloudResType,
+	"DeregisterTargets":            enumor.LoadBalancerCloudResType,
+	"ModifyTargetWeight":           enumor.LoadBalancerCloudResType,
+}
+
+// tcloudAuditEvent 腾讯云操作审计（CloudAudit）事件，对应 LookUpEvents 接口返回的 Event。
+type tcloudAuditEvent struct {
+	EventID     string `json:"EventId"`
+	EventName   string `json:"EventName"`
+	EventRegion string `json:"EventRegion"`
+	// ResourceRegion 资源所在地域，为空时使用 EventRegion
+	ResourceRegion string `json:"ResourceRegion"`
+	ErrorCode      int64  `json:"ErrorCode"`
+	Resources      *struct {
+		ResourceType string `json:"ResourceType"`
+		// ResourceName 资源云ID，多个资源以逗号分隔
+		ResourceName string `json:"ResourceName"`
+	} `json:"Resources"`
+}
+
+// tcloudAuditEvents 批量导出的操作审计事件
+type tcloudAuditEvents struct {
+	Events []json.RawMessage `json:"Events"`
+}
+
+// TCloudParser 腾讯云操作审计事件解析器，支持单条事件和 {"Events": [...]} 格式的批量事件。
+type TCloudParser struct{}
+
+var _ Parser = new(TCloudParser)
+
+// Vendor ...
+func (p *TCloudParser) Vendor() enumor.Vendor {
+	return enumor.TCloud
+}
+
+// Parse ...
+func (p *TCloudParser) Parse(data []byte) ([]Event, error) {
+	batch := new(tcloudAuditEvents)
+	if err := json.Unmarshal(data, batch); err != nil {
+		return nil, err
+	}
+
+	if len(batch.Events) == 0 {
+		return p.parseOne(data)
+	}
+
+	events := make([]Event, 0, len(batch.Events))
+	for _, one := range batch.Events {
+		parsed, err := p.parseOne(one)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, parsed...)
+	}
+	return events, nil
+}
+
+func (p *TCloudParser) parseOne(data []byte) ([]Event, error) {
+	record := new(tcloudAuditEvent)
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, err
+	}
+
+	// 调用失败的操作不会变更资源
+	if record.ErrorCode != 0 || record.Resources == nil {
+		return nil, nil
+	}
+
+	resType, exists := tcloudEventRules[record.EventName]
+	if !exists {
+		return nil, nil
+	}
+
+	cloudIDs := make([]string, 0)
+	for _, one := range strings.Split(record.Resources.ResourceName, ",") {
+		if one = strings.TrimSpace(one); len(one) != 0 {
+			cloudIDs = append(cloudIDs, one)
+		}
+	}
+	if len(cloudIDs) == 0 {
+		return nil, nil
+	}
+
+	region := record.ResourceRegion
+	if len(region) == 0 {
+		region = record.EventRegion
+	}
+
+	return []Event{{ID: record.EventID, Name: record.EventName, Region: region, ResType: resType,
+		CloudIDs: cloudIDs}}, nil
+}
@@ -0,0 +1,80 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package eventsync 云上变更事件增量同步。消费云厂商操作审计事件（如 AWS CloudTrail、腾讯云 CloudAudit），
+// 解析出发生变更的资源，合并后仅针对这些资源的云ID触发同步，作为定时全量同步的补充，降低同步开销和数据延迟。
+package eventsync
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+// Record 事件源中的一条原始事件记录。
+type Record struct {
+	Vendor enumor.Vendor
+	// AccountID 事件所属的HCM账号ID
+	AccountID string
+	// Data 云厂商原始事件内容
+	Data json.RawMessage
+}
+
+// Event 解析后的资源变更事件。
+type Event struct {
+	ID       string
+	Name     string
+	Region   string
+	ResType  enumor.CloudResourceType
+	CloudIDs []string
+}
+
+// Source 事件源，负责从外部拉取新产生的原始事件记录。
+type Source interface {
+	Name() string
+	// Poll 拉取自上次调用以来新产生的事件记录，没有新记录时返回空。
+	Poll(kt *kit.Kit) ([]Record, error)
+}
+
+// Parser 云厂商事件解析器，将原始事件记录解析为资源变更事件。
+// 只读操作、失败的操作以及不关心的资源类型不会产生事件。
+type Parser interface {
+	Vendor() enumor.Vendor
+	Parse(data []byte) ([]Event, error)
+}
+
+// BatchKey 同步批次的维度，同一批次内的资源通过一次同步完成。
+type BatchKey struct {
+	Vendor    enumor.Vendor
+	AccountID string
+	Region    string
+	ResType   enumor.CloudResourceType
+}
+
+// String ...
+func (k BatchKey) String() string {
+	return fmt.Sprintf("%s/%s/%s/%s", k.Vendor, k.AccountID, k.Region, k.ResType)
+}
+
+// Syncer 按云ID同步指定资源，云上已删除的资源会同时从本地删除。
+type Syncer interface {
+	Sync(kt *kit.Kit, key BatchKey, cloudIDs []string) error
+}
@@ -76,7 +76,7 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.AwsCvm, corecvm.Cvm[cvm.AwsCvmExtension]](
-		cvmFromCloud, cvmFromDB, isCvmChange)
+		kt, enumor.CvmCloudResType, cvmFromCloud, cvmFromDB, isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteCvm(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -88,12 +88,19 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		if err = cli.createCvm(kt, params.AccountID, params.Region, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.CvmCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateCvm(kt, params.AccountID, params.Region, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.CvmCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.Aws, params.AccountID, enumor.CvmCloudResType,
+		cvmFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
@@ -485,6 +492,10 @@ func (cli *client) getImageMap(kt *kit.Kit, accountID string, region string,
 }
 
 func (cli *client) deleteCvm(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.CvmCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("cvm delCloudIDs is <= 0, not delete")
 	}
@@ -517,6 +528,8 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, region string, delCl
 	logs.Infof("[%s] sync cvm to delete cvm success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.CvmCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
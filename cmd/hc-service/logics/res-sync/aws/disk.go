@@ -79,7 +79,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		}
 	}
 	addSlice, updateMap, delCloudIDs := common.Diff[adaptordisk.AwsDisk, *coredisk.Disk[coredisk.AwsExtension]](
-		diskFromCloud, diskFromDB, isDiskChange)
+		kt, enumor.DiskCloudResType, diskFromCloud, diskFromDB, isDiskChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteDisk(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -91,12 +91,19 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		if err = cli.createDisk(kt, params.AccountID, params.Region, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.DiskCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateDisk(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.DiskCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.Aws, params.AccountID, enumor.DiskCloudResType,
+		diskFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
@@ -237,6 +244,10 @@ func (cli *client) createDisk(kt *kit.Kit, accountID string, region string, addS
 }
 
 func (cli *client) deleteDisk(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.DiskCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("delCloudIDs is <= 0, not delete")
 	}
@@ -269,6 +280,8 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, region string, delC
 	logs.Infof("[%s] sync disk to delete disk success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.DiskCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
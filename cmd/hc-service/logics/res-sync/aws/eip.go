@@ -70,7 +70,8 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 	}
 
 	addEip, updateMap, delCloudIDs := common.Diff[*typeseip.AwsEip,
-		*dataeip.EipExtResult[dataeip.AwsEipExtensionResult]](eipFromCloud, eipFromDB, isEipChange)
+		*dataeip.EipExtResult[dataeip.AwsEipExtensionResult]](kt, enumor.EipCloudResType,
+		eipFromCloud, eipFromDB, isEipChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteEip(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -82,12 +83,19 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		if err = cli.createEip(kt, params.AccountID, addEip, opt.BkBizID); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.EipCloudResType, len(addEip))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateEip(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.EipCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.Aws, params.AccountID, enumor.EipCloudResType,
+		eipFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
@@ -164,6 +172,10 @@ func (cli *client) RemoveEipDeleteFromCloud(kt *kit.Kit, accountID string, regio
 }
 
 func (cli *client) deleteEip(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.EipCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete eip, cloudIDs is required")
 	}
@@ -195,6 +207,8 @@ func (cli *client) deleteEip(kt *kit.Kit, accountID string, region string, delCl
 	logs.Infof("[%s] sync eip to delete eip success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.EipCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
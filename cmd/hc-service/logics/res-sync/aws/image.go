@@ -70,7 +70,7 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesimage.AwsImage, coreimage.Image[coreimage.AwsExtension]](
-		imageFromCloud, imageFromDB, isImageChange)
+		kt, enumor.ImageCloudResType, imageFromCloud, imageFromDB, isImageChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteImage(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -82,12 +82,14 @@ func (cli *client) Image(kt *kit.Kit, params *SyncBaseParams, opt *SyncImageOpti
 		if err = cli.createImage(kt, params.AccountID, params.Region, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.ImageCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateImage(kt, params.AccountID, params.Region, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.ImageCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -164,6 +166,10 @@ func (cli *client) createImage(kt *kit.Kit, accountID string, region string,
 }
 
 func (cli *client) deleteImage(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.ImageCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("image delCloudIDs is <= 0, not delete")
 	}
@@ -195,6 +201,8 @@ func (cli *client) deleteImage(kt *kit.Kit, accountID string, region string, del
 	logs.Infof("[%s] sync image to delete image success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.ImageCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
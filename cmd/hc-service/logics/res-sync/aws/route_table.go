@@ -72,7 +72,8 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.AwsRouteTable,
-		routetable.AwsRouteTable](routeTableFromCloud, routeTableFromDB, isRouteTableChange)
+		routetable.AwsRouteTable](kt, enumor.RouteTableCloudResType,
+		routeTableFromCloud, routeTableFromDB, isRouteTableChange)
 
 	subnetMap := make(map[string]dataproto.RouteTableSubnetReq, 0)
 
@@ -96,6 +97,7 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 		for k, v := range addSubnetMap {
 			subnetMap[k] = v
 		}
+		common.CountCreate(kt, enumor.RouteTableCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
@@ -106,6 +108,7 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 		for k, v := range updateSubnetMap {
 			subnetMap[k] = v
 		}
+		common.CountUpdate(kt, enumor.RouteTableCloudResType, len(updateMap))
 	}
 
 	// 更新子网的路由表信息
@@ -246,6 +249,10 @@ func (cli *client) updateRouteTalbe(kt *kit.Kit, accountID string, resGroupName
 }
 
 func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.RouteTableCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("routeTable delCloudIDs is <= 0, not delete")
 	}
@@ -277,6 +284,8 @@ func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, region string
 	logs.Infof("[%s] sync routeTable to delete routeTable success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.RouteTableCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -71,7 +71,7 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[securitygroup.AwsSG, cloudcore.SecurityGroup[cloudcore.AwsSecurityGroupExtension]](
-		sgFromCloud, sgFromDB, isSGChange)
+		kt, enumor.SecurityGroupCloudResType, sgFromCloud, sgFromDB, isSGChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteSG(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -84,12 +84,14 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		if err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.SecurityGroupCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateSG(kt, params.AccountID, params.Region, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.SecurityGroupCloudResType, len(updateMap))
 	}
 
 	// 同步安全组规则
@@ -234,6 +236,10 @@ func (cli *client) createSG(kt *kit.Kit, accountID string, region string,
 }
 
 func (cli *client) deleteSG(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.SecurityGroupCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("sg delCloudIDs is <= 0, not delete")
 	}
@@ -266,6 +272,8 @@ func (cli *client) deleteSG(kt *kit.Kit, accountID string, region string, delClo
 	logs.Infof("[%s] sync sg to delete sg success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.SecurityGroupCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -124,7 +124,8 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[securitygrouprule.AwsSGRule,
-		corecloud.AwsSecurityGroupRule](sgRuleFromCloud, sgRuleFromDB, isSGRuleChange)
+		corecloud.AwsSecurityGroupRule](kt, enumor.SecurityGroupRuleCloudResType,
+		sgRuleFromCloud, sgRuleFromDB, isSGRuleChange)
 
 	if len(delCloudIDs) > 0 {
 		err := cli.deleteSGRule(kt, opt, delCloudIDs)
@@ -138,6 +139,7 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 		if err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.SecurityGroupRuleCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
@@ -145,6 +147,7 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 		if err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.SecurityGroupRuleCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -296,6 +299,10 @@ func (cli *client) genUpdateRulesList(updateMap map[string]securitygrouprule.Aws
 }
 
 func (cli *client) deleteSGRule(kt *kit.Kit, opt *syncSGRuleOption, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.SecurityGroupRuleCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("sgRule delCloudIDs is <= 0, not delete")
 	}
@@ -341,6 +348,8 @@ func (cli *client) deleteSGRule(kt *kit.Kit, opt *syncSGRuleOption, delCloudIDs
 	logs.Infof("[%s] sync sgRule to delete sgRule success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.SecurityGroupRuleCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
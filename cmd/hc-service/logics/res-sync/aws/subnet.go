@@ -73,7 +73,7 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.AwsSubnet, cloudcore.Subnet[cloudcore.AwsSubnetExtension]](
-		subnetFromCloud, subnetFromDB, isAwsSubnetChange)
+		kt, enumor.SubnetCloudResType, subnetFromCloud, subnetFromDB, isAwsSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -85,18 +85,29 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		if err = cli.createSubnet(kt, params.AccountID, params.Region, addSubnet); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.SubnetCloudResType, len(addSubnet))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateSubnet(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.SubnetCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.Aws, params.AccountID, enumor.SubnetCloudResType,
+		subnetFromCloud); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
 }
 
 func (cli *client) deleteSubnet(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.SubnetCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete subnet, cloudIDs is required")
 	}
@@ -128,6 +139,8 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID string, region string, de
 	logs.Infof("[%s] sync subnet to delete subnet success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.SubnetCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -73,7 +73,7 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 	}
 
 	addVpc, updateMap, delCloudIDs := common.Diff[types.AwsVpc, cloudcore.Vpc[cloudcore.AwsVpcExtension]](
-		vpcFromCloud, vpcFromDB, isAwsVpcChange)
+		kt, enumor.VpcCloudResType, vpcFromCloud, vpcFromDB, isAwsVpcChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteVpc(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -85,18 +85,29 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		if err = cli.createVpc(kt, params.AccountID, addVpc); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.VpcCloudResType, len(addVpc))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateVpc(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.VpcCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.Aws, params.AccountID, enumor.VpcCloudResType,
+		vpcFromCloud); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
 }
 
 func (cli *client) deleteVpc(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.VpcCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete vpc, cloudIDs is required")
 	}
@@ -128,6 +139,8 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, region string, delCl
 	logs.Infof("[%s] sync vpc to delete vpc success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.VpcCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
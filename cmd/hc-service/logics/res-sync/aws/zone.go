@@ -72,7 +72,7 @@ func (cli *client) Zone(kt *kit.Kit, opt *SyncZoneOption) (*SyncResult, error) {
 		return new(SyncResult), nil
 	}
 
-	addSlice, updateMap, delCloudIDs := common.Diff[typeszone.AwsZone, corezone.BaseZone](
+	addSlice, updateMap, delCloudIDs := common.Diff[typeszone.AwsZone, corezone.BaseZone](kt, enumor.ZoneCloudResType,
 		zoneFromCloud, zoneFromDB, isZoneChange)
 
 	if len(delCloudIDs) > 0 {
@@ -85,12 +85,14 @@ func (cli *client) Zone(kt *kit.Kit, opt *SyncZoneOption) (*SyncResult, error) {
 		if err = cli.createZone(kt, opt, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.ZoneCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateZone(kt, opt, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.ZoneCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -163,6 +165,10 @@ func (cli *client) updateZone(kt *kit.Kit, opt *SyncZoneOption,
 }
 
 func (cli *client) deleteZone(kt *kit.Kit, opt *SyncZoneOption, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.ZoneCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("zone delCloudIDs is <= 0, not delete")
 	}
@@ -198,6 +204,8 @@ func (cli *client) deleteZone(kt *kit.Kit, opt *SyncZoneOption, delCloudIDs []st
 	logs.Infof("[%s] sync zone to delete zone success, accountID: %s, count: %d, rid: %s", enumor.Aws,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.ZoneCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
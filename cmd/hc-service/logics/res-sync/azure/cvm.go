@@ -73,7 +73,7 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.AzureCvm, corecvm.Cvm[cvm.AzureCvmExtension]](
-		cvmFromCloud, cvmFromDB, isCvmChange)
+		kt, enumor.CvmCloudResType, cvmFromCloud, cvmFromDB, isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteCvm(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -85,12 +85,19 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		if err = cli.createCvm(kt, params.AccountID, params.ResourceGroupName, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.CvmCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateCvm(kt, params.AccountID, params.ResourceGroupName, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.CvmCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.Azure, params.AccountID, enumor.CvmCloudResType,
+		cvmFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
@@ -570,6 +577,10 @@ func (cli *client) getImageMap(kt *kit.Kit, accountID string, resGroupName strin
 }
 
 func (cli *client) deleteCvm(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.CvmCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("cvm delCloudIDs is <= 0, not delete")
 	}
@@ -602,6 +613,8 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, resGroupName string,
 	logs.Infof("[%s] sync cvm to delete cvm success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.CvmCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -78,7 +78,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		}
 	}
 	addSlice, updateMap, delCloudIDs := common.Diff[typesdisk.AzureDisk, *coredisk.Disk[coredisk.AzureExtension]](
-		diskFromCloud, diskFromDB, isDiskChange)
+		kt, enumor.DiskCloudResType, diskFromCloud, diskFromDB, isDiskChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteDisk(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -90,12 +90,19 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		if err = cli.createDisk(kt, params.AccountID, params.ResourceGroupName, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.DiskCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateDisk(kt, params.AccountID, params.ResourceGroupName, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.DiskCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.Azure, params.AccountID, enumor.DiskCloudResType,
+		diskFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
@@ -193,6 +200,10 @@ func (cli *client) createDisk(kt *kit.Kit, accountID string, resGroupName string
 func (cli *client) deleteDisk(kt *kit.Kit, accountID string, resGroupName string,
 	delCloudIDs []string) error {
 
+	if common.PlanDelete(kt, enumor.DiskCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("delCloudIDs is <= 0, not delete")
 	}
@@ -225,6 +236,8 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, resGroupName string
 	logs.Infof("[%s] sync disk to delete disk success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.DiskCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
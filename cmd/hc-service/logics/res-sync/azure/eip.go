@@ -71,7 +71,8 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 	}
 
 	addEip, updateMap, delCloudIDs := common.Diff[*typeseip.AzureEip,
-		*dataeip.EipExtResult[dataeip.AzureEipExtensionResult]](eipFromCloud, eipFromDB, isEipChange)
+		*dataeip.EipExtResult[dataeip.AzureEipExtensionResult]](kt, enumor.EipCloudResType,
+		eipFromCloud, eipFromDB, isEipChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteEip(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -83,12 +84,19 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		if err = cli.createEip(kt, params.AccountID, opt.BkBizID, addEip); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.EipCloudResType, len(addEip))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateEip(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.EipCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.Azure, params.AccountID, enumor.EipCloudResType,
+		eipFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
@@ -166,6 +174,10 @@ func (cli *client) RemoveEipDeleteFromCloud(kt *kit.Kit, accountID string, resGr
 }
 
 func (cli *client) deleteEip(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.EipCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("eip delCloudIDs is <= 0, not delete")
 	}
@@ -197,6 +209,8 @@ func (cli *client) deleteEip(kt *kit.Kit, accountID string, resGroupName string,
 	logs.Infof("[%s] sync eip to delete eip success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.EipCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -73,7 +73,7 @@ func (cli *client) Image(kt *kit.Kit, opt *SyncImageOption) (*SyncResult, error)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesimage.AzureImage, coreimage.Image[coreimage.AzureExtension]](
-		imageFromCloud, imageFromDB, isImageChange)
+		kt, enumor.ImageCloudResType, imageFromCloud, imageFromDB, isImageChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteImage(kt, opt, delCloudIDs); err != nil {
@@ -85,12 +85,14 @@ func (cli *client) Image(kt *kit.Kit, opt *SyncImageOption) (*SyncResult, error)
 		if err = cli.createImage(kt, opt, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.ImageCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateImage(kt, opt, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.ImageCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -177,6 +179,10 @@ func (cli *client) createImage(kt *kit.Kit, opt *SyncImageOption,
 }
 
 func (cli *client) deleteImage(kt *kit.Kit, opt *SyncImageOption, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.ImageCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("image delCloudIDs is <= 0, not delete")
 	}
@@ -221,6 +227,8 @@ func (cli *client) deleteImage(kt *kit.Kit, opt *SyncImageOption, delCloudIDs []
 	logs.Infof("[%s] sync image to delete image success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.ImageCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
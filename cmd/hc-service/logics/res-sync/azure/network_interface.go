@@ -73,7 +73,8 @@ func (cli *client) NetworkInterface(kt *kit.Kit, params *SyncBaseParams, opt *Sy
 	}
 
 	addNetworkInterface, updateMap, delCloudIDs := common.Diff[typesni.AzureNI,
-		coreni.NetworkInterface[coreni.AzureNIExtension]](niFromCloud, niFromDB, isNIChange)
+		coreni.NetworkInterface[coreni.AzureNIExtension]](kt, enumor.NetworkInterfaceCloudResType,
+		niFromCloud, niFromDB, isNIChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteNetworkInterface(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -86,12 +87,14 @@ func (cli *client) NetworkInterface(kt *kit.Kit, params *SyncBaseParams, opt *Sy
 			addNetworkInterface); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.NetworkInterfaceCloudResType, len(addNetworkInterface))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateNetworkInterface(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.NetworkInterfaceCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -170,6 +173,10 @@ func (cli *client) RemoveNetworkInterfaceDeleteFromCloud(kt *kit.Kit, accountID
 
 func (cli *client) deleteNetworkInterface(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
 
+	if common.PlanDelete(kt, enumor.NetworkInterfaceCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete network interface, network interfaces is required")
 	}
@@ -201,6 +208,8 @@ func (cli *client) deleteNetworkInterface(kt *kit.Kit, accountID string, resGrou
 	logs.Infof("[%s] sync ni to delete ni success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.NetworkInterfaceCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
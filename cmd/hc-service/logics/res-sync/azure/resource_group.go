@@ -70,7 +70,7 @@ func (cli *client) ResourceGroup(kt *kit.Kit, opt *SyncRGOption) (*SyncResult, e
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesrg.AzureResourceGroup, corerg.AzureRG](
-		resourcegroupFromCloud, resourcegroupFromDB, isResourceGroupChange)
+		kt, enumor.AzureResourceGroup, resourcegroupFromCloud, resourcegroupFromDB, isResourceGroupChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteResourceGroup(kt, opt, delCloudIDs); err != nil {
@@ -82,12 +82,14 @@ func (cli *client) ResourceGroup(kt *kit.Kit, opt *SyncRGOption) (*SyncResult, e
 		if err = cli.createResourceGroup(kt, opt, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.AzureResourceGroup, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateResourceGroup(kt, opt, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.AzureResourceGroup, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -161,6 +163,10 @@ func (cli *client) updateResourceGroup(kt *kit.Kit, opt *SyncRGOption,
 }
 
 func (cli *client) deleteResourceGroup(kt *kit.Kit, opt *SyncRGOption, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.AzureResourceGroup, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("resourcegroup delCloudIDs is <= 0, not delete")
 	}
@@ -195,6 +201,8 @@ func (cli *client) deleteResourceGroup(kt *kit.Kit, opt *SyncRGOption, delCloudI
 	logs.Infof("[%s] sync resourcegroup to delete resourcegroup success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.AzureResourceGroup, len(delCloudIDs))
+
 	return nil
 }
 
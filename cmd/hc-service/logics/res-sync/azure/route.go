@@ -123,7 +123,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.AzureRoute,
-		routetable.AzureRoute](routeFromCloud, routeFromDB, isRouteChange)
+		routetable.AzureRoute](kt, enumor.RouteCloudResType, routeFromCloud, routeFromDB, isRouteChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteRoute(kt, opt.AccountID, opt.ResourceGroupName, opt.CloudRouteTableID, routeTable.ID,
@@ -138,6 +138,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 		if err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.RouteCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
@@ -145,6 +146,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 		if err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.RouteCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -225,6 +227,10 @@ func (cli *client) updateRoute(kt *kit.Kit, accountID, resGroupName, routeTableI
 func (cli *client) deleteRoute(kt *kit.Kit, accountID, resGroupName, cloudRTID, rtID string,
 	delCloudIDs []string) error {
 
+	if common.PlanDelete(kt, enumor.RouteCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("route delCloudIDs is <= 0, not delete")
 	}
@@ -260,6 +266,8 @@ func (cli *client) deleteRoute(kt *kit.Kit, accountID, resGroupName, cloudRTID,
 	logs.Infof("[%s] sync route to delete route success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.RouteCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
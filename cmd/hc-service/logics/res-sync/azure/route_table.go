@@ -70,7 +70,8 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.AzureRouteTable,
-		routetable.AzureRouteTable](routeTableFromCloud, routeTableFromDB, isRouteTableChange)
+		routetable.AzureRouteTable](kt, enumor.RouteTableCloudResType,
+		routeTableFromCloud, routeTableFromDB, isRouteTableChange)
 
 	subnetMap := make(map[string]dataproto.RouteTableSubnetReq, 0)
 
@@ -94,6 +95,7 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 		for k, v := range addSubnetMap {
 			subnetMap[k] = v
 		}
+		common.CountCreate(kt, enumor.RouteTableCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
@@ -104,6 +106,7 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 		for k, v := range updateSubnetMap {
 			subnetMap[k] = v
 		}
+		common.CountUpdate(kt, enumor.RouteTableCloudResType, len(updateMap))
 	}
 
 	// 更新子网的路由表信息
@@ -308,6 +311,10 @@ func (cli *client) updateRouteTalbe(kt *kit.Kit, accountID string, resGroupName
 }
 
 func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.RouteTableCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("routeTable delCloudIDs is <= 0, not delete")
 	}
@@ -339,6 +346,8 @@ func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, resGroupName
 	logs.Infof("[%s] sync routeTable to delete routeTable success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.RouteTableCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -69,7 +69,7 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[securitygroup.AzureSecurityGroup, cloudcore.SecurityGroup[cloudcore.AzureSecurityGroupExtension]](
-		sgFromCloud, sgFromDB, isSGChange)
+		kt, enumor.SecurityGroupCloudResType, sgFromCloud, sgFromDB, isSGChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteSG(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -82,12 +82,14 @@ func (cli *client) SecurityGroup(kt *kit.Kit, params *SyncBaseParams, opt *SyncS
 		if err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.SecurityGroupCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateSG(kt, params.AccountID, params.ResourceGroupName, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.SecurityGroupCloudResType, len(updateMap))
 	}
 
 	// 同步安全组规则
@@ -199,6 +201,10 @@ func (cli *client) updateSG(kt *kit.Kit, accountID string, resGroupName string,
 }
 
 func (cli *client) deleteSG(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.SecurityGroupCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("sg delCloudIDs is <= 0, not delete")
 	}
@@ -231,6 +237,8 @@ func (cli *client) deleteSG(kt *kit.Kit, accountID string, resGroupName string,
 	logs.Infof("[%s] sync sg to delete sg success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.SecurityGroupCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -134,7 +134,8 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[securitygrouprule.AzureSGRule,
-		corecloud.AzureSecurityGroupRule](sgRuleFromCloud, sgRuleFromDB, isSGRuleChange)
+		corecloud.AzureSecurityGroupRule](kt, enumor.SecurityGroupRuleCloudResType,
+		sgRuleFromCloud, sgRuleFromDB, isSGRuleChange)
 
 	if len(delCloudIDs) > 0 {
 		err := cli.deleteSGRule(kt, opt, delCloudIDs)
@@ -148,6 +149,7 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 		if err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.SecurityGroupRuleCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
@@ -155,6 +157,7 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 		if err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.SecurityGroupRuleCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -350,6 +353,10 @@ func (cli *client) genUpdateRulesList(updateMap map[string]securitygrouprule.Azu
 }
 
 func (cli *client) deleteSGRule(kt *kit.Kit, opt *syncSGRuleOption, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.SecurityGroupRuleCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("sgRule delCloudIDs is <= 0, not delete")
 	}
@@ -396,6 +403,8 @@ func (cli *client) deleteSGRule(kt *kit.Kit, opt *syncSGRuleOption, delCloudIDs
 	logs.Infof("[%s] sync sgRule to delete sgRule success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.SecurityGroupRuleCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -72,7 +72,8 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.AzureSubnet,
-		cloudcore.Subnet[cloudcore.AzureSubnetExtension]](subnetFromCloud, subnetFromDB, isSubnetChange)
+		cloudcore.Subnet[cloudcore.AzureSubnetExtension]](kt, enumor.SubnetCloudResType,
+		subnetFromCloud, subnetFromDB, isSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, params.ResourceGroupName, opt.CloudVpcID,
@@ -86,12 +87,14 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 			addSubnet); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.SubnetCloudResType, len(addSubnet))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateSubnet(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.SubnetCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -171,6 +174,10 @@ func (cli *client) RemoveSubnetDeleteFromCloud(kt *kit.Kit, accountID, resGroupN
 
 func (cli *client) deleteSubnet(kt *kit.Kit, accountID, resGroupName, cloudVpcID string, delCloudIDs []string) error {
 
+	if common.PlanDelete(kt, enumor.SubnetCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete subnet, cloudIDs is required")
 	}
@@ -203,6 +210,8 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID, resGroupName, cloudVpcID
 	logs.Infof("[%s] sync subnet to delete subnet success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.SubnetCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
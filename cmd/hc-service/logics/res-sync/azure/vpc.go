@@ -71,7 +71,7 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 	}
 
 	addVpc, updateMap, delCloudIDs := common.Diff[types.AzureVpc, cloudcore.Vpc[cloudcore.AzureVpcExtension]](
-		vpcFromCloud, vpcFromDB, isVpcChange)
+		kt, enumor.VpcCloudResType, vpcFromCloud, vpcFromDB, isVpcChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteVpc(kt, params.AccountID, params.ResourceGroupName, delCloudIDs); err != nil {
@@ -83,12 +83,19 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		if err = cli.createVpc(kt, params.AccountID, addVpc); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.VpcCloudResType, len(addVpc))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateVpc(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.VpcCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.Azure, params.AccountID, enumor.VpcCloudResType,
+		vpcFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
@@ -167,6 +174,10 @@ func (cli *client) RemoveVpcDeleteFromCloud(kt *kit.Kit, accountID string, resGr
 
 func (cli *client) deleteVpc(kt *kit.Kit, accountID string, resGroupName string, delCloudIDs []string) error {
 
+	if common.PlanDelete(kt, enumor.VpcCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete vpc, cloudIDs is required")
 	}
@@ -198,6 +209,8 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, resGroupName string,
 	logs.Infof("[%s] sync vpc to delete vpc success, accountID: %s, count: %d, rid: %s", enumor.Azure,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.VpcCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
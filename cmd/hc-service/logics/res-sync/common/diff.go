@@ -20,6 +20,7 @@
 package common
 
 import (
+	synclogic "hcm/cmd/hc-service/logics/sync"
 	"hcm/pkg/adaptor/types"
 	"hcm/pkg/adaptor/types/account"
 	typeargstpl "hcm/pkg/adaptor/types/argument-template"
@@ -53,6 +54,8 @@ import (
 	corezone "hcm/pkg/api/core/cloud/zone"
 	corerecyclerecord "hcm/pkg/api/core/recycle-record"
 	dataeip "hcm/pkg/api/data-service/cloud/eip"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
 )
 
 // CloudResType 云资源类型
@@ -252,14 +255,19 @@ type DBResType interface {
 }
 
 // Diff 对比云和db资源，划分出新增数据，更新数据，删除数据。
-func Diff[CloudType CloudResType, DBType DBResType](dataFromCloud []CloudType, dataFromDB []DBType,
-	isChange func(CloudType, DBType) bool) ([]CloudType, map[string]CloudType, []string) {
+// 同步计划模式下仅将变更记录到同步计划中，并返回空结果，调用方据此不会修改db数据；
+// 非计划模式下统计新增、更新数量。
+func Diff[CloudType CloudResType, DBType DBResType](kt *kit.Kit, resType enumor.CloudResourceType,
+	dataFromCloud []CloudType, dataFromDB []DBType, isChange func(CloudType, DBType) bool) ([]CloudType,
+	map[string]CloudType, []string) {
 
 	dbMap := make(map[string]DBType, len(dataFromDB))
 	for _, one := range dataFromDB {
 		dbMap[one.GetCloudID()] = one
 	}
 
+	plan, isPlan := synclogic.PlanFromKit(kt)
+
 	newAddData := make([]CloudType, 0)
 	updateMap := make(map[string]CloudType, 0)
 	for _, oneFromCloud := range dataFromCloud {
@@ -272,6 +280,9 @@ func Diff[CloudType CloudResType, DBType DBResType](dataFromCloud []CloudType, d
 		delete(dbMap, oneFromCloud.GetCloudID())
 		if isChange(oneFromCloud, oneFromDB) {
 			updateMap[oneFromDB.GetID()] = oneFromCloud
+			if isPlan {
+				plan.AddUpdate(resType, oneFromDB.GetCloudID(), oneFromDB.GetID(), oneFromCloud, oneFromDB)
+			}
 		}
 	}
 
@@ -280,5 +291,13 @@ func Diff[CloudType CloudResType, DBType DBResType](dataFromCloud []CloudType, d
 		delCloudIDs = append(delCloudIDs, cloudID)
 	}
 
+	if isPlan {
+		for _, one := range newAddData {
+			plan.AddCreate(resType, one.GetCloudID())
+		}
+		plan.AddDelete(resType, delCloudIDs...)
+		return make([]CloudType, 0), make(map[string]CloudType), make([]string, 0)
+	}
+
 	return newAddData, updateMap, delCloudIDs
 }
@@ -0,0 +1,70 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	synclogic "hcm/cmd/hc-service/logics/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+// IsPlan 是否处于同步计划模式，计划模式下不允许修改db数据。
+func IsPlan(kt *kit.Kit) bool {
+	return synclogic.IsPlan(kt)
+}
+
+// PlanDelete 同步计划模式下记录待删除的资源并返回true，此时调用方不应执行删除；
+// 非计划模式下返回false，删除成功后由调用方通过 CountDelete 统计删除数量。
+func PlanDelete(kt *kit.Kit, resType enumor.CloudResourceType, cloudIDs []string) bool {
+	plan, ok := synclogic.PlanFromKit(kt)
+	if !ok {
+		return false
+	}
+
+	plan.AddDelete(resType, cloudIDs...)
+	return true
+}
+
+// PlanDeleteByIDs 同步计划模式下按本地资源ID记录待删除的资源并返回true，此时调用方不应执行删除；
+// 非计划模式下返回false，删除成功后由调用方通过 CountDelete 统计删除数量。
+func PlanDeleteByIDs(kt *kit.Kit, resType enumor.CloudResourceType, ids []string) bool {
+	plan, ok := synclogic.PlanFromKit(kt)
+	if !ok {
+		return false
+	}
+
+	plan.AddDeleteByIDs(resType, ids...)
+	return true
+}
+
+// CountCreate 创建成功后统计资源创建数量，只能在写入db成功之后调用。
+func CountCreate(kt *kit.Kit, resType enumor.CloudResourceType, count int) {
+	synclogic.CountChanges(kt, resType, count, 0, 0)
+}
+
+// CountUpdate 更新成功后统计资源更新数量，只能在写入db成功之后调用。
+func CountUpdate(kt *kit.Kit, resType enumor.CloudResourceType, count int) {
+	synclogic.CountChanges(kt, resType, 0, count, 0)
+}
+
+// CountDelete 删除成功后统计资源删除数量，只能在写入db成功之后调用。
+func CountDelete(kt *kit.Kit, resType enumor.CloudResourceType, count int) {
+	synclogic.CountChanges(kt, resType, 0, 0, count)
+}
@@ -35,7 +35,7 @@ import (
 
 // CancelRouteTableSubnetRel cancel route table and subnet rel.
 func CancelRouteTableSubnetRel(kt *kit.Kit, dataCli *dataclient.Client, vendor enumor.Vendor, delCloudIDs []string) error {
-	if len(delCloudIDs) == 0 {
+	if len(delCloudIDs) == 0 || IsPlan(kt) {
 		return nil
 	}
 
@@ -92,6 +92,10 @@ func CancelRouteTableSubnetRel(kt *kit.Kit, dataCli *dataclient.Client, vendor e
 func UpdateSubnetRouteTableByIDs(kt *kit.Kit, vendor enumor.Vendor, subnetMap map[string]dataproto.RouteTableSubnetReq,
 	dataCli *dataclient.Client) error {
 
+	if IsPlan(kt) {
+		return nil
+	}
+
 	tmpCloudIDs := make([]string, 0)
 	tmpCloudSubnetIDs := make([]string, 0)
 	for tmpSubnetID, tmpRouteItem := range subnetMap {
@@ -0,0 +1,127 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package common
+
+import (
+	"hcm/pkg/api/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
+	dstag "hcm/pkg/api/data-service/cloud/tag"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/slice"
+)
+
+// TagResource 带有云上标签的云资源
+type TagResource interface {
+	GetCloudID() string
+	GetTags() coretag.TagMap
+}
+
+// tagSyncBatch 每批同步标签的资源数量
+const tagSyncBatch = 500
+
+// SyncResTags 将云上资源的标签同步到资源标签表，仅同步与db中标签不一致的资源，计划模式下不做任何修改。
+func SyncResTags[T TagResource](kt *kit.Kit, dbCli *dataservice.Client, vendor enumor.Vendor, accountID string,
+	resType enumor.CloudResourceType, resources []T) error {
+
+	if IsPlan(kt) || len(resources) == 0 {
+		return nil
+	}
+
+	for _, batch := range slice.Split(resources, tagSyncBatch) {
+		cloudIDs := make([]string, 0, len(batch))
+		for _, one := range batch {
+			cloudIDs = append(cloudIDs, one.GetCloudID())
+		}
+
+		dbTags, err := listResTagByCloudIDs(kt, dbCli, accountID, resType, cloudIDs)
+		if err != nil {
+			return err
+		}
+
+		items := make([]dstag.ResTagSyncItem, 0)
+		for _, one := range batch {
+			tags := one.GetTags()
+			if tags.Equal(dbTags[one.GetCloudID()]) {
+				continue
+			}
+
+			items = append(items, dstag.ResTagSyncItem{CloudID: one.GetCloudID(), Tags: tags})
+		}
+
+		if len(items) == 0 {
+			continue
+		}
+
+		req := &dstag.ResTagSyncReq{
+			Vendor:    vendor,
+			AccountID: accountID,
+			ResType:   resType,
+			Items:     items,
+		}
+		if err = dbCli.Global.ResTag.Sync(kt, req); err != nil {
+			logs.Errorf("[%s] sync %s tag failed, err: %v, account: %s, rid: %s", vendor, resType, err, accountID,
+				kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listResTagByCloudIDs list tags of resources in db, returns map of cloud id to tags.
+func listResTagByCloudIDs(kt *kit.Kit, dbCli *dataservice.Client, accountID string,
+	resType enumor.CloudResourceType, cloudIDs []string) (map[string]coretag.TagMap, error) {
+
+	result := make(map[string]coretag.TagMap)
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("account_id", accountID),
+			tools.RuleEqual("res_type", resType),
+			tools.RuleIn("res_cloud_id", cloudIDs),
+		),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"res_cloud_id", "tag_key", "tag_value"},
+	}
+	for {
+		resp, err := dbCli.Global.ResTag.List(kt, req)
+		if err != nil {
+			logs.Errorf("list %s tag from db failed, err: %v, account: %s, rid: %s", resType, err, accountID, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range resp.Details {
+			if _, exist := result[one.ResCloudID]; !exist {
+				result[one.ResCloudID] = make(coretag.TagMap)
+			}
+			result[one.ResCloudID][one.TagKey] = one.TagValue
+		}
+
+		if uint(len(resp.Details)) < core.DefaultMaxPageLimit {
+			break
+		}
+		req.Page.Start += uint32(core.DefaultMaxPageLimit)
+	}
+
+	return result, nil
+}
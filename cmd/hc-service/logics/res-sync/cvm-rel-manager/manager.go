@@ -73,6 +73,11 @@ func (mgr *CvmRelManger) SyncRel(kt *kit.Kit, opt *SyncRelOption) error {
 		return err
 	}
 
+	// 关联关系由资源变更推导得出，同步计划模式下不处理
+	if common.IsPlan(kt) {
+		return nil
+	}
+
 	cvmMap, err := mgr.getCvmMap(kt)
 	if err != nil {
 		logs.Errorf("get cvm map failed, err: %v, rid: %s", err, kt.Rid)
@@ -78,7 +78,7 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typescvm.GcpCvm, corecvm.Cvm[cvm.GcpCvmExtension]](
-		cvmFromCloud, cvmFromDB, isCvmChange)
+		kt, enumor.CvmCloudResType, cvmFromCloud, cvmFromDB, isCvmChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteCvm(kt, params.AccountID, opt.Zone, delCloudIDs); err != nil {
@@ -90,12 +90,19 @@ func (cli *client) Cvm(kt *kit.Kit, params *SyncBaseParams, opt *SyncCvmOption)
 		if err = cli.createCvm(kt, params.AccountID, opt.Region, opt.Zone, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.CvmCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateCvm(kt, params.AccountID, opt.Region, opt.Zone, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.CvmCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.Gcp, params.AccountID, enumor.CvmCloudResType,
+		cvmFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
@@ -559,6 +566,10 @@ func (cli *client) getDiskMap(kt *kit.Kit, accountID string, zone string,
 }
 
 func (cli *client) deleteCvm(kt *kit.Kit, accountID string, zone string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.CvmCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("cvm delCloudIDs is <= 0, not delete")
 	}
@@ -590,6 +601,8 @@ func (cli *client) deleteCvm(kt *kit.Kit, accountID string, zone string, delClou
 	logs.Infof("[%s] sync cvm to delete cvm success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.CvmCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -79,7 +79,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[adaptordisk.GcpDisk, *coredisk.Disk[coredisk.GcpExtension]](
-		diskFromCloud, diskFromDB, isDiskChange)
+		kt, enumor.DiskCloudResType, diskFromCloud, diskFromDB, isDiskChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteDisk(kt, params.AccountID, opt.Zone, delCloudIDs); err != nil {
@@ -91,12 +91,19 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		if err = cli.createDisk(kt, params.AccountID, opt.Zone, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.DiskCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateDisk(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.DiskCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.Gcp, params.AccountID, enumor.DiskCloudResType,
+		diskFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
@@ -292,6 +299,10 @@ func (cli *client) createDisk(kt *kit.Kit, accountID string, zone string, addSli
 }
 
 func (cli *client) deleteDisk(kt *kit.Kit, accountID string, zone string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.DiskCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("delCloudIDs is <= 0, not delete")
 	}
@@ -323,6 +334,8 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, zone string, delClo
 	logs.Infof("[%s] sync disk to delete disk success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.DiskCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
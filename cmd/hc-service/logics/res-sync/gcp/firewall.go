@@ -69,7 +69,7 @@ func (cli *client) Firewall(kt *kit.Kit, params *SyncBaseParams, opt *SyncFirewa
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[firewallrule.GcpFirewall, cloudcore.GcpFirewallRule](
-		firewallFromCloud, firewallFromDB, isFirewallChange)
+		kt, enumor.GcpFirewallRuleCloudResType, firewallFromCloud, firewallFromDB, isFirewallChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteFirewall(kt, params.AccountID, delCloudIDs); err != nil {
@@ -81,12 +81,14 @@ func (cli *client) Firewall(kt *kit.Kit, params *SyncBaseParams, opt *SyncFirewa
 		if err = cli.createFirewall(kt, params.AccountID, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.GcpFirewallRuleCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateFirewall(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.GcpFirewallRuleCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -268,6 +270,10 @@ func (cli *client) updateFirewall(kt *kit.Kit, accountID string,
 }
 
 func (cli *client) deleteFirewall(kt *kit.Kit, accountID string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.GcpFirewallRuleCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("firewall delCloudIDs is <= 0, not delete")
 	}
@@ -299,6 +305,8 @@ func (cli *client) deleteFirewall(kt *kit.Kit, accountID string, delCloudIDs []s
 	logs.Infof("[%s] sync firewall to delete firewall success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.GcpFirewallRuleCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
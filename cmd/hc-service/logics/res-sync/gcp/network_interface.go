@@ -116,7 +116,8 @@ func (cli *client) syncNetworkInterface(kt *kit.Kit, opt *syncNIOption) (*SyncRe
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesni.GcpNI, coreni.
-		NetworkInterface[coreni.GcpNIExtension]](networkInterfaceFromCloud, networkInterfaceFromDB, isNIChange)
+		NetworkInterface[coreni.GcpNIExtension]](kt, enumor.NetworkInterfaceCloudResType,
+		networkInterfaceFromCloud, networkInterfaceFromDB, isNIChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteNetworkInterface(kt, delCloudIDs, opt); err != nil {
@@ -128,12 +129,14 @@ func (cli *client) syncNetworkInterface(kt *kit.Kit, opt *syncNIOption) (*SyncRe
 		if err = cli.createNetworkInterface(kt, opt.AccountID, cvm, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.NetworkInterfaceCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateNetworkInterface(kt, opt.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.NetworkInterfaceCloudResType, len(updateMap))
 	}
 
 	return nil, nil
@@ -141,6 +144,10 @@ func (cli *client) syncNetworkInterface(kt *kit.Kit, opt *syncNIOption) (*SyncRe
 
 func (cli *client) deleteNetworkInterface(kt *kit.Kit, delCloudIDs []string, opt *syncNIOption) error {
 
+	if common.PlanDelete(kt, enumor.NetworkInterfaceCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if err := opt.Validate(); err != nil {
 		return errf.NewFromErr(errf.InvalidParameter, err)
 	}
@@ -175,6 +182,8 @@ func (cli *client) deleteNetworkInterface(kt *kit.Kit, delCloudIDs []string, opt
 	logs.V(3).Infof("[%s] sync network interface to delete ni success, accountID: %s, count: %d, rid: %s",
 		enumor.Gcp, opt.AccountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.NetworkInterfaceCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -73,7 +73,7 @@ func (cli *client) Region(kt *kit.Kit, params *SyncBaseParams, opt *SyncRegionOp
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesregion.GcpRegion, cloudcore.GcpRegion](
-		regionFromCloud, regionFromDB, isRegionChange)
+		kt, enumor.RegionCloudResType, regionFromCloud, regionFromDB, isRegionChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteRegion(kt, params.AccountID, delCloudIDs); err != nil {
@@ -85,12 +85,14 @@ func (cli *client) Region(kt *kit.Kit, params *SyncBaseParams, opt *SyncRegionOp
 		if err = cli.createRegion(kt, params.AccountID, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.RegionCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateRegion(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.RegionCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -220,6 +222,10 @@ func (cli *client) updateRegion(kt *kit.Kit, accountID string,
 }
 
 func (cli *client) deleteRegion(kt *kit.Kit, accountID string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.RegionCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("region delCloudIDs is <= 0, not delete")
 	}
@@ -254,6 +260,8 @@ func (cli *client) deleteRegion(kt *kit.Kit, accountID string, delCloudIDs []str
 	logs.Infof("[%s] sync region to delete region success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.RegionCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
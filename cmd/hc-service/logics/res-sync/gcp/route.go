@@ -70,7 +70,7 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.GcpRoute, cloudcoreroutetable.GcpRoute](
-		routeFromCloud, routeFromDB, isRouteChange)
+		kt, enumor.RouteCloudResType, routeFromCloud, routeFromDB, isRouteChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteRoute(kt, params.AccountID, delCloudIDs, routeFromDB); err != nil {
@@ -82,12 +82,14 @@ func (cli *client) Route(kt *kit.Kit, params *SyncBaseParams, opt *SyncRouteOpti
 		if err = cli.createRoute(kt, params.AccountID, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.RouteCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateRoute(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.RouteCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -157,6 +159,10 @@ func (cli *client) updateRoute(kt *kit.Kit, accountID string,
 func (cli *client) deleteRoute(kt *kit.Kit, accountID string, delCloudIDs []string,
 	routeFromDB []cloudcoreroutetable.GcpRoute) error {
 
+	if common.PlanDelete(kt, enumor.RouteCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("route delCloudIDs is <= 0, not delete")
 	}
@@ -196,6 +202,8 @@ func (cli *client) deleteRoute(kt *kit.Kit, accountID string, delCloudIDs []stri
 	logs.Infof("[%s] sync route to delete route success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.RouteCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
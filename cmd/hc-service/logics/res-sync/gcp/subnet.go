@@ -72,7 +72,7 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.GcpSubnet, cloudcore.Subnet[cloudcore.GcpSubnetExtension]](
-		subnetFromCloud, subnetFromDB, isGcpSubnetChange)
+		kt, enumor.SubnetCloudResType, subnetFromCloud, subnetFromDB, isGcpSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, opt.Region, delCloudIDs); err != nil {
@@ -84,12 +84,14 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		if err = cli.createSubnet(kt, params.AccountID, addSubnet); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.SubnetCloudResType, len(addSubnet))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateSubnet(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.SubnetCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -165,6 +167,10 @@ func (cli *client) RemoveSubnetDeleteFromCloud(kt *kit.Kit, accountID, region st
 }
 
 func (cli *client) deleteSubnet(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.SubnetCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete subnet, cloudIDs is required")
 	}
@@ -195,6 +201,8 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID, region string, delCloudI
 	logs.Infof("[%s] sync subnet to delete subnet success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.SubnetCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
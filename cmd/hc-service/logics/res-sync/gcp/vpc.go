@@ -71,7 +71,7 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 	}
 
 	addVpc, updateMap, delCloudIDs := common.Diff[types.GcpVpc, cloudcore.Vpc[cloudcore.GcpVpcExtension]](
-		vpcFromCloud, vpcFromDB, isGcpVpcChange)
+		kt, enumor.VpcCloudResType, vpcFromCloud, vpcFromDB, isGcpVpcChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteVpc(kt, params.AccountID, delCloudIDs); err != nil {
@@ -83,12 +83,14 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		if err = cli.createVpc(kt, params.AccountID, addVpc); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.VpcCloudResType, len(addVpc))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateVpc(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.VpcCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -163,6 +165,10 @@ func (cli *client) RemoveVpcDeleteFromCloud(kt *kit.Kit, accountID string) error
 }
 
 func (cli *client) deleteVpc(kt *kit.Kit, accountID string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.VpcCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete vpc, cloudIDs is required")
 	}
@@ -193,6 +199,8 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, delCloudIDs []string
 	logs.Infof("[%s] sync vpc to delete vpc success, accountID: %s, count: %d, rid: %s", enumor.Gcp,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.VpcCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -71,7 +71,8 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 	}
 
 	addEip, updateMap, delCloudIDs := common.Diff[*typeseip.HuaWeiEip,
-		*dataeip.EipExtResult[dataeip.HuaWeiEipExtensionResult]](eipFromCloud, eipFromDB, isEipChange)
+		*dataeip.EipExtResult[dataeip.HuaWeiEipExtensionResult]](kt, enumor.EipCloudResType,
+		eipFromCloud, eipFromDB, isEipChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteEip(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -83,12 +84,14 @@ func (cli *client) Eip(kt *kit.Kit, params *SyncBaseParams, opt *SyncEipOption)
 		if err = cli.createEip(kt, params.AccountID, addEip, opt.BkBizID); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.EipCloudResType, len(addEip))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateEip(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.EipCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -165,6 +168,10 @@ func (cli *client) RemoveEipDeleteFromCloud(kt *kit.Kit, accountID string, regio
 }
 
 func (cli *client) deleteEip(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.EipCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete eip, cloudIDs is required")
 	}
@@ -196,6 +203,8 @@ func (cli *client) deleteEip(kt *kit.Kit, accountID string, region string, delCl
 	logs.Infof("[%s] sync eip to delete eip success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.EipCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -122,7 +122,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.HuaWeiRoute,
-		routetable.HuaWeiRoute](routeFromCloud, routeFromDB, isRouteChange)
+		routetable.HuaWeiRoute](kt, enumor.RouteCloudResType, routeFromCloud, routeFromDB, isRouteChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteRoute(kt, opt.AccountID, opt.Region, opt.CloudRouteTableID, routeTable.ID, delCloudIDs,
@@ -137,6 +137,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 		if err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.RouteCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
@@ -144,6 +145,7 @@ func (cli *client) route(kt *kit.Kit, opt *syncRouteOption) (*SyncResult, error)
 		if err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.RouteCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -222,6 +224,10 @@ func (cli *client) updateRoute(kt *kit.Kit, accountID, region, routeTableID stri
 func (cli *client) deleteRoute(kt *kit.Kit, accountID, region, cloudRTID, rtID string,
 	delCloudIDs []string, routeFromDB []routetable.HuaWeiRoute) error {
 
+	if common.PlanDelete(kt, enumor.RouteCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("route delCloudIDs is <= 0, not delete")
 	}
@@ -264,6 +270,8 @@ func (cli *client) deleteRoute(kt *kit.Kit, accountID, region, cloudRTID, rtID s
 	logs.Infof("[%s] sync route to delete route success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.RouteCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
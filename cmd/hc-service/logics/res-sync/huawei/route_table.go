@@ -69,7 +69,8 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesroutetable.HuaWeiRouteTable,
-		routetable.HuaWeiRouteTable](routeTableFromCloud, routeTableFromDB, isRouteTableChange)
+		routetable.HuaWeiRouteTable](kt, enumor.RouteTableCloudResType,
+		routeTableFromCloud, routeTableFromDB, isRouteTableChange)
 
 	subnetMap := make(map[string]dataproto.RouteTableSubnetReq, 0)
 
@@ -93,6 +94,7 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 		for k, v := range addSubnetMap {
 			subnetMap[k] = v
 		}
+		common.CountCreate(kt, enumor.RouteTableCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
@@ -103,6 +105,7 @@ func (cli *client) RouteTable(kt *kit.Kit, params *SyncBaseParams, opt *SyncRout
 		for k, v := range updateSubnetMap {
 			subnetMap[k] = v
 		}
+		common.CountUpdate(kt, enumor.RouteTableCloudResType, len(updateMap))
 	}
 
 	// 更新子网的路由表信息
@@ -244,6 +247,10 @@ func (cli *client) updateRouteTalbe(kt *kit.Kit, accountID string, resGroupName
 }
 
 func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.RouteTableCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("routeTable delCloudIDs is <= 0, not delete")
 	}
@@ -275,6 +282,8 @@ func (cli *client) deleteRouteTable(kt *kit.Kit, accountID string, region string
 	logs.Infof("[%s] sync routeTable to delete routeTable success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.RouteTableCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -74,7 +74,8 @@ func (cli *client) SubAccount(kt *kit.Kit, opt *SyncSubAccountOption) (*SyncResu
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[account.HuaWeiAccount,
-		coresubaccount.SubAccount[coresubaccount.HuaWeiExtension]](fromCloud, fromDB, isSubAccountChange)
+		coresubaccount.SubAccount[coresubaccount.HuaWeiExtension]](kt, enumor.SubAccountCloudResType,
+		fromCloud, fromDB, isSubAccountChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubAccount(kt, opt, delCloudIDs); err != nil {
@@ -86,12 +87,14 @@ func (cli *client) SubAccount(kt *kit.Kit, opt *SyncSubAccountOption) (*SyncResu
 		if err = cli.createSubAccount(kt, opt, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.SubAccountCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateSubAccount(kt, opt, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.SubAccountCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -232,6 +235,10 @@ func (cli *client) createSubAccount(kt *kit.Kit, opt *SyncSubAccountOption, addS
 
 func (cli *client) deleteSubAccount(kt *kit.Kit, opt *SyncSubAccountOption, delCloudIDs []string) error {
 
+	if common.PlanDelete(kt, enumor.SubAccountCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("delCloudIDs is required")
 	}
@@ -271,6 +278,8 @@ func (cli *client) deleteSubAccount(kt *kit.Kit, opt *SyncSubAccountOption, delC
 	logs.Infof("[%s] sync sub account to delete sub account success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.SubAccountCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
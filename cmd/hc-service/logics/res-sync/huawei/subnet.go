@@ -71,7 +71,8 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.HuaWeiSubnet,
-		cloudcore.Subnet[cloudcore.HuaWeiSubnetExtension]](subnetFromCloud, subnetFromDB, isHuaWeiSubnetChange)
+		cloudcore.Subnet[cloudcore.HuaWeiSubnetExtension]](kt, enumor.SubnetCloudResType,
+		subnetFromCloud, subnetFromDB, isHuaWeiSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, params.Region, opt.CloudVpcID, delCloudIDs); err != nil {
@@ -83,12 +84,14 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		if err = cli.createSubnet(kt, params.AccountID, params.Region, opt.CloudVpcID, addSubnet); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.SubnetCloudResType, len(addSubnet))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateSubnet(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.SubnetCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -166,6 +169,10 @@ func (cli *client) RemoveSubnetDeleteFromCloud(kt *kit.Kit, accountID, region, c
 }
 
 func (cli *client) deleteSubnet(kt *kit.Kit, accountID, region, cloudVpcID string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.SubnetCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete subnet, cloudIDs is required")
 	}
@@ -198,6 +205,8 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID, region, cloudVpcID strin
 	logs.Infof("[%s] sync subnet to delete subnet success, accountID: %s, count: %d, rid: %s", enumor.HuaWei,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.SubnetCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -82,7 +82,8 @@ func (cli *client) ArgsTplAddress(kt *kit.Kit, params *SyncBaseParams, opt *Sync
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeargstpl.TCloudArgsTplAddress,
-		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](fromCloud, fromDB, isChangeAddress)
+		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](kt, enumor.ArgumentTemplateResType,
+		fromCloud, fromDB, isChangeAddress)
 
 	logs.Infof("[%s] hcservice sync argument template diff address success, addNum: %d, updateNum: %d, delNum: %d, "+
 		"rid: %s", enumor.TCloud, len(addSlice), len(updateMap), len(delCloudIDs), kt.Rid)
@@ -97,18 +98,24 @@ func (cli *client) ArgsTplAddress(kt *kit.Kit, params *SyncBaseParams, opt *Sync
 		if err = cli.createAddress(kt, params.AccountID, opt, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.ArgumentTemplateResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateAddress(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.ArgumentTemplateResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
 }
 
 func (cli *client) deleteAddress(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.ArgumentTemplateResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("hcservice resource sync failed, delCloudIDs is <= 0, not delete")
 	}
@@ -141,6 +148,8 @@ func (cli *client) deleteAddress(kt *kit.Kit, accountID, region string, delCloud
 	logs.Infof("[%s] sync argument template to delete address success, accountID: %s, count: %d, rid: %s",
 		enumor.TCloud, accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.ArgumentTemplateResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -519,7 +528,8 @@ func (cli *client) ArgsTplAddressGroup(kt *kit.Kit, params *SyncBaseParams, opt
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeargstpl.TCloudArgsTplAddressGroup,
-		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](fromCloud, fromDB, isChangeAddressGroup)
+		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](kt, enumor.ArgumentTemplateResType,
+		fromCloud, fromDB, isChangeAddressGroup)
 
 	logs.Infof("[%s] hcservice sync argument template diff address group success, addNum: %d, updateNum: %d, "+
 		"delNum: %d, rid: %s", enumor.TCloud, len(addSlice), len(updateMap), len(delCloudIDs), kt.Rid)
@@ -534,18 +544,24 @@ func (cli *client) ArgsTplAddressGroup(kt *kit.Kit, params *SyncBaseParams, opt
 		if err = cli.createAddressGroup(kt, params.AccountID, opt, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.ArgumentTemplateResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateAddressGroup(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.ArgumentTemplateResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
 }
 
 func (cli *client) deleteAddressGroup(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.ArgumentTemplateResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("hcservice resource sync failed, delCloudIDs is <= 0, not delete")
 	}
@@ -578,6 +594,8 @@ func (cli *client) deleteAddressGroup(kt *kit.Kit, accountID, region string, del
 	logs.Infof("[%s] sync argument template to delete address group success, accountID: %s, count: %d, rid: %s",
 		enumor.TCloud, accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.ArgumentTemplateResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -774,7 +792,8 @@ func (cli *client) ArgsTplService(kt *kit.Kit, params *SyncBaseParams, opt *Sync
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeargstpl.TCloudArgsTplService,
-		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](fromCloud, fromDB, isChangeService)
+		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](kt, enumor.ArgumentTemplateResType,
+		fromCloud, fromDB, isChangeService)
 
 	logs.Infof("[%s] hcservice sync argument template diff service success, addNum: %d, updateNum: %d, delNum: %d, "+
 		"rid: %s", enumor.TCloud, len(addSlice), len(updateMap), len(delCloudIDs), kt.Rid)
@@ -789,18 +808,24 @@ func (cli *client) ArgsTplService(kt *kit.Kit, params *SyncBaseParams, opt *Sync
 		if err = cli.createService(kt, params.AccountID, opt, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.ArgumentTemplateResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateService(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.ArgumentTemplateResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
 }
 
 func (cli *client) deleteService(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.ArgumentTemplateResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("hcservice resource sync failed, delCloudIDs is <= 0, not delete")
 	}
@@ -833,6 +858,8 @@ func (cli *client) deleteService(kt *kit.Kit, accountID, region string, delCloud
 	logs.Infof("[%s] sync argument template to delete service success, accountID: %s, count: %d, rid: %s",
 		enumor.TCloud, accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.ArgumentTemplateResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -1048,7 +1075,8 @@ func (cli *client) ArgsTplServiceGroup(kt *kit.Kit, params *SyncBaseParams, opt
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeargstpl.TCloudArgsTplServiceGroup,
-		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](fromCloud, fromDB, isChangeServiceGroup)
+		*coreargstpl.ArgsTpl[coreargstpl.TCloudArgsTplExtension]](kt, enumor.ArgumentTemplateResType,
+		fromCloud, fromDB, isChangeServiceGroup)
 
 	logs.Infof("[%s] hcservice sync argument template diff service group success, addNum: %d, updateNum: %d, "+
 		"delNum: %d, rid: %s", enumor.TCloud, len(addSlice), len(updateMap), len(delCloudIDs), kt.Rid)
@@ -1063,18 +1091,24 @@ func (cli *client) ArgsTplServiceGroup(kt *kit.Kit, params *SyncBaseParams, opt
 		if err = cli.createServiceGroup(kt, params.AccountID, opt, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.ArgumentTemplateResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateServiceGroup(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.ArgumentTemplateResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
 }
 
 func (cli *client) deleteServiceGroup(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.ArgumentTemplateResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("hcservice resource sync failed, delCloudIDs is <= 0, not delete")
 	}
@@ -1107,6 +1141,8 @@ func (cli *client) deleteServiceGroup(kt *kit.Kit, accountID, region string, del
 	logs.Infof("[%s] sync argument template to delete service group success, accountID: %s, count: %d, rid: %s",
 		enumor.TCloud, accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.ArgumentTemplateResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -80,7 +80,7 @@ func (cli *client) Cert(kt *kit.Kit, params *SyncBaseParams, opt *SyncCertOption
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typecert.TCloudCert, *corecert.Cert[corecert.TCloudCertExtension]](
-		certFromCloud, certFromDB, isCertChange)
+		kt, enumor.CertCloudResType, certFromCloud, certFromDB, isCertChange)
 
 	if err = cli.deleteCert(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
 		return nil, err
@@ -89,15 +89,21 @@ func (cli *client) Cert(kt *kit.Kit, params *SyncBaseParams, opt *SyncCertOption
 	if err = cli.createCert(kt, params.AccountID, opt, addSlice); err != nil {
 		return nil, err
 	}
+	common.CountCreate(kt, enumor.CertCloudResType, len(addSlice))
 
 	if err = cli.updateCert(kt, params.AccountID, updateMap); err != nil {
 		return nil, err
 	}
+	common.CountUpdate(kt, enumor.CertCloudResType, len(updateMap))
 
 	return new(SyncResult), nil
 }
 
 func (cli *client) deleteCert(kt *kit.Kit, accountID, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.CertCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return nil
 	}
@@ -111,6 +117,8 @@ func (cli *client) deleteCert(kt *kit.Kit, accountID, region string, delCloudIDs
 		return err
 	}
 
+	common.CountDelete(kt, enumor.CertCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -70,7 +70,7 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesdisk.TCloudDisk, *coredisk.Disk[coredisk.TCloudExtension]](
-		diskFromCloud, diskFromDB, isDiskChange)
+		kt, enumor.DiskCloudResType, diskFromCloud, diskFromDB, isDiskChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteDisk(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -82,18 +82,29 @@ func (cli *client) Disk(kt *kit.Kit, params *SyncBaseParams, opt *SyncDiskOption
 		if err = cli.createDisk(kt, params.AccountID, params.Region, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.DiskCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateDisk(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.DiskCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.TCloud, params.AccountID, enumor.DiskCloudResType,
+		diskFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
 }
 
 func (cli *client) deleteDisk(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.DiskCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return fmt.Errorf("delCloudIDs is <= 0, not delete")
 	}
@@ -126,6 +137,8 @@ func (cli *client) deleteDisk(kt *kit.Kit, accountID string, region string, delC
 	logs.Infof("[%s] sync disk to delete disk success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.DiskCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
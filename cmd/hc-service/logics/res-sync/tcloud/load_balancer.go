@@ -110,7 +110,7 @@ func (cli *client) LoadBalancer(kt *kit.Kit, params *SyncBaseParams, opt *SyncLB
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.TCloudClb, corelb.TCloudLoadBalancer](
-		lbFromCloud, lbFromDB, isLBChange)
+		kt, enumor.LoadBalancerCloudResType, lbFromCloud, lbFromDB, isLBChange)
 
 	// 删除云上已经删除的负载均衡实例
 	if err = cli.deleteLoadBalancer(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -122,10 +122,12 @@ func (cli *client) LoadBalancer(kt *kit.Kit, params *SyncBaseParams, opt *SyncLB
 	if err != nil {
 		return nil, err
 	}
+	common.CountCreate(kt, enumor.LoadBalancerCloudResType, len(addSlice))
 	// 更新变更负载均衡
 	if err = cli.updateLoadBalancer(kt, params.AccountID, params.Region, updateMap); err != nil {
 		return nil, err
 	}
+	common.CountUpdate(kt, enumor.LoadBalancerCloudResType, len(updateMap))
 	return new(SyncResult), nil
 }
 
@@ -296,6 +298,10 @@ func (cli *client) updateLoadBalancer(kt *kit.Kit, accountID string, region stri
 // deleteLoadBalancer call data service to delete lb
 func (cli *client) deleteLoadBalancer(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
 
+	if common.PlanDelete(kt, enumor.LoadBalancerCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return nil
 	}
@@ -328,6 +334,8 @@ func (cli *client) deleteLoadBalancer(kt *kit.Kit, accountID string, region stri
 	logs.Infof("[%s] sync to delete lb success, accountID: %s, count: %d, rid: %s",
 		enumor.TCloud, accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.LoadBalancerCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
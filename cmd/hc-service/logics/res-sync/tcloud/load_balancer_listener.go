@@ -207,7 +207,7 @@ func (cli *client) listener(kt *kit.Kit, params *SyncBaseParams, opt *SyncListen
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.TCloudListener, corelb.TCloudListener](
-		cloudListeners, dbListeners, isListenerChange)
+		kt, enumor.ListenerCloudResType, cloudListeners, dbListeners, isListenerChange)
 
 	// 删除云上已经删除的监听器实例
 	if err = cli.deleteListener(kt, delCloudIDs); err != nil {
@@ -219,10 +219,12 @@ func (cli *client) listener(kt *kit.Kit, params *SyncBaseParams, opt *SyncListen
 	if err != nil {
 		return err
 	}
+	common.CountCreate(kt, enumor.ListenerCloudResType, len(addSlice))
 	// 更新变更监听器，不更新对应四层/七层 规则
 	if err = cli.updateListener(kt, opt.BizID, updateMap); err != nil {
 		return err
 	}
+	common.CountUpdate(kt, enumor.ListenerCloudResType, len(updateMap))
 
 	// 同步监听器下的四层/七层规则
 	_, err = cli.loadBalancerRule(kt, opt, cloudListeners)
@@ -287,6 +289,10 @@ func (cli *client) listListenerFromDB(kt *kit.Kit, lbID string, cloudIds []strin
 }
 
 func (cli *client) deleteListener(kt *kit.Kit, cloudIds []string) error {
+	if common.PlanDelete(kt, enumor.ListenerCloudResType, cloudIds) {
+		return nil
+	}
+
 	if len(cloudIds) == 0 {
 		return nil
 	}
@@ -297,6 +303,9 @@ func (cli *client) deleteListener(kt *kit.Kit, cloudIds []string) error {
 			cloudIds, err, kt.Rid)
 		return err
 	}
+
+	common.CountDelete(kt, enumor.ListenerCloudResType, len(cloudIds))
+
 	return nil
 }
 
@@ -106,13 +106,14 @@ func (cli *client) LoadBalancerLayer4Rule(kt *kit.Kit, lbID string, l4Listeners
 	}
 
 	// 新增实例应该在同步监听器的时候附带创建，云上已删除的规则应该在监听器同步时被删除
-	_, updateMap, _ := common.Diff[typeslb.TCloudListener, corelb.TCloudLbUrlRule](
+	_, updateMap, _ := common.Diff[typeslb.TCloudListener, corelb.TCloudLbUrlRule](kt, enumor.TCLoudUrlRuleCloudResType,
 		l4Listeners, dbRules, isLayer4RuleChange)
 
 	// 更新变更监听器，更新对应四层/七层 规则
 	if err = cli.updateLayer4Rule(kt, updateMap); err != nil {
 		return nil, err
 	}
+	common.CountUpdate(kt, enumor.TCLoudUrlRuleCloudResType, len(updateMap))
 
 	return new(SyncResult), nil
 
@@ -138,7 +139,7 @@ func (cli *client) ListenerLayer7Rule(kt *kit.Kit, opt *SyncLayer7RuleOption, cl
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typeslb.TCloudUrlRule, corelb.TCloudLbUrlRule](
-		cloudRules, dbRules, isLayer7RuleChange)
+		kt, enumor.TCLoudUrlRuleCloudResType, cloudRules, dbRules, isLayer7RuleChange)
 
 	if err = cli.deleteLayer7Rule(kt, delCloudIDs); err != nil {
 		return nil, err
@@ -147,10 +148,12 @@ func (cli *client) ListenerLayer7Rule(kt *kit.Kit, opt *SyncLayer7RuleOption, cl
 	if err = cli.updateLayer7Rule(kt, updateMap); err != nil {
 		return nil, err
 	}
+	common.CountUpdate(kt, enumor.TCLoudUrlRuleCloudResType, len(updateMap))
 
 	if _, err = cli.createLayer7Rule(kt, opt, addSlice); err != nil {
 		return nil, err
 	}
+	common.CountCreate(kt, enumor.TCLoudUrlRuleCloudResType, len(addSlice))
 	return nil, nil
 }
 
@@ -217,6 +220,10 @@ func (cli *client) updateLayer4Rule(kt *kit.Kit, updateMap map[string]typeslb.TC
 
 func (cli *client) deleteLayer7Rule(kt *kit.Kit, cloudIds []string) error {
 
+	if common.PlanDelete(kt, enumor.TCLoudUrlRuleCloudResType, cloudIds) {
+		return nil
+	}
+
 	if len(cloudIds) == 0 {
 		return nil
 	}
@@ -229,6 +236,9 @@ func (cli *client) deleteLayer7Rule(kt *kit.Kit, cloudIds []string) error {
 			return err
 		}
 	}
+
+	common.CountDelete(kt, enumor.TCLoudUrlRuleCloudResType, len(cloudIds))
+
 	return nil
 }
 
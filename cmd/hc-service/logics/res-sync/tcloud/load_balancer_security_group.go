@@ -39,6 +39,10 @@ import (
 
 // lbSgRel 同步于安全组的关联关系，按lb、有序
 func (cli *client) lbSgRel(kt *kit.Kit, params *SyncBaseParams, lbInfo []corelb.TCloudLoadBalancer) error {
+	// 关联关系由资源变更推导得出，同步计划模式下不处理
+	if common.IsPlan(kt) {
+		return nil
+	}
 
 	lbIDs := make([]string, 0, len(lbInfo))
 	// lb cloud id -> lb local id
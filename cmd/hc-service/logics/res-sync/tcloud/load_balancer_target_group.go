@@ -24,6 +24,7 @@ import (
 	"fmt"
 
 	"hcm/cmd/hc-service/logics/res-sync/common"
+	synclogic "hcm/cmd/hc-service/logics/sync"
 	typeslb "hcm/pkg/adaptor/types/load-balancer"
 	"hcm/pkg/api/core"
 	corelb "hcm/pkg/api/core/cloud/load-balancer"
@@ -79,6 +80,14 @@ func (cli *client) LocalTargetGroup(kt *kit.Kit, param *SyncBaseParams, opt *Syn
 			continue
 		}
 
+		// 同步计划模式下仅记录健康检查的变更
+		if plan, ok := synclogic.PlanFromKit(kt); ok {
+			plan.AddUpdate(enumor.TargetGroupCloudResType, tg.CloudID, tg.ID,
+				map[string]interface{}{"health_check": convHealthCheck(tgCloudHealthMap[tg.CloudID])},
+				map[string]interface{}{"health_check": tg.HealthCheck})
+			continue
+		}
+
 		// 更新 健康检查
 		updateReq := &dataproto.TargetGroupUpdateReq{
 			IDs:         []string{tg.ID},
@@ -245,7 +254,8 @@ func (cli *client) compareTargetsChange(kt *kit.Kit, accountID, tgID string, clo
 	cloudRsList := slice.Map(cloudTargets, func(rs *tclb.Backend) typeslb.Backend {
 		return typeslb.Backend{Backend: rs}
 	})
-	addSlice, updateMap, delLocalIDs := diff[typeslb.Backend, corelb.BaseTarget](cloudRsList, dbRsList, isRsChange)
+	addSlice, updateMap, delLocalIDs := diff[typeslb.Backend, corelb.BaseTarget](kt, cloudRsList, dbRsList,
+		isRsChange)
 
 	if err = cli.deleteRs(kt, delLocalIDs); err != nil {
 		return err
@@ -317,6 +327,11 @@ func (cli *client) createLocalTargetGroupL7(kt *kit.Kit, opt *SyncListenerOption
 		BindingStatus:       enumor.SuccessBindingStatus,
 	}
 
+	// 本地目标组是平台内部数据，不属于云上资源，同步计划模式下不创建
+	if common.IsPlan(kt) {
+		return nil
+	}
+
 	tgCreateReq := &dataproto.TCloudBatchCreateTgWithRelReq{
 		TargetGroups: []dataproto.CreateTargetGroupWithRel[corelb.TCloudTargetGroupExtension]{tgCreate},
 	}
@@ -395,6 +410,11 @@ func (cli *client) createLocalTargetGroupL4(kt *kit.Kit, opt *SyncListenerOption
 		BindingStatus:       enumor.SuccessBindingStatus,
 	}
 
+	// 本地目标组是平台内部数据，不属于云上资源，同步计划模式下不创建
+	if common.IsPlan(kt) {
+		return nil
+	}
+
 	tgCreateReq := &dataproto.TCloudBatchCreateTgWithRelReq{
 		TargetGroups: []dataproto.CreateTargetGroupWithRel[corelb.TCloudTargetGroupExtension]{tgCreate},
 	}
@@ -448,6 +468,10 @@ func (cli *client) listListenerWithRule(kt *kit.Kit, listenerCloudID string) (
 
 // 按cloudInstID 删除目标组中的rs
 func (cli *client) deleteRs(kt *kit.Kit, localIds []string) error {
+	if common.PlanDeleteByIDs(kt, enumor.TargetCloudResType, localIds) {
+		return nil
+	}
+
 	if len(localIds) == 0 {
 		return nil
 	}
@@ -460,6 +484,8 @@ func (cli *client) deleteRs(kt *kit.Kit, localIds []string) error {
 		}
 	}
 
+	common.CountDelete(kt, enumor.TargetCloudResType, len(localIds))
+
 	return nil
 }
 
@@ -647,15 +673,17 @@ type SyncTargetGroupOption struct {
 }
 
 // diff 该diff 和common.Diff的区别在于该接口的delete返回本地id
-func diff[CloudType common.CloudResType, DBType common.DBResType](dataFromCloud []CloudType, dataFromDB []DBType,
-	isChange func(CloudType, DBType) bool) (newAddData []CloudType, updateMap map[string]CloudType,
-	delLocalIDs []string) {
+func diff[CloudType common.CloudResType, DBType common.DBResType](kt *kit.Kit, dataFromCloud []CloudType,
+	dataFromDB []DBType, isChange func(CloudType, DBType) bool) (newAddData []CloudType,
+	updateMap map[string]CloudType, delLocalIDs []string) {
 
 	dbMap := make(map[string]DBType, len(dataFromDB))
 	for _, one := range dataFromDB {
 		dbMap[one.GetCloudID()] = one
 	}
 
+	plan, isPlan := synclogic.PlanFromKit(kt)
+
 	newAddData = make([]CloudType, 0)
 	updateMap = make(map[string]CloudType, 0)
 	for _, oneFromCloud := range dataFromCloud {
@@ -668,6 +696,10 @@ func diff[CloudType common.CloudResType, DBType common.DBResType](dataFromCloud
 		delete(dbMap, oneFromCloud.GetCloudID())
 		if isChange(oneFromCloud, oneFromDB) {
 			updateMap[oneFromDB.GetID()] = oneFromCloud
+			if isPlan {
+				plan.AddUpdate(enumor.TargetCloudResType, oneFromDB.GetCloudID(), oneFromDB.GetID(), oneFromCloud,
+					oneFromDB)
+			}
 		}
 	}
 
@@ -677,6 +709,15 @@ func diff[CloudType common.CloudResType, DBType common.DBResType](dataFromCloud
 		delLocalIDs = append(delLocalIDs, item.GetID())
 	}
 
+	// 同步计划模式下仅记录变更，返回空结果
+	if isPlan {
+		for _, one := range newAddData {
+			plan.AddCreate(enumor.TargetCloudResType, one.GetCloudID())
+		}
+		plan.AddDeleteByIDs(enumor.TargetCloudResType, delLocalIDs...)
+		return make([]CloudType, 0), make(map[string]CloudType), make([]string, 0)
+	}
+
 	return newAddData, updateMap, delLocalIDs
 }
 
@@ -71,7 +71,7 @@ func (cli *client) Region(kt *kit.Kit, opt *SyncRegionOption) (*SyncResult, erro
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[typesregion.TCloudRegion, cloudcore.TCloudRegion](
-		regionFromCloud, regionFromDB, isRegionChange)
+		kt, enumor.RegionCloudResType, regionFromCloud, regionFromDB, isRegionChange)
 
 	if len(delCloudIDs) > 0 {
 		if err := cli.deleteRegion(kt, opt, delCloudIDs); err != nil {
@@ -83,12 +83,14 @@ func (cli *client) Region(kt *kit.Kit, opt *SyncRegionOption) (*SyncResult, erro
 		if err = cli.createRegion(kt, opt, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.RegionCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateRegion(kt, opt, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.RegionCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -163,6 +165,10 @@ func (cli *client) updateRegion(kt *kit.Kit, opt *SyncRegionOption,
 }
 
 func (cli *client) deleteRegion(kt *kit.Kit, opt *SyncRegionOption, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.RegionCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("region delCloudIDs is <= 0, not delete")
 	}
@@ -199,6 +205,8 @@ func (cli *client) deleteRegion(kt *kit.Kit, opt *SyncRegionOption, delCloudIDs
 	logs.Infof("[%s] sync region to delete region success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.RegionCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
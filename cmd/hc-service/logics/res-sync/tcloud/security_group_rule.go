@@ -20,6 +20,10 @@
 package tcloud
 
 import (
+	"fmt"
+
+	"hcm/cmd/hc-service/logics/res-sync/common"
+	synclogic "hcm/cmd/hc-service/logics/sync"
 	securitygrouprule "hcm/pkg/adaptor/types/security-group-rule"
 	"hcm/pkg/api/core"
 	corecloud "hcm/pkg/api/core/cloud"
@@ -117,7 +121,9 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 
 	updateRules := make(map[string]*corecloud.TCloudSecurityGroupRule)
 	deleteRuleIDs := make([]string, 0)
+	dbRuleMap := make(map[string]corecloud.TCloudSecurityGroupRule, len(rulesFromDB))
 	for _, one := range rulesFromDB {
+		dbRuleMap[one.ID] = one
 		var ruleMap map[int64]*vpc.SecurityGroupPolicy
 		switch one.Type {
 		case enumor.Egress:
@@ -150,6 +156,19 @@ func (cli *client) securityGroupRule(kt *kit.Kit, opt *syncSGRuleOption) (*SyncR
 		createRules = append(createRules, *rule)
 	}
 
+	// 同步计划模式下仅记录规则变更
+	if plan, ok := synclogic.PlanFromKit(kt); ok {
+		for id, rule := range updateRules {
+			plan.AddUpdate(enumor.SecurityGroupRuleCloudResType, sg.CloudID, id, rule, dbRuleMap[id])
+		}
+		for _, rule := range createRules {
+			plan.AddCreate(enumor.SecurityGroupRuleCloudResType,
+				fmt.Sprintf("%s/%s/%d", sg.CloudID, rule.Type, rule.CloudPolicyIndex))
+		}
+		plan.AddDeleteByIDs(enumor.SecurityGroupRuleCloudResType, deleteRuleIDs...)
+		return new(SyncResult), nil
+	}
+
 	if len(deleteRuleIDs) != 0 {
 		if err = cli.deleteSGRule(kt, sg.ID, deleteRuleIDs); err != nil {
 			return nil, err
@@ -324,6 +343,10 @@ func (cli *client) updateSGRule(kt *kit.Kit, sgID string, updateRules map[string
 // deleteSGRule delete security group rule
 func (cli *client) deleteSGRule(kt *kit.Kit, sgID string, delIDs []string) error {
 
+	if common.PlanDeleteByIDs(kt, enumor.SecurityGroupRuleCloudResType, delIDs) {
+		return nil
+	}
+
 	// split rules into batches to avoid reaching batch operation limit
 	delIdBatches := slice.Split(delIDs, constant.BatchOperationMaxLimit)
 	for batchIdx, delIdBatch := range delIdBatches {
@@ -339,6 +362,8 @@ func (cli *client) deleteSGRule(kt *kit.Kit, sgID string, delIDs []string) error
 		}
 	}
 
+	common.CountDelete(kt, enumor.SecurityGroupRuleCloudResType, len(delIDs))
+
 	return nil
 }
 
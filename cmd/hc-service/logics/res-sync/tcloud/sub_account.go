@@ -75,7 +75,8 @@ func (cli *client) SubAccount(kt *kit.Kit, opt *SyncSubAccountOption) (*SyncResu
 	}
 
 	addSlice, updateMap, delCloudIDs := common.Diff[account.TCloudAccount,
-		coresubaccount.SubAccount[coresubaccount.TCloudExtension]](fromCloud, fromDB, isSubAccountChange)
+		coresubaccount.SubAccount[coresubaccount.TCloudExtension]](kt, enumor.SubAccountCloudResType,
+		fromCloud, fromDB, isSubAccountChange)
 
 	account, err := cli.dbCli.TCloud.Account.Get(kt.Ctx, kt.Header(), opt.AccountID)
 	if err != nil {
@@ -93,12 +94,14 @@ func (cli *client) SubAccount(kt *kit.Kit, opt *SyncSubAccountOption) (*SyncResu
 		if err = cli.createSubAccount(kt, account, addSlice); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.SubAccountCloudResType, len(addSlice))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateSubAccount(kt, opt, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.SubAccountCloudResType, len(updateMap))
 	}
 
 	return new(SyncResult), nil
@@ -239,6 +242,10 @@ func (cli *client) createSubAccount(kt *kit.Kit,
 func (cli *client) deleteSubAccount(
 	kt *kit.Kit, opt *SyncSubAccountOption, mainAccountID string, delCloudIDs []string) error {
 
+	if common.PlanDelete(kt, enumor.SubAccountCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) <= 0 {
 		return errors.New("delCloudIDs is required")
 	}
@@ -278,6 +285,8 @@ func (cli *client) deleteSubAccount(
 	logs.Infof("[%s] sync sub account to delete sub account success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		opt.AccountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.SubAccountCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
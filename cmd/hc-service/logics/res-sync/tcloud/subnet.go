@@ -71,7 +71,8 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 	}
 
 	addSubnet, updateMap, delCloudIDs := common.Diff[adtysubnet.TCloudSubnet,
-		cloudcore.Subnet[cloudcore.TCloudSubnetExtension]](subnetFromCloud, subnetFromDB, isTCloudSubnetChange)
+		cloudcore.Subnet[cloudcore.TCloudSubnetExtension]](kt, enumor.SubnetCloudResType,
+		subnetFromCloud, subnetFromDB, isTCloudSubnetChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteSubnet(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -83,18 +84,29 @@ func (cli *client) Subnet(kt *kit.Kit, params *SyncBaseParams, opt *SyncSubnetOp
 		if err = cli.createSubnet(kt, params.AccountID, params.Region, addSubnet); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.SubnetCloudResType, len(addSubnet))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateSubnet(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.SubnetCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.TCloud, params.AccountID, enumor.SubnetCloudResType,
+		subnetFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
 }
 
 func (cli *client) deleteSubnet(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.SubnetCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete subnet, cloudIDs is required")
 	}
@@ -127,6 +139,8 @@ func (cli *client) deleteSubnet(kt *kit.Kit, accountID string, region string, de
 	logs.Infof("[%s] sync subnet to delete subnet success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.SubnetCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
@@ -71,7 +71,7 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 	}
 
 	addVpc, updateMap, delCloudIDs := common.Diff[types.TCloudVpc, cloudcore.Vpc[cloudcore.TCloudVpcExtension]](
-		vpcFromCloud, vpcFromDB, isTCloudVpcChange)
+		kt, enumor.VpcCloudResType, vpcFromCloud, vpcFromDB, isTCloudVpcChange)
 
 	if len(delCloudIDs) > 0 {
 		if err = cli.deleteVpc(kt, params.AccountID, params.Region, delCloudIDs); err != nil {
@@ -83,12 +83,19 @@ func (cli *client) Vpc(kt *kit.Kit, params *SyncBaseParams, opt *SyncVpcOption)
 		if err = cli.createVpc(kt, params.AccountID, addVpc); err != nil {
 			return nil, err
 		}
+		common.CountCreate(kt, enumor.VpcCloudResType, len(addVpc))
 	}
 
 	if len(updateMap) > 0 {
 		if err = cli.updateVpc(kt, params.AccountID, updateMap); err != nil {
 			return nil, err
 		}
+		common.CountUpdate(kt, enumor.VpcCloudResType, len(updateMap))
+	}
+
+	if err = common.SyncResTags(kt, cli.dbCli, enumor.TCloud, params.AccountID, enumor.VpcCloudResType,
+		vpcFromCloud); err != nil {
+		return nil, err
 	}
 
 	return new(SyncResult), nil
@@ -165,6 +172,10 @@ func (cli *client) RemoveVpcDeleteFromCloud(kt *kit.Kit, accountID string, regio
 }
 
 func (cli *client) deleteVpc(kt *kit.Kit, accountID string, region string, delCloudIDs []string) error {
+	if common.PlanDelete(kt, enumor.VpcCloudResType, delCloudIDs) {
+		return nil
+	}
+
 	if len(delCloudIDs) == 0 {
 		return fmt.Errorf("delete vpc, cloudIDs is required")
 	}
@@ -196,6 +207,8 @@ func (cli *client) deleteVpc(kt *kit.Kit, accountID string, region string, delCl
 	logs.Infof("[%s] sync vpc to delete vpc success, accountID: %s, count: %d, rid: %s", enumor.TCloud,
 		accountID, len(delCloudIDs), kt.Rid)
 
+	common.CountDelete(kt, enumor.VpcCloudResType, len(delCloudIDs))
+
 	return nil
 }
 
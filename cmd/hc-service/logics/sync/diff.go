@@ -19,11 +19,17 @@
 
 package sync
 
+import (
+	protosync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
+)
+
 // Diff 对比源数据和目标数据的增/删/改数据。
 func Diff[SourceDataType SourceData, TargetDataType TargetData](
 	sourceData []SourceDataType, targetData []TargetDataType, isChange func(SourceDataType, TargetDataType) bool) (
 	createData []SourceDataType, idUpdateDataMap map[string]SourceDataType, delIDs []string) {
 
+	idUpdateDataMap = make(map[string]SourceDataType)
 	uuidTargetDataMap := make(map[string]TargetDataType, len(targetData))
 	for _, one := range targetData {
 		uuidTargetDataMap[one.GetUUID()] = one
@@ -48,3 +54,41 @@ func Diff[SourceDataType SourceData, TargetDataType TargetData](
 
 	return createData, idUpdateDataMap, delIDs
 }
+
+// DiffPlan 对比源数据和目标数据，返回同步将产生的增/删/改变更，更新的数据附带字段级别的差异。
+func DiffPlan[SourceDataType SourceData, TargetDataType TargetData](resType enumor.CloudResourceType,
+	sourceData []SourceDataType, targetData []TargetDataType, isChange func(SourceDataType, TargetDataType) bool) (
+	changes []protosync.PlanChange) {
+
+	uuidTargetDataMap := make(map[string]TargetDataType, len(targetData))
+	for _, one := range targetData {
+		uuidTargetDataMap[one.GetUUID()] = one
+	}
+
+	for _, oneFromSource := range sourceData {
+		oneFromTarget, exist := uuidTargetDataMap[oneFromSource.GetUUID()]
+		if !exist {
+			changes = append(changes, protosync.PlanChange{ResType: resType, Action: protosync.PlanCreate,
+				CloudID: oneFromSource.GetUUID()})
+			continue
+		}
+
+		delete(uuidTargetDataMap, oneFromSource.GetUUID())
+		if isChange(oneFromSource, oneFromTarget) {
+			changes = append(changes, protosync.PlanChange{
+				ResType: resType,
+				Action:  protosync.PlanUpdate,
+				CloudID: oneFromTarget.GetUUID(),
+				ID:      oneFromTarget.GetID(),
+				Fields:  DiffFields(oneFromSource, oneFromTarget),
+			})
+		}
+	}
+
+	for _, one := range uuidTargetDataMap {
+		changes = append(changes, protosync.PlanChange{ResType: resType, Action: protosync.PlanDelete,
+			CloudID: one.GetUUID(), ID: one.GetID()})
+	}
+
+	return changes
+}
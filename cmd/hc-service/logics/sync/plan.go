@@ -0,0 +1,257 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	gosync "sync"
+
+	protosync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+type planCtxKey struct{}
+
+// Plan 同步计划记录器。计划模式下，同步流程照常从数据源和目标源查询数据并对比，
+// 但不会修改目标源，而是将本应执行的新增/更新/删除记录到 Plan 中。
+type Plan struct {
+	lock    gosync.Mutex
+	changes []protosync.PlanChange
+}
+
+// WithPlan 将 kit 切换为计划模式，返回用于收集变更的 Plan。
+func WithPlan(kt *kit.Kit) *Plan {
+	plan := new(Plan)
+	kt.Ctx = context.WithValue(kt.Ctx, planCtxKey{}, plan)
+	return plan
+}
+
+// PlanFromKit 获取 kit 中的同步计划，第二个返回值表示是否处于计划模式。
+func PlanFromKit(kt *kit.Kit) (*Plan, bool) {
+	if kt == nil || kt.Ctx == nil {
+		return nil, false
+	}
+
+	plan, ok := kt.Ctx.Value(planCtxKey{}).(*Plan)
+	return plan, ok && plan != nil
+}
+
+// IsPlan 是否处于计划模式。
+func IsPlan(kt *kit.Kit) bool {
+	_, ok := PlanFromKit(kt)
+	return ok
+}
+
+// Add 记录变更。
+func (p *Plan) Add(changes ...protosync.PlanChange) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.changes = append(p.changes, changes...)
+}
+
+// AddCreate 记录新增资源。
+func (p *Plan) AddCreate(resType enumor.CloudResourceType, cloudIDs ...string) {
+	changes := make([]protosync.PlanChange, 0, len(cloudIDs))
+	for _, cloudID := range cloudIDs {
+		changes = append(changes, protosync.PlanChange{ResType: resType, Action: protosync.PlanCreate,
+			CloudID: cloudID})
+	}
+	p.Add(changes...)
+}
+
+// AddUpdate 记录更新资源，source 为数据源数据，target 为目标源数据。
+func (p *Plan) AddUpdate(resType enumor.CloudResourceType, cloudID, id string, source, target interface{}) {
+	p.Add(protosync.PlanChange{
+		ResType: resType,
+		Action:  protosync.PlanUpdate,
+		CloudID: cloudID,
+		ID:      id,
+		Fields:  DiffFields(source, target),
+	})
+}
+
+// AddDelete 记录删除资源。
+func (p *Plan) AddDelete(resType enumor.CloudResourceType, cloudIDs ...string) {
+	changes := make([]protosync.PlanChange, 0, len(cloudIDs))
+	for _, cloudID := range cloudIDs {
+		changes = append(changes, protosync.PlanChange{ResType: resType, Action: protosync.PlanDelete,
+			CloudID: cloudID})
+	}
+	p.Add(changes...)
+}
+
+// AddDeleteByIDs 记录删除资源，适用于仅持有本地资源ID的场景。
+func (p *Plan) AddDeleteByIDs(resType enumor.CloudResourceType, ids ...string) {
+	changes := make([]protosync.PlanChange, 0, len(ids))
+	for _, id := range ids {
+		changes = append(changes, protosync.PlanChange{ResType: resType, Action: protosync.PlanDelete, ID: id})
+	}
+	p.Add(changes...)
+}
+
+// Result 汇总同步计划。同一资源可能在分页同步中被多次对比，相同资源的相同变更只保留一条。
+func (p *Plan) Result(vendor enumor.Vendor, resType enumor.CloudResourceType) *protosync.PlanResult {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	result := &protosync.PlanResult{
+		Vendor:  vendor,
+		ResType: resType,
+		Changes: make([]protosync.PlanChange, 0, len(p.changes)),
+	}
+
+	exists := make(map[string]struct{}, len(p.changes))
+	for _, one := range p.changes {
+		key := strings.Join([]string{string(one.ResType), string(one.Action), one.CloudID, one.ID}, "/")
+		if _, exist := exists[key]; exist {
+			continue
+		}
+		exists[key] = struct{}{}
+
+		switch one.Action {
+		case protosync.PlanCreate:
+			result.Summary.CreateCount++
+		case protosync.PlanUpdate:
+			result.Summary.UpdateCount++
+		case protosync.PlanDelete:
+			result.Summary.DeleteCount++
+		}
+		result.Changes = append(result.Changes, one)
+	}
+
+	return result
+}
+
+// planIgnoreFields 对比字段差异时忽略的目标源维护字段
+var planIgnoreFields = map[string]struct{}{
+	"id":          {},
+	"creator":     {},
+	"reviser":     {},
+	"created_at":  {},
+	"updated_at":  {},
+	"sync_time":   {},
+	"bk_biz_id":   {},
+	"bk_cloud_id": {},
+}
+
+// DiffFields 对比数据源和目标源数据的字段差异。
+// 两者均按 json 展开为以"."分隔的字段路径，仅对比双方都存在的字段；目标源中 extension 下的字段
+// 同时以去掉 extension 前缀后的路径参与对比，以兼容云上数据将扩展字段平铺的结构。
+func DiffFields(source, target interface{}) []protosync.PlanFieldDiff {
+	sourceFields := flattenFields(source)
+	targetFields := flattenFields(target)
+
+	targetAlias := make(map[string]string, len(targetFields))
+	for field := range targetFields {
+		targetAlias[field] = field
+	}
+	for field := range targetFields {
+		trimmed := strings.TrimPrefix(field, "extension.")
+		if trimmed == field {
+			continue
+		}
+		if _, exist := targetAlias[trimmed]; !exist {
+			targetAlias[trimmed] = field
+		}
+	}
+
+	diffs := make([]protosync.PlanFieldDiff, 0)
+	for field, sourceVal := range sourceFields {
+		if _, ignore := planIgnoreFields[field]; ignore {
+			continue
+		}
+
+		targetField, exist := targetAlias[field]
+		if !exist {
+			continue
+		}
+
+		targetVal := targetFields[targetField]
+		if isEmptyValue(sourceVal) && isEmptyValue(targetVal) {
+			continue
+		}
+
+		if !reflect.DeepEqual(sourceVal, targetVal) {
+			diffs = append(diffs, protosync.PlanFieldDiff{Field: targetField, Source: sourceVal, Target: targetVal})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Field < diffs[j].Field
+	})
+
+	return diffs
+}
+
+func flattenFields(data interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fields
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(raw, &value); err != nil {
+		return fields
+	}
+
+	flatten("", value, fields)
+	return fields
+}
+
+func flatten(prefix string, value interface{}, fields map[string]interface{}) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		if len(prefix) != 0 {
+			fields[prefix] = value
+		}
+		return
+	}
+
+	for key, val := range obj {
+		path := key
+		if len(prefix) != 0 {
+			path = prefix + "." + key
+		}
+		flatten(path, val, fields)
+	}
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch val := value.(type) {
+	case nil:
+		return true
+	case string:
+		return len(val) == 0
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	default:
+		return false
+	}
+}
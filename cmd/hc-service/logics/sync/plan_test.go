@@ -0,0 +1,124 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"testing"
+
+	protosync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+type planSource struct {
+	CloudID string `json:"cloud_id"`
+	Name    string `json:"name"`
+	Memo    string `json:"memo"`
+	Region  string `json:"region"`
+}
+
+// GetUUID ...
+func (s planSource) GetUUID() string {
+	return s.CloudID
+}
+
+type planTarget struct {
+	ID        string              `json:"id"`
+	CloudID   string              `json:"cloud_id"`
+	Name      string              `json:"name"`
+	Memo      *string             `json:"memo"`
+	Extension planTargetExtension `json:"extension"`
+}
+
+type planTargetExtension struct {
+	Region string `json:"region"`
+}
+
+// GetUUID ...
+func (t planTarget) GetUUID() string {
+	return t.CloudID
+}
+
+// GetID ...
+func (t planTarget) GetID() string {
+	return t.ID
+}
+
+func TestDiffFields(t *testing.T) {
+	source := planSource{CloudID: "vpc-1", Name: "new", Memo: "", Region: "ap-guangzhou"}
+	target := planTarget{ID: "00000001", CloudID: "vpc-1", Name: "old",
+		Extension: planTargetExtension{Region: "ap-shanghai"}}
+
+	diffs := DiffFields(source, target)
+	if len(diffs) != 2 {
+		t.Fatalf("diff fields length mismatch, want: 2, got: %d, diffs: %+v", len(diffs), diffs)
+	}
+
+	if diffs[0].Field != "extension.region" || diffs[0].Source != "ap-guangzhou" ||
+		diffs[0].Target != "ap-shanghai" {
+		t.Errorf("extension field diff mismatch, got: %+v", diffs[0])
+	}
+
+	if diffs[1].Field != "name" || diffs[1].Source != "new" || diffs[1].Target != "old" {
+		t.Errorf("name field diff mismatch, got: %+v", diffs[1])
+	}
+}
+
+func TestDiffPlan(t *testing.T) {
+	sources := []planSource{{CloudID: "vpc-1", Name: "a"}, {CloudID: "vpc-2", Name: "b"}}
+	targets := []planTarget{{ID: "00000001", CloudID: "vpc-1", Name: "a1"}, {ID: "00000003", CloudID: "vpc-3"}}
+
+	changes := DiffPlan[planSource, planTarget](enumor.VpcCloudResType, sources, targets,
+		func(source planSource, target planTarget) bool {
+			return source.Name != target.Name
+		})
+
+	kt := kit.New()
+	plan := WithPlan(kt)
+	if !IsPlan(kt) {
+		t.Fatalf("kit should be in plan mode")
+	}
+	plan.Add(changes...)
+	// 分页重复对比时，相同变更只统计一次
+	plan.Add(changes...)
+
+	result := plan.Result(enumor.TCloud, enumor.VpcCloudResType)
+	want := protosync.PlanSummary{CreateCount: 1, UpdateCount: 1, DeleteCount: 1}
+	if result.Summary != want {
+		t.Errorf("plan summary mismatch, want: %+v, got: %+v", want, result.Summary)
+	}
+
+	for _, change := range result.Changes {
+		switch change.Action {
+		case protosync.PlanCreate:
+			if change.CloudID != "vpc-2" {
+				t.Errorf("create change mismatch, got: %+v", change)
+			}
+		case protosync.PlanUpdate:
+			if change.ID != "00000001" || len(change.Fields) != 1 || change.Fields[0].Field != "name" {
+				t.Errorf("update change mismatch, got: %+v", change)
+			}
+		case protosync.PlanDelete:
+			if change.ID != "00000003" {
+				t.Errorf("delete change mismatch, got: %+v", change)
+			}
+		}
+	}
+}
@@ -0,0 +1,263 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"hcm/pkg/api/core"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/metrics"
+	"hcm/pkg/serviced"
+	"hcm/pkg/tools/times"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// maxRunReasonLen 执行记录中失败原因的最大长度
+const maxRunReasonLen = 4096
+
+// Run 一次资源同步执行
+type Run struct {
+	Vendor    enumor.Vendor
+	AccountID string
+	Region    string
+	ResType   enumor.CloudResourceType
+	Trigger   enumor.SyncTrigger
+	StartAt   time.Time
+
+	stats *RunStats
+}
+
+// StartRun 开始一次同步执行，为 kit 挂载同步变更统计。
+func StartRun(kt *kit.Kit, vendor enumor.Vendor, accountID, region string, resType enumor.CloudResourceType,
+	trigger enumor.SyncTrigger) *Run {
+
+	return &Run{
+		Vendor:    vendor,
+		AccountID: accountID,
+		Region:    region,
+		ResType:   resType,
+		Trigger:   trigger,
+		StartAt:   time.Now(),
+		stats:     WithRunStats(kt),
+	}
+}
+
+// TriggerFromKit 根据请求用户判断同步触发方式，后台用户发起的同步为定时同步，其余为手动同步。
+func TriggerFromKit(kt *kit.Kit) enumor.SyncTrigger {
+	if kt.User == constant.BackendOperationUserKey {
+		return enumor.TimingSyncTrigger
+	}
+
+	return enumor.ManualSyncTrigger
+}
+
+// RunRecorder 资源同步执行记录器，将每次同步执行持久化到执行记录表并上报同步指标。
+type RunRecorder struct {
+	dataCli *dataservice.Client
+	metric  *runMetric
+}
+
+// NewRunRecorder new run recorder.
+func NewRunRecorder(dataCli *dataservice.Client, register prometheus.Registerer) *RunRecorder {
+	return &RunRecorder{
+		dataCli: dataCli,
+		metric:  initRunMetric(register),
+	}
+}
+
+// Finish 结束同步执行，记录执行结果。执行记录写入失败只打印日志，不影响同步结果。
+func (r *RunRecorder) Finish(kt *kit.Kit, run *Run, syncErr error) {
+	if r == nil || run == nil {
+		return
+	}
+
+	endAt := time.Now()
+	duration := endAt.Sub(run.StartAt)
+
+	status := enumor.SyncSuccess
+	reason := ""
+	if syncErr != nil {
+		status = enumor.SyncFailed
+		reason = truncateRunReason(syncErr.Error())
+	}
+
+	r.metric.observe(run, status, duration)
+
+	total := run.stats.Total()
+	req := &dssync.SyncRunBatchCreateReq{
+		Items: []dssync.SyncRunCreateField{{
+			Vendor:      run.Vendor,
+			AccountID:   run.AccountID,
+			Region:      run.Region,
+			ResType:     run.ResType,
+			TriggerType: run.Trigger,
+			Status:      status,
+			CreateCount: total.CreateCount,
+			UpdateCount: total.UpdateCount,
+			DeleteCount: total.DeleteCount,
+			DurationMs:  uint64(duration.Milliseconds()),
+			Reason:      reason,
+			StartAt:     run.StartAt,
+			EndAt:       endAt,
+		}},
+	}
+	if _, err := r.dataCli.Global.SyncRun.BatchCreate(kt, req); err != nil {
+		logs.Errorf("create sync run failed, err: %v, vendor: %s, account: %s, res type: %s, rid: %s", err,
+			run.Vendor, run.AccountID, run.ResType, kt.Rid)
+	}
+}
+
+func initRunMetric(register prometheus.Registerer) *runMetric {
+	m := new(runMetric)
+
+	m.runCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.SyncSubSys,
+			Name:      "run_total",
+			Help:      "the total count of resource sync runs",
+		}, []string{"vendor", "res_type", "trigger", "status"})
+	register.MustRegister(m.runCounter)
+
+	m.runDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.SyncSubSys,
+			Name:      "run_duration_seconds",
+			Help:      "the duration of resource sync runs",
+			Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+		}, []string{"vendor", "res_type", "trigger"})
+	register.MustRegister(m.runDuration)
+
+	m.changeCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.SyncSubSys,
+			Name:      "resource_changes_total",
+			Help:      "the total count of resources created, updated or deleted by resource sync",
+		}, []string{"vendor", "res_type", "action"})
+	register.MustRegister(m.changeCounter)
+
+	m.consecutiveFailures = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.SyncSubSys,
+			Name:      "account_consecutive_failures",
+			Help:      "the count of consecutive failed sync runs of an account's resource type",
+		}, []string{"vendor", "account_id", "res_type"})
+	register.MustRegister(m.consecutiveFailures)
+
+	return m
+}
+
+type runMetric struct {
+	// runCounter 同步执行次数
+	runCounter *prometheus.CounterVec
+
+	// runDuration 同步执行耗时
+	runDuration *prometheus.HistogramVec
+
+	// changeCounter 同步新增、更新、删除的资源数量，按实际变更的资源类型统计
+	changeCounter *prometheus.CounterVec
+
+	// consecutiveFailures 账号下资源类型连续同步失败次数，同步成功后归零，用于对持续同步失败的账号告警
+	consecutiveFailures *prometheus.GaugeVec
+}
+
+func (m *runMetric) observe(run *Run, status enumor.SyncStatus, duration time.Duration) {
+	m.runCounter.WithLabelValues(string(run.Vendor), string(run.ResType), string(run.Trigger),
+		string(status)).Inc()
+	m.runDuration.WithLabelValues(string(run.Vendor), string(run.ResType), string(run.Trigger)).
+		Observe(duration.Seconds())
+
+	for _, one := range run.stats.Counts() {
+		m.changeCounter.WithLabelValues(string(run.Vendor), string(one.ResType), "create").
+			Add(float64(one.CreateCount))
+		m.changeCounter.WithLabelValues(string(run.Vendor), string(one.ResType), "update").
+			Add(float64(one.UpdateCount))
+		m.changeCounter.WithLabelValues(string(run.Vendor), string(one.ResType), "delete").
+			Add(float64(one.DeleteCount))
+	}
+
+	failures := m.consecutiveFailures.WithLabelValues(string(run.Vendor), run.AccountID, string(run.ResType))
+	if status == enumor.SyncFailed {
+		failures.Inc()
+		return
+	}
+	failures.Set(0)
+}
+
+// deleteExpiredRunLimit 单次删除过期执行记录的最大数量
+const deleteExpiredRunLimit = 1000
+
+// CleanExpired 定期清理保留时长之外的执行记录，仅主节点执行。
+func (r *RunRecorder) CleanExpired(state serviced.State, retentionDays uint) {
+	retention := time.Duration(retentionDays) * 24 * time.Hour
+	for {
+		time.Sleep(time.Hour)
+
+		if !state.IsMaster() {
+			continue
+		}
+
+		kt := core.NewBackendKit()
+		before := time.Now().Add(-retention)
+		var total uint
+		for {
+			req := &dssync.SyncRunDeleteExpiredReq{Before: before, Limit: deleteExpiredRunLimit}
+			result, err := r.dataCli.Global.SyncRun.DeleteExpired(kt, req)
+			if err != nil {
+				logs.Errorf("delete expired sync run failed, err: %v, before: %s, rid: %s", err, before, kt.Rid)
+				break
+			}
+
+			total += result.DeletedCount
+			if result.DeletedCount < deleteExpiredRunLimit {
+				break
+			}
+		}
+
+		if total != 0 {
+			logs.Infof("delete %d expired sync runs created before %s, rid: %s", total,
+				times.ConvStdTimeFormat(before), kt.Rid)
+		}
+	}
+}
+
+// truncateRunReason 按字符边界截断失败原因，避免截断后出现不完整的UTF-8字符
+func truncateRunReason(reason string) string {
+	if len(reason) <= maxRunReasonLen {
+		return reason
+	}
+
+	end := maxRunReasonLen
+	for end > 0 && !utf8.RuneStart(reason[end]) {
+		end--
+	}
+	return reason[:end]
+}
@@ -0,0 +1,121 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"context"
+	"sort"
+	gosync "sync"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+type runStatsCtxKey struct{}
+
+// RunStats 一次同步执行的变更统计，按资源类型分别记录新增、更新、删除的数量，
+// 同步关联资源（如安全组规则、监听器）时会记录到各自的资源类型下。
+type RunStats struct {
+	lock   gosync.Mutex
+	counts map[enumor.CloudResourceType]*ChangeCount
+}
+
+// ChangeCount 资源变更数量
+type ChangeCount struct {
+	ResType     enumor.CloudResourceType `json:"res_type"`
+	CreateCount uint                     `json:"create_count"`
+	UpdateCount uint                     `json:"update_count"`
+	DeleteCount uint                     `json:"delete_count"`
+}
+
+// WithRunStats 为 kit 挂载同步变更统计，返回用于汇总的 RunStats。
+func WithRunStats(kt *kit.Kit) *RunStats {
+	stats := &RunStats{counts: make(map[enumor.CloudResourceType]*ChangeCount)}
+	kt.Ctx = context.WithValue(kt.Ctx, runStatsCtxKey{}, stats)
+	return stats
+}
+
+// RunStatsFromKit 获取 kit 中的同步变更统计，第二个返回值表示是否需要统计。
+func RunStatsFromKit(kt *kit.Kit) (*RunStats, bool) {
+	if kt == nil || kt.Ctx == nil {
+		return nil, false
+	}
+
+	stats, ok := kt.Ctx.Value(runStatsCtxKey{}).(*RunStats)
+	return stats, ok && stats != nil
+}
+
+// CountChanges 记录资源变更数量，kit 未挂载同步变更统计时不做任何处理。
+func CountChanges(kt *kit.Kit, resType enumor.CloudResourceType, createCount, updateCount, deleteCount int) {
+	stats, ok := RunStatsFromKit(kt)
+	if !ok {
+		return
+	}
+
+	stats.Add(resType, createCount, updateCount, deleteCount)
+}
+
+// Add 记录资源变更数量。
+func (s *RunStats) Add(resType enumor.CloudResourceType, createCount, updateCount, deleteCount int) {
+	if createCount == 0 && updateCount == 0 && deleteCount == 0 {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	count, exist := s.counts[resType]
+	if !exist {
+		count = &ChangeCount{ResType: resType}
+		s.counts[resType] = count
+	}
+	count.CreateCount += uint(createCount)
+	count.UpdateCount += uint(updateCount)
+	count.DeleteCount += uint(deleteCount)
+}
+
+// Counts 返回按资源类型排序的变更数量。
+func (s *RunStats) Counts() []ChangeCount {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	counts := make([]ChangeCount, 0, len(s.counts))
+	for _, one := range s.counts {
+		counts = append(counts, *one)
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].ResType < counts[j].ResType
+	})
+
+	return counts
+}
+
+// Total 返回所有资源类型的变更数量之和。
+func (s *RunStats) Total() ChangeCount {
+	total := ChangeCount{}
+	for _, one := range s.Counts() {
+		total.CreateCount += one.CreateCount
+		total.UpdateCount += one.UpdateCount
+		total.DeleteCount += one.DeleteCount
+	}
+
+	return total
+}
@@ -0,0 +1,59 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+func TestRunStats(t *testing.T) {
+	kt := kit.New()
+
+	// 未挂载统计时不做任何处理
+	CountChanges(kt, enumor.CvmCloudResType, 1, 1, 1)
+	if _, ok := RunStatsFromKit(kt); ok {
+		t.Fatalf("run stats should not exist before WithRunStats")
+	}
+
+	stats := WithRunStats(kt)
+	subKit := kt.NewSubKit()
+	CountChanges(subKit, enumor.CvmCloudResType, 2, 1, 0)
+	CountChanges(subKit, enumor.DiskCloudResType, 1, 0, 3)
+	CountChanges(kt, enumor.CvmCloudResType, 0, 0, 1)
+	CountChanges(kt, enumor.EipCloudResType, 0, 0, 0)
+
+	counts := stats.Counts()
+	if len(counts) != 2 {
+		t.Fatalf("expect 2 resource types, got %d: %+v", len(counts), counts)
+	}
+
+	if counts[0].ResType != enumor.CvmCloudResType || counts[0].CreateCount != 2 || counts[0].UpdateCount != 1 ||
+		counts[0].DeleteCount != 1 {
+		t.Errorf("unexpected cvm count: %+v", counts[0])
+	}
+
+	total := stats.Total()
+	if total.CreateCount != 3 || total.UpdateCount != 1 || total.DeleteCount != 4 {
+		t.Errorf("unexpected total count: %+v", total)
+	}
+}
@@ -22,6 +22,8 @@ package sync
 import (
 	"errors"
 
+	protosync "hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/tools/maps"
@@ -35,6 +37,10 @@ type Interface[BatchSyncParamType any, SourceDataType SourceData, TargetDataType
 	BatchOrAll(kt *kit.Kit, params BatchSyncParamType) (result *Result, err error)
 	// RemoveDeletedFromSource 移除已经从数据源删除的数据。
 	RemoveDeletedFromSource(kt *kit.Kit) (ids []string, err error)
+	// Plan 以计划模式执行分页全量同步，仅返回同步将产生的变更，不修改目标源数据。
+	Plan(kt *kit.Kit) (plan *Plan, err error)
+	// PlanBatchOrAll 以计划模式执行批量/全量同步，仅返回同步将产生的变更，不修改目标源数据。
+	PlanBatchOrAll(kt *kit.Kit, params BatchSyncParamType) (plan *Plan, err error)
 }
 
 // Syncer 同步器
@@ -58,6 +64,11 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) RemoveDe
 			return nil, err
 		}
 
+		// 目标源没有更多数据，结束遍历
+		if len(uuidIDMapFromTarget) == 0 {
+			break
+		}
+
 		if len(uuidIDMapFromTarget) != 0 {
 			// 从数据源查询数据
 			params := sync.Pager.BuildParam(maps.Keys(uuidIDMapFromTarget))
@@ -74,9 +85,18 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) RemoveDe
 				}
 
 				delIDs := maps.Values(uuidIDMapFromTarget)
-				if err = sync.Handler.DeleteTargetData(kt, params, delIDs); err != nil {
-					logs.Errorf("[%s] delete target data failed, err: %v, rid: %s", sync.Handler.Name(), err, kt.Rid)
-					return nil, err
+				if plan, ok := PlanFromKit(kt); ok {
+					for uuid, id := range uuidIDMapFromTarget {
+						plan.Add(protosync.PlanChange{ResType: enumor.CloudResourceType(sync.Handler.Name()),
+							Action: protosync.PlanDelete, CloudID: uuid, ID: id})
+					}
+				} else {
+					if err = sync.Handler.DeleteTargetData(kt, params, delIDs); err != nil {
+						logs.Errorf("[%s] delete target data failed, err: %v, rid: %s", sync.Handler.Name(), err,
+							kt.Rid)
+						return nil, err
+					}
+					CountChanges(kt, enumor.CloudResourceType(sync.Handler.Name()), 0, 0, len(delIDs))
 				}
 
 				ids = append(ids, delIDs...)
@@ -108,9 +128,10 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) AllPages
 
 	delIDs, err := sync.RemoveDeletedFromSource(kt)
 	if err != nil {
-		logs.Errorf("[%s] remove deleted from source failed, err: %v, rid: %s", err, kt.Rid)
+		logs.Errorf("[%s] remove deleted from source failed, err: %v, rid: %s", sync.Handler.Name(), err, kt.Rid)
 		return nil, err
 	}
+	result = new(Result)
 	result.DeleteIDs = append(result.DeleteIDs, delIDs...)
 
 	for {
@@ -180,10 +201,17 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) BatchOrA
 		return new(Result), nil
 	}
 
+	// 计划模式下仅记录变更，不修改目标源
+	if plan, ok := PlanFromKit(kt); ok {
+		plan.Add(DiffPlan(enumor.CloudResourceType(sync.Handler.Name()), sourceData, targetData,
+			sync.Handler.DiffFunc)...)
+		return new(Result), nil
+	}
+
 	// 对比数据源和目标源数据，对增/删/改数据进行分类
 	createData, idUpdateDataMap, delIDs := Diff(sourceData, targetData, sync.Handler.DiffFunc)
+	resType := enumor.CloudResourceType(sync.Handler.Name())
 
-	// TODO: 添加日志和metrics数量统计，和失败请求统计
 	// 删除目标源中多余的数据
 	if len(delIDs) > 0 {
 		if err = sync.Handler.DeleteTargetData(kt, params, delIDs); err != nil {
@@ -191,6 +219,8 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) BatchOrA
 				sync.Handler.Name(), err, params, delIDs, kt.Rid)
 			return nil, err
 		}
+		// 每一步写入成功后再统计，保证部分成功时统计的是实际生效的变更数量
+		CountChanges(kt, resType, 0, 0, len(delIDs))
 	}
 
 	// 更新源数据更新，但目标源没更新的数据
@@ -200,6 +230,7 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) BatchOrA
 				sync.Handler.Name(), err, params, idUpdateDataMap, kt.Rid)
 			return nil, err
 		}
+		CountChanges(kt, resType, 0, len(idUpdateDataMap), 0)
 	}
 
 	var createIDs []string
@@ -211,6 +242,7 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) BatchOrA
 				sync.Handler.Name(), err, params, createData, kt.Rid)
 			return nil, err
 		}
+		CountChanges(kt, resType, len(createIDs), 0, 0)
 	}
 
 	// 聚合处理结果
@@ -219,6 +251,32 @@ func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) BatchOrA
 		CreateIDs: createIDs,
 		UpdateIDs: maps.Keys(idUpdateDataMap),
 	}
+	logs.V(3).Infof("[%s] sync finished, create: %d, update: %d, delete: %d, rid: %s", sync.Handler.Name(),
+		len(result.CreateIDs), len(result.UpdateIDs), len(result.DeleteIDs), kt.Rid)
 
 	return result, nil
 }
+
+// Plan 以计划模式执行分页全量同步，仅返回同步将产生的变更，不修改目标源数据。
+func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) Plan(kt *kit.Kit) (*Plan, error) {
+	planKit := kt.NewSubKit()
+	plan := WithPlan(planKit)
+	if _, err := sync.AllPages(planKit); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// PlanBatchOrAll 以计划模式执行批量/全量同步，仅返回同步将产生的变更，不修改目标源数据。
+func (sync *Syncer[BatchSyncParamType, SourceDataType, TargetDataType]) PlanBatchOrAll(kt *kit.Kit,
+	params BatchSyncParamType) (*Plan, error) {
+
+	planKit := kt.NewSubKit()
+	plan := WithPlan(planKit)
+	if _, err := sync.BatchOrAll(planKit, params); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
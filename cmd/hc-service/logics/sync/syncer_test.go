@@ -0,0 +1,122 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+// countHandler 按预设结果执行写入的同步处理器，用于校验变更统计
+type countHandler struct {
+	sources   []planSource
+	targets   []planTarget
+	updateErr error
+}
+
+// Name ...
+func (h *countHandler) Name() HandlerName {
+	return HandlerName(enumor.VpcCloudResType)
+}
+
+// QueryFromSource ...
+func (h *countHandler) QueryFromSource(_ *kit.Kit, _ string) ([]planSource, error) {
+	return h.sources, nil
+}
+
+// QueryFromTarget ...
+func (h *countHandler) QueryFromTarget(_ *kit.Kit, _ string) ([]planTarget, error) {
+	return h.targets, nil
+}
+
+// DiffFunc ...
+func (h *countHandler) DiffFunc(source planSource, target planTarget) bool {
+	return source.Name != target.Name
+}
+
+// DeleteTargetData ...
+func (h *countHandler) DeleteTargetData(_ *kit.Kit, _ string, _ []string) error {
+	return nil
+}
+
+// CreateTargetData ...
+func (h *countHandler) CreateTargetData(_ *kit.Kit, _ string, createData []planSource) ([]string, error) {
+	ids := make([]string, 0, len(createData))
+	for _, one := range createData {
+		ids = append(ids, one.CloudID)
+	}
+	return ids, nil
+}
+
+// UpdateTargetData ...
+func (h *countHandler) UpdateTargetData(_ *kit.Kit, _ string, _ map[string]planSource) error {
+	return h.updateErr
+}
+
+func TestBatchOrAllCountAfterWrite(t *testing.T) {
+	sources := []planSource{{CloudID: "vpc-1", Name: "a"}, {CloudID: "vpc-2", Name: "b"}}
+	targets := []planTarget{{ID: "00000001", CloudID: "vpc-1", Name: "a1"}, {ID: "00000003", CloudID: "vpc-3"}}
+
+	kt := kit.New()
+	stats := WithRunStats(kt)
+	syncer := &Syncer[string, planSource, planTarget]{Handler: &countHandler{sources: sources, targets: targets}}
+	if _, err := syncer.BatchOrAll(kt, ""); err != nil {
+		t.Fatalf("batch sync failed, err: %v", err)
+	}
+
+	total := stats.Total()
+	if total.CreateCount != 1 || total.UpdateCount != 1 || total.DeleteCount != 1 {
+		t.Errorf("unexpected total count: %+v", total)
+	}
+
+	// 更新失败时，只统计已经写入成功的删除，未执行的创建和失败的更新均不统计
+	kt = kit.New()
+	stats = WithRunStats(kt)
+	syncer.Handler = &countHandler{sources: sources, targets: targets, updateErr: errors.New("update failed")}
+	if _, err := syncer.BatchOrAll(kt, ""); err == nil {
+		t.Fatalf("batch sync should fail when update failed")
+	}
+
+	total = stats.Total()
+	if total.CreateCount != 0 || total.UpdateCount != 0 || total.DeleteCount != 1 {
+		t.Errorf("unexpected total count after partial failure: %+v", total)
+	}
+}
+
+func TestTruncateRunReason(t *testing.T) {
+	if got := truncateRunReason("sync failed"); got != "sync failed" {
+		t.Errorf("short reason should not be truncated, got: %s", got)
+	}
+
+	// 多字节字符跨越截断位置时，截断到该字符之前
+	reason := strings.Repeat("a", maxRunReasonLen-1) + "同步失败"
+	got := truncateRunReason(reason)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncated reason is not valid utf8")
+	}
+	if len(got) != maxRunReasonLen-1 {
+		t.Errorf("truncated reason length mismatch, want: %d, got: %d", maxRunReasonLen-1, len(got))
+	}
+}
@@ -22,7 +22,9 @@ package capability
 
 import (
 	cloudclient "hcm/cmd/hc-service/logics/cloud-adaptor"
+	eventsync "hcm/cmd/hc-service/logics/event-sync"
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	synclogic "hcm/cmd/hc-service/logics/sync"
 	"hcm/pkg/client"
 
 	"github.com/emicklei/go-restful/v3"
@@ -34,4 +36,8 @@ type Capability struct {
 	ClientSet    *client.ClientSet
 	CloudAdaptor *cloudclient.CloudAdaptorClient
 	ResSyncCli   ressync.Interface
+	// Ingestor 云上变更事件接入器，未开启事件增量同步时为空
+	Ingestor *eventsync.Ingestor
+	// RunRecorder 资源同步执行记录器
+	RunRecorder *synclogic.RunRecorder
 }
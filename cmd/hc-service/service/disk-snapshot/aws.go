@@ -0,0 +1,183 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"hcm/pkg/adaptor/types/core"
+	snapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dssnapshot "hcm/pkg/api/data-service/cloud/disk-snapshot"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+)
+
+// CreateAwsDiskSnapshot create aws disk snapshot.
+func (svc *service) CreateAwsDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req, err := decodeCreateReq(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	disk, err := svc.DataCli.Aws.RetrieveDisk(cts.Kit.Ctx, cts.Kit.Header(), req.DiskID)
+	if err != nil {
+		logs.Errorf("get aws disk failed, err: %v, id: %s, rid: %s", err, req.DiskID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	client, err := svc.Adaptor.Aws(cts.Kit, disk.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同一硬盘下已存在同名快照时不再重复创建
+	cloudID, err := createIfNotExist(cts.Kit, enumor.Aws, req.Name, func() (string, error) {
+		listOpt := &snapshot.AwsSnapshotListOption{
+			Region:       disk.Region,
+			CloudDiskIDs: []string{disk.CloudID},
+			Names:        []string{req.Name},
+		}
+		exists, _, err := client.ListDiskSnapshot(cts.Kit, listOpt)
+		if err != nil || len(exists) == 0 {
+			return "", err
+		}
+		return exists[0].GetCloudID(), nil
+	}, func() (string, error) {
+		opt := &snapshot.AwsSnapshotCreateOption{
+			Region:      disk.Region,
+			CloudDiskID: disk.CloudID,
+			Name:        converter.ValToPtr(req.Name),
+		}
+		return client.CreateDiskSnapshot(cts.Kit, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.afterCreate(cts.Kit, enumor.Aws, req, cloudID, func(cloudIDs []string) error {
+		return svc.syncAwsSnapshot(cts.Kit, disk.AccountID, disk.Region, cloudIDs)
+	})
+}
+
+// DeleteAwsDiskSnapshot delete aws disk snapshot.
+func (svc *service) DeleteAwsDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	one, err := svc.decodeDeleteReq(cts, enumor.Aws)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.Adaptor.Aws(cts.Kit, one.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &snapshot.AwsSnapshotDeleteOption{Region: one.Region, CloudID: one.CloudID}
+	if err = client.DeleteDiskSnapshot(cts.Kit, opt); err != nil {
+		logs.Errorf("delete aws disk snapshot failed, err: %v, id: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, svc.deleteSnapshotFromDB(cts.Kit, one.ID)
+}
+
+// SyncAwsDiskSnapshot sync aws disk snapshot.
+func (svc *service) SyncAwsDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(sync.AwsSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, svc.syncAwsSnapshot(cts.Kit, req.AccountID, req.Region, nil)
+}
+
+// syncAwsSnapshot sync aws disk snapshot of region, sync all snapshots of region when cloudIDs is empty.
+func (svc *service) syncAwsSnapshot(kt *kit.Kit, accountID, region string, cloudIDs []string) error {
+	client, err := svc.Adaptor.Aws(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	opt := &syncOption{
+		Vendor:    enumor.Aws,
+		AccountID: accountID,
+		Rules:     []*filter.AtomRule{tools.RuleEqual("region", region)},
+	}
+	if len(cloudIDs) != 0 {
+		opt.Rules = append(opt.Rules, tools.RuleIn("cloud_id", cloudIDs))
+	}
+
+	cloudSnapshots := make([]dssnapshot.CreateReq, 0)
+	listOpt := &snapshot.AwsSnapshotListOption{
+		Region:   region,
+		CloudIDs: cloudIDs,
+		Page:     &core.AwsPage{MaxResults: converter.ValToPtr(int64(core.AwsQueryLimit))},
+	}
+	for {
+		snapshots, nextToken, err := client.ListDiskSnapshot(kt, listOpt)
+		if err != nil {
+			logs.Errorf("list aws disk snapshot failed, err: %v, account: %s, region: %s, rid: %s", err,
+				accountID, region, kt.Rid)
+			return err
+		}
+
+		for _, one := range snapshots {
+			cloudSnapshots = append(cloudSnapshots, convAwsSnapshot(accountID, region, one))
+		}
+
+		if nextToken == nil || len(*nextToken) == 0 {
+			break
+		}
+		listOpt.Page.NextToken = nextToken
+	}
+
+	return svc.syncSnapshot(kt, opt, cloudSnapshots)
+}
+
+func convAwsSnapshot(accountID, region string, one snapshot.AwsSnapshot) dssnapshot.CreateReq {
+	req := dssnapshot.CreateReq{
+		Vendor:      enumor.Aws,
+		AccountID:   accountID,
+		CloudID:     one.GetCloudID(),
+		Name:        one.GetName(),
+		Region:      region,
+		CloudDiskID: converter.PtrToVal(one.VolumeId),
+		DiskSize:    uint64(converter.PtrToVal(one.VolumeSize)),
+		Status:      converter.PtrToVal(one.State),
+		Extension: &coresnapshot.Extension{
+			Encrypted: one.Encrypted,
+			Progress:  converter.PtrToVal(one.Progress),
+		},
+	}
+	if one.StartTime != nil {
+		req.CloudCreatedTime = times.ConvStdTimeFormat(*one.StartTime)
+	}
+
+	return req
+}
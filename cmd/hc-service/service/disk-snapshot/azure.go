@@ -0,0 +1,171 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	snapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dssnapshot "hcm/pkg/api/data-service/cloud/disk-snapshot"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+)
+
+// CreateAzureDiskSnapshot create azure disk snapshot.
+func (svc *service) CreateAzureDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req, err := decodeCreateReq(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	disk, err := svc.DataCli.Azure.RetrieveDisk(cts.Kit.Ctx, cts.Kit.Header(), req.DiskID)
+	if err != nil {
+		logs.Errorf("get azure disk failed, err: %v, id: %s, rid: %s", err, req.DiskID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if disk.Extension == nil {
+		return nil, errf.Newf(errf.InvalidParameter, "azure disk: %s extension is empty", disk.ID)
+	}
+
+	client, err := svc.Adaptor.Azure(cts.Kit, disk.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// azure快照名称在资源组内唯一，已存在同名快照时不再重复创建
+	cloudID, err := createIfNotExist(cts.Kit, enumor.Azure, req.Name, func() (string, error) {
+		listOpt := &snapshot.AzureSnapshotListOption{
+			ResourceGroupName: disk.Extension.ResourceGroupName,
+			Names:             []string{req.Name},
+		}
+		exists, err := client.ListDiskSnapshot(cts.Kit, listOpt)
+		if err != nil || len(exists) == 0 {
+			return "", err
+		}
+		if converter.PtrToVal(exists[0].CloudDiskID) != disk.CloudID {
+			return "", errf.Newf(errf.InvalidParameter, "snapshot name %s is used by other disk", req.Name)
+		}
+		return exists[0].GetCloudID(), nil
+	}, func() (string, error) {
+		opt := &snapshot.AzureSnapshotCreateOption{
+			ResourceGroupName: disk.Extension.ResourceGroupName,
+			Region:            disk.Region,
+			Name:              req.Name,
+			CloudDiskID:       disk.CloudID,
+		}
+		return client.CreateDiskSnapshot(cts.Kit, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.afterCreate(cts.Kit, enumor.Azure, req, cloudID, func(cloudIDs []string) error {
+		return svc.syncAzureSnapshot(cts.Kit, disk.AccountID, disk.Extension.ResourceGroupName, cloudIDs)
+	})
+}
+
+// DeleteAzureDiskSnapshot delete azure disk snapshot.
+func (svc *service) DeleteAzureDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	one, err := svc.decodeDeleteReq(cts, enumor.Azure)
+	if err != nil {
+		return nil, err
+	}
+
+	if one.Extension == nil || len(one.Extension.ResourceGroupName) == 0 {
+		return nil, errf.Newf(errf.InvalidParameter, "azure disk snapshot: %s resource group is empty", one.ID)
+	}
+
+	client, err := svc.Adaptor.Azure(cts.Kit, one.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &snapshot.AzureSnapshotDeleteOption{ResourceGroupName: one.Extension.ResourceGroupName, Name: one.Name}
+	if err = client.DeleteDiskSnapshot(cts.Kit, opt); err != nil {
+		logs.Errorf("delete azure disk snapshot failed, err: %v, id: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, svc.deleteSnapshotFromDB(cts.Kit, one.ID)
+}
+
+// SyncAzureDiskSnapshot sync azure disk snapshot.
+func (svc *service) SyncAzureDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(sync.AzureSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, svc.syncAzureSnapshot(cts.Kit, req.AccountID, req.ResourceGroupName, nil)
+}
+
+// syncAzureSnapshot sync azure disk snapshot of resource group, sync all snapshots of resource group when
+// cloudIDs is empty.
+func (svc *service) syncAzureSnapshot(kt *kit.Kit, accountID, resGroupName string, cloudIDs []string) error {
+	client, err := svc.Adaptor.Azure(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	opt := &syncOption{
+		Vendor:    enumor.Azure,
+		AccountID: accountID,
+		Rules:     []*filter.AtomRule{tools.RuleJSONEqual("extension.resource_group_name", resGroupName)},
+	}
+	if len(cloudIDs) != 0 {
+		opt.Rules = append(opt.Rules, tools.RuleIn("cloud_id", cloudIDs))
+	}
+
+	listOpt := &snapshot.AzureSnapshotListOption{ResourceGroupName: resGroupName, CloudIDs: cloudIDs}
+	snapshots, err := client.ListDiskSnapshot(kt, listOpt)
+	if err != nil {
+		logs.Errorf("list azure disk snapshot failed, err: %v, account: %s, resource group: %s, rid: %s", err,
+			accountID, resGroupName, kt.Rid)
+		return err
+	}
+
+	cloudSnapshots := make([]dssnapshot.CreateReq, 0, len(snapshots))
+	for _, one := range snapshots {
+		cloudSnapshots = append(cloudSnapshots, dssnapshot.CreateReq{
+			Vendor:           enumor.Azure,
+			AccountID:        accountID,
+			CloudID:          one.GetCloudID(),
+			Name:             converter.PtrToVal(one.Name),
+			Region:           converter.PtrToVal(one.Location),
+			CloudDiskID:      converter.PtrToVal(one.CloudDiskID),
+			DiskSize:         uint64(converter.PtrToVal(one.DiskSizeGB)),
+			Status:           converter.PtrToVal(one.ProvisioningState),
+			CloudCreatedTime: converter.PtrToVal(one.TimeCreated),
+			Extension:        &coresnapshot.Extension{ResourceGroupName: resGroupName},
+		})
+	}
+
+	return svc.syncSnapshot(kt, opt, cloudSnapshots)
+}
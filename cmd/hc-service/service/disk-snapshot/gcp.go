@@ -0,0 +1,160 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"hcm/pkg/adaptor/types/core"
+	snapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	dssnapshot "hcm/pkg/api/data-service/cloud/disk-snapshot"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+)
+
+// CreateGcpDiskSnapshot create gcp disk snapshot.
+func (svc *service) CreateGcpDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req, err := decodeCreateReq(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	disk, err := svc.DataCli.Gcp.RetrieveDisk(cts.Kit, req.DiskID)
+	if err != nil {
+		logs.Errorf("get gcp disk failed, err: %v, id: %s, rid: %s", err, req.DiskID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	client, err := svc.Adaptor.Gcp(cts.Kit, disk.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// gcp快照名称在项目内唯一，已存在同名快照时不再重复创建
+	cloudID, err := createIfNotExist(cts.Kit, enumor.Gcp, req.Name, func() (string, error) {
+		listOpt := &snapshot.GcpSnapshotListOption{Names: []string{req.Name}}
+		exists, _, err := client.ListDiskSnapshot(cts.Kit, listOpt)
+		if err != nil || len(exists) == 0 {
+			return "", err
+		}
+		if exists[0].SourceDiskId != disk.CloudID {
+			return "", errf.Newf(errf.InvalidParameter, "snapshot name %s is used by other disk", req.Name)
+		}
+		return exists[0].GetCloudID(), nil
+	}, func() (string, error) {
+		opt := &snapshot.GcpSnapshotCreateOption{
+			Zone:     disk.Zone,
+			DiskName: disk.Name,
+			Name:     req.Name,
+		}
+		return client.CreateDiskSnapshot(cts.Kit, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.afterCreate(cts.Kit, enumor.Gcp, req, cloudID, func(cloudIDs []string) error {
+		return svc.syncGcpSnapshot(cts.Kit, disk.AccountID, cloudIDs)
+	})
+}
+
+// DeleteGcpDiskSnapshot delete gcp disk snapshot.
+func (svc *service) DeleteGcpDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	one, err := svc.decodeDeleteReq(cts, enumor.Gcp)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.Adaptor.Gcp(cts.Kit, one.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = client.DeleteDiskSnapshot(cts.Kit, &snapshot.GcpSnapshotDeleteOption{Name: one.Name}); err != nil {
+		logs.Errorf("delete gcp disk snapshot failed, err: %v, id: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, svc.deleteSnapshotFromDB(cts.Kit, one.ID)
+}
+
+// SyncGcpDiskSnapshot sync gcp disk snapshot.
+func (svc *service) SyncGcpDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(sync.GcpGlobalSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, svc.syncGcpSnapshot(cts.Kit, req.AccountID, nil)
+}
+
+// syncGcpSnapshot sync gcp disk snapshot, gcp snapshot is global resource, sync all snapshots of account when
+// cloudIDs is empty.
+func (svc *service) syncGcpSnapshot(kt *kit.Kit, accountID string, cloudIDs []string) error {
+	client, err := svc.Adaptor.Gcp(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	opt := &syncOption{Vendor: enumor.Gcp, AccountID: accountID, Rules: make([]*filter.AtomRule, 0)}
+	if len(cloudIDs) != 0 {
+		opt.Rules = append(opt.Rules, tools.RuleIn("cloud_id", cloudIDs))
+	}
+
+	cloudSnapshots := make([]dssnapshot.CreateReq, 0)
+	listOpt := &snapshot.GcpSnapshotListOption{
+		CloudIDs: cloudIDs,
+		Page:     &core.GcpPage{PageSize: core.GcpQueryLimit},
+	}
+	for {
+		snapshots, nextToken, err := client.ListDiskSnapshot(kt, listOpt)
+		if err != nil {
+			logs.Errorf("list gcp disk snapshot failed, err: %v, account: %s, rid: %s", err, accountID, kt.Rid)
+			return err
+		}
+
+		for _, one := range snapshots {
+			cloudSnapshots = append(cloudSnapshots, dssnapshot.CreateReq{
+				Vendor:           enumor.Gcp,
+				AccountID:        accountID,
+				CloudID:          one.GetCloudID(),
+				Name:             one.Name,
+				CloudDiskID:      one.SourceDiskId,
+				DiskSize:         uint64(one.DiskSizeGb),
+				Status:           one.Status,
+				CloudCreatedTime: one.CreationTimestamp,
+			})
+		}
+
+		if len(nextToken) == 0 {
+			break
+		}
+		listOpt.Page.PageToken = nextToken
+	}
+
+	return svc.syncSnapshot(kt, opt, cloudSnapshots)
+}
@@ -0,0 +1,216 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	snapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dssnapshot "hcm/pkg/api/data-service/cloud/disk-snapshot"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+)
+
+// huaWeiSnapshotQueryLimit 华为云快照分页查询数量上限
+const huaWeiSnapshotQueryLimit = 1000
+
+// CreateHuaWeiDiskSnapshot create huawei disk snapshot.
+func (svc *service) CreateHuaWeiDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req, err := decodeCreateReq(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	disk, err := svc.DataCli.HuaWei.RetrieveDisk(cts.Kit.Ctx, cts.Kit.Header(), req.DiskID)
+	if err != nil {
+		logs.Errorf("get huawei disk failed, err: %v, id: %s, rid: %s", err, req.DiskID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	client, err := svc.Adaptor.HuaWei(cts.Kit, disk.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同一硬盘下已存在同名快照时不再重复创建
+	cloudID, err := createIfNotExist(cts.Kit, enumor.HuaWei, req.Name, func() (string, error) {
+		listOpt := &snapshot.HuaWeiSnapshotListOption{Region: disk.Region, CloudDiskID: disk.CloudID, Name: req.Name}
+		exists, err := client.ListDiskSnapshot(cts.Kit, listOpt)
+		if err != nil || len(exists) == 0 {
+			return "", err
+		}
+		return exists[0].GetCloudID(), nil
+	}, func() (string, error) {
+		opt := &snapshot.HuaWeiSnapshotCreateOption{
+			Region:      disk.Region,
+			CloudDiskID: disk.CloudID,
+			Name:        converter.ValToPtr(req.Name),
+			// 硬盘挂载在运行中的主机上时也允许创建快照
+			Force: true,
+		}
+		return client.CreateDiskSnapshot(cts.Kit, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.afterCreate(cts.Kit, enumor.HuaWei, req, cloudID, func(cloudIDs []string) error {
+		return svc.syncHuaWeiSnapshot(cts.Kit, disk.AccountID, disk.Region, cloudIDs)
+	})
+}
+
+// DeleteHuaWeiDiskSnapshot delete huawei disk snapshot.
+func (svc *service) DeleteHuaWeiDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	one, err := svc.decodeDeleteReq(cts, enumor.HuaWei)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.Adaptor.HuaWei(cts.Kit, one.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &snapshot.HuaWeiSnapshotDeleteOption{Region: one.Region, CloudID: one.CloudID}
+	if err = client.DeleteDiskSnapshot(cts.Kit, opt); err != nil {
+		logs.Errorf("delete huawei disk snapshot failed, err: %v, id: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, svc.deleteSnapshotFromDB(cts.Kit, one.ID)
+}
+
+// RollbackHuaWeiDiskSnapshot rollback huawei disk by snapshot.
+func (svc *service) RollbackHuaWeiDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	one, err := svc.decodeRollbackReq(cts, enumor.HuaWei)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.Adaptor.HuaWei(cts.Kit, one.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &snapshot.HuaWeiSnapshotRollbackOption{
+		Region:      one.Region,
+		CloudID:     one.CloudID,
+		CloudDiskID: one.CloudDiskID,
+	}
+	if err = client.RollbackDiskSnapshot(cts.Kit, opt); err != nil {
+		logs.Errorf("rollback huawei disk snapshot failed, err: %v, id: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// SyncHuaWeiDiskSnapshot sync huawei disk snapshot.
+func (svc *service) SyncHuaWeiDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(sync.HuaWeiSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, svc.syncHuaWeiSnapshot(cts.Kit, req.AccountID, req.Region, nil)
+}
+
+// syncHuaWeiSnapshot sync huawei disk snapshot of region, sync all snapshots of region when cloudIDs is empty.
+func (svc *service) syncHuaWeiSnapshot(kt *kit.Kit, accountID, region string, cloudIDs []string) error {
+	client, err := svc.Adaptor.HuaWei(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	opt := &syncOption{
+		Vendor:    enumor.HuaWei,
+		AccountID: accountID,
+		Rules:     []*filter.AtomRule{tools.RuleEqual("region", region)},
+	}
+
+	cloudSnapshots := make([]dssnapshot.CreateReq, 0)
+	// 华为云查询接口仅支持指定单个快照ID
+	if len(cloudIDs) != 0 {
+		opt.Rules = append(opt.Rules, tools.RuleIn("cloud_id", cloudIDs))
+		for _, cloudID := range cloudIDs {
+			snapshots, err := client.ListDiskSnapshot(kt, &snapshot.HuaWeiSnapshotListOption{
+				Region:  region,
+				CloudID: cloudID,
+			})
+			if err != nil {
+				logs.Errorf("list huawei disk snapshot failed, err: %v, cloud_id: %s, rid: %s", err, cloudID, kt.Rid)
+				return err
+			}
+
+			for _, one := range snapshots {
+				cloudSnapshots = append(cloudSnapshots, convHuaWeiSnapshot(accountID, region, one))
+			}
+		}
+
+		return svc.syncSnapshot(kt, opt, cloudSnapshots)
+	}
+
+	listOpt := &snapshot.HuaWeiSnapshotListOption{Region: region, Offset: 0, Limit: huaWeiSnapshotQueryLimit}
+	for {
+		snapshots, err := client.ListDiskSnapshot(kt, listOpt)
+		if err != nil {
+			logs.Errorf("list huawei disk snapshot failed, err: %v, account: %s, region: %s, rid: %s", err,
+				accountID, region, kt.Rid)
+			return err
+		}
+
+		for _, one := range snapshots {
+			cloudSnapshots = append(cloudSnapshots, convHuaWeiSnapshot(accountID, region, one))
+		}
+
+		if len(snapshots) < huaWeiSnapshotQueryLimit {
+			break
+		}
+		listOpt.Offset += huaWeiSnapshotQueryLimit
+	}
+
+	return svc.syncSnapshot(kt, opt, cloudSnapshots)
+}
+
+func convHuaWeiSnapshot(accountID, region string, one snapshot.HuaWeiSnapshot) dssnapshot.CreateReq {
+	return dssnapshot.CreateReq{
+		Vendor:           enumor.HuaWei,
+		AccountID:        accountID,
+		CloudID:          one.GetCloudID(),
+		Name:             converter.PtrToVal(one.Name),
+		Region:           region,
+		CloudDiskID:      one.VolumeId,
+		DiskSize:         uint64(one.Size),
+		Status:           one.Status,
+		CloudCreatedTime: one.CreatedAt,
+		Extension: &coresnapshot.Extension{
+			Progress: one.OsExtendedSnapshotAttributesprogress,
+		},
+	}
+}
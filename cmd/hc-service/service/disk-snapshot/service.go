@@ -0,0 +1,237 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot ...
+package disksnapshot
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	cloudclient "hcm/cmd/hc-service/logics/cloud-adaptor"
+	"hcm/cmd/hc-service/service/capability"
+	"hcm/pkg/api/core"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dataproto "hcm/pkg/api/data-service"
+	dssnapshot "hcm/pkg/api/data-service/cloud/disk-snapshot"
+	proto "hcm/pkg/api/hc-service/disk-snapshot"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitService initial the disk snapshot service
+func InitService(cap *capability.Capability) {
+	svc := &service{
+		Adaptor: cap.CloudAdaptor,
+		DataCli: cap.ClientSet.DataService(),
+	}
+
+	h := rest.NewHandler()
+
+	// 创建快照
+	h.Add("CreateTCloudDiskSnapshot", http.MethodPost, "/vendors/tcloud/disk_snapshots/create",
+		svc.CreateTCloudDiskSnapshot)
+	h.Add("CreateAwsDiskSnapshot", http.MethodPost, "/vendors/aws/disk_snapshots/create", svc.CreateAwsDiskSnapshot)
+	h.Add("CreateHuaWeiDiskSnapshot", http.MethodPost, "/vendors/huawei/disk_snapshots/create",
+		svc.CreateHuaWeiDiskSnapshot)
+	h.Add("CreateAzureDiskSnapshot", http.MethodPost, "/vendors/azure/disk_snapshots/create",
+		svc.CreateAzureDiskSnapshot)
+	h.Add("CreateGcpDiskSnapshot", http.MethodPost, "/vendors/gcp/disk_snapshots/create", svc.CreateGcpDiskSnapshot)
+
+	// 删除快照
+	h.Add("DeleteTCloudDiskSnapshot", http.MethodDelete, "/vendors/tcloud/disk_snapshots", svc.DeleteTCloudDiskSnapshot)
+	h.Add("DeleteAwsDiskSnapshot", http.MethodDelete, "/vendors/aws/disk_snapshots", svc.DeleteAwsDiskSnapshot)
+	h.Add("DeleteHuaWeiDiskSnapshot", http.MethodDelete, "/vendors/huawei/disk_snapshots", svc.DeleteHuaWeiDiskSnapshot)
+	h.Add("DeleteAzureDiskSnapshot", http.MethodDelete, "/vendors/azure/disk_snapshots", svc.DeleteAzureDiskSnapshot)
+	h.Add("DeleteGcpDiskSnapshot", http.MethodDelete, "/vendors/gcp/disk_snapshots", svc.DeleteGcpDiskSnapshot)
+
+	// 快照回滚，仅腾讯云、华为云支持将快照数据回滚到源硬盘
+	h.Add("RollbackTCloudDiskSnapshot", http.MethodPost, "/vendors/tcloud/disk_snapshots/rollback",
+		svc.RollbackTCloudDiskSnapshot)
+	h.Add("RollbackHuaWeiDiskSnapshot", http.MethodPost, "/vendors/huawei/disk_snapshots/rollback",
+		svc.RollbackHuaWeiDiskSnapshot)
+
+	// 快照同步
+	h.Add("SyncTCloudDiskSnapshot", http.MethodPost, "/vendors/tcloud/disk_snapshots/sync", svc.SyncTCloudDiskSnapshot)
+	h.Add("SyncAwsDiskSnapshot", http.MethodPost, "/vendors/aws/disk_snapshots/sync", svc.SyncAwsDiskSnapshot)
+	h.Add("SyncHuaWeiDiskSnapshot", http.MethodPost, "/vendors/huawei/disk_snapshots/sync", svc.SyncHuaWeiDiskSnapshot)
+	h.Add("SyncAzureDiskSnapshot", http.MethodPost, "/vendors/azure/disk_snapshots/sync", svc.SyncAzureDiskSnapshot)
+	h.Add("SyncGcpDiskSnapshot", http.MethodPost, "/vendors/gcp/disk_snapshots/sync", svc.SyncGcpDiskSnapshot)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	DataCli *dataservice.Client
+	Adaptor *cloudclient.CloudAdaptorClient
+}
+
+// decodeDeleteReq decode and validate delete request, then return the snapshot to be deleted.
+func (svc *service) decodeDeleteReq(cts *rest.Contexts, vendor enumor.Vendor) (*coresnapshot.DiskSnapshot, error) {
+	req := new(proto.DeleteReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return svc.getSnapshot(cts.Kit, vendor, req.ID)
+}
+
+// decodeRollbackReq decode and validate rollback request, then return the snapshot to be rolled back.
+func (svc *service) decodeRollbackReq(cts *rest.Contexts, vendor enumor.Vendor) (*coresnapshot.DiskSnapshot, error) {
+	req := new(proto.RollbackReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	snapshot, err := svc.getSnapshot(cts.Kit, vendor, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snapshot.CloudDiskID) == 0 {
+		return nil, errf.Newf(errf.InvalidParameter, "disk snapshot: %s has no source disk", snapshot.ID)
+	}
+
+	return snapshot, nil
+}
+
+// decodeCreateReq decode and validate create request.
+func decodeCreateReq(cts *rest.Contexts) (*proto.CreateReq, error) {
+	req := new(proto.CreateReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(req.Name) == 0 {
+		// 部分云厂商要求快照名称必填，且只能包含小写字母、数字和中划线
+		req.Name = fmt.Sprintf("snapshot-%s", time.Now().Format("20060102150405"))
+	}
+
+	return req, nil
+}
+
+func (svc *service) getSnapshot(kt *kit.Kit, vendor enumor.Vendor, id string) (*coresnapshot.DiskSnapshot, error) {
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(tools.RuleEqual("id", id), tools.RuleEqual("vendor", vendor)),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := svc.DataCli.Global.DiskSnapshot.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list disk snapshot failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "%s disk snapshot: %s not found", vendor, id)
+	}
+
+	return &result.Details[0], nil
+}
+
+// createIfNotExist 快照按名称幂等创建，已存在同名快照时返回该快照的云上ID，否则提交快照创建请求并返回创建中的快照ID
+func createIfNotExist(kt *kit.Kit, vendor enumor.Vendor, name string, find func() (string, error),
+	create func() (string, error)) (string, error) {
+
+	cloudID, err := find()
+	if err != nil {
+		logs.Errorf("find %s disk snapshot by name failed, err: %v, name: %s, rid: %s", vendor, err, name, kt.Rid)
+		return "", err
+	}
+
+	if len(cloudID) != 0 {
+		logs.Infof("%s disk snapshot %s(%s) already exists, skip creating it, rid: %s", vendor, name, cloudID,
+			kt.Rid)
+		return cloudID, nil
+	}
+
+	cloudID, err = create()
+	if err != nil {
+		logs.Errorf("create %s disk snapshot failed, err: %v, name: %s, rid: %s", vendor, err, name, kt.Rid)
+		return "", err
+	}
+
+	return cloudID, nil
+}
+
+// afterCreate 快照提交创建或者已存在时，将快照同步到 db 中并返回快照的最新状态
+func (svc *service) afterCreate(kt *kit.Kit, vendor enumor.Vendor, req *proto.CreateReq, cloudID string,
+	syncFunc func(cloudIDs []string) error) (*proto.CreateResult, error) {
+
+	if err := syncFunc([]string{cloudID}); err != nil {
+		logs.Errorf("sync created disk snapshot failed, err: %v, cloud_id: %s, rid: %s", err, cloudID, kt.Rid)
+		return nil, err
+	}
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("vendor", vendor),
+			tools.RuleEqual("cloud_id", cloudID),
+		),
+		Page: core.NewDefaultBasePage(),
+	}
+	snapshots, err := svc.DataCli.Global.DiskSnapshot.List(kt, listReq)
+	if err != nil {
+		logs.Errorf("list created disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	if len(snapshots.Details) == 0 {
+		return nil, fmt.Errorf("created disk snapshot: %s not found in db", cloudID)
+	}
+	created := snapshots.Details[0]
+
+	if req.Memo != nil || len(req.BackupPolicyID) != 0 {
+		updateReq := &dssnapshot.BatchUpdateReq{
+			Snapshots: []dssnapshot.UpdateReq{{ID: created.ID, DiskID: created.DiskID, Memo: req.Memo,
+				BackupPolicyID: req.BackupPolicyID}},
+		}
+		if err = svc.DataCli.Global.DiskSnapshot.BatchUpdate(kt, updateReq); err != nil {
+			logs.Errorf("update created disk snapshot failed, err: %v, id: %s, rid: %s", err, created.ID, kt.Rid)
+			return nil, err
+		}
+	}
+
+	return &proto.CreateResult{ID: created.ID, CloudID: created.CloudID, Status: created.Status}, nil
+}
+
+// deleteSnapshotFromDB 云上快照删除成功后删除 db 中的快照数据
+func (svc *service) deleteSnapshotFromDB(kt *kit.Kit, id string) error {
+	delReq := &dataproto.BatchDeleteReq{Filter: tools.EqualExpression("id", id)}
+	if err := svc.DataCli.Global.DiskSnapshot.BatchDelete(kt, delReq); err != nil {
+		logs.Errorf("delete disk snapshot from db failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"errors"
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+func TestCreateIfNotExist(t *testing.T) {
+	errFind := errors.New("find failed")
+	errCreate := errors.New("create failed")
+
+	cases := []struct {
+		name       string
+		foundID    string
+		findErr    error
+		createErr  error
+		wantID     string
+		wantErr    error
+		wantCreate bool
+	}{
+		{name: "snapshot exists", foundID: "snap-exist", wantID: "snap-exist"},
+		{name: "snapshot not exists", wantID: "snap-new", wantCreate: true},
+		{name: "find failed", findErr: errFind, wantErr: errFind},
+		{name: "create failed", createErr: errCreate, wantErr: errCreate, wantCreate: true},
+	}
+
+	for _, c := range cases {
+		created := false
+		cloudID, err := createIfNotExist(kit.New(), enumor.TCloud, "snapshot-1",
+			func() (string, error) { return c.foundID, c.findErr },
+			func() (string, error) {
+				created = true
+				if c.createErr != nil {
+					return "", c.createErr
+				}
+				return "snap-new", nil
+			})
+
+		if !errors.Is(err, c.wantErr) {
+			t.Errorf("case %s: expect error %v, but got: %v", c.name, c.wantErr, err)
+		}
+		if cloudID != c.wantID {
+			t.Errorf("case %s: expect cloud id %s, but got: %s", c.name, c.wantID, cloudID)
+		}
+		if created != c.wantCreate {
+			t.Errorf("case %s: expect create called %v, but got: %v", c.name, c.wantCreate, created)
+		}
+	}
+}
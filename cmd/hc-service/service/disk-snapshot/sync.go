@@ -0,0 +1,243 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dataproto "hcm/pkg/api/data-service"
+	dssnapshot "hcm/pkg/api/data-service/cloud/disk-snapshot"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+)
+
+// syncOption 快照同步范围，db 中处于同步范围内但云上已不存在的快照会被删除
+type syncOption struct {
+	Vendor    enumor.Vendor
+	AccountID string
+	// Rules 限定同步范围的附加条件，如地域、资源组、快照云ID
+	Rules []*filter.AtomRule
+}
+
+// syncSnapshot 以云上快照数据为准，同步 db 中的快照数据
+func (svc *service) syncSnapshot(kt *kit.Kit, opt *syncOption, cloudSnapshots []dssnapshot.CreateReq) error {
+	dbSnapshots, err := svc.listSnapshotFromDB(kt, opt)
+	if err != nil {
+		return err
+	}
+
+	cloudDiskIDs := make([]string, 0, len(cloudSnapshots))
+	for _, one := range cloudSnapshots {
+		if len(one.CloudDiskID) != 0 {
+			cloudDiskIDs = append(cloudDiskIDs, one.CloudDiskID)
+		}
+	}
+	diskMap, err := svc.getDiskMap(kt, opt.AccountID, slice.Unique(cloudDiskIDs))
+	if err != nil {
+		return err
+	}
+
+	dbMap := make(map[string]coresnapshot.DiskSnapshot, len(dbSnapshots))
+	for _, one := range dbSnapshots {
+		dbMap[one.CloudID] = one
+	}
+
+	creates := make([]dssnapshot.CreateReq, 0)
+	updates := make([]dssnapshot.UpdateReq, 0)
+	for _, one := range cloudSnapshots {
+		disk, diskExists := diskMap[one.CloudDiskID]
+		if diskExists {
+			one.DiskID = disk.ID
+			// gcp 快照为全局资源，地域和可用区以源硬盘为准
+			if len(one.Region) == 0 {
+				one.Region = disk.Region
+			}
+			if len(one.Zone) == 0 {
+				one.Zone = disk.Zone
+			}
+		}
+
+		dbSnapshot, exists := dbMap[one.CloudID]
+		if !exists {
+			// 新同步的快照默认继承源硬盘的业务
+			one.BkBizID = constant.UnassignedBiz
+			if diskExists {
+				one.BkBizID = disk.BkBizID
+			}
+			creates = append(creates, one)
+			continue
+		}
+		delete(dbMap, one.CloudID)
+
+		update, changed := diffSnapshot(dbSnapshot, one, disk, diskExists)
+		if changed {
+			updates = append(updates, update)
+		}
+	}
+
+	delIDs := make([]string, 0, len(dbMap))
+	for _, one := range dbMap {
+		delIDs = append(delIDs, one.ID)
+	}
+
+	if err = svc.createSnapshot(kt, creates); err != nil {
+		return err
+	}
+
+	if err = svc.updateSnapshot(kt, updates); err != nil {
+		return err
+	}
+
+	if err = svc.deleteSnapshot(kt, delIDs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// diffSnapshot 对比云上快照和 db 快照，返回需要更新的字段
+func diffSnapshot(db coresnapshot.DiskSnapshot, cloud dssnapshot.CreateReq, disk coredisk.BaseDisk, diskExists bool) (
+	dssnapshot.UpdateReq, bool) {
+
+	update := dssnapshot.UpdateReq{ID: db.ID, DiskID: cloud.DiskID}
+	changed := db.DiskID != cloud.DiskID
+
+	if db.Name != cloud.Name {
+		update.Name = cloud.Name
+		changed = true
+	}
+
+	if db.Status != cloud.Status {
+		update.Status = cloud.Status
+		changed = true
+	}
+
+	if db.DiskSize != cloud.DiskSize {
+		update.DiskSize = cloud.DiskSize
+		changed = true
+	}
+
+	// 未分配业务的快照，源硬盘分配业务后跟随源硬盘
+	if db.BkBizID == constant.UnassignedBiz && diskExists && disk.BkBizID != constant.UnassignedBiz {
+		update.BkBizID = disk.BkBizID
+		changed = true
+	}
+
+	if cloud.Extension != nil && (db.Extension == nil || db.Extension.Progress != cloud.Extension.Progress) {
+		update.Extension = cloud.Extension
+		changed = true
+	}
+
+	return update, changed
+}
+
+func (svc *service) listSnapshotFromDB(kt *kit.Kit, opt *syncOption) ([]coresnapshot.DiskSnapshot, error) {
+	rules := []*filter.AtomRule{tools.RuleEqual("vendor", opt.Vendor), tools.RuleEqual("account_id", opt.AccountID)}
+	rules = append(rules, opt.Rules...)
+
+	listReq := &core.ListReq{
+		Filter: tools.ExpressionAnd(rules...),
+		Page:   core.NewDefaultBasePage(),
+	}
+
+	result := make([]coresnapshot.DiskSnapshot, 0)
+	for {
+		snapshots, err := svc.DataCli.Global.DiskSnapshot.List(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk snapshot from db failed, err: %v, account: %s, rid: %s", err, opt.AccountID,
+				kt.Rid)
+			return nil, err
+		}
+
+		result = append(result, snapshots.Details...)
+		if uint(len(snapshots.Details)) < listReq.Page.Limit {
+			break
+		}
+		listReq.Page.Start += uint32(listReq.Page.Limit)
+	}
+
+	return result, nil
+}
+
+// getDiskMap 查询快照源硬盘，返回 map[cloud_disk_id]disk
+func (svc *service) getDiskMap(kt *kit.Kit, accountID string, cloudDiskIDs []string) (map[string]coredisk.BaseDisk,
+	error) {
+
+	diskMap := make(map[string]coredisk.BaseDisk, len(cloudDiskIDs))
+	for _, ids := range slice.Split(cloudDiskIDs, int(core.DefaultMaxPageLimit)) {
+		listReq := &core.ListReq{
+			Filter: tools.ExpressionAnd(tools.RuleEqual("account_id", accountID), tools.RuleIn("cloud_id", ids)),
+			Page:   core.NewDefaultBasePage(),
+		}
+		disks, err := svc.DataCli.Global.ListDisk(kt, listReq)
+		if err != nil {
+			logs.Errorf("list disk failed, err: %v, account: %s, rid: %s", err, accountID, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range disks.Details {
+			diskMap[one.CloudID] = *one
+		}
+	}
+
+	return diskMap, nil
+}
+
+func (svc *service) createSnapshot(kt *kit.Kit, creates []dssnapshot.CreateReq) error {
+	for _, batch := range slice.Split(creates, constant.BatchOperationMaxLimit) {
+		_, err := svc.DataCli.Global.DiskSnapshot.BatchCreate(kt, &dssnapshot.BatchCreateReq{Snapshots: batch})
+		if err != nil {
+			logs.Errorf("batch create disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc *service) updateSnapshot(kt *kit.Kit, updates []dssnapshot.UpdateReq) error {
+	for _, batch := range slice.Split(updates, constant.BatchOperationMaxLimit) {
+		err := svc.DataCli.Global.DiskSnapshot.BatchUpdate(kt, &dssnapshot.BatchUpdateReq{Snapshots: batch})
+		if err != nil {
+			logs.Errorf("batch update disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc *service) deleteSnapshot(kt *kit.Kit, ids []string) error {
+	for _, batch := range slice.Split(ids, constant.BatchOperationMaxLimit) {
+		delReq := &dataproto.BatchDeleteReq{Filter: tools.ContainersExpression("id", batch)}
+		if err := svc.DataCli.Global.DiskSnapshot.BatchDelete(kt, delReq); err != nil {
+			logs.Errorf("batch delete disk snapshot failed, err: %v, ids: %v, rid: %s", err, batch, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"strconv"
+
+	"hcm/pkg/adaptor/types/core"
+	snapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	dssnapshot "hcm/pkg/api/data-service/cloud/disk-snapshot"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+)
+
+// CreateTCloudDiskSnapshot create tcloud disk snapshot.
+func (svc *service) CreateTCloudDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req, err := decodeCreateReq(cts)
+	if err != nil {
+		return nil, err
+	}
+
+	disk, err := svc.DataCli.TCloud.RetrieveDisk(cts.Kit.Ctx, cts.Kit.Header(), req.DiskID)
+	if err != nil {
+		logs.Errorf("get tcloud disk failed, err: %v, id: %s, rid: %s", err, req.DiskID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	client, err := svc.Adaptor.TCloud(cts.Kit, disk.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同一硬盘下已存在同名快照时不再重复创建
+	cloudID, err := createIfNotExist(cts.Kit, enumor.TCloud, req.Name, func() (string, error) {
+		listOpt := &snapshot.TCloudSnapshotListOption{
+			Region:       disk.Region,
+			CloudDiskIDs: []string{disk.CloudID},
+			Names:        []string{req.Name},
+		}
+		exists, err := client.ListDiskSnapshot(cts.Kit, listOpt)
+		if err != nil || len(exists) == 0 {
+			return "", err
+		}
+		return exists[0].GetCloudID(), nil
+	}, func() (string, error) {
+		opt := &snapshot.TCloudSnapshotCreateOption{
+			Region:      disk.Region,
+			CloudDiskID: disk.CloudID,
+			Name:        converter.ValToPtr(req.Name),
+		}
+		return client.CreateDiskSnapshot(cts.Kit, opt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.afterCreate(cts.Kit, enumor.TCloud, req, cloudID, func(cloudIDs []string) error {
+		return svc.syncTCloudSnapshot(cts.Kit, disk.AccountID, disk.Region, cloudIDs)
+	})
+}
+
+// DeleteTCloudDiskSnapshot delete tcloud disk snapshot.
+func (svc *service) DeleteTCloudDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	one, err := svc.decodeDeleteReq(cts, enumor.TCloud)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.Adaptor.TCloud(cts.Kit, one.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &snapshot.TCloudSnapshotDeleteOption{Region: one.Region, CloudIDs: []string{one.CloudID}}
+	if err = client.DeleteDiskSnapshot(cts.Kit, opt); err != nil {
+		logs.Errorf("delete tcloud disk snapshot failed, err: %v, id: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, svc.deleteSnapshotFromDB(cts.Kit, one.ID)
+}
+
+// RollbackTCloudDiskSnapshot rollback tcloud disk by snapshot.
+func (svc *service) RollbackTCloudDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	one, err := svc.decodeRollbackReq(cts, enumor.TCloud)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.Adaptor.TCloud(cts.Kit, one.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &snapshot.TCloudSnapshotRollbackOption{
+		Region:      one.Region,
+		CloudID:     one.CloudID,
+		CloudDiskID: one.CloudDiskID,
+	}
+	if err = client.RollbackDiskSnapshot(cts.Kit, opt); err != nil {
+		logs.Errorf("rollback tcloud disk snapshot failed, err: %v, id: %s, rid: %s", err, one.ID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// SyncTCloudDiskSnapshot sync tcloud disk snapshot.
+func (svc *service) SyncTCloudDiskSnapshot(cts *rest.Contexts) (interface{}, error) {
+	req := new(sync.TCloudSyncReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return nil, svc.syncTCloudSnapshot(cts.Kit, req.AccountID, req.Region, nil)
+}
+
+// syncTCloudSnapshot sync tcloud disk snapshot of region, sync all snapshots of region when cloudIDs is empty.
+func (svc *service) syncTCloudSnapshot(kt *kit.Kit, accountID, region string, cloudIDs []string) error {
+	client, err := svc.Adaptor.TCloud(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	opt := &syncOption{
+		Vendor:    enumor.TCloud,
+		AccountID: accountID,
+		Rules:     []*filter.AtomRule{tools.RuleEqual("region", region)},
+	}
+	if len(cloudIDs) != 0 {
+		opt.Rules = append(opt.Rules, tools.RuleIn("cloud_id", cloudIDs))
+	}
+
+	cloudSnapshots := make([]dssnapshot.CreateReq, 0)
+	listOpt := &snapshot.TCloudSnapshotListOption{
+		Region:   region,
+		CloudIDs: cloudIDs,
+		Page:     &core.TCloudPage{Offset: 0, Limit: core.TCloudQueryLimit},
+	}
+	for {
+		snapshots, err := client.ListDiskSnapshot(kt, listOpt)
+		if err != nil {
+			logs.Errorf("list tcloud disk snapshot failed, err: %v, account: %s, region: %s, rid: %s", err,
+				accountID, region, kt.Rid)
+			return err
+		}
+
+		for _, one := range snapshots {
+			cloudSnapshots = append(cloudSnapshots, convTCloudSnapshot(accountID, region, one))
+		}
+
+		if len(snapshots) < core.TCloudQueryLimit {
+			break
+		}
+		listOpt.Page.Offset += core.TCloudQueryLimit
+	}
+
+	return svc.syncSnapshot(kt, opt, cloudSnapshots)
+}
+
+func convTCloudSnapshot(accountID, region string, one snapshot.TCloudSnapshot) dssnapshot.CreateReq {
+	req := dssnapshot.CreateReq{
+		Vendor:           enumor.TCloud,
+		AccountID:        accountID,
+		CloudID:          one.GetCloudID(),
+		Name:             converter.PtrToVal(one.SnapshotName),
+		Region:           region,
+		CloudDiskID:      converter.PtrToVal(one.DiskId),
+		DiskSize:         converter.PtrToVal(one.DiskSize),
+		Status:           converter.PtrToVal(one.SnapshotState),
+		CloudCreatedTime: converter.PtrToVal(one.CreateTime),
+		Extension: &coresnapshot.Extension{
+			Encrypted: one.Encrypt,
+			Progress:  strconv.FormatUint(converter.PtrToVal(one.Percent), 10),
+		},
+	}
+	if one.Placement != nil {
+		req.Zone = converter.PtrToVal(one.Placement.Zone)
+	}
+
+	return req
+}
@@ -0,0 +1,96 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package eventsync 云上变更事件增量同步服务
+package eventsync
+
+import (
+	"fmt"
+
+	eventsync "hcm/cmd/hc-service/logics/event-sync"
+	"hcm/cmd/hc-service/service/capability"
+	"hcm/pkg/api/hc-service/sync"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// InitService initial event sync service
+func InitService(cap *capability.Capability) {
+	svc := &service{
+		dataCli:  cap.ClientSet.DataService(),
+		ingestor: cap.Ingestor,
+	}
+
+	h := rest.NewHandler()
+	h.Add("PushEvent", "POST", "/vendors/{vendor}/events/push", svc.PushEvent)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	dataCli  *dataservice.Client
+	ingestor *eventsync.Ingestor
+}
+
+// PushEvent 推送云上变更事件，事件由接收的节点在合并窗口结束后触发涉及资源的同步。
+func (svc *service) PushEvent(cts *rest.Contexts) (interface{}, error) {
+	if svc.ingestor == nil {
+		return nil, errf.New(errf.Aborted, "event sync is not enabled")
+	}
+
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(sync.PushEventReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	info, err := svc.dataCli.Global.Cloud.GetResBasicInfo(cts.Kit, enumor.AccountCloudResType, req.AccountID)
+	if err != nil {
+		logs.Errorf("get account basic info failed, err: %v, account: %s, rid: %s", err, req.AccountID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	if info.Vendor != vendor {
+		return nil, errf.NewFromErr(errf.InvalidParameter, fmt.Errorf("account %s vendor is %s, not %s",
+			req.AccountID, info.Vendor, vendor))
+	}
+
+	records := make([]eventsync.Record, 0, len(req.Records))
+	for _, one := range req.Records {
+		records = append(records, eventsync.Record{Vendor: vendor, AccountID: req.AccountID, Data: one})
+	}
+
+	count, err := svc.ingestor.Ingest(records...)
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	return &sync.PushEventResult{EventCount: count}, nil
+}
@@ -29,7 +29,9 @@ import (
 	"time"
 
 	cloudadaptor "hcm/cmd/hc-service/logics/cloud-adaptor"
+	eventsync "hcm/cmd/hc-service/logics/event-sync"
 	ressync "hcm/cmd/hc-service/logics/res-sync"
+	synclogic "hcm/cmd/hc-service/logics/sync"
 	"hcm/cmd/hc-service/service/account"
 	argstpl "hcm/cmd/hc-service/service/argument-template"
 	bwpkg "hcm/cmd/hc-service/service/bandwidth-package"
@@ -38,7 +40,9 @@ import (
 	"hcm/cmd/hc-service/service/cert"
 	"hcm/cmd/hc-service/service/cvm"
 	"hcm/cmd/hc-service/service/disk"
+	disksnapshot "hcm/cmd/hc-service/service/disk-snapshot"
 	"hcm/cmd/hc-service/service/eip"
+	eventsyncsvc "hcm/cmd/hc-service/service/event-sync"
 	"hcm/cmd/hc-service/service/firewall"
 	instancetype "hcm/cmd/hc-service/service/instance-type"
 	loadbalancer "hcm/cmd/hc-service/service/load-balancer"
@@ -47,12 +51,14 @@ import (
 	securitygroup "hcm/cmd/hc-service/service/security-group"
 	"hcm/cmd/hc-service/service/subnet"
 	"hcm/cmd/hc-service/service/sync"
+	"hcm/cmd/hc-service/service/tag"
 	"hcm/cmd/hc-service/service/vpc"
 	"hcm/pkg/cc"
 	"hcm/pkg/client"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/handler"
 	"hcm/pkg/logs"
+	"hcm/pkg/metrics"
 	"hcm/pkg/rest"
 	restcli "hcm/pkg/rest/client"
 	"hcm/pkg/runtime/shutdown"
@@ -67,10 +73,13 @@ type Service struct {
 	serve        *http.Server
 	clientSet    *client.ClientSet
 	cloudAdaptor *cloudadaptor.CloudAdaptorClient
+	resSyncCli   ressync.Interface
+	ingestor     *eventsync.Ingestor
+	runRecorder  *synclogic.RunRecorder
 }
 
 // NewService create a service instance.
-func NewService(dis serviced.Discover) (*Service, error) {
+func NewService(dis serviced.ServiceDiscover) (*Service, error) {
 	cli, err := restcli.NewClient(nil)
 	if err != nil {
 		return nil, err
@@ -85,6 +94,21 @@ func NewService(dis serviced.Discover) (*Service, error) {
 	svr := &Service{
 		clientSet:    cliSet,
 		cloudAdaptor: cloudAdaptor,
+		resSyncCli:   ressync.NewClient(cloudAdaptor, cliSet.DataService()),
+		runRecorder:  synclogic.NewRunRecorder(cliSet.DataService(), metrics.Register()),
+	}
+	go svr.runRecorder.CleanExpired(dis, cc.HCService().SyncRun.RetentionDays)
+
+	eventSync := cc.HCService().EventSync
+	if eventSync.Enable {
+		sources, err := eventsync.NewSources(eventSync.Sources)
+		if err != nil {
+			return nil, err
+		}
+
+		syncer := eventsync.NewResSyncer(svr.resSyncCli, svr.runRecorder)
+		svr.ingestor = eventsync.NewIngestor(eventSync, sources, syncer, dis)
+		go svr.ingestor.Run()
 	}
 
 	return svr, nil
@@ -156,7 +180,9 @@ func (s *Service) apiSet() *restful.Container {
 		WebService:   ws,
 		ClientSet:    s.clientSet,
 		CloudAdaptor: s.cloudAdaptor,
-		ResSyncCli:   ressync.NewClient(s.cloudAdaptor, s.clientSet.DataService()),
+		ResSyncCli:   s.resSyncCli,
+		Ingestor:     s.ingestor,
+		RunRecorder:  s.runRecorder,
 	}
 
 	account.InitAccountService(c)
@@ -165,6 +191,7 @@ func (s *Service) apiSet() *restful.Container {
 	vpc.InitVpcService(c)
 	subnet.InitSubnetService(c)
 	disk.InitDiskService(c)
+	disksnapshot.InitService(c)
 	cvm.InitCvmService(c)
 	routetable.InitRouteTableService(c)
 	eip.InitEipService(c)
@@ -176,6 +203,8 @@ func (s *Service) apiSet() *restful.Container {
 	cert.InitCertService(c)
 	bwpkg.InitBwPkgService(c)
 	mainaccount.InitService(c)
+	eventsyncsvc.InitService(c)
+	tag.InitService(c)
 
 	return restful.NewContainer().Add(c.WebService)
 }
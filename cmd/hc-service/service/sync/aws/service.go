@@ -23,8 +23,10 @@ import (
 	"hcm/cmd/hc-service/logics/cloud-adaptor"
 	ressync "hcm/cmd/hc-service/logics/res-sync"
 	"hcm/cmd/hc-service/service/capability"
+	"hcm/cmd/hc-service/service/sync/handler"
 	"hcm/pkg/client"
 	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/rest"
 )
 
@@ -39,18 +41,22 @@ func InitService(cap *capability.Capability) {
 
 	h := rest.NewHandler()
 	h.Path("/vendors/aws")
+	record := handler.RecordRun(cap.RunRecorder, enumor.Aws)
 
-	h.Add("SyncVpc", "POST", "/vpcs/sync", v.SyncVpc)
-	h.Add("SyncSubnet", "POST", "/subnets/sync", v.SyncSubnet)
-	h.Add("SyncDisk", "POST", "/disks/sync", v.SyncDisk)
-	h.Add("SyncSecurityGroup", "POST", "/security_groups/sync", v.SyncSecurityGroup)
-	h.Add("SyncCvmWithRelRes", "POST", "/cvms/with/relation_resources/sync", v.SyncCvmWithRelRes)
-	h.Add("SyncEip", "POST", "/eips/sync", v.SyncEip)
-	h.Add("SyncRoute", "POST", "/route_tables/sync", v.SyncRouteTable)
-	h.Add("SyncZone", "POST", "/zones/sync", v.SyncZone)
-	h.Add("SyncRegion", "POST", "/regions/sync", v.SyncRegion)
-	h.Add("SyncImage", "POST", "/images/sync", v.SyncImage)
-	h.Add("SyncSubAccount", "POST", "/sub_accounts/sync", v.SyncSubAccount)
+	h.Add("SyncVpc", "POST", "/vpcs/sync", record(enumor.VpcCloudResType, v.SyncVpc))
+	h.Add("SyncSubnet", "POST", "/subnets/sync", record(enumor.SubnetCloudResType, v.SyncSubnet))
+	h.Add("SyncDisk", "POST", "/disks/sync", record(enumor.DiskCloudResType, v.SyncDisk))
+	h.Add("SyncSecurityGroup", "POST", "/security_groups/sync",
+		record(enumor.SecurityGroupCloudResType, v.SyncSecurityGroup))
+	h.Add("SyncCvmWithRelRes", "POST", "/cvms/with/relation_resources/sync",
+		record(enumor.CvmCloudResType, v.SyncCvmWithRelRes))
+	h.Add("SyncEip", "POST", "/eips/sync", record(enumor.EipCloudResType, v.SyncEip))
+	h.Add("SyncRoute", "POST", "/route_tables/sync", record(enumor.RouteTableCloudResType, v.SyncRouteTable))
+	h.Add("SyncZone", "POST", "/zones/sync", record(enumor.ZoneCloudResType, v.SyncZone))
+	h.Add("SyncRegion", "POST", "/regions/sync", record(enumor.RegionCloudResType, v.SyncRegion))
+	h.Add("SyncImage", "POST", "/images/sync", record(enumor.ImageCloudResType, v.SyncImage))
+	h.Add("SyncSubAccount", "POST", "/sub_accounts/sync", record(enumor.SubAccountCloudResType, v.SyncSubAccount))
+	h.Add("PlanSync", "POST", "/sync/plans/{res_type}", v.PlanSync)
 
 	h.Load(cap.WebService)
 }
@@ -0,0 +1,51 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package handler
+
+import (
+	synclogic "hcm/cmd/hc-service/logics/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// SyncFunc 资源同步接口处理函数。
+type SyncFunc func(cts *rest.Contexts) (interface{}, error)
+
+// PlanSync 以计划模式执行资源同步。请求体与对应资源同步接口一致，同步流程照常查询云上和本地数据并对比，
+// 但不会修改本地数据，只返回同步将产生的资源级、字段级变更。
+func PlanSync(cts *rest.Contexts, vendor enumor.Vendor, syncers map[enumor.CloudResourceType]SyncFunc) (
+	interface{}, error) {
+
+	resType := enumor.CloudResourceType(cts.PathParameter("res_type").String())
+	syncFn, exists := syncers[resType]
+	if !exists {
+		return nil, errf.Newf(errf.InvalidParameter, "%s not support plan sync %s", vendor, resType)
+	}
+
+	plan := synclogic.WithPlan(cts.Kit)
+	if _, err := syncFn(cts); err != nil {
+		logs.Errorf("%s plan sync %s failed, err: %v, rid: %s", vendor, resType, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return plan.Result(vendor, resType), nil
+}
@@ -0,0 +1,65 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package handler
+
+import (
+	"encoding/json"
+
+	synclogic "hcm/cmd/hc-service/logics/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/rest"
+)
+
+// runParams 同步执行记录需要的请求参数，各资源同步接口的请求体中均包含账号ID，按地域同步的资源还包含地域。
+type runParams struct {
+	AccountID string `json:"account_id"`
+	Region    string `json:"region"`
+}
+
+// RecordRun 返回资源同步接口的包装函数，记录每次同步的执行结果。
+// 请求体解析失败或缺少账号ID时交由同步接口自身校验，不记录执行。
+func RecordRun(recorder *synclogic.RunRecorder, vendor enumor.Vendor) func(enumor.CloudResourceType,
+	SyncFunc) SyncFunc {
+
+	return func(resType enumor.CloudResourceType, syncFn SyncFunc) SyncFunc {
+		if recorder == nil {
+			return syncFn
+		}
+
+		return func(cts *rest.Contexts) (interface{}, error) {
+			body, err := cts.RequestBody()
+			if err != nil {
+				return syncFn(cts)
+			}
+
+			params := new(runParams)
+			if err = json.Unmarshal(body, params); err != nil || len(params.AccountID) == 0 {
+				return syncFn(cts)
+			}
+
+			run := synclogic.StartRun(cts.Kit, vendor, params.AccountID, params.Region, resType,
+				synclogic.TriggerFromKit(cts.Kit))
+			result, err := syncFn(cts)
+			recorder.Finish(cts.Kit, run, err)
+
+			return result, err
+		}
+	}
+}
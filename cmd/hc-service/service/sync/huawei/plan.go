@@ -0,0 +1,43 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"hcm/cmd/hc-service/service/sync/handler"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/rest"
+)
+
+// PlanSync 以计划模式同步HuaWei资源，仅返回同步将产生的变更，不修改本地数据。
+func (svc *service) PlanSync(cts *rest.Contexts) (interface{}, error) {
+	return handler.PlanSync(cts, enumor.HuaWei, map[enumor.CloudResourceType]handler.SyncFunc{
+		enumor.VpcCloudResType:           svc.SyncVpc,
+		enumor.SubnetCloudResType:        svc.SyncSubnet,
+		enumor.DiskCloudResType:          svc.SyncDisk,
+		enumor.SecurityGroupCloudResType: svc.SyncSecurityGroup,
+		enumor.CvmCloudResType:           svc.SyncCvmWithRelRes,
+		enumor.EipCloudResType:           svc.SyncEip,
+		enumor.RouteTableCloudResType:    svc.SyncRouteTable,
+		enumor.ZoneCloudResType:          svc.SyncZone,
+		enumor.RegionCloudResType:        svc.SyncRegion,
+		enumor.ImageCloudResType:         svc.SyncImage,
+		enumor.SubAccountCloudResType:    svc.SyncSubAccount,
+	})
+}
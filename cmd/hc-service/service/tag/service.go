@@ -0,0 +1,289 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tag 资源标签修改服务，修改云上标签成功后同步更新标签存储
+package tag
+
+import (
+	"fmt"
+
+	cloudclient "hcm/cmd/hc-service/logics/cloud-adaptor"
+	"hcm/cmd/hc-service/service/capability"
+	typestag "hcm/pkg/adaptor/types/tag"
+	coretag "hcm/pkg/api/core/cloud/tag"
+	dataproto "hcm/pkg/api/data-service/cloud"
+	dstag "hcm/pkg/api/data-service/cloud/tag"
+	prototag "hcm/pkg/api/hc-service/tag"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/tools/slice"
+)
+
+// InitService initial tag service
+func InitService(cap *capability.Capability) {
+	svc := &service{
+		adaptor: cap.CloudAdaptor,
+		dataCli: cap.ClientSet.DataService(),
+	}
+
+	h := rest.NewHandler()
+	h.Add("BatchAddTag", "POST", "/vendors/{vendor}/tags/batch/add", svc.BatchAddTag)
+	h.Add("BatchRemoveTag", "POST", "/vendors/{vendor}/tags/batch/remove", svc.BatchRemoveTag)
+
+	h.Load(cap.WebService)
+}
+
+type service struct {
+	adaptor *cloudclient.CloudAdaptorClient
+	dataCli *dataservice.Client
+}
+
+// tagAdaptor 支持批量修改标签的云厂商适配器
+type tagAdaptor interface {
+	AddTags(kt *kit.Kit, opt *typestag.AddTagOption) error
+	RemoveTags(kt *kit.Kit, opt *typestag.RemoveTagOption) error
+}
+
+// BatchAddTag 批量为资源添加标签，同名标签会被覆盖。
+func (svc *service) BatchAddTag(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(prototag.BatchAddTagReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	// 云上调用与db写入使用同一份去重后的资源ID，db写入会校验资源数量与ID数量一致
+	req.ResIDs = slice.Unique(req.ResIDs)
+
+	infos, err := svc.listResBasicInfo(cts.Kit, vendor, req.AccountID, req.ResType, req.ResIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if vendor == enumor.Gcp {
+		err = svc.updateGcpLabels(cts.Kit, req.AccountID, req.ResType, infos, req.Tags, nil)
+	} else {
+		err = svc.addTags(cts.Kit, vendor, req, infos)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	upsertReq := &dstag.ResTagBatchUpsertReq{ResType: req.ResType, ResIDs: req.ResIDs, Tags: req.Tags}
+	if err = svc.dataCli.Global.ResTag.BatchUpsert(cts.Kit, upsertReq); err != nil {
+		logs.Errorf("upsert res tag failed, err: %v, req: %+v, rid: %s", err, upsertReq, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// BatchRemoveTag 批量删除资源标签。
+func (svc *service) BatchRemoveTag(cts *rest.Contexts) (interface{}, error) {
+	vendor := enumor.Vendor(cts.PathParameter("vendor").String())
+	if err := vendor.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	req := new(prototag.BatchRemoveTagReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, errf.NewFromErr(errf.DecodeRequestFailed, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+	// 云上调用与db写入使用同一份去重后的资源ID，db写入会校验资源数量与ID数量一致
+	req.ResIDs = slice.Unique(req.ResIDs)
+
+	infos, err := svc.listResBasicInfo(cts.Kit, vendor, req.AccountID, req.ResType, req.ResIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if vendor == enumor.Gcp {
+		err = svc.updateGcpLabels(cts.Kit, req.AccountID, req.ResType, infos, nil, req.Keys)
+	} else {
+		err = svc.removeTags(cts.Kit, vendor, req, infos)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deleteReq := &dstag.ResTagBatchDeleteReq{ResType: req.ResType, ResIDs: req.ResIDs, Keys: req.Keys}
+	if err = svc.dataCli.Global.ResTag.BatchDelete(cts.Kit, deleteReq); err != nil {
+		logs.Errorf("delete res tag failed, err: %v, req: %+v, rid: %s", err, deleteReq, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// listResBasicInfo 查询资源基本信息，并校验资源都属于指定的云厂商与账号
+func (svc *service) listResBasicInfo(kt *kit.Kit, vendor enumor.Vendor, accountID string,
+	resType enumor.CloudResourceType, resIDs []string) ([]types.CloudResourceBasicInfo, error) {
+
+	fields := []string{"id", "vendor", "account_id", "region", "cloud_id"}
+	// gcp 通过资源名称修改 labels，主机与硬盘还需要可用区，弹性IP没有可用区
+	if vendor == enumor.Gcp {
+		fields = append(fields, "name")
+		if resType != enumor.EipCloudResType {
+			fields = append(fields, "zone")
+		}
+	}
+
+	req := dataproto.ListResourceBasicInfoReq{
+		ResourceType: resType,
+		IDs:          resIDs,
+		Fields:       fields,
+	}
+	infoMap, err := svc.dataCli.Global.Cloud.ListResBasicInfo(kt, req)
+	if err != nil {
+		logs.Errorf("list resource basic info failed, err: %v, req: %+v, rid: %s", err, req, kt.Rid)
+		return nil, err
+	}
+
+	infos := make([]types.CloudResourceBasicInfo, 0, len(req.IDs))
+	for _, id := range req.IDs {
+		info, exists := infoMap[id]
+		if !exists {
+			return nil, errf.Newf(errf.RecordNotFound, "%s %s not found", resType, id)
+		}
+
+		if info.Vendor != vendor || info.AccountID != accountID {
+			return nil, errf.NewFromErr(errf.InvalidParameter, fmt.Errorf("%s %s not belongs to %s account %s",
+				resType, id, vendor, accountID))
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+func (svc *service) getTagAdaptor(kt *kit.Kit, vendor enumor.Vendor, accountID string) (tagAdaptor, error) {
+	switch vendor {
+	case enumor.TCloud:
+		return svc.adaptor.TCloud(kt, accountID)
+	case enumor.Aws:
+		return svc.adaptor.Aws(kt, accountID)
+	case enumor.Azure:
+		return svc.adaptor.Azure(kt, accountID)
+	case enumor.HuaWei:
+		return svc.adaptor.HuaWei(kt, accountID)
+	default:
+		return nil, errf.Newf(errf.InvalidParameter, "vendor %s not support tag", vendor)
+	}
+}
+
+// groupCloudIDByRegion 按地域分组资源云ID，每组数量不超过单次修改标签的最大资源数量
+func groupCloudIDByRegion(infos []types.CloudResourceBasicInfo) map[string][][]string {
+	regionMap := make(map[string][]string)
+	for _, info := range infos {
+		regionMap[info.Region] = append(regionMap[info.Region], info.CloudID)
+	}
+
+	result := make(map[string][][]string, len(regionMap))
+	for region, cloudIDs := range regionMap {
+		result[region] = slice.Split(cloudIDs, typestag.MaxTagResourceCount)
+	}
+
+	return result
+}
+
+func (svc *service) addTags(kt *kit.Kit, vendor enumor.Vendor, req *prototag.BatchAddTagReq,
+	infos []types.CloudResourceBasicInfo) error {
+
+	cli, err := svc.getTagAdaptor(kt, vendor, req.AccountID)
+	if err != nil {
+		return err
+	}
+
+	for region, batches := range groupCloudIDByRegion(infos) {
+		for _, cloudIDs := range batches {
+			opt := &typestag.AddTagOption{ResType: req.ResType, Region: region, CloudIDs: cloudIDs, Tags: req.Tags}
+			if err = cli.AddTags(kt, opt); err != nil {
+				logs.Errorf("add %s tags failed, err: %v, opt: %+v, rid: %s", vendor, err, opt, kt.Rid)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (svc *service) removeTags(kt *kit.Kit, vendor enumor.Vendor, req *prototag.BatchRemoveTagReq,
+	infos []types.CloudResourceBasicInfo) error {
+
+	cli, err := svc.getTagAdaptor(kt, vendor, req.AccountID)
+	if err != nil {
+		return err
+	}
+
+	for region, batches := range groupCloudIDByRegion(infos) {
+		for _, cloudIDs := range batches {
+			opt := &typestag.RemoveTagOption{ResType: req.ResType, Region: region, CloudIDs: cloudIDs, Keys: req.Keys}
+			if err = cli.RemoveTags(kt, opt); err != nil {
+				logs.Errorf("remove %s tags failed, err: %v, opt: %+v, rid: %s", vendor, err, opt, kt.Rid)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// updateGcpLabels gcp 只能逐个资源修改 labels
+func (svc *service) updateGcpLabels(kt *kit.Kit, accountID string, resType enumor.CloudResourceType,
+	infos []types.CloudResourceBasicInfo, addLabels coretag.TagMap, removeKeys []string) error {
+
+	cli, err := svc.adaptor.Gcp(kt, accountID)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		opt := &typestag.GcpUpdateLabelOption{
+			ResType:    resType,
+			Zone:       info.Zone,
+			Region:     info.Region,
+			Name:       info.Name,
+			AddLabels:  addLabels,
+			RemoveKeys: removeKeys,
+		}
+		if err = cli.UpdateLabels(kt, opt); err != nil {
+			logs.Errorf("update gcp labels failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
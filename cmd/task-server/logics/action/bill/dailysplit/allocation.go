@@ -0,0 +1,362 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dailysplit
+
+import (
+	rawjson "encoding/json"
+	"fmt"
+	"sort"
+
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/api/data-service/bill"
+	dataclient "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/slice"
+
+	"github.com/shopspring/decimal"
+)
+
+// allocationPrecision 分摊后费用保留的小数位数，与账单明细表 cost 字段精度一致
+const allocationPrecision = 10
+
+// ResOwnerLookup 查询资源云ID所属的业务，返回 cloud_id -> bk_biz_id，未分配业务的资源不在返回结果中
+type ResOwnerLookup func(kt *kit.Kit, vendor enumor.Vendor, cloudIDs []string) (map[string]int64, error)
+
+// NewResOwnerLookup 根据已同步的主机、硬盘、弹性IP、负载均衡查询资源所属业务
+func NewResOwnerLookup(cli *dataclient.Client) ResOwnerLookup {
+	return func(kt *kit.Kit, vendor enumor.Vendor, cloudIDs []string) (map[string]int64, error) {
+		resMap, err := matchResources(kt, cli, vendor, cloudIDs)
+		if err != nil {
+			return nil, err
+		}
+		owners := make(map[string]int64, len(resMap))
+		for cloudID, res := range resMap {
+			if res.BkBizID > 0 {
+				owners[cloudID] = res.BkBizID
+			}
+		}
+		return owners, nil
+	}
+}
+
+// BillAllocator 按二级账号的分账规则将账单明细分摊到业务，无法归属的费用分摊到默认业务。
+// 按用量占比分摊时，无法归属到资源的共享费用需要等当天所有明细处理完后，由 Flush 按各业务已归属费用的占比分摊。
+type BillAllocator struct {
+	rule         *billcore.AllocationRule
+	vendor       enumor.Vendor
+	defaultBizID int64
+	extractor    resCloudIDExtractor
+	ownerLookup  ResOwnerLookup
+	// owners 已查询过的资源所属业务缓存，未归属业务的资源缓存为0
+	owners map[string]int64
+	// usageCosts 按用量占比分摊时，各业务已归属的费用
+	usageCosts map[int64]decimal.Decimal
+	// shared 按用量占比分摊时，待分摊的共享费用明细
+	shared []bill.BillItemCreateReq[rawjson.RawMessage]
+}
+
+// NewBillAllocator 创建分账器，rule 为空或未启用时不对账单明细做任何处理。
+// 规则未设置默认业务时，无法归属的费用分摊到二级账号所属业务
+func NewBillAllocator(rule *billcore.AllocationRule, vendor enumor.Vendor, mainAccountBizID int64,
+	ownerLookup ResOwnerLookup) *BillAllocator {
+
+	allocator := &BillAllocator{
+		vendor:       vendor,
+		defaultBizID: mainAccountBizID,
+		extractor:    vendorResCloudIDExtractor[vendor],
+		ownerLookup:  ownerLookup,
+		owners:       make(map[string]int64),
+		usageCosts:   make(map[int64]decimal.Decimal),
+	}
+	if rule == nil || !rule.Enabled {
+		return allocator
+	}
+	allocator.rule = rule
+	if rule.DefaultBkBizID > 0 {
+		allocator.defaultBizID = rule.DefaultBkBizID
+	}
+	return allocator
+}
+
+// Allocate 分摊账单明细，只处理规则所属二级账号的明细，其他二级账号的明细原样返回
+func (a *BillAllocator) Allocate(kt *kit.Kit, items []bill.BillItemCreateReq[rawjson.RawMessage]) (
+	[]bill.BillItemCreateReq[rawjson.RawMessage], error) {
+
+	if a.rule == nil || len(items) == 0 {
+		return items, nil
+	}
+
+	switch a.rule.RuleType {
+	case enumor.BillAllocationByRatio:
+		return a.allocateByRatio(items)
+	case enumor.BillAllocationByTag:
+		return a.allocateByTag(items)
+	case enumor.BillAllocationByOwnership:
+		return a.allocateByOwnership(kt, items)
+	case enumor.BillAllocationByUsage:
+		return a.allocateByUsage(kt, items)
+	default:
+		return nil, fmt.Errorf("unsupported allocation rule type: %s", a.rule.RuleType)
+	}
+}
+
+// Flush 返回按用量占比分摊的共享费用明细，当天没有可归属到业务的费用时，共享费用全部分摊到默认业务。
+// 调用后清空当天已归属的费用，同一分账器按天依次分账时，共享费用只按当天的用量占比分摊
+func (a *BillAllocator) Flush() []bill.BillItemCreateReq[rawjson.RawMessage] {
+	shared, usageCosts := a.shared, a.usageCosts
+	a.shared, a.usageCosts = nil, make(map[int64]decimal.Decimal)
+	if len(shared) == 0 {
+		return nil
+	}
+
+	total := decimal.Zero
+	bizIDs := make([]int64, 0, len(usageCosts))
+	for bizID, cost := range usageCosts {
+		if !cost.IsPositive() {
+			continue
+		}
+		total = total.Add(cost)
+		bizIDs = append(bizIDs, bizID)
+	}
+	if len(bizIDs) == 0 {
+		for idx := range shared {
+			shared[idx].BkBizID = a.defaultBizID
+		}
+		return shared
+	}
+
+	sort.Slice(bizIDs, func(i, j int) bool { return bizIDs[i] < bizIDs[j] })
+	targets := make([]billcore.AllocationTarget, 0, len(bizIDs))
+	for _, bizID := range bizIDs {
+		targets = append(targets, billcore.AllocationTarget{BkBizID: bizID, Ratio: usageCosts[bizID].Div(total)})
+	}
+	result := make([]bill.BillItemCreateReq[rawjson.RawMessage], 0, len(shared)*len(targets))
+	for _, item := range shared {
+		result = append(result, splitByTargets(item, targets)...)
+	}
+	return result
+}
+
+func (a *BillAllocator) isRuleOwned(item *bill.BillItemCreateReq[rawjson.RawMessage]) bool {
+	return item.MainAccountID == a.rule.MainAccountID
+}
+
+func (a *BillAllocator) allocateByRatio(items []bill.BillItemCreateReq[rawjson.RawMessage]) (
+	[]bill.BillItemCreateReq[rawjson.RawMessage], error) {
+
+	result := make([]bill.BillItemCreateReq[rawjson.RawMessage], 0, len(items)*len(a.rule.Config.Targets))
+	for _, item := range items {
+		if !a.isRuleOwned(&item) {
+			result = append(result, item)
+			continue
+		}
+		result = append(result, splitByTargets(item, a.rule.Config.Targets)...)
+	}
+	return result, nil
+}
+
+func (a *BillAllocator) allocateByTag(items []bill.BillItemCreateReq[rawjson.RawMessage]) (
+	[]bill.BillItemCreateReq[rawjson.RawMessage], error) {
+
+	for idx := range items {
+		if !a.isRuleOwned(&items[idx]) {
+			continue
+		}
+		items[idx].BkBizID = a.defaultBizID
+		if items[idx].Extension == nil {
+			continue
+		}
+		value, err := extractTagValue(*items[idx].Extension, a.rule.Config.TagKey)
+		if err != nil {
+			return nil, fmt.Errorf("extract tag %s from bill item extension failed, err: %v", a.rule.Config.TagKey,
+				err)
+		}
+		if bizID, ok := a.rule.Config.TagBizMap[value]; ok && len(value) != 0 {
+			items[idx].BkBizID = bizID
+		}
+	}
+	return items, nil
+}
+
+func (a *BillAllocator) allocateByOwnership(kt *kit.Kit, items []bill.BillItemCreateReq[rawjson.RawMessage]) (
+	[]bill.BillItemCreateReq[rawjson.RawMessage], error) {
+
+	owners, err := a.lookupOwners(kt, items)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range items {
+		if !a.isRuleOwned(&items[idx]) {
+			continue
+		}
+		items[idx].BkBizID = a.defaultBizID
+		if owners[idx] > 0 {
+			items[idx].BkBizID = owners[idx]
+		}
+	}
+	return items, nil
+}
+
+func (a *BillAllocator) allocateByUsage(kt *kit.Kit, items []bill.BillItemCreateReq[rawjson.RawMessage]) (
+	[]bill.BillItemCreateReq[rawjson.RawMessage], error) {
+
+	owners, err := a.lookupOwners(kt, items)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]bill.BillItemCreateReq[rawjson.RawMessage], 0, len(items))
+	for idx, item := range items {
+		if !a.isRuleOwned(&item) {
+			result = append(result, item)
+			continue
+		}
+		if owners[idx] <= 0 {
+			a.shared = append(a.shared, item)
+			continue
+		}
+		item.BkBizID = owners[idx]
+		a.usageCosts[item.BkBizID] = a.usageCosts[item.BkBizID].Add(item.Cost)
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// lookupOwners 返回与 items 一一对应的资源所属业务，无法归属的明细为0
+func (a *BillAllocator) lookupOwners(kt *kit.Kit, items []bill.BillItemCreateReq[rawjson.RawMessage]) (
+	[]int64, error) {
+
+	if a.extractor == nil {
+		return make([]int64, len(items)), nil
+	}
+	cloudIDs := make([]string, len(items))
+	unknown := make([]string, 0)
+	for idx, item := range items {
+		if !a.isRuleOwned(&item) || item.Extension == nil {
+			continue
+		}
+		cloudID, err := a.extractor(*item.Extension)
+		if err != nil {
+			return nil, fmt.Errorf("extract resource cloud id from bill item extension failed, err: %v", err)
+		}
+		cloudIDs[idx] = cloudID
+		if _, ok := a.owners[cloudID]; !ok && len(cloudID) != 0 {
+			unknown = append(unknown, cloudID)
+		}
+	}
+
+	unknown = slice.Unique(unknown)
+	if len(unknown) != 0 {
+		owners, err := a.ownerLookup(kt, a.vendor, unknown)
+		if err != nil {
+			return nil, fmt.Errorf("lookup owner of resources failed, err: %v", err)
+		}
+		for _, cloudID := range unknown {
+			a.owners[cloudID] = owners[cloudID]
+		}
+	}
+
+	result := make([]int64, len(items))
+	for idx, cloudID := range cloudIDs {
+		if len(cloudID) != 0 {
+			result[idx] = a.owners[cloudID]
+		}
+	}
+	return result, nil
+}
+
+// splitByTargets 按比例拆分账单明细，费用和用量按比例分摊，舍入误差计入最后一个业务
+func splitByTargets(item bill.BillItemCreateReq[rawjson.RawMessage],
+	targets []billcore.AllocationTarget) []bill.BillItemCreateReq[rawjson.RawMessage] {
+
+	result := make([]bill.BillItemCreateReq[rawjson.RawMessage], 0, len(targets))
+	restCost, restAmount := item.Cost, item.ResAmount
+	for idx, target := range targets {
+		one := item
+		one.BkBizID = target.BkBizID
+		if idx == len(targets)-1 {
+			one.Cost, one.ResAmount = restCost, restAmount
+		} else {
+			one.Cost = item.Cost.Mul(target.Ratio).Round(allocationPrecision)
+			one.ResAmount = item.ResAmount.Mul(target.Ratio).Round(allocationPrecision)
+			restCost, restAmount = restCost.Sub(one.Cost), restAmount.Sub(one.ResAmount)
+		}
+		result = append(result, one)
+	}
+	return result
+}
+
+// extractTagValue 从账单明细扩展字段中提取标签值，依次支持：
+// tags/labels 对象、labels [{key, value}] 数组（gcp）、properties.tags 对象（azure）、
+// resource_tags_user_<key>、resource_tags_<key> 字段（aws cur）
+func extractTagValue(ext rawjson.RawMessage, key string) (string, error) {
+	fields := make(map[string]rawjson.RawMessage)
+	if err := rawjson.Unmarshal(ext, &fields); err != nil {
+		return "", err
+	}
+
+	for _, name := range []string{"tags", "labels"} {
+		if value, ok := lookupTag(fields[name], key); ok {
+			return value, nil
+		}
+	}
+	if properties, ok := fields["properties"]; ok {
+		props := struct {
+			Tags rawjson.RawMessage `json:"tags"`
+		}{}
+		if err := rawjson.Unmarshal(properties, &props); err == nil {
+			if value, ok := lookupTag(props.Tags, key); ok {
+				return value, nil
+			}
+		}
+	}
+	for _, name := range []string{"resource_tags_user_" + key, "resource_tags_" + key} {
+		var value string
+		if raw, ok := fields[name]; ok && rawjson.Unmarshal(raw, &value) == nil && len(value) != 0 {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+// lookupTag 从 {key: value} 对象或 [{key, value}] 数组中查找标签值
+func lookupTag(raw rawjson.RawMessage, key string) (string, bool) {
+	if len(raw) == 0 {
+		return "", false
+	}
+	tagMap := make(map[string]string)
+	if err := rawjson.Unmarshal(raw, &tagMap); err == nil {
+		value, ok := tagMap[key]
+		return value, ok
+	}
+	tagList := make([]struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}, 0)
+	if err := rawjson.Unmarshal(raw, &tagList); err != nil {
+		return "", false
+	}
+	for _, tag := range tagList {
+		if tag.Key == key {
+			return tag.Value, true
+		}
+	}
+	return "", false
+}
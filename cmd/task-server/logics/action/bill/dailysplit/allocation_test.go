@@ -0,0 +1,230 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dailysplit
+
+import (
+	rawjson "encoding/json"
+	"testing"
+
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+
+	"github.com/shopspring/decimal"
+)
+
+func newAllocationTestItem(cost string, ext string) bill.BillItemCreateReq[rawjson.RawMessage] {
+	raw := rawjson.RawMessage(ext)
+	return bill.BillItemCreateReq[rawjson.RawMessage]{
+		RootAccountID: "root",
+		MainAccountID: "main",
+		Vendor:        enumor.Aws,
+		BkBizID:       1,
+		Cost:          decimal.RequireFromString(cost),
+		ResAmount:     decimal.RequireFromString(cost),
+		Extension:     &raw,
+	}
+}
+
+func sumBizCost(items []bill.BillItemCreateReq[rawjson.RawMessage]) map[int64]string {
+	costs := make(map[int64]decimal.Decimal)
+	for _, item := range items {
+		costs[item.BkBizID] = costs[item.BkBizID].Add(item.Cost)
+	}
+	result := make(map[int64]string, len(costs))
+	for bizID, cost := range costs {
+		result[bizID] = cost.String()
+	}
+	return result
+}
+
+func assertBizCost(t *testing.T, got map[int64]string, want map[int64]string) {
+	if len(got) != len(want) {
+		t.Fatalf("biz cost got %v, want %v", got, want)
+	}
+	for bizID, cost := range want {
+		if got[bizID] != cost {
+			t.Errorf("cost of biz %d got %s, want %s", bizID, got[bizID], cost)
+		}
+	}
+}
+
+func TestBillAllocatorByRatio(t *testing.T) {
+	rule := &billcore.AllocationRule{
+		MainAccountID: "main",
+		RuleType:      enumor.BillAllocationByRatio,
+		Config: billcore.AllocationRuleConfig{Targets: []billcore.AllocationTarget{
+			{BkBizID: 10, Ratio: decimal.RequireFromString("0.3333333333")},
+			{BkBizID: 20, Ratio: decimal.RequireFromString("0.6666666667")},
+		}},
+		Enabled: true,
+	}
+	items := []bill.BillItemCreateReq[rawjson.RawMessage]{newAllocationTestItem("10", `{}`)}
+	other := newAllocationTestItem("5", `{}`)
+	other.MainAccountID = "other"
+	items = append(items, other)
+
+	allocator := NewBillAllocator(rule, enumor.Aws, 1, nil)
+	result, err := allocator.Allocate(kit.New(), items)
+	if err != nil {
+		t.Fatalf("allocate failed, err: %v", err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("allocate result length got %d, want 3", len(result))
+	}
+	assertBizCost(t, sumBizCost(result), map[int64]string{10: "3.333333333", 20: "6.666666667", 1: "5"})
+}
+
+func TestBillAllocatorByTag(t *testing.T) {
+	rule := &billcore.AllocationRule{
+		MainAccountID:  "main",
+		RuleType:       enumor.BillAllocationByTag,
+		Config:         billcore.AllocationRuleConfig{TagKey: "team", TagBizMap: map[string]int64{"a": 10, "b": 20}},
+		DefaultBkBizID: 99,
+		Enabled:        true,
+	}
+	items := []bill.BillItemCreateReq[rawjson.RawMessage]{
+		newAllocationTestItem("1", `{"resource_tags_user_team":"a"}`),
+		newAllocationTestItem("2", `{"labels":[{"key":"team","value":"b"}]}`),
+		newAllocationTestItem("3", `{"properties":{"tags":{"team":"a"}}}`),
+		newAllocationTestItem("4", `{"tags":{"team":"unknown"}}`),
+		newAllocationTestItem("5", `{"line_item_resource_id":"i-1"}`),
+	}
+
+	allocator := NewBillAllocator(rule, enumor.Aws, 1, nil)
+	result, err := allocator.Allocate(kit.New(), items)
+	if err != nil {
+		t.Fatalf("allocate failed, err: %v", err)
+	}
+	assertBizCost(t, sumBizCost(result), map[int64]string{10: "4", 20: "2", 99: "9"})
+}
+
+func TestBillAllocatorByUsage(t *testing.T) {
+	rule := &billcore.AllocationRule{
+		MainAccountID: "main",
+		RuleType:      enumor.BillAllocationByUsage,
+		Enabled:       true,
+	}
+	lookupCount := 0
+	lookup := func(kt *kit.Kit, vendor enumor.Vendor, cloudIDs []string) (map[string]int64, error) {
+		lookupCount++
+		return map[string]int64{"i-1": 10, "i-2": 20}, nil
+	}
+	allocator := NewBillAllocator(rule, enumor.Aws, 1, lookup)
+
+	batches := [][]bill.BillItemCreateReq[rawjson.RawMessage]{
+		{
+			newAllocationTestItem("30", `{"line_item_resource_id":"i-1"}`),
+			newAllocationTestItem("6", `{"line_item_resource_id":""}`),
+		},
+		{
+			newAllocationTestItem("10", `{"line_item_resource_id":"i-2"}`),
+			newAllocationTestItem("20", `{"line_item_resource_id":"i-1"}`),
+			newAllocationTestItem("2", `{"line_item_resource_id":"i-unknown"}`),
+		},
+	}
+	var result []bill.BillItemCreateReq[rawjson.RawMessage]
+	for _, batch := range batches {
+		items, err := allocator.Allocate(kit.New(), batch)
+		if err != nil {
+			t.Fatalf("allocate failed, err: %v", err)
+		}
+		result = append(result, items...)
+	}
+	assertBizCost(t, sumBizCost(result), map[int64]string{10: "50", 20: "10"})
+	if lookupCount != 2 {
+		t.Errorf("lookup count got %d, want 2", lookupCount)
+	}
+
+	// 共享费用8按 50:10 分摊
+	result = append(result, allocator.Flush()...)
+	assertBizCost(t, sumBizCost(result), map[int64]string{10: "56.6666666667", 20: "11.3333333333"})
+}
+
+func TestBillAllocatorUsageWithoutOwner(t *testing.T) {
+	rule := &billcore.AllocationRule{
+		MainAccountID:  "main",
+		RuleType:       enumor.BillAllocationByUsage,
+		DefaultBkBizID: 99,
+		Enabled:        true,
+	}
+	lookup := func(kt *kit.Kit, vendor enumor.Vendor, cloudIDs []string) (map[string]int64, error) {
+		return nil, nil
+	}
+	allocator := NewBillAllocator(rule, enumor.Aws, 1, lookup)
+	result, err := allocator.Allocate(kit.New(), []bill.BillItemCreateReq[rawjson.RawMessage]{
+		newAllocationTestItem("3", `{"line_item_resource_id":"i-1"}`),
+	})
+	if err != nil {
+		t.Fatalf("allocate failed, err: %v", err)
+	}
+	result = append(result, allocator.Flush()...)
+	assertBizCost(t, sumBizCost(result), map[int64]string{99: "3"})
+}
+
+func TestBillAllocatorUsageMultiDay(t *testing.T) {
+	rule := &billcore.AllocationRule{
+		MainAccountID: "main",
+		RuleType:      enumor.BillAllocationByUsage,
+		Enabled:       true,
+	}
+	lookup := func(kt *kit.Kit, vendor enumor.Vendor, cloudIDs []string) (map[string]int64, error) {
+		return map[string]int64{"i-1": 10, "i-2": 20}, nil
+	}
+	allocator := NewBillAllocator(rule, enumor.Aws, 1, lookup)
+
+	days := []struct {
+		items []bill.BillItemCreateReq[rawjson.RawMessage]
+		want  map[int64]string
+	}{
+		{
+			// 第一天只有业务10的用量，共享费用全部分摊到业务10
+			items: []bill.BillItemCreateReq[rawjson.RawMessage]{
+				newAllocationTestItem("30", `{"line_item_resource_id":"i-1"}`),
+				newAllocationTestItem("6", `{"line_item_resource_id":""}`),
+			},
+			want: map[int64]string{10: "36"},
+		},
+		{
+			// 第二天只有业务20的用量，不受第一天业务10用量的影响
+			items: []bill.BillItemCreateReq[rawjson.RawMessage]{
+				newAllocationTestItem("10", `{"line_item_resource_id":"i-2"}`),
+				newAllocationTestItem("4", `{"line_item_resource_id":""}`),
+			},
+			want: map[int64]string{20: "14"},
+		},
+		{
+			// 第三天没有可归属的用量，共享费用分摊到默认业务
+			items: []bill.BillItemCreateReq[rawjson.RawMessage]{
+				newAllocationTestItem("2", `{"line_item_resource_id":""}`),
+			},
+			want: map[int64]string{1: "2"},
+		},
+	}
+	for idx, day := range days {
+		result, err := allocator.Allocate(kit.New(), day.items)
+		if err != nil {
+			t.Fatalf("allocate day %d failed, err: %v", idx+1, err)
+		}
+		result = append(result, allocator.Flush()...)
+		assertBizCost(t, sumBizCost(result), day.want)
+	}
+}
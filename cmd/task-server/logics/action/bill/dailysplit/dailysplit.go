@@ -27,8 +27,10 @@ import (
 	actcli "hcm/cmd/task-server/logics/action/cli"
 	"hcm/pkg/api/core"
 	protocore "hcm/pkg/api/core/account-set"
+	billcore "hcm/pkg/api/core/bill"
 	"hcm/pkg/api/data-service/bill"
 	"hcm/pkg/async/action/run"
+	dataclient "hcm/pkg/client/data-service"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
@@ -133,6 +135,9 @@ func (act DailyAccountSplitAction) doDailySplit(
 	if err := cleanBillItem(kt.Kit(), opt, billDay); err != nil {
 		return err
 	}
+	if err := cleanResourceCost(kt.Kit(), opt, billDay); err != nil {
+		return err
+	}
 	// step2 进行分账
 	if err := splitBillItem(kt.Kit(), opt, billDay); err != nil {
 		return err
@@ -212,6 +217,14 @@ func splitBillItem(kt *kit.Kit, opt *DailyAccountSplitActionOption, billDay int)
 	if err != nil {
 		return fmt.Errorf("failed to get splitter for %v, err %s", opt, err.Error())
 	}
+	rule, err := GetEffectiveAllocationRule(kt, actcli.GetDataService(), opt.MainAccountID, opt.BillYear,
+		opt.BillMonth)
+	if err != nil {
+		return err
+	}
+	allocator := NewBillAllocator(rule, opt.Vendor, mainAccountInfo.BkBizID,
+		NewResOwnerLookup(actcli.GetDataService()))
+	resCostAgg := newResourceCostAggregator(opt.Vendor)
 
 	for _, filename := range resp.Filenames {
 		var billItemList []bill.BillItemCreateReq[rawjson.RawMessage]
@@ -240,26 +253,80 @@ func splitBillItem(kt *kit.Kit, opt *DailyAccountSplitActionOption, billDay int)
 			}
 			billItemList = append(billItemList, reqList...)
 		}
-
-		for _, itemsBatch := range slice.Split(billItemList, constant.BatchOperationMaxLimit) {
-			createReq := &bill.BatchBillItemCreateReq[rawjson.RawMessage]{
-				ItemCommonOpt: &bill.ItemCommonOpt{
-					Vendor: opt.Vendor,
-					Year:   opt.BillYear,
-					Month:  opt.BillMonth,
-				},
-				Items: itemsBatch,
-			}
-			_, err = actcli.GetDataService().Global.Bill.BatchCreateBillItem(kt, createReq)
-			if err != nil {
-				return fmt.Errorf("batch create bill item for %s failed, err %s", filename, err.Error())
-			}
+		// 按分账规则将费用分摊到业务
+		billItemList, err = allocator.Allocate(kt, billItemList)
+		if err != nil {
+			return fmt.Errorf("allocate bill item for %s failed, err %s", filename, err.Error())
+		}
+		if err := resCostAgg.Add(billItemList); err != nil {
+			return fmt.Errorf("aggregate resource cost for %s failed, err %s", filename, err.Error())
+		}
+		if err := createBillItems(kt, opt, billItemList); err != nil {
+			return fmt.Errorf("batch create bill item for %s failed, err %s", filename, err.Error())
 		}
 		logs.Infof("split %s successfully", filename)
 	}
+
+	// 按用量占比分摊的共享费用需要在当天所有明细分账完成后分摊
+	sharedItems := allocator.Flush()
+	if err := resCostAgg.Add(sharedItems); err != nil {
+		return fmt.Errorf("aggregate resource cost of shared bill item for %v day %d failed, err %s", opt, billDay,
+			err.Error())
+	}
+	if err := createBillItems(kt, opt, sharedItems); err != nil {
+		return fmt.Errorf("batch create shared bill item for %v day %d failed, err %s", opt, billDay, err.Error())
+	}
+
+	// 按资源汇总当天费用，并关联到已同步的资源
+	if err := resCostAgg.Save(kt, opt.Vendor); err != nil {
+		return fmt.Errorf("save resource cost for %v day %d failed, err %s", opt, billDay, err.Error())
+	}
+	return nil
+}
+
+func createBillItems(kt *kit.Kit, opt *DailyAccountSplitActionOption,
+	items []bill.BillItemCreateReq[rawjson.RawMessage]) error {
+
+	for _, itemsBatch := range slice.Split(items, constant.BatchOperationMaxLimit) {
+		createReq := &bill.BatchBillItemCreateReq[rawjson.RawMessage]{
+			ItemCommonOpt: &bill.ItemCommonOpt{
+				Vendor: opt.Vendor,
+				Year:   opt.BillYear,
+				Month:  opt.BillMonth,
+			},
+			Items: itemsBatch,
+		}
+		if _, err := actcli.GetDataService().Global.Bill.BatchCreateBillItem(kt, createReq); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// GetEffectiveAllocationRule 获取二级账号在账单月份生效的最新版本分账规则，没有生效的规则时返回nil
+func GetEffectiveAllocationRule(kt *kit.Kit, cli *dataclient.Client, mainAccountID string, billYear,
+	billMonth int) (*billcore.AllocationRule, error) {
+
+	result, err := cli.Global.Bill.ListBillAllocationRule(kt, &bill.BillAllocationRuleListReq{
+		Filter: tools.EqualExpression("main_account_id", mainAccountID),
+		Page: &core.BasePage{
+			Start: 0,
+			Limit: core.DefaultMaxPageLimit,
+			Sort:  "version",
+			Order: core.Descending,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list allocation rule of main account %s failed, err %s", mainAccountID, err.Error())
+	}
+	for _, rule := range result.Details {
+		if rule.IsEffective(billYear, billMonth) {
+			return rule, nil
+		}
+	}
+	return nil, nil
+}
+
 func getMainAccount(kt *kit.Kit, mainAccountID string) (*protocore.BaseMainAccount, error) {
 	var expressions []*filter.AtomRule
 	expressions = append(expressions, []*filter.AtomRule{
@@ -0,0 +1,358 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dailysplit
+
+import (
+	rawjson "encoding/json"
+	"fmt"
+	"strings"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	corecvm "hcm/pkg/api/core/cloud/cvm"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	corelb "hcm/pkg/api/core/cloud/load-balancer"
+	dataservice "hcm/pkg/api/data-service"
+	"hcm/pkg/api/data-service/bill"
+	dataeip "hcm/pkg/api/data-service/cloud/eip"
+	dataclient "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/shopspring/decimal"
+)
+
+// resCloudIDExtractor 从分账后账单明细的扩展字段中提取资源云ID，无法提取时返回空字符串
+type resCloudIDExtractor func(ext rawjson.RawMessage) (string, error)
+
+// vendorResCloudIDExtractor 各云厂商账单明细的资源云ID提取方式。腾讯云没有日账单拉取和分账（没有对应的 RawBillSplitter），
+// 账单明细扩展字段 TCloudBillItemExtension 中也没有资源信息，因此不提取，腾讯云不生成资源维度账单
+var vendorResCloudIDExtractor = map[enumor.Vendor]resCloudIDExtractor{
+	enumor.Aws:    extractAwsResCloudID,
+	enumor.Gcp:    extractGcpResCloudID,
+	enumor.HuaWei: extractHuaWeiResCloudID,
+	enumor.Azure:  extractAzureResCloudID,
+}
+
+// extractAwsResCloudID aws 使用 line_item_resource_id，如 i-xxx、vol-xxx
+func extractAwsResCloudID(ext rawjson.RawMessage) (string, error) {
+	item := struct {
+		LineItemResourceID string `json:"line_item_resource_id"`
+	}{}
+	if err := rawjson.Unmarshal(ext, &item); err != nil {
+		return "", err
+	}
+	return item.LineItemResourceID, nil
+}
+
+// extractGcpResCloudID gcp 使用 resource_global_name 的最后一段，
+// 如 //compute.googleapis.com/projects/xxx/zones/xxx/instances/123456 中的实例数字ID
+func extractGcpResCloudID(ext rawjson.RawMessage) (string, error) {
+	item := struct {
+		ResourceGlobalName *string `json:"resource_global_name"`
+		ResourceName       *string `json:"resource_name"`
+	}{}
+	if err := rawjson.Unmarshal(ext, &item); err != nil {
+		return "", err
+	}
+	name := cvt.PtrToVal(item.ResourceGlobalName)
+	if len(name) == 0 {
+		name = cvt.PtrToVal(item.ResourceName)
+	}
+	return name[strings.LastIndex(name, "/")+1:], nil
+}
+
+// extractHuaWeiResCloudID 华为云使用 resource_id
+func extractHuaWeiResCloudID(ext rawjson.RawMessage) (string, error) {
+	item := struct {
+		ResourceID *string `json:"resource_id"`
+	}{}
+	if err := rawjson.Unmarshal(ext, &item); err != nil {
+		return "", err
+	}
+	return cvt.PtrToVal(item.ResourceID), nil
+}
+
+// extractAzureResCloudID azure 使用 properties.resourceId，与资源同步时保持一致转为小写
+func extractAzureResCloudID(ext rawjson.RawMessage) (string, error) {
+	item := struct {
+		Properties *struct {
+			ResourceID *string `json:"resourceId"`
+		} `json:"properties"`
+	}{}
+	if err := rawjson.Unmarshal(ext, &item); err != nil {
+		return "", err
+	}
+	if item.Properties == nil {
+		return "", nil
+	}
+	return strings.ToLower(cvt.PtrToVal(item.Properties.ResourceID)), nil
+}
+
+// resourceCostKey 资源维度账单的汇总维度，资源维度账单的业务为资源所属的业务，不按账单明细的业务汇总
+type resourceCostKey struct {
+	ProductID  int64
+	Currency   enumor.CurrencyCode
+	ResCloudID string
+}
+
+// resourceCostAggregator 按资源云ID汇总分账后的账单明细费用
+type resourceCostAggregator struct {
+	extractor resCloudIDExtractor
+	costs     map[resourceCostKey]*bill.BillResourceCostCreateReq
+	keys      []resourceCostKey
+}
+
+func newResourceCostAggregator(vendor enumor.Vendor) *resourceCostAggregator {
+	return &resourceCostAggregator{
+		extractor: vendorResCloudIDExtractor[vendor],
+		costs:     make(map[resourceCostKey]*bill.BillResourceCostCreateReq),
+	}
+}
+
+// Add 汇总账单明细，不支持提取资源云ID的云厂商直接忽略
+func (agg *resourceCostAggregator) Add(items []bill.BillItemCreateReq[rawjson.RawMessage]) error {
+	if agg.extractor == nil {
+		return nil
+	}
+
+	for _, item := range items {
+		if item.Extension == nil {
+			continue
+		}
+		resCloudID, err := agg.extractor(*item.Extension)
+		if err != nil {
+			return fmt.Errorf("extract resource cloud id from bill item extension failed, err: %v", err)
+		}
+		if len(resCloudID) == 0 {
+			continue
+		}
+
+		key := resourceCostKey{
+			ProductID:  item.ProductID,
+			Currency:   item.Currency,
+			ResCloudID: resCloudID,
+		}
+		cost, exists := agg.costs[key]
+		if !exists {
+			cost = &bill.BillResourceCostCreateReq{
+				RootAccountID: item.RootAccountID,
+				MainAccountID: item.MainAccountID,
+				Vendor:        item.Vendor,
+				ProductID:     item.ProductID,
+				BkBizID:       item.BkBizID,
+				BillYear:      item.BillYear,
+				BillMonth:     item.BillMonth,
+				BillDay:       item.BillDay,
+				VersionID:     item.VersionID,
+				ResCloudID:    resCloudID,
+				Currency:      item.Currency,
+				Cost:          decimal.Zero,
+			}
+			agg.costs[key] = cost
+			agg.keys = append(agg.keys, key)
+		}
+		cost.Cost = cost.Cost.Add(item.Cost)
+		cost.Count++
+	}
+	return nil
+}
+
+// Save 关联同步的资源并保存资源维度账单
+func (agg *resourceCostAggregator) Save(kt *kit.Kit, vendor enumor.Vendor) error {
+	if len(agg.keys) == 0 {
+		return nil
+	}
+
+	cloudIDs := make([]string, 0, len(agg.keys))
+	for _, key := range agg.keys {
+		cloudIDs = append(cloudIDs, key.ResCloudID)
+	}
+	resMap, err := matchResources(kt, actcli.GetDataService(), vendor, slice.Unique(cloudIDs))
+	if err != nil {
+		return err
+	}
+
+	costList := agg.costList(resMap)
+	for _, batch := range slice.Split(costList, constant.BatchOperationMaxLimit) {
+		createReq := &bill.BatchBillResourceCostCreateReq{Items: batch}
+		if _, err := actcli.GetDataService().Global.Bill.BatchCreateBillResourceCost(kt, createReq); err != nil {
+			return fmt.Errorf("batch create bill resource cost failed, err: %v", err)
+		}
+	}
+	return nil
+}
+
+// costList 生成资源维度账单，关联到资源时使用资源所属的业务，未关联到资源时保留账单明细的业务
+func (agg *resourceCostAggregator) costList(resMap map[string]matchedResource) []bill.BillResourceCostCreateReq {
+	costList := make([]bill.BillResourceCostCreateReq, 0, len(agg.keys))
+	for _, key := range agg.keys {
+		cost := agg.costs[key]
+		if res, ok := resMap[key.ResCloudID]; ok {
+			cost.ResType = res.ResType
+			cost.ResID = res.ResID
+			cost.BkBizID = res.BkBizID
+		}
+		costList = append(costList, *cost)
+	}
+	return costList
+}
+
+type matchedResource struct {
+	ResType enumor.CloudResourceType
+	ResID   string
+	BkBizID int64
+}
+
+// resourceLister 查询指定云ID的资源，返回 cloud_id -> 资源 的映射
+type resourceLister func(kt *kit.Kit, cli *dataclient.Client, req *core.ListReq) (map[string]matchedResource, error)
+
+// resourceListers 按顺序依次关联主机、硬盘、弹性IP、负载均衡
+var resourceListers = []struct {
+	ResType enumor.CloudResourceType
+	List    resourceLister
+}{
+	{
+		ResType: enumor.CvmCloudResType,
+		List: func(kt *kit.Kit, cli *dataclient.Client, req *core.ListReq) (map[string]matchedResource, error) {
+			result, err := cli.Global.Cvm.ListCvm(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return cvt.SliceToMap(result.Details, func(one corecvm.BaseCvm) (string, matchedResource) {
+				return one.CloudID, matchedResource{ResType: enumor.CvmCloudResType, ResID: one.ID,
+					BkBizID: one.BkBizID}
+			}), nil
+		},
+	},
+	{
+		ResType: enumor.DiskCloudResType,
+		List: func(kt *kit.Kit, cli *dataclient.Client, req *core.ListReq) (map[string]matchedResource, error) {
+			result, err := cli.Global.ListDisk(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return cvt.SliceToMap(result.Details, func(one *coredisk.BaseDisk) (string, matchedResource) {
+				return one.CloudID, matchedResource{ResType: enumor.DiskCloudResType, ResID: one.ID,
+					BkBizID: one.BkBizID}
+			}), nil
+		},
+	},
+	{
+		ResType: enumor.EipCloudResType,
+		List: func(kt *kit.Kit, cli *dataclient.Client, req *core.ListReq) (map[string]matchedResource, error) {
+			result, err := cli.Global.ListEip(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return cvt.SliceToMap(result.Details, func(one *dataeip.EipResult) (string, matchedResource) {
+				return one.CloudID, matchedResource{ResType: enumor.EipCloudResType, ResID: one.ID,
+					BkBizID: one.BkBizID}
+			}), nil
+		},
+	},
+	{
+		ResType: enumor.LoadBalancerCloudResType,
+		List: func(kt *kit.Kit, cli *dataclient.Client, req *core.ListReq) (map[string]matchedResource, error) {
+			result, err := cli.Global.LoadBalancer.ListLoadBalancer(kt, req)
+			if err != nil {
+				return nil, err
+			}
+			return cvt.SliceToMap(result.Details, func(one corelb.BaseLoadBalancer) (string, matchedResource) {
+				return one.CloudID, matchedResource{ResType: enumor.LoadBalancerCloudResType, ResID: one.ID,
+					BkBizID: one.BkBizID}
+			}), nil
+		},
+	},
+}
+
+// matchResources 根据云ID关联已同步的资源，未关联到的云ID不在返回结果中
+func matchResources(kt *kit.Kit, cli *dataclient.Client, vendor enumor.Vendor, cloudIDs []string) (
+	map[string]matchedResource, error) {
+
+	resMap := make(map[string]matchedResource, len(cloudIDs))
+	for _, lister := range resourceListers {
+		unmatched := slice.Filter(cloudIDs, func(cloudID string) bool {
+			_, ok := resMap[cloudID]
+			return !ok
+		})
+		for _, batch := range slice.Split(unmatched, int(core.DefaultMaxPageLimit)) {
+			req := &core.ListReq{
+				Filter: tools.ExpressionAnd(
+					tools.RuleEqual("vendor", vendor),
+					tools.RuleIn("cloud_id", batch),
+				),
+				Page:   core.NewDefaultBasePage(),
+				Fields: []string{"id", "cloud_id", "bk_biz_id"},
+			}
+			matched, err := lister.List(kt, cli, req)
+			if err != nil {
+				logs.Errorf("list %s by cloud ids failed, err: %v, vendor: %s, rid: %s", lister.ResType, err,
+					vendor, kt.Rid)
+				return nil, fmt.Errorf("list %s by cloud ids failed, err: %v", lister.ResType, err)
+			}
+			for cloudID, res := range matched {
+				resMap[cloudID] = res
+			}
+		}
+	}
+	return resMap, nil
+}
+
+func getResourceCostFilter(opt *DailyAccountSplitActionOption, billDay int) *filter.Expression {
+	return tools.ExpressionAnd(
+		tools.RuleEqual("root_account_id", opt.RootAccountID),
+		tools.RuleEqual("main_account_id", opt.MainAccountID),
+		tools.RuleEqual("vendor", opt.Vendor),
+		tools.RuleEqual("bill_year", opt.BillYear),
+		tools.RuleEqual("bill_month", opt.BillMonth),
+		tools.RuleEqual("bill_day", billDay),
+	)
+}
+
+// cleanResourceCost 与账单明细一样清理当天所有版本的资源维度账单
+func cleanResourceCost(kt *kit.Kit, opt *DailyAccountSplitActionOption, billDay int) error {
+	for {
+		listReq := &core.ListReq{Filter: getResourceCostFilter(opt, billDay), Page: core.NewCountPage()}
+		result, err := actcli.GetDataService().Global.Bill.ListBillResourceCost(kt, listReq)
+		if err != nil {
+			logs.Warnf("count bill resource cost for %v day %d failed, err %s, rid %s", opt, billDay, err.Error(),
+				kt.Rid)
+			return fmt.Errorf("count bill resource cost for %v day %d failed, err %s", opt, billDay, err.Error())
+		}
+		if result.Count == 0 {
+			return nil
+		}
+
+		delReq := &dataservice.BatchDeleteReq{Filter: getResourceCostFilter(opt, billDay)}
+		if err := actcli.GetDataService().Global.Bill.BatchDeleteBillResourceCost(kt, delReq); err != nil {
+			return fmt.Errorf("delete %d bill resource cost for %v day %d failed, err %s",
+				result.Count, opt, billDay, err.Error())
+		}
+		logs.Infof("successfully delete batch %d bill resource cost for %v day %d, rid %s",
+			result.Count, opt, billDay, kt.Rid)
+	}
+}
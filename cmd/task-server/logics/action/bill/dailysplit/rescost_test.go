@@ -0,0 +1,136 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dailysplit
+
+import (
+	rawjson "encoding/json"
+	"testing"
+
+	"hcm/pkg/api/data-service/bill"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestResCloudIDExtractor(t *testing.T) {
+	tests := []struct {
+		name   string
+		vendor enumor.Vendor
+		ext    string
+		want   string
+	}{
+		{
+			name:   "aws line item resource id",
+			vendor: enumor.Aws,
+			ext:    `{"line_item_resource_id":"i-0abc","line_item_line_item_type":"Usage"}`,
+			want:   "i-0abc",
+		},
+		{
+			name:   "gcp resource global name",
+			vendor: enumor.Gcp,
+			ext:    `{"resource_global_name":"//compute.googleapis.com/projects/p/zones/z/instances/123456"}`,
+			want:   "123456",
+		},
+		{
+			name:   "gcp without resource",
+			vendor: enumor.Gcp,
+			ext:    `{"resource_global_name":null}`,
+			want:   "",
+		},
+		{
+			name:   "huawei resource id",
+			vendor: enumor.HuaWei,
+			ext:    `{"resource_id":"9f3c-server"}`,
+			want:   "9f3c-server",
+		},
+		{
+			name:   "azure resource id lower case",
+			vendor: enumor.Azure,
+			ext: `{"kind":"legacy","properties":{"resourceId":` +
+				`"/subscriptions/S/resourceGroups/RG/providers/Microsoft.Compute/virtualMachines/VM"}}`,
+			want: "/subscriptions/s/resourcegroups/rg/providers/microsoft.compute/virtualmachines/vm",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := vendorResCloudIDExtractor[tt.vendor](rawjson.RawMessage(tt.ext))
+			if err != nil {
+				t.Fatalf("extract resource cloud id failed, err: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("extract resource cloud id got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceCostAggregatorUseResourceBiz(t *testing.T) {
+	newItem := func(bizID int64, cloudID string, cost int64) bill.BillItemCreateReq[rawjson.RawMessage] {
+		ext := rawjson.RawMessage(`{"line_item_resource_id":"` + cloudID + `"}`)
+		return bill.BillItemCreateReq[rawjson.RawMessage]{
+			Vendor:    enumor.Aws,
+			ProductID: 1,
+			BkBizID:   bizID,
+			Currency:  enumor.CurrencyUSD,
+			Cost:      decimal.NewFromInt(cost),
+			Extension: &ext,
+		}
+	}
+
+	agg := newResourceCostAggregator(enumor.Aws)
+	// 同一资源的账单明细分到了不同业务，资源维度账单按资源汇总
+	items := []bill.BillItemCreateReq[rawjson.RawMessage]{
+		newItem(100, "i-matched", 1),
+		newItem(200, "i-matched", 2),
+		newItem(300, "i-unmatched", 4),
+	}
+	if err := agg.Add(items); err != nil {
+		t.Fatalf("add bill items failed, err: %v", err)
+	}
+
+	resMap := map[string]matchedResource{
+		"i-matched": {ResType: enumor.CvmCloudResType, ResID: "cvm-1", BkBizID: 400},
+	}
+	costs := agg.costList(resMap)
+	if len(costs) != 2 {
+		t.Fatalf("expect 2 resource costs, but got: %d", len(costs))
+	}
+
+	matched, unmatched := costs[0], costs[1]
+	if matched.BkBizID != 400 || matched.ResID != "cvm-1" || !matched.Cost.Equal(decimal.NewFromInt(3)) ||
+		matched.Count != 2 {
+		t.Errorf("matched resource cost should use resource biz, but got: %+v", matched)
+	}
+	if unmatched.BkBizID != 300 || len(unmatched.ResID) != 0 || !unmatched.Cost.Equal(decimal.NewFromInt(4)) {
+		t.Errorf("unmatched resource cost should keep bill item biz, but got: %+v", unmatched)
+	}
+}
+
+func TestResourceCostAggregatorSkipTCloud(t *testing.T) {
+	ext := rawjson.RawMessage(`{}`)
+	agg := newResourceCostAggregator(enumor.TCloud)
+	items := []bill.BillItemCreateReq[rawjson.RawMessage]{{Vendor: enumor.TCloud, Extension: &ext}}
+	if err := agg.Add(items); err != nil {
+		t.Fatalf("add tcloud bill items failed, err: %v", err)
+	}
+	if len(agg.costList(nil)) != 0 {
+		t.Errorf("tcloud bill items should be skipped")
+	}
+}
@@ -0,0 +1,245 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package mainsummary
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/api/data-service/bill"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/times"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// forecastWindowDays 预测使用的最近日账单天数，以最近的日均费用作为剩余天数的费用估计
+	forecastWindowDays = 14
+	// forecastZScore 置信区间对应的z值，1.96对应95%置信度
+	forecastZScore = 1.96
+)
+
+// costEstimate 以原币种计算的费用预测值及置信区间半宽
+type costEstimate struct {
+	Cost  decimal.Decimal
+	Delta decimal.Decimal
+	// Floor 已出账费用，预测下限不低于已出账费用
+	Floor decimal.Decimal
+}
+
+// estimateCost 根据当月已出账的日账单，预测当月剩余restDays天结束时的累计费用。
+// 剩余天数的日费用以最近 forecastWindowDays 天的均值μ估计，日费用标准差为σ，
+// 累计费用的标准差同时考虑每日波动和均值估计误差：σ*sqrt(R + R²/n)，R为剩余天数，n为样本数
+func estimateCost(dailyCosts []decimal.Decimal, restDays int) costEstimate {
+	observed := decimal.Zero
+	for _, cost := range dailyCosts {
+		observed = observed.Add(cost)
+	}
+	if restDays <= 0 || len(dailyCosts) == 0 {
+		return costEstimate{Cost: observed, Delta: decimal.Zero, Floor: observed}
+	}
+
+	window := dailyCosts
+	if len(window) > forecastWindowDays {
+		window = window[len(window)-forecastWindowDays:]
+	}
+	n := float64(len(window))
+	sum := 0.0
+	for _, cost := range window {
+		sum += cost.InexactFloat64()
+	}
+	mean := sum / n
+	std := math.Abs(mean)
+	if len(window) > 1 {
+		variance := 0.0
+		for _, cost := range window {
+			diff := cost.InexactFloat64() - mean
+			variance += diff * diff
+		}
+		std = math.Sqrt(variance / (n - 1))
+	}
+
+	rest := float64(restDays)
+	return costEstimate{
+		Cost:  observed.Add(decimal.NewFromFloat(mean * rest)),
+		Delta: decimal.NewFromFloat(forecastZScore * std * math.Sqrt(rest+rest*rest/n)),
+		Floor: observed,
+	}
+}
+
+// bounds 返回置信区间上下限，下限不低于已出账费用
+func (e costEstimate) bounds() (decimal.Decimal, decimal.Decimal) {
+	lower := decimal.Max(e.Cost.Sub(e.Delta), e.Floor)
+	return lower, e.Cost.Add(e.Delta)
+}
+
+// addFixed 叠加已确定的费用，该费用没有波动，只平移预测值和已出账费用，不影响置信区间半宽
+func (e costEstimate) addFixed(cost decimal.Decimal) costEstimate {
+	return costEstimate{Cost: e.Cost.Add(cost), Delta: e.Delta, Floor: e.Floor.Add(cost)}
+}
+
+// quarterRestDays 返回账单月所在季度中，账单月之后的剩余月份总天数
+func quarterRestDays(billYear, billMonth int) int {
+	days := 0
+	quarterEndMonth := (billMonth-1)/3*3 + 3
+	for month := billMonth + 1; month <= quarterEndMonth; month++ {
+		days += times.DaysInMonth(billYear, time.Month(month))
+	}
+	return days
+}
+
+// pastQuarterCost 账单月所在季度中，账单月之前各月份的费用
+type pastQuarterCost struct {
+	Cost    decimal.Decimal
+	RMBCost decimal.Decimal
+}
+
+// buildCostForecast 预测月末及季末费用，返回原币种预测，以及使用当月汇率折算的人民币预测，汇率为空时人民币预测为空。
+// 剩余天数按最近的账单日计算，部分日期缺少日账单时不会把缺少的日期计入剩余天数。
+// extraCost 为月度账单任务拉取的不按日出账的费用，与当月实时费用一致计入已出账费用；
+// 季末预测叠加季度内已过去月份的费用，人民币预测使用已过去月份已汇总的人民币费用
+func buildCostForecast(dailyCosts []decimal.Decimal, lastBillDay, billYear, billMonth int, extraCost decimal.Decimal,
+	past pastQuarterCost, exchangeRate *decimal.Decimal) (*billcore.CurrencyCostForecast, *billcore.CostForecast) {
+
+	restDays := times.DaysInMonth(billYear, time.Month(billMonth)) - lastBillDay
+	month := estimateCost(dailyCosts, restDays).addFixed(extraCost)
+	quarter := estimateCost(dailyCosts, restDays+quarterRestDays(billYear, billMonth)).addFixed(extraCost)
+
+	monthLower, monthUpper := month.bounds()
+	quarterLower, quarterUpper := quarter.bounds()
+	currencyForecast := &billcore.CurrencyCostForecast{
+		ForecastMonthCost:    month.Cost,
+		ForecastMonthLower:   monthLower,
+		ForecastMonthUpper:   monthUpper,
+		ForecastQuarterCost:  quarter.Cost.Add(past.Cost),
+		ForecastQuarterLower: quarterLower.Add(past.Cost),
+		ForecastQuarterUpper: quarterUpper.Add(past.Cost),
+	}
+	if exchangeRate == nil {
+		return currencyForecast, nil
+	}
+
+	rate := *exchangeRate
+	return currencyForecast, &billcore.CostForecast{
+		ForecastMonthRMBCost:    month.Cost.Mul(rate),
+		ForecastMonthRMBLower:   monthLower.Mul(rate),
+		ForecastMonthRMBUpper:   monthUpper.Mul(rate),
+		ForecastQuarterRMBCost:  quarter.Cost.Mul(rate).Add(past.RMBCost),
+		ForecastQuarterRMBLower: quarterLower.Mul(rate).Add(past.RMBCost),
+		ForecastQuarterRMBUpper: quarterUpper.Mul(rate).Add(past.RMBCost),
+	}
+}
+
+// forecastCost 预测二级账号月末及季末费用，当月尚无日账单时不做预测，缺少汇率时只预测原币种费用
+func (act *MainAccountSummaryAction) forecastCost(kt *kit.Kit, opt *MainAccountSummaryActionOption, versionID int,
+	extraCost decimal.Decimal, exchangeRate *decimal.Decimal) (*billcore.CurrencyCostForecast,
+	*billcore.CostForecast, error) {
+
+	dailyCosts, lastBillDay, err := act.getDailyCostList(kt, opt, versionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(dailyCosts) == 0 {
+		return nil, nil, nil
+	}
+	past, err := act.getPastQuarterCost(kt, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	currencyForecast, rmbForecast := buildCostForecast(dailyCosts, lastBillDay, opt.BillYear, opt.BillMonth,
+		extraCost, past, exchangeRate)
+	return currencyForecast, rmbForecast, nil
+}
+
+// getDailyCostList 获取二级账号当月指定版本的每日费用，按账单日期升序排列，同时返回最近的账单日
+func (act *MainAccountSummaryAction) getDailyCostList(kt *kit.Kit, opt *MainAccountSummaryActionOption,
+	versionID int) ([]decimal.Decimal, int, error) {
+
+	expressions := []*filter.AtomRule{
+		tools.RuleEqual("root_account_id", opt.RootAccountID),
+		tools.RuleEqual("main_account_id", opt.MainAccountID),
+		tools.RuleEqual("vendor", opt.Vendor),
+		tools.RuleEqual("bill_year", opt.BillYear),
+		tools.RuleEqual("bill_month", opt.BillMonth),
+		tools.RuleEqual("version_id", versionID),
+	}
+	result, err := actcli.GetDataService().Global.Bill.ListBillSummaryDaily(kt, &bill.BillSummaryDailyListReq{
+		Filter: tools.ExpressionAnd(expressions...),
+		Page:   &core.BasePage{Start: 0, Limit: 31},
+		Fields: []string{"bill_day", "cost"},
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("list daily summary of %v failed, err: %v", opt, err)
+	}
+	sort.Slice(result.Details, func(i, j int) bool {
+		return result.Details[i].BillDay < result.Details[j].BillDay
+	})
+	costs := make([]decimal.Decimal, 0, len(result.Details))
+	lastBillDay := 0
+	for _, one := range result.Details {
+		costs = append(costs, one.Cost)
+		lastBillDay = one.BillDay
+	}
+	return costs, lastBillDay, nil
+}
+
+// getPastQuarterCost 获取账单月所在季度中，账单月之前各月份的原币种及人民币费用之和
+func (act *MainAccountSummaryAction) getPastQuarterCost(kt *kit.Kit, opt *MainAccountSummaryActionOption) (
+	pastQuarterCost, error) {
+
+	quarterStartMonth := (opt.BillMonth-1)/3*3 + 1
+	if quarterStartMonth == opt.BillMonth {
+		return pastQuarterCost{}, nil
+	}
+	months := make([]int, 0, opt.BillMonth-quarterStartMonth)
+	for month := quarterStartMonth; month < opt.BillMonth; month++ {
+		months = append(months, month)
+	}
+	expressions := []*filter.AtomRule{
+		tools.RuleEqual("root_account_id", opt.RootAccountID),
+		tools.RuleEqual("main_account_id", opt.MainAccountID),
+		tools.RuleEqual("vendor", opt.Vendor),
+		tools.RuleEqual("bill_year", opt.BillYear),
+		tools.RuleIn("bill_month", months),
+	}
+	result, err := actcli.GetDataService().Global.Bill.ListBillSummaryMain(kt, &bill.BillSummaryMainListReq{
+		Filter: tools.ExpressionAnd(expressions...),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"current_month_cost", "current_month_rmb_cost"},
+	})
+	if err != nil {
+		return pastQuarterCost{}, fmt.Errorf("list past quarter main account bill summary of %v failed, err: %v",
+			opt, err)
+	}
+	past := pastQuarterCost{Cost: decimal.Zero, RMBCost: decimal.Zero}
+	for _, one := range result.Details {
+		past.Cost = past.Cost.Add(one.CurrentMonthCost)
+		past.RMBCost = past.RMBCost.Add(one.CurrentMonthRMBCost)
+	}
+	return past, nil
+}
@@ -0,0 +1,126 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package mainsummary
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func dailyCostsOf(costs ...int64) []decimal.Decimal {
+	result := make([]decimal.Decimal, 0, len(costs))
+	for _, cost := range costs {
+		result = append(result, decimal.NewFromInt(cost))
+	}
+	return result
+}
+
+func TestBuildCostForecast(t *testing.T) {
+	rate := decimal.NewFromInt(2)
+	// 2024-04 共30天，已出账10天，每天100，无波动
+	flatCurrency, flat := buildCostForecast(dailyCostsOf(100, 100, 100, 100, 100, 100, 100, 100, 100, 100), 10,
+		2024, 4, decimal.Zero, pastQuarterCost{}, &rate)
+	if !flatCurrency.ForecastMonthCost.Equal(decimal.NewFromInt(3000)) {
+		t.Errorf("month forecast got %s, want 3000", flatCurrency.ForecastMonthCost)
+	}
+	if !flat.ForecastMonthRMBCost.Equal(decimal.NewFromInt(6000)) {
+		t.Errorf("month rmb forecast got %s, want 6000", flat.ForecastMonthRMBCost)
+	}
+	if !flat.ForecastMonthRMBLower.Equal(flat.ForecastMonthRMBUpper) {
+		t.Errorf("flat daily cost should have no band, got [%s, %s]", flat.ForecastMonthRMBLower,
+			flat.ForecastMonthRMBUpper)
+	}
+	// 季度剩余 20+31+30 天
+	if !flat.ForecastQuarterRMBCost.Equal(decimal.NewFromInt(18200)) {
+		t.Errorf("quarter forecast got %s, want 18200", flat.ForecastQuarterRMBCost)
+	}
+
+	// 季度末月份的季度预测叠加季度内已过去月份的费用，原币种和人民币分别叠加各自的已过去费用
+	past := pastQuarterCost{Cost: decimal.NewFromInt(250), RMBCost: decimal.NewFromInt(500)}
+	lastCurrency, last := buildCostForecast(dailyCostsOf(10, 20, 30), 3, 2024, 3, decimal.Zero, past, &rate)
+	if !lastCurrency.ForecastQuarterCost.Equal(decimal.NewFromInt(250 + 60 + 20*28)) {
+		t.Errorf("quarter forecast got %s, want %d", lastCurrency.ForecastQuarterCost, 250+60+20*28)
+	}
+	if !last.ForecastQuarterRMBCost.Equal(decimal.NewFromInt(500 + (60+20*28)*2)) {
+		t.Errorf("quarter rmb forecast got %s, want %d", last.ForecastQuarterRMBCost, 500+(60+20*28)*2)
+	}
+
+	one := decimal.NewFromInt(1)
+	_, noisy := buildCostForecast(dailyCostsOf(50, 150, 80, 120, 100), 5, 2024, 3, decimal.Zero, pastQuarterCost{},
+		&one)
+	if !noisy.ForecastMonthRMBLower.LessThan(noisy.ForecastMonthRMBCost) ||
+		!noisy.ForecastMonthRMBUpper.GreaterThan(noisy.ForecastMonthRMBCost) {
+		t.Errorf("forecast should lie inside band, got %s in [%s, %s]", noisy.ForecastMonthRMBCost,
+			noisy.ForecastMonthRMBLower, noisy.ForecastMonthRMBUpper)
+	}
+	if noisy.ForecastMonthRMBLower.LessThan(decimal.NewFromInt(500)) {
+		t.Errorf("lower bound %s should not be less than observed cost 500", noisy.ForecastMonthRMBLower)
+	}
+	if noisy.ForecastQuarterRMBUpper.Sub(noisy.ForecastQuarterRMBCost).
+		LessThan(noisy.ForecastMonthRMBUpper.Sub(noisy.ForecastMonthRMBCost)) {
+		t.Errorf("quarter band should be wider than month band")
+	}
+}
+
+func TestBuildCostForecastWithMissingDays(t *testing.T) {
+	// 2024-03 共31天，4日的日账单缺失，最近账单日为5日，剩余26天
+	forecast, _ := buildCostForecast(dailyCostsOf(100, 100, 100, 100), 5, 2024, 3, decimal.Zero, pastQuarterCost{},
+		nil)
+	if !forecast.ForecastMonthCost.Equal(decimal.NewFromInt(400 + 100*26)) {
+		t.Errorf("month forecast got %s, want %d", forecast.ForecastMonthCost, 400+100*26)
+	}
+}
+
+func TestBuildCostForecastWithoutExchangeRate(t *testing.T) {
+	// 缺少汇率时仍预测原币种费用，人民币预测为空
+	currencyForecast, rmbForecast := buildCostForecast(dailyCostsOf(100, 100), 2, 2024, 2, decimal.Zero,
+		pastQuarterCost{}, nil)
+	if rmbForecast != nil {
+		t.Errorf("rmb forecast should be empty without exchange rate, got %+v", rmbForecast)
+	}
+	if currencyForecast == nil || !currencyForecast.ForecastMonthCost.Equal(decimal.NewFromInt(2900)) {
+		t.Errorf("month forecast got %+v, want 2900", currencyForecast)
+	}
+}
+
+func TestBuildCostForecastWithExtraCost(t *testing.T) {
+	daily := dailyCostsOf(50, 150, 80, 120, 100)
+	base, _ := buildCostForecast(daily, 5, 2024, 3, decimal.Zero, pastQuarterCost{}, nil)
+	// 月度账单任务的费用计入当月实时费用，预测值、上下限整体平移，置信区间半宽不变
+	extra := decimal.NewFromInt(-300)
+	got, _ := buildCostForecast(daily, 5, 2024, 3, extra, pastQuarterCost{}, nil)
+	expects := []struct {
+		name      string
+		got, base decimal.Decimal
+	}{
+		{name: "month cost", got: got.ForecastMonthCost, base: base.ForecastMonthCost},
+		{name: "month lower", got: got.ForecastMonthLower, base: base.ForecastMonthLower},
+		{name: "month upper", got: got.ForecastMonthUpper, base: base.ForecastMonthUpper},
+		{name: "quarter cost", got: got.ForecastQuarterCost, base: base.ForecastQuarterCost},
+		{name: "quarter lower", got: got.ForecastQuarterLower, base: base.ForecastQuarterLower},
+		{name: "quarter upper", got: got.ForecastQuarterUpper, base: base.ForecastQuarterUpper},
+	}
+	for _, one := range expects {
+		if !one.got.Sub(one.base).Equal(extra) {
+			t.Errorf("%s got %s, want %s shifted by %s", one.name, one.got, one.base, extra)
+		}
+	}
+}
@@ -124,9 +124,13 @@ func (act MainAccountSummaryAction) Run(kt run.ExecuteKit, params interface{}) (
 		return nil, fmt.Errorf("get current month cost failed, err %s", err.Error())
 	}
 
-	// 获取当月平均汇率
+	// 获取当月平均汇率，人民币账单汇率为1
 	var exchangeRate *decimal.Decimal
-	if len(currency) != 0 {
+	switch currency {
+	case "":
+	case enumor.CurrencyRMB:
+		exchangeRate = cvt.ValToPtr(decimal.NewFromInt(1))
+	default:
 		exchangeRate, err = act.getExchangeRate(kt.Kit(), currency, enumor.CurrencyRMB, opt.BillYear, opt.BillMonth)
 		if err != nil {
 			return nil, err
@@ -153,6 +157,8 @@ func (act MainAccountSummaryAction) Run(kt run.ExecuteKit, params interface{}) (
 		req.MonthOnMonthValue = curMonthCostSynced.DivRound(*lastMonthCostSynced, 5).InexactFloat64()
 	}
 
+	// 计入当月实时成本的月度账单费用
+	monthTaskCost := decimal.Zero
 	if isCurMonthAccounted {
 		// 如果当月所有日账单都已经分账，那么就获取月度账单状态
 		extraCost, isFinished, err := act.calculateMonthTaskStatus(kt.Kit(), rootSummary, summary)
@@ -161,6 +167,7 @@ func (act MainAccountSummaryAction) Run(kt run.ExecuteKit, params interface{}) (
 			return nil, err
 		}
 		if isFinished {
+			monthTaskCost = extraCost
 			req.CurrentMonthCost = cvt.ValToPtr(extraCost.Add(cvt.PtrToVal(req.CurrentMonthCost)))
 			req.State = enumor.MainAccountBillSummaryStateAccounted
 		} else {
@@ -168,6 +175,13 @@ func (act MainAccountSummaryAction) Run(kt run.ExecuteKit, params interface{}) (
 		}
 	}
 	req = calRMBCost(req, exchangeRate)
+	// 基于日账单预测月末及季末费用，缺少汇率时人民币预测为空，不更新
+	req.CurrencyCostForecast, req.CostForecast, err = act.forecastCost(kt.Kit(), opt, summary.CurrentVersion,
+		monthTaskCost, exchangeRate)
+	if err != nil {
+		logs.Errorf("fail to forecast main account cost, err: %v, rid: %s", err, kt.Kit().Rid)
+		return nil, err
+	}
 	if err := actcli.GetDataService().Global.Bill.UpdateBillSummaryMain(kt.Kit(), req); err != nil {
 		logs.Errorf("failed to update main account bill summary %+v, err: %v, rid: %s", opt, err, kt.Kit().Rid)
 		return nil, fmt.Errorf("failed to update main account bill summary %+v, err %v", opt, err)
@@ -87,6 +87,8 @@ func (act RootAccountSummaryAction) Run(kt run.ExecuteKit, params interface{}) (
 	currentRMBCost := decimal.NewFromFloat(0)
 	adjustmentCost := decimal.NewFromFloat(0)
 	adjustmentRMBCost := decimal.NewFromFloat(0)
+	forecast := billcore.CostForecast{}
+	currencyForecast := billcore.CurrencyCostForecast{}
 	isAccounted := true
 	bkBizNum := uint64(0)
 	productNum := uint64(0)
@@ -104,6 +106,8 @@ func (act RootAccountSummaryAction) Run(kt run.ExecuteKit, params interface{}) (
 		currentRMBCost = currentRMBCost.Add(mainSummary.CurrentMonthRMBCost)
 		adjustmentCost = adjustmentCost.Add(mainSummary.AdjustmentCost)
 		adjustmentRMBCost = adjustmentRMBCost.Add(mainSummary.AdjustmentRMBCost)
+		forecast = forecast.Add(mainSummary.CostForecast)
+		currencyForecast = currencyForecast.Add(mainSummary.CurrencyCostForecast)
 		if mainSummary.BkBizID > 0 {
 			bkBizNum = bkBizNum + 1
 		}
@@ -135,6 +139,8 @@ func (act RootAccountSummaryAction) Run(kt run.ExecuteKit, params interface{}) (
 		Rate:                      rate,
 		BkBizNum:                  bkBizNum,
 		ProductNum:                productNum,
+		CostForecast:              &forecast,
+		CurrencyCostForecast:      &currencyForecast,
 	}
 	if !lastMonthSyncedCost.IsZero() {
 		req.MonthOnMonthValue = currentCostSynced.DivRound(lastMonthSyncedCost, 5).InexactFloat64()
@@ -32,7 +32,6 @@ import (
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/criteria/validator"
-	"hcm/pkg/logs"
 	"hcm/pkg/tools/json"
 )
 
@@ -198,16 +197,17 @@ func (act CreateCvmAction) Run(kt run.ExecuteKit, params interface{}) (interface
 		return nil, fmt.Errorf("vendor: %s not support", opt.Vendor)
 	}
 	if err != nil {
-		logs.Errorf("batch create cvm failed, err: %v, result: %+v, rid: %s", err, result, kt.Kit().Rid)
+		kt.Logger().Errorf("batch create %s cvm failed, err: %v, result: %+v", opt.Vendor, err, result)
 		return result, err
 	}
 
+	kt.Logger().Infof("batch create %s cvm done, success cloud ids: %v", opt.Vendor, result.SuccessCloudIDs)
 	if len(result.FailedMessage) != 0 {
 		return result, errors.New(result.FailedMessage)
 	}
 
 	if err = kt.ShareData().AppendIDs(kt.Kit(), SaveCreateCvmCloudIDKey, result.SuccessCloudIDs...); err != nil {
-		logs.Errorf("share data appendIDs failed, err: %v, rid: %s", err, kt.Kit().Rid)
+		kt.Logger().Errorf("share data appendIDs failed, err: %v", err)
 		return result, err
 	}
 
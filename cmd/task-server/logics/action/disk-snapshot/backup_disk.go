@@ -0,0 +1,308 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actiondisksnapshot
+
+import (
+	"fmt"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	corebackup "hcm/pkg/api/core/disk-backup"
+	dsbackup "hcm/pkg/api/data-service/disk-backup"
+	hcproto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/json"
+)
+
+// BackupDiskAction backup one disk of disk backup run, it submits snapshot creation of the disk, then prunes the
+// expired snapshots created by the policy. failure of the disk is recorded in the flow share data instead of being
+// returned, so that it does not stop backup of other disks and the run can still be finished.
+type BackupDiskAction struct {
+}
+
+// BackupDiskOption ...
+type BackupDiskOption struct {
+	PolicyID     string        `json:"policy_id" validate:"required"`
+	RunID        string        `json:"run_id" validate:"required"`
+	Vendor       enumor.Vendor `json:"vendor" validate:"required"`
+	DiskID       string        `json:"disk_id" validate:"required"`
+	SnapshotName string        `json:"snapshot_name" validate:"required"`
+	// RetentionCount 每块硬盘保留的该策略创建的快照数量
+	RetentionCount uint `json:"retention_count" validate:"required,min=1"`
+}
+
+// Validate ...
+func (opt *BackupDiskOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ParameterNew returns parameter of BackupDiskAction
+func (act BackupDiskAction) ParameterNew() (params interface{}) {
+	return new(BackupDiskOption)
+}
+
+// Name ActionBackupDisk
+func (act BackupDiskAction) Name() enumor.ActionName {
+	return enumor.ActionBackupDisk
+}
+
+// Run ...
+func (act BackupDiskAction) Run(kt run.ExecuteKit, params any) (any, error) {
+	opt, ok := params.(*BackupDiskOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := backupDisk(kt.Kit(), opt)
+
+	val, err := json.MarshalToString(result)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = kt.ShareData().Set(kt.Kit(), diskBackupResultKey(opt.DiskID), val); err != nil {
+		logs.Errorf("save disk backup result failed, err: %v, run: %s, disk: %s, rid: %s", err, opt.RunID,
+			opt.DiskID, kt.Kit().Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// diskBackupResult 单块硬盘的备份结果
+type diskBackupResult struct {
+	// Snapshotted 快照是否提交创建成功
+	Snapshotted bool   `json:"snapshotted"`
+	Pruned      uint   `json:"pruned"`
+	Stage       string `json:"stage,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+func diskBackupResultKey(diskID string) string {
+	return fmt.Sprintf("disk_backup_%s", diskID)
+}
+
+// backupDisk 为硬盘创建快照并清理过期快照，失败原因记录在备份结果中
+func backupDisk(kt *kit.Kit, opt *BackupDiskOption) *diskBackupResult {
+	createReq := &hcproto.CreateReq{DiskID: opt.DiskID, Name: opt.SnapshotName, BackupPolicyID: opt.PolicyID}
+	if _, err := createSnapshot(kt, opt.Vendor, createReq); err != nil {
+		logs.Errorf("create disk backup snapshot failed, err: %v, policy: %s, disk: %s, rid: %s", err,
+			opt.PolicyID, opt.DiskID, kt.Rid)
+		return &diskBackupResult{Stage: corebackup.SnapshotStage, Reason: err.Error()}
+	}
+
+	pruned, err := pruneSnapshots(kt, opt.PolicyID, opt.DiskID, opt.RetentionCount)
+	if err != nil {
+		logs.Errorf("prune disk backup snapshot failed, err: %v, policy: %s, disk: %s, rid: %s", err,
+			opt.PolicyID, opt.DiskID, kt.Rid)
+		return &diskBackupResult{Snapshotted: true, Pruned: pruned, Stage: corebackup.PruneStage,
+			Reason: err.Error()}
+	}
+
+	return &diskBackupResult{Snapshotted: true, Pruned: pruned}
+}
+
+// FinishDiskBackupRunAction finish disk backup run action, it summarizes backup results of all disks recorded in
+// the flow share data, and finishes the disk backup run record.
+type FinishDiskBackupRunAction struct {
+}
+
+// FinishDiskBackupRunOption ...
+type FinishDiskBackupRunOption struct {
+	RunID   string   `json:"run_id" validate:"required"`
+	DiskIDs []string `json:"disk_ids" validate:"required"`
+}
+
+// Validate ...
+func (opt *FinishDiskBackupRunOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ParameterNew returns parameter of FinishDiskBackupRunAction
+func (act FinishDiskBackupRunAction) ParameterNew() (params interface{}) {
+	return new(FinishDiskBackupRunOption)
+}
+
+// Name ActionFinishDiskBackupRun
+func (act FinishDiskBackupRunAction) Name() enumor.ActionName {
+	return enumor.ActionFinishDiskBackupRun
+}
+
+// Run ...
+func (act FinishDiskBackupRunAction) Run(kt run.ExecuteKit, params any) (any, error) {
+	opt, ok := params.(*FinishDiskBackupRunOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*diskBackupResult, len(opt.DiskIDs))
+	for _, diskID := range opt.DiskIDs {
+		val, exist := kt.ShareData().Get(diskBackupResultKey(diskID))
+		if !exist {
+			continue
+		}
+
+		result := new(diskBackupResult)
+		if err := json.UnmarshalFromString(val, result); err != nil {
+			logs.Errorf("decode disk backup result failed, err: %v, disk: %s, rid: %s", err, diskID, kt.Kit().Rid)
+			continue
+		}
+		results[diskID] = result
+	}
+
+	updateReq := summarizeBackupRun(opt.DiskIDs, results)
+	err := actcli.GetDataService().Global.DiskBackupPolicy.UpdateRun(freshKit(kt.Kit()), opt.RunID, updateReq)
+	if err != nil {
+		logs.Errorf("finish disk backup run failed, err: %v, run: %s, rid: %s", err, opt.RunID, kt.Kit().Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// summarizeBackupRun 汇总各硬盘的备份结果，没有备份结果的硬盘视为快照创建失败
+func summarizeBackupRun(diskIDs []string, results map[string]*diskBackupResult) *dsbackup.RunUpdateReq {
+	failed := make([]corebackup.FailedDisk, 0)
+	var successCount, prunedCount uint
+	for _, diskID := range diskIDs {
+		result, exist := results[diskID]
+		if !exist {
+			failed = append(failed, corebackup.FailedDisk{DiskID: diskID, Stage: corebackup.SnapshotStage,
+				Reason: "backup result of disk is not found"})
+			continue
+		}
+
+		if result.Snapshotted {
+			successCount++
+		}
+		prunedCount += result.Pruned
+
+		if len(result.Stage) != 0 {
+			failed = append(failed, corebackup.FailedDisk{DiskID: diskID, Stage: result.Stage,
+				Reason: result.Reason})
+		}
+	}
+
+	state := enumor.DiskBackupSuccess
+	switch {
+	case len(failed) == 0:
+	case successCount == 0:
+		state = enumor.DiskBackupFailed
+	default:
+		state = enumor.DiskBackupPartialFailed
+	}
+
+	return &dsbackup.RunUpdateReq{
+		State:         state,
+		TargetCount:   converter.ValToPtr(uint(len(diskIDs))),
+		SuccessCount:  converter.ValToPtr(successCount),
+		FailedCount:   converter.ValToPtr(uint(len(diskIDs)) - successCount),
+		PrunedCount:   converter.ValToPtr(prunedCount),
+		FailedDetails: failed,
+		Finished:      true,
+	}
+}
+
+// pruneSnapshots 清理硬盘上由备份策略创建的过期快照，返回清理的快照数量
+func pruneSnapshots(kt *kit.Kit, policyID, diskID string, retention uint) (uint, error) {
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("backup_policy_id", policyID),
+			tools.RuleEqual("disk_id", diskID),
+		),
+		Page: &core.BasePage{
+			Start: 0,
+			Limit: core.DefaultMaxPageLimit,
+			Sort:  "created_at",
+			Order: core.Descending,
+		},
+	}
+	snapshots, err := actcli.GetDataService().Global.DiskSnapshot.List(kt, req)
+	if err != nil {
+		return 0, err
+	}
+
+	var pruned uint
+	for _, snapshot := range selectExpiredSnapshots(snapshots.Details, retention) {
+		if err = deleteSnapshot(kt, snapshot.Vendor, snapshot.ID); err != nil {
+			return pruned, fmt.Errorf("delete snapshot %s failed, err: %v", snapshot.ID, err)
+		}
+		pruned++
+	}
+
+	return pruned, nil
+}
+
+// selectExpiredSnapshots 从按创建时间倒序排列的快照中选出过期快照。快照创建是异步的，只有创建完成的快照计入保留数量，
+// 保留最新的 retention 个创建完成的快照，更早的创建完成的快照和创建失败的快照为过期快照，创建中的快照不清理，
+// 避免新快照创建失败时已经清理了可用的旧快照
+func selectExpiredSnapshots(snapshots []coresnapshot.DiskSnapshot, retention uint) []coresnapshot.DiskSnapshot {
+	expired := make([]coresnapshot.DiskSnapshot, 0)
+	var kept uint
+	for _, snapshot := range snapshots {
+		switch {
+		case snapshot.IsFailed():
+			expired = append(expired, snapshot)
+		case !snapshot.IsReady():
+		case kept < retention:
+			kept++
+		default:
+			expired = append(expired, snapshot)
+		}
+	}
+
+	return expired
+}
+
+// deleteSnapshot 调用对应云厂商的接口删除硬盘快照
+func deleteSnapshot(kt *kit.Kit, vendor enumor.Vendor, id string) error {
+	cli := actcli.GetHCService()
+	req := &hcproto.DeleteReq{ID: id}
+	switch vendor {
+	case enumor.TCloud:
+		return cli.TCloud.DiskSnapshot.DeleteDiskSnapshot(kt, req)
+	case enumor.Aws:
+		return cli.Aws.DiskSnapshot.DeleteDiskSnapshot(kt, req)
+	case enumor.HuaWei:
+		return cli.HuaWei.DiskSnapshot.DeleteDiskSnapshot(kt, req)
+	case enumor.Gcp:
+		return cli.Gcp.DiskSnapshot.DeleteDiskSnapshot(kt, req)
+	case enumor.Azure:
+		return cli.Azure.DiskSnapshot.DeleteDiskSnapshot(kt, req)
+	default:
+		return errf.Newf(errf.Unknown, "snapshot: %s vendor: %s not support", id, vendor)
+	}
+}
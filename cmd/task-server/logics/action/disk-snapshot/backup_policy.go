@@ -0,0 +1,245 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actiondisksnapshot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	"hcm/pkg/api/core"
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	corebackup "hcm/pkg/api/core/disk-backup"
+	dsbackup "hcm/pkg/api/data-service/disk-backup"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/counter"
+)
+
+// RunDiskBackupPolicyAction run disk backup policy action, it creates a disk backup run record and submits a
+// disk_backup_run flow, in which each target disk of the policy is backed up by its own task, so that backup of
+// many disks is not limited by the execution timeout of one task. the run record is finished by the last task of
+// the flow after all disks are backed up.
+type RunDiskBackupPolicyAction struct {
+}
+
+// RunDiskBackupPolicyOption ...
+type RunDiskBackupPolicyOption struct {
+	PolicyID string                   `json:"policy_id" validate:"required"`
+	Trigger  enumor.DiskBackupTrigger `json:"trigger" validate:"required"`
+}
+
+// Validate ...
+func (opt *RunDiskBackupPolicyOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	return opt.Trigger.Validate()
+}
+
+// ParameterNew returns parameter of RunDiskBackupPolicyAction
+func (act RunDiskBackupPolicyAction) ParameterNew() (params interface{}) {
+	return new(RunDiskBackupPolicyOption)
+}
+
+// Name ActionRunDiskBackupPolicy
+func (act RunDiskBackupPolicyAction) Name() enumor.ActionName {
+	return enumor.ActionRunDiskBackupPolicy
+}
+
+// Run ...
+func (act RunDiskBackupPolicyAction) Run(kt run.ExecuteKit, params any) (any, error) {
+	opt, ok := params.(*RunDiskBackupPolicyOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	policy, err := getBackupPolicy(kt.Kit(), opt.PolicyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !policy.Enabled && opt.Trigger == enumor.TimingDiskBackupTrigger {
+		logs.Infof("disk backup policy %s is disabled, skip timing backup, rid: %s", policy.ID, kt.Kit().Rid)
+		return nil, nil
+	}
+
+	dsCli := actcli.GetDataService().Global.DiskBackupPolicy
+	runReq := &dsbackup.RunCreateReq{PolicyID: policy.ID, BkBizID: policy.BkBizID, TriggerType: opt.Trigger}
+	created, err := dsCli.CreateRun(kt.Kit(), runReq)
+	if err != nil {
+		logs.Errorf("create disk backup run failed, err: %v, policy: %s, rid: %s", err, policy.ID, kt.Kit().Rid)
+		return nil, err
+	}
+
+	disks, err := listTargetDisks(kt.Kit(), policy)
+	if err != nil {
+		failRun(kt.Kit(), created.ID, err)
+		return nil, err
+	}
+
+	// 没有目标硬盘时直接结束执行记录
+	if len(disks) == 0 {
+		if err = dsCli.UpdateRun(freshKit(kt.Kit()), created.ID, summarizeBackupRun(nil, nil)); err != nil {
+			logs.Errorf("update disk backup run failed, err: %v, run: %s, rid: %s", err, created.ID,
+				kt.Kit().Rid)
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	flowReq := buildBackupRunFlow(policy, created.ID, disks, time.Now())
+	result, err := actcli.GetClientSet().TaskServer().CreateCustomFlow(kt.Kit(), flowReq)
+	if err != nil {
+		logs.Errorf("create disk backup run flow failed, err: %v, policy: %s, run: %s, rid: %s", err, policy.ID,
+			created.ID, kt.Kit().Rid)
+		failRun(kt.Kit(), created.ID, err)
+		return nil, err
+	}
+
+	updateReq := &dsbackup.RunUpdateReq{TargetCount: converter.ValToPtr(uint(len(disks)))}
+	if err = dsCli.UpdateRun(kt.Kit(), created.ID, updateReq); err != nil {
+		// 目标硬盘数量在备份结束时会再次更新，这里失败不影响备份执行
+		logs.Errorf("update disk backup run target count failed, err: %v, run: %s, rid: %s", err, created.ID,
+			kt.Kit().Rid)
+	}
+
+	logs.Infof("submit disk backup run flow success, policy: %s, run: %s, flow: %s, disks: %d, rid: %s",
+		policy.ID, created.ID, result.ID, len(disks), kt.Kit().Rid)
+
+	return result, nil
+}
+
+func getBackupPolicy(kt *kit.Kit, id string) (*corebackup.DiskBackupPolicy, error) {
+	req := &core.ListReq{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	}
+	result, err := actcli.GetDataService().Global.DiskBackupPolicy.List(kt, req)
+	if err != nil {
+		logs.Errorf("list disk backup policy failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(result.Details) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "disk backup policy %s not found", id)
+	}
+
+	return &result.Details[0], nil
+}
+
+// buildBackupRunFlow 构建备份策略一次执行的任务流，每块目标硬盘一个备份任务，汇总任务依赖全部备份任务
+func buildBackupRunFlow(policy *corebackup.DiskBackupPolicy, runID string, disks []*coredisk.BaseDisk,
+	now time.Time) *ts.AddCustomFlowReq {
+
+	nextID := counter.NewNumStringCounter(1, 10)
+	tasks := make([]ts.CustomFlowTask, 0, len(disks)+1)
+	diskIDs := make([]string, 0, len(disks))
+	dependOn := make([]action.ActIDType, 0, len(disks))
+	for _, disk := range disks {
+		actionID := action.ActIDType(nextID())
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   actionID,
+			ActionName: enumor.ActionBackupDisk,
+			Params: &BackupDiskOption{
+				PolicyID:       policy.ID,
+				RunID:          runID,
+				Vendor:         enumor.Vendor(disk.Vendor),
+				DiskID:         disk.ID,
+				SnapshotName:   corebackup.SnapshotName(policy.ID, disk.ID, now.Unix()),
+				RetentionCount: policy.RetentionCount,
+			},
+		})
+		diskIDs = append(diskIDs, disk.ID)
+		dependOn = append(dependOn, actionID)
+	}
+
+	tasks = append(tasks, ts.CustomFlowTask{
+		ActionID:   action.ActIDType(nextID()),
+		ActionName: enumor.ActionFinishDiskBackupRun,
+		Params:     &FinishDiskBackupRunOption{RunID: runID, DiskIDs: diskIDs},
+		DependOn:   dependOn,
+	})
+
+	return &ts.AddCustomFlowReq{
+		Name:  enumor.FlowDiskBackupRun,
+		Memo:  fmt.Sprintf("disk backup policy %s, run: %s", policy.ID, runID),
+		Tasks: tasks,
+	}
+}
+
+// failRun 备份执行失败时结束执行记录
+func failRun(kt *kit.Kit, runID string, cause error) {
+	failReq := &dsbackup.RunUpdateReq{State: enumor.DiskBackupFailed, Reason: converter.ValToPtr(cause.Error()),
+		Finished: true}
+	if err := actcli.GetDataService().Global.DiskBackupPolicy.UpdateRun(freshKit(kt), runID, failReq); err != nil {
+		logs.Errorf("update disk backup run failed, err: %v, run: %s, rid: %s", err, runID, kt.Rid)
+	}
+}
+
+// freshKit 任务的kit会在任务执行超时后被取消，结束执行记录时使用不受任务超时影响的kit，保证执行记录能够被结束
+func freshKit(kt *kit.Kit) *kit.Kit {
+	newKit := converter.ValToPtr(*kt)
+	newKit.Ctx = context.WithValue(context.Background(), constant.RidKey, kt.Rid)
+	return newKit
+}
+
+// listTargetDisks 查询备份策略的目标硬盘，回收中的硬盘不做备份
+func listTargetDisks(kt *kit.Kit, policy *corebackup.DiskBackupPolicy) ([]*coredisk.BaseDisk, error) {
+	expr, err := tools.And(policy.Target.DiskFilter(policy.BkBizID),
+		tools.RuleNotEqual("recycle_status", enumor.RecycleStatus))
+	if err != nil {
+		return nil, err
+	}
+
+	disks := make([]*coredisk.BaseDisk, 0)
+	req := &core.ListReq{Filter: expr, Page: core.NewDefaultBasePage()}
+	for {
+		result, err := actcli.GetDataService().Global.ListDisk(kt, req)
+		if err != nil {
+			logs.Errorf("list disk backup target disks failed, err: %v, policy: %s, rid: %s", err, policy.ID,
+				kt.Rid)
+			return nil, err
+		}
+		disks = append(disks, result.Details...)
+
+		if uint(len(result.Details)) < req.Page.Limit {
+			break
+		}
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+
+	return disks, nil
+}
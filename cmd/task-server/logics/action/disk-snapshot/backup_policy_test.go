@@ -0,0 +1,204 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actiondisksnapshot
+
+import (
+	"testing"
+	"time"
+
+	coredisk "hcm/pkg/api/core/cloud/disk"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	corebackup "hcm/pkg/api/core/disk-backup"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestBuildBackupRunFlow(t *testing.T) {
+	policy := &corebackup.DiskBackupPolicy{ID: "policy-1", RetentionCount: 3}
+	disks := []*coredisk.BaseDisk{
+		{ID: "disk-1", Vendor: string(enumor.TCloud)},
+		{ID: "disk-2", Vendor: string(enumor.Aws)},
+	}
+	now := time.Unix(1700000000, 0)
+
+	req := buildBackupRunFlow(policy, "run-1", disks, now)
+	if err := req.Validate(); err != nil {
+		t.Fatalf("backup run flow should be valid, but got: %v", err)
+	}
+
+	if req.Name != enumor.FlowDiskBackupRun {
+		t.Errorf("expect flow name %s, but got: %s", enumor.FlowDiskBackupRun, req.Name)
+	}
+
+	if len(req.Tasks) != len(disks)+1 {
+		t.Fatalf("expect %d tasks, but got: %d", len(disks)+1, len(req.Tasks))
+	}
+
+	// 每块硬盘一个备份任务，备份任务之间没有依赖，可以并发执行
+	for idx, disk := range disks {
+		task := req.Tasks[idx]
+		opt, ok := task.Params.(*BackupDiskOption)
+		if !ok || task.ActionName != enumor.ActionBackupDisk {
+			t.Fatalf("task %s should backup disk, but got: %s", task.ActionID, task.ActionName)
+		}
+		if len(task.DependOn) != 0 {
+			t.Errorf("backup task of disk %s should not depend on other tasks", disk.ID)
+		}
+		if opt.DiskID != disk.ID || opt.Vendor != enumor.Vendor(disk.Vendor) || opt.RunID != "run-1" ||
+			opt.RetentionCount != policy.RetentionCount ||
+			opt.SnapshotName != corebackup.SnapshotName(policy.ID, disk.ID, now.Unix()) {
+			t.Errorf("backup task of disk %s has unexpected params: %+v", disk.ID, opt)
+		}
+	}
+
+	// 汇总任务依赖全部备份任务
+	finish := req.Tasks[len(disks)]
+	opt, ok := finish.Params.(*FinishDiskBackupRunOption)
+	if !ok || finish.ActionName != enumor.ActionFinishDiskBackupRun {
+		t.Fatalf("last task should finish run, but got: %s", finish.ActionName)
+	}
+	if opt.RunID != "run-1" || len(opt.DiskIDs) != len(disks) {
+		t.Errorf("finish task has unexpected params: %+v", opt)
+	}
+	if len(finish.DependOn) != len(disks) {
+		t.Errorf("finish task should depend on all %d backup tasks, but got: %v", len(disks), finish.DependOn)
+	}
+}
+
+func TestSelectExpiredSnapshots(t *testing.T) {
+	snapshot := func(id, status string) coresnapshot.DiskSnapshot {
+		return coresnapshot.DiskSnapshot{ID: id, Vendor: enumor.Aws, Status: status}
+	}
+
+	cases := []struct {
+		name      string
+		snapshots []coresnapshot.DiskSnapshot
+		retention uint
+		want      []string
+	}{
+		{
+			name: "prune oldest ready snapshots",
+			snapshots: []coresnapshot.DiskSnapshot{snapshot("s4", "completed"), snapshot("s3", "completed"),
+				snapshot("s2", "completed"), snapshot("s1", "completed")},
+			retention: 2,
+			want:      []string{"s2", "s1"},
+		},
+		{
+			name: "creating snapshot is not counted",
+			snapshots: []coresnapshot.DiskSnapshot{snapshot("s3", "pending"), snapshot("s2", "completed"),
+				snapshot("s1", "completed")},
+			retention: 2,
+			want:      []string{},
+		},
+		{
+			name: "failed snapshot is pruned",
+			snapshots: []coresnapshot.DiskSnapshot{snapshot("s3", "error"), snapshot("s2", "completed"),
+				snapshot("s1", "completed")},
+			retention: 2,
+			want:      []string{"s3"},
+		},
+		{
+			name:      "no snapshot",
+			retention: 1,
+			want:      []string{},
+		},
+	}
+
+	for _, c := range cases {
+		expired := selectExpiredSnapshots(c.snapshots, c.retention)
+		if len(expired) != len(c.want) {
+			t.Errorf("case %s: expect %d expired snapshots, but got: %d", c.name, len(c.want), len(expired))
+			continue
+		}
+		for idx, one := range expired {
+			if one.ID != c.want[idx] {
+				t.Errorf("case %s: expect expired snapshot %s, but got: %s", c.name, c.want[idx], one.ID)
+			}
+		}
+	}
+}
+
+func TestSummarizeBackupRun(t *testing.T) {
+	cases := []struct {
+		name        string
+		diskIDs     []string
+		results     map[string]*diskBackupResult
+		wantState   enumor.DiskBackupRunState
+		wantSuccess uint
+		wantFailed  int
+		wantPruned  uint
+	}{
+		{
+			name:      "no target disk",
+			wantState: enumor.DiskBackupSuccess,
+		},
+		{
+			name:    "all success",
+			diskIDs: []string{"disk-1", "disk-2"},
+			results: map[string]*diskBackupResult{
+				"disk-1": {Snapshotted: true, Pruned: 1},
+				"disk-2": {Snapshotted: true},
+			},
+			wantState:   enumor.DiskBackupSuccess,
+			wantSuccess: 2,
+			wantPruned:  1,
+		},
+		{
+			name:    "prune failed",
+			diskIDs: []string{"disk-1"},
+			results: map[string]*diskBackupResult{
+				"disk-1": {Snapshotted: true, Stage: corebackup.PruneStage, Reason: "delete failed"},
+			},
+			wantState:   enumor.DiskBackupPartialFailed,
+			wantSuccess: 1,
+			wantFailed:  1,
+		},
+		{
+			name:    "result of disk is missing",
+			diskIDs: []string{"disk-1", "disk-2"},
+			results: map[string]*diskBackupResult{
+				"disk-1": {Snapshotted: true},
+			},
+			wantState:   enumor.DiskBackupPartialFailed,
+			wantSuccess: 1,
+			wantFailed:  1,
+		},
+		{
+			name:    "all failed",
+			diskIDs: []string{"disk-1"},
+			results: map[string]*diskBackupResult{
+				"disk-1": {Stage: corebackup.SnapshotStage, Reason: "quota exceeded"},
+			},
+			wantState:  enumor.DiskBackupFailed,
+			wantFailed: 1,
+		},
+	}
+
+	for _, c := range cases {
+		req := summarizeBackupRun(c.diskIDs, c.results)
+		if err := req.Validate(); err != nil {
+			t.Errorf("case %s: update request should be valid, but got: %v", c.name, err)
+		}
+		if req.State != c.wantState || *req.SuccessCount != c.wantSuccess || len(req.FailedDetails) != c.wantFailed ||
+			*req.PrunedCount != c.wantPruned || *req.TargetCount != uint(len(c.diskIDs)) || !req.Finished {
+			t.Errorf("case %s: unexpected summary, state: %s, success: %d, failed: %d, pruned: %d", c.name,
+				req.State, *req.SuccessCount, len(req.FailedDetails), *req.PrunedCount)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package actiondisksnapshot ...
+package actiondisksnapshot
+
+import (
+	actcli "hcm/cmd/task-server/logics/action/cli"
+	hcproto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// CreateDiskSnapshotAction create disk snapshot action, it only submits snapshot creation, the following
+// WaitDiskSnapshotAction tasks wait for the snapshot to be ready, because snapshot creation may take much longer
+// than the task execution timeout. creation is idempotent by snapshot name, so the action can be retried.
+type CreateDiskSnapshotAction struct {
+}
+
+// CreateDiskSnapshotOption ...
+type CreateDiskSnapshotOption struct {
+	Vendor enumor.Vendor `json:"vendor" validate:"required"`
+	DiskID string        `json:"disk_id" validate:"required"`
+	// Name 快照名称，同一硬盘下同名快照只会创建一次
+	Name string  `json:"name" validate:"required"`
+	Memo *string `json:"memo" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt *CreateDiskSnapshotOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ParameterNew returns parameter of CreateDiskSnapshotAction
+func (act CreateDiskSnapshotAction) ParameterNew() (params interface{}) {
+	return new(CreateDiskSnapshotOption)
+}
+
+// Name ActionCreateDiskSnapshot
+func (act CreateDiskSnapshotAction) Name() enumor.ActionName {
+	return enumor.ActionCreateDiskSnapshot
+}
+
+// Run ...
+func (act CreateDiskSnapshotAction) Run(kt run.ExecuteKit, params any) (any, error) {
+	opt, ok := params.(*CreateDiskSnapshotOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	createReq := &hcproto.CreateReq{DiskID: opt.DiskID, Name: opt.Name, Memo: opt.Memo}
+	result, err := createSnapshot(kt.Kit(), opt.Vendor, createReq)
+	if err != nil {
+		logs.Errorf("create disk snapshot failed, err: %v, vendor: %s, opt: %+v, rid: %s",
+			err, opt.Vendor, opt, kt.Kit().Rid)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Rollback 快照创建按名称保证幂等，重试前无需回滚
+func (act CreateDiskSnapshotAction) Rollback(kt run.ExecuteKit, params any) error {
+	return nil
+}
+
+// createSnapshot 调用对应云厂商的接口创建硬盘快照
+func createSnapshot(kt *kit.Kit, vendor enumor.Vendor, req *hcproto.CreateReq) (*hcproto.CreateResult, error) {
+	cli := actcli.GetHCService()
+	switch vendor {
+	case enumor.TCloud:
+		return cli.TCloud.DiskSnapshot.CreateDiskSnapshot(kt, req)
+	case enumor.Aws:
+		return cli.Aws.DiskSnapshot.CreateDiskSnapshot(kt, req)
+	case enumor.HuaWei:
+		return cli.HuaWei.DiskSnapshot.CreateDiskSnapshot(kt, req)
+	case enumor.Gcp:
+		return cli.Gcp.DiskSnapshot.CreateDiskSnapshot(kt, req)
+	case enumor.Azure:
+		return cli.Azure.DiskSnapshot.CreateDiskSnapshot(kt, req)
+	default:
+		return nil, errf.Newf(errf.Unknown, "disk: %s vendor: %s not support", req.DiskID, vendor)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actiondisksnapshot
+
+import (
+	"testing"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestCreateDiskSnapshotOptionValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		opt     CreateDiskSnapshotOption
+		wantErr bool
+	}{
+		{name: "valid", opt: CreateDiskSnapshotOption{Vendor: enumor.TCloud, DiskID: "disk-1", Name: "snapshot-1"}},
+		{name: "without name", opt: CreateDiskSnapshotOption{Vendor: enumor.TCloud, DiskID: "disk-1"}, wantErr: true},
+		{name: "without disk", opt: CreateDiskSnapshotOption{Vendor: enumor.TCloud, Name: "snapshot-1"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		err := c.opt.Validate()
+		if (err != nil) != c.wantErr {
+			t.Errorf("case %s: expect error %v, but got: %v", c.name, c.wantErr, err)
+		}
+	}
+}
+
+func TestCreateDiskSnapshotActionRetryable(t *testing.T) {
+	// 设置了重试的任务要求 action 支持回滚
+	var act action.Action = CreateDiskSnapshotAction{}
+	rollback, ok := act.(action.RollbackAction)
+	if !ok {
+		t.Fatalf("create disk snapshot action should implement rollback action")
+	}
+
+	if err := rollback.Rollback(nil, new(CreateDiskSnapshotOption)); err != nil {
+		t.Errorf("rollback create disk snapshot action should not fail, but got: %v", err)
+	}
+}
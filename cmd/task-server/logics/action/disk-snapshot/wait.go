@@ -0,0 +1,224 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actiondisksnapshot
+
+import (
+	"fmt"
+
+	"hcm/pkg/adaptor/poller"
+	"hcm/pkg/adaptor/types"
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	hcproto "hcm/pkg/api/hc-service/disk-snapshot"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/criteria/validator"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/counter"
+)
+
+const (
+	// diskSnapshotReadyKey 快照均已创建完成时写入任务流共享数据，后续的等待任务会被跳过
+	diskSnapshotReadyKey = "disk_snapshot_ready"
+	// waitDiskSnapshotTaskCount 快照创建任务流中等待任务的数量，单个等待任务轮询80s，总共最多等待2小时
+	waitDiskSnapshotTaskCount = 90
+)
+
+// BuildCreateDiskSnapshotTasks 构建创建快照任务流的任务，先为每块硬盘提交快照创建，再由一串等待任务依次轮询快照状态，
+// 快照均创建完成时任务流执行成功，有快照创建失败或者等待超时时任务流执行失败
+func BuildCreateDiskSnapshotTasks(vendor enumor.Vendor, snapshots []SnapshotTarget, memo *string) []ts.CustomFlowTask {
+	nextID := counter.NewNumStringCounter(1, 10)
+	tasks := make([]ts.CustomFlowTask, 0, len(snapshots)+waitDiskSnapshotTaskCount)
+	dependOn := make([]action.ActIDType, 0, len(snapshots))
+	for _, one := range snapshots {
+		actionID := action.ActIDType(nextID())
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   actionID,
+			ActionName: enumor.ActionCreateDiskSnapshot,
+			Params:     &CreateDiskSnapshotOption{Vendor: vendor, DiskID: one.DiskID, Name: one.Name, Memo: memo},
+			Retry:      tableasync.NewRetryWithPolicy(3, 1000, 5000),
+		})
+		dependOn = append(dependOn, actionID)
+	}
+
+	skipWhenReady := &tableasync.Condition{
+		Rules: []tableasync.ConditionRule{{
+			Source:   tableasync.ConditionSourceShareData,
+			Key:      diskSnapshotReadyKey,
+			Operator: tableasync.ConditionNotExists,
+		}},
+	}
+	for i := 0; i < waitDiskSnapshotTaskCount; i++ {
+		actionID := action.ActIDType(nextID())
+		tasks = append(tasks, ts.CustomFlowTask{
+			ActionID:   actionID,
+			ActionName: enumor.ActionWaitDiskSnapshot,
+			Params: &WaitDiskSnapshotOption{
+				Vendor:    vendor,
+				Snapshots: snapshots,
+				Last:      i == waitDiskSnapshotTaskCount-1,
+			},
+			DependOn:  dependOn,
+			Condition: skipWhenReady,
+		})
+		dependOn = []action.ActIDType{actionID}
+	}
+
+	return tasks
+}
+
+// WaitDiskSnapshotAction 轮询等待硬盘快照创建完成，快照创建耗时远超任务执行超时时间，由多个等待任务依次轮询，
+// 单个等待任务轮询超时后执行成功，交由下一个等待任务继续轮询，最后一个等待任务超时时执行失败
+type WaitDiskSnapshotAction struct {
+}
+
+// WaitDiskSnapshotOption ...
+type WaitDiskSnapshotOption struct {
+	Vendor    enumor.Vendor    `json:"vendor" validate:"required"`
+	Snapshots []SnapshotTarget `json:"snapshots" validate:"required,min=1,dive"`
+	// Last 是否为最后一个等待任务，最后一个等待任务轮询超时时快照仍未创建完成则执行失败
+	Last bool `json:"last" validate:"omitempty"`
+}
+
+// SnapshotTarget 待创建的快照，同一硬盘下同名快照只会创建一次
+type SnapshotTarget struct {
+	DiskID string `json:"disk_id" validate:"required"`
+	Name   string `json:"name" validate:"required"`
+}
+
+// Validate ...
+func (opt *WaitDiskSnapshotOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// ParameterNew returns parameter of WaitDiskSnapshotAction
+func (act WaitDiskSnapshotAction) ParameterNew() (params interface{}) {
+	return new(WaitDiskSnapshotOption)
+}
+
+// Name ActionWaitDiskSnapshot
+func (act WaitDiskSnapshotAction) Name() enumor.ActionName {
+	return enumor.ActionWaitDiskSnapshot
+}
+
+// Run ...
+func (act WaitDiskSnapshotAction) Run(kt run.ExecuteKit, params any) (any, error) {
+	opt, ok := params.(*WaitDiskSnapshotOption)
+	if !ok {
+		return nil, errf.New(errf.InvalidParameter, "params type mismatch")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	diskIDs := make(map[string]string, len(opt.Snapshots))
+	names := make([]*string, 0, len(opt.Snapshots))
+	for _, one := range opt.Snapshots {
+		diskIDs[one.Name] = one.DiskID
+		names = append(names, converter.ValToPtr(one.Name))
+	}
+
+	respPoller := poller.Poller[enumor.Vendor, []coresnapshot.DiskSnapshot, poller.BaseDoneResult]{
+		Handler: &diskSnapshotPollingHandler{diskIDs: diskIDs},
+	}
+	result, err := respPoller.PollUntilDone(opt.Vendor, kt.Kit(), names, types.NewWaitDiskSnapshotPollerOption())
+	if err != nil {
+		logs.Errorf("wait disk snapshot failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Kit().Rid)
+		if opt.Last {
+			return nil, err
+		}
+		// 查询快照状态失败时交由下一个等待任务继续轮询
+		return nil, nil
+	}
+
+	if len(result.FailedCloudIDs) != 0 {
+		return nil, fmt.Errorf("disk snapshot %v create failed, %s", result.FailedCloudIDs, result.FailedMessage)
+	}
+
+	if len(result.UnknownCloudIDs) == 0 {
+		if err = kt.ShareData().Set(kt.Kit(), diskSnapshotReadyKey, "true"); err != nil {
+			logs.Errorf("set disk snapshot ready share data failed, err: %v, rid: %s", err, kt.Kit().Rid)
+			return nil, err
+		}
+		return result, nil
+	}
+
+	if opt.Last {
+		return nil, fmt.Errorf("wait disk snapshot %v timeout", result.UnknownCloudIDs)
+	}
+
+	return result, nil
+}
+
+// diskSnapshotPollingHandler 轮询快照状态，ids 为快照名称
+type diskSnapshotPollingHandler struct {
+	// diskIDs 快照名称与源硬盘ID的映射
+	diskIDs map[string]string
+}
+
+// Done 有快照创建失败，或者快照均已创建完成时结束轮询
+func (h *diskSnapshotPollingHandler) Done(snapshots []coresnapshot.DiskSnapshot) (bool, *poller.BaseDoneResult) {
+	result := new(poller.BaseDoneResult)
+	for _, one := range snapshots {
+		switch {
+		case one.IsFailed():
+			result.FailedCloudIDs = append(result.FailedCloudIDs, one.Name)
+			result.FailedMessage = fmt.Sprintf("snapshot %s(%s) of disk %s is %s", one.Name, one.ID, one.DiskID,
+				one.Status)
+		case one.IsReady():
+			result.SuccessCloudIDs = append(result.SuccessCloudIDs, one.Name)
+		default:
+			result.UnknownCloudIDs = append(result.UnknownCloudIDs, one.Name)
+		}
+	}
+
+	return len(result.FailedCloudIDs) != 0 || len(result.UnknownCloudIDs) == 0, result
+}
+
+// Poll 快照创建按名称保证幂等，重新调用创建接口会同步并返回已创建快照的最新状态
+func (h *diskSnapshotPollingHandler) Poll(vendor enumor.Vendor, kt *kit.Kit, names []*string) (
+	[]coresnapshot.DiskSnapshot, error) {
+
+	snapshots := make([]coresnapshot.DiskSnapshot, 0, len(names))
+	for _, name := range names {
+		diskID := h.diskIDs[converter.PtrToVal(name)]
+		result, err := createSnapshot(kt, vendor, &hcproto.CreateReq{DiskID: diskID, Name: converter.PtrToVal(name)})
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, coresnapshot.DiskSnapshot{
+			ID:      result.ID,
+			Vendor:  vendor,
+			CloudID: result.CloudID,
+			Name:    converter.PtrToVal(name),
+			DiskID:  diskID,
+			Status:  result.Status,
+		})
+	}
+
+	return snapshots, nil
+}
@@ -0,0 +1,122 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package actiondisksnapshot
+
+import (
+	"testing"
+
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+)
+
+func TestBuildCreateDiskSnapshotTasks(t *testing.T) {
+	targets := []SnapshotTarget{{DiskID: "disk-1", Name: "snapshot-1"}, {DiskID: "disk-2", Name: "snapshot-2"}}
+	tasks := BuildCreateDiskSnapshotTasks(enumor.TCloud, targets, nil)
+	if len(tasks) != len(targets)+waitDiskSnapshotTaskCount {
+		t.Fatalf("expect %d tasks, but got: %d", len(targets)+waitDiskSnapshotTaskCount, len(tasks))
+	}
+
+	for i, task := range tasks[:len(targets)] {
+		if task.ActionName != enumor.ActionCreateDiskSnapshot || len(task.DependOn) != 0 || task.Retry == nil {
+			t.Errorf("task %d: expect create task without dependency and with retry, but got: %+v", i, task)
+		}
+	}
+
+	waits := tasks[len(targets):]
+	for i, task := range waits {
+		if task.ActionName != enumor.ActionWaitDiskSnapshot {
+			t.Errorf("wait task %d: expect action %s, but got: %s", i, enumor.ActionWaitDiskSnapshot, task.ActionName)
+			continue
+		}
+
+		// 第一个等待任务依赖所有创建任务，之后的等待任务依次串行
+		wantDepend := 1
+		if i == 0 {
+			wantDepend = len(targets)
+		} else if task.DependOn[0] != waits[i-1].ActionID {
+			t.Errorf("wait task %d: expect depend on %s, but got: %v", i, waits[i-1].ActionID, task.DependOn)
+		}
+		if len(task.DependOn) != wantDepend {
+			t.Errorf("wait task %d: expect %d dependencies, but got: %v", i, wantDepend, task.DependOn)
+		}
+
+		opt := task.Params.(*WaitDiskSnapshotOption)
+		if err := opt.Validate(); err != nil {
+			t.Errorf("wait task %d: expect valid option, but got: %v", i, err)
+		}
+		if opt.Last != (i == len(waits)-1) {
+			t.Errorf("wait task %d: expect last %v, but got: %v", i, i == len(waits)-1, opt.Last)
+		}
+
+		// 快照创建完成后写入共享数据，剩余的等待任务被跳过
+		env := tableasync.ConditionEnv{ShareData: tableasync.NewShareData(map[string]string{
+			diskSnapshotReadyKey: "true"})}
+		run, err := task.Condition.Evaluate(env)
+		if err != nil || run {
+			t.Errorf("wait task %d: expect skipped when snapshot ready, but got: %v, err: %v", i, run, err)
+		}
+	}
+}
+
+func TestDiskSnapshotPollingHandlerDone(t *testing.T) {
+	snapshot := func(name, status string) coresnapshot.DiskSnapshot {
+		return coresnapshot.DiskSnapshot{Vendor: enumor.Aws, Name: name, Status: status}
+	}
+
+	cases := []struct {
+		name        string
+		snapshots   []coresnapshot.DiskSnapshot
+		wantDone    bool
+		wantFailed  int
+		wantUnknown int
+	}{
+		{
+			name:      "all ready",
+			snapshots: []coresnapshot.DiskSnapshot{snapshot("s1", "completed"), snapshot("s2", "completed")},
+			wantDone:  true,
+		},
+		{
+			name:        "creating",
+			snapshots:   []coresnapshot.DiskSnapshot{snapshot("s1", "completed"), snapshot("s2", "pending")},
+			wantUnknown: 1,
+		},
+		{
+			name:        "failed",
+			snapshots:   []coresnapshot.DiskSnapshot{snapshot("s1", "error"), snapshot("s2", "pending")},
+			wantDone:    true,
+			wantFailed:  1,
+			wantUnknown: 1,
+		},
+	}
+
+	handler := new(diskSnapshotPollingHandler)
+	for _, c := range cases {
+		done, result := handler.Done(c.snapshots)
+		if done != c.wantDone {
+			t.Errorf("case %s: expect done %v, but got: %v", c.name, c.wantDone, done)
+		}
+
+		if len(result.FailedCloudIDs) != c.wantFailed || len(result.UnknownCloudIDs) != c.wantUnknown {
+			t.Errorf("case %s: expect %d failed and %d unknown, but got: %+v", c.name, c.wantFailed,
+				c.wantUnknown, result)
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 	actionrootsummary "hcm/cmd/task-server/logics/action/bill/rootsummary"
 	actcli "hcm/cmd/task-server/logics/action/cli"
 	actioncvm "hcm/cmd/task-server/logics/action/cvm"
+	actiondisksnapshot "hcm/cmd/task-server/logics/action/disk-snapshot"
 	actioneip "hcm/cmd/task-server/logics/action/eip"
 	actionfirewall "hcm/cmd/task-server/logics/action/firewall"
 	actionlb "hcm/cmd/task-server/logics/action/load-balancer"
@@ -61,6 +62,12 @@ func register() {
 	action.RegisterAction(actionsg.DeleteSgAction{})
 	action.RegisterAction(actionsg.CreateHuaweiSGRuleAction{})
 	action.RegisterAction(actioneip.DeleteEIPAction{})
+	action.RegisterAction(actiondisksnapshot.CreateDiskSnapshotAction{})
+	action.RegisterAction(actiondisksnapshot.WaitDiskSnapshotAction{})
+	action.RegisterAction(actiondisksnapshot.RunDiskBackupPolicyAction{})
+	action.RegisterAction(actiondisksnapshot.BackupDiskAction{})
+	action.RegisterAction(actiondisksnapshot.FinishDiskBackupRunAction{})
+	action.RegisterTpl(actionflow.FlowDiskBackupPolicyTpl)
 
 	action.RegisterAction(actionlb.AddTargetToGroupAction{})
 	action.RegisterAction(actionflow.LoadBalancerOperateWatchAction{})
@@ -44,3 +44,15 @@ var FlowLoadBalancerOperateWatchTpl = action.FlowTemplate{
 		},
 	},
 }
+
+// FlowDiskBackupPolicyTpl define flow disk backup policy template, used by scheduled flow and manual run of policy.
+var FlowDiskBackupPolicyTpl = action.FlowTemplate{
+	Name:      enumor.FlowDiskBackupPolicy,
+	ShareData: tableasync.NewShareData(nil),
+	Tasks: []action.TaskTemplate{
+		{
+			ActionID:   "1",
+			ActionName: enumor.ActionRunDiskBackupPolicy,
+		},
+	},
+}
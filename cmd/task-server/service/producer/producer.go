@@ -43,6 +43,12 @@ func Init(cap *capability.Capability) {
 	h.Add("CreateCustomFlow", "POST", "/custom_flows/create", svc.CreateCustomFlow)
 	h.Add("CloneFlow", "POST", "/flows/{flow_id}/clone", svc.CloneFlow)
 
+	h.Add("RegisterScheduledFlow", "POST", "/scheduled_flows/register", svc.RegisterScheduledFlow)
+	h.Add("ListScheduledFlow", "POST", "/scheduled_flows/list", svc.ListScheduledFlow)
+	h.Add("PauseScheduledFlow", "PATCH", "/scheduled_flows/{id}/pause", svc.PauseScheduledFlow)
+	h.Add("ResumeScheduledFlow", "PATCH", "/scheduled_flows/{id}/resume", svc.ResumeScheduledFlow)
+	h.Add("DeleteScheduledFlow", "DELETE", "/scheduled_flows/{id}", svc.DeleteScheduledFlow)
+
 	h.Load(cap.WebService)
 }
 
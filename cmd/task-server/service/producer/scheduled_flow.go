@@ -0,0 +1,154 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"hcm/pkg/api/core"
+	coreasync "hcm/pkg/api/core/async"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/async/producer"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+)
+
+// RegisterScheduledFlow register scheduled flow, update it if scheduled flow with same name already exists.
+func (p service) RegisterScheduledFlow(cts *rest.Contexts) (interface{}, error) {
+	// 请求体使用的是 taskserver.RegisterScheduledFlowReq，解析使用 producer.RegisterScheduledFlowOption，
+	// 通过http请求自动序列化 task.Params。
+	opt := new(producer.RegisterScheduledFlowOption)
+	if err := cts.DecodeInto(opt); err != nil {
+		return nil, err
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	id, err := p.pro.RegisterScheduledFlow(cts.Kit, opt)
+	if err != nil {
+		logs.Errorf("register scheduled flow failed, err: %v, opt: %+v, rid: %s", err, opt, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return &core.CreateResult{ID: id}, nil
+}
+
+// ListScheduledFlow list scheduled flow.
+func (p service) ListScheduledFlow(cts *rest.Contexts) (interface{}, error) {
+	req := new(core.ListReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if req.Page.Count {
+		return nil, errf.New(errf.InvalidParameter, "scheduled flow list not support count")
+	}
+
+	input := &backend.ListInput{
+		Fields: req.Fields,
+		Filter: req.Filter,
+		Page:   req.Page,
+	}
+	flows, err := p.pro.ListScheduledFlow(cts.Kit, input)
+	if err != nil {
+		logs.Errorf("list scheduled flow failed, err: %v, rid: %s", err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	details := make([]coreasync.AsyncScheduledFlow, 0, len(flows))
+	for _, one := range flows {
+		details = append(details, convCoreScheduledFlow(one))
+	}
+
+	return &ts.ListScheduledFlowResult{Details: details}, nil
+}
+
+func convCoreScheduledFlow(one model.ScheduledFlow) coreasync.AsyncScheduledFlow {
+	return coreasync.AsyncScheduledFlow{
+		ID:              one.ID,
+		Name:            one.Name,
+		FlowName:        one.FlowName,
+		Spec:            one.Spec,
+		Tasks:           one.Tasks,
+		Memo:            one.Memo,
+		State:           one.State,
+		NextTriggerAt:   one.NextTriggerAt,
+		LastTriggeredAt: one.LastTriggeredAt,
+		LastFlowID:      one.LastFlowID,
+		Revision: core.Revision{
+			Creator:   one.Creator,
+			Reviser:   one.Reviser,
+			CreatedAt: one.CreatedAt,
+			UpdatedAt: one.UpdatedAt,
+		},
+	}
+}
+
+// PauseScheduledFlow pause scheduled flow.
+func (p service) PauseScheduledFlow(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := p.pro.PauseScheduledFlow(cts.Kit, id); err != nil {
+		logs.Errorf("pause scheduled flow(%s) failed, err: %v, rid: %s", id, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ResumeScheduledFlow resume scheduled flow.
+func (p service) ResumeScheduledFlow(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := p.pro.ResumeScheduledFlow(cts.Kit, id); err != nil {
+		logs.Errorf("resume scheduled flow(%s) failed, err: %v, rid: %s", id, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// DeleteScheduledFlow delete scheduled flow.
+func (p service) DeleteScheduledFlow(cts *rest.Contexts) (interface{}, error) {
+	id := cts.PathParameter("id").String()
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := p.pro.DeleteScheduledFlow(cts.Kit, id); err != nil {
+		logs.Errorf("delete scheduled flow(%s) failed, err: %v, rid: %s", id, err, cts.Kit.Rid)
+		return nil, err
+	}
+
+	return nil, nil
+}
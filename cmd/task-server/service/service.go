@@ -49,9 +49,11 @@ import (
 	restcli "hcm/pkg/rest/client"
 	"hcm/pkg/runtime/shutdown"
 	"hcm/pkg/serviced"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
 	"hcm/pkg/tools/ssl"
 
 	"github.com/emicklei/go-restful/v3"
+	etcd3 "go.etcd.io/etcd/client/v3"
 )
 
 // Service do all the task server's work
@@ -106,14 +108,20 @@ func NewService(sd serviced.ServiceDiscover, shutdownWaitTimeSec int) (*Service,
 }
 
 func createAndStartAsync(sd serviced.ServiceDiscover, dao dao.Set, shutdownWaitTimeSec int) (async.Async, error) {
+	cfg := cc.TaskServer().Async
+
 	// 创建async框架使用的backend
-	bd, err := backend.Factory(enumor.BackendMysql, dao)
+	bd, err := newAsyncBackend(cfg.Backend, dao)
+	if err != nil {
+		return nil, err
+	}
+
+	notifier, err := newAsyncFlowNotifier(cfg.WatchDog.FlowNotify)
 	if err != nil {
 		return nil, err
 	}
 
 	leader := leader.NewLeader(sd)
-	cfg := cc.TaskServer().Async
 	opt := &async.Option{
 		Register: metrics.Register(),
 		ConsumerOption: &consumer.Option{
@@ -129,10 +137,14 @@ func createAndStartAsync(sd serviced.ServiceDiscover, dao dao.Set, shutdownWaitT
 				WatchIntervalSec: cfg.Dispatcher.WatchIntervalSec,
 			},
 			WatchDog: &consumer.WatchDogOption{
-				WatchIntervalSec:    cfg.WatchDog.WatchIntervalSec,
-				TaskRunTimeoutSec:   cfg.WatchDog.TaskTimeoutSec,
-				ShutdownWaitTimeSec: uint(shutdownWaitTimeSec),
+				WatchIntervalSec:     cfg.WatchDog.WatchIntervalSec,
+				TaskRunTimeoutSec:    cfg.WatchDog.TaskTimeoutSec,
+				ShutdownWaitTimeSec:  uint(shutdownWaitTimeSec),
+				TaskLogRetentionDays: cfg.WatchDog.TaskLogRetentionDays,
+				FlowRetentionDays:    cfg.WatchDog.FlowRetentionDays,
+				Notifier:             notifier,
 			},
+			Fairness: newAsyncFairnessOption(cfg.Fairness),
 		},
 	}
 	async, err := async.NewAsync(bd, leader, opt)
@@ -158,6 +170,67 @@ func createAndStartAsync(sd serviced.ServiceDiscover, dao dao.Set, shutdownWaitT
 	return async, nil
 }
 
+// newAsyncFairnessOption 根据配置创建任务流公平派发配置
+func newAsyncFairnessOption(cfg cc.AsyncFairness) *consumer.FairnessOption {
+	keys := make(map[string]consumer.FairnessKeyOption, len(cfg.Keys))
+	for key, one := range cfg.Keys {
+		keys[key] = consumer.FairnessKeyOption{Weight: one.Weight, Concurrency: one.Concurrency}
+	}
+
+	return &consumer.FairnessOption{
+		DefaultWeight:      cfg.DefaultWeight,
+		DefaultConcurrency: cfg.DefaultConcurrency,
+		Keys:               keys,
+		DispatchLimit:      cfg.DispatchLimit,
+	}
+}
+
+// newAsyncFlowNotifier 根据配置创建异步任务流告警通知，未开启时返回nil
+func newAsyncFlowNotifier(cfg cc.FlowNotify) (consumer.FlowNotifier, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+
+	cmsiCli, err := cmsi.NewClient(&cfg.Cmsi, metrics.Register())
+	if err != nil {
+		logs.Errorf("failed to create cmsi client for async flow notify, err: %v", err)
+		return nil, err
+	}
+
+	return consumer.NewCmsiFlowNotifier(cmsiCli, cfg.Receivers), nil
+}
+
+// newAsyncBackend 根据配置创建异步任务框架的存储后端
+func newAsyncBackend(cfg cc.AsyncBackend, dao dao.Set) (backend.Backend, error) {
+	typ := cfg.GetType()
+	switch typ {
+	case enumor.BackendMysql:
+		return backend.Factory(typ, dao)
+
+	case enumor.BackendEtcd:
+		etcdCfg := cfg.Etcd
+		// 未单独配置etcd时，复用服务发现的etcd
+		if len(etcdCfg.Endpoints) == 0 {
+			etcdCfg = cc.TaskServer().Service.Etcd
+		}
+
+		etcdOpt, err := etcdCfg.ToConfig()
+		if err != nil {
+			return nil, fmt.Errorf("get async backend etcd config failed, err: %v", err)
+		}
+
+		cli, err := etcd3.New(etcdOpt)
+		if err != nil {
+			return nil, fmt.Errorf("new async backend etcd client failed, err: %v", err)
+		}
+
+		return backend.Factory(typ, cli)
+
+	default:
+		return nil, fmt.Errorf("unsupported async backend type: %s", typ)
+	}
+}
+
 // ListenAndServeRest listen and serve the restful server
 func (s *Service) ListenAndServeRest() error {
 	root := http.NewServeMux()
@@ -29,6 +29,7 @@ import (
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/logs"
 	"hcm/pkg/rest"
+	"hcm/pkg/tools/times"
 )
 
 // ListFlow list flow.
@@ -66,14 +67,17 @@ func (svc *service) ListFlow(cts *rest.Contexts) (interface{}, error) {
 }
 
 func convCoreFlow(one tableasync.AsyncFlowTable) coreasync.AsyncFlow {
-	return coreasync.AsyncFlow{
-		ID:        one.ID,
-		Name:      one.Name,
-		State:     one.State,
-		Reason:    one.Reason,
-		ShareData: one.ShareData,
-		Memo:      one.Memo,
-		Worker:    one.Worker,
+	flow := coreasync.AsyncFlow{
+		ID:                  one.ID,
+		Name:                one.Name,
+		State:               one.State,
+		Reason:              one.Reason,
+		ShareData:           one.ShareData,
+		Memo:                one.Memo,
+		Worker:              one.Worker,
+		Priority:            one.Priority,
+		FairnessKey:         one.FairnessKey,
+		CompensateOnFailure: one.CompensateOnFailure,
 		Revision: core.Revision{
 			Creator:   one.Creator,
 			Reviser:   one.Reviser,
@@ -81,6 +85,16 @@ func convCoreFlow(one tableasync.AsyncFlowTable) coreasync.AsyncFlow {
 			UpdatedAt: one.UpdatedAt.String(),
 		},
 	}
+
+	if one.DeadlineAt != nil && !one.DeadlineAt.IsZero() {
+		flow.DeadlineAt = times.ConvStdTimeFormat(*one.DeadlineAt)
+	}
+
+	if one.SLAAt != nil && !one.SLAAt.IsZero() {
+		flow.SLAAt = times.ConvStdTimeFormat(*one.SLAAt)
+	}
+
+	return flow
 }
 
 // GetFlow get flow.
@@ -67,17 +67,20 @@ func (svc *service) ListTask(cts *rest.Contexts) (interface{}, error) {
 
 func convCoreTask(one tableasync.AsyncFlowTaskTable) coreasync.AsyncFlowTask {
 	return coreasync.AsyncFlowTask{
-		ID:         one.ID,
-		FlowID:     one.FlowID,
-		FlowName:   one.FlowName,
-		ActionID:   one.ActionID,
-		ActionName: one.ActionName,
-		Params:     one.Params,
-		Result:     one.Result,
-		Retry:      one.Retry,
-		DependOn:   one.DependOn,
-		State:      one.State,
-		Reason:     one.Reason,
+		ID:               one.ID,
+		FlowID:           one.FlowID,
+		FlowName:         one.FlowName,
+		ActionID:         one.ActionID,
+		ActionName:       one.ActionName,
+		Kind:             one.Kind,
+		Params:           one.Params,
+		Result:           one.Result,
+		Retry:            one.Retry,
+		DependOn:         one.DependOn,
+		Condition:        one.ExecCondition,
+		CompensateAction: one.CompensateAction,
+		State:            one.State,
+		Reason:           one.Reason,
 		Revision: core.Revision{
 			Creator:   one.Creator,
 			Reviser:   one.Reviser,
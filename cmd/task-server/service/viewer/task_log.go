@@ -0,0 +1,157 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package viewer
+
+import (
+	"hcm/pkg/api/core"
+	coreasync "hcm/pkg/api/core/async"
+	ts "hcm/pkg/api/task-server"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/logs"
+	"hcm/pkg/rest"
+	"hcm/pkg/runtime/filter"
+)
+
+// ListTaskLog list task log.
+func (svc *service) ListTaskLog(cts *rest.Contexts) (interface{}, error) {
+	taskID := cts.PathParameter("task_id").String()
+	if len(taskID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "task_id is required")
+	}
+
+	req := new(ts.ListTaskLogReq)
+	if err := cts.DecodeInto(req); err != nil {
+		return nil, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	rules := []*filter.AtomRule{tools.RuleEqual("task_id", taskID)}
+	if req.Attempt != 0 {
+		rules = append(rules, tools.RuleEqual("attempt", req.Attempt))
+	}
+	if len(req.Level) != 0 {
+		rules = append(rules, tools.RuleEqual("level", req.Level))
+	}
+
+	// 日志默认按写入顺序返回
+	if len(req.Page.Sort) == 0 {
+		req.Page.Sort = "id"
+		req.Page.Order = core.Ascending
+	}
+
+	input := &backend.ListInput{
+		Filter: tools.ExpressionAnd(rules...),
+		Page:   req.Page,
+	}
+	taskLogs, err := svc.pro.ListTaskLog(cts.Kit, input)
+	if err != nil {
+		logs.Errorf("list task log failed, err: %v, taskID: %s, rid: %s", err, taskID, cts.Kit.Rid)
+		return nil, err
+	}
+
+	details := make([]coreasync.AsyncFlowTaskLog, 0, len(taskLogs))
+	for _, one := range taskLogs {
+		details = append(details, convCoreTaskLog(one))
+	}
+
+	return &ts.ListTaskLogResult{Details: details}, nil
+}
+
+func convCoreTaskLog(one model.TaskLog) coreasync.AsyncFlowTaskLog {
+	return coreasync.AsyncFlowTaskLog{
+		ID:        one.ID,
+		FlowID:    one.FlowID,
+		TaskID:    one.TaskID,
+		Attempt:   one.Attempt,
+		Kind:      one.Kind,
+		Level:     one.Level,
+		Message:   one.Message,
+		CreatedAt: one.CreatedAt,
+	}
+}
+
+// ListTaskAttempt list task attempt history, include each attempt's error.
+func (svc *service) ListTaskAttempt(cts *rest.Contexts) (interface{}, error) {
+	taskID := cts.PathParameter("task_id").String()
+	if len(taskID) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "task_id is required")
+	}
+
+	input := &backend.ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("task_id", taskID),
+			tools.RuleEqual("kind", enumor.TaskLogKindAttempt),
+		),
+		Page: &core.BasePage{
+			Start: 0,
+			Limit: core.DefaultMaxPageLimit,
+			Sort:  "attempt",
+			Order: core.Ascending,
+		},
+	}
+
+	details := make([]coreasync.AsyncFlowTaskAttempt, 0)
+	for {
+		taskLogs, err := svc.pro.ListTaskLog(cts.Kit, input)
+		if err != nil {
+			logs.Errorf("list task attempt failed, err: %v, taskID: %s, rid: %s", err, taskID, cts.Kit.Rid)
+			return nil, err
+		}
+
+		for _, one := range taskLogs {
+			details = append(details, convCoreTaskAttempt(one))
+		}
+
+		if uint(len(taskLogs)) < input.Page.Limit {
+			break
+		}
+		input.Page.Start += uint32(input.Page.Limit)
+	}
+
+	return &ts.ListTaskAttemptResult{Details: details}, nil
+}
+
+// convCoreTaskAttempt 执行记录的日志级别对应执行结果：info为成功，warn为被取消，error为失败
+func convCoreTaskAttempt(one model.TaskLog) coreasync.AsyncFlowTaskAttempt {
+	attempt := coreasync.AsyncFlowTaskAttempt{
+		Attempt:    one.Attempt,
+		FinishedAt: one.CreatedAt,
+	}
+
+	switch one.Level {
+	case enumor.TaskLogInfo:
+		attempt.State = enumor.TaskSuccess
+	case enumor.TaskLogWarn:
+		attempt.State = enumor.TaskCancel
+		attempt.Error = one.Message
+	default:
+		attempt.State = enumor.TaskFailed
+		attempt.Error = one.Message
+	}
+
+	return attempt
+}
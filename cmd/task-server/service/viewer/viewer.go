@@ -22,6 +22,7 @@ package viewer
 
 import (
 	"hcm/cmd/task-server/service/capability"
+	"hcm/pkg/async/producer"
 	"hcm/pkg/client"
 	"hcm/pkg/dal/dao"
 	"hcm/pkg/rest"
@@ -32,6 +33,7 @@ func Init(cap *capability.Capability) {
 	svc := &service{
 		cs:  cap.ApiClient,
 		dao: cap.Dao,
+		pro: cap.Async.GetProducer(),
 	}
 
 	h := rest.NewHandler()
@@ -40,6 +42,8 @@ func Init(cap *capability.Capability) {
 	h.Add("GetFlow", "GET", "/flows/{id}", svc.GetFlow)
 	h.Add("ListTask", "POST", "/tasks/list", svc.ListTask)
 	h.Add("GetTask", "GET", "/tasks/{id}", svc.GetTask)
+	h.Add("ListTaskLog", "POST", "/tasks/{task_id}/logs/list", svc.ListTaskLog)
+	h.Add("ListTaskAttempt", "GET", "/tasks/{task_id}/attempts", svc.ListTaskAttempt)
 
 	h.Load(cap.WebService)
 }
@@ -47,4 +51,5 @@ func Init(cap *capability.Capability) {
 type service struct {
 	cs  *client.ClientSet
 	dao dao.Set
+	pro producer.Producer
 }
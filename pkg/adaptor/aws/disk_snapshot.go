@@ -0,0 +1,146 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"fmt"
+
+	snapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// CreateDiskSnapshot 提交硬盘快照创建请求，返回创建中的快照ID，快照创建耗时较长，不等待创建完成
+// reference: https://docs.amazonaws.cn/AWSEC2/latest/APIReference/API_CreateSnapshot.html
+func (a *Aws) CreateDiskSnapshot(kt *kit.Kit, opt *snapshot.AwsSnapshotCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "aws disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return "", err
+	}
+
+	input := &ec2.CreateSnapshotInput{VolumeId: aws.String(opt.CloudDiskID)}
+	if opt.Name != nil {
+		input.TagSpecifications = []*ec2.TagSpecification{{
+			ResourceType: aws.String(ec2.ResourceTypeSnapshot),
+			Tags:         []*ec2.Tag{{Key: aws.String("Name"), Value: opt.Name}},
+		}}
+	}
+
+	resp, err := client.CreateSnapshotWithContext(kt.Ctx, input)
+	if err != nil {
+		logs.Errorf("aws create disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", err
+	}
+
+	if resp.SnapshotId == nil {
+		return "", fmt.Errorf("create disk snapshot return snapshot id is empty, disk: %s", opt.CloudDiskID)
+	}
+
+	return *resp.SnapshotId, nil
+}
+
+// ListDiskSnapshot 查询当前账号拥有的硬盘快照
+// reference: https://docs.amazonaws.cn/AWSEC2/latest/APIReference/API_DescribeSnapshots.html
+func (a *Aws) ListDiskSnapshot(kt *kit.Kit, opt *snapshot.AwsSnapshotListOption) ([]snapshot.AwsSnapshot, *string,
+	error) {
+
+	if opt == nil {
+		return nil, nil, errf.New(errf.InvalidParameter, "aws disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := &ec2.DescribeSnapshotsInput{OwnerIds: []*string{aws.String("self")}}
+	if len(opt.CloudDiskIDs) > 0 {
+		req.Filters = append(req.Filters, &ec2.Filter{Name: aws.String("volume-id"),
+			Values: converter.SliceToPtr(opt.CloudDiskIDs)})
+	}
+
+	if len(opt.Names) > 0 {
+		req.Filters = append(req.Filters, &ec2.Filter{Name: aws.String("tag:Name"),
+			Values: converter.SliceToPtr(opt.Names)})
+	}
+
+	// 指定快照ID查询时不能同时指定分页参数
+	if len(opt.CloudIDs) > 0 {
+		req.SnapshotIds = converter.SliceToPtr(opt.CloudIDs)
+	} else if opt.Page != nil {
+		req.MaxResults = opt.Page.MaxResults
+		req.NextToken = opt.Page.NextToken
+	}
+
+	resp, err := client.DescribeSnapshotsWithContext(kt.Ctx, req)
+	if err != nil {
+		logs.Errorf("list aws disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, nil, err
+	}
+
+	snapshots := make([]snapshot.AwsSnapshot, 0, len(resp.Snapshots))
+	for _, one := range resp.Snapshots {
+		snapshots = append(snapshots, snapshot.AwsSnapshot{Snapshot: one})
+	}
+
+	return snapshots, resp.NextToken, nil
+}
+
+// DeleteDiskSnapshot 删除硬盘快照
+// reference: https://docs.amazonaws.cn/AWSEC2/latest/APIReference/API_DeleteSnapshot.html
+func (a *Aws) DeleteDiskSnapshot(kt *kit.Kit, opt *snapshot.AwsSnapshotDeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "aws disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteSnapshotWithContext(kt.Ctx, &ec2.DeleteSnapshotInput{SnapshotId: aws.String(opt.CloudID)})
+	if err != nil {
+		logs.Errorf("aws delete disk snapshot failed, err: %v, id: %s, rid: %s", err, opt.CloudID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
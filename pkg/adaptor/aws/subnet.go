@@ -220,6 +220,7 @@ func convertSubnet(data *ec2.Subnet, region string) *adtysubnet.AwsSubnet {
 		CloudVpcID: converter.PtrToVal(data.VpcId),
 		CloudID:    converter.PtrToVal(data.SubnetId),
 		Region:     region,
+		Tags:       convTagMap(data.Tags),
 		Extension: &adtysubnet.AwsSubnetExtension{
 			State:                       converter.PtrToVal(data.State),
 			Zone:                        converter.PtrToVal(data.AvailabilityZone),
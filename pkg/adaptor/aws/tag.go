@@ -0,0 +1,112 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package aws
+
+import (
+	"hcm/pkg/adaptor/types/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// awsTagSupportResType aws ec2 支持打标签的资源类型
+var awsTagSupportResType = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:    {},
+	enumor.DiskCloudResType:   {},
+	enumor.EipCloudResType:    {},
+	enumor.VpcCloudResType:    {},
+	enumor.SubnetCloudResType: {},
+}
+
+// AddTags add tags to ec2 resources, tags with the same key will be overwritten.
+// reference: https://docs.amazonaws.cn/en_us/AWSEC2/latest/APIReference/API_CreateTags.html
+func (a *Aws) AddTags(kt *kit.Kit, opt *tag.AddTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "add tag option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, exists := awsTagSupportResType[opt.ResType]; !exists {
+		return errf.Newf(errf.InvalidParameter, "aws resource type %s not support tag", opt.ResType)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	req := &ec2.CreateTagsInput{
+		Resources: aws.StringSlice(opt.CloudIDs),
+		Tags:      make([]*ec2.Tag, 0, len(opt.Tags)),
+	}
+	for _, key := range opt.Tags.Keys() {
+		req.Tags = append(req.Tags, &ec2.Tag{Key: aws.String(key), Value: aws.String(opt.Tags[key])})
+	}
+
+	if _, err = client.CreateTagsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("create aws tags failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveTags remove tags from ec2 resources.
+// reference: https://docs.amazonaws.cn/en_us/AWSEC2/latest/APIReference/API_DeleteTags.html
+func (a *Aws) RemoveTags(kt *kit.Kit, opt *tag.RemoveTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "remove tag option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, exists := awsTagSupportResType[opt.ResType]; !exists {
+		return errf.Newf(errf.InvalidParameter, "aws resource type %s not support tag", opt.ResType)
+	}
+
+	client, err := a.clientSet.ec2Client(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	req := &ec2.DeleteTagsInput{
+		Resources: aws.StringSlice(opt.CloudIDs),
+		Tags:      make([]*ec2.Tag, 0, len(opt.Keys)),
+	}
+	for _, key := range opt.Keys {
+		req.Tags = append(req.Tags, &ec2.Tag{Key: aws.String(key)})
+	}
+
+	if _, err = client.DeleteTagsWithContext(kt.Ctx, req); err != nil {
+		logs.Errorf("delete aws tags failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -20,6 +20,8 @@
 package aws
 
 import (
+	adcore "hcm/pkg/adaptor/types/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/tools/converter"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -69,3 +71,8 @@ func parseTags(tags []*ec2.Tag) (string, []*ec2.Tag) {
 
 	return "", tags
 }
+
+// convTagMap convert ec2 tags to TagMap, it should be called before parseTags which modifies the tags.
+func convTagMap(tags []*ec2.Tag) coretag.TagMap {
+	return adcore.NewTagMap(tags, func(tag *ec2.Tag) (*string, *string) { return tag.Key, tag.Value })
+}
@@ -26,6 +26,7 @@ import (
 
 	"hcm/pkg/adaptor/types/core"
 	typecvm "hcm/pkg/adaptor/types/cvm"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
@@ -263,6 +264,7 @@ func converterToAzureCvm(vms []*armcompute.VirtualMachine) []*typecvm.AzureCvm {
 			Location: SPtrToLowerNoSpaceSPtr(v.Location),
 			Type:     v.Type,
 			Zones:    v.Zones,
+			Tags:     coretag.NewTagMapFromPtr(v.Tags),
 		}
 
 		if v.Properties == nil {
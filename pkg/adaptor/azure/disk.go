@@ -25,6 +25,7 @@ import (
 	"hcm/pkg/adaptor/types/core"
 	typecvm "hcm/pkg/adaptor/types/cvm"
 	"hcm/pkg/adaptor/types/disk"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
@@ -119,6 +120,7 @@ func (az *Azure) GetDisk(kt *kit.Kit, opt *disk.AzureDiskGetOption) (*disk.Azure
 		Status:   (*string)(resp.Disk.Properties.DiskState),
 		DiskSize: resp.Disk.Properties.DiskSizeBytes,
 		Zones:    resp.Disk.Zones,
+		Tags:     coretag.NewTagMapFromPtr(resp.Disk.Tags),
 	}
 
 	return converterResp, nil
@@ -275,6 +277,7 @@ func converterDisk(disks []*armcompute.Disk) []*disk.AzureDisk {
 			OSType:   (*string)(v.Properties.OSType),
 			SKUName:  (*string)(v.SKU.Name),
 			SKUTier:  v.SKU.Tier,
+			Tags:     coretag.NewTagMapFromPtr(v.Tags),
 		}
 		typesDisk = append(typesDisk, tmp)
 	}
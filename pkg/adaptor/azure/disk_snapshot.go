@@ -0,0 +1,171 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	"fmt"
+	"time"
+
+	snapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+)
+
+// CreateDiskSnapshot 提交增量硬盘快照创建请求，返回创建中的快照ID，快照创建耗时较长，不等待创建完成
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/create-or-update?tabs=Go
+func (az *Azure) CreateDiskSnapshot(kt *kit.Kit, opt *snapshot.AzureSnapshotCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "azure disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return "", err
+	}
+
+	req := armcompute.Snapshot{
+		Location: converter.ValToPtr(opt.Region),
+		Properties: &armcompute.SnapshotProperties{
+			CreationData: &armcompute.CreationData{
+				CreateOption:     converter.ValToPtr(armcompute.DiskCreateOptionCopy),
+				SourceResourceID: converter.ValToPtr(opt.CloudDiskID),
+			},
+			Incremental: converter.ValToPtr(true),
+		},
+	}
+	// 快照创建为长耗时操作，这里只提交创建请求，创建结果通过快照状态获取
+	_, err = client.BeginCreateOrUpdate(kt.Ctx, opt.ResourceGroupName, opt.Name, req, nil)
+	if err != nil {
+		logs.Errorf("azure create disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", errorf(err)
+	}
+
+	resp, err := client.Get(kt.Ctx, opt.ResourceGroupName, opt.Name, nil)
+	if err != nil {
+		logs.Errorf("azure get created disk snapshot failed, err: %v, name: %s, rid: %s", err, opt.Name, kt.Rid)
+		return "", errorf(err)
+	}
+
+	return converterSnapshot(&resp.Snapshot).GetCloudID(), nil
+}
+
+// ListDiskSnapshot 查询资源组下的硬盘快照
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/list-by-resource-group?tabs=Go
+func (az *Azure) ListDiskSnapshot(kt *kit.Kit, opt *snapshot.AzureSnapshotListOption) ([]snapshot.AzureSnapshot,
+	error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "azure disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return nil, err
+	}
+
+	idMap := converter.StringSliceToMap(opt.CloudIDs)
+	nameMap := converter.StringSliceToMap(opt.Names)
+	snapshots := make([]snapshot.AzureSnapshot, 0)
+	pager := client.NewListByResourceGroupPager(opt.ResourceGroupName, nil)
+	for pager.More() {
+		nextResult, err := pager.NextPage(kt.Ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to advance page: %v", err)
+		}
+
+		for _, one := range nextResult.Value {
+			converted := converterSnapshot(one)
+			if len(idMap) != 0 {
+				if _, exist := idMap[converted.GetCloudID()]; !exist {
+					continue
+				}
+			}
+			if len(nameMap) != 0 {
+				if _, exist := nameMap[converter.PtrToVal(converted.Name)]; !exist {
+					continue
+				}
+			}
+			snapshots = append(snapshots, converted)
+		}
+	}
+
+	return snapshots, nil
+}
+
+func converterSnapshot(one *armcompute.Snapshot) snapshot.AzureSnapshot {
+	result := snapshot.AzureSnapshot{
+		ID:       SPtrToLowerSPtr(one.ID),
+		Name:     one.Name,
+		Location: SPtrToLowerNoSpaceSPtr(one.Location),
+	}
+
+	if one.Properties == nil {
+		return result
+	}
+
+	result.ProvisioningState = one.Properties.ProvisioningState
+	result.DiskSizeGB = one.Properties.DiskSizeGB
+	if one.Properties.CreationData != nil {
+		result.CloudDiskID = SPtrToLowerSPtr(one.Properties.CreationData.SourceResourceID)
+	}
+	if one.Properties.TimeCreated != nil {
+		result.TimeCreated = converter.ValToPtr(one.Properties.TimeCreated.Format(time.RFC3339))
+	}
+
+	return result
+}
+
+// DeleteDiskSnapshot 删除硬盘快照
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/delete?tabs=Go
+func (az *Azure) DeleteDiskSnapshot(kt *kit.Kit, opt *snapshot.AzureSnapshotDeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "azure disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := az.clientSet.snapshotClient()
+	if err != nil {
+		return err
+	}
+
+	pollerResp, err := client.BeginDelete(kt.Ctx, opt.ResourceGroupName, opt.Name, nil)
+	if err != nil {
+		logs.Errorf("azure delete disk snapshot failed, err: %v, name: %s, rid: %s", err, opt.Name, kt.Rid)
+		return errorf(err)
+	}
+
+	_, err = pollerResp.PollUntilDone(kt.Ctx, nil)
+	return err
+}
@@ -27,6 +27,7 @@ import (
 
 	"hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/eip"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/kit"
@@ -131,6 +132,7 @@ func convertEip(one *armnetwork.PublicIPAddress, resGroupName string) *eip.Azure
 		ResourceGroupName:      strings.ToLower(resGroupName),
 		Location:               one.Location,
 		PublicIPAddressVersion: (*string)(one.Properties.PublicIPAddressVersion),
+		Tags:                   coretag.NewTagMapFromPtr(one.Tags),
 	}
 
 	if one.Properties.DNSSettings != nil {
@@ -0,0 +1,134 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	"hcm/pkg/adaptor/types/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// azureTagSupportResType azure 支持打标签的资源类型，子网不是独立的 ARM 资源，不支持标签
+var azureTagSupportResType = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:  {},
+	enumor.DiskCloudResType: {},
+	enumor.EipCloudResType:  {},
+	enumor.VpcCloudResType:  {},
+}
+
+// AddTags add tags to resources, tags with the same key will be overwritten.
+// reference: https://learn.microsoft.com/en-us/rest/api/resources/tags/update-at-scope
+func (az *Azure) AddTags(kt *kit.Kit, opt *tag.AddTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "add tag option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, exists := azureTagSupportResType[opt.ResType]; !exists {
+		return errf.Newf(errf.InvalidParameter, "azure resource type %s not support tag", opt.ResType)
+	}
+
+	client, err := az.clientSet.tagsClient()
+	if err != nil {
+		return err
+	}
+
+	tags := make(map[string]*string, len(opt.Tags))
+	for key, value := range opt.Tags {
+		tags[key] = converter.ValToPtr(value)
+	}
+
+	req := armresources.TagsPatchResource{
+		Operation:  converter.ValToPtr(armresources.TagsPatchOperationMerge),
+		Properties: &armresources.Tags{Tags: tags},
+	}
+	for _, cloudID := range opt.CloudIDs {
+		if _, err = client.UpdateAtScope(kt.Ctx, cloudID, req, nil); err != nil {
+			logs.Errorf("merge azure tags failed, err: %v, scope: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveTags remove tags from resources, azure delete operation needs tag name and value pairs,
+// so get tags of the resource first.
+// reference: https://learn.microsoft.com/en-us/rest/api/resources/tags/update-at-scope
+func (az *Azure) RemoveTags(kt *kit.Kit, opt *tag.RemoveTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "remove tag option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if _, exists := azureTagSupportResType[opt.ResType]; !exists {
+		return errf.Newf(errf.InvalidParameter, "azure resource type %s not support tag", opt.ResType)
+	}
+
+	client, err := az.clientSet.tagsClient()
+	if err != nil {
+		return err
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		resp, err := client.GetAtScope(kt.Ctx, cloudID, nil)
+		if err != nil {
+			logs.Errorf("get azure tags failed, err: %v, scope: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+
+		if resp.Properties == nil || len(resp.Properties.Tags) == 0 {
+			continue
+		}
+
+		deleteTags := make(map[string]*string)
+		for _, key := range opt.Keys {
+			if value, exists := resp.Properties.Tags[key]; exists {
+				deleteTags[key] = value
+			}
+		}
+
+		if len(deleteTags) == 0 {
+			continue
+		}
+
+		req := armresources.TagsPatchResource{
+			Operation:  converter.ValToPtr(armresources.TagsPatchOperationDelete),
+			Properties: &armresources.Tags{Tags: deleteTags},
+		}
+		if _, err = client.UpdateAtScope(kt.Ctx, cloudID, req, nil); err != nil {
+			logs.Errorf("delete azure tags failed, err: %v, scope: %s, rid: %s", err, cloudID, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"hcm/pkg/adaptor/types"
 	"hcm/pkg/adaptor/types/core"
 	"hcm/pkg/api/core/cloud"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
 	"hcm/pkg/tools/cidr"
@@ -265,6 +266,7 @@ func convertVpc(data *armnetwork.VirtualNetwork, resourceGroup string) *types.Az
 		CloudID: SPtrToLowerStr(data.ID),
 		Name:    SPtrToLowerStr(data.Name),
 		Region:  SPtrToLowerNoSpaceStr(data.Location),
+		Tags:    coretag.NewTagMapFromPtr(data.Tags),
 		Extension: &types.AzureVpcExtension{
 			ResourceGroupName: strings.ToLower(resourceGroup),
 			DNSServers:        make([]string, 0),
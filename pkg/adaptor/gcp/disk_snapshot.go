@@ -0,0 +1,132 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	snapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// CreateDiskSnapshot 提交硬盘快照创建请求，返回创建中的快照ID，快照创建耗时较长，不等待创建完成
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/disks/createSnapshot
+func (g *Gcp) CreateDiskSnapshot(kt *kit.Kit, opt *snapshot.GcpSnapshotCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "gcp disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return "", err
+	}
+
+	req := &compute.Snapshot{Name: opt.Name}
+	_, err = client.Disks.CreateSnapshot(g.CloudProjectID(), opt.Zone, opt.DiskName, req).Context(kt.Ctx).Do()
+	if err != nil {
+		logs.Errorf("gcp create disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.DiskName, kt.Rid)
+		return "", err
+	}
+
+	// 创建请求返回的是硬盘上的操作，快照ID需要根据快照名称查询
+	created, err := client.Snapshots.Get(g.CloudProjectID(), opt.Name).Context(kt.Ctx).Do()
+	if err != nil {
+		logs.Errorf("gcp get created disk snapshot failed, err: %v, name: %s, rid: %s", err, opt.Name, kt.Rid)
+		return "", err
+	}
+
+	return snapshot.GcpSnapshot{Snapshot: created}.GetCloudID(), nil
+}
+
+// ListDiskSnapshot 查询硬盘快照
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/snapshots/list
+func (g *Gcp) ListDiskSnapshot(kt *kit.Kit, opt *snapshot.GcpSnapshotListOption) ([]snapshot.GcpSnapshot, string,
+	error) {
+
+	if opt == nil {
+		return nil, "", errf.New(errf.InvalidParameter, "gcp disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	request := client.Snapshots.List(g.CloudProjectID()).Context(kt.Ctx)
+
+	if len(opt.CloudIDs) > 0 {
+		request.Filter(generateResourceIDsFilter(opt.CloudIDs))
+	}
+
+	if len(opt.Names) > 0 {
+		request.Filter(generateResourceFilter("name", opt.Names))
+	}
+
+	if opt.Page != nil {
+		request.MaxResults(opt.Page.PageSize).PageToken(opt.Page.PageToken)
+	}
+
+	resp, err := request.Do()
+	if err != nil {
+		logs.Errorf("list gcp disk snapshot failed, err: %v, opt: %v, rid: %s", err, opt, kt.Rid)
+		return nil, "", err
+	}
+
+	snapshots := make([]snapshot.GcpSnapshot, 0, len(resp.Items))
+	for _, one := range resp.Items {
+		snapshots = append(snapshots, snapshot.GcpSnapshot{Snapshot: one})
+	}
+
+	return snapshots, resp.NextPageToken, nil
+}
+
+// DeleteDiskSnapshot 删除硬盘快照
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/snapshots/delete
+func (g *Gcp) DeleteDiskSnapshot(kt *kit.Kit, opt *snapshot.GcpSnapshotDeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "gcp disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return err
+	}
+
+	if _, err = client.Snapshots.Delete(g.CloudProjectID(), opt.Name).Context(kt.Ctx).Do(); err != nil {
+		logs.Errorf("gcp delete disk snapshot failed, err: %v, name: %s, rid: %s", err, opt.Name, kt.Rid)
+		return err
+	}
+
+	return nil
+}
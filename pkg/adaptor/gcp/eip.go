@@ -24,6 +24,7 @@ import (
 
 	"hcm/pkg/adaptor/poller"
 	"hcm/pkg/adaptor/types/eip"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
@@ -340,6 +341,7 @@ func convert(resp *compute.AddressList, region string) []*eip.GcpEip {
 			Subnetwork:   item.Subnetwork,
 			SelfLink:     item.SelfLink,
 			Users:        item.Users,
+			Tags:         coretag.TagMap(item.Labels),
 		}
 		switch item.AddressType {
 		case "EXTERNAL":
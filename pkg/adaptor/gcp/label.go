@@ -0,0 +1,123 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package gcp
+
+import (
+	"hcm/pkg/adaptor/types/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// UpdateLabels update labels of resource, gcp labels need to be set with the latest label fingerprint,
+// so get the resource labels first and then set the merged labels.
+// reference: https://cloud.google.com/compute/docs/labeling-resources
+func (g *Gcp) UpdateLabels(kt *kit.Kit, opt *tag.GcpUpdateLabelOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "gcp update label option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := g.clientSet.computeClient(kt)
+	if err != nil {
+		return err
+	}
+
+	switch opt.ResType {
+	case enumor.CvmCloudResType:
+		instance, err := client.Instances.Get(g.CloudProjectID(), opt.Zone, opt.Name).Context(kt.Ctx).Do()
+		if err != nil {
+			logs.Errorf("get gcp instance failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return err
+		}
+
+		req := &compute.InstancesSetLabelsRequest{
+			Labels:           mergeLabels(instance.Labels, opt),
+			LabelFingerprint: instance.LabelFingerprint,
+		}
+		_, err = client.Instances.SetLabels(g.CloudProjectID(), opt.Zone, opt.Name, req).Context(kt.Ctx).Do()
+		if err != nil {
+			logs.Errorf("set gcp instance labels failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return err
+		}
+
+	case enumor.DiskCloudResType:
+		disk, err := client.Disks.Get(g.CloudProjectID(), opt.Zone, opt.Name).Context(kt.Ctx).Do()
+		if err != nil {
+			logs.Errorf("get gcp disk failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return err
+		}
+
+		req := &compute.ZoneSetLabelsRequest{
+			Labels:           mergeLabels(disk.Labels, opt),
+			LabelFingerprint: disk.LabelFingerprint,
+		}
+		_, err = client.Disks.SetLabels(g.CloudProjectID(), opt.Zone, opt.Name, req).Context(kt.Ctx).Do()
+		if err != nil {
+			logs.Errorf("set gcp disk labels failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return err
+		}
+
+	case enumor.EipCloudResType:
+		address, err := client.Addresses.Get(g.CloudProjectID(), opt.Region, opt.Name).Context(kt.Ctx).Do()
+		if err != nil {
+			logs.Errorf("get gcp address failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return err
+		}
+
+		req := &compute.RegionSetLabelsRequest{
+			Labels:           mergeLabels(address.Labels, opt),
+			LabelFingerprint: address.LabelFingerprint,
+		}
+		_, err = client.Addresses.SetLabels(g.CloudProjectID(), opt.Region, opt.Name, req).Context(kt.Ctx).Do()
+		if err != nil {
+			logs.Errorf("set gcp address labels failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+			return err
+		}
+
+	default:
+		return errf.Newf(errf.InvalidParameter, "gcp resource type %s not support label", opt.ResType)
+	}
+
+	return nil
+}
+
+func mergeLabels(labels map[string]string, opt *tag.GcpUpdateLabelOption) map[string]string {
+	result := make(map[string]string, len(labels)+len(opt.AddLabels))
+	for key, value := range labels {
+		result[key] = value
+	}
+
+	for key, value := range opt.AddLabels {
+		result[key] = value
+	}
+
+	for _, key := range opt.RemoveKeys {
+		delete(result, key)
+	}
+
+	return result
+}
@@ -0,0 +1,178 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"fmt"
+	"strings"
+
+	snapshot "hcm/pkg/adaptor/types/disk-snapshot"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2/model"
+)
+
+// CreateDiskSnapshot 提交硬盘快照创建请求，返回创建中的快照ID，快照创建耗时较长，不等待创建完成
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2031.html
+func (h *HuaWei) CreateDiskSnapshot(kt *kit.Kit, opt *snapshot.HuaWeiSnapshotCreateOption) (string, error) {
+	if opt == nil {
+		return "", errf.New(errf.InvalidParameter, "huawei disk snapshot create option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return "", errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return "", err
+	}
+
+	req := &model.CreateSnapshotRequest{
+		Body: &model.CreateSnapshotRequestBody{
+			Snapshot: &model.CreateSnapshotOption{
+				VolumeId: opt.CloudDiskID,
+				Force:    converter.ValToPtr(opt.Force),
+				Name:     opt.Name,
+			},
+		},
+	}
+	resp, err := client.CreateSnapshot(req)
+	if err != nil {
+		logs.Errorf("huawei create disk snapshot failed, err: %v, disk: %s, rid: %s", err, opt.CloudDiskID, kt.Rid)
+		return "", err
+	}
+
+	if resp.Snapshot == nil || resp.Snapshot.Id == nil {
+		return "", fmt.Errorf("create disk snapshot return snapshot id is empty, disk: %s", opt.CloudDiskID)
+	}
+
+	return *resp.Snapshot.Id, nil
+}
+
+// ListDiskSnapshot 查询硬盘快照
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2035.html
+func (h *HuaWei) ListDiskSnapshot(kt *kit.Kit, opt *snapshot.HuaWeiSnapshotListOption) (
+	[]snapshot.HuaWeiSnapshot, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "huawei disk snapshot list option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &model.ListSnapshotsRequest{Offset: converter.ValToPtr(opt.Offset)}
+	if opt.Limit != 0 {
+		req.Limit = converter.ValToPtr(opt.Limit)
+	}
+
+	if len(opt.CloudID) != 0 {
+		req.Id = converter.ValToPtr(opt.CloudID)
+	}
+
+	if len(opt.CloudDiskID) != 0 {
+		req.VolumeId = converter.ValToPtr(opt.CloudDiskID)
+	}
+
+	if len(opt.Name) != 0 {
+		req.Name = converter.ValToPtr(opt.Name)
+	}
+
+	resp, err := client.ListSnapshots(req)
+	if err != nil {
+		if strings.Contains(err.Error(), ErrDataNotFound) {
+			return make([]snapshot.HuaWeiSnapshot, 0), nil
+		}
+		logs.Errorf("huawei list disk snapshot failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	snapshots := make([]snapshot.HuaWeiSnapshot, 0, len(converter.PtrToVal(resp.Snapshots)))
+	for _, one := range converter.PtrToVal(resp.Snapshots) {
+		snapshots = append(snapshots, snapshot.HuaWeiSnapshot{SnapshotList: one})
+	}
+
+	return snapshots, nil
+}
+
+// DeleteDiskSnapshot 删除硬盘快照
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2034.html
+func (h *HuaWei) DeleteDiskSnapshot(kt *kit.Kit, opt *snapshot.HuaWeiSnapshotDeleteOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei disk snapshot delete option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	if _, err = client.DeleteSnapshot(&model.DeleteSnapshotRequest{SnapshotId: opt.CloudID}); err != nil {
+		logs.Errorf("huawei delete disk snapshot failed, err: %v, id: %s, rid: %s", err, opt.CloudID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// RollbackDiskSnapshot 使用快照回滚硬盘数据
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2036.html
+func (h *HuaWei) RollbackDiskSnapshot(kt *kit.Kit, opt *snapshot.HuaWeiSnapshotRollbackOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "huawei disk snapshot rollback option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	client, err := h.clientSet.evsClient(opt.Region)
+	if err != nil {
+		return err
+	}
+
+	req := &model.RollbackSnapshotRequest{
+		SnapshotId: opt.CloudID,
+		Body: &model.RollbackSnapshotRequestBody{
+			Rollback: &model.RollbackSnapshotOption{VolumeId: opt.CloudDiskID},
+		},
+	}
+	if _, err = client.RollbackSnapshot(req); err != nil {
+		logs.Errorf("huawei rollback disk snapshot failed, err: %v, snapshot: %s, disk: %s, rid: %s", err,
+			opt.CloudID, opt.CloudDiskID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"hcm/pkg/adaptor/types/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	ecsmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ecs/v2/model"
+	evsmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2/model"
+	vpcv2model "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v2/model"
+)
+
+// AddTags add tags to resources, tags with the same key will be overwritten.
+// reference: https://support.huaweicloud.com/api-ecs/ecs_02_1002.html
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2017.html
+// reference: https://support.huaweicloud.com/api-vpc/vpc_tag_0004.html
+func (h *HuaWei) AddTags(kt *kit.Kit, opt *tag.AddTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "add tag option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	keys := opt.Tags.Keys()
+	for _, cloudID := range opt.CloudIDs {
+		var err error
+		switch opt.ResType {
+		case enumor.CvmCloudResType:
+			err = h.addServerTags(opt.Region, cloudID, keys, opt.Tags)
+		case enumor.DiskCloudResType:
+			err = h.addVolumeTags(opt.Region, cloudID, keys, opt.Tags)
+		case enumor.VpcCloudResType:
+			err = h.addVpcTags(opt.Region, cloudID, keys, opt.Tags)
+		default:
+			return errf.Newf(errf.InvalidParameter, "huawei resource type %s not support tag", opt.ResType)
+		}
+
+		if err != nil {
+			logs.Errorf("add huawei %s tags failed, err: %v, cloud id: %s, rid: %s", opt.ResType, err, cloudID,
+				kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *HuaWei) addServerTags(region, cloudID string, keys []string, tags map[string]string) error {
+	client, err := h.clientSet.ecsClient(region)
+	if err != nil {
+		return err
+	}
+
+	body := &ecsmodel.BatchCreateServerTagsRequestBody{
+		Action: ecsmodel.GetBatchCreateServerTagsRequestBodyActionEnum().CREATE,
+		Tags:   make([]ecsmodel.ServerTag, 0, len(keys)),
+	}
+	for _, key := range keys {
+		body.Tags = append(body.Tags, ecsmodel.ServerTag{Key: key, Value: tags[key]})
+	}
+
+	_, err = client.BatchCreateServerTags(&ecsmodel.BatchCreateServerTagsRequest{ServerId: cloudID, Body: body})
+	return err
+}
+
+func (h *HuaWei) addVolumeTags(region, cloudID string, keys []string, tags map[string]string) error {
+	client, err := h.clientSet.evsClient(region)
+	if err != nil {
+		return err
+	}
+
+	body := &evsmodel.BatchCreateVolumeTagsRequestBody{
+		Action: evsmodel.GetBatchCreateVolumeTagsRequestBodyActionEnum().CREATE,
+		Tags:   make([]evsmodel.Tag, 0, len(keys)),
+	}
+	for _, key := range keys {
+		body.Tags = append(body.Tags, evsmodel.Tag{Key: key, Value: tags[key]})
+	}
+
+	_, err = client.BatchCreateVolumeTags(&evsmodel.BatchCreateVolumeTagsRequest{VolumeId: cloudID, Body: body})
+	return err
+}
+
+func (h *HuaWei) addVpcTags(region, cloudID string, keys []string, tags map[string]string) error {
+	client, err := h.clientSet.vpcClientV2(region)
+	if err != nil {
+		return err
+	}
+
+	body := &vpcv2model.BatchCreateVpcTagsRequestBody{
+		Action: vpcv2model.GetBatchCreateVpcTagsRequestBodyActionEnum().CREATE,
+		Tags:   make([]vpcv2model.ResourceTag, 0, len(keys)),
+	}
+	for _, key := range keys {
+		body.Tags = append(body.Tags, vpcv2model.ResourceTag{Key: key, Value: tags[key]})
+	}
+
+	_, err = client.BatchCreateVpcTags(&vpcv2model.BatchCreateVpcTagsRequest{VpcId: cloudID, Body: body})
+	return err
+}
+
+// RemoveTags remove tags from resources.
+// reference: https://support.huaweicloud.com/api-ecs/ecs_02_1003.html
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2018.html
+// reference: https://support.huaweicloud.com/api-vpc/vpc_tag_0005.html
+func (h *HuaWei) RemoveTags(kt *kit.Kit, opt *tag.RemoveTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "remove tag option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	for _, cloudID := range opt.CloudIDs {
+		var err error
+		switch opt.ResType {
+		case enumor.CvmCloudResType:
+			err = h.removeServerTags(opt.Region, cloudID, opt.Keys)
+		case enumor.DiskCloudResType:
+			err = h.removeVolumeTags(opt.Region, cloudID, opt.Keys)
+		case enumor.VpcCloudResType:
+			err = h.removeVpcTags(opt.Region, cloudID, opt.Keys)
+		default:
+			return errf.Newf(errf.InvalidParameter, "huawei resource type %s not support tag", opt.ResType)
+		}
+
+		if err != nil {
+			logs.Errorf("remove huawei %s tags failed, err: %v, cloud id: %s, rid: %s", opt.ResType, err, cloudID,
+				kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *HuaWei) removeServerTags(region, cloudID string, keys []string) error {
+	client, err := h.clientSet.ecsClient(region)
+	if err != nil {
+		return err
+	}
+
+	body := &ecsmodel.BatchDeleteServerTagsRequestBody{
+		Action: ecsmodel.GetBatchDeleteServerTagsRequestBodyActionEnum().DELETE,
+		Tags:   make([]ecsmodel.ServerTag, 0, len(keys)),
+	}
+	for _, key := range keys {
+		body.Tags = append(body.Tags, ecsmodel.ServerTag{Key: key})
+	}
+
+	_, err = client.BatchDeleteServerTags(&ecsmodel.BatchDeleteServerTagsRequest{ServerId: cloudID, Body: body})
+	return err
+}
+
+func (h *HuaWei) removeVolumeTags(region, cloudID string, keys []string) error {
+	client, err := h.clientSet.evsClient(region)
+	if err != nil {
+		return err
+	}
+
+	body := &evsmodel.BatchDeleteVolumeTagsRequestBody{
+		Action: evsmodel.GetBatchDeleteVolumeTagsRequestBodyActionEnum().DELETE,
+		Tags:   make([]evsmodel.DeleteTagsOption, 0, len(keys)),
+	}
+	for _, key := range keys {
+		body.Tags = append(body.Tags, evsmodel.DeleteTagsOption{Key: key})
+	}
+
+	_, err = client.BatchDeleteVolumeTags(&evsmodel.BatchDeleteVolumeTagsRequest{VolumeId: cloudID, Body: body})
+	return err
+}
+
+func (h *HuaWei) removeVpcTags(region, cloudID string, keys []string) error {
+	client, err := h.clientSet.vpcClientV2(region)
+	if err != nil {
+		return err
+	}
+
+	body := &vpcv2model.BatchDeleteVpcTagsRequestBody{
+		Action: vpcv2model.GetBatchDeleteVpcTagsRequestBodyActionEnum().DELETE,
+		Tags:   make([]vpcv2model.ResourceTag, 0, len(keys)),
+	}
+	for _, key := range keys {
+		body.Tags = append(body.Tags, vpcv2model.ResourceTag{Key: key})
+	}
+
+	_, err = client.BatchDeleteVpcTags(&vpcv2model.BatchDeleteVpcTagsRequest{VpcId: cloudID, Body: body})
+	return err
+}
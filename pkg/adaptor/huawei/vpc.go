@@ -27,6 +27,7 @@ import (
 	"hcm/pkg/adaptor/types"
 	"hcm/pkg/adaptor/types/core"
 	"hcm/pkg/api/core/cloud"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
@@ -237,6 +238,11 @@ func convertVpc(data *model.Vpc, region string) *types.HuaWeiVpc {
 		},
 	}
 
+	v.Tags = make(coretag.TagMap, len(data.Tags))
+	for _, tag := range data.Tags {
+		v.Tags[tag.Key] = tag.Value
+	}
+
 	if data.Cidr != "" {
 		v.Extension.Cidr = append(v.Extension.Cidr, cloud.HuaWeiCidr{
 			Type: enumor.Ipv4,
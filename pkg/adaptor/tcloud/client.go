@@ -52,6 +52,7 @@ type ClientSet interface {
 	BillClient() (*billing.Client, error)
 	ClbClient(region string) (*clb.Client, error)
 	CertClient() (*ssl.Client, error)
+	TagClient() *common.Client
 }
 
 // clientSet to get tcloud sdk client set
@@ -151,3 +152,8 @@ func (c *clientSet) CertClient() (*ssl.Client, error) {
 
 	return client, nil
 }
+
+// TagClient tcloud tag client, tag sdk is not imported, so use common client to call tag api.
+func (c *clientSet) TagClient() *common.Client {
+	return common.NewCommonClient(c.credential, "", c.profile)
+}
@@ -31,6 +31,7 @@ import (
 	"hcm/pkg/adaptor/types/core"
 	"hcm/pkg/adaptor/types/cvm"
 	"hcm/pkg/adaptor/types/disk"
+	disksnapshot "hcm/pkg/adaptor/types/disk-snapshot"
 	"hcm/pkg/adaptor/types/eip"
 	"hcm/pkg/adaptor/types/image"
 	"hcm/pkg/adaptor/types/instance-type"
@@ -40,6 +41,7 @@ import (
 	"hcm/pkg/adaptor/types/security-group"
 	"hcm/pkg/adaptor/types/security-group-rule"
 	"hcm/pkg/adaptor/types/subnet"
+	"hcm/pkg/adaptor/types/tag"
 	"hcm/pkg/adaptor/types/zone"
 	"hcm/pkg/api/core/cloud"
 	"hcm/pkg/kit"
@@ -88,6 +90,10 @@ type TCloud interface {
 	DeleteDisk(kt *kit.Kit, opt *disk.TCloudDiskDeleteOption) error
 	AttachDisk(kt *kit.Kit, opt *disk.TCloudDiskAttachOption) error
 	DetachDisk(kt *kit.Kit, opt *disk.TCloudDiskDetachOption) error
+	CreateDiskSnapshot(kt *kit.Kit, opt *disksnapshot.TCloudSnapshotCreateOption) (string, error)
+	ListDiskSnapshot(kt *kit.Kit, opt *disksnapshot.TCloudSnapshotListOption) ([]disksnapshot.TCloudSnapshot, error)
+	DeleteDiskSnapshot(kt *kit.Kit, opt *disksnapshot.TCloudSnapshotDeleteOption) error
+	RollbackDiskSnapshot(kt *kit.Kit, opt *disksnapshot.TCloudSnapshotRollbackOption) error
 	ListEip(kt *kit.Kit, opt *eip.TCloudEipListOption) (*eip.TCloudEipListResult, error)
 	CountEip(kt *kit.Kit, region string) (int32, error)
 	DeleteEip(kt *kit.Kit, opt *eip.TCloudEipDeleteOption) error
@@ -188,4 +194,7 @@ type TCloud interface {
 
 	CreateLoadBalancerSnatIps(kt *kit.Kit, opt *typelb.TCloudCreateSnatIpOpt) error
 	DeleteLoadBalancerSnatIps(kt *kit.Kit, opt *typelb.TCloudDeleteSnatIpOpt) error
+
+	AddTags(kt *kit.Kit, opt *tag.AddTagOption) error
+	RemoveTags(kt *kit.Kit, opt *tag.RemoveTagOption) error
 }
@@ -0,0 +1,144 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tcloud
+
+import (
+	"fmt"
+
+	"hcm/pkg/adaptor/types/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+const (
+	tagService = "tag"
+	tagVersion = "2018-08-13"
+)
+
+// tcloudTagResType 资源类型对应的六段式资源描述中的服务类型与资源前缀
+var tcloudTagResType = map[enumor.CloudResourceType][2]string{
+	enumor.CvmCloudResType:    {"cvm", "instance"},
+	enumor.DiskCloudResType:   {"cvm", "volume"},
+	enumor.EipCloudResType:    {"cvm", "eip"},
+	enumor.VpcCloudResType:    {"vpc", "vpc"},
+	enumor.SubnetCloudResType: {"vpc", "subnet"},
+}
+
+// AddTags add tags to resources, tags with the same key will be overwritten.
+// reference: https://cloud.tencent.com/document/api/651/72275
+func (t *TCloudImpl) AddTags(kt *kit.Kit, opt *tag.AddTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "add tag option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	resources, err := t.tagResourceNames(kt, opt.ResType, opt.Region, opt.CloudIDs)
+	if err != nil {
+		return err
+	}
+
+	tags := make([]map[string]interface{}, 0, len(opt.Tags))
+	for _, key := range opt.Tags.Keys() {
+		tags = append(tags, map[string]interface{}{"TagKey": key, "TagValue": opt.Tags[key]})
+	}
+
+	params := map[string]interface{}{"ResourceList": resources, "Tags": tags}
+	if err = t.callTagApi(kt, "TagResources", params); err != nil {
+		logs.Errorf("tcloud tag resources failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveTags remove tags from resources.
+// reference: https://cloud.tencent.com/document/api/651/72274
+func (t *TCloudImpl) RemoveTags(kt *kit.Kit, opt *tag.RemoveTagOption) error {
+	if opt == nil {
+		return errf.New(errf.InvalidParameter, "remove tag option is required")
+	}
+
+	if err := opt.Validate(); err != nil {
+		return errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	resources, err := t.tagResourceNames(kt, opt.ResType, opt.Region, opt.CloudIDs)
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{"ResourceList": resources, "TagKeys": opt.Keys}
+	if err = t.callTagApi(kt, "UnTagResources", params); err != nil {
+		logs.Errorf("tcloud untag resources failed, err: %v, opt: %+v, rid: %s", err, opt, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// tagResourceNames 生成六段式资源描述，如：qcs::cvm:ap-beijing:uin/1234567:instance/ins-xxx
+func (t *TCloudImpl) tagResourceNames(kt *kit.Kit, resType enumor.CloudResourceType, region string,
+	cloudIDs []string) ([]string, error) {
+
+	svcType, exists := tcloudTagResType[resType]
+	if !exists {
+		return nil, errf.Newf(errf.InvalidParameter, "tcloud resource type %s not support tag", resType)
+	}
+
+	if len(region) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "region is required")
+	}
+
+	info, err := t.GetAccountInfoBySecret(kt)
+	if err != nil {
+		logs.Errorf("get tcloud account info by secret failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	names := make([]string, 0, len(cloudIDs))
+	for _, cloudID := range cloudIDs {
+		names = append(names, fmt.Sprintf("qcs::%s:%s:uin/%s:%s/%s", svcType[0], region,
+			info.CloudMainAccountID, svcType[1], cloudID))
+	}
+
+	return names, nil
+}
+
+func (t *TCloudImpl) callTagApi(kt *kit.Kit, action string, params map[string]interface{}) error {
+	req := tchttp.NewCommonRequest(tagService, tagVersion, action)
+	req.SetContext(kt.Ctx)
+	if err := req.SetActionParameters(params); err != nil {
+		return err
+	}
+
+	resp := tchttp.NewCommonResponse()
+	if err := t.clientSet.TagClient().Send(req, resp); err != nil {
+		return err
+	}
+
+	return nil
+}
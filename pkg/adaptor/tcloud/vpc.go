@@ -171,6 +171,7 @@ func convertVpc(data *vpc.Vpc, region string) *types.TCloudVpc {
 		CloudID: converter.PtrToVal(data.VpcId),
 		Name:    converter.PtrToVal(data.VpcName),
 		Region:  region,
+		Tags:    core.NewTagMap(data.TagSet, func(tag *vpc.Tag) (*string, *string) { return tag.Key, tag.Value }),
 		Extension: &cloud.TCloudVpcExtension{
 			Cidr:            nil,
 			IsDefault:       converter.PtrToVal(data.IsDefault),
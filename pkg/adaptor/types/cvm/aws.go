@@ -21,6 +21,7 @@ package cvm
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -186,3 +187,8 @@ type AwsCvm struct {
 func (cvm AwsCvm) GetCloudID() string {
 	return converter.PtrToVal(cvm.InstanceId)
 }
+
+// GetTags ...
+func (cvm AwsCvm) GetTags() coretag.TagMap {
+	return core.NewTagMap(cvm.Tags, func(tag *ec2.Tag) (*string, *string) { return tag.Key, tag.Value })
+}
@@ -22,6 +22,7 @@ package cvm
 import (
 	"time"
 
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -204,9 +205,15 @@ type AzureCvm struct {
 	VCPUsPerCore        *int32                                        `json:"vcpus_per_core"`
 	TimeCreated         *time.Time                                    `json:"time_created"`
 	StorageProfile      *armcompute.StorageProfile                    `json:"storage_profile"`
+	Tags                coretag.TagMap                                `json:"tags"`
 }
 
 // GetCloudID ...
 func (cvm AzureCvm) GetCloudID() string {
 	return converter.PtrToVal(cvm.ID)
 }
+
+// GetTags ...
+func (cvm AzureCvm) GetTags() coretag.TagMap {
+	return cvm.Tags
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"hcm/pkg/adaptor/types/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/validator"
 
 	"google.golang.org/api/compute/v1"
@@ -209,3 +210,8 @@ type GcpCvm struct {
 func (cvm GcpCvm) GetCloudID() string {
 	return fmt.Sprint(cvm.Id)
 }
+
+// GetTags ...
+func (cvm GcpCvm) GetTags() coretag.TagMap {
+	return coretag.TagMap(cvm.Labels)
+}
@@ -21,9 +21,12 @@ package cvm
 
 import (
 	"fmt"
+	"strings"
 
 	"hcm/pkg/adaptor/types/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/converter"
 
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/ecs/v2/model"
 )
@@ -320,3 +323,18 @@ type HuaWeiCvm struct {
 func (cvm HuaWeiCvm) GetCloudID() string {
 	return cvm.Id
 }
+
+// GetTags ...
+func (cvm HuaWeiCvm) GetTags() coretag.TagMap {
+	// 华为云主机标签格式为 key=value
+	tags := make(coretag.TagMap)
+	for _, one := range converter.PtrToVal(cvm.ServerDetail.Tags) {
+		key, value, _ := strings.Cut(one, "=")
+		if len(key) == 0 {
+			continue
+		}
+		tags[key] = value
+	}
+
+	return tags
+}
@@ -23,6 +23,7 @@ import (
 	"errors"
 
 	"hcm/pkg/adaptor/types/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -316,6 +317,11 @@ func (cvm TCloudCvm) GetCloudID() string {
 	return converter.PtrToVal(cvm.InstanceId)
 }
 
+// GetTags ...
+func (cvm TCloudCvm) GetTags() coretag.TagMap {
+	return core.NewTagMap(cvm.Tags, func(tag *tcvm.Tag) (*string, *string) { return tag.Key, tag.Value })
+}
+
 // InquiryPriceResult define tcloud inquiry price result.
 type InquiryPriceResult struct {
 	DiscountPrice float64 `json:"discount_price"`
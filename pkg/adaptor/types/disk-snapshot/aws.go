@@ -0,0 +1,97 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"hcm/pkg/adaptor/types/core"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// AwsSnapshotCreateOption aws创建快照参数
+// reference: https://docs.amazonaws.cn/AWSEC2/latest/APIReference/API_CreateSnapshot.html
+type AwsSnapshotCreateOption struct {
+	Region      string  `json:"region" validate:"required"`
+	CloudDiskID string  `json:"cloud_disk_id" validate:"required"`
+	Name        *string `json:"name" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (opt *AwsSnapshotCreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsSnapshotListOption aws查询快照参数，只查询当前账号拥有的快照
+type AwsSnapshotListOption struct {
+	Region       string   `json:"region" validate:"required"`
+	CloudIDs     []string `json:"cloud_ids" validate:"omitempty,max=1000"`
+	CloudDiskIDs []string `json:"cloud_disk_ids" validate:"omitempty"`
+	// Names 按快照的 Name 标签查询
+	Names []string      `json:"names" validate:"omitempty"`
+	Page  *core.AwsPage `json:"page" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt *AwsSnapshotListOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if opt.Page != nil {
+		if err := opt.Page.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AwsSnapshotDeleteOption aws删除快照参数
+type AwsSnapshotDeleteOption struct {
+	Region  string `json:"region" validate:"required"`
+	CloudID string `json:"cloud_id" validate:"required"`
+}
+
+// Validate ...
+func (opt *AwsSnapshotDeleteOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AwsSnapshot for ec2 Snapshot
+type AwsSnapshot struct {
+	*ec2.Snapshot
+}
+
+// GetCloudID ...
+func (snapshot AwsSnapshot) GetCloudID() string {
+	return *snapshot.SnapshotId
+}
+
+// GetName aws快照没有名称字段，使用Name标签作为名称
+func (snapshot AwsSnapshot) GetName() string {
+	for _, tag := range snapshot.Tags {
+		if tag != nil && tag.Key != nil && *tag.Key == "Name" && tag.Value != nil {
+			return *tag.Value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,77 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"hcm/pkg/criteria/validator"
+)
+
+// AzureSnapshotCreateOption azure创建快照参数，azure快照名称在资源组内唯一
+// reference: https://learn.microsoft.com/en-us/rest/api/compute/snapshots/create-or-update?tabs=Go
+type AzureSnapshotCreateOption struct {
+	ResourceGroupName string `json:"resource_group_name" validate:"required"`
+	Region            string `json:"region" validate:"required"`
+	Name              string `json:"name" validate:"required,max=80"`
+	CloudDiskID       string `json:"cloud_disk_id" validate:"required"`
+}
+
+// Validate ...
+func (opt *AzureSnapshotCreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AzureSnapshotListOption azure查询快照参数
+type AzureSnapshotListOption struct {
+	ResourceGroupName string   `json:"resource_group_name" validate:"required"`
+	CloudIDs          []string `json:"cloud_ids" validate:"omitempty"`
+	Names             []string `json:"names" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt *AzureSnapshotListOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AzureSnapshotDeleteOption azure删除快照参数
+type AzureSnapshotDeleteOption struct {
+	ResourceGroupName string `json:"resource_group_name" validate:"required"`
+	Name              string `json:"name" validate:"required"`
+}
+
+// Validate ...
+func (opt *AzureSnapshotDeleteOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// AzureSnapshot azure快照，ID、CloudDiskID 均已转为小写，与硬盘的 cloud_id 保持一致
+type AzureSnapshot struct {
+	ID                *string `json:"id"`
+	Name              *string `json:"name"`
+	Location          *string `json:"location"`
+	ProvisioningState *string `json:"provisioning_state"`
+	CloudDiskID       *string `json:"cloud_disk_id"`
+	DiskSizeGB        *int32  `json:"disk_size_gb"`
+	TimeCreated       *string `json:"time_created"`
+}
+
+// GetCloudID ...
+func (snapshot AzureSnapshot) GetCloudID() string {
+	return *snapshot.ID
+}
@@ -0,0 +1,84 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"strconv"
+
+	"hcm/pkg/adaptor/types/core"
+	"hcm/pkg/criteria/validator"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// GcpSnapshotCreateOption gcp创建快照参数，gcp快照为全局资源，名称需符合RFC1035规范
+// reference: https://cloud.google.com/compute/docs/reference/rest/v1/disks/createSnapshot
+type GcpSnapshotCreateOption struct {
+	Zone     string `json:"zone" validate:"required"`
+	DiskName string `json:"disk_name" validate:"required"`
+	Name     string `json:"name" validate:"required,max=63"`
+}
+
+// Validate ...
+func (opt *GcpSnapshotCreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// GcpSnapshotListOption gcp查询快照参数
+type GcpSnapshotListOption struct {
+	CloudIDs []string      `json:"cloud_ids" validate:"omitempty"`
+	Names    []string      `json:"names" validate:"omitempty"`
+	Page     *core.GcpPage `json:"page" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt *GcpSnapshotListOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if opt.Page != nil {
+		if err := opt.Page.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GcpSnapshotDeleteOption gcp删除快照参数
+type GcpSnapshotDeleteOption struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// Validate ...
+func (opt *GcpSnapshotDeleteOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// GcpSnapshot for compute Snapshot
+type GcpSnapshot struct {
+	*compute.Snapshot
+}
+
+// GetCloudID ...
+func (snapshot GcpSnapshot) GetCloudID() string {
+	return strconv.FormatUint(snapshot.Id, 10)
+}
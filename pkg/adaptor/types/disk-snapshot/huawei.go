@@ -0,0 +1,90 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package disksnapshot
+
+import (
+	"hcm/pkg/criteria/validator"
+
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/evs/v2/model"
+)
+
+// HuaWeiSnapshotCreateOption 华为云创建快照参数
+// reference: https://support.huaweicloud.com/api-evs/evs_04_2031.html
+type HuaWeiSnapshotCreateOption struct {
+	Region      string  `json:"region" validate:"required"`
+	CloudDiskID string  `json:"cloud_disk_id" validate:"required"`
+	Name        *string `json:"name" validate:"omitempty,max=64"`
+	// Force 为true时允许对挂载中的硬盘创建快照
+	Force bool `json:"force"`
+}
+
+// Validate ...
+func (opt *HuaWeiSnapshotCreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiSnapshotListOption 华为云查询快照参数
+type HuaWeiSnapshotListOption struct {
+	Region      string `json:"region" validate:"required"`
+	CloudID     string `json:"cloud_id" validate:"omitempty"`
+	CloudDiskID string `json:"cloud_disk_id" validate:"omitempty"`
+	Name        string `json:"name" validate:"omitempty"`
+	Offset      int32  `json:"offset" validate:"min=0"`
+	// Limit 单次查询数量，取值范围 1-1000，为 0 时使用云上默认值
+	Limit int32 `json:"limit" validate:"min=0,max=1000"`
+}
+
+// Validate ...
+func (opt *HuaWeiSnapshotListOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiSnapshotDeleteOption 华为云删除快照参数
+type HuaWeiSnapshotDeleteOption struct {
+	Region  string `json:"region" validate:"required"`
+	CloudID string `json:"cloud_id" validate:"required"`
+}
+
+// Validate ...
+func (opt *HuaWeiSnapshotDeleteOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiSnapshotRollbackOption 华为云回滚快照参数，回滚前需确保硬盘已卸载
+type HuaWeiSnapshotRollbackOption struct {
+	Region      string `json:"region" validate:"required"`
+	CloudID     string `json:"cloud_id" validate:"required"`
+	CloudDiskID string `json:"cloud_disk_id" validate:"required"`
+}
+
+// Validate ...
+func (opt *HuaWeiSnapshotRollbackOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// HuaWeiSnapshot for evs SnapshotList
+type HuaWeiSnapshot struct {
+	model.SnapshotList
+}
+
+// GetCloudID ...
+func (snapshot HuaWeiSnapshot) GetCloudID() string {
+	return snapshot.Id
+}
@@ -0,0 +1,98 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot defines disk snapshot options and types of each vendor.
+package disksnapshot
+
+import (
+	"hcm/pkg/adaptor/types/core"
+	"hcm/pkg/criteria/validator"
+
+	cbs "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cbs/v20170312"
+)
+
+// TCloudSnapshotCreateOption 腾讯云创建快照参数
+// reference: https://cloud.tencent.com/document/api/362/15648
+type TCloudSnapshotCreateOption struct {
+	Region      string  `json:"region" validate:"required"`
+	CloudDiskID string  `json:"cloud_disk_id" validate:"required"`
+	Name        *string `json:"name" validate:"omitempty,max=60"`
+}
+
+// Validate ...
+func (opt *TCloudSnapshotCreateOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// TCloudSnapshotListOption 腾讯云查询快照参数
+type TCloudSnapshotListOption struct {
+	Region       string           `json:"region" validate:"required"`
+	CloudIDs     []string         `json:"cloud_ids" validate:"omitempty,max=100"`
+	CloudDiskIDs []string         `json:"cloud_disk_ids" validate:"omitempty,max=5"`
+	Names        []string         `json:"names" validate:"omitempty,max=5"`
+	Page         *core.TCloudPage `json:"page" validate:"omitempty"`
+}
+
+// Validate ...
+func (opt *TCloudSnapshotListOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if opt.Page != nil {
+		if err := opt.Page.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TCloudSnapshotDeleteOption 腾讯云删除快照参数
+type TCloudSnapshotDeleteOption struct {
+	Region   string   `json:"region" validate:"required"`
+	CloudIDs []string `json:"cloud_ids" validate:"required,min=1,max=100"`
+}
+
+// Validate ...
+func (opt *TCloudSnapshotDeleteOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// TCloudSnapshotRollbackOption 腾讯云回滚快照参数，回滚前需确保硬盘已卸载或者主机已关机
+type TCloudSnapshotRollbackOption struct {
+	Region      string `json:"region" validate:"required"`
+	CloudID     string `json:"cloud_id" validate:"required"`
+	CloudDiskID string `json:"cloud_disk_id" validate:"required"`
+}
+
+// Validate ...
+func (opt *TCloudSnapshotRollbackOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// TCloudSnapshot for cbs Snapshot
+type TCloudSnapshot struct {
+	*cbs.Snapshot
+}
+
+// GetCloudID ...
+func (snapshot TCloudSnapshot) GetCloudID() string {
+	return *snapshot.SnapshotId
+}
@@ -21,6 +21,7 @@ package disk
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -155,3 +156,8 @@ type AwsDisk struct {
 func (disk AwsDisk) GetCloudID() string {
 	return converter.PtrToVal(disk.VolumeId)
 }
+
+// GetTags ...
+func (disk AwsDisk) GetTags() coretag.TagMap {
+	return core.NewTagMap(disk.Tags, func(tag *ec2.Tag) (*string, *string) { return tag.Key, tag.Value })
+}
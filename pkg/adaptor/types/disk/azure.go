@@ -20,6 +20,7 @@
 package disk
 
 import (
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -144,9 +145,15 @@ type AzureDisk struct {
 	SKUName  *string   `json:"sku_name"`
 	SKUTier  *string   `json:"sku_tier"`
 	Boot     *bool
+	Tags     coretag.TagMap `json:"tags"`
 }
 
 // GetCloudID ...
 func (disk AzureDisk) GetCloudID() string {
 	return converter.PtrToVal(disk.ID)
 }
+
+// GetTags ...
+func (disk AzureDisk) GetTags() coretag.TagMap {
+	return disk.Tags
+}
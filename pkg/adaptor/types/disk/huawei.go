@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"hcm/pkg/adaptor/types/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -286,3 +287,8 @@ type HuaWeiDisk struct {
 func (disk HuaWeiDisk) GetCloudID() string {
 	return disk.Id
 }
+
+// GetTags ...
+func (disk HuaWeiDisk) GetTags() coretag.TagMap {
+	return coretag.TagMap(disk.VolumeDetail.Tags)
+}
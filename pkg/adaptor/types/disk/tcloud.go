@@ -21,6 +21,7 @@ package disk
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -196,6 +197,11 @@ func (disk TCloudDisk) GetCloudID() string {
 	return converter.PtrToVal(disk.DiskId)
 }
 
+// GetTags ...
+func (disk TCloudDisk) GetTags() coretag.TagMap {
+	return core.NewTagMap(disk.Tags, func(tag *cbs.Tag) (*string, *string) { return tag.Key, tag.Value })
+}
+
 // InquiryPriceResult define tcloud inquiry price result.
 type InquiryPriceResult struct {
 	DiscountPrice float64 `json:"discount_price"`
@@ -21,6 +21,7 @@ package eip
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/validator"
 
 	"google.golang.org/api/compute/v1"
@@ -80,6 +81,7 @@ type GcpEip struct {
 	Subnetwork   string
 	SelfLink     string
 	Users        []string
+	Tags         coretag.TagMap
 }
 
 // GetCloudID ...
@@ -87,6 +89,11 @@ func (eip *GcpEip) GetCloudID() string {
 	return eip.CloudID
 }
 
+// GetTags ...
+func (eip *GcpEip) GetTags() coretag.TagMap {
+	return eip.Tags
+}
+
 // GcpEipDeleteOption ...
 type GcpEipDeleteOption struct {
 	Region  string `json:"region" validate:"required"`
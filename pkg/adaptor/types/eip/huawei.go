@@ -20,6 +20,7 @@
 package eip
 
 import (
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/tools/converter"
 
@@ -86,6 +87,7 @@ type HuaWeiEip struct {
 	Type                *string
 	BandwidthShareType  string
 	ChargeMode          string
+	Tags                coretag.TagMap
 }
 
 // GetCloudID ...
@@ -93,6 +95,11 @@ func (eip *HuaWeiEip) GetCloudID() string {
 	return eip.CloudID
 }
 
+// GetTags ...
+func (eip *HuaWeiEip) GetTags() coretag.TagMap {
+	return eip.Tags
+}
+
 // HuaWeiEipDeleteOption ...
 type HuaWeiEipDeleteOption struct {
 	CloudID string `json:"cloud_id" validate:"required"`
@@ -103,3 +103,12 @@ func NewCreateMainAccountPollerOption() *poller.PollUntilDoneOption {
 		Retry:             retry.NewRetryPolicy(10, [2]uint{2000, 30000}),
 	}
 }
+
+// NewWaitDiskSnapshotPollerOption 快照创建耗时与硬盘数据量相关，远超异步任务的执行超时时间，由多个等待任务依次轮询快照状态，
+// 单个等待任务超时时间80s，加上最后一次重试间隔需小于任务执行超时时间，3次之内重试间隔逐次增加5s，3次之后重试间隔时间10-15s之间
+func NewWaitDiskSnapshotPollerOption() *poller.PollUntilDoneOption {
+	return &poller.PollUntilDoneOption{
+		TimeoutTimeSecond: 80,
+		Retry:             retry.NewRetryPolicy(3, [2]uint{5000, 10000}),
+	}
+}
@@ -21,6 +21,7 @@ package adtysubnet
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/criteria/validator"
 )
@@ -128,3 +129,8 @@ type AzureSubnet Subnet[AzureSubnetExtension]
 func (vpc AzureSubnet) GetCloudID() string {
 	return vpc.CloudID
 }
+
+// GetTags ...
+func (vpc AzureSubnet) GetTags() coretag.TagMap {
+	return vpc.Tags
+}
@@ -22,6 +22,7 @@ package adtysubnet
 
 import (
 	"hcm/pkg/adaptor/types/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/criteria/validator"
 )
@@ -112,3 +113,8 @@ type GcpSubnet Subnet[GcpSubnetExtension]
 func (vpc GcpSubnet) GetCloudID() string {
 	return vpc.CloudID
 }
+
+// GetTags ...
+func (vpc GcpSubnet) GetTags() coretag.TagMap {
+	return vpc.Tags
+}
@@ -20,6 +20,7 @@
 package adtysubnet
 
 import (
+	coretag "hcm/pkg/api/core/cloud/tag"
 	"hcm/pkg/criteria/errf"
 )
 
@@ -73,6 +74,8 @@ type Subnet[T SubnetExtension] struct {
 	Ipv6Cidr   []string `json:"ipv6_cidr,omitempty"`
 	Memo       *string  `json:"memo,omitempty"`
 	Extension  *T       `json:"extension"`
+	// Tags 云上标签
+	Tags coretag.TagMap `json:"tags,omitempty"`
 }
 
 // SubnetExtension defines subnet extensional info.
@@ -0,0 +1,99 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tag defines cloud resource tag options.
+package tag
+
+import (
+	"errors"
+
+	coretag "hcm/pkg/api/core/cloud/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// MaxTagResourceCount 单次修改标签的最大资源数量
+const MaxTagResourceCount = 20
+
+// AddTagOption defines add tags to cloud resources option, tags with the same key will be overwritten.
+type AddTagOption struct {
+	ResType  enumor.CloudResourceType `json:"res_type" validate:"required"`
+	Region   string                   `json:"region" validate:"omitempty"`
+	CloudIDs []string                 `json:"cloud_ids" validate:"required,min=1,max=20"`
+	Tags     coretag.TagMap           `json:"tags" validate:"required,min=1"`
+}
+
+// Validate AddTagOption.
+func (opt AddTagOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	return opt.Tags.Validate()
+}
+
+// RemoveTagOption defines remove tags from cloud resources option.
+type RemoveTagOption struct {
+	ResType  enumor.CloudResourceType `json:"res_type" validate:"required"`
+	Region   string                   `json:"region" validate:"omitempty"`
+	CloudIDs []string                 `json:"cloud_ids" validate:"required,min=1,max=20"`
+	Keys     []string                 `json:"keys" validate:"required,min=1"`
+}
+
+// Validate RemoveTagOption.
+func (opt RemoveTagOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// GcpUpdateLabelOption defines update labels of gcp resource option, gcp labels can only be set one by one
+// resource, the labels not specified remain unchanged.
+type GcpUpdateLabelOption struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	// Zone 主机、硬盘所在可用区
+	Zone string `json:"zone" validate:"omitempty"`
+	// Region 弹性IP所在地域
+	Region     string         `json:"region" validate:"omitempty"`
+	Name       string         `json:"name" validate:"required"`
+	AddLabels  coretag.TagMap `json:"add_labels" validate:"omitempty"`
+	RemoveKeys []string       `json:"remove_keys" validate:"omitempty"`
+}
+
+// Validate GcpUpdateLabelOption.
+func (opt GcpUpdateLabelOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if len(opt.AddLabels) == 0 && len(opt.RemoveKeys) == 0 {
+		return errors.New("add_labels or remove_keys is required")
+	}
+
+	switch opt.ResType {
+	case enumor.CvmCloudResType, enumor.DiskCloudResType:
+		if len(opt.Zone) == 0 {
+			return errors.New("zone is required")
+		}
+	case enumor.EipCloudResType:
+		if len(opt.Region) == 0 {
+			return errors.New("region is required")
+		}
+	}
+
+	return opt.AddLabels.Validate()
+}
@@ -0,0 +1,94 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreateAllocationRuleReq create a new version of main account bill allocation rule
+type CreateAllocationRuleReq struct {
+	MainAccountID  string                        `json:"main_account_id" validate:"required"`
+	EffectiveYear  int                           `json:"effective_year" validate:"required"`
+	EffectiveMonth int                           `json:"effective_month" validate:"required,min=1,max=12"`
+	RuleType       enumor.BillAllocationRuleType `json:"rule_type" validate:"required"`
+	Config         billcore.AllocationRuleConfig `json:"config"`
+	DefaultBkBizID int64                         `json:"default_bk_biz_id" validate:"omitempty"`
+	Enabled        *bool                         `json:"enabled" validate:"required"`
+	Memo           *string                       `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate CreateAllocationRuleReq
+func (r *CreateAllocationRuleReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+	return r.Config.Validate(r.RuleType)
+}
+
+// PreviewAllocationRuleReq preview allocation result of a saved rule version or an unsaved rule against a past month
+type PreviewAllocationRuleReq struct {
+	MainAccountID string `json:"main_account_id" validate:"required"`
+	BillYear      int    `json:"bill_year" validate:"required"`
+	BillMonth     int    `json:"bill_month" validate:"required,min=1,max=12"`
+	// RuleID 预览已保存的规则版本，为空时使用请求中的规则
+	RuleID         string                        `json:"rule_id" validate:"omitempty"`
+	RuleType       enumor.BillAllocationRuleType `json:"rule_type" validate:"omitempty"`
+	Config         billcore.AllocationRuleConfig `json:"config"`
+	DefaultBkBizID int64                         `json:"default_bk_biz_id" validate:"omitempty"`
+}
+
+// Validate PreviewAllocationRuleReq
+func (r *PreviewAllocationRuleReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+	if len(r.RuleID) != 0 {
+		return nil
+	}
+	if len(r.RuleType) == 0 {
+		return errors.New("rule_id or rule_type is required")
+	}
+	return r.Config.Validate(r.RuleType)
+}
+
+// AllocationPreviewResult allocation preview result of one main account in one month
+type AllocationPreviewResult struct {
+	MainAccountID string                      `json:"main_account_id"`
+	BillYear      int                         `json:"bill_year"`
+	BillMonth     int                         `json:"bill_month"`
+	Currency      enumor.CurrencyCode         `json:"currency"`
+	Details       []*AllocationPreviewBizCost `json:"details"`
+}
+
+// AllocationPreviewBizCost cost of one biz before and after allocation
+type AllocationPreviewBizCost struct {
+	BkBizID int64 `json:"bk_biz_id"`
+	// CurrentCost 当前账单明细中的费用
+	CurrentCost decimal.Decimal `json:"current_cost"`
+	// AllocatedCost 按规则分摊后的费用
+	AllocatedCost decimal.Decimal `json:"allocated_cost"`
+}
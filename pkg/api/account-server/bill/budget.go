@@ -0,0 +1,112 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// maxBudgetThreshold 预算告警阈值上限，单位为预算金额的百分比
+	maxBudgetThreshold = 1000
+)
+
+// CreateBudgetReq create bill budget request
+type CreateBudgetReq struct {
+	Name       string                     `json:"name" validate:"required,max=64"`
+	ScopeType  enumor.BillBudgetScopeType `json:"scope_type" validate:"required"`
+	ScopeID    string                     `json:"scope_id" validate:"required,max=64"`
+	Vendor     enumor.Vendor              `json:"vendor" validate:"omitempty"`
+	Amount     decimal.Decimal            `json:"amount" validate:"required"`
+	Thresholds []int64                    `json:"thresholds" validate:"required,min=1,max=10"`
+	Receivers  []string                   `json:"receivers" validate:"omitempty,max=20"`
+	Enabled    *bool                      `json:"enabled" validate:"required"`
+	Memo       *string                    `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate CreateBudgetReq
+func (r *CreateBudgetReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+	if err := r.ScopeType.Validate(); err != nil {
+		return err
+	}
+	if r.ScopeType == enumor.BillBudgetScopeMainAccountBiz || r.ScopeType == enumor.BillBudgetScopeProduct {
+		if _, err := strconv.ParseInt(r.ScopeID, 10, 64); err != nil {
+			return fmt.Errorf("scope_id of %s should be integer", r.ScopeType)
+		}
+	}
+	if len(r.Vendor) != 0 {
+		if err := r.Vendor.Validate(); err != nil {
+			return err
+		}
+	}
+	if !r.Amount.IsPositive() {
+		return errors.New("amount should be positive")
+	}
+	return validateBudgetThresholds(r.Thresholds)
+}
+
+// UpdateBudgetReq update bill budget request, scope of budget can not be updated
+type UpdateBudgetReq struct {
+	Name       string           `json:"name" validate:"omitempty,max=64"`
+	Vendor     enumor.Vendor    `json:"vendor" validate:"omitempty"`
+	Amount     *decimal.Decimal `json:"amount" validate:"omitempty"`
+	Thresholds []int64          `json:"thresholds" validate:"omitempty,max=10"`
+	Receivers  []string         `json:"receivers" validate:"omitempty,max=20"`
+	Enabled    *bool            `json:"enabled" validate:"omitempty"`
+	Memo       *string          `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate UpdateBudgetReq
+func (r *UpdateBudgetReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+	if len(r.Vendor) != 0 {
+		if err := r.Vendor.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Amount != nil && !r.Amount.IsPositive() {
+		return errors.New("amount should be positive")
+	}
+	if len(r.Thresholds) != 0 {
+		return validateBudgetThresholds(r.Thresholds)
+	}
+	return nil
+}
+
+func validateBudgetThresholds(thresholds []int64) error {
+	for _, threshold := range thresholds {
+		if threshold <= 0 || threshold > maxBudgetThreshold {
+			return fmt.Errorf("threshold should be in range (0, %d]", maxBudgetThreshold)
+		}
+	}
+	return nil
+}
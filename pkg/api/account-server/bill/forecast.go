@@ -0,0 +1,76 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"fmt"
+	"strconv"
+
+	"hcm/pkg/api/core"
+	billcore "hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/shopspring/decimal"
+)
+
+// ListCostForecastReq list month-end and quarter-end cost forecast request
+type ListCostForecastReq struct {
+	BillYear  int                        `json:"bill_year" validate:"required"`
+	BillMonth int                        `json:"bill_month" validate:"required,min=1,max=12"`
+	ScopeType enumor.BillBudgetScopeType `json:"scope_type" validate:"required"`
+	ScopeIDs  []string                   `json:"scope_ids" validate:"omitempty,max=500"`
+	Page      *core.BasePage             `json:"page" validate:"required"`
+}
+
+// Validate ListCostForecastReq
+func (r *ListCostForecastReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+	switch r.ScopeType {
+	case enumor.BillBudgetScopeRootAccount, enumor.BillBudgetScopeMainAccount:
+	case enumor.BillBudgetScopeMainAccountBiz:
+		for _, id := range r.ScopeIDs {
+			if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+				return fmt.Errorf("scope_id of %s should be integer", r.ScopeType)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported cost forecast scope type: %s", r.ScopeType)
+	}
+	return nil
+}
+
+// CostForecastResult cost forecast of one scope, RMB forecast is not calculated when exchange rate is missing,
+// original currency forecast is only available for root account and main account
+type CostForecastResult struct {
+	ScopeType             enumor.BillBudgetScopeType `json:"scope_type"`
+	ScopeID               string                     `json:"scope_id"`
+	Vendor                enumor.Vendor              `json:"vendor,omitempty"`
+	CurrentMonthRMBCost   decimal.Decimal            `json:"current_month_rmb_cost"`
+	billcore.CostForecast `json:",inline"`
+	// Currency 原币种费用预测的币种
+	Currency                       enumor.CurrencyCode `json:"currency,omitempty"`
+	*billcore.CurrencyCostForecast `json:",inline"`
+}
+
+// ListCostForecastResult list cost forecast result
+type ListCostForecastResult = core.ListResultT[*CostForecastResult]
@@ -0,0 +1,102 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/runtime/filter"
+)
+
+// ListResourceCostReq list resource cost request
+type ListResourceCostReq struct {
+	BillYear  int                `json:"bill_year" validate:"required"`
+	BillMonth int                `json:"bill_month" validate:"required,min=1,max=12"`
+	Filter    *filter.Expression `json:"filter" validate:"omitempty"`
+	Page      *core.BasePage     `json:"page" validate:"required"`
+}
+
+// Validate ListResourceCostReq
+func (r *ListResourceCostReq) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// SumResourceCostReq sum single resource monthly cost request
+type SumResourceCostReq struct {
+	BillYear  int                      `json:"bill_year" validate:"required"`
+	BillMonth int                      `json:"bill_month" validate:"required,min=1,max=12"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResID     string                   `json:"res_id" validate:"required"`
+}
+
+// Validate SumResourceCostReq
+func (r *SumResourceCostReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+	return ValidateResourceCostResType(r.ResType)
+}
+
+// DefaultTopResourceCostLimit default limit of top resource cost query
+const DefaultTopResourceCostLimit = 10
+
+// TopResourceCostReq list top n most expensive resources of biz request, currency is required because costs of
+// different currencies can not be compared with each other.
+type TopResourceCostReq struct {
+	BillYear  int                        `json:"bill_year" validate:"required"`
+	BillMonth int                        `json:"bill_month" validate:"required,min=1,max=12"`
+	BkBizID   int64                      `json:"bk_biz_id" validate:"required,min=1"`
+	Vendor    enumor.Vendor              `json:"vendor" validate:"omitempty"`
+	ResTypes  []enumor.CloudResourceType `json:"res_types" validate:"omitempty,max=4"`
+	Currency  enumor.CurrencyCode        `json:"currency" validate:"required"`
+	Limit     uint                       `json:"limit" validate:"omitempty,max=100"`
+}
+
+// Validate TopResourceCostReq
+func (r *TopResourceCostReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+	for _, resType := range r.ResTypes {
+		if err := ValidateResourceCostResType(resType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResourceCostResTypes resource types that bill cost can be attributed to
+var ResourceCostResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:          {},
+	enumor.DiskCloudResType:         {},
+	enumor.EipCloudResType:          {},
+	enumor.LoadBalancerCloudResType: {},
+}
+
+// ValidateResourceCostResType validate resource type of resource cost
+func ValidateResourceCostResType(resType enumor.CloudResourceType) error {
+	if _, ok := ResourceCostResTypes[resType]; !ok {
+		return errors.New("res_type should be one of cvm, disk, eip, load_balancer")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"testing"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestTopResourceCostReqValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     TopResourceCostReq
+		wantErr bool
+	}{
+		{
+			name: "with currency",
+			req:  TopResourceCostReq{BillYear: 2025, BillMonth: 1, BkBizID: 1, Currency: enumor.CurrencyRMB},
+		},
+		{
+			name:    "without currency",
+			req:     TopResourceCostReq{BillYear: 2025, BillMonth: 1, BkBizID: 1},
+			wantErr: true,
+		},
+		{
+			name: "unsupported res type",
+			req: TopResourceCostReq{BillYear: 2025, BillMonth: 1, BkBizID: 1, Currency: enumor.CurrencyRMB,
+				ResTypes: []enumor.CloudResourceType{enumor.VpcCloudResType}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
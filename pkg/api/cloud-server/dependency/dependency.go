@@ -0,0 +1,111 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package csdependency ...
+package csdependency
+
+import (
+	"fmt"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// CheckResTypes 支持删除前依赖检查的资源类型
+var CheckResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.VpcCloudResType:              {},
+	enumor.SubnetCloudResType:           {},
+	enumor.RouteTableCloudResType:       {},
+	enumor.SecurityGroupCloudResType:    {},
+	enumor.DiskCloudResType:             {},
+	enumor.EipCloudResType:              {},
+	enumor.NetworkInterfaceCloudResType: {},
+	enumor.LoadBalancerCloudResType:     {},
+	enumor.TargetGroupCloudResType:      {},
+}
+
+// LiveCheckResTypes 支持通过云上实时状态检查依赖的资源类型
+var LiveCheckResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.SubnetCloudResType: {},
+}
+
+// CheckReq define check resource dependency before deletion request.
+type CheckReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	IDs     []string                 `json:"ids" validate:"required,min=1,max=100"`
+	// Live 是否通过云上实时状态检查依赖，目前仅子网支持，检查云上子网是否仍有已使用的ip，
+	// 其他资源类型开启时返回参数错误
+	Live bool `json:"live"`
+}
+
+// Validate CheckReq.
+func (req *CheckReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if _, exist := CheckResTypes[req.ResType]; !exist {
+		return fmt.Errorf("res_type %s does not support dependency check", req.ResType)
+	}
+
+	if req.Live {
+		if _, exist := LiveCheckResTypes[req.ResType]; !exist {
+			return fmt.Errorf("res_type %s does not support live dependency check", req.ResType)
+		}
+	}
+
+	return nil
+}
+
+// CheckResult define check resource dependency result.
+type CheckResult struct {
+	Details []ResDependency `json:"details"`
+}
+
+// ResDependency define dependents of the resource to be deleted.
+type ResDependency struct {
+	ID        string `json:"id"`
+	Deletable bool   `json:"deletable"`
+	// DependentCount 依赖资源总数，Dependents 最多返回 MaxDependents 个依赖资源
+	DependentCount int         `json:"dependent_count"`
+	Dependents     []Dependent `json:"dependents"`
+}
+
+// MaxDependents 每个资源最多返回的依赖资源数量
+const MaxDependents = 100
+
+// DependentSource 依赖来源
+type DependentSource string
+
+const (
+	// LocalDependentSource 依赖来自本地已同步的资源关联关系
+	LocalDependentSource DependentSource = "local"
+	// CloudDependentSource 依赖来自云上实时状态
+	CloudDependentSource DependentSource = "cloud"
+)
+
+// Dependent define resource that blocks the deletion.
+type Dependent struct {
+	Source  DependentSource          `json:"source"`
+	ResType enumor.CloudResourceType `json:"res_type,omitempty"`
+	ID      string                   `json:"id,omitempty"`
+	CloudID string                   `json:"cloud_id,omitempty"`
+	Name    string                   `json:"name,omitempty"`
+	Reason  string                   `json:"reason"`
+}
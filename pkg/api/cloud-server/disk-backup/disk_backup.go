@@ -0,0 +1,93 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package diskbackup ...
+package diskbackup
+
+import (
+	"fmt"
+
+	corebackup "hcm/pkg/api/core/disk-backup"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/times"
+)
+
+// CreateReq define create biz disk backup policy request.
+type CreateReq struct {
+	Name           string                   `json:"name" validate:"required,max=64"`
+	Spec           string                   `json:"spec" validate:"required,max=128"`
+	RetentionCount uint                     `json:"retention_count" validate:"required,min=1,max=100"`
+	Target         *corebackup.BackupTarget `json:"target" validate:"required"`
+	AlertReceivers []string                 `json:"alert_receivers" validate:"omitempty,max=20"`
+	Enabled        *bool                    `json:"enabled" validate:"required"`
+	Memo           *string                  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate CreateReq.
+func (req *CreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := validateSpec(req.Spec); err != nil {
+		return err
+	}
+
+	return req.Target.Validate()
+}
+
+// UpdateReq define update biz disk backup policy request.
+type UpdateReq struct {
+	Name           string                   `json:"name" validate:"omitempty,max=64"`
+	Spec           string                   `json:"spec" validate:"omitempty,max=128"`
+	RetentionCount *uint                    `json:"retention_count" validate:"omitempty,min=1,max=100"`
+	Target         *corebackup.BackupTarget `json:"target" validate:"omitempty"`
+	AlertReceivers []string                 `json:"alert_receivers" validate:"omitempty,max=20"`
+	Enabled        *bool                    `json:"enabled"`
+	Memo           *string                  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate UpdateReq.
+func (req *UpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Spec) != 0 {
+		if err := validateSpec(req.Spec); err != nil {
+			return err
+		}
+	}
+
+	if req.Target != nil {
+		return req.Target.Validate()
+	}
+
+	return nil
+}
+
+// validateSpec 校验备份策略的cron表达式，提前拦截无法注册为定时任务流的表达式
+func validateSpec(spec string) error {
+	if _, err := model.NextTriggerTime(spec, times.ConvStdTimeNow()); err != nil {
+		return fmt.Errorf("spec %s is invalid, err: %v", spec, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package csipam ...
+package csipam
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	coreipam "hcm/pkg/api/core/ipam"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// Scope 地址块的查找范围，各条件之间为且关系，条件为空时表示不限制，所有条件均为空时在全部地址块中查找
+type Scope struct {
+	BkCloudIDs []int64         `json:"bk_cloud_ids" validate:"omitempty,max=100"`
+	Vendors    []enumor.Vendor `json:"vendors" validate:"omitempty,max=10"`
+	AccountIDs []string        `json:"account_ids" validate:"omitempty,max=100"`
+	Regions    []string        `json:"regions" validate:"omitempty,max=100"`
+	BkBizIDs   []int64         `json:"bk_biz_ids" validate:"omitempty,max=100"`
+}
+
+// Validate Scope.
+func (s *Scope) Validate() error {
+	return validator.Validate.Struct(s)
+}
+
+// CheckCidrReq define check cidr conflict request.
+type CheckCidrReq struct {
+	Cidr  string `json:"cidr" validate:"required,cidr"`
+	Scope *Scope `json:"scope" validate:"omitempty"`
+	// ExcludeResIDs 检查时忽略的vpc或子网ID，如检查vpc扩容网段时忽略该vpc自身
+	ExcludeResIDs []string `json:"exclude_res_ids" validate:"omitempty,max=100"`
+}
+
+// Validate CheckCidrReq.
+func (req *CheckCidrReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// CheckCidrResult define check cidr conflict result.
+type CheckCidrResult struct {
+	Available bool             `json:"available"`
+	Conflicts []coreipam.Block `json:"conflicts"`
+}
+
+// SuggestCidrReq define suggest free cidr request.
+type SuggestCidrReq struct {
+	// ParentCidr 在该网段内查找空闲地址块
+	ParentCidr string `json:"parent_cidr" validate:"required,cidr"`
+	MaskLen    int    `json:"mask_len" validate:"required,min=1,max=128"`
+	Count      int    `json:"count" validate:"omitempty,min=1,max=10"`
+	Scope      *Scope `json:"scope" validate:"omitempty"`
+}
+
+// Validate SuggestCidrReq.
+func (req *SuggestCidrReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	_, parent, err := net.ParseCIDR(req.ParentCidr)
+	if err != nil {
+		return err
+	}
+
+	ones, bits := parent.Mask.Size()
+	if req.MaskLen < ones || req.MaskLen > bits {
+		return fmt.Errorf("mask_len should be in [%d, %d]", ones, bits)
+	}
+
+	return nil
+}
+
+// SuggestCidrResult define suggest free cidr result.
+type SuggestCidrResult struct {
+	Cidrs []string `json:"cidrs"`
+}
+
+// ReserveBlockReq define reserve ipam block request.
+type ReserveBlockReq struct {
+	Cidr      string        `json:"cidr" validate:"required,cidr"`
+	BkCloudID int64         `json:"bk_cloud_id" validate:"min=0"`
+	BkBizID   int64         `json:"bk_biz_id"`
+	Vendor    enumor.Vendor `json:"vendor" validate:"omitempty"`
+	AccountID string        `json:"account_id" validate:"omitempty,max=64"`
+	Region    string        `json:"region" validate:"omitempty,max=255"`
+	Memo      *string       `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ReserveBlockReq.
+func (req *ReserveBlockReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if req.BkBizID != constant.UnassignedBiz && req.BkBizID <= 0 {
+		return fmt.Errorf("bk_biz_id %d is invalid", req.BkBizID)
+	}
+
+	if len(req.Vendor) != 0 {
+		if err := req.Vendor.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SyncBlockReq define sync ipam block request.
+type SyncBlockReq struct {
+	AccountIDs []string `json:"account_ids" validate:"required,min=1,max=20"`
+}
+
+// Validate SyncBlockReq.
+func (req *SyncBlockReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, id := range req.AccountIDs {
+		if len(id) == 0 {
+			return errors.New("account id can not be empty")
+		}
+	}
+
+	return nil
+}
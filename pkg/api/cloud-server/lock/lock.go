@@ -0,0 +1,52 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package cslock ...
+package cslock
+
+import (
+	corelock "hcm/pkg/api/core/lock"
+	"hcm/pkg/criteria/validator"
+)
+
+// ListLockReq define list distributed lock request.
+type ListLockReq struct {
+	// Prefix 锁 key 的前缀，为空时列出所有被持有的锁
+	Prefix string `json:"prefix" validate:"omitempty,max=255"`
+}
+
+// Validate ListLockReq.
+func (req *ListLockReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ListLockResult define list distributed lock result.
+type ListLockResult struct {
+	Details []corelock.LockInfo `json:"details"`
+}
+
+// ForceReleaseLockReq define force release distributed lock request.
+type ForceReleaseLockReq struct {
+	Key string `json:"key" validate:"required,max=255"`
+}
+
+// Validate ForceReleaseLockReq.
+func (req *ForceReleaseLockReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
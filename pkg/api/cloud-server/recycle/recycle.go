@@ -21,8 +21,11 @@
 package recycle
 
 import (
+	"hcm/pkg/api/core"
 	rr "hcm/pkg/api/core/recycle-record"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/runtime/filter"
 )
 
 // ------------------------ Recycle ------------------------
@@ -48,3 +51,40 @@ type CvmDetail struct {
 	AccountID           string
 	rr.CvmRecycleDetail `json:",inline"`
 }
+
+// ResRecycleReq defines recycle resources request, used by resources which have no recycle options.
+type ResRecycleReq struct {
+	IDs []string `json:"ids" validate:"min=1,max=100"`
+}
+
+// Validate ResRecycleReq.
+func (req *ResRecycleReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ResRecoverReq defines recover resources from recycle bin request.
+type ResRecoverReq struct {
+	RecordIDs []string `json:"record_ids" validate:"min=1,max=100"`
+}
+
+// Validate ResRecoverReq.
+func (req *ResRecoverReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ListUpcomingDestructionReq defines list resources which will be destroyed within hours request.
+type ListUpcomingDestructionReq struct {
+	// WithinHour 查询多少小时内将被销毁的资源
+	WithinHour uint               `json:"within_hour" validate:"required,max=720"`
+	Filter     *filter.Expression `json:"filter" validate:"omitempty"`
+	Page       *core.BasePage     `json:"page" validate:"required"`
+}
+
+// Validate ListUpcomingDestructionReq.
+func (req *ListUpcomingDestructionReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.Page.Validate()
+}
@@ -0,0 +1,124 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package cloudserver
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/runtime/filter"
+)
+
+// SGRiskAnalyzeReq security group risk analyze request.
+type SGRiskAnalyzeReq struct {
+	SecurityGroupIDs []string `json:"security_group_ids" validate:"required,min=1,max=100"`
+}
+
+// Validate SGRiskAnalyzeReq.
+func (req *SGRiskAnalyzeReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// GcpFirewallRiskAnalyzeReq gcp firewall rule risk analyze request, rules are grouped by vpc.
+type GcpFirewallRiskAnalyzeReq struct {
+	Filter *filter.Expression `json:"filter" validate:"required"`
+}
+
+// Validate GcpFirewallRiskAnalyzeReq.
+func (req *GcpFirewallRiskAnalyzeReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// SGRiskAnalyzeResult security group risk analyze result.
+type SGRiskAnalyzeResult struct {
+	Details []SGRiskReport `json:"details"`
+}
+
+// SGRiskReport 单个安全组的风险分析报告，gcp防火墙规则以vpc为维度进行分析，此时 SecurityGroupID 为 vpc id。
+type SGRiskReport struct {
+	SecurityGroupID string             `json:"security_group_id"`
+	Name            string             `json:"name"`
+	Vendor          enumor.Vendor      `json:"vendor"`
+	AccountID       string             `json:"account_id"`
+	BkBizID         int64              `json:"bk_biz_id"`
+	RuleCount       int                `json:"rule_count"`
+	Rules           []NormalizedSGRule `json:"rules"`
+	Risks           []SGRisk           `json:"risks"`
+}
+
+// SGRisk 安全组风险项
+type SGRisk struct {
+	Type    enumor.SGRiskType  `json:"type"`
+	Level   enumor.SGRiskLevel `json:"level"`
+	RuleIDs []string           `json:"rule_ids,omitempty"`
+	Message string             `json:"message"`
+}
+
+// NormalizedSGRule 各云厂商安全组规则/防火墙规则归一化后的统一模型
+type NormalizedSGRule struct {
+	ID              string                       `json:"id"`
+	SecurityGroupID string                       `json:"security_group_id"`
+	Vendor          enumor.Vendor                `json:"vendor"`
+	Type            enumor.SecurityGroupRuleType `json:"type"`
+	// Priority 规则匹配顺序，值越小越先匹配
+	Priority int64 `json:"priority"`
+	// Protocol 协议，统一为小写，all 表示所有协议
+	Protocol string `json:"protocol"`
+	// Ports 端口，如 22、80-443、80,443，ALL 表示所有端口
+	Ports string `json:"ports"`
+	// Sources 对端地址，入站规则为源地址，出站规则为目的地址
+	Sources []string `json:"sources"`
+	// RemoteRefs 对端引用的安全组、地址组、网络标签等无法展开为地址的对象
+	RemoteRefs []string            `json:"remote_refs,omitempty"`
+	Action     enumor.SGRuleAction `json:"action"`
+}
+
+// CvmSGExposureReq cvm security group effective exposure request.
+type CvmSGExposureReq struct {
+	CvmIDs []string `json:"cvm_ids" validate:"required,min=1,max=100"`
+}
+
+// Validate CvmSGExposureReq.
+func (req *CvmSGExposureReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// CvmSGExposureResult cvm security group effective exposure result.
+type CvmSGExposureResult struct {
+	Details []CvmSGExposure `json:"details"`
+}
+
+// CvmSGExposure 主机关联的所有安全组叠加后的入站暴露面
+type CvmSGExposure struct {
+	CvmID            string        `json:"cvm_id"`
+	Vendor           enumor.Vendor `json:"vendor"`
+	SecurityGroupIDs []string      `json:"security_group_ids"`
+	// PublicPorts 对任意公网地址放通的协议端口
+	PublicPorts []ExposedPort `json:"public_ports"`
+	// InboundRules 生效的入站放通规则，不包含被更高优先级拒绝规则完全覆盖的规则
+	InboundRules []NormalizedSGRule `json:"inbound_rules"`
+	// UnresolvedRuleIDs 无法确定是否作用于该主机的规则，如gcp中指定了目标网络标签或服务账号的防火墙规则
+	UnresolvedRuleIDs []string `json:"unresolved_rule_ids,omitempty"`
+}
+
+// ExposedPort 暴露的协议端口
+type ExposedPort struct {
+	Protocol string `json:"protocol"`
+	Ports    string `json:"ports"`
+}
@@ -0,0 +1,71 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package cstag ...
+package cstag
+
+import (
+	"errors"
+
+	coretag "hcm/pkg/api/core/cloud/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// BatchAddTagReq define batch add resource tag request, tags with the same key will be overwritten.
+type BatchAddTagReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	IDs     []string                 `json:"ids" validate:"required,min=1,max=100"`
+	Tags    coretag.TagMap           `json:"tags" validate:"required,min=1,max=50"`
+}
+
+// Validate BatchAddTagReq.
+func (req *BatchAddTagReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := coretag.ValidateResType(req.ResType); err != nil {
+		return err
+	}
+
+	return req.Tags.Validate()
+}
+
+// BatchRemoveTagReq define batch remove resource tag request.
+type BatchRemoveTagReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	IDs     []string                 `json:"ids" validate:"required,min=1,max=100"`
+	Keys    []string                 `json:"keys" validate:"required,min=1,max=50"`
+}
+
+// Validate BatchRemoveTagReq.
+func (req *BatchRemoveTagReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, key := range req.Keys {
+		if len(key) == 0 {
+			return errors.New("tag key can not be empty")
+		}
+	}
+
+	return coretag.ValidateResType(req.ResType)
+}
@@ -0,0 +1,112 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package cstopology ...
+package cstopology
+
+import (
+	"fmt"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+const (
+	// DefaultGraphDepth 未指定展开深度时默认展开的层数
+	DefaultGraphDepth = 2
+	// MaxGraphDepth 最大展开深度
+	MaxGraphDepth = 5
+)
+
+// GraphResTypes 拓扑图支持的资源类型
+var GraphResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.VpcCloudResType:              {},
+	enumor.SubnetCloudResType:           {},
+	enumor.CvmCloudResType:              {},
+	enumor.DiskCloudResType:             {},
+	enumor.EipCloudResType:              {},
+	enumor.NetworkInterfaceCloudResType: {},
+	enumor.SecurityGroupCloudResType:    {},
+	enumor.LoadBalancerCloudResType:     {},
+	enumor.ListenerCloudResType:         {},
+	enumor.TargetGroupCloudResType:      {},
+}
+
+// GraphReq define get resource topology graph request.
+type GraphReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ID      string                   `json:"id" validate:"required,max=64"`
+	// Depth 从根资源出发展开的层数，默认为2
+	Depth int `json:"depth" validate:"omitempty,min=1"`
+	// ResTypes 仅展开到指定类型的资源，为空时展开到所有支持的资源类型
+	ResTypes []enumor.CloudResourceType `json:"res_types" validate:"omitempty,max=10"`
+}
+
+// Validate GraphReq.
+func (req *GraphReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if _, exist := GraphResTypes[req.ResType]; !exist {
+		return fmt.Errorf("res_type %s does not support topology graph", req.ResType)
+	}
+
+	if req.Depth > MaxGraphDepth {
+		return fmt.Errorf("depth should <= %d", MaxGraphDepth)
+	}
+
+	for _, resType := range req.ResTypes {
+		if _, exist := GraphResTypes[resType]; !exist {
+			return fmt.Errorf("res_types %s does not support topology graph", resType)
+		}
+	}
+
+	return nil
+}
+
+// Graph define resource topology graph.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+	// Truncated 资源数量超过上限时停止展开，此时为true
+	Truncated bool `json:"truncated"`
+}
+
+// Node define resource node of topology graph.
+type Node struct {
+	ResType   enumor.CloudResourceType `json:"res_type"`
+	ID        string                   `json:"id"`
+	CloudID   string                   `json:"cloud_id"`
+	Name      string                   `json:"name"`
+	Vendor    enumor.Vendor            `json:"vendor"`
+	AccountID string                   `json:"account_id"`
+	BkBizID   int64                    `json:"bk_biz_id"`
+	Region    string                   `json:"region"`
+	// Depth 资源与根资源之间的层数，根资源为0
+	Depth int `json:"depth"`
+}
+
+// Edge define relation between resources, source is the upstream resource, such as vpc of subnet, cvm of disk.
+type Edge struct {
+	SourceType enumor.CloudResourceType `json:"source_type"`
+	SourceID   string                   `json:"source_id"`
+	TargetType enumor.CloudResourceType `json:"target_type"`
+	TargetID   string                   `json:"target_id"`
+}
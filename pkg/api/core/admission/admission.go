@@ -0,0 +1,140 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package coreadmission ...
+package coreadmission
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// Policy define admission policy.
+type Policy struct {
+	ID      string                   `json:"id"`
+	Name    string                   `json:"name"`
+	ResType enumor.CloudResourceType `json:"res_type"`
+	Action  enumor.AdmissionAction   `json:"action"`
+	Mode    enumor.AdmissionMode     `json:"mode"`
+	// BkBizIDs 策略作用的业务，包含 -1 时作用于所有业务及未分配业务的资源
+	BkBizIDs []int64 `json:"bk_biz_ids"`
+	Rule     *Rule   `json:"rule"`
+	// Message 命中策略时返回给用户的提示信息
+	Message       string  `json:"message"`
+	Enabled       bool    `json:"enabled"`
+	Memo          *string `json:"memo"`
+	core.Revision `json:",inline"`
+}
+
+// AppliesToBiz return if the policy applies to the resource belongs to the biz.
+func (p *Policy) AppliesToBiz(bizID int64) bool {
+	for _, one := range p.BkBizIDs {
+		if one == constant.AttachedAllBiz || one == bizID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Rule 准入策略规则，所有条件均满足时视为请求命中该策略。
+type Rule struct {
+	Conditions []Condition `json:"conditions" validate:"required,min=1,max=20,dive"`
+}
+
+// Validate Rule.
+func (r *Rule) Validate() error {
+	if r == nil {
+		return errors.New("rule is required")
+	}
+
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	for i := range r.Conditions {
+		if err := r.Conditions[i].Validate(); err != nil {
+			return fmt.Errorf("conditions[%d] is invalid, err: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Condition 准入策略规则的条件，Field 为资源请求中的字段名，字段值为数组时任一元素满足即视为满足。
+type Condition struct {
+	Field string             `json:"field" validate:"required,max=64"`
+	Op    enumor.AdmissionOp `json:"op" validate:"required"`
+	Value interface{}        `json:"value"`
+}
+
+// Validate Condition.
+func (c *Condition) Validate() error {
+	if err := validator.Validate.Struct(c); err != nil {
+		return err
+	}
+
+	if err := c.Op.Validate(); err != nil {
+		return err
+	}
+
+	switch c.Op {
+	case enumor.AdmissionIn, enumor.AdmissionNotIn:
+		values, ok := c.Value.([]interface{})
+		if !ok || len(values) == 0 {
+			return fmt.Errorf("value of %s operator should be a non-empty array", c.Op)
+		}
+
+	case enumor.AdmissionPortCovers:
+		port, ok := c.Value.(float64)
+		if !ok || port < 0 || port > 65535 || port != float64(int64(port)) {
+			return fmt.Errorf("value of %s operator should be a port number", c.Op)
+		}
+
+	default:
+		switch c.Value.(type) {
+		case string, float64, bool:
+		default:
+			return fmt.Errorf("value of %s operator should be a string, number or bool", c.Op)
+		}
+	}
+
+	return nil
+}
+
+// Decision 准入策略对一次资源请求的校验结果。
+type Decision struct {
+	PolicyID   string                   `json:"policy_id"`
+	PolicyName string                   `json:"policy_name"`
+	Mode       enumor.AdmissionMode     `json:"mode"`
+	Result     enumor.AdmissionResult   `json:"result"`
+	Message    string                   `json:"message"`
+	ResType    enumor.CloudResourceType `json:"res_type"`
+	Action     enumor.AdmissionAction   `json:"action"`
+	Vendor     enumor.Vendor            `json:"vendor"`
+	BkBizID    int64                    `json:"bk_biz_id"`
+	AccountID  string                   `json:"account_id"`
+	// Objects 命中策略的资源请求对象，策略未命中时为空
+	Objects []map[string]interface{} `json:"objects,omitempty"`
+}
@@ -28,28 +28,72 @@ import (
 
 // AsyncFlow ...
 type AsyncFlow struct {
-	ID            string                `json:"id"`
-	Name          enumor.FlowName       `json:"name"`
-	State         enumor.FlowState      `json:"state"`
-	Reason        *tableasync.Reason    `json:"reason"`
-	ShareData     *tableasync.ShareData `json:"share_data"`
-	Memo          string                `json:"memo"`
-	Worker        *string               `json:"worker"`
-	core.Revision `json:",inline"`
+	ID                  string                `json:"id"`
+	Name                enumor.FlowName       `json:"name"`
+	State               enumor.FlowState      `json:"state"`
+	Reason              *tableasync.Reason    `json:"reason"`
+	ShareData           *tableasync.ShareData `json:"share_data"`
+	Memo                string                `json:"memo"`
+	Worker              *string               `json:"worker"`
+	Priority            enumor.FlowPriority   `json:"priority"`
+	FairnessKey         string                `json:"fairness_key"`
+	CompensateOnFailure bool                  `json:"compensate_on_failure"`
+	DeadlineAt          string                `json:"deadline_at"`
+	SLAAt               string                `json:"sla_at"`
+	core.Revision       `json:",inline"`
 }
 
 // AsyncFlowTask ...
 type AsyncFlowTask struct {
-	ID            string             `json:"id"`
-	FlowID        string             `json:"flow_id"`
-	FlowName      enumor.FlowName    `json:"flow_name"`
-	ActionID      string             `json:"action_id"`
-	ActionName    enumor.ActionName  `json:"action_name"`
-	Params        types.JsonField    `json:"params"`
-	Result        types.JsonField    `json:"result"`
-	Retry         *tableasync.Retry  `json:"retry"`
-	DependOn      types.StringArray  `json:"depend_on"`
-	State         enumor.TaskState   `json:"state"`
-	Reason        *tableasync.Reason `json:"reason"`
-	core.Revision `json:",inline"`
+	ID               string                `json:"id"`
+	FlowID           string                `json:"flow_id"`
+	FlowName         enumor.FlowName       `json:"flow_name"`
+	ActionID         string                `json:"action_id"`
+	ActionName       enumor.ActionName     `json:"action_name"`
+	Kind             enumor.TaskKind       `json:"kind"`
+	Params           types.JsonField       `json:"params"`
+	Result           types.JsonField       `json:"result"`
+	Retry            *tableasync.Retry     `json:"retry"`
+	DependOn         types.StringArray     `json:"depend_on"`
+	Condition        *tableasync.Condition `json:"condition"`
+	CompensateAction enumor.ActionName     `json:"compensate_action"`
+	State            enumor.TaskState      `json:"state"`
+	Reason           *tableasync.Reason    `json:"reason"`
+	core.Revision    `json:",inline"`
+}
+
+// AsyncScheduledFlow ...
+type AsyncScheduledFlow struct {
+	ID              string                    `json:"id"`
+	Name            string                    `json:"name"`
+	FlowName        enumor.FlowName           `json:"flow_name"`
+	Spec            string                    `json:"spec"`
+	Tasks           tableasync.ScheduledTasks `json:"tasks"`
+	Memo            string                    `json:"memo"`
+	State           enumor.ScheduledFlowState `json:"state"`
+	NextTriggerAt   string                    `json:"next_trigger_at"`
+	LastTriggeredAt string                    `json:"last_triggered_at"`
+	LastFlowID      string                    `json:"last_flow_id"`
+	core.Revision   `json:",inline"`
+}
+
+// AsyncFlowTaskLog ...
+type AsyncFlowTaskLog struct {
+	ID        string              `json:"id"`
+	FlowID    string              `json:"flow_id"`
+	TaskID    string              `json:"task_id"`
+	Attempt   uint                `json:"attempt"`
+	Kind      enumor.TaskLogKind  `json:"kind"`
+	Level     enumor.TaskLogLevel `json:"level"`
+	Message   string              `json:"message"`
+	CreatedAt string              `json:"created_at"`
+}
+
+// AsyncFlowTaskAttempt 任务单次执行记录
+type AsyncFlowTaskAttempt struct {
+	Attempt uint             `json:"attempt"`
+	State   enumor.TaskState `json:"state"`
+	// Error 执行失败时的错误信息
+	Error      string `json:"error"`
+	FinishedAt string `json:"finished_at"`
 }
@@ -0,0 +1,110 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+)
+
+// AllocationRule 二级账号分账规则，同一二级账号的规则按版本保存，
+// 分账时使用生效月份不晚于账单月份的最新版本。
+// 分摊结果只体现在账单明细及资源费用中，业务账单汇总、预算告警、费用预测基于二级账号汇总，
+// 仍将二级账号的全部费用计入二级账号所属业务
+type AllocationRule struct {
+	ID             string                        `json:"id"`
+	RootAccountID  string                        `json:"root_account_id"`
+	MainAccountID  string                        `json:"main_account_id"`
+	Vendor         enumor.Vendor                 `json:"vendor"`
+	Version        int                           `json:"version"`
+	EffectiveYear  int                           `json:"effective_year"`
+	EffectiveMonth int                           `json:"effective_month"`
+	RuleType       enumor.BillAllocationRuleType `json:"rule_type"`
+	Config         AllocationRuleConfig          `json:"config"`
+	DefaultBkBizID int64                         `json:"default_bk_biz_id"`
+	Enabled        bool                          `json:"enabled"`
+	Memo           string                        `json:"memo"`
+	core.Revision
+}
+
+// IsEffective 规则在指定账单月份是否已生效
+func (r *AllocationRule) IsEffective(billYear, billMonth int) bool {
+	return r.EffectiveYear < billYear || (r.EffectiveYear == billYear && r.EffectiveMonth <= billMonth)
+}
+
+// AllocationRuleConfig 分账规则配置
+type AllocationRuleConfig struct {
+	// Targets 按比例分摊的目标业务及比例，比例之和需为1
+	Targets []AllocationTarget `json:"targets,omitempty"`
+	// TagKey 按标签分摊时使用的标签键
+	TagKey string `json:"tag_key,omitempty"`
+	// TagBizMap 标签值到业务ID的映射，未打标签或标签值不在映射中的费用分摊到默认业务
+	TagBizMap map[string]int64 `json:"tag_biz_map,omitempty"`
+}
+
+// AllocationTarget 按比例分摊的目标业务
+type AllocationTarget struct {
+	BkBizID int64           `json:"bk_biz_id"`
+	Ratio   decimal.Decimal `json:"ratio"`
+}
+
+// Validate AllocationRuleConfig by rule type
+func (c *AllocationRuleConfig) Validate(ruleType enumor.BillAllocationRuleType) error {
+	switch ruleType {
+	case enumor.BillAllocationByRatio:
+		if len(c.Targets) == 0 {
+			return errors.New("targets is required for ratio allocation")
+		}
+		total := decimal.Zero
+		for _, target := range c.Targets {
+			if target.BkBizID <= 0 {
+				return fmt.Errorf("invalid target bk_biz_id: %d", target.BkBizID)
+			}
+			if !target.Ratio.IsPositive() {
+				return fmt.Errorf("ratio of bk_biz_id %d should be positive", target.BkBizID)
+			}
+			total = total.Add(target.Ratio)
+		}
+		if !total.Equal(decimal.NewFromInt(1)) {
+			return fmt.Errorf("sum of target ratios should be 1, got %s", total)
+		}
+	case enumor.BillAllocationByTag:
+		if len(c.TagKey) == 0 {
+			return errors.New("tag_key is required for tag allocation")
+		}
+		if len(c.TagBizMap) == 0 {
+			return errors.New("tag_biz_map is required for tag allocation")
+		}
+		for value, bizID := range c.TagBizMap {
+			if bizID <= 0 {
+				return fmt.Errorf("invalid bk_biz_id %d of tag value %s", bizID, value)
+			}
+		}
+	case enumor.BillAllocationByOwnership, enumor.BillAllocationByUsage:
+	default:
+		return ruleType.Validate()
+	}
+	return nil
+}
@@ -81,4 +81,7 @@ type SummaryRoot struct {
 	State                     enumor.RootBillSummaryState `json:"state"`
 	CreatedAt                 types.Time                  `json:"created_at,omitempty"`
 	UpdatedAt                 types.Time                  `json:"updated_at,omitempty"`
+	CostForecast              `json:",inline"`
+	// CurrencyCostForecast 原币种费用预测
+	CurrencyCostForecast `json:",inline"`
 }
@@ -0,0 +1,65 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+
+	"github.com/shopspring/decimal"
+)
+
+// Budget 账单预算
+type Budget struct {
+	ID         string                     `json:"id"`
+	Name       string                     `json:"name"`
+	ScopeType  enumor.BillBudgetScopeType `json:"scope_type"`
+	ScopeID    string                     `json:"scope_id"`
+	Vendor     enumor.Vendor              `json:"vendor"`
+	Amount     decimal.Decimal            `json:"amount"`
+	Thresholds []int64                    `json:"thresholds"`
+	Receivers  []string                   `json:"receivers"`
+	Enabled    bool                       `json:"enabled"`
+	Memo       string                     `json:"memo"`
+	core.Revision
+}
+
+// Alert 账单告警记录
+type Alert struct {
+	ID           string                      `json:"id"`
+	AlertType    enumor.BillAlertType        `json:"alert_type"`
+	BudgetID     string                      `json:"budget_id"`
+	ScopeType    enumor.BillBudgetScopeType  `json:"scope_type"`
+	ScopeID      string                      `json:"scope_id"`
+	Vendor       enumor.Vendor               `json:"vendor"`
+	BillYear     int                         `json:"bill_year"`
+	BillMonth    int                         `json:"bill_month"`
+	BillDay      int                         `json:"bill_day"`
+	Threshold    int64                       `json:"threshold"`
+	Amount       decimal.Decimal             `json:"amount"`
+	Cost         decimal.Decimal             `json:"cost"`
+	ExpectedCost decimal.Decimal             `json:"expected_cost"`
+	Currency     enumor.CurrencyCode         `json:"currency"`
+	Message      string                      `json:"message"`
+	Receivers    []string                    `json:"receivers"`
+	NotifyState  enumor.BillAlertNotifyState `json:"notify_state"`
+	DedupKey     string                      `json:"dedup_key"`
+	core.Revision
+}
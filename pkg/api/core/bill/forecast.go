@@ -0,0 +1,92 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+// CostForecast 月末及季末人民币费用预测，包含预测值及置信区间上下限
+type CostForecast struct {
+	ForecastMonthRMBCost    decimal.Decimal `json:"forecast_month_rmb_cost"`
+	ForecastMonthRMBLower   decimal.Decimal `json:"forecast_month_rmb_lower"`
+	ForecastMonthRMBUpper   decimal.Decimal `json:"forecast_month_rmb_upper"`
+	ForecastQuarterRMBCost  decimal.Decimal `json:"forecast_quarter_rmb_cost"`
+	ForecastQuarterRMBLower decimal.Decimal `json:"forecast_quarter_rmb_lower"`
+	ForecastQuarterRMBUpper decimal.Decimal `json:"forecast_quarter_rmb_upper"`
+}
+
+// Add 累加另一个预测，用于将二级账号的预测汇总到一级账号或业务。各二级账号的预测误差相互独立，
+// 预测值直接累加，置信区间相对预测值的上下半宽按平方和开方（√Σδ²）汇总，而不是将上下限直接累加
+func (f CostForecast) Add(other CostForecast) CostForecast {
+	result := CostForecast{}
+	result.ForecastMonthRMBCost, result.ForecastMonthRMBLower, result.ForecastMonthRMBUpper = addBand(
+		f.ForecastMonthRMBCost, f.ForecastMonthRMBLower, f.ForecastMonthRMBUpper,
+		other.ForecastMonthRMBCost, other.ForecastMonthRMBLower, other.ForecastMonthRMBUpper)
+	result.ForecastQuarterRMBCost, result.ForecastQuarterRMBLower, result.ForecastQuarterRMBUpper = addBand(
+		f.ForecastQuarterRMBCost, f.ForecastQuarterRMBLower, f.ForecastQuarterRMBUpper,
+		other.ForecastQuarterRMBCost, other.ForecastQuarterRMBLower, other.ForecastQuarterRMBUpper)
+	return result
+}
+
+// CurrencyCostForecast 月末及季末原币种费用预测，包含预测值及置信区间上下限。缺少汇率时也会计算，
+// 只有同一币种的预测可以累加，因此只汇总到一级账号，不汇总到业务
+type CurrencyCostForecast struct {
+	ForecastMonthCost    decimal.Decimal `json:"forecast_month_cost"`
+	ForecastMonthLower   decimal.Decimal `json:"forecast_month_lower"`
+	ForecastMonthUpper   decimal.Decimal `json:"forecast_month_upper"`
+	ForecastQuarterCost  decimal.Decimal `json:"forecast_quarter_cost"`
+	ForecastQuarterLower decimal.Decimal `json:"forecast_quarter_lower"`
+	ForecastQuarterUpper decimal.Decimal `json:"forecast_quarter_upper"`
+}
+
+// Add 累加另一个同币种的预测，汇总方式与 CostForecast.Add 一致
+func (f CurrencyCostForecast) Add(other CurrencyCostForecast) CurrencyCostForecast {
+	result := CurrencyCostForecast{}
+	result.ForecastMonthCost, result.ForecastMonthLower, result.ForecastMonthUpper = addBand(
+		f.ForecastMonthCost, f.ForecastMonthLower, f.ForecastMonthUpper,
+		other.ForecastMonthCost, other.ForecastMonthLower, other.ForecastMonthUpper)
+	result.ForecastQuarterCost, result.ForecastQuarterLower, result.ForecastQuarterUpper = addBand(
+		f.ForecastQuarterCost, f.ForecastQuarterLower, f.ForecastQuarterUpper,
+		other.ForecastQuarterCost, other.ForecastQuarterLower, other.ForecastQuarterUpper)
+	return result
+}
+
+// addBand 累加两个预测值，并按平方和开方汇总置信区间的上下半宽，返回汇总后的预测值及上下限
+func addBand(cost, lower, upper, otherCost, otherLower, otherUpper decimal.Decimal) (decimal.Decimal,
+	decimal.Decimal, decimal.Decimal) {
+
+	sum := cost.Add(otherCost)
+	return sum, sum.Sub(quadratureSum(cost.Sub(lower), otherCost.Sub(otherLower))),
+		sum.Add(quadratureSum(upper.Sub(cost), otherUpper.Sub(otherCost)))
+}
+
+// quadratureSum 返回两个半宽的平方和开方，即 √(a²+b²)
+func quadratureSum(a, b decimal.Decimal) decimal.Decimal {
+	if a.IsZero() {
+		return b.Abs()
+	}
+	if b.IsZero() {
+		return a.Abs()
+	}
+	return decimal.NewFromFloat(math.Hypot(a.InexactFloat64(), b.InexactFloat64()))
+}
@@ -0,0 +1,103 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCostForecastAdd(t *testing.T) {
+	newForecast := func(cost, lower, upper int64) CostForecast {
+		return CostForecast{
+			ForecastMonthRMBCost:    decimal.NewFromInt(cost),
+			ForecastMonthRMBLower:   decimal.NewFromInt(lower),
+			ForecastMonthRMBUpper:   decimal.NewFromInt(upper),
+			ForecastQuarterRMBCost:  decimal.NewFromInt(cost * 3),
+			ForecastQuarterRMBLower: decimal.NewFromInt(lower * 3),
+			ForecastQuarterRMBUpper: decimal.NewFromInt(upper * 3),
+		}
+	}
+
+	// 半宽分别为 上3/下6、上4/下8，汇总后为 上5/下10
+	sum := CostForecast{}.Add(newForecast(100, 94, 103)).Add(newForecast(200, 192, 204))
+	expects := []struct {
+		name string
+		got  decimal.Decimal
+		want int64
+	}{
+		{name: "month cost", got: sum.ForecastMonthRMBCost, want: 300},
+		{name: "month lower", got: sum.ForecastMonthRMBLower, want: 290},
+		{name: "month upper", got: sum.ForecastMonthRMBUpper, want: 305},
+		{name: "quarter cost", got: sum.ForecastQuarterRMBCost, want: 900},
+		{name: "quarter lower", got: sum.ForecastQuarterRMBLower, want: 870},
+		{name: "quarter upper", got: sum.ForecastQuarterRMBUpper, want: 915},
+	}
+	for _, one := range expects {
+		if !one.got.Round(6).Equal(decimal.NewFromInt(one.want)) {
+			t.Errorf("%s got %s, want %d", one.name, one.got, one.want)
+		}
+	}
+
+	// 汇总的置信区间不宽于上下限直接累加的区间
+	if sum.ForecastMonthRMBLower.LessThan(decimal.NewFromInt(94 + 192)) {
+		t.Errorf("month lower %s should not be less than sum of lower bounds", sum.ForecastMonthRMBLower)
+	}
+}
+
+func TestCurrencyCostForecastAdd(t *testing.T) {
+	one := CurrencyCostForecast{
+		ForecastMonthCost:    decimal.NewFromInt(100),
+		ForecastMonthLower:   decimal.NewFromInt(94),
+		ForecastMonthUpper:   decimal.NewFromInt(103),
+		ForecastQuarterCost:  decimal.NewFromInt(300),
+		ForecastQuarterLower: decimal.NewFromInt(282),
+		ForecastQuarterUpper: decimal.NewFromInt(309),
+	}
+	other := CurrencyCostForecast{
+		ForecastMonthCost:    decimal.NewFromInt(200),
+		ForecastMonthLower:   decimal.NewFromInt(192),
+		ForecastMonthUpper:   decimal.NewFromInt(204),
+		ForecastQuarterCost:  decimal.NewFromInt(600),
+		ForecastQuarterLower: decimal.NewFromInt(576),
+		ForecastQuarterUpper: decimal.NewFromInt(612),
+	}
+
+	// 与人民币预测的汇总方式一致
+	sum := CurrencyCostForecast{}.Add(one).Add(other)
+	expects := []struct {
+		name string
+		got  decimal.Decimal
+		want int64
+	}{
+		{name: "month cost", got: sum.ForecastMonthCost, want: 300},
+		{name: "month lower", got: sum.ForecastMonthLower, want: 290},
+		{name: "month upper", got: sum.ForecastMonthUpper, want: 305},
+		{name: "quarter cost", got: sum.ForecastQuarterCost, want: 900},
+		{name: "quarter lower", got: sum.ForecastQuarterLower, want: 870},
+		{name: "quarter upper", got: sum.ForecastQuarterUpper, want: 915},
+	}
+	for _, one := range expects {
+		if !one.got.Round(6).Equal(decimal.NewFromInt(one.want)) {
+			t.Errorf("%s got %s, want %d", one.name, one.got, one.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot ...
+package disksnapshot
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+)
+
+// DiskSnapshot define disk snapshot.
+type DiskSnapshot struct {
+	ID        string        `json:"id"`
+	Vendor    enumor.Vendor `json:"vendor"`
+	AccountID string        `json:"account_id"`
+	CloudID   string        `json:"cloud_id"`
+	Name      string        `json:"name"`
+	Region    string        `json:"region"`
+	Zone      string        `json:"zone"`
+	// DiskID 快照源硬盘在hcm中的ID，源硬盘未同步或者已删除时为空
+	DiskID      string `json:"disk_id"`
+	CloudDiskID string `json:"cloud_disk_id"`
+	// DiskSize 源硬盘大小，单位GB
+	DiskSize uint64 `json:"disk_size"`
+	// Status 云上快照状态，各云厂商取值不同
+	Status  string `json:"status"`
+	BkBizID int64  `json:"bk_biz_id"`
+	// BackupPolicyID 创建快照的硬盘备份策略，手动创建或从云上同步的快照为空
+	BackupPolicyID   string     `json:"backup_policy_id"`
+	CloudCreatedTime string     `json:"cloud_created_time"`
+	Extension        *Extension `json:"extension"`
+	Memo             *string    `json:"memo"`
+	core.Revision    `json:",inline"`
+}
+
+// Extension define disk snapshot extension.
+type Extension struct {
+	// ResourceGroupName azure快照所属的资源组
+	ResourceGroupName string `json:"resource_group_name,omitempty"`
+	// Encrypted 快照是否加密
+	Encrypted *bool `json:"encrypted,omitempty"`
+	// Progress 快照创建进度
+	Progress string `json:"progress,omitempty"`
+}
+
+// readyStatus 各云厂商快照创建完成、可用于恢复数据的状态
+var readyStatus = map[enumor.Vendor]string{
+	enumor.TCloud: "NORMAL",
+	enumor.Aws:    "completed",
+	enumor.HuaWei: "available",
+	enumor.Gcp:    "READY",
+	enumor.Azure:  "Succeeded",
+}
+
+// failedStatus 各云厂商快照创建失败的状态，腾讯云快照创建失败后会被直接删除，没有失败状态
+var failedStatus = map[enumor.Vendor]string{
+	enumor.Aws:    "error",
+	enumor.HuaWei: "error",
+	enumor.Gcp:    "FAILED",
+	enumor.Azure:  "Failed",
+}
+
+// IsReady 快照是否已创建完成
+func (s DiskSnapshot) IsReady() bool {
+	status, exist := readyStatus[s.Vendor]
+	return exist && s.Status == status
+}
+
+// IsFailed 快照是否创建失败
+func (s DiskSnapshot) IsFailed() bool {
+	status, exist := failedStatus[s.Vendor]
+	return exist && s.Status == status
+}
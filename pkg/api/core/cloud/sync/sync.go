@@ -20,6 +20,8 @@
 package coresync
 
 import (
+	"time"
+
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/dal/table/types"
 )
@@ -37,3 +39,23 @@ type AccountSyncDetailTable struct {
 	CreatedAt       types.Time      `json:"created_at"`
 	UpdatedAt       types.Time      `json:"updated_at"`
 }
+
+// SyncRun 资源同步执行记录
+type SyncRun struct {
+	ID          string                   `json:"id"`
+	Vendor      enumor.Vendor            `json:"vendor"`
+	AccountID   string                   `json:"account_id"`
+	Region      string                   `json:"region"`
+	ResType     enumor.CloudResourceType `json:"res_type"`
+	TriggerType enumor.SyncTrigger       `json:"trigger_type"`
+	Status      enumor.SyncStatus        `json:"status"`
+	CreateCount uint                     `json:"create_count"`
+	UpdateCount uint                     `json:"update_count"`
+	DeleteCount uint                     `json:"delete_count"`
+	DurationMs  uint64                   `json:"duration_ms"`
+	Reason      string                   `json:"reason"`
+	StartAt     time.Time                `json:"start_at"`
+	EndAt       time.Time                `json:"end_at"`
+	Creator     string                   `json:"creator"`
+	CreatedAt   types.Time               `json:"created_at"`
+}
@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package coretag ...
+package coretag
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+)
+
+const (
+	// MaxTagKeyLength 标签键的最大长度
+	MaxTagKeyLength = 255
+	// MaxTagValueLength 标签值的最大长度
+	MaxTagValueLength = 255
+)
+
+// TagMap 资源的云上标签，key为标签键，value为标签值。gcp 的 labels 同样使用该结构表示。
+type TagMap map[string]string
+
+// Validate TagMap.
+func (t TagMap) Validate() error {
+	for key, value := range t {
+		if len(key) == 0 {
+			return errors.New("tag key can not be empty")
+		}
+
+		if len(key) > MaxTagKeyLength {
+			return fmt.Errorf("tag key %s length should <= %d", key, MaxTagKeyLength)
+		}
+
+		if len(value) > MaxTagValueLength {
+			return fmt.Errorf("tag %s's value length should <= %d", key, MaxTagValueLength)
+		}
+	}
+
+	return nil
+}
+
+// Keys return sorted tag keys.
+func (t TagMap) Keys() []string {
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Equal return whether two tag maps are the same.
+func (t TagMap) Equal(other TagMap) bool {
+	if len(t) != len(other) {
+		return false
+	}
+
+	for key, value := range t {
+		otherValue, exist := other[key]
+		if !exist || otherValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewTagMapFromPtr convert tags like azure's map[string]*string to TagMap.
+func NewTagMapFromPtr(tags map[string]*string) TagMap {
+	result := make(TagMap, len(tags))
+	for key, value := range tags {
+		if value == nil {
+			result[key] = ""
+			continue
+		}
+		result[key] = *value
+	}
+
+	return result
+}
+
+// ResTag 资源标签
+type ResTag struct {
+	ID            string                   `json:"id"`
+	Vendor        enumor.Vendor            `json:"vendor"`
+	AccountID     string                   `json:"account_id"`
+	ResType       enumor.CloudResourceType `json:"res_type"`
+	ResID         string                   `json:"res_id"`
+	ResCloudID    string                   `json:"res_cloud_id"`
+	TagKey        string                   `json:"tag_key"`
+	TagValue      string                   `json:"tag_value"`
+	core.Revision `json:",inline"`
+}
+
+// SupportedResTypes 支持标签的资源类型
+var SupportedResTypes = map[enumor.CloudResourceType]struct{}{
+	enumor.CvmCloudResType:    {},
+	enumor.DiskCloudResType:   {},
+	enumor.EipCloudResType:    {},
+	enumor.VpcCloudResType:    {},
+	enumor.SubnetCloudResType: {},
+}
+
+// ValidateResType validate whether the resource type supports tag.
+func ValidateResType(resType enumor.CloudResourceType) error {
+	if _, exist := SupportedResTypes[resType]; !exist {
+		return fmt.Errorf("resource type %s does not support tag", resType)
+	}
+
+	return nil
+}
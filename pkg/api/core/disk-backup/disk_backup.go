@@ -0,0 +1,138 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package diskbackup ...
+package diskbackup
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	coretag "hcm/pkg/api/core/cloud/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/runtime/filter"
+)
+
+// DiskBackupPolicy define disk backup policy.
+type DiskBackupPolicy struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	BkBizID int64  `json:"bk_biz_id"`
+	// Spec 标准5段式cron表达式，如：0 2 * * *
+	Spec string `json:"spec"`
+	// RetentionCount 每块硬盘保留的该策略创建的快照数量
+	RetentionCount  uint         `json:"retention_count"`
+	Target          BackupTarget `json:"target"`
+	AlertReceivers  []string     `json:"alert_receivers"`
+	Enabled         bool         `json:"enabled"`
+	ScheduledFlowID string       `json:"scheduled_flow_id"`
+	Memo            *string      `json:"memo"`
+	core.Revision   `json:",inline"`
+}
+
+// BackupTarget 备份目标硬盘的选择条件，目标硬盘为策略所属业务下同时满足标签和过滤表达式的硬盘
+type BackupTarget struct {
+	// Tags 硬盘需要包含的全部标签
+	Tags coretag.TagMap `json:"tags,omitempty"`
+	// Filter 硬盘的过滤表达式，字段为硬盘的字段
+	Filter *filter.Expression `json:"filter,omitempty"`
+}
+
+// Validate BackupTarget.
+func (t *BackupTarget) Validate() error {
+	if len(t.Tags) == 0 && (t.Filter == nil || t.Filter.IsEmpty()) {
+		return errors.New("target tags or filter is required")
+	}
+
+	if err := t.Tags.Validate(); err != nil {
+		return err
+	}
+
+	if t.Filter != nil {
+		if err := t.Filter.Validate(filter.NewExprOption(filter.MaxInLimit(100))); err != nil {
+			return fmt.Errorf("target filter is invalid, err: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// DiskFilter 生成查询业务下目标硬盘的过滤条件
+func (t *BackupTarget) DiskFilter(bizID int64) *filter.Expression {
+	rules := []filter.RuleFactory{filter.AtomRule{Field: "bk_biz_id", Op: filter.Equal.Factory(), Value: bizID}}
+	for _, key := range t.Tags.Keys() {
+		rules = append(rules, filter.AtomRule{Field: fmt.Sprintf("%s.%s", filter.TagField, key),
+			Op: filter.TagEqual.Factory(), Value: t.Tags[key]})
+	}
+
+	if t.Filter != nil && !t.Filter.IsEmpty() {
+		rules = append(rules, t.Filter)
+	}
+
+	return &filter.Expression{Op: filter.And, Rules: rules}
+}
+
+// DiskBackupRun define disk backup run, which is one execution of disk backup policy.
+type DiskBackupRun struct {
+	ID           string                    `json:"id"`
+	PolicyID     string                    `json:"policy_id"`
+	BkBizID      int64                     `json:"bk_biz_id"`
+	TriggerType  enumor.DiskBackupTrigger  `json:"trigger_type"`
+	State        enumor.DiskBackupRunState `json:"state"`
+	TargetCount  uint                      `json:"target_count"`
+	SuccessCount uint                      `json:"success_count"`
+	FailedCount  uint                      `json:"failed_count"`
+	// PrunedCount 本次清理的过期快照数量
+	PrunedCount   uint         `json:"pruned_count"`
+	FailedDetails []FailedDisk `json:"failed_details"`
+	Reason        string       `json:"reason"`
+	// Alerted 失败告警是否已发送
+	Alerted bool   `json:"alerted"`
+	Rid     string `json:"rid"`
+	StartAt string `json:"start_at"`
+	// DurationMs 备份耗时，单位毫秒
+	DurationMs    uint64 `json:"duration_ms"`
+	core.Revision `json:",inline"`
+}
+
+// FailedDisk 备份失败的硬盘及失败原因
+type FailedDisk struct {
+	DiskID string `json:"disk_id"`
+	// Stage 失败的阶段，snapshot 为创建快照失败，prune 为清理过期快照失败
+	Stage  string `json:"stage"`
+	Reason string `json:"reason"`
+}
+
+const (
+	// SnapshotStage 创建快照阶段
+	SnapshotStage = "snapshot"
+	// PruneStage 清理过期快照阶段
+	PruneStage = "prune"
+)
+
+// SnapshotName 生成备份策略创建的快照名称，部分云厂商要求快照名称唯一且长度不超过63
+func SnapshotName(policyID, diskID string, unix int64) string {
+	return fmt.Sprintf("backup-%s-%s-%d", policyID, diskID, unix)
+}
+
+// IsFailed return whether the run needs to alert.
+func (r *DiskBackupRun) IsFailed() bool {
+	return r.State == enumor.DiskBackupFailed || r.State == enumor.DiskBackupPartialFailed
+}
@@ -0,0 +1,33 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package corelock ...
+package corelock
+
+import "hcm/pkg/dal/table/types"
+
+// LockInfo 分布式锁持有信息
+type LockInfo struct {
+	Key   string `json:"key"`
+	Owner string `json:"owner"`
+	// Token 加锁的 fencing token，同一个 key 的 token 单调递增
+	Token      int64      `json:"token"`
+	AcquiredAt types.Time `json:"acquired_at"`
+	ExpireAt   types.Time `json:"expire_at"`
+}
@@ -0,0 +1,68 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package recyclepolicy ...
+package recyclepolicy
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+)
+
+// RecyclePolicy define recycle policy.
+type RecyclePolicy struct {
+	ID string `json:"id"`
+	// BkBizID 策略作用的业务，-1 表示所有业务的默认策略
+	BkBizID int64                    `json:"bk_biz_id"`
+	ResType enumor.CloudResourceType `json:"res_type"`
+	// RetentionHour 资源在回收站中的保留时长，单位小时
+	RetentionHour uint `json:"retention_hour"`
+	// DetachBeforeRecycle 回收前是否先解绑关联的eip、硬盘
+	DetachBeforeRecycle bool `json:"detach_before_recycle"`
+	// SnapshotBeforeDestroy 销毁硬盘前是否先创建快照
+	SnapshotBeforeDestroy bool `json:"snapshot_before_destroy"`
+	// NotifyBeforeHour 销毁前多少小时通知资源负责人，0 表示不通知
+	NotifyBeforeHour uint    `json:"notify_before_hour"`
+	Enabled          bool    `json:"enabled"`
+	Memo             *string `json:"memo"`
+	core.Revision    `json:",inline"`
+}
+
+// PickPolicy 从同一资源类型的回收策略中选出作用于业务的策略，业务策略优先于所有业务的默认策略，
+// 未分配业务的资源只使用默认策略。
+func PickPolicy(policies []RecyclePolicy, bizID int64) *RecyclePolicy {
+	var fallback *RecyclePolicy
+	for i := range policies {
+		if !policies[i].Enabled {
+			continue
+		}
+
+		if policies[i].BkBizID == constant.AttachedAllBiz {
+			fallback = &policies[i]
+			continue
+		}
+
+		if bizID > 0 && policies[i].BkBizID == bizID {
+			return &policies[i]
+		}
+	}
+
+	return fallback
+}
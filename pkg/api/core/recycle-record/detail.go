@@ -100,3 +100,13 @@ type DiskRecycleOptions struct{}
 type DiskRelatedRecycleOpt struct {
 	CvmID string `json:"cvm_id"`
 }
+
+// EipRecycleDetail eip回收详情，记录按回收策略在回收前解绑的主机
+type EipRecycleDetail struct {
+	DetachedCvmID string `json:"detached_cvm_id,omitempty"`
+}
+
+// RecycleNotifyDetail 回收记录中的销毁前通知信息，与各资源的回收详情合并存储
+type RecycleNotifyDetail struct {
+	NotifiedAt string `json:"notified_at,omitempty"`
+}
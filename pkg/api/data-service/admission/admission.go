@@ -0,0 +1,145 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package dsadmission ...
+package dsadmission
+
+import (
+	"errors"
+	"fmt"
+
+	coreadmission "hcm/pkg/api/core/admission"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// CreateReq define create admission policy request.
+type CreateReq struct {
+	Name     string                   `json:"name" validate:"required,max=255"`
+	ResType  enumor.CloudResourceType `json:"res_type" validate:"required"`
+	Action   enumor.AdmissionAction   `json:"action" validate:"required"`
+	Mode     enumor.AdmissionMode     `json:"mode" validate:"required"`
+	BkBizIDs []int64                  `json:"bk_biz_ids" validate:"required,min=1,max=100"`
+	Rule     *coreadmission.Rule      `json:"rule" validate:"required"`
+	Message  string                   `json:"message" validate:"max=1024"`
+	Enabled  *bool                    `json:"enabled" validate:"required"`
+	Memo     *string                  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate CreateReq.
+func (req *CreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if _, exist := enumor.AdmissionResTypes[req.ResType]; !exist {
+		return fmt.Errorf("res_type %s does not support admission policy", req.ResType)
+	}
+
+	if err := req.Action.Validate(); err != nil {
+		return err
+	}
+
+	if err := req.Mode.Validate(); err != nil {
+		return err
+	}
+
+	if err := ValidateBizIDs(req.BkBizIDs); err != nil {
+		return err
+	}
+
+	return req.Rule.Validate()
+}
+
+// UpdateReq define update admission policy request, res_type and action of policy can not be updated.
+type UpdateReq struct {
+	Name     string               `json:"name" validate:"omitempty,max=255"`
+	Mode     enumor.AdmissionMode `json:"mode" validate:"omitempty"`
+	BkBizIDs []int64              `json:"bk_biz_ids" validate:"omitempty,max=100"`
+	Rule     *coreadmission.Rule  `json:"rule" validate:"omitempty"`
+	Message  *string              `json:"message" validate:"omitempty,max=1024"`
+	Enabled  *bool                `json:"enabled" validate:"omitempty"`
+	Memo     *string              `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate UpdateReq.
+func (req *UpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Mode) != 0 {
+		if err := req.Mode.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if req.BkBizIDs != nil {
+		if err := ValidateBizIDs(req.BkBizIDs); err != nil {
+			return err
+		}
+	}
+
+	if req.Rule != nil {
+		if err := req.Rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateBizIDs validate the biz ids which admission policy applies to.
+func ValidateBizIDs(bizIDs []int64) error {
+	if len(bizIDs) == 0 {
+		return errors.New("bk_biz_ids is required")
+	}
+
+	for _, one := range bizIDs {
+		if one == constant.AttachedAllBiz {
+			if len(bizIDs) != 1 {
+				return errors.New("bk_biz_ids can not contain other biz when it contains -1")
+			}
+			continue
+		}
+
+		if one <= 0 {
+			return fmt.Errorf("bk_biz_id %d is invalid", one)
+		}
+	}
+
+	return nil
+}
+
+// ListResult define list admission policy result.
+type ListResult struct {
+	Count   uint64                 `json:"count"`
+	Details []coreadmission.Policy `json:"details"`
+}
+
+// RecordDecisionReq define record admission decisions request, decisions are recorded as audits.
+type RecordDecisionReq struct {
+	Decisions []coreadmission.Decision `json:"decisions" validate:"required,min=1,max=100"`
+}
+
+// Validate RecordDecisionReq.
+func (req *RecordDecisionReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
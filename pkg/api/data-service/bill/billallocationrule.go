@@ -0,0 +1,55 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// BillAllocationRuleCreateReq create request, version of rule is assigned by data service
+type BillAllocationRuleCreateReq struct {
+	RootAccountID  string                        `json:"root_account_id" validate:"required"`
+	MainAccountID  string                        `json:"main_account_id" validate:"required"`
+	Vendor         enumor.Vendor                 `json:"vendor" validate:"required"`
+	EffectiveYear  int                           `json:"effective_year" validate:"required"`
+	EffectiveMonth int                           `json:"effective_month" validate:"required,min=1,max=12"`
+	RuleType       enumor.BillAllocationRuleType `json:"rule_type" validate:"required"`
+	Config         bill.AllocationRuleConfig     `json:"config" validate:"required"`
+	DefaultBkBizID int64                         `json:"default_bk_biz_id" validate:"omitempty"`
+	Enabled        *bool                         `json:"enabled" validate:"required"`
+	Memo           *string                       `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (r *BillAllocationRuleCreateReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+	return r.Config.Validate(r.RuleType)
+}
+
+// BillAllocationRuleListReq list request
+type BillAllocationRuleListReq = core.ListReq
+
+// BillAllocationRuleListResult list result
+type BillAllocationRuleListResult = core.ListResultT[*bill.AllocationRule]
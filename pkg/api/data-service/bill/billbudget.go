@@ -0,0 +1,115 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/shopspring/decimal"
+)
+
+// BillBudgetCreateReq create request
+type BillBudgetCreateReq struct {
+	Name       string                     `json:"name" validate:"required,max=64"`
+	ScopeType  enumor.BillBudgetScopeType `json:"scope_type" validate:"required"`
+	ScopeID    string                     `json:"scope_id" validate:"required,max=64"`
+	Vendor     enumor.Vendor              `json:"vendor" validate:"omitempty"`
+	Amount     decimal.Decimal            `json:"amount" validate:"required"`
+	Thresholds []int64                    `json:"thresholds" validate:"required,min=1,max=10"`
+	Receivers  []string                   `json:"receivers" validate:"omitempty,max=20"`
+	Enabled    *bool                      `json:"enabled" validate:"required"`
+	Memo       *string                    `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (r *BillBudgetCreateReq) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+	return r.ScopeType.Validate()
+}
+
+// BillBudgetUpdateReq update request
+type BillBudgetUpdateReq struct {
+	ID         string           `json:"id" validate:"required"`
+	Name       string           `json:"name" validate:"omitempty,max=64"`
+	Vendor     enumor.Vendor    `json:"vendor" validate:"omitempty"`
+	Amount     *decimal.Decimal `json:"amount" validate:"omitempty"`
+	Thresholds []int64          `json:"thresholds" validate:"omitempty,max=10"`
+	Receivers  []string         `json:"receivers" validate:"omitempty,max=20"`
+	Enabled    *bool            `json:"enabled" validate:"omitempty"`
+	Memo       *string          `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate ...
+func (r *BillBudgetUpdateReq) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// BillBudgetListReq list request
+type BillBudgetListReq = core.ListReq
+
+// BillBudgetListResult list result
+type BillBudgetListResult = core.ListResultT[*bill.Budget]
+
+// BatchBillAlertCreateReq batch create request
+type BatchBillAlertCreateReq struct {
+	Items []BillAlertCreateReq `json:"items" validate:"required,min=1,max=100,dive,required"`
+}
+
+// Validate ...
+func (r *BatchBillAlertCreateReq) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// BillAlertCreateReq create request
+type BillAlertCreateReq struct {
+	AlertType    enumor.BillAlertType        `json:"alert_type" validate:"required"`
+	BudgetID     string                      `json:"budget_id" validate:"omitempty"`
+	ScopeType    enumor.BillBudgetScopeType  `json:"scope_type" validate:"required"`
+	ScopeID      string                      `json:"scope_id" validate:"required"`
+	Vendor       enumor.Vendor               `json:"vendor" validate:"omitempty"`
+	BillYear     int                         `json:"bill_year" validate:"required"`
+	BillMonth    int                         `json:"bill_month" validate:"required"`
+	BillDay      int                         `json:"bill_day" validate:"omitempty"`
+	Threshold    int64                       `json:"threshold" validate:"omitempty"`
+	Amount       decimal.Decimal             `json:"amount" validate:"omitempty"`
+	Cost         decimal.Decimal             `json:"cost" validate:"omitempty"`
+	ExpectedCost decimal.Decimal             `json:"expected_cost" validate:"omitempty"`
+	Currency     enumor.CurrencyCode         `json:"currency" validate:"omitempty"`
+	Message      string                      `json:"message" validate:"omitempty,max=1024"`
+	Receivers    []string                    `json:"receivers" validate:"omitempty"`
+	NotifyState  enumor.BillAlertNotifyState `json:"notify_state" validate:"required"`
+	DedupKey     string                      `json:"dedup_key" validate:"required,max=255"`
+}
+
+// Validate ...
+func (r *BillAlertCreateReq) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// BillAlertListReq list request
+type BillAlertListReq = core.ListReq
+
+// BillAlertListResult list result
+type BillAlertListResult = core.ListResultT[*bill.Alert]
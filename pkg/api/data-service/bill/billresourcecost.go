@@ -0,0 +1,72 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"hcm/pkg/api/core"
+	"hcm/pkg/api/core/bill"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+
+	"github.com/shopspring/decimal"
+)
+
+// BatchBillResourceCostCreateReq batch create request
+type BatchBillResourceCostCreateReq struct {
+	Items []BillResourceCostCreateReq `json:"items" validate:"required,min=1,max=100,dive,required"`
+}
+
+// Validate ...
+func (r *BatchBillResourceCostCreateReq) Validate() error {
+	return validator.Validate.Struct(r)
+}
+
+// BillResourceCostCreateReq create request
+type BillResourceCostCreateReq struct {
+	RootAccountID string                   `json:"root_account_id" validate:"required"`
+	MainAccountID string                   `json:"main_account_id" validate:"required"`
+	Vendor        enumor.Vendor            `json:"vendor" validate:"required"`
+	ProductID     int64                    `json:"product_id" validate:"omitempty"`
+	BkBizID       int64                    `json:"bk_biz_id" validate:"omitempty"`
+	BillYear      int                      `json:"bill_year" validate:"required"`
+	BillMonth     int                      `json:"bill_month" validate:"required"`
+	BillDay       int                      `json:"bill_day" validate:"required"`
+	VersionID     int                      `json:"version_id" validate:"required"`
+	ResCloudID    string                   `json:"res_cloud_id" validate:"required,max=255"`
+	ResType       enumor.CloudResourceType `json:"res_type" validate:"omitempty"`
+	ResID         string                   `json:"res_id" validate:"omitempty"`
+	Currency      enumor.CurrencyCode      `json:"currency" validate:"required"`
+	Cost          decimal.Decimal          `json:"cost" validate:"omitempty"`
+	Count         int64                    `json:"count" validate:"omitempty"`
+}
+
+// Validate ...
+func (c *BillResourceCostCreateReq) Validate() error {
+	return validator.Validate.Struct(c)
+}
+
+// BillResourceCostListReq list request
+type BillResourceCostListReq = core.ListReq
+
+// BillResourceCostListResult list result
+type BillResourceCostListResult = core.ListResultT[*bill.ResourceCost]
+
+// BillResourceCostSumListResult list result of resource cost grouped by resource
+type BillResourceCostSumListResult = core.ListResultT[*bill.ResourceCostSum]
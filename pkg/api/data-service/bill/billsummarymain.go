@@ -21,6 +21,7 @@ package bill
 
 import (
 	"hcm/pkg/api/core"
+	billcore "hcm/pkg/api/core/bill"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 	"hcm/pkg/dal/table/types"
@@ -108,6 +109,9 @@ type BillSummaryMain struct {
 	State                     enumor.MainBillSummaryState `json:"state"`
 	CreatedAt                 types.Time                  `json:"created_at,omitempty"`
 	UpdatedAt                 types.Time                  `json:"updated_at,omitempty"`
+	billcore.CostForecast     `json:",inline"`
+	// CurrencyCostForecast 原币种费用预测
+	billcore.CurrencyCostForecast `json:",inline"`
 }
 
 // BillSummaryMainUpdateReq ...
@@ -131,6 +135,10 @@ type BillSummaryMainUpdateReq struct {
 	AdjustmentRMBCost         *decimal.Decimal            `json:"adjustment_rmb_cost" validate:"omitempty"`
 	Rate                      float64                     `json:"rate" validate:"omitempty"`
 	State                     enumor.MainBillSummaryState `json:"state" validate:"omitempty"`
+	// CostForecast 人民币费用预测，缺少汇率时为空，不更新
+	*billcore.CostForecast `json:",inline"`
+	// CurrencyCostForecast 原币种费用预测
+	*billcore.CurrencyCostForecast `json:",inline"`
 }
 
 // Validate ...
@@ -153,4 +161,5 @@ type BillSummaryBizResult struct {
 	CurrentMonthRMBCost       decimal.Decimal `json:"current_month_rmb_cost" validate:"omitempty"`
 	AdjustmentCost            decimal.Decimal `json:"adjustment_cost" validate:"omitempty"`
 	AdjustmentRMBCost         decimal.Decimal `json:"adjustment_rmb_cost" validate:"omitempty"`
+	billcore.CostForecast     `json:",inline"`
 }
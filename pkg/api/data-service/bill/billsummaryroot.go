@@ -96,6 +96,9 @@ type BillSummaryRootUpdateReq struct {
 	BkBizNum                  uint64                      `json:"bk_biz_num"`
 	ProductNum                uint64                      `json:"product_num"`
 	State                     enumor.RootBillSummaryState `json:"state"`
+	*bill.CostForecast        `json:",inline"`
+	// CurrencyCostForecast 原币种费用预测
+	*bill.CurrencyCostForecast `json:",inline"`
 }
 
 // Validate ...
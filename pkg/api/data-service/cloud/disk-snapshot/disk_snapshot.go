@@ -0,0 +1,99 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot ...
+package disksnapshot
+
+import (
+	"errors"
+
+	coresnapshot "hcm/pkg/api/core/cloud/disk-snapshot"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// BatchCreateReq define batch create disk snapshot request.
+type BatchCreateReq struct {
+	Snapshots []CreateReq `json:"snapshots" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate BatchCreateReq.
+func (req *BatchCreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// CreateReq define create disk snapshot request.
+type CreateReq struct {
+	Vendor           enumor.Vendor           `json:"vendor" validate:"required"`
+	AccountID        string                  `json:"account_id" validate:"required"`
+	CloudID          string                  `json:"cloud_id" validate:"required"`
+	Name             string                  `json:"name"`
+	Region           string                  `json:"region"`
+	Zone             string                  `json:"zone"`
+	DiskID           string                  `json:"disk_id"`
+	CloudDiskID      string                  `json:"cloud_disk_id"`
+	DiskSize         uint64                  `json:"disk_size"`
+	Status           string                  `json:"status"`
+	BkBizID          int64                   `json:"bk_biz_id"`
+	CloudCreatedTime string                  `json:"cloud_created_time"`
+	Extension        *coresnapshot.Extension `json:"extension"`
+	Memo             *string                 `json:"memo" validate:"omitempty,max=255"`
+}
+
+// BatchUpdateReq define batch update disk snapshot request.
+type BatchUpdateReq struct {
+	Snapshots []UpdateReq `json:"snapshots" validate:"required,min=1,max=100,dive"`
+}
+
+// Validate BatchUpdateReq.
+func (req *BatchUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, one := range req.Snapshots {
+		if one.BkBizID != 0 && one.BkBizID < constant.UnassignedBiz {
+			return errors.New("bk_biz_id is invalid")
+		}
+	}
+
+	return nil
+}
+
+// UpdateReq define update disk snapshot request, zero value fields will not be updated except disk_id.
+type UpdateReq struct {
+	ID     string `json:"id" validate:"required"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// DiskID 源硬盘被删除后需要清空关联的硬盘ID，所以 disk_id 字段总是会被更新
+	DiskID    string                  `json:"disk_id"`
+	DiskSize  uint64                  `json:"disk_size"`
+	BkBizID   int64                   `json:"bk_biz_id"`
+	Extension *coresnapshot.Extension `json:"extension"`
+	Memo      *string                 `json:"memo" validate:"omitempty,max=255"`
+	// BackupPolicyID 备份策略创建的快照同步到 db 后设置
+	BackupPolicyID string `json:"backup_policy_id" validate:"omitempty,max=64"`
+}
+
+// ListResult define list disk snapshot result.
+type ListResult struct {
+	Count   uint64                      `json:"count"`
+	Details []coresnapshot.DiskSnapshot `json:"details"`
+}
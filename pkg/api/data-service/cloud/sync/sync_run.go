@@ -0,0 +1,107 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package dssync
+
+import (
+	"errors"
+	"time"
+
+	coresync "hcm/pkg/api/core/cloud/sync"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// SyncRunBatchCreateReq define batch create sync run request.
+type SyncRunBatchCreateReq struct {
+	Items []SyncRunCreateField `json:"items" validate:"required,min=1,max=100"`
+}
+
+// Validate SyncRunBatchCreateReq.
+func (req SyncRunBatchCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, item := range req.Items {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SyncRunCreateField define sync run create field.
+type SyncRunCreateField struct {
+	Vendor      enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID   string                   `json:"account_id" validate:"required"`
+	Region      string                   `json:"region" validate:"omitempty"`
+	ResType     enumor.CloudResourceType `json:"res_type" validate:"required"`
+	TriggerType enumor.SyncTrigger       `json:"trigger_type" validate:"required"`
+	Status      enumor.SyncStatus        `json:"status" validate:"required"`
+	CreateCount uint                     `json:"create_count" validate:"omitempty"`
+	UpdateCount uint                     `json:"update_count" validate:"omitempty"`
+	DeleteCount uint                     `json:"delete_count" validate:"omitempty"`
+	DurationMs  uint64                   `json:"duration_ms" validate:"omitempty"`
+	Reason      string                   `json:"reason" validate:"omitempty"`
+	StartAt     time.Time                `json:"start_at" validate:"required"`
+	EndAt       time.Time                `json:"end_at" validate:"required"`
+}
+
+// Validate SyncRunCreateField.
+func (req SyncRunCreateField) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.TriggerType.Validate()
+}
+
+// SyncRunDeleteExpiredReq define delete expired sync run request.
+type SyncRunDeleteExpiredReq struct {
+	// Before 删除该时间之前创建的执行记录
+	Before time.Time `json:"before" validate:"required"`
+	// Limit 单次最多删除的记录数
+	Limit uint `json:"limit" validate:"required,min=1,max=5000"`
+}
+
+// Validate SyncRunDeleteExpiredReq.
+func (req SyncRunDeleteExpiredReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if req.Before.After(time.Now()) {
+		return errors.New("before can not be later than now")
+	}
+
+	return nil
+}
+
+// SyncRunDeleteExpiredResult define delete expired sync run result.
+type SyncRunDeleteExpiredResult struct {
+	DeletedCount uint `json:"deleted_count"`
+}
+
+// SyncRunListResult defines list sync run result.
+type SyncRunListResult struct {
+	Count   uint64             `json:"count"`
+	Details []coresync.SyncRun `json:"details"`
+}
@@ -0,0 +1,126 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package dstag ...
+package dstag
+
+import (
+	"errors"
+
+	coretag "hcm/pkg/api/core/cloud/tag"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// ResTagSyncReq define sync resource tag request, the tags of each resource will be replaced
+// with the given tags, resources are specified by cloud id.
+type ResTagSyncReq struct {
+	Vendor    enumor.Vendor            `json:"vendor" validate:"required"`
+	AccountID string                   `json:"account_id" validate:"required"`
+	ResType   enumor.CloudResourceType `json:"res_type" validate:"required"`
+	Items     []ResTagSyncItem         `json:"items" validate:"required,min=1,max=500"`
+}
+
+// Validate ResTagSyncReq.
+func (req ResTagSyncReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := req.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if err := coretag.ValidateResType(req.ResType); err != nil {
+		return err
+	}
+
+	for _, item := range req.Items {
+		if err := item.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ResTagSyncItem define sync resource tag item.
+type ResTagSyncItem struct {
+	CloudID string         `json:"cloud_id" validate:"required"`
+	Tags    coretag.TagMap `json:"tags" validate:"omitempty"`
+}
+
+// Validate ResTagSyncItem.
+func (item ResTagSyncItem) Validate() error {
+	if err := validator.Validate.Struct(item); err != nil {
+		return err
+	}
+
+	return item.Tags.Validate()
+}
+
+// ResTagBatchUpsertReq define batch upsert resource tag request, tags with the same key will be overwritten,
+// and other tags of the resource remain unchanged.
+type ResTagBatchUpsertReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResIDs  []string                 `json:"res_ids" validate:"required,min=1,max=100"`
+	Tags    coretag.TagMap           `json:"tags" validate:"required,min=1,max=50"`
+}
+
+// Validate ResTagBatchUpsertReq.
+func (req ResTagBatchUpsertReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := coretag.ValidateResType(req.ResType); err != nil {
+		return err
+	}
+
+	return req.Tags.Validate()
+}
+
+// ResTagBatchDeleteReq define batch delete resource tag request.
+type ResTagBatchDeleteReq struct {
+	ResType enumor.CloudResourceType `json:"res_type" validate:"required"`
+	ResIDs  []string                 `json:"res_ids" validate:"required,min=1,max=100"`
+	// Keys 需要删除的标签键，为空时删除资源的所有标签
+	Keys []string `json:"keys" validate:"omitempty,max=50"`
+}
+
+// Validate ResTagBatchDeleteReq.
+func (req ResTagBatchDeleteReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for _, key := range req.Keys {
+		if len(key) == 0 {
+			return errors.New("tag key can not be empty")
+		}
+	}
+
+	return coretag.ValidateResType(req.ResType)
+}
+
+// ResTagListResult defines list resource tag result.
+type ResTagListResult struct {
+	Count   uint64           `json:"count"`
+	Details []coretag.ResTag `json:"details"`
+}
@@ -0,0 +1,134 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package diskbackup ...
+package diskbackup
+
+import (
+	"errors"
+
+	corebackup "hcm/pkg/api/core/disk-backup"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// CreateReq define create disk backup policy request.
+type CreateReq struct {
+	Name           string                   `json:"name" validate:"required,max=64"`
+	BkBizID        int64                    `json:"bk_biz_id" validate:"required,min=1"`
+	Spec           string                   `json:"spec" validate:"required,max=128"`
+	RetentionCount uint                     `json:"retention_count" validate:"required,min=1,max=100"`
+	Target         *corebackup.BackupTarget `json:"target" validate:"required"`
+	AlertReceivers []string                 `json:"alert_receivers" validate:"omitempty,max=20"`
+	Enabled        *bool                    `json:"enabled" validate:"required"`
+	Memo           *string                  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate CreateReq.
+func (req *CreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.Target.Validate()
+}
+
+// UpdateReq define update disk backup policy request, bk_biz_id of policy can not be updated.
+type UpdateReq struct {
+	Name            string                   `json:"name" validate:"omitempty,max=64"`
+	Spec            string                   `json:"spec" validate:"omitempty,max=128"`
+	RetentionCount  *uint                    `json:"retention_count" validate:"omitempty,min=1,max=100"`
+	Target          *corebackup.BackupTarget `json:"target" validate:"omitempty"`
+	AlertReceivers  []string                 `json:"alert_receivers" validate:"omitempty,max=20"`
+	Enabled         *bool                    `json:"enabled"`
+	ScheduledFlowID string                   `json:"scheduled_flow_id" validate:"omitempty,max=64"`
+	Memo            *string                  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate UpdateReq.
+func (req *UpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if req.Target != nil {
+		return req.Target.Validate()
+	}
+
+	return nil
+}
+
+// ListResult define list disk backup policy result.
+type ListResult struct {
+	Count   uint64                        `json:"count"`
+	Details []corebackup.DiskBackupPolicy `json:"details"`
+}
+
+// RunCreateReq define create disk backup run request, the run starts when it is created.
+type RunCreateReq struct {
+	PolicyID    string                   `json:"policy_id" validate:"required"`
+	BkBizID     int64                    `json:"bk_biz_id" validate:"required"`
+	TriggerType enumor.DiskBackupTrigger `json:"trigger_type" validate:"required"`
+}
+
+// Validate RunCreateReq.
+func (req *RunCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	return req.TriggerType.Validate()
+}
+
+// RunUpdateReq define update disk backup run request.
+type RunUpdateReq struct {
+	State         enumor.DiskBackupRunState `json:"state"`
+	TargetCount   *uint                     `json:"target_count"`
+	SuccessCount  *uint                     `json:"success_count"`
+	FailedCount   *uint                     `json:"failed_count"`
+	PrunedCount   *uint                     `json:"pruned_count"`
+	FailedDetails []corebackup.FailedDisk   `json:"failed_details" validate:"omitempty,max=1000"`
+	Reason        *string                   `json:"reason"`
+	Alerted       *bool                     `json:"alerted"`
+	// Finished 为 true 时按开始时间更新备份耗时
+	Finished bool `json:"finished"`
+}
+
+// Validate RunUpdateReq.
+func (req *RunUpdateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.State) != 0 {
+		return req.State.Validate()
+	}
+
+	if req.Finished {
+		return errors.New("state is required when run is finished")
+	}
+
+	return nil
+}
+
+// RunListResult define list disk backup run result.
+type RunListResult struct {
+	Count   uint64                     `json:"count"`
+	Details []corebackup.DiskBackupRun `json:"details"`
+}
@@ -0,0 +1,96 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package dsipam ...
+package dsipam
+
+import (
+	"fmt"
+
+	coreipam "hcm/pkg/api/core/ipam"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/tools/cidr"
+)
+
+// BatchCreateReq define batch create ipam block request.
+type BatchCreateReq struct {
+	Blocks []BlockCreate `json:"blocks" validate:"required,min=1,max=500,dive"`
+}
+
+// Validate BatchCreateReq.
+func (req *BatchCreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	for i := range req.Blocks {
+		if err := req.Blocks[i].Validate(); err != nil {
+			return fmt.Errorf("blocks[%d] is invalid, err: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// BlockCreate define ipam block create info.
+type BlockCreate struct {
+	Type      enumor.IPAMBlockType `json:"type" validate:"required"`
+	ResID     string               `json:"res_id" validate:"omitempty,max=64"`
+	Vendor    enumor.Vendor        `json:"vendor" validate:"omitempty"`
+	AccountID string               `json:"account_id" validate:"omitempty,max=64"`
+	Region    string               `json:"region" validate:"omitempty,max=255"`
+	VpcID     string               `json:"vpc_id" validate:"omitempty,max=64"`
+	BkCloudID int64                `json:"bk_cloud_id"`
+	BkBizID   int64                `json:"bk_biz_id"`
+	Cidr      string               `json:"cidr" validate:"required,max=64"`
+	Memo      *string              `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate BlockCreate.
+func (b *BlockCreate) Validate() error {
+	if err := validator.Validate.Struct(b); err != nil {
+		return err
+	}
+
+	if err := b.Type.Validate(); err != nil {
+		return err
+	}
+
+	if b.Type != enumor.IPAMReservedBlock && len(b.ResID) == 0 {
+		return fmt.Errorf("res_id is required when type is %s", b.Type)
+	}
+
+	if b.BkCloudID != constant.UnbindBkCloudID && b.BkCloudID < 0 {
+		return fmt.Errorf("bk_cloud_id %d is invalid", b.BkCloudID)
+	}
+
+	if _, err := cidr.CidrIPAddressType(b.Cidr); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListResult define list ipam block result.
+type ListResult struct {
+	Count   uint64           `json:"count"`
+	Details []coreipam.Block `json:"details"`
+}
@@ -0,0 +1,77 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package dslock ...
+package dslock
+
+import (
+	corelock "hcm/pkg/api/core/lock"
+	"hcm/pkg/criteria/validator"
+)
+
+// AcquireReq define acquire distributed lock request.
+type AcquireReq struct {
+	Key    string `json:"key" validate:"required,max=255"`
+	Owner  string `json:"owner" validate:"required,max=255"`
+	TTLSec int64  `json:"ttl_sec" validate:"required,min=1,max=86400"`
+}
+
+// Validate AcquireReq.
+func (req AcquireReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// RenewReq define renew distributed lock request.
+type RenewReq struct {
+	Key    string `json:"key" validate:"required,max=255"`
+	Token  int64  `json:"token" validate:"required,min=1"`
+	TTLSec int64  `json:"ttl_sec" validate:"required,min=1,max=86400"`
+}
+
+// Validate RenewReq.
+func (req RenewReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ReleaseReq define release distributed lock request.
+type ReleaseReq struct {
+	Key   string `json:"key" validate:"required,max=255"`
+	Token int64  `json:"token" validate:"required,min=1"`
+}
+
+// Validate ReleaseReq.
+func (req ReleaseReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ForceReleaseReq define force release distributed lock request.
+type ForceReleaseReq struct {
+	Key string `json:"key" validate:"required,max=255"`
+}
+
+// Validate ForceReleaseReq.
+func (req ForceReleaseReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ListResult defines list distributed lock result.
+type ListResult struct {
+	Count   uint64              `json:"count"`
+	Details []corelock.LockInfo `json:"details"`
+}
@@ -0,0 +1,117 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package recyclepolicy ...
+package recyclepolicy
+
+import (
+	"fmt"
+
+	corerp "hcm/pkg/api/core/recycle-policy"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+)
+
+// CreateReq define create recycle policy request.
+type CreateReq struct {
+	BkBizID               int64                    `json:"bk_biz_id" validate:"required"`
+	ResType               enumor.CloudResourceType `json:"res_type" validate:"required"`
+	RetentionHour         uint                     `json:"retention_hour" validate:"required,max=720"`
+	DetachBeforeRecycle   bool                     `json:"detach_before_recycle"`
+	SnapshotBeforeDestroy bool                     `json:"snapshot_before_destroy"`
+	NotifyBeforeHour      uint                     `json:"notify_before_hour"`
+	Enabled               *bool                    `json:"enabled" validate:"required"`
+	Memo                  *string                  `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate CreateReq.
+func (req *CreateReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if req.BkBizID <= 0 && req.BkBizID != constant.AttachedAllBiz {
+		return fmt.Errorf("bk_biz_id %d is invalid", req.BkBizID)
+	}
+
+	if _, exist := enumor.RecyclePolicyResTypes[req.ResType]; !exist {
+		return fmt.Errorf("res_type %s does not support recycle policy", req.ResType)
+	}
+
+	return validateOptions(req.ResType, req.RetentionHour, req.DetachBeforeRecycle, req.SnapshotBeforeDestroy,
+		req.NotifyBeforeHour)
+}
+
+// UpdateReq define update recycle policy request, bk_biz_id and res_type of policy can not be updated.
+type UpdateReq struct {
+	RetentionHour         *uint   `json:"retention_hour" validate:"omitempty,min=1,max=720"`
+	DetachBeforeRecycle   *bool   `json:"detach_before_recycle"`
+	SnapshotBeforeDestroy *bool   `json:"snapshot_before_destroy"`
+	NotifyBeforeHour      *uint   `json:"notify_before_hour"`
+	Enabled               *bool   `json:"enabled"`
+	Memo                  *string `json:"memo" validate:"omitempty,max=255"`
+}
+
+// Validate UpdateReq.
+func (req *UpdateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// ValidateWith validate UpdateReq merged with the policy to be updated.
+func (req *UpdateReq) ValidateWith(policy *corerp.RecyclePolicy) error {
+	merged := *policy
+	if req.RetentionHour != nil {
+		merged.RetentionHour = *req.RetentionHour
+	}
+	if req.DetachBeforeRecycle != nil {
+		merged.DetachBeforeRecycle = *req.DetachBeforeRecycle
+	}
+	if req.SnapshotBeforeDestroy != nil {
+		merged.SnapshotBeforeDestroy = *req.SnapshotBeforeDestroy
+	}
+	if req.NotifyBeforeHour != nil {
+		merged.NotifyBeforeHour = *req.NotifyBeforeHour
+	}
+
+	return validateOptions(merged.ResType, merged.RetentionHour, merged.DetachBeforeRecycle,
+		merged.SnapshotBeforeDestroy, merged.NotifyBeforeHour)
+}
+
+func validateOptions(resType enumor.CloudResourceType, retention uint, detach, snapshot bool, notify uint) error {
+	if detach && resType != enumor.CvmCloudResType && resType != enumor.EipCloudResType {
+		return fmt.Errorf("detach_before_recycle is not supported by %s", resType)
+	}
+
+	if snapshot && resType != enumor.CvmCloudResType && resType != enumor.DiskCloudResType {
+		return fmt.Errorf("snapshot_before_destroy is not supported by %s", resType)
+	}
+
+	if notify > 0 && notify >= retention {
+		return fmt.Errorf("notify_before_hour should be less than retention_hour %d", retention)
+	}
+
+	return nil
+}
+
+// ListResult define list recycle policy result.
+type ListResult struct {
+	Count   uint64                 `json:"count"`
+	Details []corerp.RecyclePolicy `json:"details"`
+}
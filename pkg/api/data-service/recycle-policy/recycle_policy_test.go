@@ -0,0 +1,102 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package recyclepolicy
+
+import (
+	"testing"
+
+	corerp "hcm/pkg/api/core/recycle-policy"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/tools/converter"
+)
+
+func TestCreateReqValidateOptions(t *testing.T) {
+	cases := []struct {
+		name    string
+		req     CreateReq
+		wantErr bool
+	}{
+		{
+			name: "cvm detach and snapshot",
+			req: CreateReq{BkBizID: 1, ResType: enumor.CvmCloudResType, RetentionHour: 48,
+				DetachBeforeRecycle: true, SnapshotBeforeDestroy: true, NotifyBeforeHour: 24},
+		},
+		{
+			name: "disk snapshot",
+			req:  CreateReq{BkBizID: 1, ResType: enumor.DiskCloudResType, RetentionHour: 48, SnapshotBeforeDestroy: true},
+		},
+		{
+			name: "eip snapshot",
+			req: CreateReq{BkBizID: 1, ResType: enumor.EipCloudResType, RetentionHour: 48,
+				SnapshotBeforeDestroy: true},
+			wantErr: true,
+		},
+		{
+			name: "load balancer detach",
+			req: CreateReq{BkBizID: 1, ResType: enumor.LoadBalancerCloudResType, RetentionHour: 48,
+				DetachBeforeRecycle: true},
+			wantErr: true,
+		},
+		{
+			name: "load balancer snapshot",
+			req: CreateReq{BkBizID: 1, ResType: enumor.LoadBalancerCloudResType, RetentionHour: 48,
+				SnapshotBeforeDestroy: true},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported res type",
+			req:     CreateReq{BkBizID: 1, ResType: enumor.VpcCloudResType, RetentionHour: 48},
+			wantErr: true,
+		},
+		{
+			name: "notify not less than retention",
+			req: CreateReq{BkBizID: 1, ResType: enumor.DiskCloudResType, RetentionHour: 24,
+				NotifyBeforeHour: 24},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		c.req.Enabled = converter.ValToPtr(true)
+		err := c.req.Validate()
+		if (err != nil) != c.wantErr {
+			t.Errorf("case %s: expect error %v, but got: %v", c.name, c.wantErr, err)
+		}
+	}
+}
+
+func TestUpdateReqValidateWith(t *testing.T) {
+	policy := &corerp.RecyclePolicy{ResType: enumor.EipCloudResType, RetentionHour: 48}
+
+	req := &UpdateReq{SnapshotBeforeDestroy: converter.ValToPtr(true)}
+	if err := req.ValidateWith(policy); err == nil {
+		t.Errorf("snapshot_before_destroy of eip policy should be rejected")
+	}
+
+	req = &UpdateReq{RetentionHour: converter.ValToPtr(uint(12)), NotifyBeforeHour: converter.ValToPtr(uint(24))}
+	if err := req.ValidateWith(policy); err == nil {
+		t.Errorf("notify_before_hour not less than retention_hour should be rejected")
+	}
+
+	req = &UpdateReq{DetachBeforeRecycle: converter.ValToPtr(true)}
+	if err := req.ValidateWith(policy); err != nil {
+		t.Errorf("detach_before_recycle of eip policy should be accepted, err: %v", err)
+	}
+}
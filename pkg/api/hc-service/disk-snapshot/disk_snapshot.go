@@ -0,0 +1,70 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package disksnapshot ...
+package disksnapshot
+
+import (
+	"hcm/pkg/criteria/validator"
+)
+
+// CreateReq define create disk snapshot request, snapshot creation is idempotent by name, if snapshot with the same
+// name of the disk already exists, it will not be created again and its latest status will be returned.
+type CreateReq struct {
+	// DiskID 源硬盘在hcm中的ID
+	DiskID string `json:"disk_id" validate:"required"`
+	// Name 快照名称，为空时自动生成，需要保证幂等时必须指定
+	Name string  `json:"name" validate:"omitempty,max=60"`
+	Memo *string `json:"memo" validate:"omitempty,max=255"`
+	// BackupPolicyID 由硬盘备份策略创建时设置，用于按策略清理过期快照
+	BackupPolicyID string `json:"backup_policy_id" validate:"omitempty,max=64"`
+}
+
+// Validate CreateReq.
+func (req *CreateReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// CreateResult define create disk snapshot result.
+type CreateResult struct {
+	ID      string `json:"id"`
+	CloudID string `json:"cloud_id"`
+	// Status 快照当前的云上状态，快照创建耗时较长，创建接口不等待快照创建完成
+	Status string `json:"status"`
+}
+
+// DeleteReq define delete disk snapshot request.
+type DeleteReq struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// Validate DeleteReq.
+func (req *DeleteReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// RollbackReq define rollback disk by snapshot request.
+type RollbackReq struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// Validate RollbackReq.
+func (req *RollbackReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
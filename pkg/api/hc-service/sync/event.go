@@ -0,0 +1,43 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"encoding/json"
+
+	"hcm/pkg/criteria/validator"
+)
+
+// PushEventReq 推送云上变更事件请求，Records 为云厂商原始事件记录，如 AWS CloudTrail 事件、腾讯云操作审计事件。
+type PushEventReq struct {
+	AccountID string            `json:"account_id" validate:"required"`
+	Records   []json.RawMessage `json:"records" validate:"required,min=1,max=500"`
+}
+
+// Validate push event request.
+func (req *PushEventReq) Validate() error {
+	return validator.Validate.Struct(req)
+}
+
+// PushEventResult 推送云上变更事件结果。
+type PushEventResult struct {
+	// EventCount 解析出的资源变更事件数，只读操作、失败的操作以及不支持的资源类型不计入
+	EventCount int `json:"event_count"`
+}
@@ -0,0 +1,75 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package sync
+
+import (
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/rest"
+)
+
+// PlanAction 同步计划中资源的变更动作
+type PlanAction string
+
+const (
+	// PlanCreate 云上存在、本地不存在，同步时将被创建
+	PlanCreate PlanAction = "create"
+	// PlanUpdate 云上和本地均存在且字段发生变化，同步时将被更新
+	PlanUpdate PlanAction = "update"
+	// PlanDelete 云上已删除、本地仍存在，同步时将被删除
+	PlanDelete PlanAction = "delete"
+)
+
+// PlanFieldDiff 资源字段级别的差异，Source 为云上的值，Target 为本地的值。
+type PlanFieldDiff struct {
+	Field  string      `json:"field"`
+	Source interface{} `json:"source"`
+	Target interface{} `json:"target"`
+}
+
+// PlanChange 同步计划中单个资源的变更。
+type PlanChange struct {
+	ResType enumor.CloudResourceType `json:"res_type"`
+	Action  PlanAction               `json:"action"`
+	CloudID string                   `json:"cloud_id,omitempty"`
+	// ID 本地资源ID，新增的资源没有本地ID
+	ID     string          `json:"id,omitempty"`
+	Fields []PlanFieldDiff `json:"fields,omitempty"`
+}
+
+// PlanSummary 同步计划变更统计。
+type PlanSummary struct {
+	CreateCount int `json:"create_count"`
+	UpdateCount int `json:"update_count"`
+	DeleteCount int `json:"delete_count"`
+}
+
+// PlanResult 同步计划结果，仅描述同步将产生的变更，不会对本地数据做任何修改。
+type PlanResult struct {
+	Vendor  enumor.Vendor            `json:"vendor"`
+	ResType enumor.CloudResourceType `json:"res_type"`
+	Summary PlanSummary              `json:"summary"`
+	Changes []PlanChange             `json:"changes"`
+}
+
+// PlanResp 同步计划返回。
+type PlanResp struct {
+	rest.BaseResp `json:",inline"`
+	Data          *PlanResult `json:"data"`
+}
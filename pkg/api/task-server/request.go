@@ -20,6 +20,10 @@
 package taskserver
 
 import (
+	"errors"
+	"strconv"
+
+	"hcm/pkg/api/core"
 	"hcm/pkg/async/action"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
@@ -36,6 +40,24 @@ type AddTemplateFlowReq struct {
 	Tasks []TemplateFlowTask `json:"tasks" validate:"required, min=1"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// TimeoutSec 任务流超时时间（秒），从创建开始计算，超时仍未结束的任务流会被置为失败或取消，0表示不限制
+	TimeoutSec uint `json:"timeout_sec" validate:"omitempty"`
+	// SLASec 任务流SLA时间（秒），从创建开始计算，超过该时间仍未结束的任务流会触发告警，0表示不告警
+	SLASec uint `json:"sla_sec" validate:"omitempty"`
+	// Priority 任务流优先级，数值越大越优先派发，取值范围[-100, 100]，默认为0
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// FairnessKey 任务流公平键（如 account:xxx、biz:xxx），不同公平键之间按照权重公平派发，并受公平键并发上限限制
+	FairnessKey string `json:"fairness_key" validate:"omitempty,lte=64"`
+}
+
+// AccountFairnessKey 生成以账号为维度的任务流公平键
+func AccountFairnessKey(accountID string) string {
+	return "account:" + accountID
+}
+
+// BizFairnessKey 生成以业务为维度的任务流公平键
+func BizFairnessKey(bizID int64) string {
+	return "biz:" + strconv.FormatInt(bizID, 10)
 }
 
 // Validate AddTemplateFlowReq
@@ -45,6 +67,10 @@ func (req *AddTemplateFlowReq) Validate() error {
 		return err
 	}
 
+	if err := req.Priority.Validate(); err != nil {
+		return err
+	}
+
 	for _, task := range req.Tasks {
 		if err := task.Validate(); err != nil {
 			return err
@@ -79,6 +105,16 @@ type AddCustomFlowReq struct {
 	Tasks []CustomFlowTask `json:"tasks" validate:"omitempty"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// TimeoutSec 任务流超时时间（秒），从创建开始计算，超时仍未结束的任务流会被置为失败或取消，0表示不限制
+	TimeoutSec uint `json:"timeout_sec" validate:"omitempty"`
+	// SLASec 任务流SLA时间（秒），从创建开始计算，超过该时间仍未结束的任务流会触发告警，0表示不告警
+	SLASec uint `json:"sla_sec" validate:"omitempty"`
+	// Priority 任务流优先级，数值越大越优先派发，取值范围[-100, 100]，默认为0
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// FairnessKey 任务流公平键（如 account:xxx、biz:xxx），不同公平键之间按照权重公平派发，并受公平键并发上限限制
+	FairnessKey string `json:"fairness_key" validate:"omitempty,lte=64"`
+	// CompensateOnFailure 是否开启补偿模式，开启后任务流执行失败时，会按照依赖关系的逆序对执行成功的任务执行补偿动作
+	CompensateOnFailure bool `json:"compensate_on_failure,omitempty" validate:"omitempty"`
 }
 
 // Validate AddCustomFlowReq
@@ -88,6 +124,10 @@ func (opt *AddCustomFlowReq) Validate() error {
 		return err
 	}
 
+	if err := opt.Priority.Validate(); err != nil {
+		return err
+	}
+
 	for _, task := range opt.Tasks {
 		if err := task.Validate(); err != nil {
 			return err
@@ -107,6 +147,12 @@ type CustomFlowTask struct {
 	Params interface{} `json:"params" validate:"omitempty"`
 	// DependOn 运行当前Action依赖的前置ActionID
 	DependOn []action.ActIDType `json:"depend_on" validate:"omitempty"`
+	// Kind 任务类型，默认为action。为child_flow时，ActionName需为run_child_flow，Params为 action.ChildFlowParams
+	Kind enumor.TaskKind `json:"kind,omitempty" validate:"omitempty"`
+	// Condition 任务执行条件，条件不成立时任务会被跳过
+	Condition *tableasync.Condition `json:"condition,omitempty" validate:"omitempty"`
+	// CompensateAction 补偿动作，任务流开启补偿模式且执行失败后，使用相同的请求参数执行，用于撤销该任务已经执行成功的操作
+	CompensateAction enumor.ActionName `json:"compensate_action,omitempty" validate:"omitempty"`
 
 	// Retry 任务运行重试相关配置参数，如果不设置，默认不允许进行重试。
 	Retry *tableasync.Retry `json:"retry" validate:"omitempty"`
@@ -116,3 +162,61 @@ type CustomFlowTask struct {
 func (task *CustomFlowTask) Validate() error {
 	return validator.Validate.Struct(task)
 }
+
+// RegisterScheduledFlowReq define register scheduled flow request.
+type RegisterScheduledFlowReq struct {
+	// Name 定时任务流名称，全局唯一，重复注册会更新已有的定时任务流
+	Name string `json:"name" validate:"required,lte=64"`
+	// FlowName 任务流模版名称
+	FlowName enumor.FlowName `json:"flow_name" validate:"required"`
+	// Spec 标准5段式cron表达式（分 时 日 月 周），如：0 2 * * *，也支持 @daily、@every 1h 等描述符
+	Spec string `json:"spec" validate:"required,lte=128"`
+	// Memo 备注
+	Memo string `json:"memo" validate:"omitempty,lte=255"`
+	// Tasks 任务私有化参数设置
+	Tasks []TemplateFlowTask `json:"tasks" validate:"omitempty"`
+}
+
+// Validate RegisterScheduledFlowReq
+func (req *RegisterScheduledFlowReq) Validate() error {
+
+	if err := req.FlowName.Validate(); err != nil {
+		return err
+	}
+
+	for _, task := range req.Tasks {
+		if err := task.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return validator.Validate.Struct(req)
+}
+
+// ListTaskLogReq define list task log request.
+type ListTaskLogReq struct {
+	// Attempt 只查询指定执行次数的日志，为0时查询全部
+	Attempt uint `json:"attempt" validate:"omitempty"`
+	// Level 只查询指定级别的日志，为空时查询全部
+	Level enumor.TaskLogLevel `json:"level" validate:"omitempty"`
+	Page  *core.BasePage      `json:"page" validate:"required"`
+}
+
+// Validate ListTaskLogReq
+func (req *ListTaskLogReq) Validate() error {
+	if err := validator.Validate.Struct(req); err != nil {
+		return err
+	}
+
+	if len(req.Level) != 0 {
+		if err := req.Level.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if req.Page.Count {
+		return errors.New("task log list not support count")
+	}
+
+	return req.Page.Validate(core.NewDefaultPageOption())
+}
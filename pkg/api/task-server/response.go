@@ -32,3 +32,18 @@ type ListTaskResult struct {
 	Count   uint64                    `json:"count"`
 	Details []coreasync.AsyncFlowTask `json:"details"`
 }
+
+// ListScheduledFlowResult ...
+type ListScheduledFlowResult struct {
+	Details []coreasync.AsyncScheduledFlow `json:"details"`
+}
+
+// ListTaskLogResult ...
+type ListTaskLogResult struct {
+	Details []coreasync.AsyncFlowTaskLog `json:"details"`
+}
+
+// ListTaskAttemptResult ...
+type ListTaskAttemptResult struct {
+	Details []coreasync.AsyncFlowTaskAttempt `json:"details"`
+}
@@ -19,12 +19,26 @@
 
 package run
 
-import "hcm/pkg/kit"
+import (
+	"fmt"
+
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
 
 // ExecuteKit is a kit using by action
 type ExecuteKit interface {
 	Kit() *kit.Kit
 	ShareData() ShareDataOperator
+	Logger() TaskLogger
+}
+
+// TaskLogger 任务执行日志，输出的日志会按照任务维度持久化，可以通过task-server的接口查询，
+// 用于任务执行失败后排查问题，无需再根据rid查询节点日志。
+type TaskLogger interface {
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
 }
 
 // ShareDataOperator used to operate share data
@@ -34,11 +48,17 @@ type ShareDataOperator interface {
 	AppendIDs(kt *kit.Kit, key string, ids ...string) error
 }
 
-// NewExecuteContext new execute context for task exec.
-func NewExecuteContext(kt *kit.Kit, shareData ShareDataOperator) ExecuteKit {
+// NewExecuteContext new execute context for task exec, logger is optional, task log is only output to the
+// node log if logger is nil.
+func NewExecuteContext(kt *kit.Kit, shareData ShareDataOperator, logger TaskLogger) ExecuteKit {
+	if logger == nil {
+		logger = NewKitLogger(kt)
+	}
+
 	return &DefExecuteContext{
 		kit:       kt,
 		shareData: shareData,
+		logger:    logger,
 	}
 }
 
@@ -46,6 +66,7 @@ func NewExecuteContext(kt *kit.Kit, shareData ShareDataOperator) ExecuteKit {
 type DefExecuteContext struct {
 	kit       *kit.Kit
 	shareData ShareDataOperator
+	logger    TaskLogger
 }
 
 // Kit return kit.
@@ -57,3 +78,33 @@ func (ctx *DefExecuteContext) Kit() *kit.Kit {
 func (ctx *DefExecuteContext) ShareData() ShareDataOperator {
 	return ctx.shareData
 }
+
+// Logger return task logger.
+func (ctx *DefExecuteContext) Logger() TaskLogger {
+	return ctx.logger
+}
+
+// NewKitLogger new task logger which only output to the node log with kit's rid.
+func NewKitLogger(kt *kit.Kit) TaskLogger {
+	return &kitLogger{kt: kt}
+}
+
+// kitLogger 只输出到节点日志的任务日志
+type kitLogger struct {
+	kt *kit.Kit
+}
+
+// Infof output info level log.
+func (l *kitLogger) Infof(format string, args ...interface{}) {
+	logs.InfoDepthf(1, "%s, rid: %s", fmt.Sprintf(format, args...), l.kt.Rid)
+}
+
+// Warnf output warn level log.
+func (l *kitLogger) Warnf(format string, args ...interface{}) {
+	logs.Warnf("%s, rid: %s", fmt.Sprintf(format, args...), l.kt.Rid)
+}
+
+// Errorf output error level log.
+func (l *kitLogger) Errorf(format string, args ...interface{}) {
+	logs.ErrorDepthf(1, "%s, rid: %s", fmt.Sprintf(format, args...), l.kt.Rid)
+}
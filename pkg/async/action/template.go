@@ -20,9 +20,13 @@
 package action
 
 import (
+	"fmt"
+
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/tools/slice"
 )
 
 // FlowTemplate 任务流模版定义，用于定义执行任务流模版，用户根据任务流 Name 创建任务流实例去创建异步任务。
@@ -30,6 +34,10 @@ type FlowTemplate struct {
 	Name      enumor.FlowName       `json:"name" validate:"required"`
 	ShareData *tableasync.ShareData `json:"share_data"`
 	Tasks     []TaskTemplate        `json:"tasks" validate:"required,min=1"`
+
+	// CompensateOnFailure 是否开启补偿模式。开启后任务流执行失败且无法继续执行时，会对执行成功且设置了补偿动作的任务，
+	// 按照依赖关系的逆序（依赖该任务的任务补偿完成后才补偿该任务）执行补偿动作，补偿结束后任务流置为失败状态。
+	CompensateOnFailure bool `json:"compensate_on_failure"`
 }
 
 // Validate FlowTemplate.
@@ -56,6 +64,21 @@ type TaskTemplate struct {
 	ActionName enumor.ActionName `json:"action_name" validate:"required"`
 	DependOn   []ActIDType       `json:"depend_on" validate:"omitempty"`
 
+	// Kind 任务类型，默认为action。为child_flow时，ActionName需为run_child_flow，任务执行时会创建 ChildFlow
+	// 模版的子任务流，并等待子任务流执行结束，子任务流成功则任务成功，否则任务失败。
+	Kind enumor.TaskKind `json:"kind" validate:"omitempty"`
+	// ChildFlow 子任务流模版名称，仅child_flow类型任务需要设置。
+	ChildFlow enumor.FlowName `json:"child_flow" validate:"omitempty"`
+
+	// Condition 任务执行条件，可以基于依赖任务的执行结果或任务流共享数据进行判断，条件不成立时任务会被跳过。
+	// 跳过的任务视为执行完成，不影响后续任务的执行。
+	Condition *tableasync.Condition `json:"condition" validate:"omitempty"`
+
+	// CompensateAction 补偿动作，用于撤销该任务已经执行成功的操作，仅在任务流开启补偿模式时生效。
+	// 补偿动作是一个普通的 Action，使用与该任务相同的请求参数执行，可能因为节点重启等原因被重复执行，需要保证幂等。
+	// 子任务流类型的任务不支持设置补偿动作。
+	CompensateAction enumor.ActionName `json:"compensate_action" validate:"omitempty"`
+
 	// Params 异步任务运行请求参数相关控制参数。
 	Params *Params `json:"params" validate:"omitempty"`
 
@@ -63,12 +86,37 @@ type TaskTemplate struct {
 	Retry *tableasync.Retry `json:"retry" validate:"omitempty"`
 }
 
+// GetKind 获取任务类型，未设置时默认为action类型
+func (tpl *TaskTemplate) GetKind() enumor.TaskKind {
+	if len(tpl.Kind) == 0 {
+		return enumor.TaskKindAction
+	}
+
+	return tpl.Kind
+}
+
 // Validate TaskTemplate.
 func (tpl *TaskTemplate) Validate() error {
 	if err := validator.Validate.Struct(tpl); err != nil {
 		return err
 	}
 
+	if err := tpl.validateKind(); err != nil {
+		return err
+	}
+
+	if tpl.Condition != nil {
+		if err := ValidateCondition(tpl.Condition, tpl.DependOn); err != nil {
+			return err
+		}
+	}
+
+	if len(tpl.CompensateAction) != 0 {
+		if err := ValidateCompensateAction(tpl.GetKind(), tpl.CompensateAction); err != nil {
+			return err
+		}
+	}
+
 	if tpl.Retry != nil {
 		if err := tpl.Retry.Validate(); err != nil {
 			return err
@@ -86,6 +134,110 @@ func (tpl *TaskTemplate) Validate() error {
 	return nil
 }
 
+func (tpl *TaskTemplate) validateKind() error {
+	kind := tpl.GetKind()
+	if err := kind.Validate(); err != nil {
+		return err
+	}
+
+	if kind != enumor.TaskKindChildFlow {
+		if tpl.ActionName == enumor.ActionRunChildFlow {
+			return fmt.Errorf("action: %s only can be used by child_flow task", tpl.ActionName)
+		}
+
+		if len(tpl.ChildFlow) != 0 {
+			return fmt.Errorf("task: %s is not child_flow task, child_flow can not set", tpl.ActionID)
+		}
+
+		return nil
+	}
+
+	if tpl.ActionName != enumor.ActionRunChildFlow {
+		return fmt.Errorf("child_flow task: %s action_name should be %s", tpl.ActionID, enumor.ActionRunChildFlow)
+	}
+
+	if len(tpl.ChildFlow) == 0 {
+		return fmt.Errorf("child_flow task: %s child_flow is required", tpl.ActionID)
+	}
+
+	if err := tpl.ChildFlow.Validate(); err != nil {
+		return err
+	}
+
+	if tpl.Retry != nil && tpl.Retry.IsEnable() {
+		return fmt.Errorf("child_flow task: %s not support retry", tpl.ActionID)
+	}
+
+	return nil
+}
+
+// ValidateCondition 校验任务执行条件，条件中引用的任务执行结果必须来自于直接依赖的任务。
+func ValidateCondition(cond *tableasync.Condition, dependOn []ActIDType) error {
+	if err := cond.Validate(); err != nil {
+		return err
+	}
+
+	for _, rule := range cond.Rules {
+		if rule.Source != tableasync.ConditionSourceResult {
+			continue
+		}
+
+		if !slice.IsItemInSlice(dependOn, ActIDType(rule.ActionID)) {
+			return fmt.Errorf("condition rule's action_id: %s should be in depend_on", rule.ActionID)
+		}
+	}
+
+	return nil
+}
+
+// ValidateCompensateAction 校验任务的补偿动作，子任务流类型的任务不支持设置补偿动作。
+func ValidateCompensateAction(kind enumor.TaskKind, compensate enumor.ActionName) error {
+	if err := compensate.Validate(); err != nil {
+		return err
+	}
+
+	if kind == enumor.TaskKindChildFlow {
+		return fmt.Errorf("child_flow task not support compensate_action")
+	}
+
+	if compensate == enumor.ActionRunChildFlow {
+		return fmt.Errorf("action: %s can not be used as compensate_action", compensate)
+	}
+
+	return nil
+}
+
+// ChildFlowParams child_flow 类型任务的请求参数，用于创建子任务流。
+type ChildFlowParams struct {
+	// FlowName 子任务流模版名称，任务流模版中的child_flow任务由 TaskTemplate.ChildFlow 自动设置
+	FlowName enumor.FlowName `json:"flow_name" validate:"required"`
+	// Tasks 子任务流任务私有化参数设置
+	Tasks []ChildFlowTask `json:"tasks" validate:"omitempty"`
+}
+
+// Validate ChildFlowParams.
+func (p ChildFlowParams) Validate() error {
+	if err := validator.Validate.Struct(p); err != nil {
+		return err
+	}
+
+	return p.FlowName.Validate()
+}
+
+// ChildFlowTask 子任务流任务私有化参数
+type ChildFlowTask struct {
+	// ActionID 任务在子任务流模版中的唯一ID
+	ActionID ActIDType `json:"action_id" validate:"required"`
+	// Params 任务执行请求参数
+	Params types.JsonField `json:"params" validate:"required"`
+}
+
+// ChildFlowResult child_flow 类型任务的执行结果
+type ChildFlowResult struct {
+	// ChildFlowID 子任务流ID
+	ChildFlowID string `json:"child_flow_id"`
+}
+
 // Params 异步任务参数相关控制参数
 type Params struct {
 	// Type 参数类型
@@ -21,11 +21,15 @@
 package backend
 
 import (
+	"time"
+
 	"hcm/pkg/api/core"
 	"hcm/pkg/async/backend/model"
 	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/dao/types"
 	typesasync "hcm/pkg/dal/dao/types/async"
 	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
 )
 
 // Backend - a common interface for all backends
@@ -41,6 +45,8 @@ type Backend interface {
 	ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error)
 	// BatchUpdateFlowStateByCAS CAS批量更新Flow状态
 	BatchUpdateFlowStateByCAS(kt *kit.Kit, infos []UpdateFlowInfo) error
+	// DeleteExpiredFlow 删除更新时间早于before的已结束任务流及其任务，单次最多删除limit个任务流，返回删除的数量
+	DeleteExpiredFlow(kt *kit.Kit, before time.Time, limit uint) (uint, error)
 
 	/*
 		Task 相关接口
@@ -56,10 +62,41 @@ type Backend interface {
 
 	// RetryTask 重试任务 将flow置为running, task 置为pending
 	RetryTask(kt *kit.Kit, flowID, taskID string) error
+
+	/*
+		ScheduledFlow 相关接口
+	*/
+	// CreateScheduledFlow 创建定时任务流
+	CreateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) (string, error)
+	// UpdateScheduledFlow 更新定时任务流，仅更新设置了值的字段
+	UpdateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) error
+	// ListScheduledFlow 查询定时任务流
+	ListScheduledFlow(kt *kit.Kit, input *ListInput) ([]model.ScheduledFlow, error)
+	// DeleteScheduledFlow 删除定时任务流
+	DeleteScheduledFlow(kt *kit.Kit, id string) error
+	// TriggerScheduledFlow 触发定时任务流，CAS更新定时任务流的下一次触发时间并创建任务流，两者原子完成，
+	// 保证同一触发时间点只会创建一个任务流
+	TriggerScheduledFlow(kt *kit.Kit, info *TriggerScheduledFlowInfo, flow *model.Flow) (string, error)
+
+	/*
+		TaskLog 相关接口
+	*/
+	// BatchCreateTaskLog 批量创建任务执行日志
+	BatchCreateTaskLog(kt *kit.Kit, taskLogs []model.TaskLog) error
+	// ListTaskLog 查询任务执行日志
+	ListTaskLog(kt *kit.Kit, input *ListInput) ([]model.TaskLog, error)
+	// DeleteExpiredTaskLog 删除创建时间早于before的任务执行日志，单次最多删除limit条，返回删除的数量
+	DeleteExpiredTaskLog(kt *kit.Kit, before time.Time, limit uint) (uint, error)
 }
 
 // ListInput 查询输入参数
-type ListInput core.ListReq
+type ListInput struct {
+	Filter *filter.Expression `json:"filter"`
+	Page   *core.BasePage     `json:"page"`
+	Fields []string           `json:"fields"`
+	// ThenBy Page.Sort 取值相同时依次使用的次级排序，目前只有任务流的查询支持
+	ThenBy []types.OrderBy `json:"then_by"`
+}
 
 // UpdateFlowInfo define update flow info.
 type UpdateFlowInfo typesasync.UpdateFlowInfo
@@ -76,3 +113,17 @@ type UpdateTaskInfo typesasync.UpdateTaskInfo
 func (info *UpdateTaskInfo) Validate() error {
 	return validator.Validate.Struct(info)
 }
+
+// TriggerScheduledFlowInfo define trigger scheduled flow info.
+type TriggerScheduledFlowInfo struct {
+	ID string `json:"id" validate:"required"`
+	// Source 触发前的下一次触发时间
+	Source time.Time `json:"source" validate:"required"`
+	// Target 触发后的下一次触发时间
+	Target time.Time `json:"target" validate:"required"`
+}
+
+// Validate TriggerScheduledFlowInfo
+func (info *TriggerScheduledFlowInfo) Validate() error {
+	return validator.Validate.Struct(info)
+}
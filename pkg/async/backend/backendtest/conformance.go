@@ -0,0 +1,990 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package backendtest 异步任务框架存储后端的一致性测试集，所有 backend.Backend 的实现都需要通过该测试集。
+package backendtest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+	"hcm/pkg/tools/uuid"
+)
+
+// RunConformance 对存储后端执行一致性测试
+func RunConformance(t *testing.T, bd backend.Backend) {
+	t.Run("CreateAndListFlow", func(t *testing.T) { testCreateAndListFlow(t, bd) })
+	t.Run("CreateInitFlow", func(t *testing.T) { testCreateInitFlow(t, bd) })
+	t.Run("FlowDeadline", func(t *testing.T) { testFlowDeadline(t, bd) })
+	t.Run("FlowPriorityAndFairness", func(t *testing.T) { testFlowPriorityAndFairness(t, bd) })
+	t.Run("TaskKindAndCondition", func(t *testing.T) { testTaskKindAndCondition(t, bd) })
+	t.Run("FlowCompensation", func(t *testing.T) { testFlowCompensation(t, bd) })
+	t.Run("FlowStateCAS", func(t *testing.T) { testFlowStateCAS(t, bd) })
+	t.Run("ConcurrentFlowStateCAS", func(t *testing.T) { testConcurrentFlowStateCAS(t, bd) })
+	t.Run("TaskStateCAS", func(t *testing.T) { testTaskStateCAS(t, bd) })
+	t.Run("UpdateTaskAndRetry", func(t *testing.T) { testUpdateTaskAndRetry(t, bd) })
+	t.Run("BatchCreateTaskAndPage", func(t *testing.T) { testBatchCreateTaskAndPage(t, bd) })
+	t.Run("ScheduledFlowCRUD", func(t *testing.T) { testScheduledFlowCRUD(t, bd) })
+	t.Run("ConcurrentTriggerScheduledFlow", func(t *testing.T) { testConcurrentTriggerScheduledFlow(t, bd) })
+	t.Run("TaskLog", func(t *testing.T) { testTaskLog(t, bd) })
+	t.Run("StateListAndExpiredFlow", func(t *testing.T) { testStateListAndExpiredFlow(t, bd) })
+}
+
+func newKit() *kit.Kit {
+	kt := kit.New()
+	kt.User = "conformance"
+	kt.AppCode = "test"
+	return kt
+}
+
+func newFlow(state enumor.FlowState) *model.Flow {
+	return &model.Flow{
+		Name:      enumor.FlowNormalTest,
+		State:     state,
+		ShareData: tableasync.NewShareData(map[string]string{"key": "value"}),
+		Memo:      "conformance",
+		Tasks: []model.Task{
+			{
+				FlowName:   enumor.FlowNormalTest,
+				ActionID:   "1",
+				ActionName: enumor.ActionCreateFactoryTest,
+				Params:     `{"name":"first"}`,
+				Retry:      &tableasync.Retry{Enable: false},
+				State:      enumor.TaskPending,
+			},
+			{
+				FlowName:   enumor.FlowNormalTest,
+				ActionID:   "2",
+				ActionName: enumor.ActionProduceTest,
+				Params:     `{"name":"second"}`,
+				Retry:      &tableasync.Retry{Enable: false},
+				DependOn:   []action.ActIDType{"1"},
+				State:      enumor.TaskPending,
+			},
+		},
+	}
+}
+
+func mustCreateFlow(t *testing.T, bd backend.Backend, state enumor.FlowState) string {
+	flowID, err := bd.CreateFlow(newKit(), newFlow(state))
+	if err != nil {
+		t.Fatalf("create flow failed, err: %v", err)
+	}
+
+	if len(flowID) == 0 {
+		t.Fatalf("create flow returned empty id")
+	}
+
+	return flowID
+}
+
+func mustGetFlow(t *testing.T, bd backend.Backend, flowID string) model.Flow {
+	flows, err := bd.ListFlow(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow failed, err: %v", err)
+	}
+
+	if len(flows) != 1 {
+		t.Fatalf("list flow by id %s expect 1, but got %d", flowID, len(flows))
+	}
+
+	return flows[0]
+}
+
+func mustListTasks(t *testing.T, bd backend.Backend, flowID string) []model.Task {
+	tasks, err := bd.ListTask(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("flow_id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list task failed, err: %v", err)
+	}
+
+	return tasks
+}
+
+func testCreateAndListFlow(t *testing.T, bd backend.Backend) {
+	flowID := mustCreateFlow(t, bd, enumor.FlowPending)
+
+	flow := mustGetFlow(t, bd, flowID)
+	if flow.Name != enumor.FlowNormalTest || flow.State != enumor.FlowPending || flow.Memo != "conformance" {
+		t.Errorf("unexpected flow: %+v", flow)
+	}
+
+	if flow.Worker == nil || len(*flow.Worker) != 0 {
+		t.Errorf("new flow worker should be empty, but got %v", flow.Worker)
+	}
+
+	if flow.ShareData == nil {
+		t.Errorf("flow share data should be stored")
+	} else if value, exist := flow.ShareData.Get("key"); !exist || value != "value" {
+		t.Errorf("flow share data mismatch, got %v", value)
+	}
+
+	if len(flow.CreatedAt) == 0 || len(flow.UpdatedAt) == 0 {
+		t.Errorf("flow created_at and updated_at should be set")
+	}
+
+	tasks := mustListTasks(t, bd, flowID)
+	if len(tasks) != 2 {
+		t.Fatalf("expect 2 tasks, but got %d", len(tasks))
+	}
+
+	for _, task := range tasks {
+		if task.FlowID != flowID || task.State != enumor.TaskPending {
+			t.Errorf("unexpected task: %+v", task)
+		}
+
+		if task.ActionID == "2" && (len(task.DependOn) != 1 || task.DependOn[0] != "1") {
+			t.Errorf("task depend on mismatch, got %v", task.DependOn)
+		}
+	}
+
+	// 组合条件查询
+	flows, err := bd.ListFlow(newKit(), &backend.ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("id", flowID),
+			tools.RuleEqual("worker", ""),
+			tools.RuleEqual("state", enumor.FlowPending),
+		),
+		Page: core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow failed, err: %v", err)
+	}
+	if len(flows) != 1 {
+		t.Errorf("list pending flow without worker expect 1, but got %d", len(flows))
+	}
+
+	flows, err = bd.ListFlow(newKit(), &backend.ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("id", flowID),
+			tools.RuleIn("state", []enumor.FlowState{enumor.FlowRunning, enumor.FlowScheduled}),
+		),
+		Page: core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list flow failed, err: %v", err)
+	}
+	if len(flows) != 0 {
+		t.Errorf("list flow with mismatched state expect 0, but got %d", len(flows))
+	}
+}
+
+func testCreateInitFlow(t *testing.T, bd backend.Backend) {
+	flow := newFlow(enumor.FlowInit)
+	for idx := range flow.Tasks {
+		flow.Tasks[idx].State = enumor.TaskInit
+	}
+
+	flowID, err := bd.CreateFlow(newKit(), flow)
+	if err != nil {
+		t.Fatalf("create flow failed, err: %v", err)
+	}
+
+	if got := mustGetFlow(t, bd, flowID); got.State != enumor.FlowInit {
+		t.Errorf("init flow state expect init, but got %s", got.State)
+	}
+
+	for _, task := range mustListTasks(t, bd, flowID) {
+		if task.State != enumor.TaskInit {
+			t.Errorf("init task state expect init, but got %s", task.State)
+		}
+	}
+}
+
+func testFlowDeadline(t *testing.T, bd backend.Backend) {
+	now := times.ConvStdTimeNow().Truncate(time.Second)
+	flow := newFlow(enumor.FlowPending)
+	flow.DeadlineAt = times.ConvStdTimeFormat(now.Add(-time.Minute))
+	flow.SLAAt = times.ConvStdTimeFormat(now.Add(-2 * time.Minute))
+	overdueID, err := bd.CreateFlow(newKit(), flow)
+	if err != nil {
+		t.Fatalf("create flow with deadline failed, err: %v", err)
+	}
+	normalID := mustCreateFlow(t, bd, enumor.FlowPending)
+
+	got := mustGetFlow(t, bd, overdueID)
+	if got.DeadlineAt != flow.DeadlineAt || got.SLAAt != flow.SLAAt {
+		t.Errorf("flow deadline mismatch, expect %s/%s, but got %s/%s", flow.DeadlineAt, flow.SLAAt,
+			got.DeadlineAt, got.SLAAt)
+	}
+
+	if got = mustGetFlow(t, bd, normalID); len(got.DeadlineAt) != 0 || len(got.SLAAt) != 0 {
+		t.Errorf("flow without deadline should be empty, but got %s/%s", got.DeadlineAt, got.SLAAt)
+	}
+
+	// 未设置截止时间的任务流不应该被查询到
+	flows, err := bd.ListFlow(newKit(), &backend.ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("id", []string{overdueID, normalID}),
+			tools.RuleLessThanEqual("deadline_at", times.ConvStdTimeFormat(now)),
+		),
+		Page: core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list overdue flow failed, err: %v", err)
+	}
+	if len(flows) != 1 || flows[0].ID != overdueID {
+		t.Errorf("list overdue flow expect %s, but got %+v", overdueID, flows)
+	}
+
+	// 更新任务流状态后截止时间保持不变
+	err = bd.BatchUpdateFlowStateByCAS(newKit(), []backend.UpdateFlowInfo{{
+		ID:     overdueID,
+		Source: enumor.FlowPending,
+		Target: enumor.FlowFailed,
+		Reason: &tableasync.Reason{Message: "deadline exceeded"},
+	}})
+	if err != nil {
+		t.Fatalf("update overdue flow state failed, err: %v", err)
+	}
+	if got = mustGetFlow(t, bd, overdueID); got.State != enumor.FlowFailed || got.DeadlineAt != flow.DeadlineAt {
+		t.Errorf("unexpected overdue flow after update: %+v", got)
+	}
+}
+
+func testFlowPriorityAndFairness(t *testing.T, bd backend.Backend) {
+	ids := make([]string, 0)
+	fairnessKey := "account:" + uuid.UUID()
+	for _, priority := range []enumor.FlowPriority{enumor.FlowPriorityLow, enumor.FlowPriorityHigh,
+		enumor.FlowPriorityNormal} {
+
+		flow := newFlow(enumor.FlowPending)
+		flow.Priority = priority
+		flow.FairnessKey = fairnessKey
+		id, err := bd.CreateFlow(newKit(), flow)
+		if err != nil {
+			t.Fatalf("create flow with priority failed, err: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	got := mustGetFlow(t, bd, ids[1])
+	if got.Priority != enumor.FlowPriorityHigh || got.FairnessKey != fairnessKey {
+		t.Errorf("flow priority mismatch, expect %d/%s, but got %d/%s", enumor.FlowPriorityHigh, fairnessKey,
+			got.Priority, got.FairnessKey)
+	}
+
+	flows, err := bd.ListFlow(newKit(), &backend.ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("fairness_key", fairnessKey),
+			tools.RuleEqual("state", enumor.FlowPending),
+		),
+		Page: &core.BasePage{Limit: core.DefaultMaxPageLimit, Sort: "priority", Order: core.Descending},
+	})
+	if err != nil {
+		t.Fatalf("list flow by fairness key failed, err: %v", err)
+	}
+	if len(flows) != 3 || flows[0].ID != ids[1] || flows[1].ID != ids[2] || flows[2].ID != ids[0] {
+		t.Errorf("list flow should sort by priority desc, but got %+v", flows)
+	}
+
+	// 更新任务流时忽略优先级和公平键，保持创建时的值不变
+	err = bd.BatchUpdateFlow(newKit(), []model.Flow{{ID: ids[0], Memo: "updated", Priority: enumor.FlowPriorityHigh}})
+	if err != nil {
+		t.Fatalf("batch update flow failed, err: %v", err)
+	}
+	if got = mustGetFlow(t, bd, ids[0]); got.Priority != enumor.FlowPriorityLow || got.FairnessKey != fairnessKey {
+		t.Errorf("flow priority should not be updated, but got %d/%s", got.Priority, got.FairnessKey)
+	}
+}
+
+func testTaskKindAndCondition(t *testing.T, bd backend.Backend) {
+	cond := &tableasync.Condition{
+		Rules: []tableasync.ConditionRule{{
+			Source:   tableasync.ConditionSourceResult,
+			ActionID: "1",
+			Key:      "name",
+			Operator: tableasync.ConditionEqual,
+			Value:    "first",
+		}},
+	}
+	flow := newFlow(enumor.FlowPending)
+	flow.Tasks[1].Condition = cond
+	flow.Tasks = append(flow.Tasks, model.Task{
+		FlowName:   enumor.FlowNormalTest,
+		ActionID:   "3",
+		ActionName: enumor.ActionRunChildFlow,
+		Kind:       enumor.TaskKindChildFlow,
+		Params:     `{"flow_name":"sleep_test"}`,
+		Retry:      &tableasync.Retry{Enable: false},
+		DependOn:   []action.ActIDType{"1"},
+		State:      enumor.TaskPending,
+	})
+	flowID, err := bd.CreateFlow(newKit(), flow)
+	if err != nil {
+		t.Fatalf("create flow with child flow task failed, err: %v", err)
+	}
+
+	tasks := mustListTasks(t, bd, flowID)
+	if len(tasks) != 3 {
+		t.Fatalf("list task expect 3, but got %d", len(tasks))
+	}
+
+	for _, one := range tasks {
+		switch one.ActionID {
+		case "1":
+			if one.Kind != enumor.TaskKindAction || one.Condition != nil {
+				t.Errorf("task 1 expect default kind without condition, but got %s/%+v", one.Kind, one.Condition)
+			}
+		case "2":
+			if one.Condition == nil || len(one.Condition.Rules) != 1 || one.Condition.Rules[0].Value != "first" {
+				t.Errorf("task 2 condition mismatch, got %+v", one.Condition)
+			}
+		case "3":
+			if one.Kind != enumor.TaskKindChildFlow {
+				t.Errorf("task 3 expect kind %s, but got %s", enumor.TaskKindChildFlow, one.Kind)
+			}
+		}
+	}
+
+	// 查询非子任务流类型的任务
+	actionTasks, err := bd.ListTask(newKit(), &backend.ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("flow_id", flowID),
+			tools.RuleNotEqual("kind", enumor.TaskKindChildFlow),
+		),
+		Page: core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list action task failed, err: %v", err)
+	}
+	if len(actionTasks) != 2 {
+		t.Errorf("list action task expect 2, but got %d", len(actionTasks))
+	}
+}
+
+func testFlowCompensation(t *testing.T, bd backend.Backend) {
+	flow := newFlow(enumor.FlowPending)
+	flow.CompensateOnFailure = true
+	flow.Tasks[0].CompensateAction = enumor.ActionProduceTest
+	flowID, err := bd.CreateFlow(newKit(), flow)
+	if err != nil {
+		t.Fatalf("create compensation flow failed, err: %v", err)
+	}
+
+	if got := mustGetFlow(t, bd, flowID); !got.CompensateOnFailure {
+		t.Errorf("flow compensate_on_failure expect true, but got false")
+	}
+
+	var taskID string
+	for _, one := range mustListTasks(t, bd, flowID) {
+		switch one.ActionID {
+		case "1":
+			taskID = one.ID
+			if one.CompensateAction != enumor.ActionProduceTest {
+				t.Errorf("task 1 compensate_action expect %s, but got %s", enumor.ActionProduceTest,
+					one.CompensateAction)
+			}
+		default:
+			if len(one.CompensateAction) != 0 {
+				t.Errorf("task %s compensate_action expect empty, but got %s", one.ActionID, one.CompensateAction)
+			}
+		}
+	}
+
+	// 任务状态 success -> compensating -> compensate_failed
+	if err = bd.UpdateTask(newKit(), &model.Task{ID: taskID, State: enumor.TaskSuccess}); err != nil {
+		t.Fatalf("update task to success failed, err: %v", err)
+	}
+	info := &backend.UpdateTaskInfo{ID: taskID, Source: enumor.TaskSuccess, Target: enumor.TaskCompensating}
+	if err = bd.UpdateTaskStateByCAS(newKit(), info); err != nil {
+		t.Fatalf("update task state to compensating failed, err: %v", err)
+	}
+	if err = bd.UpdateTaskStateByCAS(newKit(), info); err == nil {
+		t.Errorf("update task state from success should fail after compensating")
+	}
+	info = &backend.UpdateTaskInfo{ID: taskID, Source: enumor.TaskCompensating, Target: enumor.TaskCompensateFailed}
+	if err = bd.UpdateTaskStateByCAS(newKit(), info); err != nil {
+		t.Fatalf("update task state to compensate_failed failed, err: %v", err)
+	}
+
+	tasks, err := bd.ListTask(newKit(), &backend.ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("flow_id", flowID),
+			tools.RuleEqual("state", enumor.TaskCompensateFailed),
+		),
+		Page: core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list compensate failed task failed, err: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != taskID {
+		t.Errorf("list compensate failed task expect %s, but got %+v", taskID, tasks)
+	}
+
+	// 更新任务流时补偿模式保持不变
+	if err = bd.BatchUpdateFlow(newKit(), []model.Flow{{ID: flowID, Memo: "updated"}}); err != nil {
+		t.Fatalf("update flow failed, err: %v", err)
+	}
+	if got := mustGetFlow(t, bd, flowID); !got.CompensateOnFailure || got.Memo != "updated" {
+		t.Errorf("flow after update mismatch, got: %+v", got)
+	}
+}
+
+func testFlowStateCAS(t *testing.T, bd backend.Backend) {
+	flowID := mustCreateFlow(t, bd, enumor.FlowPending)
+
+	infos := []backend.UpdateFlowInfo{{
+		ID:     flowID,
+		Source: enumor.FlowPending,
+		Target: enumor.FlowScheduled,
+		Worker: converter.ValToPtr("worker-1"),
+	}}
+	if err := bd.BatchUpdateFlowStateByCAS(newKit(), infos); err != nil {
+		t.Fatalf("update flow state by cas failed, err: %v", err)
+	}
+
+	flow := mustGetFlow(t, bd, flowID)
+	if flow.State != enumor.FlowScheduled || flow.Worker == nil || *flow.Worker != "worker-1" {
+		t.Errorf("flow state or worker not updated, got state: %s, worker: %v", flow.State, flow.Worker)
+	}
+
+	// 源状态不匹配时需要失败
+	if err := bd.BatchUpdateFlowStateByCAS(newKit(), infos); err == nil {
+		t.Errorf("update flow state with mismatched source state should failed")
+	}
+
+	// 批量更新中任意一个失败，整体都不生效
+	otherID := mustCreateFlow(t, bd, enumor.FlowPending)
+	infos = []backend.UpdateFlowInfo{
+		{ID: otherID, Source: enumor.FlowPending, Target: enumor.FlowScheduled},
+		{ID: flowID, Source: enumor.FlowPending, Target: enumor.FlowScheduled},
+	}
+	if err := bd.BatchUpdateFlowStateByCAS(newKit(), infos); err == nil {
+		t.Errorf("batch update flow state with one mismatched source state should failed")
+	}
+
+	if got := mustGetFlow(t, bd, otherID); got.State != enumor.FlowPending {
+		t.Errorf("batch update flow state should be atomic, but got state: %s", got.State)
+	}
+}
+
+func testConcurrentFlowStateCAS(t *testing.T, bd backend.Backend) {
+	flowID := mustCreateFlow(t, bd, enumor.FlowPending)
+
+	const workers = 8
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	succeed := 0
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := bd.BatchUpdateFlowStateByCAS(newKit(), []backend.UpdateFlowInfo{{
+				ID:     flowID,
+				Source: enumor.FlowPending,
+				Target: enumor.FlowScheduled,
+			}})
+			if err == nil {
+				lock.Lock()
+				succeed++
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeed != 1 {
+		t.Errorf("concurrent cas update flow state expect exactly 1 success, but got %d", succeed)
+	}
+}
+
+func testTaskStateCAS(t *testing.T, bd backend.Backend) {
+	flowID := mustCreateFlow(t, bd, enumor.FlowPending)
+	tasks := mustListTasks(t, bd, flowID)
+	if len(tasks) == 0 {
+		t.Fatalf("flow %s has no task", flowID)
+	}
+	taskID := tasks[0].ID
+
+	info := &backend.UpdateTaskInfo{
+		ID:     taskID,
+		Source: enumor.TaskPending,
+		Target: enumor.TaskRunning,
+		Reason: &tableasync.Reason{Message: "start"},
+	}
+	if err := bd.UpdateTaskStateByCAS(newKit(), info); err != nil {
+		t.Fatalf("update task state by cas failed, err: %v", err)
+	}
+
+	if err := bd.UpdateTaskStateByCAS(newKit(), info); err == nil {
+		t.Errorf("update task state with mismatched source state should failed")
+	}
+
+	got, err := bd.ListTask(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("id", taskID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list task failed, err: %v", err)
+	}
+	if len(got) != 1 || got[0].State != enumor.TaskRunning || got[0].Reason == nil ||
+		got[0].Reason.Message != "start" {
+		t.Errorf("task state not updated, got: %+v", got)
+	}
+}
+
+func testUpdateTaskAndRetry(t *testing.T, bd backend.Backend) {
+	flowID := mustCreateFlow(t, bd, enumor.FlowPending)
+	tasks := mustListTasks(t, bd, flowID)
+	if len(tasks) == 0 {
+		t.Fatalf("flow %s has no task", flowID)
+	}
+	taskID := tasks[0].ID
+
+	// 非失败状态不允许重试
+	if err := bd.RetryTask(newKit(), flowID, taskID); err == nil {
+		t.Errorf("retry task of not failed flow should failed")
+	}
+
+	task := &model.Task{
+		ID:     taskID,
+		State:  enumor.TaskFailed,
+		Result: `{"ok":false}`,
+		Reason: &tableasync.Reason{Message: "failed"},
+	}
+	if err := bd.UpdateTask(newKit(), task); err != nil {
+		t.Fatalf("update task failed, err: %v", err)
+	}
+
+	flows := []model.Flow{{
+		ID:     flowID,
+		State:  enumor.FlowFailed,
+		Reason: &tableasync.Reason{Message: "failed"},
+	}}
+	if err := bd.BatchUpdateFlow(newKit(), flows); err != nil {
+		t.Fatalf("batch update flow failed, err: %v", err)
+	}
+
+	flow := mustGetFlow(t, bd, flowID)
+	if flow.State != enumor.FlowFailed || flow.Memo != "conformance" {
+		t.Errorf("flow update should only change the set fields, got: %+v", flow)
+	}
+
+	if err := bd.RetryTask(newKit(), flowID, taskID); err != nil {
+		t.Fatalf("retry task failed, err: %v", err)
+	}
+
+	if got := mustGetFlow(t, bd, flowID); got.State != enumor.FlowPending {
+		t.Errorf("retried flow state expect pending, but got %s", got.State)
+	}
+
+	for _, one := range mustListTasks(t, bd, flowID) {
+		if one.ID == taskID && one.State != enumor.TaskPending {
+			t.Errorf("retried task state expect pending, but got %s", one.State)
+		}
+	}
+
+	if err := bd.UpdateTask(newKit(), &model.Task{ID: "not-exist-task", State: enumor.TaskFailed}); err == nil {
+		t.Errorf("update not exist task should failed")
+	}
+}
+
+func testBatchCreateTaskAndPage(t *testing.T, bd backend.Backend) {
+	flowID := mustCreateFlow(t, bd, enumor.FlowPending)
+
+	kt := newKit()
+	tasks := make([]model.Task, 0, 3)
+	for _, actID := range []action.ActIDType{"3", "4", "5"} {
+		tasks = append(tasks, model.Task{
+			FlowID:     flowID,
+			FlowName:   enumor.FlowNormalTest,
+			ActionID:   actID,
+			ActionName: enumor.ActionAssembleTest,
+			Retry:      &tableasync.Retry{Enable: false},
+			Reason:     new(tableasync.Reason),
+			Creator:    kt.User,
+			Reviser:    kt.User,
+		})
+	}
+	ids, err := bd.BatchCreateTask(kt, tasks)
+	if err != nil {
+		t.Fatalf("batch create task failed, err: %v", err)
+	}
+	if len(ids) != len(tasks) {
+		t.Fatalf("batch create task expect %d ids, but got %d", len(tasks), len(ids))
+	}
+
+	if got := mustListTasks(t, bd, flowID); len(got) != 5 {
+		t.Errorf("flow expect 5 tasks, but got %d", len(got))
+	}
+
+	page := &core.BasePage{Start: 1, Limit: 2, Sort: "id", Order: core.Descending}
+	got, err := bd.ListTask(newKit(), &backend.ListInput{
+		Filter: tools.ContainersExpression("id", ids),
+		Page:   page,
+	})
+	if err != nil {
+		t.Fatalf("list task failed, err: %v", err)
+	}
+
+	if len(got) != 2 || got[0].ID != ids[1] || got[1].ID != ids[0] {
+		t.Errorf("list task with page mismatch, expect [%s %s], got %+v", ids[1], ids[0], got)
+	}
+}
+
+func mustCreateScheduledFlow(t *testing.T, bd backend.Backend, nextTriggerAt time.Time) string {
+	id, err := bd.CreateScheduledFlow(newKit(), &model.ScheduledFlow{
+		Name:     fmt.Sprintf("conformance-%d", time.Now().UnixNano()),
+		FlowName: enumor.FlowNormalTest,
+		Spec:     "*/5 * * * *",
+		Tasks: tableasync.ScheduledTasks{
+			{ActionID: "1", Params: `{"name":"first"}`},
+		},
+		Memo:          "conformance",
+		State:         enumor.ScheduledFlowEnabled,
+		NextTriggerAt: times.ConvStdTimeFormat(nextTriggerAt),
+	})
+	if err != nil {
+		t.Fatalf("create scheduled flow failed, err: %v", err)
+	}
+
+	if len(id) == 0 {
+		t.Fatalf("create scheduled flow returned empty id")
+	}
+
+	return id
+}
+
+func mustGetScheduledFlow(t *testing.T, bd backend.Backend, id string) model.ScheduledFlow {
+	flows, err := bd.ListScheduledFlow(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list scheduled flow failed, err: %v", err)
+	}
+
+	if len(flows) != 1 {
+		t.Fatalf("list scheduled flow by id %s expect 1, but got %d", id, len(flows))
+	}
+
+	return flows[0]
+}
+
+func testScheduledFlowCRUD(t *testing.T, bd backend.Backend) {
+	next := time.Now().Add(time.Hour).Truncate(time.Second)
+	id := mustCreateScheduledFlow(t, bd, next)
+
+	got := mustGetScheduledFlow(t, bd, id)
+	if got.State != enumor.ScheduledFlowEnabled || got.Spec != "*/5 * * * *" || len(got.Tasks) != 1 {
+		t.Errorf("scheduled flow fields mismatch, got: %+v", got)
+	}
+	if got.NextTriggerAt != times.ConvStdTimeFormat(next) {
+		t.Errorf("scheduled flow next_trigger_at expect %s, but got %s", times.ConvStdTimeFormat(next),
+			got.NextTriggerAt)
+	}
+	if len(got.LastTriggeredAt) != 0 || len(got.LastFlowID) != 0 {
+		t.Errorf("scheduled flow should not be triggered, got: %+v", got)
+	}
+
+	// 暂停后只更新状态，其余字段保持不变
+	if err := bd.UpdateScheduledFlow(newKit(), &model.ScheduledFlow{ID: id,
+		State: enumor.ScheduledFlowPaused}); err != nil {
+		t.Fatalf("pause scheduled flow failed, err: %v", err)
+	}
+	got = mustGetScheduledFlow(t, bd, id)
+	if got.State != enumor.ScheduledFlowPaused || got.Memo != "conformance" || len(got.Tasks) != 1 {
+		t.Errorf("scheduled flow after pause mismatch, got: %+v", got)
+	}
+
+	// 暂停状态的定时任务流不允许触发
+	_, err := bd.TriggerScheduledFlow(newKit(), &backend.TriggerScheduledFlowInfo{ID: id, Source: next,
+		Target: next.Add(time.Hour)}, newFlow(enumor.FlowPending))
+	if err == nil {
+		t.Errorf("trigger paused scheduled flow should fail")
+	}
+
+	paused, err := bd.ListScheduledFlow(newKit(), &backend.ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("id", id),
+			tools.RuleEqual("state", enumor.ScheduledFlowPaused),
+		),
+		Page: core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list scheduled flow by state failed, err: %v", err)
+	}
+	if len(paused) != 1 {
+		t.Errorf("list paused scheduled flow expect 1, but got %d", len(paused))
+	}
+
+	if err = bd.DeleteScheduledFlow(newKit(), id); err != nil {
+		t.Fatalf("delete scheduled flow failed, err: %v", err)
+	}
+	flows, err := bd.ListScheduledFlow(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list scheduled flow failed, err: %v", err)
+	}
+	if len(flows) != 0 {
+		t.Errorf("scheduled flow should be deleted, but got %d", len(flows))
+	}
+}
+
+func testConcurrentTriggerScheduledFlow(t *testing.T, bd backend.Backend) {
+	next := time.Now().Add(-time.Minute).Truncate(time.Second)
+	id := mustCreateScheduledFlow(t, bd, next)
+
+	const workers = 8
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	flowIDs := make([]string, 0)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			flowID, err := bd.TriggerScheduledFlow(newKit(), &backend.TriggerScheduledFlowInfo{ID: id, Source: next,
+				Target: next.Add(5 * time.Minute)}, newFlow(enumor.FlowPending))
+			if err == nil {
+				lock.Lock()
+				flowIDs = append(flowIDs, flowID)
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(flowIDs) != 1 {
+		t.Fatalf("concurrent trigger scheduled flow expect exactly 1 success, but got %d", len(flowIDs))
+	}
+
+	got := mustGetScheduledFlow(t, bd, id)
+	if got.LastFlowID != flowIDs[0] {
+		t.Errorf("scheduled flow last_flow_id expect %s, but got %s", flowIDs[0], got.LastFlowID)
+	}
+	if got.NextTriggerAt != times.ConvStdTimeFormat(next.Add(5*time.Minute)) {
+		t.Errorf("scheduled flow next_trigger_at not updated, got: %s", got.NextTriggerAt)
+	}
+	if len(got.LastTriggeredAt) == 0 {
+		t.Errorf("scheduled flow last_triggered_at should be set")
+	}
+
+	flow := mustGetFlow(t, bd, flowIDs[0])
+	if flow.State != enumor.FlowPending || len(mustListTasks(t, bd, flowIDs[0])) != 2 {
+		t.Errorf("triggered flow mismatch, got: %+v", flow)
+	}
+
+	if err := bd.DeleteScheduledFlow(newKit(), id); err != nil {
+		t.Fatalf("delete scheduled flow failed, err: %v", err)
+	}
+}
+
+func testTaskLog(t *testing.T, bd backend.Backend) {
+	taskID := uuid.UUID()[:16]
+	taskLogs := make([]model.TaskLog, 0)
+	for attempt := uint(1); attempt <= 3; attempt++ {
+		taskLogs = append(taskLogs, model.TaskLog{FlowID: "conformance", TaskID: taskID, Attempt: attempt,
+			Kind: enumor.TaskLogKindLog, Level: enumor.TaskLogInfo, Message: fmt.Sprintf("attempt %d", attempt)})
+
+		level, msg := enumor.TaskLogError, "failed"
+		if attempt == 3 {
+			level, msg = enumor.TaskLogInfo, "success"
+		}
+		taskLogs = append(taskLogs, model.TaskLog{FlowID: "conformance", TaskID: taskID, Attempt: attempt,
+			Kind: enumor.TaskLogKindAttempt, Level: level, Message: msg})
+	}
+	if err := bd.BatchCreateTaskLog(newKit(), taskLogs); err != nil {
+		t.Fatalf("batch create task log failed, err: %v", err)
+	}
+
+	// 默认按id升序即写入顺序返回
+	all, err := bd.ListTaskLog(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("task_id", taskID),
+		Page:   &core.BasePage{Limit: core.DefaultMaxPageLimit, Sort: "id", Order: core.Ascending},
+	})
+	if err != nil {
+		t.Fatalf("list task log failed, err: %v", err)
+	}
+	if len(all) != len(taskLogs) {
+		t.Fatalf("list task log expect %d, but got %d", len(taskLogs), len(all))
+	}
+	for i := range all {
+		if all[i].Message != taskLogs[i].Message || all[i].Attempt != taskLogs[i].Attempt {
+			t.Errorf("task log %d mismatch, expect: %+v, got: %+v", i, taskLogs[i], all[i])
+		}
+		if len(all[i].ID) == 0 || len(all[i].CreatedAt) == 0 {
+			t.Errorf("task log %d should have id and created_at, got: %+v", i, all[i])
+		}
+	}
+
+	attempts, err := bd.ListTaskLog(newKit(), &backend.ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("task_id", taskID),
+			tools.RuleEqual("kind", enumor.TaskLogKindAttempt),
+		),
+		Page: &core.BasePage{Limit: 1, Sort: "attempt", Order: core.Descending},
+	})
+	if err != nil {
+		t.Fatalf("list task attempt failed, err: %v", err)
+	}
+	if len(attempts) != 1 || attempts[0].Attempt != 3 || attempts[0].Level != enumor.TaskLogInfo {
+		t.Errorf("last attempt mismatch, got: %+v", attempts)
+	}
+
+	if _, err = bd.DeleteExpiredTaskLog(newKit(), time.Now().Add(-time.Hour), 100); err != nil {
+		t.Fatalf("delete expired task log failed, err: %v", err)
+	}
+	all, err = bd.ListTaskLog(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("task_id", taskID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list task log failed, err: %v", err)
+	}
+	if len(all) != len(taskLogs) {
+		t.Errorf("unexpired task log should be kept, expect %d, but got %d", len(taskLogs), len(all))
+	}
+
+	// 按limit分批删除
+	before := time.Now().Add(time.Minute)
+	deleted, err := bd.DeleteExpiredTaskLog(newKit(), before, 1)
+	if err != nil {
+		t.Fatalf("delete expired task log failed, err: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("delete expired task log with limit 1 expect 1, but got %d", deleted)
+	}
+	for deleted != 0 {
+		if deleted, err = bd.DeleteExpiredTaskLog(newKit(), before, 100); err != nil {
+			t.Fatalf("delete expired task log failed, err: %v", err)
+		}
+	}
+	all, err = bd.ListTaskLog(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("task_id", taskID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		t.Fatalf("list task log failed, err: %v", err)
+	}
+	if len(all) != 0 {
+		t.Errorf("expired task log should be deleted, but got %d", len(all))
+	}
+}
+
+func containsFlow(flows []model.Flow, id string) bool {
+	for _, one := range flows {
+		if one.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func mustListFlowsByState(t *testing.T, bd backend.Backend, state enumor.FlowState) []model.Flow {
+	flows, err := bd.ListFlow(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("state", state),
+		Page:   &core.BasePage{Limit: core.DefaultMaxPageLimit, Sort: "id", Order: core.Descending},
+	})
+	if err != nil {
+		t.Fatalf("list flow by state failed, err: %v", err)
+	}
+
+	return flows
+}
+
+func testStateListAndExpiredFlow(t *testing.T, bd backend.Backend) {
+	finishedID := mustCreateFlow(t, bd, enumor.FlowPending)
+	pendingID := mustCreateFlow(t, bd, enumor.FlowPending)
+
+	infos := []backend.UpdateFlowInfo{{ID: finishedID, Source: enumor.FlowPending, Target: enumor.FlowSuccess}}
+	if err := bd.BatchUpdateFlowStateByCAS(newKit(), infos); err != nil {
+		t.Fatalf("update flow state by cas failed, err: %v", err)
+	}
+
+	// 状态变化后只能按新状态查询到
+	if !containsFlow(mustListFlowsByState(t, bd, enumor.FlowSuccess), finishedID) {
+		t.Errorf("flow %s should be listed by state success", finishedID)
+	}
+	if pending := mustListFlowsByState(t, bd, enumor.FlowPending); containsFlow(pending, finishedID) ||
+		!containsFlow(pending, pendingID) {
+		t.Errorf("list flow by state pending mismatch, got: %+v", pending)
+	}
+
+	tasks := mustListTasks(t, bd, finishedID)
+	if len(tasks) != 2 {
+		t.Fatalf("list task of flow %s expect 2, but got %d", finishedID, len(tasks))
+	}
+	err := bd.UpdateTaskStateByCAS(newKit(), &backend.UpdateTaskInfo{ID: tasks[0].ID, Source: enumor.TaskPending,
+		Target: enumor.TaskSuccess})
+	if err != nil {
+		t.Fatalf("update task state by cas failed, err: %v", err)
+	}
+	successTasks, err := bd.ListTask(newKit(), &backend.ListInput{
+		Filter: tools.EqualExpression("state", enumor.TaskSuccess),
+		Page:   &core.BasePage{Limit: core.DefaultMaxPageLimit, Sort: "id", Order: core.Descending},
+	})
+	if err != nil {
+		t.Fatalf("list task by state failed, err: %v", err)
+	}
+	if len(successTasks) == 0 || successTasks[0].ID != tasks[0].ID {
+		t.Errorf("task %s should be listed by state success, got: %+v", tasks[0].ID, successTasks)
+	}
+
+	// 未过期的任务流需要保留
+	if _, err = bd.DeleteExpiredFlow(newKit(), time.Now().Add(-time.Hour), 100); err != nil {
+		t.Fatalf("delete expired flow failed, err: %v", err)
+	}
+	mustGetFlow(t, bd, finishedID)
+
+	before := time.Now().Add(time.Minute)
+	deleted, err := bd.DeleteExpiredFlow(newKit(), before, 1)
+	if err != nil {
+		t.Fatalf("delete expired flow failed, err: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("delete expired flow with limit 1 expect 1, but got %d", deleted)
+	}
+	for deleted != 0 {
+		if deleted, err = bd.DeleteExpiredFlow(newKit(), before, 100); err != nil {
+			t.Fatalf("delete expired flow failed, err: %v", err)
+		}
+	}
+
+	if containsFlow(mustListFlowsByState(t, bd, enumor.FlowSuccess), finishedID) {
+		t.Errorf("expired flow %s should be deleted", finishedID)
+	}
+	if left := mustListTasks(t, bd, finishedID); len(left) != 0 {
+		t.Errorf("tasks of expired flow %s should be deleted, but got %d", finishedID, len(left))
+	}
+
+	// 未结束的任务流不会被清理
+	mustGetFlow(t, bd, pendingID)
+}
@@ -0,0 +1,97 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/backendtest"
+	"hcm/pkg/cc"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao"
+
+	etcd3 "go.etcd.io/etcd/client/v3"
+)
+
+// 一致性测试需要依赖真实的存储，通过环境变量指定，未指定时跳过：
+//   - HCM_TEST_ETCD_ENDPOINTS: etcd地址，多个地址使用逗号分隔
+//   - HCM_TEST_MYSQL_ENDPOINT、HCM_TEST_MYSQL_DATABASE、HCM_TEST_MYSQL_USER、HCM_TEST_MYSQL_PASSWORD: mysql配置
+
+func TestEtcdBackendConformance(t *testing.T) {
+	endpoints := os.Getenv("HCM_TEST_ETCD_ENDPOINTS")
+	if len(endpoints) == 0 {
+		t.Skip("HCM_TEST_ETCD_ENDPOINTS is not set, skip etcd backend conformance test")
+	}
+
+	cli, err := etcd3.New(etcd3.Config{
+		Endpoints:   strings.Split(endpoints, ","),
+		DialTimeout: 3 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("new etcd client failed, err: %v", err)
+	}
+	defer cli.Close()
+
+	bd, err := backend.Factory(enumor.BackendEtcd, cli)
+	if err != nil {
+		t.Fatalf("new etcd backend failed, err: %v", err)
+	}
+
+	backendtest.RunConformance(t, bd)
+}
+
+func TestMysqlBackendConformance(t *testing.T) {
+	endpoint := os.Getenv("HCM_TEST_MYSQL_ENDPOINT")
+	if len(endpoint) == 0 {
+		t.Skip("HCM_TEST_MYSQL_ENDPOINT is not set, skip mysql backend conformance test")
+	}
+
+	cfg := cc.DataBase{
+		Resource: cc.ResourceDB{
+			Endpoints:      []string{endpoint},
+			Database:       os.Getenv("HCM_TEST_MYSQL_DATABASE"),
+			User:           os.Getenv("HCM_TEST_MYSQL_USER"),
+			Password:       os.Getenv("HCM_TEST_MYSQL_PASSWORD"),
+			DialTimeoutSec: 5,
+			MaxOpenConn:    10,
+			MaxIdleConn:    5,
+		},
+		MaxSlowLogLatencyMS: 200,
+		Limiter: &cc.Limiter{
+			QPS:   500,
+			Burst: 500,
+		},
+	}
+	daoSet, err := dao.NewDaoSet(cfg)
+	if err != nil {
+		t.Fatalf("new dao set failed, err: %v", err)
+	}
+
+	bd, err := backend.Factory(enumor.BackendMysql, daoSet)
+	if err != nil {
+		t.Fatalf("new mysql backend failed, err: %v", err)
+	}
+
+	backendtest.RunConformance(t, bd)
+}
@@ -0,0 +1,778 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"sync/atomic"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	tableasync "hcm/pkg/dal/table/async"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+
+	etcd3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// defaultEtcdKeyPrefix etcd backend 默认的key前缀
+	defaultEtcdKeyPrefix = "/hcm/async/backend"
+	// etcdMaxCASRetryCount 因并发修改导致事务提交失败时的最大重试次数
+	etcdMaxCASRetryCount = 10
+)
+
+/*
+etcd backend 数据存储结构:
+  - {prefix}/flow/{id}: 任务流，值为 tableasync.AsyncFlowTable 的json
+  - {prefix}/task/{id}: 任务，值为 tableasync.AsyncFlowTaskTable 的json
+  - {prefix}/scheduled_flow/{id}: 定时任务流，值为 tableasync.AsyncScheduledFlowTable 的json
+  - {prefix}/scheduled_flow_name/{name}: 定时任务流名称到ID的索引，用于保证名称唯一
+  - {prefix}/id/{resource}: 资源的最大ID，生成规则与 id_generator 表保持一致
+  - {prefix}/flow_state/{state}/{id}: 任务流状态索引，值为空
+  - {prefix}/task_state/{state}/{id}: 任务状态索引，值为空
+  - {prefix}/task_flow/{flow_id}/{id}: 任务所属任务流索引，值为空
+  - {prefix}/index_version: 索引版本标记，不存在时会根据已有的任务流和任务重建索引
+
+所有的CAS操作都通过etcd事务比较记录的 ModRevision 实现，索引与记录在同一个事务中写入。
+查询时优先根据过滤条件中的 id、state、flow_id 通过索引确定候选记录，再在内存中进行过滤、排序和分页，
+无法使用索引时才会读取全部记录。
+注意：创建任务流时任务流和任务在同一个事务中写入，单个任务流的任务数量受etcd的 --max-txn-ops 参数限制。
+*/
+
+// NewEtcd create etcd instance
+func NewEtcd(cli *etcd3.Client) Backend {
+	return &etcd{
+		cli:    cli,
+		prefix: defaultEtcdKeyPrefix,
+	}
+}
+
+// etcd etcd backend
+type etcd struct {
+	cli    *etcd3.Client
+	prefix string
+	// indexReady 索引是否已经确认构建完成
+	indexReady atomic.Bool
+}
+
+var _ Backend = new(etcd)
+
+func (e *etcd) flowKey(id string) string {
+	return path.Join(e.prefix, "flow", id)
+}
+
+func (e *etcd) flowKeyPrefix() string {
+	return path.Join(e.prefix, "flow") + "/"
+}
+
+func (e *etcd) taskKey(id string) string {
+	return path.Join(e.prefix, "task", id)
+}
+
+func (e *etcd) taskKeyPrefix() string {
+	return path.Join(e.prefix, "task") + "/"
+}
+
+func (e *etcd) idKey(resource table.Name) string {
+	return path.Join(e.prefix, "id", string(resource))
+}
+
+// genIDs 生成资源唯一ID，生成规则与 id_generator 保持一致
+func (e *etcd) genIDs(kt *kit.Kit, resource table.Name, count int) ([]string, error) {
+	if count <= 0 {
+		return make([]string, 0), nil
+	}
+
+	key := e.idKey(resource)
+	for i := 0; i < etcdMaxCASRetryCount; i++ {
+		resp, err := e.cli.Get(kt.Ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("gen %s unique id, but get max id failed, err: %v", resource, err)
+		}
+
+		var maxID uint64
+		var modRevision int64
+		if len(resp.Kvs) != 0 {
+			maxID, err = strconv.ParseUint(string(resp.Kvs[0].Value), 36, 64)
+			if err != nil {
+				return nil, fmt.Errorf("gen %s unique id, but parse max id failed, err: %v", resource, err)
+			}
+			modRevision = resp.Kvs[0].ModRevision
+		}
+
+		newMaxID := fmt.Sprintf("%08s", strconv.FormatUint(maxID+uint64(count), 36))
+		txnResp, err := e.cli.Txn(kt.Ctx).
+			If(etcd3.Compare(etcd3.ModRevision(key), "=", modRevision)).
+			Then(etcd3.OpPut(key, newMaxID)).
+			Commit()
+		if err != nil {
+			return nil, fmt.Errorf("gen %s unique id, but update max id failed, err: %v", resource, err)
+		}
+
+		if !txnResp.Succeeded {
+			// 其他节点已经更新了最大ID，重新获取
+			continue
+		}
+
+		ids := make([]string, count)
+		for idx := 0; idx < count; idx++ {
+			ids[idx] = fmt.Sprintf("%08s", strconv.FormatUint(maxID+uint64(idx+1), 36))
+		}
+		return ids, nil
+	}
+
+	return nil, fmt.Errorf("gen %s unique id, but exceed max retry count: %d", resource, etcdMaxCASRetryCount)
+}
+
+// flowRecord 任务流记录以及其在etcd中的版本
+type flowRecord struct {
+	table       tableasync.AsyncFlowTable
+	modRevision int64
+	// state 读取时的状态，用于更新状态索引
+	state enumor.FlowState
+}
+
+// taskRecord 任务记录以及其在etcd中的版本
+type taskRecord struct {
+	table       tableasync.AsyncFlowTaskTable
+	modRevision int64
+	// state 读取时的状态，用于更新状态索引
+	state enumor.TaskState
+}
+
+func (e *etcd) getFlow(kt *kit.Kit, id string) (*flowRecord, error) {
+	resp, err := e.cli.Get(kt.Ctx, e.flowKey(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Kvs) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "flow %s not found", id)
+	}
+
+	record := &flowRecord{modRevision: resp.Kvs[0].ModRevision}
+	if err = json.Unmarshal(resp.Kvs[0].Value, &record.table); err != nil {
+		return nil, fmt.Errorf("unmarshal flow %s failed, err: %v", id, err)
+	}
+	record.state = record.table.State
+
+	return record, nil
+}
+
+func (e *etcd) getTask(kt *kit.Kit, id string) (*taskRecord, error) {
+	resp, err := e.cli.Get(kt.Ctx, e.taskKey(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Kvs) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "task %s not found", id)
+	}
+
+	record := &taskRecord{modRevision: resp.Kvs[0].ModRevision}
+	if err = json.Unmarshal(resp.Kvs[0].Value, &record.table); err != nil {
+		return nil, fmt.Errorf("unmarshal task %s failed, err: %v", id, err)
+	}
+	record.state = record.table.State
+
+	return record, nil
+}
+
+// flowPutOps 生成更新任务流的比较条件和写操作，状态发生变化时同时更新状态索引
+func (e *etcd) flowPutOps(record *flowRecord) (etcd3.Cmp, []etcd3.Op, error) {
+	value, err := json.Marshal(record.table)
+	if err != nil {
+		return etcd3.Cmp{}, nil, err
+	}
+
+	id := record.table.ID
+	key := e.flowKey(id)
+	ops := []etcd3.Op{etcd3.OpPut(key, string(value)), etcd3.OpPut(e.flowStateIndexKey(record.table.State, id), "")}
+	if record.state != record.table.State {
+		ops = append(ops, etcd3.OpDelete(e.flowStateIndexKey(record.state, id)))
+	}
+
+	return etcd3.Compare(etcd3.ModRevision(key), "=", record.modRevision), ops, nil
+}
+
+// taskPutOps 生成更新任务的比较条件和写操作，状态发生变化时同时更新状态索引
+func (e *etcd) taskPutOps(record *taskRecord) (etcd3.Cmp, []etcd3.Op, error) {
+	value, err := json.Marshal(record.table)
+	if err != nil {
+		return etcd3.Cmp{}, nil, err
+	}
+
+	id := record.table.ID
+	key := e.taskKey(id)
+	ops := []etcd3.Op{etcd3.OpPut(key, string(value)), etcd3.OpPut(e.taskStateIndexKey(record.table.State, id), "")}
+	if record.state != record.table.State {
+		ops = append(ops, etcd3.OpDelete(e.taskStateIndexKey(record.state, id)))
+	}
+
+	return etcd3.Compare(etcd3.ModRevision(key), "=", record.modRevision), ops, nil
+}
+
+// commitWithRetry 执行 prepare 生成的事务，如果因为并发修改导致比较条件不满足，则重新执行 prepare 后重试。
+func (e *etcd) commitWithRetry(kt *kit.Kit, prepare func() ([]etcd3.Cmp, []etcd3.Op, error)) error {
+	for i := 0; i < etcdMaxCASRetryCount; i++ {
+		cmps, ops, err := prepare()
+		if err != nil {
+			return err
+		}
+
+		resp, err := e.cli.Txn(kt.Ctx).If(cmps...).Then(ops...).Commit()
+		if err != nil {
+			return err
+		}
+
+		if resp.Succeeded {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("etcd txn exceed max retry count: %d", etcdMaxCASRetryCount)
+}
+
+// CreateFlow 创建任务流
+func (e *etcd) CreateFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
+
+	flowID, cmps, ops, err := e.createFlowOps(kt, flow)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := e.cli.Txn(kt.Ctx).If(cmps...).Then(ops...).Commit()
+	if err != nil {
+		logs.Errorf("create flow in etcd failed, err: %v, name: %s, rid: %s", err, flow.Name, kt.Rid)
+		return "", err
+	}
+
+	if !resp.Succeeded {
+		return "", fmt.Errorf("create flow failed, flow(%s) or its tasks already exist", flowID)
+	}
+
+	return flowID, nil
+}
+
+// createFlowOps 生成创建任务流及其任务的比较条件和写操作
+func (e *etcd) createFlowOps(kt *kit.Kit, flow *model.Flow) (string, []etcd3.Cmp, []etcd3.Op, error) {
+
+	flowState := enumor.FlowPending
+	if flow.State == enumor.FlowInit {
+		flowState = flow.State
+	}
+
+	flowIDs, err := e.genIDs(kt, table.AsyncFlowTable, 1)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	flowID := flowIDs[0]
+
+	taskIDs, err := e.genIDs(kt, table.AsyncFlowTaskTable, len(flow.Tasks))
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	deadlineAt, slaAt, err := parseFlowDeadline(flow)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	now := tabletypes.Time(times.ConvStdTimeFormat(times.ConvStdTimeNow()))
+	flowMd := tableasync.AsyncFlowTable{
+		ID:                  flowID,
+		Name:                flow.Name,
+		State:               flowState,
+		Reason:              new(tableasync.Reason),
+		ShareData:           flow.ShareData,
+		Memo:                flow.Memo,
+		Worker:              converter.ValToPtr(""),
+		Priority:            flow.Priority,
+		FairnessKey:         flow.FairnessKey,
+		CompensateOnFailure: flow.CompensateOnFailure,
+		DeadlineAt:          deadlineAt,
+		SLAAt:               slaAt,
+		Creator:             kt.User,
+		Reviser:             kt.User,
+	}
+	if err = flowMd.InsertValidate(); err != nil {
+		return "", nil, nil, err
+	}
+	flowMd.CreatedAt, flowMd.UpdatedAt = now, now
+	flowValue, err := json.Marshal(flowMd)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	flowKey := e.flowKey(flowID)
+	cmps := []etcd3.Cmp{etcd3.Compare(etcd3.CreateRevision(flowKey), "=", 0)}
+	ops := []etcd3.Op{
+		etcd3.OpPut(flowKey, string(flowValue)),
+		etcd3.OpPut(e.flowStateIndexKey(flowState, flowID), ""),
+	}
+	for idx, one := range flow.Tasks {
+		taskState := enumor.TaskPending
+		if one.State == enumor.TaskInit {
+			taskState = one.State
+		}
+
+		taskMd := tableasync.AsyncFlowTaskTable{
+			ID:               taskIDs[idx],
+			FlowID:           flowID,
+			FlowName:         one.FlowName,
+			ActionID:         string(one.ActionID),
+			ActionName:       one.ActionName,
+			Kind:             one.GetKind(),
+			Params:           one.Params,
+			Retry:            one.Retry,
+			DependOn:         dependOnToStringArray(one.DependOn),
+			ExecCondition:    one.Condition,
+			CompensateAction: one.CompensateAction,
+			State:            taskState,
+			Reason:           new(tableasync.Reason),
+			Creator:          kt.User,
+			Reviser:          kt.User,
+		}
+		if err = taskMd.InsertValidate(); err != nil {
+			return "", nil, nil, err
+		}
+		taskMd.CreatedAt, taskMd.UpdatedAt = now, now
+		taskValue, err := json.Marshal(taskMd)
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		taskKey := e.taskKey(taskMd.ID)
+		cmps = append(cmps, etcd3.Compare(etcd3.CreateRevision(taskKey), "=", 0))
+		ops = append(ops, etcd3.OpPut(taskKey, string(taskValue)))
+		ops = append(ops, e.taskIndexOps(&taskMd)...)
+	}
+
+	return flowID, cmps, ops, nil
+}
+
+// BatchUpdateFlow 批量更新任务流
+func (e *etcd) BatchUpdateFlow(kt *kit.Kit, flows []model.Flow) error {
+
+	prepare := func() ([]etcd3.Cmp, []etcd3.Op, error) {
+		cmps := make([]etcd3.Cmp, 0, len(flows))
+		ops := make([]etcd3.Op, 0, len(flows))
+		for _, one := range flows {
+			if len(one.ID) == 0 {
+				return nil, nil, errf.New(errf.InvalidParameter, "id is required")
+			}
+
+			record, err := e.getFlow(kt, one.ID)
+			if err != nil {
+				if errf.IsRecordNotFound(err) {
+					return nil, nil, errf.New(errf.RecordNotUpdate, "record not update")
+				}
+				return nil, nil, err
+			}
+
+			mergeFlowUpdate(&record.table, &one)
+
+			cmp, putOps, err := e.flowPutOps(record)
+			if err != nil {
+				return nil, nil, err
+			}
+			cmps = append(cmps, cmp)
+			ops = append(ops, putOps...)
+		}
+
+		return cmps, ops, nil
+	}
+
+	if err := e.commitWithRetry(kt, prepare); err != nil {
+		logs.Errorf("batch update flow in etcd failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// mergeFlowUpdate 将需要更新的字段合并到任务流中，未设置的字段保持不变，与mysql的更新语义保持一致。
+func mergeFlowUpdate(dst *tableasync.AsyncFlowTable, src *model.Flow) {
+	if len(src.State) != 0 {
+		dst.State = src.State
+	}
+
+	if src.Reason != nil {
+		dst.Reason = src.Reason
+	}
+
+	if src.ShareData != nil {
+		dst.ShareData = src.ShareData
+	}
+
+	if len(src.Memo) != 0 {
+		dst.Memo = src.Memo
+	}
+
+	if src.Worker != nil {
+		dst.Worker = src.Worker
+	}
+
+	if len(src.Reviser) != 0 {
+		dst.Reviser = src.Reviser
+	}
+
+	dst.UpdatedAt = tabletypes.Time(times.ConvStdTimeFormat(times.ConvStdTimeNow()))
+}
+
+// BatchUpdateFlowStateByCAS CAS批量更新流状态
+func (e *etcd) BatchUpdateFlowStateByCAS(kt *kit.Kit, infos []UpdateFlowInfo) error {
+
+	for _, one := range infos {
+		if err := one.Validate(); err != nil {
+			return err
+		}
+	}
+
+	prepare := func() ([]etcd3.Cmp, []etcd3.Op, error) {
+		cmps := make([]etcd3.Cmp, 0, len(infos))
+		ops := make([]etcd3.Op, 0, len(infos))
+		for _, info := range infos {
+			record, err := e.getFlow(kt, info.ID)
+			if err != nil && !errf.IsRecordNotFound(err) {
+				return nil, nil, err
+			}
+
+			if record == nil || record.table.State != info.Source {
+				return nil, nil, errf.Newf(errf.RecordNotUpdate, "flow[%s] update state: `%s`->`%s`, worker: %+v failed",
+					info.ID, info.Source, info.Target, info.Worker)
+			}
+
+			record.table.State = info.Target
+			if info.Worker != nil {
+				record.table.Worker = info.Worker
+			}
+			if info.Reason != nil {
+				record.table.Reason = info.Reason
+			}
+			record.table.UpdatedAt = tabletypes.Time(times.ConvStdTimeFormat(times.ConvStdTimeNow()))
+
+			cmp, putOps, err := e.flowPutOps(record)
+			if err != nil {
+				return nil, nil, err
+			}
+			cmps = append(cmps, cmp)
+			ops = append(ops, putOps...)
+		}
+
+		return cmps, ops, nil
+	}
+
+	return e.commitWithRetry(kt, prepare)
+}
+
+// ListFlow 查询任务流
+func (e *etcd) ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error) {
+
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	columnTypes := tableasync.AsyncFlowColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	// count 请求在mysql实现中不返回详情，这里保持一致
+	if opt.Page.Count {
+		return make([]model.Flow, 0), nil
+	}
+
+	records, err := e.listFlowRecords(kt, opt.Filter)
+	if err != nil {
+		logs.Errorf("list flow from etcd failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	matched, err := filterAndPage(records, opt.Filter, opt.Page, columnTypes, input.ThenBy...)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make([]model.Flow, 0, len(matched))
+	for _, one := range matched {
+		flows = append(flows, convFlowTableToModel(one))
+	}
+
+	return flows, nil
+}
+
+// BatchCreateTask 批量创建任务
+func (e *etcd) BatchCreateTask(kt *kit.Kit, tasks []model.Task) ([]string, error) {
+
+	ids, err := e.genIDs(kt, table.AsyncFlowTaskTable, len(tasks))
+	if err != nil {
+		return nil, err
+	}
+
+	now := tabletypes.Time(times.ConvStdTimeFormat(times.ConvStdTimeNow()))
+	cmps := make([]etcd3.Cmp, 0, len(tasks))
+	ops := make([]etcd3.Op, 0, len(tasks))
+	for idx, one := range tasks {
+		md := tableasync.AsyncFlowTaskTable{
+			ID:               ids[idx],
+			FlowID:           one.FlowID,
+			FlowName:         one.FlowName,
+			ActionID:         string(one.ActionID),
+			ActionName:       one.ActionName,
+			Kind:             one.GetKind(),
+			Params:           one.Params,
+			Retry:            one.Retry,
+			DependOn:         dependOnToStringArray(one.DependOn),
+			ExecCondition:    one.Condition,
+			CompensateAction: one.CompensateAction,
+			State:            enumor.TaskPending,
+			Reason:           one.Reason,
+			Creator:          one.Creator,
+			Reviser:          one.Reviser,
+		}
+		if err = md.InsertValidate(); err != nil {
+			return nil, err
+		}
+		md.CreatedAt, md.UpdatedAt = now, now
+		value, err := json.Marshal(md)
+		if err != nil {
+			return nil, err
+		}
+
+		key := e.taskKey(md.ID)
+		cmps = append(cmps, etcd3.Compare(etcd3.CreateRevision(key), "=", 0))
+		ops = append(ops, etcd3.OpPut(key, string(value)))
+		ops = append(ops, e.taskIndexOps(&md)...)
+	}
+
+	resp, err := e.cli.Txn(kt.Ctx).If(cmps...).Then(ops...).Commit()
+	if err != nil {
+		logs.Errorf("batch create task in etcd failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	if !resp.Succeeded {
+		return nil, errors.New("batch create task failed, task already exist")
+	}
+
+	return ids, nil
+}
+
+// UpdateTask 更新任务
+func (e *etcd) UpdateTask(kt *kit.Kit, task *model.Task) error {
+
+	if len(task.ID) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	prepare := func() ([]etcd3.Cmp, []etcd3.Op, error) {
+		record, err := e.getTask(kt, task.ID)
+		if err != nil {
+			if errf.IsRecordNotFound(err) {
+				return nil, nil, errf.New(errf.RecordNotUpdate, "record not update")
+			}
+			return nil, nil, err
+		}
+
+		if task.Retry != nil {
+			record.table.Retry = task.Retry
+		}
+		if len(task.State) != 0 {
+			record.table.State = task.State
+		}
+		if len(task.Result) != 0 {
+			record.table.Result = task.Result
+		}
+		if task.Reason != nil {
+			record.table.Reason = task.Reason
+		}
+		if len(kt.User) != 0 {
+			record.table.Reviser = kt.User
+		}
+		record.table.UpdatedAt = tabletypes.Time(times.ConvStdTimeFormat(times.ConvStdTimeNow()))
+
+		cmp, ops, err := e.taskPutOps(record)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return []etcd3.Cmp{cmp}, ops, nil
+	}
+
+	if err := e.commitWithRetry(kt, prepare); err != nil {
+		logs.Errorf("update task in etcd failed, err: %v, id: %s, rid: %s", err, task.ID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateTaskStateByCAS CAS更新任务状态
+func (e *etcd) UpdateTaskStateByCAS(kt *kit.Kit, info *UpdateTaskInfo) error {
+
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	prepare := func() ([]etcd3.Cmp, []etcd3.Op, error) {
+		record, err := e.getTask(kt, info.ID)
+		if err != nil && !errf.IsRecordNotFound(err) {
+			return nil, nil, err
+		}
+
+		if record == nil || record.table.State != info.Source {
+			return nil, nil, errf.Newf(errf.RecordNotUpdate, "task[%s] update state: `%s`->`%s` failed",
+				info.ID, info.Source, info.Target)
+		}
+
+		record.table.State = info.Target
+		if info.Reason != nil {
+			record.table.Reason = info.Reason
+		}
+		record.table.UpdatedAt = tabletypes.Time(times.ConvStdTimeFormat(times.ConvStdTimeNow()))
+
+		cmp, ops, err := e.taskPutOps(record)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return []etcd3.Cmp{cmp}, ops, nil
+	}
+
+	if err := e.commitWithRetry(kt, prepare); err != nil {
+		logs.Errorf("fail to update task state cas, err: %v, info: %+v, rid: %s", err, info, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ListTask 查询任务
+func (e *etcd) ListTask(kt *kit.Kit, input *ListInput) ([]model.Task, error) {
+
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	columnTypes := tableasync.AsyncFlowTaskColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	// count 请求在mysql实现中不返回详情，这里保持一致
+	if opt.Page.Count {
+		return make([]model.Task, 0), nil
+	}
+
+	records, err := e.listTaskRecords(kt, opt.Filter)
+	if err != nil {
+		logs.Errorf("list task from etcd failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	matched, err := filterAndPage(records, opt.Filter, opt.Page, columnTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]model.Task, 0, len(matched))
+	for _, one := range matched {
+		tasks = append(tasks, convTaskTableToModel(one))
+	}
+
+	return tasks, nil
+}
+
+// RetryTask 重试任务 将flow置为pending, task 置为pending
+func (e *etcd) RetryTask(kt *kit.Kit, flowID, taskID string) error {
+
+	if len(flowID) == 0 || len(taskID) == 0 {
+		return errors.New("empty flow id or task id")
+	}
+
+	reason := &tableasync.Reason{Message: "retry task " + taskID}
+	prepare := func() ([]etcd3.Cmp, []etcd3.Op, error) {
+		flow, err := e.getFlow(kt, flowID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if flow.table.State != enumor.FlowFailed {
+			return nil, nil, fmt.Errorf("flow(%s) state(%s) wrong, only `failed` allowed for retry",
+				flowID, flow.table.State)
+		}
+
+		task, err := e.getTask(kt, taskID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if task.table.FlowID != flowID {
+			return nil, nil, fmt.Errorf("task(%s) of flow(%s) not found", taskID, flowID)
+		}
+		if task.table.State != enumor.TaskFailed {
+			return nil, nil, fmt.Errorf("task(%s) state(%s) wrong, only `failed` allowed for retry",
+				taskID, task.table.State)
+		}
+
+		now := tabletypes.Time(times.ConvStdTimeFormat(times.ConvStdTimeNow()))
+		task.table.State = enumor.TaskPending
+		task.table.Reason = reason
+		task.table.UpdatedAt = now
+		flow.table.State = enumor.FlowPending
+		flow.table.Reason = reason
+		flow.table.UpdatedAt = now
+
+		taskCmp, taskOps, err := e.taskPutOps(task)
+		if err != nil {
+			return nil, nil, err
+		}
+		flowCmp, flowOps, err := e.flowPutOps(flow)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return []etcd3.Cmp{taskCmp, flowCmp}, append(taskOps, flowOps...), nil
+	}
+
+	if err := e.commitWithRetry(kt, prepare); err != nil {
+		logs.Errorf("fail to retry task in etcd, err: %v, flow id: %s, task id: %s, rid: %s",
+			err, flowID, taskID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
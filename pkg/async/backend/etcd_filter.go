@@ -0,0 +1,391 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/runtime/filter"
+)
+
+// memRecord 内存过滤使用的记录，raw 为原始记录，fields 为记录按照json tag展开后的字段
+type memRecord[T any] struct {
+	raw    T
+	fields map[string]interface{}
+}
+
+// filterAndPage 在内存中对记录进行过滤、排序和分页，语义与mysql的查询保持一致，用于不支持SQL查询的存储后端。
+// thenBy 为排序字段取值相同时依次使用的次级排序
+func filterAndPage[T any](records []T, expr *filter.Expression, page *core.BasePage,
+	columnTypes map[string]enumor.ColumnType, thenBy ...types.OrderBy) ([]T, error) {
+
+	matched := make([]memRecord[T], 0, len(records))
+	for _, one := range records {
+		fields, err := toFieldMap(one)
+		if err != nil {
+			return nil, err
+		}
+
+		hit, err := matchExpression(expr, fields, columnTypes)
+		if err != nil {
+			return nil, err
+		}
+
+		if hit {
+			matched = append(matched, memRecord[T]{raw: one, fields: fields})
+		}
+	}
+
+	if page == nil {
+		page = core.NewDefaultBasePage()
+	}
+
+	sortField := page.Sort
+	if len(sortField) == 0 {
+		sortField = "id"
+	}
+	orders := append([]types.OrderBy{{Sort: sortField, Order: page.Order}}, thenBy...)
+
+	var sortErr error
+	sort.SliceStable(matched, func(i, j int) bool {
+		for _, one := range orders {
+			cmp, err := compareValue(matched[i].fields[one.Sort], matched[j].fields[one.Sort],
+				columnTypes[one.Sort])
+			if err != nil {
+				sortErr = fmt.Errorf("sort by %s failed, err: %v", one.Sort, err)
+				return false
+			}
+
+			if cmp == 0 {
+				continue
+			}
+			if one.Order.Order() == core.Descending {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	start, end := 0, len(matched)
+	if page.Start != 0 || page.Limit != 0 {
+		start = int(page.Start)
+		if start > len(matched) {
+			start = len(matched)
+		}
+
+		if end > start+int(page.Limit) {
+			end = start + int(page.Limit)
+		}
+	}
+
+	result := make([]T, 0, end-start)
+	for _, one := range matched[start:end] {
+		result = append(result, one.raw)
+	}
+
+	return result, nil
+}
+
+func toFieldMap(record interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	if err = json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// matchExpression 判断记录是否满足过滤条件
+func matchExpression(expr *filter.Expression, fields map[string]interface{},
+	columnTypes map[string]enumor.ColumnType) (bool, error) {
+
+	if expr == nil || len(expr.Rules) == 0 {
+		return true, nil
+	}
+
+	for _, rule := range expr.Rules {
+		var hit bool
+		var err error
+		switch r := rule.(type) {
+		case filter.AtomRule:
+			hit, err = matchAtomRule(&r, fields, columnTypes)
+		case *filter.AtomRule:
+			hit, err = matchAtomRule(r, fields, columnTypes)
+		case *filter.Expression:
+			hit, err = matchExpression(r, fields, columnTypes)
+		default:
+			return false, fmt.Errorf("unsupported rule type: %T", rule)
+		}
+		if err != nil {
+			return false, err
+		}
+
+		switch expr.Op {
+		case filter.And:
+			if !hit {
+				return false, nil
+			}
+		case filter.Or:
+			if hit {
+				return true, nil
+			}
+		default:
+			return false, fmt.Errorf("unsupported logic operator: %s", expr.Op)
+		}
+	}
+
+	return expr.Op == filter.And, nil
+}
+
+// matchAtomRule 判断记录是否满足单个过滤规则
+func matchAtomRule(rule *filter.AtomRule, fields map[string]interface{},
+	columnTypes map[string]enumor.ColumnType) (bool, error) {
+
+	op := filter.OpType(rule.Op)
+	switch op {
+	case filter.JSONEqual, filter.JSONNotEqual, filter.JSONIn:
+		value := lookupJSONPath(fields, rule.Field)
+		switch op {
+		case filter.JSONEqual:
+			return equalValue(value, normalizeValue(rule.Value), enumor.String)
+		case filter.JSONNotEqual:
+			equal, err := equalValue(value, normalizeValue(rule.Value), enumor.String)
+			return !equal, err
+		default:
+			return inValues(value, rule.Value, enumor.String)
+		}
+	}
+
+	colType := columnTypes[rule.Field]
+	value := fields[rule.Field]
+	switch op {
+	case filter.Equal:
+		return equalValue(value, normalizeValue(rule.Value), colType)
+
+	case filter.NotEqual:
+		equal, err := equalValue(value, normalizeValue(rule.Value), colType)
+		return !equal, err
+
+	case filter.In:
+		return inValues(value, rule.Value, colType)
+
+	case filter.NotIn:
+		in, err := inValues(value, rule.Value, colType)
+		return !in, err
+
+	case filter.GreaterThan, filter.IDGreaterThan, filter.GreaterThanEqual, filter.LessThan, filter.LessThanEqual:
+		// 与mysql中NULL值参与比较的结果保持一致，字段值为空时不满足任何比较条件
+		if value == nil {
+			return false, nil
+		}
+
+		cmp, err := compareValue(value, normalizeValue(rule.Value), colType)
+		if err != nil {
+			return false, fmt.Errorf("field %s compare failed, err: %v", rule.Field, err)
+		}
+
+		switch op {
+		case filter.GreaterThan, filter.IDGreaterThan:
+			return cmp > 0, nil
+		case filter.GreaterThanEqual:
+			return cmp >= 0, nil
+		case filter.LessThan:
+			return cmp < 0, nil
+		default:
+			return cmp <= 0, nil
+		}
+
+	case filter.ContainsSensitive:
+		return strings.Contains(fmt.Sprint(value), fmt.Sprint(rule.Value)), nil
+
+	case filter.ContainsInsensitive:
+		return strings.Contains(strings.ToLower(fmt.Sprint(value)), strings.ToLower(fmt.Sprint(rule.Value))), nil
+
+	default:
+		return false, fmt.Errorf("operator %s is not supported by memory filter", op)
+	}
+}
+
+// lookupJSONPath 根据 a.b.c 格式的字段路径获取json字段的值
+func lookupJSONPath(fields map[string]interface{}, field string) interface{} {
+	var current interface{} = fields
+	for _, key := range strings.Split(field, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+
+	return current
+}
+
+func inValues(value interface{}, values interface{}, colType enumor.ColumnType) (bool, error) {
+	list, ok := normalizeValue(values).([]interface{})
+	if !ok {
+		return false, fmt.Errorf("in operator value should be an array, but got %T", values)
+	}
+
+	for _, one := range list {
+		equal, err := equalValue(value, one, colType)
+		if err != nil {
+			return false, err
+		}
+
+		if equal {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func equalValue(a, b interface{}, colType enumor.ColumnType) (bool, error) {
+	if a == nil || b == nil {
+		return a == nil && b == nil, nil
+	}
+
+	if colType == enumor.Time {
+		cmp, err := compareValue(a, b, colType)
+		if err != nil {
+			return false, err
+		}
+		return cmp == 0, nil
+	}
+
+	return reflect.DeepEqual(a, b), nil
+}
+
+// compareValue 比较两个值的大小，a < b 返回-1，a == b 返回0，a > b 返回1，nil 视为最小值
+func compareValue(a, b interface{}, colType enumor.ColumnType) (int, error) {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0, nil
+		case a == nil:
+			return -1, nil
+		default:
+			return 1, nil
+		}
+	}
+
+	if colType == enumor.Time {
+		ta, err := parseTimeValue(a)
+		if err != nil {
+			return 0, err
+		}
+		tb, err := parseTimeValue(b)
+		if err != nil {
+			return 0, err
+		}
+		return ta.Compare(tb), nil
+	}
+
+	switch va := a.(type) {
+	case float64:
+		vb, ok := b.(float64)
+		if !ok {
+			return 0, fmt.Errorf("can not compare %T with %T", a, b)
+		}
+		switch {
+		case va < vb:
+			return -1, nil
+		case va > vb:
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	case string:
+		vb, ok := b.(string)
+		if !ok {
+			return 0, fmt.Errorf("can not compare %T with %T", a, b)
+		}
+		return strings.Compare(va, vb), nil
+	default:
+		return 0, fmt.Errorf("unsupported compare type: %T", a)
+	}
+}
+
+func parseTimeValue(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		return time.ParseInLocation(constant.TimeStdFormat, t, time.Local)
+	default:
+		return time.Time{}, fmt.Errorf("unsupported time value type: %T", v)
+	}
+}
+
+// normalizeValue 将过滤规则中的值转换为与json反序列化结果相同的类型，便于比较
+func normalizeValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	if t, ok := v.(time.Time); ok {
+		return t
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Slice, reflect.Array:
+		list := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			list = append(list, normalizeValue(rv.Index(i).Interface()))
+		}
+		return list
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return normalizeValue(rv.Elem().Interface())
+	default:
+		return v
+	}
+}
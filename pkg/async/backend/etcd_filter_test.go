@@ -0,0 +1,303 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	tableasync "hcm/pkg/dal/table/async"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+)
+
+func testTasks() []tableasync.AsyncFlowTaskTable {
+	now := times.ConvStdTimeNow()
+	return []tableasync.AsyncFlowTaskTable{
+		{
+			ID:        "00000001",
+			FlowID:    "flow1",
+			State:     enumor.TaskRunning,
+			Reason:    &tableasync.Reason{Message: "a"},
+			UpdatedAt: tabletypes.Time(times.ConvStdTimeFormat(now.Add(-time.Hour))),
+		},
+		{
+			ID:        "00000002",
+			FlowID:    "flow1",
+			State:     enumor.TaskPending,
+			Reason:    &tableasync.Reason{Message: "b"},
+			UpdatedAt: tabletypes.Time(times.ConvStdTimeFormat(now)),
+		},
+		{
+			ID:        "00000003",
+			FlowID:    "flow2",
+			State:     enumor.TaskRollback,
+			Reason:    &tableasync.Reason{Message: "c"},
+			UpdatedAt: tabletypes.Time(times.ConvStdTimeFormat(now.Add(-2 * time.Hour))),
+		},
+	}
+}
+
+func taskIDs(tasks []tableasync.AsyncFlowTaskTable) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, one := range tasks {
+		ids = append(ids, one.ID)
+	}
+	return ids
+}
+
+func TestFilterAndPage(t *testing.T) {
+	columnTypes := tableasync.AsyncFlowTaskColumns.ColumnTypes()
+	expireTime := times.ConvStdTimeFormat(times.ConvStdTimeNow().Add(-30 * time.Minute))
+
+	tests := []struct {
+		name   string
+		filter *filter.Expression
+		page   *core.BasePage
+		want   []string
+	}{
+		{
+			name:   "equal",
+			filter: tools.EqualExpression("flow_id", "flow1"),
+			page:   core.NewDefaultBasePage(),
+			want:   []string{"00000001", "00000002"},
+		},
+		{
+			name: "in and less than time",
+			filter: tools.ExpressionAnd(
+				tools.RuleIn("state", []enumor.TaskState{enumor.TaskRunning, enumor.TaskRollback}),
+				&filter.AtomRule{Field: "updated_at", Op: filter.LessThan.Factory(), Value: expireTime},
+			),
+			page: core.NewDefaultBasePage(),
+			want: []string{"00000001", "00000003"},
+		},
+		{
+			name:   "not in",
+			filter: tools.ExpressionAnd(tools.RuleNotIn("flow_id", []string{"flow1"})),
+			page:   core.NewDefaultBasePage(),
+			want:   []string{"00000003"},
+		},
+		{
+			name: "or with json equal",
+			filter: &filter.Expression{
+				Op: filter.Or,
+				Rules: []filter.RuleFactory{
+					tools.RuleJSONEqual("reason.message", "b"),
+					tools.RuleEqual("id", "00000003"),
+				},
+			},
+			page: core.NewDefaultBasePage(),
+			want: []string{"00000002", "00000003"},
+		},
+		{
+			name:   "page with desc order",
+			filter: tools.AllExpression(),
+			page:   &core.BasePage{Start: 1, Limit: 1, Sort: "id", Order: core.Descending},
+			want:   []string{"00000002"},
+		},
+		{
+			name:   "start out of range",
+			filter: tools.AllExpression(),
+			page:   &core.BasePage{Start: 10, Limit: 1},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterAndPage(testTasks(), tt.filter, tt.page, columnTypes)
+			if err != nil {
+				t.Fatalf("filter and page failed, err: %v", err)
+			}
+
+			gotIDs := taskIDs(got)
+			if len(gotIDs) != len(tt.want) {
+				t.Fatalf("expect %v, but got %v", tt.want, gotIDs)
+			}
+			for idx := range gotIDs {
+				if gotIDs[idx] != tt.want[idx] {
+					t.Fatalf("expect %v, but got %v", tt.want, gotIDs)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterAndPageThenBy(t *testing.T) {
+	now := times.ConvStdTimeNow()
+	createdAt := func(sec int) tabletypes.Time {
+		return tabletypes.Time(times.ConvStdTimeFormat(now.Add(time.Duration(sec) * time.Second)))
+	}
+	flows := []tableasync.AsyncFlowTable{
+		{ID: "00000004", Priority: enumor.FlowPriorityNormal, CreatedAt: createdAt(2)},
+		{ID: "00000003", Priority: enumor.FlowPriorityNormal, CreatedAt: createdAt(1)},
+		{ID: "00000002", Priority: enumor.FlowPriorityNormal, CreatedAt: createdAt(1)},
+		{ID: "00000001", Priority: enumor.FlowPriorityHigh, CreatedAt: createdAt(3)},
+	}
+	columnTypes := tableasync.AsyncFlowColumns.ColumnTypes()
+	page := &core.BasePage{Limit: core.DefaultMaxPageLimit, Sort: "priority", Order: core.Descending}
+
+	got, err := filterAndPage(flows, tools.AllExpression(), page, columnTypes,
+		types.OrderBy{Sort: "created_at", Order: core.Ascending}, types.OrderBy{Sort: "id", Order: core.Ascending})
+	if err != nil {
+		t.Fatalf("filter and page failed, err: %v", err)
+	}
+
+	want := []string{"00000001", "00000002", "00000003", "00000004"}
+	for idx := range want {
+		if idx >= len(got) || got[idx].ID != want[idx] {
+			t.Fatalf("expect %v, but got %+v", want, got)
+		}
+	}
+}
+
+func TestMatchFlowWorker(t *testing.T) {
+	flows := []tableasync.AsyncFlowTable{
+		{ID: "00000001", State: enumor.FlowPending, Worker: converter.ValToPtr("")},
+		{ID: "00000002", State: enumor.FlowPending, Worker: converter.ValToPtr("node-1")},
+		{ID: "00000003", State: enumor.FlowRunning, Worker: converter.ValToPtr("node-2")},
+	}
+	columnTypes := tableasync.AsyncFlowColumns.ColumnTypes()
+
+	got, err := filterAndPage(flows, tools.ExpressionAnd(
+		tools.RuleEqual("worker", ""),
+		tools.RuleEqual("state", enumor.FlowPending),
+	), core.NewDefaultBasePage(), columnTypes)
+	if err != nil {
+		t.Fatalf("filter flow failed, err: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "00000001" {
+		t.Errorf("expect flow 00000001, but got %+v", got)
+	}
+
+	got, err = filterAndPage(flows, tools.ExpressionAnd(
+		tools.RuleNotIn("worker", []string{"node-1", "node-2"}),
+	), core.NewDefaultBasePage(), columnTypes)
+	if err != nil {
+		t.Fatalf("filter flow failed, err: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "00000001" {
+		t.Errorf("expect flow 00000001, but got %+v", got)
+	}
+}
+
+func TestMatchFlowDeadline(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	flows := []tableasync.AsyncFlowTable{
+		{ID: "00000001", State: enumor.FlowRunning, DeadlineAt: converter.ValToPtr(now.Add(-time.Minute))},
+		{ID: "00000002", State: enumor.FlowRunning, DeadlineAt: converter.ValToPtr(now.Add(time.Minute))},
+		{ID: "00000003", State: enumor.FlowRunning},
+	}
+	columnTypes := tableasync.AsyncFlowColumns.ColumnTypes()
+
+	// 未设置截止时间的任务流不满足任何比较条件
+	got, err := filterAndPage(flows, tools.ExpressionAnd(
+		&filter.AtomRule{Field: "deadline_at", Op: filter.LessThan.Factory(), Value: times.ConvStdTimeFormat(now)},
+	), core.NewDefaultBasePage(), columnTypes)
+	if err != nil {
+		t.Fatalf("filter flow failed, err: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "00000001" {
+		t.Errorf("expect flow 00000001, but got %+v", got)
+	}
+
+	got, err = filterAndPage(flows, tools.ExpressionAnd(
+		tools.RuleGreaterThanEqual("deadline_at", times.ConvStdTimeFormat(now)),
+	), core.NewDefaultBasePage(), columnTypes)
+	if err != nil {
+		t.Fatalf("filter flow failed, err: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "00000002" {
+		t.Errorf("expect flow 00000002, but got %+v", got)
+	}
+}
+
+func TestIndexValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  *filter.Expression
+		field   string
+		want    []string
+		indexed bool
+	}{
+		{
+			name:    "equal",
+			filter:  tools.EqualExpression("state", enumor.FlowPending),
+			field:   "state",
+			want:    []string{"pending"},
+			indexed: true,
+		},
+		{
+			name: "in with other rules",
+			filter: tools.ExpressionAnd(
+				tools.RuleEqual("worker", ""),
+				tools.RuleIn("state", []enumor.FlowState{enumor.FlowScheduled, enumor.FlowRunning, enumor.FlowRunning}),
+			),
+			field:   "state",
+			want:    []string{"scheduled", "running"},
+			indexed: true,
+		},
+		{
+			name:    "field not in filter",
+			filter:  tools.EqualExpression("worker", "w1"),
+			field:   "state",
+			indexed: false,
+		},
+		{
+			name: "or expression",
+			filter: &filter.Expression{
+				Op:    filter.Or,
+				Rules: []filter.RuleFactory{tools.RuleEqual("state", enumor.FlowPending)},
+			},
+			field:   "state",
+			indexed: false,
+		},
+		{
+			name:    "not in",
+			filter:  tools.ExpressionAnd(tools.RuleNotIn("state", []enumor.FlowState{enumor.FlowPending})),
+			field:   "state",
+			indexed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, indexed := indexValues(tt.filter, tt.field)
+			if indexed != tt.indexed {
+				t.Fatalf("expect indexed %v, but got %v", tt.indexed, indexed)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("expect %v, but got %v", tt.want, got)
+			}
+			for idx := range got {
+				if got[idx] != tt.want[idx] {
+					t.Fatalf("expect %v, but got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,475 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+
+	etcd3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcdIndexVersion etcd backend 索引版本，索引结构变化时需要升级版本以重建索引
+const etcdIndexVersion = "1"
+
+// finishedFlowStates 已经结束的任务流状态，过期后可以被清理
+var finishedFlowStates = []enumor.FlowState{enumor.FlowSuccess, enumor.FlowFailed, enumor.FlowCancel}
+
+func (e *etcd) flowStateIndexKey(state enumor.FlowState, id string) string {
+	return path.Join(e.flowStateIndexPrefix(state), id)
+}
+
+func (e *etcd) flowStateIndexPrefix(state enumor.FlowState) string {
+	return path.Join(e.prefix, "flow_state", string(state)) + "/"
+}
+
+func (e *etcd) taskStateIndexKey(state enumor.TaskState, id string) string {
+	return path.Join(e.taskStateIndexPrefix(state), id)
+}
+
+func (e *etcd) taskStateIndexPrefix(state enumor.TaskState) string {
+	return path.Join(e.prefix, "task_state", string(state)) + "/"
+}
+
+func (e *etcd) taskFlowIndexKey(flowID, id string) string {
+	return path.Join(e.taskFlowIndexPrefix(flowID), id)
+}
+
+func (e *etcd) taskFlowIndexPrefix(flowID string) string {
+	return path.Join(e.prefix, "task_flow", flowID) + "/"
+}
+
+func (e *etcd) indexVersionKey() string {
+	return path.Join(e.prefix, "index_version")
+}
+
+// taskIndexOps 生成任务的索引写操作
+func (e *etcd) taskIndexOps(task *tableasync.AsyncFlowTaskTable) []etcd3.Op {
+	return []etcd3.Op{
+		etcd3.OpPut(e.taskStateIndexKey(task.State, task.ID), ""),
+		etcd3.OpPut(e.taskFlowIndexKey(task.FlowID, task.ID), ""),
+	}
+}
+
+// etcdKV etcd中读取到的键值以及其版本
+type etcdKV struct {
+	key         string
+	value       []byte
+	modRevision int64
+}
+
+// batchGet 分批读取指定key的值，不存在的key会被忽略
+func (e *etcd) batchGet(kt *kit.Kit, keys []string) ([]etcdKV, error) {
+	kvs := make([]etcdKV, 0, len(keys))
+	for _, batch := range slice.Split(keys, etcdMaxTxnOps) {
+		ops := make([]etcd3.Op, 0, len(batch))
+		for _, key := range batch {
+			ops = append(ops, etcd3.OpGet(key))
+		}
+
+		resp, err := e.cli.Txn(kt.Ctx).Then(ops...).Commit()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, one := range resp.Responses {
+			for _, kv := range one.GetResponseRange().Kvs {
+				kvs = append(kvs, etcdKV{key: string(kv.Key), value: kv.Value, modRevision: kv.ModRevision})
+			}
+		}
+	}
+
+	return kvs, nil
+}
+
+// getPrefix 读取指定前缀下的全部键值
+func (e *etcd) getPrefix(kt *kit.Kit, prefix string) ([]etcdKV, error) {
+	resp, err := e.cli.Get(kt.Ctx, prefix, etcd3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	kvs := make([]etcdKV, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		kvs = append(kvs, etcdKV{key: string(kv.Key), value: kv.Value, modRevision: kv.ModRevision})
+	}
+
+	return kvs, nil
+}
+
+// listIndexIDs 查询索引前缀下的记录ID
+func (e *etcd) listIndexIDs(kt *kit.Kit, prefixes []string) ([]string, error) {
+	ids := make([]string, 0)
+	for _, prefix := range prefixes {
+		resp, err := e.cli.Get(kt.Ctx, prefix, etcd3.WithPrefix(), etcd3.WithKeysOnly())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, kv := range resp.Kvs {
+			ids = append(ids, path.Base(string(kv.Key)))
+		}
+	}
+
+	return slice.Unique(ids), nil
+}
+
+// indexValues 从过滤条件中提取指定字段可以用于索引查询的取值，仅处理顶层为AND的 equal 和 in 规则。
+// 返回的取值只用于确定候选记录，候选记录仍需要经过完整的过滤条件过滤。
+func indexValues(expr *filter.Expression, field string) ([]string, bool) {
+	if expr == nil || expr.Op != filter.And {
+		return nil, false
+	}
+
+	for _, rule := range expr.Rules {
+		var atom *filter.AtomRule
+		switch r := rule.(type) {
+		case filter.AtomRule:
+			atom = &r
+		case *filter.AtomRule:
+			atom = r
+		default:
+			continue
+		}
+
+		if atom.Field != field {
+			continue
+		}
+
+		var values []interface{}
+		switch filter.OpType(atom.Op) {
+		case filter.Equal:
+			values = []interface{}{normalizeValue(atom.Value)}
+		case filter.In:
+			list, ok := normalizeValue(atom.Value).([]interface{})
+			if !ok {
+				continue
+			}
+			values = list
+		default:
+			continue
+		}
+
+		result := make([]string, 0, len(values))
+		for _, one := range values {
+			str, ok := one.(string)
+			if !ok || len(str) == 0 {
+				return nil, false
+			}
+			result = append(result, str)
+		}
+		return slice.Unique(result), true
+	}
+
+	return nil, false
+}
+
+// listFlowRecords 查询可能满足过滤条件的任务流，能够使用索引时只读取索引命中的任务流
+func (e *etcd) listFlowRecords(kt *kit.Kit, expr *filter.Expression) ([]tableasync.AsyncFlowTable, error) {
+	ids, indexed := indexValues(expr, "id")
+	if !indexed {
+		states, ok := indexValues(expr, "state")
+		if ok {
+			if err := e.ensureIndex(kt); err != nil {
+				return nil, err
+			}
+
+			prefixes := make([]string, 0, len(states))
+			for _, state := range states {
+				prefixes = append(prefixes, e.flowStateIndexPrefix(enumor.FlowState(state)))
+			}
+
+			var err error
+			if ids, err = e.listIndexIDs(kt, prefixes); err != nil {
+				return nil, err
+			}
+			indexed = true
+		}
+	}
+
+	var kvs []etcdKV
+	var err error
+	if indexed {
+		kvs, err = e.batchGet(kt, slice.Map(ids, e.flowKey))
+	} else {
+		kvs, err = e.getPrefix(kt, e.flowKeyPrefix())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]tableasync.AsyncFlowTable, 0, len(kvs))
+	for _, kv := range kvs {
+		one := tableasync.AsyncFlowTable{}
+		if err = json.Unmarshal(kv.value, &one); err != nil {
+			return nil, fmt.Errorf("unmarshal flow %s failed, err: %v", kv.key, err)
+		}
+		records = append(records, one)
+	}
+
+	return records, nil
+}
+
+// listTaskRecords 查询可能满足过滤条件的任务，能够使用索引时只读取索引命中的任务
+func (e *etcd) listTaskRecords(kt *kit.Kit, expr *filter.Expression) ([]tableasync.AsyncFlowTaskTable, error) {
+	ids, indexed := indexValues(expr, "id")
+	if !indexed {
+		prefixes := make([]string, 0)
+		if flowIDs, ok := indexValues(expr, "flow_id"); ok {
+			for _, flowID := range flowIDs {
+				prefixes = append(prefixes, e.taskFlowIndexPrefix(flowID))
+			}
+		} else if states, ok := indexValues(expr, "state"); ok {
+			for _, state := range states {
+				prefixes = append(prefixes, e.taskStateIndexPrefix(enumor.TaskState(state)))
+			}
+		}
+
+		if len(prefixes) != 0 {
+			if err := e.ensureIndex(kt); err != nil {
+				return nil, err
+			}
+
+			var err error
+			if ids, err = e.listIndexIDs(kt, prefixes); err != nil {
+				return nil, err
+			}
+			indexed = true
+		}
+	}
+
+	var kvs []etcdKV
+	var err error
+	if indexed {
+		kvs, err = e.batchGet(kt, slice.Map(ids, e.taskKey))
+	} else {
+		kvs, err = e.getPrefix(kt, e.taskKeyPrefix())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]tableasync.AsyncFlowTaskTable, 0, len(kvs))
+	for _, kv := range kvs {
+		one := tableasync.AsyncFlowTaskTable{}
+		if err = json.Unmarshal(kv.value, &one); err != nil {
+			return nil, fmt.Errorf("unmarshal task %s failed, err: %v", kv.key, err)
+		}
+		records = append(records, one)
+	}
+
+	return records, nil
+}
+
+// ensureIndex 确认索引已经构建完成，升级前写入的任务流和任务没有索引，首次使用索引时根据已有记录重建。
+// 注意：重建完成后旧版本节点写入的记录不会维护索引，升级时需要先停止全部旧版本的 task-server。
+func (e *etcd) ensureIndex(kt *kit.Kit) error {
+	if e.indexReady.Load() {
+		return nil
+	}
+
+	resp, err := e.cli.Get(kt.Ctx, e.indexVersionKey())
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Kvs) == 0 || string(resp.Kvs[0].Value) != etcdIndexVersion {
+		if err = e.rebuildIndex(kt); err != nil {
+			logs.Errorf("rebuild etcd backend index failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+
+		if _, err = e.cli.Put(kt.Ctx, e.indexVersionKey(), etcdIndexVersion); err != nil {
+			return err
+		}
+	}
+
+	e.indexReady.Store(true)
+	return nil
+}
+
+// rebuildIndex 根据已有的任务流和任务重建索引，写入索引时比较记录的 ModRevision，
+// 记录已经被修改时修改操作已经写入了最新的索引，不会再写入过期的索引。
+func (e *etcd) rebuildIndex(kt *kit.Kit) error {
+	flowKvs, err := e.getPrefix(kt, e.flowKeyPrefix())
+	if err != nil {
+		return err
+	}
+
+	for _, kv := range flowKvs {
+		one := tableasync.AsyncFlowTable{}
+		if err = json.Unmarshal(kv.value, &one); err != nil {
+			return fmt.Errorf("unmarshal flow %s failed, err: %v", kv.key, err)
+		}
+
+		ops := []etcd3.Op{etcd3.OpPut(e.flowStateIndexKey(one.State, one.ID), "")}
+		if err = e.putIndexIfUnchanged(kt, kv, ops); err != nil {
+			return err
+		}
+	}
+
+	taskKvs, err := e.getPrefix(kt, e.taskKeyPrefix())
+	if err != nil {
+		return err
+	}
+
+	for _, kv := range taskKvs {
+		one := tableasync.AsyncFlowTaskTable{}
+		if err = json.Unmarshal(kv.value, &one); err != nil {
+			return fmt.Errorf("unmarshal task %s failed, err: %v", kv.key, err)
+		}
+
+		if err = e.putIndexIfUnchanged(kt, kv, e.taskIndexOps(&one)); err != nil {
+			return err
+		}
+	}
+
+	logs.Infof("rebuild etcd backend index success, flow count: %d, task count: %d, rid: %s", len(flowKvs),
+		len(taskKvs), kt.Rid)
+
+	return nil
+}
+
+func (e *etcd) putIndexIfUnchanged(kt *kit.Kit, kv etcdKV, ops []etcd3.Op) error {
+	_, err := e.cli.Txn(kt.Ctx).If(etcd3.Compare(etcd3.ModRevision(kv.key), "=", kv.modRevision)).
+		Then(ops...).Commit()
+	return err
+}
+
+// DeleteExpiredFlow 删除更新时间早于before的已结束任务流及其任务
+func (e *etcd) DeleteExpiredFlow(kt *kit.Kit, before time.Time, limit uint) (uint, error) {
+
+	if limit == 0 {
+		return 0, errors.New("limit is required")
+	}
+
+	if err := e.ensureIndex(kt); err != nil {
+		return 0, err
+	}
+
+	prefixes := make([]string, 0, len(finishedFlowStates))
+	for _, state := range finishedFlowStates {
+		prefixes = append(prefixes, e.flowStateIndexPrefix(state))
+	}
+	ids, err := e.listIndexIDs(kt, prefixes)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted uint
+	for _, batch := range slice.Split(ids, etcdMaxTxnOps) {
+		kvs, err := e.batchGet(kt, slice.Map(batch, e.flowKey))
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, kv := range kvs {
+			if deleted >= limit {
+				return deleted, nil
+			}
+
+			one := tableasync.AsyncFlowTable{}
+			if err = json.Unmarshal(kv.value, &one); err != nil {
+				return deleted, fmt.Errorf("unmarshal flow %s failed, err: %v", kv.key, err)
+			}
+
+			if !slice.IsItemInSlice(finishedFlowStates, one.State) {
+				continue
+			}
+
+			updatedAt, err := parseTimeValue(string(one.UpdatedAt))
+			if err != nil {
+				return deleted, fmt.Errorf("parse flow %s updated_at failed, err: %v", one.ID, err)
+			}
+			if !updatedAt.Before(before) {
+				continue
+			}
+
+			ok, err := e.deleteFlow(kt, kv, &one)
+			if err != nil {
+				logs.Errorf("delete expired flow from etcd failed, err: %v, id: %s, rid: %s", err, one.ID, kt.Rid)
+				return deleted, err
+			}
+			if ok {
+				deleted++
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// deleteFlow 删除任务流及其任务，先通过CAS删除任务流，保证删除期间任务流没有被重试，再分批删除任务和索引。
+// 删除任务失败时残留的任务不会影响任务流的调度，返回任务流是否被删除。
+func (e *etcd) deleteFlow(kt *kit.Kit, kv etcdKV, flow *tableasync.AsyncFlowTable) (bool, error) {
+	resp, err := e.cli.Txn(kt.Ctx).
+		If(etcd3.Compare(etcd3.ModRevision(kv.key), "=", kv.modRevision)).
+		Then(etcd3.OpDelete(kv.key), etcd3.OpDelete(e.flowStateIndexKey(flow.State, flow.ID))).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+
+	if !resp.Succeeded {
+		// 任务流已经被修改，等待下次清理时重新判断
+		return false, nil
+	}
+
+	taskIDs, err := e.listIndexIDs(kt, []string{e.taskFlowIndexPrefix(flow.ID)})
+	if err != nil {
+		return true, err
+	}
+
+	// 每个任务需要删除任务本身、状态索引和任务流索引三个key
+	for _, batch := range slice.Split(taskIDs, etcdMaxTxnOps/3) {
+		taskKvs, err := e.batchGet(kt, slice.Map(batch, e.taskKey))
+		if err != nil {
+			return true, err
+		}
+
+		ops := make([]etcd3.Op, 0, len(batch)*3)
+		for _, taskKv := range taskKvs {
+			task := tableasync.AsyncFlowTaskTable{}
+			if err = json.Unmarshal(taskKv.value, &task); err != nil {
+				return true, fmt.Errorf("unmarshal task %s failed, err: %v", taskKv.key, err)
+			}
+			ops = append(ops, etcd3.OpDelete(taskKv.key), etcd3.OpDelete(e.taskStateIndexKey(task.State, task.ID)))
+		}
+		for _, id := range batch {
+			ops = append(ops, etcd3.OpDelete(e.taskFlowIndexKey(flow.ID, id)))
+		}
+
+		if _, err = e.cli.Txn(kt.Ctx).Then(ops...).Commit(); err != nil {
+			return true, err
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,332 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	tableasync "hcm/pkg/dal/table/async"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+
+	etcd3 "go.etcd.io/etcd/client/v3"
+)
+
+func (e *etcd) scheduledFlowKey(id string) string {
+	return path.Join(e.prefix, "scheduled_flow", id)
+}
+
+func (e *etcd) scheduledFlowKeyPrefix() string {
+	return path.Join(e.prefix, "scheduled_flow") + "/"
+}
+
+func (e *etcd) scheduledFlowNameKey(name string) string {
+	return path.Join(e.prefix, "scheduled_flow_name", name)
+}
+
+// scheduledFlowRecord 定时任务流记录以及其在etcd中的版本
+type scheduledFlowRecord struct {
+	table       tableasync.AsyncScheduledFlowTable
+	modRevision int64
+}
+
+func (e *etcd) getScheduledFlow(kt *kit.Kit, id string) (*scheduledFlowRecord, error) {
+	resp, err := e.cli.Get(kt.Ctx, e.scheduledFlowKey(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Kvs) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "scheduled flow %s not found", id)
+	}
+
+	record := &scheduledFlowRecord{modRevision: resp.Kvs[0].ModRevision}
+	if err = json.Unmarshal(resp.Kvs[0].Value, &record.table); err != nil {
+		return nil, fmt.Errorf("unmarshal scheduled flow %s failed, err: %v", id, err)
+	}
+
+	return record, nil
+}
+
+// scheduledFlowPutOps 生成更新定时任务流的比较条件和写操作
+func (e *etcd) scheduledFlowPutOps(record *scheduledFlowRecord) (etcd3.Cmp, etcd3.Op, error) {
+	value, err := json.Marshal(record.table)
+	if err != nil {
+		return etcd3.Cmp{}, etcd3.Op{}, err
+	}
+
+	key := e.scheduledFlowKey(record.table.ID)
+	return etcd3.Compare(etcd3.ModRevision(key), "=", record.modRevision), etcd3.OpPut(key, string(value)), nil
+}
+
+// CreateScheduledFlow 创建定时任务流
+func (e *etcd) CreateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) (string, error) {
+
+	md, err := convScheduledFlowModelToTable(flow)
+	if err != nil {
+		return "", err
+	}
+
+	ids, err := e.genIDs(kt, table.AsyncScheduledFlowTable, 1)
+	if err != nil {
+		return "", err
+	}
+	md.ID = ids[0]
+	md.Creator = kt.User
+	md.Reviser = kt.User
+	if md.LastFlowID == nil {
+		md.LastFlowID = converter.ValToPtr("")
+	}
+	if err = md.InsertValidate(); err != nil {
+		return "", err
+	}
+
+	now := tabletypes.Time(times.ConvStdTimeFormat(times.ConvStdTimeNow()))
+	md.CreatedAt, md.UpdatedAt = now, now
+	value, err := json.Marshal(md)
+	if err != nil {
+		return "", err
+	}
+
+	key := e.scheduledFlowKey(md.ID)
+	nameKey := e.scheduledFlowNameKey(md.Name)
+	resp, err := e.cli.Txn(kt.Ctx).
+		If(etcd3.Compare(etcd3.CreateRevision(key), "=", 0), etcd3.Compare(etcd3.CreateRevision(nameKey), "=", 0)).
+		Then(etcd3.OpPut(key, string(value)), etcd3.OpPut(nameKey, md.ID)).
+		Commit()
+	if err != nil {
+		logs.Errorf("create scheduled flow in etcd failed, err: %v, name: %s, rid: %s", err, md.Name, kt.Rid)
+		return "", err
+	}
+
+	if !resp.Succeeded {
+		return "", fmt.Errorf("create scheduled flow failed, scheduled flow(%s) already exist", md.Name)
+	}
+
+	return md.ID, nil
+}
+
+// UpdateScheduledFlow 更新定时任务流
+func (e *etcd) UpdateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) error {
+
+	if len(flow.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	md, err := convScheduledFlowModelToTable(flow)
+	if err != nil {
+		return err
+	}
+	md.Name = ""
+	md.Reviser = kt.User
+	if err = md.UpdateValidate(); err != nil {
+		return err
+	}
+
+	prepare := func() ([]etcd3.Cmp, []etcd3.Op, error) {
+		record, err := e.getScheduledFlow(kt, flow.ID)
+		if err != nil {
+			if errf.IsRecordNotFound(err) {
+				return nil, nil, errf.New(errf.RecordNotUpdate, "record not update")
+			}
+			return nil, nil, err
+		}
+
+		mergeScheduledFlowUpdate(&record.table, md)
+		cmp, op, err := e.scheduledFlowPutOps(record)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return []etcd3.Cmp{cmp}, []etcd3.Op{op}, nil
+	}
+
+	return e.commitWithRetry(kt, prepare)
+}
+
+// mergeScheduledFlowUpdate 将需要更新的字段合并到原记录中，与mysql中只更新非零值字段的语义保持一致
+func mergeScheduledFlowUpdate(dst *tableasync.AsyncScheduledFlowTable, src *tableasync.AsyncScheduledFlowTable) {
+	if len(src.FlowName) != 0 {
+		dst.FlowName = src.FlowName
+	}
+
+	if len(src.Spec) != 0 {
+		dst.Spec = src.Spec
+	}
+
+	if src.Tasks != nil {
+		dst.Tasks = src.Tasks
+	}
+
+	if src.Memo != nil {
+		dst.Memo = src.Memo
+	}
+
+	if len(src.State) != 0 {
+		dst.State = src.State
+	}
+
+	if !src.NextTriggerAt.IsZero() {
+		dst.NextTriggerAt = src.NextTriggerAt
+	}
+
+	if len(src.Reviser) != 0 {
+		dst.Reviser = src.Reviser
+	}
+
+	dst.UpdatedAt = tabletypes.Time(times.ConvStdTimeFormat(times.ConvStdTimeNow()))
+}
+
+// ListScheduledFlow 查询定时任务流
+func (e *etcd) ListScheduledFlow(kt *kit.Kit, input *ListInput) ([]model.ScheduledFlow, error) {
+
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	columnTypes := tableasync.AsyncScheduledFlowColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	// count 请求在mysql实现中不返回详情，这里保持一致
+	if opt.Page.Count {
+		return make([]model.ScheduledFlow, 0), nil
+	}
+
+	resp, err := e.cli.Get(kt.Ctx, e.scheduledFlowKeyPrefix(), etcd3.WithPrefix())
+	if err != nil {
+		logs.Errorf("list scheduled flow from etcd failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	records := make([]tableasync.AsyncScheduledFlowTable, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		one := tableasync.AsyncScheduledFlowTable{}
+		if err = json.Unmarshal(kv.Value, &one); err != nil {
+			return nil, fmt.Errorf("unmarshal scheduled flow %s failed, err: %v", kv.Key, err)
+		}
+		records = append(records, one)
+	}
+
+	matched, err := filterAndPage(records, opt.Filter, opt.Page, columnTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make([]model.ScheduledFlow, 0, len(matched))
+	for _, one := range matched {
+		flows = append(flows, convScheduledFlowTableToModel(one))
+	}
+
+	return flows, nil
+}
+
+// DeleteScheduledFlow 删除定时任务流
+func (e *etcd) DeleteScheduledFlow(kt *kit.Kit, id string) error {
+
+	if len(id) == 0 {
+		return errors.New("id is required")
+	}
+
+	record, err := e.getScheduledFlow(kt, id)
+	if err != nil {
+		if errf.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	_, err = e.cli.Txn(kt.Ctx).
+		Then(etcd3.OpDelete(e.scheduledFlowKey(id)), etcd3.OpDelete(e.scheduledFlowNameKey(record.table.Name))).
+		Commit()
+	if err != nil {
+		logs.Errorf("delete scheduled flow from etcd failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// TriggerScheduledFlow 触发定时任务流，CAS更新下一次触发时间和创建任务流在同一个事务中完成
+func (e *etcd) TriggerScheduledFlow(kt *kit.Kit, info *TriggerScheduledFlowInfo, flow *model.Flow) (
+	string, error) {
+
+	if err := info.Validate(); err != nil {
+		return "", err
+	}
+
+	record, err := e.getScheduledFlow(kt, info.ID)
+	if err != nil {
+		if errf.IsRecordNotFound(err) {
+			return "", errf.Newf(errf.RecordNotUpdate, "scheduled flow[%s] not found", info.ID)
+		}
+		return "", err
+	}
+
+	if record.table.State != enumor.ScheduledFlowEnabled || !record.table.NextTriggerAt.Equal(info.Source) {
+		return "", errf.Newf(errf.RecordNotUpdate, "scheduled flow[%s] update next_trigger_at: `%s`->`%s` failed",
+			info.ID, info.Source, info.Target)
+	}
+
+	flowID, cmps, ops, err := e.createFlowOps(kt, flow)
+	if err != nil {
+		return "", err
+	}
+
+	triggeredAt := time.Now().Truncate(time.Second)
+	record.table.NextTriggerAt = info.Target
+	record.table.LastTriggeredAt = &triggeredAt
+	record.table.LastFlowID = converter.ValToPtr(flowID)
+	cmp, op, err := e.scheduledFlowPutOps(record)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := e.cli.Txn(kt.Ctx).If(append(cmps, cmp)...).Then(append(ops, op)...).Commit()
+	if err != nil {
+		logs.Errorf("trigger scheduled flow in etcd failed, err: %v, info: %+v, rid: %s", err, info, kt.Rid)
+		return "", err
+	}
+
+	// 定时任务流已经被其他节点触发或者被修改，本次不再触发
+	if !resp.Succeeded {
+		return "", errf.Newf(errf.RecordNotUpdate, "scheduled flow[%s] update next_trigger_at: `%s`->`%s` failed",
+			info.ID, info.Source, info.Target)
+	}
+
+	return flowID, nil
+}
@@ -0,0 +1,190 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	tableasync "hcm/pkg/dal/table/async"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+	"hcm/pkg/tools/times"
+
+	etcd3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcdMaxTxnOps etcd单个事务中允许的最大操作数量（etcd默认 --max-txn-ops 为128）
+const etcdMaxTxnOps = 100
+
+func (e *etcd) taskLogKey(id string) string {
+	return path.Join(e.prefix, "task_log", id)
+}
+
+func (e *etcd) taskLogKeyPrefix() string {
+	return path.Join(e.prefix, "task_log") + "/"
+}
+
+// BatchCreateTaskLog 批量创建任务执行日志，日志数量超过单个事务允许的最大操作数量时分批写入
+func (e *etcd) BatchCreateTaskLog(kt *kit.Kit, taskLogs []model.TaskLog) error {
+
+	if len(taskLogs) == 0 {
+		return errors.New("task logs is required")
+	}
+
+	ids, err := e.genIDs(kt, table.AsyncFlowTaskLogTable, len(taskLogs))
+	if err != nil {
+		return err
+	}
+
+	now := tabletypes.Time(times.ConvStdTimeFormat(times.ConvStdTimeNow()))
+	ops := make([]etcd3.Op, 0, len(taskLogs))
+	for idx, one := range taskLogs {
+		if err = one.CreateValidate(); err != nil {
+			return err
+		}
+
+		md := convTaskLogModelToTable(one)
+		md.ID = ids[idx]
+		if err = md.InsertValidate(); err != nil {
+			return err
+		}
+		md.CreatedAt = now
+		value, err := json.Marshal(md)
+		if err != nil {
+			return err
+		}
+
+		ops = append(ops, etcd3.OpPut(e.taskLogKey(md.ID), string(value)))
+	}
+
+	for _, batch := range slice.Split(ops, etcdMaxTxnOps) {
+		if _, err = e.cli.Txn(kt.Ctx).Then(batch...).Commit(); err != nil {
+			logs.Errorf("batch create task log in etcd failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListTaskLog 查询任务执行日志
+func (e *etcd) ListTaskLog(kt *kit.Kit, input *ListInput) ([]model.TaskLog, error) {
+
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	columnTypes := tableasync.AsyncFlowTaskLogColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	// count 请求在mysql实现中不返回详情，这里保持一致
+	if opt.Page.Count {
+		return make([]model.TaskLog, 0), nil
+	}
+
+	records, err := e.listAllTaskLog(kt)
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := filterAndPage(records, opt.Filter, opt.Page, columnTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	taskLogs := make([]model.TaskLog, 0, len(matched))
+	for _, one := range matched {
+		taskLogs = append(taskLogs, convTaskLogTableToModel(one))
+	}
+
+	return taskLogs, nil
+}
+
+func (e *etcd) listAllTaskLog(kt *kit.Kit) ([]tableasync.AsyncFlowTaskLogTable, error) {
+	resp, err := e.cli.Get(kt.Ctx, e.taskLogKeyPrefix(), etcd3.WithPrefix())
+	if err != nil {
+		logs.Errorf("list task log from etcd failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	records := make([]tableasync.AsyncFlowTaskLogTable, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		one := tableasync.AsyncFlowTaskLogTable{}
+		if err = json.Unmarshal(kv.Value, &one); err != nil {
+			return nil, fmt.Errorf("unmarshal task log %s failed, err: %v", kv.Key, err)
+		}
+		records = append(records, one)
+	}
+
+	return records, nil
+}
+
+// DeleteExpiredTaskLog 删除创建时间早于before的任务执行日志
+func (e *etcd) DeleteExpiredTaskLog(kt *kit.Kit, before time.Time, limit uint) (uint, error) {
+
+	if limit == 0 {
+		return 0, errors.New("limit is required")
+	}
+
+	records, err := e.listAllTaskLog(kt)
+	if err != nil {
+		return 0, err
+	}
+
+	ops := make([]etcd3.Op, 0)
+	for _, one := range records {
+		if uint(len(ops)) >= limit {
+			break
+		}
+
+		createdAt, err := parseTimeValue(string(one.CreatedAt))
+		if err != nil {
+			return 0, fmt.Errorf("parse task log %s created_at failed, err: %v", one.ID, err)
+		}
+
+		if createdAt.Before(before) {
+			ops = append(ops, etcd3.OpDelete(e.taskLogKey(one.ID)))
+		}
+	}
+
+	for _, batch := range slice.Split(ops, etcdMaxTxnOps) {
+		if _, err = e.cli.Txn(kt.Ctx).Then(batch...).Commit(); err != nil {
+			logs.Errorf("delete expired task log from etcd failed, err: %v, rid: %s", err, kt.Rid)
+			return 0, err
+		}
+	}
+
+	return uint(len(ops)), nil
+}
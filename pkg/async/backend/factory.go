@@ -25,6 +25,8 @@ import (
 
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/dal/dao"
+
+	etcd3 "go.etcd.io/etcd/client/v3"
 )
 
 // Factory 根据类型返回不同的backend接口的实现
@@ -36,7 +38,13 @@ func Factory(typ enumor.BackendType, client interface{}) (Backend, error) {
 			return nil, errors.New("client is not mysql dao set")
 		}
 		return NewMysql(cli), nil
+	case enumor.BackendEtcd:
+		cli, ok := client.(*etcd3.Client)
+		if !ok {
+			return nil, errors.New("client is not etcd client")
+		}
+		return NewEtcd(cli), nil
 	default:
-		return nil, fmt.Errorf("unsupported mysql type: %s", typ)
+		return nil, fmt.Errorf("unsupported backend type: %s", typ)
 	}
 }
@@ -21,7 +21,10 @@ package model
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	tableasync "hcm/pkg/dal/table/async"
 )
@@ -32,6 +35,19 @@ type Flow struct {
 	ShareData *tableasync.ShareData `json:"share_data"`
 	Memo      string                `json:"memo"`
 
+	// DeadlineAt 任务流截止时间，超过该时间仍未结束的任务流会被看门狗置为失败或取消，为空表示不限制
+	DeadlineAt string `json:"deadline_at"`
+	// SLAAt 任务流SLA时间，超过该时间仍未结束的任务流会触发告警，为空表示不告警
+	SLAAt string `json:"sla_at"`
+
+	// Priority 任务流优先级，数值越大越优先派发，默认为0
+	Priority enumor.FlowPriority `json:"priority"`
+	// FairnessKey 任务流公平键（如账号ID、业务ID），派发时不同公平键之间按照权重公平派发，并受公平键并发上限限制
+	FairnessKey string `json:"fairness_key"`
+
+	// CompensateOnFailure 任务流失败后是否对执行成功的任务按照依赖关系的逆序执行补偿动作
+	CompensateOnFailure bool `json:"compensate_on_failure"`
+
 	ID        string             `json:"id"`
 	State     enumor.FlowState   `json:"state"`
 	Reason    *tableasync.Reason `json:"reason"`
@@ -63,6 +79,26 @@ func (f Flow) CreateValidate() error {
 		return errors.New("reason can not set")
 	}
 
+	if err := f.Priority.Validate(); err != nil {
+		return err
+	}
+
+	if len(f.FairnessKey) > 64 {
+		return errors.New("fairness_key length should <= 64")
+	}
+
+	if len(f.DeadlineAt) != 0 {
+		if _, err := time.Parse(constant.TimeStdFormat, f.DeadlineAt); err != nil {
+			return fmt.Errorf("deadline_at is invalid, err: %v", err)
+		}
+	}
+
+	if len(f.SLAAt) != 0 {
+		if _, err := time.Parse(constant.TimeStdFormat, f.SLAAt); err != nil {
+			return fmt.Errorf("sla_at is invalid, err: %v", err)
+		}
+	}
+
 	if len(f.Creator) == 0 {
 		return errors.New("creator is required")
 	}
@@ -97,6 +133,26 @@ func (f Flow) UpdateValidate() error {
 		return errors.New("creator can not set")
 	}
 
+	if f.Priority != 0 {
+		return errors.New("priority can not set")
+	}
+
+	if len(f.FairnessKey) != 0 {
+		return errors.New("fairness_key can not set")
+	}
+
+	if f.CompensateOnFailure {
+		return errors.New("compensate_on_failure can not set")
+	}
+
+	if len(f.DeadlineAt) != 0 {
+		return errors.New("deadline_at can not set")
+	}
+
+	if len(f.SLAAt) != 0 {
+		return errors.New("sla_at can not set")
+	}
+
 	if len(f.Reviser) == 0 {
 		return errors.New("reviser is required")
 	}
@@ -0,0 +1,73 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	tableasync "hcm/pkg/dal/table/async"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ScheduledFlow 定时任务流，按照cron表达式定时基于任务流模版创建任务流
+type ScheduledFlow struct {
+	ID string `json:"id"`
+	// Name 定时任务流名称，全局唯一
+	Name string `json:"name"`
+	// FlowName 任务流模版名称
+	FlowName enumor.FlowName `json:"flow_name"`
+	// Spec cron表达式
+	Spec string `json:"spec"`
+	// Tasks 任务流模版中任务的请求参数
+	Tasks tableasync.ScheduledTasks `json:"tasks"`
+	Memo  string                    `json:"memo"`
+	State enumor.ScheduledFlowState `json:"state"`
+	// NextTriggerAt 下一次触发时间
+	NextTriggerAt string `json:"next_trigger_at"`
+	// LastTriggeredAt 最近一次触发时间，未触发过为空
+	LastTriggeredAt string `json:"last_triggered_at"`
+	// LastFlowID 最近一次触发创建的任务流ID
+	LastFlowID string `json:"last_flow_id"`
+	Creator    string `json:"creator"`
+	Reviser    string `json:"reviser"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// cronParser 标准5段式cron表达式解析器（分 时 日 月 周），同时支持 @daily、@every 1h 等描述符
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// NextTriggerTime 根据cron表达式计算 after 之后的下一次触发时间，时间精度为秒。
+func NextTriggerTime(spec string, after time.Time) (time.Time, error) {
+	schedule, err := cronParser.Parse(spec)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse cron spec: %s failed, err: %v", spec, err)
+	}
+
+	next := schedule.Next(after)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("cron spec: %s has no next trigger time after %s", spec, after)
+	}
+
+	return next.Truncate(time.Second), nil
+}
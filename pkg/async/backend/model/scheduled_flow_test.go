@@ -0,0 +1,56 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextTriggerTime(t *testing.T) {
+	base := time.Date(2024, 11, 1, 10, 3, 20, 500, time.Local)
+
+	cases := []struct {
+		spec   string
+		expect time.Time
+	}{
+		{spec: "*/5 * * * *", expect: time.Date(2024, 11, 1, 10, 5, 0, 0, time.Local)},
+		{spec: "0 2 * * *", expect: time.Date(2024, 11, 2, 2, 0, 0, 0, time.Local)},
+		{spec: "@hourly", expect: time.Date(2024, 11, 1, 11, 0, 0, 0, time.Local)},
+		{spec: "@every 1m", expect: time.Date(2024, 11, 1, 10, 4, 20, 0, time.Local)},
+	}
+
+	for _, c := range cases {
+		next, err := NextTriggerTime(c.spec, base)
+		if err != nil {
+			t.Errorf("spec: %s, unexpected err: %v", c.spec, err)
+			continue
+		}
+
+		if !next.Equal(c.expect) {
+			t.Errorf("spec: %s, expect next trigger time: %s, but got: %s", c.spec, c.expect, next)
+		}
+	}
+
+	// 不支持秒级表达式
+	if _, err := NextTriggerTime("0 */5 * * * *", base); err == nil {
+		t.Errorf("six fields spec should be invalid")
+	}
+}
@@ -21,6 +21,7 @@ package model
 
 import (
 	"errors"
+	"fmt"
 
 	"hcm/pkg/async/action"
 	"hcm/pkg/criteria/enumor"
@@ -35,16 +36,35 @@ type Task struct {
 	FlowName   enumor.FlowName    `json:"flow_name"`
 	ActionID   action.ActIDType   `json:"action_id"`
 	ActionName enumor.ActionName  `json:"action_name"`
+	Kind       enumor.TaskKind    `json:"kind"`
 	Params     types.JsonField    `json:"params"`
 	Retry      *tableasync.Retry  `json:"can_retry"`
 	DependOn   []action.ActIDType `json:"depend_on"`
-	State      enumor.TaskState   `json:"state"`
-	Reason     *tableasync.Reason `json:"reason"`
-	Result     types.JsonField    `json:"result"`
-	Creator    string             `json:"creator"`
-	Reviser    string             `json:"reviser"`
-	CreatedAt  string             `json:"created_at"`
-	UpdatedAt  string             `json:"updated_at"`
+	// Condition 任务执行条件，条件不成立时任务会被跳过
+	Condition *tableasync.Condition `json:"condition"`
+	// CompensateAction 补偿动作，任务流开启补偿模式且执行失败后，用于撤销该任务已经执行成功的操作
+	CompensateAction enumor.ActionName  `json:"compensate_action"`
+	State            enumor.TaskState   `json:"state"`
+	Reason           *tableasync.Reason `json:"reason"`
+	Result           types.JsonField    `json:"result"`
+	Creator          string             `json:"creator"`
+	Reviser          string             `json:"reviser"`
+	CreatedAt        string             `json:"created_at"`
+	UpdatedAt        string             `json:"updated_at"`
+}
+
+// GetKind 获取任务类型，未设置时默认为action类型
+func (t *Task) GetKind() enumor.TaskKind {
+	if len(t.Kind) == 0 {
+		return enumor.TaskKindAction
+	}
+
+	return t.Kind
+}
+
+// IsChildFlow 是否是子任务流类型的任务
+func (t *Task) IsChildFlow() bool {
+	return t.GetKind() == enumor.TaskKindChildFlow
 }
 
 // CreateValidate Task create validate.
@@ -69,6 +89,26 @@ func (t *Task) CreateValidate() error {
 		return errors.New("action_name is required")
 	}
 
+	if err := t.GetKind().Validate(); err != nil {
+		return err
+	}
+
+	if t.IsChildFlow() != (t.ActionName == enumor.ActionRunChildFlow) {
+		return fmt.Errorf("child_flow task action_name should be %s", enumor.ActionRunChildFlow)
+	}
+
+	if t.Condition != nil {
+		if err := t.Condition.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(t.CompensateAction) != 0 {
+		if err := action.ValidateCompensateAction(t.GetKind(), t.CompensateAction); err != nil {
+			return err
+		}
+	}
+
 	if t.Reason != nil {
 		return errors.New("reason can not set")
 	}
@@ -115,10 +155,22 @@ func (t *Task) UpdateValidate() error {
 		return errors.New("action_name can not set")
 	}
 
+	if len(t.Kind) != 0 {
+		return errors.New("kind can not set")
+	}
+
 	if len(t.Params) != 0 {
 		return errors.New("params can not set")
 	}
 
+	if t.Condition != nil {
+		return errors.New("condition can not set")
+	}
+
+	if len(t.CompensateAction) != 0 {
+		return errors.New("compensate_action can not set")
+	}
+
 	if len(t.Creator) != 0 {
 		return errors.New("creator can not set")
 	}
@@ -0,0 +1,94 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package model
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+// TaskLogMaxMessageLength 单条任务执行日志内容的最大长度，超出部分会被截断
+const TaskLogMaxMessageLength = 4096
+
+// TaskLog 任务执行日志，按照任务维度持久化，包含Action执行过程中输出的日志以及每次执行的结果
+type TaskLog struct {
+	ID     string `json:"id"`
+	FlowID string `json:"flow_id"`
+	TaskID string `json:"task_id"`
+	// Attempt 任务第几次执行，从1开始，重试和手动重试都会增加执行次数，0表示日志不属于任何一次执行（如条件不成立跳过执行）
+	Attempt uint `json:"attempt"`
+	// Kind 日志类型，log: Action输出的日志，attempt: 任务每次执行结束时记录的执行结果
+	Kind  enumor.TaskLogKind  `json:"kind"`
+	Level enumor.TaskLogLevel `json:"level"`
+	// Message 日志内容，执行结果类型的日志记录本次执行失败的原因
+	Message   string `json:"message"`
+	CreatedAt string `json:"created_at"`
+}
+
+// CreateValidate TaskLog.
+func (l TaskLog) CreateValidate() error {
+	if len(l.ID) != 0 {
+		return errors.New("id can not set")
+	}
+
+	if len(l.FlowID) == 0 {
+		return errors.New("flow_id is required")
+	}
+
+	if len(l.TaskID) == 0 {
+		return errors.New("task_id is required")
+	}
+
+	if err := l.Kind.Validate(); err != nil {
+		return err
+	}
+
+	if err := l.Level.Validate(); err != nil {
+		return err
+	}
+
+	if len(l.Message) > TaskLogMaxMessageLength {
+		return errors.New("message length should <= 4096")
+	}
+
+	if len(l.CreatedAt) != 0 {
+		return errors.New("created_at can not set")
+	}
+
+	return nil
+}
+
+// TruncateTaskLogMessage 截断超过最大长度的日志内容，保证截断后仍是合法的utf8字符串
+func TruncateTaskLogMessage(msg string) string {
+	if len(msg) <= TaskLogMaxMessageLength {
+		return msg
+	}
+
+	const suffix = "...(truncated)"
+	cut := TaskLogMaxMessageLength - len(suffix)
+	// 避免截断多字节字符
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+
+	return msg[:cut] + suffix
+}
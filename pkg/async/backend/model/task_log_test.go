@@ -0,0 +1,78 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package model
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"hcm/pkg/criteria/enumor"
+)
+
+func TestTruncateTaskLogMessage(t *testing.T) {
+	short := "create cvm failed"
+	if got := TruncateTaskLogMessage(short); got != short {
+		t.Errorf("short message should not be truncated, got: %s", got)
+	}
+
+	cases := []string{
+		strings.Repeat("a", TaskLogMaxMessageLength+1),
+		// 多字节字符跨越截断位置
+		"a" + strings.Repeat("主机", TaskLogMaxMessageLength),
+	}
+	for _, msg := range cases {
+		got := TruncateTaskLogMessage(msg)
+		if len(got) > TaskLogMaxMessageLength {
+			t.Errorf("truncated message length %d exceed %d", len(got), TaskLogMaxMessageLength)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("truncated message should be valid utf8")
+		}
+		if !strings.HasSuffix(got, "...(truncated)") {
+			t.Errorf("truncated message should end with truncated suffix")
+		}
+	}
+}
+
+func TestTaskLogCreateValidate(t *testing.T) {
+	valid := TaskLog{FlowID: "flow1", TaskID: "task1", Attempt: 1, Kind: enumor.TaskLogKindLog,
+		Level: enumor.TaskLogInfo, Message: "ok"}
+	if err := valid.CreateValidate(); err != nil {
+		t.Fatalf("valid task log validate failed, err: %v", err)
+	}
+
+	invalids := map[string]func(l *TaskLog){
+		"id set":           func(l *TaskLog) { l.ID = "1" },
+		"flow id missing":  func(l *TaskLog) { l.FlowID = "" },
+		"task id missing":  func(l *TaskLog) { l.TaskID = "" },
+		"invalid kind":     func(l *TaskLog) { l.Kind = "unknown" },
+		"invalid level":    func(l *TaskLog) { l.Level = "unknown" },
+		"message too long": func(l *TaskLog) { l.Message = strings.Repeat("a", TaskLogMaxMessageLength+1) },
+		"created at set":   func(l *TaskLog) { l.CreatedAt = "2024-11-12 10:00:00" },
+	}
+	for name, modify := range invalids {
+		one := valid
+		modify(&one)
+		if err := one.CreateValidate(); err == nil {
+			t.Errorf("case %s: validate should failed", name)
+		}
+	}
+}
@@ -23,10 +23,12 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"hcm/pkg/api/core"
 	"hcm/pkg/async/action"
 	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/dal/dao"
 	"hcm/pkg/dal/dao/orm"
@@ -37,7 +39,9 @@ import (
 	tabletypes "hcm/pkg/dal/table/types"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
 	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -190,64 +194,83 @@ var _ Backend = new(mysql)
 // CreateFlow 创建任务流
 func (db *mysql) CreateFlow(kt *kit.Kit, flow *model.Flow) (string, error) {
 
+	result, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return db.createFlowWithTx(kt, txn, flow)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	flowID, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("return result not string type, type: %s", reflect.TypeOf(result).String())
+	}
+
+	return flowID, nil
+}
+
+// createFlowWithTx 在事务中创建任务流及其任务
+func (db *mysql) createFlowWithTx(kt *kit.Kit, txn *sqlx.Tx, flow *model.Flow) (string, error) {
+
 	flowState := enumor.FlowPending
 	if flow.State == enumor.FlowInit {
 		flowState = flow.State
 	}
 
-	result, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
-		// 创建任务流
-		md := &tableasync.AsyncFlowTable{
-			Name:      flow.Name,
-			State:     flowState,
-			Reason:    new(tableasync.Reason),
-			ShareData: flow.ShareData,
-			Memo:      flow.Memo,
-			Worker:    converter.ValToPtr(""),
-			Creator:   kt.User,
-			Reviser:   kt.User,
-		}
-		flowID, err := db.dao.AsyncFlow().Create(kt, txn, md)
-		if err != nil {
-			return nil, err
-		}
-
-		// 创建任务
-		tasks := flow.Tasks
-		mds := make([]tableasync.AsyncFlowTaskTable, 0, len(tasks))
-		for _, one := range tasks {
-			taskState := enumor.TaskPending
-			if one.State == enumor.TaskInit {
-				taskState = one.State
-			}
-
-			mds = append(mds, tableasync.AsyncFlowTaskTable{
-				FlowID:     flowID,
-				FlowName:   one.FlowName,
-				ActionID:   string(one.ActionID),
-				ActionName: one.ActionName,
-				Params:     one.Params,
-				Retry:      one.Retry,
-				DependOn:   dependOnToStringArray(one.DependOn),
-				State:      taskState,
-				Reason:     new(tableasync.Reason),
-				Creator:    kt.User,
-				Reviser:    kt.User,
-			})
-		}
-		if _, err = db.dao.AsyncFlowTask().BatchCreateWithTx(kt, txn, mds); err != nil {
-			return nil, err
-		}
+	deadlineAt, slaAt, err := parseFlowDeadline(flow)
+	if err != nil {
+		return "", err
+	}
 
-		return flowID, nil
-	})
+	// 创建任务流
+	md := &tableasync.AsyncFlowTable{
+		Name:                flow.Name,
+		State:               flowState,
+		Reason:              new(tableasync.Reason),
+		ShareData:           flow.ShareData,
+		Memo:                flow.Memo,
+		Worker:              converter.ValToPtr(""),
+		Priority:            flow.Priority,
+		FairnessKey:         flow.FairnessKey,
+		CompensateOnFailure: flow.CompensateOnFailure,
+		DeadlineAt:          deadlineAt,
+		SLAAt:               slaAt,
+		Creator:             kt.User,
+		Reviser:             kt.User,
+	}
+	flowID, err := db.dao.AsyncFlow().Create(kt, txn, md)
 	if err != nil {
 		return "", err
 	}
 
-	flowID, ok := result.(string)
-	if !ok {
-		return "", fmt.Errorf("return result not string type, type: %s", reflect.TypeOf(result).String())
+	// 创建任务
+	tasks := flow.Tasks
+	mds := make([]tableasync.AsyncFlowTaskTable, 0, len(tasks))
+	for _, one := range tasks {
+		taskState := enumor.TaskPending
+		if one.State == enumor.TaskInit {
+			taskState = one.State
+		}
+
+		mds = append(mds, tableasync.AsyncFlowTaskTable{
+			FlowID:           flowID,
+			FlowName:         one.FlowName,
+			ActionID:         string(one.ActionID),
+			ActionName:       one.ActionName,
+			Kind:             one.GetKind(),
+			Params:           one.Params,
+			Retry:            one.Retry,
+			DependOn:         dependOnToStringArray(one.DependOn),
+			ExecCondition:    one.Condition,
+			CompensateAction: one.CompensateAction,
+			State:            taskState,
+			Reason:           new(tableasync.Reason),
+			Creator:          kt.User,
+			Reviser:          kt.User,
+		})
+	}
+	if _, err = db.dao.AsyncFlowTask().BatchCreateWithTx(kt, txn, mds); err != nil {
+		return "", err
 	}
 
 	return flowID, nil
@@ -288,6 +311,7 @@ func (db *mysql) ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error) {
 		Fields: input.Fields,
 		Filter: input.Filter,
 		Page:   input.Page,
+		ThenBy: input.ThenBy,
 	}
 	list, err := db.dao.AsyncFlow().List(kt, opt)
 	if err != nil {
@@ -296,41 +320,80 @@ func (db *mysql) ListFlow(kt *kit.Kit, input *ListInput) ([]model.Flow, error) {
 
 	flows := make([]model.Flow, 0, len(list.Details))
 	for _, one := range list.Details {
-		flows = append(flows, model.Flow{
-			ID:        one.ID,
-			Name:      one.Name,
-			State:     one.State,
-			Reason:    one.Reason,
-			ShareData: one.ShareData,
-			Memo:      one.Memo,
-			Worker:    one.Worker,
-			Creator:   one.Creator,
-			Reviser:   one.Reviser,
-			CreatedAt: one.CreatedAt.String(),
-			UpdatedAt: one.UpdatedAt.String(),
-		})
+		flows = append(flows, convFlowTableToModel(one))
 	}
 
 	return flows, nil
 }
 
+// DeleteExpiredFlow 删除更新时间早于before的已结束任务流及其任务
+func (db *mysql) DeleteExpiredFlow(kt *kit.Kit, before time.Time, limit uint) (uint, error) {
+
+	if limit == 0 {
+		return 0, errors.New("limit is required")
+	}
+	if limit > core.DefaultMaxPageLimit {
+		limit = core.DefaultMaxPageLimit
+	}
+
+	opt := &types.ListOption{
+		Fields: []string{"id"},
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("state", []enumor.FlowState{enumor.FlowSuccess, enumor.FlowFailed, enumor.FlowCancel}),
+			&filter.AtomRule{Field: "updated_at", Op: filter.LessThan.Factory(),
+				Value: times.ConvStdTimeFormat(before)},
+		),
+		Page: &core.BasePage{Limit: limit},
+	}
+	list, err := db.dao.AsyncFlow().List(kt, opt)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(list.Details) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]string, 0, len(list.Details))
+	for _, one := range list.Details {
+		ids = append(ids, one.ID)
+	}
+
+	_, err = db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		if err := db.dao.AsyncFlowTask().DeleteWithTx(kt, txn, tools.ContainersExpression("flow_id", ids)); err != nil {
+			return nil, err
+		}
+
+		return nil, db.dao.AsyncFlow().DeleteWithTx(kt, txn, tools.ContainersExpression("id", ids))
+	})
+	if err != nil {
+		logs.Errorf("delete expired flow failed, err: %v, ids: %v, rid: %s", err, ids, kt.Rid)
+		return 0, err
+	}
+
+	return uint(len(ids)), nil
+}
+
 // BatchCreateTask 批量创建任务
 func (db *mysql) BatchCreateTask(kt *kit.Kit, tasks []model.Task) ([]string, error) {
 
 	mds := make([]tableasync.AsyncFlowTaskTable, 0, len(tasks))
 	for _, one := range tasks {
 		mds = append(mds, tableasync.AsyncFlowTaskTable{
-			FlowID:     one.FlowID,
-			FlowName:   one.FlowName,
-			ActionID:   string(one.ActionID),
-			ActionName: one.ActionName,
-			Params:     one.Params,
-			Retry:      one.Retry,
-			DependOn:   dependOnToStringArray(one.DependOn),
-			State:      enumor.TaskPending,
-			Reason:     one.Reason,
-			Creator:    one.Creator,
-			Reviser:    one.Reviser,
+			FlowID:           one.FlowID,
+			FlowName:         one.FlowName,
+			ActionID:         string(one.ActionID),
+			ActionName:       one.ActionName,
+			Kind:             one.GetKind(),
+			Params:           one.Params,
+			Retry:            one.Retry,
+			DependOn:         dependOnToStringArray(one.DependOn),
+			ExecCondition:    one.Condition,
+			CompensateAction: one.CompensateAction,
+			State:            enumor.TaskPending,
+			Reason:           one.Reason,
+			Creator:          one.Creator,
+			Reviser:          one.Reviser,
 		})
 	}
 
@@ -366,28 +429,85 @@ func (db *mysql) ListTask(kt *kit.Kit, input *ListInput) ([]model.Task, error) {
 
 	tasks := make([]model.Task, 0, len(list.Details))
 	for _, one := range list.Details {
-		tasks = append(tasks, model.Task{
-			ID:         one.ID,
-			FlowID:     one.FlowID,
-			FlowName:   one.FlowName,
-			ActionID:   action.ActIDType(one.ActionID),
-			ActionName: one.ActionName,
-			Params:     one.Params,
-			Retry:      one.Retry,
-			DependOn:   dependOnToActIDArray(one.DependOn),
-			State:      one.State,
-			Reason:     one.Reason,
-			Result:     one.Result,
-			Creator:    one.Creator,
-			Reviser:    one.Reviser,
-			CreatedAt:  one.CreatedAt.String(),
-			UpdatedAt:  one.UpdatedAt.String(),
-		})
+		tasks = append(tasks, convTaskTableToModel(one))
 	}
 
 	return tasks, nil
 }
 
+func convFlowTableToModel(one tableasync.AsyncFlowTable) model.Flow {
+	flow := model.Flow{
+		ID:                  one.ID,
+		Name:                one.Name,
+		State:               one.State,
+		Reason:              one.Reason,
+		ShareData:           one.ShareData,
+		Memo:                one.Memo,
+		Worker:              one.Worker,
+		Priority:            one.Priority,
+		FairnessKey:         one.FairnessKey,
+		CompensateOnFailure: one.CompensateOnFailure,
+		Creator:             one.Creator,
+		Reviser:             one.Reviser,
+		CreatedAt:           one.CreatedAt.String(),
+		UpdatedAt:           one.UpdatedAt.String(),
+	}
+
+	if one.DeadlineAt != nil && !one.DeadlineAt.IsZero() {
+		flow.DeadlineAt = times.ConvStdTimeFormat(*one.DeadlineAt)
+	}
+
+	if one.SLAAt != nil && !one.SLAAt.IsZero() {
+		flow.SLAAt = times.ConvStdTimeFormat(*one.SLAAt)
+	}
+
+	return flow
+}
+
+// parseFlowDeadline 解析任务流的截止时间和SLA时间，未设置时返回nil
+func parseFlowDeadline(flow *model.Flow) (deadlineAt *time.Time, slaAt *time.Time, err error) {
+	if len(flow.DeadlineAt) != 0 {
+		deadline, err := time.Parse(constant.TimeStdFormat, flow.DeadlineAt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse deadline_at failed, err: %v", err)
+		}
+		deadlineAt = &deadline
+	}
+
+	if len(flow.SLAAt) != 0 {
+		sla, err := time.Parse(constant.TimeStdFormat, flow.SLAAt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse sla_at failed, err: %v", err)
+		}
+		slaAt = &sla
+	}
+
+	return deadlineAt, slaAt, nil
+}
+
+func convTaskTableToModel(one tableasync.AsyncFlowTaskTable) model.Task {
+	return model.Task{
+		ID:               one.ID,
+		FlowID:           one.FlowID,
+		FlowName:         one.FlowName,
+		ActionID:         action.ActIDType(one.ActionID),
+		ActionName:       one.ActionName,
+		Kind:             one.Kind,
+		Params:           one.Params,
+		Retry:            one.Retry,
+		DependOn:         dependOnToActIDArray(one.DependOn),
+		Condition:        one.ExecCondition,
+		CompensateAction: one.CompensateAction,
+		State:            one.State,
+		Reason:           one.Reason,
+		Result:           one.Result,
+		Creator:          one.Creator,
+		Reviser:          one.Reviser,
+		CreatedAt:        one.CreatedAt.String(),
+		UpdatedAt:        one.UpdatedAt.String(),
+	}
+}
+
 func dependOnToStringArray(d []action.ActIDType) tabletypes.StringArray {
 	result := make(tabletypes.StringArray, 0, len(d))
 	for _, one := range d {
@@ -0,0 +1,213 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typesasync "hcm/pkg/dal/dao/types/async"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateScheduledFlow 创建定时任务流
+func (db *mysql) CreateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) (string, error) {
+
+	md, err := convScheduledFlowModelToTable(flow)
+	if err != nil {
+		return "", err
+	}
+	md.Creator = kt.User
+	md.Reviser = kt.User
+	if md.LastFlowID == nil {
+		md.LastFlowID = converter.ValToPtr("")
+	}
+
+	result, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return db.dao.AsyncScheduledFlow().CreateWithTx(kt, txn, md)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	id, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("return result not string type, type: %s", reflect.TypeOf(result).String())
+	}
+
+	return id, nil
+}
+
+// UpdateScheduledFlow 更新定时任务流
+func (db *mysql) UpdateScheduledFlow(kt *kit.Kit, flow *model.ScheduledFlow) error {
+
+	if len(flow.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	md, err := convScheduledFlowModelToTable(flow)
+	if err != nil {
+		return err
+	}
+	md.Name = ""
+	md.Reviser = kt.User
+
+	_, err = db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, db.dao.AsyncScheduledFlow().UpdateByIDWithTx(kt, txn, flow.ID, md)
+	})
+	return err
+}
+
+// ListScheduledFlow 查询定时任务流
+func (db *mysql) ListScheduledFlow(kt *kit.Kit, input *ListInput) ([]model.ScheduledFlow, error) {
+
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	list, err := db.dao.AsyncScheduledFlow().List(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	flows := make([]model.ScheduledFlow, 0, len(list.Details))
+	for _, one := range list.Details {
+		flows = append(flows, convScheduledFlowTableToModel(one))
+	}
+
+	return flows, nil
+}
+
+// DeleteScheduledFlow 删除定时任务流
+func (db *mysql) DeleteScheduledFlow(kt *kit.Kit, id string) error {
+
+	if len(id) == 0 {
+		return errors.New("id is required")
+	}
+
+	_, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		return nil, db.dao.AsyncScheduledFlow().DeleteWithTx(kt, txn, tools.EqualExpression("id", id))
+	})
+	return err
+}
+
+// TriggerScheduledFlow 触发定时任务流，CAS更新下一次触发时间和创建任务流在同一个事务中完成
+func (db *mysql) TriggerScheduledFlow(kt *kit.Kit, info *TriggerScheduledFlowInfo, flow *model.Flow) (
+	string, error) {
+
+	if err := info.Validate(); err != nil {
+		return "", err
+	}
+
+	result, err := db.dao.Txn().AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		flowID, err := db.createFlowWithTx(kt, txn, flow)
+		if err != nil {
+			return nil, err
+		}
+
+		trigger := &typesasync.TriggerScheduledFlowInfo{
+			ID:          info.ID,
+			Source:      info.Source,
+			Target:      info.Target,
+			TriggeredAt: time.Now().Truncate(time.Second),
+			FlowID:      flowID,
+		}
+		if err = db.dao.AsyncScheduledFlow().UpdateTriggerByCAS(kt, txn, trigger); err != nil {
+			logs.Errorf("update scheduled flow trigger by cas failed, err: %v, info: %+v, rid: %s", err, info,
+				kt.Rid)
+			return nil, err
+		}
+
+		return flowID, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	flowID, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("return result not string type, type: %s", reflect.TypeOf(result).String())
+	}
+
+	return flowID, nil
+}
+
+// convScheduledFlowModelToTable 转换定时任务流，未设置值的字段不进行转换
+func convScheduledFlowModelToTable(flow *model.ScheduledFlow) (*tableasync.AsyncScheduledFlowTable, error) {
+	md := &tableasync.AsyncScheduledFlowTable{
+		Name:     flow.Name,
+		FlowName: flow.FlowName,
+		Spec:     flow.Spec,
+		Tasks:    flow.Tasks,
+		State:    flow.State,
+	}
+
+	if len(flow.Memo) != 0 {
+		md.Memo = converter.ValToPtr(flow.Memo)
+	}
+
+	if len(flow.NextTriggerAt) != 0 {
+		next, err := time.Parse(constant.TimeStdFormat, flow.NextTriggerAt)
+		if err != nil {
+			return nil, fmt.Errorf("parse next_trigger_at failed, err: %v", err)
+		}
+		md.NextTriggerAt = next
+	}
+
+	return md, nil
+}
+
+func convScheduledFlowTableToModel(one tableasync.AsyncScheduledFlowTable) model.ScheduledFlow {
+	flow := model.ScheduledFlow{
+		ID:            one.ID,
+		Name:          one.Name,
+		FlowName:      one.FlowName,
+		Spec:          one.Spec,
+		Tasks:         one.Tasks,
+		Memo:          converter.PtrToVal(one.Memo),
+		State:         one.State,
+		NextTriggerAt: times.ConvStdTimeFormat(one.NextTriggerAt),
+		LastFlowID:    converter.PtrToVal(one.LastFlowID),
+		Creator:       one.Creator,
+		Reviser:       one.Reviser,
+		CreatedAt:     one.CreatedAt.String(),
+		UpdatedAt:     one.UpdatedAt.String(),
+	}
+
+	if one.LastTriggeredAt != nil && !one.LastTriggeredAt.IsZero() {
+		flow.LastTriggeredAt = times.ConvStdTimeFormat(*one.LastTriggeredAt)
+	}
+
+	return flow
+}
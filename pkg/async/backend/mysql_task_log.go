@@ -0,0 +1,102 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package backend
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/dal/dao/types"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+)
+
+// BatchCreateTaskLog 批量创建任务执行日志
+func (db *mysql) BatchCreateTaskLog(kt *kit.Kit, taskLogs []model.TaskLog) error {
+
+	if len(taskLogs) == 0 {
+		return errors.New("task logs is required")
+	}
+
+	mds := make([]tableasync.AsyncFlowTaskLogTable, 0, len(taskLogs))
+	for _, one := range taskLogs {
+		if err := one.CreateValidate(); err != nil {
+			return err
+		}
+		mds = append(mds, convTaskLogModelToTable(one))
+	}
+
+	if _, err := db.dao.AsyncFlowTaskLog().BatchCreate(kt, mds); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListTaskLog 查询任务执行日志
+func (db *mysql) ListTaskLog(kt *kit.Kit, input *ListInput) ([]model.TaskLog, error) {
+
+	opt := &types.ListOption{
+		Fields: input.Fields,
+		Filter: input.Filter,
+		Page:   input.Page,
+	}
+	list, err := db.dao.AsyncFlowTaskLog().List(kt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	taskLogs := make([]model.TaskLog, 0, len(list.Details))
+	for _, one := range list.Details {
+		taskLogs = append(taskLogs, convTaskLogTableToModel(one))
+	}
+
+	return taskLogs, nil
+}
+
+// DeleteExpiredTaskLog 删除创建时间早于before的任务执行日志
+func (db *mysql) DeleteExpiredTaskLog(kt *kit.Kit, before time.Time, limit uint) (uint, error) {
+	return db.dao.AsyncFlowTaskLog().DeleteBefore(kt, before, limit)
+}
+
+func convTaskLogModelToTable(one model.TaskLog) tableasync.AsyncFlowTaskLogTable {
+	return tableasync.AsyncFlowTaskLogTable{
+		FlowID:  one.FlowID,
+		TaskID:  one.TaskID,
+		Attempt: one.Attempt,
+		Kind:    one.Kind,
+		Level:   one.Level,
+		Message: one.Message,
+	}
+}
+
+func convTaskLogTableToModel(one tableasync.AsyncFlowTaskLogTable) model.TaskLog {
+	return model.TaskLog{
+		ID:        one.ID,
+		FlowID:    one.FlowID,
+		TaskID:    one.TaskID,
+		Attempt:   one.Attempt,
+		Kind:      one.Kind,
+		Level:     one.Level,
+		Message:   one.Message,
+		CreatedAt: one.CreatedAt.String(),
+	}
+}
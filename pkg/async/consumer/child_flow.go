@@ -0,0 +1,231 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/retry"
+	"hcm/pkg/tools/slice"
+)
+
+// childFlowWatcher 定期检查当前节点上等待子任务流执行结束的任务，子任务流结束后更新任务状态并继续调度
+func (sch *scheduler) childFlowWatcher() {
+	defer sch.workerWg.Done()
+
+	for {
+		select {
+		case <-sch.closeCh:
+			return
+		default:
+		}
+		// Kit: Kit initiate, 每次执行创建新kit
+		kt := NewKit()
+		if err := sch.handleAwaitingChildFlowTasks(kt); err != nil {
+			logs.Errorf("%s: scheduler watch child flow failed, err: %v, rid: %s",
+				constant.AsyncTaskWarnSign, err, kt.Rid)
+		}
+
+		time.Sleep(sch.watchIntervalSec)
+	}
+}
+
+// handleAwaitingChildFlowTasks 处理等待子任务流执行结束的任务
+// 1. 子任务流执行成功，任务状态置为成功
+// 2. 子任务流执行失败或被取消，任务状态置为失败
+// 3. 子任务流未结束，继续等待
+func (sch *scheduler) handleAwaitingChildFlowTasks(kt *kit.Kit) error {
+	flowMap := make(map[string]*Flow)
+	taskIDs := make([]string, 0)
+	sch.taskTrees.Range(func(key, value any) bool {
+		tree := value.(*TaskTree)
+		ids := tree.Root.GetAwaitingChildFlowTasks()
+		if len(ids) != 0 {
+			flowMap[tree.Flow.ID] = tree.Flow
+			taskIDs = append(taskIDs, ids...)
+		}
+		return true
+	})
+
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	tasks, err := listTaskByIDs(kt, sch.backend, taskIDs)
+	if err != nil {
+		return err
+	}
+
+	childFlowIDs := make([]string, 0, len(tasks))
+	childFlowIDMap := make(map[string]string, len(tasks))
+	for _, task := range tasks {
+		childFlowID, err := getChildFlowID(task.Task)
+		if err != nil {
+			logs.Errorf("get child flow id failed, err: %v, task id: %s, rid: %s", err, task.ID, kt.Rid)
+			continue
+		}
+		childFlowIDs = append(childFlowIDs, childFlowID)
+		childFlowIDMap[task.ID] = childFlowID
+	}
+
+	childFlows, err := listFlowByIDs(kt, sch.backend, childFlowIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		flow, exist := flowMap[task.FlowID]
+		if !exist || task.State != enumor.TaskRunning {
+			continue
+		}
+
+		target, reason := enumor.TaskFailed, ""
+		childFlowID, exist := childFlowIDMap[task.ID]
+		if !exist {
+			reason = "child flow id not found in task result"
+		} else if childFlow, ok := childFlows[childFlowID]; !ok {
+			reason = fmt.Sprintf("child flow: %s not found", childFlowID)
+		} else {
+			switch childFlow.State {
+			case enumor.FlowSuccess:
+				target = enumor.TaskSuccess
+			case enumor.FlowFailed, enumor.FlowCancel:
+				reason = fmt.Sprintf("child flow: %s %s", childFlowID, childFlow.State)
+				if childFlow.Reason != nil && len(childFlow.Reason.Message) != 0 {
+					reason += ", reason: " + childFlow.Reason.Message
+				}
+			default:
+				// 子任务流未结束，继续等待
+				continue
+			}
+		}
+
+		if err = updateChildFlowTaskState(kt, sch.backend, task, target, reason); err != nil {
+			logs.Errorf("update child flow task state to %s failed, err: %v, task id: %s, rid: %s", target, err,
+				task.ID, kt.Rid)
+			continue
+		}
+
+		task.State = target
+		task.Flow = flow
+		sch.EntryTask(task)
+	}
+
+	return nil
+}
+
+// updateChildFlowTaskState CAS更新子任务流任务的状态，running -> success/failed
+func updateChildFlowTaskState(kt *kit.Kit, bd backend.Backend, task *Task, target enumor.TaskState,
+	reason string) error {
+
+	info := &backend.UpdateTaskInfo{
+		ID:     task.ID,
+		Source: enumor.TaskRunning,
+		Target: target,
+	}
+	if len(reason) != 0 {
+		info.Reason = &tableasync.Reason{
+			Message:  reason,
+			PreState: string(enumor.TaskRunning),
+		}
+	}
+
+	rty := retry.NewRetryPolicy(DefRetryCount, DefRetryRangeMS)
+	return rty.BaseExec(kt, func() error {
+		return bd.UpdateTaskStateByCAS(kt, info)
+	})
+}
+
+// cancelChildFlow 取消子任务流任务创建的子任务流，后续步骤由子任务流所在节点的`canceledFlowWatcher`继续执行
+func cancelChildFlow(kt *kit.Kit, bd backend.Backend, task model.Task) error {
+	childFlowID, err := getChildFlowID(task)
+	if err != nil {
+		return err
+	}
+
+	flows, err := listFlowByIDs(kt, bd, []string{childFlowID})
+	if err != nil {
+		return err
+	}
+
+	flow, exist := flows[childFlowID]
+	if !exist {
+		return nil
+	}
+
+	switch flow.State {
+	case enumor.FlowSuccess, enumor.FlowFailed, enumor.FlowCancel:
+		return nil
+	}
+
+	return updateFlowStateAndReason(kt, bd, flow.ID, flow.State, enumor.FlowCancel,
+		fmt.Sprintf("parent flow: %s canceled", task.FlowID))
+}
+
+// getChildFlowID 从子任务流任务的执行结果中获取子任务流ID
+func getChildFlowID(task model.Task) (string, error) {
+	if task.Result.IsEmpty() {
+		return "", fmt.Errorf("task: %s result is empty", task.ID)
+	}
+
+	result := new(action.ChildFlowResult)
+	if err := json.UnmarshalFromString(string(task.Result), result); err != nil {
+		return "", fmt.Errorf("unmarshal child flow task result failed, err: %v", err)
+	}
+
+	if len(result.ChildFlowID) == 0 {
+		return "", fmt.Errorf("task: %s child_flow_id is empty", task.ID)
+	}
+
+	return result.ChildFlowID, nil
+}
+
+// listFlowByIDs 根据ID查询任务流
+func listFlowByIDs(kt *kit.Kit, bd backend.Backend, ids []string) (map[string]model.Flow, error) {
+	flowMap := make(map[string]model.Flow, len(ids))
+	for _, partIDs := range slice.Split(ids, int(core.DefaultMaxPageLimit)) {
+		flows, err := bd.ListFlow(kt, &backend.ListInput{
+			Filter: tools.ContainersExpression("id", partIDs),
+			Page:   core.NewDefaultBasePage(),
+		})
+		if err != nil {
+			logs.Errorf("list flow failed, err: %v, ids: %v, rid: %s", err, partIDs, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range flows {
+			flowMap[one.ID] = one
+		}
+	}
+
+	return flowMap, nil
+}
@@ -0,0 +1,158 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+/*
+任务流补偿（Saga）流程：
+ 1. 开启补偿模式的任务流执行失败且没有可以继续执行的任务时，任务流状态由 running 置为 compensating。
+ 2. 按照依赖关系的逆序，对执行成功且设置了补偿动作的任务执行补偿动作，任务状态 success -> compensating ->
+    compensated/compensate_failed，后继任务都补偿完成后才补偿当前任务，后继任务补偿失败时不再补偿当前任务。
+ 3. 没有可以补偿的任务后，任务流状态由 compensating 置为 failed，失败原因中记录补偿结果。
+ 4. 执行节点下线后，compensating 状态的任务流会被重新调度，补偿中的任务会重新执行补偿动作。
+*/
+
+// compensateFlow 开始或继续任务流补偿，resume 为 true 时重新执行处于补偿中的任务（任务流被重新调度）
+func (sch *scheduler) compensateFlow(kt *kit.Kit, tree *TaskTree, resume bool) error {
+	flow := tree.Flow
+	if flow.State != enumor.FlowCompensating {
+		if err := updateFlowStateAndReason(kt, sch.backend, flow.ID, enumor.FlowRunning, enumor.FlowCompensating,
+			ErrSomeTaskExecFailed); err != nil {
+
+			logs.Errorf("update flow state to %s failed, err: %v, rid: %s", enumor.FlowCompensating, err, kt.Rid)
+			return err
+		}
+		flow.State = enumor.FlowCompensating
+		logs.Infof("flow %s start to compensate, rid: %s", flow.ID, kt.Rid)
+	}
+
+	ids := tree.Root.GetCompensableTasks()
+	if resume {
+		ids = append(ids, tree.Root.GetCompensatingTasks()...)
+	}
+
+	if len(ids) == 0 {
+		// 存在补偿中的任务，等待其补偿结束
+		if len(tree.Root.GetCompensatingTasks()) != 0 {
+			return nil
+		}
+
+		return sch.finishCompensation(kt, tree)
+	}
+
+	// 推送前先标记为补偿中，避免其他任务补偿结束时重复推送
+	tree.Root.SetTaskState(ids, enumor.TaskCompensating)
+	sch.taskTrees.Store(flow.ID, tree)
+
+	return sch.pushTasks(kt, flow, ids)
+}
+
+// finishCompensation 补偿结束，任务流置为失败状态，并记录补偿结果
+func (sch *scheduler) finishCompensation(kt *kit.Kit, tree *TaskTree) error {
+	reason := ErrSomeTaskCompensated
+	if tree.Root.HasCompensateFailed() {
+		reason = ErrSomeTaskCompensateFailed
+		logs.Errorf("%s: flow %s compensate failed, rid: %s", constant.AsyncTaskWarnSign, tree.Flow.ID, kt.Rid)
+	}
+
+	if err := updateFlowStateAndReason(kt, sch.backend, tree.Flow.ID, enumor.FlowCompensating, enumor.FlowFailed,
+		reason); err != nil {
+
+		logs.Errorf("update flow state to %s failed, err: %v, rid: %s", enumor.FlowFailed, err, kt.Rid)
+		return err
+	}
+
+	sch.DeleteFlowTaskTree(tree.Flow.ID)
+	return nil
+}
+
+// executeNextCompensation 任务补偿结束后更新任务树，并继续补偿其他任务
+func (sch *scheduler) executeNextCompensation(kt *kit.Kit, tree *TaskTree, task *Task) error {
+	switch task.State {
+	case enumor.TaskCompensated, enumor.TaskCompensateFailed:
+		tree.Root.SetTaskState([]string{task.ID}, task.State)
+	default:
+		// 开始补偿时仍在执行中的任务，执行结束后更新状态，执行成功的任务也需要补偿
+		node := tree.Root.FindNode(task.ID)
+		if node != nil && (node.State == enumor.TaskRunning || node.State == enumor.TaskRollback) {
+			node.State = task.State
+		}
+	}
+
+	return sch.compensateFlow(kt, tree, false)
+}
+
+// runCompensate 执行任务的补偿动作，执行成功置为compensated状态，执行失败置为compensate_failed状态
+func (exec *executor) runCompensate(task *Task) error {
+	if task.State == enumor.TaskSuccess {
+		info := &backend.UpdateTaskInfo{
+			ID:     task.ID,
+			Source: enumor.TaskSuccess,
+			Target: enumor.TaskCompensating,
+		}
+		if err := exec.backend.UpdateTaskStateByCAS(exec.kt, info); err != nil {
+			return fmt.Errorf("update task state to compensating failed, err: %v", err)
+		}
+		task.State = enumor.TaskCompensating
+	}
+
+	task.logger.Infof("start to compensate by action: %s", task.CompensateAction)
+	result, err := exec.compensate(task)
+	if err != nil {
+		task.logger.Errorf("compensate by action: %s failed, err: %v", task.CompensateAction, err)
+		if patchErr := exec.UpdateTask(task, enumor.TaskCompensateFailed, err.Error(), result); patchErr != nil {
+			return fmt.Errorf("task set %s state failed, after compensate failed, err: %v, patchErr: %v",
+				enumor.TaskCompensateFailed, err, patchErr)
+		}
+		return err
+	}
+
+	task.logger.Infof("compensate by action: %s success", task.CompensateAction)
+	return exec.UpdateTaskState(task, enumor.TaskCompensated)
+}
+
+// compensate 使用任务的请求参数执行补偿动作
+func (exec *executor) compensate(task *Task) (any, error) {
+	if len(task.CompensateAction) == 0 {
+		return nil, fmt.Errorf("task: %s has no compensate action", task.ID)
+	}
+
+	act, exist := action.GetAction(task.CompensateAction)
+	if !exist {
+		return nil, fmt.Errorf("compensate action: %s not found", task.CompensateAction)
+	}
+
+	params, err := task.prepareParams(act)
+	if err != nil {
+		return nil, err
+	}
+
+	return act.Run(task.ExecuteKit, params)
+}
@@ -0,0 +1,119 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+)
+
+func newCompensateTask(id string, state enumor.TaskState, compensate bool, dependOn ...string) *Task {
+	task := &Task{Task: model.Task{ID: id, ActionID: action.ActIDType(id), State: state}}
+	for _, one := range dependOn {
+		task.DependOn = append(task.DependOn, action.ActIDType(one))
+	}
+	if compensate {
+		task.CompensateAction = "compensate_" + enumor.ActionName(id)
+	}
+	return task
+}
+
+func sortedIDs(ids []string) string {
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func TestGetCompensableTasks(t *testing.T) {
+	// a -> b -> e(failed)
+	//   -> c(no compensate) -> d
+	tasks := []*Task{
+		newCompensateTask("a", enumor.TaskSuccess, true),
+		newCompensateTask("b", enumor.TaskSuccess, true, "a"),
+		newCompensateTask("c", enumor.TaskSuccess, false, "a"),
+		newCompensateTask("d", enumor.TaskSuccess, true, "c"),
+		newCompensateTask("e", enumor.TaskFailed, true, "b"),
+	}
+	root, err := BuildTaskRoot(tasks)
+	if err != nil {
+		t.Fatalf("build task root failed, err: %v", err)
+	}
+
+	if root.InCompensation() {
+		t.Errorf("task tree should not be in compensation")
+	}
+
+	// 后继任务都补偿完成后才能补偿前置任务
+	if got := sortedIDs(root.GetCompensableTasks()); got != "b,d" {
+		t.Errorf("compensable tasks expect b,d, but got %s", got)
+	}
+
+	root.SetTaskState([]string{"b", "d"}, enumor.TaskCompensating)
+	if !root.InCompensation() {
+		t.Errorf("task tree should be in compensation")
+	}
+	if got := sortedIDs(root.GetCompensableTasks()); got != "" {
+		t.Errorf("compensable tasks expect empty, but got %s", got)
+	}
+	if got := sortedIDs(root.GetCompensatingTasks()); got != "b,d" {
+		t.Errorf("compensating tasks expect b,d, but got %s", got)
+	}
+
+	root.SetTaskState([]string{"b"}, enumor.TaskCompensated)
+	if got := sortedIDs(root.GetCompensableTasks()); got != "" {
+		t.Errorf("compensable tasks expect empty while d is compensating, but got %s", got)
+	}
+
+	root.SetTaskState([]string{"d"}, enumor.TaskCompensated)
+	if got := sortedIDs(root.GetCompensableTasks()); got != "a" {
+		t.Errorf("compensable tasks expect a, but got %s", got)
+	}
+	if root.HasCompensateFailed() {
+		t.Errorf("task tree should not have compensate failed task")
+	}
+}
+
+func TestCompensateFailedBlockPredecessor(t *testing.T) {
+	tasks := []*Task{
+		newCompensateTask("a", enumor.TaskSuccess, true),
+		newCompensateTask("b", enumor.TaskCompensateFailed, true, "a"),
+		newCompensateTask("c", enumor.TaskSuccess, true),
+		newCompensateTask("d", enumor.TaskFailed, false, "b", "c"),
+	}
+	root, err := BuildTaskRoot(tasks)
+	if err != nil {
+		t.Fatalf("build task root failed, err: %v", err)
+	}
+
+	// b补偿失败，a不能再补偿，不影响没有依赖关系的c
+	if got := sortedIDs(root.GetCompensableTasks()); got != "c" {
+		t.Errorf("compensable tasks expect c, but got %s", got)
+	}
+	if !root.HasCompensateFailed() {
+		t.Errorf("task tree should have compensate failed task")
+	}
+	if node := root.FindNode("b"); node == nil || node.State != enumor.TaskCompensateFailed {
+		t.Errorf("find node b failed, got: %+v", node)
+	}
+}
@@ -47,6 +47,7 @@ Consumer 异步任务消费者。组件分为两类，公共组件、主节点
     1. 处理超时任务
     2. 处理处于Scheduled状态，但执行节点已经挂掉的任务流
     3. 处理处于Running状态，但执行节点正在Shutdown或者已经挂掉的任务流
+    4. 处理超过截止时间的任务流，对超过SLA时间的任务流进行告警
 
 公共组件：
   - scheduler（调度器）:
@@ -118,7 +119,7 @@ func (csm *consumer) Start() error {
 // initLeaderComponent 初始化主节点私有组件并启动，同时设置关闭函数
 func (csm *consumer) initLeaderComponent(kt *kit.Kit, opt *Option) {
 
-	handler := NewLeaderChangeHandler(csm.backend, csm.leader, opt)
+	handler := NewLeaderChangeHandler(csm.backend, csm.leader, csm.mc, opt)
 	handler.Start()
 	csm.closers = append(csm.closers, handler)
 
@@ -130,7 +131,7 @@ func (csm *consumer) initCommonComponent(kt *kit.Kit, opt *Option) {
 	csm.executor = NewExecutor(kt, csm.backend, opt.Executor)
 
 	// 设置调度器
-	csm.scheduler = NewScheduler(csm.backend, csm.executor, csm.leader, opt.Scheduler)
+	csm.scheduler = NewScheduler(csm.backend, csm.executor, csm.leader, opt.Scheduler, opt.Fairness)
 
 	// 设置执行器获取调度器函数。
 	csm.executor.SetGetSchedulerFunc(func() Scheduler {
@@ -26,19 +26,28 @@ import (
 
 	"hcm/pkg/api/core"
 	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
 	"hcm/pkg/async/consumer/leader"
+	"hcm/pkg/async/producer"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
 	cvt "hcm/pkg/tools/converter"
+	"hcm/pkg/tools/times"
 )
 
 // NewDispatcher new dispatcher.
-func NewDispatcher(bd backend.Backend, ld leader.Leader, opt *DispatcherOption) *Dispatcher {
+func NewDispatcher(bd backend.Backend, ld leader.Leader, opt *DispatcherOption,
+	fairness *FairnessOption) *Dispatcher {
+
 	return &Dispatcher{
 		watchIntervalSec: time.Duration(opt.WatchIntervalSec) * time.Second,
+		fairness:         fairness,
 		bd:               bd,
 		ld:               ld,
 		closeCh:          make(chan struct{}),
@@ -47,8 +56,11 @@ func NewDispatcher(bd backend.Backend, ld leader.Leader, opt *DispatcherOption)
 }
 
 // Dispatcher 派发器，负责将Pending状态的任务流，派发到指定节点去执行，并将Flow状态改为Scheduled。。
+// 同时负责触发到期的定时任务流，为其创建Pending状态的任务流。
+// 派发时高优先级的任务流优先，同优先级下按照公平键权重公平派发，并限制每个公平键的并发数量。
 type Dispatcher struct {
 	watchIntervalSec time.Duration
+	fairness         *FairnessOption
 
 	bd backend.Backend
 	ld leader.Leader
@@ -85,20 +97,25 @@ func (d *Dispatcher) WatchPendingFlow() {
 
 // Do 监听处于Pending状态的流，并派发到指定节点。
 func (d *Dispatcher) Do(kt *kit.Kit) error {
-	input := &backend.ListInput{
-		Filter: tools.ExpressionAnd(
-			// 走worker,state 索引
-			tools.RuleEqual("worker", ""),
-			tools.RuleEqual("state", enumor.FlowPending),
-		),
-		Page: core.NewDefaultBasePage(),
+	// 定时任务流触发失败不影响已有任务流的派发
+	if err := d.DispatchScheduledFlow(kt); err != nil {
+		logs.Errorf("%s: dispatch scheduled flow failed, err: %v, rid: %s", constant.AsyncTaskWarnSign, err, kt.Rid)
 	}
-	flows, err := d.bd.ListFlow(kt, input)
+
+	// 统计各公平键已处于调度和运行中的任务流数量，用于公平派发及并发限制
+	inflight, err := d.countInflightFlows(kt)
 	if err != nil {
-		logs.Errorf("list flow failed, err: %v, rid: %s", err, kt.Rid)
 		return err
 	}
 
+	// 每轮最多派发 limit 个任务流，各公平键按照权重分配派发数量，未派发的任务流在后续轮次继续参与公平派发
+	limit := d.fairness.GetDispatchLimit()
+	pendingFlows, err := d.listPendingFlows(kt, inflight, limit)
+	if err != nil {
+		return err
+	}
+
+	flows := selectFairFlows(pendingFlows, inflight, d.fairness, int(limit))
 	if len(flows) == 0 {
 		logs.V(3).Infof("currently no task flows to assign, skip handleRunningFlow, rid: %s", kt.Rid)
 		return nil
@@ -131,6 +148,190 @@ func (d *Dispatcher) Do(kt *kit.Kit) error {
 	return nil
 }
 
+// pendingFlowThenBy 待派发任务流按照优先级倒序查询，相同优先级按照创建时间、ID升序排列
+var pendingFlowThenBy = []types.OrderBy{
+	{Sort: "created_at", Order: core.Ascending},
+	{Sort: "id", Order: core.Ascending},
+}
+
+// listPendingFlows 查询待派发的任务流，每个公平键最多查询 limit 个候选任务流。公平键的候选任务流取满后，
+// 后续查询排除该公平键，避免单个公平键的大量任务流占满查询分页，导致其他公平键的任务流无法参与公平派发。
+func (d *Dispatcher) listPendingFlows(kt *kit.Kit, inflight map[string]uint, limit uint) ([]model.Flow, error) {
+	excluded := d.saturatedFairnessKeys(inflight)
+	counts := make(map[string]uint)
+	exists := make(map[string]struct{})
+	result := make([]model.Flow, 0)
+	for round := 0; round < maxListPendingRounds; round++ {
+		rules := []*filter.AtomRule{
+			// 走worker,state 索引
+			tools.RuleEqual("worker", ""),
+			tools.RuleEqual("state", enumor.FlowPending),
+		}
+		if len(excluded) != 0 {
+			rules = append(rules, tools.RuleNotIn("fairness_key", excluded))
+		}
+		input := &backend.ListInput{
+			Filter: tools.ExpressionAnd(rules...),
+			Page: &core.BasePage{
+				Start: 0,
+				Limit: core.DefaultMaxPageLimit,
+				Sort:  "priority",
+				Order: core.Descending,
+			},
+			// 相同优先级的任务流先创建的先派发，避免分页查询结果不稳定导致较早创建的任务流一直查询不到
+			ThenBy: pendingFlowThenBy,
+		}
+		flows, err := d.bd.ListFlow(kt, input)
+		if err != nil {
+			logs.Errorf("list flow failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		newFull := false
+		for _, one := range flows {
+			if _, exist := exists[one.ID]; exist || counts[one.FairnessKey] >= limit {
+				continue
+			}
+
+			exists[one.ID] = struct{}{}
+			result = append(result, one)
+			counts[one.FairnessKey]++
+			if counts[one.FairnessKey] == limit {
+				excluded = append(excluded, one.FairnessKey)
+				newFull = true
+			}
+		}
+
+		// 已经查询到全部待派发任务流，或者没有新的公平键取满，再次查询也不会有新的候选任务流
+		if uint(len(flows)) < input.Page.Limit || !newFull {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// countInflightFlows 统计各公平键处于调度、运行和补偿中的任务流数量，未配置公平键并发上限时无需统计
+func (d *Dispatcher) countInflightFlows(kt *kit.Kit) (map[string]uint, error) {
+	inflight := make(map[string]uint)
+	if !d.fairness.HasConcurrencyLimit() {
+		return inflight, nil
+	}
+
+	input := &backend.ListInput{
+		Filter: tools.ContainersExpression("state", []enumor.FlowState{enumor.FlowScheduled, enumor.FlowRunning,
+			enumor.FlowCompensating}),
+		Page:   core.NewDefaultBasePage(),
+		Fields: []string{"id", "fairness_key"},
+	}
+	for {
+		flows, err := d.bd.ListFlow(kt, input)
+		if err != nil {
+			logs.Errorf("list inflight flow failed, err: %v, rid: %s", err, kt.Rid)
+			return nil, err
+		}
+
+		for _, one := range flows {
+			inflight[one.FairnessKey]++
+		}
+
+		if uint(len(flows)) < input.Page.Limit {
+			break
+		}
+		input.Page.Start += uint32(input.Page.Limit)
+	}
+
+	return inflight, nil
+}
+
+// saturatedFairnessKeys 获取已达到并发上限的公平键，查询待派发任务流时直接过滤，避免其占满派发窗口
+func (d *Dispatcher) saturatedFairnessKeys(inflight map[string]uint) []string {
+	keys := make([]string, 0)
+	for key, count := range inflight {
+		if concurrency := d.fairness.GetConcurrency(key); concurrency > 0 && count >= concurrency {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// DispatchScheduledFlow 触发到期的定时任务流，每个触发时间点只会创建一个任务流。
+// 主节点宕机等原因错过的多个触发时间点只会补偿触发一次，下一次触发时间从当前时间重新计算。
+func (d *Dispatcher) DispatchScheduledFlow(kt *kit.Kit) error {
+	now := times.ConvStdTimeNow()
+
+	page := core.NewDefaultBasePage()
+	for {
+		input := &backend.ListInput{
+			Filter: tools.EqualExpression("state", enumor.ScheduledFlowEnabled),
+			Page:   page,
+		}
+		flows, err := d.bd.ListScheduledFlow(kt, input)
+		if err != nil {
+			logs.Errorf("list scheduled flow failed, err: %v, rid: %s", err, kt.Rid)
+			return err
+		}
+
+		for i := range flows {
+			d.triggerScheduledFlow(kt, &flows[i], now)
+		}
+
+		if uint(len(flows)) < page.Limit {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) triggerScheduledFlow(kt *kit.Kit, sf *model.ScheduledFlow, now time.Time) {
+	source, err := time.Parse(constant.TimeStdFormat, sf.NextTriggerAt)
+	if err != nil {
+		logs.Errorf("parse scheduled flow next trigger time failed, err: %v, id: %s, rid: %s", err, sf.ID, kt.Rid)
+		return
+	}
+
+	if source.After(now) {
+		return
+	}
+
+	target, err := model.NextTriggerTime(sf.Spec, now)
+	if err != nil {
+		logs.Errorf("calculate scheduled flow next trigger time failed, err: %v, id: %s, rid: %s", err, sf.ID,
+			kt.Rid)
+		return
+	}
+
+	flow, err := producer.BuildScheduledFlow(kt, sf)
+	if err != nil {
+		logs.Errorf("%s: build scheduled flow failed, err: %v, id: %s, rid: %s", constant.AsyncTaskWarnSign, err,
+			sf.ID, kt.Rid)
+		return
+	}
+
+	info := &backend.TriggerScheduledFlowInfo{
+		ID:     sf.ID,
+		Source: source,
+		Target: target,
+	}
+	flowID, err := d.bd.TriggerScheduledFlow(kt, info, flow)
+	if err != nil {
+		// 定时任务流已经被触发或者被修改，本次跳过
+		if errf.Error(err).Code == errf.RecordNotUpdate {
+			logs.V(3).Infof("scheduled flow %s already triggered or changed, skip, rid: %s", sf.ID, kt.Rid)
+			return
+		}
+
+		logs.Errorf("trigger scheduled flow failed, err: %v, id: %s, rid: %s", err, sf.ID, kt.Rid)
+		return
+	}
+
+	logs.Infof("scheduled flow %s(%s) triggered, flow id: %s, next trigger at: %s, rid: %s", sf.Name, sf.ID,
+		flowID, times.ConvStdTimeFormat(target), kt.Rid)
+}
+
 // Close dispatcher
 func (d *Dispatcher) Close() {
 
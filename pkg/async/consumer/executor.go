@@ -31,14 +31,18 @@ import (
 	"hcm/pkg/async/backend"
 	"hcm/pkg/async/backend/model"
 	"hcm/pkg/async/compctrl"
+	"hcm/pkg/async/producer"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	"hcm/pkg/dal/dao/tools"
 	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/types"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
+	"hcm/pkg/tools/json"
 	"hcm/pkg/tools/retry"
+	"hcm/pkg/tools/slice"
 	"hcm/pkg/tools/times"
 )
 
@@ -141,7 +145,9 @@ func (exec *executor) initWorkerTask(flow *Flow, task *Task) {
 	}
 
 	// 设置task执行所需要的 kit，更新Task函数，所属流
-	task.InitDep(run.NewExecuteContext(task.Kit, flow.ShareData), func(taskKit *kit.Kit, task *model.Task) error {
+	task.logger = newTaskLogger(exec.kt, exec.backend, task)
+	task.InitDep(run.NewExecuteContext(task.Kit, flow.ShareData, task.logger), func(taskKit *kit.Kit,
+		task *model.Task) error {
 		return exec.backend.UpdateTask(exec.kt, task)
 	}, flow)
 
@@ -172,19 +178,11 @@ func (exec *executor) workerDo(task *Task) (err error) {
 	// 无论任务成功还是失败，都需要交给scheduler分析任务流的状态
 	// 执行完的任务回写到scheduler用于获取待执行的任务
 	defer exec.GetSchedulerFunc().EntryTask(task)
+	// 持久化剩余的任务执行日志
+	defer task.logger.Flush()
 	var runErr error
 	var failedRet any
 
-	// 执行任务
-	act, exist := action.GetAction(task.ActionName)
-	if !exist {
-		return fmt.Errorf("action: %s not found", task.ActionName)
-	}
-
-	if err := task.ValidateBeforeExec(act); err != nil {
-		return err
-	}
-
 	defer func() {
 		if runErr == nil {
 			return
@@ -207,6 +205,41 @@ func (exec *executor) workerDo(task *Task) (err error) {
 		return
 	}()
 
+	// 任务流补偿中，执行成功的任务和补偿中断的任务执行补偿动作
+	if task.State == enumor.TaskSuccess || task.State == enumor.TaskCompensating {
+		return exec.runCompensate(task)
+	}
+
+	// 任务执行条件不成立，跳过该任务
+	if task.State == enumor.TaskPending && task.Condition != nil {
+		hit, condErr := exec.evaluateCondition(task)
+		if condErr != nil {
+			runErr = fmt.Errorf("evaluate task condition failed, err: %v", condErr)
+			return
+		}
+
+		if !hit {
+			task.logger.Infof("task condition is not satisfied, skip it")
+			return exec.UpdateTaskState(task, enumor.TaskSkipped)
+		}
+	}
+
+	// 子任务流任务，创建子任务流后等待其执行结束
+	if task.IsChildFlow() {
+		runErr = exec.runChildFlow(task)
+		return
+	}
+
+	// 执行任务
+	act, exist := action.GetAction(task.ActionName)
+	if !exist {
+		return fmt.Errorf("action: %s not found", task.ActionName)
+	}
+
+	if err := task.ValidateBeforeExec(act); err != nil {
+		return err
+	}
+
 	if !task.Retry.IsEnable() {
 		_, failedRet, runErr = exec.runTaskOnce(task, act)
 		return
@@ -215,6 +248,8 @@ func (exec *executor) workerDo(task *Task) (err error) {
 	if task.State == enumor.TaskRollback && task.Reason.RollbackCount >= task.Retry.Policy.Count {
 		// 超过指定重试次数，置为失败
 		runErr = fmt.Errorf("too many retries: %w", errors.New(task.Reason.Message))
+		task.logger.Errorf("task rollback count %d exceed retry count, last err: %s", task.Reason.RollbackCount,
+			task.Reason.Message)
 		return
 	}
 	// 减去已经执行的count
@@ -244,6 +279,12 @@ func (exec *executor) workerDo(task *Task) (err error) {
 // runTaskOnce 只有执行Action运行逻辑失败才会允许重试，更改状态失败不进行重试。
 // 如果执行成功直接写入状态和结果，失败时才将状态和结果返回到上层
 func (exec *executor) runTaskOnce(task *Task, act action.Action) (needRetry bool, failedResult any, err error) {
+	// 每次执行记录一次执行结果
+	task.logger.StartAttempt(task.State)
+	defer func() {
+		task.logger.EndAttempt(err)
+	}()
+
 	params, err := task.prepareParams(act)
 	if err != nil {
 		return false, nil, err
@@ -288,6 +329,102 @@ func (exec *executor) runTaskOnce(task *Task, act action.Action) (needRetry bool
 	return false, nil, nil
 }
 
+// evaluateCondition 计算任务执行条件是否成立，条件中引用的依赖任务执行结果从DB中获取
+func (exec *executor) evaluateCondition(task *Task) (bool, error) {
+	env := tableasync.ConditionEnv{
+		Results: make(map[string]types.JsonField),
+	}
+	if task.Flow != nil {
+		env.ShareData = task.Flow.ShareData
+	}
+
+	actionIDs := make([]string, 0)
+	for _, rule := range task.Condition.Rules {
+		if rule.Source == tableasync.ConditionSourceResult {
+			actionIDs = append(actionIDs, rule.ActionID)
+		}
+	}
+
+	if len(actionIDs) != 0 {
+		kt := task.ExecuteKit.Kit()
+		parents, err := exec.backend.ListTask(kt, &backend.ListInput{
+			Filter: tools.ExpressionAnd(
+				tools.RuleEqual("flow_id", task.FlowID),
+				tools.RuleIn("action_id", slice.Unique(actionIDs)),
+			),
+			Page: core.NewDefaultBasePage(),
+		})
+		if err != nil {
+			logs.Errorf("list condition depend tasks failed, err: %v, task id: %s, rid: %s", err, task.ID, kt.Rid)
+			return false, err
+		}
+
+		for _, one := range parents {
+			env.Results[string(one.ActionID)] = one.Result
+		}
+	}
+
+	return task.Condition.Evaluate(env)
+}
+
+// runChildFlow 创建子任务流，并将任务置为running状态、记录子任务流ID，子任务流执行结束后由调度器更新任务状态
+func (exec *executor) runChildFlow(task *Task) error {
+	if task.State != enumor.TaskPending {
+		return fmt.Errorf("child flow task can not run, state: %s", task.State)
+	}
+
+	params := new(action.ChildFlowParams)
+	if err := json.UnmarshalFromString(string(task.Params), params); err != nil {
+		return fmt.Errorf("decode child flow params failed, err: %v", err)
+	}
+
+	kt := task.ExecuteKit.Kit()
+	flow, err := producer.BuildChildFlow(kt, params, fmt.Sprintf("child flow of task: %s", task.ID))
+	if err != nil {
+		return err
+	}
+
+	// 子任务流继承父任务流的优先级和公平键
+	if task.Flow != nil {
+		flow.Priority, flow.FairnessKey = task.Flow.Priority, task.Flow.FairnessKey
+	}
+
+	// 先通过CAS将任务置为running再创建子任务流，保证同一个任务只会创建一个子任务流
+	info := &backend.UpdateTaskInfo{ID: task.ID, Source: enumor.TaskPending, Target: enumor.TaskRunning}
+	if err = exec.backend.UpdateTaskStateByCAS(kt, info); err != nil {
+		if errf.Error(err).Code != errf.RecordNotUpdate {
+			return err
+		}
+
+		// 任务已经被其他执行者处理，以存储中的状态为准，不再创建子任务流
+		tasks, listErr := listTaskByIDs(kt, exec.backend, []string{task.ID})
+		if listErr != nil || len(tasks) == 0 {
+			return fmt.Errorf("child flow task state changed, but get task failed, err: %v", listErr)
+		}
+		task.State = tasks[0].State
+		task.logger.Infof("child flow task state already changed to %s, skip creating child flow", task.State)
+		return nil
+	}
+	task.State = enumor.TaskRunning
+
+	childFlowID, err := exec.backend.CreateFlow(kt, flow)
+	if err != nil {
+		logs.Errorf("create child flow failed, err: %v, task id: %s, rid: %s", err, task.ID, kt.Rid)
+		return err
+	}
+
+	task.logger.Infof("child flow %s(%s) created", params.FlowName, childFlowID)
+
+	result := action.ChildFlowResult{ChildFlowID: childFlowID}
+	if err = exec.UpdateTaskStateResult(task, enumor.TaskRunning, result); err != nil {
+		logs.Errorf("update child flow task to running failed, err: %v, task id: %s, child flow id: %s, rid: %s",
+			err, task.ID, childFlowID, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
 // Push 任务写入到initQueue
 func (exec *executor) Push(flow *Flow, task *Task) {
 
@@ -343,9 +480,17 @@ func (exec *executor) CancelFlow(kt *kit.Kit, flowId string) error {
 			err := exec.UpdateTask(&Task{Task: task}, enumor.TaskCancel, string(task.State), nil)
 			logs.Errorf("fail to update task(%s) state for cancel, err: %v, rid: %s", task.ID, err, kt.Rid)
 			cancelIDs = append(cancelIDs, task.ID)
-		case enumor.TaskSuccess, enumor.TaskCancel:
+		case enumor.TaskSuccess, enumor.TaskCancel, enumor.TaskSkipped,
+			enumor.TaskCompensating, enumor.TaskCompensated, enumor.TaskCompensateFailed:
 			// 	跳过
 		}
+
+		// 子任务流任务需要同时取消其创建的子任务流
+		if task.IsChildFlow() && task.State == enumor.TaskRunning {
+			if err := cancelChildFlow(kt, exec.backend, task); err != nil {
+				logs.Errorf("fail to cancel child flow, err: %v, task id: %s, rid: %s", err, task.ID, kt.Rid)
+			}
+		}
 	}
 	if len(cancelIDs) == 0 {
 		return nil
@@ -0,0 +1,129 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"sort"
+
+	"hcm/pkg/async/backend/model"
+)
+
+// fairGroup 同一公平键下待派发的任务流
+type fairGroup struct {
+	key    string
+	flows  []model.Flow
+	weight uint
+	// served 公平键已经占用的任务流数量（包含已处于调度和运行中的任务流）
+	served uint
+	// remain 公平键剩余可派发数量，-1表示不限制
+	remain int
+}
+
+func (g *fairGroup) available() bool {
+	return len(g.flows) != 0 && g.remain != 0
+}
+
+// before 判断当前公平键是否应该先于另一个公平键派发：先比较队首任务流优先级，
+// 再比较加权后的已占用数量（served/weight，通过交叉相乘避免浮点运算），最后比较队首任务流创建时间。
+func (g *fairGroup) before(other *fairGroup) bool {
+	a, b := g.flows[0], other.flows[0]
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+
+	left, right := g.served*other.weight, other.served*g.weight
+	if left != right {
+		return left < right
+	}
+
+	if a.CreatedAt != b.CreatedAt {
+		return a.CreatedAt < b.CreatedAt
+	}
+
+	return g.key < other.key
+}
+
+// selectFairFlows 按照优先级和公平键加权公平调度，从待派发的任务流中选出最多limit个任务流，返回结果即为派发顺序。
+// inflight 为各公平键已处于调度和运行中的任务流数量，达到公平键并发上限的任务流本轮不派发。
+// Note: 待派发任务流由 Dispatcher.listPendingFlows 按公平键限制候选数量后查询，单个公平键不会占满候选任务流。
+func selectFairFlows(flows []model.Flow, inflight map[string]uint, opt *FairnessOption, limit int) []model.Flow {
+	if len(flows) == 0 || limit <= 0 {
+		return make([]model.Flow, 0)
+	}
+
+	groupMap := make(map[string]*fairGroup)
+	groups := make([]*fairGroup, 0)
+	for _, one := range flows {
+		group, exist := groupMap[one.FairnessKey]
+		if !exist {
+			group = &fairGroup{
+				key:    one.FairnessKey,
+				weight: opt.GetWeight(one.FairnessKey),
+				served: inflight[one.FairnessKey],
+				remain: -1,
+			}
+			if concurrency := opt.GetConcurrency(one.FairnessKey); concurrency > 0 {
+				group.remain = 0
+				if concurrency > group.served {
+					group.remain = int(concurrency - group.served)
+				}
+			}
+			groupMap[one.FairnessKey] = group
+			groups = append(groups, group)
+		}
+		group.flows = append(group.flows, one)
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group.flows, func(i, j int) bool {
+			if group.flows[i].Priority != group.flows[j].Priority {
+				return group.flows[i].Priority > group.flows[j].Priority
+			}
+			return group.flows[i].CreatedAt < group.flows[j].CreatedAt
+		})
+	}
+
+	result := make([]model.Flow, 0, limit)
+	for len(result) < limit {
+		var selected *fairGroup
+		for _, group := range groups {
+			if !group.available() {
+				continue
+			}
+
+			if selected == nil || group.before(selected) {
+				selected = group
+			}
+		}
+
+		if selected == nil {
+			break
+		}
+
+		result = append(result, selected.flows[0])
+		selected.flows = selected.flows[1:]
+		selected.served++
+		if selected.remain > 0 {
+			selected.remain--
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,243 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+)
+
+func newFairFlow(id, key string, priority enumor.FlowPriority, createdAt string) model.Flow {
+	return model.Flow{ID: id, FairnessKey: key, Priority: priority, CreatedAt: createdAt}
+}
+
+func flowIDs(flows []model.Flow) string {
+	ids := make([]string, 0, len(flows))
+	for _, one := range flows {
+		ids = append(ids, one.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestSelectFairFlows(t *testing.T) {
+	flows := []model.Flow{
+		newFairFlow("a1", "account:a", enumor.FlowPriorityNormal, "2024-11-12 10:00:01"),
+		newFairFlow("a2", "account:a", enumor.FlowPriorityNormal, "2024-11-12 10:00:02"),
+		newFairFlow("a3", "account:a", enumor.FlowPriorityNormal, "2024-11-12 10:00:03"),
+		newFairFlow("a4", "account:a", enumor.FlowPriorityNormal, "2024-11-12 10:00:04"),
+		newFairFlow("b1", "account:b", enumor.FlowPriorityNormal, "2024-11-12 10:00:05"),
+		newFairFlow("b2", "account:b", enumor.FlowPriorityNormal, "2024-11-12 10:00:06"),
+		newFairFlow("c1", "biz:1", enumor.FlowPriorityHigh, "2024-11-12 10:00:07"),
+	}
+
+	cases := []struct {
+		name     string
+		inflight map[string]uint
+		opt      *FairnessOption
+		limit    int
+		expect   string
+	}{
+		{
+			name:   "priority first then round robin",
+			limit:  10,
+			expect: "c1,a1,b1,a2,b2,a3,a4",
+		},
+		{
+			name:   "weighted",
+			opt:    &FairnessOption{Keys: map[string]FairnessKeyOption{"account:a": {Weight: 3}}},
+			limit:  10,
+			expect: "c1,a1,b1,a2,a3,a4,b2",
+		},
+		{
+			name:     "key prefix concurrency and inflight",
+			inflight: map[string]uint{"account:b": 1},
+			opt: &FairnessOption{DefaultConcurrency: 2,
+				Keys: map[string]FairnessKeyOption{"biz": {Concurrency: 5}}},
+			limit:  10,
+			expect: "c1,a1,a2,b1",
+		},
+		{
+			name:   "limit",
+			limit:  3,
+			expect: "c1,a1,b1",
+		},
+	}
+
+	for _, c := range cases {
+		got := flowIDs(selectFairFlows(flows, c.inflight, c.opt, c.limit))
+		if got != c.expect {
+			t.Errorf("case %s: expect %s, but got %s", c.name, c.expect, got)
+		}
+	}
+}
+
+// pendingFlowBackend 只实现派发查询的后端，按照优先级倒序和查询指定的次级排序返回未被排除公平键的待派发任务流
+type pendingFlowBackend struct {
+	backend.Backend
+	flows []model.Flow
+}
+
+func flowSortValue(flow model.Flow, field string) string {
+	switch field {
+	case "created_at":
+		return flow.CreatedAt
+	case "id":
+		return flow.ID
+	default:
+		return ""
+	}
+}
+
+func (bd *pendingFlowBackend) ListFlow(_ *kit.Kit, input *backend.ListInput) ([]model.Flow, error) {
+	excluded := make(map[string]struct{})
+	for _, rule := range input.Filter.Rules {
+		atom, ok := rule.(*filter.AtomRule)
+		if !ok || atom.Field != "fairness_key" {
+			continue
+		}
+		for _, key := range atom.Value.([]string) {
+			excluded[key] = struct{}{}
+		}
+	}
+
+	sorted := append([]model.Flow{}, bd.flows...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		for _, one := range input.ThenBy {
+			vi, vj := flowSortValue(sorted[i], one.Sort), flowSortValue(sorted[j], one.Sort)
+			if vi == vj {
+				continue
+			}
+			if one.Order.Order() == core.Descending {
+				return vi > vj
+			}
+			return vi < vj
+		}
+		return false
+	})
+
+	result := make([]model.Flow, 0)
+	for _, one := range sorted {
+		if uint(len(result)) >= input.Page.Limit {
+			break
+		}
+		if _, exist := excluded[one.FairnessKey]; !exist {
+			result = append(result, one)
+		}
+	}
+
+	return result, nil
+}
+
+func TestNoisyKeyNotStarveOthers(t *testing.T) {
+	// 嘈杂的公平键先创建了远超一页的任务流，安静的公平键的任务流排在分页之外
+	flows := make([]model.Flow, 0)
+	for i := 0; i < 2000; i++ {
+		flows = append(flows, newFairFlow(fmt.Sprintf("noisy%d", i), "account:noisy", enumor.FlowPriorityNormal,
+			"2024-11-12 10:00:00"))
+	}
+	for i := 0; i < 3; i++ {
+		flows = append(flows, newFairFlow(fmt.Sprintf("quiet%d", i), "account:quiet", enumor.FlowPriorityNormal,
+			"2024-11-12 10:00:01"))
+	}
+
+	opt := &FairnessOption{DispatchLimit: 10}
+	d := &Dispatcher{fairness: opt, bd: &pendingFlowBackend{flows: flows}}
+	limit := opt.GetDispatchLimit()
+	pending, err := d.listPendingFlows(kit.New(), map[string]uint{}, limit)
+	if err != nil {
+		t.Fatalf("list pending flows failed, err: %v", err)
+	}
+
+	selected := selectFairFlows(pending, map[string]uint{}, opt, int(limit))
+	if len(selected) != int(limit) {
+		t.Fatalf("expect %d flows dispatched in one round, but got %d", limit, len(selected))
+	}
+
+	counts := make(map[string]int)
+	for _, one := range selected {
+		counts[one.FairnessKey]++
+	}
+	if counts["account:quiet"] != 3 || counts["account:noisy"] != 7 {
+		t.Errorf("quiet key should not be starved, got: %s", flowIDs(selected))
+	}
+}
+
+func TestListPendingFlowsOrder(t *testing.T) {
+	// 写入顺序与创建时间不一致，相同优先级的任务流按照创建时间、ID升序返回
+	flows := []model.Flow{
+		newFairFlow("a4", "account:a", enumor.FlowPriorityNormal, "2024-11-12 10:00:03"),
+		newFairFlow("a3", "account:a", enumor.FlowPriorityNormal, "2024-11-12 10:00:02"),
+		newFairFlow("a2", "account:a", enumor.FlowPriorityNormal, "2024-11-12 10:00:01"),
+		newFairFlow("a1", "account:a", enumor.FlowPriorityNormal, "2024-11-12 10:00:01"),
+		newFairFlow("a5", "account:a", enumor.FlowPriorityHigh, "2024-11-12 10:00:04"),
+		newFairFlow("b2", "account:b", enumor.FlowPriorityNormal, "2024-11-12 10:00:02"),
+		newFairFlow("b1", "account:b", enumor.FlowPriorityNormal, "2024-11-12 10:00:00"),
+	}
+
+	cases := []struct {
+		name   string
+		limit  uint
+		expect string
+	}{
+		{
+			name:   "all",
+			limit:  10,
+			expect: "a5,b1,a1,a2,a3,b2,a4",
+		},
+		{
+			name:   "limit per fairness key",
+			limit:  2,
+			expect: "a5,b1,a1,b2",
+		},
+	}
+
+	for _, c := range cases {
+		d := &Dispatcher{fairness: &FairnessOption{}, bd: &pendingFlowBackend{flows: flows}}
+		pending, err := d.listPendingFlows(kit.New(), map[string]uint{}, c.limit)
+		if err != nil {
+			t.Fatalf("case %s: list pending flows failed, err: %v", c.name, err)
+		}
+		if got := flowIDs(pending); got != c.expect {
+			t.Errorf("case %s: expect %s, but got %s", c.name, c.expect, got)
+		}
+	}
+}
+
+func TestDefaultConcurrencyIgnoreEmptyKey(t *testing.T) {
+	opt := &FairnessOption{DefaultConcurrency: 2}
+	if got := opt.GetConcurrency(""); got != 0 {
+		t.Errorf("default concurrency should not limit empty fairness key, got: %d", got)
+	}
+	if got := opt.GetConcurrency("account:a"); got != 2 {
+		t.Errorf("default concurrency of account:a expect 2, got: %d", got)
+	}
+}
@@ -30,11 +30,12 @@ import (
 )
 
 // NewLeaderChangeHandler new leader change handler.
-func NewLeaderChangeHandler(bd backend.Backend, ld leader.Leader, opt *Option) *LeaderChangeHandler {
+func NewLeaderChangeHandler(bd backend.Backend, ld leader.Leader, mc *metric, opt *Option) *LeaderChangeHandler {
 	return &LeaderChangeHandler{
 		opt:     opt,
 		ld:      ld,
 		bd:      bd,
+		mc:      mc,
 		closeCh: make(chan struct{}),
 		closers: make([]compctrl.Closer, 0),
 		wg:      sync.WaitGroup{},
@@ -47,6 +48,7 @@ type LeaderChangeHandler struct {
 
 	ld leader.Leader
 	bd backend.Backend
+	mc *metric
 
 	dispatcher *Dispatcher
 	watchDog   WatchDog
@@ -103,13 +105,13 @@ func (handler *LeaderChangeHandler) Do() {
 }
 
 func (handler *LeaderChangeHandler) startLeaderComponent() {
-	dis := NewDispatcher(handler.bd, handler.ld, handler.opt.Dispatcher)
+	dis := NewDispatcher(handler.bd, handler.ld, handler.opt.Dispatcher, handler.opt.Fairness)
 	dis.Start()
 	handler.closers = append(handler.closers, dis)
 	handler.dispatcher = dis
 
 	// 初始化watchdog并启动同时设置关闭函数
-	wd := NewWatchDog(handler.bd, handler.ld, handler.opt.WatchDog)
+	wd := NewWatchDog(handler.bd, handler.ld, handler.mc, handler.opt.WatchDog)
 	wd.Start()
 	handler.closers = append(handler.closers, wd)
 	handler.watchDog = wd
@@ -20,6 +20,8 @@
 package consumer
 
 import (
+	"hcm/pkg/metrics"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -28,8 +30,55 @@ import (
 func initMetric(register prometheus.Registerer) *metric {
 	m := new(metric)
 
+	m.flowDeadlineExceededCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.AsyncSubSys,
+			Name:      "flow_deadline_exceeded_total",
+			Help:      "the total count of flows which are failed or canceled by watch dog for exceeding deadline",
+		}, []string{"flow_name", "state"})
+	register.MustRegister(m.flowDeadlineExceededCounter)
+
+	m.flowSLABreachedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.AsyncSubSys,
+			Name:      "flow_sla_breached_total",
+			Help:      "the total count of flows which are not finished after sla time",
+		}, []string{"flow_name"})
+	register.MustRegister(m.flowSLABreachedCounter)
+
+	m.flowSLABreachedGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.AsyncSubSys,
+			Name:      "flow_sla_breached",
+			Help:      "the current count of unfinished flows which have breached sla time",
+		}, []string{"flow_name"})
+	register.MustRegister(m.flowSLABreachedGauge)
+
+	m.flowNotifyErrCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.AsyncSubSys,
+			Name:      "flow_notify_err_total",
+			Help:      "the total error count when notify flow deadline exceeded or sla breached event",
+		}, []string{"event"})
+	register.MustRegister(m.flowNotifyErrCounter)
+
 	return m
 }
 
 type metric struct {
+	// flowDeadlineExceededCounter 超过截止时间被看门狗置为失败或取消的任务流数量，state为处理前的任务流状态
+	flowDeadlineExceededCounter *prometheus.CounterVec
+
+	// flowSLABreachedCounter 超过SLA时间仍未结束的任务流数量，每个任务流只统计一次
+	flowSLABreachedCounter *prometheus.CounterVec
+
+	// flowSLABreachedGauge 当前超过SLA时间仍未结束的任务流数量
+	flowSLABreachedGauge *prometheus.GaugeVec
+
+	// flowNotifyErrCounter 任务流告警通知失败次数
+	flowNotifyErrCounter *prometheus.CounterVec
 }
@@ -0,0 +1,102 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/kit"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	cvt "hcm/pkg/tools/converter"
+)
+
+// FlowEventType 任务流告警事件类型
+type FlowEventType string
+
+const (
+	// FlowDeadlineExceeded 任务流超过截止时间，已被看门狗置为失败或取消
+	FlowDeadlineExceeded FlowEventType = "deadline_exceeded"
+	// FlowSLABreached 任务流超过SLA时间仍未结束
+	FlowSLABreached FlowEventType = "sla_breached"
+)
+
+// FlowEvent 任务流告警事件
+type FlowEvent struct {
+	Type FlowEventType `json:"type"`
+	// Flow 触发告警时的任务流信息
+	Flow model.Flow `json:"flow"`
+}
+
+// FlowNotifier 任务流告警通知，看门狗发现任务流超过截止时间或SLA时间时调用，未设置时只记录日志和metrics。
+type FlowNotifier interface {
+	Notify(kt *kit.Kit, event *FlowEvent) error
+}
+
+var _ FlowNotifier = new(cmsiFlowNotifier)
+
+// NewCmsiFlowNotifier 创建通过CMSI发送邮件的任务流告警通知
+func NewCmsiFlowNotifier(cli cmsi.Client, receivers []string) FlowNotifier {
+	return &cmsiFlowNotifier{
+		cli:       cli,
+		receivers: receivers,
+	}
+}
+
+type cmsiFlowNotifier struct {
+	cli       cmsi.Client
+	receivers []string
+}
+
+// Notify 发送任务流告警邮件
+func (n *cmsiFlowNotifier) Notify(kt *kit.Kit, event *FlowEvent) error {
+	var title string
+	switch event.Type {
+	case FlowDeadlineExceeded:
+		title = fmt.Sprintf("HCM异步任务流超过截止时间: %s(%s)", event.Flow.Name, event.Flow.ID)
+	case FlowSLABreached:
+		title = fmt.Sprintf("HCM异步任务流超过SLA时间: %s(%s)", event.Flow.Name, event.Flow.ID)
+	default:
+		return fmt.Errorf("unsupported flow event type: %s", event.Type)
+	}
+
+	mail := &cmsi.CmsiMail{
+		Receiver: strings.Join(n.receivers, ","),
+		Title:    title,
+		Content: fmt.Sprintf(flowEventMailTemplate, event.Flow.ID, event.Flow.Name, event.Flow.State,
+			html.EscapeString(event.Flow.Memo), html.EscapeString(cvt.PtrToVal(event.Flow.Worker)),
+			event.Flow.CreatedAt, event.Flow.DeadlineAt, event.Flow.SLAAt),
+		BodyFormat: "Html",
+	}
+
+	return n.cli.SendMail(kt, mail)
+}
+
+// flowEventMailTemplate 任务流告警邮件模版
+const flowEventMailTemplate = `<p>任务流ID: %s</p>
+<p>任务流名称: %s</p>
+<p>任务流状态: %s</p>
+<p>备注: %s</p>
+<p>执行节点: %s</p>
+<p>创建时间: %s</p>
+<p>截止时间: %s</p>
+<p>SLA时间: %s</p>`
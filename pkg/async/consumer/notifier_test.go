@@ -0,0 +1,114 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"strings"
+	"testing"
+
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/kit"
+	"hcm/pkg/thirdparty/api-gateway/cmsi"
+	"hcm/pkg/tools/converter"
+)
+
+// recordMailClient 记录发送的邮件
+type recordMailClient struct {
+	mails []*cmsi.CmsiMail
+}
+
+func (c *recordMailClient) SendMail(_ *kit.Kit, m *cmsi.CmsiMail) error {
+	c.mails = append(c.mails, m)
+	return nil
+}
+
+func TestCmsiFlowNotifier(t *testing.T) {
+	flow := model.Flow{
+		ID:     "00000001",
+		Name:   "test_flow",
+		Memo:   `<script>alert("memo")</script>`,
+		Worker: converter.ValToPtr("node<1>&"),
+	}
+
+	cases := []struct {
+		name          string
+		event         *FlowEvent
+		expectTitle   string
+		expectContain []string
+		expectErr     bool
+	}{
+		{
+			// 备注和执行节点可能包含用户输入，需要转义后再放入html邮件
+			name:        "deadline exceeded escape html",
+			event:       &FlowEvent{Type: FlowDeadlineExceeded, Flow: flow},
+			expectTitle: "HCM异步任务流超过截止时间: test_flow(00000001)",
+			expectContain: []string{
+				"<p>备注: &lt;script&gt;alert(&#34;memo&#34;)&lt;/script&gt;</p>",
+				"<p>执行节点: node&lt;1&gt;&amp;</p>",
+			},
+		},
+		{
+			name:          "sla breached",
+			event:         &FlowEvent{Type: FlowSLABreached, Flow: model.Flow{ID: "00000002", Name: "test_flow"}},
+			expectTitle:   "HCM异步任务流超过SLA时间: test_flow(00000002)",
+			expectContain: []string{"<p>备注: </p>", "<p>执行节点: </p>"},
+		},
+		{
+			name:      "unsupported event type",
+			event:     &FlowEvent{Type: "unknown", Flow: flow},
+			expectErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		cli := new(recordMailClient)
+		notifier := NewCmsiFlowNotifier(cli, []string{"user1", "user2"})
+
+		err := notifier.Notify(kit.New(), c.event)
+		if c.expectErr {
+			if err == nil || len(cli.mails) != 0 {
+				t.Errorf("case %s: expect err and no mail sent, but got err: %v, mails: %d", c.name, err,
+					len(cli.mails))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case %s: notify failed, err: %v", c.name, err)
+			continue
+		}
+		if len(cli.mails) != 1 {
+			t.Errorf("case %s: expect 1 mail, but got: %d", c.name, len(cli.mails))
+			continue
+		}
+
+		mail := cli.mails[0]
+		if mail.Title != c.expectTitle || mail.Receiver != "user1,user2" || mail.BodyFormat != "Html" {
+			t.Errorf("case %s: mail header mismatch, got: %+v", c.name, mail)
+		}
+		for _, one := range c.expectContain {
+			if !strings.Contains(mail.Content, one) {
+				t.Errorf("case %s: expect content contains %s, but got: %s", c.name, one, mail.Content)
+			}
+		}
+		if strings.Contains(mail.Content, "<script>") {
+			t.Errorf("case %s: content should not contain raw script tag, got: %s", c.name, mail.Content)
+		}
+	}
+}
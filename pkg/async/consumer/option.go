@@ -19,7 +19,11 @@
 
 package consumer
 
-import "hcm/pkg/criteria/validator"
+import (
+	"strings"
+
+	"hcm/pkg/criteria/validator"
+)
 
 // Option defines consumer run option.
 type Option struct {
@@ -27,6 +31,8 @@ type Option struct {
 	Executor   *ExecutorOption   `json:"executor" validate:"required"`
 	Dispatcher *DispatcherOption `json:"dispatcher" validate:"required"`
 	WatchDog   *WatchDogOption   `json:"watch_dog" validate:"required"`
+	// Fairness 任务流优先级和公平派发配置，未配置时仅按照优先级派发，且不限制公平键并发
+	Fairness *FairnessOption `json:"fairness" validate:"omitempty"`
 }
 
 // Validate Option
@@ -71,9 +77,138 @@ type WatchDogOption struct {
 	WatchIntervalSec    uint `json:"watch_interval_sec" validate:"required"`
 	TaskRunTimeoutSec   uint `json:"task_run_timeout_sec" validate:"required"`
 	ShutdownWaitTimeSec uint `json:"shutdown_wait_time_sec" validate:"required"`
+	// TaskLogRetentionDays 任务执行日志保留天数，为0时默认保留30天
+	TaskLogRetentionDays uint `json:"task_log_retention_days"`
+	// FlowRetentionDays 已结束任务流及其任务的保留天数，为0时不清理
+	FlowRetentionDays uint `json:"flow_retention_days"`
+	// Notifier 任务流超过截止时间或SLA时间时的告警通知，可选
+	Notifier FlowNotifier `json:"-" validate:"-"`
 }
 
 // Validate WatchDogOption
 func (opt WatchDogOption) Validate() error {
 	return validator.Validate.Struct(opt)
 }
+
+// GetTaskLogRetentionDays return task log retention days, default is 30 days.
+func (opt WatchDogOption) GetTaskLogRetentionDays() uint {
+	if opt.TaskLogRetentionDays == 0 {
+		return defaultTaskLogRetentionDays
+	}
+
+	return opt.TaskLogRetentionDays
+}
+
+// FairnessOption 任务流公平派发配置。主节点派发和节点调度时，高优先级的任务流优先，同优先级下不同公平键
+// （如账号ID、业务ID）之间按照权重进行加权公平调度，并限制每个公平键同时处于调度和运行中的任务流数量。
+type FairnessOption struct {
+	// DefaultWeight 未单独配置的公平键的权重，为0时默认为1
+	DefaultWeight uint `json:"default_weight"`
+	// DefaultConcurrency 未单独配置的公平键同时处于调度和运行中的任务流数量上限，为0表示不限制，不作用于未设置公平键的任务流
+	DefaultConcurrency uint `json:"default_concurrency"`
+	// Keys 公平键单独配置，key 可以是完整的公平键（如 account:00000001），也可以是公平键类型前缀（如 account）
+	Keys map[string]FairnessKeyOption `json:"keys"`
+	// DispatchLimit 主节点每轮最多派发的任务流数量，为0时默认为100
+	DispatchLimit uint `json:"dispatch_limit"`
+}
+
+// FairnessKeyOption 公平键配置
+type FairnessKeyOption struct {
+	// Weight 公平键权重，权重越大，同优先级下被派发的任务流比例越高，为0时使用默认权重
+	Weight uint `json:"weight"`
+	// Concurrency 公平键同时处于调度和运行中的任务流数量上限，为0时使用默认上限
+	Concurrency uint `json:"concurrency"`
+}
+
+// Validate FairnessOption
+func (opt FairnessOption) Validate() error {
+	return validator.Validate.Struct(opt)
+}
+
+// lookupKey 查找公平键配置，优先完整公平键，其次公平键类型前缀（公平键中第一个':'之前的部分）
+func (opt *FairnessOption) lookupKey(key string) (FairnessKeyOption, bool) {
+	if opt == nil || len(opt.Keys) == 0 {
+		return FairnessKeyOption{}, false
+	}
+
+	if one, exist := opt.Keys[key]; exist {
+		return one, true
+	}
+
+	if idx := strings.Index(key, ":"); idx > 0 {
+		if one, exist := opt.Keys[key[:idx]]; exist {
+			return one, true
+		}
+	}
+
+	return FairnessKeyOption{}, false
+}
+
+// GetWeight 获取公平键权重
+func (opt *FairnessOption) GetWeight(key string) uint {
+	if one, exist := opt.lookupKey(key); exist && one.Weight > 0 {
+		return one.Weight
+	}
+
+	if opt != nil && opt.DefaultWeight > 0 {
+		return opt.DefaultWeight
+	}
+
+	return 1
+}
+
+// GetConcurrency 获取公平键并发上限，为0表示不限制。未设置公平键的任务流来自不同的业务场景，
+// 默认并发上限只作用于设置了公平键的任务流，避免所有未设置公平键的任务流共享同一个并发上限。
+func (opt *FairnessOption) GetConcurrency(key string) uint {
+	if one, exist := opt.lookupKey(key); exist && one.Concurrency > 0 {
+		return one.Concurrency
+	}
+
+	if opt != nil && len(key) != 0 {
+		return opt.DefaultConcurrency
+	}
+
+	return 0
+}
+
+// GetDispatchLimit 获取主节点每轮最多派发的任务流数量
+func (opt *FairnessOption) GetDispatchLimit() uint {
+	if opt == nil || opt.DispatchLimit == 0 {
+		return defaultDispatchLimit
+	}
+
+	return opt.DispatchLimit
+}
+
+// HasConcurrencyLimit 是否配置了公平键并发上限
+func (opt *FairnessOption) HasConcurrencyLimit() bool {
+	if opt == nil {
+		return false
+	}
+
+	if opt.DefaultConcurrency > 0 {
+		return true
+	}
+
+	for _, one := range opt.Keys {
+		if one.Concurrency > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// weightOnly 返回仅包含权重配置的公平派发配置，用于不需要限制并发的场景
+func (opt *FairnessOption) weightOnly() *FairnessOption {
+	if opt == nil {
+		return nil
+	}
+
+	keys := make(map[string]FairnessKeyOption, len(opt.Keys))
+	for key, one := range opt.Keys {
+		keys[key] = FairnessKeyOption{Weight: one.Weight}
+	}
+
+	return &FairnessOption{DefaultWeight: opt.DefaultWeight, Keys: keys}
+}
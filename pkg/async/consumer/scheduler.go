@@ -44,6 +44,7 @@ import (
 Scheduler （调度器）: TODO: 换为 捕获器、消费器，添加假死任务销毁逻辑
  1. 获取分配给当前节点的处于Scheduled状态的任务流，构建任务流树，将待执行任务推送到执行器执行。
  2. 分析执行器执行完的任务，判断任务流树状态，如果任务流处理完，更新状态，否则将子节点推送到执行器执行。
+ 3. 检查等待子任务流执行结束的任务，子任务流结束后更新任务状态，并继续调度。
 */
 type Scheduler interface {
 	compctrl.Closer
@@ -61,6 +62,7 @@ type Scheduler interface {
 type scheduler struct {
 	workerNumber     uint
 	watchIntervalSec time.Duration
+	fairness         *FairnessOption
 
 	taskTrees   sync.Map
 	workerQueue chan *Task
@@ -74,7 +76,8 @@ type scheduler struct {
 }
 
 // NewScheduler 实例化任务流调度器
-func NewScheduler(bd backend.Backend, exec Executor, ld leader.Leader, opt *SchedulerOption) Scheduler {
+func NewScheduler(bd backend.Backend, exec Executor, ld leader.Leader, opt *SchedulerOption,
+	fairness *FairnessOption) Scheduler {
 
 	return &scheduler{
 		closeCh:          make(chan struct{}),
@@ -82,6 +85,7 @@ func NewScheduler(bd backend.Backend, exec Executor, ld leader.Leader, opt *Sche
 		workerQueue:      make(chan *Task, 10),
 		workerNumber:     opt.WorkerNumber,
 		watchIntervalSec: time.Duration(opt.WatchIntervalSec) * time.Second,
+		fairness:         fairness,
 		backend:          bd,
 		executor:         exec,
 		leader:           ld,
@@ -94,9 +98,10 @@ func (sch *scheduler) Start() {
 	logs.Infof("scheduler start, worker number: %d, interval: %v", sch.workerNumber, sch.watchIntervalSec)
 
 	// 定期获取等待执行的任务流
-	sch.workerWg.Add(2)
+	sch.workerWg.Add(3)
 	go sch.scheduledFlowWatcher()
 	go sch.canceledFlowWatcher()
+	go sch.childFlowWatcher()
 
 	// 启动workerNumber个协程进行任务流解析
 	for i := 0; i < int(sch.workerNumber); i++ {
@@ -140,6 +145,8 @@ func (sch *scheduler) queryCurrNodeFlow(kt *kit.Kit, state enumor.FlowState, lim
 		Page: &core.BasePage{
 			Start: 0,
 			Limit: uint(limit),
+			Sort:  "priority",
+			Order: core.Descending,
 		},
 	}
 	result, err := sch.backend.ListFlow(kt, input)
@@ -185,7 +192,7 @@ func (sch *scheduler) handleCanceledFlow(kt *kit.Kit) error {
 		logs.Infof("canceling flow: %s", flow.ID)
 		// 清空任务树，阻止继续调度
 		sch.DeleteFlowTaskTree(flow.ID)
-		err = updateFlowToCancel(kt, sch.backend, flow.ID, cvt.PtrToVal(flow.Worker), enumor.FlowCancel)
+		err = updateFlowToCancel(kt, sch.backend, flow, enumor.FlowCancel)
 		if err != nil {
 			logs.Errorf("fail to update flow clear worker id, err: %v, flow id: %s rid: %s",
 				err, flow.ID, kt.Rid)
@@ -300,6 +307,11 @@ func (sch *scheduler) parseFlowAndPushTask(kt *kit.Kit, flow *Flow) error {
 		Root: root,
 	}
 
+	// 任务流在补偿过程中被重新调度，继续补偿
+	if flow.CompensateOnFailure && taskTree.Root.InCompensation() {
+		return sch.compensateFlow(kt, taskTree, true)
+	}
+
 	// 获取可执行的节点
 	executableTaskNodes := taskTree.Root.GetExecutableTasks()
 	if len(executableTaskNodes) == 0 {
@@ -312,6 +324,10 @@ func (sch *scheduler) parseFlowAndPushTask(kt *kit.Kit, flow *Flow) error {
 			}
 		}
 
+		if state == enumor.FlowFailed && flow.CompensateOnFailure {
+			return sch.compensateFlow(kt, taskTree, false)
+		}
+
 		if state == enumor.FlowFailed {
 			if err = updateFlowStateAndReason(kt, sch.backend, flow.ID, enumor.FlowRunning, state,
 				ErrSomeTaskExecFailed); err != nil {
@@ -321,6 +337,11 @@ func (sch *scheduler) parseFlowAndPushTask(kt *kit.Kit, flow *Flow) error {
 			}
 		}
 
+		// 存在等待子任务流执行结束的任务，存储任务流执行树，由childFlowWatcher继续调度
+		if len(taskTree.Root.GetAwaitingChildFlowTasks()) != 0 {
+			sch.taskTrees.Store(flow.ID, taskTree)
+		}
+
 		return nil
 	}
 
@@ -375,17 +396,23 @@ func updateFlowStateAndReason(kt *kit.Kit, bd backend.Backend, flowID string, so
 	return nil
 }
 
-// updateFlowToCancel 状态改为取消，清空 worker字段,
-func updateFlowToCancel(kt *kit.Kit, bd backend.Backend, flowId, oldWorkerID string, source enumor.FlowState) error {
+// updateFlowToCancel 状态改为取消，清空 worker字段，如果任务流已经记录了取消原因（如：超过截止时间），保留原有原因
+func updateFlowToCancel(kt *kit.Kit, bd backend.Backend, flow model.Flow, source enumor.FlowState) error {
 
+	reason := &tableasync.Reason{
+		Message:  "canceled from " + cvt.PtrToVal(flow.Worker),
+		PreState: string(source),
+	}
+	if flow.Reason != nil && len(flow.Reason.Message) != 0 {
+		reason = flow.Reason
+	}
+
+	flowId := flow.ID
 	info := backend.UpdateFlowInfo{
 		ID:     flowId,
 		Source: source,
 		Target: enumor.FlowCancel,
-		Reason: &tableasync.Reason{
-			Message:  "canceled from " + oldWorkerID,
-			PreState: string(source),
-		},
+		Reason: reason,
 		Worker: cvt.ValToPtr(""),
 	}
 
@@ -410,6 +437,8 @@ func (sch *scheduler) runScheduledFlow(kt *kit.Kit) error {
 		logs.Errorf("")
 		return err
 	}
+	// 并发上限已由主节点派发时控制，这里仅按照优先级和公平键权重确定执行顺序
+	dbFlows = selectFairFlows(dbFlows, nil, sch.fairness.weightOnly(), len(dbFlows))
 
 	flows := slice.Map(dbFlows, func(one model.Flow) *Flow {
 		// Note: first sub kit, scheduler.watcher -> flow
@@ -461,6 +490,11 @@ func (sch *scheduler) executeNext(kt *kit.Kit, task *Task) error {
 		return fmt.Errorf("flow: %s not found", task.FlowID)
 	}
 
+	// 任务流补偿中，继续补偿其他任务
+	if tree.Flow.State == enumor.FlowCompensating {
+		return sch.executeNextCompensation(kt, tree, task)
+	}
+
 	// 获取下次执行的任务
 	executableIds := tree.Root.GetNextExecutableTaskNodes(task)
 	if len(executableIds) == 0 {
@@ -475,6 +509,10 @@ func (sch *scheduler) executeNext(kt *kit.Kit, task *Task) error {
 
 			sch.DeleteFlowTaskTree(task.FlowID)
 		case enumor.FlowFailed:
+			if tree.Flow.CompensateOnFailure {
+				return sch.compensateFlow(kt, tree, false)
+			}
+
 			if err := updateFlowStateAndReason(kt, sch.backend, task.FlowID, enumor.FlowRunning, state,
 				ErrSomeTaskExecFailed); err != nil {
 
@@ -44,6 +44,9 @@ type Task struct {
 	ExecuteKit run.ExecuteKit `json:"-"`
 	Patch      func(taskKit *kit.Kit, task *model.Task) error
 	Flow       *Flow
+
+	// logger 持久化的任务执行日志，仅在执行器中执行的任务设置
+	logger *taskLogger
 }
 
 // ValidateBeforeExec task validate before execute.
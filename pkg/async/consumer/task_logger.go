@@ -0,0 +1,201 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"fmt"
+	"sync"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/action/run"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+var _ run.TaskLogger = new(taskLogger)
+
+// taskLogger 持久化的任务执行日志。日志先缓存在内存中，每次执行结束或者缓存数量达到上限时批量持久化，
+// 同时输出到节点日志。持久化失败只记录节点日志，不影响任务执行。
+type taskLogger struct {
+	// kt 持久化日志使用的kit，不能使用任务执行的kit，避免任务超时后日志无法持久化
+	kt     *kit.Kit
+	rid    string
+	bd     backend.Backend
+	flowID string
+	taskID string
+
+	lock          sync.Mutex
+	attempt       uint
+	attemptLoaded bool
+	buffer        []model.TaskLog
+}
+
+func newTaskLogger(kt *kit.Kit, bd backend.Backend, task *Task) *taskLogger {
+	return &taskLogger{
+		kt:     kt,
+		rid:    task.Kit.Rid,
+		bd:     bd,
+		flowID: task.FlowID,
+		taskID: task.ID,
+		buffer: make([]model.TaskLog, 0),
+	}
+}
+
+// Infof output info level task log.
+func (l *taskLogger) Infof(format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+	logs.InfoDepthf(1, "task %s: %s, rid: %s", l.taskID, msg, l.rid)
+	l.append(enumor.TaskLogKindLog, enumor.TaskLogInfo, msg)
+}
+
+// Warnf output warn level task log.
+func (l *taskLogger) Warnf(format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+	logs.Warnf("task %s: %s, rid: %s", l.taskID, msg, l.rid)
+	l.append(enumor.TaskLogKindLog, enumor.TaskLogWarn, msg)
+}
+
+// Errorf output error level task log.
+func (l *taskLogger) Errorf(format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+	logs.ErrorDepthf(1, "task %s: %s, rid: %s", l.taskID, msg, l.rid)
+	l.append(enumor.TaskLogKindLog, enumor.TaskLogError, msg)
+}
+
+func (l *taskLogger) append(kind enumor.TaskLogKind, level enumor.TaskLogLevel, msg string) {
+	l.lock.Lock()
+	l.buffer = append(l.buffer, model.TaskLog{
+		FlowID:  l.flowID,
+		TaskID:  l.taskID,
+		Attempt: l.attempt,
+		Kind:    kind,
+		Level:   level,
+		Message: model.TruncateTaskLogMessage(msg),
+	})
+	full := len(l.buffer) >= taskLogFlushSize
+	l.lock.Unlock()
+
+	if full {
+		l.Flush()
+	}
+}
+
+// StartAttempt 开始一次新的执行，执行次数在该任务已持久化的最大执行次数基础上递增，保证任务被手动重试或者
+// 在其他节点重新执行时执行次数也是连续的。
+func (l *taskLogger) StartAttempt(state enumor.TaskState) {
+	if l == nil {
+		return
+	}
+
+	l.lock.Lock()
+	if !l.attemptLoaded {
+		l.attempt = l.lastAttempt()
+		l.attemptLoaded = true
+	}
+	l.attempt++
+	attempt := l.attempt
+	l.lock.Unlock()
+
+	l.Infof("attempt %d started, state: %s", attempt, state)
+}
+
+// EndAttempt 结束本次执行，记录本次执行结果并持久化缓存的日志
+func (l *taskLogger) EndAttempt(err error) {
+	if l == nil {
+		return
+	}
+
+	level, msg := enumor.TaskLogInfo, "success"
+	if err != nil {
+		level, msg = enumor.TaskLogError, err.Error()
+		if errf.IsContextCanceled(err) {
+			level = enumor.TaskLogWarn
+		}
+	}
+
+	l.append(enumor.TaskLogKindAttempt, level, msg)
+	l.Flush()
+}
+
+// Flush 持久化缓存的日志
+func (l *taskLogger) Flush() {
+	if l == nil {
+		return
+	}
+
+	l.lock.Lock()
+	buffer := l.buffer
+	l.buffer = make([]model.TaskLog, 0)
+	l.lock.Unlock()
+
+	if len(buffer) == 0 {
+		return
+	}
+
+	if err := l.bd.BatchCreateTaskLog(l.kt, buffer); err != nil {
+		logs.Errorf("%s: persist task log failed, err: %v, task: %s, count: %d, rid: %s",
+			constant.AsyncTaskWarnSign, err, l.taskID, len(buffer), l.rid)
+	}
+}
+
+// lastAttempt 查询该任务已持久化的最大执行次数，查询失败时从0开始计数
+func (l *taskLogger) lastAttempt() uint {
+	input := &backend.ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleEqual("task_id", l.taskID),
+			tools.RuleEqual("kind", enumor.TaskLogKindAttempt),
+		),
+		Page: &core.BasePage{
+			Start: 0,
+			Limit: 1,
+			Sort:  "attempt",
+			Order: core.Descending,
+		},
+	}
+	result, err := l.bd.ListTaskLog(l.kt, input)
+	if err != nil {
+		logs.Errorf("list task last attempt log failed, err: %v, task: %s, rid: %s", err, l.taskID, l.rid)
+		return 0
+	}
+
+	if len(result) == 0 {
+		return 0
+	}
+
+	return result[0].Attempt
+}
@@ -0,0 +1,152 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+)
+
+// taskLogBackend 只实现任务执行日志接口的内存后端
+type taskLogBackend struct {
+	backend.Backend
+
+	lock        sync.Mutex
+	logs        []model.TaskLog
+	lastAttempt uint
+	flushCount  int
+	createErr   error
+}
+
+func (bd *taskLogBackend) BatchCreateTaskLog(_ *kit.Kit, taskLogs []model.TaskLog) error {
+	bd.lock.Lock()
+	defer bd.lock.Unlock()
+
+	bd.flushCount++
+	if bd.createErr != nil {
+		return bd.createErr
+	}
+	bd.logs = append(bd.logs, taskLogs...)
+	return nil
+}
+
+func (bd *taskLogBackend) ListTaskLog(_ *kit.Kit, _ *backend.ListInput) ([]model.TaskLog, error) {
+	if bd.lastAttempt == 0 {
+		return make([]model.TaskLog, 0), nil
+	}
+
+	return []model.TaskLog{{Kind: enumor.TaskLogKindAttempt, Attempt: bd.lastAttempt}}, nil
+}
+
+func newTestTaskLogger(bd backend.Backend) *taskLogger {
+	task := &Task{Task: model.Task{ID: "task1", FlowID: "flow1"}, Kit: kit.New()}
+	return newTaskLogger(kit.New(), bd, task)
+}
+
+func TestTaskLoggerAttempt(t *testing.T) {
+	// 任务已经执行过两次，本次执行从第三次开始计数
+	bd := &taskLogBackend{lastAttempt: 2}
+	logger := newTestTaskLogger(bd)
+
+	logger.StartAttempt(enumor.TaskPending)
+	logger.Infof("create %d cvm", 2)
+	if len(bd.logs) != 0 {
+		t.Fatalf("task log should be buffered before attempt end, but got %d persisted", len(bd.logs))
+	}
+	logger.EndAttempt(errors.New("quota exceeded"))
+
+	logger.StartAttempt(enumor.TaskRollback)
+	logger.EndAttempt(nil)
+
+	expects := []model.TaskLog{
+		{Attempt: 3, Kind: enumor.TaskLogKindLog, Level: enumor.TaskLogInfo,
+			Message: "attempt 3 started, state: pending"},
+		{Attempt: 3, Kind: enumor.TaskLogKindLog, Level: enumor.TaskLogInfo, Message: "create 2 cvm"},
+		{Attempt: 3, Kind: enumor.TaskLogKindAttempt, Level: enumor.TaskLogError, Message: "quota exceeded"},
+		{Attempt: 4, Kind: enumor.TaskLogKindLog, Level: enumor.TaskLogInfo,
+			Message: "attempt 4 started, state: rollback"},
+		{Attempt: 4, Kind: enumor.TaskLogKindAttempt, Level: enumor.TaskLogInfo, Message: "success"},
+	}
+	if len(bd.logs) != len(expects) {
+		t.Fatalf("expect %d task logs, but got %d: %+v", len(expects), len(bd.logs), bd.logs)
+	}
+	for i, expect := range expects {
+		got := bd.logs[i]
+		if got.FlowID != "flow1" || got.TaskID != "task1" || got.Attempt != expect.Attempt ||
+			got.Kind != expect.Kind || got.Level != expect.Level || got.Message != expect.Message {
+			t.Errorf("task log %d expect %+v, but got %+v", i, expect, got)
+		}
+	}
+}
+
+func TestTaskLoggerCanceledAttempt(t *testing.T) {
+	bd := new(taskLogBackend)
+	logger := newTestTaskLogger(bd)
+
+	logger.StartAttempt(enumor.TaskPending)
+	logger.EndAttempt(context.Canceled)
+
+	last := bd.logs[len(bd.logs)-1]
+	if last.Attempt != 1 || last.Kind != enumor.TaskLogKindAttempt || last.Level != enumor.TaskLogWarn {
+		t.Errorf("canceled attempt should be recorded as warn, got: %+v", last)
+	}
+}
+
+func TestTaskLoggerFlushWhenFull(t *testing.T) {
+	bd := new(taskLogBackend)
+	logger := newTestTaskLogger(bd)
+
+	for i := 0; i < taskLogFlushSize+1; i++ {
+		logger.Warnf("log %d", i)
+	}
+	if bd.flushCount != 1 || len(bd.logs) != taskLogFlushSize {
+		t.Fatalf("task log should be flushed when buffer is full, flush count: %d, persisted: %d", bd.flushCount,
+			len(bd.logs))
+	}
+
+	logger.Flush()
+	if len(bd.logs) != taskLogFlushSize+1 {
+		t.Errorf("remaining task log should be flushed, persisted: %d", len(bd.logs))
+	}
+}
+
+func TestTaskLoggerPersistFailed(t *testing.T) {
+	bd := &taskLogBackend{createErr: errors.New("backend unavailable")}
+	logger := newTestTaskLogger(bd)
+
+	// 持久化失败不影响任务执行，缓存的日志被丢弃，不会重复持久化
+	logger.Errorf("something wrong")
+	logger.Flush()
+	logger.Flush()
+	if bd.flushCount != 1 {
+		t.Errorf("failed task log should not be flushed again, flush count: %d", bd.flushCount)
+	}
+
+	var nilLogger *taskLogger
+	nilLogger.Infof("nil logger should be ignored")
+	nilLogger.EndAttempt(nil)
+}
@@ -42,6 +42,9 @@ type TaskTree struct {
 type TaskNode struct {
 	TaskID string
 	State  enumor.TaskState
+	Kind   enumor.TaskKind
+	// Compensable 是否设置了补偿动作
+	Compensable bool
 
 	children []*TaskNode
 	parents  []*TaskNode
@@ -50,8 +53,10 @@ type TaskNode struct {
 // NewTaskNode new task node
 func NewTaskNode(task *Task) *TaskNode {
 	return &TaskNode{
-		TaskID: task.ID,
-		State:  task.State,
+		TaskID:      task.ID,
+		State:       task.State,
+		Kind:        task.GetKind(),
+		Compensable: len(task.CompensateAction) != 0,
 	}
 }
 
@@ -75,9 +80,14 @@ func (t *TaskNode) GetParents() []*TaskNode {
 	return t.parents
 }
 
-// CanExecuteChild can execute child
+// CanExecuteChild can execute child, 跳过的节点视为执行完成
 func (t *TaskNode) CanExecuteChild() bool {
-	return t.State == enumor.TaskSuccess
+	return t.State == enumor.TaskSuccess || t.State == enumor.TaskSkipped
+}
+
+// IsAwaitingChildFlow 是否是等待子任务流执行结束的节点
+func (t *TaskNode) IsAwaitingChildFlow() bool {
+	return t.Kind == enumor.TaskKindChildFlow && t.State == enumor.TaskRunning
 }
 
 // CanBeExecuted check whether task could be executed
@@ -124,8 +134,8 @@ func (t *TaskNode) ComputeState() (state enumor.FlowState) {
 		case enumor.TaskFailed:
 			state = enumor.FlowFailed
 			return false
-		// 如果当前节点运行成功，继续遍历当前节点子节点。
-		case enumor.TaskSuccess:
+		// 如果当前节点运行成功或被跳过，继续遍历当前节点子节点。
+		case enumor.TaskSuccess, enumor.TaskSkipped:
 			state = enumor.FlowSuccess
 			return true
 
@@ -151,9 +161,13 @@ func (t *TaskNode) GetExecutableTasks() (executables []string) {
 	return
 }
 
-// GetExecStateTasks 获取执行状态的节点
+// GetExecStateTasks 获取执行状态的节点，等待子任务流执行结束的节点不在执行器中执行，不包含在内
 func (t *TaskNode) GetExecStateTasks() (ids []string) {
 	walkNode(t, func(node *TaskNode) bool {
+		if node.IsAwaitingChildFlow() {
+			return true
+		}
+
 		if node.State == enumor.TaskRunning || node.State == enumor.TaskRollback {
 			ids = append(ids, node.TaskID)
 		}
@@ -163,11 +177,28 @@ func (t *TaskNode) GetExecStateTasks() (ids []string) {
 	return
 }
 
+// GetAwaitingChildFlowTasks 获取等待子任务流执行结束的节点
+func (t *TaskNode) GetAwaitingChildFlowTasks() (ids []string) {
+	walkNode(t, func(node *TaskNode) bool {
+		if node.IsAwaitingChildFlow() {
+			ids = append(ids, node.TaskID)
+		}
+		return true
+	})
+
+	return
+}
+
 // GetNextExecutableTaskNodes get next executable task nodes
 func (t *TaskNode) GetNextExecutableTaskNodes(completedOrRetryTask *Task) (executable []string) {
 	walkNode(t, func(node *TaskNode) (proceed bool) {
 		if node.TaskID == completedOrRetryTask.ID {
 			node.State = completedOrRetryTask.State
+			// 等待子任务流执行结束的节点，由调度器监听子任务流状态后继续调度
+			if node.IsAwaitingChildFlow() {
+				return false
+			}
+
 			// running 和 rollback 都要放回去执行
 			if node.State == enumor.TaskRunning || node.State == enumor.TaskRollback {
 				executable = append(executable, node.TaskID)
@@ -192,6 +223,105 @@ func (t *TaskNode) GetNextExecutableTaskNodes(completedOrRetryTask *Task) (execu
 	return
 }
 
+// InCompensation 是否存在处于补偿流程中的节点
+func (t *TaskNode) InCompensation() (in bool) {
+	walkAllNodes(t, func(node *TaskNode) {
+		switch node.State {
+		case enumor.TaskCompensating, enumor.TaskCompensated, enumor.TaskCompensateFailed:
+			in = true
+		}
+	})
+
+	return
+}
+
+// needCompensate 执行成功且设置了补偿动作的节点需要补偿
+func (t *TaskNode) needCompensate() bool {
+	return t.Compensable && t.State == enumor.TaskSuccess
+}
+
+// blockCompensation 节点的补偿是否阻塞其前置节点的补偿，需要补偿、补偿中、补偿失败的节点都会阻塞前置节点的补偿
+func (t *TaskNode) blockCompensation() bool {
+	return t.needCompensate() || t.State == enumor.TaskCompensating || t.State == enumor.TaskCompensateFailed
+}
+
+// CanBeCompensated 节点需要补偿，且所有后继节点都已经补偿完成或者不需要补偿，即按照依赖关系的逆序进行补偿。
+// 后继节点补偿失败时不再补偿当前节点，避免后继节点的操作未撤销时撤销其依赖的操作。
+func (t *TaskNode) CanBeCompensated() bool {
+	if !t.needCompensate() {
+		return false
+	}
+
+	blocked := false
+	for _, child := range t.children {
+		walkAllNodes(child, func(node *TaskNode) {
+			if node.blockCompensation() {
+				blocked = true
+			}
+		})
+	}
+
+	return !blocked
+}
+
+// GetCompensableTasks 获取可以执行补偿的节点
+func (t *TaskNode) GetCompensableTasks() (ids []string) {
+	walkAllNodes(t, func(node *TaskNode) {
+		if node.CanBeCompensated() {
+			ids = append(ids, node.TaskID)
+		}
+	})
+
+	return
+}
+
+// GetCompensatingTasks 获取补偿中的节点
+func (t *TaskNode) GetCompensatingTasks() (ids []string) {
+	walkAllNodes(t, func(node *TaskNode) {
+		if node.State == enumor.TaskCompensating {
+			ids = append(ids, node.TaskID)
+		}
+	})
+
+	return
+}
+
+// HasCompensateFailed 是否存在补偿失败的节点
+func (t *TaskNode) HasCompensateFailed() (failed bool) {
+	walkAllNodes(t, func(node *TaskNode) {
+		if node.State == enumor.TaskCompensateFailed {
+			failed = true
+		}
+	})
+
+	return
+}
+
+// SetTaskState 设置指定节点的状态
+func (t *TaskNode) SetTaskState(ids []string, state enumor.TaskState) {
+	idMap := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		idMap[id] = struct{}{}
+	}
+
+	walkAllNodes(t, func(node *TaskNode) {
+		if _, ok := idMap[node.TaskID]; ok {
+			node.State = state
+		}
+	})
+}
+
+// FindNode 查找指定ID的节点，不存在时返回nil
+func (t *TaskNode) FindNode(id string) (found *TaskNode) {
+	walkAllNodes(t, func(node *TaskNode) {
+		if node.TaskID == id {
+			found = node
+		}
+	})
+
+	return
+}
+
 // HasCycle check has cycle
 func (t *TaskNode) HasCycle() (cycleStart *TaskNode) {
 	visited, incomplete := map[string]struct{}{}, map[string]*TaskNode{}
@@ -301,6 +431,25 @@ func walkNode(root *TaskNode, walkFunc func(node *TaskNode) (proceed bool)) {
 	dfsWalk(root, walkFunc)
 }
 
+// walkAllNodes 遍历节点及其所有后继节点，每个节点只遍历一次，不受节点状态影响
+func walkAllNodes(root *TaskNode, walkFunc func(node *TaskNode)) {
+	visited := make(map[string]struct{})
+	queue := []*TaskNode{root}
+	for len(queue) != 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[cur.TaskID]; ok {
+			continue
+		}
+		visited[cur.TaskID] = struct{}{}
+
+		if cur.TaskID != VirtualTaskRootID {
+			walkFunc(cur)
+		}
+		queue = append(queue, cur.children...)
+	}
+}
+
 // dfsWalk 从某个节点进行深度优先遍历并依次遍历它的子节点
 // Note: walkFunc
 func dfsWalk(root *TaskNode, walkFunc func(node *TaskNode) (proceed bool)) (stop bool) {
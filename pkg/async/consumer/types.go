@@ -20,6 +20,8 @@
 package consumer
 
 import (
+	"time"
+
 	"hcm/pkg/api/core"
 	"hcm/pkg/async/backend/model"
 	"hcm/pkg/kit"
@@ -44,12 +46,39 @@ const (
 	ErrTaskNodeShutdown = "task node shutdown"
 	// ErrSomeTaskExecFailed 部分任务执行失败
 	ErrSomeTaskExecFailed = "some tasks failed to be executed"
+	// ErrSomeTaskCompensated 部分任务执行失败，执行成功的任务已经完成补偿
+	ErrSomeTaskCompensated = "some tasks failed to be executed, succeeded tasks have been compensated"
+	// ErrSomeTaskCompensateFailed 部分任务执行失败，且部分任务补偿失败
+	ErrSomeTaskCompensateFailed = "some tasks failed to be executed, and some tasks failed to be compensated"
+	// ErrFlowDeadlineExceeded 任务流超过截止时间
+	ErrFlowDeadlineExceeded = "flow deadline exceeded"
 
 	//  listScheduledFlowLimit 每次调度器查询分配给当前节点的任务流数量
 	listScheduledFlowLimit = 10
 
 	// listExpiredTasksLimit 每次WatchDog查询超时任务的数量
 	listExpiredTasksLimit = 100
+
+	// defaultDispatchLimit 主节点每轮默认最多派发的任务流数量
+	defaultDispatchLimit = 100
+	// maxListPendingRounds 主节点每轮查询待派发任务流的最大次数
+	maxListPendingRounds = 5
+
+	// listOverdueFlowsLimit 每次WatchDog查询超过截止时间或SLA时间的任务流数量
+	listOverdueFlowsLimit = 100
+
+	// taskLogFlushSize 任务执行日志缓存数量达到该值时批量持久化
+	taskLogFlushSize = 50
+	// deleteExpiredTaskLogLimit 每次WatchDog删除过期任务执行日志的数量
+	deleteExpiredTaskLogLimit = 1000
+	// defaultTaskLogRetentionDays 任务执行日志默认保留天数
+	defaultTaskLogRetentionDays = 30
+	// taskLogCleanInterval WatchDog清理过期任务执行日志的周期
+	taskLogCleanInterval = 10 * time.Minute
+	// deleteExpiredFlowLimit 每次WatchDog删除过期任务流的数量
+	deleteExpiredFlowLimit = 100
+	// flowCleanInterval WatchDog清理过期任务流的周期
+	flowCleanInterval = 10 * time.Minute
 )
 
 // Flow 消费所需的异步任务流。
@@ -32,6 +32,8 @@ import (
 	"hcm/pkg/async/consumer/leader"
 	"hcm/pkg/criteria/constant"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
@@ -45,6 +47,7 @@ WatchDog （看门狗）:
  1. 处理超时任务
  2. 处理处于Scheduled状态，但执行节点已经挂掉的任务流
  3. 处理处于Running状态，但执行节点正在Shutdown或者已经挂掉的任务流
+ 4. 处理超过截止时间的任务流，对超过SLA时间的任务流进行告警
 */
 type WatchDog interface {
 	compctrl.Closer
@@ -54,8 +57,10 @@ type WatchDog interface {
 
 // watchDog 任务流、任务纠正策略
 type watchDog struct {
-	bd backend.Backend
-	ld leader.Leader
+	bd       backend.Backend
+	ld       leader.Leader
+	mc       *metric
+	notifier FlowNotifier
 
 	taskTimeoutSec      time.Duration
 	shutdownWaitTimeSec time.Duration
@@ -65,20 +70,37 @@ type watchDog struct {
 	closeCh chan struct{}
 
 	runningFlowMap map[string]time.Time
+	// slaBreachedFlowMap 已经进行过SLA告警的任务流，避免重复告警
+	slaBreachedFlowMap map[string]struct{}
+
+	// taskLogRetention 任务执行日志保留时长
+	taskLogRetention time.Duration
+	// taskLogCleanedAt 上一次清理过期任务执行日志的时间
+	taskLogCleanedAt time.Time
+
+	// flowRetention 已结束任务流保留时长，为0时不清理
+	flowRetention time.Duration
+	// flowCleanedAt 上一次清理过期任务流的时间
+	flowCleanedAt time.Time
 }
 
 // NewWatchDog 创建一个watchdog
-func NewWatchDog(bd backend.Backend, ld leader.Leader, opt *WatchDogOption) WatchDog {
+func NewWatchDog(bd backend.Backend, ld leader.Leader, mc *metric, opt *WatchDogOption) WatchDog {
 
 	return &watchDog{
 		bd:                  bd,
 		ld:                  ld,
+		mc:                  mc,
+		notifier:            opt.Notifier,
 		taskTimeoutSec:      time.Duration(opt.TaskRunTimeoutSec) * time.Second,
 		shutdownWaitTimeSec: time.Duration(opt.ShutdownWaitTimeSec) * time.Second,
 		watchIntervalSec:    time.Duration(opt.WatchIntervalSec) * time.Second,
 		wg:                  sync.WaitGroup{},
 		closeCh:             make(chan struct{}),
 		runningFlowMap:      make(map[string]time.Time),
+		slaBreachedFlowMap:  make(map[string]struct{}),
+		taskLogRetention:    time.Duration(opt.GetTaskLogRetentionDays()) * 24 * time.Hour,
+		flowRetention:       time.Duration(opt.FlowRetentionDays) * 24 * time.Hour,
 	}
 }
 
@@ -90,6 +112,14 @@ func (wd *watchDog) Start() {
 	go wd.watchWrapper(wd.handleScheduledNotExistWorkerFlow)
 	wd.wg.Add(1)
 	go wd.watchWrapper(wd.handleRunningNotExistWorkerFlow)
+	wd.wg.Add(1)
+	go wd.watchWrapper(wd.handleDeadlineExceededFlows)
+	wd.wg.Add(1)
+	go wd.watchWrapper(wd.handleSLABreachedFlows)
+	wd.wg.Add(1)
+	go wd.watchWrapper(wd.handleExpiredTaskLogs)
+	wd.wg.Add(1)
+	go wd.watchWrapper(wd.handleExpiredFlows)
 }
 
 // 定期处理异常任务流或任务
@@ -116,6 +146,8 @@ func (wd *watchDog) watchWrapper(do func(kt *kit.Kit) error) {
 func (wd *watchDog) Close() {
 	close(wd.closeCh)
 	wd.wg.Wait()
+	// 切换为从节点后不再统计，避免上报过期数据
+	wd.mc.flowSLABreachedGauge.Reset()
 }
 
 // handleExpiredTasks 将超时任务和所属的任务流，设置为失败状态，失败原因：ErrTaskExecTimeout
@@ -135,6 +167,12 @@ func (wd *watchDog) handleExpiredTasks(kt *kit.Kit) error {
 					Op:    filter.LessThan.Factory(),
 					Value: times.ConvStdTimeFormat(times.ConvStdTimeNow().Add(-wd.taskTimeoutSec)),
 				},
+				// 子任务流任务处于running状态时在等待子任务流执行结束，不受任务执行超时时间限制
+				&filter.AtomRule{
+					Field: "kind",
+					Op:    filter.NotEqual.Factory(),
+					Value: enumor.TaskKindChildFlow,
+				},
 			},
 		},
 		Page: &core.BasePage{
@@ -166,17 +204,7 @@ func (wd *watchDog) handleExpiredTasks(kt *kit.Kit) error {
 			return err
 		}
 
-		flows := []model.Flow{
-			{
-				ID:    one.FlowID,
-				State: enumor.FlowFailed,
-				Reason: &tableasync.Reason{
-					Message: ErrTaskExecTimeout,
-				},
-			},
-		}
-		if err = wd.bd.BatchUpdateFlow(kt, flows); err != nil {
-			logs.Errorf("update flow to failed state failed, err: %v, rid: %s", err, kt.Rid)
+		if err = wd.failTimeoutFlow(kt, one.FlowID); err != nil {
 			return err
 		}
 	}
@@ -186,6 +214,33 @@ func (wd *watchDog) handleExpiredTasks(kt *kit.Kit) error {
 	return nil
 }
 
+// failTimeoutFlow 任务超时后将所属任务流置为失败状态，开启补偿模式的任务流置为Pending状态，重新调度后进行补偿
+func (wd *watchDog) failTimeoutFlow(kt *kit.Kit, flowID string) error {
+	flowMap, err := listFlowByIDs(kt, wd.bd, []string{flowID})
+	if err != nil {
+		logs.Errorf("list timeout task's flow failed, err: %v, flow id: %s, rid: %s", err, flowID, kt.Rid)
+		return err
+	}
+
+	md := model.Flow{
+		ID:    flowID,
+		State: enumor.FlowFailed,
+		Reason: &tableasync.Reason{
+			Message: ErrTaskExecTimeout,
+		},
+	}
+	if flow, exist := flowMap[flowID]; exist && flow.CompensateOnFailure {
+		md.State, md.Worker = enumor.FlowPending, converter.ValToPtr("")
+	}
+
+	if err = wd.bd.BatchUpdateFlow(kt, []model.Flow{md}); err != nil {
+		logs.Errorf("update flow to %s state failed, err: %v, rid: %s", md.State, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
 func (wd *watchDog) updateTimeoutTask(kt *kit.Kit, id string) error {
 	task := &model.Task{
 		ID:    id,
@@ -270,7 +325,7 @@ func (wd *watchDog) queryNotExistNodesFlowByState(kt *kit.Kit, state enumor.Flow
 	return flows, nil
 }
 
-// handleRunningNotExistWorkerFlow 处理处于Running状态且处理Worker已经下线的Flow。
+// handleRunningNotExistWorkerFlow 处理处于Running、Compensating状态且处理Worker已经下线的Flow。
 func (wd *watchDog) handleRunningNotExistWorkerFlow(kt *kit.Kit) error {
 
 	flows, err := wd.queryNotExistNodesFlowByState(kt, enumor.FlowRunning)
@@ -278,6 +333,12 @@ func (wd *watchDog) handleRunningNotExistWorkerFlow(kt *kit.Kit) error {
 		return err
 	}
 
+	compensatingFlows, err := wd.queryNotExistNodesFlowByState(kt, enumor.FlowCompensating)
+	if err != nil {
+		return err
+	}
+	flows = append(flows, compensatingFlows...)
+
 	if len(flows) == 0 {
 		logs.V(3).Infof("handleRunningNotExistWorkerFlow not found flow, skip, rid: %s", kt.Rid)
 		return nil
@@ -315,6 +376,11 @@ func (wd *watchDog) handleRunningNotExistWorkerFlow(kt *kit.Kit) error {
 }
 
 func (wd *watchDog) handleRunningFlow(kt *kit.Kit, flow model.Flow) error {
+	// 补偿中的任务流置于Pending状态，重新调度后继续补偿
+	if flow.State == enumor.FlowCompensating {
+		return wd.resetFlowToPending(kt, flow.ID)
+	}
+
 	// 根据任务流ID获取对应的任务集合
 	taskModels, err := listTaskByFlowID(kt, wd.bd, flow.ID)
 	if err != nil {
@@ -327,9 +393,9 @@ func (wd *watchDog) handleRunningFlow(kt *kit.Kit, flow model.Flow) error {
 		return err
 	}
 
-	// 如果树已经处于结束状态，则直接更新
+	// 如果树已经处于结束状态，则直接更新，开启补偿模式的失败任务流需要重新调度后进行补偿
 	state := root.ComputeState()
-	if state == enumor.FlowSuccess || state == enumor.FlowFailed {
+	if state == enumor.FlowSuccess || (state == enumor.FlowFailed && !flow.CompensateOnFailure) {
 		if err = updateFlowState(kt, wd.bd, flow.ID, enumor.FlowRunning, state); err != nil {
 			logs.Errorf("update flow state to %s failed, err: %v, rid: %s", state, err, kt.Rid)
 			return err
@@ -341,19 +407,7 @@ func (wd *watchDog) handleRunningFlow(kt *kit.Kit, flow model.Flow) error {
 	ids := root.GetExecStateTasks()
 	// 如果没有处于执行中的节点，将Flow置于Pending状态，等待重新被调度
 	if len(ids) == 0 {
-		mds := []model.Flow{
-			{
-				ID:     flow.ID,
-				State:  enumor.FlowPending,
-				Worker: converter.ValToPtr(""),
-			},
-		}
-		if err = wd.bd.BatchUpdateFlow(kt, mds); err != nil {
-			logs.Errorf("update flows failed, err: %v, rid: %s", err, kt.Rid)
-			return err
-		}
-
-		return nil
+		return wd.resetFlowToPending(kt, flow.ID)
 	}
 
 	// 否则，找出所有处于执行状态的节点，判断它的执行节点是否已经退出，如果退出将Task回滚或者置于失败状态。
@@ -364,6 +418,23 @@ func (wd *watchDog) handleRunningFlow(kt *kit.Kit, flow model.Flow) error {
 	return nil
 }
 
+// resetFlowToPending 将Flow置于Pending状态，并清空执行节点，等待重新被调度
+func (wd *watchDog) resetFlowToPending(kt *kit.Kit, flowID string) error {
+	mds := []model.Flow{
+		{
+			ID:     flowID,
+			State:  enumor.FlowPending,
+			Worker: converter.ValToPtr(""),
+		},
+	}
+	if err := wd.bd.BatchUpdateFlow(kt, mds); err != nil {
+		logs.Errorf("update flows failed, err: %v, rid: %s", err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
 // handleRunningTasks 找出所有处于执行状态的节点，判断它的执行节点是否已经退出，如果退出将Task回滚或者置于失败状态。
 func (wd *watchDog) handleRunningTasks(kt *kit.Kit, flow model.Flow, ids []string) error {
 	tasks, err := listTaskByIDs(kt, wd.bd, ids)
@@ -398,7 +469,7 @@ func (wd *watchDog) handleRunningTasks(kt *kit.Kit, flow model.Flow, ids []strin
 			}
 		}
 
-		taskExecKit := run.NewExecuteContext(task.Kit, flow.ShareData)
+		taskExecKit := run.NewExecuteContext(task.Kit, flow.ShareData, nil)
 		task.InitDep(taskExecKit, func(taskKit *kit.Kit, task *model.Task) error {
 			return wd.bd.UpdateTask(kt, task)
 		}, &Flow{Flow: flow})
@@ -444,3 +515,217 @@ func (wd *watchDog) checkIsExpireTask(kt *kit.Kit, task model.Task) bool {
 
 	return true
 }
+
+// handleDeadlineExceededFlows 处理超过截止时间仍未结束的任务流，失败原因：ErrFlowDeadlineExceeded
+//  1. Pending、Scheduled状态的任务流还未开始执行，直接置为失败状态，并清空执行节点
+//  2. Running状态的任务流存在执行中的任务，置为取消状态，由执行节点的canceledFlowWatcher终止执行中的任务
+func (wd *watchDog) handleDeadlineExceededFlows(kt *kit.Kit) error {
+
+	page := &core.BasePage{
+		Start: 0,
+		Limit: listOverdueFlowsLimit,
+	}
+	flows, err := wd.listOverdueFlows(kt, "deadline_at", page)
+	if err != nil {
+		return err
+	}
+
+	if len(flows) == 0 {
+		logs.V(3).Infof("handleDeadlineExceededFlows not found flow, skip, rid: %s", kt.Rid)
+		return nil
+	}
+
+	ids := make([]string, 0, len(flows))
+	for _, flow := range flows {
+		info := backend.UpdateFlowInfo{
+			ID:     flow.ID,
+			Source: flow.State,
+			Target: enumor.FlowFailed,
+			Reason: &tableasync.Reason{
+				Message:  ErrFlowDeadlineExceeded,
+				PreState: string(flow.State),
+			},
+			Worker: converter.ValToPtr(""),
+		}
+		if flow.State == enumor.FlowRunning {
+			info.Target = enumor.FlowCancel
+			info.Worker = nil
+		}
+
+		if err = wd.bd.BatchUpdateFlowStateByCAS(kt, []backend.UpdateFlowInfo{info}); err != nil {
+			// 任务流状态已经发生变化，等待下次检查
+			if errf.Error(err).Code == errf.RecordNotUpdate {
+				logs.V(3).Infof("flow %s state changed, skip handle deadline exceeded, rid: %s", flow.ID, kt.Rid)
+				continue
+			}
+
+			logs.Errorf("update deadline exceeded flow state failed, err: %v, id: %s, rid: %s", err, flow.ID,
+				kt.Rid)
+			return err
+		}
+
+		ids = append(ids, flow.ID)
+		wd.mc.flowDeadlineExceededCounter.WithLabelValues(string(flow.Name), string(flow.State)).Inc()
+		logs.Warnf("%s: flow deadline exceeded, id: %s, name: %s, state: %s, deadline_at: %s, rid: %s",
+			constant.AsyncTaskWarnSign, flow.ID, flow.Name, flow.State, flow.DeadlineAt, kt.Rid)
+
+		flow.State = info.Target
+		flow.Reason = info.Reason
+		wd.notify(kt, &FlowEvent{Type: FlowDeadlineExceeded, Flow: flow})
+	}
+
+	if len(ids) != 0 {
+		logs.Infof("handleDeadlineExceededFlows success, count: %d, ids: %v, rid: %s", len(ids), ids, kt.Rid)
+	}
+
+	return nil
+}
+
+// handleSLABreachedFlows 对超过SLA时间仍未结束的任务流进行告警，每个任务流只告警一次，并统计当前超过SLA时间的任务流数量。
+// 注：已告警的任务流记录在内存中，主节点切换后会对仍未结束的任务流重新告警一次。
+func (wd *watchDog) handleSLABreachedFlows(kt *kit.Kit) error {
+
+	breached := make(map[string]struct{})
+	counts := make(map[enumor.FlowName]int)
+	page := &core.BasePage{
+		Start: 0,
+		Limit: listOverdueFlowsLimit,
+	}
+	for {
+		flows, err := wd.listOverdueFlows(kt, "sla_at", page)
+		if err != nil {
+			return err
+		}
+
+		for _, flow := range flows {
+			breached[flow.ID] = struct{}{}
+			counts[flow.Name]++
+
+			if _, exist := wd.slaBreachedFlowMap[flow.ID]; exist {
+				continue
+			}
+			wd.slaBreachedFlowMap[flow.ID] = struct{}{}
+
+			wd.mc.flowSLABreachedCounter.WithLabelValues(string(flow.Name)).Inc()
+			logs.Warnf("%s: flow sla breached, id: %s, name: %s, state: %s, sla_at: %s, rid: %s",
+				constant.AsyncTaskWarnSign, flow.ID, flow.Name, flow.State, flow.SLAAt, kt.Rid)
+			wd.notify(kt, &FlowEvent{Type: FlowSLABreached, Flow: flow})
+		}
+
+		if len(flows) < int(page.Limit) {
+			break
+		}
+		page.Start += uint32(page.Limit)
+	}
+
+	// 清理已经结束的任务流
+	for id := range wd.slaBreachedFlowMap {
+		if _, exist := breached[id]; !exist {
+			delete(wd.slaBreachedFlowMap, id)
+		}
+	}
+
+	wd.mc.flowSLABreachedGauge.Reset()
+	for name, count := range counts {
+		wd.mc.flowSLABreachedGauge.WithLabelValues(string(name)).Set(float64(count))
+	}
+
+	return nil
+}
+
+// handleExpiredTaskLogs 定期清理超过保留时长的任务执行日志
+func (wd *watchDog) handleExpiredTaskLogs(kt *kit.Kit) error {
+	now := times.ConvStdTimeNow()
+	if now.Sub(wd.taskLogCleanedAt) < taskLogCleanInterval {
+		return nil
+	}
+
+	before := now.Add(-wd.taskLogRetention)
+	var total uint
+	for {
+		count, err := wd.bd.DeleteExpiredTaskLog(kt, before, deleteExpiredTaskLogLimit)
+		if err != nil {
+			logs.Errorf("delete expired task log failed, err: %v, before: %s, rid: %s", err, before, kt.Rid)
+			return err
+		}
+
+		total += count
+		if count < deleteExpiredTaskLogLimit {
+			break
+		}
+	}
+	wd.taskLogCleanedAt = now
+
+	if total != 0 {
+		logs.Infof("delete %d expired task logs created before %s, rid: %s", total,
+			times.ConvStdTimeFormat(before), kt.Rid)
+	}
+
+	return nil
+}
+
+// handleExpiredFlows 定期清理超过保留时长的已结束任务流及其任务
+func (wd *watchDog) handleExpiredFlows(kt *kit.Kit) error {
+	if wd.flowRetention == 0 {
+		return nil
+	}
+
+	now := times.ConvStdTimeNow()
+	if now.Sub(wd.flowCleanedAt) < flowCleanInterval {
+		return nil
+	}
+
+	before := now.Add(-wd.flowRetention)
+	var total uint
+	for {
+		count, err := wd.bd.DeleteExpiredFlow(kt, before, deleteExpiredFlowLimit)
+		if err != nil {
+			logs.Errorf("delete expired flow failed, err: %v, before: %s, rid: %s", err, before, kt.Rid)
+			return err
+		}
+
+		total += count
+		if count < deleteExpiredFlowLimit {
+			break
+		}
+	}
+	wd.flowCleanedAt = now
+
+	if total != 0 {
+		logs.Infof("delete %d expired flows updated before %s, rid: %s", total, times.ConvStdTimeFormat(before),
+			kt.Rid)
+	}
+
+	return nil
+}
+
+// listOverdueFlows 查询指定时间字段已经到期且仍未结束的任务流
+func (wd *watchDog) listOverdueFlows(kt *kit.Kit, field string, page *core.BasePage) ([]model.Flow, error) {
+	input := &backend.ListInput{
+		Filter: tools.ExpressionAnd(
+			tools.RuleIn("state", []enumor.FlowState{enumor.FlowPending, enumor.FlowScheduled, enumor.FlowRunning}),
+			tools.RuleLessThanEqual(field, times.ConvStdTimeFormat(times.ConvStdTimeNow())),
+		),
+		Page: page,
+	}
+	flows, err := wd.bd.ListFlow(kt, input)
+	if err != nil {
+		logs.Errorf("list overdue flows failed, err: %v, field: %s, rid: %s", err, field, kt.Rid)
+		return nil, err
+	}
+
+	return flows, nil
+}
+
+// notify 发送任务流告警通知，通知失败只记录日志，不影响看门狗处理
+func (wd *watchDog) notify(kt *kit.Kit, event *FlowEvent) {
+	if wd.notifier == nil {
+		return
+	}
+
+	if err := wd.notifier.Notify(kt, event); err != nil {
+		wd.mc.flowNotifyErrCounter.WithLabelValues(string(event.Type)).Inc()
+		logs.Errorf("notify flow event failed, err: %v, type: %s, flow: %s, rid: %s", err, event.Type,
+			event.Flow.ID, kt.Rid)
+	}
+}
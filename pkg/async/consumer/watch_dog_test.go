@@ -0,0 +1,285 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package consumer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/converter"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// overdueFlowBackend 只实现看门狗查询和更新超期任务流的后端，按照写入顺序分页返回超期任务流
+type overdueFlowBackend struct {
+	backend.Backend
+	flows []model.Flow
+	// casErr 更新指定任务流状态时返回的错误
+	casErr  map[string]error
+	updated []backend.UpdateFlowInfo
+}
+
+func (bd *overdueFlowBackend) ListFlow(_ *kit.Kit, input *backend.ListInput) ([]model.Flow, error) {
+	start := int(input.Page.Start)
+	if start >= len(bd.flows) {
+		return []model.Flow{}, nil
+	}
+	end := start + int(input.Page.Limit)
+	if end > len(bd.flows) {
+		end = len(bd.flows)
+	}
+	return bd.flows[start:end], nil
+}
+
+func (bd *overdueFlowBackend) BatchUpdateFlowStateByCAS(_ *kit.Kit, infos []backend.UpdateFlowInfo) error {
+	for _, one := range infos {
+		if err := bd.casErr[one.ID]; err != nil {
+			return err
+		}
+		bd.updated = append(bd.updated, one)
+	}
+	return nil
+}
+
+// recordNotifier 记录收到的告警事件
+type recordNotifier struct {
+	events []*FlowEvent
+	err    error
+}
+
+func (n *recordNotifier) Notify(_ *kit.Kit, event *FlowEvent) error {
+	n.events = append(n.events, event)
+	return n.err
+}
+
+func newTestWatchDog(bd backend.Backend, notifier FlowNotifier) *watchDog {
+	return &watchDog{
+		bd:                 bd,
+		mc:                 initMetric(prometheus.NewRegistry()),
+		notifier:           notifier,
+		slaBreachedFlowMap: make(map[string]struct{}),
+	}
+}
+
+func newOverdueFlow(id string, state enumor.FlowState) model.Flow {
+	return model.Flow{ID: id, Name: "test_flow", State: state, Worker: converter.ValToPtr("node-1")}
+}
+
+func updatedFlows(infos []backend.UpdateFlowInfo) string {
+	result := make([]string, 0, len(infos))
+	for _, one := range infos {
+		// 执行节点为nil表示不更新执行节点
+		worker := "nil"
+		if one.Worker != nil {
+			worker = *one.Worker
+		}
+		result = append(result, fmt.Sprintf("%s:%s:%s", one.ID, one.Target, worker))
+	}
+	return strings.Join(result, ",")
+}
+
+func eventFlows(events []*FlowEvent) string {
+	result := make([]string, 0, len(events))
+	for _, one := range events {
+		result = append(result, fmt.Sprintf("%s:%s:%s", one.Type, one.Flow.ID, one.Flow.State))
+	}
+	return strings.Join(result, ",")
+}
+
+func TestHandleDeadlineExceededFlows(t *testing.T) {
+	flows := []model.Flow{
+		newOverdueFlow("pending", enumor.FlowPending),
+		newOverdueFlow("scheduled", enumor.FlowScheduled),
+		newOverdueFlow("running", enumor.FlowRunning),
+	}
+
+	cases := []struct {
+		name          string
+		flows         []model.Flow
+		casErr        map[string]error
+		notifyErr     error
+		expectUpdated string
+		expectEvents  string
+		expectErr     bool
+	}{
+		{
+			// 未开始执行的任务流置为失败并清空执行节点，执行中的任务流置为取消，由执行节点终止执行中的任务
+			name:          "fail not started and cancel running",
+			flows:         flows,
+			expectUpdated: "pending:failed:,scheduled:failed:,running:canceled:nil",
+			expectEvents: "deadline_exceeded:pending:failed,deadline_exceeded:scheduled:failed," +
+				"deadline_exceeded:running:canceled",
+		},
+		{
+			name:          "skip flow whose state changed",
+			flows:         flows,
+			casErr:        map[string]error{"scheduled": errf.New(errf.RecordNotUpdate, "state changed")},
+			expectUpdated: "pending:failed:,running:canceled:nil",
+			expectEvents:  "deadline_exceeded:pending:failed,deadline_exceeded:running:canceled",
+		},
+		{
+			name:          "update failed",
+			flows:         flows,
+			casErr:        map[string]error{"scheduled": errors.New("db error")},
+			expectUpdated: "pending:failed:",
+			expectEvents:  "deadline_exceeded:pending:failed",
+			expectErr:     true,
+		},
+		{
+			// 通知失败不影响任务流的处理
+			name:          "notify failed",
+			flows:         flows[:1],
+			notifyErr:     errors.New("send mail failed"),
+			expectUpdated: "pending:failed:",
+			expectEvents:  "deadline_exceeded:pending:failed",
+		},
+		{
+			name: "no overdue flow",
+		},
+	}
+
+	for _, c := range cases {
+		bd := &overdueFlowBackend{flows: c.flows, casErr: c.casErr}
+		notifier := &recordNotifier{err: c.notifyErr}
+		wd := newTestWatchDog(bd, notifier)
+
+		err := wd.handleDeadlineExceededFlows(kit.New())
+		if (err != nil) != c.expectErr {
+			t.Errorf("case %s: expect err: %v, but got: %v", c.name, c.expectErr, err)
+		}
+		if got := updatedFlows(bd.updated); got != c.expectUpdated {
+			t.Errorf("case %s: expect updated %s, but got: %s", c.name, c.expectUpdated, got)
+		}
+		if got := eventFlows(notifier.events); got != c.expectEvents {
+			t.Errorf("case %s: expect events %s, but got: %s", c.name, c.expectEvents, got)
+		}
+		for _, one := range notifier.events {
+			if one.Flow.Reason == nil || one.Flow.Reason.Message != ErrFlowDeadlineExceeded {
+				t.Errorf("case %s: flow %s reason should be deadline exceeded, but got: %+v", c.name,
+					one.Flow.ID, one.Flow.Reason)
+			}
+		}
+	}
+}
+
+func runningFlows(ids ...string) []model.Flow {
+	flows := make([]model.Flow, 0, len(ids))
+	for _, id := range ids {
+		flows = append(flows, newOverdueFlow(id, enumor.FlowRunning))
+	}
+	return flows
+}
+
+func alertedFlows(wd *watchDog) string {
+	ids := make([]string, 0, len(wd.slaBreachedFlowMap))
+	for id := range wd.slaBreachedFlowMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func TestHandleSLABreachedFlows(t *testing.T) {
+	// 同一个看门狗依次执行各轮检查，每轮返回当前超过SLA时间仍未结束的任务流
+	rounds := []struct {
+		name          string
+		flows         []model.Flow
+		expectEvents  string
+		expectAlerted string
+	}{
+		{
+			name:          "alert new breached flows",
+			flows:         runningFlows("a", "b"),
+			expectEvents:  "sla_breached:a:running,sla_breached:b:running",
+			expectAlerted: "a,b",
+		},
+		{
+			name:          "alert once per flow",
+			flows:         runningFlows("a", "b", "c"),
+			expectEvents:  "sla_breached:c:running",
+			expectAlerted: "a,b,c",
+		},
+		{
+			// 已结束的任务流从告警记录中清理
+			name:          "clean finished flows",
+			flows:         runningFlows("c"),
+			expectAlerted: "c",
+		},
+		{
+			name:          "alert again after cleaned",
+			flows:         runningFlows("a", "c"),
+			expectEvents:  "sla_breached:a:running",
+			expectAlerted: "a,c",
+		},
+		{
+			name: "no breached flow",
+		},
+	}
+
+	bd := new(overdueFlowBackend)
+	notifier := new(recordNotifier)
+	wd := newTestWatchDog(bd, notifier)
+	for _, r := range rounds {
+		bd.flows = r.flows
+		notifier.events = nil
+
+		if err := wd.handleSLABreachedFlows(kit.New()); err != nil {
+			t.Fatalf("round %s: handle sla breached flows failed, err: %v", r.name, err)
+		}
+		if got := eventFlows(notifier.events); got != r.expectEvents {
+			t.Errorf("round %s: expect events %s, but got: %s", r.name, r.expectEvents, got)
+		}
+		if got := alertedFlows(wd); got != r.expectAlerted {
+			t.Errorf("round %s: expect alerted flows %s, but got: %s", r.name, r.expectAlerted, got)
+		}
+	}
+}
+
+func TestHandleSLABreachedFlowsMultiPage(t *testing.T) {
+	ids := make([]string, 0, listOverdueFlowsLimit+1)
+	for i := 0; i <= listOverdueFlowsLimit; i++ {
+		ids = append(ids, fmt.Sprintf("flow%03d", i))
+	}
+
+	// 超过一页的任务流全部告警，且不会因为分页被当作已结束清理
+	notifier := new(recordNotifier)
+	wd := newTestWatchDog(&overdueFlowBackend{flows: runningFlows(ids...)}, notifier)
+	for round := 0; round < 2; round++ {
+		if err := wd.handleSLABreachedFlows(kit.New()); err != nil {
+			t.Fatalf("handle sla breached flows failed, err: %v", err)
+		}
+	}
+
+	if len(notifier.events) != len(ids) {
+		t.Errorf("expect %d events, but got: %d", len(ids), len(notifier.events))
+	}
+	if got := alertedFlows(wd); got != strings.Join(ids, ",") {
+		t.Errorf("expect all flows alerted, but got: %s", got)
+	}
+}
@@ -22,9 +22,12 @@ package producer
 import (
 	"fmt"
 
+	"hcm/pkg/api/core"
 	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
 	"hcm/pkg/async/backend/model"
 	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/dao/tools"
 	tableasync "hcm/pkg/dal/table/async"
 	"hcm/pkg/kit"
 	"hcm/pkg/logs"
@@ -64,6 +67,30 @@ func validateCustomFlowParam(kt *kit.Kit, opt *AddCustomFlowOption) error {
 	}
 
 	for _, task := range opt.Tasks {
+		// 依赖节点是否存在校验
+		for _, one := range task.DependOn {
+			if !taskMap[one] {
+				return fmt.Errorf("dependOn's actionID: %s not exist", one)
+			}
+		}
+
+		// 子任务流任务没有对应的Action，校验子任务流模版使用参数
+		if task.GetKind() == enumor.TaskKindChildFlow {
+			if task.Retry != nil && task.Retry.IsEnable() {
+				return fmt.Errorf("child_flow task: %s not support retry", task.ActionID)
+			}
+
+			params, err := decodeChildFlowParams("", task.Params)
+			if err != nil {
+				return err
+			}
+
+			if err = validateChildFlowParams(kt, params, []enumor.FlowName{opt.Name}); err != nil {
+				return err
+			}
+			continue
+		}
+
 		act, exist := action.GetAction(task.ActionName)
 		if !exist {
 			return fmt.Errorf("action: %s not exist", task.ActionName)
@@ -92,10 +119,10 @@ func validateCustomFlowParam(kt *kit.Kit, opt *AddCustomFlowOption) error {
 			}
 		}
 
-		// 依赖节点是否存在校验
-		for _, one := range task.DependOn {
-			if !taskMap[one] {
-				return fmt.Errorf("dependOn's actionID: %s not exist", one)
+		// 补偿动作校验
+		if len(task.CompensateAction) != 0 {
+			if err := validateCompensateActionParam(kt, task.CompensateAction, task.Params); err != nil {
+				return err
 			}
 		}
 	}
@@ -117,6 +144,9 @@ func buildCustomFlow(opt *AddCustomFlowOption) *model.Flow {
 	if opt.IsInitState {
 		flow.State = enumor.FlowInit
 	}
+	setFlowDeadline(flow, opt.TimeoutSec, opt.SLASec)
+	flow.Priority, flow.FairnessKey = opt.Priority, opt.FairnessKey
+	flow.CompensateOnFailure = opt.CompensateOnFailure
 
 	for _, one := range opt.Tasks {
 		if one.Retry == nil {
@@ -124,12 +154,15 @@ func buildCustomFlow(opt *AddCustomFlowOption) *model.Flow {
 		}
 
 		task := model.Task{
-			FlowName:   opt.Name,
-			ActionID:   one.ActionID,
-			ActionName: one.ActionName,
-			Params:     one.Params,
-			Retry:      one.Retry,
-			DependOn:   one.DependOn,
+			FlowName:         opt.Name,
+			ActionID:         one.ActionID,
+			ActionName:       one.ActionName,
+			Kind:             one.GetKind(),
+			Params:           one.Params,
+			Retry:            one.Retry,
+			DependOn:         one.DependOn,
+			Condition:        one.Condition,
+			CompensateAction: one.CompensateAction,
 		}
 
 		flow.Tasks = append(flow.Tasks, task)
@@ -156,7 +189,21 @@ func (p *producer) BatchUpdateCustomFlowState(kt *kit.Kit, opt *UpdateCustomFlow
 // RetryFlowTask retry task of flow
 func (p *producer) RetryFlowTask(kt *kit.Kit, flowID, taskID string) error {
 
-	err := p.backend.RetryTask(kt, flowID, taskID)
+	flows, err := p.backend.ListFlow(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("id", flowID),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list flow(%s) for retry failed, err: %v, rid: %s", flowID, err, kt.Rid)
+		return err
+	}
+
+	// 开启补偿模式的任务流失败后已经对执行成功的任务进行了补偿，不能再从失败的任务继续执行
+	if len(flows) != 0 && flows[0].CompensateOnFailure {
+		return fmt.Errorf("flow(%s) is compensated on failure, can not retry task", flowID)
+	}
+
+	err = p.backend.RetryTask(kt, flowID, taskID)
 	if err != nil {
 		logs.Errorf("retry task(%s) of flow(%s) failed, err: %v, rid: %s", taskID, flowID, err, kt.Rid)
 		return err
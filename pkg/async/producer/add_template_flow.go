@@ -33,22 +33,11 @@ import (
 
 // AddTemplateFlow add template flow
 func (p *producer) AddTemplateFlow(kt *kit.Kit, opt *AddTemplateFlowOption) (id string, err error) {
-	if err = opt.Validate(); err != nil {
-		return "", err
-	}
-
-	tpl, exist := action.GetTpl(opt.Name)
-	if !exist {
-		return "", fmt.Errorf("flow tempalte: %s not found", opt.Name)
-	}
-
-	if err = validateTplUseParam(kt, tpl, opt); err != nil {
-		logs.Errorf("validate flow template use param failed, err: %v, rid: %s", err, kt.Rid)
+	flow, err := BuildTemplateFlow(kt, opt)
+	if err != nil {
 		return "", err
 	}
 
-	flow := buildFlow(tpl, opt)
-
 	id, err = p.backend.CreateFlow(kt, flow)
 	if err != nil {
 		logs.Errorf("create flow failed, err: %v, rid: %s", err, kt.Rid)
@@ -58,7 +47,26 @@ func (p *producer) AddTemplateFlow(kt *kit.Kit, opt *AddTemplateFlowOption) (id
 	return id, nil
 }
 
-func buildFlow(tpl action.FlowTemplate, opt *AddTemplateFlowOption) *model.Flow {
+// BuildTemplateFlow 校验任务流模版使用参数，并根据任务流模版构建任务流。
+func BuildTemplateFlow(kt *kit.Kit, opt *AddTemplateFlowOption) (*model.Flow, error) {
+	if err := opt.Validate(); err != nil {
+		return nil, err
+	}
+
+	tpl, exist := action.GetTpl(opt.Name)
+	if !exist {
+		return nil, fmt.Errorf("flow tempalte: %s not found", opt.Name)
+	}
+
+	if err := validateTplUseParam(kt, tpl, opt); err != nil {
+		logs.Errorf("validate flow template use param failed, err: %v, rid: %s", err, kt.Rid)
+		return nil, err
+	}
+
+	return buildFlow(tpl, opt)
+}
+
+func buildFlow(tpl action.FlowTemplate, opt *AddTemplateFlowOption) (*model.Flow, error) {
 	flow := &model.Flow{
 		Name:      tpl.Name,
 		ShareData: tpl.ShareData,
@@ -68,6 +76,9 @@ func buildFlow(tpl action.FlowTemplate, opt *AddTemplateFlowOption) *model.Flow
 	if opt.IsInitState {
 		flow.State = enumor.FlowInit
 	}
+	setFlowDeadline(flow, opt.TimeoutSec, opt.SLASec)
+	flow.Priority, flow.FairnessKey = opt.Priority, opt.FairnessKey
+	flow.CompensateOnFailure = tpl.CompensateOnFailure
 
 	m := make(map[action.ActIDType]types.JsonField, len(opt.Tasks))
 	for _, one := range opt.Tasks {
@@ -80,27 +91,46 @@ func buildFlow(tpl action.FlowTemplate, opt *AddTemplateFlowOption) *model.Flow
 		}
 
 		task := model.Task{
-			FlowName:   tpl.Name,
-			ActionID:   one.ActionID,
-			ActionName: one.ActionName,
-			Params:     m[one.ActionID],
-			Retry:      one.Retry,
-			DependOn:   one.DependOn,
+			FlowName:         tpl.Name,
+			ActionID:         one.ActionID,
+			ActionName:       one.ActionName,
+			Kind:             one.GetKind(),
+			Params:           m[one.ActionID],
+			Retry:            one.Retry,
+			DependOn:         one.DependOn,
+			Condition:        one.Condition,
+			CompensateAction: one.CompensateAction,
 		}
 		if opt.IsInitState {
 			task.State = enumor.TaskInit
 		}
 
+		// 子任务流任务的请求参数需要补充子任务流模版名称
+		if task.IsChildFlow() {
+			params, err := buildChildFlowParams(one.ChildFlow, task.Params)
+			if err != nil {
+				return nil, err
+			}
+			task.Params = params
+		}
+
 		flow.Tasks = append(flow.Tasks, task)
 	}
 
-	return flow
+	return flow, nil
 }
 
 // validateTplUseParam 校验任务流执行动作所需参数满足要求
 // 1. Task参数校验
 // 2. 回滚参数校验
+// 3. 子任务流参数校验
 func validateTplUseParam(kt *kit.Kit, template action.FlowTemplate, opt *AddTemplateFlowOption) error {
+	return validateTplParamWithChain(kt, template, opt, []enumor.FlowName{template.Name})
+}
+
+// validateTplParamWithChain 校验任务流模版使用参数，chain 为当前任务流模版所在的子任务流调用链，用于检测循环引用
+func validateTplParamWithChain(kt *kit.Kit, template action.FlowTemplate, opt *AddTemplateFlowOption,
+	chain []enumor.FlowName) error {
 
 	// 校验Action请求参数都已经提供
 	m := make(map[action.ActIDType]types.JsonField, len(opt.Tasks))
@@ -109,6 +139,19 @@ func validateTplUseParam(kt *kit.Kit, template action.FlowTemplate, opt *AddTemp
 	}
 
 	for _, task := range template.Tasks {
+		// 子任务流任务没有对应的Action，校验子任务流模版使用参数
+		if task.GetKind() == enumor.TaskKindChildFlow {
+			params, err := decodeChildFlowParams(task.ChildFlow, m[task.ActionID])
+			if err != nil {
+				return err
+			}
+
+			if err = validateChildFlowParams(kt, params, chain); err != nil {
+				return err
+			}
+			continue
+		}
+
 		act, exist := action.GetAction(task.ActionName)
 		if !exist {
 			return fmt.Errorf("action: %s not exist", task.ActionName)
@@ -141,6 +184,13 @@ func validateTplUseParam(kt *kit.Kit, template action.FlowTemplate, opt *AddTemp
 				return fmt.Errorf("action: %s can retry, but not impl RollbackAction", task.ActionName)
 			}
 		}
+
+		// 补偿动作校验
+		if len(task.CompensateAction) != 0 {
+			if err := validateCompensateActionParam(kt, task.CompensateAction, m[task.ActionID]); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
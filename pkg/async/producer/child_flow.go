@@ -0,0 +1,114 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"fmt"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/tools/json"
+	"hcm/pkg/tools/slice"
+)
+
+// BuildChildFlow 根据子任务流任务的请求参数构建子任务流。
+func BuildChildFlow(kt *kit.Kit, params *action.ChildFlowParams, memo string) (*model.Flow, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	opt := &AddTemplateFlowOption{
+		Name:  params.FlowName,
+		Memo:  memo,
+		Tasks: make([]TemplateFlowTask, 0, len(params.Tasks)),
+	}
+	for _, one := range params.Tasks {
+		opt.Tasks = append(opt.Tasks, TemplateFlowTask{ActionID: one.ActionID, Params: one.Params})
+	}
+
+	return BuildTemplateFlow(kt, opt)
+}
+
+// decodeChildFlowParams 解析子任务流任务的请求参数，flowName 不为空时，请求参数中的子任务流模版名称需要与其一致。
+func decodeChildFlowParams(flowName enumor.FlowName, fields types.JsonField) (*action.ChildFlowParams, error) {
+	params := new(action.ChildFlowParams)
+	if !fields.IsEmpty() {
+		if err := json.UnmarshalFromString(string(fields), params); err != nil {
+			return nil, fmt.Errorf("can not decode child flow params, err: %v", err)
+		}
+	}
+
+	if len(flowName) != 0 {
+		if len(params.FlowName) != 0 && params.FlowName != flowName {
+			return nil, fmt.Errorf("child flow params flow_name: %s not match template child_flow: %s",
+				params.FlowName, flowName)
+		}
+		params.FlowName = flowName
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// validateChildFlowParams 校验子任务流模版使用参数，chain 为父任务流的调用链，子任务流模版不能出现在调用链中
+func validateChildFlowParams(kt *kit.Kit, params *action.ChildFlowParams, chain []enumor.FlowName) error {
+	if slice.IsItemInSlice(chain, params.FlowName) {
+		return fmt.Errorf("child flow: %s circular reference, chain: %v", params.FlowName, chain)
+	}
+
+	tpl, exist := action.GetTpl(params.FlowName)
+	if !exist {
+		return fmt.Errorf("child flow template: %s not found", params.FlowName)
+	}
+
+	opt := &AddTemplateFlowOption{
+		Name:  params.FlowName,
+		Tasks: make([]TemplateFlowTask, 0, len(params.Tasks)),
+	}
+	for _, one := range params.Tasks {
+		opt.Tasks = append(opt.Tasks, TemplateFlowTask{ActionID: one.ActionID, Params: one.Params})
+	}
+	if err := opt.Validate(); err != nil {
+		return err
+	}
+
+	return validateTplParamWithChain(kt, tpl, opt, append(chain, params.FlowName))
+}
+
+// buildChildFlowParams 构建子任务流任务的请求参数，补充子任务流模版名称
+func buildChildFlowParams(flowName enumor.FlowName, fields types.JsonField) (types.JsonField, error) {
+	params, err := decodeChildFlowParams(flowName, fields)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := json.MarshalToString(params)
+	if err != nil {
+		return "", fmt.Errorf("marshal child flow params failed, err: %v", err)
+	}
+
+	return types.JsonField(raw), nil
+}
@@ -67,15 +67,18 @@ func (p *producer) CloneFlow(kt *kit.Kit, flowId string, opt *CloneFlowOption) (
 
 func clone(kt *kit.Kit, oldFlow model.Flow, oldTaskList []model.Task, opt *CloneFlowOption) (newFlow *model.Flow) {
 	newFlow = &model.Flow{
-		Name:      oldFlow.Name,
-		ShareData: tableasync.NewShareData(oldFlow.ShareData.GetInitData()),
-		Memo:      oldFlow.Memo,
-		State:     enumor.FlowPending,
-		Reason:    nil,
-		Worker:    nil,
-		Tasks:     make([]model.Task, len(oldTaskList)),
-		Creator:   kt.User,
-		Reviser:   kt.User,
+		Name:                oldFlow.Name,
+		ShareData:           tableasync.NewShareData(oldFlow.ShareData.GetInitData()),
+		Memo:                oldFlow.Memo,
+		Priority:            oldFlow.Priority,
+		FairnessKey:         oldFlow.FairnessKey,
+		CompensateOnFailure: oldFlow.CompensateOnFailure,
+		State:               enumor.FlowPending,
+		Reason:              nil,
+		Worker:              nil,
+		Tasks:               make([]model.Task, len(oldTaskList)),
+		Creator:             kt.User,
+		Reviser:             kt.User,
 	}
 
 	if opt.IsInitState {
@@ -84,19 +87,23 @@ func clone(kt *kit.Kit, oldFlow model.Flow, oldTaskList []model.Task, opt *Clone
 	if len(opt.Memo) > 0 {
 		newFlow.Memo = opt.Memo
 	}
+	setFlowDeadline(newFlow, opt.TimeoutSec, opt.SLASec)
 	for i, old := range oldTaskList {
 		newFlow.Tasks[i] = model.Task{
-			FlowName:   oldFlow.Name,
-			ActionID:   old.ActionID,
-			ActionName: old.ActionName,
-			Params:     old.Params,
-			Retry:      old.Retry,
-			DependOn:   old.DependOn,
-			State:      mapCloneTaskState(old.State),
-			Reason:     nil,
-			Result:     "",
-			Creator:    kt.User,
-			Reviser:    kt.User,
+			FlowName:         oldFlow.Name,
+			ActionID:         old.ActionID,
+			ActionName:       old.ActionName,
+			Kind:             old.Kind,
+			Params:           old.Params,
+			Retry:            old.Retry,
+			DependOn:         old.DependOn,
+			Condition:        old.Condition,
+			CompensateAction: old.CompensateAction,
+			State:            mapCloneTaskState(old.State),
+			Reason:           nil,
+			Result:           "",
+			Creator:          kt.User,
+			Reviser:          kt.User,
 		}
 	}
 	return newFlow
@@ -0,0 +1,56 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"fmt"
+
+	"hcm/pkg/async/action"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// validateCompensateActionParam 校验补偿动作已注册，且能够解析被补偿任务的请求参数
+func validateCompensateActionParam(kt *kit.Kit, name enumor.ActionName, params types.JsonField) error {
+	act, exist := action.GetAction(name)
+	if !exist {
+		return fmt.Errorf("compensate action: %s not exist", name)
+	}
+
+	if params.IsEmpty() {
+		return nil
+	}
+
+	paramAct, ok := act.(action.ParameterAction)
+	if !ok {
+		return fmt.Errorf("compensate action: %s need params, but not impl ParameterAction", name)
+	}
+
+	p := paramAct.ParameterNew()
+	if err := action.Decode(params, p); err != nil {
+		logs.Errorf("compensate action: %s can not decode params, err: %v, field: %s, type: %T, rid: %s", name,
+			err, params, p, kt.Rid)
+		return fmt.Errorf("compensate action: %s can not decode param, err: %v", name, err)
+	}
+
+	return nil
+}
@@ -22,11 +22,14 @@ package producer
 
 import (
 	"errors"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
 	"hcm/pkg/kit"
+	"hcm/pkg/tools/times"
 )
 
 // Producer 异步任务生产者，提供异步任务下发相关功能。。
@@ -36,6 +39,14 @@ type Producer interface {
 	BatchUpdateCustomFlowState(kt *kit.Kit, opt *UpdateCustomFlowStateOption) error
 	RetryFlowTask(kt *kit.Kit, flowID, taskID string) error
 	CloneFlow(kt *kit.Kit, flowId string, opt *CloneFlowOption) (id string, err error)
+
+	RegisterScheduledFlow(kt *kit.Kit, opt *RegisterScheduledFlowOption) (id string, err error)
+	ListScheduledFlow(kt *kit.Kit, input *backend.ListInput) ([]model.ScheduledFlow, error)
+	PauseScheduledFlow(kt *kit.Kit, id string) error
+	ResumeScheduledFlow(kt *kit.Kit, id string) error
+	DeleteScheduledFlow(kt *kit.Kit, id string) error
+
+	ListTaskLog(kt *kit.Kit, input *backend.ListInput) ([]model.TaskLog, error)
 }
 
 var _ Producer = new(producer)
@@ -61,3 +72,15 @@ type producer struct {
 	backend backend.Backend
 	mc      *metric
 }
+
+// setFlowDeadline 根据超时时间和SLA时间（秒）设置任务流的截止时间和SLA时间，从当前时间开始计算
+func setFlowDeadline(flow *model.Flow, timeoutSec, slaSec uint) {
+	now := times.ConvStdTimeNow()
+	if timeoutSec != 0 {
+		flow.DeadlineAt = times.ConvStdTimeFormat(now.Add(time.Duration(timeoutSec) * time.Second))
+	}
+
+	if slaSec != 0 {
+		flow.SLAAt = times.ConvStdTimeFormat(now.Add(time.Duration(slaSec) * time.Second))
+	}
+}
@@ -0,0 +1,217 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package producer
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/async/action"
+	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/tools"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/tools/times"
+)
+
+// RegisterScheduledFlow 注册定时任务流，同名定时任务流已存在时更新其模版、cron表达式和任务参数，状态保持不变。
+func (p *producer) RegisterScheduledFlow(kt *kit.Kit, opt *RegisterScheduledFlowOption) (string, error) {
+	if err := opt.Validate(); err != nil {
+		return "", err
+	}
+
+	// 提前校验任务流模版及参数，避免到触发时才发现任务流无法创建
+	tplOpt := &AddTemplateFlowOption{Name: opt.FlowName, Tasks: opt.Tasks}
+	if _, err := BuildTemplateFlow(kt, tplOpt); err != nil {
+		return "", err
+	}
+
+	tasks := make(tableasync.ScheduledTasks, 0, len(opt.Tasks))
+	for _, one := range opt.Tasks {
+		tasks = append(tasks, tableasync.ScheduledTask{ActionID: string(one.ActionID), Params: one.Params})
+	}
+
+	next, err := model.NextTriggerTime(opt.Spec, times.ConvStdTimeNow())
+	if err != nil {
+		return "", err
+	}
+
+	flows, err := p.backend.ListScheduledFlow(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("name", opt.Name),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list scheduled flow by name failed, err: %v, name: %s, rid: %s", err, opt.Name, kt.Rid)
+		return "", err
+	}
+
+	if len(flows) == 0 {
+		flow := &model.ScheduledFlow{
+			Name:          opt.Name,
+			FlowName:      opt.FlowName,
+			Spec:          opt.Spec,
+			Tasks:         tasks,
+			Memo:          opt.Memo,
+			State:         enumor.ScheduledFlowEnabled,
+			NextTriggerAt: times.ConvStdTimeFormat(next),
+		}
+		id, err := p.backend.CreateScheduledFlow(kt, flow)
+		if err != nil {
+			logs.Errorf("create scheduled flow failed, err: %v, name: %s, rid: %s", err, opt.Name, kt.Rid)
+			return "", err
+		}
+
+		return id, nil
+	}
+
+	exist := flows[0]
+	update := &model.ScheduledFlow{
+		ID:       exist.ID,
+		FlowName: opt.FlowName,
+		Spec:     opt.Spec,
+		Tasks:    tasks,
+		Memo:     opt.Memo,
+	}
+	// cron表达式未变化时保留原有的下一次触发时间，避免重复注册导致错过的触发被跳过
+	if exist.Spec != opt.Spec {
+		update.NextTriggerAt = times.ConvStdTimeFormat(next)
+	}
+	if err = p.backend.UpdateScheduledFlow(kt, update); err != nil {
+		logs.Errorf("update scheduled flow failed, err: %v, id: %s, rid: %s", err, exist.ID, kt.Rid)
+		return "", err
+	}
+
+	return exist.ID, nil
+}
+
+// ListScheduledFlow 查询定时任务流
+func (p *producer) ListScheduledFlow(kt *kit.Kit, input *backend.ListInput) ([]model.ScheduledFlow, error) {
+	if input == nil {
+		return nil, errors.New("list input is required")
+	}
+
+	return p.backend.ListScheduledFlow(kt, input)
+}
+
+// PauseScheduledFlow 暂停定时任务流，暂停后不再触发
+func (p *producer) PauseScheduledFlow(kt *kit.Kit, id string) error {
+	flow, err := p.getScheduledFlow(kt, id)
+	if err != nil {
+		return err
+	}
+
+	if flow.State == enumor.ScheduledFlowPaused {
+		return nil
+	}
+
+	update := &model.ScheduledFlow{
+		ID:    id,
+		State: enumor.ScheduledFlowPaused,
+	}
+	if err = p.backend.UpdateScheduledFlow(kt, update); err != nil {
+		logs.Errorf("pause scheduled flow failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ResumeScheduledFlow 恢复定时任务流，从当前时间重新计算下一次触发时间，暂停期间错过的触发不再补偿
+func (p *producer) ResumeScheduledFlow(kt *kit.Kit, id string) error {
+	flow, err := p.getScheduledFlow(kt, id)
+	if err != nil {
+		return err
+	}
+
+	if flow.State == enumor.ScheduledFlowEnabled {
+		return nil
+	}
+
+	next, err := model.NextTriggerTime(flow.Spec, times.ConvStdTimeNow())
+	if err != nil {
+		return err
+	}
+
+	update := &model.ScheduledFlow{
+		ID:            id,
+		State:         enumor.ScheduledFlowEnabled,
+		NextTriggerAt: times.ConvStdTimeFormat(next),
+	}
+	if err = p.backend.UpdateScheduledFlow(kt, update); err != nil {
+		logs.Errorf("resume scheduled flow failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteScheduledFlow 删除定时任务流，已经触发创建的任务流不受影响
+func (p *producer) DeleteScheduledFlow(kt *kit.Kit, id string) error {
+	if _, err := p.getScheduledFlow(kt, id); err != nil {
+		return err
+	}
+
+	if err := p.backend.DeleteScheduledFlow(kt, id); err != nil {
+		logs.Errorf("delete scheduled flow failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+func (p *producer) getScheduledFlow(kt *kit.Kit, id string) (*model.ScheduledFlow, error) {
+	if len(id) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	flows, err := p.backend.ListScheduledFlow(kt, &backend.ListInput{
+		Filter: tools.EqualExpression("id", id),
+		Page:   core.NewDefaultBasePage(),
+	})
+	if err != nil {
+		logs.Errorf("list scheduled flow failed, err: %v, id: %s, rid: %s", err, id, kt.Rid)
+		return nil, err
+	}
+
+	if len(flows) == 0 {
+		return nil, errf.Newf(errf.RecordNotFound, "scheduled flow: %s not found", id)
+	}
+
+	return &flows[0], nil
+}
+
+// BuildScheduledFlow 根据定时任务流的模版和任务参数构建本次触发需要创建的任务流
+func BuildScheduledFlow(kt *kit.Kit, sf *model.ScheduledFlow) (*model.Flow, error) {
+	opt := &AddTemplateFlowOption{
+		Name:  sf.FlowName,
+		Memo:  fmt.Sprintf("scheduled flow: %s", sf.ID),
+		Tasks: make([]TemplateFlowTask, 0, len(sf.Tasks)),
+	}
+	for _, one := range sf.Tasks {
+		opt.Tasks = append(opt.Tasks, TemplateFlowTask{ActionID: action.ActIDType(one.ActionID), Params: one.Params})
+	}
+
+	return BuildTemplateFlow(kt, opt)
+}
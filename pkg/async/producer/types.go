@@ -21,9 +21,12 @@ package producer
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"hcm/pkg/async/action"
 	"hcm/pkg/async/backend"
+	"hcm/pkg/async/backend/model"
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
 	tableasync "hcm/pkg/dal/table/async"
@@ -40,6 +43,14 @@ type AddTemplateFlowOption struct {
 	Tasks []TemplateFlowTask `json:"tasks" validate:"omitempty"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// TimeoutSec 任务流超时时间（秒），从创建开始计算，超时仍未结束的任务流会被置为失败或取消，0表示不限制
+	TimeoutSec uint `json:"timeout_sec" validate:"omitempty"`
+	// SLASec 任务流SLA时间（秒），从创建开始计算，超过该时间仍未结束的任务流会触发告警，0表示不告警
+	SLASec uint `json:"sla_sec" validate:"omitempty"`
+	// Priority 任务流优先级，数值越大越优先派发，取值范围[-100, 100]，默认为0
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// FairnessKey 任务流公平键（如 account:xxx、biz:xxx），不同公平键之间按照权重公平派发，并受公平键并发上限限制
+	FairnessKey string `json:"fairness_key" validate:"omitempty,lte=64"`
 }
 
 // Validate AddTemplateFlowOption
@@ -59,7 +70,11 @@ func (opt *AddTemplateFlowOption) Validate() error {
 		}
 	}
 
-	return nil
+	if err := opt.Priority.Validate(); err != nil {
+		return err
+	}
+
+	return validateFlowDeadline(opt.TimeoutSec, opt.SLASec)
 }
 
 // TemplateFlowTask define task info.
@@ -87,6 +102,16 @@ type AddCustomFlowOption struct {
 	Tasks []CustomFlowTask `json:"tasks" validate:"required"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// TimeoutSec 任务流超时时间（秒），从创建开始计算，超时仍未结束的任务流会被置为失败或取消，0表示不限制
+	TimeoutSec uint `json:"timeout_sec" validate:"omitempty"`
+	// SLASec 任务流SLA时间（秒），从创建开始计算，超过该时间仍未结束的任务流会触发告警，0表示不告警
+	SLASec uint `json:"sla_sec" validate:"omitempty"`
+	// Priority 任务流优先级，数值越大越优先派发，取值范围[-100, 100]，默认为0
+	Priority enumor.FlowPriority `json:"priority" validate:"omitempty"`
+	// FairnessKey 任务流公平键（如 account:xxx、biz:xxx），不同公平键之间按照权重公平派发，并受公平键并发上限限制
+	FairnessKey string `json:"fairness_key" validate:"omitempty,lte=64"`
+	// CompensateOnFailure 是否开启补偿模式，开启后任务流执行失败时，会按照依赖关系的逆序对执行成功的任务执行补偿动作
+	CompensateOnFailure bool `json:"compensate_on_failure" validate:"omitempty"`
 }
 
 // Validate AddCustomFlowOption
@@ -106,6 +131,14 @@ func (opt *AddCustomFlowOption) Validate() error {
 		}
 	}
 
+	if err := opt.Priority.Validate(); err != nil {
+		return err
+	}
+
+	if err := validateFlowDeadline(opt.TimeoutSec, opt.SLASec); err != nil {
+		return err
+	}
+
 	return validator.Validate.Struct(opt)
 }
 
@@ -117,12 +150,27 @@ type CustomFlowTask struct {
 	ActionName enumor.ActionName `json:"action_name" validate:"required"`
 	// DependOn 运行当前Action依赖的前置ActionID
 	DependOn []action.ActIDType `json:"depend_on" validate:"omitempty"`
+	// Kind 任务类型，默认为action。为child_flow时，ActionName需为run_child_flow，Params为子任务流参数
+	Kind enumor.TaskKind `json:"kind" validate:"omitempty"`
+	// Condition 任务执行条件，条件不成立时任务会被跳过
+	Condition *tableasync.Condition `json:"condition" validate:"omitempty"`
+	// CompensateAction 补偿动作，任务流开启补偿模式且执行失败后，使用相同的请求参数执行，用于撤销该任务已经执行成功的操作
+	CompensateAction enumor.ActionName `json:"compensate_action" validate:"omitempty"`
 	// Params 执行请求参数
 	Params types.JsonField `json:"params" validate:"omitempty"`
 	// Retry 任务运行重试相关配置参数，如果不设置，默认不允许进行重试。
 	Retry *tableasync.Retry `json:"retry" validate:"omitempty"`
 }
 
+// GetKind 获取任务类型，未设置时默认为action类型
+func (task *CustomFlowTask) GetKind() enumor.TaskKind {
+	if len(task.Kind) == 0 {
+		return enumor.TaskKindAction
+	}
+
+	return task.Kind
+}
+
 // Validate CustomFlowTask
 func (task *CustomFlowTask) Validate() error {
 	if err := validator.Validate.Struct(task); err != nil {
@@ -133,6 +181,26 @@ func (task *CustomFlowTask) Validate() error {
 		return err
 	}
 
+	if err := task.GetKind().Validate(); err != nil {
+		return err
+	}
+
+	if (task.GetKind() == enumor.TaskKindChildFlow) != (task.ActionName == enumor.ActionRunChildFlow) {
+		return fmt.Errorf("child_flow task action_name should be %s", enumor.ActionRunChildFlow)
+	}
+
+	if task.Condition != nil {
+		if err := action.ValidateCondition(task.Condition, task.DependOn); err != nil {
+			return err
+		}
+	}
+
+	if len(task.CompensateAction) != 0 {
+		if err := action.ValidateCompensateAction(task.GetKind(), task.CompensateAction); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -164,10 +232,64 @@ type CloneFlowOption struct {
 	Memo string `json:"memo" validate:"omitempty"`
 	// IsInitState 是否初始化状态
 	IsInitState bool `json:"is_init_state" validate:"omitempty"`
+	// TimeoutSec 任务流超时时间（秒），从创建开始计算，超时仍未结束的任务流会被置为失败或取消，0表示不限制
+	TimeoutSec uint `json:"timeout_sec" validate:"omitempty"`
+	// SLASec 任务流SLA时间（秒），从创建开始计算，超过该时间仍未结束的任务流会触发告警，0表示不告警
+	SLASec uint `json:"sla_sec" validate:"omitempty"`
 }
 
 // Validate AddTemplateFlowOption
 func (opt *CloneFlowOption) Validate() error {
 
+	if err := validateFlowDeadline(opt.TimeoutSec, opt.SLASec); err != nil {
+		return err
+	}
+
 	return validator.Validate.Struct(opt)
 }
+
+// validateFlowDeadline 校验任务流超时时间和SLA时间，SLA告警需要在超时之前触发
+func validateFlowDeadline(timeoutSec, slaSec uint) error {
+	if timeoutSec != 0 && slaSec > timeoutSec {
+		return errors.New("sla_sec should be less than or equal to timeout_sec")
+	}
+
+	return nil
+}
+
+// RegisterScheduledFlowOption define register scheduled flow option.
+type RegisterScheduledFlowOption struct {
+	// Name 定时任务流名称，全局唯一，重复注册会更新已有的定时任务流
+	Name string `json:"name" validate:"required,lte=64"`
+	// FlowName 任务流模版名称
+	FlowName enumor.FlowName `json:"flow_name" validate:"required"`
+	// Spec 标准5段式cron表达式（分 时 日 月 周），如：0 2 * * *，也支持 @daily、@every 1h 等描述符
+	Spec string `json:"spec" validate:"required,lte=128"`
+	// Memo 备注
+	Memo string `json:"memo" validate:"omitempty,lte=255"`
+	// Tasks 任务私有化参数设置
+	Tasks []TemplateFlowTask `json:"tasks" validate:"omitempty"`
+}
+
+// Validate RegisterScheduledFlowOption
+func (opt *RegisterScheduledFlowOption) Validate() error {
+	if err := validator.Validate.Struct(opt); err != nil {
+		return err
+	}
+
+	if err := opt.FlowName.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := model.NextTriggerTime(opt.Spec, time.Now()); err != nil {
+		return err
+	}
+
+	for index := range opt.Tasks {
+		if err := opt.Tasks[index].Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
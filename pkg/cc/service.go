@@ -124,6 +124,7 @@ type CloudServerSetting struct {
 	Itsm           ApiGateway     `yaml:"itsm"`
 	CloudSelection CloudSelection `yaml:"cloudSelection"`
 	Cmsi           CMSI           `yaml:"cmsi"`
+	Lock           Lock           `yaml:"lock"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -136,6 +137,7 @@ func (s *CloudServerSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
+	s.Lock.trySetDefault()
 
 	return
 }
@@ -170,6 +172,10 @@ func (s CloudServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.Lock.validate(); err != nil {
+		return err
+	}
+
 	if err := s.Itsm.validate(); err != nil {
 		return err
 	}
@@ -250,6 +256,8 @@ type HCServiceSetting struct {
 	Service    Service    `yaml:"service"`
 	Log        LogOption  `yaml:"log"`
 	SyncConfig SyncConfig `yaml:"sync"`
+	EventSync  EventSync  `yaml:"eventSync"`
+	SyncRun    SyncRun    `yaml:"syncRun"`
 }
 
 // trySetFlagBindIP try set flag bind ip.
@@ -263,6 +271,8 @@ func (s *HCServiceSetting) trySetDefault() {
 	s.Service.trySetDefault()
 	s.Log.trySetDefault()
 	s.SyncConfig.trySetDefault()
+	s.EventSync.trySetDefault()
+	s.SyncRun.trySetDefault()
 
 	return
 }
@@ -278,6 +288,10 @@ func (s HCServiceSetting) Validate() error {
 		return err
 	}
 
+	if err := s.EventSync.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -402,6 +416,9 @@ func (s *TaskServerSetting) trySetDefault() {
 	s.Service.trySetDefault()
 	s.Database.trySetDefault()
 	s.Log.trySetDefault()
+	if len(s.Async.Backend.Etcd.Endpoints) != 0 {
+		s.Async.Backend.Etcd.trySetDefault()
+	}
 
 	return
 }
@@ -421,6 +438,14 @@ func (s TaskServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.Async.Backend.GetType().Validate(); err != nil {
+		return err
+	}
+
+	if err := s.Async.WatchDog.FlowNotify.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -431,6 +456,8 @@ type AccountServerSetting struct {
 	Controller     BillControllerOption `yaml:"controller"`
 	Log            LogOption            `yaml:"log"`
 	BillAllocation BillAllocationOption `yaml:"billAllocation"`
+	BillAlert      BillAlertOption      `yaml:"billAlert"`
+	ExchangeRate   ExchangeRateOption   `yaml:"exchangeRate"`
 	Esb            Esb                  `yaml:"esb"`
 	TmpFileDir     string               `yaml:"tmpFileDir"`
 }
@@ -445,6 +472,8 @@ func (s *AccountServerSetting) trySetDefault() {
 	s.Network.trySetDefault()
 	s.Service.trySetDefault()
 	s.Controller.trySetDefault()
+	s.BillAlert.trySetDefault()
+	s.ExchangeRate.trySetDefault()
 	s.Log.trySetDefault()
 	if s.TmpFileDir == "" {
 		s.TmpFileDir = "/tmp"
@@ -466,6 +495,14 @@ func (s AccountServerSetting) Validate() error {
 		return err
 	}
 
+	if err := s.BillAlert.validate(); err != nil {
+		return err
+	}
+
+	if err := s.ExchangeRate.validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -159,10 +159,13 @@ func (lm *Limiter) trySetDefault() {
 
 // Async defines async relating.
 type Async struct {
-	Scheduler  Parser     `yaml:"scheduler"`
-	Executor   Executor   `yaml:"executor"`
-	Dispatcher Dispatcher `yaml:"dispatcher"`
-	WatchDog   WatchDog   `yaml:"watchDog"`
+	Backend    AsyncBackend `yaml:"backend"`
+	Scheduler  Parser       `yaml:"scheduler"`
+	Executor   Executor     `yaml:"executor"`
+	Dispatcher Dispatcher   `yaml:"dispatcher"`
+	WatchDog   WatchDog     `yaml:"watchDog"`
+	// Fairness 任务流公平派发配置
+	Fairness AsyncFairness `yaml:"fairness"`
 }
 
 // Validate Async
@@ -171,6 +174,55 @@ func (a Async) Validate() error {
 	return nil
 }
 
+// AsyncBackend 异步任务框架存储后端，负责保存任务流和任务的状态
+type AsyncBackend struct {
+	// Type 存储后端类型，支持 mysql、etcd，未配置时默认为 mysql
+	Type enumor.BackendType `yaml:"type"`
+	// Etcd 存储后端为 etcd 时使用的 etcd 配置，未配置 endpoints 时复用服务发现的 etcd 配置
+	Etcd Etcd `yaml:"etcd"`
+}
+
+// GetType return async backend type, default is mysql.
+func (b AsyncBackend) GetType() enumor.BackendType {
+	if len(b.Type) == 0 {
+		return enumor.BackendMysql
+	}
+
+	return b.Type
+}
+
+// Lock 分布式锁配置
+type Lock struct {
+	// Backend 分布式锁存储后端，支持 etcd、mysql，未配置时默认为 etcd
+	Backend enumor.BackendType `yaml:"backend"`
+	// Etcd 存储后端为 etcd 时使用的 etcd 配置，未配置 endpoints 时复用服务发现的 etcd 配置
+	Etcd Etcd `yaml:"etcd"`
+}
+
+// GetBackend return lock backend type, default is etcd.
+func (l Lock) GetBackend() enumor.BackendType {
+	if len(l.Backend) == 0 {
+		return enumor.BackendEtcd
+	}
+
+	return l.Backend
+}
+
+// trySetDefault set the Lock default value if user not configured.
+func (l *Lock) trySetDefault() {
+	if len(l.Etcd.Endpoints) != 0 {
+		l.Etcd.trySetDefault()
+	}
+}
+
+func (l Lock) validate() error {
+	if err := l.GetBackend().Validate(); err != nil {
+		return fmt.Errorf("lock backend is invalid, err: %v", err)
+	}
+
+	return nil
+}
+
 // Parser 公共组件，负责获取分配给当前节点的任务流，并解析成任务树后，派发当前要执行的任务给executor执行
 type Parser struct {
 	WatchIntervalSec uint `yaml:"watchIntervalSec"`
@@ -188,10 +240,172 @@ type Dispatcher struct {
 	WatchIntervalSec uint `yaml:"watchIntervalSec"`
 }
 
+// AsyncFairness 任务流公平派发配置，高优先级的任务流优先派发，同优先级下不同公平键（如账号ID、业务ID）之间
+// 按照权重公平派发，并限制每个公平键同时处于调度和运行中的任务流数量
+type AsyncFairness struct {
+	// DefaultWeight 未单独配置的公平键的权重，为0时默认为1
+	DefaultWeight uint `yaml:"defaultWeight"`
+	// DefaultConcurrency 未单独配置的公平键并发上限，为0表示不限制，不作用于未设置公平键的任务流
+	DefaultConcurrency uint `yaml:"defaultConcurrency"`
+	// Keys 公平键单独配置，key 可以是完整的公平键（如 account:00000001），也可以是公平键类型前缀（如 account）
+	Keys map[string]AsyncFairnessKey `yaml:"keys"`
+	// DispatchLimit 主节点每轮最多派发的任务流数量，为0时默认为100
+	DispatchLimit uint `yaml:"dispatchLimit"`
+}
+
+// AsyncFairnessKey 公平键配置
+type AsyncFairnessKey struct {
+	Weight      uint `yaml:"weight"`
+	Concurrency uint `yaml:"concurrency"`
+}
+
 // WatchDog 主节点组件，负责异常任务修正（超时任务，任务处理节点已经挂掉的任务等）
 type WatchDog struct {
 	WatchIntervalSec uint `yaml:"watchIntervalSec"`
 	TaskTimeoutSec   uint `yaml:"taskTimeoutSec"`
+	// TaskLogRetentionDays 任务执行日志保留天数，为0时默认保留30天
+	TaskLogRetentionDays uint `yaml:"taskLogRetentionDays"`
+	// FlowRetentionDays 已结束任务流及其任务的保留天数，为0时不清理
+	FlowRetentionDays uint `yaml:"flowRetentionDays"`
+	// FlowNotify 任务流超过截止时间或SLA时间时的告警通知配置
+	FlowNotify FlowNotify `yaml:"flowNotify"`
+}
+
+// FlowNotify 异步任务流告警通知配置，开启后通过CMSI邮件通知接收人
+type FlowNotify struct {
+	Enable    bool     `yaml:"enable"`
+	Receivers []string `yaml:"receivers"`
+	Cmsi      CMSI     `yaml:"cmsi"`
+}
+
+// validate FlowNotify
+func (n *FlowNotify) validate() error {
+	if !n.Enable {
+		return nil
+	}
+
+	if len(n.Receivers) == 0 {
+		return errors.New("flow notify receivers cannot be empty")
+	}
+
+	if err := n.Cmsi.validate(); err != nil {
+		return fmt.Errorf("flow notify cmsi validate failed, err: %v", err)
+	}
+
+	return nil
+}
+
+// EventSync 云上变更事件增量同步配置，消费云厂商操作审计事件（如 CloudTrail、CloudAudit），
+// 只同步事件涉及的资源，作为定时全量同步的补充
+type EventSync struct {
+	Enable bool `yaml:"enable"`
+	// PollIntervalSec 拉取事件源的间隔
+	PollIntervalSec uint `yaml:"pollIntervalSec"`
+	// DebounceSec 事件合并窗口，窗口内同一资源的多次变更只触发一次同步
+	DebounceSec uint `yaml:"debounceSec"`
+	// Concurrency 同时执行的同步批次数
+	Concurrency uint `yaml:"concurrency"`
+	// MaxRetry 同步失败后的最大重试次数
+	MaxRetry uint `yaml:"maxRetry"`
+	// Sources 事件源，仅主节点拉取。除此之外，所有节点都可以通过接口推送事件
+	Sources []EventSource `yaml:"sources"`
+}
+
+// trySetDefault set the EventSync default value if user not configured.
+func (e *EventSync) trySetDefault() {
+	if e.PollIntervalSec == 0 {
+		e.PollIntervalSec = 10
+	}
+
+	if e.DebounceSec == 0 {
+		e.DebounceSec = 30
+	}
+
+	if e.Concurrency == 0 {
+		e.Concurrency = 5
+	}
+
+	if e.MaxRetry == 0 {
+		e.MaxRetry = 3
+	}
+}
+
+// validate EventSync
+func (e EventSync) validate() error {
+	if !e.Enable {
+		return nil
+	}
+
+	names := make(map[string]struct{}, len(e.Sources))
+	for _, source := range e.Sources {
+		if err := source.validate(); err != nil {
+			return fmt.Errorf("event sync source validate failed, err: %v", err)
+		}
+
+		if _, exists := names[source.Name]; exists {
+			return fmt.Errorf("event sync source name %s is duplicated", source.Name)
+		}
+		names[source.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// SyncRun 资源同步执行记录配置
+type SyncRun struct {
+	// RetentionDays 执行记录保留天数，过期记录由主节点定期清理
+	RetentionDays uint `yaml:"retentionDays"`
+}
+
+// trySetDefault set the SyncRun default value if user not configured.
+func (s *SyncRun) trySetDefault() {
+	if s.RetentionDays == 0 {
+		s.RetentionDays = 30
+	}
+}
+
+// EventSourceType 事件源类型
+type EventSourceType string
+
+const (
+	// FileEventSource 本地文件事件源，文件每行为一条云厂商原始事件记录，新追加的记录会被增量消费
+	FileEventSource EventSourceType = "file"
+)
+
+// EventSource 事件源配置，一个事件源只包含一个账号的事件
+type EventSource struct {
+	Name      string          `yaml:"name"`
+	Type      EventSourceType `yaml:"type"`
+	Vendor    enumor.Vendor   `yaml:"vendor"`
+	AccountID string          `yaml:"accountID"`
+	// Path 本地文件事件源的文件路径
+	Path string `yaml:"path"`
+}
+
+// validate EventSource
+func (e EventSource) validate() error {
+	if len(e.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(e.Vendor) == 0 {
+		return fmt.Errorf("source %s vendor is required", e.Name)
+	}
+
+	if len(e.AccountID) == 0 {
+		return fmt.Errorf("source %s accountID is required", e.Name)
+	}
+
+	switch e.Type {
+	case FileEventSource:
+		if len(e.Path) == 0 {
+			return fmt.Errorf("source %s path is required", e.Name)
+		}
+	default:
+		return fmt.Errorf("source %s type %s is not supported", e.Name, e.Type)
+	}
+
+	return nil
 }
 
 // DataBase defines database related runtime
@@ -856,6 +1070,166 @@ func (bco *BillControllerOption) trySetDefault() {
 	}
 }
 
+var (
+	defaultBillAlertSyncDuration = 10 * time.Minute
+	defaultAnomalyWindowDays     = uint(14)
+	defaultAnomalyZScore         = 3.0
+)
+
+// BillAlertOption 账单预算及费用异常告警配置，开启后在每次日账单汇总完成后评估预算和费用异常，并通过CMSI邮件通知
+type BillAlertOption struct {
+	Enable bool `yaml:"enable"`
+	// SyncDuration 检查日账单汇总是否有更新的间隔
+	SyncDuration *time.Duration `yaml:"syncDuration,omitempty"`
+	// AnomalyWindowDays 费用异常检测使用的历史天数
+	AnomalyWindowDays uint `yaml:"anomalyWindowDays"`
+	// AnomalyZScore 当日费用偏离历史均值超过多少倍标准差时视为异常
+	AnomalyZScore float64 `yaml:"anomalyZScore"`
+	// AnomalyMinCost 当日费用高出历史均值的最小金额（账单原币种），避免小额波动触发告警
+	AnomalyMinCost float64 `yaml:"anomalyMinCost"`
+	Cmsi           CMSI    `yaml:"cmsi"`
+}
+
+func (bao *BillAlertOption) trySetDefault() {
+	if bao.SyncDuration == nil {
+		bao.SyncDuration = &defaultBillAlertSyncDuration
+	}
+	if bao.AnomalyWindowDays == 0 {
+		bao.AnomalyWindowDays = defaultAnomalyWindowDays
+	}
+	if bao.AnomalyZScore <= 0 {
+		bao.AnomalyZScore = defaultAnomalyZScore
+	}
+}
+
+// validate BillAlertOption
+func (bao *BillAlertOption) validate() error {
+	if !bao.Enable {
+		return nil
+	}
+
+	if bao.AnomalyMinCost < 0 {
+		return errors.New("bill alert anomalyMinCost should not be negative")
+	}
+
+	if err := bao.Cmsi.validate(); err != nil {
+		return fmt.Errorf("bill alert cmsi validate failed, err: %v", err)
+	}
+
+	return nil
+}
+
+var (
+	defaultExchangeRateSyncDuration = time.Hour
+	defaultExchangeRateTimeout      = 30 * time.Second
+	defaultExchangeRateMaxJumpRatio = 0.1
+	// 当月汇率在月内可能随每次同步小幅变化，默认每天最多重算一次当月账单汇总
+	defaultExchangeRateRecalculateInterval = 24 * time.Hour
+)
+
+// ExchangeRateProviderType 汇率数据源类型
+type ExchangeRateProviderType string
+
+const (
+	// ExchangeRateProviderHttpJson http接口返回json格式汇率
+	ExchangeRateProviderHttpJson ExchangeRateProviderType = "http_json"
+	// ExchangeRateProviderHttpCsv http接口返回csv格式汇率
+	ExchangeRateProviderHttpCsv ExchangeRateProviderType = "http_csv"
+	// ExchangeRateProviderStatic 配置文件中的固定汇率，用于本地调试
+	ExchangeRateProviderStatic ExchangeRateProviderType = "static"
+)
+
+// ExchangeRateOption 汇率自动同步配置，开启后定时从汇率数据源拉取当月及上月汇率，汇率变化时重新计算账单汇总的人民币费用
+type ExchangeRateOption struct {
+	Enable bool `yaml:"enable"`
+	// SyncDuration 拉取汇率的间隔
+	SyncDuration *time.Duration `yaml:"syncDuration,omitempty"`
+	// FromCurrencies 需要同步汇率的原币种，默认为USD
+	FromCurrencies []enumor.CurrencyCode `yaml:"fromCurrencies"`
+	// ToCurrency 目标币种，默认为CNY
+	ToCurrency enumor.CurrencyCode `yaml:"toCurrency"`
+	// MaxJumpRatio 与参考汇率相比允许的最大变化比例，超过时拒绝写入，避免数据源异常导致人民币费用错误
+	MaxJumpRatio float64 `yaml:"maxJumpRatio"`
+	// RecalculateInterval 当月汇率变化后重算当月账单汇总的最小间隔，间隔内的汇率变化合并到下次重算，上月汇率变化时立即重算
+	RecalculateInterval *time.Duration       `yaml:"recalculateInterval,omitempty"`
+	Provider            ExchangeRateProvider `yaml:"provider"`
+}
+
+func (ero *ExchangeRateOption) trySetDefault() {
+	if ero.SyncDuration == nil {
+		ero.SyncDuration = &defaultExchangeRateSyncDuration
+	}
+	if len(ero.FromCurrencies) == 0 {
+		ero.FromCurrencies = []enumor.CurrencyCode{enumor.CurrencyUSD}
+	}
+	if len(ero.ToCurrency) == 0 {
+		ero.ToCurrency = enumor.CurrencyRMB
+	}
+	if ero.MaxJumpRatio <= 0 {
+		ero.MaxJumpRatio = defaultExchangeRateMaxJumpRatio
+	}
+	if ero.RecalculateInterval == nil {
+		ero.RecalculateInterval = &defaultExchangeRateRecalculateInterval
+	}
+	if ero.Provider.Timeout == nil {
+		ero.Provider.Timeout = &defaultExchangeRateTimeout
+	}
+}
+
+// validate ExchangeRateOption
+func (ero *ExchangeRateOption) validate() error {
+	if !ero.Enable {
+		return nil
+	}
+
+	if err := ero.ToCurrency.Validate(); err != nil {
+		return err
+	}
+	for _, currency := range ero.FromCurrencies {
+		if err := currency.Validate(); err != nil {
+			return err
+		}
+		if currency == ero.ToCurrency {
+			return fmt.Errorf("exchange rate from currency should not be same as to currency: %s", currency)
+		}
+	}
+
+	if err := ero.Provider.validate(); err != nil {
+		return fmt.Errorf("exchange rate provider validate failed, err: %v", err)
+	}
+
+	return nil
+}
+
+// ExchangeRateProvider 汇率数据源配置
+type ExchangeRateProvider struct {
+	Type ExchangeRateProviderType `yaml:"type"`
+	// URL http数据源地址，支持{year}、{month}、{from}、{to}占位符
+	URL string `yaml:"url"`
+	// Headers 请求http数据源时附带的请求头，如鉴权信息
+	Headers map[string]string `yaml:"headers"`
+	Timeout *time.Duration    `yaml:"timeout,omitempty"`
+	// Rates static数据源的固定汇率，key为原币种
+	Rates map[enumor.CurrencyCode]float64 `yaml:"rates"`
+}
+
+// validate ExchangeRateProvider
+func (erp *ExchangeRateProvider) validate() error {
+	switch erp.Type {
+	case ExchangeRateProviderHttpJson, ExchangeRateProviderHttpCsv:
+		if len(erp.URL) == 0 {
+			return errors.New("url cannot be empty")
+		}
+	case ExchangeRateProviderStatic:
+		if len(erp.Rates) == 0 {
+			return errors.New("rates cannot be empty")
+		}
+	default:
+		return fmt.Errorf("unsupported exchange rate provider type: %s", erp.Type)
+	}
+	return nil
+}
+
 // CMSI cmsi config
 type CMSI struct {
 	CC         []string `yaml:"cc"`
@@ -0,0 +1,154 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dsadmission "hcm/pkg/api/data-service/admission"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// AdmissionPolicyClient is data service admission policy api client.
+type AdmissionPolicyClient struct {
+	client rest.ClientInterface
+}
+
+// NewAdmissionPolicyClient create a new admission policy api client.
+func NewAdmissionPolicyClient(client rest.ClientInterface) *AdmissionPolicyClient {
+	return &AdmissionPolicyClient{
+		client: client,
+	}
+}
+
+// Create admission policy.
+func (a *AdmissionPolicyClient) Create(kt *kit.Kit, req *dsadmission.CreateReq) (*core.CreateResult, error) {
+	resp := new(core.CreateResp)
+
+	err := a.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/admission_policies/create").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// Update admission policy.
+func (a *AdmissionPolicyClient) Update(kt *kit.Kit, id string, req *dsadmission.UpdateReq) error {
+	resp := new(rest.BaseResp)
+
+	err := a.client.Patch().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/admission_policies/%s", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// List admission policy.
+func (a *AdmissionPolicyClient) List(kt *kit.Kit, req *core.ListReq) (*dsadmission.ListResult, error) {
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *dsadmission.ListResult `json:"data"`
+	}{}
+
+	err := a.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/admission_policies/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// BatchDelete admission policy.
+func (a *AdmissionPolicyClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	resp := new(rest.BaseResp)
+
+	err := a.client.Delete().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/admission_policies/batch").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// RecordDecision record admission decisions as audits.
+func (a *AdmissionPolicyClient) RecordDecision(kt *kit.Kit, req *dsadmission.RecordDecisionReq) error {
+	resp := new(rest.BaseResp)
+
+	err := a.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/admission_policies/decisions/record").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
@@ -498,3 +498,89 @@ func (b *BillClient) ListBillSyncRecord(kt *kit.Kit, req *billproto.BillSyncReco
 	return common.Request[billproto.BillSyncRecordListReq, billproto.BillSyncRecordListResult](
 		b.client, rest.POST, kt, req, "/bills/sync_records/list")
 }
+
+// --- bill resource cost ---
+
+// BatchCreateBillResourceCost batch create bill resource cost
+func (b *BillClient) BatchCreateBillResourceCost(kt *kit.Kit, req *billproto.BatchBillResourceCostCreateReq) (
+	*core.BatchCreateResult, error) {
+	return common.Request[billproto.BatchBillResourceCostCreateReq, core.BatchCreateResult](
+		b.client, rest.POST, kt, req, "/bills/resource_costs/batch/create")
+}
+
+// BatchDeleteBillResourceCost delete bill resource cost
+func (b *BillClient) BatchDeleteBillResourceCost(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](
+		b.client, rest.DELETE, kt, req, "/bills/resource_costs")
+}
+
+// ListBillResourceCost list bill resource cost
+func (b *BillClient) ListBillResourceCost(kt *kit.Kit, req *billproto.BillResourceCostListReq) (
+	*billproto.BillResourceCostListResult, error) {
+	return common.Request[billproto.BillResourceCostListReq, billproto.BillResourceCostListResult](
+		b.client, rest.POST, kt, req, "/bills/resource_costs/list")
+}
+
+// ListBillResourceCostGroupByRes list bill resource cost summed by resource
+func (b *BillClient) ListBillResourceCostGroupByRes(kt *kit.Kit, req *billproto.BillResourceCostListReq) (
+	*billproto.BillResourceCostSumListResult, error) {
+	return common.Request[billproto.BillResourceCostListReq, billproto.BillResourceCostSumListResult](
+		b.client, rest.POST, kt, req, "/bills/resource_costs/list_group_by_res")
+}
+
+// CreateBillBudget create bill budget
+func (b *BillClient) CreateBillBudget(kt *kit.Kit, req *billproto.BillBudgetCreateReq) (*core.CreateResult, error) {
+	return common.Request[billproto.BillBudgetCreateReq, core.CreateResult](
+		b.client, rest.POST, kt, req, "/bills/budgets/create")
+}
+
+// UpdateBillBudget update bill budget
+func (b *BillClient) UpdateBillBudget(kt *kit.Kit, req *billproto.BillBudgetUpdateReq) error {
+	return common.RequestNoResp[billproto.BillBudgetUpdateReq](b.client, rest.PUT, kt, req, "/bills/budgets")
+}
+
+// ListBillBudget list bill budget
+func (b *BillClient) ListBillBudget(kt *kit.Kit, req *billproto.BillBudgetListReq) (
+	*billproto.BillBudgetListResult, error) {
+	return common.Request[billproto.BillBudgetListReq, billproto.BillBudgetListResult](
+		b.client, rest.POST, kt, req, "/bills/budgets/list")
+}
+
+// BatchDeleteBillBudget delete bill budget
+func (b *BillClient) BatchDeleteBillBudget(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](b.client, rest.DELETE, kt, req, "/bills/budgets")
+}
+
+// CreateBillAllocationRule create a new version of bill allocation rule
+func (b *BillClient) CreateBillAllocationRule(kt *kit.Kit, req *billproto.BillAllocationRuleCreateReq) (
+	*core.CreateResult, error) {
+	return common.Request[billproto.BillAllocationRuleCreateReq, core.CreateResult](
+		b.client, rest.POST, kt, req, "/bills/allocation_rules/create")
+}
+
+// ListBillAllocationRule list bill allocation rule
+func (b *BillClient) ListBillAllocationRule(kt *kit.Kit, req *billproto.BillAllocationRuleListReq) (
+	*billproto.BillAllocationRuleListResult, error) {
+	return common.Request[billproto.BillAllocationRuleListReq, billproto.BillAllocationRuleListResult](
+		b.client, rest.POST, kt, req, "/bills/allocation_rules/list")
+}
+
+// BatchDeleteBillAllocationRule delete bill allocation rule
+func (b *BillClient) BatchDeleteBillAllocationRule(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	return common.RequestNoResp[dataservice.BatchDeleteReq](b.client, rest.DELETE, kt, req,
+		"/bills/allocation_rules")
+}
+
+// BatchCreateBillAlert batch create bill alert
+func (b *BillClient) BatchCreateBillAlert(kt *kit.Kit, req *billproto.BatchBillAlertCreateReq) (
+	*core.BatchCreateResult, error) {
+	return common.Request[billproto.BatchBillAlertCreateReq, core.BatchCreateResult](
+		b.client, rest.POST, kt, req, "/bills/alerts/batch/create")
+}
+
+// ListBillAlert list bill alert
+func (b *BillClient) ListBillAlert(kt *kit.Kit, req *billproto.BillAlertListReq) (
+	*billproto.BillAlertListResult, error) {
+	return common.Request[billproto.BillAlertListReq, billproto.BillAlertListResult](
+		b.client, rest.POST, kt, req, "/bills/alerts/list")
+}
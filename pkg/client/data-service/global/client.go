@@ -41,6 +41,14 @@ type Client struct {
 	NetworkInterfaceCvmRel *NetworkInterfaceCvmRelClient
 	SubAccount             *SubAccountClient
 	AccountSyncDetail      *AccountSyncDetailClient
+	SyncRun                *SyncRunClient
+	ResTag                 *ResTagClient
+	DistributedLock        *DistributedLockClient
+	AdmissionPolicy        *AdmissionPolicyClient
+	RecyclePolicy          *RecyclePolicyClient
+	DiskSnapshot           *DiskSnapshotClient
+	DiskBackupPolicy       *DiskBackupPolicyClient
+	IPAMBlock              *IPAMBlockClient
 
 	Auth          *AuthClient
 	Account       *AccountClient
@@ -85,6 +93,14 @@ func NewClient(client rest.ClientInterface) *Client {
 		NetworkInterfaceCvmRel: NewNetworkInterfaceCvmRelClient(client),
 		SubAccount:             NewSubAccountClient(client),
 		AccountSyncDetail:      NewAccountSyncDetailClient(client),
+		SyncRun:                NewSyncRunClient(client),
+		ResTag:                 NewResTagClient(client),
+		DistributedLock:        NewDistributedLockClient(client),
+		AdmissionPolicy:        NewAdmissionPolicyClient(client),
+		RecyclePolicy:          NewRecyclePolicyClient(client),
+		DiskSnapshot:           NewDiskSnapshotClient(client),
+		DiskBackupPolicy:       NewDiskBackupPolicyClient(client),
+		IPAMBlock:              NewIPAMBlockClient(client),
 
 		Auth:          NewAuthClient(client),
 		Account:       NewAccountClient(client),
@@ -0,0 +1,201 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dsbackup "hcm/pkg/api/data-service/disk-backup"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// DiskBackupPolicyClient is data service disk backup policy api client.
+type DiskBackupPolicyClient struct {
+	client rest.ClientInterface
+}
+
+// NewDiskBackupPolicyClient create a new disk backup policy api client.
+func NewDiskBackupPolicyClient(client rest.ClientInterface) *DiskBackupPolicyClient {
+	return &DiskBackupPolicyClient{
+		client: client,
+	}
+}
+
+// Create disk backup policy.
+func (a *DiskBackupPolicyClient) Create(kt *kit.Kit, req *dsbackup.CreateReq) (*core.CreateResult, error) {
+	resp := new(core.CreateResp)
+
+	err := a.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disk_backup_policies/create").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// Update disk backup policy.
+func (a *DiskBackupPolicyClient) Update(kt *kit.Kit, id string, req *dsbackup.UpdateReq) error {
+	resp := new(rest.BaseResp)
+
+	err := a.client.Patch().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disk_backup_policies/%s", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// List disk backup policy.
+func (a *DiskBackupPolicyClient) List(kt *kit.Kit, req *core.ListReq) (*dsbackup.ListResult, error) {
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *dsbackup.ListResult `json:"data"`
+	}{}
+
+	err := a.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disk_backup_policies/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// BatchDelete disk backup policy.
+func (a *DiskBackupPolicyClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	resp := new(rest.BaseResp)
+
+	err := a.client.Delete().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disk_backup_policies/batch").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// CreateRun create disk backup run.
+func (a *DiskBackupPolicyClient) CreateRun(kt *kit.Kit, req *dsbackup.RunCreateReq) (*core.CreateResult, error) {
+	resp := new(core.CreateResp)
+
+	err := a.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disk_backup_runs/create").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// UpdateRun update disk backup run.
+func (a *DiskBackupPolicyClient) UpdateRun(kt *kit.Kit, id string, req *dsbackup.RunUpdateReq) error {
+	resp := new(rest.BaseResp)
+
+	err := a.client.Patch().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disk_backup_runs/%s", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// ListRun list disk backup run.
+func (a *DiskBackupPolicyClient) ListRun(kt *kit.Kit, req *core.ListReq) (*dsbackup.RunListResult, error) {
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *dsbackup.RunListResult `json:"data"`
+	}{}
+
+	err := a.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disk_backup_runs/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
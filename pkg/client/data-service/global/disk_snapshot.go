@@ -0,0 +1,134 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dssnapshot "hcm/pkg/api/data-service/cloud/disk-snapshot"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// DiskSnapshotClient is data service disk snapshot api client.
+type DiskSnapshotClient struct {
+	client rest.ClientInterface
+}
+
+// NewDiskSnapshotClient create a new disk snapshot api client.
+func NewDiskSnapshotClient(client rest.ClientInterface) *DiskSnapshotClient {
+	return &DiskSnapshotClient{
+		client: client,
+	}
+}
+
+// BatchCreate disk snapshot.
+func (a *DiskSnapshotClient) BatchCreate(kt *kit.Kit, req *dssnapshot.BatchCreateReq) (*core.BatchCreateResult,
+	error) {
+
+	resp := new(core.BatchCreateResp)
+
+	err := a.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disk_snapshots/batch/create").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// BatchUpdate disk snapshot.
+func (a *DiskSnapshotClient) BatchUpdate(kt *kit.Kit, req *dssnapshot.BatchUpdateReq) error {
+	resp := new(rest.BaseResp)
+
+	err := a.client.Patch().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disk_snapshots/batch/update").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// List disk snapshot.
+func (a *DiskSnapshotClient) List(kt *kit.Kit, req *core.ListReq) (*dssnapshot.ListResult, error) {
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *dssnapshot.ListResult `json:"data"`
+	}{}
+
+	err := a.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disk_snapshots/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// BatchDelete disk snapshot.
+func (a *DiskSnapshotClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	resp := new(rest.BaseResp)
+
+	err := a.client.Delete().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/disk_snapshots/batch").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	corelock "hcm/pkg/api/core/lock"
+	dslock "hcm/pkg/api/data-service/lock"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// DistributedLockClient is data service distributed lock api client.
+type DistributedLockClient struct {
+	client rest.ClientInterface
+}
+
+// NewDistributedLockClient create a new distributed lock api client.
+func NewDistributedLockClient(client rest.ClientInterface) *DistributedLockClient {
+	return &DistributedLockClient{
+		client: client,
+	}
+}
+
+// Acquire distributed lock, return errf.LockHeld error if the lock is held by others.
+func (d *DistributedLockClient) Acquire(kt *kit.Kit, req *dslock.AcquireReq) (*corelock.LockInfo, error) {
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *corelock.LockInfo `json:"data"`
+	}{}
+
+	err := d.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/distributed_locks/acquire").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// Renew distributed lock, return errf.LockLost error if the lock is lost.
+func (d *DistributedLockClient) Renew(kt *kit.Kit, req *dslock.RenewReq) error {
+	return d.post(kt, "/distributed_locks/renew", req)
+}
+
+// Release distributed lock.
+func (d *DistributedLockClient) Release(kt *kit.Kit, req *dslock.ReleaseReq) error {
+	return d.post(kt, "/distributed_locks/release", req)
+}
+
+// ForceRelease distributed lock.
+func (d *DistributedLockClient) ForceRelease(kt *kit.Kit, req *dslock.ForceReleaseReq) error {
+	return d.post(kt, "/distributed_locks/force_release", req)
+}
+
+// List distributed lock.
+func (d *DistributedLockClient) List(kt *kit.Kit, req *core.ListReq) (*dslock.ListResult, error) {
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *dslock.ListResult `json:"data"`
+	}{}
+
+	err := d.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/distributed_locks/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+func (d *DistributedLockClient) post(kt *kit.Kit, path string, req interface{}) error {
+	resp := new(rest.BaseResp)
+
+	err := d.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef(path).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
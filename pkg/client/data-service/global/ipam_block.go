@@ -0,0 +1,110 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dsipam "hcm/pkg/api/data-service/ipam"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// IPAMBlockClient is data service ipam block api client.
+type IPAMBlockClient struct {
+	client rest.ClientInterface
+}
+
+// NewIPAMBlockClient create a new ipam block api client.
+func NewIPAMBlockClient(client rest.ClientInterface) *IPAMBlockClient {
+	return &IPAMBlockClient{
+		client: client,
+	}
+}
+
+// BatchCreate ipam block.
+func (i *IPAMBlockClient) BatchCreate(kt *kit.Kit, req *dsipam.BatchCreateReq) (*core.BatchCreateResult, error) {
+	resp := new(core.BatchCreateResp)
+
+	err := i.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/ipam_blocks/batch/create").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// List ipam block.
+func (i *IPAMBlockClient) List(kt *kit.Kit, req *core.ListReq) (*dsipam.ListResult, error) {
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *dsipam.ListResult `json:"data"`
+	}{}
+
+	err := i.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/ipam_blocks/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// BatchDelete ipam block.
+func (i *IPAMBlockClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	resp := new(rest.BaseResp)
+
+	err := i.client.Delete().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/ipam_blocks/batch").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
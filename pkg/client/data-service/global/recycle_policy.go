@@ -0,0 +1,132 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dataservice "hcm/pkg/api/data-service"
+	dsrp "hcm/pkg/api/data-service/recycle-policy"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// RecyclePolicyClient is data service recycle policy api client.
+type RecyclePolicyClient struct {
+	client rest.ClientInterface
+}
+
+// NewRecyclePolicyClient create a new recycle policy api client.
+func NewRecyclePolicyClient(client rest.ClientInterface) *RecyclePolicyClient {
+	return &RecyclePolicyClient{
+		client: client,
+	}
+}
+
+// Create recycle policy.
+func (a *RecyclePolicyClient) Create(kt *kit.Kit, req *dsrp.CreateReq) (*core.CreateResult, error) {
+	resp := new(core.CreateResp)
+
+	err := a.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/recycle_policies/create").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// Update recycle policy.
+func (a *RecyclePolicyClient) Update(kt *kit.Kit, id string, req *dsrp.UpdateReq) error {
+	resp := new(rest.BaseResp)
+
+	err := a.client.Patch().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/recycle_policies/%s", id).
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// List recycle policy.
+func (a *RecyclePolicyClient) List(kt *kit.Kit, req *core.ListReq) (*dsrp.ListResult, error) {
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *dsrp.ListResult `json:"data"`
+	}{}
+
+	err := a.client.Post().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/recycle_policies/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// BatchDelete recycle policy.
+func (a *RecyclePolicyClient) BatchDelete(kt *kit.Kit, req *dataservice.BatchDeleteReq) error {
+	resp := new(rest.BaseResp)
+
+	err := a.client.Delete().
+		WithContext(kt.Ctx).
+		Body(req).
+		SubResourcef("/recycle_policies/batch").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
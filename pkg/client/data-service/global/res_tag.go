@@ -0,0 +1,131 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dstag "hcm/pkg/api/data-service/cloud/tag"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// ResTagClient is data service res_tag api client.
+type ResTagClient struct {
+	client rest.ClientInterface
+}
+
+// NewResTagClient create a new res_tag api client.
+func NewResTagClient(client rest.ClientInterface) *ResTagClient {
+	return &ResTagClient{
+		client: client,
+	}
+}
+
+// Sync replace resource tags with the tags synced from cloud.
+func (r *ResTagClient) Sync(kt *kit.Kit, request *dstag.ResTagSyncReq) error {
+	resp := new(rest.BaseResp)
+
+	err := r.client.Post().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/res_tags/sync").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// BatchUpsert ...
+func (r *ResTagClient) BatchUpsert(kt *kit.Kit, request *dstag.ResTagBatchUpsertReq) error {
+	resp := new(rest.BaseResp)
+
+	err := r.client.Post().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/res_tags/batch/upsert").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// BatchDelete ...
+func (r *ResTagClient) BatchDelete(kt *kit.Kit, request *dstag.ResTagBatchDeleteReq) error {
+	resp := new(rest.BaseResp)
+
+	err := r.client.Delete().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/res_tags/batch").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.Code != errf.OK {
+		return errf.New(resp.Code, resp.Message)
+	}
+
+	return nil
+}
+
+// List ...
+func (r *ResTagClient) List(kt *kit.Kit, request *core.ListReq) (*dstag.ResTagListResult, error) {
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *dstag.ResTagListResult `json:"data"`
+	}{}
+
+	err := r.client.Post().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/res_tags/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
@@ -0,0 +1,116 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package global
+
+import (
+	"hcm/pkg/api/core"
+	dssync "hcm/pkg/api/data-service/cloud/sync"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// SyncRunClient is data service sync_run api client.
+type SyncRunClient struct {
+	client rest.ClientInterface
+}
+
+// NewSyncRunClient create a new sync_run api client.
+func NewSyncRunClient(client rest.ClientInterface) *SyncRunClient {
+	return &SyncRunClient{
+		client: client,
+	}
+}
+
+// BatchCreate ...
+func (s *SyncRunClient) BatchCreate(kt *kit.Kit, request *dssync.SyncRunBatchCreateReq) (*core.BatchCreateResult,
+	error) {
+
+	resp := new(core.BatchCreateResp)
+
+	err := s.client.Post().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/sync_runs/batch/create").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// List ...
+func (s *SyncRunClient) List(kt *kit.Kit, request *core.ListReq) (*dssync.SyncRunListResult, error) {
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *dssync.SyncRunListResult `json:"data"`
+	}{}
+
+	err := s.client.Post().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/sync_runs/list").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
+
+// DeleteExpired ...
+func (s *SyncRunClient) DeleteExpired(kt *kit.Kit, request *dssync.SyncRunDeleteExpiredReq) (
+	*dssync.SyncRunDeleteExpiredResult, error) {
+
+	resp := &struct {
+		rest.BaseResp `json:",inline"`
+		Data          *dssync.SyncRunDeleteExpiredResult `json:"data"`
+	}{}
+
+	err := s.client.Delete().
+		WithContext(kt.Ctx).
+		Body(request).
+		SubResourcef("/sync_runs/expired").
+		WithHeaders(kt.Header()).
+		Do().
+		Into(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Code != errf.OK {
+		return nil, errf.New(resp.Code, resp.Message)
+	}
+
+	return resp.Data, nil
+}
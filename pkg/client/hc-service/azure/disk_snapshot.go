@@ -0,0 +1,56 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package azure
+
+import (
+	proto "hcm/pkg/api/hc-service/disk-snapshot"
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/client/common"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// DiskSnapshotClient is hc service disk snapshot api client.
+type DiskSnapshotClient struct {
+	client rest.ClientInterface
+}
+
+// NewDiskSnapshotClient create a new disk snapshot api client.
+func NewDiskSnapshotClient(client rest.ClientInterface) *DiskSnapshotClient {
+	return &DiskSnapshotClient{
+		client: client,
+	}
+}
+
+// CreateDiskSnapshot 创建硬盘快照，快照创建完成后返回
+func (cli *DiskSnapshotClient) CreateDiskSnapshot(kt *kit.Kit, req *proto.CreateReq) (*proto.CreateResult, error) {
+	return common.Request[proto.CreateReq, proto.CreateResult](cli.client, rest.POST, kt, req,
+		"/disk_snapshots/create")
+}
+
+// DeleteDiskSnapshot 删除硬盘快照
+func (cli *DiskSnapshotClient) DeleteDiskSnapshot(kt *kit.Kit, req *proto.DeleteReq) error {
+	return common.RequestNoResp[proto.DeleteReq](cli.client, rest.DELETE, kt, req, "/disk_snapshots")
+}
+
+// SyncDiskSnapshot 同步硬盘快照
+func (cli *DiskSnapshotClient) SyncDiskSnapshot(kt *kit.Kit, req *sync.AzureSyncReq) error {
+	return common.RequestNoResp[sync.AzureSyncReq](cli.client, rest.POST, kt, req, "/disk_snapshots/sync")
+}
@@ -0,0 +1,48 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package huawei
+
+import (
+	"hcm/pkg/api/hc-service/sync"
+	"hcm/pkg/client/common"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+	"hcm/pkg/rest"
+)
+
+// SyncClient is hc service sync api client.
+type SyncClient struct {
+	client rest.ClientInterface
+}
+
+// NewSyncClient create a new sync api client.
+func NewSyncClient(client rest.ClientInterface) *SyncClient {
+	return &SyncClient{
+		client: client,
+	}
+}
+
+// PlanSync 以计划模式同步资源，req 与对应资源的同步请求一致，只返回同步将产生的变更。
+func (cli *SyncClient) PlanSync(kt *kit.Kit, resType enumor.CloudResourceType, req *map[string]interface{}) (
+	*sync.PlanResult, error) {
+
+	return common.Request[map[string]interface{}, sync.PlanResult](cli.client, rest.POST, kt, req,
+		"/sync/plans/%s", resType)
+}
@@ -31,6 +31,7 @@ type Client struct {
 	Vpc           *VpcClient
 	Eip           *EipClient
 	Disk          *DiskClient
+	DiskSnapshot  *DiskSnapshotClient
 	Zone          *ZoneClient
 	Region        *RegionClient
 	Cvm           *CvmClient
@@ -42,6 +43,8 @@ type Client struct {
 	Cert          *CertClient
 	Clb           *ClbClient
 	BandPkg       *BandwidthPackageClient
+	Sync          *SyncClient
+	Tag           *TagClient
 }
 
 // NewClient create a new tcloud api client.
@@ -53,6 +56,7 @@ func NewClient(client rest.ClientInterface) *Client {
 		Vpc:           NewVpcClient(client),
 		Eip:           NewEipClient(client),
 		Disk:          NewCloudDiskClient(client),
+		DiskSnapshot:  NewDiskSnapshotClient(client),
 		Zone:          NewZoneClient(client),
 		Region:        NewRegionClient(client),
 		Cvm:           NewCvmClient(client),
@@ -64,5 +68,7 @@ func NewClient(client rest.ClientInterface) *Client {
 		Cert:          NewCertClient(client),
 		Clb:           NewClbClient(client),
 		BandPkg:       NewBandPkgClient(client),
+		Sync:          NewSyncClient(client),
+		Tag:           NewTagClient(client),
 	}
 }
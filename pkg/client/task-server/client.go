@@ -183,3 +183,44 @@ func (c *Client) RetryTask(kt *kit.Kit, flowID string, taskID string) error {
 	return common.RequestNoResp[common.Empty](c.client, rest.PATCH, kt, nil,
 		"/flows/%s/tasks/%s/retry", flowID, taskID)
 }
+
+// RegisterScheduledFlow 注册定时任务流，同名定时任务流已存在时进行更新
+func (c *Client) RegisterScheduledFlow(kt *kit.Kit, req *apits.RegisterScheduledFlowReq) (*core.CreateResult, error) {
+	return common.Request[apits.RegisterScheduledFlowReq, core.CreateResult](c.client, rest.POST, kt, req,
+		"/scheduled_flows/register")
+}
+
+// ListScheduledFlow 查询定时任务流
+func (c *Client) ListScheduledFlow(kt *kit.Kit, req *core.ListReq) (*apits.ListScheduledFlowResult, error) {
+	return common.Request[core.ListReq, apits.ListScheduledFlowResult](c.client, rest.POST, kt, req,
+		"/scheduled_flows/list")
+}
+
+// PauseScheduledFlow 暂停定时任务流
+func (c *Client) PauseScheduledFlow(kt *kit.Kit, id string) error {
+	return common.RequestNoResp[common.Empty](c.client, rest.PATCH, kt, nil, "/scheduled_flows/%s/pause", id)
+}
+
+// ResumeScheduledFlow 恢复定时任务流
+func (c *Client) ResumeScheduledFlow(kt *kit.Kit, id string) error {
+	return common.RequestNoResp[common.Empty](c.client, rest.PATCH, kt, nil, "/scheduled_flows/%s/resume", id)
+}
+
+// DeleteScheduledFlow 删除定时任务流
+func (c *Client) DeleteScheduledFlow(kt *kit.Kit, id string) error {
+	return common.RequestNoResp[common.Empty](c.client, rest.DELETE, kt, nil, "/scheduled_flows/%s", id)
+}
+
+// ListTaskLog 查询任务执行日志
+func (c *Client) ListTaskLog(kt *kit.Kit, taskID string, req *apits.ListTaskLogReq) (*apits.ListTaskLogResult,
+	error) {
+
+	return common.Request[apits.ListTaskLogReq, apits.ListTaskLogResult](c.client, rest.POST, kt, req,
+		"/tasks/%s/logs/list", taskID)
+}
+
+// ListTaskAttempt 查询任务执行记录
+func (c *Client) ListTaskAttempt(kt *kit.Kit, taskID string) (*apits.ListTaskAttemptResult, error) {
+	return common.Request[common.Empty, apits.ListTaskAttemptResult](c.client, rest.GET, kt, nil,
+		"/tasks/%s/attempts", taskID)
+}
@@ -0,0 +1,123 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// AdmissionMode is the mode of admission policy.
+type AdmissionMode string
+
+// Validate AdmissionMode.
+func (m AdmissionMode) Validate() error {
+	switch m {
+	case AdmissionDenyMode:
+	case AdmissionWarnMode:
+	default:
+		return fmt.Errorf("unsupported admission mode: %s", m)
+	}
+
+	return nil
+}
+
+const (
+	// AdmissionDenyMode 命中策略时拒绝请求
+	AdmissionDenyMode AdmissionMode = "deny"
+	// AdmissionWarnMode 命中策略时仅记录告警，请求继续执行
+	AdmissionWarnMode AdmissionMode = "warn"
+)
+
+// AdmissionAction is the resource operation which admission policy applies to.
+type AdmissionAction string
+
+// Validate AdmissionAction.
+func (a AdmissionAction) Validate() error {
+	switch a {
+	case AdmissionCreate:
+	case AdmissionUpdate:
+	default:
+		return fmt.Errorf("unsupported admission action: %s", a)
+	}
+
+	return nil
+}
+
+const (
+	// AdmissionCreate 资源创建
+	AdmissionCreate AdmissionAction = "create"
+	// AdmissionUpdate 资源修改
+	AdmissionUpdate AdmissionAction = "update"
+)
+
+// AdmissionResTypes is the resource types which support admission policy.
+var AdmissionResTypes = map[CloudResourceType]struct{}{
+	SecurityGroupRuleCloudResType: {},
+	CvmCloudResType:               {},
+	LoadBalancerCloudResType:      {},
+	EipCloudResType:               {},
+}
+
+// AdmissionResult is the decision result of admission policy.
+type AdmissionResult string
+
+const (
+	// AdmissionPass 策略未命中，请求放行
+	AdmissionPass AdmissionResult = "pass"
+	// AdmissionWarn 告警模式策略命中，请求放行
+	AdmissionWarn AdmissionResult = "warn"
+	// AdmissionDeny 拒绝模式策略命中，请求被拒绝
+	AdmissionDeny AdmissionResult = "deny"
+)
+
+// AdmissionOp is the operator of admission policy rule condition.
+type AdmissionOp string
+
+// Validate AdmissionOp.
+func (op AdmissionOp) Validate() error {
+	switch op {
+	case AdmissionEqual:
+	case AdmissionNotEqual:
+	case AdmissionIn:
+	case AdmissionNotIn:
+	case AdmissionPrefix:
+	case AdmissionNotPrefix:
+	case AdmissionPortCovers:
+	default:
+		return fmt.Errorf("unsupported admission rule operator: %s", op)
+	}
+
+	return nil
+}
+
+const (
+	// AdmissionEqual 字段值等于 value
+	AdmissionEqual AdmissionOp = "eq"
+	// AdmissionNotEqual 字段值不等于 value
+	AdmissionNotEqual AdmissionOp = "neq"
+	// AdmissionIn 字段值在 value 数组中
+	AdmissionIn AdmissionOp = "in"
+	// AdmissionNotIn 字段值不在 value 数组中
+	AdmissionNotIn AdmissionOp = "nin"
+	// AdmissionPrefix 字段值以 value 为前缀
+	AdmissionPrefix AdmissionOp = "prefix"
+	// AdmissionNotPrefix 字段值不以 value 为前缀
+	AdmissionNotPrefix AdmissionOp = "not_prefix"
+	// AdmissionPortCovers 字段值表示的端口范围(如 22、20-30、22,80、ALL)覆盖 value 端口
+	AdmissionPortCovers AdmissionOp = "port_covers"
+)
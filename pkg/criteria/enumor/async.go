@@ -39,6 +39,83 @@ const (
 	TaskSuccess TaskState = "success"
 	// TaskFailed task state is failed
 	TaskFailed TaskState = "failed"
+	// TaskSkipped task state is skipped（任务执行条件不满足时被跳过，视为执行完成）
+	TaskSkipped TaskState = "skipped"
+	// TaskCompensating task state is compensating（任务流失败后，执行成功的任务正在执行补偿动作）
+	TaskCompensating TaskState = "compensating"
+	// TaskCompensated task state is compensated（补偿动作执行成功）
+	TaskCompensated TaskState = "compensated"
+	// TaskCompensateFailed task state is compensate_failed（补偿动作执行失败）
+	TaskCompensateFailed TaskState = "compensate_failed"
+)
+
+// TaskKind is task kind.
+type TaskKind string
+
+// Validate TaskKind.
+func (v TaskKind) Validate() error {
+	switch v {
+	case TaskKindAction:
+	case TaskKindChildFlow:
+	default:
+		return fmt.Errorf("unsupported task kind: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// TaskKindAction task kind is action, 执行注册的Action.
+	TaskKindAction TaskKind = "action"
+	// TaskKindChildFlow task kind is child flow, 创建子任务流并等待其执行结束.
+	TaskKindChildFlow TaskKind = "child_flow"
+)
+
+// TaskLogKind is task log kind.
+type TaskLogKind string
+
+// Validate TaskLogKind.
+func (v TaskLogKind) Validate() error {
+	switch v {
+	case TaskLogKindLog:
+	case TaskLogKindAttempt:
+	default:
+		return fmt.Errorf("unsupported task log kind: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// TaskLogKindLog task log kind is log, Action执行过程中输出的日志.
+	TaskLogKindLog TaskLogKind = "log"
+	// TaskLogKindAttempt task log kind is attempt, 任务每次执行结束时记录的执行结果.
+	TaskLogKindAttempt TaskLogKind = "attempt"
+)
+
+// TaskLogLevel is task log level.
+type TaskLogLevel string
+
+// Validate TaskLogLevel.
+func (v TaskLogLevel) Validate() error {
+	switch v {
+	case TaskLogInfo:
+	case TaskLogWarn:
+	case TaskLogError:
+	default:
+		return fmt.Errorf("unsupported task log level: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// TaskLogInfo task log level is info
+	TaskLogInfo TaskLogLevel = "info"
+	// TaskLogWarn task log level is warn
+	TaskLogWarn TaskLogLevel = "warn"
+	// TaskLogError task log level is error
+	TaskLogError TaskLogLevel = "error"
 )
 
 // FlowState is flow state.
@@ -53,6 +130,8 @@ const (
 	FlowScheduled FlowState = "scheduled"
 	// FlowRunning flow state is running
 	FlowRunning FlowState = "running"
+	// FlowCompensating flow state is compensating（任务流失败后，正在对执行成功的任务进行补偿，补偿结束后置为失败）
+	FlowCompensating FlowState = "compensating"
 	// FlowCancel flow state is cancel
 	FlowCancel FlowState = "canceled"
 	// FlowSuccess flow state is success
@@ -61,6 +140,31 @@ const (
 	FlowFailed FlowState = "failed"
 )
 
+// FlowPriority is flow priority, 数值越大优先级越高，派发时高优先级的任务流优先派发。
+type FlowPriority int
+
+// Validate FlowPriority.
+func (v FlowPriority) Validate() error {
+	if v < FlowPriorityMin || v > FlowPriorityMax {
+		return fmt.Errorf("flow priority should be in [%d, %d], but got %d", FlowPriorityMin, FlowPriorityMax, v)
+	}
+
+	return nil
+}
+
+const (
+	// FlowPriorityMin flow priority min value
+	FlowPriorityMin FlowPriority = -100
+	// FlowPriorityLow flow priority is low
+	FlowPriorityLow FlowPriority = -10
+	// FlowPriorityNormal flow priority is normal, 未设置时的默认优先级
+	FlowPriorityNormal FlowPriority = 0
+	// FlowPriorityHigh flow priority is high
+	FlowPriorityHigh FlowPriority = 10
+	// FlowPriorityMax flow priority max value
+	FlowPriorityMax FlowPriority = 100
+)
+
 // BackendType is backend type.
 type BackendType string
 
@@ -68,6 +172,7 @@ type BackendType string
 func (v BackendType) Validate() error {
 	switch v {
 	case BackendMysql:
+	case BackendEtcd:
 	default:
 		return fmt.Errorf("unsupported backend type: %s", v)
 	}
@@ -78,4 +183,28 @@ func (v BackendType) Validate() error {
 const (
 	// BackendMysql mysql backend
 	BackendMysql BackendType = "mysql"
+	// BackendEtcd etcd backend
+	BackendEtcd BackendType = "etcd"
+)
+
+// ScheduledFlowState is scheduled flow state.
+type ScheduledFlowState string
+
+// Validate ScheduledFlowState.
+func (v ScheduledFlowState) Validate() error {
+	switch v {
+	case ScheduledFlowEnabled:
+	case ScheduledFlowPaused:
+	default:
+		return fmt.Errorf("unsupported scheduled flow state: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// ScheduledFlowEnabled scheduled flow state is enabled, it will be triggered by cron spec.
+	ScheduledFlowEnabled ScheduledFlowState = "enabled"
+	// ScheduledFlowPaused scheduled flow state is paused（该状态不参与触发）
+	ScheduledFlowPaused ScheduledFlowState = "paused"
 )
@@ -53,12 +53,17 @@ var defaultFlowNameMap = map[FlowName]struct{}{
 	FlowDeleteSecurityGroup:    {},
 	FlowCreateHuaweiSGRule:     {},
 	FlowDeleteEIP:              {},
+	FlowCreateDiskSnapshot:     {},
+	FlowDiskBackupPolicy:       {},
+	FlowDiskBackupRun:          {},
 	FlowPullRawBill:            {},
 	FlowSplitBill:              {},
 	FlowBillDailySummary:       {},
 	FlowBillMainAccountSummary: {},
 	FlowBillRootAccountSummary: {},
 	FlowBillMonthTask:          {},
+
+	FlowBillExchangeRateRecalculate: {},
 }
 
 // ValidateDefault validate default FlowName.
@@ -130,6 +135,16 @@ const (
 	FlowDeleteEIP FlowName = "delete_eip"
 )
 
+// DiskSnapshot 相关Flow
+const (
+	// FlowCreateDiskSnapshot ...
+	FlowCreateDiskSnapshot FlowName = "create_disk_snapshot"
+	// FlowDiskBackupPolicy 按备份策略定时为硬盘创建快照并清理过期快照
+	FlowDiskBackupPolicy FlowName = "disk_backup_policy"
+	// FlowDiskBackupRun 备份策略的一次执行，每块目标硬盘一个备份任务，全部结束后汇总执行结果
+	FlowDiskBackupRun FlowName = "disk_backup_run"
+)
+
 // Flow 相关Flow
 const (
 	// FlowLoadBalancerOperateWatch 负载均衡操作查询
@@ -158,4 +173,6 @@ const (
 	FlowBillMainAccountSummary FlowName = "bill_main_account_summary"
 	FlowBillRootAccountSummary FlowName = "bill_root_account_summary"
 	FlowBillMonthTask          FlowName = "bill_month_task"
+	// FlowBillExchangeRateRecalculate 汇率变化后重新计算账单汇总的人民币费用
+	FlowBillExchangeRateRecalculate FlowName = "bill_exchange_rate_recalculate"
 )
@@ -35,8 +35,10 @@ func (v ActionName) Validate() error {
 	case ActionDeleteSubnet:
 	case ActionDeleteSecurityGroup, ActionCreateHuaweiSGRule:
 	case ActionDeleteEIP:
+	case ActionCreateDiskSnapshot, ActionWaitDiskSnapshot, ActionRunDiskBackupPolicy, ActionBackupDisk,
+		ActionFinishDiskBackupRun:
 
-	case VirRoot:
+	case VirRoot, ActionRunChildFlow:
 	case ActionCreateFactoryTest, ActionProduceTest, ActionAssembleTest, ActionSleep:
 	case ActionTargetGroupAddRS, ActionTargetGroupRemoveRS, ActionTargetGroupModifyPort, ActionTargetGroupModifyWeight:
 	case ActionLoadBalancerOperateWatch:
@@ -81,6 +83,8 @@ const (
 const (
 	// VirRoot vir root
 	VirRoot ActionName = "root"
+	// ActionRunChildFlow 创建并等待子任务流执行结束，仅用于 child_flow 类型的任务
+	ActionRunChildFlow ActionName = "run_child_flow"
 
 	// ActionCreateFactoryTest 测试相关Action
 	ActionCreateFactoryTest ActionName = "create_factory"
@@ -101,6 +105,20 @@ const (
 	ActionDeleteEIP ActionName = "delete_eip"
 )
 
+// DiskSnapshot related action
+const (
+	// ActionCreateDiskSnapshot ...
+	ActionCreateDiskSnapshot ActionName = "create_disk_snapshot"
+	// ActionWaitDiskSnapshot 轮询等待硬盘快照创建完成
+	ActionWaitDiskSnapshot ActionName = "wait_disk_snapshot"
+	// ActionRunDiskBackupPolicy ...
+	ActionRunDiskBackupPolicy ActionName = "run_disk_backup_policy"
+	// ActionBackupDisk 按备份策略为单块硬盘创建快照并清理过期快照
+	ActionBackupDisk ActionName = "backup_disk"
+	// ActionFinishDiskBackupRun 汇总各硬盘的备份结果，结束备份执行记录
+	ActionFinishDiskBackupRun ActionName = "finish_disk_backup_run"
+)
+
 // Flow相关Action
 const (
 	ActionLoadBalancerOperateWatch ActionName = "load_balancer_operate_watch"
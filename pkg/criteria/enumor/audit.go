@@ -50,6 +50,10 @@ const (
 	UrlRuleDomainAuditResType     AuditResourceType = "url_rule_domain"
 	MainAccountAuditResType       AuditResourceType = "main_account"
 	RootAccountAuditResType       AuditResourceType = "root_account"
+	AdmissionPolicyAuditResType   AuditResourceType = "admission_policy"
+	RecyclePolicyAuditResType     AuditResourceType = "recycle_policy"
+	DiskBackupPolicyAuditResType  AuditResourceType = "disk_backup_policy"
+	DiskSnapshotAuditResType      AuditResourceType = "disk_snapshot"
 )
 
 // AuditResourceTypeEnums resource type map.
@@ -75,6 +79,10 @@ var AuditResourceTypeEnums = map[AuditResourceType]struct{}{
 	UrlRuleDomainAuditResType:     {},
 	MainAccountAuditResType:       {},
 	RootAccountAuditResType:       {},
+	AdmissionPolicyAuditResType:   {},
+	RecyclePolicyAuditResType:     {},
+	DiskBackupPolicyAuditResType:  {},
+	DiskSnapshotAuditResType:      {},
 }
 
 // Exist judge enum value exist.
@@ -115,6 +123,10 @@ const (
 	Bind AuditAction = "bind"
 	// Deliver 交付
 	Deliver AuditAction = "deliver"
+	// Admission 准入策略校验
+	Admission AuditAction = "admission"
+	// Rollback 回滚
+	Rollback AuditAction = "rollback"
 )
 
 // AuditActionEnums op type map.
@@ -133,6 +145,8 @@ var AuditActionEnums = map[AuditAction]struct{}{
 	Disassociate: {},
 	Bind:         {},
 	Deliver:      {},
+	Admission:    {},
+	Rollback:     {},
 }
 
 // Exist judge enum value exist.
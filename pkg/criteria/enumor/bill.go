@@ -84,8 +84,28 @@ const (
 	CurrencyCNY CurrencyCode = "CNY"
 	// CurrencyRMB rmb currency
 	CurrencyRMB = CurrencyCNY
+	// CurrencyEUR euro currency
+	CurrencyEUR CurrencyCode = "EUR"
+	// CurrencyGBP british pound currency
+	CurrencyGBP CurrencyCode = "GBP"
+	// CurrencyHKD hong kong dollar currency
+	CurrencyHKD CurrencyCode = "HKD"
+	// CurrencyJPY japanese yen currency
+	CurrencyJPY CurrencyCode = "JPY"
+	// CurrencySGD singapore dollar currency
+	CurrencySGD CurrencyCode = "SGD"
 )
 
+// Validate CurrencyCode.
+func (c CurrencyCode) Validate() error {
+	switch c {
+	case CurrencyUSD, CurrencyCNY, CurrencyEUR, CurrencyGBP, CurrencyHKD, CurrencyJPY, CurrencySGD:
+	default:
+		return fmt.Errorf("unsupported currency code: %s", c)
+	}
+	return nil
+}
+
 // BillAdjustmentType 调账类型
 type BillAdjustmentType string
 
@@ -250,6 +270,76 @@ const (
 // MonthTaskSpecialBillDay special bill day 0 to represent the whole month
 const MonthTaskSpecialBillDay = 0
 
+// BillBudgetScopeType 预算范围类型
+type BillBudgetScopeType string
+
+const (
+	// BillBudgetScopeMainAccountBiz 二级账号所属业务，统计所属业务下二级账号的全部费用
+	BillBudgetScopeMainAccountBiz BillBudgetScopeType = "main_account_biz"
+	// BillBudgetScopeMainAccount 二级账号
+	BillBudgetScopeMainAccount BillBudgetScopeType = "main_account"
+	// BillBudgetScopeRootAccount 一级账号
+	BillBudgetScopeRootAccount BillBudgetScopeType = "root_account"
+	// BillBudgetScopeProduct 运营产品
+	BillBudgetScopeProduct BillBudgetScopeType = "product"
+)
+
+// Validate BillBudgetScopeType.
+func (s BillBudgetScopeType) Validate() error {
+	switch s {
+	case BillBudgetScopeMainAccountBiz, BillBudgetScopeMainAccount, BillBudgetScopeRootAccount, BillBudgetScopeProduct:
+	default:
+		return fmt.Errorf("unsupported budget scope type: %s", s)
+	}
+	return nil
+}
+
+// BillAlertType 账单告警类型
+type BillAlertType string
+
+const (
+	// BillAlertBudget 预算超出阈值告警
+	BillAlertBudget BillAlertType = "budget"
+	// BillAlertAnomaly 日账单费用异常告警
+	BillAlertAnomaly BillAlertType = "anomaly"
+)
+
+// BillAlertNotifyState 账单告警通知状态
+type BillAlertNotifyState string
+
+const (
+	// BillAlertNotifySuccess 通知成功
+	BillAlertNotifySuccess BillAlertNotifyState = "success"
+	// BillAlertNotifyFailed 通知失败
+	BillAlertNotifyFailed BillAlertNotifyState = "failed"
+	// BillAlertNotifySkipped 无接收人，未通知
+	BillAlertNotifySkipped BillAlertNotifyState = "skipped"
+)
+
+// BillAllocationRuleType 分账规则类型
+type BillAllocationRuleType string
+
+const (
+	// BillAllocationByRatio 按固定比例分摊到多个业务
+	BillAllocationByRatio BillAllocationRuleType = "ratio"
+	// BillAllocationByTag 按资源标签值分摊到对应业务
+	BillAllocationByTag BillAllocationRuleType = "tag"
+	// BillAllocationByOwnership 按资源所属业务分摊
+	BillAllocationByOwnership BillAllocationRuleType = "ownership"
+	// BillAllocationByUsage 按资源所属业务分摊，无法归属的共享费用按各业务当天用量占比分摊
+	BillAllocationByUsage BillAllocationRuleType = "usage"
+)
+
+// Validate BillAllocationRuleType.
+func (t BillAllocationRuleType) Validate() error {
+	switch t {
+	case BillAllocationByRatio, BillAllocationByTag, BillAllocationByOwnership, BillAllocationByUsage:
+	default:
+		return fmt.Errorf("unsupported bill allocation rule type: %s", t)
+	}
+	return nil
+}
+
 var (
 	// BillAdjustmentStateNameMap is the map of bill adjustment state name
 	BillAdjustmentStateNameMap = map[BillAdjustmentState]string{
@@ -37,6 +37,7 @@ var typeMapping = map[CloudResourceType]table.Name{
 	SubnetCloudResType:           table.SubnetTable,
 	EipCloudResType:              table.EipTable,
 	DiskCloudResType:             table.DiskTable,
+	DiskSnapshotCloudResType:     table.DiskSnapshotTable,
 	CvmCloudResType:              table.CvmTable,
 	RouteTableCloudResType:       table.RouteTableTable,
 	NetworkInterfaceCloudResType: table.NetworkInterfaceTable,
@@ -66,26 +67,29 @@ func (rt CloudResourceType) ConvTableName() (table.Name, error) {
 
 // CloudResourceType define all cloud resource type.
 const (
-	AccountCloudResType          CloudResourceType = "account"
-	SubAccountCloudResType       CloudResourceType = "sub_account"
-	SecurityGroupCloudResType    CloudResourceType = "security_group"
-	GcpFirewallRuleCloudResType  CloudResourceType = "gcp_firewall_rule"
-	VpcCloudResType              CloudResourceType = "vpc"
-	SubnetCloudResType           CloudResourceType = "subnet"
-	EipCloudResType              CloudResourceType = "eip"
-	CvmCloudResType              CloudResourceType = "cvm"
-	DiskCloudResType             CloudResourceType = "disk"
-	RouteTableCloudResType       CloudResourceType = "route_table"
-	RouteCloudResType            CloudResourceType = "route"
-	NetworkInterfaceCloudResType CloudResourceType = "network_interface"
-	RegionCloudResType           CloudResourceType = "region"
-	ImageCloudResType            CloudResourceType = "image"
-	ZoneCloudResType             CloudResourceType = "zone"
-	AzureResourceGroup           CloudResourceType = "azure_resource_group"
-	ArgumentTemplateResType      CloudResourceType = "argument_template"
-	CertCloudResType             CloudResourceType = "cert"
-	LoadBalancerCloudResType     CloudResourceType = "load_balancer"
-	ListenerCloudResType         CloudResourceType = "listener"
-	TargetGroupCloudResType      CloudResourceType = "target_group"
-	TCLoudUrlRuleCloudResType    CloudResourceType = "tcloud_url_rule"
+	AccountCloudResType           CloudResourceType = "account"
+	SubAccountCloudResType        CloudResourceType = "sub_account"
+	SecurityGroupCloudResType     CloudResourceType = "security_group"
+	SecurityGroupRuleCloudResType CloudResourceType = "security_group_rule"
+	GcpFirewallRuleCloudResType   CloudResourceType = "gcp_firewall_rule"
+	VpcCloudResType               CloudResourceType = "vpc"
+	SubnetCloudResType            CloudResourceType = "subnet"
+	EipCloudResType               CloudResourceType = "eip"
+	CvmCloudResType               CloudResourceType = "cvm"
+	DiskCloudResType              CloudResourceType = "disk"
+	DiskSnapshotCloudResType      CloudResourceType = "disk_snapshot"
+	RouteTableCloudResType        CloudResourceType = "route_table"
+	RouteCloudResType             CloudResourceType = "route"
+	NetworkInterfaceCloudResType  CloudResourceType = "network_interface"
+	RegionCloudResType            CloudResourceType = "region"
+	ImageCloudResType             CloudResourceType = "image"
+	ZoneCloudResType              CloudResourceType = "zone"
+	AzureResourceGroup            CloudResourceType = "azure_resource_group"
+	ArgumentTemplateResType       CloudResourceType = "argument_template"
+	CertCloudResType              CloudResourceType = "cert"
+	LoadBalancerCloudResType      CloudResourceType = "load_balancer"
+	ListenerCloudResType          CloudResourceType = "listener"
+	TargetGroupCloudResType       CloudResourceType = "target_group"
+	TargetCloudResType            CloudResourceType = "target"
+	TCLoudUrlRuleCloudResType     CloudResourceType = "tcloud_url_rule"
 )
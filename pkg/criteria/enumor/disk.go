@@ -39,3 +39,53 @@ const (
 	// DiskBindCvm disk bind cvm
 	DiskBindCvm DiskBindType = "CVM"
 )
+
+// DiskBackupRunState is the state of disk backup policy run.
+type DiskBackupRunState string
+
+// Validate DiskBackupRunState.
+func (v DiskBackupRunState) Validate() error {
+	switch v {
+	case DiskBackupRunning:
+	case DiskBackupSuccess:
+	case DiskBackupPartialFailed:
+	case DiskBackupFailed:
+	default:
+		return fmt.Errorf("unsupported disk backup run state: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// DiskBackupRunning 备份执行中
+	DiskBackupRunning DiskBackupRunState = "running"
+	// DiskBackupSuccess 所有目标硬盘均备份成功
+	DiskBackupSuccess DiskBackupRunState = "success"
+	// DiskBackupPartialFailed 部分硬盘备份或过期快照清理失败
+	DiskBackupPartialFailed DiskBackupRunState = "partial_failed"
+	// DiskBackupFailed 备份执行失败
+	DiskBackupFailed DiskBackupRunState = "failed"
+)
+
+// DiskBackupTrigger is the trigger of disk backup policy run.
+type DiskBackupTrigger string
+
+// Validate DiskBackupTrigger.
+func (v DiskBackupTrigger) Validate() error {
+	switch v {
+	case TimingDiskBackupTrigger:
+	case ManualDiskBackupTrigger:
+	default:
+		return fmt.Errorf("unsupported disk backup trigger: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// TimingDiskBackupTrigger 按备份策略的cron表达式定时触发
+	TimingDiskBackupTrigger DiskBackupTrigger = "timing"
+	// ManualDiskBackupTrigger 用户手动触发
+	ManualDiskBackupTrigger DiskBackupTrigger = "manual"
+)
@@ -0,0 +1,45 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+import "fmt"
+
+// IPAMBlockType is ipam block type.
+type IPAMBlockType string
+
+// Validate IPAMBlockType.
+func (t IPAMBlockType) Validate() error {
+	switch t {
+	case IPAMVpcBlock, IPAMSubnetBlock, IPAMReservedBlock:
+	default:
+		return fmt.Errorf("unsupported ipam block type: %s", t)
+	}
+
+	return nil
+}
+
+const (
+	// IPAMVpcBlock 从已同步的vpc网段索引的地址块
+	IPAMVpcBlock IPAMBlockType = "vpc"
+	// IPAMSubnetBlock 从已同步的子网网段索引的地址块
+	IPAMSubnetBlock IPAMBlockType = "subnet"
+	// IPAMReservedBlock 手动预留的地址块，用于规划尚未创建的vpc
+	IPAMReservedBlock IPAMBlockType = "reserved"
+)
@@ -42,8 +42,18 @@ const (
 
 // RecycleAuditResTypeMap recycle resource audit type to cloud resource type map.
 var RecycleAuditResTypeMap = map[AuditResourceType]CloudResourceType{
-	CvmAuditResType:  CvmCloudResType,
-	DiskAuditResType: DiskCloudResType,
+	CvmAuditResType:          CvmCloudResType,
+	DiskAuditResType:         DiskCloudResType,
+	EipAuditResType:          EipCloudResType,
+	LoadBalancerAuditResType: LoadBalancerCloudResType,
+}
+
+// RecyclePolicyResTypes 支持配置回收策略的资源类型
+var RecyclePolicyResTypes = map[CloudResourceType]struct{}{
+	CvmCloudResType:          {},
+	DiskCloudResType:         {},
+	EipCloudResType:          {},
+	LoadBalancerCloudResType: {},
 }
 
 // RecycleType 回收类型
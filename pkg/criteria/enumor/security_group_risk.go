@@ -0,0 +1,56 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package enumor
+
+// SGRuleAction 归一化后的安全组规则动作
+type SGRuleAction string
+
+const (
+	// SGRuleAccept 放通
+	SGRuleAccept SGRuleAction = "accept"
+	// SGRuleDrop 拒绝
+	SGRuleDrop SGRuleAction = "drop"
+)
+
+// SGRiskType 安全组风险类型
+type SGRiskType string
+
+const (
+	// WorldOpenAdminPort 管理类端口(如22、3389、3306)对公网开放
+	WorldOpenAdminPort SGRiskType = "world_open_admin_port"
+	// ShadowedSGRule 规则被更高优先级且动作相反的规则完全覆盖，永远不会生效
+	ShadowedSGRule SGRiskType = "shadowed_rule"
+	// RedundantSGRule 规则被更高优先级且动作相同的规则完全覆盖，属于冗余规则
+	RedundantSGRule SGRiskType = "redundant_rule"
+	// UnusedSecurityGroup 安全组未关联任何主机
+	UnusedSecurityGroup SGRiskType = "unused_security_group"
+)
+
+// SGRiskLevel 安全组风险等级
+type SGRiskLevel string
+
+const (
+	// SGRiskHigh 高风险
+	SGRiskHigh SGRiskLevel = "high"
+	// SGRiskMedium 中风险
+	SGRiskMedium SGRiskLevel = "medium"
+	// SGRiskLow 低风险
+	SGRiskLow SGRiskLevel = "low"
+)
@@ -47,3 +47,28 @@ const (
 	// Syncing status
 	Syncing SyncStatus = "syncing"
 )
+
+// SyncTrigger is the trigger of a sync run.
+type SyncTrigger string
+
+// Validate SyncTrigger.
+func (v SyncTrigger) Validate() error {
+	switch v {
+	case TimingSyncTrigger:
+	case ManualSyncTrigger:
+	case EventSyncTrigger:
+	default:
+		return fmt.Errorf("unsupported sync trigger: %s", v)
+	}
+
+	return nil
+}
+
+const (
+	// TimingSyncTrigger 定时同步
+	TimingSyncTrigger SyncTrigger = "timing"
+	// ManualSyncTrigger 用户手动触发同步
+	ManualSyncTrigger SyncTrigger = "manual"
+	// EventSyncTrigger 云上变更事件触发的增量同步
+	EventSyncTrigger SyncTrigger = "event"
+)
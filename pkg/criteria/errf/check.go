@@ -58,6 +58,27 @@ func IsRecordNotFound(err error) bool {
 	return false
 }
 
+// IsLockHeld return true if error is a lock held error
+func IsLockHeld(err error) bool {
+	return isCode(err, LockHeld)
+}
+
+// IsLockLost return true if error is a lock lost error
+func IsLockLost(err error) bool {
+	return isCode(err, LockLost)
+}
+
+func isCode(err error, code int32) bool {
+	if err == nil {
+		return false
+	}
+	var ef *ErrorF
+	if errors.As(err, &ef) {
+		return ef.Code == code
+	}
+	return false
+}
+
 // IsContextCanceled return true if error contains string "context canceled"
 func IsContextCanceled(err error) bool {
 	if err == nil {
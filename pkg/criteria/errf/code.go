@@ -72,4 +72,14 @@ const (
 	BillItemImportDataError int32 = 2000016
 	// BillItemImportEmptyDataError 账单导入空列表
 	BillItemImportEmptyDataError int32 = 2000017
+	// LockHeld 分布式锁已被其他持有者占用
+	LockHeld int32 = 2000018
+	// LockLost 分布式锁已过期或被强制释放，当前持有者已失去该锁
+	LockLost int32 = 2000019
+	// AdmissionDenied 请求被拒绝模式的准入策略拦截
+	AdmissionDenied int32 = 2000020
+	// CidrConflict 网段与同一范围内已有的vpc、子网或预留地址块冲突
+	CidrConflict int32 = 2000021
+	// ResourceHasDependents 资源仍被其他资源依赖，无法删除
+	ResourceHasDependents int32 = 2000022
 )
@@ -261,7 +261,12 @@ func (dao *AsyncFlowDao) ListWithTx(kt *kit.Kit, tx *sqlx.Tx,
 		return &typesasync.ListAsyncFlows{Count: count}, nil
 	}
 
-	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	pageOpt, err := flowPageSQLOption(opt.ThenBy)
+	if err != nil {
+		return nil, err
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, pageOpt)
 	if err != nil {
 		return nil, err
 	}
@@ -309,7 +314,12 @@ func (dao *AsyncFlowDao) List(kt *kit.Kit, opt *types.ListOption) (*typesasync.L
 		return &typesasync.ListAsyncFlows{Count: count}, nil
 	}
 
-	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	pageOpt, err := flowPageSQLOption(opt.ThenBy)
+	if err != nil {
+		return nil, err
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, pageOpt)
 	if err != nil {
 		return nil, err
 	}
@@ -346,3 +356,19 @@ func (dao *AsyncFlowDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, filterExpr *filt
 
 	return nil
 }
+
+// flowPageSQLOption 返回任务流分页查询的排序选项，次级排序字段需为任务流表的字段
+func flowPageSQLOption(thenBy []types.OrderBy) (*types.PageSQLOption, error) {
+	if len(thenBy) == 0 {
+		return types.DefaultPageSQLOption, nil
+	}
+
+	columnTypes := tableasync.AsyncFlowColumns.ColumnTypes()
+	for _, one := range thenBy {
+		if _, exist := columnTypes[one.Sort]; !exist {
+			return nil, errf.Newf(errf.InvalidParameter, "then by field %s is not supported", one.Sort)
+		}
+	}
+
+	return &types.PageSQLOption{Sort: types.DefaultPageSQLOption.Sort, ThenBy: thenBy}, nil
+}
@@ -0,0 +1,279 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package daoasync
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typesasync "hcm/pkg/dal/dao/types/async"
+	"hcm/pkg/dal/table"
+	tableasync "hcm/pkg/dal/table/async"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AsyncScheduledFlow only used async scheduled flow.
+type AsyncScheduledFlow interface {
+	CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, model *tableasync.AsyncScheduledFlowTable) (string, error)
+	UpdateByIDWithTx(kt *kit.Kit, tx *sqlx.Tx, id string, model *tableasync.AsyncScheduledFlowTable) error
+	UpdateTriggerByCAS(kt *kit.Kit, tx *sqlx.Tx, info *typesasync.TriggerScheduledFlowInfo) error
+	List(kt *kit.Kit, opt *types.ListOption) (*typesasync.ListAsyncScheduledFlows, error)
+	ListWithTx(kt *kit.Kit, tx *sqlx.Tx, opt *types.ListOption) (*typesasync.ListAsyncScheduledFlows, error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
+}
+
+var _ AsyncScheduledFlow = new(AsyncScheduledFlowDao)
+
+// AsyncScheduledFlowDao async scheduled flow dao.
+type AsyncScheduledFlowDao struct {
+	Orm   orm.Interface
+	IDGen idgenerator.IDGenInterface
+}
+
+// CreateWithTx async scheduled flow with tx.
+func (dao *AsyncScheduledFlowDao) CreateWithTx(kt *kit.Kit, tx *sqlx.Tx,
+	model *tableasync.AsyncScheduledFlowTable) (string, error) {
+
+	id, err := dao.IDGen.One(kt, table.AsyncScheduledFlowTable)
+	if err != nil {
+		return "", err
+	}
+	model.ID = id
+
+	if err = model.InsertValidate(); err != nil {
+		return "", err
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.AsyncScheduledFlowTable,
+		tableasync.AsyncScheduledFlowColumns.ColumnExpr(), tableasync.AsyncScheduledFlowColumns.ColonNameExpr())
+
+	if err = dao.Orm.Txn(tx).BulkInsert(kt.Ctx, sql, model); err != nil {
+		logs.Errorf("insert %s failed, err: %v, sql: %s, rid: %s", table.AsyncScheduledFlowTable, err, sql, kt.Rid)
+		return "", fmt.Errorf("insert %s failed, err: %v", table.AsyncScheduledFlowTable, err)
+	}
+
+	return id, nil
+}
+
+// UpdateByIDWithTx async scheduled flow.
+func (dao *AsyncScheduledFlowDao) UpdateByIDWithTx(kt *kit.Kit, tx *sqlx.Tx, id string,
+	model *tableasync.AsyncScheduledFlowTable) error {
+
+	if len(id) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return err
+	}
+
+	opts := utils.NewFieldOptions().AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s where id = :id`, model.TableName(), setExpr)
+
+	toUpdate["id"] = id
+	effected, err := dao.Orm.Txn(tx).Update(kt.Ctx, sql, toUpdate)
+	if err != nil {
+		logs.Errorf("update async scheduled flow failed, err: %v, id: %s, sql: %s, rid: %v", err, id,
+			sql, kt.Rid)
+		return err
+	}
+
+	if effected == 0 {
+		return errf.New(errf.RecordNotUpdate, "record not update")
+	}
+
+	return nil
+}
+
+// UpdateTriggerByCAS update async scheduled flow trigger info by CAS, only enabled scheduled flow whose
+// next_trigger_at equals to source can be updated, so that one tick can only be triggered once.
+func (dao *AsyncScheduledFlowDao) UpdateTriggerByCAS(kt *kit.Kit, tx *sqlx.Tx,
+	info *typesasync.TriggerScheduledFlowInfo) error {
+
+	if err := info.Validate(); err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`update %s set next_trigger_at = :target, last_triggered_at = :triggered_at, `+
+		`last_flow_id = :flow_id where id = :id and state = :state and next_trigger_at = :source`,
+		table.AsyncScheduledFlowTable)
+
+	whereValue := map[string]interface{}{
+		"id":           info.ID,
+		"state":        enumor.ScheduledFlowEnabled,
+		"source":       info.Source,
+		"target":       info.Target,
+		"triggered_at": info.TriggeredAt,
+		"flow_id":      info.FlowID,
+	}
+	effected, err := dao.Orm.Txn(tx).Update(kt.Ctx, sql, whereValue)
+	if err != nil {
+		logs.Errorf("update async scheduled flow trigger failed, err: %v, id: %s, sql: %s, rid: %v", err, info.ID,
+			sql, kt.Rid)
+		return err
+	}
+
+	if effected == 0 {
+		return errf.Newf(errf.RecordNotUpdate, "scheduled flow[%s] update next_trigger_at: `%s`->`%s` failed",
+			info.ID, info.Source, info.Target)
+	}
+
+	return nil
+}
+
+// ListWithTx async scheduled flow with tx.
+func (dao *AsyncScheduledFlowDao) ListWithTx(kt *kit.Kit, tx *sqlx.Tx,
+	opt *types.ListOption) (*typesasync.ListAsyncScheduledFlows, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list async scheduled flow options is nil")
+	}
+
+	columnTypes := tableasync.AsyncScheduledFlowColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is dao count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.AsyncScheduledFlowTable, whereExpr)
+
+		count, err := dao.Orm.Txn(tx).Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count async scheduled flow failed, err: %v, filter: %s, rid: %s", err,
+				opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typesasync.ListAsyncScheduledFlows{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`,
+		tableasync.AsyncScheduledFlowColumns.FieldsNamedExpr(opt.Fields), table.AsyncScheduledFlowTable,
+		whereExpr, pageExpr)
+
+	details := make([]tableasync.AsyncScheduledFlowTable, 0)
+	if err = dao.Orm.Txn(tx).Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select async scheduled flow failed, err: %v, sql: %s, filter: %v, rid: %s", err, sql,
+			opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typesasync.ListAsyncScheduledFlows{Count: 0, Details: details}, nil
+}
+
+// List async scheduled flow.
+func (dao *AsyncScheduledFlowDao) List(kt *kit.Kit, opt *types.ListOption) (
+	*typesasync.ListAsyncScheduledFlows, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list async scheduled flow options is nil")
+	}
+
+	columnTypes := tableasync.AsyncScheduledFlowColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is dao count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.AsyncScheduledFlowTable, whereExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count async scheduled flow failed, err: %v, filter: %s, rid: %s", err,
+				opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typesasync.ListAsyncScheduledFlows{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`,
+		tableasync.AsyncScheduledFlowColumns.FieldsNamedExpr(opt.Fields), table.AsyncScheduledFlowTable,
+		whereExpr, pageExpr)
+
+	details := make([]tableasync.AsyncScheduledFlowTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select async scheduled flow failed, err: %v, sql: %s, filter: %v, rid: %s", err, sql,
+			opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typesasync.ListAsyncScheduledFlows{Count: 0, Details: details}, nil
+}
+
+// DeleteWithTx async scheduled flow with tx.
+func (dao *AsyncScheduledFlowDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, filterExpr *filter.Expression) error {
+	if filterExpr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := filterExpr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.AsyncScheduledFlowTable, whereExpr)
+	if _, err = dao.Orm.Txn(tx).Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.ErrorJson("delete async scheduled flow failed, err: %v, filter: %s, rid: %s", err, filterExpr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typesbill "hcm/pkg/dal/dao/types/bill"
+	"hcm/pkg/dal/table"
+	tablebill "hcm/pkg/dal/table/bill"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AccountBillResourceCost only used for interface.
+type AccountBillResourceCost interface {
+	CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []*tablebill.AccountBillResourceCost) ([]string, error)
+	List(kt *kit.Kit, opt *types.ListOption) (*typesbill.ListAccountBillResourceCostDetails, error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, filterExpr *filter.Expression) error
+	ListGroupByResource(kt *kit.Kit, opt *types.ListOption) (*typesbill.ListAccountBillResourceCostSumDetails, error)
+}
+
+var _ AccountBillResourceCost = (*AccountBillResourceCostDao)(nil)
+
+// AccountBillResourceCostDao account bill resource cost dao
+type AccountBillResourceCostDao struct {
+	Orm   orm.Interface
+	IDGen idgenerator.IDGenInterface
+}
+
+// CreateWithTx create account bill resource cost with tx.
+func (a AccountBillResourceCostDao) CreateWithTx(kt *kit.Kit, tx *sqlx.Tx,
+	models []*tablebill.AccountBillResourceCost) ([]string, error) {
+
+	if len(models) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "models to create cannot be empty")
+	}
+
+	ids, err := a.IDGen.Batch(kt, models[0].TableName(), len(models))
+	if err != nil {
+		return nil, err
+	}
+
+	for index, model := range models {
+		models[index].ID = ids[index]
+		if err = model.InsertValidate(); err != nil {
+			return nil, err
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, models[0].TableName(),
+		tablebill.AccountBillResourceCostColumns.ColumnExpr(),
+		tablebill.AccountBillResourceCostColumns.ColonNameExpr())
+
+	if err = a.Orm.Txn(tx).BulkInsert(kt.Ctx, sql, models); err != nil {
+		logs.Errorf("insert %s failed, err: %v, rid: %s", models[0].TableName(), err, kt.Rid)
+		return nil, fmt.Errorf("insert %s failed, err: %v", models[0].TableName(), err)
+	}
+
+	return ids, nil
+}
+
+// List get account bill resource cost list.
+func (a AccountBillResourceCostDao) List(kt *kit.Kit, opt *types.ListOption) (
+	*typesbill.ListAccountBillResourceCostDetails, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list account bill resource cost options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(
+		tablebill.AccountBillResourceCostColumns.ColumnTypes())), core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.AccountBillResourceCostTable, whereExpr)
+		count, err := a.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count account bill resource cost failed, err: %v, filter: %s, rid: %s",
+				err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typesbill.ListAccountBillResourceCostDetails{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`,
+		tablebill.AccountBillResourceCostColumns.FieldsNamedExpr(opt.Fields),
+		table.AccountBillResourceCostTable, whereExpr, pageExpr)
+
+	details := make([]tablebill.AccountBillResourceCost, 0)
+	if err = a.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		return nil, err
+	}
+	return &typesbill.ListAccountBillResourceCostDetails{Details: details}, nil
+}
+
+// DeleteWithTx delete account bill resource cost with tx.
+func (a AccountBillResourceCostDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error {
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.AccountBillResourceCostTable, whereExpr)
+
+	if _, err = a.Orm.Txn(tx).Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.ErrorJson("delete account bill resource cost failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// resourceCostGroupSortFields 按资源分组查询时支持的排序字段
+var resourceCostGroupSortFields = map[string]struct{}{
+	"cost":         {},
+	"count":        {},
+	"res_cloud_id": {},
+}
+
+// ListGroupByResource 根据资源分组汇总费用，默认按费用降序排列，可用于查询资源月度费用及费用TopN资源
+func (a AccountBillResourceCostDao) ListGroupByResource(kt *kit.Kit, opt *types.ListOption) (
+	*typesbill.ListAccountBillResourceCostSumDetails, error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list account bill resource cost options is nil")
+	}
+
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(
+		tablebill.AccountBillResourceCostColumns.ColumnTypes())), core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	groupExpr := "vendor, res_cloud_id, res_type, res_id, currency"
+	if opt.Page.Count {
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM (SELECT %s FROM %s %s GROUP BY %s) AS t`, groupExpr,
+			table.AccountBillResourceCostTable, whereExpr, groupExpr)
+		count, err := a.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count account bill resource cost group by resource failed, err: %v, filter: %s, rid: %s",
+				err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typesbill.ListAccountBillResourceCostSumDetails{Count: count}, nil
+	}
+
+	// 排序字段默认设置为按费用降序, 避免因为设置成根据id排序导致sql执行失败
+	if opt.Page.Sort == "" {
+		opt.Page.Sort = "cost"
+		opt.Page.Order = core.Descending
+	}
+	if _, ok := resourceCostGroupSortFields[opt.Page.Sort]; !ok {
+		return nil, errf.Newf(errf.InvalidParameter, "sort field %s is not supported", opt.Page.Sort)
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldExpr := groupExpr + ", SUM(cost) as cost, SUM(count) as count"
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s GROUP BY %s %s`, fieldExpr, table.AccountBillResourceCostTable,
+		whereExpr, groupExpr, pageExpr)
+
+	details := make([]typesbill.AccountBillResourceCostSum, 0)
+	if err = a.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.Errorf("list account bill resource cost group by resource failed, err: %v, sql: %s, rid: %s",
+			err, sql, kt.Rid)
+		return nil, err
+	}
+	return &typesbill.ListAccountBillResourceCostSumDetails{Details: details}, nil
+}
@@ -40,6 +40,16 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// forecastGroupExpr 按业务汇总二级账号的费用预测，与 CostForecast.Add 一致，各二级账号的预测误差相互独立，
+// 预测值直接累加，置信区间相对预测值的上下半宽按平方和开方汇总
+func forecastGroupExpr(prefix string) string {
+	return fmt.Sprintf("SUM(%[1]s_cost) as %[1]s_cost, "+
+		"CAST(SUM(%[1]s_cost) - IFNULL(SQRT(SUM(POW(%[1]s_cost - %[1]s_lower, 2))), 0) AS DECIMAL(38, 10)) "+
+		"as %[1]s_lower, "+
+		"CAST(SUM(%[1]s_cost) + IFNULL(SQRT(SUM(POW(%[1]s_upper - %[1]s_cost, 2))), 0) AS DECIMAL(38, 10)) "+
+		"as %[1]s_upper", prefix)
+}
+
 // AccountBillSummaryMain only used for interface.
 type AccountBillSummaryMain interface {
 	CreateWithTx(kt *kit.Kit, tx *sqlx.Tx, datas []*tablebill.AccountBillSummaryMain) ([]string, error)
@@ -225,7 +235,8 @@ func (a AccountBillSummaryMainDao) ListGroupByBiz(kt *kit.Kit, opt *types.ListOp
 		"SUM(current_month_rmb_cost_synced) as current_month_rmb_cost_synced, " +
 		"SUM(current_month_cost) as current_month_cost, " +
 		"SUM(current_month_rmb_cost) as current_month_rmb_cost, " +
-		"SUM(adjustment_cost) as adjustment_cost, SUM(adjustment_rmb_cost) as adjustment_rmb_cost"
+		"SUM(adjustment_cost) as adjustment_cost, SUM(adjustment_rmb_cost) as adjustment_rmb_cost, " +
+		forecastGroupExpr("forecast_month_rmb") + ", " + forecastGroupExpr("forecast_quarter_rmb")
 
 	sql := fmt.Sprintf(`SELECT %s FROM %s %s group by bk_biz_id %s`, fieldExpr,
 		table.AccountBillSummaryMainTable, whereExpr, pageExpr)
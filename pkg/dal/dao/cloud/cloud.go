@@ -42,6 +42,8 @@ type Cloud interface {
 	ListResourceBasicInfo(kt *kit.Kit, resType enumor.CloudResourceType, ids []string, fields ...string) (
 		[]types.CloudResourceBasicInfo, error)
 	ListResourceIDs(kt *kit.Kit, resType enumor.CloudResourceType, expr *filter.Expression) ([]string, error)
+	ListResourceBasicInfoByCloudIDs(kt *kit.Kit, resType enumor.CloudResourceType, accountID string,
+		cloudIDs []string) ([]types.CloudResourceBasicInfo, error)
 	AssignResourceToBiz(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType, expr *filter.Expression,
 		bizID int64) error
 }
@@ -97,6 +99,41 @@ func (dao CloudDao) ListResourceBasicInfo(kt *kit.Kit, resType enumor.CloudResou
 	return list, nil
 }
 
+// ListResourceBasicInfoByCloudIDs list account's cloud resource basic info by cloud ids, only id, vendor,
+// account_id and cloud_id fields are returned.
+func (dao CloudDao) ListResourceBasicInfoByCloudIDs(kt *kit.Kit, resType enumor.CloudResourceType, accountID string,
+	cloudIDs []string) ([]types.CloudResourceBasicInfo, error) {
+
+	tableName, err := resType.ConvTableName()
+	if err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	if len(accountID) == 0 || len(cloudIDs) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "account_id and cloud_ids are required")
+	}
+
+	sql := fmt.Sprintf("select id, vendor, account_id, cloud_id from %s where account_id = :account_id "+
+		"and cloud_id in (:cloud_ids)", tableName)
+
+	list := make([]types.CloudResourceBasicInfo, 0)
+	args := map[string]interface{}{
+		"account_id": accountID,
+		"cloud_ids":  cloudIDs,
+	}
+	if err := dao.Orm.Do().Select(kt.Ctx, &list, sql, args); err != nil {
+		logs.Errorf("select %s resource by cloud ids failed, err: %v, account: %s, cloud ids: %v, rid: %s", resType,
+			err, accountID, cloudIDs, kt.Rid)
+		return nil, err
+	}
+
+	for index := range list {
+		list[index].ResType = resType
+	}
+
+	return list, nil
+}
+
 // ListResourceIDs list cloud resource ids.
 func (dao CloudDao) ListResourceIDs(kt *kit.Kit, resType enumor.CloudResourceType, expr *filter.Expression) ([]string,
 	error) {
@@ -129,12 +129,13 @@ func (eipDao EipDao) List(kt *kit.Kit, opt *types.ListOption) (*cloud.EipListRes
 	columnTypes["extension.self_link"] = enumor.String
 	columnTypes["extension.resource_group_name"] = enumor.String
 	columnTypes["extension.zones"] = enumor.Json
+	columnTypes[filter.TagField] = enumor.String
 	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
 		core.NewDefaultPageOption()); err != nil {
 		return nil, err
 	}
 
-	whereOpt := tools.DefaultSqlWhereOption
+	whereOpt := tools.TagSqlWhereOption(tools.DefaultSqlWhereOption, enumor.EipCloudResType)
 	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(whereOpt)
 	if err != nil {
 		return nil, err
@@ -192,6 +192,7 @@ func (s *subnetDao) List(kt *kit.Kit, opt *types.ListOption, whereOpts ...*filte
 	columnTypes["extension.self_link"] = enumor.String
 	columnTypes["extension.resource_group_name"] = enumor.String
 	columnTypes["extension.security_group_id"] = enumor.String
+	columnTypes[filter.TagField] = enumor.String
 	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
 		core.NewDefaultPageOption()); err != nil {
 		return nil, err
@@ -205,6 +206,7 @@ func (s *subnetDao) List(kt *kit.Kit, opt *types.ListOption, whereOpts ...*filte
 		}
 		whereOpt = whereOpts[0]
 	}
+	whereOpt = tools.TagSqlWhereOption(whereOpt, enumor.SubnetCloudResType)
 	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(whereOpt)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,154 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package daosync
+
+import (
+	"fmt"
+	"time"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typessync "hcm/pkg/dal/dao/types/sync"
+	"hcm/pkg/dal/table"
+	tablessync "hcm/pkg/dal/table/cloud/sync"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+)
+
+// SyncRun only used sync run.
+type SyncRun interface {
+	BatchCreate(kt *kit.Kit, models []tablessync.SyncRunTable) ([]string, error)
+	List(kt *kit.Kit, opt *types.ListOption) (*typessync.ListSyncRuns, error)
+	DeleteBefore(kt *kit.Kit, before time.Time, limit uint) (uint, error)
+}
+
+var _ SyncRun = new(SyncRunDao)
+
+// SyncRunDao sync run dao.
+type SyncRunDao struct {
+	Orm   orm.Interface
+	IDGen idgenerator.IDGenInterface
+}
+
+// BatchCreate sync run.
+func (dao *SyncRunDao) BatchCreate(kt *kit.Kit, models []tablessync.SyncRunTable) (
+	[]string, error) {
+
+	if len(models) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "models to create cannot be empty")
+	}
+
+	ids, err := dao.IDGen.Batch(kt, table.SyncRunTable, len(models))
+	if err != nil {
+		return nil, err
+	}
+
+	for index := range models {
+		models[index].ID = ids[index]
+
+		if err = models[index].InsertValidate(); err != nil {
+			return nil, err
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.SyncRunTable,
+		tablessync.SyncRunColumns.ColumnExpr(), tablessync.SyncRunColumns.ColonNameExpr())
+
+	if err = dao.Orm.Do().BulkInsert(kt.Ctx, sql, models); err != nil {
+		logs.Errorf("insert %s failed, err: %v, sql: %s, rid: %s", table.SyncRunTable, err, sql, kt.Rid)
+		return nil, fmt.Errorf("insert %s failed, err: %v", table.SyncRunTable, err)
+	}
+
+	return ids, nil
+}
+
+// List sync run.
+func (dao *SyncRunDao) List(kt *kit.Kit, opt *types.ListOption) (*typessync.ListSyncRuns,
+	error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list sync run options is nil")
+	}
+
+	columnTypes := tablessync.SyncRunColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is dao count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.SyncRunTable, whereExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count sync run failed, err: %v, filter: %s, rid: %s", err,
+				opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typessync.ListSyncRuns{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`,
+		tablessync.SyncRunColumns.FieldsNamedExpr(opt.Fields), table.SyncRunTable,
+		whereExpr, pageExpr)
+
+	details := make([]tablessync.SyncRunTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select sync run failed, err: %v, sql: %s, filter: %v, rid: %s", err, sql,
+			opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typessync.ListSyncRuns{Count: 0, Details: details}, nil
+}
+
+// DeleteBefore delete sync run created before the given time, at most limit records are
+// deleted at a time to avoid a large transaction, returns the number of deleted records.
+func (dao *SyncRunDao) DeleteBefore(kt *kit.Kit, before time.Time, limit uint) (uint, error) {
+	if limit == 0 {
+		return 0, errf.New(errf.InvalidParameter, "limit is required")
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s WHERE created_at < :before LIMIT %d`, table.SyncRunTable, limit)
+	effected, err := dao.Orm.Do().Delete(kt.Ctx, sql, map[string]interface{}{"before": before})
+	if err != nil {
+		logs.Errorf("delete expired sync run failed, err: %v, before: %s, rid: %s", err, before, kt.Rid)
+		return 0, err
+	}
+
+	return uint(effected), nil
+}
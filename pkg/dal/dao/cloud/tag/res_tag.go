@@ -0,0 +1,174 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package daotag ...
+package daotag
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/errf"
+	idgenerator "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	tabletag "hcm/pkg/dal/table/cloud/tag"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+	"hcm/pkg/tools/slice"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ResTag only used for resource tag.
+type ResTag interface {
+	BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []tabletag.ResTagTable) ([]string, error)
+	List(kt *kit.Kit, opt *types.ListOption) (*types.ListResTagDetails, error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
+	DeleteByResIDsWithTx(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType, resIDs []string) error
+}
+
+var _ ResTag = new(ResTagDao)
+
+// ResTagDao resource tag dao.
+type ResTagDao struct {
+	Orm   orm.Interface
+	IDGen idgenerator.IDGenInterface
+}
+
+// BatchCreateWithTx create resource tag.
+func (dao *ResTagDao) BatchCreateWithTx(kt *kit.Kit, tx *sqlx.Tx, models []tabletag.ResTagTable) ([]string, error) {
+	if len(models) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "models to create cannot be empty")
+	}
+
+	ids, err := dao.IDGen.Batch(kt, table.ResTagTable, len(models))
+	if err != nil {
+		return nil, err
+	}
+
+	for index := range models {
+		models[index].ID = ids[index]
+
+		if err = models[index].InsertValidate(); err != nil {
+			return nil, err
+		}
+	}
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, table.ResTagTable,
+		tabletag.ResTagColumns.ColumnExpr(), tabletag.ResTagColumns.ColonNameExpr())
+
+	if err = dao.Orm.Txn(tx).BulkInsert(kt.Ctx, sql, models); err != nil {
+		logs.Errorf("insert %s failed, err: %v, rid: %s", table.ResTagTable, err, kt.Rid)
+		return nil, fmt.Errorf("insert %s failed, err: %v", table.ResTagTable, err)
+	}
+
+	return ids, nil
+}
+
+// List resource tag.
+func (dao *ResTagDao) List(kt *kit.Kit, opt *types.ListOption) (*types.ListResTagDetails, error) {
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list resource tag options is nil")
+	}
+
+	columnTypes := tabletag.ResTagColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is dao count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.ResTagTable, whereExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count resource tag failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &types.ListResTagDetails{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, tabletag.ResTagColumns.FieldsNamedExpr(opt.Fields),
+		table.ResTagTable, whereExpr, pageExpr)
+
+	details := make([]tabletag.ResTagTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select resource tag failed, err: %v, sql: %s, filter: %v, rid: %s", err, sql,
+			opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &types.ListResTagDetails{Details: details}, nil
+}
+
+// DeleteWithTx delete resource tag with tx.
+func (dao *ResTagDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error {
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.ResTagTable, whereExpr)
+	if _, err = dao.Orm.Txn(tx).Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.Errorf("delete resource tag failed, sql: %s, whereValue: %+v, err: %v, rid: %s",
+			sql, whereValue, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByResIDsWithTx delete all tags of the resources with tx, it is used when resources are deleted.
+func (dao *ResTagDao) DeleteByResIDsWithTx(kt *kit.Kit, tx *sqlx.Tx, resType enumor.CloudResourceType,
+	resIDs []string) error {
+
+	if len(resIDs) == 0 {
+		return nil
+	}
+
+	for _, ids := range slice.Split(resIDs, int(filter.DefaultMaxInLimit)) {
+		expr := tools.ExpressionAnd(tools.RuleEqual("res_type", resType), tools.RuleIn("res_id", ids))
+		if err := dao.DeleteWithTx(kt, tx, expr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
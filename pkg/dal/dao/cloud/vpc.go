@@ -181,6 +181,7 @@ func (v *vpcDao) List(kt *kit.Kit, opt *types.ListOption, whereOpts ...*filter.S
 	columnTypes := cloud.VpcColumns.ColumnTypes()
 	columnTypes["extension.self_link"] = enumor.String
 	columnTypes["extension.resource_group_name"] = enumor.String
+	columnTypes[filter.TagField] = enumor.String
 	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
 		core.NewDefaultPageOption()); err != nil {
 		return nil, err
@@ -194,6 +195,7 @@ func (v *vpcDao) List(kt *kit.Kit, opt *types.ListOption, whereOpts ...*filter.S
 		}
 		whereOpt = whereOpts[0]
 	}
+	whereOpt = tools.TagSqlWhereOption(whereOpt, enumor.VpcCloudResType)
 	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(whereOpt)
 	if err != nil {
 		return nil, err
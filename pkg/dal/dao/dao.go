@@ -27,6 +27,7 @@ import (
 
 	"hcm/pkg/cc"
 	accountset "hcm/pkg/dal/dao/account-set"
+	daoadmission "hcm/pkg/dal/dao/admission"
 	"hcm/pkg/dal/dao/application"
 	daoasync "hcm/pkg/dal/dao/async"
 	"hcm/pkg/dal/dao/audit"
@@ -40,6 +41,7 @@ import (
 	"hcm/pkg/dal/dao/cloud/cvm"
 	"hcm/pkg/dal/dao/cloud/disk"
 	diskcvmrel "hcm/pkg/dal/dao/cloud/disk-cvm-rel"
+	disksnapshot "hcm/pkg/dal/dao/cloud/disk-snapshot"
 	"hcm/pkg/dal/dao/cloud/eip"
 	eipcvmrel "hcm/pkg/dal/dao/cloud/eip-cvm-rel"
 	cimage "hcm/pkg/dal/dao/cloud/image"
@@ -55,9 +57,14 @@ import (
 	sgcvmrel "hcm/pkg/dal/dao/cloud/security-group-cvm-rel"
 	daosubaccount "hcm/pkg/dal/dao/cloud/sub-account"
 	daosync "hcm/pkg/dal/dao/cloud/sync"
+	daotag "hcm/pkg/dal/dao/cloud/tag"
 	"hcm/pkg/dal/dao/cloud/zone"
+	daobackup "hcm/pkg/dal/dao/disk-backup"
 	idgenerator "hcm/pkg/dal/dao/id-generator"
+	daoipam "hcm/pkg/dal/dao/ipam"
+	daolock "hcm/pkg/dal/dao/lock"
 	"hcm/pkg/dal/dao/orm"
+	recyclepolicy "hcm/pkg/dal/dao/recycle-policy"
 	recyclerecord "hcm/pkg/dal/dao/recycle-record"
 	daouser "hcm/pkg/dal/dao/user"
 	"hcm/pkg/kit"
@@ -89,6 +96,11 @@ type Set interface {
 	AzureRegion() region.AzureRegion
 	Zone() zone.Zone
 	AccountSyncDetail() daosync.AccountSyncDetail
+	SyncRun() daosync.SyncRun
+	ResTag() daotag.ResTag
+	DistributedLock() daolock.DistributedLock
+	AdmissionPolicy() daoadmission.AdmissionPolicy
+	IPAMBlock() daoipam.IPAMBlock
 	TCloudRegion() region.TCloudRegion
 	AwsRegion() region.AwsRegion
 	GcpRegion() region.GcpRegion
@@ -99,6 +111,10 @@ type Set interface {
 	ApprovalProcess() application.ApprovalProcess
 	NetworkInterface() networkinterface.NetworkInterface
 	RecycleRecord() recyclerecord.RecycleRecord
+	RecyclePolicy() recyclepolicy.RecyclePolicy
+	DiskSnapshot() disksnapshot.DiskSnapshot
+	DiskBackupPolicy() daobackup.DiskBackupPolicy
+	DiskBackupRun() daobackup.DiskBackupRun
 	Eip() eip.Eip
 	Disk() disk.Disk
 	NiCvmRel() nicvmrel.NiCvmRel
@@ -117,8 +133,14 @@ type Set interface {
 	RootAccountBillConfig() bill.RootAccountBillConfig
 	AccountBillExchangeRate() bill.AccountBillExchangeRate
 	AccountBillSyncRecord() bill.AccountBillSyncRecord
+	AccountBillResourceCost() bill.AccountBillResourceCost
+	AccountBillBudget() bill.AccountBillBudget
+	AccountBillAlert() bill.AccountBillAlert
+	AccountBillAllocationRule() bill.AccountBillAllocationRule
 	AsyncFlow() daoasync.AsyncFlow
 	AsyncFlowTask() daoasync.AsyncFlowTask
+	AsyncScheduledFlow() daoasync.AsyncScheduledFlow
+	AsyncFlowTaskLog() daoasync.AsyncFlowTaskLog
 	UserCollection() daouser.Interface
 	CloudSelectionScheme() daoselection.SchemeInterface
 	CloudSelectionBizType() daoselection.BizTypeInterface
@@ -268,6 +290,46 @@ func (s *set) AccountSyncDetail() daosync.AccountSyncDetail {
 	}
 }
 
+// SyncRun return SyncRun dao.
+func (s *set) SyncRun() daosync.SyncRun {
+	return &daosync.SyncRunDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// ResTag return ResTag dao.
+func (s *set) ResTag() daotag.ResTag {
+	return &daotag.ResTagDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// DistributedLock return DistributedLock dao.
+func (s *set) DistributedLock() daolock.DistributedLock {
+	return &daolock.DistributedLockDao{
+		Orm: s.orm,
+	}
+}
+
+// AdmissionPolicy return AdmissionPolicy dao.
+func (s *set) AdmissionPolicy() daoadmission.AdmissionPolicy {
+	return &daoadmission.AdmissionPolicyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+		Audit: s.audit,
+	}
+}
+
+// IPAMBlock return IPAMBlock dao.
+func (s *set) IPAMBlock() daoipam.IPAMBlock {
+	return &daoipam.IPAMBlockDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // AzureRegion return AzureRegion dao.
 func (s *set) AzureRegion() region.AzureRegion {
 	return &region.AzureRegionDao{
@@ -471,6 +533,41 @@ func (s *set) RecycleRecord() recyclerecord.RecycleRecord {
 	return recyclerecord.NewRecycleRecordDao(s.orm, s.idGen, s.audit)
 }
 
+// RecyclePolicy return recycle policy dao.
+func (s *set) RecyclePolicy() recyclepolicy.RecyclePolicy {
+	return &recyclepolicy.RecyclePolicyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+		Audit: s.audit,
+	}
+}
+
+// DiskSnapshot return disk snapshot dao.
+func (s *set) DiskSnapshot() disksnapshot.DiskSnapshot {
+	return &disksnapshot.DiskSnapshotDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+		Audit: s.audit,
+	}
+}
+
+// DiskBackupPolicy return disk backup policy dao.
+func (s *set) DiskBackupPolicy() daobackup.DiskBackupPolicy {
+	return &daobackup.DiskBackupPolicyDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+		Audit: s.audit,
+	}
+}
+
+// DiskBackupRun return disk backup run dao.
+func (s *set) DiskBackupRun() daobackup.DiskBackupRun {
+	return &daobackup.DiskBackupRunDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // Txn define dao set Txn.
 type Txn struct {
 	orm orm.Interface
@@ -586,6 +683,38 @@ func (s *set) AccountBillSyncRecord() bill.AccountBillSyncRecord {
 	}
 }
 
+// AccountBillResourceCost return bill.AccountBillResourceCost dao
+func (s *set) AccountBillResourceCost() bill.AccountBillResourceCost {
+	return &bill.AccountBillResourceCostDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AccountBillBudget return bill.AccountBillBudget dao
+func (s *set) AccountBillBudget() bill.AccountBillBudget {
+	return &bill.AccountBillBudgetDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AccountBillAlert return bill.AccountBillAlert dao
+func (s *set) AccountBillAlert() bill.AccountBillAlert {
+	return &bill.AccountBillAlertDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AccountBillAllocationRule return bill.AccountBillAllocationRule dao
+func (s *set) AccountBillAllocationRule() bill.AccountBillAllocationRule {
+	return &bill.AccountBillAllocationRuleDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // UserCollection returns user collection dao.
 func (s *set) UserCollection() daouser.Interface {
 	return &daouser.Dao{
@@ -610,6 +739,22 @@ func (s *set) AsyncFlowTask() daoasync.AsyncFlowTask {
 	}
 }
 
+// AsyncScheduledFlow return AsyncScheduledFlow dao.
+func (s *set) AsyncScheduledFlow() daoasync.AsyncScheduledFlow {
+	return &daoasync.AsyncScheduledFlowDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
+// AsyncFlowTaskLog return AsyncFlowTaskLog dao.
+func (s *set) AsyncFlowTaskLog() daoasync.AsyncFlowTaskLog {
+	return &daoasync.AsyncFlowTaskLogDao{
+		Orm:   s.orm,
+		IDGen: s.idGen,
+	}
+}
+
 // CloudSelectionScheme returns cloud selection scheme dao.
 func (s *set) CloudSelectionScheme() daoselection.SchemeInterface {
 	return &daoselection.SchemeDao{
@@ -0,0 +1,175 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package diskbackup
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	idgen "hcm/pkg/dal/dao/id-generator"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	"hcm/pkg/dal/table"
+	tablebackup "hcm/pkg/dal/table/disk-backup"
+	"hcm/pkg/dal/table/utils"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DiskBackupRun only used for disk backup run.
+type DiskBackupRun interface {
+	Create(kt *kit.Kit, model *tablebackup.DiskBackupRunTable) (string, error)
+	UpdateByID(kt *kit.Kit, id string, model *tablebackup.DiskBackupRunTable) error
+	List(kt *kit.Kit, opt *types.ListOption) (*types.ListResult[tablebackup.DiskBackupRunTable], error)
+	DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error
+}
+
+var _ DiskBackupRun = new(DiskBackupRunDao)
+
+// DiskBackupRunDao disk backup run dao.
+type DiskBackupRunDao struct {
+	Orm   orm.Interface
+	IDGen idgen.IDGenInterface
+}
+
+// Create disk backup run.
+func (dao *DiskBackupRunDao) Create(kt *kit.Kit, model *tablebackup.DiskBackupRunTable) (string, error) {
+	if err := model.InsertValidate(); err != nil {
+		return "", err
+	}
+
+	id, err := dao.IDGen.One(kt, table.DiskBackupRunTable)
+	if err != nil {
+		return "", err
+	}
+	model.ID = id
+
+	sql := fmt.Sprintf(`INSERT INTO %s (%s)	VALUES(%s)`, model.TableName(),
+		tablebackup.DiskBackupRunColumns.ColumnExpr(), tablebackup.DiskBackupRunColumns.ColonNameExpr())
+
+	if err = dao.Orm.Do().Insert(kt.Ctx, sql, model); err != nil {
+		logs.Errorf("insert %s failed, err: %v, sql: %s, rid: %s", model.TableName(), err, sql, kt.Rid)
+		return "", fmt.Errorf("insert %s failed, err: %v", model.TableName(), err)
+	}
+
+	return id, nil
+}
+
+// UpdateByID update disk backup run by id.
+func (dao *DiskBackupRunDao) UpdateByID(kt *kit.Kit, id string, model *tablebackup.DiskBackupRunTable) error {
+	if len(id) == 0 {
+		return errf.New(errf.InvalidParameter, "id is required")
+	}
+
+	if err := model.UpdateValidate(); err != nil {
+		return err
+	}
+
+	opts := utils.NewFieldOptions().AddIgnoredFields(types.DefaultIgnoredFields...)
+	setExpr, toUpdate, err := utils.RearrangeSQLDataWithOption(model, opts)
+	if err != nil {
+		return fmt.Errorf("prepare parsed sql set filter expr failed, err: %v", err)
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s %s where id = :id`, model.TableName(), setExpr)
+
+	toUpdate["id"] = id
+	if _, err = dao.Orm.Do().Update(kt.Ctx, sql, toUpdate); err != nil {
+		logs.Errorf("update disk backup run failed, id: %s, err: %v, rid: %s", id, err, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// List disk backup run.
+func (dao *DiskBackupRunDao) List(kt *kit.Kit, opt *types.ListOption) (
+	*types.ListResult[tablebackup.DiskBackupRunTable], error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list options is nil")
+	}
+
+	columnTypes := tablebackup.DiskBackupRunColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is a count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.DiskBackupRunTable, whereExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count disk backup run failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &types.ListResult[tablebackup.DiskBackupRunTable]{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, types.DefaultPageSQLOption)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`,
+		tablebackup.DiskBackupRunColumns.FieldsNamedExpr(opt.Fields), table.DiskBackupRunTable, whereExpr,
+		pageExpr)
+
+	details := make([]tablebackup.DiskBackupRunTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select disk backup run failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &types.ListResult[tablebackup.DiskBackupRunTable]{Details: details}, nil
+}
+
+// DeleteWithTx delete disk backup run with tx.
+func (dao *DiskBackupRunDao) DeleteWithTx(kt *kit.Kit, tx *sqlx.Tx, expr *filter.Expression) error {
+	if expr == nil {
+		return errf.New(errf.InvalidParameter, "filter expr is required")
+	}
+
+	whereExpr, whereValue, err := expr.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s %s`, table.DiskBackupRunTable, whereExpr)
+	if _, err = dao.Orm.Txn(tx).Delete(kt.Ctx, sql, whereValue); err != nil {
+		logs.ErrorJson("delete disk backup run failed, err: %v, filter: %s, rid: %s", err, expr, kt.Rid)
+		return err
+	}
+
+	return nil
+}
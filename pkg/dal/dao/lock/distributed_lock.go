@@ -0,0 +1,243 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package daolock ...
+package daolock
+
+import (
+	"fmt"
+
+	"hcm/pkg/api/core"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/dal/dao/orm"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/dal/dao/types"
+	typeslock "hcm/pkg/dal/dao/types/lock"
+	"hcm/pkg/dal/table"
+	tablelock "hcm/pkg/dal/table/lock"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+	"hcm/pkg/runtime/filter"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DistributedLock only used for distributed lock.
+type DistributedLock interface {
+	Acquire(kt *kit.Kit, key, owner string, ttlSec int64) (*tablelock.DistributedLockTable, error)
+	Renew(kt *kit.Kit, key string, token int64, ttlSec int64) error
+	Release(kt *kit.Kit, key string, token int64) error
+	ForceRelease(kt *kit.Kit, key string) error
+	List(kt *kit.Kit, opt *types.ListOption) (*typeslock.ListDistributedLockDetails, error)
+}
+
+var _ DistributedLock = new(DistributedLockDao)
+
+// DistributedLockDao distributed lock dao.
+type DistributedLockDao struct {
+	Orm orm.Interface
+}
+
+// Acquire 尝试获取锁，锁不存在、已释放或已过期时获取成功，并将 fencing token 加一；
+// 锁被其他持有者占用时返回 errf.LockHeld 错误。
+func (dao *DistributedLockDao) Acquire(kt *kit.Kit, key, owner string, ttlSec int64) (
+	*tablelock.DistributedLockTable, error) {
+
+	if len(key) == 0 || len(owner) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "lock key and owner are required")
+	}
+
+	if ttlSec <= 0 {
+		return nil, errf.New(errf.InvalidParameter, "lock ttl should > 0")
+	}
+
+	result, err := dao.Orm.AutoTxn(kt, func(txn *sqlx.Tx, opt *orm.TxnOption) (interface{}, error) {
+		args := map[string]interface{}{
+			"lock_key": key,
+			"owner":    owner,
+			"ttl":      ttlSec,
+			"creator":  kt.User,
+			"reviser":  kt.User,
+		}
+
+		sql := fmt.Sprintf(`UPDATE %s SET owner = :owner, token = token + 1, acquired_at = NOW(), `+
+			`expire_at = DATE_ADD(NOW(), INTERVAL :ttl SECOND), reviser = :reviser `+
+			`WHERE lock_key = :lock_key AND (owner = '' OR expire_at <= NOW())`, table.DistributedLockTable)
+		effect, err := dao.Orm.Txn(txn).Update(kt.Ctx, sql, args)
+		if err != nil {
+			logs.Errorf("update distributed lock failed, err: %v, key: %s, rid: %s", err, key, kt.Rid)
+			return nil, err
+		}
+
+		if effect == 0 {
+			if err = dao.insertWithTx(kt, txn, key, args); err != nil {
+				return nil, err
+			}
+		}
+
+		sql = fmt.Sprintf(`SELECT %s FROM %s WHERE lock_key = :lock_key`, tablelock.DistributedLockColumns.NamedExpr(),
+			table.DistributedLockTable)
+		details := make([]tablelock.DistributedLockTable, 0, 1)
+		if err = dao.Orm.Txn(txn).Select(kt.Ctx, &details, sql, map[string]interface{}{"lock_key": key}); err != nil {
+			logs.Errorf("select distributed lock failed, err: %v, key: %s, rid: %s", err, key, kt.Rid)
+			return nil, err
+		}
+
+		if len(details) == 0 {
+			return nil, errf.Newf(errf.RecordNotFound, "distributed lock %s not found", key)
+		}
+
+		return &details[0], nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*tablelock.DistributedLockTable), nil
+}
+
+// insertWithTx 锁记录不存在时插入记录，如果记录已存在(被其他持有者占用或并发插入)则返回 errf.LockHeld 错误。
+func (dao *DistributedLockDao) insertWithTx(kt *kit.Kit, tx *sqlx.Tx, key string,
+	args map[string]interface{}) error {
+
+	sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE lock_key = :lock_key`, table.DistributedLockTable)
+	count, err := dao.Orm.Txn(tx).Count(kt.Ctx, sql, map[string]interface{}{"lock_key": key})
+	if err != nil {
+		logs.Errorf("count distributed lock failed, err: %v, key: %s, rid: %s", err, key, kt.Rid)
+		return err
+	}
+
+	if count != 0 {
+		return errf.Newf(errf.LockHeld, "lock %s is held by others", key)
+	}
+
+	sql = fmt.Sprintf(`INSERT INTO %s (lock_key, owner, token, acquired_at, expire_at, creator, reviser) `+
+		`VALUES (:lock_key, :owner, 1, NOW(), DATE_ADD(NOW(), INTERVAL :ttl SECOND), :creator, :reviser)`,
+		table.DistributedLockTable)
+	if err = dao.Orm.Txn(tx).Insert(kt.Ctx, sql, args); err != nil {
+		if errf.GetMySQLDuplicated(err) != nil {
+			return errf.Newf(errf.LockHeld, "lock %s is held by others", key)
+		}
+
+		logs.Errorf("insert distributed lock failed, err: %v, key: %s, rid: %s", err, key, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// Renew 续期锁，锁已过期或 token 不匹配(已被释放或被他人获取)时返回 errf.LockLost 错误。
+func (dao *DistributedLockDao) Renew(kt *kit.Kit, key string, token int64, ttlSec int64) error {
+	if ttlSec <= 0 {
+		return errf.New(errf.InvalidParameter, "lock ttl should > 0")
+	}
+
+	sql := fmt.Sprintf(`UPDATE %s SET expire_at = DATE_ADD(NOW(), INTERVAL :ttl SECOND) `+
+		`WHERE lock_key = :lock_key AND token = :token AND owner != '' AND expire_at > NOW()`,
+		table.DistributedLockTable)
+	args := map[string]interface{}{"lock_key": key, "token": token, "ttl": ttlSec}
+	effect, err := dao.Orm.Do().Update(kt.Ctx, sql, args)
+	if err != nil {
+		logs.Errorf("renew distributed lock failed, err: %v, key: %s, token: %d, rid: %s", err, key, token, kt.Rid)
+		return err
+	}
+
+	if effect == 0 {
+		return errf.Newf(errf.LockLost, "lock %s with token %d is lost", key, token)
+	}
+
+	return nil
+}
+
+// Release 释放锁，只会释放 token 匹配的锁，锁已被他人获取时不做任何操作。
+func (dao *DistributedLockDao) Release(kt *kit.Kit, key string, token int64) error {
+	sql := fmt.Sprintf(`UPDATE %s SET owner = '', expire_at = NOW() WHERE lock_key = :lock_key AND token = :token`,
+		table.DistributedLockTable)
+	args := map[string]interface{}{"lock_key": key, "token": token}
+	if _, err := dao.Orm.Do().Update(kt.Ctx, sql, args); err != nil {
+		logs.Errorf("release distributed lock failed, err: %v, key: %s, token: %d, rid: %s", err, key, token, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// ForceRelease 强制释放锁，不校验持有者。锁记录会被保留，以保证后续加锁的 fencing token 继续递增。
+func (dao *DistributedLockDao) ForceRelease(kt *kit.Kit, key string) error {
+	sql := fmt.Sprintf(`UPDATE %s SET owner = '', expire_at = NOW(), reviser = :reviser WHERE lock_key = :lock_key`,
+		table.DistributedLockTable)
+	args := map[string]interface{}{"lock_key": key, "reviser": kt.User}
+	if _, err := dao.Orm.Do().Update(kt.Ctx, sql, args); err != nil {
+		logs.Errorf("force release distributed lock failed, err: %v, key: %s, rid: %s", err, key, kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// List distributed lock.
+func (dao *DistributedLockDao) List(kt *kit.Kit, opt *types.ListOption) (*typeslock.ListDistributedLockDetails,
+	error) {
+
+	if opt == nil {
+		return nil, errf.New(errf.InvalidParameter, "list distributed lock options is nil")
+	}
+
+	columnTypes := tablelock.DistributedLockColumns.ColumnTypes()
+	if err := opt.Validate(filter.NewExprOption(filter.RuleFields(columnTypes)),
+		core.NewDefaultPageOption()); err != nil {
+		return nil, err
+	}
+
+	whereExpr, whereValue, err := opt.Filter.SQLWhereExpr(tools.DefaultSqlWhereOption)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.Page.Count {
+		// this is dao count request, then do count operation only.
+		sql := fmt.Sprintf(`SELECT COUNT(*) FROM %s %s`, table.DistributedLockTable, whereExpr)
+
+		count, err := dao.Orm.Do().Count(kt.Ctx, sql, whereValue)
+		if err != nil {
+			logs.ErrorJson("count distributed lock failed, err: %v, filter: %s, rid: %s", err, opt.Filter, kt.Rid)
+			return nil, err
+		}
+
+		return &typeslock.ListDistributedLockDetails{Count: count}, nil
+	}
+
+	pageExpr, err := types.PageSQLExpr(opt.Page, &types.PageSQLOption{Sort: types.SortOption{Sort: "lock_key",
+		IfNotPresent: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	sql := fmt.Sprintf(`SELECT %s FROM %s %s %s`, tablelock.DistributedLockColumns.FieldsNamedExpr(opt.Fields),
+		table.DistributedLockTable, whereExpr, pageExpr)
+
+	details := make([]tablelock.DistributedLockTable, 0)
+	if err = dao.Orm.Do().Select(kt.Ctx, &details, sql, whereValue); err != nil {
+		logs.ErrorJson("select distributed lock failed, err: %v, sql: %s, filter: %v, rid: %s", err, sql,
+			opt.Filter, kt.Rid)
+		return nil, err
+	}
+
+	return &typeslock.ListDistributedLockDetails{Details: details}, nil
+}
@@ -22,6 +22,7 @@ package tools
 import (
 	"fmt"
 
+	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/runtime/filter"
 )
 
@@ -70,6 +71,18 @@ var DefaultSqlWhereOption = &filter.SQLWhereOption{
 	Priority: filter.Priority{"id"},
 }
 
+// TagSqlWhereOption return a copy of sql where option which supports the tag rules of the resource type,
+// the default sql where option is used when opt is nil.
+func TagSqlWhereOption(opt *filter.SQLWhereOption, resType enumor.CloudResourceType) *filter.SQLWhereOption {
+	if opt == nil {
+		opt = DefaultSqlWhereOption
+	}
+
+	copied := *opt
+	copied.TagResType = string(resType)
+	return &copied
+}
+
 // And merge expressions using 'and' operation.
 func And(rules ...filter.RuleFactory) (*filter.Expression, error) {
 	if len(rules) == 0 {
@@ -0,0 +1,51 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package typesasync
+
+import (
+	"time"
+
+	"hcm/pkg/criteria/validator"
+	tableasync "hcm/pkg/dal/table/async"
+)
+
+// ListAsyncScheduledFlows list async scheduled flows.
+type ListAsyncScheduledFlows struct {
+	Count   uint64                               `json:"count,omitempty"`
+	Details []tableasync.AsyncScheduledFlowTable `json:"details,omitempty"`
+}
+
+// TriggerScheduledFlowInfo define scheduled flow trigger info.
+type TriggerScheduledFlowInfo struct {
+	ID string `json:"id" validate:"required"`
+	// Source 触发前的下一次触发时间，用于CAS判断，保证同一触发时间点只触发一次
+	Source time.Time `json:"source" validate:"required"`
+	// Target 触发后的下一次触发时间
+	Target time.Time `json:"target" validate:"required"`
+	// TriggeredAt 本次触发时间
+	TriggeredAt time.Time `json:"triggered_at" validate:"required"`
+	// FlowID 本次触发创建的任务流ID
+	FlowID string `json:"flow_id" validate:"required"`
+}
+
+// Validate TriggerScheduledFlowInfo.
+func (info *TriggerScheduledFlowInfo) Validate() error {
+	return validator.Validate.Struct(info)
+}
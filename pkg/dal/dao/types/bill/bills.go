@@ -23,6 +23,7 @@ import (
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/errf"
 	tablebill "hcm/pkg/dal/table/bill"
+	tabletypes "hcm/pkg/dal/table/types"
 )
 
 // ListAccountBillSummaryMainDetails list account bill config details.
@@ -91,6 +92,47 @@ type ListAccountBillSyncRecordDetails struct {
 	Details []tablebill.AccountBillSyncRecord `json:"details,omitempty"`
 }
 
+// ListAccountBillResourceCostDetails list account bill resource cost details
+type ListAccountBillResourceCostDetails struct {
+	Count   uint64                              `json:"count,omitempty"`
+	Details []tablebill.AccountBillResourceCost `json:"details,omitempty"`
+}
+
+// ListAccountBillBudgetDetails list account bill budget details
+type ListAccountBillBudgetDetails struct {
+	Count   uint64                        `json:"count,omitempty"`
+	Details []tablebill.AccountBillBudget `json:"details,omitempty"`
+}
+
+// ListAccountBillAlertDetails list account bill alert details
+type ListAccountBillAlertDetails struct {
+	Count   uint64                       `json:"count,omitempty"`
+	Details []tablebill.AccountBillAlert `json:"details,omitempty"`
+}
+
+// ListAccountBillAllocationRuleDetails list account bill allocation rule details
+type ListAccountBillAllocationRuleDetails struct {
+	Count   uint64                                `json:"count,omitempty"`
+	Details []tablebill.AccountBillAllocationRule `json:"details,omitempty"`
+}
+
+// AccountBillResourceCostSum account bill resource cost summed by resource
+type AccountBillResourceCostSum struct {
+	Vendor     enumor.Vendor            `db:"vendor" json:"vendor"`
+	ResCloudID string                   `db:"res_cloud_id" json:"res_cloud_id"`
+	ResType    enumor.CloudResourceType `db:"res_type" json:"res_type"`
+	ResID      string                   `db:"res_id" json:"res_id"`
+	Currency   enumor.CurrencyCode      `db:"currency" json:"currency"`
+	Cost       *tabletypes.Decimal      `db:"cost" json:"cost"`
+	Count      int64                    `db:"count" json:"count"`
+}
+
+// ListAccountBillResourceCostSumDetails list account bill resource cost sum details
+type ListAccountBillResourceCostSumDetails struct {
+	Count   uint64                       `json:"count,omitempty"`
+	Details []AccountBillResourceCostSum `json:"details,omitempty"`
+}
+
 // ItemCommonOpt  bill item table partition parameters
 type ItemCommonOpt struct {
 	Vendor enumor.Vendor `json:"vendor" validate:"required"`
@@ -35,6 +35,15 @@ type PageSQLOption struct {
 	// 1. If set, then user defined Sort field will be overlapped.
 	// 2. Sort field should always be an indexed field in db.
 	Sort SortOption `json:"sort"`
+	// ThenBy defines the secondary sort columns, which are used in order when the values of
+	// the sort column are equal, so that the page result is stable.
+	ThenBy []OrderBy `json:"then_by"`
+}
+
+// OrderBy defines a sort column and its sort direction.
+type OrderBy struct {
+	Sort  string     `json:"sort"`
+	Order core.Order `json:"order"`
 }
 
 // SortOption defines how to set the order column when do the BasePage.SQLExpr
@@ -97,16 +106,19 @@ func PageSQLExpr(bp *core.BasePage, ps *PageSQLOption) (where string, err error)
 		// identity id as the default sort column.
 		sort = "id"
 	}
-	expr := fmt.Sprintf("ORDER BY %s", sort)
+	expr := fmt.Sprintf("ORDER BY %s %s", sort, bp.Order.Order())
+	for _, one := range ps.ThenBy {
+		expr = fmt.Sprintf("%s, %s %s", expr, one.Sort, one.Order.Order())
+	}
 	if bp.Start == 0 && bp.Limit == 0 {
 		// this is a special scenario, which means query all the resources at once.
-		return fmt.Sprintf("%s %s", expr, bp.Order.Order()), nil
+		return expr, nil
 	}
 	// if Start >=1, then Limit can not be 0.
 	if bp.Limit == 0 {
 		return "", errors.New("page.limit value should >= 1")
 	}
 	// bp.Limit is > 0, already validated upper.
-	expr = fmt.Sprintf("%s %s LIMIT %d OFFSET %d", expr, bp.Order.Order(), bp.Limit, bp.Start)
+	expr = fmt.Sprintf("%s LIMIT %d OFFSET %d", expr, bp.Limit, bp.Start)
 	return expr, nil
 }
@@ -28,3 +28,9 @@ type ListAccountSyncDetails struct {
 	Count   uint64                              `json:"count,omitempty"`
 	Details []tablessync.AccountSyncDetailTable `json:"details,omitempty"`
 }
+
+// ListSyncRuns list sync runs.
+type ListSyncRuns struct {
+	Count   uint64                    `json:"count,omitempty"`
+	Details []tablessync.SyncRunTable `json:"details,omitempty"`
+}
@@ -37,6 +37,8 @@ type ListOption struct {
 	Fields []string
 	Filter *filter.Expression
 	Page   *core.BasePage
+	// ThenBy Page.Sort 取值相同时依次使用的次级排序，由系统设置，目前只有异步任务流的查询支持
+	ThenBy []OrderBy
 }
 
 // Validate list option.
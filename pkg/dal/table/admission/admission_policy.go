@@ -0,0 +1,138 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tableadmission defines admission policy tables.
+package tableadmission
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AdmissionPolicyColumns defines all the admission_policy table's columns.
+var AdmissionPolicyColumns = utils.MergeColumns(nil, AdmissionPolicyColumnDescriptor)
+
+// AdmissionPolicyColumnDescriptor is admission_policy's column descriptors.
+var AdmissionPolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "action", NamedC: "action", Type: enumor.String},
+	{Column: "mode", NamedC: "mode", Type: enumor.String},
+	{Column: "bk_biz_ids", NamedC: "bk_biz_ids", Type: enumor.Json},
+	{Column: "rule", NamedC: "rule", Type: enumor.Json},
+	{Column: "message", NamedC: "message", Type: enumor.String},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AdmissionPolicyTable define admission_policy table.
+type AdmissionPolicyTable struct {
+	ID   string `db:"id" json:"id" validate:"lte=64"`
+	Name string `db:"name" json:"name" validate:"lte=255"`
+	// ResType 策略作用的资源类型
+	ResType enumor.CloudResourceType `db:"res_type" json:"res_type" validate:"lte=64"`
+	// Action 策略作用的资源操作
+	Action enumor.AdmissionAction `db:"action" json:"action" validate:"lte=16"`
+	// Mode 策略命中后的处理模式
+	Mode enumor.AdmissionMode `db:"mode" json:"mode" validate:"lte=16"`
+	// BkBizIDs 策略作用的业务，-1 表示所有业务
+	BkBizIDs types.Int64Array `db:"bk_biz_ids" json:"bk_biz_ids"`
+	// Rule 策略规则
+	Rule      types.JsonField `db:"rule" json:"rule"`
+	Message   string          `db:"message" json:"message" validate:"lte=1024"`
+	Enabled   *bool           `db:"enabled" json:"enabled"`
+	Memo      *string         `db:"memo" json:"memo" validate:"omitempty,lte=255"`
+	Creator   string          `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser   string          `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt types.Time      `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt types.Time      `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return admission_policy table name.
+func (t AdmissionPolicyTable) TableName() table.Name {
+	return table.AdmissionPolicyTable
+}
+
+// InsertValidate admission_policy table when insert.
+func (t AdmissionPolicyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("res_type is required")
+	}
+
+	if len(t.Action) == 0 {
+		return errors.New("action is required")
+	}
+
+	if len(t.Mode) == 0 {
+		return errors.New("mode is required")
+	}
+
+	if len(t.BkBizIDs) == 0 {
+		return errors.New("bk_biz_ids is required")
+	}
+
+	if len(t.Rule) == 0 {
+		return errors.New("rule is required")
+	}
+
+	if t.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate admission_policy table when update.
+func (t AdmissionPolicyTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableasync
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/tools/json"
+)
+
+// ConditionLogic 条件规则之间的逻辑关系
+type ConditionLogic string
+
+const (
+	// ConditionAnd 所有规则都满足时条件成立
+	ConditionAnd ConditionLogic = "and"
+	// ConditionOr 任一规则满足时条件成立
+	ConditionOr ConditionLogic = "or"
+)
+
+// ConditionSource 条件规则的取值来源
+type ConditionSource string
+
+const (
+	// ConditionSourceResult 取依赖任务的执行结果
+	ConditionSourceResult ConditionSource = "result"
+	// ConditionSourceShareData 取任务流的共享数据
+	ConditionSourceShareData ConditionSource = "share_data"
+)
+
+// ConditionOperator 条件规则的比较操作符
+type ConditionOperator string
+
+const (
+	// ConditionEqual 取值等于Value
+	ConditionEqual ConditionOperator = "eq"
+	// ConditionNotEqual 取值不等于Value
+	ConditionNotEqual ConditionOperator = "neq"
+	// ConditionIn 取值在Value数组中
+	ConditionIn ConditionOperator = "in"
+	// ConditionNotIn 取值不在Value数组中
+	ConditionNotIn ConditionOperator = "nin"
+	// ConditionExists 取值存在
+	ConditionExists ConditionOperator = "exists"
+	// ConditionNotExists 取值不存在
+	ConditionNotExists ConditionOperator = "not_exists"
+)
+
+// Condition define task exec condition, 条件不成立时任务会被跳过。
+type Condition struct {
+	// Logic 规则之间的逻辑关系，默认为and
+	Logic ConditionLogic `json:"logic,omitempty" validate:"omitempty"`
+	// Rules 条件规则
+	Rules []ConditionRule `json:"rules" validate:"required,min=1"`
+}
+
+// Validate Condition.
+func (c Condition) Validate() error {
+	if err := validator.Validate.Struct(c); err != nil {
+		return err
+	}
+
+	switch c.Logic {
+	case "", ConditionAnd, ConditionOr:
+	default:
+		return fmt.Errorf("unsupported condition logic: %s", c.Logic)
+	}
+
+	for _, rule := range c.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ConditionEnv 条件计算时用到的上下文数据
+type ConditionEnv struct {
+	// Results 依赖任务的执行结果，key为依赖任务的ActionID
+	Results map[string]types.JsonField
+	// ShareData 任务流共享数据
+	ShareData *ShareData
+}
+
+// Evaluate 计算条件是否成立
+func (c Condition) Evaluate(env ConditionEnv) (bool, error) {
+	for _, rule := range c.Rules {
+		hit, err := rule.Evaluate(env)
+		if err != nil {
+			return false, err
+		}
+
+		if c.Logic == ConditionOr && hit {
+			return true, nil
+		}
+
+		if c.Logic != ConditionOr && !hit {
+			return false, nil
+		}
+	}
+
+	return c.Logic != ConditionOr, nil
+}
+
+// Scan is used to decode raw message which is read from db into Condition.
+func (c *Condition) Scan(raw interface{}) error {
+	return types.Scan(raw, c)
+}
+
+// Value encode the Condition to a json raw, so that it can be stored to db with json raw.
+func (c Condition) Value() (driver.Value, error) {
+	return types.Value(c)
+}
+
+// ConditionRule define condition rule.
+type ConditionRule struct {
+	// Source 取值来源
+	Source ConditionSource `json:"source" validate:"required"`
+	// ActionID Source为result时，取值的依赖任务ActionID
+	ActionID string `json:"action_id,omitempty" validate:"omitempty"`
+	// Key Source为result时为结果中的字段路径，多级字段用.分割；Source为share_data时为共享数据的key
+	Key string `json:"key" validate:"required"`
+	// Operator 比较操作符
+	Operator ConditionOperator `json:"operator" validate:"required"`
+	// Value 比较值，in/nin 时为数组，exists/not_exists 时不需要设置
+	Value interface{} `json:"value,omitempty" validate:"omitempty"`
+}
+
+// Validate ConditionRule.
+func (r ConditionRule) Validate() error {
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+
+	switch r.Source {
+	case ConditionSourceResult:
+		if len(r.ActionID) == 0 {
+			return errors.New("condition rule source is result, action_id is required")
+		}
+	case ConditionSourceShareData:
+		if len(r.ActionID) != 0 {
+			return errors.New("condition rule source is share_data, action_id can not set")
+		}
+	default:
+		return fmt.Errorf("unsupported condition rule source: %s", r.Source)
+	}
+
+	switch r.Operator {
+	case ConditionEqual, ConditionNotEqual:
+		if r.Value == nil || isSlice(r.Value) {
+			return fmt.Errorf("condition rule operator is %s, value should be a scalar", r.Operator)
+		}
+	case ConditionIn, ConditionNotIn:
+		if !isSlice(r.Value) {
+			return fmt.Errorf("condition rule operator is %s, value should be an array", r.Operator)
+		}
+	case ConditionExists, ConditionNotExists:
+		if r.Value != nil {
+			return fmt.Errorf("condition rule operator is %s, value can not set", r.Operator)
+		}
+	default:
+		return fmt.Errorf("unsupported condition rule operator: %s", r.Operator)
+	}
+
+	return nil
+}
+
+// Evaluate 计算规则是否成立
+func (r ConditionRule) Evaluate(env ConditionEnv) (bool, error) {
+	value, exist, err := r.lookup(env)
+	if err != nil {
+		return false, err
+	}
+
+	switch r.Operator {
+	case ConditionExists:
+		return exist, nil
+	case ConditionNotExists:
+		return !exist, nil
+	case ConditionEqual:
+		return exist && conditionValueString(value) == conditionValueString(r.Value), nil
+	case ConditionNotEqual:
+		return !exist || conditionValueString(value) != conditionValueString(r.Value), nil
+	case ConditionIn, ConditionNotIn:
+		in := false
+		if exist {
+			target := conditionValueString(value)
+			rv := reflect.ValueOf(r.Value)
+			for i := 0; i < rv.Len(); i++ {
+				if conditionValueString(rv.Index(i).Interface()) == target {
+					in = true
+					break
+				}
+			}
+		}
+
+		if r.Operator == ConditionIn {
+			return in, nil
+		}
+		return !in, nil
+	default:
+		return false, fmt.Errorf("unsupported condition rule operator: %s", r.Operator)
+	}
+}
+
+// lookup 根据取值来源获取规则的取值
+func (r ConditionRule) lookup(env ConditionEnv) (interface{}, bool, error) {
+	switch r.Source {
+	case ConditionSourceShareData:
+		if env.ShareData == nil {
+			return nil, false, nil
+		}
+		value, exist := env.ShareData.Get(r.Key)
+		return value, exist, nil
+
+	case ConditionSourceResult:
+		raw := env.Results[r.ActionID]
+		if len(raw) == 0 {
+			return nil, false, nil
+		}
+
+		var value interface{}
+		if err := json.UnmarshalFromString(string(raw), &value); err != nil {
+			return nil, false, fmt.Errorf("unmarshal task: %s result failed, err: %v", r.ActionID, err)
+		}
+
+		for _, field := range strings.Split(r.Key, ".") {
+			obj, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false, nil
+			}
+
+			if value, ok = obj[field]; !ok {
+				return nil, false, nil
+			}
+		}
+
+		return value, value != nil, nil
+
+	default:
+		return nil, false, fmt.Errorf("unsupported condition rule source: %s", r.Source)
+	}
+}
+
+// conditionValueString 将取值统一转为字符串进行比较，避免json解析后数值类型不一致
+func conditionValueString(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(raw)
+}
+
+func isSlice(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+
+	kind := reflect.TypeOf(value).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
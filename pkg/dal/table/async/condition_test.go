@@ -0,0 +1,128 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableasync
+
+import (
+	"testing"
+
+	"hcm/pkg/dal/table/types"
+)
+
+func TestConditionEvaluate(t *testing.T) {
+	env := ConditionEnv{
+		Results: map[string]types.JsonField{
+			"1": `{"status":"ok","count":3,"detail":{"zone":"ap-guangzhou-1"}}`,
+		},
+		ShareData: NewShareData(map[string]string{"vendor": "tcloud"}),
+	}
+
+	cases := []struct {
+		name   string
+		cond   Condition
+		expect bool
+	}{
+		{
+			name: "result equal",
+			cond: Condition{Rules: []ConditionRule{
+				{Source: ConditionSourceResult, ActionID: "1", Key: "status", Operator: ConditionEqual, Value: "ok"},
+			}},
+			expect: true,
+		},
+		{
+			name: "result number equal",
+			cond: Condition{Rules: []ConditionRule{
+				{Source: ConditionSourceResult, ActionID: "1", Key: "count", Operator: ConditionEqual, Value: 3},
+			}},
+			expect: true,
+		},
+		{
+			name: "result nested in",
+			cond: Condition{Rules: []ConditionRule{
+				{Source: ConditionSourceResult, ActionID: "1", Key: "detail.zone", Operator: ConditionIn,
+					Value: []string{"ap-guangzhou-1", "ap-guangzhou-2"}},
+			}},
+			expect: true,
+		},
+		{
+			name: "result of skipped task not exists",
+			cond: Condition{Rules: []ConditionRule{
+				{Source: ConditionSourceResult, ActionID: "2", Key: "status", Operator: ConditionNotExists},
+			}},
+			expect: true,
+		},
+		{
+			name: "share data not equal",
+			cond: Condition{Rules: []ConditionRule{
+				{Source: ConditionSourceShareData, Key: "vendor", Operator: ConditionNotEqual, Value: "aws"},
+			}},
+			expect: true,
+		},
+		{
+			name: "and with one rule failed",
+			cond: Condition{Rules: []ConditionRule{
+				{Source: ConditionSourceShareData, Key: "vendor", Operator: ConditionEqual, Value: "tcloud"},
+				{Source: ConditionSourceResult, ActionID: "1", Key: "status", Operator: ConditionNotIn,
+					Value: []interface{}{"ok"}},
+			}},
+			expect: false,
+		},
+		{
+			name: "or with one rule hit",
+			cond: Condition{Logic: ConditionOr, Rules: []ConditionRule{
+				{Source: ConditionSourceShareData, Key: "vendor", Operator: ConditionEqual, Value: "aws"},
+				{Source: ConditionSourceShareData, Key: "vendor", Operator: ConditionExists},
+			}},
+			expect: true,
+		},
+	}
+
+	for _, c := range cases {
+		if err := c.cond.Validate(); err != nil {
+			t.Errorf("case %s validate failed, err: %v", c.name, err)
+			continue
+		}
+
+		got, err := c.cond.Evaluate(env)
+		if err != nil {
+			t.Errorf("case %s evaluate failed, err: %v", c.name, err)
+			continue
+		}
+
+		if got != c.expect {
+			t.Errorf("case %s expect %v, but got %v", c.name, c.expect, got)
+		}
+	}
+}
+
+func TestConditionValidate(t *testing.T) {
+	invalids := []Condition{
+		{},
+		{Logic: "xor", Rules: []ConditionRule{{Source: ConditionSourceShareData, Key: "a", Operator: ConditionExists}}},
+		{Rules: []ConditionRule{{Source: ConditionSourceResult, Key: "a", Operator: ConditionExists}}},
+		{Rules: []ConditionRule{{Source: ConditionSourceShareData, Key: "a", Operator: ConditionIn, Value: "b"}}},
+		{Rules: []ConditionRule{{Source: ConditionSourceShareData, Key: "a", Operator: ConditionEqual}}},
+	}
+
+	for idx, one := range invalids {
+		if err := one.Validate(); err == nil {
+			t.Errorf("case %d expect validate failed, but succeed", idx)
+		}
+	}
+}
@@ -21,6 +21,7 @@ package tableasync
 
 import (
 	"errors"
+	"time"
 
 	"hcm/pkg/criteria/enumor"
 	"hcm/pkg/criteria/validator"
@@ -41,6 +42,11 @@ var AsyncFlowTableColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "memo", NamedC: "memo", Type: enumor.String},
 	{Column: "share_data", NamedC: "share_data", Type: enumor.Json},
 	{Column: "worker", NamedC: "worker", Type: enumor.String},
+	{Column: "priority", NamedC: "priority", Type: enumor.Numeric},
+	{Column: "fairness_key", NamedC: "fairness_key", Type: enumor.String},
+	{Column: "compensate_on_failure", NamedC: "compensate_on_failure", Type: enumor.Boolean},
+	{Column: "deadline_at", NamedC: "deadline_at", Type: enumor.Time},
+	{Column: "sla_at", NamedC: "sla_at", Type: enumor.Time},
 	{Column: "creator", NamedC: "creator", Type: enumor.String},
 	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
@@ -49,17 +55,23 @@ var AsyncFlowTableColumnDescriptor = utils.ColumnDescriptors{
 
 // AsyncFlowTable define async_flow table.
 type AsyncFlowTable struct {
-	ID        string           `db:"id" json:"id" validate:"lte=64"`
-	Name      enumor.FlowName  `db:"name" json:"name"`
-	State     enumor.FlowState `db:"state" json:"state"`
-	Reason    *Reason          `db:"reason" json:"reason"`
-	ShareData *ShareData       `db:"share_data" json:"share_data"`
-	Memo      string           `db:"memo" json:"memo"`
-	Worker    *string          `db:"worker" json:"worker"`
-	Creator   string           `db:"creator" json:"creator" validate:"lte=64"`
-	Reviser   string           `db:"reviser" json:"reviser" validate:"lte=64"`
-	CreatedAt types.Time       `db:"created_at" json:"created_at" validate:"excluded_unless"`
-	UpdatedAt types.Time       `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+	ID          string              `db:"id" json:"id" validate:"lte=64"`
+	Name        enumor.FlowName     `db:"name" json:"name"`
+	State       enumor.FlowState    `db:"state" json:"state"`
+	Reason      *Reason             `db:"reason" json:"reason"`
+	ShareData   *ShareData          `db:"share_data" json:"share_data"`
+	Memo        string              `db:"memo" json:"memo"`
+	Worker      *string             `db:"worker" json:"worker"`
+	Priority    enumor.FlowPriority `db:"priority" json:"priority"`
+	FairnessKey string              `db:"fairness_key" json:"fairness_key" validate:"lte=64"`
+	// CompensateOnFailure 任务流失败后是否对执行成功的任务进行补偿
+	CompensateOnFailure bool       `db:"compensate_on_failure" json:"compensate_on_failure"`
+	DeadlineAt          *time.Time `db:"deadline_at" json:"deadline_at"`
+	SLAAt               *time.Time `db:"sla_at" json:"sla_at"`
+	Creator             string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser             string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt           types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt           types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
 }
 
 // TableName return async_flow table name.
@@ -112,5 +124,25 @@ func (a AsyncFlowTable) UpdateValidate() error {
 		return errors.New("creator can not update")
 	}
 
+	if a.Priority != 0 {
+		return errors.New("priority can not update")
+	}
+
+	if len(a.FairnessKey) != 0 {
+		return errors.New("fairness_key can not update")
+	}
+
+	if a.CompensateOnFailure {
+		return errors.New("compensate_on_failure can not update")
+	}
+
+	if a.DeadlineAt != nil {
+		return errors.New("deadline_at can not update")
+	}
+
+	if a.SLAAt != nil {
+		return errors.New("sla_at can not update")
+	}
+
 	return nil
 }
@@ -0,0 +1,175 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableasync
+
+import (
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AsyncScheduledFlowColumns defines all the async_scheduled_flow table's columns.
+var AsyncScheduledFlowColumns = utils.MergeColumns(nil, AsyncScheduledFlowTableColumnDescriptor)
+
+// AsyncScheduledFlowTableColumnDescriptor is async_scheduled_flow's column descriptors.
+var AsyncScheduledFlowTableColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "flow_name", NamedC: "flow_name", Type: enumor.String},
+	{Column: "spec", NamedC: "spec", Type: enumor.String},
+	{Column: "tasks", NamedC: "tasks", Type: enumor.Json},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "state", NamedC: "state", Type: enumor.String},
+	{Column: "next_trigger_at", NamedC: "next_trigger_at", Type: enumor.Time},
+	{Column: "last_triggered_at", NamedC: "last_triggered_at", Type: enumor.Time},
+	{Column: "last_flow_id", NamedC: "last_flow_id", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AsyncScheduledFlowTable define async_scheduled_flow table.
+type AsyncScheduledFlowTable struct {
+	ID string `db:"id" json:"id" validate:"lte=64"`
+	// Name 定时任务流名称，全局唯一
+	Name string `db:"name" json:"name" validate:"lte=64"`
+	// FlowName 定时触发的任务流模版名称
+	FlowName enumor.FlowName `db:"flow_name" json:"flow_name" validate:"lte=64"`
+	// Spec 标准cron表达式，如：0 2 * * *
+	Spec string `db:"spec" json:"spec" validate:"lte=128"`
+	// Tasks 任务流模版中任务的请求参数
+	Tasks ScheduledTasks            `db:"tasks" json:"tasks"`
+	Memo  *string                   `db:"memo" json:"memo" validate:"omitempty,lte=255"`
+	State enumor.ScheduledFlowState `db:"state" json:"state"`
+	// NextTriggerAt 下一次触发时间
+	NextTriggerAt time.Time `db:"next_trigger_at" json:"next_trigger_at"`
+	// LastTriggeredAt 最近一次触发时间
+	LastTriggeredAt *time.Time `db:"last_triggered_at" json:"last_triggered_at"`
+	// LastFlowID 最近一次触发创建的任务流ID
+	LastFlowID *string    `db:"last_flow_id" json:"last_flow_id" validate:"omitempty,lte=64"`
+	Creator    string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser    string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt  types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt  types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return async_scheduled_flow table name.
+func (a AsyncScheduledFlowTable) TableName() table.Name {
+	return table.AsyncScheduledFlowTable
+}
+
+// InsertValidate async_scheduled_flow table when insert.
+func (a AsyncScheduledFlowTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(a.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if len(a.FlowName) == 0 {
+		return errors.New("flow_name is required")
+	}
+
+	if len(a.Spec) == 0 {
+		return errors.New("spec is required")
+	}
+
+	if err := a.State.Validate(); err != nil {
+		return err
+	}
+
+	if a.NextTriggerAt.IsZero() {
+		return errors.New("next_trigger_at is required")
+	}
+
+	if len(a.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(a.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate async_scheduled_flow table when update.
+func (a AsyncScheduledFlowTable) UpdateValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.Name) != 0 {
+		return errors.New("name can not update")
+	}
+
+	if len(a.State) != 0 {
+		if err := a.State.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// 触发信息只允许在触发时通过CAS更新
+	if a.LastTriggeredAt != nil {
+		return errors.New("last_triggered_at can not update")
+	}
+
+	if len(a.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	return nil
+}
+
+// ScheduledTask define scheduled flow task params.
+type ScheduledTask struct {
+	// ActionID 任务在任务流模版中的唯一ID
+	ActionID string `json:"action_id"`
+	// Params 任务执行请求参数
+	Params types.JsonField `json:"params"`
+}
+
+// ScheduledTasks define scheduled flow tasks.
+type ScheduledTasks []ScheduledTask
+
+// Scan is used to decode raw message which is read from db into ScheduledTasks.
+func (d *ScheduledTasks) Scan(raw interface{}) error {
+	return types.Scan(raw, d)
+}
+
+// Value encode the ScheduledTasks to a json raw, so that it can be stored to db with json raw.
+func (d ScheduledTasks) Value() (driver.Value, error) {
+	return types.Value(d)
+}
@@ -39,9 +39,12 @@ var AsyncFlowTaskTableColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "flow_name", NamedC: "flow_name", Type: enumor.String},
 	{Column: "action_id", NamedC: "action_id", Type: enumor.String},
 	{Column: "action_name", NamedC: "action_name", Type: enumor.String},
+	{Column: "kind", NamedC: "kind", Type: enumor.String},
 	{Column: "params", NamedC: "params", Type: enumor.Json},
 	{Column: "retry", NamedC: "retry", Type: enumor.Json},
 	{Column: "depend_on", NamedC: "depend_on", Type: enumor.Json},
+	{Column: "exec_condition", NamedC: "exec_condition", Type: enumor.Json},
+	{Column: "compensate_action", NamedC: "compensate_action", Type: enumor.String},
 	{Column: "state", NamedC: "state", Type: enumor.String},
 	{Column: "reason", NamedC: "reason", Type: enumor.Json},
 	{Column: "result", NamedC: "result", Type: enumor.Json},
@@ -53,21 +56,25 @@ var AsyncFlowTaskTableColumnDescriptor = utils.ColumnDescriptors{
 
 // AsyncFlowTaskTable define async_flow_task table.
 type AsyncFlowTaskTable struct {
-	ID         string            `db:"id" json:"id" validate:"lte=64"`
-	FlowID     string            `db:"flow_id" json:"flow_id"`
-	FlowName   enumor.FlowName   `db:"flow_name" json:"flow_name"`
-	ActionID   string            `db:"action_id" json:"action_id"`
-	ActionName enumor.ActionName `db:"action_name" json:"action_name"`
-	Params     types.JsonField   `db:"params" json:"params"`
-	Retry      *Retry            `db:"retry" json:"retry"`
-	DependOn   types.StringArray `db:"depend_on" json:"depend_on"`
-	State      enumor.TaskState  `db:"state" json:"state"`
-	Reason     *Reason           `db:"reason" json:"reason"`
-	Result     types.JsonField   `db:"result" json:"result"`
-	Creator    string            `db:"creator" json:"creator" validate:"lte=64"`
-	Reviser    string            `db:"reviser" json:"reviser" validate:"lte=64"`
-	CreatedAt  types.Time        `db:"created_at" json:"created_at" validate:"excluded_unless"`
-	UpdatedAt  types.Time        `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+	ID            string            `db:"id" json:"id" validate:"lte=64"`
+	FlowID        string            `db:"flow_id" json:"flow_id"`
+	FlowName      enumor.FlowName   `db:"flow_name" json:"flow_name"`
+	ActionID      string            `db:"action_id" json:"action_id"`
+	ActionName    enumor.ActionName `db:"action_name" json:"action_name"`
+	Kind          enumor.TaskKind   `db:"kind" json:"kind" validate:"lte=16"`
+	Params        types.JsonField   `db:"params" json:"params"`
+	Retry         *Retry            `db:"retry" json:"retry"`
+	DependOn      types.StringArray `db:"depend_on" json:"depend_on"`
+	ExecCondition *Condition        `db:"exec_condition" json:"exec_condition"`
+	// CompensateAction 补偿动作，任务流开启补偿模式且执行失败后，用于撤销该任务已经执行成功的操作
+	CompensateAction enumor.ActionName `db:"compensate_action" json:"compensate_action" validate:"lte=64"`
+	State            enumor.TaskState  `db:"state" json:"state"`
+	Reason           *Reason           `db:"reason" json:"reason"`
+	Result           types.JsonField   `db:"result" json:"result"`
+	Creator          string            `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser          string            `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt        types.Time        `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt        types.Time        `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
 }
 
 // TableName return async_flow_task table name.
@@ -98,6 +105,12 @@ func (a AsyncFlowTaskTable) InsertValidate() error {
 		return errors.New("action_name is required")
 	}
 
+	if len(a.Kind) != 0 {
+		if err := a.Kind.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if len(a.Creator) == 0 {
 		return errors.New("creator is required")
 	}
@@ -128,6 +141,18 @@ func (a AsyncFlowTaskTable) UpdateValidate() error {
 		return errors.New("action_name can not update")
 	}
 
+	if len(a.Kind) != 0 {
+		return errors.New("kind can not update")
+	}
+
+	if a.ExecCondition != nil {
+		return errors.New("exec_condition can not update")
+	}
+
+	if len(a.CompensateAction) != 0 {
+		return errors.New("compensate_action can not update")
+	}
+
 	if len(a.Creator) != 0 {
 		return errors.New("creator can not update")
 	}
@@ -0,0 +1,94 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tableasync
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AsyncFlowTaskLogColumns defines all the async_flow_task_log table's columns.
+var AsyncFlowTaskLogColumns = utils.MergeColumns(nil, AsyncFlowTaskLogTableColumnDescriptor)
+
+// AsyncFlowTaskLogTableColumnDescriptor is async_flow_task_log's column descriptors.
+var AsyncFlowTaskLogTableColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "flow_id", NamedC: "flow_id", Type: enumor.String},
+	{Column: "task_id", NamedC: "task_id", Type: enumor.String},
+	{Column: "attempt", NamedC: "attempt", Type: enumor.Numeric},
+	{Column: "kind", NamedC: "kind", Type: enumor.String},
+	{Column: "level", NamedC: "level", Type: enumor.String},
+	{Column: "message", NamedC: "message", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// AsyncFlowTaskLogTable define async_flow_task_log table.
+type AsyncFlowTaskLogTable struct {
+	ID     string `db:"id" json:"id" validate:"lte=64"`
+	FlowID string `db:"flow_id" json:"flow_id" validate:"lte=64"`
+	TaskID string `db:"task_id" json:"task_id" validate:"lte=64"`
+	// Attempt 任务第几次执行，从1开始，0表示日志不属于任何一次执行
+	Attempt uint                `db:"attempt" json:"attempt"`
+	Kind    enumor.TaskLogKind  `db:"kind" json:"kind"`
+	Level   enumor.TaskLogLevel `db:"level" json:"level"`
+	// Message 日志内容，执行结果类型的日志记录本次执行失败的原因
+	Message   string     `db:"message" json:"message" validate:"lte=4096"`
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+}
+
+// TableName return async_flow_task_log table name.
+func (a AsyncFlowTaskLogTable) TableName() table.Name {
+	return table.AsyncFlowTaskLogTable
+}
+
+// InsertValidate async_flow_task_log table when insert.
+func (a AsyncFlowTaskLogTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if len(a.FlowID) == 0 {
+		return errors.New("flow_id is required")
+	}
+
+	if len(a.TaskID) == 0 {
+		return errors.New("task_id is required")
+	}
+
+	if err := a.Kind.Validate(); err != nil {
+		return err
+	}
+
+	if err := a.Level.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
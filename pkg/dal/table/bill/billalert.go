@@ -0,0 +1,141 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AccountBillAlertColumns defines account_bill_alert's columns.
+var AccountBillAlertColumns = utils.MergeColumns(nil, AccountBillAlertColumnDescriptor)
+
+// AccountBillAlertColumnDescriptor is account_bill_alert's column descriptors.
+var AccountBillAlertColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "alert_type", NamedC: "alert_type", Type: enumor.String},
+	{Column: "budget_id", NamedC: "budget_id", Type: enumor.String},
+	{Column: "scope_type", NamedC: "scope_type", Type: enumor.String},
+	{Column: "scope_id", NamedC: "scope_id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "bill_year", NamedC: "bill_year", Type: enumor.Numeric},
+	{Column: "bill_month", NamedC: "bill_month", Type: enumor.Numeric},
+	{Column: "bill_day", NamedC: "bill_day", Type: enumor.Numeric},
+	{Column: "threshold", NamedC: "threshold", Type: enumor.Numeric},
+	{Column: "amount", NamedC: "amount", Type: enumor.Numeric},
+	{Column: "cost", NamedC: "cost", Type: enumor.Numeric},
+	{Column: "expected_cost", NamedC: "expected_cost", Type: enumor.Numeric},
+	{Column: "currency", NamedC: "currency", Type: enumor.String},
+	{Column: "message", NamedC: "message", Type: enumor.String},
+	{Column: "receivers", NamedC: "receivers", Type: enumor.Json},
+	{Column: "notify_state", NamedC: "notify_state", Type: enumor.String},
+	{Column: "dedup_key", NamedC: "dedup_key", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AccountBillAlert account_bill_alert表，存储已触发的账单告警记录
+type AccountBillAlert struct {
+	// ID 自增ID
+	ID string `db:"id" validate:"lte=64" json:"id"`
+	// AlertType 告警类型：预算告警、费用异常告警
+	AlertType enumor.BillAlertType `db:"alert_type" json:"alert_type"`
+	// BudgetID 触发告警的预算ID，费用异常告警为空
+	BudgetID string `db:"budget_id" json:"budget_id"`
+	// ScopeType 告警范围类型
+	ScopeType enumor.BillBudgetScopeType `db:"scope_type" json:"scope_type"`
+	// ScopeID 告警范围ID
+	ScopeID string `db:"scope_id" json:"scope_id"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" json:"vendor"`
+	// BillYear 账单年份
+	BillYear int `db:"bill_year" json:"bill_year"`
+	// BillMonth 账单月份
+	BillMonth int `db:"bill_month" json:"bill_month"`
+	// BillDay 账单日期，预算告警为0
+	BillDay int `db:"bill_day" json:"bill_day"`
+	// Threshold 触发的预算阈值百分比
+	Threshold int64 `db:"threshold" json:"threshold"`
+	// Amount 预算金额
+	Amount *types.Decimal `db:"amount" json:"amount"`
+	// Cost 触发告警时的费用
+	Cost *types.Decimal `db:"cost" json:"cost"`
+	// ExpectedCost 费用异常告警时的预期费用，即历史均值
+	ExpectedCost *types.Decimal `db:"expected_cost" json:"expected_cost"`
+	// Currency 币种
+	Currency enumor.CurrencyCode `db:"currency" json:"currency"`
+	// Message 告警内容
+	Message string `db:"message" validate:"lte=1024" json:"message"`
+	// Receivers 告警接收人
+	Receivers types.StringArray `db:"receivers" json:"receivers"`
+	// NotifyState 通知状态
+	NotifyState enumor.BillAlertNotifyState `db:"notify_state" json:"notify_state"`
+	// DedupKey 告警去重标识，同一标识只告警一次
+	DedupKey string `db:"dedup_key" validate:"lte=255" json:"dedup_key"`
+	// Creator 创建者
+	Creator string `db:"creator" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName 返回账单告警记录表名
+func (a *AccountBillAlert) TableName() table.Name {
+	return table.AccountBillAlertTable
+}
+
+// InsertValidate validate account bill alert on insert
+func (a *AccountBillAlert) InsertValidate() error {
+	if len(a.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(a.AlertType) == 0 {
+		return errors.New("alert_type is required")
+	}
+	if len(a.ScopeType) == 0 || len(a.ScopeID) == 0 {
+		return errors.New("scope_type and scope_id is required")
+	}
+	if a.BillYear == 0 || a.BillMonth == 0 {
+		return errors.New("bill_year and bill_month is required")
+	}
+	if a.Cost == nil {
+		return errors.New("cost is required")
+	}
+	if len(a.NotifyState) == 0 {
+		return errors.New("notify_state is required")
+	}
+	if len(a.DedupKey) == 0 {
+		return errors.New("dedup_key is required")
+	}
+	if err := validator.Validate.Struct(a); err != nil {
+		return err
+	}
+	return nil
+}
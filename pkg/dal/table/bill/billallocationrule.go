@@ -0,0 +1,129 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AccountBillAllocationRuleColumns defines account_bill_allocation_rule's columns.
+var AccountBillAllocationRuleColumns = utils.MergeColumns(nil, AccountBillAllocationRuleColumnDescriptor)
+
+// AccountBillAllocationRuleColumnDescriptor is account_bill_allocation_rule's column descriptors.
+var AccountBillAllocationRuleColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "root_account_id", NamedC: "root_account_id", Type: enumor.String},
+	{Column: "main_account_id", NamedC: "main_account_id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "version", NamedC: "version", Type: enumor.Numeric},
+	{Column: "effective_year", NamedC: "effective_year", Type: enumor.Numeric},
+	{Column: "effective_month", NamedC: "effective_month", Type: enumor.Numeric},
+	{Column: "rule_type", NamedC: "rule_type", Type: enumor.String},
+	{Column: "config", NamedC: "config", Type: enumor.Json},
+	{Column: "default_bk_biz_id", NamedC: "default_bk_biz_id", Type: enumor.Numeric},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AccountBillAllocationRule account_bill_allocation_rule表，存储二级账号分账规则，规则只新增版本不修改
+type AccountBillAllocationRule struct {
+	// ID 自增ID
+	ID string `db:"id" validate:"lte=64" json:"id"`
+	// RootAccountID 一级账号ID
+	RootAccountID string `db:"root_account_id" validate:"lte=64" json:"root_account_id"`
+	// MainAccountID 二级账号ID
+	MainAccountID string `db:"main_account_id" validate:"lte=64" json:"main_account_id"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" json:"vendor"`
+	// Version 规则版本，同一二级账号内递增
+	Version int `db:"version" json:"version"`
+	// EffectiveYear 生效年份
+	EffectiveYear int `db:"effective_year" json:"effective_year"`
+	// EffectiveMonth 生效月份
+	EffectiveMonth int `db:"effective_month" json:"effective_month"`
+	// RuleType 分账规则类型：按比例、按标签、按资源归属、按用量占比
+	RuleType enumor.BillAllocationRuleType `db:"rule_type" json:"rule_type"`
+	// Config 分账规则配置
+	Config types.JsonField `db:"config" json:"config"`
+	// DefaultBkBizID 无法归属的费用分摊到的默认业务，未设置时使用二级账号所属业务
+	DefaultBkBizID int64 `db:"default_bk_biz_id" json:"default_bk_biz_id"`
+	// Enabled 是否启用，未启用的版本表示从生效月份开始不再分摊
+	Enabled *bool `db:"enabled" json:"enabled"`
+	// Memo 备注
+	Memo *string `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+	// Creator 创建者
+	Creator string `db:"creator" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName 返回分账规则表名
+func (r *AccountBillAllocationRule) TableName() table.Name {
+	return table.AccountBillAllocationRuleTable
+}
+
+// InsertValidate validate account bill allocation rule on insert
+func (r *AccountBillAllocationRule) InsertValidate() error {
+	if len(r.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(r.RootAccountID) == 0 {
+		return errors.New("root_account_id is required")
+	}
+	if len(r.MainAccountID) == 0 {
+		return errors.New("main_account_id is required")
+	}
+	if len(r.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+	if r.Version <= 0 {
+		return errors.New("version should be positive")
+	}
+	if r.EffectiveYear == 0 || r.EffectiveMonth == 0 {
+		return errors.New("effective_year and effective_month is required")
+	}
+	if err := r.RuleType.Validate(); err != nil {
+		return err
+	}
+	if len(r.Config) == 0 {
+		return errors.New("config is required")
+	}
+	if r.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+	if err := validator.Validate.Struct(r); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AccountBillBudgetColumns defines account_bill_budget's columns.
+var AccountBillBudgetColumns = utils.MergeColumns(nil, AccountBillBudgetColumnDescriptor)
+
+// AccountBillBudgetColumnDescriptor is account_bill_budget's column descriptors.
+var AccountBillBudgetColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "scope_type", NamedC: "scope_type", Type: enumor.String},
+	{Column: "scope_id", NamedC: "scope_id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "amount", NamedC: "amount", Type: enumor.Numeric},
+	{Column: "thresholds", NamedC: "thresholds", Type: enumor.Json},
+	{Column: "receivers", NamedC: "receivers", Type: enumor.Json},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AccountBillBudget account_bill_budget表，存储月度账单预算
+type AccountBillBudget struct {
+	// ID 自增ID
+	ID string `db:"id" validate:"lte=64" json:"id"`
+	// Name 预算名称
+	Name string `db:"name" validate:"lte=64" json:"name"`
+	// ScopeType 预算范围类型：业务、二级账号、一级账号、运营产品
+	ScopeType enumor.BillBudgetScopeType `db:"scope_type" json:"scope_type"`
+	// ScopeID 预算范围ID，如业务ID、账号ID、运营产品ID
+	ScopeID string `db:"scope_id" validate:"lte=64" json:"scope_id"`
+	// Vendor 云厂商，为空表示不区分云厂商
+	Vendor enumor.Vendor `db:"vendor" json:"vendor"`
+	// Amount 月度预算金额，单位人民币
+	Amount *types.Decimal `db:"amount" json:"amount"`
+	// Thresholds 告警阈值，预算金额的百分比，如 [80, 100]
+	Thresholds types.Int64Array `db:"thresholds" json:"thresholds"`
+	// Receivers 告警接收人
+	Receivers types.StringArray `db:"receivers" json:"receivers"`
+	// Enabled 是否启用
+	Enabled *bool `db:"enabled" json:"enabled"`
+	// Memo 备注
+	Memo *string `db:"memo" validate:"omitempty,lte=255" json:"memo"`
+	// Creator 创建者
+	Creator string `db:"creator" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName 返回账单预算表名
+func (b *AccountBillBudget) TableName() table.Name {
+	return table.AccountBillBudgetTable
+}
+
+// InsertValidate validate account bill budget on insert
+func (b *AccountBillBudget) InsertValidate() error {
+	if len(b.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(b.Name) == 0 {
+		return errors.New("name is required")
+	}
+	if err := b.ScopeType.Validate(); err != nil {
+		return err
+	}
+	if len(b.ScopeID) == 0 {
+		return errors.New("scope_id is required")
+	}
+	if b.Amount == nil || !b.Amount.IsPositive() {
+		return errors.New("amount should be positive")
+	}
+	if len(b.Thresholds) == 0 {
+		return errors.New("thresholds is required")
+	}
+	if b.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+	if err := validator.Validate.Struct(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateValidate validate account bill budget on update
+func (b *AccountBillBudget) UpdateValidate() error {
+	if len(b.ScopeType) != 0 || len(b.ScopeID) != 0 {
+		return errors.New("scope can not update")
+	}
+	if b.Amount != nil && !b.Amount.IsPositive() {
+		return errors.New("amount should be positive")
+	}
+	if len(b.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+	if err := validator.Validate.Struct(b); err != nil {
+		return err
+	}
+	return nil
+}
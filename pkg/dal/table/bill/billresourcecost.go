@@ -0,0 +1,145 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package bill
+
+import (
+	"errors"
+	"fmt"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// AccountBillResourceCostColumns defines account_bill_resource_cost's columns.
+var AccountBillResourceCostColumns = utils.MergeColumns(nil, AccountBillResourceCostColumnDescriptor)
+
+// AccountBillResourceCostColumnDescriptor is account_bill_resource_cost's column descriptors.
+var AccountBillResourceCostColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "root_account_id", NamedC: "root_account_id", Type: enumor.String},
+	{Column: "main_account_id", NamedC: "main_account_id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "product_id", NamedC: "product_id", Type: enumor.Numeric},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "bill_year", NamedC: "bill_year", Type: enumor.Numeric},
+	{Column: "bill_month", NamedC: "bill_month", Type: enumor.Numeric},
+	{Column: "bill_day", NamedC: "bill_day", Type: enumor.Numeric},
+	{Column: "version_id", NamedC: "version_id", Type: enumor.Numeric},
+	{Column: "res_cloud_id", NamedC: "res_cloud_id", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "currency", NamedC: "currency", Type: enumor.String},
+	{Column: "cost", NamedC: "cost", Type: enumor.Numeric},
+	{Column: "count", NamedC: "count", Type: enumor.Numeric},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// AccountBillResourceCost account_bill_resource_cost表，存储按资源维度每天汇总的账单费用
+type AccountBillResourceCost struct {
+	// ID 自增ID
+	ID string `db:"id" validate:"lte=64" json:"id"`
+	// RootAccountID 一级账号ID
+	RootAccountID string `db:"root_account_id" json:"root_account_id"`
+	// MainAccountID 账号ID
+	MainAccountID string `db:"main_account_id" json:"main_account_id"`
+	// Vendor 云厂商
+	Vendor enumor.Vendor `db:"vendor" json:"vendor"`
+	// ProductID 运营产品ID
+	ProductID int64 `db:"product_id" json:"product_id"`
+	// BkBizID 业务ID
+	BkBizID int64 `db:"bk_biz_id" json:"bk_biz_id"`
+	// BillYear 账单年份
+	BillYear int `db:"bill_year" json:"bill_year"`
+	// BillMonth 账单月份
+	BillMonth int `db:"bill_month" json:"bill_month"`
+	// BillDay 账单日期
+	BillDay int `db:"bill_day" json:"bill_day"`
+	// VersionID 版本号
+	VersionID int `db:"version_id" json:"version_id"`
+	// ResCloudID 从账单明细中提取的资源云ID
+	ResCloudID string `db:"res_cloud_id" validate:"lte=255" json:"res_cloud_id"`
+	// ResType 关联到的资源类型，未关联到同步资源时为空
+	ResType enumor.CloudResourceType `db:"res_type" json:"res_type"`
+	// ResID 关联到的资源ID，未关联到同步资源时为空
+	ResID string `db:"res_id" json:"res_id"`
+	// Currency 币种
+	Currency enumor.CurrencyCode `db:"currency" json:"currency"`
+	// Cost 费用
+	Cost *types.Decimal `db:"cost" json:"cost"`
+	// Count 账单明细条数
+	Count int64 `db:"count" json:"count"`
+	// Creator 创建者
+	Creator string `db:"creator" json:"creator"`
+	// Reviser 更新者
+	Reviser string `db:"reviser" json:"reviser"`
+	// CreatedAt 创建时间
+	CreatedAt types.Time `db:"created_at" json:"created_at"`
+	// UpdatedAt 更新时间
+	UpdatedAt types.Time `db:"updated_at" json:"updated_at"`
+}
+
+// TableName 返回资源维度日账单表名
+func (rc *AccountBillResourceCost) TableName() table.Name {
+	return table.AccountBillResourceCostTable
+}
+
+// InsertValidate validate account bill resource cost on insert
+func (rc *AccountBillResourceCost) InsertValidate() error {
+	if len(rc.ID) == 0 {
+		return errors.New("id is required")
+	}
+	if len(rc.Vendor) == 0 {
+		return errors.New("vendor is required")
+	}
+	if len(rc.RootAccountID) == 0 {
+		return errors.New("root_account_id is required")
+	}
+	if len(rc.MainAccountID) == 0 {
+		return errors.New("main_account_id is required")
+	}
+	if rc.BillYear == 0 {
+		return errors.New("bill_year is required")
+	}
+	if rc.BillMonth == 0 {
+		return errors.New("bill_month is required")
+	}
+	if rc.BillDay == 0 {
+		return errors.New("bill_day is required")
+	}
+	if rc.VersionID < 0 {
+		return fmt.Errorf("version_id %d is invalid", rc.VersionID)
+	}
+	if len(rc.ResCloudID) == 0 {
+		return errors.New("res_cloud_id is required")
+	}
+	if rc.Cost == nil {
+		return errors.New("cost is required")
+	}
+	if err := validator.Validate.Struct(rc); err != nil {
+		return err
+	}
+	return nil
+}
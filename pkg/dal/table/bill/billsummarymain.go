@@ -60,6 +60,18 @@ var AccountBillSummaryMainColumnDescriptor = utils.ColumnDescriptors{
 	{Column: "rate", NamedC: "rate", Type: enumor.Numeric},
 	{Column: "adjustment_cost", NamedC: "adjustment_cost", Type: enumor.Numeric},
 	{Column: "adjustment_rmb_cost", NamedC: "adjustment_rmb_cost", Type: enumor.Numeric},
+	{Column: "forecast_month_rmb_cost", NamedC: "forecast_month_rmb_cost", Type: enumor.Numeric},
+	{Column: "forecast_month_rmb_lower", NamedC: "forecast_month_rmb_lower", Type: enumor.Numeric},
+	{Column: "forecast_month_rmb_upper", NamedC: "forecast_month_rmb_upper", Type: enumor.Numeric},
+	{Column: "forecast_quarter_rmb_cost", NamedC: "forecast_quarter_rmb_cost", Type: enumor.Numeric},
+	{Column: "forecast_quarter_rmb_lower", NamedC: "forecast_quarter_rmb_lower", Type: enumor.Numeric},
+	{Column: "forecast_quarter_rmb_upper", NamedC: "forecast_quarter_rmb_upper", Type: enumor.Numeric},
+	{Column: "forecast_month_cost", NamedC: "forecast_month_cost", Type: enumor.Numeric},
+	{Column: "forecast_month_lower", NamedC: "forecast_month_lower", Type: enumor.Numeric},
+	{Column: "forecast_month_upper", NamedC: "forecast_month_upper", Type: enumor.Numeric},
+	{Column: "forecast_quarter_cost", NamedC: "forecast_quarter_cost", Type: enumor.Numeric},
+	{Column: "forecast_quarter_lower", NamedC: "forecast_quarter_lower", Type: enumor.Numeric},
+	{Column: "forecast_quarter_upper", NamedC: "forecast_quarter_upper", Type: enumor.Numeric},
 	{Column: "state", NamedC: "state", Type: enumor.String},
 	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
 	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
@@ -117,6 +129,30 @@ type AccountBillSummaryMain struct {
 	AdjustmentCost *types.Decimal `db:"adjustment_cost" json:"adjustment_cost"`
 	// AdjustmentRMBCost 实时人民币调账账单
 	AdjustmentRMBCost *types.Decimal `db:"adjustment_rmb_cost" json:"adjustment_rmb_cost"`
+	// ForecastMonthRMBCost 预测月末人民币账单
+	ForecastMonthRMBCost *types.Decimal `db:"forecast_month_rmb_cost" json:"forecast_month_rmb_cost"`
+	// ForecastMonthRMBLower 预测月末人民币账单置信区间下限
+	ForecastMonthRMBLower *types.Decimal `db:"forecast_month_rmb_lower" json:"forecast_month_rmb_lower"`
+	// ForecastMonthRMBUpper 预测月末人民币账单置信区间上限
+	ForecastMonthRMBUpper *types.Decimal `db:"forecast_month_rmb_upper" json:"forecast_month_rmb_upper"`
+	// ForecastQuarterRMBCost 预测季末人民币账单
+	ForecastQuarterRMBCost *types.Decimal `db:"forecast_quarter_rmb_cost" json:"forecast_quarter_rmb_cost"`
+	// ForecastQuarterRMBLower 预测季末人民币账单置信区间下限
+	ForecastQuarterRMBLower *types.Decimal `db:"forecast_quarter_rmb_lower" json:"forecast_quarter_rmb_lower"`
+	// ForecastQuarterRMBUpper 预测季末人民币账单置信区间上限
+	ForecastQuarterRMBUpper *types.Decimal `db:"forecast_quarter_rmb_upper" json:"forecast_quarter_rmb_upper"`
+	// ForecastMonthCost 预测月末账单
+	ForecastMonthCost *types.Decimal `db:"forecast_month_cost" json:"forecast_month_cost"`
+	// ForecastMonthLower 预测月末账单置信区间下限
+	ForecastMonthLower *types.Decimal `db:"forecast_month_lower" json:"forecast_month_lower"`
+	// ForecastMonthUpper 预测月末账单置信区间上限
+	ForecastMonthUpper *types.Decimal `db:"forecast_month_upper" json:"forecast_month_upper"`
+	// ForecastQuarterCost 预测季末账单
+	ForecastQuarterCost *types.Decimal `db:"forecast_quarter_cost" json:"forecast_quarter_cost"`
+	// ForecastQuarterLower 预测季末账单置信区间下限
+	ForecastQuarterLower *types.Decimal `db:"forecast_quarter_lower" json:"forecast_quarter_lower"`
+	// ForecastQuarterUpper 预测季末账单置信区间上限
+	ForecastQuarterUpper *types.Decimal `db:"forecast_quarter_upper" json:"forecast_quarter_upper"`
 	// State 状态
 	State enumor.MainBillSummaryState `db:"state" json:"state"`
 	// CreatedAt 创建时间
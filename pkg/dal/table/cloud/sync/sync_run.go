@@ -0,0 +1,124 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package tablessync
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// SyncRunColumns defines all the sync_run table's columns.
+var SyncRunColumns = utils.MergeColumns(nil, SyncRunColumnDescriptor)
+
+// SyncRunColumnDescriptor is sync_run's column descriptors.
+var SyncRunColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "trigger_type", NamedC: "trigger_type", Type: enumor.String},
+	{Column: "status", NamedC: "status", Type: enumor.String},
+	{Column: "create_count", NamedC: "create_count", Type: enumor.Numeric},
+	{Column: "update_count", NamedC: "update_count", Type: enumor.Numeric},
+	{Column: "delete_count", NamedC: "delete_count", Type: enumor.Numeric},
+	{Column: "duration_ms", NamedC: "duration_ms", Type: enumor.Numeric},
+	{Column: "reason", NamedC: "reason", Type: enumor.String},
+	{Column: "start_at", NamedC: "start_at", Type: enumor.Time},
+	{Column: "end_at", NamedC: "end_at", Type: enumor.Time},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+}
+
+// SyncRunTable define sync_run table, each record is one execution of resource sync.
+type SyncRunTable struct {
+	ID          string                   `db:"id" json:"id" validate:"lte=64"`
+	Vendor      enumor.Vendor            `db:"vendor" json:"vendor"`
+	AccountID   string                   `db:"account_id" json:"account_id" validate:"lte=64"`
+	Region      string                   `db:"region" json:"region" validate:"lte=64"`
+	ResType     enumor.CloudResourceType `db:"res_type" json:"res_type" validate:"lte=64"`
+	TriggerType enumor.SyncTrigger       `db:"trigger_type" json:"trigger_type"`
+	Status      enumor.SyncStatus        `db:"status" json:"status"`
+	// CreateCount/UpdateCount/DeleteCount 本次同步新增、更新、删除的资源数量，包含同步的关联资源
+	CreateCount uint `db:"create_count" json:"create_count"`
+	UpdateCount uint `db:"update_count" json:"update_count"`
+	DeleteCount uint `db:"delete_count" json:"delete_count"`
+	// DurationMs 同步耗时，单位毫秒
+	DurationMs uint64 `db:"duration_ms" json:"duration_ms"`
+	// Reason 同步失败原因
+	Reason    string     `db:"reason" json:"reason" validate:"lte=4096"`
+	StartAt   time.Time  `db:"start_at" json:"start_at"`
+	EndAt     time.Time  `db:"end_at" json:"end_at"`
+	Creator   string     `db:"creator" json:"creator" validate:"lte=64"`
+	CreatedAt types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+}
+
+// TableName return sync_run table name.
+func (s SyncRunTable) TableName() table.Name {
+	return table.SyncRunTable
+}
+
+// InsertValidate sync_run table when insert.
+func (s SyncRunTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(s); err != nil {
+		return err
+	}
+
+	if len(s.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if err := s.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if len(s.AccountID) == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if len(s.ResType) == 0 {
+		return errors.New("res_type is required")
+	}
+
+	if err := s.TriggerType.Validate(); err != nil {
+		return err
+	}
+
+	if s.Status != enumor.SyncSuccess && s.Status != enumor.SyncFailed {
+		return errors.New("status should be sync_success or sync_failed")
+	}
+
+	if s.StartAt.IsZero() || s.EndAt.IsZero() {
+		return errors.New("start_at and end_at are required")
+	}
+
+	if len(s.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
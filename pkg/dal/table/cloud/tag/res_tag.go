@@ -0,0 +1,113 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tabletag defines resource tag tables.
+package tabletag
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// ResTagColumns defines all the res_tag table's columns.
+var ResTagColumns = utils.MergeColumns(nil, ResTagColumnDescriptor)
+
+// ResTagColumnDescriptor is res_tag's column descriptors.
+var ResTagColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "res_cloud_id", NamedC: "res_cloud_id", Type: enumor.String},
+	{Column: "tag_key", NamedC: "tag_key", Type: enumor.String},
+	{Column: "tag_value", NamedC: "tag_value", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// ResTagTable define res_tag table, each record is one cloud tag of a resource.
+type ResTagTable struct {
+	ID         string                   `db:"id" json:"id" validate:"lte=64"`
+	Vendor     enumor.Vendor            `db:"vendor" json:"vendor"`
+	AccountID  string                   `db:"account_id" json:"account_id" validate:"lte=64"`
+	ResType    enumor.CloudResourceType `db:"res_type" json:"res_type" validate:"lte=64"`
+	ResID      string                   `db:"res_id" json:"res_id" validate:"lte=64"`
+	ResCloudID string                   `db:"res_cloud_id" json:"res_cloud_id" validate:"lte=255"`
+	TagKey     string                   `db:"tag_key" json:"tag_key" validate:"lte=255"`
+	TagValue   string                   `db:"tag_value" json:"tag_value" validate:"lte=255"`
+	Creator    string                   `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser    string                   `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt  types.Time               `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt  types.Time               `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return res_tag table name.
+func (t ResTagTable) TableName() table.Name {
+	return table.ResTagTable
+}
+
+// InsertValidate res_tag table when insert.
+func (t ResTagTable) InsertValidate() error {
+	// length validate.
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.ID) == 0 {
+		return errors.New("id is required")
+	}
+
+	if err := t.Vendor.Validate(); err != nil {
+		return err
+	}
+
+	if len(t.AccountID) == 0 {
+		return errors.New("account_id is required")
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("res_type is required")
+	}
+
+	if len(t.ResID) == 0 {
+		return errors.New("res_id is required")
+	}
+
+	if len(t.TagKey) == 0 {
+		return errors.New("tag_key is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package diskbackup defines disk backup policy and run table.
+package diskbackup
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// DiskBackupPolicyColumns defines all the disk_backup_policy table's columns.
+var DiskBackupPolicyColumns = utils.MergeColumns(nil, DiskBackupPolicyColumnDescriptor)
+
+// DiskBackupPolicyColumnDescriptor is disk_backup_policy's column descriptors.
+var DiskBackupPolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "name", NamedC: "name", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "spec", NamedC: "spec", Type: enumor.String},
+	{Column: "retention_count", NamedC: "retention_count", Type: enumor.Numeric},
+	{Column: "target", NamedC: "target", Type: enumor.Json},
+	{Column: "alert_receivers", NamedC: "alert_receivers", Type: enumor.Json},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "scheduled_flow_id", NamedC: "scheduled_flow_id", Type: enumor.String},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// DiskBackupPolicyTable define disk_backup_policy table.
+type DiskBackupPolicyTable struct {
+	ID   string `db:"id" json:"id" validate:"lte=64"`
+	Name string `db:"name" json:"name" validate:"lte=64"`
+	// BkBizID 策略所属业务，策略只会备份该业务下的硬盘
+	BkBizID int64 `db:"bk_biz_id" json:"bk_biz_id"`
+	// Spec 标准5段式cron表达式，如：0 2 * * *
+	Spec string `db:"spec" json:"spec" validate:"lte=128"`
+	// RetentionCount 每块硬盘保留的该策略创建的快照数量，超出的最早的快照会被清理
+	RetentionCount uint `db:"retention_count" json:"retention_count"`
+	// Target 备份目标硬盘的选择条件，包括标签和过滤表达式
+	Target types.JsonField `db:"target" json:"target"`
+	// AlertReceivers 备份失败时的告警接收人
+	AlertReceivers types.StringArray `db:"alert_receivers" json:"alert_receivers"`
+	Enabled        *bool             `db:"enabled" json:"enabled"`
+	// ScheduledFlowID 按cron表达式定时执行备份的定时任务流ID
+	ScheduledFlowID string     `db:"scheduled_flow_id" json:"scheduled_flow_id" validate:"lte=64"`
+	Memo            *string    `db:"memo" json:"memo" validate:"omitempty,lte=255"`
+	Creator         string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser         string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt       types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt       types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return disk_backup_policy table name.
+func (t DiskBackupPolicyTable) TableName() table.Name {
+	return table.DiskBackupPolicyTable
+}
+
+// InsertValidate disk_backup_policy table when insert.
+func (t DiskBackupPolicyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if t.BkBizID <= 0 {
+		return errors.New("bk_biz_id is invalid")
+	}
+
+	if len(t.Spec) == 0 {
+		return errors.New("spec is required")
+	}
+
+	if t.RetentionCount == 0 {
+		return errors.New("retention_count is required")
+	}
+
+	if len(t.Target) == 0 {
+		return errors.New("target is required")
+	}
+
+	if t.Enabled == nil {
+		return errors.New("enabled is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate disk_backup_policy table when update.
+func (t DiskBackupPolicyTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if t.BkBizID != 0 {
+		return errors.New("bk_biz_id can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
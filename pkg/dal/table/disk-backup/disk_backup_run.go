@@ -0,0 +1,148 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package diskbackup
+
+import (
+	"errors"
+	"time"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// DiskBackupRunColumns defines all the disk_backup_run table's columns.
+var DiskBackupRunColumns = utils.MergeColumns(nil, DiskBackupRunColumnDescriptor)
+
+// DiskBackupRunColumnDescriptor is disk_backup_run's column descriptors.
+var DiskBackupRunColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "policy_id", NamedC: "policy_id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "trigger_type", NamedC: "trigger_type", Type: enumor.String},
+	{Column: "state", NamedC: "state", Type: enumor.String},
+	{Column: "target_count", NamedC: "target_count", Type: enumor.Numeric},
+	{Column: "success_count", NamedC: "success_count", Type: enumor.Numeric},
+	{Column: "failed_count", NamedC: "failed_count", Type: enumor.Numeric},
+	{Column: "pruned_count", NamedC: "pruned_count", Type: enumor.Numeric},
+	{Column: "failed_details", NamedC: "failed_details", Type: enumor.Json},
+	{Column: "reason", NamedC: "reason", Type: enumor.String},
+	{Column: "alerted", NamedC: "alerted", Type: enumor.Boolean},
+	{Column: "rid", NamedC: "rid", Type: enumor.String},
+	{Column: "start_at", NamedC: "start_at", Type: enumor.Time},
+	{Column: "duration_ms", NamedC: "duration_ms", Type: enumor.Numeric},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// DiskBackupRunTable define disk_backup_run table, each record is one execution of disk backup policy.
+type DiskBackupRunTable struct {
+	ID          string                    `db:"id" json:"id" validate:"lte=64"`
+	PolicyID    string                    `db:"policy_id" json:"policy_id" validate:"lte=64"`
+	BkBizID     int64                     `db:"bk_biz_id" json:"bk_biz_id"`
+	TriggerType enumor.DiskBackupTrigger  `db:"trigger_type" json:"trigger_type"`
+	State       enumor.DiskBackupRunState `db:"state" json:"state"`
+	// TargetCount 本次备份匹配到的目标硬盘数量
+	TargetCount *uint `db:"target_count" json:"target_count"`
+	// SuccessCount/FailedCount 快照创建成功、失败的硬盘数量
+	SuccessCount *uint `db:"success_count" json:"success_count"`
+	FailedCount  *uint `db:"failed_count" json:"failed_count"`
+	// PrunedCount 本次清理的过期快照数量
+	PrunedCount *uint `db:"pruned_count" json:"pruned_count"`
+	// FailedDetails 备份或清理失败的硬盘及失败原因
+	FailedDetails types.JsonField `db:"failed_details" json:"failed_details"`
+	// Reason 备份执行失败原因
+	Reason *string `db:"reason" json:"reason" validate:"omitempty,lte=4096"`
+	// Alerted 失败告警是否已发送
+	Alerted *bool     `db:"alerted" json:"alerted"`
+	Rid     string    `db:"rid" json:"rid" validate:"lte=64"`
+	StartAt time.Time `db:"start_at" json:"start_at"`
+	// DurationMs 备份耗时，单位毫秒，备份结束时更新
+	DurationMs uint64     `db:"duration_ms" json:"duration_ms"`
+	Creator    string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser    string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt  types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt  types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return disk_backup_run table name.
+func (t DiskBackupRunTable) TableName() table.Name {
+	return table.DiskBackupRunTable
+}
+
+// InsertValidate disk_backup_run table when insert.
+func (t DiskBackupRunTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.PolicyID) == 0 {
+		return errors.New("policy_id is required")
+	}
+
+	if err := t.TriggerType.Validate(); err != nil {
+		return err
+	}
+
+	if err := t.State.Validate(); err != nil {
+		return err
+	}
+
+	if t.StartAt.IsZero() {
+		return errors.New("start_at is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate disk_backup_run table when update.
+func (t DiskBackupRunTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.PolicyID) != 0 || t.BkBizID != 0 || len(t.TriggerType) != 0 || !t.StartAt.IsZero() {
+		return errors.New("policy_id, bk_biz_id, trigger_type and start_at can not update")
+	}
+
+	if len(t.State) != 0 {
+		if err := t.State.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
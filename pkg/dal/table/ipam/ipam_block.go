@@ -0,0 +1,111 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tableipam defines ip address management tables.
+package tableipam
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// IPAMBlockColumns defines all the ipam_block table's columns.
+var IPAMBlockColumns = utils.MergeColumns(nil, IPAMBlockColumnDescriptor)
+
+// IPAMBlockColumnDescriptor is ipam_block's column descriptors.
+var IPAMBlockColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "type", NamedC: "type", Type: enumor.String},
+	{Column: "res_id", NamedC: "res_id", Type: enumor.String},
+	{Column: "vendor", NamedC: "vendor", Type: enumor.String},
+	{Column: "account_id", NamedC: "account_id", Type: enumor.String},
+	{Column: "region", NamedC: "region", Type: enumor.String},
+	{Column: "vpc_id", NamedC: "vpc_id", Type: enumor.String},
+	{Column: "bk_cloud_id", NamedC: "bk_cloud_id", Type: enumor.Numeric},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "cidr", NamedC: "cidr", Type: enumor.String},
+	{Column: "ip_version", NamedC: "ip_version", Type: enumor.String},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// IPAMBlockTable define ipam_block table.
+type IPAMBlockTable struct {
+	ID string `db:"id" json:"id" validate:"lte=64"`
+	// Type 地址块类型，vpc、子网网段为同步索引，reserved 为手动预留
+	Type enumor.IPAMBlockType `db:"type" json:"type" validate:"lte=16"`
+	// ResID 地址块所属的vpc或子网ID，预留地址块为空
+	ResID     string        `db:"res_id" json:"res_id" validate:"lte=64"`
+	Vendor    enumor.Vendor `db:"vendor" json:"vendor" validate:"lte=16"`
+	AccountID string        `db:"account_id" json:"account_id" validate:"lte=64"`
+	Region    string        `db:"region" json:"region" validate:"lte=255"`
+	VpcID     string        `db:"vpc_id" json:"vpc_id" validate:"lte=64"`
+	// BkCloudID 地址块所属的云区域，同一云区域内的地址块不允许重叠
+	BkCloudID int64                `db:"bk_cloud_id" json:"bk_cloud_id"`
+	BkBizID   int64                `db:"bk_biz_id" json:"bk_biz_id"`
+	Cidr      string               `db:"cidr" json:"cidr" validate:"lte=64"`
+	IPVersion enumor.IPAddressType `db:"ip_version" json:"ip_version" validate:"lte=16"`
+	Memo      *string              `db:"memo" json:"memo" validate:"omitempty,lte=255"`
+	Creator   string               `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser   string               `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt types.Time           `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt types.Time           `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return ipam_block table name.
+func (t IPAMBlockTable) TableName() table.Name {
+	return table.IPAMBlockTable
+}
+
+// InsertValidate ipam_block table when insert.
+func (t IPAMBlockTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if err := t.Type.Validate(); err != nil {
+		return err
+	}
+
+	if t.Type != enumor.IPAMReservedBlock && len(t.ResID) == 0 {
+		return errors.New("res_id is required")
+	}
+
+	if len(t.Cidr) == 0 {
+		return errors.New("cidr is required")
+	}
+
+	if len(t.IPVersion) == 0 {
+		return errors.New("ip_version is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
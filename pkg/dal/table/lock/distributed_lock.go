@@ -0,0 +1,89 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package tablelock defines distributed lock tables.
+package tablelock
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// DistributedLockColumns defines all the distributed_lock table's columns.
+var DistributedLockColumns = utils.MergeColumns(nil, DistributedLockColumnDescriptor)
+
+// DistributedLockColumnDescriptor is distributed_lock's column descriptors.
+var DistributedLockColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "lock_key", NamedC: "lock_key", Type: enumor.String},
+	{Column: "owner", NamedC: "owner", Type: enumor.String},
+	{Column: "token", NamedC: "token", Type: enumor.Numeric},
+	{Column: "acquired_at", NamedC: "acquired_at", Type: enumor.Time},
+	{Column: "expire_at", NamedC: "expire_at", Type: enumor.Time},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// DistributedLockTable define distributed_lock table, the record of a lock key is kept after released,
+// so that the fencing token of the key keeps increasing.
+type DistributedLockTable struct {
+	LockKey string `db:"lock_key" json:"lock_key" validate:"lte=255"`
+	// Owner 锁持有者，为空表示锁已释放
+	Owner string `db:"owner" json:"owner" validate:"lte=255"`
+	// Token 加锁的 fencing token，每次加锁成功后递增
+	Token      int64      `db:"token" json:"token"`
+	AcquiredAt types.Time `db:"acquired_at" json:"acquired_at"`
+	ExpireAt   types.Time `db:"expire_at" json:"expire_at"`
+	Creator    string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser    string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt  types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt  types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return distributed_lock table name.
+func (t DistributedLockTable) TableName() table.Name {
+	return table.DistributedLockTable
+}
+
+// InsertValidate distributed_lock table when insert.
+func (t DistributedLockTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if len(t.LockKey) == 0 {
+		return errors.New("lock_key is required")
+	}
+
+	if len(t.Owner) == 0 {
+		return errors.New("owner is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
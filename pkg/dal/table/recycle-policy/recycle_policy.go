@@ -0,0 +1,132 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package recyclepolicy defines recycle policy table.
+package recyclepolicy
+
+import (
+	"errors"
+
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/criteria/validator"
+	"hcm/pkg/dal/table"
+	"hcm/pkg/dal/table/types"
+	"hcm/pkg/dal/table/utils"
+)
+
+// RecyclePolicyColumns defines all the recycle_policy table's columns.
+var RecyclePolicyColumns = utils.MergeColumns(nil, RecyclePolicyColumnDescriptor)
+
+// RecyclePolicyColumnDescriptor is recycle_policy's column descriptors.
+var RecyclePolicyColumnDescriptor = utils.ColumnDescriptors{
+	{Column: "id", NamedC: "id", Type: enumor.String},
+	{Column: "bk_biz_id", NamedC: "bk_biz_id", Type: enumor.Numeric},
+	{Column: "res_type", NamedC: "res_type", Type: enumor.String},
+	{Column: "retention_hour", NamedC: "retention_hour", Type: enumor.Numeric},
+	{Column: "detach_before_recycle", NamedC: "detach_before_recycle", Type: enumor.Boolean},
+	{Column: "snapshot_before_destroy", NamedC: "snapshot_before_destroy", Type: enumor.Boolean},
+	{Column: "notify_before_hour", NamedC: "notify_before_hour", Type: enumor.Numeric},
+	{Column: "enabled", NamedC: "enabled", Type: enumor.Boolean},
+	{Column: "memo", NamedC: "memo", Type: enumor.String},
+	{Column: "creator", NamedC: "creator", Type: enumor.String},
+	{Column: "reviser", NamedC: "reviser", Type: enumor.String},
+	{Column: "created_at", NamedC: "created_at", Type: enumor.Time},
+	{Column: "updated_at", NamedC: "updated_at", Type: enumor.Time},
+}
+
+// RecyclePolicyTable define recycle_policy table.
+type RecyclePolicyTable struct {
+	ID string `db:"id" json:"id" validate:"lte=64"`
+	// BkBizID 策略作用的业务，-1 表示作为所有业务的默认策略
+	BkBizID int64                    `db:"bk_biz_id" json:"bk_biz_id"`
+	ResType enumor.CloudResourceType `db:"res_type" json:"res_type" validate:"lte=64"`
+	// RetentionHour 资源在回收站中的保留时长，单位小时
+	RetentionHour *uint `db:"retention_hour" json:"retention_hour"`
+	// DetachBeforeRecycle 回收前是否先解绑关联资源，主机解绑的eip、硬盘不随主机一起回收
+	DetachBeforeRecycle *bool `db:"detach_before_recycle" json:"detach_before_recycle"`
+	// SnapshotBeforeDestroy 销毁硬盘前是否先创建快照
+	SnapshotBeforeDestroy *bool `db:"snapshot_before_destroy" json:"snapshot_before_destroy"`
+	// NotifyBeforeHour 销毁前多少小时通知资源负责人，0 表示不通知
+	NotifyBeforeHour *uint      `db:"notify_before_hour" json:"notify_before_hour"`
+	Enabled          *bool      `db:"enabled" json:"enabled"`
+	Memo             *string    `db:"memo" json:"memo" validate:"omitempty,lte=255"`
+	Creator          string     `db:"creator" json:"creator" validate:"lte=64"`
+	Reviser          string     `db:"reviser" json:"reviser" validate:"lte=64"`
+	CreatedAt        types.Time `db:"created_at" json:"created_at" validate:"excluded_unless"`
+	UpdatedAt        types.Time `db:"updated_at" json:"updated_at" validate:"excluded_unless"`
+}
+
+// TableName return recycle_policy table name.
+func (t RecyclePolicyTable) TableName() table.Name {
+	return table.RecyclePolicyTable
+}
+
+// InsertValidate recycle_policy table when insert.
+func (t RecyclePolicyTable) InsertValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if t.BkBizID == 0 {
+		return errors.New("bk_biz_id is required")
+	}
+
+	if len(t.ResType) == 0 {
+		return errors.New("res_type is required")
+	}
+
+	if t.RetentionHour == nil {
+		return errors.New("retention_hour is required")
+	}
+
+	if t.DetachBeforeRecycle == nil || t.SnapshotBeforeDestroy == nil || t.Enabled == nil {
+		return errors.New("detach_before_recycle, snapshot_before_destroy and enabled are required")
+	}
+
+	if t.NotifyBeforeHour == nil {
+		return errors.New("notify_before_hour is required")
+	}
+
+	if len(t.Creator) == 0 {
+		return errors.New("creator is required")
+	}
+
+	return nil
+}
+
+// UpdateValidate recycle_policy table when update.
+func (t RecyclePolicyTable) UpdateValidate() error {
+	if err := validator.Validate.Struct(t); err != nil {
+		return err
+	}
+
+	if t.BkBizID != 0 || len(t.ResType) != 0 {
+		return errors.New("bk_biz_id and res_type can not update")
+	}
+
+	if len(t.Creator) != 0 {
+		return errors.New("creator can not update")
+	}
+
+	if len(t.Reviser) == 0 {
+		return errors.New("reviser is required")
+	}
+
+	return nil
+}
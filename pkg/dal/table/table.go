@@ -115,6 +115,24 @@ const (
 
 	// AccountSyncDetailTable is account_sync_detail table's name.
 	AccountSyncDetailTable Name = "account_sync_detail"
+	// SyncRunTable is sync_run table's name.
+	SyncRunTable Name = "sync_run"
+	// ResTagTable is res_tag table's name.
+	ResTagTable Name = "res_tag"
+	// DistributedLockTable is distributed_lock table's name.
+	DistributedLockTable Name = "distributed_lock"
+	// AdmissionPolicyTable is admission_policy table's name.
+	AdmissionPolicyTable Name = "admission_policy"
+	// IPAMBlockTable is ipam_block table's name.
+	IPAMBlockTable Name = "ipam_block"
+	// RecyclePolicyTable is recycle_policy table's name.
+	RecyclePolicyTable Name = "recycle_policy"
+	// DiskSnapshotTable is disk_snapshot table's name.
+	DiskSnapshotTable Name = "disk_snapshot"
+	// DiskBackupPolicyTable is disk_backup_policy table's name.
+	DiskBackupPolicyTable Name = "disk_backup_policy"
+	// DiskBackupRunTable is disk_backup_run table's name.
+	DiskBackupRunTable Name = "disk_backup_run"
 
 	// ApplicationTable is application table name
 	ApplicationTable Name = "application"
@@ -138,6 +156,10 @@ const (
 	AsyncFlowTable Name = "async_flow"
 	// AsyncFlowTaskTable is async flow task table's name.
 	AsyncFlowTaskTable Name = "async_flow_task"
+	// AsyncScheduledFlowTable is async scheduled flow table's name.
+	AsyncScheduledFlowTable Name = "async_scheduled_flow"
+	// AsyncFlowTaskLogTable is async flow task log table's name.
+	AsyncFlowTaskLogTable Name = "async_flow_task_log"
 
 	// CloudSelectionSchemeTable is cloud selection scheme table's name.
 	CloudSelectionSchemeTable Name = "cloud_selection_scheme"
@@ -198,6 +220,14 @@ const (
 	AccountBillExchangeRateTable = "account_bill_exchange_rate"
 	// AccountBillSyncRecordTable 账单同步记录
 	AccountBillSyncRecordTable = "account_bill_sync_record"
+	// AccountBillResourceCostTable 资源维度日账单
+	AccountBillResourceCostTable = "account_bill_resource_cost"
+	// AccountBillBudgetTable 账单预算
+	AccountBillBudgetTable = "account_bill_budget"
+	// AccountBillAlertTable 账单告警记录
+	AccountBillAlertTable = "account_bill_alert"
+	// AccountBillAllocationRuleTable 分账规则
+	AccountBillAllocationRuleTable = "account_bill_allocation_rule"
 )
 
 // Validate whether the table name is valid or not.
@@ -261,6 +291,15 @@ var TableMap = map[Name]struct{}{
 	AccountBillConfigTable:       {},
 	UserCollectionTable:          {},
 	AccountSyncDetailTable:       {},
+	SyncRunTable:                 {},
+	ResTagTable:                  {},
+	DistributedLockTable:         {},
+	AdmissionPolicyTable:         {},
+	IPAMBlockTable:               {},
+	RecyclePolicyTable:           {},
+	DiskSnapshotTable:            {},
+	DiskBackupPolicyTable:        {},
+	DiskBackupRunTable:           {},
 	CloudSelectionSchemeTable:    {},
 	CloudSelectionBizTypeTable:   {},
 	CloudSelectionIdcTable:       {},
@@ -269,8 +308,10 @@ var TableMap = map[Name]struct{}{
 	// TODO: 临时方案
 	RecycleRecordTableTaskID: {},
 
-	AsyncFlowTable:     {},
-	AsyncFlowTaskTable: {},
+	AsyncFlowTable:          {},
+	AsyncFlowTaskTable:      {},
+	AsyncScheduledFlowTable: {},
+	AsyncFlowTaskLogTable:   {},
 
 	ArgumentTemplateTable: {},
 
@@ -285,6 +326,10 @@ var TableMap = map[Name]struct{}{
 	RootAccountBillConfigTable:      {},
 	AccountBillExchangeRateTable:    {},
 	AccountBillSyncRecordTable:      {},
+	AccountBillResourceCostTable:    {},
+	AccountBillBudgetTable:          {},
+	AccountBillAlertTable:           {},
+	AccountBillAllocationRuleTable:  {},
 	LoadBalancerTable:               {},
 	SecurityGroupCommonRelTable:     {},
 	LoadBalancerListenerTable:       {},
@@ -39,6 +39,8 @@ const (
 	Subnet ResourceType = "subnet"
 	// Disk defines disk hcm auth resource type
 	Disk ResourceType = "disk"
+	// DiskSnapshot defines disk snapshot hcm auth resource type
+	DiskSnapshot ResourceType = "disk_snapshot"
 	// SecurityGroup defines security group's hcm auth resource type
 	SecurityGroup ResourceType = "security_group"
 	// SecurityGroupRule defines security group rule's hcm auth resource type
@@ -104,4 +106,19 @@ const (
 
 	// AccountBillThirdParty 第三方账单拉取
 	AccountBillThirdParty ResourceType = "account_bill_third_party"
+
+	// DistributedLock 分布式锁
+	DistributedLock ResourceType = "distributed_lock"
+
+	// AdmissionPolicy 准入策略
+	AdmissionPolicy ResourceType = "admission_policy"
+
+	// IPAM 地址管理
+	IPAM ResourceType = "ipam"
+
+	// RecyclePolicy 回收策略
+	RecyclePolicy ResourceType = "recycle_policy"
+
+	// DiskBackupPolicy 硬盘备份策略
+	DiskBackupPolicy ResourceType = "disk_backup_policy"
 )
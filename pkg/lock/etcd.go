@@ -0,0 +1,229 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package lock
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	corelock "hcm/pkg/api/core/lock"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	tabletypes "hcm/pkg/dal/table/types"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcd3 "go.etcd.io/etcd/client/v3"
+)
+
+// defaultEtcdKeyPrefix etcd 后端锁 key 的前缀
+const defaultEtcdKeyPrefix = "/hcm/lock/"
+
+/*
+etcd 后端数据存储结构:
+  - {prefix}{key}: 锁，值为 etcdLockValue 的json，绑定到锁的 lease 上，lease 过期后锁被自动删除
+
+加锁通过事务比较 key 的 CreateRevision 是否为 0 实现，fencing token 为 key 的 CreateRevision，
+etcd 的 revision 全局单调递增，因此同一个 key 每次加锁得到的 token 也单调递增。
+*/
+
+func newEtcdStore(cli *etcd3.Client) store {
+	return &etcdStore{
+		cli:    cli,
+		prefix: defaultEtcdKeyPrefix,
+	}
+}
+
+type etcdStore struct {
+	cli    *etcd3.Client
+	prefix string
+}
+
+type etcdLockValue struct {
+	Owner      string `json:"owner"`
+	AcquiredAt string `json:"acquired_at"`
+}
+
+func (e *etcdStore) lockKey(key string) string {
+	return e.prefix + strings.TrimPrefix(key, "/")
+}
+
+func (e *etcdStore) acquire(kt *kit.Kit, key, owner string, ttl time.Duration) (*Lease, error) {
+	grant, err := e.cli.Grant(kt.Ctx, int64(ttl/time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(etcdLockValue{Owner: owner, AcquiredAt: time.Now().Format(constant.TimeStdFormat)})
+	if err != nil {
+		return nil, err
+	}
+
+	lockKey := e.lockKey(key)
+	resp, err := e.cli.Txn(kt.Ctx).
+		If(etcd3.Compare(etcd3.CreateRevision(lockKey), "=", 0)).
+		Then(etcd3.OpPut(lockKey, string(value), etcd3.WithLease(grant.ID))).
+		Else(etcd3.OpGet(lockKey)).
+		Commit()
+	if err != nil {
+		e.revoke(kt, grant.ID)
+		return nil, err
+	}
+
+	if !resp.Succeeded {
+		e.revoke(kt, grant.ID)
+
+		holder := ""
+		if rng := resp.Responses[0].GetResponseRange(); rng != nil && len(rng.Kvs) != 0 {
+			holder = e.decode(rng.Kvs[0]).Owner
+		}
+		return nil, errf.Newf(errf.LockHeld, "lock %s is held by %s", key, holder)
+	}
+
+	return newLease(key, owner, resp.Header.Revision, ttl, int64(grant.ID)), nil
+}
+
+func (e *etcdStore) revoke(kt *kit.Kit, id etcd3.LeaseID) {
+	if _, err := e.cli.Revoke(kt.Ctx, id); err != nil && err != rpctypes.ErrLeaseNotFound {
+		logs.Errorf("revoke etcd lease failed, lease: %d, err: %v, rid: %s", id, err, kt.Rid)
+	}
+}
+
+func (e *etcdStore) renew(kt *kit.Kit, lease *Lease) error {
+	resp, err := e.cli.Get(kt.Ctx, e.lockKey(lease.Key))
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Kvs) == 0 || resp.Kvs[0].CreateRevision != lease.Token {
+		return errf.Newf(errf.LockLost, "lock %s with token %d is lost", lease.Key, lease.Token)
+	}
+
+	if _, err = e.cli.KeepAliveOnce(kt.Ctx, etcd3.LeaseID(lease.handle)); err != nil {
+		if err == rpctypes.ErrLeaseNotFound {
+			return errf.Newf(errf.LockLost, "lock %s with token %d is lost", lease.Key, lease.Token)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (e *etcdStore) release(kt *kit.Kit, lease *Lease) error {
+	lockKey := e.lockKey(lease.Key)
+	_, err := e.cli.Txn(kt.Ctx).
+		If(etcd3.Compare(etcd3.CreateRevision(lockKey), "=", lease.Token)).
+		Then(etcd3.OpDelete(lockKey)).
+		Commit()
+	if err != nil {
+		return err
+	}
+
+	e.revoke(kt, etcd3.LeaseID(lease.handle))
+	return nil
+}
+
+func (e *etcdStore) get(kt *kit.Kit, key string) (*corelock.LockInfo, error) {
+	resp, err := e.cli.Get(kt.Ctx, e.lockKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+
+	info := e.toLockInfo(kt, resp.Kvs[0])
+	return &info, nil
+}
+
+func (e *etcdStore) list(kt *kit.Kit, prefix string) ([]corelock.LockInfo, error) {
+	resp, err := e.cli.Get(kt.Ctx, e.lockKey(prefix), etcd3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]corelock.LockInfo, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		infos = append(infos, e.toLockInfo(kt, kv))
+	}
+
+	return infos, nil
+}
+
+func (e *etcdStore) forceRelease(kt *kit.Kit, key string) error {
+	resp, err := e.cli.Delete(kt.Ctx, e.lockKey(key), etcd3.WithPrevKV())
+	if err != nil {
+		return err
+	}
+
+	// 同时撤销 lease，避免 lease 上还绑定有其他 key
+	for _, kv := range resp.PrevKvs {
+		if kv.Lease != 0 {
+			e.revoke(kt, etcd3.LeaseID(kv.Lease))
+		}
+	}
+
+	return nil
+}
+
+func (e *etcdStore) close() {
+	if err := e.cli.Close(); err != nil {
+		logs.Errorf("close lock etcd client failed, err: %v", err)
+	}
+}
+
+func (e *etcdStore) decode(kv *mvccpb.KeyValue) etcdLockValue {
+	value := etcdLockValue{}
+	if err := json.Unmarshal(kv.Value, &value); err != nil {
+		logs.Errorf("unmarshal etcd lock value failed, key: %s, err: %v", kv.Key, err)
+	}
+	return value
+}
+
+func (e *etcdStore) toLockInfo(kt *kit.Kit, kv *mvccpb.KeyValue) corelock.LockInfo {
+	value := e.decode(kv)
+	info := corelock.LockInfo{
+		Key:        strings.TrimPrefix(string(kv.Key), e.prefix),
+		Owner:      value.Owner,
+		Token:      kv.CreateRevision,
+		AcquiredAt: tabletypes.Time(value.AcquiredAt),
+	}
+
+	if kv.Lease == 0 {
+		return info
+	}
+
+	ttl, err := e.cli.TimeToLive(kt.Ctx, etcd3.LeaseID(kv.Lease))
+	if err != nil {
+		logs.Errorf("get etcd lease ttl failed, key: %s, lease: %d, err: %v, rid: %s", kv.Key, kv.Lease, err, kt.Rid)
+		return info
+	}
+
+	if ttl.TTL > 0 {
+		expireAt := time.Now().Add(time.Duration(ttl.TTL) * time.Second)
+		info.ExpireAt = tabletypes.Time(expireAt.Format(constant.TimeStdFormat))
+	}
+
+	return info
+}
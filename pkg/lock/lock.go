@@ -0,0 +1,174 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+// Package lock 提供分布式锁，支持 etcd 和 mysql 两种存储后端。
+//
+// 锁具备以下能力:
+//   - 租约: 锁在 TTL 后自动过期，可开启自动续期，续期失败或锁被强制释放时通过 Lease.Lost 通知持有者
+//   - fencing token: 同一个 key 每次加锁成功得到的 token 单调递增，下游可以据此拒绝过期持有者的写入
+//   - 持有者查询: 支持查询、列出当前被持有的锁以及强制释放锁
+package lock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	corelock "hcm/pkg/api/core/lock"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/enumor"
+	"hcm/pkg/kit"
+
+	etcd3 "go.etcd.io/etcd/client/v3"
+)
+
+// Locker 分布式锁接口
+type Locker interface {
+	// TryLock 尝试加锁，不会阻塞等待，锁被他人持有时返回 errf.LockHeld 错误
+	TryLock(kt *kit.Kit, key string, opt *Option) (*Lease, error)
+	// Unlock 释放锁，只会释放 token 匹配的锁
+	Unlock(kt *kit.Kit, lease *Lease) error
+	// Get 查询锁的持有信息，锁未被持有时返回 nil
+	Get(kt *kit.Kit, key string) (*corelock.LockInfo, error)
+	// List 列出指定前缀下当前被持有的锁
+	List(kt *kit.Kit, prefix string) ([]corelock.LockInfo, error)
+	// ForceRelease 强制释放锁，不校验持有者，原持有者续期时会发现锁已丢失
+	ForceRelease(kt *kit.Kit, key string) error
+	// Close 停止所有续期，已持有的锁在 TTL 后过期
+	Close()
+}
+
+// DefaultTTL 未指定 TTL 时锁的默认过期时间
+const DefaultTTL = 30 * time.Second
+
+// Option 加锁选项
+type Option struct {
+	// TTL 锁的过期时间，最小为1秒，未设置时为 DefaultTTL
+	TTL time.Duration
+	// Owner 锁持有者标识，未设置时为 "主机名:进程号"
+	Owner string
+	// AutoRenew 是否在锁释放前每 TTL/3 自动续期
+	AutoRenew bool
+}
+
+// Validate Option.
+func (opt *Option) Validate() error {
+	if opt.TTL < time.Second {
+		return fmt.Errorf("lock ttl should >= 1s, but got %s", opt.TTL)
+	}
+
+	return nil
+}
+
+// Lease 加锁成功后得到的租约
+type Lease struct {
+	Key   string
+	Owner string
+	// Token fencing token，同一个 key 的 token 单调递增
+	Token int64
+
+	ttl time.Duration
+	// handle 存储后端使用的租约句柄，etcd 后端为 lease id
+	handle int64
+
+	lost     chan struct{}
+	lostOnce sync.Once
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newLease(key, owner string, token int64, ttl time.Duration, handle int64) *Lease {
+	return &Lease{
+		Key:    key,
+		Owner:  owner,
+		Token:  token,
+		ttl:    ttl,
+		handle: handle,
+		lost:   make(chan struct{}),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Lost 返回锁丢失的通知 channel，锁续期失败、过期或被强制释放后该 channel 会被关闭。
+// 注意: 只有开启了 AutoRenew 的锁才会检测锁丢失。
+func (l *Lease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// IsLost 返回锁是否已丢失
+func (l *Lease) IsLost() bool {
+	select {
+	case <-l.lost:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *Lease) markLost() {
+	l.lostOnce.Do(func() {
+		close(l.lost)
+	})
+}
+
+func (l *Lease) stopRenew() {
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+}
+
+// Key 拼接锁的 key，如 Key("cloud-server", "sync", accountID) 返回 "cloud-server/sync/{accountID}"
+func Key(elem ...string) string {
+	return path.Join(elem...)
+}
+
+// New 根据存储后端类型创建分布式锁，mysql 后端的 client 为 *dataservice.Client，etcd 后端的 client 为 *etcd3.Client
+func New(typ enumor.BackendType, client interface{}) (Locker, error) {
+	var s store
+	switch typ {
+	case enumor.BackendMysql:
+		cli, ok := client.(*dataservice.Client)
+		if !ok {
+			return nil, errors.New("client is not data service client")
+		}
+		s = newMysqlStore(cli)
+	case enumor.BackendEtcd:
+		cli, ok := client.(*etcd3.Client)
+		if !ok {
+			return nil, errors.New("client is not etcd client")
+		}
+		s = newEtcdStore(cli)
+	default:
+		return nil, fmt.Errorf("unsupported lock backend type: %s", typ)
+	}
+
+	return newLocker(s, defaultOwner()), nil
+}
+
+func defaultOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}
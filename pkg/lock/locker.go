@@ -0,0 +1,194 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	corelock "hcm/pkg/api/core/lock"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+	"hcm/pkg/logs"
+)
+
+// store 分布式锁的存储后端，负责锁的原子操作，续期等通用逻辑由 locker 实现
+type store interface {
+	acquire(kt *kit.Kit, key, owner string, ttl time.Duration) (*Lease, error)
+	// renew 续期锁，锁已丢失时返回 errf.LockLost 错误
+	renew(kt *kit.Kit, lease *Lease) error
+	release(kt *kit.Kit, lease *Lease) error
+	get(kt *kit.Kit, key string) (*corelock.LockInfo, error)
+	list(kt *kit.Kit, prefix string) ([]corelock.LockInfo, error)
+	forceRelease(kt *kit.Kit, key string) error
+	close()
+}
+
+func newLocker(s store, owner string) *locker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &locker{
+		store:  s,
+		owner:  owner,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// locker 基于 store 实现的分布式锁
+type locker struct {
+	store store
+	owner string
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+var _ Locker = new(locker)
+
+// TryLock 尝试加锁
+func (l *locker) TryLock(kt *kit.Kit, key string, opt *Option) (*Lease, error) {
+	if len(key) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "lock key is required")
+	}
+
+	// 复制一份选项再设置默认值，避免修改调用方传入的选项
+	option := Option{}
+	if opt != nil {
+		option = *opt
+	}
+	opt = &option
+
+	if opt.TTL == 0 {
+		opt.TTL = DefaultTTL
+	}
+
+	if err := opt.Validate(); err != nil {
+		return nil, errf.NewFromErr(errf.InvalidParameter, err)
+	}
+
+	owner := opt.Owner
+	if len(owner) == 0 {
+		owner = l.owner
+	}
+
+	lease, err := l.store.acquire(kt, key, owner, opt.TTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if opt.AutoRenew {
+		go l.keepAlive(l.backgroundKit(kt), lease)
+	}
+
+	return lease, nil
+}
+
+// backgroundKit 续期不能使用请求的上下文，请求结束后锁仍需要继续续期，直到被释放或 locker 被关闭
+func (l *locker) backgroundKit(kt *kit.Kit) *kit.Kit {
+	sub := kt.NewSubKitWithSuffix("lock")
+	sub.Ctx = context.WithValue(l.ctx, constant.RidKey, sub.Rid)
+	return sub
+}
+
+// keepAlive 每 TTL/3 续期一次，锁已丢失或超过 TTL 没有续期成功时，标记锁丢失并退出
+func (l *locker) keepAlive(kt *kit.Kit, lease *Lease) {
+	ticker := time.NewTicker(lease.ttl / 3)
+	defer ticker.Stop()
+
+	lastRenewAt := time.Now()
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-lease.stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := l.store.renew(kt, lease)
+		if err == nil {
+			lastRenewAt = time.Now()
+			continue
+		}
+
+		if errf.IsLockLost(err) || time.Since(lastRenewAt) >= lease.ttl {
+			logs.Errorf("renew lock failed, lock is lost, key: %s, owner: %s, token: %d, err: %v, rid: %s",
+				lease.Key, lease.Owner, lease.Token, err, kt.Rid)
+			lease.markLost()
+			return
+		}
+
+		logs.Warnf("renew lock failed, will retry later, key: %s, token: %d, err: %v, rid: %s", lease.Key,
+			lease.Token, err, kt.Rid)
+	}
+}
+
+// Unlock 释放锁
+func (l *locker) Unlock(kt *kit.Kit, lease *Lease) error {
+	if lease == nil {
+		return errors.New("lease is nil")
+	}
+
+	lease.stopRenew()
+
+	// 释放锁同样不使用请求的上下文，避免请求结束后锁释放失败，只能等待锁过期
+	if err := l.store.release(l.backgroundKit(kt), lease); err != nil {
+		logs.Errorf("release lock failed, key: %s, token: %d, err: %v, rid: %s", lease.Key, lease.Token, err,
+			kt.Rid)
+		return err
+	}
+
+	return nil
+}
+
+// Get 查询锁的持有信息
+func (l *locker) Get(kt *kit.Kit, key string) (*corelock.LockInfo, error) {
+	if len(key) == 0 {
+		return nil, errf.New(errf.InvalidParameter, "lock key is required")
+	}
+
+	return l.store.get(kt, key)
+}
+
+// List 列出指定前缀下当前被持有的锁
+func (l *locker) List(kt *kit.Kit, prefix string) ([]corelock.LockInfo, error) {
+	return l.store.list(kt, prefix)
+}
+
+// ForceRelease 强制释放锁
+func (l *locker) ForceRelease(kt *kit.Kit, key string) error {
+	if len(key) == 0 {
+		return errf.New(errf.InvalidParameter, "lock key is required")
+	}
+
+	return l.store.forceRelease(kt, key)
+}
+
+// Close 停止所有续期并关闭存储后端
+func (l *locker) Close() {
+	l.closeOnce.Do(func() {
+		l.cancel()
+		l.store.close()
+	})
+}
@@ -0,0 +1,173 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package lock
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	corelock "hcm/pkg/api/core/lock"
+	"hcm/pkg/criteria/errf"
+	"hcm/pkg/kit"
+)
+
+// memStore 基于内存的 store，仅用于测试 locker 的通用逻辑
+type memStore struct {
+	lock   sync.Mutex
+	tokens map[string]int64
+	holds  map[string]corelock.LockInfo
+}
+
+func newMemStore() *memStore {
+	return &memStore{tokens: make(map[string]int64), holds: make(map[string]corelock.LockInfo)}
+}
+
+func (m *memStore) acquire(_ *kit.Kit, key, owner string, ttl time.Duration) (*Lease, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if hold, ok := m.holds[key]; ok {
+		return nil, errf.Newf(errf.LockHeld, "lock %s is held by %s", key, hold.Owner)
+	}
+
+	m.tokens[key]++
+	m.holds[key] = corelock.LockInfo{Key: key, Owner: owner, Token: m.tokens[key]}
+	return newLease(key, owner, m.tokens[key], ttl, 0), nil
+}
+
+func (m *memStore) renew(_ *kit.Kit, lease *Lease) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if hold, ok := m.holds[lease.Key]; !ok || hold.Token != lease.Token {
+		return errf.Newf(errf.LockLost, "lock %s is lost", lease.Key)
+	}
+	return nil
+}
+
+func (m *memStore) release(_ *kit.Kit, lease *Lease) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if hold, ok := m.holds[lease.Key]; ok && hold.Token == lease.Token {
+		delete(m.holds, lease.Key)
+	}
+	return nil
+}
+
+func (m *memStore) get(_ *kit.Kit, key string) (*corelock.LockInfo, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	hold, ok := m.holds[key]
+	if !ok {
+		return nil, nil
+	}
+	return &hold, nil
+}
+
+func (m *memStore) list(_ *kit.Kit, _ string) ([]corelock.LockInfo, error) {
+	return nil, nil
+}
+
+func (m *memStore) forceRelease(_ *kit.Kit, key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.holds, key)
+	return nil
+}
+
+func (m *memStore) close() {}
+
+func TestLocker(t *testing.T) {
+	kt := kit.New()
+	l := newLocker(newMemStore(), "test")
+	defer l.Close()
+
+	first, err := l.TryLock(kt, "a", nil)
+	if err != nil {
+		t.Fatalf("try lock failed, err: %v", err)
+	}
+
+	if first.Owner != "test" || first.Token != 1 {
+		t.Errorf("unexpected lease: %+v", first)
+	}
+
+	opt := &Option{Owner: "other"}
+	if _, err = l.TryLock(kt, "a", opt); !errf.IsLockHeld(err) {
+		t.Fatalf("expect lock held error, got: %v", err)
+	}
+
+	// 默认值不能写回调用方的选项
+	if opt.TTL != 0 {
+		t.Errorf("caller option should not be modified, got ttl: %s", opt.TTL)
+	}
+
+	if err = l.Unlock(kt, first); err != nil {
+		t.Fatalf("unlock failed, err: %v", err)
+	}
+
+	// 释放后重新加锁，fencing token 递增
+	second, err := l.TryLock(kt, "a", &Option{Owner: "other"})
+	if err != nil {
+		t.Fatalf("try lock after unlock failed, err: %v", err)
+	}
+
+	if second.Token <= first.Token {
+		t.Errorf("fencing token should increase, first: %d, second: %d", first.Token, second.Token)
+	}
+
+	// 过期持有者释放锁不影响新的持有者
+	if err = l.Unlock(kt, first); err != nil {
+		t.Fatalf("unlock stale lease failed, err: %v", err)
+	}
+
+	info, err := l.Get(kt, "a")
+	if err != nil {
+		t.Fatalf("get lock failed, err: %v", err)
+	}
+
+	if info == nil || info.Owner != "other" {
+		t.Errorf("lock should be held by other, got: %+v", info)
+	}
+}
+
+func TestLockerLost(t *testing.T) {
+	kt := kit.New()
+	l := newLocker(newMemStore(), "test")
+	defer l.Close()
+
+	lease, err := l.TryLock(kt, "a", &Option{TTL: time.Second, AutoRenew: true})
+	if err != nil {
+		t.Fatalf("try lock failed, err: %v", err)
+	}
+
+	if err = l.ForceRelease(kt, "a"); err != nil {
+		t.Fatalf("force release failed, err: %v", err)
+	}
+
+	select {
+	case <-lease.Lost():
+	case <-time.After(2 * time.Second):
+		t.Fatalf("lease should be lost after force release")
+	}
+}
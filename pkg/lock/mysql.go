@@ -0,0 +1,147 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+package lock
+
+import (
+	"strings"
+	"time"
+
+	"hcm/pkg/api/core"
+	corelock "hcm/pkg/api/core/lock"
+	dslock "hcm/pkg/api/data-service/lock"
+	dataservice "hcm/pkg/client/data-service"
+	"hcm/pkg/criteria/constant"
+	"hcm/pkg/dal/dao/tools"
+	"hcm/pkg/kit"
+	"hcm/pkg/runtime/filter"
+)
+
+/*
+mysql 后端通过 data-service 操作 distributed_lock 表，锁释放后记录会被保留，
+再次加锁时 token 在原记录的基础上加一，以此保证 fencing token 单调递增。
+*/
+
+func newMysqlStore(cli *dataservice.Client) store {
+	return &mysqlStore{cli: cli}
+}
+
+type mysqlStore struct {
+	cli *dataservice.Client
+}
+
+func (m *mysqlStore) acquire(kt *kit.Kit, key, owner string, ttl time.Duration) (*Lease, error) {
+	req := &dslock.AcquireReq{
+		Key:    key,
+		Owner:  owner,
+		TTLSec: int64(ttl / time.Second),
+	}
+	info, err := m.cli.Global.DistributedLock.Acquire(kt, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return newLease(key, owner, info.Token, ttl, 0), nil
+}
+
+func (m *mysqlStore) renew(kt *kit.Kit, lease *Lease) error {
+	req := &dslock.RenewReq{
+		Key:    lease.Key,
+		Token:  lease.Token,
+		TTLSec: int64(lease.ttl / time.Second),
+	}
+	return m.cli.Global.DistributedLock.Renew(kt, req)
+}
+
+func (m *mysqlStore) release(kt *kit.Kit, lease *Lease) error {
+	req := &dslock.ReleaseReq{
+		Key:   lease.Key,
+		Token: lease.Token,
+	}
+	return m.cli.Global.DistributedLock.Release(kt, req)
+}
+
+func (m *mysqlStore) get(kt *kit.Kit, key string) (*corelock.LockInfo, error) {
+	infos, err := m.listHeld(kt, tools.RuleEqual("lock_key", key))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(infos) == 0 {
+		return nil, nil
+	}
+
+	return &infos[0], nil
+}
+
+func (m *mysqlStore) list(kt *kit.Kit, prefix string) ([]corelock.LockInfo, error) {
+	if len(prefix) == 0 {
+		return m.listHeld(kt)
+	}
+
+	// cs 为包含匹配，需要再按前缀过滤
+	infos, err := m.listHeld(kt, &filter.AtomRule{Field: "lock_key", Op: filter.ContainsSensitive.Factory(),
+		Value: prefix})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]corelock.LockInfo, 0, len(infos))
+	for _, info := range infos {
+		if strings.HasPrefix(info.Key, prefix) {
+			result = append(result, info)
+		}
+	}
+
+	return result, nil
+}
+
+// listHeld 查询当前被持有(持有者不为空且未过期)的锁
+func (m *mysqlStore) listHeld(kt *kit.Kit, rules ...*filter.AtomRule) ([]corelock.LockInfo, error) {
+	rules = append(rules, tools.RuleNotEqual("owner", ""),
+		tools.RuleGreaterThan("expire_at", time.Now().Format(constant.TimeStdFormat)))
+
+	req := &core.ListReq{
+		Filter: tools.ExpressionAnd(rules...),
+		Page:   core.NewDefaultBasePage(),
+	}
+
+	result := make([]corelock.LockInfo, 0)
+	for {
+		resp, err := m.cli.Global.DistributedLock.List(kt, req)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, resp.Details...)
+		if uint(len(resp.Details)) < req.Page.Limit {
+			break
+		}
+
+		req.Page.Start += uint32(req.Page.Limit)
+	}
+
+	return result, nil
+}
+
+func (m *mysqlStore) forceRelease(kt *kit.Kit, key string) error {
+	return m.cli.Global.DistributedLock.ForceRelease(kt, &dslock.ForceReleaseReq{Key: key})
+}
+
+func (m *mysqlStore) close() {}
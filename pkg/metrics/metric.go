@@ -62,6 +62,12 @@ const (
 
 	// OrmCmdSubSys defines all the orm command related sub system.
 	OrmCmdSubSys = "orm"
+
+	// AsyncSubSys defines the async task framework related sub system.
+	AsyncSubSys = "async"
+
+	// SyncSubSys defines the cloud resource sync related sub system.
+	SyncSubSys = "sync"
 )
 
 // labels
@@ -448,7 +448,7 @@ func (ar AtomRule) Validate(opt *ExprOption) error {
 	}
 
 	if opt != nil {
-		typ, exist := opt.RuleFields[ar.Field]
+		typ, exist := opt.RuleFields[ar.RuleField()]
 		if !exist {
 			return fmt.Errorf("rule field: %s is not exist in the expr option", ar.Field)
 		}
@@ -533,13 +533,25 @@ func validateSliceElements(v interface{}, typ enumor.ColumnType) error {
 	return nil
 }
 
-// RuleField get atom rule's filed
+// RuleField get atom rule's filed, tag operator's rule field is always TagField.
 func (ar AtomRule) RuleField() string {
+	if OpType(ar.Op).IsTagOp() {
+		return TagField
+	}
+
 	return ar.Field
 }
 
 // SQLExprAndValue convert this atom rule to a mysql's sub query expression, and field's value.
 func (ar AtomRule) SQLExprAndValue(opt *SQLWhereOption) (string, map[string]interface{}, error) {
+	if tagOp, ok := ar.Op.Operator().(TagOperator); ok {
+		if opt == nil || len(opt.TagResType) == 0 {
+			return "", nil, fmt.Errorf("field %s's tag operator is not supported by this resource", ar.Field)
+		}
+
+		return tagOp.TagSQLExprAndValue(opt.TagResType, ar.Field, ar.Value)
+	}
+
 	expr, value, err := ar.Op.Operator().SQLExprAndValue(ar.Field, ar.Value)
 	if err != nil {
 		return "", nil, err
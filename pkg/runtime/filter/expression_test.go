@@ -479,3 +479,65 @@ func TestNestedSqlWhereExpr(t *testing.T) {
 		return
 	}
 }
+
+func TestTagSQLWhereExpr(t *testing.T) {
+	expr := &Expression{
+		Op: And,
+		Rules: []RuleFactory{
+			&AtomRule{Field: "vendor", Op: Equal.Factory(), Value: "tcloud"},
+			&AtomRule{Field: "tags.owner", Op: TagEqual.Factory(), Value: "tom"},
+			&AtomRule{Field: "tags.env", Op: TagIn.Factory(), Value: []interface{}{"prod", "test"}},
+		},
+	}
+
+	opt := &ExprOption{RuleFields: map[string]enumor.ColumnType{"vendor": enumor.String, TagField: enumor.String}}
+	if err := expr.Validate(opt); err != nil {
+		t.Errorf("validate tag expression failed, err: %v", err)
+		return
+	}
+
+	if err := expr.Validate(&ExprOption{RuleFields: map[string]enumor.ColumnType{"vendor": enumor.String}}); err == nil {
+		t.Errorf("tag expression should be invalid without tags rule field")
+		return
+	}
+
+	if _, _, err := expr.SQLWhereExpr(&SQLWhereOption{Priority: []string{"id"}}); err == nil {
+		t.Errorf("tag expression should not generate sql without tag resource type")
+		return
+	}
+
+	where, values, err := expr.SQLWhereExpr(&SQLWhereOption{Priority: []string{"id"}, TagResType: "cvm"})
+	if err != nil {
+		t.Errorf("generate tag sql where expression failed, err: %v", err)
+		return
+	}
+
+	if strings.Count(where, "id IN (SELECT res_id FROM res_tag WHERE res_type = :tag_res_type_") != 2 {
+		t.Errorf("tag sql where expression is wrong: %s", where)
+		return
+	}
+
+	keys := make(map[interface{}]bool)
+	for name, val := range values {
+		if strings.HasPrefix(name, "tag_key_") {
+			keys[val] = true
+		}
+	}
+
+	if !keys["owner"] || !keys["env"] || len(values) != 7 {
+		t.Errorf("tag sql where values is wrong: %v", values)
+		return
+	}
+
+	// 非interface类型的切片也需要能够校验，不能panic
+	typedOp := TagIn.Factory().Operator()
+	if err := typedOp.ValidateValue([]string{"prod", "test"}, nil); err != nil {
+		t.Errorf("validate tag_in string slice failed, err: %v", err)
+		return
+	}
+
+	if err := typedOp.ValidateValue([]int{1, 2}, nil); err == nil {
+		t.Errorf("tag_in int slice should be invalid")
+		return
+	}
+}
@@ -32,8 +32,10 @@ import (
 
 // timeFields 因为mysql在8.0.19之后才支持了带时区的时间字符串查询能力，所以，需要将带时区的时间字符串转成时UTC时间去查询。
 var timeFields = map[string]struct{}{
-	"created_at": {},
-	"updated_at": {},
+	"created_at":  {},
+	"updated_at":  {},
+	"deadline_at": {},
+	"sla_at":      {},
 }
 
 var opFactory map[OpFactory]Operator
@@ -65,6 +67,9 @@ func init() {
 	opFactory[JSONContainsPath.Factory()] = JSONContainsPathOp(JSONContainsPath)
 	opFactory[JSONNotContainsPath.Factory()] = JSONNotContainsPathOp(JSONNotContainsPath)
 	opFactory[JSONLength.Factory()] = JSONLengthOp(JSONLength)
+
+	opFactory[TagEqual.Factory()] = TagEqualOp(TagEqual)
+	opFactory[TagIn.Factory()] = TagInOp(TagIn)
 }
 
 const (
@@ -160,6 +165,22 @@ const (
 	JSONLength OpType = "json_length"
 )
 
+// 标签操作符，规则字段格式为 tags.{tag_key}，通过资源标签表 res_tag 的子查询过滤资源，
+// 需要在 SQLWhereOption 中指定 TagResType.
+const (
+	// TagEqual is resource tag equal operator.
+	TagEqual OpType = "tag_eq"
+	// TagIn is resource tag in operator.
+	TagIn OpType = "tag_in"
+)
+
+const (
+	// TagField is the rule field of resource tag operator.
+	TagField = "tags"
+	// tagTableName is the table name that stores resource tags.
+	tagTableName = "res_tag"
+)
+
 // OpType defines the operators supported by mysql.
 type OpType string
 
@@ -177,6 +198,8 @@ func (op OpType) Validate() error {
 
 	case IDGreaterThan:
 
+	case TagEqual, TagIn:
+
 	default:
 		return fmt.Errorf("unsupported operator: %s", op)
 	}
@@ -1060,3 +1083,151 @@ func (op JSONLengthOp) SQLExprAndValue(field string, value interface{}) (string,
 			placeholder: value,
 		}, nil
 }
+
+// IsTagOp returns whether the operator is a resource tag operator.
+func (op OpType) IsTagOp() bool {
+	return op == TagEqual || op == TagIn
+}
+
+// TagOperator is the resource tag operator, which filters resources with the res_tag table.
+type TagOperator interface {
+	Operator
+	// TagSQLExprAndValue generate the sub query expression of the resource type's tag.
+	TagSQLExprAndValue(resType string, field string, value interface{}) (string, map[string]interface{}, error)
+}
+
+// parseTagKey parse tag key from the tag rule field like tags.{tag_key}.
+func parseTagKey(field string) (string, error) {
+	key := strings.TrimPrefix(field, TagField+".")
+	if key == field || len(key) == 0 {
+		return "", fmt.Errorf("tag rule field should be like %s.{tag_key}, but got %s", TagField, field)
+	}
+
+	return key, nil
+}
+
+// tagSQLExpr generate the sub query expression of resource tag with value condition.
+func tagSQLExpr(resType, field, valueExpr string) (string, map[string]interface{}, error) {
+	if len(resType) == 0 {
+		return "", nil, errors.New("tag resource type is required")
+	}
+
+	key, err := parseTagKey(field)
+	if err != nil {
+		return "", nil, err
+	}
+
+	typePH := fieldPlaceholderName("tag_res_type")
+	keyPH := fieldPlaceholderName("tag_key")
+	return fmt.Sprintf(`id IN (SELECT res_id FROM %s WHERE res_type = %s%s AND tag_key = %s%s AND %s)`,
+			tagTableName, SqlPlaceholder, typePH, SqlPlaceholder, keyPH, valueExpr),
+		map[string]interface{}{typePH: resType, keyPH: key}, nil
+}
+
+// TagEqualOp is resource tag equal operator
+type TagEqualOp OpType
+
+// Name is resource tag equal operator
+func (op TagEqualOp) Name() OpType {
+	return TagEqual
+}
+
+// ValidateValue validate resource tag equal's value
+func (op TagEqualOp) ValidateValue(v interface{}, opt *ExprOption) error {
+	if reflect.ValueOf(v).Kind() != reflect.String {
+		return errors.New("tag_eq operator's value should be a string")
+	}
+
+	return nil
+}
+
+// SQLExprAndValue tag operator can not generate sql expression without resource type.
+func (op TagEqualOp) SQLExprAndValue(field string, _ interface{}) (string, map[string]interface{}, error) {
+	return "", nil, fmt.Errorf("tag operator of field %s requires tag resource type", field)
+}
+
+// TagSQLExprAndValue convert this operator's field and value to a mysql's sub query expression.
+func (op TagEqualOp) TagSQLExprAndValue(resType string, field string, value interface{}) (string,
+	map[string]interface{}, error) {
+
+	if err := op.ValidateValue(value, nil); err != nil {
+		return "", nil, err
+	}
+
+	valuePH := fieldPlaceholderName("tag_value")
+	expr, args, err := tagSQLExpr(resType, field, fmt.Sprintf("tag_value = %s%s", SqlPlaceholder, valuePH))
+	if err != nil {
+		return "", nil, err
+	}
+	args[valuePH] = value
+
+	return expr, args, nil
+}
+
+// TagInOp is resource tag in operator
+type TagInOp OpType
+
+// Name is resource tag in operator
+func (op TagInOp) Name() OpType {
+	return TagIn
+}
+
+// ValidateValue validate resource tag in's value
+func (op TagInOp) ValidateValue(v interface{}, opt *ExprOption) error {
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Array:
+	case reflect.Slice:
+	default:
+		return errors.New("tag_in operator's value should be an array")
+	}
+
+	value := reflect.ValueOf(v)
+	length := value.Len()
+	if length == 0 {
+		return errors.New("tag_in operator's value should be an non-empty array")
+	}
+
+	maxInLimit := DefaultMaxInLimit
+	if opt != nil && opt.MaxInLimit > 0 {
+		maxInLimit = opt.MaxInLimit
+	}
+
+	if length > int(maxInLimit) {
+		return fmt.Errorf("tag_in operator's elements number is overhead, at most have %d elements", maxInLimit)
+	}
+
+	for i := 0; i < length; i++ {
+		elem := value.Index(i)
+		if elem.Kind() == reflect.Interface {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.String {
+			return errors.New("tag_in operator's elements should be string")
+		}
+	}
+
+	return nil
+}
+
+// SQLExprAndValue tag operator can not generate sql expression without resource type.
+func (op TagInOp) SQLExprAndValue(field string, _ interface{}) (string, map[string]interface{}, error) {
+	return "", nil, fmt.Errorf("tag operator of field %s requires tag resource type", field)
+}
+
+// TagSQLExprAndValue convert this operator's field and value to a mysql's sub query expression.
+func (op TagInOp) TagSQLExprAndValue(resType string, field string, value interface{}) (string,
+	map[string]interface{}, error) {
+
+	if err := op.ValidateValue(value, nil); err != nil {
+		return "", nil, err
+	}
+
+	valuePH := fieldPlaceholderName("tag_value")
+	expr, args, err := tagSQLExpr(resType, field, fmt.Sprintf("tag_value IN (%s%s)", SqlPlaceholder, valuePH))
+	if err != nil {
+		return "", nil, err
+	}
+	args[valuePH] = value
+
+	return expr, args, nil
+}
@@ -68,6 +68,9 @@ type SQLWhereOption struct {
 	// field during query.
 	Priority      Priority
 	CrownedOption *CrownedOption
+	// TagResType is the resource type in res_tag table, rules with tag operator can only be used
+	// when it is set.
+	TagResType string
 }
 
 // Validate the options is valid or not
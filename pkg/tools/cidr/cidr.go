@@ -26,6 +26,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"net"
 	"sort"
 
@@ -166,3 +167,108 @@ func NextAvailableNet(outer net.IPNet, used []net.IPNet, masklen int) (net.IPNet
 	return nextAvailable, nil
 
 }
+
+// IsOverlapped 判断两个网段是否存在重叠，不同ip版本的网段不重叠
+func IsOverlapped(a, b string) (bool, error) {
+	_, netA, err := net.ParseCIDR(a)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse cidr: %w", err)
+	}
+
+	_, netB, err := net.ParseCIDR(b)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse cidr: %w", err)
+	}
+
+	startA, endA, bitsA := netRange(*netA)
+	startB, endB, bitsB := netRange(*netB)
+	if bitsA != bitsB {
+		return false, nil
+	}
+
+	return startA.Cmp(endB) <= 0 && startB.Cmp(endA) <= 0, nil
+}
+
+// FirstAvailableNet find first available net, supports ipv4 and ipv6.
+// 与 NextAvailableNet 不同，已用网段可以相互重叠，已用网段之间的空隙也会被分配。
+// Params:
+// 1. outer: 待分配的网段
+// 2. used: 已经使用的网段，可以不在outer内
+// 3. masklen: 待分配的网段掩码长度
+func FirstAvailableNet(outer net.IPNet, used []net.IPNet, masklen int) (net.IPNet, error) {
+	outerStart, outerEnd, bits := netRange(outer)
+	outerMasklen, _ := outer.Mask.Size()
+	if masklen < outerMasklen {
+		return net.IPNet{}, errors.New("new net mask length is shorter than outer net")
+	}
+	if masklen > bits {
+		return net.IPNet{}, fmt.Errorf("new net mask length is longer than %d", bits)
+	}
+
+	type ipRange struct {
+		start *big.Int
+		end   *big.Int
+	}
+	ranges := make([]ipRange, 0, len(used))
+	for _, one := range used {
+		start, end, usedBits := netRange(one)
+		if usedBits != bits {
+			continue
+		}
+		ranges = append(ranges, ipRange{start: start, end: end})
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start.Cmp(ranges[j].start) < 0 })
+
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-masklen))
+	current := new(big.Int).Set(outerStart)
+	for {
+		candidateEnd := new(big.Int).Sub(new(big.Int).Add(current, size), big.NewInt(1))
+		if candidateEnd.Cmp(outerEnd) > 0 {
+			return net.IPNet{}, errors.New("out of range")
+		}
+
+		var conflict *ipRange
+		for idx := range ranges {
+			if ranges[idx].start.Cmp(candidateEnd) <= 0 && current.Cmp(ranges[idx].end) <= 0 {
+				conflict = &ranges[idx]
+				break
+			}
+		}
+
+		if conflict == nil {
+			return net.IPNet{IP: bigToIP(current, bits), Mask: net.CIDRMask(masklen, bits)}, nil
+		}
+
+		// 跳过冲突的网段，并按待分配网段的大小对齐
+		next := new(big.Int).Add(conflict.end, big.NewInt(1))
+		next.Add(next, new(big.Int).Sub(size, big.NewInt(1)))
+		next.Div(next, size)
+		next.Mul(next, size)
+		current = next
+	}
+}
+
+// netRange 返回网段的起止地址及ip地址位数
+func netRange(ipNet net.IPNet) (*big.Int, *big.Int, int) {
+	ip := ipNet.IP.To4()
+	if ip == nil {
+		ip = ipNet.IP.To16()
+	}
+	bits := len(ip) * 8
+
+	ones, _ := ipNet.Mask.Size()
+	if len(ipNet.Mask) != len(ip) {
+		ones -= len(ipNet.Mask)*8 - bits
+	}
+
+	start := new(big.Int).SetBytes(ip.Mask(net.CIDRMask(ones, bits)))
+	hostCount := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	end := new(big.Int).Sub(new(big.Int).Add(start, hostCount), big.NewInt(1))
+	return start, end, bits
+}
+
+func bigToIP(value *big.Int, bits int) net.IP {
+	ip := make(net.IP, bits/8)
+	value.FillBytes(ip)
+	return ip
+}
@@ -183,3 +183,72 @@ func TestNextAvailableNetByIPNum(t *testing.T) {
 
 	}
 }
+
+func TestIsOverlapped(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"10.0.0.0/16", "10.0.1.0/24", true},
+		{"10.0.0.0/16", "10.1.0.0/16", false},
+		{"10.0.0.0/8", "10.255.255.0/24", true},
+		{"192.168.0.0/24", "192.168.0.255/32", true},
+		{"fd00::/64", "fd00::1/128", true},
+		{"fd00::/64", "fd00:0:0:1::/64", false},
+		{"10.0.0.0/8", "::/0", false},
+	}
+
+	for _, c := range cases {
+		overlapped, err := IsOverlapped(c.a, c.b)
+		if err != nil {
+			t.Errorf("check %s and %s overlapped failed, err: %v", c.a, c.b, err)
+			continue
+		}
+		if overlapped != c.expected {
+			t.Errorf("check %s and %s overlapped, got %v, expected %v", c.a, c.b, overlapped, c.expected)
+		}
+	}
+}
+
+func TestFirstAvailableNet(t *testing.T) {
+	parse := func(cidrs ...string) []net.IPNet {
+		result := make([]net.IPNet, 0, len(cidrs))
+		for _, one := range cidrs {
+			_, ipNet, _ := net.ParseCIDR(one)
+			result = append(result, *ipNet)
+		}
+		return result
+	}
+
+	cases := []struct {
+		outer    string
+		used     []string
+		masklen  int
+		expected string
+	}{
+		// gaps between used nets are allocated.
+		{"10.0.0.0/16", []string{"10.0.0.0/24", "10.0.2.0/24"}, 24, "10.0.1.0/24"},
+		// the new net is aligned to its own size.
+		{"10.0.0.0/16", []string{"10.0.0.0/24", "10.0.2.0/24"}, 23, "10.0.4.0/23"},
+		// used nets larger than outer net and overlapped used nets.
+		{"10.0.0.0/16", []string{"10.0.0.0/8"}, 24, ""},
+		{"10.0.0.0/16", []string{"10.0.0.0/17", "10.0.64.0/18", "172.16.0.0/12"}, 17, "10.0.128.0/17"},
+		{"10.0.0.0/16", nil, 16, "10.0.0.0/16"},
+		{"fd00::/48", []string{"fd00::/64", "fd00:0:0:1::/64"}, 64, "fd00:0:0:2::/64"},
+	}
+
+	for _, c := range cases {
+		_, outer, _ := net.ParseCIDR(c.outer)
+		got, err := FirstAvailableNet(*outer, parse(c.used...), c.masklen)
+		if len(c.expected) == 0 {
+			if err == nil {
+				t.Errorf("find available net in %s, expected error, got %s", c.outer, got.String())
+			}
+			continue
+		}
+		if err != nil || got.String() != c.expected {
+			t.Errorf("find available net in %s, got %s, err: %v, expected %s", c.outer, got.String(), err,
+				c.expected)
+		}
+	}
+}
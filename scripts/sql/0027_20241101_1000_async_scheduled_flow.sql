@@ -0,0 +1,58 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0027,HCMVER=v1.6.11
+
+    Notes:
+    1. 新增`async_scheduled_flow`定时任务流表
+*/
+
+START TRANSACTION;
+
+create table if not exists `async_scheduled_flow`
+(
+    `id`                varchar(64)  not null,
+    `name`              varchar(64)  not null,
+    `flow_name`         varchar(64)  not null,
+    `spec`              varchar(128) not null,
+    `tasks`             json                  default null,
+    `memo`              varchar(255)          default '',
+    `state`             varchar(16)  not null,
+    `next_trigger_at`   timestamp    not null,
+    `last_triggered_at` timestamp    null     default null,
+    `last_flow_id`      varchar(64)           default '',
+    `creator`           varchar(64)  not null,
+    `reviser`           varchar(64)  not null,
+    `created_at`        timestamp    not null default current_timestamp,
+    `updated_at`        timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_name` (`name`),
+    index `idx_state_next_trigger_at` (`state`, `next_trigger_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('async_scheduled_flow', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.11' as `hcm_ver`, '0027' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,43 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0028,HCMVER=v1.6.12
+
+    Notes:
+    1. 修改`async_flow`表，增加`deadline_at`截止时间、`sla_at`SLA时间字段
+    2. 修改`async_flow`表，增加(`state`, `deadline_at`)、(`state`, `sla_at`)索引
+*/
+
+START TRANSACTION;
+
+alter table async_flow
+    add column `deadline_at` timestamp null default null after `worker`;
+alter table async_flow
+    add column `sla_at` timestamp null default null after `deadline_at`;
+
+alter table async_flow
+    add index idx_state_deadline_at (state, deadline_at);
+alter table async_flow
+    add index idx_state_sla_at (state, sla_at);
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.12' as `hcm_ver`, '0028' as `sql_ver`;
+
+COMMIT;
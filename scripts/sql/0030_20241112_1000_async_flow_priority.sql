@@ -0,0 +1,41 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0030,HCMVER=v1.6.14
+
+    Notes:
+    1. 修改`async_flow`表，增加`priority`优先级、`fairness_key`公平键字段
+    2. 修改`async_flow`表，增加(`state`, `fairness_key`)索引，用于统计各公平键执行中的任务流数量
+*/
+
+START TRANSACTION;
+
+alter table async_flow
+    add column `priority` int not null default 0 after `worker`;
+alter table async_flow
+    add column `fairness_key` varchar(64) not null default '' after `priority`;
+
+alter table async_flow
+    add index idx_state_fairness_key (state, fairness_key);
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.14' as `hcm_ver`, '0030' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,51 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0031,HCMVER=v1.6.15
+
+    Notes:
+    1. 新增`async_flow_task_log`异步任务执行日志表，记录任务执行过程中输出的日志以及每次执行的结果
+*/
+
+START TRANSACTION;
+
+create table if not exists `async_flow_task_log`
+(
+    `id`         varchar(64)  not null,
+    `flow_id`    varchar(64)  not null,
+    `task_id`    varchar(64)  not null,
+    `attempt`    int unsigned not null default 0,
+    `kind`       varchar(16)  not null,
+    `level`      varchar(16)  not null,
+    `message`    text,
+    `created_at` timestamp    not null default current_timestamp,
+    primary key (`id`),
+    index `idx_task_id_attempt` (`task_id`, `attempt`),
+    index `idx_created_at` (`created_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('async_flow_task_log', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.15' as `hcm_ver`, '0031' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,42 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2024 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0032,HCMVER=v1.6.16
+
+    Notes:
+    1. 修改`async_flow`表，增加`compensate_on_failure`字段，用于开启任务流失败后的补偿模式
+    2. 修改`async_flow_task`表，增加`compensate_action`补偿动作字段
+    3. 修改`async_flow_task`表，`state`字段长度调整为32，用于存储补偿相关的任务状态
+*/
+
+START TRANSACTION;
+
+alter table async_flow
+    add column `compensate_on_failure` boolean not null default false after `fairness_key`;
+
+alter table async_flow_task
+    add column `compensate_action` varchar(64) not null default '' after `exec_condition`;
+alter table async_flow_task
+    modify column `state` varchar(32) not null;
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.16' as `hcm_ver`, '0032' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,60 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0033,HCMVER=v1.6.17
+
+    Notes:
+    1. 新增`sync_run`资源同步执行记录表，记录每次资源同步的账号、地域、资源类型、触发方式、耗时、变更数量及失败原因
+*/
+
+START TRANSACTION;
+
+create table if not exists `sync_run`
+(
+    `id`           varchar(64)     not null,
+    `vendor`       varchar(16)     not null,
+    `account_id`   varchar(64)     not null,
+    `region`       varchar(64)     not null default '',
+    `res_type`     varchar(64)     not null,
+    `trigger_type` varchar(16)     not null,
+    `status`       varchar(16)     not null,
+    `create_count` int unsigned    not null default 0,
+    `update_count` int unsigned    not null default 0,
+    `delete_count` int unsigned    not null default 0,
+    `duration_ms`  bigint unsigned not null default 0,
+    `reason`       text,
+    `start_at`     timestamp       not null default current_timestamp,
+    `end_at`       timestamp       not null default current_timestamp,
+    `creator`      varchar(64)     not null,
+    `created_at`   timestamp       not null default current_timestamp,
+    primary key (`id`),
+    index `idx_account_id_res_type_created_at` (`account_id`, `res_type`, `created_at`),
+    index `idx_created_at` (`created_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('sync_run', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.17' as `hcm_ver`, '0033' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,49 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0035,HCMVER=v1.6.19
+
+    Notes:
+    1. 新增`distributed_lock`分布式锁表，作为分布式锁的 mysql 存储后端
+*/
+
+START TRANSACTION;
+
+create table if not exists `distributed_lock`
+(
+    `lock_key`    varchar(255) not null,
+    `owner`       varchar(255) not null default '',
+    `token`       bigint(1)    not null default 0,
+    `acquired_at` timestamp    not null default current_timestamp,
+    `expire_at`   timestamp    not null default current_timestamp,
+    `creator`     varchar(64)  not null,
+    `reviser`     varchar(64)  not null,
+    `created_at`  timestamp    not null default current_timestamp,
+    `updated_at`  timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`lock_key`),
+    index `idx_expire_at` (`expire_at`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.19' as `hcm_ver`, '0035' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,58 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0036,HCMVER=v1.6.20
+
+    Notes:
+    1. 新增`admission_policy`准入策略表，在资源创建、修改请求下发到 hc-service 前按策略规则进行校验
+*/
+
+START TRANSACTION;
+
+create table if not exists `admission_policy`
+(
+    `id`         varchar(64)   not null,
+    `name`       varchar(255)  not null,
+    `res_type`   varchar(64)   not null,
+    `action`     varchar(16)   not null,
+    `mode`       varchar(16)   not null,
+    `bk_biz_ids` json          not null,
+    `rule`       json          not null,
+    `message`    varchar(1024) not null default '',
+    `enabled`    boolean                default true,
+    `memo`       varchar(255)           default '',
+    `creator`    varchar(64)   not null,
+    `reviser`    varchar(64)   not null,
+    `created_at` timestamp     not null default current_timestamp,
+    `updated_at` timestamp     not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_name` (`name`),
+    index `idx_res_type_action` (`res_type`, `action`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('admission_policy', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.20' as `hcm_ver`, '0036' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,61 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+
+/*
+    SQLVER=0037,HCMVER=v1.6.21
+
+    Notes:
+    1. 新增`ipam_block`地址块索引表，索引各云已同步的vpc、子网网段及手动预留的网段，用于跨云厂商、跨账号的网段冲突检测
+*/
+
+START TRANSACTION;
+
+create table if not exists `ipam_block`
+(
+    `id`          varchar(64)  not null,
+    `type`        varchar(16)  not null,
+    `res_id`      varchar(64)  not null default '',
+    `vendor`      varchar(16)  not null default '',
+    `account_id`  varchar(64)  not null default '',
+    `region`      varchar(255) not null default '',
+    `vpc_id`      varchar(64)  not null default '',
+    `bk_cloud_id` bigint       not null default -1,
+    `bk_biz_id`   bigint       not null default -1,
+    `cidr`        varchar(64)  not null,
+    `ip_version`  varchar(16)  not null,
+    `memo`        varchar(255)          default '',
+    `creator`     varchar(64)  not null,
+    `reviser`     varchar(64)  not null,
+    `created_at`  timestamp    not null default current_timestamp,
+    `updated_at`  timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_type_res_id_cidr` (`type`, `res_id`, `cidr`),
+    index `idx_bk_cloud_id` (`bk_cloud_id`),
+    index `idx_account_id` (`account_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('ipam_block', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.21' as `hcm_ver`, '0037' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,59 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0038,HCMVER=v1.6.22
+
+    Notes:
+    1. 新增`recycle_policy`回收策略表，按业务、资源类型配置回收站保留时长、回收前解绑、销毁前快照及销毁前通知
+    2. `load_balancer`表增加回收状态`recycle_status`字段，支持负载均衡进入回收站
+*/
+
+START TRANSACTION;
+
+create table if not exists `recycle_policy`
+(
+    `id`                      varchar(64)  not null,
+    `bk_biz_id`               bigint       not null,
+    `res_type`                varchar(64)  not null,
+    `retention_hour`          int unsigned not null,
+    `detach_before_recycle`   boolean      not null default false,
+    `snapshot_before_destroy` boolean      not null default false,
+    `notify_before_hour`      int unsigned not null default 0,
+    `enabled`                 boolean      default true,
+    `memo`                    varchar(255) default '',
+    `creator`                 varchar(64)  not null,
+    `reviser`                 varchar(64)  not null,
+    `created_at`              timestamp    not null default current_timestamp,
+    `updated_at`              timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_bk_biz_id_res_type` (`bk_biz_id`, `res_type`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('recycle_policy', '0');
+
+alter table load_balancer
+    add column `recycle_status` varchar(32) default '' after `extension`;
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.22' as `hcm_ver`, '0038' as `sql_ver`;
+
+COMMIT;
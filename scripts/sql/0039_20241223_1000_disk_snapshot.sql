@@ -0,0 +1,63 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0039,HCMVER=v1.6.23
+
+    Notes:
+    1. 新增`disk_snapshot`硬盘快照表，同步各云厂商硬盘快照并关联源硬盘
+*/
+
+START TRANSACTION;
+
+create table if not exists `disk_snapshot`
+(
+    `id`                 varchar(64)     not null,
+    `vendor`             varchar(16)     not null,
+    `account_id`         varchar(64)     not null,
+    `cloud_id`           varchar(255)    not null,
+    `name`               varchar(255)    default '',
+    `region`             varchar(255)    default '',
+    `zone`               varchar(255)    default '',
+    `disk_id`            varchar(64)     default '',
+    `cloud_disk_id`      varchar(255)    default '',
+    `disk_size`          bigint unsigned default 0,
+    `status`             varchar(32)     default '',
+    `bk_biz_id`          bigint          default -1,
+    `cloud_created_time` varchar(64)     default '',
+    `extension`          json,
+    `memo`               varchar(255)    default '',
+    `creator`            varchar(64)     not null,
+    `reviser`            varchar(64)     not null,
+    `created_at`         timestamp       not null default current_timestamp,
+    `updated_at`         timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_vendor_account_id_cloud_id` (`vendor`, `account_id`, `cloud_id`),
+    key `idx_disk_id` (`disk_id`),
+    key `idx_cloud_disk_id` (`cloud_disk_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('disk_snapshot', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.23' as `hcm_ver`, '0039' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,93 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0040,HCMVER=v1.6.24
+
+    Notes:
+    1. 新增`disk_backup_policy`硬盘备份策略表，按cron表达式定时为业务下匹配的硬盘创建快照，并按保留数量清理过期快照
+    2. 新增`disk_backup_run`硬盘备份执行记录表
+    3. `disk_snapshot`表增加`backup_policy_id`字段，记录创建快照的备份策略
+*/
+
+START TRANSACTION;
+
+create table if not exists `disk_backup_policy`
+(
+    `id`                varchar(64)  not null,
+    `name`              varchar(64)  not null,
+    `bk_biz_id`         bigint       not null,
+    `spec`              varchar(128) not null,
+    `retention_count`   int unsigned not null,
+    `target`            json         not null,
+    `alert_receivers`   json,
+    `enabled`           boolean      default true,
+    `scheduled_flow_id` varchar(64)  default '',
+    `memo`              varchar(255) default '',
+    `creator`           varchar(64)  not null,
+    `reviser`           varchar(64)  not null,
+    `created_at`        timestamp    not null default current_timestamp,
+    `updated_at`        timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_bk_biz_id_name` (`bk_biz_id`, `name`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+create table if not exists `disk_backup_run`
+(
+    `id`             varchar(64)     not null,
+    `policy_id`      varchar(64)     not null,
+    `bk_biz_id`      bigint          not null,
+    `trigger_type`   varchar(32)     not null,
+    `state`          varchar(32)     not null,
+    `target_count`   int unsigned    default 0,
+    `success_count`  int unsigned    default 0,
+    `failed_count`   int unsigned    default 0,
+    `pruned_count`   int unsigned    default 0,
+    `failed_details` json,
+    `reason`         varchar(4096)   default '',
+    `alerted`        boolean         default false,
+    `rid`            varchar(64)     default '',
+    `start_at`       timestamp       not null default current_timestamp,
+    `duration_ms`    bigint unsigned default 0,
+    `creator`        varchar(64)     not null,
+    `reviser`        varchar(64)     not null,
+    `created_at`     timestamp       not null default current_timestamp,
+    `updated_at`     timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    key `idx_policy_id_start_at` (`policy_id`, `start_at`),
+    key `idx_state_alerted` (`state`, `alerted`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('disk_backup_policy', '0'),
+       ('disk_backup_run', '0');
+
+alter table disk_snapshot
+    add column `backup_policy_id` varchar(64) default '' after `bk_biz_id`;
+
+alter table disk_snapshot
+    add index `idx_backup_policy_id` (`backup_policy_id`);
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.24' as `hcm_ver`, '0040' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,67 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0041,HCMVER=v1.6.25
+
+    Notes:
+    1. 新增`account_bill_resource_cost`资源维度日账单表，由日分账流程从账单明细中提取资源云ID并按天汇总，
+       并关联主机、硬盘、弹性IP、负载均衡资源
+*/
+
+START TRANSACTION;
+
+create table if not exists `account_bill_resource_cost`
+(
+    `id`              varchar(64)     not null,
+    `root_account_id` varchar(64)     not null,
+    `main_account_id` varchar(64)     not null,
+    `vendor`          varchar(32)     not null,
+    `product_id`      bigint          default 0,
+    `bk_biz_id`       bigint          default 0,
+    `bill_year`       int             not null,
+    `bill_month`      tinyint(1)      not null,
+    `bill_day`        tinyint(1)      not null,
+    `version_id`      int             not null,
+    `res_cloud_id`    varchar(255)    not null,
+    `res_type`        varchar(64)     default '',
+    `res_id`          varchar(64)     default '',
+    `currency`        varchar(16)     not null,
+    `cost`            decimal(38, 10) not null,
+    `count`           bigint          default 0,
+    `creator`         varchar(64)     not null,
+    `reviser`         varchar(64)     not null,
+    `created_at`      timestamp       not null default current_timestamp,
+    `updated_at`      timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    key `idx_root_main_account_bill_date` (`root_account_id`, `main_account_id`, `bill_year`, `bill_month`,
+                                           `bill_day`),
+    key `idx_bk_biz_id_bill_date` (`bk_biz_id`, `bill_year`, `bill_month`),
+    key `idx_res_id_bill_date` (`res_id`, `bill_year`, `bill_month`),
+    key `idx_res_cloud_id_bill_date` (`res_cloud_id`, `bill_year`, `bill_month`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('account_bill_resource_cost', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.25' as `hcm_ver`, '0041' as `sql_ver`;
+
+COMMIT;
@@ -0,0 +1,91 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0042,HCMVER=v1.6.26
+
+    Notes:
+    1. 新增`account_bill_budget`账单预算表，支持按业务、二级账号、一级账号、运营产品设置月度预算及告警阈值
+    2. 新增`account_bill_alert`账单告警记录表，记录预算超出阈值及日账单费用异常告警
+*/
+
+START TRANSACTION;
+
+create table if not exists `account_bill_budget`
+(
+    `id`         varchar(64)     not null,
+    `name`       varchar(64)     not null,
+    `scope_type` varchar(32)     not null,
+    `scope_id`   varchar(64)     not null,
+    `vendor`     varchar(32)     default '',
+    `amount`     decimal(38, 10) not null,
+    `thresholds` json            not null,
+    `receivers`  json,
+    `enabled`    boolean         default true,
+    `memo`       varchar(255)    default '',
+    `creator`    varchar(64)     not null,
+    `reviser`    varchar(64)     not null,
+    `created_at` timestamp       not null default current_timestamp,
+    `updated_at` timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_name` (`name`),
+    key `idx_scope_type_scope_id` (`scope_type`, `scope_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+create table if not exists `account_bill_alert`
+(
+    `id`            varchar(64)     not null,
+    `alert_type`    varchar(32)     not null,
+    `budget_id`     varchar(64)     default '',
+    `scope_type`    varchar(32)     not null,
+    `scope_id`      varchar(64)     not null,
+    `vendor`        varchar(32)     default '',
+    `bill_year`     int             not null,
+    `bill_month`    tinyint(1)      not null,
+    `bill_day`      tinyint(1)      default 0,
+    `threshold`     int             default 0,
+    `amount`        decimal(38, 10) default 0,
+    `cost`          decimal(38, 10) not null,
+    `expected_cost` decimal(38, 10) default 0,
+    `currency`      varchar(16)     default '',
+    `message`       varchar(1024)   default '',
+    `receivers`     json,
+    `notify_state`  varchar(32)     not null,
+    `dedup_key`     varchar(255)    not null,
+    `creator`       varchar(64)     not null,
+    `reviser`       varchar(64)     not null,
+    `created_at`    timestamp       not null default current_timestamp,
+    `updated_at`    timestamp       not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_dedup_key` (`dedup_key`),
+    key `idx_alert_type_bill_date` (`alert_type`, `bill_year`, `bill_month`),
+    key `idx_budget_id` (`budget_id`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('account_bill_budget', '0'),
+       ('account_bill_alert', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.26' as `hcm_ver`, '0042' as `sql_ver`;
+
+COMMIT;
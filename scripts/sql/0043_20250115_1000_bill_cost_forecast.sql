@@ -0,0 +1,60 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0043,HCMVER=v1.6.27
+
+    Notes:
+    1. `account_bill_summary_main`、`account_bill_summary_root`表增加月末及季末人民币费用预测字段，包含预测值及置信区间上下限
+    2. `account_bill_summary_main`、`account_bill_summary_root`表增加月末及季末原币种费用预测字段，缺少汇率时只有原币种预测
+*/
+
+START TRANSACTION;
+
+alter table account_bill_summary_main
+    add column `forecast_month_rmb_cost`    decimal(38, 10) default 0 after `adjustment_rmb_cost`,
+    add column `forecast_month_rmb_lower`   decimal(38, 10) default 0 after `forecast_month_rmb_cost`,
+    add column `forecast_month_rmb_upper`   decimal(38, 10) default 0 after `forecast_month_rmb_lower`,
+    add column `forecast_quarter_rmb_cost`  decimal(38, 10) default 0 after `forecast_month_rmb_upper`,
+    add column `forecast_quarter_rmb_lower` decimal(38, 10) default 0 after `forecast_quarter_rmb_cost`,
+    add column `forecast_quarter_rmb_upper` decimal(38, 10) default 0 after `forecast_quarter_rmb_lower`,
+    add column `forecast_month_cost`        decimal(38, 10) default 0 after `forecast_quarter_rmb_upper`,
+    add column `forecast_month_lower`       decimal(38, 10) default 0 after `forecast_month_cost`,
+    add column `forecast_month_upper`       decimal(38, 10) default 0 after `forecast_month_lower`,
+    add column `forecast_quarter_cost`      decimal(38, 10) default 0 after `forecast_month_upper`,
+    add column `forecast_quarter_lower`     decimal(38, 10) default 0 after `forecast_quarter_cost`,
+    add column `forecast_quarter_upper`     decimal(38, 10) default 0 after `forecast_quarter_lower`;
+
+alter table account_bill_summary_root
+    add column `forecast_month_rmb_cost`    decimal(38, 10) default 0 after `adjustment_rmb_cost`,
+    add column `forecast_month_rmb_lower`   decimal(38, 10) default 0 after `forecast_month_rmb_cost`,
+    add column `forecast_month_rmb_upper`   decimal(38, 10) default 0 after `forecast_month_rmb_lower`,
+    add column `forecast_quarter_rmb_cost`  decimal(38, 10) default 0 after `forecast_month_rmb_upper`,
+    add column `forecast_quarter_rmb_lower` decimal(38, 10) default 0 after `forecast_quarter_rmb_cost`,
+    add column `forecast_quarter_rmb_upper` decimal(38, 10) default 0 after `forecast_quarter_rmb_lower`,
+    add column `forecast_month_cost`        decimal(38, 10) default 0 after `forecast_quarter_rmb_upper`,
+    add column `forecast_month_lower`       decimal(38, 10) default 0 after `forecast_month_cost`,
+    add column `forecast_month_upper`       decimal(38, 10) default 0 after `forecast_month_lower`,
+    add column `forecast_quarter_cost`      decimal(38, 10) default 0 after `forecast_month_upper`,
+    add column `forecast_quarter_lower`     decimal(38, 10) default 0 after `forecast_quarter_cost`,
+    add column `forecast_quarter_upper`     decimal(38, 10) default 0 after `forecast_quarter_lower`;
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.27' as `hcm_ver`, '0043' as `sql_ver`;
+
+COMMIT;
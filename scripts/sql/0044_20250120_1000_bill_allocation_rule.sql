@@ -0,0 +1,59 @@
+/*
+ * TencentBlueKing is pleased to support the open source community by making
+ * 蓝鲸智云 - 混合云管理平台 (BlueKing - Hybrid Cloud Management System) available.
+ * Copyright (C) 2022 THL A29 Limited,
+ * a Tencent company. All rights reserved.
+ * Licensed under the MIT License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at http://opensource.org/licenses/MIT
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on
+ * an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+ * either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ * We undertake not to change the open source license (MIT license) applicable
+ *
+ * to the current version of the project delivered to anyone in the future.
+ */
+/*
+    SQLVER=0044,HCMVER=v1.6.28
+
+    Notes:
+    1. 新增`account_bill_allocation_rule`分账规则表，支持按比例、标签、资源归属、用量占比将二级账号费用分摊到业务，
+       规则按二级账号分版本保存，每个版本从生效月份开始生效
+*/
+
+START TRANSACTION;
+
+create table if not exists `account_bill_allocation_rule`
+(
+    `id`                varchar(64)  not null,
+    `root_account_id`   varchar(64)  not null,
+    `main_account_id`   varchar(64)  not null,
+    `vendor`            varchar(32)  not null,
+    `version`           int          not null,
+    `effective_year`    int          not null,
+    `effective_month`   tinyint(1)   not null,
+    `rule_type`         varchar(32)  not null,
+    `config`            json         not null,
+    `default_bk_biz_id` bigint       default -1,
+    `enabled`           boolean      default true,
+    `memo`              varchar(255) default '',
+    `creator`           varchar(64)  not null,
+    `reviser`           varchar(64)  not null,
+    `created_at`        timestamp    not null default current_timestamp,
+    `updated_at`        timestamp    not null default current_timestamp on update current_timestamp,
+    primary key (`id`),
+    unique key `idx_uk_main_account_id_version` (`main_account_id`, `version`)
+) engine = innodb
+  default charset = utf8mb4
+  collate utf8mb4_bin;
+
+insert into id_generator(`resource`, `max_id`)
+values ('account_bill_allocation_rule', '0');
+
+CREATE OR REPLACE VIEW `hcm_version`(`hcm_ver`, `sql_ver`) AS
+SELECT 'v1.6.28' as `hcm_ver`, '0044' as `sql_ver`;
+
+COMMIT;